@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/consumer"
+	"github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+	"github.com/erniealice/espyna-golang/registry/roletemplate"
+)
+
+/*
+ ESPYNA PERMSYNC - Roll role template changes out to existing workspaces
+
+Diffs the in-code role templates (registry/roletemplate) against every
+workspace's role and role_permission records and applies the difference:
+missing template roles are created, template permissions the role lacks
+are granted, and ALLOW grants the template no longer lists are revoked
+(soft-deleted). Roles that match no template and DENY grants are never
+touched. Each workspace is written in one transaction where the provider
+supports it, so a failure leaves that workspace as it was.
+
+The database provider is selected by build tags and CONFIG_DATABASE_PROVIDER,
+exactly like cmd/server.
+
+Examples:
+  go run -tags postgres,mock_auth,mock_storage ./cmd/permsync -dry-run
+  go run -tags postgres,mock_auth,mock_storage ./cmd/permsync -workspace ws-canary-1,ws-canary-2
+  go run -tags postgres,mock_auth,mock_storage ./cmd/permsync
+
+Flags:
+  -dry-run     Report the changes per workspace without writing anything
+  -workspace   Comma-separated workspace ids to sync instead of every
+               active workspace, e.g. to canary a change first
+*/
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report the changes without writing them")
+	workspaces := flag.String("workspace", "", "comma-separated workspace ids to sync (default: every active workspace)")
+	flag.Parse()
+
+	os.Exit(run(*dryRun, splitList(*workspaces)))
+}
+
+// run syncs the templates and returns the process exit code. It is split
+// from main so the deferred container.Close runs before exiting.
+func run(dryRun bool, only []string) int {
+	container, err := consumer.NewContainerFromEnv()
+	if err != nil {
+		log.Printf("Failed to create container from environment: %v", err)
+		return 1
+	}
+	defer container.Close()
+
+	ops, ok := container.GetDatabaseOperations().(interfaces.DatabaseOperation)
+	if !ok {
+		log.Print("No database operations available — check CONFIG_DATABASE_PROVIDER and build tags")
+		return 1
+	}
+
+	tableName := container.GetDBTableConfig().TableName
+	t := tables{
+		Workspace:      tableName(entityid.Workspace),
+		Role:           tableName(entityid.Role),
+		Permission:     tableName(entityid.Permission),
+		RolePermission: tableName(entityid.RolePermission),
+	}
+
+	ctx := context.Background()
+	workspaceIDs := only
+	if len(workspaceIDs) == 0 {
+		rows, err := listWhere(ctx, ops, t.Workspace, "", "")
+		if err != nil {
+			log.Printf("Failed to read workspaces: %v", err)
+			return 1
+		}
+		for _, row := range rows {
+			if id := str(row["id"]); id != "" && active(row) {
+				workspaceIDs = append(workspaceIDs, id)
+			}
+		}
+	}
+
+	mode := "Permission sync"
+	if dryRun {
+		mode = "Permission sync (dry run)"
+	}
+	log.Printf("%s: %d role templates, %d workspaces", mode, len(roletemplate.Templates), len(workspaceIDs))
+
+	var changed, unchanged, failed, roles, grants, revoked int
+	for _, wsID := range workspaceIDs {
+		state, err := loadWorkspace(ctx, ops, t, wsID, roletemplate.Templates)
+		if err != nil {
+			failed++
+			log.Printf("FAILED: workspace %s: %v", wsID, err)
+			continue
+		}
+		plan := planWorkspace(wsID, roletemplate.Templates, state, t)
+		if plan.empty() {
+			unchanged++
+			continue
+		}
+		for _, c := range plan.Changes {
+			log.Printf("  workspace %s: %s", wsID, c)
+		}
+		if !dryRun {
+			if err := applyPlan(ctx, ops, container.GetTransactor(), t, plan); err != nil {
+				failed++
+				log.Printf("FAILED: workspace %s: %v", wsID, err)
+				continue
+			}
+		}
+		changed++
+		for _, c := range plan.Changes {
+			if c.NewRole {
+				roles++
+			}
+			grants += len(c.Added)
+			revoked += len(c.Removed)
+		}
+	}
+
+	log.Printf("%s finished: %d workspaces changed, %d unchanged, %d failed; %d roles created, %d grants added, %d revoked",
+		mode, changed, unchanged, failed, roles, grants, revoked)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
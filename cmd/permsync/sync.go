@@ -0,0 +1,374 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+
+	"github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/ports"
+	"github.com/erniealice/espyna-golang/registry/roletemplate"
+)
+
+// Permission types as stored in role_permission.permission_type.
+const (
+	permissionAllow = "PERMISSION_TYPE_ALLOW"
+	permissionDeny  = "PERMISSION_TYPE_DENY"
+)
+
+// listPageSize is the page size used to read rows.
+const listPageSize = 100
+
+// tables holds the table or collection name of each entity the sync touches.
+type tables struct {
+	Workspace, Role, Permission, RolePermission string
+}
+
+// workspaceState is one workspace's stored RBAC rows, active or not.
+type workspaceState struct {
+	Roles       []map[string]any
+	Permissions []map[string]any
+	// Grants are the role_permission rows of the roles named like a template.
+	Grants []map[string]any
+}
+
+// roleChange is what the sync does to one template role in a workspace.
+type roleChange struct {
+	Role    string
+	NewRole bool
+	Added   []string
+	Removed []string
+}
+
+func (c roleChange) String() string {
+	s := c.Role
+	if c.NewRole {
+		s += " (new role)"
+	}
+	if len(c.Added) > 0 {
+		s += fmt.Sprintf(" +%d %s", len(c.Added), codeList(c.Added))
+	}
+	if len(c.Removed) > 0 {
+		s += fmt.Sprintf(" -%d %s", len(c.Removed), codeList(c.Removed))
+	}
+	return s
+}
+
+// codeListMax is how many permission codes a report line spells out.
+const codeListMax = 5
+
+func codeList(codes []string) string {
+	if len(codes) <= codeListMax {
+		return "[" + strings.Join(codes, ", ") + "]"
+	}
+	return fmt.Sprintf("[%s, ... %d more]", strings.Join(codes[:codeListMax], ", "), len(codes)-codeListMax)
+}
+
+// workspacePlan is every write one workspace needs to match the templates.
+// Rows are never hard-deleted: revoked grants are soft-deleted, and rows a
+// previous sync created and a later one revoked are reactivated.
+type workspacePlan struct {
+	WorkspaceID string
+	Roles       []map[string]any
+	Permissions []map[string]any
+	Grants      []map[string]any
+	// Reactivate lists inactive row ids to set active again, by table.
+	Reactivate map[string][]string
+	// Revoke lists role_permission ids to soft-delete.
+	Revoke  []string
+	Changes []roleChange
+}
+
+func (p workspacePlan) empty() bool {
+	return len(p.Roles) == 0 && len(p.Permissions) == 0 && len(p.Grants) == 0 &&
+		len(p.Reactivate) == 0 && len(p.Revoke) == 0
+}
+
+// Row ids are derived from the workspace, role and permission code, so a
+// re-run finds the rows an earlier run wrote.
+func roleID(workspaceID, name string) string {
+	return workspaceID + "-role-" + strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "-"))
+}
+
+func permissionID(workspaceID, code string) string {
+	return workspaceID + "-perm-" + strings.ReplaceAll(code, ":", "-")
+}
+
+func grantID(roleID, code string) string {
+	return roleID + "-" + strings.ReplaceAll(code, ":", "-")
+}
+
+// planWorkspace diffs the templates against one workspace. Template roles
+// are matched by name; other roles are left alone. On a template role:
+//   - a template code without an active grant is granted (ALLOW), creating
+//     the workspace's permission row when it has none;
+//   - an active ALLOW grant whose code is not in the template is revoked;
+//   - DENY grants are explicit restrictions and are never touched.
+func planWorkspace(workspaceID string, templates []roletemplate.Template, state workspaceState, t tables) workspacePlan {
+	plan := workspacePlan{WorkspaceID: workspaceID, Reactivate: make(map[string][]string)}
+
+	rolesByName := make(map[string]map[string]any)
+	rowsByID := make(map[string]map[string]any)
+	for _, role := range state.Roles {
+		rowsByID[str(role["id"])] = role
+		name := str(role["name"])
+		if active(role) && rolesByName[name] == nil {
+			rolesByName[name] = role
+		}
+	}
+	permissionsByCode := make(map[string]map[string]any)
+	codeByPermissionID := make(map[string]string)
+	for _, p := range state.Permissions {
+		id, code := str(p["id"]), str(p["permission_code"])
+		rowsByID[id] = p
+		codeByPermissionID[id] = code
+		if active(p) && permissionsByCode[code] == nil {
+			permissionsByCode[code] = p
+		}
+	}
+	grantsByRole := make(map[string][]map[string]any)
+	for _, g := range state.Grants {
+		rowsByID[str(g["id"])] = g
+		grantsByRole[str(g["role_id"])] = append(grantsByRole[str(g["role_id"])], g)
+	}
+
+	// ensure makes the row with a derived id exist and be active: it is
+	// reactivated when an earlier sync left it inactive, created otherwise.
+	ensure := func(table, id string, row map[string]any, creates *[]map[string]any) {
+		if existing, ok := rowsByID[id]; ok {
+			if !active(existing) {
+				plan.Reactivate[table] = append(plan.Reactivate[table], id)
+				existing["active"] = true
+			}
+			return
+		}
+		*creates = append(*creates, row)
+		rowsByID[id] = row
+	}
+
+	for _, tmpl := range templates {
+		change := roleChange{Role: tmpl.Name}
+		var rID string
+		if role := rolesByName[tmpl.Name]; role != nil {
+			rID = str(role["id"])
+		} else {
+			rID = roleID(workspaceID, tmpl.Name)
+			change.NewRole = true
+			ensure(t.Role, rID, map[string]any{
+				"id":           rID,
+				"workspace_id": workspaceID,
+				"name":         tmpl.Name,
+				"description":  tmpl.Description,
+				"active":       true,
+			}, &plan.Roles)
+		}
+
+		want := make(map[string]bool, len(tmpl.Permissions))
+		for _, code := range tmpl.Permissions {
+			want[code] = true
+		}
+		held := make(map[string]bool)
+		for _, g := range grantsByRole[rID] {
+			code, known := codeByPermissionID[str(g["permission_id"])]
+			if !known || !active(g) {
+				continue
+			}
+			if isDeny(g["permission_type"]) {
+				held[code] = true
+				continue
+			}
+			if !want[code] {
+				plan.Revoke = append(plan.Revoke, str(g["id"]))
+				change.Removed = append(change.Removed, code)
+				continue
+			}
+			held[code] = true
+		}
+
+		for _, code := range tmpl.Permissions {
+			if held[code] {
+				continue
+			}
+			permission := permissionsByCode[code]
+			if permission == nil {
+				pID := permissionID(workspaceID, code)
+				permission = map[string]any{
+					"id":              pID,
+					"workspace_id":    workspaceID,
+					"name":            code,
+					"permission_code": code,
+					"permission_type": permissionAllow,
+					"active":          true,
+				}
+				ensure(t.Permission, pID, permission, &plan.Permissions)
+				permission = rowsByID[pID]
+				permissionsByCode[code] = permission
+			}
+			gID := grantID(rID, code)
+			ensure(t.RolePermission, gID, map[string]any{
+				"id":              gID,
+				"role_id":         rID,
+				"permission_id":   str(permission["id"]),
+				"permission_type": permissionAllow,
+				"active":          true,
+			}, &plan.Grants)
+			held[code] = true
+			change.Added = append(change.Added, code)
+		}
+
+		if change.NewRole || len(change.Added) > 0 || len(change.Removed) > 0 {
+			plan.Changes = append(plan.Changes, change)
+		}
+	}
+	if len(plan.Reactivate) == 0 {
+		plan.Reactivate = nil
+	}
+	return plan
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// active treats a missing active column as active.
+func active(row map[string]any) bool {
+	b, ok := row["active"].(bool)
+	return !ok || b
+}
+
+// isDeny recognizes DENY grants stored by enum name or number.
+func isDeny(v any) bool {
+	switch t := v.(type) {
+	case string:
+		return t == permissionDeny
+	case int64:
+		return t == 2
+	case int32:
+		return t == 2
+	case float64:
+		return t == 2
+	}
+	return false
+}
+
+// loadWorkspace reads a workspace's roles and permissions and the grants of
+// its template roles.
+func loadWorkspace(ctx context.Context, ops interfaces.DatabaseOperation, t tables, workspaceID string, templates []roletemplate.Template) (workspaceState, error) {
+	var state workspaceState
+	var err error
+	if state.Roles, err = listWhere(ctx, ops, t.Role, "workspace_id", workspaceID); err != nil {
+		return state, err
+	}
+	if state.Permissions, err = listWhere(ctx, ops, t.Permission, "workspace_id", workspaceID); err != nil {
+		return state, err
+	}
+	names := make(map[string]bool, len(templates))
+	for _, tmpl := range templates {
+		names[tmpl.Name] = true
+	}
+	for _, role := range state.Roles {
+		if !names[str(role["name"])] {
+			continue
+		}
+		grants, err := listWhere(ctx, ops, t.RolePermission, "role_id", str(role["id"]))
+		if err != nil {
+			return state, err
+		}
+		state.Grants = append(state.Grants, grants...)
+	}
+	return state, nil
+}
+
+// listWhere reads every row of table whose field equals value. An empty
+// field reads the whole table.
+func listWhere(ctx context.Context, ops interfaces.DatabaseOperation, table, field, value string) ([]map[string]any, error) {
+	var filters *commonpb.FilterRequest
+	if field != "" {
+		filters = &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{{
+			Field: field,
+			FilterType: &commonpb.TypedFilter_StringFilter{
+				StringFilter: &commonpb.StringFilter{
+					Value:         value,
+					Operator:      commonpb.StringOperator_STRING_EQUALS,
+					CaseSensitive: true,
+				},
+			},
+		}}}
+	}
+
+	var all []map[string]any
+	for page := int32(1); ; page++ {
+		result, err := ops.List(ctx, table, &interfaces.ListParams{
+			Filters: filters,
+			Pagination: &commonpb.PaginationRequest{
+				Limit: listPageSize,
+				Method: &commonpb.PaginationRequest_Offset{
+					Offset: &commonpb.OffsetPagination{Page: page},
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", table, err)
+		}
+		if result == nil {
+			return all, nil
+		}
+		for _, row := range result.Data {
+			// Guard against providers that ignore the filter.
+			if field == "" || str(row[field]) == value {
+				all = append(all, row)
+			}
+		}
+		if len(result.Data) < listPageSize {
+			return all, nil
+		}
+	}
+}
+
+// applyPlan writes a plan in foreign-key order: roles and permissions, then
+// reactivations, then new grants, then revocations. It runs in one
+// transaction when the provider has them.
+func applyPlan(ctx context.Context, ops interfaces.DatabaseOperation, transactor ports.Transactor, t tables, plan workspacePlan) error {
+	write := func(ctx context.Context) error {
+		for _, w := range []interfaces.TableRows{{Table: t.Role, Rows: plan.Roles}, {Table: t.Permission, Rows: plan.Permissions}} {
+			if len(w.Rows) == 0 {
+				continue
+			}
+			if _, err := ops.CreateMany(ctx, w.Table, w.Rows); err != nil {
+				return fmt.Errorf("create %s: %w", w.Table, err)
+			}
+		}
+		for _, table := range []string{t.Role, t.Permission, t.RolePermission} {
+			ids := plan.Reactivate[table]
+			if len(ids) == 0 {
+				continue
+			}
+			updates := make([]interfaces.BatchUpdate, len(ids))
+			for i, id := range ids {
+				updates[i] = interfaces.BatchUpdate{ID: id, Data: map[string]any{"active": true}}
+			}
+			if _, err := ops.UpdateMany(ctx, table, updates); err != nil {
+				return fmt.Errorf("reactivate %s: %w", table, err)
+			}
+		}
+		if len(plan.Grants) > 0 {
+			if _, err := ops.CreateMany(ctx, t.RolePermission, plan.Grants); err != nil {
+				return fmt.Errorf("create %s: %w", t.RolePermission, err)
+			}
+		}
+		if len(plan.Revoke) > 0 {
+			if err := ops.DeleteMany(ctx, t.RolePermission, plan.Revoke); err != nil {
+				return fmt.Errorf("revoke %s: %w", t.RolePermission, err)
+			}
+		}
+		return nil
+	}
+
+	if transactor == nil || !transactor.SupportsTransactions() {
+		return write(ctx)
+	}
+	return transactor.ExecuteInTransaction(ctx, write)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/registry/roletemplate"
+)
+
+var testTables = tables{Workspace: "workspace", Role: "role", Permission: "permission", RolePermission: "role_permission"}
+
+func TestPlanWorkspace_GrantsRevokesAndKeepsDeny(t *testing.T) {
+	templates := []roletemplate.Template{
+		{Name: "Owner", Permissions: []string{"client:delete", "client:read"}},
+		{Name: "Viewer", Permissions: []string{"client:read"}},
+	}
+	state := workspaceState{
+		Roles: []map[string]any{
+			{"id": "r-owner", "workspace_id": "ws1", "name": "Owner", "active": true},
+			{"id": "r-custom", "workspace_id": "ws1", "name": "Custom", "active": true},
+		},
+		Permissions: []map[string]any{
+			{"id": "p-read", "permission_code": "client:read", "active": true},
+			{"id": "p-update", "permission_code": "client:update", "active": true},
+			{"id": "p-delete", "permission_code": "client:delete", "active": true},
+		},
+		Grants: []map[string]any{
+			{"id": "g1", "role_id": "r-owner", "permission_id": "p-read", "permission_type": permissionAllow, "active": true},
+			{"id": "g2", "role_id": "r-owner", "permission_id": "p-update", "permission_type": permissionAllow, "active": true},
+			{"id": "g3", "role_id": "r-owner", "permission_id": "p-delete", "permission_type": permissionDeny, "active": true},
+		},
+	}
+
+	plan := planWorkspace("ws1", templates, state, testTables)
+
+	if !reflect.DeepEqual(plan.Revoke, []string{"g2"}) {
+		t.Fatalf("revoke = %v, want the stale ALLOW grant only", plan.Revoke)
+	}
+	if len(plan.Roles) != 1 || plan.Roles[0]["id"] != "ws1-role-viewer" || plan.Roles[0]["workspace_id"] != "ws1" {
+		t.Fatalf("roles = %v", plan.Roles)
+	}
+	if len(plan.Permissions) != 0 {
+		t.Fatalf("permissions = %v, want the existing rows reused", plan.Permissions)
+	}
+	if len(plan.Grants) != 1 || plan.Grants[0]["role_id"] != "ws1-role-viewer" || plan.Grants[0]["permission_id"] != "p-read" {
+		t.Fatalf("grants = %v", plan.Grants)
+	}
+	want := []roleChange{
+		{Role: "Owner", Removed: []string{"client:update"}},
+		{Role: "Viewer", NewRole: true, Added: []string{"client:read"}},
+	}
+	if !reflect.DeepEqual(plan.Changes, want) {
+		t.Fatalf("changes = %+v", plan.Changes)
+	}
+}
+
+func TestPlanWorkspace_RerunIsEmptyAndReactivates(t *testing.T) {
+	templates := []roletemplate.Template{{Name: "Owner", Permissions: []string{"client:read"}}}
+	first := planWorkspace("ws1", templates, workspaceState{}, testTables)
+	if len(first.Roles) != 1 || len(first.Permissions) != 1 || len(first.Grants) != 1 {
+		t.Fatalf("first plan = %+v", first)
+	}
+
+	// The rows the first run wrote, with the grant since revoked.
+	first.Grants[0]["active"] = false
+	state := workspaceState{Roles: first.Roles, Permissions: first.Permissions, Grants: first.Grants}
+	second := planWorkspace("ws1", templates, state, testTables)
+	grant := first.Grants[0]["id"].(string)
+	if len(second.Roles)+len(second.Permissions)+len(second.Grants) != 0 ||
+		!reflect.DeepEqual(second.Reactivate, map[string][]string{"role_permission": {grant}}) {
+		t.Fatalf("second plan = %+v", second)
+	}
+
+	third := planWorkspace("ws1", templates, state, testTables)
+	if !third.empty() {
+		t.Fatalf("third plan = %+v, want nothing to do", third)
+	}
+}
+
+func TestTemplates_ByNameAndSorted(t *testing.T) {
+	owner, ok := roletemplate.ByName(roletemplate.Owner)
+	if !ok || len(owner.Permissions) == 0 {
+		t.Fatal("missing Owner template")
+	}
+	for i := 1; i < len(owner.Permissions); i++ {
+		if owner.Permissions[i-1] >= owner.Permissions[i] {
+			t.Fatalf("permissions not sorted and unique at %q", owner.Permissions[i])
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+	"github.com/goccy/go-yaml"
+)
+
+// exportPageSize is the largest page the database adapters serve.
+const exportPageSize = 100
+
+// exportOmit lists columns the database stamps on write. They are left out
+// of exports so a dump re-seeds cleanly and diffs only on template content.
+var exportOmit = map[string]bool{
+	"date_created":         true,
+	"date_created_string":  true,
+	"date_modified":        true,
+	"date_modified_string": true,
+}
+
+// exportRow is one template row with a stable key order: id, name, the
+// remaining columns alphabetically, then the nested child list. The order
+// keeps dumps diffable against hand-written vya sources.
+type exportRow struct {
+	keys   []string
+	values map[string]any
+}
+
+func newExportRow(row map[string]any, drop ...string) exportRow {
+	r := exportRow{values: make(map[string]any, len(row))}
+	for k, v := range row {
+		if v == nil || exportOmit[k] {
+			continue
+		}
+		r.values[k] = v
+	}
+	for _, k := range drop {
+		delete(r.values, k)
+	}
+
+	var rest []string
+	for k := range r.values {
+		if k != "id" && k != "name" {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	for _, k := range []string{"id", "name"} {
+		if _, ok := r.values[k]; ok {
+			r.keys = append(r.keys, k)
+		}
+	}
+	r.keys = append(r.keys, rest...)
+	return r
+}
+
+// setChildren appends the nested list under key; empty lists are omitted.
+func (r *exportRow) setChildren(key string, children []exportRow) {
+	if len(children) == 0 {
+		return
+	}
+	r.keys = append(r.keys, key)
+	r.values[key] = children
+}
+
+// MarshalJSON writes the row's keys in order.
+func (r exportRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		value, err := json.Marshal(r.values[k])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", k, err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML writes the row's keys in order.
+func (r exportRow) MarshalYAML() (any, error) {
+	items := make(yaml.MapSlice, 0, len(r.keys))
+	for _, k := range r.keys {
+		items = append(items, yaml.MapItem{Key: k, Value: r.values[k]})
+	}
+	return items, nil
+}
+
+// exportFile mirrors seedFile so an export can be loaded with -file.
+type exportFile struct {
+	WorkflowTemplates []exportRow `json:"workflow_templates" yaml:"workflow_templates"`
+}
+
+// exportTemplates reads every workflow, stage and activity template and
+// nests them in the seed file layout. orphans counts stages and activities
+// whose parent template was not found.
+func exportTemplates(ctx context.Context, ops interfaces.DatabaseOperation, tableName func(string) string) (f *exportFile, orphans int, err error) {
+	templates, err := listAll(ctx, ops, tableName(entityid.WorkflowTemplate))
+	if err != nil {
+		return nil, 0, err
+	}
+	stages, err := listAll(ctx, ops, tableName(entityid.StageTemplate))
+	if err != nil {
+		return nil, 0, err
+	}
+	activities, err := listAll(ctx, ops, tableName(entityid.ActivityTemplate))
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, orphans := nestTemplates(templates, stages, activities)
+	return &exportFile{WorkflowTemplates: rows}, orphans, nil
+}
+
+// listAll pages through a table.
+func listAll(ctx context.Context, ops interfaces.DatabaseOperation, table string) ([]map[string]any, error) {
+	var all []map[string]any
+	for page := int32(1); ; page++ {
+		result, err := ops.List(ctx, table, &interfaces.ListParams{
+			Pagination: &commonpb.PaginationRequest{
+				Limit: exportPageSize,
+				Method: &commonpb.PaginationRequest_Offset{
+					Offset: &commonpb.OffsetPagination{Page: page},
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", table, err)
+		}
+		if result == nil {
+			return all, nil
+		}
+		all = append(all, result.Data...)
+		if len(result.Data) < exportPageSize {
+			return all, nil
+		}
+	}
+}
+
+// nestTemplates groups stages under their workflow template and activities
+// under their stage. Templates sort by id; stages and activities by
+// order_index, then id. The link columns are dropped because the nesting
+// carries them.
+func nestTemplates(templates, stages, activities []map[string]any) ([]exportRow, int) {
+	activitiesByStage := groupBy(activities, "stage_template_id")
+	stagesByTemplate := groupBy(stages, "workflow_template_id")
+	sortRows(templates, false)
+
+	placed := 0
+	rows := make([]exportRow, 0, len(templates))
+	for _, wt := range templates {
+		wtID, _ := wt["id"].(string)
+		templateRow := newExportRow(wt)
+
+		wtStages := stagesByTemplate[wtID]
+		sortRows(wtStages, true)
+		stageRows := make([]exportRow, 0, len(wtStages))
+		for _, st := range wtStages {
+			stID, _ := st["id"].(string)
+			stageRow := newExportRow(st, "workflow_template_id")
+
+			stActivities := activitiesByStage[stID]
+			sortRows(stActivities, true)
+			activityRows := make([]exportRow, 0, len(stActivities))
+			for _, act := range stActivities {
+				activityRows = append(activityRows, newExportRow(act, "stage_template_id"))
+			}
+			stageRow.setChildren("activities", activityRows)
+			stageRows = append(stageRows, stageRow)
+			placed += 1 + len(stActivities)
+		}
+		templateRow.setChildren("stages", stageRows)
+		rows = append(rows, templateRow)
+	}
+	return rows, len(stages) + len(activities) - placed
+}
+
+func groupBy(rows []map[string]any, key string) map[string][]map[string]any {
+	out := make(map[string][]map[string]any)
+	for _, row := range rows {
+		parent, _ := row[key].(string)
+		out[parent] = append(out[parent], row)
+	}
+	return out
+}
+
+// sortRows orders rows by id, or by order_index then id.
+func sortRows(rows []map[string]any, byOrder bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if byOrder {
+			oi, oj := orderIndex(rows[i]), orderIndex(rows[j])
+			if oi != oj {
+				return oi < oj
+			}
+		}
+		idI, _ := rows[i]["id"].(string)
+		idJ, _ := rows[j]["id"].(string)
+		return idI < idJ
+	})
+}
+
+// orderIndex reads order_index whatever numeric type the driver returned.
+func orderIndex(row map[string]any) float64 {
+	n, _ := toNumber(row["order_index"])
+	return n
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// exportFormat resolves -format, falling back to the output file extension.
+func exportFormat(format, path string) (string, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		return "json", nil
+	case "yaml", "yml":
+		return "yaml", nil
+	case "":
+		if isYAMLPath(path) {
+			return "yaml", nil
+		}
+		return "json", nil
+	default:
+		return "", fmt.Errorf("unknown -format %q (want json or yaml)", format)
+	}
+}
+
+func isYAMLPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// writeExport encodes an export or snapshot in the given format.
+func writeExport(w io.Writer, f any, format string) error {
+	if format == "yaml" {
+		out, err := yaml.Marshal(f)
+		if err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		_, err = w.Write(out)
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// writeExportFile writes the export to path, reporting close errors so a
+// truncated file is not mistaken for a complete dump.
+func writeExportFile(path string, f any, format string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	if err := writeExport(out, f, format); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
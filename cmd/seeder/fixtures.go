@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/database/model"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+	"github.com/goccy/go-yaml"
+)
+
+// fixtureFile is the -fixtures format, as JSON or the equivalent YAML: a
+// list of row sets, each for one entity (an entityid name, mapped to its
+// table like the rest of the app) or one raw table. Every row needs an
+// explicit id, so tests and preview environments can address the data they
+// loaded. A column that points at another fixture row is written as a
+// reference, which is checked when the file loads and orders the inserts:
+//
+//	fixtures:
+//	  - entity: client
+//	    rows:
+//	      - id: client-ana
+//	        user_id: {$ref: user/user-ana}
+//	  - entity: user
+//	    rows:
+//	      - {id: user-ana, email_address: ana@example.com}
+//
+// A reference is "<entity or table>/<id>" as named in the same file.
+type fixtureFile struct {
+	Fixtures []fixtureSet `json:"fixtures"`
+}
+
+type fixtureSet struct {
+	Entity string           `json:"entity,omitempty"`
+	Table  string           `json:"table,omitempty"`
+	Rows   []map[string]any `json:"rows"`
+}
+
+// fixtureRow is one row of the load order.
+type fixtureRow struct {
+	Table string
+	ID    string
+	Row   map[string]any
+}
+
+// refKey is the object key that marks a reference value.
+const refKey = "$ref"
+
+// loadFixtureFile reads and decodes a fixture file.
+func loadFixtureFile(path string) (*fixtureFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture file: %w", err)
+	}
+	if isYAMLPath(path) {
+		if raw, err = yaml.YAMLToJSON(raw); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture file %s: %w", path, err)
+		}
+	}
+	var f fixtureFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture file %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// planFixtures resolves references and returns the rows in an order where
+// every row comes after the rows it references; rows without a dependency
+// between them keep their file order. tableName maps an entityid name to its
+// table or collection name.
+func planFixtures(f *fixtureFile, tableName func(string) string) ([]fixtureRow, error) {
+	known := make(map[string]bool, len(entityid.All))
+	for _, e := range entityid.All {
+		known[e] = true
+	}
+
+	var rows []fixtureRow
+	index := make(map[string]int) // "<name>/<id>" -> position in rows
+	for si, set := range f.Fixtures {
+		name, table := set.Entity, set.Table
+		switch {
+		case (name == "") == (table == ""):
+			return nil, fmt.Errorf("fixtures[%d]: set exactly one of entity or table", si)
+		case name != "" && !known[name]:
+			return nil, fmt.Errorf("fixtures[%d]: unknown entity %q", si, name)
+		case name != "":
+			table = tableName(name)
+		default:
+			name = table
+		}
+		for ri, row := range set.Rows {
+			id, _ := row["id"].(string)
+			if id == "" {
+				return nil, fmt.Errorf("%s[%d]: id is required", name, ri)
+			}
+			key := name + "/" + id
+			if _, dup := index[key]; dup {
+				return nil, fmt.Errorf("%s: duplicate id %q", name, id)
+			}
+			index[key] = len(rows)
+			rows = append(rows, fixtureRow{Table: table, ID: id, Row: columns(row)})
+		}
+	}
+
+	// deps[i] lists the rows row i references.
+	deps := make([][]int, len(rows))
+	for i, r := range rows {
+		for column, value := range r.Row {
+			ref, ok, err := fixtureRef(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %s: %w", r.Table, r.ID, column, err)
+			}
+			if !ok {
+				continue
+			}
+			target, found := index[ref]
+			if !found {
+				return nil, fmt.Errorf("%s %s: %s references %s, which is not in the fixture file", r.Table, r.ID, column, ref)
+			}
+			r.Row[column] = rows[target].ID
+			if target != i {
+				deps[i] = append(deps[i], target)
+			}
+		}
+		slices.Sort(deps[i])
+	}
+
+	// Depth-first topological sort in file order, so a row is emitted right
+	// after what it needs.
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(rows))
+	ordered := make([]fixtureRow, 0, len(rows))
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		path = append(path, rows[i].Table+"/"+rows[i].ID)
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("reference cycle: %s", strings.Join(path, " -> "))
+		}
+		state[i] = visiting
+		for _, d := range deps[i] {
+			if err := visit(d, path); err != nil {
+				return err
+			}
+		}
+		state[i] = done
+		ordered = append(ordered, rows[i])
+		return nil
+	}
+	for i := range rows {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// fixtureRef returns the "<name>/<id>" of a reference value.
+func fixtureRef(value any) (string, bool, error) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return "", false, nil
+	}
+	raw, ok := obj[refKey]
+	if !ok {
+		return "", false, nil
+	}
+	ref, _ := raw.(string)
+	if len(obj) != 1 || !strings.Contains(ref, "/") || strings.HasPrefix(ref, "/") || strings.HasSuffix(ref, "/") {
+		return "", false, fmt.Errorf(`a reference is {"$ref": "<entity>/<id>"}`)
+	}
+	return ref, true, nil
+}
+
+// fixturePlan groups the load order into consecutive per-table writes, so
+// -atomic can hand it to seedTemplate as one unit.
+func fixturePlan(rows []fixtureRow) templatePlan {
+	plan := templatePlan{ID: "fixtures", Name: "fixture set"}
+	for _, r := range rows {
+		if n := len(plan.Writes); n > 0 && plan.Writes[n-1].Table == r.Table {
+			plan.Writes[n-1].Rows = append(plan.Writes[n-1].Rows, r.Row)
+			continue
+		}
+		plan.Writes = append(plan.Writes, interfaces.TableRows{Table: r.Table, Rows: []map[string]any{r.Row}})
+	}
+	return plan
+}
+
+// teardownFixtures hard-deletes the fixture rows in reverse load order, so
+// referencing rows go before the rows they reference. Rows already gone are
+// skipped. It returns how many rows were deleted and the failures.
+func teardownFixtures(ctx context.Context, ops interfaces.DatabaseOperation, rows []fixtureRow) (int, []error) {
+	deleted := 0
+	var failures []error
+	for i := len(rows) - 1; i >= 0; i-- {
+		r := rows[i]
+		err := ops.HardDelete(ctx, r.Table, r.ID)
+		if dbErr, ok := model.GetDatabaseError(err); ok && dbErr.HTTPStatus == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			failures = append(failures, fmt.Errorf("delete %s %s: %w", r.Table, r.ID, err))
+			continue
+		}
+		deleted++
+	}
+	return deleted, failures
+}
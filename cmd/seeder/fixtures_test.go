@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/database/model"
+)
+
+const testFixtures = `{"fixtures": [
+	{"entity": "client", "rows": [
+		{"id": "client-ana", "user_id": {"$ref": "user/user-ana"}, "active": true}
+	]},
+	{"table": "notification_inbox", "rows": [
+		{"id": "inbox-1", "user_id": {"$ref": "user/user-ana"}, "priority": 2}
+	]},
+	{"entity": "user", "rows": [
+		{"id": "user-ana", "email_address": "ana@example.com"},
+		{"id": "user-ben", "email_address": "ben@example.com"}
+	]}
+]}`
+
+func parseFixtures(t *testing.T, raw string) *fixtureFile {
+	t.Helper()
+	var f fixtureFile
+	if err := json.Unmarshal([]byte(raw), &f); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return &f
+}
+
+func fixtureIDs(rows []fixtureRow) string {
+	ids := make([]string, len(rows))
+	for i, r := range rows {
+		ids[i] = r.Table + "/" + r.ID
+	}
+	return strings.Join(ids, " ")
+}
+
+func TestPlanFixtures_OrdersByReference(t *testing.T) {
+	rows, err := planFixtures(parseFixtures(t, testFixtures), func(e string) string { return "t_" + e })
+	if err != nil {
+		t.Fatalf("planFixtures: %v", err)
+	}
+	want := "t_user/user-ana t_client/client-ana notification_inbox/inbox-1 t_user/user-ben"
+	if got := fixtureIDs(rows); got != want {
+		t.Fatalf("order = %s, want %s", got, want)
+	}
+	if rows[1].Row["user_id"] != "user-ana" || rows[2].Row["priority"] != int64(2) {
+		t.Fatalf("rows = %v, %v; want the reference resolved and numbers kept whole", rows[1].Row, rows[2].Row)
+	}
+
+	plan := fixturePlan(rows)
+	if len(plan.Writes) != 4 || plan.rowCount() != 4 {
+		t.Fatalf("plan = %+v, want one write per table run", plan.Writes)
+	}
+}
+
+func TestPlanFixtures_Rejects(t *testing.T) {
+	for name, raw := range map[string]string{
+		"missing id":     `{"fixtures": [{"entity": "user", "rows": [{"email_address": "x"}]}]}`,
+		"duplicate id":   `{"fixtures": [{"entity": "user", "rows": [{"id": "u"}, {"id": "u"}]}]}`,
+		"unknown entity": `{"fixtures": [{"entity": "usr", "rows": [{"id": "u"}]}]}`,
+		"entity + table": `{"fixtures": [{"entity": "user", "table": "users", "rows": [{"id": "u"}]}]}`,
+		"dangling ref":   `{"fixtures": [{"entity": "client", "rows": [{"id": "c", "user_id": {"$ref": "user/u"}}]}]}`,
+		"malformed ref":  `{"fixtures": [{"entity": "client", "rows": [{"id": "c", "user_id": {"$ref": "u"}}]}]}`,
+		"cycle": `{"fixtures": [{"entity": "user", "rows": [
+			{"id": "a", "manager_id": {"$ref": "user/b"}},
+			{"id": "b", "manager_id": {"$ref": "user/a"}}]}]}`,
+	} {
+		if _, err := planFixtures(parseFixtures(t, raw), func(e string) string { return e }); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+// deletingOps records hard deletes; rows in missing are not found.
+type deletingOps struct {
+	interfaces.DatabaseOperation
+	missing map[string]bool
+	deleted []string
+}
+
+func (o *deletingOps) HardDelete(_ context.Context, table, id string) error {
+	if o.missing[id] {
+		return model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	if id == "inbox-1" {
+		return errors.New("boom")
+	}
+	o.deleted = append(o.deleted, table+"/"+id)
+	return nil
+}
+
+func TestTeardownFixtures_ReverseOrderSkipsMissing(t *testing.T) {
+	rows, err := planFixtures(parseFixtures(t, testFixtures), func(e string) string { return e })
+	if err != nil {
+		t.Fatalf("planFixtures: %v", err)
+	}
+	ops := &deletingOps{missing: map[string]bool{"user-ben": true}}
+
+	deleted, failures := teardownFixtures(context.Background(), ops, rows)
+	if deleted != 2 || len(failures) != 1 {
+		t.Fatalf("deleted %d, failures %v; want 2 and the inbox failure", deleted, failures)
+	}
+	if got := strings.Join(ops.deleted, " "); got != "client/client-ana user/user-ana" {
+		t.Fatalf("deleted %s, want the client before its user", got)
+	}
+}
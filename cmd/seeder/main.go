@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/erniealice/espyna-golang/consumer"
+	"github.com/erniealice/espyna-golang/database/interfaces"
+)
+
+/*
+ ESPYNA SEEDER - Workflow template seeding, export and test fixtures
+
+Loads workflow templates, their stage templates and activity templates from
+a JSON or YAML seed file (see seedFile in seed.go) into the configured
+database, or with -export dumps the templates already in the database to a
+file in the same layout.
+
+With -fixtures it instead loads a fixture set (see fixtureFile in
+fixtures.go): rows of any entity with explicit IDs and references between
+them, for integration tests and preview environments. -teardown removes
+the same rows again.
+
+With -snapshot it copies tables of the configured database into a fixture
+file with their personal data anonymized (see database/anonymize), so a
+staging environment can be loaded with production-shaped data: run it
+against production, then -fixtures with the file against staging. Names,
+emails and phone numbers become consistent fakes, identifiers keyed
+hashes, and secrets and notes are dropped before anything is written.
+
+The database provider is selected by build tags and CONFIG_DATABASE_PROVIDER,
+exactly like cmd/server.
+
+Examples:
+  go run -tags postgres,mock_auth,mock_storage ./cmd/seeder -file seeds/workflows.json -atomic
+  go run -tags postgres,mock_auth,mock_storage ./cmd/seeder -file seeds/workflows.json -upsert
+  go run -tags postgres,mock_auth,mock_storage ./cmd/seeder -export -file staging-workflows.yaml
+  go run -tags postgres,mock_auth,mock_storage ./cmd/seeder -fixtures -file testdata/fixtures.yaml -atomic
+  go run -tags postgres,mock_auth,mock_storage ./cmd/seeder -fixtures -teardown -file testdata/fixtures.yaml
+  CONFIG_ANONYMIZE_SALT=... go run -tags postgres,mock_auth,mock_storage ./cmd/seeder -snapshot \
+      -tables user,client,location -workspace ws-acme -anonymize anonymize.yaml -file staging.yaml
+
+Flags:
+  -file     Seed file to load (required). With -export, the file to write;
+            "-" writes to stdout
+  -atomic   Write each template all-or-nothing. Postgres wraps the template,
+            its stages and activities in one transaction and rolls back on
+            error; Firestore commits them as one batched write (max 500
+            documents per template). Without -atomic every row is an
+            independent write and a failure leaves the rows before it.
+  -upsert   Compare the seed with the stored templates and write only the
+            difference: new rows are inserted, changed columns updated and
+            unchanged templates skipped, with a per-template diff summary.
+            Templates match on (workspace_id, id, version); a stored
+            template with the same id but another workspace or version is
+            reported as a conflict. Stored rows missing from the seed are
+            reported as stale, never deleted. With -atomic, templates that
+            need updates require transactions (Postgres).
+  -export   Read templates from the database instead of seeding them. The
+            output drops date_created/date_modified columns and the parent
+            links implied by nesting, so it re-seeds with -file and diffs
+            cleanly against the vya source.
+  -format   Export format: json or yaml. Defaults to yaml for .yaml/.yml
+            files and json otherwise.
+  -fixtures Load -file as a fixture set instead of workflow templates.
+            Rows are inserted so referenced rows come first; with -atomic
+            the whole set is written all-or-nothing.
+  -teardown With -fixtures, hard-delete the set's rows (referencing rows
+            first) instead of loading them. Rows already gone are skipped,
+            so a test can tear down before loading to start clean.
+  -snapshot Write the -tables rows of the database to -file as an
+            anonymized fixture set instead of seeding. Sets follow the
+            -tables order, so list referenced tables first.
+  -tables   With -snapshot, the entities or tables to copy, e.g.
+            "user,client,subscription"
+  -workspace
+            With -snapshot, copy only this workspace's rows; rows without
+            a workspace_id column are copied whole
+  -anonymize
+            With -snapshot, the anonymization policy file (JSON or YAML),
+            or "builtin" for the default rules. Required: a snapshot is
+            never written in the clear. The salt keying hashes and fakes
+            is read from CONFIG_ANONYMIZE_SALT (at least 16 bytes); reuse
+            it so fakes stay stable between refreshes.
+*/
+
+func main() {
+	file := flag.String("file", "", "seed file to load (JSON or YAML), or the export destination")
+	atomic := flag.Bool("atomic", false, "write each workflow template in a single transaction or batched write")
+	upsert := flag.Bool("upsert", false, "update existing templates in place and insert only new rows")
+	export := flag.Bool("export", false, "export templates from the database to -file instead of seeding")
+	format := flag.String("format", "", "export format: json or yaml (default: from the -file extension)")
+	fixtures := flag.Bool("fixtures", false, "load -file as a fixture set instead of workflow templates")
+	teardown := flag.Bool("teardown", false, "with -fixtures, delete the fixture rows instead of loading them")
+	snapshot := flag.Bool("snapshot", false, "write -tables to -file as an anonymized fixture set")
+	tables := flag.String("tables", "", "with -snapshot, comma-separated entities or tables to copy")
+	workspace := flag.String("workspace", "", "with -snapshot, copy only this workspace's rows")
+	policy := flag.String("anonymize", "", `with -snapshot, the anonymization policy file, or "builtin"`)
+	flag.Parse()
+
+	if *file == "" || (*teardown && !*fixtures) || (*snapshot && (*tables == "" || *policy == "")) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *snapshot {
+		os.Exit(runSnapshot(*file, *format, splitNames(*tables), *workspace, *policy))
+	}
+	if *fixtures {
+		os.Exit(runFixtures(*file, *atomic, *teardown))
+	}
+	if *export {
+		os.Exit(runExport(*file, *format))
+	}
+	os.Exit(run(*file, *atomic, *upsert))
+}
+
+// run seeds the file and returns the process exit code. It is split from
+// main so the deferred container.Close runs before exiting.
+func run(file string, atomic, upsert bool) int {
+	seed, err := loadSeedFile(file)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	container, err := consumer.NewContainerFromEnv()
+	if err != nil {
+		log.Printf("Failed to create container from environment: %v", err)
+		return 1
+	}
+	defer container.Close()
+
+	ops, ok := container.GetDatabaseOperations().(interfaces.DatabaseOperation)
+	if !ok {
+		log.Print("No database operations available — check CONFIG_DATABASE_PROVIDER and build tags")
+		return 1
+	}
+
+	plans, err := buildPlans(seed, container.GetDBTableConfig().TableName)
+	if err != nil {
+		log.Printf("Invalid seed file: %v", err)
+		return 1
+	}
+
+	s := &seeder{ops: ops, transactor: container.GetTransactor(), atomic: atomic}
+	ctx := context.Background()
+	if upsert {
+		stored, err := loadStored(ctx, ops, container.GetDBTableConfig().TableName)
+		if err != nil {
+			log.Printf("Failed to read stored templates: %v", err)
+			return 1
+		}
+		if upsertAll(ctx, s, plans, stored) > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	failed := 0
+	for _, plan := range plans {
+		if err := s.seedTemplate(ctx, plan); err != nil {
+			failed++
+			if atomic {
+				log.Printf("FAILED: workflow template %s (%s) rolled back: %v", plan.ID, plan.Name, err)
+			} else {
+				log.Printf("FAILED: workflow template %s (%s) may be partially written: %v", plan.ID, plan.Name, err)
+			}
+			continue
+		}
+		log.Printf("Seeded workflow template %s (%s): %d rows", plan.ID, plan.Name, plan.rowCount())
+	}
+
+	log.Printf("Seeding finished: %d of %d workflow templates written", len(plans)-failed, len(plans))
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runExport writes the database's templates to file and returns the process
+// exit code.
+func runExport(file, format string) int {
+	format, err := exportFormat(format, file)
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+
+	container, err := consumer.NewContainerFromEnv()
+	if err != nil {
+		log.Printf("Failed to create container from environment: %v", err)
+		return 1
+	}
+	defer container.Close()
+
+	ops, ok := container.GetDatabaseOperations().(interfaces.DatabaseOperation)
+	if !ok {
+		log.Print("No database operations available — check CONFIG_DATABASE_PROVIDER and build tags")
+		return 1
+	}
+
+	export, orphans, err := exportTemplates(context.Background(), ops, container.GetDBTableConfig().TableName)
+	if err != nil {
+		log.Printf("Export failed: %v", err)
+		return 1
+	}
+	if orphans > 0 {
+		log.Printf("WARNING: %d stage/activity templates reference a missing parent and were not exported", orphans)
+	}
+
+	if file == "-" {
+		err = writeExport(os.Stdout, export, format)
+	} else {
+		err = writeExportFile(file, export, format)
+	}
+	if err != nil {
+		log.Printf("Export failed: %v", err)
+		return 1
+	}
+
+	log.Printf("Exported %d workflow templates as %s", len(export.WorkflowTemplates), format)
+	return 0
+}
+
+// runSnapshot writes an anonymized copy of tables to file and returns the
+// process exit code.
+func runSnapshot(file, format string, tables []string, workspaceID, policyPath string) int {
+	format, err := exportFormat(format, file)
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+	anon, err := loadAnonymizer(policyPath, os.Getenv("CONFIG_ANONYMIZE_SALT"))
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+
+	container, err := consumer.NewContainerFromEnv()
+	if err != nil {
+		log.Printf("Failed to create container from environment: %v", err)
+		return 1
+	}
+	defer container.Close()
+
+	ops, ok := container.GetDatabaseOperations().(interfaces.DatabaseOperation)
+	if !ok {
+		log.Print("No database operations available — check CONFIG_DATABASE_PROVIDER and build tags")
+		return 1
+	}
+
+	snapshot, err := snapshotTables(context.Background(), ops, container.GetDBTableConfig().TableName, tables, workspaceID, anon)
+	if err != nil {
+		log.Printf("Snapshot failed: %v", err)
+		return 1
+	}
+	if file == "-" {
+		err = writeExport(os.Stdout, snapshot, format)
+	} else {
+		err = writeExportFile(file, snapshot, format)
+	}
+	if err != nil {
+		log.Printf("Snapshot failed: %v", err)
+		return 1
+	}
+
+	rows := 0
+	for _, set := range snapshot.Fixtures {
+		rows += len(set.Rows)
+	}
+	for _, c := range anon.Stats() {
+		log.Printf("Anonymized %s: %d values", c.Field, c.Count)
+	}
+	log.Printf("Snapshot of %d rows from %d tables written as %s", rows, len(snapshot.Fixtures), format)
+	return 0
+}
+
+// runFixtures loads or tears down a fixture set and returns the process
+// exit code.
+func runFixtures(file string, atomic, teardown bool) int {
+	f, err := loadFixtureFile(file)
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	container, err := consumer.NewContainerFromEnv()
+	if err != nil {
+		log.Printf("Failed to create container from environment: %v", err)
+		return 1
+	}
+	defer container.Close()
+
+	ops, ok := container.GetDatabaseOperations().(interfaces.DatabaseOperation)
+	if !ok {
+		log.Print("No database operations available — check CONFIG_DATABASE_PROVIDER and build tags")
+		return 1
+	}
+
+	rows, err := planFixtures(f, container.GetDBTableConfig().TableName)
+	if err != nil {
+		log.Printf("Invalid fixture file: %v", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	if teardown {
+		deleted, failures := teardownFixtures(ctx, ops, rows)
+		for _, err := range failures {
+			log.Printf("FAILED: %v", err)
+		}
+		log.Printf("Teardown finished: %d of %d fixture rows deleted, %d failed", deleted, len(rows), len(failures))
+		if len(failures) > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	s := &seeder{ops: ops, transactor: container.GetTransactor(), atomic: atomic}
+	if err := s.seedTemplate(ctx, fixturePlan(rows)); err != nil {
+		if atomic {
+			log.Printf("FAILED: fixture set rolled back: %v", err)
+		} else {
+			log.Printf("FAILED: fixture set may be partially written: %v", err)
+		}
+		return 1
+	}
+	log.Printf("Loaded %d fixture rows", len(rows))
+	return 0
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/ports"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+	"github.com/goccy/go-yaml"
+)
+
+// seedFile is the on-disk seed format, as JSON or the equivalent YAML. Each
+// workflow template carries its own columns plus a nested "stages" list;
+// each stage carries a nested "activities" list:
+//
+//	{"workflow_templates": [{
+//	  "id": "wt-onboarding", "name": "Client Onboarding",
+//	  "stages": [{
+//	    "name": "Intake",
+//	    "activities": [{"name": "Collect documents", "activity_type": "human"}]
+//	  }]
+//	}]}
+//
+// Stage and activity IDs default to "<parent-id>-stage-<n>" and
+// "<parent-id>-activity-<n>", order_index defaults to the list position, and
+// the workflow_template_id / stage_template_id links are filled in.
+type seedFile struct {
+	WorkflowTemplates []map[string]any `json:"workflow_templates"`
+}
+
+// templatePlan is every row one workflow template needs, in foreign-key
+// order: the template, its stage templates, then their activity templates.
+type templatePlan struct {
+	ID     string
+	Name   string
+	Writes []interfaces.TableRows
+}
+
+func (p templatePlan) rowCount() int {
+	n := 0
+	for _, w := range p.Writes {
+		n += len(w.Rows)
+	}
+	return n
+}
+
+// loadSeedFile reads and decodes a seed file. Files ending in .yaml or .yml
+// are read as YAML, which is how -export writes vya-compatible dumps.
+func loadSeedFile(path string) (*seedFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed file: %w", err)
+	}
+	if isYAMLPath(path) {
+		if raw, err = yaml.YAMLToJSON(raw); err != nil {
+			return nil, fmt.Errorf("failed to parse seed file %s: %w", path, err)
+		}
+	}
+	var f seedFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse seed file %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// buildPlans turns the seed file into one plan per workflow template.
+// tableName maps an entityid constant to its table or collection name.
+func buildPlans(f *seedFile, tableName func(string) string) ([]templatePlan, error) {
+	plans := make([]templatePlan, 0, len(f.WorkflowTemplates))
+	seen := make(map[string]bool, len(f.WorkflowTemplates))
+	for i, wt := range f.WorkflowTemplates {
+		wtID, _ := wt["id"].(string)
+		if wtID == "" {
+			return nil, fmt.Errorf("workflow_templates[%d]: id is required", i)
+		}
+		if seen[wtID] {
+			return nil, fmt.Errorf("workflow_templates[%d]: duplicate id %q", i, wtID)
+		}
+		seen[wtID] = true
+
+		stages, err := children(wt, "stages")
+		if err != nil {
+			return nil, fmt.Errorf("workflow template %s: %w", wtID, err)
+		}
+		templateRow := columns(wt, "stages")
+
+		var stageRows, activityRows []map[string]any
+		for si, stage := range stages {
+			activities, err := children(stage, "activities")
+			if err != nil {
+				return nil, fmt.Errorf("workflow template %s stage %d: %w", wtID, si+1, err)
+			}
+			stageRow := columns(stage, "activities")
+			stageID := defaultString(stageRow, "id", fmt.Sprintf("%s-stage-%d", wtID, si+1))
+			stageRow["workflow_template_id"] = wtID
+			defaultValue(stageRow, "order_index", int64(si+1))
+			stageRows = append(stageRows, stageRow)
+
+			for ai, activity := range activities {
+				activityRow := columns(activity)
+				defaultString(activityRow, "id", fmt.Sprintf("%s-activity-%d", stageID, ai+1))
+				activityRow["stage_template_id"] = stageID
+				defaultValue(activityRow, "order_index", int64(ai+1))
+				activityRows = append(activityRows, activityRow)
+			}
+		}
+
+		name, _ := templateRow["name"].(string)
+		plans = append(plans, templatePlan{
+			ID:   wtID,
+			Name: name,
+			Writes: []interfaces.TableRows{
+				{Table: tableName(entityid.WorkflowTemplate), Rows: []map[string]any{templateRow}},
+				{Table: tableName(entityid.StageTemplate), Rows: stageRows},
+				{Table: tableName(entityid.ActivityTemplate), Rows: activityRows},
+			},
+		})
+	}
+	return plans, nil
+}
+
+// children returns the nested object list under key.
+func children(obj map[string]any, key string) ([]map[string]any, error) {
+	raw, ok := obj[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list", key)
+	}
+	out := make([]map[string]any, 0, len(list))
+	for i, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be an object", key, i)
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// columns copies obj without the nested keys. Whole JSON numbers become
+// int64 so integer columns and Firestore fields keep their type.
+func columns(obj map[string]any, nested ...string) map[string]any {
+	row := make(map[string]any, len(obj))
+	for k, v := range obj {
+		row[k] = v
+	}
+	for _, k := range nested {
+		delete(row, k)
+	}
+	for k, v := range row {
+		if f, ok := v.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			row[k] = int64(f)
+		}
+	}
+	return row
+}
+
+func defaultString(row map[string]any, key, value string) string {
+	if s, ok := row[key].(string); ok && s != "" {
+		return s
+	}
+	row[key] = value
+	return value
+}
+
+func defaultValue(row map[string]any, key string, value any) {
+	if _, ok := row[key]; !ok {
+		row[key] = value
+	}
+}
+
+// errAtomicUnsupported is returned in -atomic mode when the database
+// provider offers neither transactions nor atomic batched writes.
+var errAtomicUnsupported = errors.New("the database provider supports neither transactions nor atomic batched writes")
+
+// seeder writes template plans through the generic database operations.
+type seeder struct {
+	ops        interfaces.DatabaseOperation
+	transactor ports.Transactor
+	// atomic writes each template's rows all-or-nothing: in one database
+	// transaction where the provider has them (Postgres), otherwise as one
+	// atomic batched write (Firestore).
+	atomic bool
+}
+
+// seedTemplate writes one template plan.
+func (s *seeder) seedTemplate(ctx context.Context, plan templatePlan) error {
+	if !s.atomic {
+		return createAll(ctx, s.ops, plan.Writes)
+	}
+	if batcher, ok := s.ops.(interfaces.AtomicCreator); ok {
+		_, err := batcher.CreateAtomic(ctx, plan.Writes)
+		return err
+	}
+	if s.transactor == nil || !s.transactor.SupportsTransactions() {
+		return errAtomicUnsupported
+	}
+	return s.transactor.ExecuteInTransaction(ctx, func(txCtx context.Context) error {
+		return createAll(txCtx, s.ops, plan.Writes)
+	})
+}
+
+// createAll creates every row in order, stopping at the first failure.
+func createAll(ctx context.Context, ops interfaces.DatabaseOperation, writes []interfaces.TableRows) error {
+	for _, w := range writes {
+		for _, row := range w.Rows {
+			if _, err := ops.Create(ctx, w.Table, row); err != nil {
+				return fmt.Errorf("create %s %v: %w", w.Table, row["id"], err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/database/interfaces"
+)
+
+const testSeed = `{"workflow_templates": [{
+	"id": "wt-onboarding", "name": "Client Onboarding",
+	"stages": [
+		{"name": "Intake", "activities": [{"name": "Collect documents"}, {"id": "act-review", "name": "Review", "order_index": 5}]},
+		{"id": "st-close", "name": "Close"}
+	]
+}]}`
+
+func parseTestSeed(t *testing.T) *seedFile {
+	t.Helper()
+	var f seedFile
+	if err := json.Unmarshal([]byte(testSeed), &f); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return &f
+}
+
+func TestBuildPlans_LinksAndDefaults(t *testing.T) {
+	plans, err := buildPlans(parseTestSeed(t), func(e string) string { return "t_" + e })
+	if err != nil {
+		t.Fatalf("buildPlans: %v", err)
+	}
+	if len(plans) != 1 || plans[0].ID != "wt-onboarding" || plans[0].rowCount() != 5 {
+		t.Fatalf("unexpected plans: %+v", plans)
+	}
+	w := plans[0].Writes
+	if w[0].Table != "t_workflow_template" || w[1].Table != "t_stage_template" || w[2].Table != "t_activity_template" {
+		t.Fatalf("writes not in foreign-key order: %s, %s, %s", w[0].Table, w[1].Table, w[2].Table)
+	}
+	if _, nested := w[0].Rows[0]["stages"]; nested {
+		t.Fatalf("template row still carries nested stages")
+	}
+
+	intake, closing := w[1].Rows[0], w[1].Rows[1]
+	if intake["id"] != "wt-onboarding-stage-1" || intake["workflow_template_id"] != "wt-onboarding" || intake["order_index"] != int64(1) {
+		t.Fatalf("intake stage = %v", intake)
+	}
+	if closing["id"] != "st-close" || closing["order_index"] != int64(2) {
+		t.Fatalf("close stage = %v", closing)
+	}
+
+	collect, review := w[2].Rows[0], w[2].Rows[1]
+	if collect["id"] != "wt-onboarding-stage-1-activity-1" || collect["stage_template_id"] != "wt-onboarding-stage-1" {
+		t.Fatalf("collect activity = %v", collect)
+	}
+	if review["id"] != "act-review" || review["order_index"] != int64(5) {
+		t.Fatalf("review activity = %v", review)
+	}
+}
+
+func TestBuildPlans_RequiresTemplateID(t *testing.T) {
+	f := &seedFile{WorkflowTemplates: []map[string]any{{"name": "No ID"}}}
+	if _, err := buildPlans(f, func(e string) string { return e }); err == nil {
+		t.Fatal("expected an error for a template without id")
+	}
+}
+
+// failingOps fails Create for one table and records the rest.
+type failingOps struct {
+	interfaces.DatabaseOperation
+	failTable string
+	created   []string
+}
+
+func (o *failingOps) Create(_ context.Context, table string, data map[string]any) (map[string]any, error) {
+	if table == o.failTable {
+		return nil, errors.New("boom")
+	}
+	o.created = append(o.created, table)
+	return data, nil
+}
+
+// recordingTransactor runs the operation and records whether it failed.
+type recordingTransactor struct {
+	calls      int
+	rolledBack bool
+}
+
+func (r *recordingTransactor) ExecuteInTransaction(ctx context.Context, fn func(context.Context) error) error {
+	r.calls++
+	err := fn(ctx)
+	r.rolledBack = err != nil
+	return err
+}
+func (r *recordingTransactor) SupportsTransactions() bool                 { return true }
+func (r *recordingTransactor) IsTransactionActive(_ context.Context) bool { return false }
+
+func TestSeedTemplate_AtomicUsesOneTransaction(t *testing.T) {
+	plans, err := buildPlans(parseTestSeed(t), func(e string) string { return e })
+	if err != nil {
+		t.Fatalf("buildPlans: %v", err)
+	}
+	ops := &failingOps{failTable: "activity_template"}
+	tx := &recordingTransactor{}
+	s := &seeder{ops: ops, transactor: tx, atomic: true}
+
+	if err := s.seedTemplate(context.Background(), plans[0]); err == nil {
+		t.Fatal("expected the activity failure to surface")
+	}
+	if tx.calls != 1 || !tx.rolledBack {
+		t.Fatalf("transaction calls = %d, rolled back = %v; want 1, true", tx.calls, tx.rolledBack)
+	}
+}
+
+func TestSeedTemplate_AtomicWithoutTransactions(t *testing.T) {
+	plans, err := buildPlans(parseTestSeed(t), func(e string) string { return e })
+	if err != nil {
+		t.Fatalf("buildPlans: %v", err)
+	}
+	s := &seeder{ops: &failingOps{}, atomic: true}
+	if err := s.seedTemplate(context.Background(), plans[0]); !errors.Is(err, errAtomicUnsupported) {
+		t.Fatalf("err = %v, want errAtomicUnsupported", err)
+	}
+}
+
+func TestNestTemplates_OrdersAndDropsLinks(t *testing.T) {
+	templates := []map[string]any{{"id": "wt-b", "name": "B"}, {"id": "wt-a", "name": "A", "date_created": int64(1)}}
+	stages := []map[string]any{
+		{"id": "st-2", "workflow_template_id": "wt-a", "name": "Second", "order_index": int64(2)},
+		{"id": "st-1", "workflow_template_id": "wt-a", "name": "First", "order_index": int64(1)},
+		{"id": "st-x", "workflow_template_id": "wt-gone", "name": "Orphan"},
+	}
+	activities := []map[string]any{{"id": "act-1", "stage_template_id": "st-1", "name": "Do", "order_index": int64(1)}}
+
+	rows, orphans := nestTemplates(templates, stages, activities)
+	if orphans != 1 {
+		t.Fatalf("orphans = %d, want 1", orphans)
+	}
+	out, err := json.Marshal(rows)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `[{"id":"wt-a","name":"A","stages":[` +
+		`{"id":"st-1","name":"First","order_index":1,"activities":[{"id":"act-1","name":"Do","order_index":1}]},` +
+		`{"id":"st-2","name":"Second","order_index":2}]},` +
+		`{"id":"wt-b","name":"B"}]`
+	if string(out) != want {
+		t.Fatalf("export =\n%s\nwant\n%s", out, want)
+	}
+
+	// The export must load back as an equivalent seed.
+	var f seedFile
+	if err := json.Unmarshal([]byte(`{"workflow_templates":`+string(out)+`}`), &f); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	plans, err := buildPlans(&f, func(e string) string { return e })
+	if err != nil {
+		t.Fatalf("buildPlans: %v", err)
+	}
+	if got := plans[0].Writes[2].Rows[0]["stage_template_id"]; got != "st-1" {
+		t.Fatalf("re-seeded activity links to %v, want st-1", got)
+	}
+}
+
+func TestDiffPlan_UpdatesChangedAndInsertsNew(t *testing.T) {
+	plans, err := buildPlans(parseTestSeed(t), func(e string) string { return e })
+	if err != nil {
+		t.Fatalf("buildPlans: %v", err)
+	}
+	stored := &storedRows{
+		templates: map[string]map[string]any{
+			"wt-onboarding": {"id": "wt-onboarding", "name": "Client Onboarding", "version": int32(1), "date_created": int64(1)},
+		},
+		stages: map[string]map[string]any{
+			"wt-onboarding-stage-1": {"id": "wt-onboarding-stage-1", "workflow_template_id": "wt-onboarding", "name": "Intake", "order_index": float64(1)},
+			"st-close":              {"id": "st-close", "workflow_template_id": "wt-onboarding", "name": "Closing", "order_index": int32(2)},
+		},
+		activities: map[string]map[string]any{
+			"wt-onboarding-stage-1-activity-1": {"id": "wt-onboarding-stage-1-activity-1", "stage_template_id": "wt-onboarding-stage-1", "name": "Collect documents", "order_index": int64(1)},
+			"act-old":                          {"id": "act-old", "stage_template_id": "st-close", "name": "Retired"},
+		},
+	}
+
+	up, err := diffPlan(plans[0], stored)
+	if err != nil {
+		t.Fatalf("diffPlan: %v", err)
+	}
+	if len(up.Updates) != 1 || up.Updates[0].ID != "st-close" || len(up.Updates[0].Data) != 1 || up.Updates[0].Data["name"] != "Close" {
+		t.Fatalf("updates = %+v, want only st-close's name", up.Updates)
+	}
+	if up.rowCount() != 1 || up.Writes[2].Rows[0]["id"] != "act-review" {
+		t.Fatalf("inserts = %+v, want only act-review", up.Writes)
+	}
+	want := "template unchanged; stages: 0 new, 1 updated, 1 unchanged; activities: 1 new, 0 updated, 1 unchanged, 1 stale"
+	if got := up.Diff.String(); got != want {
+		t.Fatalf("diff = %q, want %q", got, want)
+	}
+
+	// A stored template with another version is a conflict, not an update.
+	plans[0].Writes[0].Rows[0]["version"] = int64(2)
+	if _, err := diffPlan(plans[0], stored); err == nil {
+		t.Fatal("expected a version conflict")
+	}
+}
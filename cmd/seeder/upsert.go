@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// Upsert mode compares each seed template with the rows already in the
+// database and writes only the difference, so re-running a seed file is
+// safe. A template is identified by (workspace_id, id, version);
+// workspace_id and version are only compared when the seed sets them.
+// Rows are never deleted: stages and activities that are stored but no
+// longer in the seed are reported as stale.
+
+// errAtomicUpdatesUnsupported is returned in -atomic -upsert mode when a
+// template needs updates and the provider has no transactions. Atomic
+// batched writes (Firestore) only cover inserts.
+var errAtomicUpdatesUnsupported = errors.New("-atomic updates need database transactions, which the provider does not support")
+
+// tableDiff counts what upsert does to one table's rows for a template.
+type tableDiff struct {
+	Inserted, Updated, Unchanged, Stale int
+}
+
+func (d tableDiff) String() string {
+	s := fmt.Sprintf("%d new, %d updated, %d unchanged", d.Inserted, d.Updated, d.Unchanged)
+	if d.Stale > 0 {
+		s += fmt.Sprintf(", %d stale", d.Stale)
+	}
+	return s
+}
+
+// templateDiff summarizes the changes upsert makes for one template.
+type templateDiff struct {
+	New bool
+	// Columns lists the template columns that changed.
+	Columns    []string
+	Stages     tableDiff
+	Activities tableDiff
+}
+
+func (d templateDiff) String() string {
+	template := "unchanged"
+	switch {
+	case d.New:
+		template = "new"
+	case len(d.Columns) > 0:
+		template = "updated (" + strings.Join(d.Columns, ", ") + ")"
+	}
+	return fmt.Sprintf("template %s; stages: %s; activities: %s", template, d.Stages, d.Activities)
+}
+
+// rowUpdate writes the changed columns of one stored row.
+type rowUpdate struct {
+	Table string
+	ID    string
+	Data  map[string]any
+}
+
+// upsertPlan is what one template needs to match the seed. Its Writes hold
+// only the rows to insert.
+type upsertPlan struct {
+	templatePlan
+	Updates []rowUpdate
+	Diff    templateDiff
+}
+
+func (p upsertPlan) empty() bool {
+	return len(p.Updates) == 0 && p.rowCount() == 0
+}
+
+// storedRows is the database's template rows, keyed by id.
+type storedRows struct {
+	templates, stages, activities map[string]map[string]any
+}
+
+// loadStored reads every workflow, stage and activity template.
+func loadStored(ctx context.Context, ops interfaces.DatabaseOperation, tableName func(string) string) (*storedRows, error) {
+	byID := func(entity string) (map[string]map[string]any, error) {
+		rows, err := listAll(ctx, ops, tableName(entity))
+		if err != nil {
+			return nil, err
+		}
+		out := make(map[string]map[string]any, len(rows))
+		for _, row := range rows {
+			if id, _ := row["id"].(string); id != "" {
+				out[id] = row
+			}
+		}
+		return out, nil
+	}
+
+	var stored storedRows
+	var err error
+	if stored.templates, err = byID(entityid.WorkflowTemplate); err != nil {
+		return nil, err
+	}
+	if stored.stages, err = byID(entityid.StageTemplate); err != nil {
+		return nil, err
+	}
+	if stored.activities, err = byID(entityid.ActivityTemplate); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// diffPlan compares a template plan with the stored rows. It fails when the
+// template id is taken by a different workspace or version.
+func diffPlan(plan templatePlan, stored *storedRows) (upsertPlan, error) {
+	up := upsertPlan{templatePlan: templatePlan{ID: plan.ID, Name: plan.Name}}
+	templateRows, stageRows, activityRows := plan.Writes[0], plan.Writes[1], plan.Writes[2]
+
+	current, exists := stored.templates[plan.ID]
+	if !exists {
+		up.Writes = plan.Writes
+		up.Diff = templateDiff{
+			New:        true,
+			Stages:     tableDiff{Inserted: len(stageRows.Rows)},
+			Activities: tableDiff{Inserted: len(activityRows.Rows)},
+		}
+		return up, nil
+	}
+	for _, key := range []string{"workspace_id", "version"} {
+		want, set := templateRows.Rows[0][key]
+		if set && !sameValue(want, current[key]) {
+			return up, fmt.Errorf("workflow template %s is stored with %s %v, the seed has %v; give a new version its own id",
+				plan.ID, key, current[key], want)
+		}
+	}
+
+	var stats [3]tableDiff
+	for i, w := range plan.Writes {
+		inserts := interfaces.TableRows{Table: w.Table}
+		table := []map[string]map[string]any{stored.templates, stored.stages, stored.activities}[i]
+		for _, row := range w.Rows {
+			id, _ := row["id"].(string)
+			existing, ok := table[id]
+			if !ok {
+				inserts.Rows = append(inserts.Rows, row)
+				stats[i].Inserted++
+				continue
+			}
+			changed := changedColumns(row, existing)
+			if len(changed) == 0 {
+				stats[i].Unchanged++
+				continue
+			}
+			up.Updates = append(up.Updates, rowUpdate{Table: w.Table, ID: id, Data: changed})
+			stats[i].Updated++
+			if i == 0 {
+				up.Diff.Columns = sortedKeys(changed)
+			}
+		}
+		up.Writes = append(up.Writes, inserts)
+	}
+
+	// Stale rows: stored under this template but absent from the seed.
+	stageIDs := idSet(stageRows.Rows)
+	for id, row := range stored.stages {
+		if row["workflow_template_id"] == plan.ID && !stageIDs[id] {
+			stageIDs[id] = true
+			stats[1].Stale++
+		}
+	}
+	activityIDs := idSet(activityRows.Rows)
+	for id, row := range stored.activities {
+		parent, _ := row["stage_template_id"].(string)
+		if stageIDs[parent] && !activityIDs[id] {
+			stats[2].Stale++
+		}
+	}
+
+	up.Diff.Stages, up.Diff.Activities = stats[1], stats[2]
+	return up, nil
+}
+
+// changedColumns returns the seed columns whose value differs from the
+// stored row. Columns the seed leaves out are not touched, and the
+// database-stamped audit columns are ignored.
+func changedColumns(seed, stored map[string]any) map[string]any {
+	changed := make(map[string]any)
+	for k, v := range seed {
+		if k == "id" || exportOmit[k] {
+			continue
+		}
+		if !sameValue(v, stored[k]) {
+			changed[k] = v
+		}
+	}
+	return changed
+}
+
+// sameValue compares a seed value with a stored one. Numbers compare by
+// value whatever type the driver returned; other values by their JSON form.
+func sameValue(a, b any) bool {
+	if x, ok := toNumber(a); ok {
+		y, ok := toNumber(b)
+		return ok && x == y
+	}
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+func idSet(rows []map[string]any) map[string]bool {
+	ids := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if id, _ := row["id"].(string); id != "" {
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// upsertTemplate applies one upsert plan: inserts first, in foreign-key
+// order, then updates. Insert-only plans take the seedTemplate path, so
+// -atomic can still use batched writes.
+func (s *seeder) upsertTemplate(ctx context.Context, up upsertPlan) error {
+	if len(up.Updates) == 0 {
+		return s.seedTemplate(ctx, up.templatePlan)
+	}
+	if !s.atomic {
+		return applyUpsert(ctx, s.ops, up)
+	}
+	if s.transactor == nil || !s.transactor.SupportsTransactions() {
+		return errAtomicUpdatesUnsupported
+	}
+	return s.transactor.ExecuteInTransaction(ctx, func(txCtx context.Context) error {
+		return applyUpsert(txCtx, s.ops, up)
+	})
+}
+
+func applyUpsert(ctx context.Context, ops interfaces.DatabaseOperation, up upsertPlan) error {
+	if err := createAll(ctx, ops, up.Writes); err != nil {
+		return err
+	}
+	for _, u := range up.Updates {
+		if _, err := ops.Update(ctx, u.Table, u.ID, u.Data); err != nil {
+			return fmt.Errorf("update %s %s: %w", u.Table, u.ID, err)
+		}
+	}
+	return nil
+}
+
+// upsertAll diffs and applies every plan, logging a summary per template,
+// and returns the number of templates that failed.
+func upsertAll(ctx context.Context, s *seeder, plans []templatePlan, stored *storedRows) int {
+	var created, updated, unchanged, failed, stale int
+	for _, plan := range plans {
+		up, err := diffPlan(plan, stored)
+		if err != nil {
+			failed++
+			log.Printf("FAILED: %v", err)
+			continue
+		}
+		stale += up.Diff.Stages.Stale + up.Diff.Activities.Stale
+		if up.empty() {
+			unchanged++
+			log.Printf("Unchanged workflow template %s (%s)", plan.ID, plan.Name)
+			continue
+		}
+		if err := s.upsertTemplate(ctx, up); err != nil {
+			failed++
+			if s.atomic {
+				log.Printf("FAILED: workflow template %s (%s) rolled back: %v", plan.ID, plan.Name, err)
+			} else {
+				log.Printf("FAILED: workflow template %s (%s) may be partially written: %v", plan.ID, plan.Name, err)
+			}
+			continue
+		}
+		if up.Diff.New {
+			created++
+		} else {
+			updated++
+		}
+		log.Printf("Upserted workflow template %s (%s): %s", plan.ID, plan.Name, up.Diff)
+	}
+
+	log.Printf("Upsert finished: %d new, %d updated, %d unchanged, %d failed", created, updated, unchanged, failed)
+	if stale > 0 {
+		log.Printf("NOTE: %d stored stage/activity templates are not in the seed file; upsert never deletes, remove them by hand if intended", stale)
+	}
+	return failed
+}
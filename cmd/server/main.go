@@ -1,11 +1,16 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
 
+	"github.com/erniealice/espyna-golang/composition/routing"
+	"github.com/erniealice/espyna-golang/composition/routing/customization"
+	"github.com/erniealice/espyna-golang/composition/routing/openapi"
 	"github.com/erniealice/espyna-golang/consumer"
+	"github.com/erniealice/espyna-golang/shared/correlation"
 )
 
 /*
@@ -29,17 +34,32 @@ Example Build Commands:
 Environment Variables:
   - SERVER_HOST: Server host (default: localhost)
   - SERVER_PORT: Server port (default: 8080)
-  - CONFIG_DATABASE_PROVIDER: Database provider (mock_db, postgresql, firestore)
+  - CONFIG_DATABASE_PROVIDER: Database provider (mock_db, postgresql, firestore, sqlite)
   - CONFIG_AUTH_PROVIDER: Auth provider (mock, password, firebase)
   - CONFIG_ID_PROVIDER: ID provider (noop, google_uuidv7)
   - CONFIG_STORAGE_PROVIDER: Storage provider (mock_storage, local)
   - CONFIG_SERVER_PROVIDER: Server hint (gin, fiber, fiber_v3, vanilla) - for logging only
+  - CONFIG_LOG_LEVEL: JSON log level (debug, info, warn, error; default: info)
 
 The actual server implementation is determined by build tags at compile time.
 CONFIG_SERVER_PROVIDER is only used for logging/configuration validation.
+
+OpenAPI:
+  The running server serves an OpenAPI 3 document of its routes at
+  /api/openapi.json. To export it without starting the server:
+
+  go run -tags vanilla,mock_db,mock_auth,mock_storage main.go -openapi openapi.json
+  go run -tags vanilla,mock_db,mock_auth,mock_storage main.go -openapi -   # stdout
 */
 
 func main() {
+	openapiOut := flag.String("openapi", "", "write the OpenAPI document to this file (- for stdout) and exit")
+	flag.Parse()
+
+	// Log as JSON with request correlation IDs before any provider builds
+	// its logger
+	correlation.InstallDefault()
+
 	// Create container from environment variables
 	container, err := consumer.NewContainerFromEnv()
 	if err != nil {
@@ -49,6 +69,13 @@ func main() {
 
 	log.Println("SUCCESS: Container initialized")
 
+	if *openapiOut != "" {
+		if err := exportOpenAPI(container.GetRouteManager().GetAllRoutes(), *openapiOut); err != nil {
+			log.Fatalf("Failed to export OpenAPI document: %v", err)
+		}
+		return
+	}
+
 	// Create server adapter (implementation selected by build tags)
 	adapter := consumer.NewServerAdapterFromContainer(container)
 	if adapter == nil {
@@ -75,6 +102,24 @@ func main() {
 	}
 }
 
+// exportOpenAPI writes the OpenAPI document for routes, with the same path
+// customizations the server adapters apply.
+func exportOpenAPI(routes []*routing.Route, out string) error {
+	doc, err := openapi.Marshal(customization.NewRouteCustomizer().ApplyCustomizations(routes), openapi.DefaultInfo)
+	if err != nil {
+		return err
+	}
+	if out == "-" {
+		_, err = os.Stdout.Write(doc)
+		return err
+	}
+	if err := os.WriteFile(out, doc, 0o644); err != nil {
+		return err
+	}
+	log.Printf("SUCCESS: OpenAPI document for %d routes written to %s", len(routes), out)
+	return nil
+}
+
 // getEnv returns environment variable value or default if not set
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
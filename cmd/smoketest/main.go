@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+/*
+ ESPYNA SMOKETEST - Post-deploy smoke suite
+
+Sends a suite of smoke requests to a deployed environment and exits
+non-zero when any of them fails, so a deploy pipeline can gate on it. A
+suite (see suite.example.yaml) lists checks in order: typically health,
+sign-in, one read per critical entity and a checkout dry run against the
+sandbox payment provider. Each check states the status, JSON values and
+latency its response must have; values captured from one response (a
+session token) are sent by the checks after it. A failed required check
+skips the rest.
+
+The tool talks only HTTP; it needs no build tags or database access.
+
+Examples:
+  SMOKE_BASE_URL=https://staging.example.com go run ./cmd/smoketest -suite cmd/smoketest/suite.example.yaml
+  go run ./cmd/smoketest -suite smoke.yaml -base-url http://localhost:8080 -only health,"read client"
+  go run ./cmd/smoketest -suite smoke.yaml -report smoke-report.json
+
+Flags:
+  -suite     Suite file, JSON or YAML (required)
+  -base-url  Environment to test, overriding the suite's base_url
+  -only      Comma-separated check names to run instead of the whole suite
+  -report    Also write the report as JSON to this file ("-" for stdout)
+
+Exit codes: 0 when every check passed, 1 when any failed, 2 when the suite
+or flags are invalid.
+*/
+
+func main() {
+	suitePath := flag.String("suite", "", "suite file to run (JSON or YAML)")
+	baseURL := flag.String("base-url", "", "environment to test (default: the suite's base_url)")
+	only := flag.String("only", "", "comma-separated check names to run (default: every check)")
+	reportPath := flag.String("report", "", "write the report as JSON to this file, or - for stdout")
+	flag.Parse()
+
+	if *suitePath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	os.Exit(run(*suitePath, *baseURL, splitList(*only), *reportPath))
+}
+
+// run runs the suite and returns the process exit code.
+func run(suitePath, baseURL string, only []string, reportPath string) int {
+	s, err := loadSuite(suitePath)
+	if err != nil {
+		log.Print(err)
+		return 2
+	}
+	if baseURL != "" {
+		s.baseURL = strings.TrimRight(baseURL, "/")
+	}
+	if s.baseURL == "" {
+		log.Print("No environment to test: set base_url in the suite or pass -base-url")
+		return 2
+	}
+	if len(only) > 0 {
+		if err := s.only(only); err != nil {
+			log.Print(err)
+			return 2
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	rep := newRunner(s).run(ctx)
+
+	if reportPath != "-" {
+		rep.print(os.Stdout)
+	}
+	if reportPath != "" {
+		if err := writeReport(reportPath, rep); err != nil {
+			log.Printf("Failed to write report: %v", err)
+			return 1
+		}
+	}
+	if !rep.ok() {
+		return 1
+	}
+	return 0
+}
+
+func writeReport(path string, rep *report) error {
+	raw, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	raw = append(raw, '\n')
+	if path == "-" {
+		_, err = os.Stdout.Write(raw)
+		return err
+	}
+	return os.WriteFile(path, raw, 0o644)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
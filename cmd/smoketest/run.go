@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxBody bounds how much of a response is read.
+const maxBody = 4 << 20
+
+// result is the outcome of one check.
+type result struct {
+	Name      string  `json:"name"`
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status,omitempty"`
+	LatencyMS float64 `json:"latency_ms,omitempty"`
+	Passed    bool    `json:"passed"`
+	Skipped   bool    `json:"skipped,omitempty"`
+	// Failures lists every expectation the response missed.
+	Failures []string `json:"failures,omitempty"`
+}
+
+// report is the outcome of a suite run.
+type report struct {
+	BaseURL  string    `json:"base_url"`
+	Started  time.Time `json:"started"`
+	Passed   int       `json:"passed"`
+	Failed   int       `json:"failed"`
+	Skipped  int       `json:"skipped"`
+	Results  []result  `json:"results"`
+	Duration float64   `json:"duration_ms"`
+}
+
+func (r *report) ok() bool { return r.Failed == 0 }
+
+// runner sends a suite's checks in order. Cookies persist across checks,
+// so a session sign-in works like a captured bearer token.
+type runner struct {
+	suite  *suite
+	client *http.Client
+	vars   map[string]string
+}
+
+func newRunner(s *suite) *runner {
+	jar, _ := cookiejar.New(nil)
+	return &runner{
+		suite:  s,
+		client: &http.Client{Timeout: s.timeout, Jar: jar},
+		vars:   make(map[string]string),
+	}
+}
+
+// run sends every check and reports the outcome. After a failed required
+// check the rest are skipped.
+func (r *runner) run(ctx context.Context) *report {
+	rep := &report{BaseURL: r.suite.baseURL, Started: time.Now().UTC()}
+	stopped := false
+	for _, c := range r.suite.checks {
+		var res result
+		if stopped {
+			res = result{Name: c.Name, Method: c.Method, Path: c.Path, Skipped: true}
+		} else {
+			res = r.check(ctx, c)
+		}
+		switch {
+		case res.Skipped:
+			rep.Skipped++
+		case res.Passed:
+			rep.Passed++
+		default:
+			rep.Failed++
+			stopped = stopped || c.Required
+		}
+		rep.Results = append(rep.Results, res)
+	}
+	rep.Duration = msSince(rep.Started)
+	return rep
+}
+
+// check sends one request and compares the response with the check.
+func (r *runner) check(ctx context.Context, c check) result {
+	path := r.substitute(c.Path)
+	res := result{Name: c.Name, Method: c.Method, Path: path}
+	fail := func(format string, args ...any) {
+		res.Failures = append(res.Failures, fmt.Sprintf(format, args...))
+	}
+
+	var body io.Reader
+	if c.Body != nil {
+		raw, err := json.Marshal(r.substituteValue(c.Body))
+		if err != nil {
+			fail("encode body: %v", err)
+			return res
+		}
+		body = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, c.Method, r.suite.baseURL+path, body)
+	if err != nil {
+		fail("build request: %v", err)
+		return res
+	}
+	if c.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range r.suite.headers {
+		req.Header.Set(name, r.substitute(value))
+	}
+	for name, value := range c.Headers {
+		req.Header.Set(name, r.substitute(value))
+	}
+
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		fail("request failed: %v", err)
+		return res
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	resp.Body.Close()
+	res.LatencyMS = msSince(start)
+	res.Status = resp.StatusCode
+	if err != nil {
+		fail("read response: %v", err)
+		return res
+	}
+
+	if resp.StatusCode != c.Status {
+		fail("status %d, want %d%s", resp.StatusCode, c.Status, excerpt(raw))
+	}
+	if c.maxLatency > 0 && res.LatencyMS > float64(c.maxLatency.Microseconds())/1000 {
+		fail("took %.0fms, limit %s", res.LatencyMS, c.maxLatency)
+	}
+	if c.Contains != "" && !bytes.Contains(raw, []byte(r.substitute(c.Contains))) {
+		fail("body does not contain %q", c.Contains)
+	}
+
+	if len(c.Expect) > 0 || len(c.Exists) > 0 || len(c.Capture) > 0 {
+		var doc any
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			fail("response is not JSON%s", excerpt(raw))
+			return res
+		}
+		for path, want := range c.Expect {
+			got, ok := lookupPath(doc, path)
+			if !ok {
+				fail("%s missing", path)
+			} else if !reflect.DeepEqual(got, r.substituteValue(want)) {
+				fail("%s = %s, want %s", path, jsonText(got), jsonText(want))
+			}
+		}
+		for _, path := range c.Exists {
+			if got, ok := lookupPath(doc, path); !ok || empty(got) {
+				fail("%s missing or empty", path)
+			}
+		}
+		for name, path := range c.Capture {
+			got, ok := lookupPath(doc, path)
+			if !ok || empty(got) {
+				fail("capture %s: %s missing or empty", name, path)
+				continue
+			}
+			if s, isString := got.(string); isString {
+				r.vars[name] = s
+			} else {
+				r.vars[name] = jsonText(got)
+			}
+		}
+	}
+
+	res.Passed = len(res.Failures) == 0
+	return res
+}
+
+var varRef = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// substitute replaces {{name}} with captured values. Unknown names stay as
+// written, so the check fails visibly instead of sending an empty value.
+func (r *runner) substitute(s string) string {
+	return varRef.ReplaceAllStringFunc(s, func(ref string) string {
+		if v, ok := r.vars[varRef.FindStringSubmatch(ref)[1]]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// substituteValue substitutes every string in a decoded JSON value.
+func (r *runner) substituteValue(v any) any {
+	switch node := v.(type) {
+	case string:
+		return r.substitute(node)
+	case map[string]any:
+		out := make(map[string]any, len(node))
+		for k, child := range node {
+			out[k] = r.substituteValue(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(node))
+		for i, child := range node {
+			out[i] = r.substituteValue(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// print writes the report as one line per check and a summary.
+func (rep *report) print(w io.Writer) {
+	for _, res := range rep.Results {
+		switch {
+		case res.Skipped:
+			fmt.Fprintf(w, "SKIP  %-28s %s %s\n", res.Name, res.Method, res.Path)
+		case res.Passed:
+			fmt.Fprintf(w, "PASS  %-28s %s %s  %d  %.0fms\n", res.Name, res.Method, res.Path, res.Status, res.LatencyMS)
+		default:
+			fmt.Fprintf(w, "FAIL  %-28s %s %s  %d  %.0fms\n", res.Name, res.Method, res.Path, res.Status, res.LatencyMS)
+			for _, f := range res.Failures {
+				fmt.Fprintf(w, "        %s\n", f)
+			}
+		}
+	}
+	fmt.Fprintf(w, "\n%s: %d passed, %d failed, %d skipped in %.1fs\n",
+		rep.BaseURL, rep.Passed, rep.Failed, rep.Skipped, rep.Duration/1000)
+}
+
+func empty(v any) bool {
+	switch node := v.(type) {
+	case nil:
+		return true
+	case string:
+		return node == ""
+	case []any:
+		return len(node) == 0
+	case map[string]any:
+		return len(node) == 0
+	}
+	return false
+}
+
+func jsonText(v any) string {
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
+
+// excerpt quotes the start of a response body for a failure message.
+func excerpt(raw []byte) string {
+	s := strings.TrimSpace(string(raw))
+	if s == "" {
+		return ""
+	}
+	if len(s) > 200 {
+		s = s[:200] + "..."
+	}
+	return ": " + s
+}
+
+func msSince(t time.Time) float64 {
+	return float64(time.Since(t).Microseconds()) / 1000
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func smokeServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	})
+	mux.HandleFunc("POST /auth/login", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["password"] != `pa"ss` {
+			http.Error(w, "bad credentials", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"token":"t-1"}}`))
+	})
+	mux.HandleFunc("POST /api/entity/client/list", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t-1" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true,"data":[{"id":"c-1"}]}`))
+	})
+	mux.HandleFunc("POST /api/entity/product/list", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success":true,"data":[]}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func writeSuite(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "smoke.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const testSuite = `
+base_url: ${SMOKE_BASE_URL}
+checks:
+  - name: health
+    path: /health
+    expect: {status: ok}
+    required: true
+  - name: sign-in
+    path: /auth/login
+    body: {email: smoke@example.com, password: "${SMOKE_PASSWORD}"}
+    capture: {token: data.token}
+    required: true
+  - name: read client
+    path: /api/entity/client/list
+    headers: {Authorization: "Bearer {{token}}"}
+    body: {}
+    expect: {success: true}
+    exists: [data.0.id]
+  - name: read product
+    path: /api/entity/product/list
+    body: {}
+    exists: [data.0.id]
+`
+
+func TestRun_Suite(t *testing.T) {
+	srv := smokeServer(t)
+	t.Setenv("SMOKE_BASE_URL", srv.URL)
+	t.Setenv("SMOKE_PASSWORD", `pa"ss`)
+
+	s, err := loadSuite(writeSuite(t, testSuite))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := newRunner(s).run(context.Background())
+
+	if rep.Passed != 3 || rep.Failed != 1 || rep.ok() {
+		t.Fatalf("passed %d, failed %d; want 3 and 1", rep.Passed, rep.Failed)
+	}
+	last := rep.Results[3]
+	if last.Passed || len(last.Failures) != 1 || !strings.Contains(last.Failures[0], "data.0.id") {
+		t.Errorf("read product = %+v", last)
+	}
+}
+
+func TestRun_RequiredFailureSkipsRest(t *testing.T) {
+	srv := smokeServer(t)
+	t.Setenv("SMOKE_BASE_URL", srv.URL)
+	t.Setenv("SMOKE_PASSWORD", "wrong")
+
+	s, err := loadSuite(writeSuite(t, testSuite))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := newRunner(s).run(context.Background())
+
+	if rep.Passed != 1 || rep.Failed != 1 || rep.Skipped != 2 {
+		t.Fatalf("passed %d, failed %d, skipped %d; want 1, 1, 2", rep.Passed, rep.Failed, rep.Skipped)
+	}
+	if !strings.Contains(rep.Results[1].Failures[0], "status 401, want 200") {
+		t.Errorf("sign-in failures = %v", rep.Results[1].Failures)
+	}
+}
+
+func TestLoadSuite_Invalid(t *testing.T) {
+	t.Setenv("SMOKE_BASE_URL", "http://localhost")
+	tests := map[string]string{
+		"missing env":    "base_url: ${SMOKE_UNSET_VAR}\nchecks: [{name: a, path: /a}]",
+		"no checks":      "base_url: x\nchecks: []",
+		"duplicate name": "checks: [{name: a, path: /a}, {name: a, path: /b}]",
+		"relative path":  "checks: [{name: a, path: a}]",
+		"bad latency":    "checks: [{name: a, path: /a, max_latency: soon}]",
+	}
+	for name, body := range tests {
+		if _, err := loadSuite(writeSuite(t, body)); err == nil {
+			t.Errorf("%s: loadSuite succeeded", name)
+		}
+	}
+}
+
+func TestSuite_Only(t *testing.T) {
+	s := &suite{checks: []check{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+	if err := s.only([]string{"c", "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.checks) != 2 || s.checks[0].Name != "a" || s.checks[1].Name != "c" {
+		t.Errorf("checks = %v", s.checks)
+	}
+	if err := s.only([]string{"zzz"}); err == nil {
+		t.Error("only accepted an unknown check")
+	}
+}
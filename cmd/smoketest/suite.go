@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// defaultTimeout bounds each request when the suite sets no timeout.
+const defaultTimeout = 10 * time.Second
+
+// suiteFile is the layout of a suite file (see suite.example.yaml).
+type suiteFile struct {
+	// BaseURL is the environment under test; -base-url overrides it.
+	BaseURL string `json:"base_url"`
+	// Timeout bounds each request, e.g. "5s" (10s by default).
+	Timeout string `json:"timeout"`
+	// Headers are sent with every request.
+	Headers map[string]string `json:"headers"`
+	Checks  []check           `json:"checks"`
+}
+
+// check is one smoke request and what its response must look like.
+type check struct {
+	Name string `json:"name"`
+	// Method defaults to POST when the check has a body and GET otherwise.
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers"`
+	// Body is sent as JSON.
+	Body any `json:"body"`
+
+	// Status is the expected response status (200 by default).
+	Status int `json:"status"`
+	// Expect maps dotted JSON paths of the response ("data.0.status") to
+	// the values they must hold.
+	Expect map[string]any `json:"expect"`
+	// Exists lists dotted JSON paths that must hold a non-empty value, e.g.
+	// "data.0.id" for a read that must return at least one record.
+	Exists []string `json:"exists"`
+	// Contains is a substring the response body must contain.
+	Contains string `json:"contains"`
+	// MaxLatency fails a slower response, e.g. "800ms".
+	MaxLatency string `json:"max_latency"`
+
+	// Capture stores values of the response under a name, which later
+	// checks use as {{name}} in their path, headers and body, e.g.
+	// {token: data.token} for "Authorization: Bearer {{token}}".
+	Capture map[string]string `json:"capture"`
+	// Required stops the suite when this check fails; the checks after it
+	// are reported as skipped. Use it for health and sign-in.
+	Required bool `json:"required"`
+
+	maxLatency time.Duration
+}
+
+// suite is a validated suite file.
+type suite struct {
+	baseURL string
+	timeout time.Duration
+	headers map[string]string
+	checks  []check
+}
+
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// loadSuite reads and validates a JSON or YAML suite file. ${VAR}
+// references are replaced with environment variables before parsing, so
+// credentials stay out of the file; an unset variable is an error.
+func loadSuite(path string) (*suite, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite file: %w", err)
+	}
+	raw, err = expandEnv(raw, os.LookupEnv)
+	if err != nil {
+		return nil, fmt.Errorf("suite file %s: %w", path, err)
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		if raw, err = yaml.YAMLToJSON(raw); err != nil {
+			return nil, fmt.Errorf("failed to parse suite file %s: %w", path, err)
+		}
+	}
+	var f suiteFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse suite file %s: %w", path, err)
+	}
+	s, err := f.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid suite file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// expandEnv replaces ${VAR} references with their values. Values are JSON
+// string escaped (without quotes) so a password with a quote stays valid.
+func expandEnv(raw []byte, lookup func(string) (string, bool)) ([]byte, error) {
+	var missing []string
+	out := envRef.ReplaceAllFunc(raw, func(ref []byte) []byte {
+		name := string(envRef.FindSubmatch(ref)[1])
+		value, ok := lookup(name)
+		if !ok {
+			missing = append(missing, name)
+			return ref
+		}
+		quoted, _ := json.Marshal(value)
+		return quoted[1 : len(quoted)-1]
+	})
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("environment variables not set: %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+func (f *suiteFile) validate() (*suite, error) {
+	s := &suite{
+		baseURL: strings.TrimRight(f.BaseURL, "/"),
+		timeout: defaultTimeout,
+		headers: f.Headers,
+		checks:  f.Checks,
+	}
+	if f.Timeout != "" {
+		d, err := time.ParseDuration(f.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("timeout %q is not a duration", f.Timeout)
+		}
+		s.timeout = d
+	}
+	if len(s.checks) == 0 {
+		return nil, fmt.Errorf("no checks")
+	}
+	seen := make(map[string]bool, len(s.checks))
+	for i := range s.checks {
+		c := &s.checks[i]
+		if c.Name == "" {
+			return nil, fmt.Errorf("checks[%d]: name is required", i)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("checks[%d]: duplicate name %q", i, c.Name)
+		}
+		seen[c.Name] = true
+		if !strings.HasPrefix(c.Path, "/") {
+			return nil, fmt.Errorf("check %q: path must start with /", c.Name)
+		}
+		if c.Method == "" {
+			c.Method = http.MethodGet
+			if c.Body != nil {
+				c.Method = http.MethodPost
+			}
+		}
+		c.Method = strings.ToUpper(c.Method)
+		if c.Status == 0 {
+			c.Status = http.StatusOK
+		}
+		if c.MaxLatency != "" {
+			d, err := time.ParseDuration(c.MaxLatency)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("check %q: max_latency %q is not a duration", c.Name, c.MaxLatency)
+			}
+			c.maxLatency = d
+		}
+	}
+	return s, nil
+}
+
+// only keeps the named checks, in suite order.
+func (s *suite) only(names []string) error {
+	want := make(map[string]bool, len(names))
+	for _, name := range names {
+		want[name] = true
+	}
+	var kept []check
+	for _, c := range s.checks {
+		if want[c.Name] {
+			kept = append(kept, c)
+			delete(want, c.Name)
+		}
+	}
+	if len(want) > 0 {
+		var unknown []string
+		for name := range want {
+			unknown = append(unknown, name)
+		}
+		sort.Strings(unknown)
+		return fmt.Errorf("no check named %s", strings.Join(unknown, ", "))
+	}
+	s.checks = kept
+	return nil
+}
+
+// lookupPath reads a dotted path ("data.0.id") of a decoded JSON value.
+func lookupPath(v any, path string) (any, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
@@ -65,3 +65,229 @@ type Service = internal.Service
 
 // Provider is the base interface for infrastructure providers.
 type Provider = internal.Provider
+
+// =============================================================================
+// Streaming List Responses
+// =============================================================================
+
+// RecordStreamer is implemented by route handlers that can produce their
+// list results record by record.
+type RecordStreamer = internal.RecordStreamer
+
+// JSONStreamWriter writes streamed records as one JSON document.
+type JSONStreamWriter = internal.JSONStreamWriter
+
+// StreamQueryParam is the query parameter that asks for a streamed list.
+const StreamQueryParam = internal.StreamQueryParam
+
+var (
+	// ErrStreamingUnsupported is returned when a route is not a paginated list.
+	ErrStreamingUnsupported = internal.ErrStreamingUnsupported
+
+	// NewJSONStreamWriter creates a JSONStreamWriter.
+	NewJSONStreamWriter = internal.NewJSONStreamWriter
+)
+
+// =============================================================================
+// List Exports
+// =============================================================================
+
+// RecordExporter is implemented by export route handlers.
+type RecordExporter = internal.RecordExporter
+
+// ExportHandler serves an export route over a list route's handler.
+type ExportHandler = internal.ExportHandler
+
+// ExportWriter writes streamed records as rows of a CSV or XLSX file.
+type ExportWriter = internal.ExportWriter
+
+// ExportOptions selects the format and columns of an export.
+type ExportOptions = internal.ExportOptions
+
+// ExportFormat is the file format of an export.
+type ExportFormat = internal.ExportFormat
+
+// Export formats and query parameters.
+const (
+	ExportCSV          = internal.ExportCSV
+	ExportXLSX         = internal.ExportXLSX
+	ExportFormatParam  = internal.ExportFormatParam
+	ExportColumnsParam = internal.ExportColumnsParam
+)
+
+var (
+	// ErrInvalidExport is wrapped by errors in the export format or columns.
+	ErrInvalidExport = internal.ErrInvalidExport
+
+	// ErrExportOnly is returned when an export route is executed as a regular route.
+	ErrExportOnly = internal.ErrExportOnly
+
+	// NewExportHandler creates the export route handler for a list route.
+	NewExportHandler = internal.NewExportHandler
+
+	// NewExportWriter creates an ExportWriter.
+	NewExportWriter = internal.NewExportWriter
+
+	// ParseExportOptions reads the format and columns query parameters.
+	ParseExportOptions = internal.ParseExportOptions
+)
+
+// =============================================================================
+// Route Time Budgets
+// =============================================================================
+
+// DefaultRouteTimeout is the time budget of a route that sets none.
+const DefaultRouteTimeout = internal.DefaultRouteTimeout
+
+var (
+	// ErrRouteTimeout is returned when a route runs past its time budget.
+	ErrRouteTimeout = internal.ErrRouteTimeout
+
+	// RouteBudget returns the time budget for a route.
+	RouteBudget = internal.RouteBudget
+
+	// WithRouteDeadline derives a context that expires with the route's budget.
+	WithRouteDeadline = internal.WithRouteDeadline
+
+	// ExecuteRoute runs a route's handler within its context deadline.
+	ExecuteRoute = internal.ExecuteRoute
+)
+
+// =============================================================================
+// Rate Limiting
+// =============================================================================
+
+// RateLimitConfig configures the global, per-IP, per-workspace and
+// per-endpoint limits.
+type RateLimitConfig = internal.RateLimitConfig
+
+// EndpointRateLimit is a single token bucket limit.
+type EndpointRateLimit = internal.EndpointRateLimit
+
+// RateLimiter applies a RateLimitConfig to requests.
+type RateLimiter = internal.RateLimiter
+
+// RateLimitRequest identifies a request for rate limiting.
+type RateLimitRequest = internal.RateLimitRequest
+
+// RateLimitDecision is the outcome of RateLimiter.Allow.
+type RateLimitDecision = internal.RateLimitDecision
+
+// RateLimitStore keeps token buckets by key.
+type RateLimitStore = internal.RateLimitStore
+
+// RateLimitResult is the outcome of taking a token from one bucket.
+type RateLimitResult = internal.RateLimitResult
+
+// WorkspaceHeader names the workspace a request is made for.
+const WorkspaceHeader = internal.WorkspaceHeader
+
+// Rate limit scopes.
+const (
+	RateLimitScopeGlobal    = internal.RateLimitScopeGlobal
+	RateLimitScopeEndpoint  = internal.RateLimitScopeEndpoint
+	RateLimitScopeIP        = internal.RateLimitScopeIP
+	RateLimitScopeWorkspace = internal.RateLimitScopeWorkspace
+)
+
+var (
+	// NewRateLimiter creates a limiter, or nil when rate limiting is disabled.
+	NewRateLimiter = internal.NewRateLimiter
+
+	// NewRateLimiterWithStore creates a limiter with a custom store.
+	NewRateLimiterWithStore = internal.NewRateLimiterWithStore
+
+	// NewMemoryRateLimitStore creates a per-instance in-memory store.
+	NewMemoryRateLimitStore = internal.NewMemoryRateLimitStore
+
+	// NewRedisRateLimitStore creates a store shared through Redis.
+	NewRedisRateLimitStore = internal.NewRedisRateLimitStore
+
+	// LoadRateLimitConfig overlays a config with the CONFIG_RATE_LIMIT_* settings.
+	LoadRateLimitConfig = internal.LoadRateLimitConfig
+)
+
+// =============================================================================
+// Idempotency Keys
+// =============================================================================
+
+// IdempotencyConfig configures idempotency keys for POST requests.
+type IdempotencyConfig = internal.IdempotencyConfig
+
+// Idempotency replays the stored response of a POST request retried with
+// the same Idempotency-Key.
+type Idempotency = internal.Idempotency
+
+// IdempotencyClaim is held by the request running under a key.
+type IdempotencyClaim = internal.IdempotencyClaim
+
+// IdempotencyRequest describes a request for Idempotency.Begin.
+type IdempotencyRequest = internal.IdempotencyRequest
+
+// IdempotentResponse is a response kept for replay.
+type IdempotentResponse = internal.IdempotentResponse
+
+// IdempotencyKey identifies a stored response.
+type IdempotencyKey = internal.IdempotencyKey
+
+// IdempotencyRecord is what an IdempotencyStore holds for a key.
+type IdempotencyRecord = internal.IdempotencyRecord
+
+// IdempotencyStore keeps idempotency records.
+type IdempotencyStore = internal.IdempotencyStore
+
+// Idempotency headers.
+const (
+	IdempotencyKeyHeader     = internal.IdempotencyKeyHeader
+	IdempotentReplayedHeader = internal.IdempotentReplayedHeader
+)
+
+var (
+	// NewIdempotency creates the tracker, or nil when idempotency keys are disabled.
+	NewIdempotency = internal.NewIdempotency
+
+	// NewIdempotencyWithStore creates the tracker with a custom store.
+	NewIdempotencyWithStore = internal.NewIdempotencyWithStore
+
+	// NewMemoryIdempotencyStore creates a per-instance in-memory store.
+	NewMemoryIdempotencyStore = internal.NewMemoryIdempotencyStore
+
+	// NewPostgresIdempotencyStore creates a store shared through a Postgres table.
+	NewPostgresIdempotencyStore = internal.NewPostgresIdempotencyStore
+
+	// LoadIdempotencyConfig overlays a config with the CONFIG_IDEMPOTENCY_* settings.
+	LoadIdempotencyConfig = internal.LoadIdempotencyConfig
+)
+
+// =============================================================================
+// Route Authorization
+// =============================================================================
+
+// AuthorizationConfig configures route-level RBAC.
+type AuthorizationConfig = internal.AuthorizationConfig
+
+// RouteAuthorizer enforces the permission each route maps to.
+type RouteAuthorizer = internal.RouteAuthorizer
+
+// PermissionChecker answers whether a user holds a permission in a workspace.
+type PermissionChecker = internal.PermissionChecker
+
+// AuthorizationRequest describes a request for RouteAuthorizer.Authorize.
+type AuthorizationRequest = internal.AuthorizationRequest
+
+// AuthorizationDecision is the outcome of RouteAuthorizer.Authorize.
+type AuthorizationDecision = internal.AuthorizationDecision
+
+// DefaultAuthorizationCacheTTL is how long a verdict is reused by default.
+const DefaultAuthorizationCacheTTL = internal.DefaultAuthorizationCacheTTL
+
+var (
+	// NewRouteAuthorizer creates the authorizer, or nil when route authorization is disabled.
+	NewRouteAuthorizer = internal.NewRouteAuthorizer
+
+	// RoutePermission derives the permission code a route needs.
+	RoutePermission = internal.RoutePermission
+
+	// LoadAuthorizationConfig overlays a config with the CONFIG_AUTHORIZATION_* settings.
+	LoadAuthorizationConfig = internal.LoadAuthorizationConfig
+)
@@ -0,0 +1,28 @@
+// Package openapi re-exports the internal OpenAPI generator for use by contrib sub-modules.
+// Contrib packages (which are separate Go modules) cannot import internal/ directly,
+// so this package provides stable public aliases.
+package openapi
+
+import (
+	internal "github.com/erniealice/espyna-golang/internal/composition/routing/openapi"
+)
+
+// Path is where server adapters serve the generated document.
+const Path = internal.Path
+
+// Document is an OpenAPI 3 document.
+type Document = internal.Document
+
+// Info describes the API in the document header.
+type Info = internal.Info
+
+var (
+	// DefaultInfo is used when an empty Info is given.
+	DefaultInfo = internal.DefaultInfo
+
+	// Generate builds the OpenAPI document for a set of routes.
+	Generate = internal.Generate
+
+	// Marshal generates the document for a set of routes as indented JSON.
+	Marshal = internal.Marshal
+)
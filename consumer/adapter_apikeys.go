@@ -0,0 +1,130 @@
+package consumer
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/auth/apikey"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - API Keys
+
+Machine-to-machine callers (integrations, scripts, other services)
+authenticate with per-workspace API keys instead of a user's token. Build
+with -tags apikey_auth and set CONFIG_AUTH_PROVIDER=apikey; every token
+that is not an API key goes to CONFIG_APIKEY_FALLBACK_PROVIDER (firebase,
+password or mock), so users sign in as before.
+
+A key is shown once, when issued or rotated; the api_key table keeps only
+a hash of its secret. It acts as the principal "apikey:<id>" in its own
+workspace and holds exactly the permissions it was issued with — the
+container's authorizer answers for it from the key, not from roles. Keys
+can expire, are revoked rather than deleted, and record their last use.
+
+Usage:
+
+	keys := consumer.NewAPIKeyAuthFromContainer(container)
+
+	// Before the session middleware: a request bearing a key
+	// (X-API-Key: esk_..., or Authorization: Bearer esk_...) gets the
+	// key's identity and skips the session cookie
+	handler = consumer.APIKeyMiddleware(keys)(sessionMiddleware.Handler(mux))
+
+	// Key management, behind the authentication middleware; api_key:list,
+	// api_key:create, api_key:update and api_key:delete
+	consumer.RegisterAPIKeyRoutes(server, keys, authorizer)
+*/
+
+// APIKeyAuth is the API key auth provider.
+type APIKeyAuth = apikey.APIKeyAuthAdapter
+
+// APIKey is a workspace's API key.
+type APIKey = apikey.Key
+
+// NewAPIKeyAuthFromContainer returns the container's API key auth
+// provider, its keys on the container's database. It returns nil unless
+// CONFIG_AUTH_PROVIDER is apikey.
+func NewAPIKeyAuthFromContainer(container *Container) *APIKeyAuth {
+	if container == nil {
+		return nil
+	}
+	providerContract := container.GetAuthProvider()
+	if providerContract == nil {
+		return nil
+	}
+	var raw any = providerContract
+	if w, ok := providerContract.(interface{ Provider() interface{} }); ok {
+		if inner := w.Provider(); inner != nil {
+			raw = inner
+		}
+	}
+	keys, ok := raw.(*APIKeyAuth)
+	if !ok {
+		return nil
+	}
+	if keys.Store() == nil {
+		if ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation); ok && ops != nil {
+			keys.SetOperations(ops)
+		}
+	}
+	return keys
+}
+
+// APIKeyMiddleware authenticates requests bearing an API key, in the
+// X-API-Key header or as a bearer token starting with esk_, as the key's
+// principal in the key's workspace. A bad key is refused with 401; requests
+// without one pass through untouched.
+func APIKeyMiddleware(keys *APIKeyAuth) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if keys == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get("X-API-Key")
+			if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && apikey.IsToken(bearer) {
+				token = bearer
+			}
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key, err := keys.Verify(r.Context(), token)
+			if err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				_ = json.NewEncoder(w).Encode(map[string]any{"success": false, "error": err.Error()})
+				return
+			}
+			ctx := WithSessionIdentity(r.Context(), key.Principal(), key.WorkspaceID, "", "")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RegisterAPIKeyRoutes mounts the key management endpoints (see
+// apikey.KeysPath and apikey.RotatePath). The routes must sit behind the
+// authentication middleware; authorizer decides who holds api_key:list,
+// api_key:create, api_key:update and api_key:delete, and a nil authorizer
+// denies everyone.
+func RegisterAPIKeyRoutes(server *ServerAdapter, keys *APIKeyAuth, authorizer ports.Authorizer) error {
+	if server == nil || keys == nil || keys.Store() == nil {
+		return nil
+	}
+	var gate *actiongate.ActionGatekeeper
+	if authorizer != nil {
+		gate = actiongate.NewActionGatekeeper(authorizer, ports.NewNoOpTranslator())
+	}
+	handlers := apikey.NewHandlers(keys.Store(), gate)
+	for _, method := range []string{"GET", "POST", "DELETE"} {
+		if err := server.RegisterCustomHandler(method, apikey.KeysPath, handlers.Keys); err != nil {
+			return err
+		}
+	}
+	return server.RegisterCustomHandler("POST", apikey.RotatePath, handlers.Rotate)
+}
@@ -0,0 +1,67 @@
+package consumer
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/eventlog"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - API Event Log
+
+Keeps every request served in a workspace (method, path, status, latency and
+the acting user) in the api_event_log table of the active database for
+CONFIG_API_LOG_RETENTION_DAYS days (14 by default), and lets workspace admins
+read it at /api/logs/tail, or follow it live over server-sent events with
+?follow=true. The http, gin and fiber server adapters record the requests.
+
+Usage:
+
+	eventLog := consumer.NewAPIEventLogFromContainer(container)
+	go eventLog.Run(ctx)
+	apilog.SetSink(eventLog)
+
+	// Endpoint, behind the authentication middleware; reading needs api_log:list
+	consumer.RegisterAPIEventLogRoutes(server, eventLog, authorizer)
+*/
+
+// APIEventLog is the database-backed API event log.
+type APIEventLog = eventlog.DatabaseLog
+
+// NewAPIEventLogFromContainer creates the API event log on the container's
+// database. It returns nil when no database is configured. Install it with
+// apilog.SetSink and start its writer with Run.
+func NewAPIEventLogFromContainer(container *Container) *APIEventLog {
+	if container == nil {
+		return nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil
+	}
+	var retention time.Duration
+	if days, err := strconv.Atoi(os.Getenv("CONFIG_API_LOG_RETENTION_DAYS")); err == nil && days > 0 {
+		retention = time.Duration(days) * 24 * time.Hour
+	}
+	return eventlog.NewDatabaseLog(ops, "", retention)
+}
+
+// RegisterAPIEventLogRoutes mounts the tail endpoint (see eventlog.TailPath).
+// The route must sit behind the authentication middleware; authorizer
+// decides who holds api_log:list, and a nil authorizer denies everyone.
+func RegisterAPIEventLogRoutes(server *ServerAdapter, log *APIEventLog, authorizer ports.Authorizer) error {
+	if server == nil || log == nil {
+		return nil
+	}
+	var gate *actiongate.ActionGatekeeper
+	if authorizer != nil {
+		gate = actiongate.NewActionGatekeeper(authorizer, ports.NewNoOpTranslator())
+	}
+	handlers := eventlog.NewHandlers(log, gate)
+	return server.RegisterCustomHandler("GET", eventlog.TailPath, handlers.Tail)
+}
@@ -245,10 +245,23 @@ func (a *AuthAdapter) ValidateAndExtractToken(ctx context.Context, token string)
 
 // --- Database Auth Methods ---
 
+// databaseAuth returns the provider's password operations, looking past a
+// provider that fronts another (the apikey provider) to the one behind it.
+func (a *AuthAdapter) databaseAuth() (databaseAuthOperations, bool) {
+	if dbAuth, ok := a.provider.(databaseAuthOperations); ok {
+		return dbAuth, true
+	}
+	if f, ok := a.provider.(interface{ Fallback() authProviderOperations }); ok {
+		dbAuth, ok := f.Fallback().(databaseAuthOperations)
+		return dbAuth, ok
+	}
+	return nil, false
+}
+
 // Register creates a new user account with the given credentials.
 // Only supported by password provider. Returns ErrNotSupported for other providers.
 func (a *AuthAdapter) Register(ctx context.Context, email, password, firstName, lastName, mobileNumber string) (string, error) {
-	dbAuth, ok := a.provider.(databaseAuthOperations)
+	dbAuth, ok := a.databaseAuth()
 	if !ok {
 		return "", fmt.Errorf("register not supported by %s provider", a.Name())
 	}
@@ -258,7 +271,7 @@ func (a *AuthAdapter) Register(ctx context.Context, email, password, firstName,
 // Login authenticates a user with email/password and returns a session token + identity.
 // Only supported by password provider.
 func (a *AuthAdapter) Login(ctx context.Context, email, password string) (string, *authpb.Identity, error) {
-	dbAuth, ok := a.provider.(databaseAuthOperations)
+	dbAuth, ok := a.databaseAuth()
 	if !ok {
 		return "", nil, fmt.Errorf("login not supported by %s provider", a.Name())
 	}
@@ -268,7 +281,7 @@ func (a *AuthAdapter) Login(ctx context.Context, email, password string) (string
 // RequestPasswordReset generates a reset token for the given email.
 // Returns the raw token (caller sends it via email). Only supported by password provider.
 func (a *AuthAdapter) RequestPasswordReset(ctx context.Context, email string) (string, error) {
-	dbAuth, ok := a.provider.(databaseAuthOperations)
+	dbAuth, ok := a.databaseAuth()
 	if !ok {
 		return "", fmt.Errorf("password reset not supported by %s provider", a.Name())
 	}
@@ -278,7 +291,7 @@ func (a *AuthAdapter) RequestPasswordReset(ctx context.Context, email string) (s
 // ExecutePasswordReset validates a reset token and sets a new password.
 // Only supported by password provider.
 func (a *AuthAdapter) ExecutePasswordReset(ctx context.Context, token, newPassword string) error {
-	dbAuth, ok := a.provider.(databaseAuthOperations)
+	dbAuth, ok := a.databaseAuth()
 	if !ok {
 		return fmt.Errorf("password reset not supported by %s provider", a.Name())
 	}
@@ -288,7 +301,7 @@ func (a *AuthAdapter) ExecutePasswordReset(ctx context.Context, token, newPasswo
 // CreateSession creates a new session for the given user.
 // Only supported by password provider.
 func (a *AuthAdapter) CreateSession(ctx context.Context, userID string) (string, error) {
-	dbAuth, ok := a.provider.(databaseAuthOperations)
+	dbAuth, ok := a.databaseAuth()
 	if !ok {
 		return "", fmt.Errorf("session management not supported by %s provider", a.Name())
 	}
@@ -298,7 +311,7 @@ func (a *AuthAdapter) CreateSession(ctx context.Context, userID string) (string,
 // ValidateSession checks if a session token is valid and returns the user ID.
 // Only supported by password provider.
 func (a *AuthAdapter) ValidateSession(ctx context.Context, token string) (string, error) {
-	dbAuth, ok := a.provider.(databaseAuthOperations)
+	dbAuth, ok := a.databaseAuth()
 	if !ok {
 		return "", fmt.Errorf("session management not supported by %s provider", a.Name())
 	}
@@ -308,7 +321,7 @@ func (a *AuthAdapter) ValidateSession(ctx context.Context, token string) (string
 // InvalidateSession marks a session as inactive.
 // Only supported by password provider.
 func (a *AuthAdapter) InvalidateSession(ctx context.Context, token string) error {
-	dbAuth, ok := a.provider.(databaseAuthOperations)
+	dbAuth, ok := a.databaseAuth()
 	if !ok {
 		return fmt.Errorf("session management not supported by %s provider", a.Name())
 	}
@@ -320,7 +333,7 @@ func (a *AuthAdapter) InvalidateSession(ctx context.Context, token string) error
 // need to import bcrypt directly.
 // Only supported by the password provider.
 func (a *AuthAdapter) HashPassword(password string) (string, error) {
-	dbAuth, ok := a.provider.(databaseAuthOperations)
+	dbAuth, ok := a.databaseAuth()
 	if !ok {
 		return "", fmt.Errorf("hash password not supported by %s provider", a.Name())
 	}
@@ -334,7 +347,7 @@ func (a *AuthAdapter) HashPassword(password string) (string, error) {
 // so there is no enumeration risk.
 // Only supported by the password provider.
 func (a *AuthAdapter) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error {
-	dbAuth, ok := a.provider.(databaseAuthOperations)
+	dbAuth, ok := a.databaseAuth()
 	if !ok {
 		return fmt.Errorf("change password not supported by %s provider", a.Name())
 	}
@@ -344,7 +357,7 @@ func (a *AuthAdapter) ChangePassword(ctx context.Context, userID, oldPassword, n
 // GetSessionWorkspaceContext returns the workspace_user_id and workspace_id stored on the session.
 // Only supported by password provider. Returns empty strings for other providers.
 func (a *AuthAdapter) GetSessionWorkspaceContext(ctx context.Context, token string) (wsUserID, wsID string) {
-	dbAuth, ok := a.provider.(databaseAuthOperations)
+	dbAuth, ok := a.databaseAuth()
 	if !ok {
 		return "", ""
 	}
@@ -0,0 +1,157 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/billing"
+	"github.com/erniealice/espyna-golang/ports"
+
+	workspacepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/workspace"
+)
+
+/*
+ ESPYNA CONSUMER APP - Recurring Billing
+
+A billing pass generates the invoices (revenues) that active subscriptions
+owe as of a date, through the revenue run machinery, so a period is never
+invoiced twice. Each new invoice gets a due date from its payment term and,
+when it has an amount, a checkout session with its payment provider (or the
+primary one). The pass then marks open invoices past their due date as
+"overdue". Passes run on an interval for every billed workspace, or on
+demand over the API for the caller's workspace.
+
+After each scheduled pass the workspace's checkouts are reconciled with
+their payment providers: revenues the provider reports paid but that are
+not settled, captured for a different amount, or refunded after settling
+are reported (logged, or returned over the API). With auto-correct on,
+paid-but-unrecorded revenues are settled, their payment is recorded and
+the change is written to the audit trail; the other mismatches always need
+a person.
+
+Usage:
+
+	scheduler, err := consumer.NewBillingSchedulerFromContainer(container)
+	if err != nil {
+		log.Fatal(err) // a malformed CONFIG_BILLING_* setting
+	}
+	if scheduler != nil {
+		go scheduler.Run(ctx)
+	}
+
+	// On demand, behind the authentication middleware: POST
+	// /api/billing/run needs revenue:update in the caller's workspace;
+	// POST /api/billing/reconcile needs revenue:read, or revenue:update
+	// with ?auto_correct=true
+	consumer.RegisterBillingRoutes(server, scheduler, authorizer)
+*/
+
+// BillingScheduler runs recurring billing passes and payment
+// reconciliations.
+type BillingScheduler = billing.Scheduler
+
+// NewBillingSchedulerFromContainer creates the billing scheduler. It returns
+// nil when CONFIG_BILLING_RUN_AS_USER_ID is unset.
+//
+// Settings:
+//
+//	CONFIG_BILLING_RUN_AS_USER_ID     user scheduled passes act as; needs
+//	                                  revenue:create, revenue:update and
+//	                                  subscription:read where it bills
+//	CONFIG_BILLING_WORKSPACES         comma separated workspaces to bill
+//	                                  (default every workspace of the
+//	                                  run-as user)
+//	CONFIG_BILLING_INTERVAL           time between passes (default 1h)
+//	CONFIG_BILLING_DEFAULT_NET_DAYS   due date for invoices without a
+//	                                  payment term (default none)
+//	CONFIG_BILLING_CHECKOUT_SUCCESS_URL,
+//	CONFIG_BILLING_CHECKOUT_FAILURE_URL,
+//	CONFIG_BILLING_CHECKOUT_CANCEL_URL
+//	                                  where checkouts return the payer
+//	CONFIG_BILLING_RECONCILE_AUTO_CORRECT
+//	                                  settle revenues their provider
+//	                                  reports paid during scheduled
+//	                                  reconciliations (default false:
+//	                                  report only)
+func NewBillingSchedulerFromContainer(container *Container) (*BillingScheduler, error) {
+	runAs := strings.TrimSpace(os.Getenv("CONFIG_BILLING_RUN_AS_USER_ID"))
+	if runAs == "" || container == nil {
+		return nil, nil
+	}
+	uc := container.GetUseCases()
+	if uc == nil || uc.Revenue == nil || uc.Revenue.Revenue == nil || uc.Revenue.Revenue.RunRecurringBilling == nil {
+		return nil, fmt.Errorf("recurring billing needs the revenue use cases")
+	}
+
+	config := billing.Config{RunAsUserID: runAs}
+	if uc.Revenue.Revenue.ReconcilePayments != nil {
+		config.Reconciler = uc.Revenue.Revenue.ReconcilePayments
+	}
+	var err error
+	if config.Interval, err = envDuration("CONFIG_BILLING_INTERVAL"); err != nil {
+		return nil, err
+	}
+	if config.Template.DefaultNetDays, err = envPositiveInt("CONFIG_BILLING_DEFAULT_NET_DAYS"); err != nil {
+		return nil, err
+	}
+	config.Template.SuccessURL = strings.TrimSpace(os.Getenv("CONFIG_BILLING_CHECKOUT_SUCCESS_URL"))
+	config.Template.FailureURL = strings.TrimSpace(os.Getenv("CONFIG_BILLING_CHECKOUT_FAILURE_URL"))
+	config.Template.CancelURL = strings.TrimSpace(os.Getenv("CONFIG_BILLING_CHECKOUT_CANCEL_URL"))
+	if raw := strings.TrimSpace(os.Getenv("CONFIG_BILLING_RECONCILE_AUTO_CORRECT")); raw != "" {
+		if config.ReconcileAutoCorrect, err = strconv.ParseBool(raw); err != nil {
+			return nil, fmt.Errorf("CONFIG_BILLING_RECONCILE_AUTO_CORRECT: %w", err)
+		}
+	}
+
+	if raw := strings.TrimSpace(os.Getenv("CONFIG_BILLING_WORKSPACES")); raw != "" {
+		var workspaces []string
+		for _, id := range strings.Split(raw, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				workspaces = append(workspaces, id)
+			}
+		}
+		config.Workspaces = func(context.Context) ([]string, error) { return workspaces, nil }
+	} else {
+		if uc.Entity == nil || uc.Entity.Workspace == nil || uc.Entity.Workspace.ListUserWorkspaces == nil {
+			return nil, fmt.Errorf("CONFIG_BILLING_WORKSPACES is unset and workspaces cannot be listed")
+		}
+		listUserWorkspaces := uc.Entity.Workspace.ListUserWorkspaces
+		config.Workspaces = func(ctx context.Context) ([]string, error) {
+			resp, err := listUserWorkspaces.Execute(contextutil.WithUserID(ctx, runAs),
+				&workspacepb.ListUserWorkspacesRequest{UserId: runAs})
+			if err != nil {
+				return nil, err
+			}
+			var workspaces []string
+			for _, ws := range resp.GetWorkspaces() {
+				workspaces = append(workspaces, ws.GetWorkspaceId())
+			}
+			return workspaces, nil
+		}
+	}
+	return billing.NewScheduler(uc.Revenue.Revenue.RunRecurringBilling, config), nil
+}
+
+// RegisterBillingRoutes mounts billing.RunPath and billing.ReconcilePath.
+// The routes must sit behind the authentication middleware; authorizer
+// decides who holds revenue:read and revenue:update, and a nil authorizer
+// denies everyone.
+func RegisterBillingRoutes(server *ServerAdapter, scheduler *BillingScheduler, authorizer ports.Authorizer) error {
+	if server == nil || scheduler == nil {
+		return nil
+	}
+	var gate *actiongate.ActionGatekeeper
+	if authorizer != nil {
+		gate = actiongate.NewActionGatekeeper(authorizer, ports.NewNoOpTranslator())
+	}
+	handlers := billing.NewHandlers(scheduler, gate)
+	if err := server.RegisterCustomHandler("POST", billing.RunPath, handlers.Run); err != nil {
+		return err
+	}
+	return server.RegisterCustomHandler("POST", billing.ReconcilePath, handlers.Reconcile)
+}
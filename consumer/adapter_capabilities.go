@@ -0,0 +1,48 @@
+package consumer
+
+import (
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/capabilities"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - Integration Capability Discovery
+
+GET /api/integrations/capabilities reports, for the caller's workspace,
+every integration (payment, tabular, scheduler, storage, messaging) with
+its active providers and their capabilities, normalized to lower snake case
+names ("recurring", "webhooks", "presigned_urls", "push_ios"). A provider's
+status is "enabled", "provider_disabled" or "not_permitted"; an integration
+without a provider is reported once as "not_configured". The frontend
+renders a feature only for an enabled provider with the capability.
+
+Usage:
+
+	// Behind the authentication middleware. A workspace's roles switch an
+	// integration on with integration_<name>:read, e.g.
+	// integration_payment:read.
+	consumer.RegisterCapabilityRoutes(server, container, authorizer)
+*/
+
+// RegisterCapabilityRoutes mounts capabilities.Path over the container's
+// providers. The route must sit behind the authentication middleware; a nil
+// authorizer reports every provider as not permitted.
+func RegisterCapabilityRoutes(server *ServerAdapter, container *Container, authorizer ports.Authorizer) error {
+	if server == nil || container == nil {
+		return nil
+	}
+	var gate *actiongate.ActionGatekeeper
+	if authorizer != nil {
+		gate = actiongate.NewActionGatekeeper(authorizer, ports.NewNoOpTranslator())
+	}
+	handler := capabilities.NewHandler(capabilities.Providers{
+		Payment:   container.GetPaymentProviders(),
+		Scheduler: container.GetSchedulerProviders(),
+		Tabular:   container.GetTabularProvider(),
+		Storage:   container.GetStorageProvider(),
+		Email:     container.GetEmailProvider(),
+		Push:      container.GetPushProviders(),
+	}, gate)
+	return server.RegisterCustomHandler("GET", capabilities.Path, handler.ServeHTTP)
+}
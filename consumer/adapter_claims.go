@@ -0,0 +1,115 @@
+package consumer
+
+import (
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/claimsync"
+	"github.com/erniealice/espyna-golang/ports"
+	"github.com/erniealice/espyna-golang/shared/authclaims"
+)
+
+/*
+ ESPYNA CONSUMER APP - Role Claims Sync
+
+Keeps each user's roles and permissions per workspace in the auth
+provider's custom claims (Firebase Auth), under the "rbac" claim, so
+frontends and the authentication middleware can make coarse checks from
+the ID token without a database hit. Whenever a workspace_user_role is
+created, updated or deleted, the user's claims are rewritten; other custom
+claims are kept. Claims reach a user's tokens when the tokens are next
+refreshed, and a set too large for the provider's limit loses its
+permissions (then its workspaces) and is marked truncated: fall back to
+the authorizer for what it cannot answer.
+
+Usage:
+
+	syncer := consumer.NewClaimsSyncerFromContainer(container)
+	consumer.EnableClaimsSync(syncer) // role changes start queueing
+	go syncer.Run(ctx)
+
+	// POST /api/entity/workspace-user-role/sync-claims, behind the
+	// authentication middleware; workspace_user_role:update
+	consumer.RegisterClaimsSyncRoutes(server, syncer, authorizer)
+
+	// Reading the claims from a verified token
+	claims, ok := consumer.ParseRoleClaims(resp.Token.CustomClaims[consumer.RoleClaimsKey])
+	if has, known := claims.HasPermission(workspaceID, "client:create"); known && !has { ... }
+*/
+
+// ClaimsSyncer writes users' role claims to the auth provider.
+type ClaimsSyncer = claimsync.Syncer
+
+// RoleClaims is the role and permission set kept in custom claims.
+type RoleClaims = authclaims.Claims
+
+// RoleClaimsKey is the custom claim holding RoleClaims.
+const RoleClaimsKey = authclaims.Key
+
+// ParseRoleClaims reads RoleClaims from a token's claim value.
+var ParseRoleClaims = authclaims.Parse
+
+// NewClaimsSyncerFromContainer creates the claims syncer on the container's
+// database and auth provider. It returns nil when no database is
+// configured or the auth provider keeps no custom claims.
+func NewClaimsSyncerFromContainer(container *Container) *ClaimsSyncer {
+	if container == nil {
+		return nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil
+	}
+	providerContract := container.GetAuthProvider()
+	if providerContract == nil {
+		return nil
+	}
+	// Unwrap the composition layer's ProviderWrapper, as
+	// NewAuthAdapterFromContainer does.
+	var raw any = providerContract
+	if w, ok := providerContract.(interface{ Provider() interface{} }); ok {
+		if inner := w.Provider(); inner != nil {
+			raw = inner
+		}
+	}
+	// The apikey provider keeps no claims; the provider behind it may.
+	if f, ok := raw.(interface{ Fallback() ports.AuthProvider }); ok {
+		raw = f.Fallback()
+	}
+	store, ok := raw.(claimsync.Store)
+	if !ok {
+		return nil
+	}
+	tables := container.GetDBTableConfig()
+	return claimsync.NewSyncer(ops, store, claimsync.Config{Tables: claimsync.Tables{
+		WorkspaceUser:     tables.TableName("workspace_user"),
+		WorkspaceUserRole: tables.TableName("workspace_user_role"),
+		Role:              tables.TableName("role"),
+		RolePermission:    tables.TableName("role_permission"),
+		Permission:        tables.TableName("permission"),
+	}})
+}
+
+// EnableClaimsSync makes syncer receive the role changes the use cases
+// emit. A nil syncer turns it off.
+func EnableClaimsSync(syncer *ClaimsSyncer) {
+	if syncer == nil {
+		domainevent.SetSink("claims", nil)
+		return
+	}
+	domainevent.SetSink("claims", syncer)
+}
+
+// RegisterClaimsSyncRoutes mounts claimsync.SyncClaimsPath. The route must
+// sit behind the authentication middleware; authorizer decides who holds
+// workspace_user_role:update, and a nil authorizer denies everyone.
+func RegisterClaimsSyncRoutes(server *ServerAdapter, syncer *ClaimsSyncer, authorizer ports.Authorizer) error {
+	if server == nil || syncer == nil {
+		return nil
+	}
+	var gate *actiongate.ActionGatekeeper
+	if authorizer != nil {
+		gate = actiongate.NewActionGatekeeper(authorizer, ports.NewNoOpTranslator())
+	}
+	return server.RegisterCustomHandler("POST", claimsync.SyncClaimsPath, claimsync.NewHandler(syncer, gate).ServeHTTP)
+}
@@ -0,0 +1,129 @@
+package consumer
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/credentials"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - Credential Inventory
+
+Records which provider credentials (PayPal, Maya, Calendly, Microsoft, the
+Google service-account keys, S3, SFTP, ...) this environment has configured,
+in the credential_inventory table of the active database: a fingerprint of
+each, never the secret, with its age and, where known, its expiry. Run scans
+once a day and logs a reminder for every credential that is expired, expires
+within two weeks, or is past its rotation period.
+
+With CONFIG_CREDENTIAL_BACKUP_KEY set (32 bytes, base64 or hex) every
+credential also gets an AES-256-GCM encrypted copy for disaster recovery,
+exported at /api/credentials/backup and opened offline with
+credentials.OpenBundle and the same key. Keep the key out of the database
+it protects.
+
+Usage:
+
+	inventory, err := consumer.NewCredentialInventoryFromContainer(container)
+	if err != nil {
+		log.Fatal(err) // a malformed CONFIG_CREDENTIAL_* setting
+	}
+	go inventory.Run(ctx, 24*time.Hour)
+
+	// Endpoints, behind the authentication middleware; the report needs
+	// credential:list, the backup credential:manage
+	consumer.RegisterCredentialInventoryRoutes(server, inventory, authorizer)
+*/
+
+// CredentialInventory is the database-backed credential inventory.
+type CredentialInventory = credentials.Inventory
+
+// NewCredentialInventoryFromContainer creates the credential inventory on
+// the container's database. It returns nil when no database is configured.
+//
+// Settings:
+//
+//	CONFIG_CREDENTIAL_ENVIRONMENT  the environment label (APP_ENV by default)
+//	CONFIG_CREDENTIAL_BACKUP_KEY   the key sealing backup copies
+//	CONFIG_CREDENTIAL_EXPIRIES     NAME=YYYY-MM-DD,... for expiries the
+//	                               providers do not publish
+//	CONFIG_CREDENTIAL_ROTATE_DAYS  NAME=days,... rotation periods; *=days
+//	                               applies to every credential without one
+func NewCredentialInventoryFromContainer(container *Container) (*CredentialInventory, error) {
+	if container == nil {
+		return nil, nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil, nil
+	}
+	config := credentials.Config{
+		Environment: os.Getenv("CONFIG_CREDENTIAL_ENVIRONMENT"),
+		Expiries:    map[string]time.Time{},
+		RotateAfter: map[string]time.Duration{},
+	}
+	if config.Environment == "" {
+		config.Environment = os.Getenv("APP_ENV")
+	}
+	if key := os.Getenv("CONFIG_CREDENTIAL_BACKUP_KEY"); key != "" {
+		sealer, err := credentials.ParseSealerKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("CONFIG_CREDENTIAL_BACKUP_KEY: %w", err)
+		}
+		config.Sealer = sealer
+	}
+	for name, raw := range credentialSettings(os.Getenv("CONFIG_CREDENTIAL_EXPIRIES")) {
+		expires, err := credentials.ParseExpiry(raw)
+		if err != nil {
+			return nil, fmt.Errorf("CONFIG_CREDENTIAL_EXPIRIES: %s: %w", name, err)
+		}
+		config.Expiries[name] = expires
+	}
+	for name, raw := range credentialSettings(os.Getenv("CONFIG_CREDENTIAL_ROTATE_DAYS")) {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 1 {
+			return nil, fmt.Errorf("CONFIG_CREDENTIAL_ROTATE_DAYS: %s: %q is not a number of days", name, raw)
+		}
+		config.RotateAfter[name] = time.Duration(days) * 24 * time.Hour
+	}
+	return credentials.NewInventory(ops, config, os.Getenv), nil
+}
+
+// credentialSettings reads a NAME=value,... setting.
+func credentialSettings(raw string) map[string]string {
+	settings := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if name = strings.TrimSpace(name); ok && name != "" {
+			settings[name] = strings.TrimSpace(value)
+		}
+	}
+	return settings
+}
+
+// RegisterCredentialInventoryRoutes mounts the inventory endpoints (see
+// credentials.ListPath and credentials.BackupPath). The routes must sit
+// behind the authentication middleware; authorizer decides who holds
+// credential:list and credential:manage, and a nil authorizer denies
+// everyone.
+func RegisterCredentialInventoryRoutes(server *ServerAdapter, inventory *CredentialInventory, authorizer ports.Authorizer) error {
+	if server == nil || inventory == nil {
+		return nil
+	}
+	var gate *actiongate.ActionGatekeeper
+	if authorizer != nil {
+		gate = actiongate.NewActionGatekeeper(authorizer, ports.NewNoOpTranslator())
+	}
+	handlers := credentials.NewHandlers(inventory, gate)
+	if err := server.RegisterCustomHandler("GET", credentials.ListPath, handlers.List); err != nil {
+		return err
+	}
+	return server.RegisterCustomHandler("GET", credentials.BackupPath, handlers.Backup)
+}
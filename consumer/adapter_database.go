@@ -141,6 +141,48 @@ func (a *DatabaseAdapter) HardDelete(ctx context.Context, collection string, id
 	return a.ops.HardDelete(ctx, collection, id)
 }
 
+// Restore re-activates a soft-deleted document.
+func (a *DatabaseAdapter) Restore(ctx context.Context, collection string, id string) error {
+	if a.ops == nil {
+		return fmt.Errorf("database operations not initialized")
+	}
+	return a.ops.Restore(ctx, collection, id)
+}
+
+// ListDeleted retrieves soft-deleted (active=false) documents, with the same
+// parameters as List.
+func (a *DatabaseAdapter) ListDeleted(ctx context.Context, collection string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	if a.ops == nil {
+		return nil, fmt.Errorf("database operations not initialized")
+	}
+	return a.ops.ListDeleted(ctx, collection, params)
+}
+
+// CreateMany creates several documents in one call. Results are returned in
+// input order; see interfaces.DatabaseOperation for per-backend atomicity.
+func (a *DatabaseAdapter) CreateMany(ctx context.Context, collection string, data []map[string]any) ([]map[string]any, error) {
+	if a.ops == nil {
+		return nil, fmt.Errorf("database operations not initialized")
+	}
+	return a.ops.CreateMany(ctx, collection, data)
+}
+
+// UpdateMany updates several documents in one call.
+func (a *DatabaseAdapter) UpdateMany(ctx context.Context, collection string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	if a.ops == nil {
+		return nil, fmt.Errorf("database operations not initialized")
+	}
+	return a.ops.UpdateMany(ctx, collection, updates)
+}
+
+// DeleteMany soft deletes several documents in one call.
+func (a *DatabaseAdapter) DeleteMany(ctx context.Context, collection string, ids []string) error {
+	if a.ops == nil {
+		return fmt.Errorf("database operations not initialized")
+	}
+	return a.ops.DeleteMany(ctx, collection, ids)
+}
+
 // List retrieves documents from the specified collection with optional parameters.
 // Supports filtering, sorting, and pagination via ListParams.
 // Automatically filters by active=true.
@@ -151,6 +193,15 @@ func (a *DatabaseAdapter) List(ctx context.Context, collection string, params *i
 	return a.ops.List(ctx, collection, params)
 }
 
+// Count returns how many active documents match params, without reading
+// them on backends with a native count (Postgres, Firestore).
+func (a *DatabaseAdapter) Count(ctx context.Context, collection string, params *interfaces.ListParams) (int64, error) {
+	if a.ops == nil {
+		return 0, fmt.Errorf("database operations not initialized")
+	}
+	return a.ops.Count(ctx, collection, params)
+}
+
 // ListSimple retrieves all active documents from a collection without parameters.
 // This is a convenience method for simple listing without filters/pagination.
 func (a *DatabaseAdapter) ListSimple(ctx context.Context, collection string) ([]map[string]any, error) {
@@ -185,5 +236,8 @@ type ListParams = interfaces.ListParams
 // ListResult re-exports the ListResult type for consumer convenience
 type ListResult = interfaces.ListResult
 
+// BatchUpdate re-exports the BatchUpdate type for consumer convenience
+type BatchUpdate = interfaces.BatchUpdate
+
 // QueryBuilder re-exports the QueryBuilder interface for consumer convenience
 type QueryBuilder = interfaces.QueryBuilder
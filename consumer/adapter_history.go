@@ -0,0 +1,62 @@
+package consumer
+
+import (
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/history"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - Revision History
+
+Writes to the tables named in CONFIG_HISTORY_TABLES record a revision: the
+record as the write left it and the fields it changed. The history route
+lists a record's revisions and reads it back as it stood at a revision or a
+point in time.
+
+Usage:
+
+	// Behind the authentication middleware; needs <entity>:read
+	consumer.RegisterHistoryRoutes(server, container, authorizer)
+
+	GET /api/client/history?id=c1                          revisions, newest first
+	GET /api/client/history?id=c1&revision=3               revision 3
+	GET /api/client/history?id=c1&at=2026-01-31T00:00:00Z  the record on that date
+*/
+
+// RegisterHistoryRoutes mounts history.Path for entities, by default every
+// entity the configured database provider has a repository for whose table
+// keeps a history. The routes must sit behind the authentication
+// middleware; authorizer decides who holds <entity>:read, and a nil
+// authorizer denies everyone.
+func RegisterHistoryRoutes(server *ServerAdapter, container *Container, authorizer ports.Authorizer, entities ...string) error {
+	if server == nil || container == nil {
+		return nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil
+	}
+	policy := registry.GetDefaultHistory()
+	tables := container.GetDBTableConfig()
+	if len(entities) == 0 {
+		for _, entity := range repositoryEntities(container) {
+			if policy.Records(tables.TableName(entity)) {
+				entities = append(entities, entity)
+			}
+		}
+	}
+	var gate *actiongate.ActionGatekeeper
+	if authorizer != nil {
+		gate = actiongate.NewActionGatekeeper(authorizer, ports.NewNoOpTranslator())
+	}
+	handlers := history.NewHandlers(ops, policy.Table(), gate)
+	for _, entity := range entities {
+		if err := server.RegisterCustomHandler("GET", history.Path(entity), handlers.History(entity, tables.TableName(entity))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package consumer
+
+import (
+	"net/http"
+
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/notification/inbox"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - In-App Notification Inbox
+
+Keeps notifications sent on the in_app channel in the notification_inbox
+table of the active database and serves the endpoints behind the web app's
+bell icon: a filtered, paginated list, the unread count, and mark-read.
+
+Usage:
+
+	// Endpoints, behind the authentication middleware
+	consumer.RegisterNotificationInboxRoutes(server, consumer.NewNotificationInboxFromContainer(container))
+
+	// The notifier delivers in_app notifications (and digests) to the inbox
+	notifier := consumer.NewNotifierFromContainer(container, config, "no-reply@example.com")
+	notifier.Notify(ctx, &ports.Notification{
+	    UserID: userID, WorkspaceID: workspaceID, Channel: ports.NotificationChannelInApp, Recipient: userID,
+	    Template: "workflow.assigned", Subject: "New assignment", TextBody: "...",
+	    Data: map[string]string{"link": "/workflow/123"},
+	})
+*/
+
+// NewNotificationInboxFromContainer creates the in-app inbox on the
+// container's database. It returns nil when no database is configured.
+func NewNotificationInboxFromContainer(container *Container) ports.NotificationInbox {
+	if container == nil {
+		return nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil
+	}
+	return inbox.NewDatabaseInbox(ops, "")
+}
+
+// NewInboxSenderFromContainer creates the in_app notification channel. It
+// returns nil when no database is configured.
+func NewInboxSenderFromContainer(container *Container) ports.NotificationSender {
+	store := NewNotificationInboxFromContainer(container)
+	if store == nil {
+		return nil
+	}
+	return inbox.NewSender(store)
+}
+
+// RegisterNotificationInboxRoutes mounts the inbox endpoints (see
+// inbox.ListPath). The routes must sit behind the authentication
+// middleware; requests without a user are rejected.
+func RegisterNotificationInboxRoutes(server *ServerAdapter, store ports.NotificationInbox) error {
+	if server == nil || store == nil {
+		return nil
+	}
+	handlers := inbox.NewHandlers(store)
+	routes := []struct {
+		method, path string
+		handler      http.HandlerFunc
+	}{
+		{"POST", inbox.ListPath, handlers.List},
+		{"GET", inbox.UnreadCountPath, handlers.UnreadCount},
+		{"POST", inbox.MarkReadPath, handlers.MarkRead},
+		{"POST", inbox.MarkAllReadPath, handlers.MarkAllRead},
+	}
+	for _, route := range routes {
+		if err := server.RegisterCustomHandler(route.method, route.path, route.handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
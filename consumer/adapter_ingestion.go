@@ -0,0 +1,90 @@
+package consumer
+
+import (
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/ingestion"
+)
+
+/*
+ ESPYNA CONSUMER APP - File Drop Ingestion
+
+Imports the CSV exports other systems drop off, such as a school information
+system's nightly student files. Profiles match files by name and map their
+columns to fields; files arrive in an SFTP folder the watcher polls or
+through the upload endpoint.
+
+Usage:
+
+	config, _ := consumer.LoadIngestionConfig("ingestion.yaml")
+	// profiles:
+	//   - name: students
+	//     pattern: "students_*.csv"
+	//     table: client
+	//     workspace_id: ws_main
+	//     columns: {"Student No": internal_id, "Last Name": last_name, "First Name": first_name}
+	//     required: [internal_id, last_name]
+	//     verify: true   # read rows back and report fields that did not persist
+	ingestor, err := consumer.NewIngestorFromContainer(container, config)
+
+	// Poll the SFTP folder from LEAPFOR_INTEGRATION_INGESTION_SFTP_*
+	if sftp, ok, err := consumer.IngestionSFTPConfigFromEnv(); err == nil && ok {
+	    watcher, err := consumer.NewIngestionWatcher(ingestor, sftp)
+	    go watcher.Run(ctx, 5*time.Minute)
+	}
+
+	// Upload endpoint, behind the authentication middleware
+	consumer.RegisterIngestionDropRoute(server, ingestor)
+*/
+
+// File drop ingestion types.
+type (
+	IngestionConfig       = ingestion.Config
+	IngestionProfile      = ingestion.Profile
+	IngestionReport       = ingestion.Report
+	IngestionVerification = ingestion.Verification
+	IngestionImporter     = ingestion.Importer
+	IngestionSFTPConfig   = ingestion.SFTPConfig
+	Ingestor              = ingestion.Ingestor
+	IngestionWatcher      = ingestion.Watcher
+)
+
+// LoadIngestionConfig reads ingestion profiles from a JSON or YAML file.
+func LoadIngestionConfig(file string) (IngestionConfig, error) {
+	return ingestion.LoadConfig(file)
+}
+
+// IngestionSFTPConfigFromEnv reads the SFTP drop folder settings. ok is
+// false when LEAPFOR_INTEGRATION_INGESTION_SFTP_ADDR is unset.
+func IngestionSFTPConfigFromEnv() (IngestionSFTPConfig, bool, error) {
+	return ingestion.SFTPConfigFromEnv()
+}
+
+// NewIngestorFromContainer creates an Ingestor that creates the rows of
+// ingested files in the container's database. It returns nil when no
+// database is configured.
+func NewIngestorFromContainer(container *Container, config IngestionConfig) (*Ingestor, error) {
+	if container == nil {
+		return nil, nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil, nil
+	}
+	return ingestion.New(config, ingestion.NewDatabaseImporter(ops))
+}
+
+// NewIngestionWatcher creates a watcher of the SFTP folder. Start it with
+// Run.
+func NewIngestionWatcher(ingestor *Ingestor, config IngestionSFTPConfig) (*IngestionWatcher, error) {
+	return ingestion.NewWatcher(ingestor, config)
+}
+
+// RegisterIngestionDropRoute mounts the upload endpoint (see
+// ingestion.DropPath). The route must sit behind the authentication
+// middleware.
+func RegisterIngestionDropRoute(server *ServerAdapter, ingestor *Ingestor) error {
+	if server == nil || ingestor == nil {
+		return nil
+	}
+	return server.RegisterCustomHandler("POST", ingestion.DropPath, ingestion.NewHandler(ingestor).ServeHTTP)
+}
@@ -0,0 +1,125 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	sqlexec "github.com/erniealice/espyna-golang/database/sqlexec"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/jobqueue"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/jobqueue/memory"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/jobqueue/postgres"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - Background Job Queue
+
+Deferred work (invoice generation, tabular sync, ...) is enqueued with a
+payload and a run_at time and run by a pool of workers, each job kind with
+its own concurrency limit. Failed jobs are retried with exponential
+backoff; jobs out of attempts, or failed with jobqueue.Permanent, wait in
+the dead-letter state until retried over the API. With the postgres queue
+jobs live in the job_queue table, survive restarts and are shared by every
+instance; the memory queue is for tests and a single instance.
+
+Usage:
+
+	runner, err := consumer.NewJobRunnerFromContainer(container)
+	if err != nil {
+		log.Fatal(err) // a malformed CONFIG_JOBQUEUE_* setting
+	}
+	runner.Handle("invoice.generate", generateInvoices, 2)
+	go runner.Run(ctx)
+
+	// anywhere: run in an hour, retried on failure
+	runner.Enqueue(ctx, "invoice.generate", workspaceID, payload, time.Now().Add(time.Hour))
+
+	// Dead letters, behind the authentication middleware; job_queue:list
+	// and job_queue:update in the caller's workspace
+	consumer.RegisterJobQueueRoutes(server, runner, authorizer)
+*/
+
+// JobRunner runs deferred jobs from the job queue.
+type JobRunner = jobqueue.Runner
+
+// JobHandler does the work of one job kind.
+type JobHandler = jobqueue.Handler
+
+// NewJobRunnerFromContainer creates the job runner. It returns nil when
+// CONFIG_JOBQUEUE_PROVIDER is unset.
+//
+// Settings:
+//
+//	CONFIG_JOBQUEUE_PROVIDER      "postgres" (the container's database,
+//	                              table job_queue) or "memory"
+//	CONFIG_JOBQUEUE_WORKERS       jobs running at once (default 4)
+//	CONFIG_JOBQUEUE_MAX_ATTEMPTS  attempts per job, the first included
+//	                              (default 10)
+//	CONFIG_JOBQUEUE_BACKOFF       wait after the first failure, doubling
+//	                              after each further one (default 30s)
+//	CONFIG_JOBQUEUE_LEASE         bound on a job's run before it is
+//	                              claimed again (default 5m)
+func NewJobRunnerFromContainer(container *Container) (*JobRunner, error) {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("CONFIG_JOBQUEUE_PROVIDER")))
+	if provider == "" {
+		return nil, nil
+	}
+
+	var queue ports.JobQueue
+	switch provider {
+	case "memory":
+		queue = memory.NewQueue()
+	case "postgres":
+		var ops any
+		if container != nil {
+			ops = container.GetDatabaseOperations()
+		}
+		ep, ok := ops.(interface {
+			GetExecutor(ctx context.Context) sqlexec.DBExecutor
+		})
+		if !ok {
+			return nil, fmt.Errorf("CONFIG_JOBQUEUE_PROVIDER=postgres needs the postgresql database provider")
+		}
+		queue = postgres.NewQueue(ep.GetExecutor(context.Background()), "")
+	default:
+		return nil, fmt.Errorf("CONFIG_JOBQUEUE_PROVIDER=%q is not postgres or memory", provider)
+	}
+
+	var config jobqueue.Config
+	var err error
+	if config.Workers, err = envPositiveInt("CONFIG_JOBQUEUE_WORKERS"); err != nil {
+		return nil, err
+	}
+	if config.MaxAttempts, err = envPositiveInt("CONFIG_JOBQUEUE_MAX_ATTEMPTS"); err != nil {
+		return nil, err
+	}
+	if config.BaseBackoff, err = envDuration("CONFIG_JOBQUEUE_BACKOFF"); err != nil {
+		return nil, err
+	}
+	if config.Lease, err = envDuration("CONFIG_JOBQUEUE_LEASE"); err != nil {
+		return nil, err
+	}
+	return jobqueue.NewRunner(queue, config), nil
+}
+
+// RegisterJobQueueRoutes mounts the job queue endpoints (see
+// jobqueue.JobsPath and jobqueue.RetryPath). The routes must sit behind the
+// authentication middleware; authorizer decides who holds job_queue:list
+// and job_queue:update, and a nil authorizer denies everyone.
+func RegisterJobQueueRoutes(server *ServerAdapter, runner *JobRunner, authorizer ports.Authorizer) error {
+	if server == nil || runner == nil {
+		return nil
+	}
+	var gate *actiongate.ActionGatekeeper
+	if authorizer != nil {
+		gate = actiongate.NewActionGatekeeper(authorizer, ports.NewNoOpTranslator())
+	}
+	handlers := jobqueue.NewHandlers(runner.Queue(), gate)
+	if err := server.RegisterCustomHandler("GET", jobqueue.JobsPath, handlers.Jobs); err != nil {
+		return err
+	}
+	return server.RegisterCustomHandler("POST", jobqueue.RetryPath, handlers.Retry)
+}
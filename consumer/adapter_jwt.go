@@ -0,0 +1,123 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/auth/jwt"
+)
+
+/*
+ ESPYNA CONSUMER APP - JWT Auth
+
+For deployments without Firebase Auth: build with -tags jwt_auth and set
+CONFIG_AUTH_PROVIDER=jwt (and CONFIG_JWT_SECRET). Users sign in with the
+email and password in the user table (bcrypt hashes, as the password
+provider keeps them) and get a short-lived HS256 access token with a
+refresh token. Refresh tokens are single-use: each refresh returns a new
+one, and replaying a spent one signs that whole session out. Revoked
+access tokens are refused until they expire.
+
+Usage:
+
+	tokens := consumer.NewJWTAuthFromContainer(container)
+
+	// POST /auth/token, /auth/token/refresh, /auth/token/revoke; public
+	consumer.RegisterJWTAuthRoutes(server, tokens)
+
+	// Before the session middleware: a request with a valid access token
+	// (Authorization: Bearer ...) is the token's user and skips the
+	// session cookie; a bad token is refused with 401
+	handler = consumer.JWTMiddleware(tokens)(sessionMiddleware.Handler(mux))
+
+	// Sign a user out everywhere, e.g. after a password reset
+	err := tokens.RevokeUser(ctx, userID)
+*/
+
+// JWTAuth is the JWT auth provider.
+type JWTAuth = jwt.JWTAuthAdapter
+
+// JWTTokenPair is the access and refresh token a sign-in returns.
+type JWTTokenPair = jwt.TokenPair
+
+// contextKeyBearerAuth marks a request JWTMiddleware authenticated.
+const contextKeyBearerAuth SessionContextKey = "bearer_auth"
+
+// NewJWTAuthFromContainer returns the container's JWT auth provider, on the
+// container's database. It returns nil unless CONFIG_AUTH_PROVIDER is jwt.
+func NewJWTAuthFromContainer(container *Container) *JWTAuth {
+	if container == nil {
+		return nil
+	}
+	providerContract := container.GetAuthProvider()
+	if providerContract == nil {
+		return nil
+	}
+	var raw any = providerContract
+	if w, ok := providerContract.(interface{ Provider() interface{} }); ok {
+		if inner := w.Provider(); inner != nil {
+			raw = inner
+		}
+	}
+	tokens, ok := raw.(*JWTAuth)
+	if !ok {
+		return nil
+	}
+	if tokens.Store() == nil {
+		if ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation); ok && ops != nil {
+			tokens.SetOperations(ops)
+		}
+	}
+	return tokens
+}
+
+// JWTMiddleware authenticates requests bearing an access token as the
+// token's user, with no workspace selected. A bad token is refused with
+// 401; requests without a bearer token, or with an API key, pass through
+// untouched.
+func JWTMiddleware(tokens *JWTAuth) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if tokens == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || strings.HasPrefix(token, "esk_") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			claims, err := tokens.Verify(r.Context(), strings.TrimSpace(token))
+			if err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				_ = json.NewEncoder(w).Encode(map[string]any{"success": false, "error": err.Error()})
+				return
+			}
+			ctx := WithSessionIdentity(r.Context(), claims.Subject, "", "", claims.Email)
+			ctx = context.WithValue(ctx, contextKeyBearerAuth, true)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RegisterJWTAuthRoutes mounts the token endpoints (see jwt.TokenPath,
+// jwt.RefreshPath and jwt.RevokePath). They authenticate by what they are
+// sent and must stay outside the authentication middleware; the session
+// middleware's default "/auth/" exclusion covers them.
+func RegisterJWTAuthRoutes(server *ServerAdapter, tokens *JWTAuth) error {
+	if server == nil || tokens == nil {
+		return nil
+	}
+	handlers := jwt.NewHandlers(tokens)
+	if err := server.RegisterCustomHandler("POST", jwt.TokenPath, handlers.Token); err != nil {
+		return err
+	}
+	if err := server.RegisterCustomHandler("POST", jwt.RefreshPath, handlers.Refresh); err != nil {
+		return err
+	}
+	return server.RegisterCustomHandler("POST", jwt.RevokePath, handlers.Revoke)
+}
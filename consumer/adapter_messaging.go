@@ -0,0 +1,85 @@
+package consumer
+
+import (
+	"sort"
+
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/notification/messaging"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - SMS and WhatsApp Notifications
+
+Delivers notifications as SMS and WhatsApp messages through the messaging
+provider selected with CONFIG_MESSAGING_PROVIDER (twilio, built with the
+twilio tag). Sent messages and the delivery status the provider reports are
+kept in the notification_log table of the active database.
+
+Usage:
+
+	// Status callbacks; point TWILIO_STATUS_CALLBACK_URL at
+	// https://<host>/api/webhooks/messaging/twilio/status
+	consumer.RegisterMessagingStatusRoutes(server, container, consumer.NewNotificationLogFromContainer(container))
+
+	// SMS and WhatsApp are notification channels like email
+	notifier := consumer.NewNotifierFromContainer(container, config, "no-reply@example.com")
+	notifier.Notify(ctx, &ports.Notification{
+	    UserID: userID, Channel: ports.NotificationChannelWhatsApp, Recipient: "+15550100",
+	    Template: "invoice.due", TextBody: "...", Data: map[string]string{"1": "INV-7"},
+	})
+*/
+
+// NewNotificationLogFromContainer creates the notification log on the
+// container's database. It returns nil when no database is configured.
+func NewNotificationLogFromContainer(container *Container) ports.NotificationLog {
+	if container == nil {
+		return nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil
+	}
+	return messaging.NewDatabaseLog(ops, "")
+}
+
+// messagingProviders returns the container's messaging providers by name.
+func messagingProviders(container *Container) []ports.MessagingProvider {
+	providers := container.GetMessagingProviders()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]ports.MessagingProvider, 0, len(names))
+	for _, name := range names {
+		list = append(list, providers[name])
+	}
+	return list
+}
+
+// NewMessagingSendersFromContainer creates a notification channel for each
+// of SMS and WhatsApp the container's messaging providers serve. Messages
+// are logged when a database is configured.
+func NewMessagingSendersFromContainer(container *Container) []ports.NotificationSender {
+	if container == nil {
+		return nil
+	}
+	return messaging.NewSenders(NewNotificationLogFromContainer(container), messagingProviders(container)...)
+}
+
+// RegisterMessagingStatusRoutes mounts the delivery status webhook of each
+// messaging provider (see messaging.StatusPath). The routes must be public;
+// the providers verify the callbacks' signatures.
+func RegisterMessagingStatusRoutes(server *ServerAdapter, container *Container, log ports.NotificationLog) error {
+	if server == nil || container == nil || log == nil {
+		return nil
+	}
+	for _, provider := range messagingProviders(container) {
+		handlers := messaging.NewHandlers(provider, log)
+		if err := server.RegisterCustomHandler("POST", messaging.StatusPath(provider.Name()), handlers.Status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
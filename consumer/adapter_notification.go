@@ -0,0 +1,75 @@
+package consumer
+
+import (
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/notification/digest"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - Notification Digests
+
+Builds a Notifier that batches notifications into digests and caps how many
+messages a user receives per channel, delivering email through the
+container's email provider, push through its push providers, SMS and
+WhatsApp through its messaging providers, and in-app notifications into the
+inbox on its database.
+
+Usage:
+
+	notifier := consumer.NewNotifierFromContainer(container, consumer.NotificationConfig{
+	    Rules: map[string]consumer.NotificationRule{
+	        "conversation_post.new": {Mode: consumer.NotificationDigest, Window: consumer.NotificationHourly, Subject: "%d new messages"},
+	        "workflow.assigned":     {Mode: consumer.NotificationDigest, Window: consumer.NotificationDaily, MaxBatch: 20},
+	    },
+	    Caps: map[ports.NotificationChannel]consumer.NotificationCap{
+	        ports.NotificationChannelEmail: {Max: 10, Per: time.Hour},
+	    },
+	}, "no-reply@example.com")
+	go notifier.Run(ctx, time.Minute)
+
+	notifier.Notify(ctx, &ports.Notification{UserID: userID, Channel: ports.NotificationChannelEmail, ...})
+*/
+
+// Notification digest configuration types.
+type (
+	NotificationConfig = digest.Config
+	NotificationRule   = digest.Rule
+	NotificationCap    = digest.Cap
+	NotificationEngine = digest.Engine
+)
+
+// Notification delivery modes and digest windows.
+const (
+	NotificationImmediate = digest.Immediate
+	NotificationDigest    = digest.Digest
+	NotificationHourly    = digest.Hourly
+	NotificationDaily     = digest.Daily
+)
+
+// NewNotifierFromContainer creates a digesting Notifier that emails through
+// the container's email provider from the given address (empty uses the
+// provider default), pushes to registered devices when push providers are
+// configured, texts over SMS and WhatsApp when messaging providers are, and
+// keeps in_app notifications in the database inbox. It
+// returns nil when the container has none of these. Start its flush loop
+// with Run.
+func NewNotifierFromContainer(container *Container, config NotificationConfig, from string) *NotificationEngine {
+	if container == nil {
+		return nil
+	}
+	var senders []ports.NotificationSender
+	if provider := container.GetEmailProvider(); provider != nil {
+		senders = append(senders, digest.NewEmailSender(provider, from))
+	}
+	if sender := NewPushSenderFromContainer(container); sender != nil {
+		senders = append(senders, sender)
+	}
+	senders = append(senders, NewMessagingSendersFromContainer(container)...)
+	if sender := NewInboxSenderFromContainer(container); sender != nil {
+		senders = append(senders, sender)
+	}
+	if len(senders) == 0 {
+		return nil
+	}
+	return digest.New(config, nil, senders...)
+}
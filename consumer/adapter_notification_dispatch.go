@@ -0,0 +1,122 @@
+package consumer
+
+import (
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/notification/dispatch"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - Event Notifications
+
+Notifies people when business events happen: a booking's invitee when a
+schedule is booked, the client when an invoice becomes overdue, the
+workflow's creator when one of its stages completes. Each recipient gets
+the notification on the channel they prefer (email, sms, whatsapp, push,
+in_app, or none), kept in the notification_preference table, through the
+Notifier with its digests and caps. Failed deliveries are retried with
+backoff.
+
+Usage:
+
+	notifier := consumer.NewNotifierFromContainer(container, config, "no-reply@example.com")
+	dispatcher, err := consumer.NewNotificationDispatcherFromContainer(container, notifier, consumer.NotificationDispatchConfig{})
+	consumer.EnableNotificationDispatch(dispatcher) // events start queueing
+	go dispatcher.Run(ctx)
+
+	// GET/POST /api/notification/preferences, behind the authentication middleware
+	consumer.RegisterNotificationPreferenceRoutes(server, consumer.NewNotificationPreferencesFromContainer(container))
+
+Custom rules replace the defaults; start from consumer.DefaultNotificationRules
+to keep them:
+
+	rules := consumer.DefaultNotificationRules(container)
+	rules["invoice.created"] = consumer.NotificationEventRule{
+	    Recipients: rules["invoice.overdue"].Recipients,
+	    Subject:    "New invoice {{.Data.reference_number}}",
+	}
+*/
+
+// Event notification types.
+type (
+	NotificationDispatcher     = dispatch.Dispatcher
+	NotificationDispatchConfig = dispatch.Config
+	NotificationEventRule      = dispatch.Rule
+	NotificationRecipient      = dispatch.Recipient
+	NotificationRetryPolicy    = dispatch.RetryPolicy
+)
+
+// NewNotificationPreferencesFromContainer creates the channel preference
+// store on the container's database. It returns nil when no database is
+// configured.
+func NewNotificationPreferencesFromContainer(container *Container) ports.NotificationPreferences {
+	if container == nil {
+		return nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil
+	}
+	return dispatch.NewDatabasePreferences(ops, "")
+}
+
+// DefaultNotificationRules returns the built-in event rules, resolving
+// recipients on the container's database. It returns nil when no database
+// is configured.
+func DefaultNotificationRules(container *Container) map[string]NotificationEventRule {
+	if container == nil {
+		return nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil
+	}
+	tables := container.GetDBTableConfig()
+	return dispatch.DefaultRules(ops, dispatch.Tables{
+		Client:   tables.TableName("client"),
+		User:     tables.TableName("user"),
+		Workflow: tables.TableName("workflow"),
+	})
+}
+
+// NewNotificationDispatcherFromContainer creates the event notification
+// dispatcher delivering through notifier, with the container's preference
+// store. config.Rules defaults to DefaultNotificationRules. It returns nil
+// when notifier is nil or there are no rules.
+func NewNotificationDispatcherFromContainer(container *Container, notifier ports.Notifier, config NotificationDispatchConfig) (*NotificationDispatcher, error) {
+	if container == nil || notifier == nil {
+		return nil, nil
+	}
+	if config.Rules == nil {
+		config.Rules = DefaultNotificationRules(container)
+	}
+	if len(config.Rules) == 0 {
+		return nil, nil
+	}
+	return dispatch.NewDispatcher(notifier, NewNotificationPreferencesFromContainer(container), config)
+}
+
+// EnableNotificationDispatch makes dispatcher receive the events the use
+// cases emit. A nil dispatcher turns it off.
+func EnableNotificationDispatch(dispatcher *NotificationDispatcher) {
+	if dispatcher == nil {
+		domainevent.SetSink("notifications", nil)
+		return
+	}
+	domainevent.SetSink("notifications", dispatcher)
+}
+
+// RegisterNotificationPreferenceRoutes mounts the preference endpoints (see
+// dispatch.PreferencesPath). The routes must sit behind the authentication
+// middleware; requests without a user are rejected.
+func RegisterNotificationPreferenceRoutes(server *ServerAdapter, prefs ports.NotificationPreferences) error {
+	if server == nil || prefs == nil {
+		return nil
+	}
+	handlers := dispatch.NewHandlers(prefs)
+	if err := server.RegisterCustomHandler("GET", dispatch.PreferencesPath, handlers.List); err != nil {
+		return err
+	}
+	return server.RegisterCustomHandler("POST", dispatch.PreferencesPath, handlers.Set)
+}
@@ -0,0 +1,94 @@
+package consumer
+
+import (
+	"fmt"
+	"net/http"
+
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/auth/oauth"
+)
+
+/*
+ ESPYNA CONSUMER APP - OAuth2 / OpenID Connect Login
+
+"Sign in with Google / Microsoft" through the authorization-code flow with
+PKCE. Configure a provider with CONFIG_OAUTH_GOOGLE_CLIENT_ID or
+CONFIG_OAUTH_MICROSOFT_CLIENT_ID (see .env.example) and register
+CONFIG_OAUTH_REDIRECT_URL, the absolute URL of /auth/oauth/callback, with
+it.
+
+The first sign-in of an identity links it to the user with the same
+verified email or, when there is none, provisions a user and a
+workspace_user in the workspace configured for the email's domain. Later
+sign-ins go straight to the linked user. The session is the password
+provider's: a session is created and its cookie set, as after a password
+login.
+
+Usage:
+
+	authAdapter := consumer.NewAuthAdapterFromContainer(container)
+	sessions := consumer.NewSessionMiddleware(authAdapter)
+	login, err := consumer.NewOAuthLoginFromEnv(container, authAdapter, sessions)
+
+	// GET /auth/oauth/login?provider=google&return_to=/app and
+	// GET /auth/oauth/callback; public, covered by the session
+	// middleware's "/auth/" exclusion
+	consumer.RegisterOAuthRoutes(server, login)
+*/
+
+// OAuthLogin runs the OAuth2 / OpenID Connect sign-in flow.
+type OAuthLogin = oauth.Flow
+
+// OAuthConfig configures OAuthLogin.
+type OAuthConfig = oauth.Config
+
+// OAuthSessionStarter starts the session of a signed-in user.
+type OAuthSessionStarter = oauth.SessionStarter
+
+// NewOAuthLoginFromEnv creates the sign-in flow configured by the
+// CONFIG_OAUTH_* variables, starting password-provider sessions through
+// authAdapter and setting their cookie with sessions. It returns nil, nil
+// when no provider is configured.
+func NewOAuthLoginFromEnv(container *Container, authAdapter *AuthAdapter, sessions *SessionMiddleware) (*OAuthLogin, error) {
+	config, ok, err := oauth.ConfigFromEnv()
+	if err != nil || !ok {
+		return nil, err
+	}
+	if authAdapter == nil || sessions == nil {
+		return nil, fmt.Errorf("oauth: an auth adapter and session middleware are required")
+	}
+	return NewOAuthLogin(container, config, func(w http.ResponseWriter, r *http.Request, userID string) error {
+		token, err := authAdapter.CreateSession(r.Context(), userID)
+		if err != nil {
+			return err
+		}
+		sessions.SetSessionCookie(w, token)
+		return nil
+	})
+}
+
+// NewOAuthLogin creates the sign-in flow on the container's database with
+// a session starter of the caller's.
+func NewOAuthLogin(container *Container, config OAuthConfig, start OAuthSessionStarter) (*OAuthLogin, error) {
+	if container == nil {
+		return nil, fmt.Errorf("oauth: container is required")
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil, fmt.Errorf("oauth: no database is configured")
+	}
+	config.IdentityTable = container.GetDBTableConfig().TableName("user_identity")
+	return oauth.NewFlow(config, ops, start)
+}
+
+// RegisterOAuthRoutes mounts oauth.LoginPath and oauth.CallbackPath. They
+// must stay outside the authentication middleware.
+func RegisterOAuthRoutes(server *ServerAdapter, login *OAuthLogin) error {
+	if server == nil || login == nil {
+		return nil
+	}
+	if err := server.RegisterCustomHandler("GET", oauth.LoginPath, login.Login); err != nil {
+		return err
+	}
+	return server.RegisterCustomHandler("GET", oauth.CallbackPath, login.Callback)
+}
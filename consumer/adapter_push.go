@@ -0,0 +1,77 @@
+package consumer
+
+import (
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/notification/push"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - Mobile Push Notifications
+
+Delivers notifications to the devices users register from the mobile app,
+through the push providers selected with CONFIG_PUSH_PROVIDER (google_fcm,
+apns). Device tokens are kept in the device_token table of the active
+database.
+
+Usage:
+
+	// Endpoints the app calls after sign-in and on sign-out
+	consumer.RegisterDeviceTokenRoutes(server, consumer.NewDeviceTokenStoreFromContainer(container))
+
+	// Push is a notification channel like email
+	notifier := consumer.NewNotifierFromContainer(container, config, "no-reply@example.com")
+	notifier.Notify(ctx, &ports.Notification{
+	    UserID: userID, Channel: ports.NotificationChannelPush, Recipient: userID,
+	    Subject: "New assignment", TextBody: "...", Data: map[string]string{"link": "/workflow/123"},
+	})
+*/
+
+// NewDeviceTokenStoreFromContainer creates the device token store on the
+// container's database. It returns nil when no database is configured.
+func NewDeviceTokenStoreFromContainer(container *Container) ports.DeviceTokenStore {
+	if container == nil {
+		return nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil
+	}
+	return push.NewDatabaseTokenStore(ops, "")
+}
+
+// NewPushSenderFromContainer creates the push notification channel over the
+// container's push providers and device token store. It returns nil when no
+// push provider or database is configured.
+func NewPushSenderFromContainer(container *Container) ports.NotificationSender {
+	if container == nil {
+		return nil
+	}
+	providers := container.GetPushProviders()
+	if len(providers) == 0 {
+		return nil
+	}
+	store := NewDeviceTokenStoreFromContainer(container)
+	if store == nil {
+		return nil
+	}
+	list := make([]ports.PushProvider, 0, len(providers))
+	for _, p := range providers {
+		list = append(list, p)
+	}
+	return push.NewSender(store, list...)
+}
+
+// RegisterDeviceTokenRoutes mounts the device token register and unregister
+// endpoints (see push.RegisterPath). The routes must sit behind the
+// authentication middleware; requests without a user are rejected.
+func RegisterDeviceTokenRoutes(server *ServerAdapter, store ports.DeviceTokenStore) error {
+	if server == nil || store == nil {
+		return nil
+	}
+	handlers := push.NewHandlers(store)
+	if err := server.RegisterCustomHandler("POST", push.RegisterPath, handlers.Register); err != nil {
+		return err
+	}
+	return server.RegisterCustomHandler("POST", push.UnregisterPath, handlers.Unregister)
+}
@@ -0,0 +1,97 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/scheduler/subscription"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - Scheduler Webhook Subscription
+
+Registers the scheduler provider's webhook subscription (Calendly's
+invitee.created and invitee.canceled) so a deployment needs no manual setup
+in the provider's dashboard. On startup EnsureSchedulerWebhook registers the
+callback URL, or verifies the existing subscription and recreates it when
+the provider disabled it. The scheduler health check reports the
+subscription too, failing while it is missing or disabled.
+
+When no signing key is configured (CALENDLY_WEBHOOK_SECRET) one is generated
+and returned once; store it, or the next restart registers a new one.
+
+Usage:
+
+	// On startup; "" uses the provider's configured URL (CALENDLY_WEBHOOK_URL)
+	if err := consumer.EnsureSchedulerWebhook(ctx, container, ""); err != nil {
+		log.Printf("scheduler webhook: %v", err)
+	}
+
+	// Endpoints, behind the authentication middleware; the status needs
+	// scheduler_webhook:list, ensure and rotate scheduler_webhook:manage
+	consumer.RegisterSchedulerWebhookRoutes(server, container, authorizer)
+*/
+
+// SchedulerWebhookSubscription describes a provider webhook subscription.
+type SchedulerWebhookSubscription = ports.SchedulerWebhookSubscription
+
+// schedulerWebhookManager returns the container's scheduler provider when it
+// manages its webhook subscription.
+func schedulerWebhookManager(container *Container) (ports.SchedulerWebhookManager, bool) {
+	if container == nil {
+		return nil, false
+	}
+	provider := container.GetSchedulerProvider()
+	if provider == nil || !provider.IsEnabled() {
+		return nil, false
+	}
+	manager, ok := provider.(ports.SchedulerWebhookManager)
+	return manager, ok
+}
+
+// EnsureSchedulerWebhook registers or repairs the scheduler provider's
+// webhook subscription for callbackURL (the provider's configured URL when
+// empty). It is a no-op when the provider cannot manage subscriptions.
+func EnsureSchedulerWebhook(ctx context.Context, container *Container, callbackURL string) error {
+	manager, ok := schedulerWebhookManager(container)
+	if !ok {
+		return nil
+	}
+	sub, err := manager.EnsureWebhookSubscription(ctx, callbackURL)
+	if err != nil {
+		return fmt.Errorf("ensure scheduler webhook subscription: %w", err)
+	}
+	log.Printf("📅 Scheduler webhook subscription %s %s for %s", sub.ID, sub.State, sub.CallbackURL)
+	if sub.SigningKey != "" {
+		log.Printf("📅 Scheduler webhook signing key generated; store it in the provider's webhook secret setting")
+	}
+	return nil
+}
+
+// RegisterSchedulerWebhookRoutes mounts the subscription endpoints (see
+// subscription.StatusPath, EnsurePath and RotatePath). The routes must sit
+// behind the authentication middleware; authorizer decides who holds
+// scheduler_webhook:list and scheduler_webhook:manage, and a nil authorizer
+// denies everyone. Nothing is mounted when the provider cannot manage
+// subscriptions.
+func RegisterSchedulerWebhookRoutes(server *ServerAdapter, container *Container, authorizer ports.Authorizer) error {
+	manager, ok := schedulerWebhookManager(container)
+	if server == nil || !ok {
+		return nil
+	}
+	var gate *actiongate.ActionGatekeeper
+	if authorizer != nil {
+		gate = actiongate.NewActionGatekeeper(authorizer, ports.NewNoOpTranslator())
+	}
+	handlers := subscription.NewHandlers(manager, gate)
+	if err := server.RegisterCustomHandler("GET", subscription.StatusPath, handlers.Status); err != nil {
+		return err
+	}
+	if err := server.RegisterCustomHandler("POST", subscription.EnsurePath, handlers.Ensure); err != nil {
+		return err
+	}
+	return server.RegisterCustomHandler("POST", subscription.RotatePath, handlers.Rotate)
+}
@@ -0,0 +1,169 @@
+package consumer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/softdelete"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	"github.com/erniealice/espyna-golang/ports"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+/*
+ ESPYNA CONSUMER APP - Soft-Delete Recovery and Purge
+
+Delete only marks a record inactive. Until it is purged, a soft-deleted
+record can be listed (DatabaseOperation.ListDeleted) and restored
+(DatabaseOperation.Restore, or POST /api/{entity}/restore). The purger
+hard-deletes records that have stayed deleted longer than the retention
+period, on an interval for the entities named in
+CONFIG_SOFT_DELETE_PURGE_ENTITIES, or on demand over POST
+/api/{entity}/purge for the caller's workspace. A record another table
+still references is left in place and reported. Purging a record also
+removes its attachments and, when the storage provider can delete
+objects, their stored files.
+
+Usage:
+
+	purger, err := consumer.NewSoftDeletePurgerFromContainer(container)
+	if err != nil {
+		log.Fatal(err) // a malformed CONFIG_SOFT_DELETE_* setting
+	}
+	if purger != nil {
+		go purger.Run(ctx)
+	}
+
+	// Behind the authentication middleware: /restore needs <entity>:update,
+	// /purge needs <entity>:manage
+	consumer.RegisterSoftDeleteRoutes(server, container, purger, authorizer)
+*/
+
+// SoftDeletePurger hard-deletes soft-deleted records past their retention.
+type SoftDeletePurger = softdelete.Purger
+
+// NewSoftDeletePurgerFromContainer creates the purger on the container's
+// database. It returns nil when no database is configured.
+//
+// Settings:
+//
+//	CONFIG_SOFT_DELETE_RETENTION        how long deleted records stay
+//	                                    restorable (default 720h)
+//	CONFIG_SOFT_DELETE_PURGE_ENTITIES   comma separated entities Run purges
+//	                                    (default none: purge on demand only)
+//	CONFIG_SOFT_DELETE_PURGE_INTERVAL   time between scheduled purges
+//	                                    (default 24h)
+func NewSoftDeletePurgerFromContainer(container *Container) (*SoftDeletePurger, error) {
+	if container == nil {
+		return nil, nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil, nil
+	}
+	var config softdelete.Config
+	var err error
+	if config.Retention, err = envDuration("CONFIG_SOFT_DELETE_RETENTION"); err != nil {
+		return nil, err
+	}
+	if config.Interval, err = envDuration("CONFIG_SOFT_DELETE_PURGE_INTERVAL"); err != nil {
+		return nil, err
+	}
+	if raw := strings.TrimSpace(os.Getenv("CONFIG_SOFT_DELETE_PURGE_ENTITIES")); raw != "" {
+		known := map[string]bool{}
+		for _, entity := range repositoryEntities(container) {
+			known[entity] = true
+		}
+		tables := container.GetDBTableConfig()
+		config.Tables = map[string]string{}
+		for _, entity := range strings.Split(raw, ",") {
+			if entity = strings.TrimSpace(entity); entity == "" {
+				continue
+			}
+			if !known[entity] {
+				return nil, fmt.Errorf("CONFIG_SOFT_DELETE_PURGE_ENTITIES: unknown entity %q", entity)
+			}
+			config.Tables[entity] = tables.TableName(entity)
+		}
+	}
+	if cascade := attachmentCascade(container, ops); cascade != nil {
+		config.OnPurge = cascade.OnPurge
+	}
+	return softdelete.NewPurger(ops, config), nil
+}
+
+// attachmentCascade creates the hook removing the attachments of purged
+// records, or returns nil when the database has no attachment repository.
+func attachmentCascade(container *Container, ops dbinterfaces.DatabaseOperation) *softdelete.Attachments {
+	entities := repositoryEntities(container)
+	if i := sort.SearchStrings(entities, entityid.Attachment); i == len(entities) || entities[i] != entityid.Attachment {
+		return nil
+	}
+	tables := container.GetDBTableConfig()
+	entityTables := make(map[string]string, len(entities))
+	for _, entity := range entities {
+		entityTables[entity] = tables.TableName(entity)
+	}
+
+	// The container's storage provider may sit in a ProviderWrapper.
+	var objects softdelete.ObjectDeleter
+	if provider := container.GetStorageProvider(); provider != nil {
+		var ok bool
+		if objects, ok = provider.(softdelete.ObjectDeleter); !ok {
+			if wrapper, wrapperOk := provider.(interface{ Provider() interface{} }); wrapperOk {
+				objects, _ = wrapper.Provider().(softdelete.ObjectDeleter)
+			}
+		}
+	}
+	return softdelete.NewAttachments(ops, objects, tables.TableName(entityid.Attachment), entityTables)
+}
+
+// RegisterSoftDeleteRoutes mounts softdelete.RestorePath and
+// softdelete.PurgePath for entities, by default every entity the configured
+// database provider has a repository for. The routes must sit behind the
+// authentication middleware; authorizer decides who holds <entity>:update
+// and <entity>:manage, and a nil authorizer denies everyone.
+func RegisterSoftDeleteRoutes(server *ServerAdapter, container *Container, purger *SoftDeletePurger, authorizer ports.Authorizer, entities ...string) error {
+	if server == nil || container == nil || purger == nil {
+		return nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil
+	}
+	if len(entities) == 0 {
+		entities = repositoryEntities(container)
+	}
+	var gate *actiongate.ActionGatekeeper
+	if authorizer != nil {
+		gate = actiongate.NewActionGatekeeper(authorizer, ports.NewNoOpTranslator())
+	}
+	handlers := softdelete.NewHandlers(ops, purger, gate)
+	tables := container.GetDBTableConfig()
+	for _, entity := range entities {
+		table := tables.TableName(entity)
+		if err := server.RegisterCustomHandler("POST", softdelete.RestorePath(entity), handlers.Restore(entity, table)); err != nil {
+			return err
+		}
+		if err := server.RegisterCustomHandler("POST", softdelete.PurgePath(entity), handlers.Purge(entity, table)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// repositoryEntities lists the entities the configured database provider
+// has a repository for, sorted.
+func repositoryEntities(container *Container) []string {
+	provider := container.GetDatabaseProvider()
+	if provider == nil {
+		return nil
+	}
+	entities := registry.ListRepositoryFactories(provider.Name())
+	sort.Strings(entities)
+	return entities
+}
@@ -0,0 +1,137 @@
+package consumer
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/webhook"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+/*
+ ESPYNA CONSUMER APP - Outbound Webhooks
+
+Workspaces register HTTPS endpoints for the domain events the use cases
+emit (client.created, invoice.paid, schedule.cancelled, ...). Each event is
+POSTed as JSON, signed with the endpoint's secret in the Espyna-Signature
+header (webhook.Verify checks it), and retried with exponential backoff
+until the endpoint answers 2xx. Endpoints live in the webhook_endpoint
+table and every delivery in webhook_delivery, which also drives the
+retries, so they survive a restart.
+
+Usage:
+
+	dispatcher, err := consumer.NewWebhookDispatcherFromContainer(container)
+	if err != nil {
+		log.Fatal(err) // a malformed CONFIG_WEBHOOK_* setting
+	}
+	consumer.EnableWebhooks(dispatcher) // use cases start emitting to it
+	go dispatcher.Run(ctx, 15*time.Second)
+
+	// Endpoints, behind the authentication middleware; webhook:list,
+	// webhook:create and webhook:delete in the caller's workspace
+	consumer.RegisterWebhookRoutes(server, dispatcher, authorizer)
+*/
+
+// WebhookDispatcher delivers domain events to workspace endpoints.
+type WebhookDispatcher = webhook.Dispatcher
+
+// NewWebhookDispatcherFromContainer creates the webhook dispatcher on the
+// container's database. It returns nil when no database is configured.
+//
+// Settings:
+//
+//	CONFIG_WEBHOOK_MAX_ATTEMPTS  attempts per delivery, the first included
+//	                             (default 8)
+//	CONFIG_WEBHOOK_BACKOFF       wait after the first failure, doubling
+//	                             after each further one (default 30s)
+//	CONFIG_WEBHOOK_TIMEOUT       bound on each request (default 10s)
+//	CONFIG_WEBHOOK_ALLOW_HTTP    accept plain http endpoints, for local
+//	                             development
+func NewWebhookDispatcherFromContainer(container *Container) (*WebhookDispatcher, error) {
+	if container == nil {
+		return nil, nil
+	}
+	ops, ok := container.GetDatabaseOperations().(dbinterfaces.DatabaseOperation)
+	if !ok || ops == nil {
+		return nil, nil
+	}
+	var config webhook.Config
+	var err error
+	if config.MaxAttempts, err = envPositiveInt("CONFIG_WEBHOOK_MAX_ATTEMPTS"); err != nil {
+		return nil, err
+	}
+	if config.BaseBackoff, err = envDuration("CONFIG_WEBHOOK_BACKOFF"); err != nil {
+		return nil, err
+	}
+	if config.Timeout, err = envDuration("CONFIG_WEBHOOK_TIMEOUT"); err != nil {
+		return nil, err
+	}
+	store := webhook.NewStore(ops, "")
+	store.AllowHTTP, _ = strconv.ParseBool(os.Getenv("CONFIG_WEBHOOK_ALLOW_HTTP"))
+	return webhook.NewDispatcher(ops, store, config), nil
+}
+
+// envDuration reads an optional duration setting; zero when unset.
+func envDuration(name string) (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s=%q is not a duration like 30s", name, raw)
+	}
+	return d, nil
+}
+
+// envPositiveInt reads an optional positive number setting; zero when unset.
+func envPositiveInt(name string) (int, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s=%q is not a positive number", name, raw)
+	}
+	return n, nil
+}
+
+// EnableWebhooks makes dispatcher the receiver of the domain events the use
+// cases emit. A nil dispatcher turns emitting off.
+func EnableWebhooks(dispatcher *WebhookDispatcher) {
+	if dispatcher == nil {
+		domainevent.SetSink("webhook", nil)
+		return
+	}
+	domainevent.SetSink("webhook", dispatcher)
+}
+
+// RegisterWebhookRoutes mounts the webhook endpoints (see
+// webhook.EndpointsPath and webhook.DeliveriesPath). The routes must sit
+// behind the authentication middleware; authorizer decides who holds
+// webhook:list, webhook:create and webhook:delete, and a nil authorizer
+// denies everyone.
+func RegisterWebhookRoutes(server *ServerAdapter, dispatcher *WebhookDispatcher, authorizer ports.Authorizer) error {
+	if server == nil || dispatcher == nil {
+		return nil
+	}
+	var gate *actiongate.ActionGatekeeper
+	if authorizer != nil {
+		gate = actiongate.NewActionGatekeeper(authorizer, ports.NewNoOpTranslator())
+	}
+	handlers := webhook.NewHandlers(dispatcher, gate)
+	for _, method := range []string{"GET", "POST", "DELETE"} {
+		if err := server.RegisterCustomHandler(method, webhook.EndpointsPath, handlers.Endpoints); err != nil {
+			return err
+		}
+	}
+	return server.RegisterCustomHandler("GET", webhook.DeliveriesPath, handlers.Deliveries)
+}
@@ -0,0 +1,187 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/integration/payment"
+)
+
+// maxDisputeEvidenceBytes caps an evidence upload. PayPal rejects files over
+// 10 MB and requests over 50 MB.
+const maxDisputeEvidenceBytes = 50 << 20
+
+// paymentDisputeJSON is the wire shape of one dispute.
+type paymentDisputeJSON struct {
+	ID                 string `json:"id"`
+	ProviderID         string `json:"provider_id"`
+	ProviderDisputeRef string `json:"provider_dispute_ref"`
+	ProviderPaymentRef string `json:"provider_payment_ref,omitempty"`
+	PaymentID          string `json:"payment_id,omitempty"`
+	SubscriptionID     string `json:"subscription_id,omitempty"`
+	InvoiceID          string `json:"invoice_id,omitempty"`
+	Status             string `json:"status"`
+	ProviderStatus     string `json:"provider_status,omitempty"`
+	Reason             string `json:"reason,omitempty"`
+	Stage              string `json:"stage,omitempty"`
+	Amount             int64  `json:"amount"`
+	Currency           string `json:"currency"`
+	EvidenceDueAt      int64  `json:"evidence_due_at,omitempty"`
+	OpenedAt           int64  `json:"opened_at,omitempty"`
+	UpdatedAt          int64  `json:"updated_at,omitempty"`
+}
+
+func toPaymentDisputeJSON(d *ports.PaymentDispute) paymentDisputeJSON {
+	millis := func(t time.Time) int64 {
+		if t.IsZero() {
+			return 0
+		}
+		return t.UnixMilli()
+	}
+	return paymentDisputeJSON{
+		ID:                 d.ID,
+		ProviderID:         d.ProviderID,
+		ProviderDisputeRef: d.ProviderDisputeRef,
+		ProviderPaymentRef: d.ProviderPaymentRef,
+		PaymentID:          d.PaymentID,
+		SubscriptionID:     d.SubscriptionID,
+		InvoiceID:          d.InvoiceID,
+		Status:             string(d.Status),
+		ProviderStatus:     d.ProviderStatus,
+		Reason:             d.Reason,
+		Stage:              d.Stage,
+		Amount:             d.Amount,
+		Currency:           d.Currency,
+		EvidenceDueAt:      millis(d.EvidenceDueAt),
+		OpenedAt:           millis(d.OpenedAt),
+		UpdatedAt:          millis(d.UpdatedAt),
+	}
+}
+
+// paymentDisputeUseCases returns the payment use cases when dispute
+// tracking is wired, or nil.
+func (s *Server) paymentDisputeUseCases() *payment.UseCases {
+	if s.useCases == nil || s.useCases.Integration == nil || s.useCases.Integration.Payment == nil {
+		return nil
+	}
+	uc := s.useCases.Integration.Payment
+	if !uc.ListDisputes.Available() {
+		return nil
+	}
+	return uc
+}
+
+// paymentDisputesHandler serves GET /api/payment/disputes — stored payment
+// disputes, most recently updated first.
+//
+// Query parameters (all optional):
+//   - provider_id, status, subscription_id: exact-match filters.
+//   - unresolved: "true" keeps only disputes that are not won/lost/closed.
+//   - limit: maximum rows, default and cap 500.
+func (s *Server) paymentDisputesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	uc := s.paymentDisputeUseCases()
+	if uc == nil {
+		writeResolveError(w, http.StatusServiceUnavailable, "payment dispute tracking is not configured")
+		return
+	}
+
+	q := r.URL.Query()
+	filter := ports.PaymentDisputeFilter{
+		ProviderID:     q.Get("provider_id"),
+		Status:         ports.PaymentDisputeStatus(q.Get("status")),
+		SubscriptionID: q.Get("subscription_id"),
+		Unresolved:     q.Get("unresolved") == "true",
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeResolveError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		filter.Limit = n
+	}
+
+	disputes, err := uc.ListDisputes.Execute(r.Context(), filter)
+	if err != nil {
+		writeResolveError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]paymentDisputeJSON, 0, len(disputes))
+	for _, d := range disputes {
+		out = append(out, toPaymentDisputeJSON(d))
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"disputes": out})
+}
+
+// paymentDisputeEvidenceHandler serves POST
+// /api/payment/disputes/{id}/evidence — forwards seller evidence to the
+// provider. The body is multipart/form-data (or a urlencoded form when no
+// files are attached) with optional fields type, notes, carrier,
+// tracking_number and any number of "document" files.
+func (s *Server) paymentDisputeEvidenceHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	uc := s.paymentDisputeUseCases()
+	if uc == nil || !uc.SubmitDisputeEvidence.Supported() {
+		writeResolveError(w, http.StatusServiceUnavailable, "dispute evidence submission is not available for this payment provider")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxDisputeEvidenceBytes)
+	if err := r.ParseMultipartForm(maxDisputeEvidenceBytes); err != nil {
+		if !errors.Is(err, http.ErrNotMultipart) {
+			writeResolveError(w, http.StatusBadRequest, "invalid evidence upload: "+err.Error())
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			writeResolveError(w, http.StatusBadRequest, "invalid evidence form: "+err.Error())
+			return
+		}
+	}
+
+	evidence := &ports.PaymentDisputeEvidence{
+		Type:           r.FormValue("type"),
+		Notes:          r.FormValue("notes"),
+		Carrier:        r.FormValue("carrier"),
+		TrackingNumber: r.FormValue("tracking_number"),
+	}
+	if r.MultipartForm != nil {
+		for _, fh := range r.MultipartForm.File["document"] {
+			f, err := fh.Open()
+			if err != nil {
+				writeResolveError(w, http.StatusBadRequest, "failed to read "+fh.Filename)
+				return
+			}
+			content, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				writeResolveError(w, http.StatusBadRequest, "failed to read "+fh.Filename)
+				return
+			}
+			evidence.Documents = append(evidence.Documents, ports.PaymentDisputeDocument{
+				Name:        fh.Filename,
+				ContentType: fh.Header.Get("Content-Type"),
+				Content:     content,
+			})
+		}
+	}
+	if evidence.Notes == "" && evidence.TrackingNumber == "" && len(evidence.Documents) == 0 {
+		writeResolveError(w, http.StatusBadRequest, "evidence needs notes, a tracking number or at least one document")
+		return
+	}
+
+	if err := uc.SubmitDisputeEvidence.Execute(r.Context(), r.PathValue("id"), evidence); err != nil {
+		writeResolveError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]bool{"submitted": true})
+}
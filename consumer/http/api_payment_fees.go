@@ -0,0 +1,50 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/internal/application/usecases/service/reporting/payment_fees"
+)
+
+// paymentFeesHandler serves GET /api/reports/payment-fees — gross, provider
+// fees and net revenue per payment provider and currency.
+//
+// Query parameters:
+//   - start, end: required. Either epoch milliseconds or YYYY-MM-DD dates;
+//     a date-only end is inclusive (the whole day is counted).
+//   - provider_id: optional, narrows the report to one provider.
+func (s *Server) paymentFeesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if s.useCases == nil || s.useCases.Service == nil || s.useCases.Service.Reporting == nil ||
+		s.useCases.Service.Reporting.PaymentFees == nil ||
+		!s.useCases.Service.Reporting.PaymentFees.GetPaymentFeeReport.Available() {
+		writeResolveError(w, http.StatusServiceUnavailable, "payment fee reporting is not configured")
+		return
+	}
+
+	q := r.URL.Query()
+	start, ok := parseReportBound(q.Get("start"), false)
+	if !ok {
+		writeResolveError(w, http.StatusBadRequest, "start must be epoch milliseconds or YYYY-MM-DD")
+		return
+	}
+	end, ok := parseReportBound(q.Get("end"), true)
+	if !ok {
+		writeResolveError(w, http.StatusBadRequest, "end must be epoch milliseconds or YYYY-MM-DD")
+		return
+	}
+
+	resp, err := s.useCases.Service.Reporting.PaymentFees.GetPaymentFeeReport.Execute(r.Context(), &payment_fees.GetPaymentFeeReportRequest{
+		StartMillis: start,
+		EndMillis:   end,
+		ProviderID:  q.Get("provider_id"),
+	})
+	if err != nil {
+		writeResolveError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
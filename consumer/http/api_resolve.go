@@ -0,0 +1,41 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/internal/application/usecases/service/resolve"
+)
+
+// resolveIDHandler serves GET /api/resolve/{id}: it reports which entity type
+// a bare ID belongs to plus a minimal summary, for support tooling.
+func (s *Server) resolveIDHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if s.useCases == nil || s.useCases.Service == nil || s.useCases.Service.Resolve == nil ||
+		s.useCases.Service.Resolve.ResolveID == nil {
+		writeResolveError(w, http.StatusServiceUnavailable, "id resolution is not configured")
+		return
+	}
+
+	resp, err := s.useCases.Service.Resolve.ResolveID.Execute(r.Context(), &resolve.ResolveIDRequest{
+		ID: r.PathValue("id"),
+	})
+	switch {
+	case errors.Is(err, resolve.ErrIDNotFound):
+		writeResolveError(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		// Remaining failures are action-gate denials on the resolved type.
+		writeResolveError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func writeResolveError(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/usecases/service/sandbox"
+)
+
+// sandboxClockJSON is the wire shape of the business clock.
+type sandboxClockJSON struct {
+	Now           string `json:"now"`
+	OffsetSeconds int64  `json:"offset_seconds"`
+	Frozen        bool   `json:"frozen"`
+}
+
+// sandboxClockAdjustJSON is the POST body. Exactly one field is expected.
+type sandboxClockAdjustJSON struct {
+	// Advance is a Go duration ("36h", "-15m"). Days are not a Go duration
+	// unit; use "720h" for 30 days.
+	Advance string `json:"advance,omitempty"`
+	// Set is an RFC 3339 instant the clock is pinned at.
+	Set   string `json:"set,omitempty"`
+	Reset bool   `json:"reset,omitempty"`
+}
+
+func toSandboxClockJSON(state *sandbox.ClockState) sandboxClockJSON {
+	return sandboxClockJSON{
+		Now:           state.Now.Format(time.RFC3339),
+		OffsetSeconds: state.OffsetSeconds,
+		Frozen:        state.Frozen,
+	}
+}
+
+// sandboxUseCases returns the sandbox use cases when time travel is enabled,
+// or nil.
+func (s *Server) sandboxUseCases() *sandbox.UseCases {
+	if s.useCases == nil || s.useCases.Service == nil || s.useCases.Service.Sandbox == nil {
+		return nil
+	}
+	uc := s.useCases.Service.Sandbox
+	if !uc.GetClock.Available() {
+		return nil
+	}
+	return uc
+}
+
+// sandboxClockHandler serves GET /api/sandbox/clock — the business clock
+// used by billing, job spawning, SLA sweeps and the workflow engine.
+func (s *Server) sandboxClockHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	uc := s.sandboxUseCases()
+	if uc == nil {
+		writeResolveError(w, http.StatusServiceUnavailable, "sandbox time travel is not enabled")
+		return
+	}
+	state, err := uc.GetClock.Execute(r.Context())
+	if err != nil {
+		writeResolveError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(toSandboxClockJSON(state))
+}
+
+// sandboxClockAdjustHandler serves POST /api/sandbox/clock — advances, pins
+// or resets the business clock. Body: {"advance":"720h"},
+// {"set":"2030-01-31T00:00:00Z"} or {"reset":true}. Requires the
+// sandbox_clock:manage permission.
+func (s *Server) sandboxClockAdjustHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	uc := s.sandboxUseCases()
+	if uc == nil {
+		writeResolveError(w, http.StatusServiceUnavailable, "sandbox time travel is not enabled")
+		return
+	}
+
+	var body sandboxClockAdjustJSON
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&body); err != nil {
+		writeResolveError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	req := &sandbox.AdjustClockRequest{Reset: body.Reset}
+	if body.Advance != "" {
+		d, err := time.ParseDuration(body.Advance)
+		if err != nil {
+			writeResolveError(w, http.StatusBadRequest, "advance must be a duration such as 36h or -15m")
+			return
+		}
+		req.Advance = d
+	}
+	if body.Set != "" {
+		t, err := time.Parse(time.RFC3339, body.Set)
+		if err != nil {
+			writeResolveError(w, http.StatusBadRequest, "set must be an RFC 3339 timestamp")
+			return
+		}
+		req.Set = t
+	}
+
+	state, err := uc.AdjustClock.Execute(r.Context(), req)
+	switch {
+	case errors.Is(err, sandbox.ErrInvalidAdjustment):
+		writeResolveError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		// Remaining failures are action-gate denials.
+		writeResolveError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(toSandboxClockJSON(state))
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/integration/scheduler"
+)
+
+// scheduleImportJSON is the POST body of a schedule history import.
+type scheduleImportJSON struct {
+	// From and To are dates (YYYY-MM-DD) or RFC 3339 instants. To defaults
+	// to now.
+	From                 string `json:"from"`
+	To                   string `json:"to,omitempty"`
+	CreateMissingClients bool   `json:"create_missing_clients,omitempty"`
+	IncludeCanceled      bool   `json:"include_canceled,omitempty"`
+}
+
+// parseImportTime accepts a date or an RFC 3339 instant. A date as the
+// upper bound covers that whole day.
+func parseImportTime(v string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// scheduleImportHandler serves POST /api/scheduler/import — a one-time
+// backfill of past scheduler bookings (e.g. Calendly) as events of the
+// current workspace, linked to the clients matching invitee emails. Body:
+// {"from":"2024-01-01","create_missing_clients":true}. Re-running over
+// the same window only imports what is new.
+func (s *Server) scheduleImportHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	var uc *scheduler.ImportSchedulesUseCase
+	if s.useCases != nil && s.useCases.Integration != nil && s.useCases.Integration.Scheduler != nil {
+		uc = s.useCases.Integration.Scheduler.ImportSchedules
+	}
+	if !uc.Available() {
+		writeResolveError(w, http.StatusServiceUnavailable, "schedule import is not configured")
+		return
+	}
+
+	var body scheduleImportJSON
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&body); err != nil {
+		writeResolveError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	req := &scheduler.ImportSchedulesRequest{
+		CreateMissingClients: body.CreateMissingClients,
+		IncludeCanceled:      body.IncludeCanceled,
+	}
+	var err error
+	if req.From, err = parseImportTime(body.From, false); err != nil {
+		writeResolveError(w, http.StatusBadRequest, "from must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+		return
+	}
+	if body.To != "" {
+		if req.To, err = parseImportTime(body.To, true); err != nil {
+			writeResolveError(w, http.StatusBadRequest, "to must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+			return
+		}
+	}
+
+	result, err := uc.Execute(r.Context(), req)
+	switch {
+	case errors.Is(err, scheduler.ErrInvalidImportWindow):
+		writeResolveError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, scheduler.ErrImportUnavailable):
+		writeResolveError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	case err != nil && result == nil:
+		// Remaining failures before the run starts are action-gate denials.
+		writeResolveError(w, http.StatusForbidden, err.Error())
+		return
+	case err != nil:
+		// The provider listing failed part way; report what was imported.
+		w.WriteHeader(http.StatusBadGateway)
+		_ = json.NewEncoder(w).Encode(map[string]any{"error": err.Error(), "result": result})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
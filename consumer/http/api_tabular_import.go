@@ -0,0 +1,58 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/integration/tabular"
+)
+
+// tabularImportHandler serves POST /api/tabular/import — a bulk import of
+// the rows of a tabular source (a Google Sheet, a Notion database) as
+// clients, staff or locations of the current workspace. Body:
+//
+//	{"source_id":"1AbC...","table":"Clients","dry_run":true,
+//	 "mapping":{"entity":"client","columns":{"Email":"user.email_address"}}}
+//
+// The response reports each row that was skipped and why; with dry_run
+// nothing is written, so a mapping can be checked before the real run.
+func (s *Server) tabularImportHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	var uc *tabular.ImportEntitiesUseCase
+	if s.useCases != nil && s.useCases.Integration != nil && s.useCases.Integration.Tabular != nil {
+		uc = s.useCases.Integration.Tabular.ImportEntities
+	}
+	if !uc.Available() {
+		writeResolveError(w, http.StatusServiceUnavailable, "tabular import is not configured")
+		return
+	}
+
+	var req tabular.ImportEntitiesRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+		writeResolveError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	result, err := uc.Execute(r.Context(), &req)
+	switch {
+	case errors.Is(err, tabular.ErrInvalidImport):
+		writeResolveError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, tabular.ErrImportUnavailable):
+		writeResolveError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	case err != nil && result == nil:
+		// Remaining failures before the run starts are action-gate denials.
+		writeResolveError(w, http.StatusForbidden, err.Error())
+		return
+	case err != nil:
+		// The provider read failed part way; report what was imported.
+		w.WriteHeader(http.StatusBadGateway)
+		_ = json.NewEncoder(w).Encode(map[string]any{"error": err.Error(), "result": result})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
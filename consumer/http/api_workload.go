@@ -0,0 +1,85 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/usecases/service/reporting/workload"
+)
+
+// staffWorkloadHandler serves GET /api/reports/staff-workload for the
+// operations dashboard.
+//
+// Query parameters:
+//   - start, end: required. Either epoch milliseconds or YYYY-MM-DD dates;
+//     a date-only end is inclusive (the whole day is counted).
+//   - staff_id: optional, narrows the report to one staff member.
+//   - hours_per_day: optional per-working-day capacity (default 8).
+func (s *Server) staffWorkloadHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if s.useCases == nil || s.useCases.Service == nil || s.useCases.Service.Reporting == nil ||
+		s.useCases.Service.Reporting.Workload == nil ||
+		!s.useCases.Service.Reporting.Workload.GetStaffWorkloadReport.Available() {
+		writeResolveError(w, http.StatusServiceUnavailable, "staff workload reporting is not configured")
+		return
+	}
+
+	q := r.URL.Query()
+	start, ok := parseReportBound(q.Get("start"), false)
+	if !ok {
+		writeResolveError(w, http.StatusBadRequest, "start must be epoch milliseconds or YYYY-MM-DD")
+		return
+	}
+	end, ok := parseReportBound(q.Get("end"), true)
+	if !ok {
+		writeResolveError(w, http.StatusBadRequest, "end must be epoch milliseconds or YYYY-MM-DD")
+		return
+	}
+	var perDay float64
+	if v := q.Get("hours_per_day"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed <= 0 || parsed > 24 {
+			writeResolveError(w, http.StatusBadRequest, "hours_per_day must be between 0 and 24")
+			return
+		}
+		perDay = parsed
+	}
+
+	resp, err := s.useCases.Service.Reporting.Workload.GetStaffWorkloadReport.Execute(r.Context(), &workload.GetStaffWorkloadReportRequest{
+		StartMillis:         start,
+		EndMillis:           end,
+		StaffID:             q.Get("staff_id"),
+		CapacityHoursPerDay: perDay,
+	})
+	if err != nil {
+		// Range validation and action-gate denials both surface here; the
+		// reporter itself was checked above.
+		writeResolveError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// parseReportBound accepts epoch milliseconds or a YYYY-MM-DD date (UTC).
+// When endOfDay is set a date-only value moves to the following midnight so
+// the range end is inclusive of that day.
+func parseReportBound(v string, endOfDay bool) (int64, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return ms, true
+	}
+	d, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return 0, false
+	}
+	if endOfDay {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d.UnixMilli(), true
+}
@@ -541,6 +541,15 @@ func (s *Server) assembleHandler() http.Handler {
 		w.Header().Set("Cache-Control", "no-store")
 		_, _ = io.WriteString(w, `{"notifications":[]}`)
 	})
+	mux.HandleFunc("GET /api/resolve/{id}", s.resolveIDHandler)
+	mux.HandleFunc("GET /api/reports/staff-workload", s.staffWorkloadHandler)
+	mux.HandleFunc("GET /api/reports/payment-fees", s.paymentFeesHandler)
+	mux.HandleFunc("GET /api/payment/disputes", s.paymentDisputesHandler)
+	mux.HandleFunc("POST /api/payment/disputes/{id}/evidence", s.paymentDisputeEvidenceHandler)
+	mux.HandleFunc("POST /api/scheduler/import", s.scheduleImportHandler)
+	mux.HandleFunc("POST /api/tabular/import", s.tabularImportHandler)
+	mux.HandleFunc("GET /api/sandbox/clock", s.sandboxClockHandler)
+	mux.HandleFunc("POST /api/sandbox/clock", s.sandboxClockAdjustHandler)
 	if s.catchAllHandler != nil {
 		mux.Handle("/", s.catchAllHandler)
 	} else {
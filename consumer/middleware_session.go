@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	consumermw "github.com/erniealice/espyna-golang/consumer/http/middleware"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/auth/apikey"
 	sharedidentity "github.com/erniealice/espyna-golang/shared/identity"
 )
 
@@ -78,6 +79,17 @@ func (m *SessionMiddleware) Handler(next http.Handler) http.Handler {
 			return
 		}
 
+		// A request APIKeyMiddleware or JWTMiddleware authenticated carries
+		// its identity and no session cookie.
+		if rid, ok := sharedidentity.FromContext(r.Context()); ok && strings.HasPrefix(rid.UserID, apikey.PrincipalPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if bearer, _ := r.Context().Value(contextKeyBearerAuth).(bool); bearer {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		// Read session token from cookie. This is the SINGLE source of binding
 		// truth for the request: after a principal switch A→B the cookie holds
 		// either the rotated token (workspace change) or the same token whose
@@ -0,0 +1,7 @@
+//go:build apikey_auth
+
+package consumer
+
+// Registers the apikey auth provider, which fronts the provider named by
+// CONFIG_APIKEY_FALLBACK_PROVIDER, under -tags apikey_auth.
+import _ "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/auth/apikey"
@@ -0,0 +1,7 @@
+//go:build jwt_auth
+
+package consumer
+
+// Registers the jwt auth provider, which issues its own access and refresh
+// tokens, under -tags jwt_auth.
+import _ "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/auth/jwt"
@@ -0,0 +1,6 @@
+//go:build mock_cache
+
+package consumer
+
+// Activates the in-memory database result cache under -tags mock_cache.
+import _ "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/cache/mock"
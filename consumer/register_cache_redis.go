@@ -0,0 +1,6 @@
+//go:build redis_cache
+
+package consumer
+
+// Activates the Redis database result cache under -tags redis_cache.
+import _ "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/cache/redis"
@@ -0,0 +1,6 @@
+//go:build mock_events
+
+package consumer
+
+// Activates the in-memory event publisher under -tags mock_events.
+import _ "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/events/mock"
@@ -0,0 +1,8 @@
+//go:build pubsub
+
+package consumer
+
+// Pulls in the Google Pub/Sub event publisher via the contrib/google
+// sibling module, which only registers it when the pubsub build tag is
+// active.
+import _ "github.com/erniealice/espyna-golang/contrib/google"
@@ -0,0 +1,5 @@
+//go:build twilio
+
+package consumer
+
+import _ "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/notification/messaging/twilio"
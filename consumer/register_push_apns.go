@@ -0,0 +1,5 @@
+//go:build apns
+
+package consumer
+
+import _ "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/notification/push/apns"
@@ -0,0 +1,7 @@
+//go:build google_fcm
+
+package consumer
+
+// Pulls in the FCM push adapter via the contrib/google sibling module,
+// which only registers it when the google_fcm build tag is active.
+import _ "github.com/erniealice/espyna-golang/contrib/google"
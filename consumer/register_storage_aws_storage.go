@@ -1,4 +1,4 @@
-//go:build aws_storage
+//go:build aws_storage || s3_storage
 
 package consumer
 
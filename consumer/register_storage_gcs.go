@@ -1,4 +1,4 @@
-//go:build gcs
+//go:build gcs || gcs_storage
 
 package consumer
 
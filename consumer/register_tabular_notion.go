@@ -0,0 +1,6 @@
+//go:build notion
+
+package consumer
+
+// Activates the Notion tabular adapter under -tags notion.
+import _ "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/tabular/notion"
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -53,6 +54,11 @@ func init() {
 // Env key scheme (ST-M2): the canonical keys converge on STORAGE_S3_<KEY>. The
 // legacy STORAGE_BUCKET_NAME / AWS_REGION keys remain accepted fallbacks for one
 // release so existing deploys do not break.
+//
+// Workspace isolation: STORAGE_S3_ISOLATION=prefix keeps each workspace under
+// workspaces/<workspace_id>/ in the bucket; =bucket gives each workspace its own
+// bucket named by STORAGE_S3_BUCKET_TEMPLATE (e.g. acme-files-{workspace}),
+// created on first upload. Both read the workspace from the request identity.
 func buildFromEnv() (ports.StorageProvider, error) {
 	// Bucket + region: prefer the standardized STORAGE_S3_* keys, fall back to the
 	// legacy keys for one release.
@@ -71,6 +77,18 @@ func buildFromEnv() (ports.StorageProvider, error) {
 	secretAccessKey := os.Getenv("STORAGE_S3_SECRET_ACCESS_KEY")
 	sessionToken := os.Getenv("STORAGE_S3_SESSION_TOKEN")
 
+	isolation, err := storagecommon.ParseIsolation(os.Getenv("STORAGE_S3_ISOLATION"))
+	if err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+	scope := storagecommon.WorkspaceScope{
+		Mode:           isolation,
+		BucketTemplate: os.Getenv("STORAGE_S3_BUCKET_TEMPLATE"),
+	}
+	if err := scope.Validate(); err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+
 	protoConfig := &pb.StorageProviderConfig{
 		Provider: pb.StorageProvider_STORAGE_PROVIDER_AWS,
 		Config: &pb.StorageProviderConfig_S3Config{
@@ -86,7 +104,8 @@ func buildFromEnv() (ports.StorageProvider, error) {
 			},
 		},
 	}
-	p := NewS3StorageProvider()
+	p := NewS3StorageProvider().(*S3StorageProvider)
+	p.scope = scope
 	if err := p.Initialize(protoConfig); err != nil {
 		return nil, fmt.Errorf("s3: failed to initialize: %w", err)
 	}
@@ -121,6 +140,11 @@ type S3StorageProvider struct {
 	region     string
 	enabled    bool
 	timeout    time.Duration
+
+	// scope maps logical containers/keys to workspace-isolated ones; buckets
+	// remembers the workspace buckets known to exist.
+	scope   storagecommon.WorkspaceScope
+	buckets sync.Map
 }
 
 // NewS3StorageProvider creates a new AWS S3 storage provider
@@ -241,6 +265,41 @@ func (p *S3StorageProvider) testBucketAccess(ctx context.Context) error {
 	return nil
 }
 
+// locate returns the bucket and key a logical container/key is stored under for
+// the request's workspace. Responses keep reporting the logical names.
+func (p *S3StorageProvider) locate(ctx context.Context, container, key string) (string, string, error) {
+	if container == "" {
+		container = p.bucketName
+	}
+	return p.scope.Resolve(ctx, container, key)
+}
+
+// ensureBucket creates a workspace's bucket the first time it is written to.
+// Only bucket isolation creates buckets; otherwise the bucket must exist.
+func (p *S3StorageProvider) ensureBucket(ctx context.Context, bucket string) error {
+	if p.scope.Mode != storagecommon.IsolationBucket {
+		return nil
+	}
+	if _, ok := p.buckets.Load(bucket); ok {
+		return nil
+	}
+	headCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if _, err := p.client.HeadBucket(headCtx, &s3.HeadBucketInput{Bucket: aws.String(bucket)}); err == nil {
+		p.buckets.Store(bucket, struct{}{})
+		return nil
+	}
+	_, err := p.CreateContainer(ctx, &pb.CreateContainerRequest{Name: bucket})
+	if err != nil {
+		var storageErr *ports.StorageError
+		if !errors.As(err, &storageErr) || storageErr.Code != ports.StorageErrorCodeAlreadyExists {
+			return fmt.Errorf("create workspace bucket %s: %w", bucket, err)
+		}
+	}
+	p.buckets.Store(bucket, struct{}{})
+	return nil
+}
+
 // UploadObject stores an object in S3
 func (p *S3StorageProvider) UploadObject(ctx context.Context, req *pb.UploadObjectRequest) (*pb.UploadObjectResponse, error) {
 	startTime := time.Now()
@@ -268,6 +327,19 @@ func (p *S3StorageProvider) UploadObject(ctx context.Context, req *pb.UploadObje
 
 	// Sanitize object key
 	objectKey := strings.Trim(req.ObjectKey, "/")
+	bucket, key, scopeErr := p.locate(ctx, bucketName, objectKey)
+	if scopeErr != nil {
+		return &pb.UploadObjectResponse{
+			Success: false,
+			Message: scopeErr.Error(),
+		}, ports.NewStorageError(ports.StorageErrorCodeAccessDenied, "workspace scope", scopeErr)
+	}
+	if err := p.ensureBucket(ctx, bucket); err != nil {
+		return &pb.UploadObjectResponse{
+			Success: false,
+			Message: err.Error(),
+		}, ports.NewStorageError(ports.StorageErrorCodeProviderError, "workspace bucket", err)
+	}
 
 	// Create context with timeout
 	uploadCtx, cancel := context.WithTimeout(ctx, p.timeout)
@@ -275,8 +347,8 @@ func (p *S3StorageProvider) UploadObject(ctx context.Context, req *pb.UploadObje
 
 	// Prepare upload input
 	input := &s3.PutObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(objectKey),
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
 		Body:   bytes.NewReader(req.Content),
 	}
 
@@ -324,8 +396,8 @@ func (p *S3StorageProvider) UploadObject(ctx context.Context, req *pb.UploadObje
 	// Check if exists and handle overwrite
 	if !req.Overwrite {
 		_, err := p.client.HeadObject(uploadCtx, &s3.HeadObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(objectKey),
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
 		})
 		if err == nil {
 			return &pb.UploadObjectResponse{
@@ -395,6 +467,13 @@ func (p *S3StorageProvider) DownloadObject(ctx context.Context, req *pb.Download
 	}
 
 	objectKey := strings.Trim(req.ObjectKey, "/")
+	bucket, key, scopeErr := p.locate(ctx, bucketName, objectKey)
+	if scopeErr != nil {
+		return &pb.DownloadObjectResponse{
+			Success: false,
+			Message: scopeErr.Error(),
+		}, ports.NewStorageError(ports.StorageErrorCodeAccessDenied, "workspace scope", scopeErr)
+	}
 
 	// Create context with timeout
 	downloadCtx, cancel := context.WithTimeout(ctx, p.timeout)
@@ -402,8 +481,8 @@ func (p *S3StorageProvider) DownloadObject(ctx context.Context, req *pb.Download
 
 	// Prepare input
 	input := &s3.GetObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(objectKey),
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
 	}
 
 	if req.VersionId != "" {
@@ -491,6 +570,13 @@ func (p *S3StorageProvider) GetPresignedUrl(ctx context.Context, req *pb.GetPres
 	}
 
 	objectKey := strings.Trim(req.ObjectKey, "/")
+	bucket, key, scopeErr := p.locate(ctx, bucketName, objectKey)
+	if scopeErr != nil {
+		return &pb.GetPresignedUrlResponse{
+			Success: false,
+			Message: scopeErr.Error(),
+		}, ports.NewStorageError(ports.StorageErrorCodeAccessDenied, "workspace scope", scopeErr)
+	}
 	expiresIn := time.Duration(req.ExpiresInSeconds) * time.Second
 	expiresAt := time.Now().Add(expiresIn)
 
@@ -505,8 +591,8 @@ func (p *S3StorageProvider) GetPresignedUrl(ctx context.Context, req *pb.GetPres
 	switch req.Operation {
 	case pb.PresignedUrlOperation_PRESIGNED_URL_OPERATION_DOWNLOAD:
 		getReq := &s3.GetObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(objectKey),
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
 		}
 		// Disposition + safe-content-type pinning AT SIGNING TIME (Q-ST-STREAM B+C,
 		// ST-H3 baked into the signed URL). These two response-header overrides are
@@ -545,9 +631,15 @@ func (p *S3StorageProvider) GetPresignedUrl(ctx context.Context, req *pb.GetPres
 		}
 
 	case pb.PresignedUrlOperation_PRESIGNED_URL_OPERATION_UPLOAD:
+		if err := p.ensureBucket(ctx, bucket); err != nil {
+			return &pb.GetPresignedUrlResponse{
+				Success: false,
+				Message: err.Error(),
+			}, ports.NewStorageError(ports.StorageErrorCodeProviderError, "workspace bucket", err)
+		}
 		putReq := &s3.PutObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(objectKey),
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
 		}
 		if req.ContentType != "" {
 			putReq.ContentType = aws.String(req.ContentType)
@@ -564,8 +656,8 @@ func (p *S3StorageProvider) GetPresignedUrl(ctx context.Context, req *pb.GetPres
 
 	case pb.PresignedUrlOperation_PRESIGNED_URL_OPERATION_DELETE:
 		deleteReq := &s3.DeleteObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(objectKey),
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
 		}
 		presignResult, presignErr := presignClient.PresignDeleteObject(ctx, deleteReq, func(opts *s3.PresignOptions) {
 			opts.Expires = expiresIn
@@ -803,6 +895,37 @@ func (p *S3StorageProvider) DeleteContainer(ctx context.Context, req *pb.DeleteC
 	}, nil
 }
 
+// DeleteObject deletes an object from the request workspace's bucket or prefix.
+// S3 reports success for a missing key; a missing workspace bucket means there is
+// nothing to delete either.
+func (p *S3StorageProvider) DeleteObject(ctx context.Context, containerName, objectKey string) error {
+	if !p.enabled {
+		return ports.NewStorageError(ports.StorageErrorCodeProviderError, "not initialized", nil)
+	}
+	objectKey = strings.Trim(objectKey, "/")
+	if objectKey == "" {
+		return ports.NewStorageError(ports.StorageErrorCodeInvalidPath, "object key required", nil)
+	}
+	bucket, key, err := p.locate(ctx, containerName, objectKey)
+	if err != nil {
+		return ports.NewStorageError(ports.StorageErrorCodeAccessDenied, "workspace scope", err)
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if _, err := p.client.DeleteObject(deleteCtx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}); err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchBucket" {
+			return nil
+		}
+		return ports.NewStorageError(ports.StorageErrorCodeDeleteFailed, "delete object failed", err)
+	}
+	return nil
+}
+
 // IsHealthy checks if the S3 storage service is available
 func (p *S3StorageProvider) IsHealthy(ctx context.Context) error {
 	if !p.enabled {
@@ -830,10 +953,12 @@ func (p *S3StorageProvider) IsEnabled() bool {
 // Streaming tier (StreamingStorageProvider) + capability discovery
 // =============================================================================
 
-// Compile-time assertions that S3 implements both optional sub-interfaces.
+// Compile-time assertions that S3 implements the optional sub-interfaces.
 var (
-	_ ports.StreamingStorageProvider  = (*S3StorageProvider)(nil)
-	_ ports.StorageCapabilityProvider = (*S3StorageProvider)(nil)
+	_ ports.StreamingStorageProvider      = (*S3StorageProvider)(nil)
+	_ ports.MultipartStorageProvider      = (*S3StorageProvider)(nil)
+	_ ports.StorageCapabilityProvider     = (*S3StorageProvider)(nil)
+	_ ports.ObjectDeletingStorageProvider = (*S3StorageProvider)(nil)
 )
 
 // UploadStream streams body directly to S3 via PutObject. The AWS SDK accepts any
@@ -862,13 +987,26 @@ func (p *S3StorageProvider) UploadStream(ctx context.Context, req *pb.UploadObje
 		bucketName = p.bucketName
 	}
 	objectKey := strings.Trim(req.ObjectKey, "/")
+	bucket, key, scopeErr := p.locate(ctx, bucketName, objectKey)
+	if scopeErr != nil {
+		return &pb.UploadObjectResponse{
+			Success: false,
+			Message: scopeErr.Error(),
+		}, ports.NewStorageError(ports.StorageErrorCodeAccessDenied, "workspace scope", scopeErr)
+	}
+	if err := p.ensureBucket(ctx, bucket); err != nil {
+		return &pb.UploadObjectResponse{
+			Success: false,
+			Message: err.Error(),
+		}, ports.NewStorageError(ports.StorageErrorCodeProviderError, "workspace bucket", err)
+	}
 
 	uploadCtx, cancel := context.WithTimeout(ctx, p.timeout)
 	defer cancel()
 
 	input := &s3.PutObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(objectKey),
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
 		Body:   body, // streamed natively — io.Reader is consumed lazily by the SDK
 	}
 
@@ -962,10 +1100,17 @@ func (p *S3StorageProvider) DownloadStream(ctx context.Context, req *pb.Download
 		bucketName = p.bucketName
 	}
 	objectKey := strings.Trim(req.ObjectKey, "/")
+	bucket, key, scopeErr := p.locate(ctx, bucketName, objectKey)
+	if scopeErr != nil {
+		return nil, &pb.DownloadObjectResponse{
+			Success: false,
+			Message: scopeErr.Error(),
+		}, ports.NewStorageError(ports.StorageErrorCodeAccessDenied, "workspace scope", scopeErr)
+	}
 
 	input := &s3.GetObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(objectKey),
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
 	}
 	if req.VersionId != "" {
 		input.VersionId = aws.String(req.VersionId)
@@ -1029,6 +1174,7 @@ func (p *S3StorageProvider) GetCapabilities() []ports.StorageCapability {
 		ports.StorageCapabilityDelete,
 		ports.StorageCapabilityStreaming,
 		ports.StorageCapabilityPresignedUrls,
+		ports.StorageCapabilityMultipartUpload,
 		ports.StorageCapabilityMetadata,
 	}
 }
@@ -0,0 +1,251 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/erniealice/espyna-golang/ports"
+	storagecommon "github.com/erniealice/espyna-golang/storage/helpers"
+	pb "github.com/erniealice/esqyma/pkg/schema/v1/infrastructure/storage"
+)
+
+// =============================================================================
+// Multipart tier (MultipartStorageProvider)
+// =============================================================================
+
+// maxParts is the most parts an S3 multipart upload may have.
+const maxParts = 10000
+
+// InitiateMultipartUpload starts a multipart upload. The returned upload carries
+// the logical container/key; every later call re-resolves them against the
+// request's workspace, so an upload ID cannot be replayed from another workspace.
+func (p *S3StorageProvider) InitiateMultipartUpload(ctx context.Context, req *pb.UploadObjectRequest) (*ports.MultipartUpload, error) {
+	if !p.enabled {
+		return nil, ports.NewStorageError(ports.StorageErrorCodeProviderError, "not initialized", nil)
+	}
+	if req.ContainerName == "" || req.ObjectKey == "" {
+		return nil, ports.NewStorageError(ports.StorageErrorCodeInvalidPath, "missing required fields", nil)
+	}
+
+	objectKey := strings.Trim(req.ObjectKey, "/")
+	bucket, key, err := p.locate(ctx, req.ContainerName, objectKey)
+	if err != nil {
+		return nil, ports.NewStorageError(ports.StorageErrorCodeAccessDenied, "workspace scope", err)
+	}
+	if err := p.ensureBucket(ctx, bucket); err != nil {
+		return nil, ports.NewStorageError(ports.StorageErrorCodeProviderError, "workspace bucket", err)
+	}
+
+	input := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	contentType := req.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(req.ObjectKey))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+	}
+	input.ContentType = aws.String(contentType)
+	if len(req.Metadata) > 0 {
+		input.Metadata = req.Metadata
+	}
+	if req.CacheControl != "" {
+		input.CacheControl = aws.String(req.CacheControl)
+	}
+	if req.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(req.ContentDisposition)
+	}
+	if s3Opts := req.GetS3Options(); s3Opts != nil {
+		if s3Opts.StorageClass != "" {
+			input.StorageClass = types.StorageClass(s3Opts.StorageClass)
+		}
+		if s3Opts.SseAlgorithm != "" {
+			input.ServerSideEncryption = types.ServerSideEncryption(s3Opts.SseAlgorithm)
+		}
+		if s3Opts.KmsKeyId != "" {
+			input.SSEKMSKeyId = aws.String(s3Opts.KmsKeyId)
+		}
+		if s3Opts.Acl != "" {
+			input.ACL = types.ObjectCannedACL(s3Opts.Acl)
+		}
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	result, err := p.client.CreateMultipartUpload(createCtx, input)
+	if err != nil {
+		return nil, ports.NewStorageError(ports.StorageErrorCodeUploadFailed, "initiate multipart upload failed", err)
+	}
+	return &ports.MultipartUpload{
+		ContainerName: req.ContainerName,
+		ObjectKey:     objectKey,
+		UploadID:      aws.ToString(result.UploadId),
+	}, nil
+}
+
+// UploadPart streams one part to S3. size is sent as the Content-Length; pass -1
+// only when body is seekable.
+func (p *S3StorageProvider) UploadPart(ctx context.Context, upload *ports.MultipartUpload, partNumber int32, body io.Reader, size int64) (*ports.CompletedPart, error) {
+	bucket, key, err := p.locateUpload(ctx, upload, partNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.UploadPartInput{
+		Bucket:     aws.String(bucket),
+		Key:        aws.String(key),
+		UploadId:   aws.String(upload.UploadID),
+		PartNumber: aws.Int32(partNumber),
+		Body:       body,
+	}
+	if size >= 0 {
+		input.ContentLength = aws.Int64(size)
+	}
+
+	// NOTE: no per-call timeout — a large part may outlive p.timeout. The parent
+	// ctx governs cancellation, as in DownloadStream.
+	result, err := p.client.UploadPart(ctx, input)
+	if err != nil {
+		return nil, ports.NewStorageError(ports.StorageErrorCodeUploadFailed, "upload part failed", err)
+	}
+	return &ports.CompletedPart{PartNumber: partNumber, ETag: aws.ToString(result.ETag)}, nil
+}
+
+// PresignUploadPart returns a URL the client PUTs one part to directly, keeping
+// large bodies off the server entirely.
+func (p *S3StorageProvider) PresignUploadPart(ctx context.Context, upload *ports.MultipartUpload, partNumber int32, expiresIn time.Duration) (string, error) {
+	bucket, key, err := p.locateUpload(ctx, upload, partNumber)
+	if err != nil {
+		return "", err
+	}
+	result, err := s3.NewPresignClient(p.client).PresignUploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(bucket),
+		Key:        aws.String(key),
+		UploadId:   aws.String(upload.UploadID),
+		PartNumber: aws.Int32(partNumber),
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = expiresIn
+	})
+	if err != nil {
+		return "", ports.NewStorageError(ports.StorageErrorCodeProviderError, "presign failed", err)
+	}
+	return result.URL, nil
+}
+
+// CompleteMultipartUpload assembles the parts into the object.
+func (p *S3StorageProvider) CompleteMultipartUpload(ctx context.Context, upload *ports.MultipartUpload, parts []ports.CompletedPart) (*pb.UploadObjectResponse, error) {
+	startTime := time.Now()
+
+	bucket, key, err := p.locateUpload(ctx, upload, 1)
+	if err != nil {
+		return &pb.UploadObjectResponse{Success: false, Message: err.Error()}, err
+	}
+	if len(parts) == 0 || len(parts) > maxParts {
+		err := ports.NewStorageError(ports.StorageErrorCodeInvalidPath, fmt.Sprintf("a multipart upload has 1 to %d parts", maxParts), nil)
+		return &pb.UploadObjectResponse{Success: false, Message: err.Message}, err
+	}
+
+	// S3 wants the parts in ascending order.
+	sorted := append([]ports.CompletedPart(nil), parts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PartNumber < sorted[j].PartNumber })
+	completed := make([]types.CompletedPart, 0, len(sorted))
+	for _, part := range sorted {
+		completed = append(completed, types.CompletedPart{
+			PartNumber: aws.Int32(part.PartNumber),
+			ETag:       aws.String(part.ETag),
+		})
+	}
+
+	completeCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	result, err := p.client.CompleteMultipartUpload(completeCtx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(upload.UploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return &pb.UploadObjectResponse{
+			Success: false,
+			Message: fmt.Sprintf("failed to complete multipart upload: %v", err),
+		}, ports.NewStorageError(ports.StorageErrorCodeUploadFailed, "complete multipart upload failed", err)
+	}
+
+	now := time.Now()
+	storageObject := &pb.StorageObject{
+		Id:            storagecommon.GenerateObjectID(upload.ContainerName, upload.ObjectKey),
+		Provider:      pb.StorageProvider_STORAGE_PROVIDER_AWS,
+		ContainerName: upload.ContainerName,
+		ObjectKey:     upload.ObjectKey,
+		Etag:          aws.ToString(result.ETag),
+		LastModified:  timestamppb.New(now),
+		CreatedAt:     timestamppb.New(now),
+		VersionId:     aws.ToString(result.VersionId),
+	}
+	// The object's size and type are only known to S3 once assembled.
+	if head, err := p.client.HeadObject(completeCtx, &s3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}); err == nil {
+		storageObject.ContentType = aws.ToString(head.ContentType)
+		storageObject.StorageClass = string(head.StorageClass)
+		storageObject.Metadata = head.Metadata
+		if head.ContentLength != nil {
+			storageObject.Size = *head.ContentLength
+		}
+	}
+
+	return &pb.UploadObjectResponse{
+		Success:          true,
+		Object:           storageObject,
+		UploadDurationMs: time.Since(startTime).Milliseconds(),
+		Message:          "multipart upload completed",
+	}, nil
+}
+
+// AbortMultipartUpload discards the upload and the parts uploaded so far.
+func (p *S3StorageProvider) AbortMultipartUpload(ctx context.Context, upload *ports.MultipartUpload) error {
+	bucket, key, err := p.locateUpload(ctx, upload, 1)
+	if err != nil {
+		return err
+	}
+	abortCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if _, err := p.client.AbortMultipartUpload(abortCtx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(upload.UploadID),
+	}); err != nil {
+		return ports.NewStorageError(ports.StorageErrorCodeProviderError, "abort multipart upload failed", err)
+	}
+	return nil
+}
+
+// locateUpload validates an upload handle and part number and returns where the
+// upload's object is stored for the request's workspace.
+func (p *S3StorageProvider) locateUpload(ctx context.Context, upload *ports.MultipartUpload, partNumber int32) (string, string, error) {
+	if !p.enabled {
+		return "", "", ports.NewStorageError(ports.StorageErrorCodeProviderError, "not initialized", nil)
+	}
+	if upload == nil || upload.UploadID == "" || upload.ContainerName == "" || upload.ObjectKey == "" {
+		return "", "", ports.NewStorageError(ports.StorageErrorCodeInvalidPath, "incomplete multipart upload handle", nil)
+	}
+	if partNumber < 1 || partNumber > maxParts {
+		return "", "", ports.NewStorageError(ports.StorageErrorCodeInvalidPath, fmt.Sprintf("part number must be 1 to %d", maxParts), nil)
+	}
+	bucket, key, err := p.locate(ctx, upload.ContainerName, strings.Trim(upload.ObjectKey, "/"))
+	if err != nil {
+		return "", "", ports.NewStorageError(ports.StorageErrorCodeAccessDenied, "workspace scope", err)
+	}
+	return bucket, key, nil
+}
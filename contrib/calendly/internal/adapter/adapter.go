@@ -10,10 +10,13 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/erniealice/espyna-golang/ports"
 	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/tracing"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
 	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -44,13 +47,29 @@ type CalendlyAdapter struct {
 	userURI     string
 	orgURI      string
 	enabled     bool
+
+	// Webhook subscription (see webhook_subscription.go); webhookMu guards
+	// the signing key, which rotation replaces.
+	webhookMu     sync.RWMutex
+	webhookURL    string
+	webhookSecret string
+	webhookScope  string
+
+	// Inbound signature checks (see webhook_signature.go)
+	webhookLenient   bool
+	webhookTolerance time.Duration
+	now              func() time.Time // overridden in tests
 }
 
 // NewCalendlyAdapter creates a new Calendly adapter
 func NewCalendlyAdapter() *CalendlyAdapter {
 	return &CalendlyAdapter{
-		httpClient: &http.Client{Timeout: DefaultTimeout},
-		enabled:    false,
+		// Outbound calls carry the request's correlation ID and trace context
+		httpClient: &http.Client{
+			Timeout:   DefaultTimeout,
+			Transport: tracing.NewTransport(correlation.NewTransport(nil)),
+		},
+		enabled: false,
 	}
 }
 
@@ -72,6 +91,12 @@ func NewCalendlyAdapterFromEnv() *CalendlyAdapter {
 		UserUri:            os.Getenv("CALENDLY_USER_URI"),
 		OrganizationUri:    os.Getenv("CALENDLY_ORGANIZATION_URI"),
 		WebhookSecret:      os.Getenv("CALENDLY_WEBHOOK_SECRET"),
+		WebhookUrl:         os.Getenv("CALENDLY_WEBHOOK_URL"),
+		Config: map[string]string{
+			"webhook_scope":          os.Getenv("CALENDLY_WEBHOOK_SCOPE"),
+			"webhook_signature_mode": os.Getenv("CALENDLY_WEBHOOK_SIGNATURE_MODE"),
+			"webhook_tolerance":      os.Getenv("CALENDLY_WEBHOOK_TOLERANCE"),
+		},
 	}
 
 	if err := adapter.Initialize(config); err != nil {
@@ -101,14 +126,34 @@ func (a *CalendlyAdapter) Initialize(config *schedulerpb.SchedulerProviderConfig
 	a.accessToken = config.AccessToken
 	a.userURI = config.UserUri
 	a.orgURI = config.OrganizationUri
+	a.webhookURL = config.WebhookUrl
+	a.webhookSecret = config.WebhookSecret
+	a.webhookScope = config.Config["webhook_scope"]
+	switch a.webhookScope {
+	case "":
+		a.webhookScope = webhookScopeUser
+	case webhookScopeUser, webhookScopeOrganization:
+	default:
+		return fmt.Errorf("invalid webhook scope %q: must be %q or %q", a.webhookScope, webhookScopeUser, webhookScopeOrganization)
+	}
+	if err := a.configureWebhookSignature(config.Config); err != nil {
+		return err
+	}
 
-	// If user URI not provided, fetch it from the API
-	if a.userURI == "" {
-		userURI, err := a.fetchCurrentUserURI()
+	// If user or organization URI not provided, fetch them from the API.
+	// They are resolved once here: the webhook subscription calls need both
+	// and may run concurrently.
+	if a.userURI == "" || a.orgURI == "" {
+		userURI, orgURI, err := a.fetchCurrentUser()
 		if err != nil {
 			log.Printf("[CalendlyAdapter] Warning: failed to fetch user URI: %v", err)
 		} else {
-			a.userURI = userURI
+			if a.userURI == "" {
+				a.userURI = userURI
+			}
+			if a.orgURI == "" {
+				a.orgURI = orgURI
+			}
 		}
 	}
 
@@ -479,6 +524,71 @@ func (a *CalendlyAdapter) ListSchedules(ctx context.Context, req *schedulerpb.Li
 	}, nil
 }
 
+// ListScheduleInvitees lists every invitee of a scheduled event, following
+// pagination. It implements ports.ScheduleInviteeLister.
+func (a *CalendlyAdapter) ListScheduleInvitees(ctx context.Context, providerScheduleID string) ([]*schedulerpb.InviteeInfo, error) {
+	if !a.enabled {
+		return nil, fmt.Errorf("calendly adapter is disabled")
+	}
+	if providerScheduleID == "" {
+		return nil, fmt.Errorf("provider schedule ID is required")
+	}
+
+	var invitees []*schedulerpb.InviteeInfo
+	pageToken := ""
+	for {
+		url := fmt.Sprintf("%s/scheduled_events/%s/invitees?count=100", DefaultAPIBaseURL, providerScheduleID)
+		if pageToken != "" {
+			url += fmt.Sprintf("&page_token=%s", pageToken)
+		}
+
+		httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+a.accessToken)
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := a.httpClient.Do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list invitees: %w", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Calendly API returned status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var listResp CalendlyListInviteesResponse
+		if err := json.Unmarshal(body, &listResp); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		for _, inv := range listResp.Collection {
+			invitee := &schedulerpb.InviteeInfo{
+				Name:     inv.Name,
+				Email:    inv.Email,
+				Phone:    inv.TextReminderNumber,
+				Timezone: inv.Timezone,
+				Uri:      inv.URI,
+			}
+			if len(inv.QuestionsAndAnswers) > 0 {
+				invitee.CustomAnswers = make(map[string]string)
+				for _, qa := range inv.QuestionsAndAnswers {
+					invitee.CustomAnswers[qa.Question] = qa.Answer
+				}
+			}
+			invitees = append(invitees, invitee)
+		}
+
+		pageToken = listResp.Pagination.NextPageToken
+		if pageToken == "" {
+			return invitees, nil
+		}
+	}
+}
+
 // CheckAvailability checks available time slots
 func (a *CalendlyAdapter) CheckAvailability(ctx context.Context, req *schedulerpb.CheckAvailabilityRequest) (*schedulerpb.CheckAvailabilityResponse, error) {
 	if !a.enabled {
@@ -644,6 +754,18 @@ func (a *CalendlyAdapter) ProcessWebhook(ctx context.Context, req *schedulerpb.P
 		}, nil
 	}
 
+	// Only deliveries signed with the subscription's key are trusted
+	if err := a.checkWebhookSignature(req.Data); err != nil {
+		log.Printf("[CalendlyAdapter] Rejected webhook: %v", err)
+		return &schedulerpb.ProcessSchedulerWebhookResponse{
+			Success: false,
+			Error: &commonpb.Error{
+				Code:    "INVALID_SIGNATURE",
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
 	// Parse webhook payload
 	var webhook CalendlyWebhookPayload
 	if err := json.Unmarshal(req.Data.Payload, &webhook); err != nil {
@@ -679,6 +801,9 @@ func (a *CalendlyAdapter) ProcessWebhook(ctx context.Context, req *schedulerpb.P
 	case "invitee.canceled":
 		action = "cancelled"
 		status = schedulerpb.ScheduleStatus_SCHEDULE_STATUS_CANCELLED
+		// Rescheduling cancels the old booking; the invitee.created that
+		// follows carries the new one
+		isReschedule = webhook.Payload.Rescheduled
 	default:
 		action = "no_action"
 		status = schedulerpb.ScheduleStatus_SCHEDULE_STATUS_UNSPECIFIED
@@ -898,9 +1023,16 @@ func (a *CalendlyAdapter) GetEventType(ctx context.Context, req *schedulerpb.Get
 // Helper methods
 
 func (a *CalendlyAdapter) fetchCurrentUserURI() (string, error) {
+	userURI, _, err := a.fetchCurrentUser()
+	return userURI, err
+}
+
+// fetchCurrentUser returns the token owner's user URI and current
+// organization URI.
+func (a *CalendlyAdapter) fetchCurrentUser() (string, string, error) {
 	req, err := http.NewRequest("GET", DefaultAPIBaseURL+"/users/me", nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+a.accessToken)
@@ -908,29 +1040,30 @@ func (a *CalendlyAdapter) fetchCurrentUserURI() (string, error) {
 
 	resp, err := a.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch user: %w", err)
+		return "", "", fmt.Errorf("failed to fetch user: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return "", "", fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("Calendly API returned status %d: %s", resp.StatusCode, string(body))
+		return "", "", fmt.Errorf("Calendly API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
 	var result struct {
 		Resource struct {
-			URI string `json:"uri"`
+			URI                 string `json:"uri"`
+			CurrentOrganization string `json:"current_organization"`
 		} `json:"resource"`
 	}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return "", "", fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	return result.Resource.URI, nil
+	return result.Resource.URI, result.Resource.CurrentOrganization, nil
 }
 
 func (a *CalendlyAdapter) convertEventToSchedule(event *CalendlyEvent) *schedulerpb.Schedule {
@@ -964,9 +1097,8 @@ func (a *CalendlyAdapter) convertEventToSchedule(event *CalendlyEvent) *schedule
 		StartTime:          startTime.Format("15:04"),
 		EndDate:            endTime.Format("2006-01-02"),
 		EndTime:            endTime.Format("15:04"),
-		Timezone:           event.Timezone,
+		Timezone:           "UTC", // Calendly reports start/end times in UTC
 		DurationMinutes:    int32(endTime.Sub(startTime).Minutes()),
-		CancelUrl:          event.CancellationURL,
 		RescheduleUrl:      event.RescheduleURL,
 		CreatedAt:          timestamppb.New(createdAt),
 		UpdatedAt:          timestamppb.New(updatedAt),
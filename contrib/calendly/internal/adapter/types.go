@@ -18,6 +18,7 @@ type CalendlyInviteeData struct {
 	CancelURL           string                   `json:"cancel_url"`
 	RescheduleURL       string                   `json:"reschedule_url"`
 	OldInvitee          string                   `json:"old_invitee"`
+	Rescheduled         bool                     `json:"rescheduled"`
 	ScheduledEvent      *CalendlyScheduledEvent  `json:"scheduled_event"`
 	QuestionsAndAnswers []CalendlyQuestionAnswer `json:"questions_and_answers"`
 	Tracking            *CalendlyTracking        `json:"tracking"`
@@ -25,16 +26,16 @@ type CalendlyInviteeData struct {
 
 // CalendlyScheduledEvent contains scheduled event details
 type CalendlyScheduledEvent struct {
-	URI             string `json:"uri"`
-	Name            string `json:"name"`
-	Status          string `json:"status"`
-	StartTime       string `json:"start_time"`
-	EndTime         string `json:"end_time"`
-	EventType       string `json:"event_type"`
-	Location        string `json:"location"`
-	MeetingNotes    string `json:"meeting_notes"`
-	CancellationURL string `json:"cancellation_url"`
-	RescheduleURL   string `json:"reschedule_url"`
+	URI             string            `json:"uri"`
+	Name            string            `json:"name"`
+	Status          string            `json:"status"`
+	StartTime       string            `json:"start_time"`
+	EndTime         string            `json:"end_time"`
+	EventType       string            `json:"event_type"`
+	Location        *CalendlyLocation `json:"location"`
+	MeetingNotes    string            `json:"meeting_notes"`
+	CancellationURL string            `json:"cancellation_url"`
+	RescheduleURL   string            `json:"reschedule_url"`
 }
 
 // CalendlyQuestionAnswer represents a custom question and answer
@@ -55,18 +56,32 @@ type CalendlyTracking struct {
 
 // CalendlyEvent represents a scheduled event
 type CalendlyEvent struct {
-	URI             string            `json:"uri"`
-	Name            string            `json:"name"`
-	Status          string            `json:"status"`
-	StartTime       string            `json:"start_time"`
-	EndTime         string            `json:"end_time"`
-	EventType       string            `json:"event_type"`
-	Location        *CalendlyLocation `json:"location"`
-	CancellationURL string            `json:"cancellation"`
-	RescheduleURL   string            `json:"reschedule"`
-	Timezone        string            `json:"event_memberships"`
-	CreatedAt       string            `json:"created_at"`
-	UpdatedAt       string            `json:"updated_at"`
+	URI              string                    `json:"uri"`
+	Name             string                    `json:"name"`
+	Status           string                    `json:"status"`
+	StartTime        string                    `json:"start_time"`
+	EndTime          string                    `json:"end_time"`
+	EventType        string                    `json:"event_type"`
+	Location         *CalendlyLocation         `json:"location"`
+	Cancellation     *CalendlyCancellation     `json:"cancellation"`
+	RescheduleURL    string                    `json:"reschedule"`
+	EventMemberships []CalendlyEventMembership `json:"event_memberships"`
+	CreatedAt        string                    `json:"created_at"`
+	UpdatedAt        string                    `json:"updated_at"`
+}
+
+// CalendlyCancellation describes why a scheduled event was canceled
+type CalendlyCancellation struct {
+	CanceledBy   string `json:"canceled_by"`
+	Reason       string `json:"reason"`
+	CancelerType string `json:"canceler_type"`
+}
+
+// CalendlyEventMembership is a host of a scheduled event
+type CalendlyEventMembership struct {
+	User      string `json:"user"`
+	UserEmail string `json:"user_email"`
+	UserName  string `json:"user_name"`
 }
 
 // CalendlyLocation represents event location
@@ -96,6 +111,24 @@ type CalendlyPaginationInfo struct {
 	PreviousPage  string `json:"previous_page"`
 }
 
+// CalendlyInvitee represents an invitee of a scheduled event
+type CalendlyInvitee struct {
+	URI                 string                   `json:"uri"`
+	Name                string                   `json:"name"`
+	Email               string                   `json:"email"`
+	Status              string                   `json:"status"`
+	Timezone            string                   `json:"timezone"`
+	TextReminderNumber  string                   `json:"text_reminder_number"`
+	Rescheduled         bool                     `json:"rescheduled"`
+	QuestionsAndAnswers []CalendlyQuestionAnswer `json:"questions_and_answers"`
+}
+
+// CalendlyListInviteesResponse wraps a list of invitees
+type CalendlyListInviteesResponse struct {
+	Collection []CalendlyInvitee      `json:"collection"`
+	Pagination CalendlyPaginationInfo `json:"pagination"`
+}
+
 // CalendlyEventTypeInfo represents an event type
 type CalendlyEventTypeInfo struct {
 	URI           string `json:"uri"`
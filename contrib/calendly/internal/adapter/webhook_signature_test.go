@@ -0,0 +1,177 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
+)
+
+// Recorded deliveries: the payloads in testdata, signed with
+// testSigningKey at the times below.
+const (
+	testSigningKey = "test-signing-key"
+
+	createdSignature  = "t=1767225600,v1=62b4401d369ffeae3cc43f9359d91cf086d10acf1c99b20f023f4e7039906b00"
+	canceledSignature = "t=1767344400,v1=de29d181fd98bd9566f2f5676977c91e1ce119d16f765a544291bd794852ff2a"
+)
+
+var (
+	createdAt  = time.Unix(1767225600, 0)
+	canceledAt = time.Unix(1767344400, 0)
+)
+
+func readPayload(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+// newWebhookAdapter returns an enabled adapter that checks signatures
+// with key at now.
+func newWebhookAdapter(t *testing.T, key string, now time.Time, config map[string]string) *CalendlyAdapter {
+	t.Helper()
+	a := NewCalendlyAdapter()
+	a.enabled = true
+	a.webhookSecret = key
+	a.now = func() time.Time { return now }
+	if err := a.configureWebhookSignature(config); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func webhookRequest(body []byte, headers map[string]string) *schedulerpb.ProcessSchedulerWebhookRequest {
+	return &schedulerpb.ProcessSchedulerWebhookRequest{Data: &schedulerpb.SchedulerWebhookData{
+		ProviderId:  "calendly",
+		Payload:     body,
+		Headers:     headers,
+		ContentType: "application/json",
+	}}
+}
+
+func TestProcessWebhook_RecordedDeliveries(t *testing.T) {
+	cases := []struct {
+		file      string
+		signature string
+		at        time.Time
+		action    string
+	}{
+		{"invitee_created.json", createdSignature, createdAt, "created"},
+		{"invitee_canceled.json", canceledSignature, canceledAt, "cancelled"},
+	}
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			a := newWebhookAdapter(t, testSigningKey, c.at.Add(time.Minute), nil)
+			// Header names arrive in whatever case the transport forwards.
+			req := webhookRequest(readPayload(t, c.file), map[string]string{"calendly-webhook-signature": c.signature})
+
+			resp, err := a.ProcessWebhook(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !resp.Success {
+				t.Fatalf("error = %v", resp.Error)
+			}
+			result := resp.Data[0]
+			if result.Action != c.action || result.Schedule.ProviderScheduleId != "CCCCCCCCCCCCCCCC" {
+				t.Errorf("action = %q, schedule = %q", result.Action, result.Schedule.ProviderScheduleId)
+			}
+		})
+	}
+}
+
+func TestProcessWebhook_RejectsInStrictMode(t *testing.T) {
+	created := readPayload(t, "invitee_created.json")
+	tampered := append([]byte(nil), created...)
+	tampered[len(tampered)-3] = ' '
+
+	cases := []struct {
+		name string
+		key  string
+		now  time.Time
+		body []byte
+		sig  string
+	}{
+		{"missing signature", testSigningKey, createdAt, created, ""},
+		{"malformed signature", testSigningKey, createdAt, created, "v1=62b4401d"},
+		{"tampered body", testSigningKey, createdAt, tampered, createdSignature},
+		{"other key", "rotated-key", createdAt, created, createdSignature},
+		{"replayed later", testSigningKey, createdAt.Add(DefaultWebhookTolerance + time.Second), created, createdSignature},
+		{"timestamp in the future", testSigningKey, createdAt.Add(-DefaultWebhookTolerance - time.Second), created, createdSignature},
+		{"no signing key", "", createdAt, created, createdSignature},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := newWebhookAdapter(t, c.key, c.now, map[string]string{"webhook_signature_mode": "strict"})
+			req := webhookRequest(c.body, nil)
+			req.Data.Signature = c.sig
+
+			resp, err := a.ProcessWebhook(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Success || resp.Error.GetCode() != "INVALID_SIGNATURE" {
+				t.Errorf("success = %v, error = %v", resp.Success, resp.Error)
+			}
+		})
+	}
+}
+
+func TestProcessWebhook_LenientModeAcceptsUnverified(t *testing.T) {
+	a := newWebhookAdapter(t, testSigningKey, createdAt, map[string]string{"webhook_signature_mode": "lenient"})
+
+	resp, err := a.ProcessWebhook(context.Background(), webhookRequest(readPayload(t, "invitee_created.json"), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Success {
+		t.Errorf("lenient mode refused an unsigned delivery: %v", resp.Error)
+	}
+}
+
+func TestVerifyWebhookSignature_Tolerance(t *testing.T) {
+	body := readPayload(t, "invitee_created.json")
+	late := createdAt.Add(10 * time.Minute)
+
+	if err := verifyWebhookSignature(testSigningKey, createdSignature, body, late, 0); !errors.Is(err, errBadWebhookSignature) {
+		t.Errorf("default tolerance accepted a 10 minute old signature: %v", err)
+	}
+	if err := verifyWebhookSignature(testSigningKey, createdSignature, body, late, 15*time.Minute); err != nil {
+		t.Errorf("15 minute tolerance: %v", err)
+	}
+
+	// A header may carry several v1 signatures while keys roll over.
+	ts := strconv.FormatInt(createdAt.Unix(), 10)
+	header := "t=" + ts + ",v1=" + webhookSignature("old-key", ts, body) + ",v1=" + webhookSignature(testSigningKey, ts, body)
+	if err := verifyWebhookSignature(testSigningKey, header, body, createdAt, 0); err != nil {
+		t.Errorf("second v1 signature: %v", err)
+	}
+}
+
+func TestConfigureWebhookSignature(t *testing.T) {
+	a := NewCalendlyAdapter()
+	if err := a.configureWebhookSignature(map[string]string{"webhook_tolerance": "90s"}); err != nil {
+		t.Fatal(err)
+	}
+	if a.webhookLenient || a.webhookTolerance != 90*time.Second {
+		t.Errorf("lenient = %v, tolerance = %s", a.webhookLenient, a.webhookTolerance)
+	}
+
+	for _, bad := range []map[string]string{
+		{"webhook_signature_mode": "off"},
+		{"webhook_tolerance": "soon"},
+		{"webhook_tolerance": "-1m"},
+	} {
+		if err := a.configureWebhookSignature(bad); err == nil {
+			t.Errorf("config %v accepted", bad)
+		}
+	}
+}
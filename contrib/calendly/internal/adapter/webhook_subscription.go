@@ -0,0 +1,329 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+// =============================================================================
+// Webhook subscription management
+// =============================================================================
+
+// Compile-time check: the adapter manages its own webhook subscription.
+var _ ports.SchedulerWebhookManager = (*CalendlyAdapter)(nil)
+
+// Subscription scopes: a user subscription receives the token owner's
+// bookings, an organization subscription every member's (admin tokens only).
+const (
+	webhookScopeUser         = "user"
+	webhookScopeOrganization = "organization"
+)
+
+// webhookEvents are the events ProcessWebhook handles.
+var webhookEvents = []string{"invitee.created", "invitee.canceled"}
+
+// calendlyWebhookSubscription is a webhook_subscriptions resource.
+type calendlyWebhookSubscription struct {
+	URI         string   `json:"uri"`
+	CallbackURL string   `json:"callback_url"`
+	CreatedAt   string   `json:"created_at"`
+	State       string   `json:"state"`
+	Events      []string `json:"events"`
+	Scope       string   `json:"scope"`
+}
+
+// EnsureWebhookSubscription registers callbackURL for invitee events, or
+// verifies the subscription already registered for it. A disabled
+// subscription, one missing events, or one whose signing key the adapter
+// does not know is deleted and recreated: Calendly rejects a second
+// subscription for the same URL and cannot update one in place.
+func (a *CalendlyAdapter) EnsureWebhookSubscription(ctx context.Context, callbackURL string) (*ports.SchedulerWebhookSubscription, error) {
+	if !a.enabled {
+		return nil, fmt.Errorf("Calendly adapter is disabled")
+	}
+
+	a.webhookMu.Lock()
+	defer a.webhookMu.Unlock()
+
+	if callbackURL != "" {
+		a.webhookURL = callbackURL
+	}
+	if a.webhookURL == "" {
+		return nil, fmt.Errorf("no webhook callback URL configured (CALENDLY_WEBHOOK_URL)")
+	}
+
+	existing, err := a.findWebhookSubscription(ctx, a.webhookURL)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.State == "active" && hasEvents(existing.Events, webhookEvents) && a.webhookSecret != "" {
+		return convertWebhookSubscription(existing), nil
+	}
+
+	key := a.webhookSecret
+	generated := key == ""
+	if generated {
+		if key, err = newSigningKey(); err != nil {
+			return nil, err
+		}
+	}
+	if existing != nil {
+		log.Printf("[CalendlyAdapter] Recreating webhook subscription %s (state %s)", existing.URI, existing.State)
+		if err := a.deleteWebhookSubscription(ctx, existing.URI); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := a.createWebhookSubscription(ctx, a.webhookURL, key)
+	if err != nil {
+		return nil, err
+	}
+	a.webhookSecret = key
+
+	subscription := convertWebhookSubscription(created)
+	if generated {
+		log.Printf("[CalendlyAdapter] Generated a webhook signing key; store it as CALENDLY_WEBHOOK_SECRET")
+		subscription.SigningKey = key
+	}
+	return subscription, nil
+}
+
+// RotateWebhookSecret recreates the subscription with a new signing key and
+// keeps the new key as the adapter's webhook secret.
+func (a *CalendlyAdapter) RotateWebhookSecret(ctx context.Context) (*ports.SchedulerWebhookSubscription, error) {
+	if !a.enabled {
+		return nil, fmt.Errorf("Calendly adapter is disabled")
+	}
+
+	a.webhookMu.Lock()
+	defer a.webhookMu.Unlock()
+
+	if a.webhookURL == "" {
+		return nil, fmt.Errorf("no webhook callback URL configured (CALENDLY_WEBHOOK_URL)")
+	}
+
+	key, err := newSigningKey()
+	if err != nil {
+		return nil, err
+	}
+	existing, err := a.findWebhookSubscription(ctx, a.webhookURL)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if err := a.deleteWebhookSubscription(ctx, existing.URI); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := a.createWebhookSubscription(ctx, a.webhookURL, key)
+	if err != nil {
+		return nil, err
+	}
+	a.webhookSecret = key
+	log.Printf("[CalendlyAdapter] Rotated webhook signing key; store the new key as CALENDLY_WEBHOOK_SECRET")
+
+	subscription := convertWebhookSubscription(created)
+	subscription.SigningKey = key
+	return subscription, nil
+}
+
+// WebhookSubscriptionStatus returns the subscription registered for the
+// configured callback URL.
+func (a *CalendlyAdapter) WebhookSubscriptionStatus(ctx context.Context) (*ports.SchedulerWebhookSubscription, error) {
+	if !a.enabled {
+		return nil, fmt.Errorf("Calendly adapter is disabled")
+	}
+
+	a.webhookMu.RLock()
+	callbackURL := a.webhookURL
+	a.webhookMu.RUnlock()
+	if callbackURL == "" {
+		return nil, nil
+	}
+
+	existing, err := a.findWebhookSubscription(ctx, callbackURL)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return &ports.SchedulerWebhookSubscription{
+			CallbackURL: callbackURL,
+			State:       ports.SchedulerWebhookMissing,
+		}, nil
+	}
+	return convertWebhookSubscription(existing), nil
+}
+
+// findWebhookSubscription returns the subscription for callbackURL in the
+// adapter's scope, or nil when there is none.
+func (a *CalendlyAdapter) findWebhookSubscription(ctx context.Context, callbackURL string) (*calendlyWebhookSubscription, error) {
+	orgURI, err := a.organizationURI()
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("organization", orgURI)
+	query.Set("scope", a.webhookScope)
+	query.Set("count", "100")
+	if a.webhookScope == webhookScopeUser {
+		query.Set("user", a.userURI)
+	}
+	next := DefaultAPIBaseURL + "/webhook_subscriptions?" + query.Encode()
+
+	for next != "" {
+		var result struct {
+			Collection []calendlyWebhookSubscription `json:"collection"`
+			Pagination struct {
+				NextPage string `json:"next_page"`
+			} `json:"pagination"`
+		}
+		if err := a.webhookAPI(ctx, http.MethodGet, next, nil, http.StatusOK, &result); err != nil {
+			return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+		}
+		for i := range result.Collection {
+			if result.Collection[i].CallbackURL == callbackURL {
+				return &result.Collection[i], nil
+			}
+		}
+		next = result.Pagination.NextPage
+	}
+	return nil, nil
+}
+
+// createWebhookSubscription registers callbackURL for webhookEvents, signed
+// with key.
+func (a *CalendlyAdapter) createWebhookSubscription(ctx context.Context, callbackURL, key string) (*calendlyWebhookSubscription, error) {
+	orgURI, err := a.organizationURI()
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]any{
+		"url":          callbackURL,
+		"events":       webhookEvents,
+		"organization": orgURI,
+		"scope":        a.webhookScope,
+		"signing_key":  key,
+	}
+	if a.webhookScope == webhookScopeUser {
+		body["user"] = a.userURI
+	}
+
+	var result struct {
+		Resource calendlyWebhookSubscription `json:"resource"`
+	}
+	if err := a.webhookAPI(ctx, http.MethodPost, DefaultAPIBaseURL+"/webhook_subscriptions", body, http.StatusCreated, &result); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	log.Printf("[CalendlyAdapter] Registered webhook subscription %s for %s", result.Resource.URI, callbackURL)
+	return &result.Resource, nil
+}
+
+// deleteWebhookSubscription deletes the subscription at uri.
+func (a *CalendlyAdapter) deleteWebhookSubscription(ctx context.Context, uri string) error {
+	if err := a.webhookAPI(ctx, http.MethodDelete, uri, nil, http.StatusNoContent, nil); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// organizationURI returns the organization webhook subscriptions are listed
+// and created in. Initialize resolves it, with the user URI, when they were
+// not configured.
+func (a *CalendlyAdapter) organizationURI() (string, error) {
+	if a.orgURI == "" || (a.webhookScope == webhookScopeUser && a.userURI == "") {
+		return "", fmt.Errorf("Calendly user and organization URIs are unknown; set CALENDLY_USER_URI and CALENDLY_ORGANIZATION_URI")
+	}
+	return a.orgURI, nil
+}
+
+// webhookAPI sends a request to the Calendly API and decodes the response
+// into out (when non-nil), failing unless it has the expected status.
+func (a *CalendlyAdapter) webhookAPI(ctx context.Context, method, endpoint string, body any, expected int, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+a.accessToken)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != expected {
+		return fmt.Errorf("Calendly API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+func convertWebhookSubscription(s *calendlyWebhookSubscription) *ports.SchedulerWebhookSubscription {
+	createdAt, _ := time.Parse(time.RFC3339, s.CreatedAt)
+	state := ports.SchedulerWebhookActive
+	if s.State != "active" {
+		state = ports.SchedulerWebhookDisabled
+	}
+	return &ports.SchedulerWebhookSubscription{
+		ID:          extractEventUUID(s.URI),
+		CallbackURL: s.CallbackURL,
+		Events:      s.Events,
+		State:       state,
+		CreatedAt:   createdAt,
+	}
+}
+
+// hasEvents reports whether events includes every one of want.
+func hasEvents(events, want []string) bool {
+	have := make(map[string]bool, len(events))
+	for _, e := range events {
+		have[e] = true
+	}
+	for _, e := range want {
+		if !have[e] {
+			return false
+		}
+	}
+	return true
+}
+
+// newSigningKey returns a random signing key.
+func newSigningKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -4,6 +4,7 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -19,9 +20,11 @@ import (
 	"github.com/erniealice/espyna-golang/composition/core"
 	"github.com/erniealice/espyna-golang/composition/routing"
 	"github.com/erniealice/espyna-golang/composition/routing/customization"
+	"github.com/erniealice/espyna-golang/composition/routing/openapi"
 	fibermw "github.com/erniealice/espyna-golang/contrib/fiber/internal/adapter/middleware"
 	"github.com/erniealice/espyna-golang/ports"
 	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/metrics"
 )
 
 // =============================================================================
@@ -53,6 +56,8 @@ type FiberAdapter struct {
 	app       *fiber.App
 	container *core.Container
 	enabled   bool
+	limiter   *contracts.RateLimiter
+	idem      *contracts.Idempotency
 }
 
 // NewFiberAdapter creates a new Fiber server adapter.
@@ -95,16 +100,44 @@ func (a *FiberAdapter) Initialize(container any) error {
 		},
 	})
 
+	// Add request logging with correlation IDs
+	app.Use(fibermw.RequestLogger())
+
+	// Rate limits from the routing config (CONFIG_RATE_LIMIT_*)
+	limiter, err := contracts.NewRateLimiter(c.GetRouteManager().GetConfig().RateLimit)
+	if err != nil {
+		log.Printf("WARNING: rate limiting disabled: %v", err)
+	}
+	a.limiter = limiter
+	app.Use(fibermw.RateLimit(limiter))
+
 	// Add CORS middleware
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 		AllowMethods: "GET, POST, PUT, DELETE, OPTIONS",
-		AllowHeaders: "Content-Type, Authorization",
+		AllowHeaders: "Content-Type, Authorization, Idempotency-Key",
 	}))
 
 	// Add compression middleware
 	app.Use(compress.New())
 
+	// Route-level RBAC (CONFIG_AUTHORIZATION_*), checked against the
+	// container's authorizer
+	authz, err := contracts.NewRouteAuthorizer(c.GetRouteManager().GetConfig().Authorization,
+		customization.NewRouteCustomizer().ApplyCustomizations(c.GetRouteManager().GetAllRoutes()), c.GetAuthorizer())
+	if err != nil {
+		return fmt.Errorf("route authorization: %w", err)
+	}
+	app.Use(fibermw.RouteAuthorization(authz))
+
+	// Idempotency keys for POST requests (CONFIG_IDEMPOTENCY_*)
+	idem, err := contracts.NewIdempotency(c.GetRouteManager().GetConfig().Idempotency, c.GetDatabaseOperations())
+	if err != nil {
+		log.Printf("WARNING: idempotency keys disabled: %v", err)
+	}
+	a.idem = idem
+	app.Use(fibermw.Idempotency(idem))
+
 	// Populate AuditContext (ActorID, ActorType, IP, UserAgent, RequestID) on every
 	// request. Runs before business routes. Auth middleware doesn't exist for fiber
 	// yet — gracefully falls through with "system" defaults when no auth context.
@@ -125,6 +158,13 @@ func (a *FiberAdapter) Initialize(container any) error {
 		})
 	})
 
+	// Prometheus metrics
+	app.Get(metrics.Path, func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, metrics.ContentType)
+		metrics.Default.Write(c.UserContext(), c)
+		return nil
+	})
+
 	// Add root endpoint — response shape mirrors vanilla routes.go setupBasicRoutes.
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -149,6 +189,20 @@ func (a *FiberAdapter) installRoutes() {
 	for _, route := range routes {
 		a.installRouteOnFiber(route)
 	}
+	a.installOpenAPI(routes)
+}
+
+// installOpenAPI serves the OpenAPI document describing the installed routes.
+func (a *FiberAdapter) installOpenAPI(routes []*routing.Route) {
+	doc, err := openapi.Marshal(routes, openapi.DefaultInfo)
+	if err != nil {
+		log.Printf("WARNING: OpenAPI document not served: %v", err)
+		return
+	}
+	a.app.Get(openapi.Path, func(c *fiber.Ctx) error {
+		c.Set("Content-Type", "application/json")
+		return c.Send(doc)
+	})
 }
 
 // installRouteOnFiber installs a single route on the Fiber app
@@ -174,7 +228,7 @@ func (a *FiberAdapter) installRouteOnFiber(route *routing.Route) {
 // createFiberHandler creates a Fiber handler from an espyna route
 func (a *FiberAdapter) createFiberHandler(route *routing.Route) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		ctx, cancel := context.WithTimeout(c.Context(), 30*time.Second)
+		ctx, cancel := contracts.WithRouteDeadline(c.UserContext(), route)
 		defer cancel()
 
 		ctx = context.WithValue(ctx, "user_id", "consumer-app-user")
@@ -206,7 +260,14 @@ func (a *FiberAdapter) createFiberHandler(route *routing.Route) fiber.Handler {
 			}
 		}
 
-		resp, err := route.Handler.Execute(ctx, req)
+		resp, err := contracts.ExecuteRoute(ctx, route, req)
+		if errors.Is(err, contracts.ErrRouteTimeout) {
+			return c.Status(504).JSON(fiber.Map{
+				"error":      "Request timed out",
+				"details":    err.Error(),
+				"route_name": route.Metadata.Name,
+			})
+		}
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{
 				"error":      "Handler execution failed",
@@ -242,6 +303,12 @@ func (a *FiberAdapter) IsHealthy(ctx context.Context) error {
 
 // Close shuts down the Fiber server.
 func (a *FiberAdapter) Close() error {
+	if err := a.limiter.Close(); err != nil {
+		log.Printf("WARNING: failed to close rate limiter: %v", err)
+	}
+	if err := a.idem.Close(); err != nil {
+		log.Printf("WARNING: failed to close idempotency store: %v", err)
+	}
 	if a.app != nil {
 		log.Printf("Fiber adapter closing")
 		return a.app.Shutdown()
@@ -269,7 +336,7 @@ func (a *FiberAdapter) RegisterCustomHandler(method, path string, handler http.H
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": "failed to create request"})
 		}
-		httpReq = httpReq.WithContext(c.Context())
+		httpReq = httpReq.WithContext(c.UserContext())
 		handler(w, httpReq)
 		return nil
 	}
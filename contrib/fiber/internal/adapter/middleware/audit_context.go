@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	infraports "github.com/erniealice/espyna-golang/ports"
+	"github.com/erniealice/espyna-golang/shared/correlation"
 	"github.com/erniealice/espyna-golang/shared/identity"
 )
 
@@ -29,8 +30,12 @@ func AuditContext() fiber.Handler {
 			actorType = "system"
 		}
 
-		// Request ID: use incoming header or generate one.
-		requestID := c.Get("X-Request-ID")
+		// Request ID: reuse the correlation ID assigned by RequestLogger,
+		// else the incoming header, else generate one.
+		requestID := correlation.FromContext(c.UserContext())
+		if requestID == "" {
+			requestID = c.Get("X-Request-ID")
+		}
 		if requestID == "" {
 			requestID = uuid.New().String()
 		}
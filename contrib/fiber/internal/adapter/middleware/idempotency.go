@@ -0,0 +1,69 @@
+//go:build fiber
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/erniealice/espyna-golang/composition/contracts"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// Idempotency makes POST requests carrying an Idempotency-Key header safe
+// to retry, replaying the first response for a key (per workspace, user
+// and route) with Idempotent-Replayed: true. Mirrors vanilla
+// contrib/http/internal/adapter/middleware/idempotency.go. A nil tracker
+// passes everything through.
+func Idempotency(tracker *contracts.Idempotency) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(contracts.IdempotencyKeyHeader)
+		if !tracker.Tracks(c.Method(), key) {
+			return c.Next()
+		}
+		// The authenticated identity scopes the key; the header counts only
+		// when the identity has no workspace.
+		var workspaceID, userID string
+		if id, ok := identity.FromContext(c.UserContext()); ok {
+			workspaceID, userID = id.WorkspaceID, id.UserID
+		}
+		if workspaceID == "" {
+			workspaceID = c.Get(contracts.WorkspaceHeader)
+		}
+		claim, resp := tracker.Begin(c.UserContext(), contracts.IdempotencyRequest{
+			Method:      c.Method(),
+			Path:        c.Path(),
+			Query:       string(c.Request().URI().QueryString()),
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+			Key:         key,
+			Body:        c.Body(),
+		})
+		if resp != nil {
+			if resp.Replayed {
+				c.Set(contracts.IdempotentReplayedHeader, "true")
+			}
+			if resp.ContentType != "" {
+				c.Set(fiber.HeaderContentType, resp.ContentType)
+			}
+			return c.Status(resp.Status).Send(resp.Body)
+		}
+		if claim == nil {
+			return c.Next()
+		}
+
+		if err := c.Next(); err != nil {
+			// The error handler writes the response after the chain
+			// returns, too late to store it; release the key instead.
+			claim.Finish(c.UserContext(), contracts.IdempotentResponse{Status: http.StatusInternalServerError})
+			return err
+		}
+		claim.Finish(c.UserContext(), contracts.IdempotentResponse{
+			Status:      c.Response().StatusCode(),
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		})
+		return nil
+	}
+}
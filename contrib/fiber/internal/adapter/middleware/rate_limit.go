@@ -0,0 +1,45 @@
+//go:build fiber
+
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/erniealice/espyna-golang/composition/contracts"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// RateLimit refuses requests over the routing config's rate limits with 429
+// Too Many Requests and a Retry-After header, identifying clients by IP and
+// by the X-Workspace-ID header. Mirrors vanilla
+// contrib/http/internal/adapter/middleware/rate_limit.go. A nil limiter
+// passes everything through.
+func RateLimit(limiter *contracts.RateLimiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if limiter == nil {
+			return c.Next()
+		}
+		workspaceID := c.Get(contracts.WorkspaceHeader)
+		if workspaceID == "" {
+			if id, ok := identity.FromContext(c.UserContext()); ok {
+				workspaceID = id.WorkspaceID
+			}
+		}
+		decision := limiter.Allow(c.UserContext(), contracts.RateLimitRequest{
+			Path:        c.Path(),
+			ClientIP:    extractFiberIP(c),
+			WorkspaceID: workspaceID,
+		})
+		for k, v := range decision.Headers() {
+			c.Set(k, v)
+		}
+		if !decision.Allowed {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"success": false,
+				"error":   "Too many requests",
+				"details": decision.Scope + " rate limit exceeded",
+			})
+		}
+		return c.Next()
+	}
+}
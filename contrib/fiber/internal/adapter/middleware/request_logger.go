@@ -0,0 +1,75 @@
+//go:build fiber
+
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/erniealice/espyna-golang/shared/apilog"
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/identity"
+	"github.com/erniealice/espyna-golang/shared/metrics"
+	"github.com/erniealice/espyna-golang/shared/tracing"
+)
+
+// RequestLogger assigns every request a correlation ID, stores it on the
+// user context for use cases and downstream adapters, echoes it in the
+// X-Request-ID response header, and writes one JSON log line with method,
+// path, status and latency once the request completes, recording the
+// request in the route metrics, as a server span under its route pattern
+// and in the workspace's API event log. Mirrors vanilla
+// contrib/http/internal/adapter/middleware/request_logger.go.
+//
+// Errors returned down the chain are answered through the app's error
+// handler here, so the logged status is the one the client receives.
+func RequestLogger() fiber.Handler {
+	correlation.InstallDefault()
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		id := correlation.Accept(c.Get(correlation.Header))
+		c.Set(correlation.Header, id)
+		ctx := correlation.WithID(c.UserContext(), id)
+		ctx, span := tracing.StartServer(ctx, c.Method(), traceHeaders(c),
+			tracing.String("http.request.method", c.Method()),
+			tracing.String("url.path", c.Path()),
+			tracing.String(correlation.LogKey, id),
+		)
+		ctx, who := identity.Observe(ctx)
+		c.SetUserContext(ctx)
+
+		if err := c.Next(); err != nil {
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		latency := time.Since(start)
+		correlation.LogRequest(ctx, c.Method(), c.Path(), c.Response().StatusCode(), latency)
+		apilog.Record(ctx, who, c.Method(), c.Path(), c.Response().StatusCode(), latency)
+		metrics.ObserveRequest(c.Method(), routePattern(c), c.Response().StatusCode(), latency)
+		tracing.EndServer(span, c.Method(), routePattern(c), c.Response().StatusCode())
+		return nil
+	}
+}
+
+// traceHeaders copies the trace context headers of the request.
+func traceHeaders(c *fiber.Ctx) tracing.HeaderCarrier {
+	h := http.Header{}
+	for _, key := range tracing.PropagationFields {
+		if v := c.Get(key); v != "" {
+			h.Set(key, v)
+		}
+	}
+	return tracing.HeaderCarrier(h)
+}
+
+// routePattern returns the pattern of the route that handled the request,
+// e.g. "/api/client/:id" rather than the requested path.
+func routePattern(c *fiber.Ctx) string {
+	if route := c.Route(); route != nil {
+		return route.Path
+	}
+	return ""
+}
@@ -0,0 +1,40 @@
+//go:build fiber
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/erniealice/espyna-golang/composition/contracts"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// RouteAuthorization refuses requests whose caller lacks the permission
+// their route maps to. Mirrors vanilla
+// contrib/http/internal/adapter/middleware/route_authorization.go. A nil
+// authorizer passes everything through.
+func RouteAuthorization(authorizer *contracts.RouteAuthorizer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if authorizer == nil {
+			return c.Next()
+		}
+		req := contracts.AuthorizationRequest{Method: c.Method(), Path: c.Path()}
+		if id, ok := identity.FromContext(c.UserContext()); ok {
+			req.UserID, req.WorkspaceID = id.UserID, id.WorkspaceID
+		}
+		if req.WorkspaceID == "" {
+			req.WorkspaceID = c.Get(contracts.WorkspaceHeader)
+		}
+		decision := authorizer.Authorize(c.UserContext(), req)
+		if !decision.Allowed {
+			return c.Status(decision.Status).JSON(fiber.Map{
+				"success": false,
+				"error":   http.StatusText(decision.Status),
+				"details": decision.Reason,
+			})
+		}
+		return c.Next()
+	}
+}
@@ -4,6 +4,7 @@ package adapterv3
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -19,8 +20,14 @@ import (
 	"github.com/erniealice/espyna-golang/composition/core"
 	"github.com/erniealice/espyna-golang/composition/routing"
 	"github.com/erniealice/espyna-golang/composition/routing/customization"
+	"github.com/erniealice/espyna-golang/composition/routing/openapi"
 	"github.com/erniealice/espyna-golang/ports"
 	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/apilog"
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/identity"
+	"github.com/erniealice/espyna-golang/shared/metrics"
+	"github.com/erniealice/espyna-golang/shared/tracing"
 )
 
 // =============================================================================
@@ -52,6 +59,8 @@ type FiberV3Adapter struct {
 	app       *fiber.App
 	container *core.Container
 	enabled   bool
+	limiter   *contracts.RateLimiter
+	idem      *contracts.Idempotency
 }
 
 // NewFiberV3Adapter creates a new Fiber v3 server adapter.
@@ -94,16 +103,44 @@ func (a *FiberV3Adapter) Initialize(container any) error {
 		},
 	})
 
+	// Add request logging with correlation IDs
+	app.Use(requestLogger())
+
+	// Rate limits from the routing config (CONFIG_RATE_LIMIT_*)
+	limiter, err := contracts.NewRateLimiter(c.GetRouteManager().GetConfig().RateLimit)
+	if err != nil {
+		log.Printf("WARNING: rate limiting disabled: %v", err)
+	}
+	a.limiter = limiter
+	app.Use(rateLimit(limiter))
+
 	// Add CORS middleware
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: []string{"*"},
 		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders: []string{"Content-Type", "Authorization"},
+		AllowHeaders: []string{"Content-Type", "Authorization", "Idempotency-Key"},
 	}))
 
 	// Add compression middleware
 	app.Use(compress.New())
 
+	// Route-level RBAC (CONFIG_AUTHORIZATION_*), checked against the
+	// container's authorizer
+	authz, err := contracts.NewRouteAuthorizer(c.GetRouteManager().GetConfig().Authorization,
+		customization.NewRouteCustomizer().ApplyCustomizations(c.GetRouteManager().GetAllRoutes()), c.GetAuthorizer())
+	if err != nil {
+		return fmt.Errorf("route authorization: %w", err)
+	}
+	app.Use(routeAuthorization(authz))
+
+	// Idempotency keys for POST requests (CONFIG_IDEMPOTENCY_*)
+	idem, err := contracts.NewIdempotency(c.GetRouteManager().GetConfig().Idempotency, c.GetDatabaseOperations())
+	if err != nil {
+		log.Printf("WARNING: idempotency keys disabled: %v", err)
+	}
+	a.idem = idem
+	app.Use(idempotency(idem))
+
 	a.app = app
 	a.enabled = true
 
@@ -119,6 +156,13 @@ func (a *FiberV3Adapter) Initialize(container any) error {
 		})
 	})
 
+	// Prometheus metrics
+	app.Get(metrics.Path, func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, metrics.ContentType)
+		metrics.Default.Write(c.Context(), c)
+		return nil
+	})
+
 	// Add root endpoint — response shape mirrors vanilla routes.go setupBasicRoutes.
 	app.Get("/", func(c fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -143,6 +187,20 @@ func (a *FiberV3Adapter) installRoutes() {
 	for _, route := range routes {
 		a.installRouteOnFiber(route)
 	}
+	a.installOpenAPI(routes)
+}
+
+// installOpenAPI serves the OpenAPI document describing the installed routes.
+func (a *FiberV3Adapter) installOpenAPI(routes []*routing.Route) {
+	doc, err := openapi.Marshal(routes, openapi.DefaultInfo)
+	if err != nil {
+		log.Printf("WARNING: OpenAPI document not served: %v", err)
+		return
+	}
+	a.app.Get(openapi.Path, func(c fiber.Ctx) error {
+		c.Set("Content-Type", "application/json")
+		return c.Send(doc)
+	})
 }
 
 // installRouteOnFiber installs a single route on the Fiber app
@@ -168,7 +226,7 @@ func (a *FiberV3Adapter) installRouteOnFiber(route *routing.Route) {
 // createFiberHandler creates a Fiber v3 handler from an espyna route
 func (a *FiberV3Adapter) createFiberHandler(route *routing.Route) fiber.Handler {
 	return func(c fiber.Ctx) error {
-		ctx, cancel := context.WithTimeout(c.Context(), 30*time.Second)
+		ctx, cancel := contracts.WithRouteDeadline(c.Context(), route)
 		defer cancel()
 
 		ctx = context.WithValue(ctx, "user_id", "consumer-app-user")
@@ -200,7 +258,14 @@ func (a *FiberV3Adapter) createFiberHandler(route *routing.Route) fiber.Handler
 			}
 		}
 
-		resp, err := route.Handler.Execute(ctx, req)
+		resp, err := contracts.ExecuteRoute(ctx, route, req)
+		if errors.Is(err, contracts.ErrRouteTimeout) {
+			return c.Status(504).JSON(fiber.Map{
+				"error":      "Request timed out",
+				"details":    err.Error(),
+				"route_name": route.Metadata.Name,
+			})
+		}
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{
 				"error":      "Handler execution failed",
@@ -236,6 +301,12 @@ func (a *FiberV3Adapter) IsHealthy(ctx context.Context) error {
 
 // Close shuts down the Fiber v3 server.
 func (a *FiberV3Adapter) Close() error {
+	if err := a.limiter.Close(); err != nil {
+		log.Printf("WARNING: failed to close rate limiter: %v", err)
+	}
+	if err := a.idem.Close(); err != nil {
+		log.Printf("WARNING: failed to close idempotency store: %v", err)
+	}
 	if a.app != nil {
 		log.Printf("Fiber v3 adapter closing")
 		return a.app.Shutdown()
@@ -313,6 +384,164 @@ func (w *fiberV3ResponseWriter) WriteHeader(statusCode int) {
 	w.Ctx.Status(statusCode)
 }
 
+// requestLogger assigns every request a correlation ID, stores it on the
+// request context for use cases and downstream adapters, echoes it in the
+// X-Request-ID response header, and writes one JSON log line with method,
+// path, status and latency, recording the request in the route metrics, as
+// a server span and in the workspace's API event log. Mirrors the fiber v2
+// RequestLogger middleware.
+func requestLogger() fiber.Handler {
+	correlation.InstallDefault()
+	return func(c fiber.Ctx) error {
+		start := time.Now()
+		id := correlation.Accept(c.Get(correlation.Header))
+		c.Set(correlation.Header, id)
+		ctx := correlation.WithID(c.Context(), id)
+		headers := http.Header{}
+		for _, key := range tracing.PropagationFields {
+			if v := c.Get(key); v != "" {
+				headers.Set(key, v)
+			}
+		}
+		ctx, span := tracing.StartServer(ctx, c.Method(), tracing.HeaderCarrier(headers),
+			tracing.String("http.request.method", c.Method()),
+			tracing.String("url.path", c.Path()),
+			tracing.String(correlation.LogKey, id),
+		)
+		ctx, who := identity.Observe(ctx)
+		c.SetContext(ctx)
+
+		if err := c.Next(); err != nil {
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		latency := time.Since(start)
+		correlation.LogRequest(ctx, c.Method(), c.Path(), c.Response().StatusCode(), latency)
+		apilog.Record(ctx, who, c.Method(), c.Path(), c.Response().StatusCode(), latency)
+		route := ""
+		if r := c.Route(); r != nil {
+			route = r.Path
+		}
+		metrics.ObserveRequest(c.Method(), route, c.Response().StatusCode(), latency)
+		tracing.EndServer(span, c.Method(), route, c.Response().StatusCode())
+		return nil
+	}
+}
+
+// rateLimit refuses requests over the routing config's rate limits with
+// 429 Too Many Requests, identifying clients by IP and by the
+// X-Workspace-ID header. Mirrors the v2 adapter's middleware.RateLimit.
+func rateLimit(limiter *contracts.RateLimiter) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if limiter == nil {
+			return c.Next()
+		}
+		// The authenticated identity scopes the key; the header counts only
+		// when the identity has no workspace.
+		var workspaceID, userID string
+		if id, ok := identity.FromContext(c.Context()); ok {
+			workspaceID, userID = id.WorkspaceID, id.UserID
+		}
+		if workspaceID == "" {
+			workspaceID = c.Get(contracts.WorkspaceHeader)
+		}
+		decision := limiter.Allow(c.Context(), contracts.RateLimitRequest{
+			Path:        c.Path(),
+			ClientIP:    c.IP(),
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+		})
+		for k, v := range decision.Headers() {
+			c.Set(k, v)
+		}
+		if !decision.Allowed {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"success": false,
+				"error":   "Too many requests",
+				"details": decision.Scope + " rate limit exceeded",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// routeAuthorization refuses requests whose caller lacks the permission
+// their route maps to. Mirrors the v2 adapter's
+// middleware.RouteAuthorization.
+func routeAuthorization(authorizer *contracts.RouteAuthorizer) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if authorizer == nil {
+			return c.Next()
+		}
+		req := contracts.AuthorizationRequest{Method: c.Method(), Path: c.Path()}
+		if id, ok := identity.FromContext(c.Context()); ok {
+			req.UserID, req.WorkspaceID = id.UserID, id.WorkspaceID
+		}
+		if req.WorkspaceID == "" {
+			req.WorkspaceID = c.Get(contracts.WorkspaceHeader)
+		}
+		decision := authorizer.Authorize(c.Context(), req)
+		if !decision.Allowed {
+			return c.Status(decision.Status).JSON(fiber.Map{
+				"success": false,
+				"error":   http.StatusText(decision.Status),
+				"details": decision.Reason,
+			})
+		}
+		return c.Next()
+	}
+}
+
+// idempotency replays the first response to POST requests retried with
+// the same Idempotency-Key header. Mirrors the v2 adapter's
+// middleware.Idempotency.
+func idempotency(tracker *contracts.Idempotency) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		key := c.Get(contracts.IdempotencyKeyHeader)
+		if !tracker.Tracks(c.Method(), key) {
+			return c.Next()
+		}
+		workspaceID := c.Get(contracts.WorkspaceHeader)
+		if workspaceID == "" {
+			if id, ok := identity.FromContext(c.Context()); ok {
+				workspaceID = id.WorkspaceID
+			}
+		}
+		claim, resp := tracker.Begin(c.Context(), contracts.IdempotencyRequest{
+			Method:      c.Method(),
+			Path:        c.Path(),
+			Query:       string(c.Request().URI().QueryString()),
+			WorkspaceID: workspaceID,
+			Key:         key,
+			Body:        c.Body(),
+		})
+		if resp != nil {
+			if resp.Replayed {
+				c.Set(contracts.IdempotentReplayedHeader, "true")
+			}
+			if resp.ContentType != "" {
+				c.Set(fiber.HeaderContentType, resp.ContentType)
+			}
+			return c.Status(resp.Status).Send(resp.Body)
+		}
+		if claim == nil {
+			return c.Next()
+		}
+		if err := c.Next(); err != nil {
+			// Written by the error handler later; release the key.
+			claim.Finish(c.Context(), contracts.IdempotentResponse{Status: fiber.StatusInternalServerError})
+			return err
+		}
+		claim.Finish(c.Context(), contracts.IdempotentResponse{
+			Status:      c.Response().StatusCode(),
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		})
+		return nil
+	}
+}
+
 // printServerInfo prints server startup information
 func printServerInfo(framework, addr string) {
 	fmt.Printf("\n")
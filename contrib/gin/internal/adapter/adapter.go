@@ -4,6 +4,7 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -17,9 +18,11 @@ import (
 	"github.com/erniealice/espyna-golang/composition/core"
 	"github.com/erniealice/espyna-golang/composition/routing"
 	"github.com/erniealice/espyna-golang/composition/routing/customization"
+	"github.com/erniealice/espyna-golang/composition/routing/openapi"
 	ginmiddleware "github.com/erniealice/espyna-golang/contrib/gin/internal/adapter/middleware"
 	"github.com/erniealice/espyna-golang/ports"
 	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/metrics"
 )
 
 // =============================================================================
@@ -54,6 +57,8 @@ type GinAdapter struct {
 	router    *gin.Engine
 	container *core.Container
 	enabled   bool
+	limiter   *contracts.RateLimiter
+	idem      *contracts.Idempotency
 }
 
 // NewGinAdapter creates a new Gin server adapter.
@@ -89,14 +94,26 @@ func (a *GinAdapter) Initialize(container any) error {
 
 	router := gin.New()
 
+	// Add request logging with correlation IDs. Runs before recovery so
+	// requests that panic are still logged with their 500.
+	router.Use(ginmiddleware.RequestLogger())
+
 	// Add recovery middleware
 	router.Use(gin.Recovery())
 
+	// Rate limits from the routing config (CONFIG_RATE_LIMIT_*)
+	limiter, err := contracts.NewRateLimiter(c.GetRouteManager().GetConfig().RateLimit)
+	if err != nil {
+		log.Printf("WARNING: rate limiting disabled: %v", err)
+	}
+	a.limiter = limiter
+	router.Use(ginmiddleware.RateLimit(limiter))
+
 	// Add CORS middleware
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -104,20 +121,22 @@ func (a *GinAdapter) Initialize(container any) error {
 		c.Next()
 	})
 
-	// Add request logging
-	router.Use(gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
-			param.ClientIP,
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
-		)
-	}))
+	// Route-level RBAC (CONFIG_AUTHORIZATION_*), checked against the
+	// container's authorizer
+	authz, err := contracts.NewRouteAuthorizer(c.GetRouteManager().GetConfig().Authorization,
+		customization.NewRouteCustomizer().ApplyCustomizations(c.GetRouteManager().GetAllRoutes()), c.GetAuthorizer())
+	if err != nil {
+		return fmt.Errorf("route authorization: %w", err)
+	}
+	router.Use(ginmiddleware.RouteAuthorization(authz))
+
+	// Idempotency keys for POST requests (CONFIG_IDEMPOTENCY_*)
+	idem, err := contracts.NewIdempotency(c.GetRouteManager().GetConfig().Idempotency, c.GetDatabaseOperations())
+	if err != nil {
+		log.Printf("WARNING: idempotency keys disabled: %v", err)
+	}
+	a.idem = idem
+	router.Use(ginmiddleware.Idempotency(idem))
 
 	// Populate AuditContext (ActorID, ActorType, IP, UserAgent, RequestID) after
 	// authentication middleware so that uid is already present in the Gin context.
@@ -140,6 +159,9 @@ func (a *GinAdapter) Initialize(container any) error {
 		})
 	})
 
+	// Prometheus metrics
+	router.GET(metrics.Path, gin.WrapH(metrics.Handler()))
+
 	// Root endpoint — mirrors vanilla setupBasicRoutes "/" handler, with the
 	// framework-specific API name.
 	router.GET("/", func(c *gin.Context) {
@@ -165,6 +187,19 @@ func (a *GinAdapter) installRoutes() {
 	for _, route := range routes {
 		a.installRouteOnGin(route)
 	}
+	a.installOpenAPI(routes)
+}
+
+// installOpenAPI serves the OpenAPI document describing the installed routes.
+func (a *GinAdapter) installOpenAPI(routes []*routing.Route) {
+	doc, err := openapi.Marshal(routes, openapi.DefaultInfo)
+	if err != nil {
+		log.Printf("WARNING: OpenAPI document not served: %v", err)
+		return
+	}
+	a.router.GET(openapi.Path, func(c *gin.Context) {
+		c.Data(200, "application/json", doc)
+	})
 }
 
 // installRouteOnGin installs a single route on the Gin router
@@ -190,8 +225,8 @@ func (a *GinAdapter) installRouteOnGin(route *routing.Route) {
 // createGinHandler creates a Gin handler from an espyna route
 func (a *GinAdapter) createGinHandler(route *routing.Route) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Set timeout context
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		// Bound the request by the route's time budget
+		ctx, cancel := contracts.WithRouteDeadline(c.Request.Context(), route)
 		defer cancel()
 
 		// Add user context for mock auth
@@ -236,8 +271,28 @@ func (a *GinAdapter) createGinHandler(route *routing.Route) gin.HandlerFunc {
 			}
 		}
 
+		// ?stream=true writes every page of a list route as it is fetched
+		if c.Query(contracts.StreamQueryParam) == "true" {
+			streamRecords(ctx, c, route, req)
+			return
+		}
+
+		// Export routes write every record as a CSV or XLSX download
+		if exporter, ok := route.Handler.(contracts.RecordExporter); ok {
+			exportRecords(ctx, c, route, exporter, req)
+			return
+		}
+
 		// Execute handler
-		resp, err := route.Handler.Execute(ctx, req)
+		resp, err := contracts.ExecuteRoute(ctx, route, req)
+		if errors.Is(err, contracts.ErrRouteTimeout) {
+			c.JSON(504, gin.H{
+				"error":      "Request timed out",
+				"details":    err.Error(),
+				"route_name": route.Metadata.Name,
+			})
+			return
+		}
 		if err != nil {
 			c.JSON(500, gin.H{
 				"error":      "Handler execution failed",
@@ -256,6 +311,98 @@ func (a *GinAdapter) createGinHandler(route *routing.Route) gin.HandlerFunc {
 	}
 }
 
+// streamRecords writes a list route's records as they arrive from the
+// handler, flushing periodically, so large exports never sit in memory as
+// one protobuf slice or JSON buffer.
+func streamRecords(ctx context.Context, c *gin.Context, route *routing.Route, req proto.Message) {
+	streamer, ok := route.Handler.(contracts.RecordStreamer)
+	if !ok {
+		c.JSON(400, gin.H{
+			"error":      contracts.ErrStreamingUnsupported.Error(),
+			"route_name": route.Metadata.Name,
+		})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	sw := contracts.NewJSONStreamWriter(c.Writer, c.Writer.Flush)
+	err := streamer.StreamRecords(ctx, req, sw.Write)
+	if err != nil && !sw.Started() {
+		status := 500
+		switch {
+		case errors.Is(err, contracts.ErrStreamingUnsupported):
+			status = 400
+		case errors.Is(err, context.DeadlineExceeded):
+			status = 504
+		}
+		c.JSON(status, gin.H{
+			"error":      "Handler execution failed",
+			"details":    err.Error(),
+			"route_name": route.Metadata.Name,
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("WARNING: stream for %s failed after output started: %v", route.Path, err)
+	}
+	if err := sw.Close(err); err != nil {
+		log.Printf("WARNING: failed to finish stream for %s: %v", route.Path, err)
+	}
+}
+
+// exportRecords writes an export route's records as a CSV or XLSX
+// attachment, flushing periodically. A failure after the file has started
+// aborts the response, so the client sees a broken download rather than a
+// short file.
+func exportRecords(ctx context.Context, c *gin.Context, route *routing.Route, exporter contracts.RecordExporter, req proto.Message) {
+	opts, err := contracts.ParseExportOptions(c.Query(contracts.ExportFormatParam), c.Query(contracts.ExportColumnsParam))
+	if err != nil {
+		c.JSON(400, gin.H{
+			"error":      "Invalid export request",
+			"details":    err.Error(),
+			"route_name": route.Metadata.Name,
+		})
+		return
+	}
+	ew, err := contracts.NewExportWriter(c.Writer, exporter, opts, c.Writer.Flush)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"error":      "Invalid export request",
+			"details":    err.Error(),
+			"route_name": route.Metadata.Name,
+		})
+		return
+	}
+
+	c.Header("Content-Type", opts.Format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", ew.Filename(time.Now())))
+	err = exporter.StreamRecords(ctx, req, ew.Write)
+	if err != nil && !ew.Started() {
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		status := 500
+		switch {
+		case errors.Is(err, contracts.ErrStreamingUnsupported), errors.Is(err, contracts.ErrInvalidExport):
+			status = 400
+		case errors.Is(err, context.DeadlineExceeded):
+			status = 504
+		}
+		c.JSON(status, gin.H{
+			"error":      "Export failed",
+			"details":    err.Error(),
+			"route_name": route.Metadata.Name,
+		})
+		return
+	}
+	if closeErr := ew.Close(err); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Printf("WARNING: export for %s failed after %d records: %v", route.Path, ew.Count(), err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
 // Start starts the Gin HTTP server on the specified address.
 func (a *GinAdapter) Start(addr string) error {
 	if a.router == nil {
@@ -277,7 +424,10 @@ func (a *GinAdapter) IsHealthy(ctx context.Context) error {
 // Close shuts down the Gin server.
 func (a *GinAdapter) Close() error {
 	log.Printf("Gin adapter closing")
-	return nil
+	if err := a.idem.Close(); err != nil {
+		log.Printf("WARNING: failed to close idempotency store: %v", err)
+	}
+	return a.limiter.Close()
 }
 
 // IsEnabled returns whether this adapter is enabled.
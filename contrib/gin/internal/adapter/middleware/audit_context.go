@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	infraports "github.com/erniealice/espyna-golang/ports"
+	"github.com/erniealice/espyna-golang/shared/correlation"
 )
 
 // AuditContext populates infraports.AuditContext on every request with ActorID,
@@ -29,8 +30,12 @@ func AuditContext() gin.HandlerFunc {
 			actorType = "system"
 		}
 
-		// Request ID: use incoming header or generate one.
-		requestID := c.GetHeader("X-Request-ID")
+		// Request ID: reuse the correlation ID assigned by RequestLogger,
+		// else the incoming header, else generate one.
+		requestID := correlation.FromContext(c.Request.Context())
+		if requestID == "" {
+			requestID = c.GetHeader("X-Request-ID")
+		}
 		if requestID == "" {
 			requestID = uuid.New().String()
 		}
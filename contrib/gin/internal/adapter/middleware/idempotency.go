@@ -0,0 +1,109 @@
+//go:build gin
+
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/erniealice/espyna-golang/composition/contracts"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// Idempotency makes POST requests carrying an Idempotency-Key header safe
+// to retry, replaying the first response for a key (per workspace, user
+// and route) with Idempotent-Replayed: true. Mirrors vanilla
+// contrib/http/internal/adapter/middleware/idempotency.go. A nil tracker
+// passes everything through.
+func Idempotency(tracker *contracts.Idempotency) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(contracts.IdempotencyKeyHeader)
+		if !tracker.Tracks(c.Request.Method, key) {
+			c.Next()
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(tracker.MaxBodyBytes())+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"success": false, "error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+
+		// The authenticated identity scopes the key; the header counts only
+		// when the identity has no workspace.
+		var workspaceID, userID string
+		if id, ok := identity.FromContext(c.Request.Context()); ok {
+			workspaceID, userID = id.WorkspaceID, id.UserID
+		}
+		if workspaceID == "" {
+			workspaceID = c.GetHeader(contracts.WorkspaceHeader)
+		}
+		claim, resp := tracker.Begin(c.Request.Context(), contracts.IdempotencyRequest{
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			Query:       c.Request.URL.RawQuery,
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+			Key:         key,
+			Body:        body,
+		})
+		if resp != nil {
+			if resp.Replayed {
+				c.Header(contracts.IdempotentReplayedHeader, "true")
+			}
+			c.Data(resp.Status, resp.ContentType, resp.Body)
+			c.Abort()
+			return
+		}
+		if claim == nil {
+			c.Next()
+			return
+		}
+
+		rec := &idempotencyWriter{ResponseWriter: c.Writer, limit: tracker.MaxBodyBytes()}
+		c.Writer = rec
+		defer func() {
+			// gin.Recovery further out answers a panic with a 500.
+			if p := recover(); p != nil {
+				claim.Finish(c.Request.Context(), contracts.IdempotentResponse{Status: http.StatusInternalServerError})
+				panic(p)
+			}
+		}()
+		c.Next()
+		claim.Finish(c.Request.Context(), contracts.IdempotentResponse{
+			Status:      rec.Status(),
+			ContentType: rec.Header().Get("Content-Type"),
+			Body:        rec.body.Bytes(),
+		})
+	}
+}
+
+// idempotencyWriter passes the response through while keeping a copy of
+// it, up to one byte over limit.
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body  bytes.Buffer
+	limit int
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.keep(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *idempotencyWriter) keep(b []byte) {
+	if room := w.limit + 1 - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(len(b), room)])
+	}
+}
@@ -0,0 +1,48 @@
+//go:build gin
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/erniealice/espyna-golang/composition/contracts"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// RateLimit refuses requests over the routing config's rate limits with 429
+// Too Many Requests and a Retry-After header, identifying clients by IP and
+// by the X-Workspace-ID header. Mirrors vanilla
+// contrib/http/internal/adapter/middleware/rate_limit.go. A nil limiter
+// passes everything through.
+func RateLimit(limiter *contracts.RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		workspaceID := c.GetHeader(contracts.WorkspaceHeader)
+		if workspaceID == "" {
+			if id, ok := identity.FromContext(c.Request.Context()); ok {
+				workspaceID = id.WorkspaceID
+			}
+		}
+		decision := limiter.Allow(c.Request.Context(), contracts.RateLimitRequest{
+			Path:        c.Request.URL.Path,
+			ClientIP:    extractClientIP(c),
+			WorkspaceID: workspaceID,
+		})
+		for k, v := range decision.Headers() {
+			c.Header(k, v)
+		}
+		if !decision.Allowed {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Too many requests",
+				"details": decision.Scope + " rate limit exceeded",
+			})
+			return
+		}
+		c.Next()
+	}
+}
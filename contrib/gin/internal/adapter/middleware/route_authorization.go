@@ -0,0 +1,41 @@
+//go:build gin
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/erniealice/espyna-golang/composition/contracts"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// RouteAuthorization refuses requests whose caller lacks the permission
+// their route maps to. Mirrors vanilla
+// contrib/http/internal/adapter/middleware/route_authorization.go. A nil
+// authorizer passes everything through.
+func RouteAuthorization(authorizer *contracts.RouteAuthorizer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authorizer == nil {
+			c.Next()
+			return
+		}
+		req := contracts.AuthorizationRequest{Method: c.Request.Method, Path: c.Request.URL.Path}
+		if id, ok := identity.FromContext(c.Request.Context()); ok {
+			req.UserID, req.WorkspaceID = id.UserID, id.WorkspaceID
+		}
+		if req.WorkspaceID == "" {
+			req.WorkspaceID = c.GetHeader(contracts.WorkspaceHeader)
+		}
+		decision := authorizer.Authorize(c.Request.Context(), req)
+		if !decision.Allowed {
+			c.AbortWithStatusJSON(decision.Status, gin.H{
+				"error":   http.StatusText(decision.Status),
+				"details": decision.Reason,
+			})
+			return
+		}
+		c.Next()
+	}
+}
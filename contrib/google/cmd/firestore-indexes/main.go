@@ -0,0 +1,118 @@
+// Package main emits firestore.indexes.json, the composite indexes the
+// Firestore adapter's queries need. It walks the registered Firestore
+// repositories for the query every GetXxxListPageData runs with cursor
+// pagination (active, then date_created in either direction), then adds the
+// filter and sort combinations recorded by the running adapter: with
+// CONFIG_FIRESTORE_INDEX_PATTERNS_FILE set, every query pattern needing a
+// composite index is appended to that file. Running the app against the
+// emulator, which does not enforce indexes, collects them before deploying.
+//
+// Usage:
+//
+//	firestore-indexes [-patterns patterns.jsonl]... [-existing firestore.indexes.json] [-out firestore.indexes.json]
+//
+// Deploy the result with `firebase deploy --only firestore:indexes`.
+// Collection names follow the FIRESTORE_TABLE_* variables the adapter reads.
+
+//go:build firestore
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	_ "github.com/erniealice/espyna-golang/contrib/google/internal/database/firestore"
+	"github.com/erniealice/espyna-golang/contrib/google/internal/database/firestore/core"
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/registry"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// fileList is a repeatable flag.
+type fileList []string
+
+func (f *fileList) String() string     { return strings.Join(*f, ",") }
+func (f *fileList) Set(v string) error { *f = append(*f, v); return nil }
+
+func main() {
+	var patternFiles fileList
+	flag.Var(&patternFiles, "patterns", "query patterns recorded through CONFIG_FIRESTORE_INDEX_PATTERNS_FILE (repeatable)")
+	existing := flag.String("existing", "", "firestore.indexes.json whose indexes are kept")
+	out := flag.String("out", "", "file to write (default stdout)")
+	flag.Parse()
+
+	advisor := core.NewIndexAdvisor(nil)
+
+	if *existing != "" {
+		f, err := os.Open(*existing)
+		if err != nil {
+			log.Fatalf("open %s: %v", *existing, err)
+		}
+		indexes, err := core.ReadIndexesFile(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("%s: %v", *existing, err)
+		}
+		for _, index := range indexes {
+			advisor.Add(index)
+		}
+	}
+
+	tables, err := registry.BuildDatabaseTableConfig("firestore")
+	if err != nil {
+		log.Fatalf("firestore table config: %v", err)
+	}
+	entities := registry.ListRepositoryFactories("firestore")
+	sort.Strings(entities)
+	for _, entity := range entities {
+		collection := tables.TableName(entity)
+		for _, dir := range []commonpb.SortDirection{commonpb.SortDirection_DESC, commonpb.SortDirection_ASC} {
+			advisor.Observe(core.ListPattern(collection, cursorParams(dir), true))
+		}
+	}
+
+	for _, path := range patternFiles {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("open %s: %v", path, err)
+		}
+		patterns, err := core.ReadPatterns(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("%s: %v", path, err)
+		}
+		for _, p := range patterns {
+			advisor.Observe(p)
+		}
+	}
+
+	indexes := advisor.Indexes()
+	w := os.Stdout
+	if *out != "" {
+		if w, err = os.Create(*out); err != nil {
+			log.Fatalf("create %s: %v", *out, err)
+		}
+	}
+	if err := core.WriteIndexesFile(w, indexes); err != nil {
+		log.Fatalf("write indexes: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		log.Fatalf("write indexes: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "%d composite indexes for %d collections\n", len(indexes), len(entities))
+}
+
+// cursorParams are the list params of a cursor page in direction dir.
+func cursorParams(dir commonpb.SortDirection) *interfaces.ListParams {
+	return &interfaces.ListParams{
+		Sort: &commonpb.SortRequest{Fields: []*commonpb.SortField{{Field: interfaces.CursorKeyField, Direction: dir}}},
+		Pagination: &commonpb.PaginationRequest{
+			Method: &commonpb.PaginationRequest_Cursor{Cursor: &commonpb.CursorPagination{}},
+		},
+	}
+}
@@ -4,6 +4,7 @@ go 1.25.1
 
 require (
 	cloud.google.com/go/firestore v1.18.0
+	cloud.google.com/go/pubsub v1.50.1
 	cloud.google.com/go/secretmanager v1.15.0
 	cloud.google.com/go/storage v1.57.0
 	firebase.google.com/go/v4 v4.18.0
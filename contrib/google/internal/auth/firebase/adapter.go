@@ -2,17 +2,20 @@ package firebase
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"time"
 
+	"firebase.google.com/go/v4/auth"
 	authpb "github.com/erniealice/esqyma/pkg/schema/v1/infrastructure/auth"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	firebaseCommon "github.com/erniealice/espyna-golang/contrib/google/internal/common/firebase"
 	"github.com/erniealice/espyna-golang/ports"
 	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/authclaims"
 )
 
 // =============================================================================
@@ -278,6 +281,14 @@ func (p *FirebaseAuthAdapter) VerifyToken(ctx context.Context, req *authpb.Valid
 		Provider:  authpb.Provider_PROVIDER_GCP,
 	}
 
+	// Pass the synced role claims on as JSON, for the middleware's coarse
+	// checks (authclaims.Parse reads them back)
+	if rbac, ok := firebaseToken.Claims[authclaims.Key]; ok {
+		if encoded, err := json.Marshal(rbac); err == nil {
+			jwtToken.CustomClaims = map[string]string{authclaims.Key: string(encoded)}
+		}
+	}
+
 	return &authpb.ValidateJwtTokenResponse{
 		IsValid:  true,
 		Token:    jwtToken,
@@ -295,6 +306,42 @@ func (p *FirebaseAuthAdapter) ChangePassword(ctx context.Context, userID, oldPas
 	return fmt.Errorf("change password not supported by firebase auth provider; use Firebase Admin SDK")
 }
 
+// GetCustomUserClaims returns the user's custom claims, for the claims
+// syncer.
+func (p *FirebaseAuthAdapter) GetCustomUserClaims(ctx context.Context, uid string) (map[string]any, error) {
+	authClient, err := p.authClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := authClient.GetUser(ctx, uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get firebase user %s: %w", uid, err)
+	}
+	return user.CustomClaims, nil
+}
+
+// SetCustomUserClaims replaces the user's custom claims, for the claims
+// syncer. They reach the user's ID tokens when the tokens are next
+// refreshed.
+func (p *FirebaseAuthAdapter) SetCustomUserClaims(ctx context.Context, uid string, claims map[string]any) error {
+	authClient, err := p.authClient(ctx)
+	if err != nil {
+		return err
+	}
+	if err := authClient.SetCustomUserClaims(ctx, uid, claims); err != nil {
+		return fmt.Errorf("failed to set firebase custom claims for %s: %w", uid, err)
+	}
+	return nil
+}
+
+// authClient returns the Firebase auth client of an enabled adapter.
+func (p *FirebaseAuthAdapter) authClient(ctx context.Context) (*auth.Client, error) {
+	if !p.enabled || p.clientManager == nil {
+		return nil, fmt.Errorf("firebase auth provider is not enabled")
+	}
+	return p.clientManager.GetAuthClient(ctx)
+}
+
 // Helper function to safely extract string claims
 func getStringClaim(claims map[string]interface{}, key string) string {
 	if val, ok := claims[key]; ok {
@@ -9,6 +9,7 @@ import (
 	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/auth"
+	"firebase.google.com/go/v4/messaging"
 	"github.com/erniealice/espyna-golang/contrib/google/internal/common/gcp"
 )
 
@@ -20,6 +21,7 @@ type FirebaseClientManager struct {
 	app             *firebase.App
 	authClient      *auth.Client
 	firestoreClient *firestore.Client
+	messagingClient *messaging.Client
 	config          *gcp.CredentialConfig
 	firestoreDB     string
 }
@@ -123,6 +125,24 @@ func (m *FirebaseClientManager) GetFirestoreClient(ctx context.Context) (*firest
 	return m.firestoreClient, nil
 }
 
+// GetMessagingClient returns or creates the Firebase Cloud Messaging client
+//
+// The client is created lazily on first access and cached for subsequent calls.
+func (m *FirebaseClientManager) GetMessagingClient(ctx context.Context) (*messaging.Client, error) {
+	if m.messagingClient != nil {
+		return m.messagingClient, nil
+	}
+
+	client, err := m.app.Messaging(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create messaging client: %w", err)
+	}
+
+	m.messagingClient = client
+	log.Println("✅ Firebase Cloud Messaging client initialized successfully")
+	return m.messagingClient, nil
+}
+
 // GetProjectID returns the Firebase project ID
 func (m *FirebaseClientManager) GetProjectID() string {
 	return m.config.ProjectID
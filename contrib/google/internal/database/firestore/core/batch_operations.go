@@ -0,0 +1,285 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/database/model"
+)
+
+// CreateMany writes every document through a BulkWriter. Documents are
+// stamped exactly like Create. BulkWriter batches and retries writes but is
+// not atomic: on error some documents may already exist.
+func (f *FirestoreOperations) CreateMany(ctx context.Context, collectionName string, data []map[string]any) ([]map[string]any, error) {
+	if collectionName == "" {
+		return nil, model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
+	}
+	if len(data) == 0 {
+		return []map[string]any{}, nil
+	}
+
+	now := time.Now().UTC()
+	refs := make([]*firestore.DocumentRef, len(data))
+	for i, doc := range data {
+		if id, exists := doc["id"]; exists && id != "" {
+			refs[i] = f.client.Collection(collectionName).Doc(fmt.Sprintf("%v", id))
+		} else {
+			refs[i] = f.client.Collection(collectionName).NewDoc()
+			doc["id"] = refs[i].ID
+		}
+		doc["active"] = true
+		doc["date_created"] = now.UnixMilli() // Store as int64 for protobuf
+		doc["date_created_string"] = now.Format("2006-01-02T15:04:05.000Z")
+		doc["date_modified"] = now.UnixMilli() // Store as int64 for protobuf
+		doc["date_modified_string"] = now.Format("2006-01-02T15:04:05.000Z")
+	}
+
+	bw := f.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, len(data))
+	for i, doc := range data {
+		job, err := bw.Set(refs[i], doc)
+		if err != nil {
+			bw.End()
+			return nil, model.NewDatabaseError(
+				fmt.Sprintf("failed to queue document create: %v", err),
+				"FIRESTORE_CREATE_FAILED",
+				500,
+			)
+		}
+		jobs[i] = job
+	}
+	if err := endBulkWriter(bw, jobs, "FIRESTORE_CREATE_FAILED", "create"); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// maxAtomicWrites is Firestore's limit on writes in one commit.
+const maxAtomicWrites = 500
+
+// CreateAtomic creates documents across several collections in one
+// write-only transaction, so they commit together or not at all. Documents
+// are stamped exactly like Create. Unlike CreateMany it is capped at 500
+// writes, Firestore's per-commit limit.
+func (f *FirestoreOperations) CreateAtomic(ctx context.Context, writes []interfaces.TableRows) ([]interfaces.TableRows, error) {
+	total := 0
+	for _, w := range writes {
+		if w.Table == "" {
+			return nil, model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
+		}
+		total += len(w.Rows)
+	}
+	if total == 0 {
+		return writes, nil
+	}
+	if total > maxAtomicWrites {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("atomic create of %d documents exceeds the Firestore limit of %d", total, maxAtomicWrites),
+			"FIRESTORE_BATCH_TOO_LARGE",
+			400,
+		)
+	}
+
+	now := time.Now().UTC()
+	type pending struct {
+		ref *firestore.DocumentRef
+		doc map[string]any
+	}
+	queued := make([]pending, 0, total)
+	for _, w := range writes {
+		for _, doc := range w.Rows {
+			var ref *firestore.DocumentRef
+			if id, exists := doc["id"]; exists && id != "" {
+				ref = f.client.Collection(w.Table).Doc(fmt.Sprintf("%v", id))
+			} else {
+				ref = f.client.Collection(w.Table).NewDoc()
+				doc["id"] = ref.ID
+			}
+			doc["active"] = true
+			doc["date_created"] = now.UnixMilli() // Store as int64 for protobuf
+			doc["date_created_string"] = now.Format("2006-01-02T15:04:05.000Z")
+			doc["date_modified"] = now.UnixMilli() // Store as int64 for protobuf
+			doc["date_modified_string"] = now.Format("2006-01-02T15:04:05.000Z")
+			queued = append(queued, pending{ref: ref, doc: doc})
+		}
+	}
+
+	err := f.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, p := range queued {
+			if err := tx.Set(p.ref, p.doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to create %d document(s) atomically: %v", total, err),
+			"FIRESTORE_CREATE_FAILED",
+			500,
+		)
+	}
+	return writes, nil
+}
+
+// UpdateMany merges every update through a BulkWriter after one GetAll
+// round trip that confirms each document exists and recovers its creation
+// stamps (see Update). Not atomic — see CreateMany.
+func (f *FirestoreOperations) UpdateMany(ctx context.Context, collectionName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	if collectionName == "" {
+		return nil, model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
+	}
+	if len(updates) == 0 {
+		return []map[string]any{}, nil
+	}
+
+	refs := make([]*firestore.DocumentRef, len(updates))
+	for i, u := range updates {
+		if u.ID == "" {
+			return nil, model.NewDatabaseError("document ID is required", "MISSING_DOCUMENT_ID", 400)
+		}
+		refs[i] = f.client.Collection(collectionName).Doc(u.ID)
+	}
+	snaps, err := f.getAllExisting(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	results := make([]map[string]any, len(updates))
+	bw := f.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, len(updates))
+	for i, u := range updates {
+		data := u.Data
+		if data == nil {
+			data = map[string]any{}
+		}
+		data["date_modified"] = now.UnixMilli() // Store as int64 for protobuf
+		data["date_modified_string"] = now.Format("2006-01-02T15:04:05.000Z")
+		originalData := snaps[i].Data()
+		if dateCreated, exists := originalData["date_created"]; exists {
+			data["date_created"] = dateCreated
+		}
+		if dateCreatedString, exists := originalData["date_created_string"]; exists {
+			data["date_created_string"] = dateCreatedString
+		}
+
+		job, err := bw.Set(refs[i], data, firestore.MergeAll)
+		if err != nil {
+			bw.End()
+			return nil, model.NewDatabaseError(
+				fmt.Sprintf("failed to queue document update: %v", err),
+				"FIRESTORE_UPDATE_FAILED",
+				500,
+			)
+		}
+		jobs[i] = job
+		data["id"] = u.ID
+		results[i] = data
+	}
+	if err := endBulkWriter(bw, jobs, "FIRESTORE_UPDATE_FAILED", "update"); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// DeleteMany soft-deletes every document through a BulkWriter after one
+// GetAll existence check. Not atomic — see CreateMany.
+func (f *FirestoreOperations) DeleteMany(ctx context.Context, collectionName string, ids []string) error {
+	if collectionName == "" {
+		return model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	refs := make([]*firestore.DocumentRef, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			return model.NewDatabaseError("document ID is required", "MISSING_DOCUMENT_ID", 400)
+		}
+		if !seen[id] {
+			seen[id] = true
+			refs = append(refs, f.client.Collection(collectionName).Doc(id))
+		}
+	}
+	if _, err := f.getAllExisting(ctx, refs); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	updateData := map[string]any{
+		"active":               false,
+		"date_modified":        now.UnixMilli(), // Store as int64 for protobuf
+		"date_modified_string": now.Format("2006-01-02T15:04:05.000Z"),
+	}
+	bw := f.client.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, len(refs))
+	for i, ref := range refs {
+		job, err := bw.Set(ref, updateData, firestore.MergeAll)
+		if err != nil {
+			bw.End()
+			return model.NewDatabaseError(
+				fmt.Sprintf("failed to queue document delete: %v", err),
+				"FIRESTORE_DELETE_FAILED",
+				500,
+			)
+		}
+		jobs[i] = job
+	}
+	return endBulkWriter(bw, jobs, "FIRESTORE_DELETE_FAILED", "delete")
+}
+
+// getAllExisting fetches refs in one round trip and fails with
+// DOCUMENT_NOT_FOUND when any of them is missing.
+func (f *FirestoreOperations) getAllExisting(ctx context.Context, refs []*firestore.DocumentRef) ([]*firestore.DocumentSnapshot, error) {
+	snaps, err := f.client.GetAll(ctx, refs)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to get documents: %v", err),
+			"FIRESTORE_READ_FAILED",
+			500,
+		)
+	}
+	var missing []string
+	for i, snap := range snaps {
+		if !snap.Exists() {
+			missing = append(missing, refs[i].ID)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("%d document(s) not found: %v", len(missing), missing),
+			"DOCUMENT_NOT_FOUND",
+			404,
+		)
+	}
+	return snaps, nil
+}
+
+// endBulkWriter flushes bw and reports the first failed job, if any.
+func endBulkWriter(bw *firestore.BulkWriter, jobs []*firestore.BulkWriterJob, code, verb string) error {
+	bw.End()
+	failed := 0
+	var first error
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			failed++
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	if failed > 0 {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to %s %d of %d document(s): %v", verb, failed, len(jobs), first),
+			code,
+			500,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,404 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// =============================================================================
+// Composite Index Advisor
+// =============================================================================
+
+// Firestore serves a query that filters or sorts on more than one field only
+// from a composite index, and fails with FailedPrecondition otherwise. List
+// builds its queries from the request (the filters and sort of each
+// GetXxxListPageData), so the indexes needed depend on how the API is used.
+// The advisor derives the index each query needs, logs the console link of
+// the ones missing, and can append every pattern seen to a file from which
+// cmd/firestore-indexes emits firestore.indexes.json.
+
+// Index field orders, as firestore.indexes.json spells them.
+const (
+	IndexAscending  = "ASCENDING"
+	IndexDescending = "DESCENDING"
+)
+
+// documentIDField is the field path of the document ID in orderings.
+const documentIDField = "__name__"
+
+// IndexPatternsFileEnv names the file the advisor appends query patterns to,
+// one JSON object per line; unset, patterns are only kept in memory.
+const IndexPatternsFileEnv = "CONFIG_FIRESTORE_INDEX_PATTERNS_FILE"
+
+// IndexField is one field of a composite index, or of a query's ordering.
+// Array fields queried with array-contains have an ArrayConfig of CONTAINS
+// instead of an Order.
+type IndexField struct {
+	FieldPath   string `json:"fieldPath"`
+	Order       string `json:"order,omitempty"`
+	ArrayConfig string `json:"arrayConfig,omitempty"`
+}
+
+// QueryPattern is the shape of a query: the fields it compares for
+// equality, the array fields it tests with array-contains, those it
+// compares with inequalities, and its ordering. Values do not matter to the
+// index, so queries differing only in values share a pattern.
+type QueryPattern struct {
+	Collection string       `json:"collection"`
+	Equality   []string     `json:"equality,omitempty"`
+	Contains   []string     `json:"contains,omitempty"`
+	Range      []string     `json:"range,omitempty"`
+	OrderBy    []IndexField `json:"orderBy,omitempty"`
+}
+
+// CompositeIndex is an entry of firestore.indexes.json.
+type CompositeIndex struct {
+	CollectionGroup string       `json:"collectionGroup"`
+	QueryScope      string       `json:"queryScope"`
+	Fields          []IndexField `json:"fields"`
+}
+
+// key identifies the index for deduplication.
+func (i CompositeIndex) key() string {
+	var b strings.Builder
+	b.WriteString(i.CollectionGroup)
+	for _, f := range i.Fields {
+		b.WriteString("|" + f.FieldPath + ":" + f.Order + f.ArrayConfig)
+	}
+	return b.String()
+}
+
+// ListPattern returns the pattern of the query List (or Count, without the
+// sort) runs for params: the active filter, the typed filters, then the
+// requested sort or, for cursor pages, the date_created/document ID order.
+func ListPattern(collection string, params *interfaces.ListParams, sorted bool) QueryPattern {
+	p := QueryPattern{Collection: collection, Equality: []string{"active"}}
+	if params != nil && params.Filters != nil {
+		for _, filter := range params.Filters.Filters {
+			if filter.GetField() == interfaces.ActiveField && filter.GetBooleanFilter() != nil {
+				continue // replaces the default active filter
+			}
+			if equality, ok := filterKind(filter); ok {
+				if equality {
+					p.Equality = append(p.Equality, filter.Field)
+				} else {
+					p.Range = append(p.Range, filter.Field)
+				}
+			}
+		}
+	}
+	if !sorted {
+		return p
+	}
+	if _, ok := interfaces.CursorRequest(params); ok {
+		order := IndexDescending
+		if descending, err := interfaces.CursorDescending(params); err == nil && !descending {
+			order = IndexAscending
+		}
+		p.OrderBy = []IndexField{{FieldPath: interfaces.CursorKeyField, Order: order}, {FieldPath: documentIDField, Order: order}}
+		return p
+	}
+	if params != nil && params.Sort != nil {
+		for _, s := range params.Sort.Fields {
+			order := IndexAscending
+			if s.Direction == commonpb.SortDirection_DESC {
+				order = IndexDescending
+			}
+			p.OrderBy = append(p.OrderBy, IndexField{FieldPath: s.Field, Order: order})
+		}
+	}
+	return p
+}
+
+// filterKind reports whether applyTypedFilter compares the filter's field
+// for equality (== and in) or with an inequality; ok is false when it
+// applies no condition.
+func filterKind(filter *commonpb.TypedFilter) (equality, ok bool) {
+	switch ft := filter.FilterType.(type) {
+	case *commonpb.TypedFilter_StringFilter:
+		switch ft.StringFilter.Operator {
+		case commonpb.StringOperator_STRING_EQUALS:
+			return true, true
+		case commonpb.StringOperator_STRING_NOT_EQUALS, commonpb.StringOperator_STRING_STARTS_WITH:
+			return false, true
+		}
+	case *commonpb.TypedFilter_NumberFilter:
+		switch ft.NumberFilter.Operator {
+		case commonpb.NumberOperator_NUMBER_EQUALS:
+			return true, true
+		case commonpb.NumberOperator_NUMBER_NOT_EQUALS,
+			commonpb.NumberOperator_NUMBER_GREATER_THAN,
+			commonpb.NumberOperator_NUMBER_GREATER_THAN_OR_EQUAL,
+			commonpb.NumberOperator_NUMBER_LESS_THAN,
+			commonpb.NumberOperator_NUMBER_LESS_THAN_OR_EQUAL:
+			return false, true
+		}
+	case *commonpb.TypedFilter_BooleanFilter:
+		return true, true
+	case *commonpb.TypedFilter_ListFilter:
+		switch ft.ListFilter.Operator {
+		case commonpb.ListOperator_LIST_IN:
+			return true, true
+		case commonpb.ListOperator_LIST_NOT_IN:
+			return false, true
+		}
+	case *commonpb.TypedFilter_RangeFilter:
+		return false, true
+	}
+	return false, false
+}
+
+// ConditionsPattern returns the pattern of a structured query (see Query).
+func ConditionsPattern(collection string, filter interfaces.QueryFilter) QueryPattern {
+	p := QueryPattern{Collection: collection}
+	for _, c := range filter.Conditions {
+		switch c.Operator {
+		case "==", "in":
+			p.Equality = append(p.Equality, c.Field)
+		case "array-contains", "array-contains-any":
+			p.Contains = append(p.Contains, c.Field)
+		default:
+			p.Range = append(p.Range, c.Field)
+		}
+	}
+	for _, o := range filter.OrderBy {
+		order := IndexAscending
+		if !o.Ascending {
+			order = IndexDescending
+		}
+		p.OrderBy = append(p.OrderBy, IndexField{FieldPath: o.Field, Order: order})
+	}
+	return p
+}
+
+// Index returns the composite index serving the pattern, following
+// Firestore's layout: equality and array-contains fields first, then the
+// ordering, then inequality fields not already ordered (Firestore orders by
+// them implicitly). ok is false when the single-field indexes suffice: one
+// field in all, or equality filters only, which Firestore merges.
+func (p QueryPattern) Index() (CompositeIndex, bool) {
+	ordered := make(map[string]bool)
+	for _, f := range p.OrderBy {
+		ordered[f.FieldPath] = true
+	}
+	for _, f := range p.Range {
+		ordered[f] = true
+	}
+
+	var fields []IndexField
+	seen := make(map[string]bool)
+	equality := append([]string(nil), p.Equality...)
+	sort.Strings(equality)
+	for _, f := range equality {
+		if !ordered[f] && !seen[f] {
+			seen[f] = true
+			fields = append(fields, IndexField{FieldPath: f, Order: IndexAscending})
+		}
+	}
+	for _, f := range p.Contains {
+		if !seen[f] {
+			seen[f] = true
+			fields = append(fields, IndexField{FieldPath: f, ArrayConfig: "CONTAINS"})
+		}
+	}
+	onlyEquality := len(p.OrderBy) == 0 && len(p.Range) == 0 && len(p.Contains) == 0
+	for _, f := range p.OrderBy {
+		if !seen[f.FieldPath] {
+			seen[f.FieldPath] = true
+			fields = append(fields, f)
+		}
+	}
+	ranges := append([]string(nil), p.Range...)
+	sort.Strings(ranges)
+	for _, f := range ranges {
+		if !seen[f] {
+			seen[f] = true
+			fields = append(fields, IndexField{FieldPath: f, Order: IndexAscending})
+		}
+	}
+
+	// Every index ends in the document ID in the direction of the last
+	// field, so a trailing __name__ in that direction is implied.
+	if n := len(fields); n > 1 && fields[n-1].FieldPath == documentIDField && fields[n-1].Order == fields[n-2].Order {
+		fields = fields[:n-1]
+	}
+	if onlyEquality || len(fields) < 2 {
+		return CompositeIndex{}, false
+	}
+	return CompositeIndex{CollectionGroup: p.Collection, QueryScope: "COLLECTION", Fields: fields}, true
+}
+
+// IndexAdvisor collects the composite indexes of the query patterns it is
+// shown.
+type IndexAdvisor struct {
+	mu      sync.Mutex
+	indexes map[string]CompositeIndex
+	missing map[string]bool
+	out     io.Writer
+}
+
+// NewIndexAdvisor creates an advisor appending each new pattern to out as a
+// JSON line; out may be nil.
+func NewIndexAdvisor(out io.Writer) *IndexAdvisor {
+	return &IndexAdvisor{indexes: make(map[string]CompositeIndex), missing: make(map[string]bool), out: out}
+}
+
+// defaultIndexAdvisor sees every query of FirestoreOperations.
+var defaultIndexAdvisor = newIndexAdvisorFromEnv()
+
+// newIndexAdvisorFromEnv opens IndexPatternsFileEnv for appending when set.
+func newIndexAdvisorFromEnv() *IndexAdvisor {
+	path := strings.TrimSpace(os.Getenv(IndexPatternsFileEnv))
+	if path == "" {
+		return NewIndexAdvisor(nil)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Printf("⚠️  Firestore index advisor cannot open %s: %v", path, err)
+		return NewIndexAdvisor(nil)
+	}
+	return NewIndexAdvisor(f)
+}
+
+// Observe records the pattern's index, if it needs one.
+func (a *IndexAdvisor) Observe(p QueryPattern) {
+	index, ok := p.Index()
+	if !ok {
+		return
+	}
+	key := index.key()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, seen := a.indexes[key]; seen {
+		return
+	}
+	a.indexes[key] = index
+	if a.out != nil {
+		line, _ := json.Marshal(p)
+		if _, err := a.out.Write(append(line, '\n')); err != nil {
+			log.Printf("⚠️  Firestore index advisor cannot record pattern: %v", err)
+		}
+	}
+}
+
+// Missing logs, once per index, that the pattern's query failed for want
+// of an index, with the console link that creates it.
+func (a *IndexAdvisor) Missing(p QueryPattern, link string) {
+	a.Observe(p)
+	index, ok := p.Index()
+	key := link
+	if ok {
+		key = index.key()
+	}
+	a.mu.Lock()
+	first := !a.missing[key]
+	a.missing[key] = true
+	a.mu.Unlock()
+	if !first {
+		return
+	}
+	if ok {
+		log.Printf("⚠️  Firestore query on %s needs the composite index %s; create it at %s", p.Collection, describeIndex(index), link)
+	} else {
+		log.Printf("⚠️  Firestore query on %s needs an index; create it at %s", p.Collection, link)
+	}
+}
+
+// Add records an index directly, e.g. one already deployed.
+func (a *IndexAdvisor) Add(index CompositeIndex) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.indexes[index.key()] = index
+}
+
+// Indexes returns the indexes recorded, sorted by collection and fields.
+func (a *IndexAdvisor) Indexes() []CompositeIndex {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]CompositeIndex, 0, len(a.indexes))
+	for _, index := range a.indexes {
+		out = append(out, index)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].key() < out[j].key() })
+	return out
+}
+
+// WriteIndexesFile writes indexes as a firestore.indexes.json document,
+// deployable with `firebase deploy --only firestore:indexes`.
+func WriteIndexesFile(w io.Writer, indexes []CompositeIndex) error {
+	if indexes == nil {
+		indexes = []CompositeIndex{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Indexes        []CompositeIndex `json:"indexes"`
+		FieldOverrides []any            `json:"fieldOverrides"`
+	}{indexes, []any{}})
+}
+
+// ReadIndexesFile reads the indexes of a firestore.indexes.json document.
+func ReadIndexesFile(r io.Reader) ([]CompositeIndex, error) {
+	var doc struct {
+		Indexes []CompositeIndex `json:"indexes"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("read firestore indexes: %w", err)
+	}
+	return doc.Indexes, nil
+}
+
+// ReadPatterns reads the JSON lines an advisor appended.
+func ReadPatterns(r io.Reader) ([]QueryPattern, error) {
+	var patterns []QueryPattern
+	dec := json.NewDecoder(r)
+	for dec.More() {
+		var p QueryPattern
+		if err := dec.Decode(&p); err != nil {
+			return nil, fmt.Errorf("read query pattern %d: %w", len(patterns)+1, err)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// describeIndex renders an index as collection(field ASC, ...).
+func describeIndex(index CompositeIndex) string {
+	parts := make([]string, len(index.Fields))
+	for i, f := range index.Fields {
+		switch {
+		case f.ArrayConfig != "":
+			parts[i] = f.FieldPath + " " + f.ArrayConfig
+		case f.Order == IndexDescending:
+			parts[i] = f.FieldPath + " DESC"
+		default:
+			parts[i] = f.FieldPath + " ASC"
+		}
+	}
+	return index.CollectionGroup + "(" + strings.Join(parts, ", ") + ")"
+}
+
+// indexLinkPattern matches the console link in Firestore's missing index
+// error.
+var indexLinkPattern = regexp.MustCompile(`https://console\.firebase\.google\.com/\S+`)
+
+// MissingIndexLink returns the link creating the index err reports missing.
+func MissingIndexLink(err error) (string, bool) {
+	if err == nil || status.Code(err) != codes.FailedPrecondition {
+		return "", false
+	}
+	link := indexLinkPattern.FindString(err.Error())
+	return strings.TrimRight(link, ".,;)"), link != ""
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func describe(p QueryPattern) string {
+	index, ok := p.Index()
+	if !ok {
+		return "none"
+	}
+	return describeIndex(index)
+}
+
+func TestQueryPattern_Index(t *testing.T) {
+	eq := func(field string) *commonpb.TypedFilter {
+		return &commonpb.TypedFilter{Field: field, FilterType: &commonpb.TypedFilter_StringFilter{
+			StringFilter: &commonpb.StringFilter{Operator: commonpb.StringOperator_STRING_EQUALS}}}
+	}
+	between := &commonpb.TypedFilter{Field: "amount", FilterType: &commonpb.TypedFilter_RangeFilter{RangeFilter: &commonpb.RangeFilter{}}}
+	sortBy := func(field string, dir commonpb.SortDirection) *commonpb.SortRequest {
+		return &commonpb.SortRequest{Fields: []*commonpb.SortField{{Field: field, Direction: dir}}}
+	}
+
+	for name, tc := range map[string]struct {
+		params *interfaces.ListParams
+		want   string
+	}{
+		"active only": {nil, "none"},
+		"equalities merge": {&interfaces.ListParams{Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{eq("workspace_id"), eq("status")}}},
+			"none"},
+		"sorted": {&interfaces.ListParams{Sort: sortBy("name", commonpb.SortDirection_DESC)},
+			"client(active ASC, name DESC)"},
+		"filtered and sorted": {&interfaces.ListParams{
+			Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{eq("workspace_id"), between}},
+			Sort:    sortBy("amount", commonpb.SortDirection_ASC),
+		}, "client(active ASC, workspace_id ASC, amount ASC)"},
+		"range implies order": {&interfaces.ListParams{Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{between}},
+			Sort: sortBy("name", commonpb.SortDirection_ASC)},
+			"client(active ASC, name ASC, amount ASC)"},
+		"deleted": {interfaces.DeletedParams(&interfaces.ListParams{Sort: sortBy("name", commonpb.SortDirection_DESC)}),
+			"client(active ASC, name DESC)"},
+		"cursor": {&interfaces.ListParams{Pagination: &commonpb.PaginationRequest{
+			Method: &commonpb.PaginationRequest_Cursor{Cursor: &commonpb.CursorPagination{}}}},
+			"client(active ASC, date_created DESC)"},
+	} {
+		if got := describe(ListPattern("client", tc.params, true)); got != tc.want {
+			t.Errorf("%s: index = %s, want %s", name, got, tc.want)
+		}
+	}
+
+	contains := ConditionsPattern("event", interfaces.QueryFilter{
+		Conditions: []interfaces.QueryCondition{{Field: "tags", Operator: "array-contains"}, {Field: "workspace_id", Operator: "=="}},
+		OrderBy:    []interfaces.OrderByClause{{Field: "start", Ascending: true}},
+	})
+	if got := describe(contains); got != "event(workspace_id ASC, tags CONTAINS, start ASC)" {
+		t.Errorf("structured query index = %s", got)
+	}
+}
+
+func TestIndexAdvisor(t *testing.T) {
+	var recorded bytes.Buffer
+	advisor := NewIndexAdvisor(&recorded)
+	sorted := QueryPattern{Collection: "client", Equality: []string{"active"}, OrderBy: []IndexField{{FieldPath: "name", Order: IndexAscending}}}
+	advisor.Observe(sorted)
+	advisor.Observe(sorted)
+	advisor.Observe(QueryPattern{Collection: "client", Equality: []string{"active"}})
+	advisor.Add(CompositeIndex{CollectionGroup: "admin", QueryScope: "COLLECTION", Fields: []IndexField{{FieldPath: "a", Order: IndexAscending}, {FieldPath: "b", Order: IndexAscending}}})
+
+	patterns, err := ReadPatterns(&recorded)
+	if err != nil || len(patterns) != 1 || patterns[0].Collection != "client" {
+		t.Fatalf("recorded patterns = %+v, %v", patterns, err)
+	}
+	var out bytes.Buffer
+	if err := WriteIndexesFile(&out, advisor.Indexes()); err != nil {
+		t.Fatal(err)
+	}
+	indexes, err := ReadIndexesFile(&out)
+	if err != nil || len(indexes) != 2 || indexes[0].CollectionGroup != "admin" || indexes[1].Fields[1].FieldPath != "name" {
+		t.Fatalf("indexes = %+v, %v", indexes, err)
+	}
+}
+
+func TestMissingIndexLink(t *testing.T) {
+	link := "https://console.firebase.google.com/v1/r/project/p/firestore/indexes?create_composite=Ck1wcm9q"
+	err := status.Error(codes.FailedPrecondition, "The query requires an index. You can create it here: "+link)
+	if got, ok := MissingIndexLink(fmt.Errorf("list: %w", err)); !ok || got != link {
+		t.Errorf("link = %q, %v", got, ok)
+	}
+	if _, ok := MissingIndexLink(status.Error(codes.Unavailable, link)); ok {
+		t.Error("link reported for an unavailable backend")
+	}
+	if _, ok := MissingIndexLink(errors.New(strings.ToUpper(link))); ok {
+		t.Error("link reported for a plain error")
+	}
+}
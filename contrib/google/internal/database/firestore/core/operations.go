@@ -7,9 +7,15 @@ import (
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	dbhistory "github.com/erniealice/espyna-golang/database/history"
 	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
 	"github.com/erniealice/espyna-golang/database/model"
+	dbshadow "github.com/erniealice/espyna-golang/database/shadow"
+	dbtenancy "github.com/erniealice/espyna-golang/database/tenancy"
 	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/metrics"
+	"github.com/erniealice/espyna-golang/shared/tracing"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
 )
 
@@ -29,15 +35,48 @@ type FirestoreOperations struct {
 	client *firestore.Client
 }
 
-// NewFirestoreOperations creates a new Firestore operations instance
+// NewFirestoreOperations creates a new Firestore operations instance. Its
+// reads are replayed against the shadow candidate when one is published
+// through registry.SetDefaultShadow, e.g. Postgres before a cutover, and
+// writes to the tables published through registry.SetDefaultHistory record
+// a revision. Once the policy published through registry.SetDefaultTenancy
+// is enabled, every non-global collection is confined to the request's
+// workspace.
 func NewFirestoreOperations(client *firestore.Client) interfaces.DatabaseOperation {
-	return &FirestoreOperations{
+	base := &FirestoreOperations{
 		client: client,
 	}
+	tenancy := dbtenancy.NewTenancyOperations(dbhistory.NewHistoryOperations(dbshadow.NewShadowOperations(base, "firestore")))
+	return &shadowedOperations{
+		DatabaseOperation: tenancy,
+		base:              base,
+		tenancy:           tenancy,
+	}
+}
+
+// shadowedOperations keeps CreateAtomic reachable through the tenancy,
+// shadow-read and history decorators. Atomic creates record no revision.
+type shadowedOperations struct {
+	interfaces.DatabaseOperation
+	base    *FirestoreOperations
+	tenancy *dbtenancy.TenancyOperations
+}
+
+// CreateAtomic stamps the rows with the request's workspace and writes
+// through to Firestore.
+func (s *shadowedOperations) CreateAtomic(ctx context.Context, writes []interfaces.TableRows) ([]interfaces.TableRows, error) {
+	stamped := make([]interfaces.TableRows, len(writes))
+	for i, w := range writes {
+		stamped[i] = interfaces.TableRows{Table: w.Table, Rows: s.tenancy.Stamp(ctx, w.Table, w.Rows)}
+	}
+	return s.base.CreateAtomic(ctx, stamped)
 }
 
 // Create creates a new document in the specified collection
-func (f *FirestoreOperations) Create(ctx context.Context, collectionName string, data map[string]any) (map[string]any, error) {
+func (f *FirestoreOperations) Create(ctx context.Context, collectionName string, data map[string]any) (_ map[string]any, opErr error) {
+	defer metrics.ObserveDBOperation("firestore", "create", collectionName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "firestore", "create", collectionName)
+	defer func() { span.End(opErr) }()
 	if collectionName == "" {
 		return nil, model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
 	}
@@ -73,7 +112,10 @@ func (f *FirestoreOperations) Create(ctx context.Context, collectionName string,
 }
 
 // Read retrieves a document by ID from the specified collection
-func (f *FirestoreOperations) Read(ctx context.Context, collectionName string, id string) (map[string]any, error) {
+func (f *FirestoreOperations) Read(ctx context.Context, collectionName string, id string) (_ map[string]any, opErr error) {
+	defer metrics.ObserveDBOperation("firestore", "read", collectionName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "firestore", "read", collectionName)
+	defer func() { span.End(opErr) }()
 	if collectionName == "" {
 		return nil, model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
 	}
@@ -101,7 +143,10 @@ func (f *FirestoreOperations) Read(ctx context.Context, collectionName string, i
 }
 
 // Update updates an existing document in the specified collection
-func (f *FirestoreOperations) Update(ctx context.Context, collectionName string, id string, data map[string]any) (map[string]any, error) {
+func (f *FirestoreOperations) Update(ctx context.Context, collectionName string, id string, data map[string]any) (_ map[string]any, opErr error) {
+	defer metrics.ObserveDBOperation("firestore", "update", collectionName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "firestore", "update", collectionName)
+	defer func() { span.End(opErr) }()
 	if collectionName == "" {
 		return nil, model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
 	}
@@ -154,7 +199,10 @@ func (f *FirestoreOperations) Update(ctx context.Context, collectionName string,
 }
 
 // Delete deletes a document from the specified collection (soft delete by default)
-func (f *FirestoreOperations) Delete(ctx context.Context, collectionName string, id string) error {
+func (f *FirestoreOperations) Delete(ctx context.Context, collectionName string, id string) (opErr error) {
+	defer metrics.ObserveDBOperation("firestore", "delete", collectionName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "firestore", "delete", collectionName)
+	defer func() { span.End(opErr) }()
 	if collectionName == "" {
 		return model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
 	}
@@ -198,7 +246,10 @@ func (f *FirestoreOperations) Delete(ctx context.Context, collectionName string,
 }
 
 // HardDelete permanently deletes a document from the specified collection
-func (f *FirestoreOperations) HardDelete(ctx context.Context, collectionName string, id string) error {
+func (f *FirestoreOperations) HardDelete(ctx context.Context, collectionName string, id string) (opErr error) {
+	defer metrics.ObserveDBOperation("firestore", "hard_delete", collectionName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "firestore", "hard_delete", collectionName)
+	defer func() { span.End(opErr) }()
 	if collectionName == "" {
 		return model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
 	}
@@ -234,22 +285,75 @@ func (f *FirestoreOperations) HardDelete(ctx context.Context, collectionName str
 	return nil
 }
 
+// Restore undoes a soft Delete by setting active back to true and stamping
+// date_modified. Restoring an active document is not an error.
+func (f *FirestoreOperations) Restore(ctx context.Context, collectionName string, id string) (opErr error) {
+	defer metrics.ObserveDBOperation("firestore", "restore", collectionName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "firestore", "restore", collectionName)
+	defer func() { span.End(opErr) }()
+	if collectionName == "" {
+		return model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
+	}
+	if id == "" {
+		return model.NewDatabaseError("document ID is required", "MISSING_DOCUMENT_ID", 400)
+	}
+
+	docRef := f.client.Collection(collectionName).Doc(id)
+
+	// Check if document exists
+	docSnap, err := docRef.Get(ctx)
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to get document: %v", err),
+			"FIRESTORE_READ_FAILED",
+			500,
+		)
+	}
+	if !docSnap.Exists() {
+		return model.NewDatabaseError("document not found", "DOCUMENT_NOT_FOUND", 404)
+	}
+
+	now := time.Now().UTC()
+	updateData := map[string]any{
+		"active":               true,
+		"date_modified":        now.UnixMilli(), // Store as int64 for protobuf
+		"date_modified_string": now.Format("2006-01-02T15:04:05.000Z"),
+	}
+
+	_, err = docRef.Set(ctx, updateData, firestore.MergeAll)
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to restore document: %v", err),
+			"FIRESTORE_RESTORE_FAILED",
+			500,
+		)
+	}
+
+	return nil
+}
+
+// ListDeleted lists soft-deleted documents (active == false) through List,
+// so filters, sort and pagination behave the same. Its queries need the same
+// composite indexes as List's, which already lead with active.
+func (f *FirestoreOperations) ListDeleted(ctx context.Context, collectionName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return f.List(ctx, collectionName, interfaces.DeletedParams(params))
+}
+
 // List retrieves documents from the specified collection with standardized params
-func (f *FirestoreOperations) List(ctx context.Context, collectionName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+func (f *FirestoreOperations) List(ctx context.Context, collectionName string, params *interfaces.ListParams) (_ *interfaces.ListResult, opErr error) {
+	defer metrics.ObserveDBOperation("firestore", "list", collectionName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "firestore", "list", collectionName)
+	defer func() { span.End(opErr) }()
 	if collectionName == "" {
 		return nil, model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
 	}
 
-	query := f.client.Collection(collectionName).Query
+	query := f.listQuery(collectionName, params)
+	pattern := ListPattern(collectionName, params, true)
+	defaultIndexAdvisor.Observe(pattern)
 
-	// Apply default active filter
-	query = query.Where("active", "==", true)
-
-	// Apply filters from FilterRequest
-	if params != nil && params.Filters != nil {
-		for _, filter := range params.Filters.Filters {
-			query = f.applyTypedFilter(query, filter)
-		}
+	if token, ok := interfaces.CursorRequest(params); ok {
+		return f.listByCursor(ctx, collectionName, query, params, token, pattern)
 	}
 
 	// Apply sorting from SortRequest
@@ -263,17 +367,13 @@ func (f *FirestoreOperations) List(ctx context.Context, collectionName string, p
 		}
 	}
 
-	// Get total count before pagination (for pagination response)
-	countQuery := query
-	allDocs, err := countQuery.Documents(ctx).GetAll()
+	// Get total count before pagination (for pagination response) with an
+	// aggregation query, so the matching documents are not read
+	count, err := f.countQuery(ctx, query, pattern)
 	if err != nil {
-		return nil, model.NewDatabaseError(
-			fmt.Sprintf("failed to count documents: %v", err),
-			"FIRESTORE_COUNT_FAILED",
-			500,
-		)
+		return nil, err
 	}
-	totalItems := int32(len(allDocs))
+	totalItems := int32(count)
 
 	// Apply pagination from PaginationRequest
 	limit := int32(100) // Default limit
@@ -299,10 +399,9 @@ func (f *FirestoreOperations) List(ctx context.Context, collectionName string, p
 	// Execute query
 	docs, err := query.Documents(ctx).GetAll()
 	if err != nil {
-		return nil, model.NewDatabaseError(
-			fmt.Sprintf("failed to list documents from collection '%s': %v", collectionName, err),
+		return nil, queryError(pattern, err,
+			fmt.Sprintf("failed to list documents from collection '%s'", collectionName),
 			"FIRESTORE_LIST_FAILED",
-			500,
 		)
 	}
 
@@ -336,6 +435,158 @@ func (f *FirestoreOperations) List(ctx context.Context, collectionName string, p
 	}, nil
 }
 
+// listByCursor serves a keyset page ordered by (date_created, document ID),
+// resuming with StartAfter from the token's position. One extra document is
+// fetched to learn whether another page follows. Combined with filters the
+// ordering needs a composite index ending in date_created and __name__
+// (pattern).
+func (f *FirestoreOperations) listByCursor(ctx context.Context, collectionName string, query firestore.Query, params *interfaces.ListParams, token string, pattern QueryPattern) (*interfaces.ListResult, error) {
+	descending, err := interfaces.CursorDescending(params)
+	if err != nil {
+		return nil, model.NewDatabaseError(err.Error(), "INVALID_CURSOR_SORT", 400)
+	}
+
+	count, err := f.countQuery(ctx, query, ListPattern(collectionName, params, false))
+	if err != nil {
+		return nil, err
+	}
+
+	direction := firestore.Desc
+	if !descending {
+		direction = firestore.Asc
+	}
+	query = query.OrderBy(interfaces.CursorKeyField, direction).OrderBy(firestore.DocumentID, direction)
+	if token != "" {
+		cursor, err := interfaces.DecodeCursor(token)
+		if err != nil {
+			return nil, model.NewDatabaseError(err.Error(), "INVALID_CURSOR", 400)
+		}
+		query = query.StartAfter(cursor.Key, cursor.ID)
+	}
+
+	limit := int32(100) // Default limit
+	if params.Pagination.Limit > 0 && params.Pagination.Limit <= 100 {
+		limit = params.Pagination.Limit
+	}
+
+	docs, err := query.Limit(int(limit) + 1).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, queryError(pattern, err,
+			fmt.Sprintf("failed to list documents from collection '%s'", collectionName),
+			"FIRESTORE_LIST_FAILED",
+		)
+	}
+	more := len(docs) > int(limit)
+	if more {
+		docs = docs[:limit]
+	}
+
+	var results []map[string]any
+	for _, doc := range docs {
+		data := doc.Data()
+		data["id"] = doc.Ref.ID
+		results = append(results, data)
+	}
+
+	var last map[string]any
+	if len(results) > 0 {
+		last = results[len(results)-1]
+	}
+	pagination, next, err := interfaces.CursorPagination(last, token, more, int32(count))
+	if err != nil {
+		return nil, model.NewDatabaseError(err.Error(), "FIRESTORE_LIST_FAILED", 500)
+	}
+	return &interfaces.ListResult{
+		Data:       results,
+		Total:      int32(count),
+		Pagination: pagination,
+		NextCursor: next,
+	}, nil
+}
+
+// Count returns the number of documents List would match for params using a
+// COUNT aggregation query, billed as one read per 1000 matches instead of
+// one per document.
+func (f *FirestoreOperations) Count(ctx context.Context, collectionName string, params *interfaces.ListParams) (_ int64, opErr error) {
+	defer metrics.ObserveDBOperation("firestore", "count", collectionName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "firestore", "count", collectionName)
+	defer func() { span.End(opErr) }()
+	if collectionName == "" {
+		return 0, model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
+	}
+
+	pattern := ListPattern(collectionName, params, false)
+	defaultIndexAdvisor.Observe(pattern)
+	return f.countQuery(ctx, f.listQuery(collectionName, params), pattern)
+}
+
+// listQuery builds the query shared by List and Count: active documents
+// matching the FilterRequest. An explicit "active" BooleanFilter replaces
+// the default active == true, as in the SQL backends, which is how
+// ListDeleted reaches inactive documents.
+func (f *FirestoreOperations) listQuery(collectionName string, params *interfaces.ListParams) firestore.Query {
+	query := f.client.Collection(collectionName).Query
+
+	// Apply default active filter
+	if !hasActiveFilter(params) {
+		query = query.Where("active", "==", true)
+	}
+
+	// Apply filters from FilterRequest
+	if params != nil && params.Filters != nil {
+		for _, filter := range params.Filters.Filters {
+			query = f.applyTypedFilter(query, filter)
+		}
+	}
+	return query
+}
+
+// hasActiveFilter reports whether params carry an explicit "active"
+// BooleanFilter.
+func hasActiveFilter(params *interfaces.ListParams) bool {
+	if params == nil || params.Filters == nil {
+		return false
+	}
+	for _, filter := range params.Filters.Filters {
+		if filter.GetField() == interfaces.ActiveField && filter.GetBooleanFilter() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// countQuery runs a COUNT aggregation over query, of the given pattern.
+func (f *FirestoreOperations) countQuery(ctx context.Context, query firestore.Query, pattern QueryPattern) (int64, error) {
+	result, err := query.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return 0, queryError(pattern, err, "failed to count documents", "FIRESTORE_COUNT_FAILED")
+	}
+	value, ok := result["total"].(*firestorepb.Value)
+	if !ok {
+		return 0, model.NewDatabaseError(
+			fmt.Sprintf("unexpected count aggregation result %T", result["total"]),
+			"FIRESTORE_COUNT_FAILED",
+			500,
+		)
+	}
+	return value.GetIntegerValue(), nil
+}
+
+// queryError wraps the failure of a query of the given pattern. A missing
+// composite index is reported to the index advisor, which logs the link
+// creating it, and is returned as FIRESTORE_INDEX_REQUIRED with the link.
+func queryError(pattern QueryPattern, err error, message, code string) error {
+	if link, ok := MissingIndexLink(err); ok {
+		defaultIndexAdvisor.Missing(pattern, link)
+		return model.NewDatabaseError(
+			fmt.Sprintf("%s: query on collection '%s' needs a composite index, create it at %s", message, pattern.Collection, link),
+			"FIRESTORE_INDEX_REQUIRED",
+			500,
+		)
+	}
+	return model.NewDatabaseError(fmt.Sprintf("%s: %v", message, err), code, 500)
+}
+
 // applyTypedFilter applies a TypedFilter to a Firestore query
 func (f *FirestoreOperations) applyTypedFilter(query firestore.Query, filter *commonpb.TypedFilter) firestore.Query {
 	field := filter.Field
@@ -412,7 +663,10 @@ func (f *FirestoreOperations) applySearch(query firestore.Query, search *commonp
 }
 
 // ListWithQuery provides advanced querying capabilities
-func (f *FirestoreOperations) ListWithQuery(ctx context.Context, collectionName string, queryBuilder func(firestore.Query) firestore.Query) ([]map[string]any, error) {
+func (f *FirestoreOperations) ListWithQuery(ctx context.Context, collectionName string, queryBuilder func(firestore.Query) firestore.Query) (_ []map[string]any, opErr error) {
+	defer metrics.ObserveDBOperation("firestore", "list", collectionName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "firestore", "list", collectionName)
+	defer func() { span.End(opErr) }()
 	if collectionName == "" {
 		return nil, model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
 	}
@@ -424,14 +678,11 @@ func (f *FirestoreOperations) ListWithQuery(ctx context.Context, collectionName
 		query = queryBuilder(query)
 	}
 
-	// Execute query
+	// Execute query; the builder's conditions are opaque, so only a
+	// missing index's link can be reported
 	docs, err := query.Documents(ctx).GetAll()
 	if err != nil {
-		return nil, model.NewDatabaseError(
-			fmt.Sprintf("failed to execute query: %v", err),
-			"FIRESTORE_QUERY_FAILED",
-			500,
-		)
+		return nil, queryError(QueryPattern{Collection: collectionName}, err, "failed to execute query", "FIRESTORE_QUERY_FAILED")
 	}
 
 	// Convert documents to map slice
@@ -446,7 +697,10 @@ func (f *FirestoreOperations) ListWithQuery(ctx context.Context, collectionName
 }
 
 // Query executes a structured query against the collection
-func (f *FirestoreOperations) Query(ctx context.Context, collectionName string, queryBuilder interfaces.QueryBuilder) ([]map[string]any, error) {
+func (f *FirestoreOperations) Query(ctx context.Context, collectionName string, queryBuilder interfaces.QueryBuilder) (_ []map[string]any, opErr error) {
+	defer metrics.ObserveDBOperation("firestore", "query", collectionName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "firestore", "query", collectionName)
+	defer func() { span.End(opErr) }()
 	if collectionName == "" {
 		return nil, model.NewDatabaseError("collection name is required", "MISSING_COLLECTION_NAME", 400)
 	}
@@ -486,14 +740,13 @@ func (f *FirestoreOperations) Query(ctx context.Context, collectionName string,
 		query = query.Limit(filter.Limit)
 	}
 
+	pattern := ConditionsPattern(collectionName, filter)
+	defaultIndexAdvisor.Observe(pattern)
+
 	// Execute query
 	docs, err := query.Documents(ctx).GetAll()
 	if err != nil {
-		return nil, model.NewDatabaseError(
-			fmt.Sprintf("failed to execute query: %v", err),
-			"FIRESTORE_QUERY_FAILED",
-			500,
-		)
+		return nil, queryError(pattern, err, "failed to execute query", "FIRESTORE_QUERY_FAILED")
 	}
 
 	// Convert documents to map slice
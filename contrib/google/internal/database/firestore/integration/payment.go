@@ -59,6 +59,7 @@ func (r *FirestoreIntegrationPaymentRepository) LogWebhook(ctx context.Context,
 
 	// Build document for Firestore
 	now := time.Now()
+	fees := integrationPorts.PaymentFeesFromRawData(data.Amount, data.Currency, data.RawData)
 	doc := map[string]any{
 		"id":                   id,
 		"payment_id":           data.PaymentId,
@@ -77,6 +78,9 @@ func (r *FirestoreIntegrationPaymentRepository) LogWebhook(ctx context.Context,
 		"active":               true,
 		"date_created":         now.Unix(),
 		"received_at":          now,
+		"gross_amount":         fees.Gross,
+		"fee_amount":           fees.Fee,
+		"net_amount":           fees.Net,
 	}
 
 	// Create document using common operations
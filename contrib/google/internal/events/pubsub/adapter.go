@@ -0,0 +1,143 @@
+// Package pubsub publishes domain events to a Google Cloud Pub/Sub topic,
+// authenticating like the other GOOGLE_ adapters (service account
+// settings or Application Default Credentials).
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"google.golang.org/api/option"
+
+	"github.com/erniealice/espyna-golang/contrib/google/internal/common/gcp"
+	"github.com/erniealice/espyna-golang/ports"
+	"github.com/erniealice/espyna-golang/registry"
+)
+
+// =============================================================================
+// Self-Registration - Adapter registers itself with the factory
+// =============================================================================
+
+func init() {
+	// Registered name matches CONFIG_EVENTS_PROVIDER=pubsub and the build tag
+	// (pubsub). See register_pubsub.go for the //go:build pubsub constraint.
+	registry.RegisterEventPublisherBuildFromEnv("pubsub", buildFromEnv)
+}
+
+// buildFromEnv creates a publisher on CONFIG_EVENTS_PUBSUB_TOPIC in
+// CONFIG_EVENTS_PUBSUB_PROJECT_ID (default GOOGLE_CLOUD_PROJECT_ID).
+func buildFromEnv() (ports.EventPublisher, error) {
+	credConfig := gcp.DefaultCredentialConfig("GOOGLE_")
+	if projectID := strings.TrimSpace(os.Getenv("CONFIG_EVENTS_PUBSUB_PROJECT_ID")); projectID != "" {
+		credConfig.ProjectID = projectID
+	}
+	if err := credConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("pubsub: invalid credential config: %w", err)
+	}
+	topicID := strings.TrimSpace(os.Getenv("CONFIG_EVENTS_PUBSUB_TOPIC"))
+	if topicID == "" {
+		return nil, fmt.Errorf("CONFIG_EVENTS_PUBSUB_TOPIC is required for the pubsub event publisher")
+	}
+	var ordered bool
+	if raw := strings.TrimSpace(os.Getenv("CONFIG_EVENTS_PUBSUB_ORDERED")); raw != "" {
+		var err error
+		if ordered, err = strconv.ParseBool(raw); err != nil {
+			return nil, fmt.Errorf("CONFIG_EVENTS_PUBSUB_ORDERED=%q is not true or false", raw)
+		}
+	}
+
+	opt, err := gcp.GetClientOption(credConfig)
+	if err != nil {
+		return nil, fmt.Errorf("pubsub: failed to get client option: %w", err)
+	}
+	var opts []option.ClientOption
+	if opt != nil {
+		opts = append(opts, opt)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return NewPublisher(ctx, credConfig.ProjectID, topicID, ordered, opts...)
+}
+
+// =============================================================================
+// Adapter Implementation
+// =============================================================================
+
+// Publisher sends each event as one Pub/Sub message: the JSON body as data
+// and the event ID, type and workspace as attributes, so subscriptions can
+// filter with attributes.event_type = "invoice.paid". With ordering on,
+// the workspace is the ordering key.
+type Publisher struct {
+	client  *pubsub.Client
+	topic   *pubsub.Topic
+	ordered bool
+}
+
+var _ ports.EventPublisher = (*Publisher)(nil)
+
+// NewPublisher connects to topicID in projectID. The topic must exist;
+// the publisher does not create it.
+func NewPublisher(ctx context.Context, projectID, topicID string, ordered bool, opts ...option.ClientOption) (*Publisher, error) {
+	client, err := pubsub.NewClient(ctx, projectID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("pubsub: failed to create client: %w", err)
+	}
+	topic := client.Topic(topicID)
+	exists, err := topic.Exists(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("pubsub: failed to check topic %s: %w", topicID, err)
+	}
+	if !exists {
+		client.Close()
+		return nil, fmt.Errorf("pubsub: topic %s does not exist in project %s", topicID, projectID)
+	}
+	topic.EnableMessageOrdering = ordered
+
+	log.Printf("✅ Pub/Sub event publisher initialized (topic: %s, project: %s)", topicID, projectID)
+	return &Publisher{client: client, topic: topic, ordered: ordered}, nil
+}
+
+// Name returns the provider name
+func (p *Publisher) Name() string {
+	return "pubsub"
+}
+
+// Publish waits for the server to acknowledge msg. The client batches and
+// retries on its own.
+func (p *Publisher) Publish(ctx context.Context, msg ports.EventMessage) error {
+	message := &pubsub.Message{
+		Data: msg.Data,
+		Attributes: map[string]string{
+			"event_id":     msg.ID,
+			"event_type":   msg.Type,
+			"workspace_id": msg.WorkspaceID,
+			"occurred_at":  msg.OccurredAt.UTC().Format(time.RFC3339Nano),
+		},
+	}
+	if p.ordered {
+		message.OrderingKey = msg.WorkspaceID
+	}
+	if _, err := p.topic.Publish(ctx, message).Get(ctx); err != nil {
+		if p.ordered {
+			// A failed publish pauses its ordering key; later events of the
+			// workspace go out once it resumes.
+			p.topic.ResumePublish(message.OrderingKey)
+		}
+		return fmt.Errorf("pubsub: publish %s event %s: %w", msg.Type, msg.ID, err)
+	}
+	return nil
+}
+
+// Close sends the batched messages and closes the client
+func (p *Publisher) Close() error {
+	p.topic.Stop()
+	return p.client.Close()
+}
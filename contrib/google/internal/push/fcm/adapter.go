@@ -0,0 +1,132 @@
+// Package fcm sends push notifications through Firebase Cloud Messaging,
+// reusing the FIREBASE_ service account credentials of the other Firebase
+// adapters. FCM reaches both Android and iOS devices.
+package fcm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"firebase.google.com/go/v4/messaging"
+
+	firebaseCommon "github.com/erniealice/espyna-golang/contrib/google/internal/common/firebase"
+	"github.com/erniealice/espyna-golang/ports"
+	"github.com/erniealice/espyna-golang/registry"
+)
+
+// =============================================================================
+// Self-Registration - Adapter registers itself with the factory
+// =============================================================================
+
+func init() {
+	registry.RegisterPushProviderFactory("google_fcm", func() ports.PushProvider {
+		return &FCMPushAdapter{}
+	})
+	registry.RegisterPushBuildFromEnv("google_fcm", buildFromEnv)
+}
+
+// buildFromEnv creates an FCM provider from the shared FIREBASE_ credentials.
+func buildFromEnv() (ports.PushProvider, error) {
+	p := &FCMPushAdapter{timeout: 30 * time.Second}
+	if err := p.initialize(); err != nil {
+		return nil, fmt.Errorf("google_fcm: failed to initialize: %w", err)
+	}
+	return p, nil
+}
+
+// =============================================================================
+// Adapter Implementation
+// =============================================================================
+
+// FCMPushAdapter implements ports.PushProvider with Firebase Cloud Messaging.
+type FCMPushAdapter struct {
+	enabled       bool
+	timeout       time.Duration
+	clientManager *firebaseCommon.FirebaseClientManager
+	client        *messaging.Client
+}
+
+var _ ports.PushProvider = (*FCMPushAdapter)(nil)
+
+func (p *FCMPushAdapter) initialize() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	manager, err := firebaseCommon.NewFirebaseClientManager(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to initialize Firebase client manager: %w", err)
+	}
+	client, err := manager.GetMessagingClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	p.clientManager = manager
+	p.client = client
+	p.enabled = true
+	log.Printf("✅ FCM push provider initialized (project: %s)", manager.GetProjectID())
+	return nil
+}
+
+// Name returns the provider name
+func (p *FCMPushAdapter) Name() string {
+	return "google_fcm"
+}
+
+// Platforms returns Android and iOS; FCM relays iOS messages through APNs.
+func (p *FCMPushAdapter) Platforms() []ports.PushPlatform {
+	return []ports.PushPlatform{ports.PushPlatformAndroid, ports.PushPlatformIOS}
+}
+
+// IsEnabled returns whether the provider is initialized
+func (p *FCMPushAdapter) IsEnabled() bool {
+	return p.enabled
+}
+
+// Close releases the Firebase clients
+func (p *FCMPushAdapter) Close() error {
+	p.enabled = false
+	if p.clientManager != nil {
+		return p.clientManager.Close()
+	}
+	return nil
+}
+
+// SendPush delivers msg to one device token.
+func (p *FCMPushAdapter) SendPush(ctx context.Context, msg *ports.PushMessage) error {
+	if !p.enabled || p.client == nil {
+		return fmt.Errorf("google_fcm: provider is not enabled")
+	}
+
+	message := &messaging.Message{
+		Token: msg.Token,
+		Notification: &messaging.Notification{
+			Title: msg.Title,
+			Body:  msg.Body,
+		},
+		Data: msg.Data,
+	}
+	if msg.Badge > 0 {
+		badge := msg.Badge
+		message.APNS = &messaging.APNSConfig{
+			Payload: &messaging.APNSPayload{Aps: &messaging.Aps{Badge: &badge}},
+		}
+	}
+
+	if _, err := p.client.Send(ctx, message); err != nil {
+		if messaging.IsUnregistered(err) || messaging.IsSenderIDMismatch(err) || isInvalidToken(err) {
+			return fmt.Errorf("google_fcm: %v: %w", err, ports.ErrPushTokenInvalid)
+		}
+		return fmt.Errorf("google_fcm: send: %w", err)
+	}
+	return nil
+}
+
+// isInvalidToken reports an INVALID_ARGUMENT caused by a malformed token,
+// as opposed to a malformed message.
+func isInvalidToken(err error) bool {
+	return messaging.IsInvalidArgument(err) && strings.Contains(err.Error(), "registration token")
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"mime"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,8 +27,8 @@ import (
 // =============================================================================
 
 func init() {
-	// Registered name matches CONFIG_STORAGE_PROVIDER=gcs and the build tag (gcs).
-	// See register_gcs.go for the //go:build gcs constraint.
+	// Registered name matches CONFIG_STORAGE_PROVIDER=gcs and the build tag (gcs,
+	// or gcs_storage). See register_gcs.go for the //go:build constraint.
 	registry.RegisterStorageProvider(
 		"gcs",
 		func() ports.StorageProvider {
@@ -38,10 +40,34 @@ func init() {
 }
 
 // buildFromEnv creates and initializes a GCS storage provider from environment variables.
+//
+// Credentials come from the shared gcp package (GOOGLE_ prefix). Signed URLs:
+// GOOGLE_STORAGE_SIGNED_URL_TTL is the default lifetime (15m) of a URL whose
+// request asks for none; GOOGLE_STORAGE_SIGNER_EMAIL names the service account
+// to sign as through the IAM API when the credentials hold no private key (e.g.
+// on Cloud Run). GOOGLE_STORAGE_TEMP_TTL_DAYS, when set, adds a lifecycle rule
+// deleting objects under GOOGLE_STORAGE_TEMP_PREFIX (tmp/) after that many days.
 func buildFromEnv() (ports.StorageProvider, error) {
 	bucketName := os.Getenv("GOOGLE_CLOUD_STORAGE_BUCKET_NAME")
 	projectId := os.Getenv("GOOGLE_CLOUD_PROJECT_ID")
 
+	signedURLTTL := defaultSignedURLTTL
+	if v := os.Getenv("GOOGLE_STORAGE_SIGNED_URL_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 || ttl > maxSignedURLTTL {
+			return nil, fmt.Errorf("gcs: GOOGLE_STORAGE_SIGNED_URL_TTL must be a duration up to %s, got %q", maxSignedURLTTL, v)
+		}
+		signedURLTTL = ttl
+	}
+	var tempTTLDays int64
+	if v := os.Getenv("GOOGLE_STORAGE_TEMP_TTL_DAYS"); v != "" {
+		days, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || days < 1 {
+			return nil, fmt.Errorf("gcs: GOOGLE_STORAGE_TEMP_TTL_DAYS must be a positive number of days, got %q", v)
+		}
+		tempTTLDays = days
+	}
+
 	protoConfig := &pb.StorageProviderConfig{
 		Provider: pb.StorageProvider_STORAGE_PROVIDER_GCP,
 		Config: &pb.StorageProviderConfig_GcsConfig{
@@ -51,10 +77,23 @@ func buildFromEnv() (ports.StorageProvider, error) {
 			},
 		},
 	}
-	p := NewGCSStorageProvider()
+	p := NewGCSStorageProvider().(*GCSStorageProvider)
+	p.signedURLTTL = signedURLTTL
+	p.signerEmail = os.Getenv("GOOGLE_STORAGE_SIGNER_EMAIL")
 	if err := p.Initialize(protoConfig); err != nil {
 		return nil, fmt.Errorf("gcs: failed to initialize: %w", err)
 	}
+	if tempTTLDays > 0 {
+		prefix := os.Getenv("GOOGLE_STORAGE_TEMP_PREFIX")
+		if prefix == "" {
+			prefix = DefaultTempPrefix
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+		defer cancel()
+		if err := p.EnsureTempLifecycle(ctx, bucketName, prefix, tempTTLDays); err != nil {
+			return nil, fmt.Errorf("gcs: %w", err)
+		}
+	}
 	return p, nil
 }
 
@@ -76,13 +115,24 @@ type GCSStorageProvider struct {
 	enabled       bool
 	timeout       time.Duration
 	clientManager *google.GoogleClientManager
+	// signedURLTTL is the lifetime of a signed URL whose request sets none;
+	// signerEmail, when set, is the service account URLs are signed as.
+	signedURLTTL time.Duration
+	signerEmail  string
 }
 
+// Signed URL lifetimes. V4 signatures are valid for at most seven days.
+const (
+	defaultSignedURLTTL = 15 * time.Minute
+	maxSignedURLTTL     = 7 * 24 * time.Hour
+)
+
 // NewGCSStorageProvider creates a new Google Cloud Storage provider
 func NewGCSStorageProvider() ports.StorageProvider {
 	return &GCSStorageProvider{
-		enabled: false,
-		timeout: 30 * time.Second,
+		enabled:      false,
+		timeout:      30 * time.Second,
+		signedURLTTL: defaultSignedURLTTL,
 	}
 }
 
@@ -206,10 +256,8 @@ func (p *GCSStorageProvider) UploadObject(ctx context.Context, req *pb.UploadObj
 	}
 	writer.ContentType = contentType
 
-	// Set metadata
-	if len(req.Metadata) > 0 {
-		writer.Metadata = req.Metadata
-	}
+	// Set metadata, tagged with the workspace and owning entity
+	writer.Metadata = storagecommon.TagMetadata(ctx, req.Metadata)
 
 	// Write data
 	if _, err := writer.Write(req.Content); err != nil {
@@ -359,39 +407,58 @@ func (p *GCSStorageProvider) GetPresignedUrl(ctx context.Context, req *pb.GetPre
 		}, ports.NewStorageError(ports.StorageErrorCodeProviderError, "not initialized", nil)
 	}
 
-	// Note: Generating signed URLs requires service account credentials
 	bucketName := req.ContainerName
 	if bucketName == "" {
 		bucketName = p.bucketName
 	}
 
 	objectKey := strings.Trim(req.ObjectKey, "/")
-	expiresIn := time.Duration(req.ExpiresInSeconds) * time.Second
-	expiresAt := time.Now().Add(expiresIn)
-
-	// Determine HTTP method based on operation
-	method := "GET"
-	if req.Operation == pb.PresignedUrlOperation_PRESIGNED_URL_OPERATION_UPLOAD {
-		method = "PUT"
-	} else if req.Operation == pb.PresignedUrlOperation_PRESIGNED_URL_OPERATION_DELETE {
-		method = "DELETE"
+	expiresIn := p.signedURLTTL
+	if req.ExpiresInSeconds > 0 {
+		expiresIn = time.Duration(req.ExpiresInSeconds) * time.Second
 	}
-
-	// Create SignedURLOptions for GCS
-	opts := &storage.SignedURLOptions{
-		Method:  method,
-		Expires: expiresAt,
+	if expiresIn > maxSignedURLTTL {
+		expiresIn = maxSignedURLTTL
 	}
+	expiresAt := time.Now().Add(expiresIn)
 
-	// Add content type for uploads
-	if req.Operation == pb.PresignedUrlOperation_PRESIGNED_URL_OPERATION_UPLOAD && req.ContentType != "" {
+	// V4 signing. BucketHandle.SignedURL takes the signer from the client's
+	// credentials (a service account key) or, with only GoogleAccessID set, signs
+	// through the IAM Credentials API — so ADC on Cloud Run/GKE works once the
+	// runtime account may act as the signer.
+	opts := &storage.SignedURLOptions{
+		Scheme:         storage.SigningSchemeV4,
+		Expires:        expiresAt,
+		GoogleAccessID: p.signerEmail,
+	}
+
+	switch req.Operation {
+	case pb.PresignedUrlOperation_PRESIGNED_URL_OPERATION_DOWNLOAD:
+		opts.Method = "GET"
+		// Pin the disposition and type into the signature, as the S3 adapter
+		// does: the object always downloads as an attachment under the
+		// server-derived type, whatever was stored on it.
+		opts.QueryParameters = url.Values{
+			"response-content-disposition": {fmt.Sprintf("attachment; filename=%q", storagecommon.SanitizeDownloadFilename(req.Filename, objectKey))},
+		}
+		if req.ContentType != "" {
+			opts.QueryParameters.Set("response-content-type", req.ContentType)
+		}
+	case pb.PresignedUrlOperation_PRESIGNED_URL_OPERATION_UPLOAD:
+		opts.Method = "PUT"
+		// The client must send exactly this Content-Type.
 		opts.ContentType = req.ContentType
+	case pb.PresignedUrlOperation_PRESIGNED_URL_OPERATION_DELETE:
+		opts.Method = "DELETE"
+	default:
+		return &pb.GetPresignedUrlResponse{
+			Success: false,
+			Message: "unsupported operation",
+		}, ports.NewStorageError(ports.StorageErrorCodeProviderError, "unsupported operation", nil)
 	}
 
-	// Note: SignedURL requires credentials to be set up properly
-	// For now, return not supported error if we can't generate signed URLs
-	// This typically requires service account credentials
-	url, err := storage.SignedURL(bucketName, objectKey, opts)
+	client := p.clientManager.GetStorageClient()
+	signedURL, err := client.Bucket(bucketName).SignedURL(objectKey, opts)
 	if err != nil {
 		return &pb.GetPresignedUrlResponse{
 			Success: false,
@@ -401,9 +468,9 @@ func (p *GCSStorageProvider) GetPresignedUrl(ctx context.Context, req *pb.GetPre
 
 	return &pb.GetPresignedUrlResponse{
 		Success:    true,
-		Url:        url,
+		Url:        signedURL,
 		ExpiresAt:  timestamppb.New(expiresAt),
-		HttpMethod: method,
+		HttpMethod: opts.Method,
 		Message:    "signed URL generated successfully",
 	}, nil
 }
@@ -538,6 +605,29 @@ func (p *GCSStorageProvider) DeleteContainer(ctx context.Context, req *pb.Delete
 	}, nil
 }
 
+// DeleteObject deletes an object. A missing object is not an error.
+func (p *GCSStorageProvider) DeleteObject(ctx context.Context, containerName, objectKey string) error {
+	if !p.enabled {
+		return ports.NewStorageError(ports.StorageErrorCodeProviderError, "not initialized", nil)
+	}
+	objectKey = strings.Trim(objectKey, "/")
+	if objectKey == "" {
+		return ports.NewStorageError(ports.StorageErrorCodeInvalidPath, "object key required", nil)
+	}
+	bucketName := containerName
+	if bucketName == "" {
+		bucketName = p.bucketName
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	err := p.clientManager.GetStorageClient().Bucket(bucketName).Object(objectKey).Delete(deleteCtx)
+	if err != nil && err != storage.ErrObjectNotExist {
+		return ports.NewStorageError(ports.StorageErrorCodeDeleteFailed, "delete object failed", err)
+	}
+	return nil
+}
+
 // IsHealthy checks if the GCS storage service is available
 func (p *GCSStorageProvider) IsHealthy(ctx context.Context) error {
 	if !p.enabled {
@@ -573,8 +663,9 @@ func (p *GCSStorageProvider) IsEnabled() bool {
 
 // Compile-time assertions that GCS implements both optional sub-interfaces.
 var (
-	_ ports.StreamingStorageProvider  = (*GCSStorageProvider)(nil)
-	_ ports.StorageCapabilityProvider = (*GCSStorageProvider)(nil)
+	_ ports.StreamingStorageProvider      = (*GCSStorageProvider)(nil)
+	_ ports.StorageCapabilityProvider     = (*GCSStorageProvider)(nil)
+	_ ports.ObjectDeletingStorageProvider = (*GCSStorageProvider)(nil)
 )
 
 // UploadStream streams body to GCS via ObjectHandle.NewWriter + io.Copy. The GCS
@@ -604,13 +695,12 @@ func (p *GCSStorageProvider) UploadStream(ctx context.Context, req *pb.UploadObj
 	}
 	objectKey := strings.Trim(req.ObjectKey, "/")
 
-	uploadCtx, cancel := context.WithTimeout(ctx, p.timeout)
-	defer cancel()
-
+	// NOTE: no per-call timeout on the writer — a large stream may outlive
+	// p.timeout. The parent ctx governs cancellation, as in DownloadStream.
 	client := p.clientManager.GetStorageClient()
 	obj := client.Bucket(bucketName).Object(objectKey)
 
-	writer := obj.NewWriter(uploadCtx)
+	writer := obj.NewWriter(ctx)
 	contentType := req.ContentType
 	if contentType == "" {
 		contentType = mime.TypeByExtension(filepath.Ext(req.ObjectKey))
@@ -619,9 +709,7 @@ func (p *GCSStorageProvider) UploadStream(ctx context.Context, req *pb.UploadObj
 		}
 	}
 	writer.ContentType = contentType
-	if len(req.Metadata) > 0 {
-		writer.Metadata = req.Metadata
-	}
+	writer.Metadata = storagecommon.TagMetadata(ctx, req.Metadata)
 
 	// io.Copy streams body -> GCS in bounded chunks. On a copy error we still
 	// Close() to release the writer's resources, then surface the copy error.
@@ -639,7 +727,9 @@ func (p *GCSStorageProvider) UploadStream(ctx context.Context, req *pb.UploadObj
 		}, ports.NewStorageError(ports.StorageErrorCodeUploadFailed, "finalize failed", err)
 	}
 
-	attrs, _ := obj.Attrs(uploadCtx)
+	attrsCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	attrs, _ := obj.Attrs(attrsCtx)
 	storageObject := &pb.StorageObject{
 		Id:            storagecommon.GenerateObjectID(bucketName, objectKey),
 		Provider:      pb.StorageProvider_STORAGE_PROVIDER_GCP,
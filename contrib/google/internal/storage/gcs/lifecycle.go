@@ -0,0 +1,82 @@
+package gcs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/storage"
+)
+
+// =============================================================================
+// Lifecycle rules for temporary objects
+// =============================================================================
+
+// DefaultTempPrefix is where short-lived objects go: upload staging, generated
+// exports, previews. A lifecycle rule, not the application, deletes them.
+const DefaultTempPrefix = "tmp/"
+
+// TempObjectKey returns the key of a temporary object under prefix (the default
+// when empty).
+func TempObjectKey(prefix, key string) string {
+	if prefix == "" {
+		prefix = DefaultTempPrefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimLeft(key, "/")
+}
+
+// EnsureTempLifecycle makes bucket delete objects under prefix ageDays after
+// they are created. The bucket's other rules are kept; a rule for the same
+// prefix with another age is replaced. It is a no-op when the rule is in place.
+func (p *GCSStorageProvider) EnsureTempLifecycle(ctx context.Context, bucket, prefix string, ageDays int64) error {
+	if !p.enabled {
+		return fmt.Errorf("GCS storage provider is not initialized")
+	}
+	if prefix == "" || ageDays < 1 {
+		return fmt.Errorf("a temp lifecycle rule needs a prefix and an age of at least one day")
+	}
+	handle := p.clientManager.GetStorageClient().Bucket(bucket)
+	attrs, err := handle.Attrs(ctx)
+	if err != nil {
+		return fmt.Errorf("read lifecycle of bucket %s: %w", bucket, err)
+	}
+	lifecycle, changed := withTempRule(attrs.Lifecycle, prefix, ageDays)
+	if !changed {
+		return nil
+	}
+	if _, err := handle.Update(ctx, storage.BucketAttrsToUpdate{Lifecycle: &lifecycle}); err != nil {
+		return fmt.Errorf("update lifecycle of bucket %s: %w", bucket, err)
+	}
+	return nil
+}
+
+// withTempRule returns lc with a delete rule for objects under prefix older than
+// ageDays, and whether that changed it.
+func withTempRule(lc storage.Lifecycle, prefix string, ageDays int64) (storage.Lifecycle, bool) {
+	rule := storage.LifecycleRule{
+		Action:    storage.LifecycleAction{Type: storage.DeleteAction},
+		Condition: storage.LifecycleCondition{AgeInDays: ageDays, MatchesPrefix: []string{prefix}},
+	}
+	rules := make([]storage.LifecycleRule, 0, len(lc.Rules)+1)
+	for _, r := range lc.Rules {
+		if isTempRule(r, prefix) {
+			if r.Condition.AgeInDays == ageDays {
+				return lc, false
+			}
+			continue
+		}
+		rules = append(rules, r)
+	}
+	lc.Rules = append(rules, rule)
+	return lc, true
+}
+
+// isTempRule reports whether r is a plain delete-by-age rule for prefix.
+func isTempRule(r storage.LifecycleRule, prefix string) bool {
+	c := r.Condition
+	return r.Action.Type == storage.DeleteAction && c.Liveness == storage.LiveAndArchived &&
+		len(c.MatchesPrefix) == 1 && c.MatchesPrefix[0] == prefix &&
+		len(c.MatchesSuffix) == 0 && len(c.MatchesStorageClasses) == 0 &&
+		c.CreatedBefore.IsZero() && c.CustomTimeBefore.IsZero() && c.NoncurrentTimeBefore.IsZero() &&
+		c.NumNewerVersions == 0 && c.DaysSinceCustomTime == 0 && c.DaysSinceNoncurrentTime == 0
+}
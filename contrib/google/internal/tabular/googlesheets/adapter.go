@@ -16,6 +16,9 @@ import (
 	"github.com/erniealice/espyna-golang/contrib/google/internal/common/google"
 	"github.com/erniealice/espyna-golang/ports/integration"
 	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/metrics"
+	"github.com/erniealice/espyna-golang/shared/tracing"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
 	tabularpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/tabular"
 )
@@ -138,7 +141,7 @@ type GoogleSheetsProvider struct {
 func NewGoogleSheetsProvider() *GoogleSheetsProvider {
 	return &GoogleSheetsProvider{
 		timeout: 30 * time.Second,
-		logger:  slog.Default().With("provider", "google_sheets"),
+		logger:  slog.New(correlation.NewHandler(slog.Default().Handler())).With("provider", "google_sheets"),
 	}
 }
 
@@ -269,8 +272,32 @@ func (p *GoogleSheetsProvider) GetProviderType() tabularpb.TabularProviderType {
 // Core CRUD Operations
 // =============================================================================
 
+// startCall traces a Sheets call as a client span.
+func startCall(ctx context.Context, operation, sheet string) (context.Context, tracing.Span) {
+	return tracing.StartClient(ctx, "google_sheets "+operation,
+		tracing.String("tabular.provider", "google_sheets"),
+		tracing.String("tabular.operation", operation),
+		tracing.String("tabular.table", sheet),
+	)
+}
+
+// observe records a Sheets call in the tabular operation metrics, so slow
+// sheets show up next to slow database tables, and ends its span.
+func observe(span tracing.Span, operation, sheet string, start time.Time, success bool, err error) {
+	metrics.ObserveTabularOperation("google_sheets", operation, sheet, start, err != nil || !success)
+	if err == nil && !success {
+		err = fmt.Errorf("google sheets %s failed", operation)
+	}
+	span.End(err)
+}
+
 // ReadRecords reads records from a Google Sheets spreadsheet
-func (p *GoogleSheetsProvider) ReadRecords(ctx context.Context, req *tabularpb.ReadRecordsRequest) (*tabularpb.ReadRecordsResponse, error) {
+func (p *GoogleSheetsProvider) ReadRecords(ctx context.Context, req *tabularpb.ReadRecordsRequest) (reply *tabularpb.ReadRecordsResponse, opErr error) {
+	ctx, span := startCall(ctx, "read", req.GetData().GetSelection().GetTable())
+	defer func(start time.Time) {
+		observe(span, "read", req.GetData().GetSelection().GetTable(), start, reply.GetSuccess(), opErr)
+	}(time.Now())
+
 	if !p.IsEnabled() {
 		return &tabularpb.ReadRecordsResponse{
 			Success: false,
@@ -296,6 +323,36 @@ func (p *GoogleSheetsProvider) ReadRecords(ctx context.Context, req *tabularpb.R
 	service := p.clientManager.GetService()
 	p.mu.RUnlock()
 
+	// A paginated read without a sort fetches only its page
+	if windowed(data) {
+		window := newReadWindow(data.Selection)
+		result, err := p.fetchWindow(ctx, service, data.SourceId, window)
+		if err != nil {
+			p.logger.ErrorContext(ctx, "Failed to read from Google Sheets", "error", err, "source_id", data.SourceId, "range", window.pageRange())
+			return &tabularpb.ReadRecordsResponse{
+				Success: false,
+				Error: &commonpb.Error{
+					Code:    "READ_FAILED",
+					Message: fmt.Sprintf("Failed to read from Google Sheets: %v", err),
+				},
+			}, nil
+		}
+		if data.IncludeSchema {
+			if schema, err := p.fetchSchema(ctx, service, data.SourceId, data.Selection.GetTable()); err == nil {
+				result.Schema = schema
+			}
+		}
+		p.logger.InfoContext(ctx, "Read records from Google Sheets",
+			"source_id", data.SourceId,
+			"range", window.pageRange(),
+			"count", len(result.Records),
+		)
+		return &tabularpb.ReadRecordsResponse{
+			Success: true,
+			Data:    []*tabularpb.ReadRecordsResult{result},
+		}, nil
+	}
+
 	// Build A1 notation from selection
 	a1Range := selectionToA1Notation(data.Selection)
 
@@ -306,7 +363,7 @@ func (p *GoogleSheetsProvider) ReadRecords(ctx context.Context, req *tabularpb.R
 		Context(ctx).
 		Do()
 	if err != nil {
-		p.logger.Error("Failed to read from Google Sheets", "error", err, "source_id", data.SourceId, "range", a1Range)
+		p.logger.ErrorContext(ctx, "Failed to read from Google Sheets", "error", err, "source_id", data.SourceId, "range", a1Range)
 		return &tabularpb.ReadRecordsResponse{
 			Success: false,
 			Error: &commonpb.Error{
@@ -365,7 +422,7 @@ func (p *GoogleSheetsProvider) ReadRecords(ctx context.Context, req *tabularpb.R
 		}
 	}
 
-	p.logger.Info("Read records from Google Sheets",
+	p.logger.InfoContext(ctx, "Read records from Google Sheets",
 		"source_id", data.SourceId,
 		"range", a1Range,
 		"count", len(paginatedRecords),
@@ -378,7 +435,12 @@ func (p *GoogleSheetsProvider) ReadRecords(ctx context.Context, req *tabularpb.R
 }
 
 // WriteRecords writes new records to a Google Sheets spreadsheet
-func (p *GoogleSheetsProvider) WriteRecords(ctx context.Context, req *tabularpb.WriteRecordsRequest) (*tabularpb.WriteRecordsResponse, error) {
+func (p *GoogleSheetsProvider) WriteRecords(ctx context.Context, req *tabularpb.WriteRecordsRequest) (reply *tabularpb.WriteRecordsResponse, opErr error) {
+	ctx, span := startCall(ctx, "write", req.GetData().GetTable())
+	defer func(start time.Time) {
+		observe(span, "write", req.GetData().GetTable(), start, reply.GetSuccess(), opErr)
+	}(time.Now())
+
 	if !p.IsEnabled() {
 		return &tabularpb.WriteRecordsResponse{
 			Success: false,
@@ -439,7 +501,7 @@ func (p *GoogleSheetsProvider) WriteRecords(ctx context.Context, req *tabularpb.
 	}
 
 	if err != nil {
-		p.logger.Error("Failed to write to Google Sheets", "error", err, "source_id", data.SourceId)
+		p.logger.ErrorContext(ctx, "Failed to write to Google Sheets", "error", err, "source_id", data.SourceId)
 		return &tabularpb.WriteRecordsResponse{
 			Success: false,
 			Error: &commonpb.Error{
@@ -462,7 +524,7 @@ func (p *GoogleSheetsProvider) WriteRecords(ctx context.Context, req *tabularpb.
 		result.WrittenRecords = data.Records
 	}
 
-	p.logger.Info("Wrote records to Google Sheets",
+	p.logger.InfoContext(ctx, "Wrote records to Google Sheets",
 		"source_id", data.SourceId,
 		"table", tableName,
 		"count", len(data.Records),
@@ -475,7 +537,12 @@ func (p *GoogleSheetsProvider) WriteRecords(ctx context.Context, req *tabularpb.
 }
 
 // UpdateRecords updates existing records in a Google Sheets spreadsheet
-func (p *GoogleSheetsProvider) UpdateRecords(ctx context.Context, req *tabularpb.UpdateRecordsRequest) (*tabularpb.UpdateRecordsResponse, error) {
+func (p *GoogleSheetsProvider) UpdateRecords(ctx context.Context, req *tabularpb.UpdateRecordsRequest) (reply *tabularpb.UpdateRecordsResponse, opErr error) {
+	ctx, span := startCall(ctx, "update", req.GetData().GetSelection().GetTable())
+	defer func(start time.Time) {
+		observe(span, "update", req.GetData().GetSelection().GetTable(), start, reply.GetSuccess(), opErr)
+	}(time.Now())
+
 	if !p.IsEnabled() {
 		return &tabularpb.UpdateRecordsResponse{
 			Success: false,
@@ -510,7 +577,7 @@ func (p *GoogleSheetsProvider) UpdateRecords(ctx context.Context, req *tabularpb
 		Context(ctx).
 		Do()
 	if err != nil {
-		p.logger.Error("Failed to read for update", "error", err, "source_id", data.SourceId)
+		p.logger.ErrorContext(ctx, "Failed to read for update", "error", err, "source_id", data.SourceId)
 		return &tabularpb.UpdateRecordsResponse{
 			Success: false,
 			Error: &commonpb.Error{
@@ -562,7 +629,7 @@ func (p *GoogleSheetsProvider) UpdateRecords(ctx context.Context, req *tabularpb
 			Context(ctx).
 			Do()
 		if err != nil {
-			p.logger.Error("Failed to update records", "error", err, "source_id", data.SourceId)
+			p.logger.ErrorContext(ctx, "Failed to update records", "error", err, "source_id", data.SourceId)
 			return &tabularpb.UpdateRecordsResponse{
 				Success: false,
 				Error: &commonpb.Error{
@@ -573,7 +640,7 @@ func (p *GoogleSheetsProvider) UpdateRecords(ctx context.Context, req *tabularpb
 		}
 	}
 
-	p.logger.Info("Updated records in Google Sheets",
+	p.logger.InfoContext(ctx, "Updated records in Google Sheets",
 		"source_id", data.SourceId,
 		"matched", recordsMatched,
 		"updated", recordsUpdated,
@@ -591,7 +658,12 @@ func (p *GoogleSheetsProvider) UpdateRecords(ctx context.Context, req *tabularpb
 }
 
 // DeleteRecords deletes records from a Google Sheets spreadsheet
-func (p *GoogleSheetsProvider) DeleteRecords(ctx context.Context, req *tabularpb.DeleteRecordsRequest) (*tabularpb.DeleteRecordsResponse, error) {
+func (p *GoogleSheetsProvider) DeleteRecords(ctx context.Context, req *tabularpb.DeleteRecordsRequest) (reply *tabularpb.DeleteRecordsResponse, opErr error) {
+	ctx, span := startCall(ctx, "delete", req.GetData().GetSelection().GetTable())
+	defer func(start time.Time) {
+		observe(span, "delete", req.GetData().GetSelection().GetTable(), start, reply.GetSuccess(), opErr)
+	}(time.Now())
+
 	if !p.IsEnabled() {
 		return &tabularpb.DeleteRecordsResponse{
 			Success: false,
@@ -620,7 +692,7 @@ func (p *GoogleSheetsProvider) DeleteRecords(ctx context.Context, req *tabularpb
 	// Get sheet ID for the table
 	spreadsheet, err := service.Spreadsheets.Get(data.SourceId).Context(ctx).Do()
 	if err != nil {
-		p.logger.Error("Failed to get spreadsheet", "error", err, "source_id", data.SourceId)
+		p.logger.ErrorContext(ctx, "Failed to get spreadsheet", "error", err, "source_id", data.SourceId)
 		return &tabularpb.DeleteRecordsResponse{
 			Success: false,
 			Error: &commonpb.Error{
@@ -700,7 +772,7 @@ func (p *GoogleSheetsProvider) DeleteRecords(ctx context.Context, req *tabularpb
 		}
 		_, err = service.Spreadsheets.BatchUpdate(data.SourceId, batchReq).Context(ctx).Do()
 		if err != nil {
-			p.logger.Error("Failed to delete records", "error", err, "source_id", data.SourceId)
+			p.logger.ErrorContext(ctx, "Failed to delete records", "error", err, "source_id", data.SourceId)
 			return &tabularpb.DeleteRecordsResponse{
 				Success: false,
 				Error: &commonpb.Error{
@@ -711,7 +783,7 @@ func (p *GoogleSheetsProvider) DeleteRecords(ctx context.Context, req *tabularpb
 		}
 	}
 
-	p.logger.Info("Deleted records from Google Sheets",
+	p.logger.InfoContext(ctx, "Deleted records from Google Sheets",
 		"source_id", data.SourceId,
 		"table", tableName,
 		"count", recordsDeleted,
@@ -728,7 +800,12 @@ func (p *GoogleSheetsProvider) DeleteRecords(ctx context.Context, req *tabularpb
 }
 
 // SearchRecords searches for records matching specified criteria
-func (p *GoogleSheetsProvider) SearchRecords(ctx context.Context, req *tabularpb.SearchRecordsRequest) (*tabularpb.SearchRecordsResponse, error) {
+func (p *GoogleSheetsProvider) SearchRecords(ctx context.Context, req *tabularpb.SearchRecordsRequest) (reply *tabularpb.SearchRecordsResponse, opErr error) {
+	ctx, span := startCall(ctx, "search", req.GetData().GetTable())
+	defer func(start time.Time) {
+		observe(span, "search", req.GetData().GetTable(), start, reply.GetSuccess(), opErr)
+	}(time.Now())
+
 	if !p.IsEnabled() {
 		return &tabularpb.SearchRecordsResponse{
 			Success: false,
@@ -760,13 +837,37 @@ func (p *GoogleSheetsProvider) SearchRecords(ctx context.Context, req *tabularpb
 		tableName = "Sheet1"
 	}
 
+	// Filters the columns alone can answer read only those columns and the hits
+	if result, ok, err := p.searchPushdown(ctx, service, data, tableName); ok {
+		if err != nil {
+			p.logger.ErrorContext(ctx, "Failed to read for search", "error", err, "source_id", data.SourceId)
+			return &tabularpb.SearchRecordsResponse{
+				Success: false,
+				Error: &commonpb.Error{
+					Code:    "READ_FAILED",
+					Message: fmt.Sprintf("Failed to read records for search: %v", err),
+				},
+			}, nil
+		}
+		p.logger.InfoContext(ctx, "Searched records in Google Sheets",
+			"source_id", data.SourceId,
+			"table", tableName,
+			"found", len(result.Records),
+			"pushdown", true,
+		)
+		return &tabularpb.SearchRecordsResponse{
+			Success: true,
+			Data:    []*tabularpb.SearchRecordsResult{result},
+		}, nil
+	}
+
 	// Read all records from the table
 	resp, err := service.Spreadsheets.Values.Get(data.SourceId, tableName).
 		ValueRenderOption("FORMATTED_VALUE").
 		Context(ctx).
 		Do()
 	if err != nil {
-		p.logger.Error("Failed to read for search", "error", err, "source_id", data.SourceId)
+		p.logger.ErrorContext(ctx, "Failed to read for search", "error", err, "source_id", data.SourceId)
 		return &tabularpb.SearchRecordsResponse{
 			Success: false,
 			Error: &commonpb.Error{
@@ -811,7 +912,7 @@ func (p *GoogleSheetsProvider) SearchRecords(ctx context.Context, req *tabularpb
 	paginatedRecords := filteredRecords[start:end]
 	hasMore := end < len(filteredRecords)
 
-	p.logger.Info("Searched records in Google Sheets",
+	p.logger.InfoContext(ctx, "Searched records in Google Sheets",
 		"source_id", data.SourceId,
 		"table", tableName,
 		"found", len(paginatedRecords),
@@ -863,7 +964,7 @@ func (p *GoogleSheetsProvider) GetSchema(ctx context.Context, req *tabularpb.Get
 	// Get spreadsheet metadata
 	spreadsheet, err := service.Spreadsheets.Get(data.SourceId).Context(ctx).Do()
 	if err != nil {
-		p.logger.Error("Failed to get spreadsheet", "error", err, "source_id", data.SourceId)
+		p.logger.ErrorContext(ctx, "Failed to get spreadsheet", "error", err, "source_id", data.SourceId)
 		return &tabularpb.GetSchemaResponse{
 			Success: false,
 			Error: &commonpb.Error{
@@ -897,7 +998,7 @@ func (p *GoogleSheetsProvider) GetSchema(ctx context.Context, req *tabularpb.Get
 		result.TableSchema = schema
 	}
 
-	p.logger.Info("Got schema from Google Sheets",
+	p.logger.InfoContext(ctx, "Got schema from Google Sheets",
 		"source_id", data.SourceId,
 		"table", data.Table,
 	)
@@ -938,7 +1039,7 @@ func (p *GoogleSheetsProvider) GetSource(ctx context.Context, req *tabularpb.Get
 	// Get spreadsheet metadata
 	spreadsheet, err := service.Spreadsheets.Get(data.SourceId).Context(ctx).Do()
 	if err != nil {
-		p.logger.Error("Failed to get spreadsheet", "error", err, "source_id", data.SourceId)
+		p.logger.ErrorContext(ctx, "Failed to get spreadsheet", "error", err, "source_id", data.SourceId)
 		return &tabularpb.GetSourceResponse{
 			Success: false,
 			Error: &commonpb.Error{
@@ -967,7 +1068,7 @@ func (p *GoogleSheetsProvider) GetSource(ctx context.Context, req *tabularpb.Get
 		}
 	}
 
-	p.logger.Info("Got source from Google Sheets", "source_id", data.SourceId)
+	p.logger.InfoContext(ctx, "Got source from Google Sheets", "source_id", data.SourceId)
 
 	return &tabularpb.GetSourceResponse{
 		Success: true,
@@ -1005,7 +1106,7 @@ func (p *GoogleSheetsProvider) ListTables(ctx context.Context, req *tabularpb.Li
 	// Get spreadsheet metadata
 	spreadsheet, err := service.Spreadsheets.Get(data.SourceId).Context(ctx).Do()
 	if err != nil {
-		p.logger.Error("Failed to get spreadsheet", "error", err, "source_id", data.SourceId)
+		p.logger.ErrorContext(ctx, "Failed to get spreadsheet", "error", err, "source_id", data.SourceId)
 		return &tabularpb.ListTablesResponse{
 			Success: false,
 			Error: &commonpb.Error{
@@ -1025,7 +1126,7 @@ func (p *GoogleSheetsProvider) ListTables(ctx context.Context, req *tabularpb.Li
 		})
 	}
 
-	p.logger.Info("Listed tables from Google Sheets",
+	p.logger.InfoContext(ctx, "Listed tables from Google Sheets",
 		"source_id", data.SourceId,
 		"count", len(tables),
 	)
@@ -1105,7 +1206,7 @@ func (p *GoogleSheetsProvider) BatchExecute(ctx context.Context, req *tabularpb.
 		results = append(results, opResult)
 	}
 
-	p.logger.Info("Batch executed operations",
+	p.logger.InfoContext(ctx, "Batch executed operations",
 		"source_id", data.SourceId,
 		"total", len(data.Operations),
 		"success", successCount,
@@ -1274,6 +1375,10 @@ func selectionToA1Notation(selection *tabularpb.Selection) string {
 
 	// Build A1 notation
 	if endRow < 0 {
+		if endCol == "" && startRow == 1 {
+			// Every row of every column, as a paginated read without a sort needs
+			return tableName
+		}
 		if endCol == "" {
 			return fmt.Sprintf("%s!%s%d:%s", tableName, startCol, startRow, startCol)
 		}
@@ -1551,7 +1656,14 @@ func matchesSingleFilter(record *tabularpb.Record, filter *tabularpb.Filter) boo
 		return fieldValue != nil
 	}
 
-	if fieldValue == nil || filter.Value == nil {
+	if fieldValue == nil {
+		return false
+	}
+
+	// IN and NOT_IN compare against Values; every other operator needs Value
+	if filter.Value == nil &&
+		filter.Operator != tabularpb.FilterOperator_FILTER_OPERATOR_IN &&
+		filter.Operator != tabularpb.FilterOperator_FILTER_OPERATOR_NOT_IN {
 		return false
 	}
 
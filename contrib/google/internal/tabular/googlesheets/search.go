@@ -0,0 +1,215 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"google.golang.org/api/sheets/v4"
+
+	tabularpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/tabular"
+)
+
+// =============================================================================
+// Filter Pushdown
+// =============================================================================
+
+// A search whose filters are all equals, contains or in on a column index
+// is pushed down: one batchGet reads only the filtered columns, the filter
+// runs on those, and a second batchGet reads only the matching rows (just
+// the requested page when the search is unsorted). A filter on a 10-column,
+// 100k-row tab then moves one column plus the hits instead of every cell.
+//
+// The Sheets API has no server-side predicate to push to: a DataFilter
+// matches developer metadata and grid ranges, not cell values, and a
+// QUERY/FILTER formula needs a scratch sheet written on every search. Any
+// other operator, a filter by field name, or an empty comparison value
+// (where a missing and an empty cell differ) reads the whole tab and
+// filters in memory as before.
+
+// maxPushdownRanges caps the row runs of the second batchGet, whose ranges
+// travel in the URL. A search matching more scattered rows reads the tab.
+const maxPushdownRanges = 200
+
+// pushdownColumns returns the sorted column indices a filter reads, and
+// whether every condition in it can be pushed down.
+func pushdownColumns(filter *tabularpb.FilterGroup) ([]int, bool) {
+	set := make(map[int]bool)
+	if !collectPushdownColumns(filter, set) || len(set) == 0 {
+		return nil, false
+	}
+	columns := make([]int, 0, len(set))
+	for column := range set {
+		columns = append(columns, column)
+	}
+	sort.Ints(columns)
+	return columns, true
+}
+
+func collectPushdownColumns(filter *tabularpb.FilterGroup, set map[int]bool) bool {
+	for _, f := range filter.GetFilters() {
+		field, ok := f.GetField().(*tabularpb.Filter_FieldIndex)
+		if !ok || field.FieldIndex < 0 || !pushable(f) {
+			return false
+		}
+		set[int(field.FieldIndex)] = true
+	}
+	for _, group := range filter.GetGroups() {
+		if !collectPushdownColumns(group, set) {
+			return false
+		}
+	}
+	return true
+}
+
+// pushable reports whether a condition gives the same answer on a column
+// read alone as on whole rows: it must not match a missing or empty cell.
+func pushable(f *tabularpb.Filter) bool {
+	switch f.GetOperator() {
+	case tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS,
+		tabularpb.FilterOperator_FILTER_OPERATOR_CONTAINS:
+		return f.GetValue() != nil && getStringValue(f.GetValue()) != ""
+	case tabularpb.FilterOperator_FILTER_OPERATOR_IN:
+		for _, v := range f.GetValues() {
+			if getStringValue(v) == "" {
+				return false
+			}
+		}
+		return len(f.GetValues()) > 0
+	default:
+		return false
+	}
+}
+
+// columnA1 is the A1 range of a whole column.
+func columnA1(table string, column int) string {
+	letter := columnIndexToLetter(column)
+	return fmt.Sprintf("%s!%s:%s", table, letter, letter)
+}
+
+// matchProjected runs filter over the filtered columns read alone, one
+// value range per entry of columns, and returns the matching row indices.
+func matchProjected(filter *tabularpb.FilterGroup, columns []int, ranges []*sheets.ValueRange) []int64 {
+	rows := 0
+	for _, vr := range ranges {
+		rows = max(rows, len(vr.Values))
+	}
+	width := columns[len(columns)-1] + 1
+
+	var matches []int64
+	for i := 0; i < rows; i++ {
+		record := &tabularpb.Record{Values: make([]*tabularpb.FieldValue, width)}
+		for c, vr := range ranges {
+			if i < len(vr.Values) && len(vr.Values[i]) > 0 {
+				record.Values[columns[c]] = interfaceToFieldValue(vr.Values[i][0])
+			}
+		}
+		if matchesFilter(record, filter) {
+			matches = append(matches, int64(i))
+		}
+	}
+	return matches
+}
+
+// rowRuns groups sorted row indices into runs of consecutive rows, each
+// [first, last].
+func rowRuns(rows []int64) [][2]int64 {
+	var runs [][2]int64
+	for _, row := range rows {
+		if n := len(runs); n > 0 && runs[n-1][1] == row-1 {
+			runs[n-1][1] = row
+			continue
+		}
+		runs = append(runs, [2]int64{row, row})
+	}
+	return runs
+}
+
+// searchPushdown runs a search through the filtered columns. ok is false
+// when the search cannot be pushed down and the caller should read the tab.
+func (p *GoogleSheetsProvider) searchPushdown(ctx context.Context, service *sheets.Service, data *tabularpb.SearchRecordsData, table string) (_ *tabularpb.SearchRecordsResult, ok bool, _ error) {
+	columns, ok := pushdownColumns(data.GetFilter())
+	if !ok {
+		return nil, false, nil
+	}
+
+	columnRanges := make([]string, len(columns))
+	for i, column := range columns {
+		columnRanges[i] = columnA1(table, column)
+	}
+	projected, err := service.Spreadsheets.Values.BatchGet(data.SourceId).
+		Ranges(columnRanges...).
+		ValueRenderOption("FORMATTED_VALUE").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, true, err
+	}
+	if len(projected.ValueRanges) != len(columns) {
+		return nil, true, fmt.Errorf("batchGet returned %d ranges, want %d", len(projected.ValueRanges), len(columns))
+	}
+	matches := matchProjected(data.GetFilter(), columns, projected.ValueRanges)
+
+	// Unsorted searches read only their page; sorted ones need every hit.
+	total := int64(len(matches))
+	wanted := matches
+	sorted := len(data.GetSortBy()) > 0
+	if !sorted {
+		start := min(int64(max(data.GetOffset(), 0)), total)
+		end := total
+		if data.GetLimit() > 0 {
+			end = min(start+int64(data.GetLimit()), total)
+		}
+		wanted = matches[start:end]
+	}
+
+	runs := rowRuns(wanted)
+	if len(runs) > maxPushdownRanges {
+		return nil, false, nil
+	}
+	var records []*tabularpb.Record
+	if len(runs) > 0 {
+		rowRanges := make([]string, len(runs))
+		for i, run := range runs {
+			rowRanges[i] = rowsA1(table, "", "", run[0], run[1]+1)
+		}
+		resp, err := service.Spreadsheets.Values.BatchGet(data.SourceId).
+			Ranges(rowRanges...).
+			ValueRenderOption("FORMATTED_VALUE").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, true, err
+		}
+		if len(resp.ValueRanges) != len(runs) {
+			return nil, true, fmt.Errorf("batchGet returned %d ranges, want %d", len(resp.ValueRanges), len(runs))
+		}
+		for i, vr := range resp.ValueRanges {
+			for j, record := range valueRangeToRecords(vr) {
+				record.Index = runs[i][0] + int64(j)
+				record.Id = fmt.Sprintf("row_%d", record.Index)
+				records = append(records, record)
+			}
+		}
+	}
+
+	hasMore := false
+	if sorted {
+		records = applySort(records, data.GetSortBy())
+		start := min(int(max(data.GetOffset(), 0)), len(records))
+		end := len(records)
+		if data.GetLimit() > 0 {
+			end = min(start+int(data.GetLimit()), len(records))
+		}
+		hasMore = end < len(records)
+		records = records[start:end]
+	} else {
+		hasMore = int64(max(data.GetOffset(), 0))+int64(len(wanted)) < total
+	}
+
+	return &tabularpb.SearchRecordsResult{
+		Records:    records,
+		TotalCount: total,
+		HasMore:    hasMore,
+	}, true, nil
+}
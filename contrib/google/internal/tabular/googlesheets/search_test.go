@@ -0,0 +1,347 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+
+	tabularpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/tabular"
+)
+
+func str(s string) *tabularpb.FieldValue {
+	return &tabularpb.FieldValue{Value: &tabularpb.FieldValue_StringValue{StringValue: s}}
+}
+
+func onColumn(column int32, op tabularpb.FilterOperator, value string) *tabularpb.Filter {
+	return &tabularpb.Filter{
+		Field:    &tabularpb.Filter_FieldIndex{FieldIndex: column},
+		Operator: op,
+		Value:    str(value),
+	}
+}
+
+func inColumn(column int32, values ...string) *tabularpb.Filter {
+	f := &tabularpb.Filter{
+		Field:    &tabularpb.Filter_FieldIndex{FieldIndex: column},
+		Operator: tabularpb.FilterOperator_FILTER_OPERATOR_IN,
+	}
+	for _, v := range values {
+		f.Values = append(f.Values, str(v))
+	}
+	return f
+}
+
+func TestPushdownColumns(t *testing.T) {
+	const (
+		equals   = tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS
+		contains = tabularpb.FilterOperator_FILTER_OPERATOR_CONTAINS
+	)
+	cases := []struct {
+		name    string
+		filter  *tabularpb.FilterGroup
+		columns []int
+		ok      bool
+	}{
+		{
+			name: "equals and contains across groups",
+			filter: &tabularpb.FilterGroup{
+				Filters: []*tabularpb.Filter{onColumn(2, equals, "open")},
+				Groups: []*tabularpb.FilterGroup{{
+					Operator: tabularpb.LogicalOperator_LOGICAL_OPERATOR_OR,
+					Filters:  []*tabularpb.Filter{onColumn(0, contains, "ann"), onColumn(2, contains, "pen")},
+				}},
+			},
+			columns: []int{0, 2},
+			ok:      true,
+		},
+		{
+			name:    "in",
+			filter:  &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{inColumn(1, "open", "closed")}},
+			columns: []int{1},
+			ok:      true,
+		},
+		{
+			name:   "other operator",
+			filter: &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{onColumn(1, equals, "x"), onColumn(2, tabularpb.FilterOperator_FILTER_OPERATOR_NOT_EQUALS, "x")}},
+		},
+		{
+			name: "operator in a nested group",
+			filter: &tabularpb.FilterGroup{Groups: []*tabularpb.FilterGroup{{
+				Filters: []*tabularpb.Filter{onColumn(1, tabularpb.FilterOperator_FILTER_OPERATOR_IS_EMPTY, "x")},
+			}}},
+		},
+		{
+			name: "field name",
+			filter: &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{{
+				Field:    &tabularpb.Filter_FieldName{FieldName: "status"},
+				Operator: equals,
+				Value:    str("open"),
+			}}},
+		},
+		{
+			name:   "negative index",
+			filter: &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{onColumn(-1, equals, "open")}},
+		},
+		{
+			name:   "empty equals value",
+			filter: &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{onColumn(1, equals, "")}},
+		},
+		{
+			name: "missing contains value",
+			filter: &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{{
+				Field:    &tabularpb.Filter_FieldIndex{FieldIndex: 1},
+				Operator: contains,
+			}}},
+		},
+		{
+			name:   "in with an empty value",
+			filter: &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{inColumn(1, "open", "")}},
+		},
+		{
+			name:   "in without values",
+			filter: &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{inColumn(1)}},
+		},
+		{
+			name:   "no conditions",
+			filter: &tabularpb.FilterGroup{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			columns, ok := pushdownColumns(c.filter)
+			if ok != c.ok || !reflect.DeepEqual(columns, c.columns) {
+				t.Errorf("pushdownColumns = %v, %v; want %v, %v", columns, ok, c.columns, c.ok)
+			}
+		})
+	}
+}
+
+func TestMatchProjected(t *testing.T) {
+	filter := &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{
+		onColumn(1, tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS, "open"),
+		onColumn(3, tabularpb.FilterOperator_FILTER_OPERATOR_CONTAINS, "ann"),
+	}}
+	// Column D is shorter than B: the Sheets API drops trailing empty cells.
+	ranges := []*sheets.ValueRange{
+		{Values: [][]interface{}{{"Status"}, {"open"}, {"OPEN"}, {"closed"}, {}, {"open"}}},
+		{Values: [][]interface{}{{"Owner"}, {"Anna"}, {"Joanne"}, {"Hannah"}, {"Anna"}}},
+	}
+
+	got := matchProjected(filter, []int{1, 3}, ranges)
+	if want := []int64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("matches = %v, want %v", got, want)
+	}
+}
+
+func TestRowRuns(t *testing.T) {
+	got := rowRuns([]int64{1, 2, 3, 7, 9, 10})
+	if want := [][2]int64{{1, 3}, {7, 7}, {9, 10}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("runs = %v, want %v", got, want)
+	}
+	if got := rowRuns(nil); got != nil {
+		t.Errorf("runs of no rows = %v", got)
+	}
+}
+
+// tabServer serves batchGet for a tab holding values, answering whole
+// column ranges ("Sheet1!B:B") and row ranges ("Sheet1!4:5"). Each call's
+// ranges are recorded.
+func tabServer(t *testing.T, values [][]interface{}, calls *[][]string) *sheets.Service {
+	return newTestService(t, func(rw http.ResponseWriter, r *http.Request) {
+		ranges := r.URL.Query()["ranges"]
+		*calls = append(*calls, ranges)
+
+		resp := &sheets.BatchGetValuesResponse{}
+		for _, a1 := range ranges {
+			cells := strings.TrimPrefix(a1, "Sheet1!")
+			vr := &sheets.ValueRange{Range: a1}
+			var first, last int
+			if _, err := fmt.Sscanf(cells, "%d:%d", &first, &last); err == nil {
+				for i := first - 1; i < min(last, len(values)); i++ {
+					vr.Values = append(vr.Values, values[i])
+				}
+			} else {
+				column := int(cells[0] - 'A')
+				for _, row := range values {
+					if column < len(row) {
+						vr.Values = append(vr.Values, []interface{}{row[column]})
+					} else {
+						vr.Values = append(vr.Values, []interface{}{})
+					}
+				}
+			}
+			resp.ValueRanges = append(resp.ValueRanges, vr)
+		}
+		writeJSON(t, rw, resp)
+	})
+}
+
+var statusTab = [][]interface{}{
+	{"Name", "Status"},
+	{"a1", "open"},
+	{"a2", "closed"},
+	{"a3", "open"},
+	{"a4", "OPEN"},
+	{"a5", "closed"},
+	{"a6", "open"},
+}
+
+func names(records []*tabularpb.Record) []string {
+	var out []string
+	for _, record := range records {
+		out = append(out, record.Values[0].GetStringValue())
+	}
+	return out
+}
+
+func TestSearchPushdown(t *testing.T) {
+	openFilter := &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{
+		onColumn(1, tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS, "open"),
+	}}
+	cases := []struct {
+		name      string
+		data      *tabularpb.SearchRecordsData
+		names     []string
+		indices   []int64
+		total     int64
+		more      bool
+		rowRanges []string
+	}{
+		{
+			name:      "unsorted page reads only its rows",
+			data:      &tabularpb.SearchRecordsData{Filter: openFilter, Offset: 1, Limit: 2},
+			names:     []string{"a3", "a4"},
+			indices:   []int64{3, 4},
+			total:     4,
+			more:      true,
+			rowRanges: []string{"Sheet1!4:5"},
+		},
+		{
+			name: "sorted search reads every hit",
+			data: &tabularpb.SearchRecordsData{
+				Filter: openFilter,
+				Limit:  2,
+				SortBy: []*tabularpb.SortSpec{{
+					Field:     &tabularpb.SortSpec_FieldIndex{FieldIndex: 0},
+					Direction: tabularpb.SortDirection_SORT_DIRECTION_DESCENDING,
+				}},
+			},
+			names:     []string{"a6", "a4"},
+			indices:   []int64{6, 4},
+			total:     4,
+			more:      true,
+			rowRanges: []string{"Sheet1!2:2", "Sheet1!4:5", "Sheet1!7:7"},
+		},
+		{
+			name: "in",
+			data: &tabularpb.SearchRecordsData{Filter: &tabularpb.FilterGroup{
+				Filters: []*tabularpb.Filter{inColumn(1, "closed", "pending")},
+			}},
+			names:     []string{"a2", "a5"},
+			indices:   []int64{2, 5},
+			total:     2,
+			rowRanges: []string{"Sheet1!3:3", "Sheet1!6:6"},
+		},
+		{
+			name: "no hits",
+			data: &tabularpb.SearchRecordsData{Filter: &tabularpb.FilterGroup{
+				Filters: []*tabularpb.Filter{onColumn(1, tabularpb.FilterOperator_FILTER_OPERATOR_CONTAINS, "pend")},
+			}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var calls [][]string
+			service := tabServer(t, statusTab, &calls)
+			c.data.SourceId = "sheet-1"
+
+			got, ok, err := (&GoogleSheetsProvider{}).searchPushdown(context.Background(), service, c.data, "Sheet1")
+			if err != nil || !ok {
+				t.Fatalf("ok = %v, err = %v", ok, err)
+			}
+			if !reflect.DeepEqual(names(got.Records), c.names) {
+				t.Errorf("names = %v, want %v", names(got.Records), c.names)
+			}
+			var indices []int64
+			for _, record := range got.Records {
+				indices = append(indices, record.Index)
+			}
+			if !reflect.DeepEqual(indices, c.indices) {
+				t.Errorf("indices = %v, want %v", indices, c.indices)
+			}
+			if got.TotalCount != c.total || got.HasMore != c.more {
+				t.Errorf("total = %d, more = %v; want %d, %v", got.TotalCount, got.HasMore, c.total, c.more)
+			}
+
+			if len(calls) == 0 || !reflect.DeepEqual(calls[0], []string{"Sheet1!B:B"}) {
+				t.Fatalf("first batchGet = %v, want only the filtered column", calls)
+			}
+			var rowRanges []string
+			if len(calls) > 1 {
+				rowRanges = calls[1]
+			}
+			if len(calls) > 2 || !reflect.DeepEqual(rowRanges, c.rowRanges) {
+				t.Errorf("row batchGets = %v, want %v", calls[1:], c.rowRanges)
+			}
+		})
+	}
+}
+
+func TestSearchPushdown_FallsBack(t *testing.T) {
+	t.Run("operator that cannot be pushed", func(t *testing.T) {
+		var calls [][]string
+		service := tabServer(t, statusTab, &calls)
+		data := &tabularpb.SearchRecordsData{SourceId: "sheet-1", Filter: &tabularpb.FilterGroup{
+			Filters: []*tabularpb.Filter{onColumn(1, tabularpb.FilterOperator_FILTER_OPERATOR_NOT_EQUALS, "open")},
+		}}
+
+		_, ok, err := (&GoogleSheetsProvider{}).searchPushdown(context.Background(), service, data, "Sheet1")
+		if ok || err != nil {
+			t.Errorf("ok = %v, err = %v", ok, err)
+		}
+		if len(calls) != 0 {
+			t.Errorf("batchGets = %v, want none", calls)
+		}
+	})
+
+	t.Run("too many scattered hits", func(t *testing.T) {
+		tab := [][]interface{}{{"Name", "Status"}}
+		for i := 0; i < 2*maxPushdownRanges+2; i++ {
+			status := "closed"
+			if i%2 == 0 {
+				status = "open"
+			}
+			tab = append(tab, []interface{}{fmt.Sprintf("a%d", i), status})
+		}
+		var calls [][]string
+		service := tabServer(t, tab, &calls)
+		data := &tabularpb.SearchRecordsData{SourceId: "sheet-1", Filter: &tabularpb.FilterGroup{
+			Filters: []*tabularpb.Filter{onColumn(1, tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS, "open")},
+		}}
+
+		_, ok, err := (&GoogleSheetsProvider{}).searchPushdown(context.Background(), service, data, "Sheet1")
+		if ok || err != nil {
+			t.Errorf("ok = %v, err = %v", ok, err)
+		}
+		if len(calls) != 1 {
+			t.Errorf("batchGets = %d, want only the column read", len(calls))
+		}
+	})
+}
+
+func TestMatchesSingleFilter_InWithoutValue(t *testing.T) {
+	record := &tabularpb.Record{Values: []*tabularpb.FieldValue{str("a1"), str("Closed")}}
+	if !matchesSingleFilter(record, inColumn(1, "open", "closed")) {
+		t.Error("IN did not match a listed value")
+	}
+	notIn := inColumn(1, "open", "closed")
+	notIn.Operator = tabularpb.FilterOperator_FILTER_OPERATOR_NOT_IN
+	if matchesSingleFilter(record, notIn) {
+		t.Error("NOT_IN matched a listed value")
+	}
+}
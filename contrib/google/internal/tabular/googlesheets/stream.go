@@ -0,0 +1,210 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+
+	"github.com/erniealice/espyna-golang/ports/integration"
+	tabularpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/tabular"
+)
+
+// =============================================================================
+// Windowed and Streaming Reads
+// =============================================================================
+
+// A paginated read without a sort fetches only its page: one batchGet with
+// the first selected column of the whole selection, to count the rows, and
+// the page's rows. Reading page 500 of a 100k-row sheet then moves one
+// column of the sheet plus one page through the API instead of every cell.
+// A sort still needs every row, so sorted reads load the whole range.
+//
+// Rows are counted by the first selected column, so trailing rows that
+// leave it empty are not counted.
+
+// defaultStreamChunkRows is the chunk size of StreamRecords when the caller
+// does not choose one.
+const defaultStreamChunkRows = 5000
+
+// readWindow is the part of a sheet a read selects, in 0-based rows.
+type readWindow struct {
+	table       string
+	first, last string // column span; empty for whole rows
+	base, end   int64  // selected rows [base, end); end < 0 is open-ended
+	offset      int64
+	limit       int64
+}
+
+// windowed reports whether a read can fetch only its page.
+func windowed(data *tabularpb.ReadRecordsData) bool {
+	return data.GetSelection().GetRecords().GetLimit() > 0 && len(data.GetSortBy()) == 0
+}
+
+// newReadWindow returns the window of a paginated selection.
+func newReadWindow(selection *tabularpb.Selection) readWindow {
+	w := readWindow{table: selection.GetTable(), end: -1}
+	if w.table == "" {
+		w.table = "Sheet1"
+	}
+	w.first, w.last = columnSpan(selection.GetFields())
+
+	records := selection.GetRecords()
+	if r := records.GetIndexRange(); r != nil {
+		w.base = r.Start
+		if r.End > 0 {
+			w.end = r.End
+		}
+	}
+	w.offset = int64(max(records.GetOffset(), 0))
+	w.limit = int64(records.GetLimit())
+	return w
+}
+
+// columnSpan returns the first and last column letters of a field
+// selection, or empty strings for every column.
+func columnSpan(fields *tabularpb.FieldSelection) (first, last string) {
+	if len(fields.GetIndices()) == 0 {
+		return "", ""
+	}
+	indices := make([]int, len(fields.Indices))
+	for i, idx := range fields.Indices {
+		indices[i] = int(idx)
+	}
+	sort.Ints(indices)
+	return columnIndexToLetter(indices[0]), columnIndexToLetter(indices[len(indices)-1])
+}
+
+// rowsA1 is the A1 range of rows [from, to) within the column span.
+func rowsA1(table, first, last string, from, to int64) string {
+	if first == "" {
+		return fmt.Sprintf("%s!%d:%d", table, from+1, to)
+	}
+	return fmt.Sprintf("%s!%s%d:%s%d", table, first, from+1, last, to)
+}
+
+// countRange is the A1 range of the column the window counts rows by.
+func (w readWindow) countRange() string {
+	column := w.first
+	if column == "" {
+		column = "A"
+	}
+	if w.end < 0 {
+		return fmt.Sprintf("%s!%s%d:%s", w.table, column, w.base+1, column)
+	}
+	return fmt.Sprintf("%s!%s%d:%s%d", w.table, column, w.base+1, column, w.end)
+}
+
+// pageRange is the A1 range of the window's page. A page past the end of
+// the selection still asks for one row, which result drops.
+func (w readWindow) pageRange() string {
+	from := w.base + w.offset
+	to := from + w.limit
+	if w.end >= 0 && to > w.end {
+		to = max(w.end, from+1)
+	}
+	return rowsA1(w.table, w.first, w.last, from, to)
+}
+
+// result builds the read result from the counted column and the page rows,
+// numbering records from the start of the selection like a full read.
+func (w readWindow) result(counted, page *sheets.ValueRange) *tabularpb.ReadRecordsResult {
+	records := valueRangeToRecords(page)
+	for i, record := range records {
+		index := w.offset + int64(i)
+		record.Index = index
+		record.Id = fmt.Sprintf("row_%d", index)
+	}
+	if w.end >= 0 && int64(len(records)) > w.end-w.base-w.offset {
+		records = records[:max(w.end-w.base-w.offset, 0)]
+	}
+
+	// Rows the page returned exist even when the counted column is empty
+	// there; an empty page past the end proves nothing.
+	total := int64(len(counted.Values))
+	if len(records) > 0 {
+		total = max(total, w.offset+int64(len(records)))
+	}
+	next := min(w.offset+int64(len(records)), total)
+	return &tabularpb.ReadRecordsResult{
+		Records:    records,
+		TotalCount: total,
+		HasMore:    next < total,
+		NextOffset: int32(next),
+	}
+}
+
+// fetchWindow fetches a paginated read's page and row count in one batchGet.
+func (p *GoogleSheetsProvider) fetchWindow(ctx context.Context, service *sheets.Service, sourceID string, w readWindow) (*tabularpb.ReadRecordsResult, error) {
+	resp, err := service.Spreadsheets.Values.BatchGet(sourceID).
+		Ranges(w.countRange(), w.pageRange()).
+		ValueRenderOption("FORMATTED_VALUE").
+		DateTimeRenderOption("FORMATTED_STRING").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.ValueRanges) != 2 {
+		return nil, fmt.Errorf("batchGet returned %d ranges, want 2", len(resp.ValueRanges))
+	}
+	return w.result(resp.ValueRanges[0], resp.ValueRanges[1]), nil
+}
+
+// StreamRecords reads every row of a sheet in order, chunkRows at a time
+// (5000 when 0), and calls fn with each chunk, so a large sheet can be
+// processed without holding it in memory. Records are numbered from the
+// first row like ReadRecords. It stops at the first empty chunk or when fn
+// returns an error, which it returns.
+func (p *GoogleSheetsProvider) StreamRecords(ctx context.Context, sourceID, table string, chunkRows int, fn func([]*tabularpb.Record) error) (opErr error) {
+	ctx, span := startCall(ctx, "stream", table)
+	defer func(start time.Time) {
+		observe(span, "stream", table, start, opErr == nil, opErr)
+	}(time.Now())
+
+	if !p.IsEnabled() {
+		return fmt.Errorf("googlesheets: provider is not enabled")
+	}
+	if table == "" {
+		table = "Sheet1"
+	}
+	if chunkRows <= 0 {
+		chunkRows = defaultStreamChunkRows
+	}
+
+	p.mu.RLock()
+	service := p.clientManager.GetService()
+	p.mu.RUnlock()
+
+	return streamRecords(ctx, service, sourceID, table, chunkRows, fn)
+}
+
+// streamRecords reads a sheet chunkRows rows at a time for StreamRecords.
+func streamRecords(ctx context.Context, service *sheets.Service, sourceID, table string, chunkRows int, fn func([]*tabularpb.Record) error) error {
+	for from := int64(0); ; from += int64(chunkRows) {
+		a1Range := rowsA1(table, "", "", from, from+int64(chunkRows))
+		resp, err := service.Spreadsheets.Values.Get(sourceID, a1Range).
+			ValueRenderOption("FORMATTED_VALUE").
+			DateTimeRenderOption("FORMATTED_STRING").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return fmt.Errorf("googlesheets: failed to read %s: %w", a1Range, err)
+		}
+		if len(resp.Values) == 0 {
+			return nil
+		}
+		records := valueRangeToRecords(resp)
+		for i, record := range records {
+			record.Index = from + int64(i)
+			record.Id = fmt.Sprintf("row_%d", record.Index)
+		}
+		if err := fn(records); err != nil {
+			return err
+		}
+	}
+}
+
+var _ integration.TabularRecordStreamer = (*GoogleSheetsProvider)(nil)
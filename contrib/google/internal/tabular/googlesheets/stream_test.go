@@ -0,0 +1,321 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+
+	tabularpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/tabular"
+)
+
+// newTestService returns a Sheets client whose requests go to handler.
+func newTestService(t *testing.T, handler http.HandlerFunc) *sheets.Service {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	service, err := sheets.NewService(context.Background(),
+		option.WithHTTPClient(server.Client()),
+		option.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	return service
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Error(err)
+	}
+}
+
+// rows returns n single-cell rows starting at row from (0-based).
+func rows(from, n int) [][]interface{} {
+	values := make([][]interface{}, n)
+	for i := range values {
+		values[i] = []interface{}{fmt.Sprintf("r%d", from+i)}
+	}
+	return values
+}
+
+func TestNewReadWindow(t *testing.T) {
+	w := newReadWindow(&tabularpb.Selection{
+		Records: &tabularpb.RecordSelection{
+			IndexRange: &tabularpb.IndexRange{Start: 10, End: 50},
+			Offset:     5,
+			Limit:      20,
+		},
+		Fields: &tabularpb.FieldSelection{Indices: []int32{3, 1}},
+	})
+	want := readWindow{table: "Sheet1", first: "B", last: "D", base: 10, end: 50, offset: 5, limit: 20}
+	if w != want {
+		t.Errorf("window = %+v, want %+v", w, want)
+	}
+
+	w = newReadWindow(&tabularpb.Selection{
+		Table:   "Orders",
+		Records: &tabularpb.RecordSelection{Offset: -3, Limit: 10},
+	})
+	want = readWindow{table: "Orders", end: -1, limit: 10}
+	if w != want {
+		t.Errorf("window = %+v, want %+v", w, want)
+	}
+}
+
+func TestReadWindowRanges(t *testing.T) {
+	cases := []struct {
+		name        string
+		w           readWindow
+		count, page string
+	}{
+		{
+			name:  "open-ended whole rows",
+			w:     readWindow{table: "T", end: -1, offset: 40, limit: 20},
+			count: "T!A1:A",
+			page:  "T!41:60",
+		},
+		{
+			name:  "column span inside an index range",
+			w:     readWindow{table: "T", first: "B", last: "D", base: 10, end: 50, offset: 5, limit: 20},
+			count: "T!B11:B50",
+			page:  "T!B16:D35",
+		},
+		{
+			name:  "page clamped to the end of the range",
+			w:     readWindow{table: "T", base: 10, end: 50, offset: 35, limit: 20},
+			count: "T!A11:A50",
+			page:  "T!46:50",
+		},
+		{
+			name:  "page past the end asks for one row",
+			w:     readWindow{table: "T", base: 10, end: 50, offset: 45, limit: 20},
+			count: "T!A11:A50",
+			page:  "T!56:56",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.w.countRange(); got != c.count {
+				t.Errorf("countRange = %q, want %q", got, c.count)
+			}
+			if got := c.w.pageRange(); got != c.page {
+				t.Errorf("pageRange = %q, want %q", got, c.page)
+			}
+		})
+	}
+}
+
+func TestReadWindowResult(t *testing.T) {
+	cases := []struct {
+		name      string
+		w         readWindow
+		counted   int
+		page      int
+		wantFirst int64
+		wantLen   int
+		wantTotal int64
+		wantNext  int32
+		wantMore  bool
+	}{
+		{
+			name:      "middle page",
+			w:         readWindow{end: -1, offset: 40, limit: 20},
+			counted:   100,
+			page:      20,
+			wantFirst: 40, wantLen: 20, wantTotal: 100, wantNext: 60, wantMore: true,
+		},
+		{
+			name:      "last partial page",
+			w:         readWindow{end: -1, offset: 40, limit: 20},
+			counted:   45,
+			page:      5,
+			wantFirst: 40, wantLen: 5, wantTotal: 45, wantNext: 45,
+		},
+		{
+			name:      "page rows beyond an empty counted column",
+			w:         readWindow{end: -1, offset: 40, limit: 20},
+			counted:   42,
+			page:      8,
+			wantFirst: 40, wantLen: 8, wantTotal: 48, wantNext: 48,
+		},
+		{
+			name:      "rows past the index range are dropped",
+			w:         readWindow{base: 10, end: 20, offset: 5, limit: 20},
+			counted:   10,
+			page:      7,
+			wantFirst: 5, wantLen: 5, wantTotal: 10, wantNext: 10,
+		},
+		{
+			name:    "page past the end of the range",
+			w:       readWindow{base: 0, end: 10, offset: 12, limit: 5},
+			counted: 10,
+			page:    1,
+			wantLen: 0, wantTotal: 10, wantNext: 10,
+		},
+		{
+			name:    "empty page past the end of the sheet",
+			w:       readWindow{end: -1, offset: 30, limit: 10},
+			counted: 25,
+			page:    0,
+			wantLen: 0, wantTotal: 25, wantNext: 25,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.w.result(&sheets.ValueRange{Values: rows(0, c.counted)}, &sheets.ValueRange{Values: rows(0, c.page)})
+			if len(got.Records) != c.wantLen {
+				t.Fatalf("records = %d, want %d", len(got.Records), c.wantLen)
+			}
+			if c.wantLen > 0 {
+				first := got.Records[0]
+				if first.Index != c.wantFirst || first.Id != fmt.Sprintf("row_%d", c.wantFirst) {
+					t.Errorf("first record = %d %q, want index %d", first.Index, first.Id, c.wantFirst)
+				}
+			}
+			if got.TotalCount != c.wantTotal || got.NextOffset != c.wantNext || got.HasMore != c.wantMore {
+				t.Errorf("total = %d, next = %d, more = %v; want %d, %d, %v",
+					got.TotalCount, got.NextOffset, got.HasMore, c.wantTotal, c.wantNext, c.wantMore)
+			}
+		})
+	}
+}
+
+func TestFetchWindow(t *testing.T) {
+	w := readWindow{table: "T", end: -1, offset: 2, limit: 2}
+	var ranges []string
+	service := newTestService(t, func(rw http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/spreadsheets/sheet-1/values:batchGet") {
+			http.Error(rw, "unexpected path "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		ranges = r.URL.Query()["ranges"]
+		writeJSON(t, rw, &sheets.BatchGetValuesResponse{ValueRanges: []*sheets.ValueRange{
+			{Values: rows(0, 5)},
+			{Values: rows(2, 2)},
+		}})
+	})
+
+	got, err := (&GoogleSheetsProvider{}).fetchWindow(context.Background(), service, "sheet-1", w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"T!A1:A", "T!3:4"}; !reflect.DeepEqual(ranges, want) {
+		t.Errorf("ranges = %v, want %v", ranges, want)
+	}
+	if len(got.Records) != 2 || got.Records[0].Index != 2 || got.Records[0].Values[0].GetStringValue() != "r2" {
+		t.Errorf("records = %v", got.Records)
+	}
+	if got.TotalCount != 5 || got.NextOffset != 4 || !got.HasMore {
+		t.Errorf("total = %d, next = %d, more = %v", got.TotalCount, got.NextOffset, got.HasMore)
+	}
+}
+
+func TestFetchWindow_WrongRangeCount(t *testing.T) {
+	service := newTestService(t, func(rw http.ResponseWriter, r *http.Request) {
+		writeJSON(t, rw, &sheets.BatchGetValuesResponse{ValueRanges: []*sheets.ValueRange{{}}})
+	})
+
+	_, err := (&GoogleSheetsProvider{}).fetchWindow(context.Background(), service, "sheet-1", readWindow{table: "T", end: -1, limit: 1})
+	if err == nil {
+		t.Fatal("expected an error for a single range")
+	}
+}
+
+// sheetServer serves Values.Get for a sheet of n single-cell rows and
+// records the ranges asked for.
+func sheetServer(t *testing.T, n int, requested *[]string) *sheets.Service {
+	return newTestService(t, func(rw http.ResponseWriter, r *http.Request) {
+		a1 := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		*requested = append(*requested, a1)
+		var first, last int
+		if _, err := fmt.Sscanf(a1, "Sheet1!%d:%d", &first, &last); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		from, to := first-1, min(last, n)
+		vr := &sheets.ValueRange{Range: a1}
+		if from < to {
+			vr.Values = rows(from, to-from)
+		}
+		writeJSON(t, rw, vr)
+	})
+}
+
+func TestStreamRecords_ChunkBoundaries(t *testing.T) {
+	cases := []struct {
+		name   string
+		rows   int
+		chunks []int
+		ranges []string
+	}{
+		{"partial last chunk", 5, []int{2, 2, 1}, []string{"Sheet1!1:2", "Sheet1!3:4", "Sheet1!5:6", "Sheet1!7:8"}},
+		{"exact multiple", 4, []int{2, 2}, []string{"Sheet1!1:2", "Sheet1!3:4", "Sheet1!5:6"}},
+		{"empty sheet", 0, nil, []string{"Sheet1!1:2"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var requested []string
+			service := sheetServer(t, c.rows, &requested)
+
+			var chunks []int
+			var next int64
+			err := streamRecords(context.Background(), service, "sheet-1", "Sheet1", 2, func(records []*tabularpb.Record) error {
+				chunks = append(chunks, len(records))
+				for _, record := range records {
+					if record.Index != next || record.Id != fmt.Sprintf("row_%d", next) ||
+						record.Values[0].GetStringValue() != fmt.Sprintf("r%d", next) {
+						t.Errorf("record %d = %d %q %v", next, record.Index, record.Id, record.Values)
+					}
+					next++
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(chunks, c.chunks) {
+				t.Errorf("chunks = %v, want %v", chunks, c.chunks)
+			}
+			if !reflect.DeepEqual(requested, c.ranges) {
+				t.Errorf("ranges = %v, want %v", requested, c.ranges)
+			}
+		})
+	}
+}
+
+func TestStreamRecords_StopsOnCallbackError(t *testing.T) {
+	var requested []string
+	service := sheetServer(t, 10, &requested)
+	sentinel := errors.New("stop")
+
+	err := streamRecords(context.Background(), service, "sheet-1", "Sheet1", 3, func([]*tabularpb.Record) error {
+		return sentinel
+	})
+	if !errors.Is(err, sentinel) {
+		t.Fatalf("err = %v, want the callback's error", err)
+	}
+	if len(requested) != 1 {
+		t.Errorf("ranges = %v after the callback failed", requested)
+	}
+}
+
+func TestStreamRecords_APIError(t *testing.T) {
+	service := newTestService(t, func(rw http.ResponseWriter, r *http.Request) {
+		http.Error(rw, `{"error":{"code":403,"message":"denied"}}`, http.StatusForbidden)
+	})
+
+	err := streamRecords(context.Background(), service, "sheet-1", "Sheet1", 2, func([]*tabularpb.Record) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "Sheet1!1:2") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,5 @@
+//go:build google_fcm
+
+package google
+
+import _ "github.com/erniealice/espyna-golang/contrib/google/internal/push/fcm"
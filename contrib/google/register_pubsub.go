@@ -0,0 +1,5 @@
+//go:build pubsub
+
+package google
+
+import _ "github.com/erniealice/espyna-golang/contrib/google/internal/events/pubsub"
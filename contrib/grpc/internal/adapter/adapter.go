@@ -13,10 +13,12 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/erniealice/espyna-golang/composition/contracts"
 	"github.com/erniealice/espyna-golang/composition/core"
 	"github.com/erniealice/espyna-golang/contrib/grpc/internal/interceptors"
 	"github.com/erniealice/espyna-golang/ports"
 	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/metrics"
 )
 
 // =============================================================================
@@ -54,6 +56,7 @@ type GRPCVanillaAdapter struct {
 	authInterceptor     *interceptors.AuthenticationInterceptor
 	recoveryInterceptor *interceptors.RecoveryInterceptor
 	loggingInterceptor  *interceptors.LoggingInterceptor
+	rateLimiter         *contracts.RateLimiter
 }
 
 // NewGRPCVanillaAdapter creates a new gRPC server adapter.
@@ -87,6 +90,13 @@ func (a *GRPCVanillaAdapter) Initialize(container any) error {
 	a.recoveryInterceptor = interceptors.NewRecoveryInterceptor()
 	a.loggingInterceptor = interceptors.NewLoggingInterceptor()
 
+	// Rate limits from the routing config (CONFIG_RATE_LIMIT_*)
+	limiter, err := contracts.NewRateLimiter(c.GetRouteManager().GetConfig().RateLimit)
+	if err != nil {
+		log.Printf("WARNING: rate limiting disabled: %v", err)
+	}
+	a.rateLimiter = limiter
+
 	// Get auth service from provider manager
 	authService := ports.AuthService(nil)
 	if provider := c.GetAuthProvider(); provider != nil {
@@ -97,11 +107,12 @@ func (a *GRPCVanillaAdapter) Initialize(container any) error {
 	a.authInterceptor = interceptors.NewAuthenticationInterceptor(authService)
 
 	// Create gRPC server with interceptor chain
-	// Order: Recovery -> Logging -> Authentication
+	// Order: Recovery -> Logging -> Rate limit -> Authentication
 	a.server = grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			a.recoveryInterceptor.UnaryInterceptor(),
 			a.loggingInterceptor.UnaryInterceptor(),
+			interceptors.NewRateLimitInterceptor(limiter).UnaryInterceptor(),
 			a.authInterceptor.UnaryInterceptor(),
 		),
 	)
@@ -139,6 +150,18 @@ func (a *GRPCVanillaAdapter) Start(addr string) error {
 	}
 	a.listener = listener
 
+	// gRPC has no HTTP routes, so metrics get their own listener
+	if metricsAddr := getEnv("CONFIG_METRICS_ADDR", ""); metricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle(metrics.Path, metrics.Handler())
+		go func() {
+			log.Printf("gRPC metrics serving on http://%s%s", metricsAddr, metrics.Path)
+			if err := http.ListenAndServe(metricsAddr, mux); err != nil {
+				log.Printf("WARNING: metrics listener stopped: %v", err)
+			}
+		}()
+	}
+
 	log.Printf("gRPC server starting on %s", addr)
 	return a.server.Serve(listener)
 }
@@ -153,6 +176,9 @@ func (a *GRPCVanillaAdapter) IsHealthy(ctx context.Context) error {
 
 // Close shuts down the gRPC server.
 func (a *GRPCVanillaAdapter) Close() error {
+	if err := a.rateLimiter.Close(); err != nil {
+		log.Printf("WARNING: failed to close rate limiter: %v", err)
+	}
 	if a.server != nil {
 		log.Printf("gRPC adapter closing")
 		a.server.GracefulStop()
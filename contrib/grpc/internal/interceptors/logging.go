@@ -4,40 +4,96 @@ package interceptors
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/metrics"
+	"github.com/erniealice/espyna-golang/shared/tracing"
 )
 
-// LoggingInterceptor provides request/response logging for gRPC requests
+// LoggingInterceptor assigns each call a correlation ID and logs it as a
+// structured JSON line
 type LoggingInterceptor struct{}
 
 // NewLoggingInterceptor creates a new logging interceptor instance
 func NewLoggingInterceptor() *LoggingInterceptor {
+	correlation.InstallDefault()
 	return &LoggingInterceptor{}
 }
 
-// UnaryInterceptor returns a unary server interceptor that logs requests and responses
+// UnaryInterceptor returns a unary server interceptor that takes the
+// correlation ID from the x-request-id metadata (or generates one), stores
+// it on the context for use cases and downstream adapters, returns it in the
+// response header, and logs method, status code and latency. Calls are
+// counted in the request metrics with method GRPC and their status code,
+// and traced as server spans continuing the caller's traceparent metadata.
 func (i *LoggingInterceptor) UnaryInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
 
-		// Log request
-		log.Printf("GRPC_REQUEST: method=%s", info.FullMethod)
+		var incoming string
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(correlation.MetadataKey); len(values) > 0 {
+			incoming = values[0]
+		}
+		id := correlation.Accept(incoming)
+		ctx = correlation.WithID(ctx, id)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(correlation.MetadataKey, id))
+		ctx, span := tracing.StartServer(ctx, info.FullMethod, metadataCarrier(md),
+			tracing.String("rpc.system", "grpc"),
+			tracing.String("rpc.method", info.FullMethod),
+			tracing.String(correlation.LogKey, id),
+		)
 
 		// Call handler
 		resp, err := handler(ctx, req)
 
-		// Log response
-		duration := time.Since(start)
+		latency := time.Since(start)
+		code := status.Code(err)
+		metrics.ObserveRequest("GRPC", info.FullMethod, int(code), latency)
+		span.SetAttributes(tracing.Int("rpc.grpc.status_code", int(code)))
+		span.End(err)
+
+		level := slog.LevelInfo
+		attrs := []slog.Attr{
+			slog.String("method", info.FullMethod),
+			slog.String("code", code.String()),
+			slog.Float64("latency_ms", float64(latency.Microseconds())/1000),
+		}
 		if err != nil {
-			log.Printf("GRPC_RESPONSE: method=%s status=error duration=%s error=%v", info.FullMethod, duration, err)
-		} else {
-			log.Printf("GRPC_RESPONSE: method=%s status=ok duration=%s", info.FullMethod, duration)
+			level = slog.LevelError
+			attrs = append(attrs, slog.String("error", err.Error()))
 		}
+		slog.LogAttrs(ctx, level, "grpc request", attrs...)
 
 		return resp, err
 	}
 }
+
+// metadataCarrier reads the trace context from incoming gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
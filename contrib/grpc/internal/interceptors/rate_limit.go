@@ -0,0 +1,68 @@
+//go:build grpc
+
+package interceptors
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/erniealice/espyna-golang/composition/contracts"
+)
+
+// RateLimitInterceptor applies the routing config's rate limits to gRPC
+// calls
+type RateLimitInterceptor struct {
+	limiter *contracts.RateLimiter
+}
+
+// NewRateLimitInterceptor creates a rate limit interceptor. A nil limiter
+// lets every call through.
+func NewRateLimitInterceptor(limiter *contracts.RateLimiter) *RateLimitInterceptor {
+	return &RateLimitInterceptor{limiter: limiter}
+}
+
+// UnaryInterceptor returns a unary server interceptor that identifies the
+// caller by peer address and x-workspace-id metadata, matches endpoint
+// limits against the full method name, and answers calls over a limit with
+// ResourceExhausted. The rate limit headers are sent as response metadata.
+func (i *RateLimitInterceptor) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if i.limiter == nil {
+			return handler(ctx, req)
+		}
+
+		var workspaceID, clientIP string
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(MetadataKeyXWorkspaceID); len(values) > 0 {
+			workspaceID = values[0]
+		}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			clientIP = p.Addr.String()
+			if host, _, err := net.SplitHostPort(clientIP); err == nil {
+				clientIP = host
+			}
+		}
+
+		decision := i.limiter.Allow(ctx, contracts.RateLimitRequest{
+			Path:        info.FullMethod,
+			ClientIP:    clientIP,
+			WorkspaceID: workspaceID,
+		})
+		if headers := decision.Headers(); len(headers) > 0 {
+			_ = grpc.SetHeader(ctx, metadata.New(headers))
+		}
+		if !decision.Allowed {
+			return nil, status.Errorf(codes.ResourceExhausted, "%s rate limit exceeded, retry after %s",
+				decision.Scope, decision.RetryAfter.Round(time.Millisecond))
+		}
+		return handler(ctx, req)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -20,8 +21,11 @@ import (
 	"github.com/erniealice/espyna-golang/composition/core"
 	"github.com/erniealice/espyna-golang/composition/routing"
 	"github.com/erniealice/espyna-golang/composition/routing/customization"
+	"github.com/erniealice/espyna-golang/composition/routing/openapi"
+	vanillaMiddleware "github.com/erniealice/espyna-golang/contrib/http/internal/adapter/middleware"
 	"github.com/erniealice/espyna-golang/ports"
 	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/metrics"
 )
 
 // =============================================================================
@@ -54,6 +58,9 @@ type VanillaAdapter struct {
 	container *core.Container
 	enabled   bool
 	server    *http.Server
+	limiter   *contracts.RateLimiter
+	idem      *contracts.Idempotency
+	authz     *contracts.RouteAuthorizer
 }
 
 // NewVanillaAdapter creates a new vanilla HTTP server adapter.
@@ -87,6 +94,29 @@ func (a *VanillaAdapter) Initialize(container any) error {
 	// Install espyna routes
 	a.installRoutes()
 
+	// Rate limits from the routing config (CONFIG_RATE_LIMIT_*)
+	limiter, err := contracts.NewRateLimiter(c.GetRouteManager().GetConfig().RateLimit)
+	if err != nil {
+		log.Printf("WARNING: rate limiting disabled: %v", err)
+	}
+	a.limiter = limiter
+
+	// Route-level RBAC (CONFIG_AUTHORIZATION_*), checked against the
+	// container's authorizer
+	authz, err := contracts.NewRouteAuthorizer(c.GetRouteManager().GetConfig().Authorization,
+		customization.NewRouteCustomizer().ApplyCustomizations(c.GetRouteManager().GetAllRoutes()), c.GetAuthorizer())
+	if err != nil {
+		return fmt.Errorf("route authorization: %w", err)
+	}
+	a.authz = authz
+
+	// Idempotency keys for POST requests (CONFIG_IDEMPOTENCY_*)
+	idem, err := contracts.NewIdempotency(c.GetRouteManager().GetConfig().Idempotency, c.GetDatabaseOperations())
+	if err != nil {
+		log.Printf("WARNING: idempotency keys disabled: %v", err)
+	}
+	a.idem = idem
+
 	// Add default health endpoint
 	a.mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -97,6 +127,9 @@ func (a *VanillaAdapter) Initialize(container any) error {
 		})
 	})
 
+	// Prometheus metrics
+	a.mux.Handle(metrics.Path, metrics.Handler())
+
 	log.Printf("HTTP adapter initialized successfully")
 	return nil
 }
@@ -112,6 +145,20 @@ func (a *VanillaAdapter) installRoutes() {
 	for _, route := range routes {
 		a.installRouteOnMux(route)
 	}
+	a.installOpenAPI(routes)
+}
+
+// installOpenAPI serves the OpenAPI document describing the installed routes.
+func (a *VanillaAdapter) installOpenAPI(routes []*routing.Route) {
+	doc, err := openapi.Marshal(routes, openapi.DefaultInfo)
+	if err != nil {
+		log.Printf("WARNING: OpenAPI document not served: %v", err)
+		return
+	}
+	a.mux.HandleFunc(openapi.Path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(doc)
+	})
 }
 
 // installRouteOnMux installs a single route on the HTTP mux
@@ -133,7 +180,7 @@ func (a *VanillaAdapter) createHTTPHandler(route *routing.Route) http.HandlerFun
 		if r.Method == "OPTIONS" {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key")
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
@@ -142,8 +189,8 @@ func (a *VanillaAdapter) createHTTPHandler(route *routing.Route) http.HandlerFun
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Content-Type", "application/json")
 
-		// Set timeout context
-		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		// Bound the request by the route's time budget
+		ctx, cancel := contracts.WithRouteDeadline(r.Context(), route)
 		defer cancel()
 
 		// Add user context for mock auth
@@ -179,8 +226,24 @@ func (a *VanillaAdapter) createHTTPHandler(route *routing.Route) http.HandlerFun
 			}
 		}
 
+		// ?stream=true writes every page of a list route as it is fetched
+		if r.URL.Query().Get(contracts.StreamQueryParam) == "true" {
+			streamRecords(ctx, w, route, req)
+			return
+		}
+
+		// Export routes write every record as a CSV or XLSX download
+		if exporter, ok := route.Handler.(contracts.RecordExporter); ok {
+			exportRecords(ctx, w, r, route, exporter, req)
+			return
+		}
+
 		// Execute handler
-		resp, err := route.Handler.Execute(ctx, req)
+		resp, err := contracts.ExecuteRoute(ctx, route, req)
+		if errors.Is(err, contracts.ErrRouteTimeout) {
+			writeJSONError(w, http.StatusGatewayTimeout, "Request timed out", err.Error())
+			return
+		}
 		if err != nil {
 			writeJSONError(w, http.StatusInternalServerError, "Handler execution failed", err.Error())
 			return
@@ -206,8 +269,9 @@ func (a *VanillaAdapter) Start(addr string) error {
 
 	printServerInfo("http", addr)
 
-	// Wrap the mux with CORS and Gzip middleware
-	handler := corsMiddleware(gzipMiddleware(a.mux))
+	// Wrap the mux with request logging, rate limiting, CORS, Gzip, route
+	// authorization and idempotency key middleware
+	handler := vanillaMiddleware.RequestLogger(vanillaMiddleware.RateLimit(a.limiter)(corsMiddleware(gzipMiddleware(vanillaMiddleware.RouteAuthorization(a.authz)(vanillaMiddleware.Idempotency(a.idem)(vanillaMiddleware.RecordRoute(a.mux)))))))
 
 	a.server = &http.Server{
 		Addr:    addr,
@@ -227,6 +291,12 @@ func (a *VanillaAdapter) IsHealthy(ctx context.Context) error {
 
 // Close shuts down the vanilla server.
 func (a *VanillaAdapter) Close() error {
+	if err := a.limiter.Close(); err != nil {
+		log.Printf("WARNING: failed to close rate limiter: %v", err)
+	}
+	if err := a.idem.Close(); err != nil {
+		log.Printf("WARNING: failed to close idempotency store: %v", err)
+	}
 	if a.server != nil {
 		log.Printf("HTTP adapter closing")
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -279,12 +349,87 @@ func writeJSONError(w http.ResponseWriter, status int, message, details string)
 	json.NewEncoder(w).Encode(response)
 }
 
+// streamRecords writes a list route's records as they arrive from the
+// handler, flushing periodically, so large exports never sit in memory as
+// one protobuf slice or JSON buffer.
+func streamRecords(ctx context.Context, w http.ResponseWriter, route *routing.Route, req proto.Message) {
+	streamer, ok := route.Handler.(contracts.RecordStreamer)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, contracts.ErrStreamingUnsupported.Error(), route.Path)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	sw := contracts.NewJSONStreamWriter(w, func() { _ = rc.Flush() })
+	err := streamer.StreamRecords(ctx, req, sw.Write)
+	if err != nil && !sw.Started() {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, contracts.ErrStreamingUnsupported):
+			status = http.StatusBadRequest
+		case errors.Is(err, context.DeadlineExceeded):
+			status = http.StatusGatewayTimeout
+		}
+		writeJSONError(w, status, "Handler execution failed", err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("WARNING: stream for %s failed after output started: %v", route.Path, err)
+	}
+	if err := sw.Close(err); err != nil {
+		log.Printf("WARNING: failed to finish stream for %s: %v", route.Path, err)
+	}
+}
+
+// exportRecords writes an export route's records as a CSV or XLSX
+// attachment, flushing periodically. A failure after the file has started
+// aborts the response, so the client sees a broken download rather than a
+// short file.
+func exportRecords(ctx context.Context, w http.ResponseWriter, r *http.Request, route *routing.Route, exporter contracts.RecordExporter, req proto.Message) {
+	query := r.URL.Query()
+	opts, err := contracts.ParseExportOptions(query.Get(contracts.ExportFormatParam), query.Get(contracts.ExportColumnsParam))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "Invalid export request", err.Error())
+		return
+	}
+	rc := http.NewResponseController(w)
+	ew, err := contracts.NewExportWriter(w, exporter, opts, func() { _ = rc.Flush() })
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "Invalid export request", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", opts.Format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", ew.Filename(time.Now())))
+	err = exporter.StreamRecords(ctx, req, ew.Write)
+	if err != nil && !ew.Started() {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Del("Content-Disposition")
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, contracts.ErrStreamingUnsupported), errors.Is(err, contracts.ErrInvalidExport):
+			status = http.StatusBadRequest
+		case errors.Is(err, context.DeadlineExceeded):
+			status = http.StatusGatewayTimeout
+		}
+		writeJSONError(w, status, "Export failed", err.Error())
+		return
+	}
+	if closeErr := ew.Close(err); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Printf("WARNING: export for %s failed after %d records: %v", route.Path, ew.Count(), err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
 // corsMiddleware adds CORS headers to responses
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusNoContent)
@@ -324,6 +469,15 @@ func (w *gzipResponseWriter) Write(b []byte) (int, error) {
 	return w.Writer.Write(b)
 }
 
+// Flush pushes compressed output to the client so streamed responses are
+// not held back by the gzip buffer.
+func (w *gzipResponseWriter) Flush() {
+	if gz, ok := w.Writer.(*gzip.Writer); ok {
+		_ = gz.Flush()
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
 // printServerInfo prints server startup information
 func printServerInfo(framework, addr string) {
 	fmt.Printf("\n")
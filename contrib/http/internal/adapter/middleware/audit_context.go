@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	infraports "github.com/erniealice/espyna-golang/ports"
+	"github.com/erniealice/espyna-golang/shared/correlation"
 	"github.com/erniealice/espyna-golang/shared/identity"
 )
 
@@ -27,8 +28,12 @@ func AuditContextMiddleware(next http.Handler) http.Handler {
 			actorType = "system"
 		}
 
-		// Request ID: use incoming header or generate one
-		requestID := r.Header.Get("X-Request-ID")
+		// Request ID: reuse the correlation ID assigned by RequestLogger,
+		// else the incoming header, else generate one
+		requestID := correlation.FromContext(r.Context())
+		if requestID == "" {
+			requestID = r.Header.Get("X-Request-ID")
+		}
 		if requestID == "" {
 			requestID = uuid.New().String()
 		}
@@ -89,6 +89,16 @@ func (m *AuthenticationMiddleware) RequireAuth(next http.Handler) http.Handler {
 			ctx = context.WithValue(ctx, "expires", resp.Token.ExpiresAt.AsTime().Unix())
 		}
 
+		// A service principal bound to one workspace (an API key) is the
+		// exception: its token names the workspace, so the full identity
+		// is known here.
+		if ws := resp.Token.GetCustomClaims()["workspace_id"]; ws != "" && resp.Identity.GetType() == authpb.IdentityType_IDENTITY_TYPE_SERVICE {
+			ctx = identity.WithRequestIdentity(ctx, &identity.RequestIdentity{
+				UserID:      resp.Identity.GetId(),
+				WorkspaceID: ws,
+			})
+		}
+
 		// Continue with authenticated request
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -105,6 +115,11 @@ func (m *AuthenticationMiddleware) extractToken(r *http.Request) string {
 		return authHeader
 	}
 
+	// API keys may come in their own header
+	if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+		return apiKey
+	}
+
 	// Try token cookie as fallback
 	if cookie, err := r.Cookie("token"); err == nil {
 		return cookie.Value
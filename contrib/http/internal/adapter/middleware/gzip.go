@@ -19,6 +19,15 @@ func (w gzipResponseWriter) Write(b []byte) (int, error) {
 	return w.Writer.Write(b)
 }
 
+// Flush sends the compressed bytes written so far, so streamed responses
+// such as server-sent events reach the client as they are written.
+func (w gzipResponseWriter) Flush() {
+	if gz, ok := w.Writer.(*gzip.Writer); ok {
+		_ = gz.Flush()
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
 // Gzip compresses responses for clients that accept gzip encoding.
 // Pre-compressed binary formats (images, fonts, archives) are excluded
 // by file extension so they pass through without double-compression overhead.
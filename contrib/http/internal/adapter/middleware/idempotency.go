@@ -0,0 +1,125 @@
+//go:build http
+
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/composition/contracts"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// Idempotency makes POST requests carrying an Idempotency-Key header safe
+// to retry: the first response for a key (per workspace, user and route) is
+// stored and sent again, with Idempotent-Replayed: true, to later requests
+// with the key. Requests without the header pass through. A nil tracker
+// passes everything through.
+func Idempotency(tracker *contracts.Idempotency) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if tracker == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(contracts.IdempotencyKeyHeader)
+			if !tracker.Tracks(r.Method, key) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			body, err := io.ReadAll(io.LimitReader(r.Body, int64(tracker.MaxBodyBytes())+1))
+			if err != nil {
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+
+			// The authenticated identity scopes the key; the header counts only
+			// when the identity has no workspace.
+			var workspaceID, userID string
+			if id, ok := identity.FromContext(r.Context()); ok {
+				workspaceID, userID = id.WorkspaceID, id.UserID
+			}
+			if workspaceID == "" {
+				workspaceID = r.Header.Get(contracts.WorkspaceHeader)
+			}
+			claim, resp := tracker.Begin(r.Context(), contracts.IdempotencyRequest{
+				Method:      r.Method,
+				Path:        r.URL.Path,
+				Query:       r.URL.RawQuery,
+				WorkspaceID: workspaceID,
+				UserID:      userID,
+				Key:         key,
+				Body:        body,
+			})
+			if resp != nil {
+				if resp.Replayed {
+					w.Header().Set(contracts.IdempotentReplayedHeader, "true")
+				}
+				if resp.ContentType != "" {
+					w.Header().Set("Content-Type", resp.ContentType)
+				}
+				w.WriteHeader(resp.Status)
+				w.Write(resp.Body)
+				return
+			}
+			if claim == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &idempotencyRecorder{ResponseWriter: w, status: http.StatusOK, limit: tracker.MaxBodyBytes()}
+			defer func() {
+				// A panic is answered with a 500 further out; release the key.
+				if p := recover(); p != nil {
+					claim.Finish(r.Context(), contracts.IdempotentResponse{Status: http.StatusInternalServerError})
+					panic(p)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+			claim.Finish(r.Context(), rec.response())
+		})
+	}
+}
+
+// idempotencyRecorder passes the response through while keeping a copy of
+// it, up to one byte over limit.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	limit       int
+}
+
+func (r *idempotencyRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	if room := r.limit + 1 - r.body.Len(); room > 0 {
+		r.body.Write(b[:min(len(b), room)])
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *idempotencyRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *idempotencyRecorder) response() contracts.IdempotentResponse {
+	return contracts.IdempotentResponse{
+		Status:      r.status,
+		ContentType: r.Header().Get("Content-Type"),
+		Body:        r.body.Bytes(),
+	}
+}
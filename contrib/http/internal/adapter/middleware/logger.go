@@ -6,6 +6,9 @@ import (
 	"log"
 	"net/http"
 	"time"
+
+	"github.com/erniealice/espyna-golang/shared/apilog"
+	"github.com/erniealice/espyna-golang/shared/identity"
 )
 
 // responseWrapper captures the status code
@@ -19,17 +22,27 @@ func (w *responseWrapper) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
-// Logger logs HTTP requests with method, path, status code, and duration
+// Unwrap exposes the underlying writer to http.ResponseController so
+// streamed responses can still be flushed.
+func (w *responseWrapper) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Logger logs HTTP requests with method, path, status code, and duration,
+// and hands them to the workspace's API event log (see shared/apilog).
 func Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		ctx, who := identity.Observe(r.Context())
 		wrapped := &responseWrapper{ResponseWriter: w, statusCode: http.StatusOK}
-		next.ServeHTTP(wrapped, r)
+		next.ServeHTTP(wrapped, r.WithContext(ctx))
+		latency := time.Since(start)
 		log.Printf("%s %s %d %v",
 			r.Method,
 			r.URL.Path,
 			wrapped.statusCode,
-			time.Since(start),
+			latency,
 		)
+		apilog.Record(ctx, who, r.Method, r.URL.Path, wrapped.statusCode, latency)
 	})
 }
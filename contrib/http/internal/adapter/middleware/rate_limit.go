@@ -0,0 +1,51 @@
+//go:build http
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/composition/contracts"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// RateLimit refuses requests over the routing config's rate limits with 429
+// Too Many Requests and a Retry-After header. Requests are identified by
+// client IP and by the workspace in the X-Workspace-ID header (or the
+// request identity, when an earlier middleware set one). Every response
+// carries X-RateLimit-Limit and X-RateLimit-Remaining for the tightest
+// limit. A nil limiter passes everything through.
+func RateLimit(limiter *contracts.RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			workspaceID := r.Header.Get(contracts.WorkspaceHeader)
+			if workspaceID == "" {
+				if id, ok := identity.FromContext(r.Context()); ok {
+					workspaceID = id.WorkspaceID
+				}
+			}
+			decision := limiter.Allow(r.Context(), contracts.RateLimitRequest{
+				Path:        r.URL.Path,
+				ClientIP:    extractClientIP(r),
+				WorkspaceID: workspaceID,
+			})
+			for k, v := range decision.Headers() {
+				w.Header().Set(k, v)
+			}
+			if !decision.Allowed {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+				json.NewEncoder(w).Encode(map[string]string{
+					"error":   "Too many requests",
+					"details": decision.Scope + " rate limit exceeded",
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
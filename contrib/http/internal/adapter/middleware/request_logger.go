@@ -0,0 +1,64 @@
+//go:build http
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/erniealice/espyna-golang/shared/apilog"
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/identity"
+	"github.com/erniealice/espyna-golang/shared/metrics"
+	"github.com/erniealice/espyna-golang/shared/tracing"
+)
+
+// routeKey holds the slot RecordRoute fills with the matched mux pattern.
+type routeKey struct{}
+
+// RequestLogger assigns every request a correlation ID, stores it on the
+// request context for use cases and downstream adapters, echoes it in the
+// X-Request-ID response header, and writes one JSON log line with method,
+// path, status and latency once the request completes. It also records the
+// request in the route metrics and traces it as a server span, both named
+// after the pattern RecordRoute reports, and hands it to the workspace's API
+// event log (see shared/apilog). Install it outermost so the line covers
+// the whole middleware chain.
+func RequestLogger(next http.Handler) http.Handler {
+	correlation.InstallDefault()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := correlation.Accept(r.Header.Get(correlation.Header))
+		w.Header().Set(correlation.Header, id)
+		ctx := correlation.WithID(r.Context(), id)
+		route := new(string)
+		ctx = context.WithValue(ctx, routeKey{}, route)
+		ctx, who := identity.Observe(ctx)
+		ctx, span := tracing.StartServer(ctx, r.Method, tracing.HeaderCarrier(r.Header),
+			tracing.String("http.request.method", r.Method),
+			tracing.String("url.path", r.URL.Path),
+			tracing.String(correlation.LogKey, id),
+		)
+
+		wrapped := &responseWrapper{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(wrapped, r.WithContext(ctx))
+		latency := time.Since(start)
+		correlation.LogRequest(ctx, r.Method, r.URL.Path, wrapped.statusCode, latency)
+		apilog.Record(ctx, who, r.Method, r.URL.Path, wrapped.statusCode, latency)
+		metrics.ObserveRequest(r.Method, *route, wrapped.statusCode, latency)
+		tracing.EndServer(span, r.Method, *route, wrapped.statusCode)
+	})
+}
+
+// RecordRoute wraps the mux and reports the pattern it matched to
+// RequestLogger. Middleware in between may copy the request, so the
+// pattern the mux sets on its request is passed back through the context.
+func RecordRoute(mux http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r)
+		if route, ok := r.Context().Value(routeKey{}).(*string); ok {
+			*route = r.Pattern
+		}
+	})
+}
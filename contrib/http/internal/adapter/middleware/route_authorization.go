@@ -0,0 +1,43 @@
+//go:build http
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/composition/contracts"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// RouteAuthorization refuses requests whose caller lacks the permission
+// their route maps to, in the workspace of the request identity (or the
+// X-Workspace-ID header). It runs after the authentication middleware has
+// set the identity. A nil authorizer passes everything through.
+func RouteAuthorization(authorizer *contracts.RouteAuthorizer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if authorizer == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req := contracts.AuthorizationRequest{Method: r.Method, Path: r.URL.Path}
+			if id, ok := identity.FromContext(r.Context()); ok {
+				req.UserID, req.WorkspaceID = id.UserID, id.WorkspaceID
+			}
+			if req.WorkspaceID == "" {
+				req.WorkspaceID = r.Header.Get(contracts.WorkspaceHeader)
+			}
+			decision := authorizer.Authorize(r.Context(), req)
+			if !decision.Allowed {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(decision.Status)
+				json.NewEncoder(w).Encode(map[string]string{
+					"error":   http.StatusText(decision.Status),
+					"details": decision.Reason,
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
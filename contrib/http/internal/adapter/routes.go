@@ -13,6 +13,7 @@ import (
 	"github.com/erniealice/espyna-golang/composition/contracts"
 	"github.com/erniealice/espyna-golang/composition/routing"
 	"github.com/erniealice/espyna-golang/shared/identity"
+	"github.com/erniealice/espyna-golang/shared/metrics"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -188,6 +189,9 @@ func (s *Server) setupBasicRoutes() {
 		w.Write(jsonBytes)
 	})
 
+	// Prometheus metrics
+	s.mux.Handle(metrics.Path, metrics.Handler())
+
 	// Root endpoint
 	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -40,10 +40,11 @@ func (s *Server) GetHandler() http.Handler {
 
 // Start starts the HTTP server on the specified address
 func (s *Server) Start(addr string) error {
-	// Wrap the mux with BusinessType, CORS, and Gzip middleware
-	// Apply middleware in order: BusinessType -> CORS -> Gzip -> Mux
-	handler := s.businessTypeMw.SetBusinessType(
-		vanillaMiddleware.CORS(
-			vanillaMiddleware.Gzip(s.mux)))
+	// Wrap the mux with RequestLogger, BusinessType, CORS, and Gzip middleware
+	// Apply middleware in order: RequestLogger -> BusinessType -> CORS -> Gzip -> Mux
+	handler := vanillaMiddleware.RequestLogger(
+		s.businessTypeMw.SetBusinessType(
+			vanillaMiddleware.CORS(
+				vanillaMiddleware.Gzip(vanillaMiddleware.RecordRoute(s.mux)))))
 	return http.ListenAndServe(addr, handler)
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/erniealice/espyna-golang/ports"
 	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/correlation"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
 	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -40,6 +41,7 @@ func buildFromEnv() (ports.PaymentProvider, error) {
 	secretKey := os.Getenv("LEAPFOR_INTEGRATION_PAYMENT_MAYA_SECRET_KEY")
 	sandboxMode := os.Getenv("LEAPFOR_INTEGRATION_PAYMENT_MAYA_SANDBOX") == "true"
 	baseURL := os.Getenv("LEAPFOR_INTEGRATION_PAYMENT_MAYA_BASE_URL")
+	webhookVerification := os.Getenv("LEAPFOR_INTEGRATION_PAYMENT_MAYA_WEBHOOK_VERIFICATION")
 
 	if publicKey == "" {
 		return nil, fmt.Errorf("maya: LEAPFOR_INTEGRATION_PAYMENT_MAYA_PUBLIC_KEY is required")
@@ -54,7 +56,14 @@ func buildFromEnv() (ports.PaymentProvider, error) {
 		Enabled:      true,
 		SandboxMode:  sandboxMode,
 		RedirectUrls: &paymentpb.RedirectUrls{
-			BaseUrl: baseURL,
+			BaseUrl:     baseURL,
+			SuccessPath: os.Getenv("LEAPFOR_INTEGRATION_PAYMENT_MAYA_SUCCESS_PATH"),
+			FailurePath: os.Getenv("LEAPFOR_INTEGRATION_PAYMENT_MAYA_FAILURE_PATH"),
+			CancelPath:  os.Getenv("LEAPFOR_INTEGRATION_PAYMENT_MAYA_CANCEL_PATH"),
+			WebhookPath: os.Getenv("LEAPFOR_INTEGRATION_PAYMENT_MAYA_WEBHOOK_PATH"),
+		},
+		WebhookConfig: &paymentpb.WebhookConfig{
+			VerificationMethod: webhookVerification,
 		},
 		Auth: &paymentpb.PaymentProviderConfig_ApiKeyAuth{
 			ApiKeyAuth: &paymentpb.ApiKeyAuth{
@@ -63,6 +72,13 @@ func buildFromEnv() (ports.PaymentProvider, error) {
 			},
 		},
 	}
+	if raw := os.Getenv("LEAPFOR_INTEGRATION_PAYMENT_MAYA_TIMEOUT"); raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout < time.Second {
+			return nil, fmt.Errorf("maya: LEAPFOR_INTEGRATION_PAYMENT_MAYA_TIMEOUT=%q is not a duration like 30s", raw)
+		}
+		protoConfig.TimeoutSeconds = int32(timeout / time.Second)
+	}
 
 	p := NewMayaProvider()
 	if err := p.Initialize(protoConfig); err != nil {
@@ -97,6 +113,9 @@ func transformConfig(rawConfig map[string]any) (*paymentpb.PaymentProviderConfig
 	if sandboxMode, ok := rawConfig["sandbox_mode"].(bool); ok {
 		protoConfig.SandboxMode = sandboxMode
 	}
+	if verification, ok := rawConfig["webhook_verification"].(string); ok {
+		protoConfig.WebhookConfig = &paymentpb.WebhookConfig{VerificationMethod: verification}
+	}
 
 	return protoConfig, nil
 }
@@ -124,8 +143,11 @@ type MayaProvider struct {
 	failurePath string
 	cancelPath  string
 	webhookPath string
-	timeout     time.Duration
-	httpClient  *http.Client
+	// verifyWebhooks re-reads every webhook's payment from the Payments API:
+	// Maya does not sign webhooks, so the payload alone proves nothing.
+	verifyWebhooks bool
+	timeout        time.Duration
+	httpClient     *http.Client
 }
 
 // MayaCheckoutRequest represents the checkout creation request
@@ -193,12 +215,13 @@ type MayaCheckoutResponse struct {
 	RedirectUrl string `json:"redirectUrl"`
 }
 
-// MayaWebhookPayload represents the webhook payload from Maya
+// MayaWebhookPayload represents the webhook payload from Maya. Webhooks
+// post the payment object, so Payments API reads decode into it too.
 type MayaWebhookPayload struct {
 	ID                     string          `json:"id"`
 	IsPaid                 bool            `json:"isPaid"`
 	Status                 string          `json:"status"`
-	Amount                 float64         `json:"amount"`
+	Amount                 MayaDecimal     `json:"amount"`
 	Currency               string          `json:"currency"`
 	CanVoid                bool            `json:"canVoid"`
 	CanRefund              bool            `json:"canRefund"`
@@ -314,12 +337,16 @@ func (p *MayaProvider) Initialize(config *paymentpb.PaymentProviderConfig) error
 		}
 	}
 
+	p.verifyWebhooks = config.GetWebhookConfig().GetVerificationMethod() != webhookVerificationNone
+
 	if config.TimeoutSeconds > 0 {
 		p.timeout = time.Duration(config.TimeoutSeconds) * time.Second
 	}
 
+	// Outbound calls carry the request's correlation ID for tracing
 	p.httpClient = &http.Client{
-		Timeout: p.timeout,
+		Timeout:   p.timeout,
+		Transport: correlation.NewTransport(nil),
 	}
 
 	p.enabled = config.Enabled
@@ -558,73 +585,35 @@ func (p *MayaProvider) ProcessWebhook(ctx context.Context, req *paymentpb.Proces
 		}, nil
 	}
 
-	// Map Maya status to our status
-	var status paymentpb.PaymentStatus
-	var action string
-
-	switch webhook.PaymentStatus {
-	case "PAYMENT_SUCCESS":
-		status = paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS
-		action = "success"
-	case "PAYMENT_FAILED":
-		status = paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED
-		action = "failure"
-	case "PAYMENT_EXPIRED":
-		status = paymentpb.PaymentStatus_PAYMENT_STATUS_EXPIRED
-		action = "expired"
-	case "PAYMENT_CANCELLED":
-		status = paymentpb.PaymentStatus_PAYMENT_STATUS_CANCELLED
-		action = "cancelled"
-	case "AUTHORIZED":
-		status = paymentpb.PaymentStatus_PAYMENT_STATUS_AUTHORIZED
-		action = "authorized"
-	default:
-		status = paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING
-		action = "processing"
-	}
-
-	// Extract payment method details
-	methodDetails := &paymentpb.PaymentMethodDetails{}
-	paymentMethod := "unknown"
-	if webhook.FundSource != nil {
-		paymentMethod = webhook.FundSource.Type
-		methodDetails.Type = webhook.FundSource.Type
-		if webhook.FundSource.Details != nil {
-			methodDetails.LastFour = webhook.FundSource.Details.Last4
-			if webhook.FundSource.Details.ThreeDSecure != nil {
-				methodDetails.Eci = webhook.FundSource.Details.ThreeDSecure.Eci
-				methodDetails.PayerAuth = webhook.FundSource.Details.ThreeDSecure.Status
-			}
+	// Trust the payment as the API reports it, not as posted
+	if p.verifyWebhooks {
+		if webhook.ID == "" {
+			return &paymentpb.ProcessWebhookResponse{
+				Success: false,
+				Error: &commonpb.Error{
+					Code:        "WEBHOOK_VERIFICATION_FAILED",
+					Description: "Webhook payload has no payment ID",
+					Category:    commonpb.ErrorCategory_ERROR_CATEGORY_VALIDATION,
+				},
+			}, nil
 		}
-	}
-
-	// Extract payment_id from metadata if available
-	paymentId := webhook.RequestReferenceNumber
-	if webhook.Metadata != nil {
-		if pid, ok := webhook.Metadata["payment_id"].(string); ok && pid != "" {
-			paymentId = pid
+		payment, err := p.getPayment(ctx, webhook.ID)
+		if err != nil {
+			return &paymentpb.ProcessWebhookResponse{
+				Success: false,
+				Error: &commonpb.Error{
+					Code:        "WEBHOOK_VERIFICATION_FAILED",
+					Description: fmt.Sprintf("Failed to confirm Maya payment %s: %v", webhook.ID, err),
+					Category:    commonpb.ErrorCategory_ERROR_CATEGORY_EXTERNAL_SERVICE,
+				},
+			}, nil
 		}
+		webhook = *payment
 	}
 
-	transaction := &paymentpb.PaymentTransaction{
-		Id:                 webhook.ID,
-		ProviderRef:        webhook.RequestReferenceNumber,
-		ProviderPaymentRef: webhook.ReceiptNumber,
-		ProviderId:         "maya",
-		Status:             status,
-		Amount:             int64(webhook.Amount * 100), // Convert to cents
-		Currency:           webhook.Currency,
-		PaymentMethod:      paymentMethod,
-		MethodDetails:      methodDetails,
-		PaymentId:          paymentId,
-		OrderRef:           webhook.RequestReferenceNumber,
-		ProcessedAt:        timestamppb.Now(),
-		RawData: map[string]string{
-			"id":             webhook.ID,
-			"payment_status": webhook.PaymentStatus,
-			"is_paid":        fmt.Sprintf("%v", webhook.IsPaid),
-		},
-	}
+	status, action := mayaPaymentStatus(webhook.PaymentStatus)
+	transaction := paymentTransaction(&webhook)
+	paymentId := transaction.PaymentId
 
 	log.Printf("📨 Maya webhook processed: %s -> %s", webhook.ID, action)
 	return &paymentpb.ProcessWebhookResponse{
@@ -638,40 +627,6 @@ func (p *MayaProvider) ProcessWebhook(ctx context.Context, req *paymentpb.Proces
 	}, nil
 }
 
-func (p *MayaProvider) GetPaymentStatus(ctx context.Context, req *paymentpb.GetPaymentStatusRequest) (*paymentpb.GetPaymentStatusResponse, error) {
-	if !p.enabled {
-		return nil, fmt.Errorf("Maya provider is not initialized")
-	}
-
-	// Maya supports payment status queries via GET /payments/v1/payments/{paymentId}
-	// For now, return not supported - can be implemented later
-	return &paymentpb.GetPaymentStatusResponse{
-		Success: false,
-		Error: &commonpb.Error{
-			Code:        "NOT_SUPPORTED",
-			Description: "Direct status query not implemented - use webhook callbacks",
-			Category:    commonpb.ErrorCategory_ERROR_CATEGORY_EXTERNAL_SERVICE,
-		},
-	}, nil
-}
-
-func (p *MayaProvider) RefundPayment(ctx context.Context, req *paymentpb.RefundPaymentRequest) (*paymentpb.RefundPaymentResponse, error) {
-	if !p.enabled {
-		return nil, fmt.Errorf("Maya provider is not initialized")
-	}
-
-	// Maya supports refunds via POST /payments/v1/payments/{paymentId}/refunds
-	// Requires secret key authentication
-	return &paymentpb.RefundPaymentResponse{
-		Success: false,
-		Error: &commonpb.Error{
-			Code:        "NOT_SUPPORTED",
-			Description: "Refund API not implemented - contact support or use Maya dashboard",
-			Category:    commonpb.ErrorCategory_ERROR_CATEGORY_EXTERNAL_SERVICE,
-		},
-	}, nil
-}
-
 func (p *MayaProvider) IsHealthy(ctx context.Context) error {
 	if !p.enabled {
 		return fmt.Errorf("Maya provider is not initialized")
@@ -698,6 +653,7 @@ func (p *MayaProvider) GetCapabilities() []paymentpb.PaymentCapability {
 		paymentpb.PaymentCapability_PAYMENT_CAPABILITY_WEBHOOKS,
 		paymentpb.PaymentCapability_PAYMENT_CAPABILITY_3DS,
 		paymentpb.PaymentCapability_PAYMENT_CAPABILITY_REFUND,
+		paymentpb.PaymentCapability_PAYMENT_CAPABILITY_PARTIAL_REFUND,
 		paymentpb.PaymentCapability_PAYMENT_CAPABILITY_VOID,
 		paymentpb.PaymentCapability_PAYMENT_CAPABILITY_TOKENIZATION,
 	}
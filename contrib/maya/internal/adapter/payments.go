@@ -0,0 +1,407 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Maya Payments API endpoints; they take the secret key.
+const (
+	paymentsPath    = "/payments/v1/payments"
+	paymentRRNsPath = "/payments/v1/payment-rrns"
+)
+
+// webhookVerificationNone turns off re-reading webhook payments from the
+// API (WebhookConfig.VerificationMethod). Only for replaying captured
+// payloads locally: Maya does not sign its webhooks.
+const webhookVerificationNone = "none"
+
+// errPaymentNotFound is returned by the payment reads on a 404.
+var errPaymentNotFound = errors.New("Maya payment not found")
+
+// MayaDecimal is an amount Maya sends either as a JSON number or as a
+// decimal string ("1500.00").
+type MayaDecimal float64
+
+// UnmarshalJSON accepts both forms.
+func (d *MayaDecimal) UnmarshalJSON(b []byte) error {
+	s := string(bytes.Trim(b, `"`))
+	if s == "" || s == "null" {
+		*d = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Maya amount %s: %w", b, err)
+	}
+	*d = MayaDecimal(v)
+	return nil
+}
+
+// minorUnits converts a major-unit amount to centavos.
+func (d MayaDecimal) minorUnits() int64 {
+	return int64(math.Round(float64(d) * 100))
+}
+
+// MayaRefundRequest is the body of a refund.
+type MayaRefundRequest struct {
+	TotalAmount MayaRefundAmount `json:"totalAmount"`
+	Reason      string           `json:"reason"`
+}
+
+// MayaRefundAmount is a refund amount; refunds name the field "amount"
+// where checkouts use "value".
+type MayaRefundAmount struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// MayaVoidRequest is the body of a void.
+type MayaVoidRequest struct {
+	Reason string `json:"reason"`
+}
+
+// MayaRefundResponse is a refund or void as Maya reports it.
+type MayaRefundResponse struct {
+	ID          string `json:"id"`
+	Payment     string `json:"payment"`
+	Status      string `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+	TotalAmount *struct {
+		Amount   MayaDecimal `json:"amount"`
+		Currency string      `json:"currency"`
+	} `json:"totalAmount,omitempty"`
+}
+
+// mayaPaymentStatus maps a Maya payment status to ours and to the action a
+// webhook reports.
+func mayaPaymentStatus(paymentStatus string) (paymentpb.PaymentStatus, string) {
+	switch paymentStatus {
+	case "PAYMENT_SUCCESS":
+		return paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS, "success"
+	case "PAYMENT_FAILED", "AUTH_FAILED":
+		return paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED, "failure"
+	case "PAYMENT_EXPIRED":
+		return paymentpb.PaymentStatus_PAYMENT_STATUS_EXPIRED, "expired"
+	case "PAYMENT_CANCELLED", "VOIDED":
+		return paymentpb.PaymentStatus_PAYMENT_STATUS_CANCELLED, "cancelled"
+	case "REFUNDED":
+		return paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED, "refunded"
+	case "AUTHORIZED":
+		return paymentpb.PaymentStatus_PAYMENT_STATUS_AUTHORIZED, "authorized"
+	case "PENDING_TOKEN", "PENDING_PAYMENT":
+		return paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING, "pending"
+	}
+	return paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING, "processing"
+}
+
+// paymentTransaction maps a Maya payment, from a webhook or the Payments
+// API, to a transaction.
+func paymentTransaction(payment *MayaWebhookPayload) *paymentpb.PaymentTransaction {
+	status, _ := mayaPaymentStatus(payment.PaymentStatus)
+
+	methodDetails := &paymentpb.PaymentMethodDetails{}
+	paymentMethod := "unknown"
+	if payment.FundSource != nil {
+		paymentMethod = payment.FundSource.Type
+		methodDetails.Type = payment.FundSource.Type
+		if payment.FundSource.Details != nil {
+			methodDetails.LastFour = payment.FundSource.Details.Last4
+			if payment.FundSource.Details.ThreeDSecure != nil {
+				methodDetails.Eci = payment.FundSource.Details.ThreeDSecure.Eci
+				methodDetails.PayerAuth = payment.FundSource.Details.ThreeDSecure.Status
+			}
+		}
+	}
+
+	// Checkouts put our payment_id in the metadata; fall back to the
+	// reference number, which is the payment ID when no order ref was set.
+	paymentID := payment.RequestReferenceNumber
+	if pid, ok := payment.Metadata["payment_id"].(string); ok && pid != "" {
+		paymentID = pid
+	}
+
+	return &paymentpb.PaymentTransaction{
+		Id:                 payment.ID,
+		ProviderRef:        payment.RequestReferenceNumber,
+		ProviderPaymentRef: payment.ReceiptNumber,
+		ProviderId:         "maya",
+		Status:             status,
+		Amount:             payment.Amount.minorUnits(),
+		Currency:           payment.Currency,
+		PaymentMethod:      paymentMethod,
+		MethodDetails:      methodDetails,
+		PaymentId:          paymentID,
+		OrderRef:           payment.RequestReferenceNumber,
+		ProcessedAt:        timestamppb.Now(),
+		RawData: map[string]string{
+			"id":             payment.ID,
+			"payment_status": payment.PaymentStatus,
+			"is_paid":        fmt.Sprintf("%v", payment.IsPaid),
+		},
+	}
+}
+
+// GetPaymentStatus reads the payment from the Payments API. ProviderRef is
+// tried as the Maya payment ID (a Maya Checkout's checkout ID is its
+// payment ID), then as the request reference number, as is PaymentId.
+func (p *MayaProvider) GetPaymentStatus(ctx context.Context, req *paymentpb.GetPaymentStatusRequest) (*paymentpb.GetPaymentStatusResponse, error) {
+	if !p.enabled {
+		return nil, fmt.Errorf("Maya provider is not initialized")
+	}
+
+	data := req.Data
+	if data == nil || (data.ProviderRef == "" && data.PaymentId == "") {
+		return &paymentpb.GetPaymentStatusResponse{
+			Success: false,
+			Error: &commonpb.Error{
+				Code:        "INVALID_REQUEST",
+				Description: "Provider reference or payment ID is required",
+				Category:    commonpb.ErrorCategory_ERROR_CATEGORY_VALIDATION,
+			},
+		}, nil
+	}
+
+	payment, err := p.findPayment(ctx, data.ProviderRef, data.ProviderRef, data.PaymentId)
+	if err != nil {
+		return &paymentpb.GetPaymentStatusResponse{
+			Success: false,
+			Error: &commonpb.Error{
+				Code:        "MAYA_API_ERROR",
+				Description: fmt.Sprintf("Failed to read Maya payment: %v", err),
+				Category:    commonpb.ErrorCategory_ERROR_CATEGORY_EXTERNAL_SERVICE,
+			},
+		}, nil
+	}
+
+	transaction := paymentTransaction(payment)
+	return &paymentpb.GetPaymentStatusResponse{
+		Success: true,
+		Data: []*paymentpb.PaymentStatusData{{
+			Status:      transaction.Status,
+			Transaction: transaction,
+		}},
+	}, nil
+}
+
+// RefundPayment refunds a captured payment, in full when Amount is zero.
+// TransactionId is the Maya payment ID; without it ProviderRef is looked
+// up as the request reference number. A full refund of a payment Maya can
+// still void (same day, not yet settled) is sent as a void.
+func (p *MayaProvider) RefundPayment(ctx context.Context, req *paymentpb.RefundPaymentRequest) (*paymentpb.RefundPaymentResponse, error) {
+	if !p.enabled {
+		return nil, fmt.Errorf("Maya provider is not initialized")
+	}
+
+	data := req.Data
+	if data == nil || (data.TransactionId == "" && data.ProviderRef == "") {
+		return &paymentpb.RefundPaymentResponse{
+			Success: false,
+			Error: &commonpb.Error{
+				Code:        "INVALID_REQUEST",
+				Description: "Transaction ID or provider reference is required",
+				Category:    commonpb.ErrorCategory_ERROR_CATEGORY_VALIDATION,
+			},
+		}, nil
+	}
+
+	payment, err := p.findPayment(ctx, data.TransactionId, data.ProviderRef)
+	if err != nil {
+		return &paymentpb.RefundPaymentResponse{
+			Success: false,
+			Error: &commonpb.Error{
+				Code:        "MAYA_API_ERROR",
+				Description: fmt.Sprintf("Failed to read Maya payment: %v", err),
+				Category:    commonpb.ErrorCategory_ERROR_CATEGORY_EXTERNAL_SERVICE,
+			},
+		}, nil
+	}
+
+	captured := payment.Amount.minorUnits()
+	amount := data.Amount
+	if amount <= 0 {
+		amount = captured
+	}
+	if amount > captured {
+		return &paymentpb.RefundPaymentResponse{
+			Success: false,
+			Error: &commonpb.Error{
+				Code:        "INVALID_AMOUNT",
+				Description: fmt.Sprintf("Refund of %d exceeds the captured %d", amount, captured),
+				Category:    commonpb.ErrorCategory_ERROR_CATEGORY_VALIDATION,
+			},
+		}, nil
+	}
+	reason := data.Reason
+	if reason == "" {
+		reason = "Refund requested by merchant"
+	}
+
+	var result *MayaRefundResponse
+	switch {
+	case payment.CanVoid && amount == captured:
+		result, err = p.postPayment(ctx, payment.ID, "voids", MayaVoidRequest{Reason: reason})
+	case payment.CanRefund:
+		result, err = p.postPayment(ctx, payment.ID, "refunds", MayaRefundRequest{
+			TotalAmount: MayaRefundAmount{Amount: float64(amount) / 100.0, Currency: payment.Currency},
+			Reason:      reason,
+		})
+	default:
+		return &paymentpb.RefundPaymentResponse{
+			Success: false,
+			Error: &commonpb.Error{
+				Code:        "NOT_REFUNDABLE",
+				Description: fmt.Sprintf("Maya payment %s (%s) can be neither voided nor refunded", payment.ID, payment.PaymentStatus),
+				Category:    commonpb.ErrorCategory_ERROR_CATEGORY_VALIDATION,
+			},
+		}, nil
+	}
+	if err != nil {
+		return &paymentpb.RefundPaymentResponse{
+			Success: false,
+			Error: &commonpb.Error{
+				Code:        "MAYA_API_ERROR",
+				Description: fmt.Sprintf("Failed to refund Maya payment: %v", err),
+				Category:    commonpb.ErrorCategory_ERROR_CATEGORY_EXTERNAL_SERVICE,
+			},
+		}, nil
+	}
+
+	refund := &paymentpb.RefundResponse{
+		Success:     true,
+		RefundId:    result.ID,
+		Amount:      amount,
+		ProviderRef: payment.ID,
+	}
+	switch result.Status {
+	case "SUCCESS", "VOIDED", "REFUNDED":
+		refund.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED
+		if amount < captured {
+			refund.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_PARTIAL_REFUND
+		}
+	case "FAILED":
+		refund.Success = false
+		refund.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED
+		refund.ErrorMessage = "Maya declined the refund"
+	default:
+		refund.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING
+	}
+
+	log.Printf("💸 Maya refund %s for payment %s: %s", result.ID, payment.ID, result.Status)
+	return &paymentpb.RefundPaymentResponse{Success: refund.Success, Data: []*paymentpb.RefundResponse{refund}}, nil
+}
+
+// findPayment reads the payment with ID paymentID, or else the payment
+// carrying one of the request reference numbers. Several payments can share
+// a reference number (a failed attempt, then a paid one); the paid one wins.
+func (p *MayaProvider) findPayment(ctx context.Context, paymentID string, referenceNumbers ...string) (*MayaWebhookPayload, error) {
+	if paymentID != "" {
+		payment, err := p.getPayment(ctx, paymentID)
+		if !errors.Is(err, errPaymentNotFound) {
+			return payment, err
+		}
+	}
+	for _, rrn := range referenceNumbers {
+		if rrn == "" {
+			continue
+		}
+		var payments []MayaWebhookPayload
+		err := p.secretRequest(ctx, http.MethodGet, paymentRRNsPath+"/"+url.PathEscape(rrn), nil, &payments)
+		if errors.Is(err, errPaymentNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var best *MayaWebhookPayload
+		for i := range payments {
+			if best == nil || (payments[i].IsPaid && !best.IsPaid) {
+				best = &payments[i]
+			}
+		}
+		if best != nil {
+			return best, nil
+		}
+	}
+	return nil, errPaymentNotFound
+}
+
+func (p *MayaProvider) getPayment(ctx context.Context, paymentID string) (*MayaWebhookPayload, error) {
+	var payment MayaWebhookPayload
+	if err := p.secretRequest(ctx, http.MethodGet, paymentsPath+"/"+url.PathEscape(paymentID), nil, &payment); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// postPayment posts body to a payment's "voids" or "refunds".
+func (p *MayaProvider) postPayment(ctx context.Context, paymentID, action string, body any) (*MayaRefundResponse, error) {
+	var result MayaRefundResponse
+	if err := p.secretRequest(ctx, http.MethodPost, paymentsPath+"/"+url.PathEscape(paymentID)+"/"+action, body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// secretRequest calls the Payments API with Basic auth on the secret key,
+// decoding the response into out.
+func (p *MayaProvider) secretRequest(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, p.apiEndpoint+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte(p.secretKey + ":"))
+	httpReq.Header.Set("Authorization", "Basic "+auth)
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return errPaymentNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var errResp MayaErrorResponse
+		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Code != "" {
+			return fmt.Errorf("Maya API error [%s]: %s", errResp.Code, errResp.Message)
+		}
+		return fmt.Errorf("Maya API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
+)
+
+// fakeMaya serves the Payments API from payments (by ID) and records the
+// voids and refunds posted.
+type fakeMaya struct {
+	payments map[string]string // ID -> payment JSON
+	posted   []string
+	bodies   []map[string]any
+}
+
+func (f *fakeMaya) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, _, _ := r.BasicAuth(); user != "sk-test" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == paymentRRNsPath+"/rev-1":
+		_, _ = w.Write([]byte("[" + f.payments["failed"] + "," + f.payments["pay-1"] + "]"))
+	case r.Method == http.MethodGet:
+		payment, ok := f.payments[r.URL.Path[len(paymentsPath)+1:]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(payment))
+	case r.Method == http.MethodPost:
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.posted = append(f.posted, r.URL.Path)
+		f.bodies = append(f.bodies, body)
+		_, _ = w.Write([]byte(`{"id":"refund-1","status":"SUCCESS"}`))
+	}
+}
+
+func newTestProvider(t *testing.T, fake *fakeMaya) *MayaProvider {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	p := NewMayaProvider().(*MayaProvider)
+	if err := p.Initialize(&paymentpb.PaymentProviderConfig{
+		Enabled:     true,
+		SandboxMode: true,
+		ApiEndpoint: server.URL,
+		Auth: &paymentpb.PaymentProviderConfig_ApiKeyAuth{
+			ApiKeyAuth: &paymentpb.ApiKeyAuth{ApiKey: "pk-test", ApiSecret: "sk-test"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func testPayments() map[string]string {
+	return map[string]string{
+		"pay-1":  `{"id":"pay-1","isPaid":true,"paymentStatus":"PAYMENT_SUCCESS","amount":"1500.50","currency":"PHP","canVoid":true,"canRefund":false,"requestReferenceNumber":"rev-1","receiptNumber":"R-1","metadata":{"payment_id":"rev-1"},"fundSource":{"type":"qrph"}}`,
+		"pay-2":  `{"id":"pay-2","isPaid":true,"paymentStatus":"PAYMENT_SUCCESS","amount":800,"currency":"PHP","canVoid":false,"canRefund":true,"requestReferenceNumber":"rev-2"}`,
+		"failed": `{"id":"failed","isPaid":false,"paymentStatus":"PAYMENT_FAILED","amount":"1500.50","currency":"PHP","requestReferenceNumber":"rev-1"}`,
+	}
+}
+
+func TestGetPaymentStatus(t *testing.T) {
+	p := newTestProvider(t, &fakeMaya{payments: testPayments()})
+
+	// The checkout ID is the payment ID.
+	resp, err := p.GetPaymentStatus(context.Background(), &paymentpb.GetPaymentStatusRequest{
+		Data: &paymentpb.PaymentStatusLookup{ProviderRef: "pay-1"},
+	})
+	if err != nil || !resp.GetSuccess() {
+		t.Fatalf("status = %v, %v", resp, err)
+	}
+	tx := resp.GetData()[0].GetTransaction()
+	if resp.GetData()[0].GetStatus() != paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS || tx.GetAmount() != 150050 ||
+		tx.GetPaymentId() != "rev-1" || tx.GetProviderPaymentRef() != "R-1" || tx.GetPaymentMethod() != "qrph" {
+		t.Errorf("transaction = %+v", tx)
+	}
+
+	// An unknown ID falls back to the reference number, where the paid
+	// attempt wins over the failed one.
+	resp, _ = p.GetPaymentStatus(context.Background(), &paymentpb.GetPaymentStatusRequest{
+		Data: &paymentpb.PaymentStatusLookup{ProviderRef: "checkout-gone", PaymentId: "rev-1"},
+	})
+	if !resp.GetSuccess() || resp.GetData()[0].GetTransaction().GetId() != "pay-1" {
+		t.Errorf("by reference = %v", resp)
+	}
+
+	resp, _ = p.GetPaymentStatus(context.Background(), &paymentpb.GetPaymentStatusRequest{
+		Data: &paymentpb.PaymentStatusLookup{ProviderRef: "nope"},
+	})
+	if resp.GetSuccess() {
+		t.Errorf("unknown payment reported %v", resp)
+	}
+}
+
+func TestRefundPayment(t *testing.T) {
+	fake := &fakeMaya{payments: testPayments()}
+	p := newTestProvider(t, fake)
+	ctx := context.Background()
+
+	// A voidable payment refunded in full is voided.
+	resp, err := p.RefundPayment(ctx, &paymentpb.RefundPaymentRequest{Data: &paymentpb.RefundData{TransactionId: "pay-1"}})
+	if err != nil || !resp.GetSuccess() || resp.GetData()[0].GetStatus() != paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED {
+		t.Fatalf("void = %v, %v", resp, err)
+	}
+	// A settled one is refunded, here in part.
+	resp, _ = p.RefundPayment(ctx, &paymentpb.RefundPaymentRequest{Data: &paymentpb.RefundData{TransactionId: "pay-2", Amount: 30000, Reason: "overcharged"}})
+	if !resp.GetSuccess() || resp.GetData()[0].GetStatus() != paymentpb.PaymentStatus_PAYMENT_STATUS_PARTIAL_REFUND {
+		t.Fatalf("refund = %v", resp)
+	}
+	if len(fake.posted) != 2 || fake.posted[0] != paymentsPath+"/pay-1/voids" || fake.posted[1] != paymentsPath+"/pay-2/refunds" {
+		t.Fatalf("posted = %v", fake.posted)
+	}
+	total, _ := fake.bodies[1]["totalAmount"].(map[string]any)
+	if total["amount"] != 300.0 || total["currency"] != "PHP" || fake.bodies[1]["reason"] != "overcharged" {
+		t.Errorf("refund body = %v", fake.bodies[1])
+	}
+
+	// More than was captured is refused before calling Maya.
+	resp, _ = p.RefundPayment(ctx, &paymentpb.RefundPaymentRequest{Data: &paymentpb.RefundData{TransactionId: "pay-2", Amount: 90000}})
+	if resp.GetSuccess() || resp.GetError().GetCode() != "INVALID_AMOUNT" || len(fake.posted) != 2 {
+		t.Errorf("over-refund = %v", resp)
+	}
+}
+
+func TestProcessWebhook_VerifiesWithAPI(t *testing.T) {
+	p := newTestProvider(t, &fakeMaya{payments: testPayments()})
+	process := func(payload string) *paymentpb.ProcessWebhookResponse {
+		resp, err := p.ProcessWebhook(context.Background(), &paymentpb.ProcessWebhookRequest{
+			Data: &paymentpb.WebhookData{Payload: []byte(payload)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	// A forged success for a payment Maya reports failed is not believed.
+	resp := process(`{"id":"failed","isPaid":true,"paymentStatus":"PAYMENT_SUCCESS","amount":1500.5}`)
+	if !resp.GetSuccess() || resp.GetData()[0].GetStatus() != paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED {
+		t.Errorf("forged webhook = %v", resp)
+	}
+	if resp := process(`{"id":"unknown","paymentStatus":"PAYMENT_SUCCESS"}`); resp.GetSuccess() {
+		t.Errorf("unknown payment accepted: %v", resp)
+	}
+
+	p.verifyWebhooks = false
+	resp = process(`{"id":"local","paymentStatus":"PAYMENT_SUCCESS","amount":"10.00","requestReferenceNumber":"rev-9"}`)
+	if !resp.GetSuccess() || resp.GetData()[0].GetPaymentId() != "rev-9" || resp.GetData()[0].GetTransaction().GetAmount() != 1000 {
+		t.Errorf("unverified webhook = %v", resp)
+	}
+}
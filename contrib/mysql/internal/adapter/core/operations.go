@@ -478,6 +478,9 @@ func (m *MySQLOperations) List(ctx context.Context, tableName string, params *in
 	if tableName == "" {
 		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
 	}
+	if _, ok := interfaces.CursorRequest(params); ok {
+		return nil, model.NewDatabaseError("cursor pagination is not supported by MySQL", "CURSOR_PAGINATION_UNSUPPORTED", 400)
+	}
 
 	// Build WHERE clause.
 	// Default to active = true unless the caller supplies an explicit "active"
@@ -815,6 +818,39 @@ func (m *MySQLOperations) QueryOne(ctx context.Context, tableName string, queryB
 	return results[0], nil
 }
 
+// CreateMany creates each row in turn. There is no multi-row path yet; run inside a context transaction for all-or-nothing behavior.
+func (m *MySQLOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	return interfaces.CreateEach(ctx, m, tableName, data)
+}
+
+// UpdateMany updates each row in turn (see CreateMany).
+func (m *MySQLOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	return interfaces.UpdateEach(ctx, m, tableName, updates)
+}
+
+// DeleteMany soft-deletes each row in turn (see CreateMany).
+func (m *MySQLOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	return interfaces.DeleteEach(ctx, m, tableName, ids)
+}
+
+// Count counts through List, whose COUNT(*) uses the same filters (see
+// interfaces.CountFromList).
+func (m *MySQLOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (int64, error) {
+	return interfaces.CountFromList(ctx, m, tableName, params)
+}
+
+// Restore re-activates a soft-deleted row through Update (see
+// interfaces.RestoreWithUpdate).
+func (m *MySQLOperations) Restore(ctx context.Context, tableName string, id string) error {
+	return interfaces.RestoreWithUpdate(ctx, m, tableName, id)
+}
+
+// ListDeleted lists inactive rows through List, which honours an explicit
+// active filter (see interfaces.ListDeletedFromList).
+func (m *MySQLOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return interfaces.ListDeletedFromList(ctx, m, tableName, params)
+}
+
 // Helper methods
 
 // readByID fetches a single row by id and scans it into a snake_case map.
@@ -10,6 +10,8 @@ import (
 	"github.com/erniealice/espyna-golang/shared/identity"
 	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
 	"github.com/erniealice/espyna-golang/database/model"
+	dbtenancy "github.com/erniealice/espyna-golang/database/tenancy"
+	"github.com/erniealice/espyna-golang/registry"
 	sqlexec "github.com/erniealice/espyna-golang/database/sqlexec"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
 )
@@ -60,6 +62,14 @@ func (w *WorkspaceAwareOperations) List(ctx context.Context, tableName string, p
 	return w.inner.List(ctx, tableName, params)
 }
 
+func (w *WorkspaceAwareOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (int64, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		params = w.injectWorkspaceFilter(params, wsID)
+	}
+	return w.inner.Count(ctx, tableName, params)
+}
+
 func (w *WorkspaceAwareOperations) Create(ctx context.Context, tableName string, data map[string]any) (map[string]any, error) {
 	wsID := w.getWorkspaceID(ctx)
 	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
@@ -135,14 +145,58 @@ func (w *WorkspaceAwareOperations) HardDelete(ctx context.Context, tableName str
 	return w.inner.HardDelete(ctx, tableName, id)
 }
 
+func (w *WorkspaceAwareOperations) Restore(ctx context.Context, tableName string, id string) error {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		if _, err := w.Read(ctx, tableName, id); err != nil {
+			return err
+		}
+	}
+	return w.inner.Restore(ctx, tableName, id)
+}
+
+func (w *WorkspaceAwareOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		params = w.injectWorkspaceFilter(params, wsID)
+	}
+	return w.inner.ListDeleted(ctx, tableName, params)
+}
+
+// Query adds a workspace_id condition when the context carries a workspace
+// and the table has the column, like List.
 func (w *WorkspaceAwareOperations) Query(ctx context.Context, tableName string, query interfaces.QueryBuilder) ([]map[string]any, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		query = dbtenancy.ScopeQuery(query, wsID)
+	}
 	return w.inner.Query(ctx, tableName, query)
 }
 
+// QueryOne adds a workspace_id condition (see Query).
 func (w *WorkspaceAwareOperations) QueryOne(ctx context.Context, tableName string, query interfaces.QueryBuilder) (map[string]any, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		query = dbtenancy.ScopeQuery(query, wsID)
+	}
 	return w.inner.QueryOne(ctx, tableName, query)
 }
 
+// CreateMany creates each row in turn. Going through the decorator's own Create keeps the per-row workspace injection and ownership checks.
+func (w *WorkspaceAwareOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	return interfaces.CreateEach(ctx, w, tableName, data)
+}
+
+// UpdateMany updates each row in turn (see CreateMany).
+func (w *WorkspaceAwareOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	return interfaces.UpdateEach(ctx, w, tableName, updates)
+}
+
+// DeleteMany soft-deletes each row in turn (see CreateMany).
+func (w *WorkspaceAwareOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	return interfaces.DeleteEach(ctx, w, tableName, ids)
+}
+
 // ── Optional interface methods ───────────────────────────────────────────────
 
 // GetDB returns the underlying *sql.DB.
@@ -171,6 +225,12 @@ func (w *WorkspaceAwareOperations) getWorkspaceID(ctx context.Context) string {
 // Results are cached; the first miss queries information_schema scoped to
 // DATABASE() (MySQL's current schema).
 func (w *WorkspaceAwareOperations) tableHasWorkspaceColumn(ctx context.Context, tableName string) bool {
+	// Tables configured as global (CONFIG_TENANCY_GLOBAL_TABLES) are shared
+	// by every workspace even when they carry the column.
+	if registry.GetDefaultTenancy().GlobalTables[tableName] {
+		return false
+	}
+
 	w.columnCacheMu.RLock()
 	cols, cached := w.columnCache[tableName]
 	w.columnCacheMu.RUnlock()
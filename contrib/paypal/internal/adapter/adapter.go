@@ -8,15 +8,19 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/erniealice/espyna-golang/ports"
 	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/tracing"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
 	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -114,6 +118,7 @@ const (
 	paypalSandboxURL    = "https://api-m.sandbox.paypal.com"
 	ordersPath          = "/v2/checkout/orders"
 	tokenPath           = "/v1/oauth2/token"
+	disputesPath        = "/v1/customer/disputes"
 )
 
 // PayPalProvider implements the PaymentProvider interface for PayPal payment gateway
@@ -241,9 +246,18 @@ type PayPalPayments struct {
 
 // PayPalCapture represents a capture
 type PayPalCapture struct {
-	ID     string      `json:"id"`
-	Status string      `json:"status"`
-	Amount PayPalMoney `json:"amount"`
+	ID                        string                     `json:"id"`
+	Status                    string                     `json:"status"`
+	Amount                    PayPalMoney                `json:"amount"`
+	SellerReceivableBreakdown *PayPalReceivableBreakdown `json:"seller_receivable_breakdown,omitempty"`
+}
+
+// PayPalReceivableBreakdown is the gross/fee/net split PayPal reports on a
+// capture (seller_receivable_breakdown) or refund (seller_payable_breakdown).
+type PayPalReceivableBreakdown struct {
+	GrossAmount *PayPalMoney `json:"gross_amount,omitempty"`
+	PayPalFee   *PayPalMoney `json:"paypal_fee,omitempty"`
+	NetAmount   *PayPalMoney `json:"net_amount,omitempty"`
 }
 
 // PayPalAuthorization represents an authorization
@@ -280,6 +294,11 @@ type PayPalWebhookResource struct {
 	CustomID      string                       `json:"custom_id,omitempty"`
 	InvoiceID     string                       `json:"invoice_id,omitempty"`
 	PurchaseUnits []PayPalPurchaseUnitResponse `json:"purchase_units,omitempty"`
+
+	// Fee breakdowns: captures carry the receivable side, refunds the
+	// payable side. At most one is present per event.
+	SellerReceivableBreakdown *PayPalReceivableBreakdown `json:"seller_receivable_breakdown,omitempty"`
+	SellerPayableBreakdown    *PayPalReceivableBreakdown `json:"seller_payable_breakdown,omitempty"`
 }
 
 // PayPalTokenResponse represents the OAuth token response
@@ -374,8 +393,10 @@ func (p *PayPalProvider) Initialize(config *paymentpb.PaymentProviderConfig) err
 		p.timeout = time.Duration(config.TimeoutSeconds) * time.Second
 	}
 
+	// Outbound calls carry the request's correlation ID and trace context
 	p.httpClient = &http.Client{
-		Timeout: p.timeout,
+		Timeout:   p.timeout,
+		Transport: tracing.NewTransport(correlation.NewTransport(nil)),
 	}
 
 	p.enabled = config.Enabled
@@ -461,8 +482,8 @@ func (p *PayPalProvider) CreateCheckoutSession(ctx context.Context, req *payment
 	log.Printf("[PayPal] 🔗 Return URL Config: baseURL=%q, successPath=%q, cancelPath=%q", p.baseURL, p.successPath, p.cancelPath)
 	log.Printf("[PayPal] 🔗 Built URLs: successURL=%q, cancelURL=%q", successURL, cancelURL)
 
-	// Format amount as string with 2 decimal places
-	amountStr := fmt.Sprintf("%.2f", data.Amount)
+	// Format amount (cents) as major units with 2 decimal places
+	amountStr := fmt.Sprintf("%.2f", float64(data.Amount)/100.0)
 
 	// Create order request
 	orderReq := PayPalOrderRequest{
@@ -757,6 +778,11 @@ func (p *PayPalProvider) ProcessWebhook(ctx context.Context, req *paymentpb.Proc
 	case "PAYMENT.CAPTURE.PENDING":
 		status = paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING
 		action = "processing"
+	case disputeCreatedEvent, disputeUpdatedEvent, disputeResolvedEvent:
+		// Dispute resources have no amount/custom_id; ParseDisputeWebhook
+		// reads the full dispute for the dispute workflow.
+		status = paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING
+		action = "dispute"
 	default:
 		status = paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING
 		action = "processing"
@@ -779,10 +805,7 @@ func (p *PayPalProvider) ProcessWebhook(ctx context.Context, req *paymentpb.Proc
 
 	var amount int64
 	if resource.Amount != nil {
-		// Parse amount string to float then convert to cents
-		var amountFloat float64
-		fmt.Sscanf(resource.Amount.Value, "%f", &amountFloat)
-		amount = int64(amountFloat * 100)
+		amount = moneyToMinorUnits(resource.Amount)
 	}
 
 	currency := "USD"
@@ -790,6 +813,20 @@ func (p *PayPalProvider) ProcessWebhook(ctx context.Context, req *paymentpb.Proc
 		currency = resource.Amount.CurrencyCode
 	}
 
+	rawData := map[string]string{
+		"event_id":     webhookEvent.ID,
+		"event_type":   webhookEvent.EventType,
+		"resource_id":  resource.ID,
+		"order_status": resource.Status,
+	}
+	breakdown := resource.SellerReceivableBreakdown
+	if breakdown == nil {
+		breakdown = resource.SellerPayableBreakdown
+	}
+	if fees, ok := breakdownFees(breakdown, amount, currency); ok {
+		fees.SetRawData(rawData)
+	}
+
 	transaction := &paymentpb.PaymentTransaction{
 		Id:                 webhookEvent.ID,
 		ProviderRef:        resource.ID,
@@ -802,12 +839,7 @@ func (p *PayPalProvider) ProcessWebhook(ctx context.Context, req *paymentpb.Proc
 		PaymentId:          paymentID,
 		OrderRef:           orderRef,
 		ProcessedAt:        timestamppb.Now(),
-		RawData: map[string]string{
-			"event_id":     webhookEvent.ID,
-			"event_type":   webhookEvent.EventType,
-			"resource_id":  resource.ID,
-			"order_status": resource.Status,
-		},
+		RawData:            rawData,
 	}
 
 	log.Printf("📨 PayPal webhook processed: %s -> %s (event: %s)", webhookEvent.ID, action, webhookEvent.EventType)
@@ -822,6 +854,45 @@ func (p *PayPalProvider) ProcessWebhook(ctx context.Context, req *paymentpb.Proc
 	}, nil
 }
 
+// moneyToMinorUnits converts a PayPal decimal amount string to minor units
+// (cents). Rounds rather than truncates so "19.99" does not become 1998.
+func moneyToMinorUnits(m *PayPalMoney) int64 {
+	if m == nil {
+		return 0
+	}
+	v, err := strconv.ParseFloat(m.Value, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(math.Round(v * 100))
+}
+
+// breakdownFees maps a PayPal seller breakdown to the shared PaymentFees
+// shape. Returns false when PayPal reported no fee.
+func breakdownFees(b *PayPalReceivableBreakdown, amount int64, currency string) (ports.PaymentFees, bool) {
+	if b == nil || b.PayPalFee == nil {
+		return ports.PaymentFees{}, false
+	}
+	fees := ports.PaymentFees{
+		Gross:    amount,
+		Fee:      moneyToMinorUnits(b.PayPalFee),
+		Currency: currency,
+		Reported: true,
+	}
+	if b.GrossAmount != nil {
+		fees.Gross = moneyToMinorUnits(b.GrossAmount)
+	}
+	if b.NetAmount != nil {
+		fees.Net = moneyToMinorUnits(b.NetAmount)
+	} else {
+		fees.Net = fees.Gross - fees.Fee
+	}
+	if b.PayPalFee.CurrencyCode != "" {
+		fees.Currency = b.PayPalFee.CurrencyCode
+	}
+	return fees, true
+}
+
 func (p *PayPalProvider) GetPaymentStatus(ctx context.Context, req *paymentpb.GetPaymentStatusRequest) (*paymentpb.GetPaymentStatusResponse, error) {
 	if !p.enabled {
 		return nil, fmt.Errorf("PayPal provider is not initialized")
@@ -909,16 +980,33 @@ func (p *PayPalProvider) GetPaymentStatus(ctx context.Context, req *paymentpb.Ge
 		status = paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING
 	}
 
+	transaction := &paymentpb.PaymentTransaction{
+		ProviderRef: order.ID,
+		ProviderId:  "paypal",
+		Status:      status,
+	}
+	// Completed orders carry the capture, including PayPal's fee breakdown.
+	for _, unit := range order.PurchaseUnits {
+		if unit.Payments == nil || len(unit.Payments.Captures) == 0 {
+			continue
+		}
+		capture := unit.Payments.Captures[0]
+		transaction.ProviderPaymentRef = capture.ID
+		transaction.Amount = moneyToMinorUnits(&capture.Amount)
+		transaction.Currency = capture.Amount.CurrencyCode
+		if fees, ok := breakdownFees(capture.SellerReceivableBreakdown, transaction.Amount, transaction.Currency); ok {
+			transaction.RawData = map[string]string{}
+			fees.SetRawData(transaction.RawData)
+		}
+		break
+	}
+
 	return &paymentpb.GetPaymentStatusResponse{
 		Success: true,
 		Data: []*paymentpb.PaymentStatusData{
 			{
-				Status: status,
-				Transaction: &paymentpb.PaymentTransaction{
-					ProviderRef: order.ID,
-					ProviderId:  "paypal",
-					Status:      status,
-				},
+				Status:      status,
+				Transaction: transaction,
 			},
 		},
 	}, nil
@@ -0,0 +1,255 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"time"
+
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+// PayPal dispute webhook event types
+const (
+	disputeCreatedEvent  = "CUSTOMER.DISPUTE.CREATED"
+	disputeUpdatedEvent  = "CUSTOMER.DISPUTE.UPDATED"
+	disputeResolvedEvent = "CUSTOMER.DISPUTE.RESOLVED"
+)
+
+// PayPalDispute is the resource of a CUSTOMER.DISPUTE.* webhook event.
+type PayPalDispute struct {
+	DisputeID             string                      `json:"dispute_id"`
+	CreateTime            string                      `json:"create_time"`
+	UpdateTime            string                      `json:"update_time"`
+	DisputedTransactions  []PayPalDisputedTransaction `json:"disputed_transactions,omitempty"`
+	Reason                string                      `json:"reason"`
+	Status                string                      `json:"status"`
+	DisputeAmount         *PayPalMoney                `json:"dispute_amount,omitempty"`
+	DisputeOutcome        *PayPalDisputeOutcome       `json:"dispute_outcome,omitempty"`
+	DisputeLifeCycleStage string                      `json:"dispute_life_cycle_stage,omitempty"`
+	SellerResponseDueDate string                      `json:"seller_response_due_date,omitempty"`
+}
+
+// PayPalDisputedTransaction links a dispute to the seller's capture. Custom
+// and InvoiceNumber echo the custom_id / invoice_id set at checkout.
+type PayPalDisputedTransaction struct {
+	SellerTransactionID string `json:"seller_transaction_id"`
+	InvoiceNumber       string `json:"invoice_number,omitempty"`
+	Custom              string `json:"custom,omitempty"`
+}
+
+// PayPalDisputeOutcome is reported once a dispute is resolved.
+type PayPalDisputeOutcome struct {
+	OutcomeCode string `json:"outcome_code"`
+}
+
+// ParseDisputeWebhook maps a CUSTOMER.DISPUTE.* event to a PaymentDispute.
+// Other event types return ok == false.
+func (p *PayPalProvider) ParseDisputeWebhook(ctx context.Context, payload []byte) (*ports.PaymentDispute, bool, error) {
+	var event PayPalWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, false, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+	switch event.EventType {
+	case disputeCreatedEvent, disputeUpdatedEvent, disputeResolvedEvent:
+	default:
+		return nil, false, nil
+	}
+
+	var resource PayPalDispute
+	if err := json.Unmarshal(event.Resource, &resource); err != nil {
+		return nil, true, fmt.Errorf("failed to parse dispute resource: %w", err)
+	}
+	if resource.DisputeID == "" {
+		return nil, true, fmt.Errorf("dispute resource has no dispute_id")
+	}
+
+	outcome := ""
+	if resource.DisputeOutcome != nil {
+		outcome = resource.DisputeOutcome.OutcomeCode
+	}
+	dispute := &ports.PaymentDispute{
+		ProviderID:         "paypal",
+		ProviderDisputeRef: resource.DisputeID,
+		Status:             disputeStatus(resource.Status, outcome),
+		ProviderStatus:     resource.Status,
+		Reason:             resource.Reason,
+		Stage:              resource.DisputeLifeCycleStage,
+		Amount:             moneyToMinorUnits(resource.DisputeAmount),
+		EvidenceDueAt:      parsePayPalTime(resource.SellerResponseDueDate),
+		OpenedAt:           parsePayPalTime(resource.CreateTime),
+		UpdatedAt:          parsePayPalTime(resource.UpdateTime),
+		RawData: map[string]string{
+			"event_id":       event.ID,
+			"event_type":     event.EventType,
+			"dispute_status": resource.Status,
+			"outcome_code":   outcome,
+		},
+	}
+	if resource.DisputeAmount != nil {
+		dispute.Currency = resource.DisputeAmount.CurrencyCode
+	}
+	if len(resource.DisputedTransactions) > 0 {
+		tx := resource.DisputedTransactions[0]
+		dispute.ProviderPaymentRef = tx.SellerTransactionID
+		dispute.PaymentID = tx.Custom
+		dispute.SubscriptionID = tx.InvoiceNumber
+	}
+	return dispute, true, nil
+}
+
+// disputeStatus maps PayPal's dispute status and outcome code to the
+// provider-neutral status.
+func disputeStatus(status, outcome string) ports.PaymentDisputeStatus {
+	switch status {
+	case "WAITING_FOR_SELLER_RESPONSE":
+		return ports.PaymentDisputeEvidenceRequired
+	case "UNDER_REVIEW":
+		return ports.PaymentDisputeUnderReview
+	case "RESOLVED":
+		switch outcome {
+		case "RESOLVED_SELLER_FAVOUR", "CANCELED_BY_BUYER", "DENIED":
+			return ports.PaymentDisputeWon
+		case "RESOLVED_BUYER_FAVOUR", "RESOLVED_WITH_PAYOUT", "ACCEPTED":
+			return ports.PaymentDisputeLost
+		default:
+			return ports.PaymentDisputeClosed
+		}
+	default:
+		// OPEN, WAITING_FOR_BUYER_RESPONSE, OTHER
+		return ports.PaymentDisputeOpen
+	}
+}
+
+func parsePayPalTime(v string) time.Time {
+	if v == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// payPalEvidenceInput is the JSON "input" part of a provide-evidence call.
+type payPalEvidenceInput struct {
+	Evidences []payPalEvidence `json:"evidences"`
+}
+
+type payPalEvidence struct {
+	EvidenceType string              `json:"evidence_type"`
+	EvidenceInfo *payPalEvidenceInfo `json:"evidence_info,omitempty"`
+	Notes        string              `json:"notes,omitempty"`
+}
+
+type payPalEvidenceInfo struct {
+	TrackingInfo []payPalTrackingInfo `json:"tracking_info,omitempty"`
+}
+
+type payPalTrackingInfo struct {
+	CarrierName    string `json:"carrier_name"`
+	TrackingNumber string `json:"tracking_number"`
+}
+
+// SubmitDisputeEvidence posts evidence to
+// POST /v1/customer/disputes/{id}/provide-evidence as multipart form data:
+// a JSON "input" part plus one part per document.
+func (p *PayPalProvider) SubmitDisputeEvidence(ctx context.Context, providerDisputeRef string, evidence *ports.PaymentDisputeEvidence) error {
+	if !p.enabled {
+		return fmt.Errorf("PayPal provider is not initialized")
+	}
+	if providerDisputeRef == "" || evidence == nil {
+		return fmt.Errorf("dispute reference and evidence are required")
+	}
+
+	item := payPalEvidence{EvidenceType: evidence.Type, Notes: evidence.Notes}
+	if item.EvidenceType == "" {
+		item.EvidenceType = "OTHER"
+	}
+	if evidence.TrackingNumber != "" {
+		item.EvidenceInfo = &payPalEvidenceInfo{TrackingInfo: []payPalTrackingInfo{{
+			CarrierName:    evidence.Carrier,
+			TrackingNumber: evidence.TrackingNumber,
+		}}}
+	}
+	input, err := json.Marshal(payPalEvidenceInput{Evidences: []payPalEvidence{item}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal evidence: %w", err)
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="input"; filename="input.json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to build evidence request: %w", err)
+	}
+	if _, err := part.Write(input); err != nil {
+		return fmt.Errorf("failed to build evidence request: %w", err)
+	}
+	for _, doc := range evidence.Documents {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="evidence_file"; filename=%q`, doc.Name))
+		contentType := doc.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		h.Set("Content-Type", contentType)
+		fp, err := mw.CreatePart(h)
+		if err != nil {
+			return fmt.Errorf("failed to attach %s: %w", doc.Name, err)
+		}
+		if _, err := fp.Write(doc.Content); err != nil {
+			return fmt.Errorf("failed to attach %s: %w", doc.Name, err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("failed to build evidence request: %w", err)
+	}
+
+	token, err := p.getAccessToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+	endpoint := p.apiEndpoint + disputesPath + "/" + url.PathEscape(providerDisputeRef) + "/provide-evidence"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpReq.Header.Set("Content-Type", mw.FormDataContentType())
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		var errResp PayPalErrorResponse
+		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Name != "" {
+			return fmt.Errorf("PayPal API error [%s]: %s", errResp.Name, errResp.Message)
+		}
+		return fmt.Errorf("PayPal API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	log.Printf("📎 PayPal dispute evidence submitted: %s (%d document(s))", providerDisputeRef, len(evidence.Documents))
+	return nil
+}
+
+var (
+	_ ports.DisputeProvider          = (*PayPalProvider)(nil)
+	_ ports.DisputeEvidenceSubmitter = (*PayPalProvider)(nil)
+)
@@ -0,0 +1,181 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+const disputeEvent = `{
+  "id": "WH-1",
+  "event_type": "CUSTOMER.DISPUTE.CREATED",
+  "resource": {
+    "dispute_id": "PP-D-1",
+    "create_time": "2026-03-01T10:00:00Z",
+    "update_time": "2026-03-02T10:00:00Z",
+    "disputed_transactions": [{
+      "seller_transaction_id": "CAP-1",
+      "invoice_number": "sub-1",
+      "custom": "pay-1"
+    }],
+    "reason": "MERCHANDISE_OR_SERVICE_NOT_RECEIVED",
+    "status": "WAITING_FOR_SELLER_RESPONSE",
+    "dispute_amount": {"currency_code": "USD", "value": "25.50"},
+    "dispute_life_cycle_stage": "CHARGEBACK",
+    "seller_response_due_date": "2026-03-11T10:00:00Z"
+  }
+}`
+
+func TestParseDisputeWebhook(t *testing.T) {
+	p := &PayPalProvider{}
+	d, ok, err := p.ParseDisputeWebhook(context.Background(), []byte(disputeEvent))
+	if err != nil || !ok {
+		t.Fatalf("ok = %v, err = %v", ok, err)
+	}
+	if d.ProviderID != "paypal" || d.ProviderDisputeRef != "PP-D-1" || d.ProviderPaymentRef != "CAP-1" {
+		t.Errorf("refs = %+v", d)
+	}
+	if d.PaymentID != "pay-1" || d.SubscriptionID != "sub-1" {
+		t.Errorf("links = %q, %q", d.PaymentID, d.SubscriptionID)
+	}
+	if d.Status != ports.PaymentDisputeEvidenceRequired || d.Stage != "CHARGEBACK" {
+		t.Errorf("status = %s, stage = %s", d.Status, d.Stage)
+	}
+	if d.Amount != 2550 || d.Currency != "USD" {
+		t.Errorf("amount = %d %s", d.Amount, d.Currency)
+	}
+	if want := time.Date(2026, 3, 11, 10, 0, 0, 0, time.UTC); !d.EvidenceDueAt.Equal(want) {
+		t.Errorf("evidence due = %v", d.EvidenceDueAt)
+	}
+	if d.RawData["event_id"] != "WH-1" {
+		t.Errorf("raw data = %v", d.RawData)
+	}
+}
+
+func TestParseDisputeWebhook_NotADispute(t *testing.T) {
+	p := &PayPalProvider{}
+	_, ok, err := p.ParseDisputeWebhook(context.Background(), []byte(`{"event_type":"PAYMENT.CAPTURE.COMPLETED","resource":{}}`))
+	if ok || err != nil {
+		t.Errorf("ok = %v, err = %v", ok, err)
+	}
+
+	_, ok, err = p.ParseDisputeWebhook(context.Background(), []byte(`{"event_type":"CUSTOMER.DISPUTE.UPDATED","resource":{}}`))
+	if !ok || err == nil {
+		t.Errorf("dispute without ID: ok = %v, err = %v", ok, err)
+	}
+}
+
+func TestDisputeStatus(t *testing.T) {
+	cases := []struct {
+		status, outcome string
+		want            ports.PaymentDisputeStatus
+	}{
+		{"OPEN", "", ports.PaymentDisputeOpen},
+		{"WAITING_FOR_BUYER_RESPONSE", "", ports.PaymentDisputeOpen},
+		{"WAITING_FOR_SELLER_RESPONSE", "", ports.PaymentDisputeEvidenceRequired},
+		{"UNDER_REVIEW", "", ports.PaymentDisputeUnderReview},
+		{"RESOLVED", "RESOLVED_SELLER_FAVOUR", ports.PaymentDisputeWon},
+		{"RESOLVED", "CANCELED_BY_BUYER", ports.PaymentDisputeWon},
+		{"RESOLVED", "RESOLVED_BUYER_FAVOUR", ports.PaymentDisputeLost},
+		{"RESOLVED", "ACCEPTED", ports.PaymentDisputeLost},
+		{"RESOLVED", "", ports.PaymentDisputeClosed},
+	}
+	for _, c := range cases {
+		if got := disputeStatus(c.status, c.outcome); got != c.want {
+			t.Errorf("disputeStatus(%q, %q) = %s, want %s", c.status, c.outcome, got, c.want)
+		}
+	}
+}
+
+func TestSubmitDisputeEvidence(t *testing.T) {
+	var input payPalEvidenceInput
+	var files []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != disputesPath+"/PP-D-1/provide-evidence" || r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		reader, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if part.FormName() == "input" {
+				if err := json.NewDecoder(part).Decode(&input); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				continue
+			}
+			files = append(files, part.FileName())
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	p := &PayPalProvider{
+		enabled:        true,
+		apiEndpoint:    server.URL,
+		httpClient:     server.Client(),
+		accessToken:    "test-token",
+		tokenExpiresAt: time.Now().Add(time.Hour),
+	}
+	err := p.SubmitDisputeEvidence(context.Background(), "PP-D-1", &ports.PaymentDisputeEvidence{
+		Notes:          "Delivered",
+		Carrier:        "UPS",
+		TrackingNumber: "1Z999",
+		Documents:      []ports.PaymentDisputeDocument{{Name: "receipt.pdf", Content: []byte("%PDF")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(input.Evidences) != 1 {
+		t.Fatalf("evidences = %+v", input.Evidences)
+	}
+	e := input.Evidences[0]
+	if e.EvidenceType != "OTHER" || e.Notes != "Delivered" {
+		t.Errorf("evidence = %+v", e)
+	}
+	if e.EvidenceInfo == nil || e.EvidenceInfo.TrackingInfo[0].TrackingNumber != "1Z999" {
+		t.Errorf("tracking = %+v", e.EvidenceInfo)
+	}
+	if strings.Join(files, ",") != "receipt.pdf" {
+		t.Errorf("files = %v", files)
+	}
+}
+
+func TestSubmitDisputeEvidence_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"name":"UNPROCESSABLE_ENTITY","message":"The dispute is not in a state to accept evidence."}`))
+	}))
+	defer server.Close()
+
+	p := &PayPalProvider{
+		enabled:        true,
+		apiEndpoint:    server.URL,
+		httpClient:     server.Client(),
+		accessToken:    "test-token",
+		tokenExpiresAt: time.Now().Add(time.Hour),
+	}
+	err := p.SubmitDisputeEvidence(context.Background(), "PP-D-1", &ports.PaymentDisputeEvidence{Notes: "Delivered"})
+	if err == nil || !strings.Contains(err.Error(), "UNPROCESSABLE_ENTITY") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/erniealice/espyna-golang/ports"
+	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	transactionsPath = "/v1/reporting/transactions"
+	// PayPal searches at most 31 days per request.
+	transactionSearchWindow   = 31 * 24 * time.Hour
+	transactionSearchPageSize = 500
+	payPalSearchTimeLayout    = "2006-01-02T15:04:05-0700"
+)
+
+// payPalTransactionSearch is a page of GET /v1/reporting/transactions.
+type payPalTransactionSearch struct {
+	TransactionDetails []struct {
+		TransactionInfo payPalTransactionInfo `json:"transaction_info"`
+	} `json:"transaction_details"`
+	Page       int `json:"page"`
+	TotalPages int `json:"total_pages"`
+}
+
+// payPalTransactionInfo is one transaction of a search. CustomField and
+// InvoiceID echo the custom_id / invoice_id set at checkout.
+type payPalTransactionInfo struct {
+	TransactionID     string       `json:"transaction_id"`
+	PayPalReferenceID string       `json:"paypal_reference_id,omitempty"`
+	EventCode         string       `json:"transaction_event_code"`
+	UpdatedDate       string       `json:"transaction_updated_date"`
+	Amount            *PayPalMoney `json:"transaction_amount,omitempty"`
+	FeeAmount         *PayPalMoney `json:"fee_amount,omitempty"`
+	Status            string       `json:"transaction_status"`
+	InvoiceID         string       `json:"invoice_id,omitempty"`
+	CustomField       string       `json:"custom_field,omitempty"`
+}
+
+// SearchTransactions lists the account's transactions updated in
+// [from, to) with PayPal's transaction search, splitting the range into the
+// 31-day windows PayPal accepts. Transactions appear there a few hours after
+// they happen.
+func (p *PayPalProvider) SearchTransactions(ctx context.Context, from, to time.Time) ([]*paymentpb.PaymentTransaction, error) {
+	if !p.enabled {
+		return nil, fmt.Errorf("PayPal provider is not initialized")
+	}
+	var out []*paymentpb.PaymentTransaction
+	for start := from; start.Before(to); start = start.Add(transactionSearchWindow) {
+		end := start.Add(transactionSearchWindow)
+		if end.After(to) {
+			end = to
+		}
+		for page := 1; ; page++ {
+			result, err := p.searchTransactionsPage(ctx, start, end, page)
+			if err != nil {
+				return nil, err
+			}
+			for _, detail := range result.TransactionDetails {
+				out = append(out, payPalTransaction(detail.TransactionInfo))
+			}
+			if page >= result.TotalPages {
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func (p *PayPalProvider) searchTransactionsPage(ctx context.Context, start, end time.Time, page int) (*payPalTransactionSearch, error) {
+	token, err := p.getAccessToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+	query := url.Values{}
+	query.Set("start_date", start.UTC().Format(payPalSearchTimeLayout))
+	query.Set("end_date", end.UTC().Format(payPalSearchTimeLayout))
+	query.Set("fields", "transaction_info")
+	query.Set("page_size", strconv.Itoa(transactionSearchPageSize))
+	query.Set("page", strconv.Itoa(page))
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiEndpoint+transactionsPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction search: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp PayPalErrorResponse
+		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Name != "" {
+			return nil, fmt.Errorf("PayPal API error [%s]: %s", errResp.Name, errResp.Message)
+		}
+		return nil, fmt.Errorf("PayPal API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result payPalTransactionSearch
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction search: %w", err)
+	}
+	return &result, nil
+}
+
+// payPalTransaction maps a searched transaction. PayPal reports refunds and
+// reversals as separate transactions with a negative amount; they map to
+// PAYMENT_STATUS_REFUNDED with the amount made positive.
+func payPalTransaction(info payPalTransactionInfo) *paymentpb.PaymentTransaction {
+	var status paymentpb.PaymentStatus
+	switch info.Status {
+	case "S":
+		status = paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS
+	case "P":
+		status = paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING
+	case "D":
+		status = paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED
+	case "V":
+		status = paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED
+	default:
+		status = paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING
+	}
+
+	tx := &paymentpb.PaymentTransaction{
+		ProviderRef:        info.PayPalReferenceID,
+		ProviderPaymentRef: info.TransactionID,
+		ProviderId:         "paypal",
+		Status:             status,
+		PaymentId:          info.CustomField,
+		OrderRef:           info.InvoiceID,
+		RawData:            map[string]string{"transaction_event_code": info.EventCode},
+	}
+	if info.Amount != nil {
+		tx.Amount = moneyToMinorUnits(info.Amount)
+		tx.Currency = info.Amount.CurrencyCode
+	}
+	if tx.Amount < 0 {
+		tx.Amount = -tx.Amount
+		tx.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED
+	}
+	if info.FeeAmount != nil && tx.Status == paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS {
+		// PayPal reports the fee as a negative amount.
+		fee := -moneyToMinorUnits(info.FeeAmount)
+		ports.PaymentFees{Gross: tx.Amount, Fee: fee, Net: tx.Amount - fee, Currency: tx.Currency, Reported: true}.SetRawData(tx.RawData)
+	}
+	if t := parsePayPalTime(info.UpdatedDate); !t.IsZero() {
+		tx.ProcessedAt = timestamppb.New(t)
+	}
+	return tx
+}
+
+var _ ports.PaymentTransactionSearcher = (*PayPalProvider)(nil)
@@ -0,0 +1,225 @@
+// Package main is the schema migration CLI for the PostgreSQL adapter. It
+// applies the SQL embedded in contrib/postgres/migrations, so the binary
+// carries the schema it expects and every environment evolves the same way.
+//
+// Usage:
+//
+//	migrate [flags] up              apply all pending migrations
+//	migrate [flags] up -steps 1     apply the next migration only
+//	migrate [flags] down            roll back the latest migration
+//	migrate [flags] down -steps 3   roll back three migrations
+//	migrate [flags] status          print current version and pending files
+//	migrate [flags] goto <version>  migrate up or down to a version
+//	migrate [flags] force <version> mark a version applied (clears dirty state)
+//
+// -dry-run prints the SQL that up/down would execute without touching the
+// database (the current version is still read to compute the plan).
+//
+// Connection settings come from the same POSTGRES_* env vars the adapter uses.
+
+//go:build postgresql
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	_ "github.com/lib/pq"
+
+	"github.com/erniealice/espyna-golang/contrib/postgres/internal/migration"
+	"github.com/erniealice/espyna-golang/contrib/postgres/migrations"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "print the SQL that would run without applying it")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: migrate [-dry-run] up|down|status|goto <version>|force <version> [-steps N]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command := flag.Arg(0)
+
+	sub := flag.NewFlagSet(command, flag.ExitOnError)
+	steps := sub.Int("steps", 0, "number of migrations (up: 0 = all pending; down: 0 = 1)")
+	subDryRun := sub.Bool("dry-run", false, "print the SQL that would run without applying it")
+	_ = sub.Parse(flag.Args()[1:])
+	*dryRun = *dryRun || *subDryRun
+
+	files, err := migrations.List()
+	if err != nil {
+		log.Fatalf("load migrations: %v", err)
+	}
+
+	db, err := sql.Open("postgres", buildDSN())
+	if err != nil {
+		log.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		log.Fatalf("ping db: %v", err)
+	}
+
+	fsys, err := migrations.FS()
+	if err != nil {
+		log.Fatalf("load migrations: %v", err)
+	}
+	svc, err := migration.NewEmbeddedPostgresMigrationService(db, fsys)
+	if err != nil {
+		log.Fatalf("init migrations: %v", err)
+	}
+	defer svc.Close()
+
+	ctx := context.Background()
+	current, dirty, err := svc.Version(ctx)
+	if err != nil {
+		log.Fatalf("read version: %v", err)
+	}
+	if dirty && command != "force" && command != "status" {
+		log.Fatalf("database is dirty at version %d; fix it by hand, then run `migrate force %d`", current, current)
+	}
+
+	switch command {
+	case "up", "down":
+		n := *steps
+		if command == "down" {
+			if n <= 0 {
+				n = 1
+			}
+			n = -n
+		}
+		plan := migrations.Plan(files, current, n)
+		if len(plan) == 0 {
+			fmt.Printf("no change (version %d)\n", current)
+			return
+		}
+		if *dryRun {
+			printPlan(plan, command == "up")
+			return
+		}
+		if command == "up" && n == 0 {
+			err = svc.Up(ctx)
+		} else {
+			err = svc.Steps(ctx, n)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, f := range plan {
+			fmt.Printf("%s %06d %s/%s\n", command, f.Version, f.Domain, f.Title)
+		}
+
+	case "goto":
+		target := versionArg(sub)
+		if *dryRun {
+			if target >= current {
+				printPlan(migrations.Plan(upTo(files, target), current, 0), true)
+			} else {
+				printPlan(migrations.Plan(files, current, -countBetween(files, target, current)), false)
+			}
+			return
+		}
+		if err := svc.Migrate(ctx, target); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("migrated to version %d\n", target)
+
+	case "force":
+		target := versionArg(sub)
+		if *dryRun {
+			fmt.Printf("would force version %d (currently %d, dirty=%v)\n", target, current, dirty)
+			return
+		}
+		if err := svc.Force(ctx, target); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("forced version %d\n", target)
+
+	case "status":
+		fmt.Printf("version: %d\ndirty:   %v\n", current, dirty)
+		pending := migrations.Plan(files, current, 0)
+		fmt.Printf("pending: %d\n", len(pending))
+		for _, f := range pending {
+			fmt.Printf("  %06d %s/%s\n", f.Version, f.Domain, f.Title)
+		}
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// printPlan writes each migration's SQL to stdout in execution order.
+func printPlan(plan []migrations.File, up bool) {
+	for _, f := range plan {
+		p := f.DownPath
+		if up {
+			p = f.UpPath
+		}
+		body, err := migrations.ReadSQL(p)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("-- %s\n%s\n", p, body)
+	}
+}
+
+func versionArg(fs *flag.FlagSet) uint {
+	if fs.NArg() < 1 {
+		log.Fatal("a version argument is required")
+	}
+	v, err := strconv.ParseUint(fs.Arg(0), 10, 64)
+	if err != nil {
+		log.Fatalf("invalid version %q", fs.Arg(0))
+	}
+	return uint(v)
+}
+
+// upTo returns the files at or below target.
+func upTo(files []migrations.File, target uint) []migrations.File {
+	var out []migrations.File
+	for _, f := range files {
+		if f.Version <= target {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// countBetween counts files with target < version <= current.
+func countBetween(files []migrations.File, target, current uint) int {
+	n := 0
+	for _, f := range files {
+		if f.Version > target && f.Version <= current {
+			n++
+		}
+	}
+	return n
+}
+
+func buildDSN() string {
+	host := getenv("POSTGRES_HOST", "localhost")
+	port := getenv("POSTGRES_PORT", "5432")
+	user := getenv("POSTGRES_USER", "postgres")
+	pass := getenv("POSTGRES_PASSWORD", "")
+	dbname := getenv("POSTGRES_NAME", "espyna")
+	sslmode := getenv("POSTGRES_SSL_MODE", "disable")
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		host, port, user, pass, dbname, sslmode)
+}
+
+func getenv(k, def string) string {
+	if v := os.Getenv(k); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,568 @@
+//go:build postgresql
+
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/database/model"
+	infraports "github.com/erniealice/espyna-golang/internal/application/ports/infrastructure"
+	"github.com/lib/pq"
+)
+
+// maxBatchParams is the PostgreSQL wire-protocol limit on bind parameters per
+// statement. Batches are chunked so no single statement exceeds it.
+const maxBatchParams = 65535
+
+// CreateMany inserts every row with multi-row INSERT ... VALUES statements
+// (one per chunk of maxBatchParams parameters) inside a single transaction.
+//
+// Rows are stamped exactly like Create (id, active, date_created,
+// date_modified). Rows may carry different column sets; a column missing
+// from a row is written as DEFAULT. Results are returned in input order.
+func (p *PostgresOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	if tableName == "" {
+		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+	if len(data) == 0 {
+		return []map[string]any{}, nil
+	}
+
+	resultColumns, err := p.getTableColumns(ctx, tableName)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to get table columns: %v", err),
+			"POSTGRES_SCHEMA_ERROR",
+			500,
+		)
+	}
+	validColumns := make(map[string]bool, len(resultColumns))
+	for _, col := range resultColumns {
+		validColumns[col] = true
+	}
+	columnTypes, err := p.getTableColumnTypes(ctx, tableName)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to get table column types: %v", err),
+			"POSTGRES_SCHEMA_ERROR",
+			500,
+		)
+	}
+	shadowAssertColumnSet(tableName, validColumns)
+
+	now := time.Now().UTC()
+	dateCreated := autoTimestampValue(shadowTimestampType(tableName, "date_created", columnTypes), now)
+	dateModified := autoTimestampValue(shadowTimestampType(tableName, "date_modified", columnTypes), now)
+
+	rows := make([]map[string]any, len(data))
+	ids := make([]string, len(data))
+	used := make(map[string]bool)
+	skipped := make(map[string]bool)
+	for i, row := range data {
+		row = normalizeKeys(row)
+		if _, exists := row["id"]; !exists {
+			row["id"] = generateUUID()
+		}
+		row["active"] = true
+		row["date_created"] = dateCreated
+		row["date_modified"] = dateModified
+		for column := range row {
+			if validColumns[column] {
+				used[column] = true
+			} else {
+				skipped[column] = true
+			}
+		}
+		rows[i] = row
+		ids[i] = fmt.Sprintf("%v", row["id"])
+	}
+	if err := checkDroppedFields(ctx, "create_many", tableName, sortedKeys(skipped)); err != nil {
+		return nil, err
+	}
+	columns := sortedKeys(used)
+	chunkSize := max(maxBatchParams/len(columns), 1)
+
+	created := make(map[string]map[string]any, len(rows))
+	err = p.runBatch(ctx, func(exec dbExecutor) error {
+		for start := 0; start < len(rows); start += chunkSize {
+			end := min(start+chunkSize, len(rows))
+			tuples := make([]string, 0, end-start)
+			values := make([]any, 0, (end-start)*len(columns))
+			for _, row := range rows[start:end] {
+				placeholders := make([]string, len(columns))
+				for j, column := range columns {
+					value, ok := row[column]
+					if !ok {
+						placeholders[j] = "DEFAULT"
+						continue
+					}
+					values = append(values, serializeValue(value))
+					placeholders[j] = fmt.Sprintf("$%d", len(values))
+				}
+				tuples = append(tuples, "("+strings.Join(placeholders, ", ")+")")
+			}
+			query := fmt.Sprintf(
+				"INSERT INTO \"%s\" (%s) VALUES %s RETURNING *",
+				tableName,
+				quoteColumns(columns),
+				strings.Join(tuples, ", "),
+			)
+			if err := p.collectReturning(ctx, exec, query, values, resultColumns, created); err != nil {
+				return model.NewDatabaseError(
+					fmt.Sprintf("failed to create records: %v", err),
+					"POSTGRES_CREATE_FAILED",
+					500,
+				)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := inInputOrder(ids, created)
+	if err != nil {
+		return nil, err
+	}
+	if p.auditService != nil {
+		for _, result := range results {
+			if err := infraports.DiffAndLog(ctx, p.auditService, infraports.DiffAndLogRequest{
+				EntityType: tableName,
+				EntityID:   fmt.Sprintf("%v", result["id"]),
+				Domain:     tableName,
+				Action:     1, // INSERT
+				MethodName: "PostgresOperations.CreateMany",
+				NewData:    result,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// UpdateMany applies every update with UPDATE ... FROM (VALUES ...) inside a
+// single transaction. Updates that touch the same column set share one
+// statement per chunk; parameters are cast to the target column types so
+// the VALUES list types the same way a single-row UPDATE would.
+//
+// date_created is never rewritten and date_modified is stamped once for the
+// batch. Unlike Update there is no per-row existence read: a missing id
+// fails the whole batch with RECORD_NOT_FOUND after the fact.
+func (p *PostgresOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	if tableName == "" {
+		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+	if len(updates) == 0 {
+		return []map[string]any{}, nil
+	}
+
+	resultColumns, err := p.getTableColumns(ctx, tableName)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to get table columns: %v", err),
+			"POSTGRES_SCHEMA_ERROR",
+			500,
+		)
+	}
+	validColumns := make(map[string]bool, len(resultColumns))
+	for _, col := range resultColumns {
+		validColumns[col] = true
+	}
+	columnTypes, err := p.getTableColumnTypes(ctx, tableName)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to get table column types: %v", err),
+			"POSTGRES_SCHEMA_ERROR",
+			500,
+		)
+	}
+	castTypes, err := p.getTableCastTypes(ctx, tableName)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to get table column types: %v", err),
+			"POSTGRES_SCHEMA_ERROR",
+			500,
+		)
+	}
+	shadowAssertColumnSet(tableName, validColumns)
+
+	now := time.Now().UTC()
+	dateModified := autoTimestampValue(shadowTimestampType(tableName, "date_modified", columnTypes), now)
+
+	// Group updates by column set so each group is one statement shape.
+	type group struct {
+		columns []string
+		rows    []int
+	}
+	groups := make(map[string]*group)
+	var order []string
+	ids := make([]string, len(updates))
+	prepared := make([]map[string]any, len(updates))
+	seen := make(map[string]bool, len(updates))
+	skipped := make(map[string]bool)
+	for i, u := range updates {
+		if u.ID == "" {
+			return nil, model.NewDatabaseError("record ID is required", "MISSING_RECORD_ID", 400)
+		}
+		if seen[u.ID] {
+			return nil, model.NewDatabaseError(
+				fmt.Sprintf("record %s appears more than once in the batch", u.ID),
+				"DUPLICATE_RECORD_ID",
+				400,
+			)
+		}
+		seen[u.ID] = true
+		ids[i] = u.ID
+
+		data := normalizeKeys(u.Data)
+		delete(data, "id")
+		delete(data, "date_created")
+		data["date_modified"] = dateModified
+		columns := make([]string, 0, len(data))
+		for column := range data {
+			if validColumns[column] {
+				columns = append(columns, column)
+			} else {
+				skipped[column] = true
+			}
+		}
+		sort.Strings(columns)
+		prepared[i] = data
+
+		key := strings.Join(columns, ",")
+		g, ok := groups[key]
+		if !ok {
+			g = &group{columns: columns}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.rows = append(g.rows, i)
+	}
+	if err := checkDroppedFields(ctx, "update_many", tableName, sortedKeys(skipped)); err != nil {
+		return nil, err
+	}
+
+	idType := castTypes["id"]
+	if idType == "" {
+		idType = "text"
+	}
+
+	var existing map[string]map[string]any
+	updated := make(map[string]map[string]any, len(updates))
+	err = p.runBatch(ctx, func(exec dbExecutor) error {
+		if p.auditService != nil {
+			existing = make(map[string]map[string]any, len(ids))
+			query := fmt.Sprintf("SELECT * FROM \"%s\" WHERE id = ANY($1::%s[])", tableName, idType)
+			if err := p.collectReturning(ctx, exec, query, []any{pq.Array(ids)}, resultColumns, existing); err != nil {
+				return model.NewDatabaseError(
+					fmt.Sprintf("failed to read records for update: %v", err),
+					"POSTGRES_READ_FAILED",
+					500,
+				)
+			}
+		}
+
+		for _, key := range order {
+			g := groups[key]
+			perRow := len(g.columns) + 1
+			chunkSize := max(maxBatchParams/perRow, 1)
+
+			aliases := make([]string, 0, perRow)
+			aliases = append(aliases, "id")
+			setParts := make([]string, len(g.columns))
+			for j, column := range g.columns {
+				aliases = append(aliases, quoteIdent(column))
+				setParts[j] = quoteIdent(column) + " = v." + quoteIdent(column)
+			}
+
+			for start := 0; start < len(g.rows); start += chunkSize {
+				end := min(start+chunkSize, len(g.rows))
+				tuples := make([]string, 0, end-start)
+				values := make([]any, 0, (end-start)*perRow)
+				for _, idx := range g.rows[start:end] {
+					values = append(values, ids[idx])
+					placeholders := make([]string, 0, perRow)
+					placeholders = append(placeholders, fmt.Sprintf("$%d::%s", len(values), idType))
+					for _, column := range g.columns {
+						values = append(values, serializeValue(prepared[idx][column]))
+						placeholders = append(placeholders, fmt.Sprintf("$%d::%s", len(values), castTypeOrText(castTypes, column)))
+					}
+					tuples = append(tuples, "("+strings.Join(placeholders, ", ")+")")
+				}
+				// No active filter — same as Update, so soft-deleted rows can be
+				// re-activated in bulk.
+				query := fmt.Sprintf(
+					"UPDATE \"%s\" AS t SET %s FROM (VALUES %s) AS v(%s) WHERE t.id = v.id RETURNING t.*",
+					tableName,
+					strings.Join(setParts, ", "),
+					strings.Join(tuples, ", "),
+					strings.Join(aliases, ", "),
+				)
+				if err := p.collectReturning(ctx, exec, query, values, resultColumns, updated); err != nil {
+					return model.NewDatabaseError(
+						fmt.Sprintf("failed to update records: %v", err),
+						"POSTGRES_UPDATE_FAILED",
+						500,
+					)
+				}
+			}
+		}
+
+		// Returning before commit rolls the whole batch back.
+		if missing := missingIDs(ids, updated); len(missing) > 0 {
+			return model.NewDatabaseError(
+				fmt.Sprintf("%d record(s) not found: %s", len(missing), strings.Join(missing, ", ")),
+				"RECORD_NOT_FOUND",
+				404,
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := inInputOrder(ids, updated)
+	if err != nil {
+		return nil, err
+	}
+	if p.auditService != nil {
+		for i, result := range results {
+			if err := infraports.DiffAndLog(ctx, p.auditService, infraports.DiffAndLogRequest{
+				EntityType: tableName,
+				EntityID:   ids[i],
+				Domain:     tableName,
+				Action:     2, // UPDATE
+				MethodName: "PostgresOperations.UpdateMany",
+				OldData:    existing[ids[i]],
+				NewData:    result,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// DeleteMany soft-deletes every id with a single UPDATE. Like Delete it is
+// idempotent for already-inactive rows; an id that does not exist fails the
+// whole batch with RECORD_NOT_FOUND.
+func (p *PostgresOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	if tableName == "" {
+		return model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			return model.NewDatabaseError("record ID is required", "MISSING_RECORD_ID", 400)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+
+	columnTypes, err := p.getTableColumnTypes(ctx, tableName)
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to get table column types: %v", err),
+			"POSTGRES_SCHEMA_ERROR",
+			500,
+		)
+	}
+	castTypes, err := p.getTableCastTypes(ctx, tableName)
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to get table column types: %v", err),
+			"POSTGRES_SCHEMA_ERROR",
+			500,
+		)
+	}
+	idType := castTypes["id"]
+	if idType == "" {
+		idType = "text"
+	}
+	now := time.Now().UTC()
+	dateModified := autoTimestampValue(shadowTimestampType(tableName, "date_modified", columnTypes), now)
+
+	query := fmt.Sprintf(
+		"UPDATE \"%s\" SET active = false, date_modified = $1 WHERE id = ANY($2::%s[])",
+		tableName,
+		idType,
+	)
+	err = p.runBatch(ctx, func(exec dbExecutor) error {
+		result, err := exec.ExecContext(ctx, query, dateModified, pq.Array(unique))
+		if err != nil {
+			return model.NewDatabaseError(
+				fmt.Sprintf("failed to delete records: %v", err),
+				"POSTGRES_DELETE_FAILED",
+				500,
+			)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return model.NewDatabaseError(
+				fmt.Sprintf("failed to get affected rows: %v", err),
+				"POSTGRES_DELETE_FAILED",
+				500,
+			)
+		}
+		if rowsAffected != int64(len(unique)) {
+			return model.NewDatabaseError(
+				fmt.Sprintf("%d of %d record(s) not found", int64(len(unique))-rowsAffected, len(unique)),
+				"RECORD_NOT_FOUND",
+				404,
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if p.auditService != nil {
+		for _, id := range unique {
+			if err := infraports.DiffAndLog(ctx, p.auditService, infraports.DiffAndLogRequest{
+				EntityType: tableName,
+				EntityID:   id,
+				Domain:     tableName,
+				Action:     3, // DELETE
+				MethodName: "PostgresOperations.DeleteMany",
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runBatch runs fn on the context transaction when one is active, otherwise
+// on a transaction of its own so a batch is all-or-nothing either way.
+func (p *PostgresOperations) runBatch(ctx context.Context, fn func(exec dbExecutor) error) error {
+	if tx, ok := p.getExecutor(ctx).(*sql.Tx); ok {
+		return fn(tx)
+	}
+	return p.RunWithTransaction(ctx, func(tx *sql.Tx) error {
+		return fn(tx)
+	})
+}
+
+// collectReturning runs a row-returning statement and indexes every row by id.
+func (p *PostgresOperations) collectReturning(
+	ctx context.Context,
+	exec dbExecutor,
+	query string,
+	values []any,
+	columns []string,
+	into map[string]map[string]any,
+) error {
+	rows, err := exec.QueryContext(ctx, query, values...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		result, err := p.scanRowsToMap(rows, columns)
+		if err != nil {
+			return err
+		}
+		into[fmt.Sprintf("%v", result["id"])] = result
+	}
+	return rows.Err()
+}
+
+// getTableCastTypes returns column-name → SQL type name (format_type, e.g.
+// "character varying(64)", "text[]", "jsonb") for casting VALUES-list
+// parameters. information_schema.data_type is not castable for arrays and
+// enums ("ARRAY", "USER-DEFINED"), hence the pg_catalog lookup.
+func (p *PostgresOperations) getTableCastTypes(ctx context.Context, tableName string) (map[string]string, error) {
+	query := `
+		SELECT a.attname, format_type(a.atttypid, a.atttypmod)
+		FROM pg_attribute a
+		WHERE a.attrelid = to_regclass(quote_ident($1))
+		  AND a.attnum > 0
+		  AND NOT a.attisdropped
+	`
+	rows, err := p.getExecutor(ctx).QueryContext(ctx, query, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := make(map[string]string)
+	for rows.Next() {
+		var name, typeName string
+		if err := rows.Scan(&name, &typeName); err != nil {
+			return nil, err
+		}
+		types[name] = typeName
+	}
+	return types, rows.Err()
+}
+
+func castTypeOrText(castTypes map[string]string, column string) string {
+	if t := castTypes[column]; t != "" {
+		return t
+	}
+	return "text"
+}
+
+// inInputOrder lines results up with the ids the caller passed.
+func inInputOrder(ids []string, byID map[string]map[string]any) ([]map[string]any, error) {
+	results := make([]map[string]any, len(ids))
+	for i, id := range ids {
+		result, ok := byID[id]
+		if !ok {
+			return nil, model.NewDatabaseError(
+				fmt.Sprintf("record %s missing from batch result", id),
+				"POSTGRES_BATCH_RESULT_MISMATCH",
+				500,
+			)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+func missingIDs(ids []string, byID map[string]map[string]any) []string {
+	var missing []string
+	for _, id := range ids {
+		if _, ok := byID[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+func quoteColumns(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quoteIdent(column)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"strings"
 
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
 )
 
 // BuildFilterWhere constructs parameterized WHERE clauses from proto filter/search requests.
 // Returns (clauses, args, nextParamIndex). Caller joins clauses with " AND ".
-// searchFields specifies which columns to ILIKE search against.
+// searchFields specifies which columns to search: ILIKE, or full-text for
+// columns marked with interfaces.FullText. A string filter whose field is
+// marked matches the words of its value instead of its operator.
 // This function is used by entity CTE adapters to avoid duplicating filter logic.
 func BuildFilterWhere(
 	filters *commonpb.FilterRequest,
@@ -21,16 +24,13 @@ func BuildFilterWhere(
 ) (clauses []string, args []any, nextIdx int) {
 	nextIdx = startIdx
 
-	// Search — ILIKE OR block across declared search fields
+	// Search — OR block across declared search fields
 	if search != nil && search.Query != "" && len(searchFields) > 0 {
-		query := "%" + search.Query + "%"
-		var likeClauses []string
-		for _, col := range searchFields {
-			args = append(args, query)
-			likeClauses = append(likeClauses, fmt.Sprintf("%s ILIKE $%d", col, nextIdx))
-			nextIdx++
-		}
-		clauses = append(clauses, "("+strings.Join(likeClauses, " OR ")+")")
+		var condition string
+		var searchArgs []any
+		condition, searchArgs, nextIdx, _ = buildSearchCondition(search.Query, searchFields, nextIdx)
+		clauses = append(clauses, condition)
+		args = append(args, searchArgs...)
 	}
 
 	// Typed filters
@@ -41,6 +41,13 @@ func BuildFilterWhere(
 			switch ft := filter.FilterType.(type) {
 			case *commonpb.TypedFilter_StringFilter:
 				sf := ft.StringFilter
+				if column, ok := interfaces.FullTextField(field); ok {
+					clause, clauseArgs, next := buildTextFilter(column, sf.Value, nextIdx)
+					clauses = append(clauses, clause)
+					args = append(args, clauseArgs...)
+					nextIdx = next
+					continue
+				}
 				switch sf.Operator {
 				case commonpb.StringOperator_STRING_CONTAINS:
 					args = append(args, "%"+sf.Value+"%")
@@ -0,0 +1,187 @@
+//go:build postgresql
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/database/model"
+	"github.com/erniealice/espyna-golang/shared/metrics"
+	"github.com/erniealice/espyna-golang/shared/tracing"
+)
+
+// textSearchConfig is the text search configuration of both sides of a
+// full-text match. 'simple' lowercases without stemming or stop words,
+// which suits names, emails and reference numbers in any language. The GIN
+// indexes of migration 000009 are built on the same expression (see
+// textVector); changing it needs a migration rebuilding them.
+const textSearchConfig = "simple"
+
+// fullTextEnvVar set to "false" turns full-text matching off, for
+// Postgres-compatible databases without tsvector support. Marked filters
+// and search fields then fall back to ILIKE.
+const fullTextEnvVar = "POSTGRES_FULL_TEXT"
+
+var fullTextEnabled = os.Getenv(fullTextEnvVar) != "false"
+
+// textQuery turns user input into a tsquery matching rows that contain every
+// word, each as a prefix: "Ana San" -> "ana:* & san:*". Words are runs of
+// letters and digits, so the result never carries tsquery syntax. ok is
+// false when the input has no words.
+func textQuery(input string) (query string, ok bool) {
+	words := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return "", false
+	}
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & "), true
+}
+
+// textVector is the tsvector expression of column. It must stay identical
+// to the index expressions for the planner to use them.
+func textVector(column string) string {
+	return fmt.Sprintf("to_tsvector('%s', coalesce(%s, ''))", textSearchConfig, column)
+}
+
+// textMatch is the condition matching column against the tsquery bound at
+// paramIndex.
+func textMatch(column string, paramIndex int) string {
+	return fmt.Sprintf("%s @@ to_tsquery('%s', $%d)", textVector(column), textSearchConfig, paramIndex)
+}
+
+// textRank is the relevance of column for the tsquery bound at paramIndex.
+func textRank(column string, paramIndex int) string {
+	return fmt.Sprintf("ts_rank(%s, to_tsquery('%s', $%d))", textVector(column), textSearchConfig, paramIndex)
+}
+
+// buildTextFilter builds the condition of a filter marked full-text: the
+// words of value against column, or column ILIKE %value% when full-text
+// matching is off or value has no words.
+func buildTextFilter(column, value string, paramIndex int) (string, []any, int) {
+	if query, ok := textQuery(value); ok && fullTextEnabled {
+		return textMatch(column, paramIndex), []any{query}, paramIndex + 1
+	}
+	return fmt.Sprintf("%s ILIKE $%d", column, paramIndex), []any{"%" + value + "%"}, paramIndex + 1
+}
+
+// buildSearchCondition builds the OR block of a search across fields.
+// Fields marked full-text share one tsquery parameter; the others, and all
+// of them when full-text matching is off or query has no words, use ILIKE.
+// rank sums the relevance of the full-text fields and is empty when none
+// were matched that way.
+func buildSearchCondition(query string, fields []string, paramIndex int) (condition string, values []any, nextIndex int, rank string) {
+	tsQuery, ok := textQuery(query)
+	ok = ok && fullTextEnabled
+	tsIndex := 0
+
+	var clauses, ranks []string
+	for _, field := range fields {
+		column, marked := interfaces.FullTextField(field)
+		if marked && ok {
+			if tsIndex == 0 {
+				tsIndex = paramIndex
+				values = append(values, tsQuery)
+				paramIndex++
+			}
+			clauses = append(clauses, textMatch(column, tsIndex))
+			ranks = append(ranks, textRank(column, tsIndex))
+			continue
+		}
+		values = append(values, "%"+query+"%")
+		clauses = append(clauses, fmt.Sprintf("%s ILIKE $%d", column, paramIndex))
+		paramIndex++
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", values, paramIndex, strings.Join(ranks, " + ")
+}
+
+// SearchText lists the rows of tableName whose fields contain the words of
+// query, best matches first, narrowed and paged by params like List. Every
+// field is searched full-text; see interfaces.TextSearcher.
+func (p *PostgresOperations) SearchText(ctx context.Context, tableName string, fields []string, query string, params *interfaces.ListParams) (_ *interfaces.ListResult, opErr error) {
+	defer metrics.ObserveDBOperation("postgresql", "search_text", tableName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "postgresql", "search_text", tableName)
+	defer func() { span.End(opErr) }()
+	if tableName == "" {
+		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+	if len(fields) == 0 {
+		return nil, model.NewDatabaseError("text search requires fields", "MISSING_SEARCH_FIELDS", 400)
+	}
+	if _, byCursor := interfaces.CursorRequest(params); byCursor {
+		return nil, model.NewDatabaseError(
+			"cursor pagination is not supported by text search",
+			"CURSOR_PAGINATION_UNSUPPORTED",
+			400,
+		)
+	}
+	if params != nil && params.Search != nil {
+		withoutSearch := *params
+		withoutSearch.Search = nil
+		params = &withoutSearch
+	}
+
+	whereConditions, values, paramIndex, err := p.listConditions(params)
+	if err != nil {
+		return nil, err
+	}
+	marked := make([]string, len(fields))
+	for i, field := range fields {
+		marked[i] = interfaces.FullText(field)
+	}
+	condition, searchValues, paramIndex, rank := buildSearchCondition(query, marked, paramIndex)
+	whereConditions = append(whereConditions, condition)
+	values = append(values, searchValues...)
+
+	orderByClause := "ORDER BY date_created DESC"
+	if rank != "" {
+		orderByClause = "ORDER BY " + rank + " DESC, date_created DESC"
+	}
+
+	count, err := p.countWhere(ctx, tableName, whereConditions, values)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset := offsetPage(params)
+
+	sqlQuery := fmt.Sprintf(
+		"SELECT * FROM \"%s\" WHERE %s %s LIMIT $%d OFFSET $%d",
+		tableName,
+		strings.Join(whereConditions, " AND "),
+		orderByClause,
+		paramIndex,
+		paramIndex+1,
+	)
+	values = append(values, limit, offset)
+
+	results, err := p.listRows(ctx, sqlQuery, values)
+	if err != nil {
+		return nil, err
+	}
+	return &interfaces.ListResult{
+		Data:       results,
+		Total:      int32(count),
+		Pagination: offsetPagination(int32(count), limit, offset),
+	}, nil
+}
+
+// SearchText scopes the search to the context's workspace like List.
+func (w *WorkspaceAwareOperations) SearchText(ctx context.Context, tableName string, fields []string, query string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	searcher, ok := w.inner.(interfaces.TextSearcher)
+	if !ok {
+		return nil, model.NewDatabaseError("text search is not supported", "TEXT_SEARCH_UNSUPPORTED", 400)
+	}
+	if wsID := w.getWorkspaceID(ctx); wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		params = w.injectWorkspaceFilter(params, wsID)
+	}
+	return searcher.SearchText(ctx, tableName, fields, query, params)
+}
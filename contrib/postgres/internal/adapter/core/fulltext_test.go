@@ -0,0 +1,89 @@
+//go:build postgresql
+
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+func TestTextQuery(t *testing.T) {
+	tests := map[string]string{
+		"Ana San":         "ana:* & san:*",
+		"  INV-2024/0012": "inv:* & 2024:* & 0012:*",
+		"o'brien & (x|y)": "o:* & brien:* & x:* & y:*",
+		"Peña":            "peña:*",
+	}
+	for input, want := range tests {
+		if got, ok := textQuery(input); !ok || got != want {
+			t.Errorf("textQuery(%q) = %q, %v; want %q", input, got, ok, want)
+		}
+	}
+	if _, ok := textQuery(" %_-! "); ok {
+		t.Error("textQuery accepted input without words")
+	}
+}
+
+func TestBuildFilterWhere_FullText(t *testing.T) {
+	search := &commonpb.SearchRequest{Query: "ana san"}
+	filters := &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{
+		interfaces.FullTextFilter("c.notes", "late payer"),
+	}}
+	fields := []string{interfaces.FullText("c.name"), "c.internal_id", interfaces.FullText("u.last_name")}
+
+	clauses, args, next := BuildFilterWhere(filters, search, fields, 2)
+	wantClauses := []string{
+		"(to_tsvector('simple', coalesce(c.name, '')) @@ to_tsquery('simple', $2)" +
+			" OR c.internal_id ILIKE $3" +
+			" OR to_tsvector('simple', coalesce(u.last_name, '')) @@ to_tsquery('simple', $2))",
+		"to_tsvector('simple', coalesce(c.notes, '')) @@ to_tsquery('simple', $4)",
+	}
+	wantArgs := []any{"ana:* & san:*", "%ana san%", "late:* & payer:*"}
+	if !reflect.DeepEqual(clauses, wantClauses) || !reflect.DeepEqual(args, wantArgs) || next != 5 {
+		t.Fatalf("BuildFilterWhere =\n%q\n%q\n%d", clauses, args, next)
+	}
+}
+
+func TestBuildSearchCondition_FallsBackToILIKE(t *testing.T) {
+	fields := []string{interfaces.FullText("name")}
+
+	condition, values, next, rank := buildSearchCondition("%", fields, 1)
+	if condition != "(name ILIKE $1)" || !reflect.DeepEqual(values, []any{"%%%"}) || next != 2 || rank != "" {
+		t.Errorf("no words: %q %q %d %q", condition, values, next, rank)
+	}
+
+	fullTextEnabled = false
+	defer func() { fullTextEnabled = true }()
+	if condition, _, _, _ := buildSearchCondition("ana", fields, 1); condition != "(name ILIKE $1)" {
+		t.Errorf("disabled: %q", condition)
+	}
+	if condition, values, _ := buildTextFilter("name", "ana", 3); condition != "name ILIKE $3" || values[0] != "%ana%" {
+		t.Errorf("disabled filter: %q %q", condition, values)
+	}
+}
+
+func TestListConditions_FullTextFilter(t *testing.T) {
+	p := &PostgresOperations{}
+	params := &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{interfaces.FullTextFilter("name", "ana")}},
+		Search: &commonpb.SearchRequest{Query: "santos", Options: &commonpb.SearchOptions{
+			SearchFields: []string{interfaces.FullText("name")},
+		}},
+	}
+
+	conditions, values, next, err := p.listConditions(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"active = true",
+		"to_tsvector('simple', coalesce(name, '')) @@ to_tsquery('simple', $1)",
+		"(to_tsvector('simple', coalesce(name, '')) @@ to_tsquery('simple', $2))",
+	}
+	if !reflect.DeepEqual(conditions, want) || !reflect.DeepEqual(values, []any{"ana:*", "santos:*"}) || next != 3 {
+		t.Fatalf("listConditions =\n%q\n%q\n%d", conditions, values, next)
+	}
+}
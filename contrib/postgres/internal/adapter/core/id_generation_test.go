@@ -0,0 +1,52 @@
+//go:build postgresql
+
+package core
+
+import (
+	"testing"
+
+	"github.com/erniealice/espyna-golang/registry"
+	"github.com/google/uuid"
+)
+
+// stubIDGenerator mints id while enabled.
+type stubIDGenerator struct {
+	id      string
+	enabled bool
+}
+
+func (g *stubIDGenerator) GenerateID() string                        { return g.id }
+func (g *stubIDGenerator) GenerateIDWithPrefix(prefix string) string { return prefix + "_" + g.id }
+func (g *stubIDGenerator) IsEnabled() bool                           { return g.enabled }
+func (g *stubIDGenerator) GetProviderInfo() string                   { return "stub" }
+
+func TestGenerateUUIDUsesConfiguredGenerator(t *testing.T) {
+	t.Cleanup(func() { registry.SetDefaultIDGenerator(nil) })
+
+	const v7 = "0190a6b2-7c3e-7000-8000-000000000001"
+	registry.SetDefaultIDGenerator(&stubIDGenerator{id: v7, enabled: true})
+	if got := generateUUID(); got != v7 {
+		t.Errorf("generateUUID() = %q, want the configured generator's %q", got, v7)
+	}
+}
+
+func TestGenerateUUIDFallsBackToUUIDv4(t *testing.T) {
+	t.Cleanup(func() { registry.SetDefaultIDGenerator(nil) })
+
+	for _, gen := range []*stubIDGenerator{
+		nil,                            // no provider published
+		{id: "noop_1", enabled: false}, // disabled provider
+		{id: "", enabled: true},        // provider returned nothing
+	} {
+		if gen == nil {
+			registry.SetDefaultIDGenerator(nil)
+		} else {
+			registry.SetDefaultIDGenerator(gen)
+		}
+		got := generateUUID()
+		parsed, err := uuid.Parse(got)
+		if err != nil || parsed.Version() != 4 {
+			t.Errorf("generator %+v: generateUUID() = %q, want a UUIDv4", gen, got)
+		}
+	}
+}
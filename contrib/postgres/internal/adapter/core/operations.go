@@ -20,7 +20,10 @@ import (
 	infraports "github.com/erniealice/espyna-golang/internal/application/ports/infrastructure"
 	"github.com/erniealice/espyna-golang/registry"
 	"github.com/erniealice/espyna-golang/schema"
+	"github.com/erniealice/espyna-golang/shared/metrics"
+	"github.com/erniealice/espyna-golang/shared/tracing"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 )
 
@@ -73,7 +76,10 @@ func NewPostgresOperationsWithAudit(db *sql.DB, auditSvc infraports.AuditService
 }
 
 // Create creates a new record in the specified table
-func (p *PostgresOperations) Create(ctx context.Context, tableName string, data map[string]any) (map[string]any, error) {
+func (p *PostgresOperations) Create(ctx context.Context, tableName string, data map[string]any) (_ map[string]any, opErr error) {
+	defer metrics.ObserveDBOperation("postgresql", "create", tableName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "postgresql", "create", tableName)
+	defer func() { span.End(opErr) }()
 	if tableName == "" {
 		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
 	}
@@ -151,8 +157,8 @@ func (p *PostgresOperations) Create(ctx context.Context, tableName string, data
 		values = append(values, serializeValue(value))
 		i++
 	}
-	if len(skipped) > 0 {
-		log.Printf("PostgresOperations.Create: dropped %d unknown column(s) for table=%q skipped=%v", len(skipped), tableName, skipped)
+	if err := checkDroppedFields(ctx, "create", tableName, skipped); err != nil {
+		return nil, err
 	}
 	// SHADOW: surface where a descriptor-authoritative drop would differ from the
 	// reflected drop (the reflected `skipped` set still drives the write).
@@ -195,7 +201,10 @@ func (p *PostgresOperations) Create(ctx context.Context, tableName string, data
 }
 
 // Read retrieves a record by ID from the specified table
-func (p *PostgresOperations) Read(ctx context.Context, tableName string, id string) (map[string]any, error) {
+func (p *PostgresOperations) Read(ctx context.Context, tableName string, id string) (_ map[string]any, opErr error) {
+	defer metrics.ObserveDBOperation("postgresql", "read", tableName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "postgresql", "read", tableName)
+	defer func() { span.End(opErr) }()
 	if tableName == "" {
 		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
 	}
@@ -234,7 +243,10 @@ func (p *PostgresOperations) Read(ctx context.Context, tableName string, id stri
 }
 
 // Update updates an existing record in the specified table
-func (p *PostgresOperations) Update(ctx context.Context, tableName string, id string, data map[string]any) (map[string]any, error) {
+func (p *PostgresOperations) Update(ctx context.Context, tableName string, id string, data map[string]any) (_ map[string]any, opErr error) {
+	defer metrics.ObserveDBOperation("postgresql", "update", tableName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "postgresql", "update", tableName)
+	defer func() { span.End(opErr) }()
 	if tableName == "" {
 		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
 	}
@@ -338,8 +350,8 @@ func (p *PostgresOperations) Update(ctx context.Context, tableName string, id st
 		values = append(values, serializeValue(value))
 		i++
 	}
-	if len(skipped) > 0 {
-		log.Printf("PostgresOperations.Update: dropped %d unknown column(s) for table=%q id=%q skipped=%v", len(skipped), tableName, id, skipped)
+	if err := checkDroppedFields(ctx, "update", tableName, skipped); err != nil {
+		return nil, err
 	}
 	// SHADOW: surface where a descriptor-authoritative drop would differ from the
 	// reflected drop (the reflected `skipped` set still drives the write). The
@@ -386,7 +398,10 @@ func (p *PostgresOperations) Update(ctx context.Context, tableName string, id st
 }
 
 // Delete deletes a record from the specified table (soft delete by default)
-func (p *PostgresOperations) Delete(ctx context.Context, tableName string, id string) error {
+func (p *PostgresOperations) Delete(ctx context.Context, tableName string, id string) (opErr error) {
+	defer metrics.ObserveDBOperation("postgresql", "delete", tableName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "postgresql", "delete", tableName)
+	defer func() { span.End(opErr) }()
 	if tableName == "" {
 		return model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
 	}
@@ -459,6 +474,80 @@ func (p *PostgresOperations) Delete(ctx context.Context, tableName string, id st
 	return nil
 }
 
+// Restore undoes a soft Delete by setting active back to true and stamping
+// date_modified, typed like Delete's stamp. Restoring an active row is not
+// an error; a missing row is RECORD_NOT_FOUND.
+func (p *PostgresOperations) Restore(ctx context.Context, tableName string, id string) (opErr error) {
+	defer metrics.ObserveDBOperation("postgresql", "restore", tableName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "postgresql", "restore", tableName)
+	defer func() { span.End(opErr) }()
+	if tableName == "" {
+		return model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+	if id == "" {
+		return model.NewDatabaseError("record ID is required", "MISSING_RECORD_ID", 400)
+	}
+
+	columnTypes, err := p.getTableColumnTypes(ctx, tableName)
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to get table column types: %v", err),
+			"POSTGRES_SCHEMA_ERROR",
+			500,
+		)
+	}
+	now := time.Now().UTC()
+	dateModifiedType := shadowTimestampType(tableName, "date_modified", columnTypes)
+	query := fmt.Sprintf(
+		"UPDATE \"%s\" SET active = true, date_modified = $1 WHERE id = $2",
+		tableName,
+	)
+
+	result, err := p.getExecutor(ctx).ExecContext(ctx, query, autoTimestampValue(dateModifiedType, now), id)
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to restore record: %v", err),
+			"POSTGRES_RESTORE_FAILED",
+			500,
+		)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to get affected rows: %v", err),
+			"POSTGRES_RESTORE_FAILED",
+			500,
+		)
+	}
+
+	if rowsAffected == 0 {
+		return model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+
+	if p.auditService != nil {
+		if err := infraports.DiffAndLog(ctx, p.auditService, infraports.DiffAndLogRequest{
+			EntityType: tableName,
+			EntityID:   id,
+			Domain:     tableName,
+			Action:     2, // UPDATE
+			MethodName: "PostgresOperations.Restore",
+			OldData:    map[string]any{"active": false},
+			NewData:    map[string]any{"active": true},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListDeleted lists soft-deleted rows (active = false) through List, so
+// filters, sort, offset and cursor pagination behave the same.
+func (p *PostgresOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return p.List(ctx, tableName, interfaces.DeletedParams(params))
+}
+
 // HardDelete permanently deletes a record from the specified table.
 //
 // TODO(recycle-bin): long-term, catalog entities (product, plan, price_plan,
@@ -470,7 +559,10 @@ func (p *PostgresOperations) Delete(ctx context.Context, tableName string, id st
 // previous soft-delete implementation suffered from. The current hard-delete
 // behavior relies on FK RESTRICT as the safety net; the recycle-bin layer
 // should preserve that guarantee by checking references before bin insert.
-func (p *PostgresOperations) HardDelete(ctx context.Context, tableName string, id string) error {
+func (p *PostgresOperations) HardDelete(ctx context.Context, tableName string, id string) (opErr error) {
+	defer metrics.ObserveDBOperation("postgresql", "hard_delete", tableName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "postgresql", "hard_delete", tableName)
+	defer func() { span.End(opErr) }()
 	if tableName == "" {
 		return model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
 	}
@@ -506,59 +598,21 @@ func (p *PostgresOperations) HardDelete(ctx context.Context, tableName string, i
 }
 
 // List retrieves records from the specified table with standardized params
-func (p *PostgresOperations) List(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+func (p *PostgresOperations) List(ctx context.Context, tableName string, params *interfaces.ListParams) (_ *interfaces.ListResult, opErr error) {
+	defer metrics.ObserveDBOperation("postgresql", "list", tableName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "postgresql", "list", tableName)
+	defer func() { span.End(opErr) }()
 	if tableName == "" {
 		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
 	}
 
-	// Build WHERE clause.
-	// Default to active = true unless the caller supplies an explicit "active"
-	// BooleanFilter — in that case we honour the caller's value so that inactive
-	// records can be retrieved (e.g. inactive product/service list page).
-	hasActiveFilter := false
-	if params != nil && params.Filters != nil {
-		for _, f := range params.Filters.Filters {
-			if f.GetField() == "active" {
-				if _, ok := f.FilterType.(*commonpb.TypedFilter_BooleanFilter); ok {
-					hasActiveFilter = true
-					break
-				}
-			}
-		}
-	}
-	var whereConditions []string
-	if !hasActiveFilter {
-		whereConditions = []string{"active = true"}
-	}
-	values := []any{}
-	paramIndex := 1
-
-	// Apply filters from FilterRequest
-	if params != nil && params.Filters != nil {
-		filterConditions, filterValues, nextIndex := p.buildFilterConditions(params.Filters, paramIndex)
-		whereConditions = append(whereConditions, filterConditions...)
-		values = append(values, filterValues...)
-		paramIndex = nextIndex
+	whereConditions, values, paramIndex, err := p.listConditions(params)
+	if err != nil {
+		return nil, err
 	}
 
-	// Search — ILIKE OR block across declared search fields
-	if params != nil && params.Search != nil && params.Search.Query != "" {
-		query := "%" + params.Search.Query + "%"
-		fields := params.Search.GetOptions().GetSearchFields()
-		if len(fields) == 0 {
-			return nil, model.NewDatabaseError(
-				"search requires SearchOptions.search_fields",
-				"MISSING_SEARCH_FIELDS",
-				400,
-			)
-		}
-		var likeClauses []string
-		for _, col := range fields {
-			values = append(values, query)
-			likeClauses = append(likeClauses, fmt.Sprintf("%s ILIKE $%d", col, paramIndex))
-			paramIndex++
-		}
-		whereConditions = append(whereConditions, "("+strings.Join(likeClauses, " OR ")+")")
+	if token, byCursor := interfaces.CursorRequest(params); byCursor {
+		return p.listByCursor(ctx, tableName, params, token, whereConditions, values, paramIndex)
 	}
 
 	// Build ORDER BY clause
@@ -585,25 +639,42 @@ func (p *PostgresOperations) List(ctx context.Context, tableName string, params
 	}
 
 	// Get total count before pagination
-	countQuery := fmt.Sprintf(
-		"SELECT COUNT(*) FROM \"%s\" WHERE %s",
+	count, err := p.countWhere(ctx, tableName, whereConditions, values)
+	if err != nil {
+		return nil, err
+	}
+	totalItems := int32(count)
+
+	// Apply pagination
+	limit, offset := offsetPage(params)
+
+	// Build final query with pagination
+	query := fmt.Sprintf(
+		"SELECT * FROM \"%s\" WHERE %s %s LIMIT $%d OFFSET $%d",
 		tableName,
 		strings.Join(whereConditions, " AND "),
+		orderByClause,
+		paramIndex,
+		paramIndex+1,
 	)
+	values = append(values, limit, offset)
 
-	var totalItems int32
-	err := p.getExecutor(ctx).QueryRowContext(ctx, countQuery, values...).Scan(&totalItems)
+	results, err := p.listRows(ctx, query, values)
 	if err != nil {
-		return nil, model.NewDatabaseError(
-			fmt.Sprintf("failed to count records: %v", err),
-			"POSTGRES_COUNT_FAILED",
-			500,
-		)
+		return nil, err
 	}
 
-	// Apply pagination
-	limit := int32(100) // Default limit
-	offset := int32(0)
+	return &interfaces.ListResult{
+		Data:       results,
+		Total:      totalItems,
+		Pagination: offsetPagination(totalItems, limit, offset),
+	}, nil
+}
+
+// offsetPage returns the LIMIT and OFFSET of an offset-paginated list: at
+// most 100 rows, 100 by default.
+func offsetPage(params *interfaces.ListParams) (limit, offset int32) {
+	limit = 100
 	if params != nil && params.Pagination != nil {
 		if params.Pagination.Limit > 0 && params.Pagination.Limit <= 100 {
 			limit = params.Pagination.Limit
@@ -615,19 +686,108 @@ func (p *PostgresOperations) List(ctx context.Context, tableName string, params
 			}
 		}
 	}
+	return limit, offset
+}
 
-	// Build final query with pagination
+// offsetPagination builds the pagination response of an offset page.
+func offsetPagination(totalItems, limit, offset int32) *commonpb.PaginationResponse {
+	currentPage := int32(1)
+	if offset > 0 && limit > 0 {
+		currentPage = (offset / limit) + 1
+	}
+	totalPages := (totalItems + limit - 1) / limit
+	if totalPages == 0 {
+		totalPages = 1
+	}
+	return &commonpb.PaginationResponse{
+		TotalItems:  totalItems,
+		CurrentPage: &currentPage,
+		TotalPages:  &totalPages,
+		HasNext:     currentPage < totalPages,
+		HasPrev:     currentPage > 1,
+	}
+}
+
+// cursorKeyColumn carries the exact date_created of each row of a cursor
+// page; it is removed before the rows are returned.
+const cursorKeyColumn = "_cursor_key"
+
+// listByCursor serves a keyset page: rows after the token's (date_created,
+// id) in that order, fetching one extra row to learn whether another page
+// follows. The total still counts every matching row.
+func (p *PostgresOperations) listByCursor(ctx context.Context, tableName string, params *interfaces.ListParams, token string, whereConditions []string, values []any, paramIndex int) (*interfaces.ListResult, error) {
+	descending, err := interfaces.CursorDescending(params)
+	if err != nil {
+		return nil, model.NewDatabaseError(err.Error(), "INVALID_CURSOR_SORT", 400)
+	}
+
+	count, err := p.countWhere(ctx, tableName, whereConditions, values)
+	if err != nil {
+		return nil, err
+	}
+
+	direction, comparison := "DESC", "<"
+	if !descending {
+		direction, comparison = "ASC", ">"
+	}
+	if token != "" {
+		cursor, err := interfaces.DecodeCursor(token)
+		if err != nil {
+			return nil, model.NewDatabaseError(err.Error(), "INVALID_CURSOR", 400)
+		}
+		whereConditions = append(whereConditions, fmt.Sprintf("(date_created, id) %s ($%d, $%d)", comparison, paramIndex, paramIndex+1))
+		values = append(values, cursor.Key, cursor.ID)
+		paramIndex += 2
+	}
+
+	limit := int32(100) // Default limit
+	if params.Pagination.Limit > 0 && params.Pagination.Limit <= 100 {
+		limit = params.Pagination.Limit
+	}
+
+	// The cursor key is selected as text: scanned timestamps are normalized
+	// to milliseconds, and resuming from a truncated key could skip rows
+	// created within the same millisecond. Postgres parses the text back into
+	// the column's own type when the token is bound.
 	query := fmt.Sprintf(
-		"SELECT * FROM \"%s\" WHERE %s %s LIMIT $%d OFFSET $%d",
+		"SELECT *, date_created::text AS %s FROM \"%s\" WHERE %s ORDER BY date_created %s, id %s LIMIT $%d",
+		cursorKeyColumn,
 		tableName,
 		strings.Join(whereConditions, " AND "),
-		orderByClause,
+		direction,
+		direction,
 		paramIndex,
-		paramIndex+1,
 	)
-	values = append(values, limit, offset)
+	values = append(values, limit+1)
 
-	// Execute query
+	results, err := p.listRows(ctx, query, values)
+	if err != nil {
+		return nil, err
+	}
+	more := len(results) > int(limit)
+	if more {
+		results = results[:limit]
+	}
+	var last map[string]any
+	for _, row := range results {
+		last = map[string]any{interfaces.CursorKeyField: row[cursorKeyColumn], "id": row["id"]}
+		delete(row, cursorKeyColumn)
+	}
+
+	pagination, next, err := interfaces.CursorPagination(last, token, more, int32(count))
+	if err != nil {
+		return nil, model.NewDatabaseError(err.Error(), "POSTGRES_LIST_FAILED", 500)
+	}
+	return &interfaces.ListResult{
+		Data:       results,
+		Total:      int32(count),
+		Pagination: pagination,
+		NextCursor: next,
+	}, nil
+}
+
+// listRows runs a list query and scans every row.
+func (p *PostgresOperations) listRows(ctx context.Context, query string, values []any) ([]map[string]any, error) {
 	rows, err := p.getExecutor(ctx).QueryContext(ctx, query, values...)
 	if err != nil {
 		return nil, model.NewDatabaseError(
@@ -669,34 +829,102 @@ func (p *PostgresOperations) List(ctx context.Context, tableName string, params
 			500,
 		)
 	}
+	return results, nil
+}
 
-	// Build pagination response
-	currentPage := int32(1)
-	if offset > 0 && limit > 0 {
-		currentPage = (offset / limit) + 1
+// Count returns the number of rows List would match for params with a
+// single SELECT COUNT(*), ignoring sort and pagination.
+func (p *PostgresOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (_ int64, opErr error) {
+	defer metrics.ObserveDBOperation("postgresql", "count", tableName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "postgresql", "count", tableName)
+	defer func() { span.End(opErr) }()
+	if tableName == "" {
+		return 0, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
 	}
-	totalPages := (totalItems + limit - 1) / limit
-	if totalPages == 0 {
-		totalPages = 1
+
+	whereConditions, values, _, err := p.listConditions(params)
+	if err != nil {
+		return 0, err
 	}
-	hasNext := currentPage < totalPages
-	hasPrev := currentPage > 1
+	return p.countWhere(ctx, tableName, whereConditions, values)
+}
 
-	return &interfaces.ListResult{
-		Data:  results,
-		Total: totalItems,
-		Pagination: &commonpb.PaginationResponse{
-			TotalItems:  totalItems,
-			CurrentPage: &currentPage,
-			TotalPages:  &totalPages,
-			HasNext:     hasNext,
-			HasPrev:     hasPrev,
-		},
-	}, nil
+// listConditions builds the WHERE conditions shared by List and Count and
+// returns them with their values and the next placeholder index.
+func (p *PostgresOperations) listConditions(params *interfaces.ListParams) ([]string, []any, int, error) {
+	// Default to active = true unless the caller supplies an explicit "active"
+	// BooleanFilter — in that case we honour the caller's value so that inactive
+	// records can be retrieved (e.g. inactive product/service list page).
+	hasActiveFilter := false
+	if params != nil && params.Filters != nil {
+		for _, f := range params.Filters.Filters {
+			if f.GetField() == "active" {
+				if _, ok := f.FilterType.(*commonpb.TypedFilter_BooleanFilter); ok {
+					hasActiveFilter = true
+					break
+				}
+			}
+		}
+	}
+	var whereConditions []string
+	if !hasActiveFilter {
+		whereConditions = []string{"active = true"}
+	}
+	values := []any{}
+	paramIndex := 1
+
+	// Apply filters from FilterRequest
+	if params != nil && params.Filters != nil {
+		filterConditions, filterValues, nextIndex := p.buildFilterConditions(params.Filters, paramIndex)
+		whereConditions = append(whereConditions, filterConditions...)
+		values = append(values, filterValues...)
+		paramIndex = nextIndex
+	}
+
+	// Search — OR block across declared search fields, full-text for the
+	// fields marked so and ILIKE for the rest
+	if params != nil && params.Search != nil && params.Search.Query != "" {
+		fields := params.Search.GetOptions().GetSearchFields()
+		if len(fields) == 0 {
+			return nil, nil, 0, model.NewDatabaseError(
+				"search requires SearchOptions.search_fields",
+				"MISSING_SEARCH_FIELDS",
+				400,
+			)
+		}
+		condition, searchValues, nextIndex, _ := buildSearchCondition(params.Search.Query, fields, paramIndex)
+		whereConditions = append(whereConditions, condition)
+		values = append(values, searchValues...)
+		paramIndex = nextIndex
+	}
+
+	return whereConditions, values, paramIndex, nil
+}
+
+// countWhere counts the rows of tableName matching whereConditions.
+func (p *PostgresOperations) countWhere(ctx context.Context, tableName string, whereConditions []string, values []any) (int64, error) {
+	countQuery := fmt.Sprintf(
+		"SELECT COUNT(*) FROM \"%s\" WHERE %s",
+		tableName,
+		strings.Join(whereConditions, " AND "),
+	)
+
+	var total int64
+	if err := p.getExecutor(ctx).QueryRowContext(ctx, countQuery, values...).Scan(&total); err != nil {
+		return 0, model.NewDatabaseError(
+			fmt.Sprintf("failed to count records: %v", err),
+			"POSTGRES_COUNT_FAILED",
+			500,
+		)
+	}
+	return total, nil
 }
 
 // Query executes a structured query against the PostgreSQL table
-func (p *PostgresOperations) Query(ctx context.Context, tableName string, queryBuilder interfaces.QueryBuilder) ([]map[string]any, error) {
+func (p *PostgresOperations) Query(ctx context.Context, tableName string, queryBuilder interfaces.QueryBuilder) (_ []map[string]any, opErr error) {
+	defer metrics.ObserveDBOperation("postgresql", "query", tableName, time.Now(), &opErr)
+	ctx, span := tracing.StartDBOperation(ctx, "postgresql", "query", tableName)
+	defer func() { span.End(opErr) }()
 	if tableName == "" {
 		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
 	}
@@ -874,7 +1102,14 @@ func (p *PostgresOperations) buildFilterConditions(filterReq *commonpb.FilterReq
 
 		switch ft := filter.FilterType.(type) {
 		case *commonpb.TypedFilter_StringFilter:
-			condition, vals, nextIndex := p.buildStringFilter(field, ft.StringFilter, paramIndex)
+			var condition string
+			var vals []any
+			var nextIndex int
+			if column, ok := interfaces.FullTextField(field); ok {
+				condition, vals, nextIndex = buildTextFilter(column, ft.StringFilter.Value, paramIndex)
+			} else {
+				condition, vals, nextIndex = p.buildStringFilter(field, ft.StringFilter, paramIndex)
+			}
 			conditions = append(conditions, condition)
 			values = append(values, vals...)
 			paramIndex = nextIndex
@@ -1557,9 +1792,16 @@ func normalizeValue(v any) any {
 	}
 }
 
-// generateUUID generates a simple UUID (simplified implementation)
+// generateUUID mints a primary key with the container's configured ID
+// provider (google_uuidv7, etc.), published via registry.SetDefaultIDGenerator.
+// Falls back to a crypto-random UUIDv4 when no enabled provider is set.
 func generateUUID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
+	if gen := registry.GetDefaultIDGenerator(); gen != nil {
+		if id := gen.GenerateID(); id != "" {
+			return id
+		}
+	}
+	return uuid.NewString()
 }
 
 // RunWithTransaction executes a function within a database transaction
@@ -0,0 +1,93 @@
+//go:build postgresql
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/database/model"
+	"github.com/erniealice/espyna-golang/schema"
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/metrics"
+)
+
+// Writes only store fields the live table has a column for. Most dropped
+// fields are expected: the message declares them but never persists them
+// (computed *_string mirrors, relations). The rest are unknown fields — a
+// field no message declares, or a descriptor column the live table lacks —
+// and mean the schema has drifted from the code, so their data is lost.
+//
+// unknownFieldsEnvVar chooses what a write does with unknown fields:
+//
+//	warn   (default) write the rest, log the fields, count them in
+//	       espyna_db_dropped_fields_total and report them to the context's
+//	       DroppedFields collector
+//	strict reject the write with UNKNOWN_FIELDS before touching the table
+//	ignore write the rest and only report them to the collector
+const unknownFieldsEnvVar = "POSTGRES_UNKNOWN_FIELDS"
+
+type unknownFieldsMode int
+
+const (
+	unknownFieldsWarn unknownFieldsMode = iota
+	unknownFieldsStrict
+	unknownFieldsIgnore
+)
+
+var unknownFields = parseUnknownFieldsMode(os.Getenv(unknownFieldsEnvVar))
+
+func parseUnknownFieldsMode(v string) unknownFieldsMode {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "strict":
+		return unknownFieldsStrict
+	case "ignore":
+		return unknownFieldsIgnore
+	default:
+		return unknownFieldsWarn
+	}
+}
+
+// unknownDroppedFields returns the sorted dropped fields of a write to
+// tableName that are not expected drops. Every drop is unknown for a table
+// the schema registry does not describe.
+func unknownDroppedFields(tableName string, dropped []string) []string {
+	columns, described := descriptorColumnSet(tableName)
+	var unknown []string
+	for _, field := range dropped {
+		if has, _ := schema.HasField(tableName, field); described && has && !columns[field] {
+			continue
+		}
+		unknown = append(unknown, field)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// checkDroppedFields applies the unknown-fields mode to the fields a write
+// (op is "create", "update_many", ...) is about to drop. It returns an
+// error only in strict mode, and must run before the write.
+func checkDroppedFields(ctx context.Context, op, tableName string, dropped []string) error {
+	unknown := unknownDroppedFields(tableName, dropped)
+	if len(unknown) == 0 {
+		return nil
+	}
+	switch unknownFields {
+	case unknownFieldsStrict:
+		return model.NewDatabaseError(
+			fmt.Sprintf("table %s has no column for field(s) %s", tableName, strings.Join(unknown, ", ")),
+			"UNKNOWN_FIELDS",
+			400,
+		)
+	case unknownFieldsWarn:
+		log.Printf("PostgresOperations %s: dropped %d unknown field(s) for table=%q fields=%v request_id=%q", op, len(unknown), tableName, unknown, correlation.FromContext(ctx))
+		metrics.ObserveDroppedFields("postgresql", op, tableName, len(unknown))
+	}
+	interfaces.ReportDroppedFields(ctx, tableName, unknown)
+	return nil
+}
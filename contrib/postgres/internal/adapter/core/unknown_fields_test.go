@@ -0,0 +1,52 @@
+//go:build postgresql
+
+package core
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/database/model"
+)
+
+func TestUnknownDroppedFields(t *testing.T) {
+	buildRegistry(t)
+
+	// The relation and the *_string mirror are never columns; cost is a
+	// column the live table has lost, and nickname is declared nowhere.
+	dropped := []string{"nickname", "asset", "date_created_string", "cost"}
+	if got := unknownDroppedFields("asset_component", dropped); !reflect.DeepEqual(got, []string{"cost", "nickname"}) {
+		t.Errorf("unknownDroppedFields(asset_component) = %v", got)
+	}
+	if got := unknownDroppedFields("no_such_table", []string{"name"}); !reflect.DeepEqual(got, []string{"name"}) {
+		t.Errorf("unknownDroppedFields(no_such_table) = %v", got)
+	}
+}
+
+func TestCheckDroppedFields(t *testing.T) {
+	buildRegistry(t)
+	defer func() { unknownFields = unknownFieldsWarn }()
+
+	ctx, dropped := interfaces.WithDroppedFields(context.Background())
+	if err := checkDroppedFields(ctx, "create", "asset_component", []string{"date_modified_string"}); err != nil || !dropped.Empty() {
+		t.Errorf("expected drop: err = %v, reported %v", err, dropped.Tables())
+	}
+	if err := checkDroppedFields(ctx, "create", "asset_component", []string{"nickname"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := dropped.Fields("asset_component"); !reflect.DeepEqual(got, []string{"nickname"}) {
+		t.Errorf("warn mode reported %v", got)
+	}
+
+	unknownFields = parseUnknownFieldsMode(" Strict ")
+	ctx, dropped = interfaces.WithDroppedFields(context.Background())
+	err := checkDroppedFields(ctx, "update", "asset_component", []string{"nickname"})
+	if dbErr, ok := model.GetDatabaseError(err); !ok || dbErr.Code != "UNKNOWN_FIELDS" || dbErr.HTTPStatus != 400 {
+		t.Errorf("strict mode err = %v", err)
+	}
+	if !dropped.Empty() {
+		t.Error("strict mode reported fields of a rejected write")
+	}
+}
@@ -5,15 +5,23 @@ package core
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log"
 	"os"
 	"sync"
 
-	"github.com/erniealice/espyna-golang/shared/identity"
+	dbcache "github.com/erniealice/espyna-golang/database/cache"
+	dbhistory "github.com/erniealice/espyna-golang/database/history"
 	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
 	"github.com/erniealice/espyna-golang/database/model"
+	dbshadow "github.com/erniealice/espyna-golang/database/shadow"
 	sqlexec "github.com/erniealice/espyna-golang/database/sqlexec"
+	dbtenancy "github.com/erniealice/espyna-golang/database/tenancy"
+	"github.com/erniealice/espyna-golang/registry"
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/identity"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+	"github.com/lib/pq"
 )
 
 // columnLessTenantTables is the set of TENANT-scoped tables that SHOULD be
@@ -244,13 +252,13 @@ func (w *WorkspaceAwareOperations) scopeColumnLessByParent(ctx context.Context,
 	if err != nil {
 		if w.enforce {
 			// ENFORCE: could not confirm ownership → fail closed.
-			log.Printf("AUTHZ_WS_DENY | mode=ENFORCE | table=%s | op=%s | id=%s | actingWs=%s | parentJoin=%s.%s->%s.%s | reason=probe_error | error=%v",
-				tableName, op, id, wsID, probe.childAlias, probe.fkColumn, probe.parentTable, probe.parentWs, err)
+			log.Printf("AUTHZ_WS_DENY | mode=ENFORCE | table=%s | op=%s | id=%s | actingWs=%s | parentJoin=%s.%s->%s.%s | reason=probe_error | error=%v | request_id=%s",
+				tableName, op, id, wsID, probe.childAlias, probe.fkColumn, probe.parentTable, probe.parentWs, err, correlation.FromContext(ctx))
 			return model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
 		}
 		// SHADOW: a probe error must NOT change behavior — pass through unchanged.
-		log.Printf("AUTHZ_WS_SHADOW_PASS | mode=SHADOW(passed) | table=%s | op=%s | id=%s | ws=%s | note=probe_error(%v)",
-			tableName, op, id, wsID, err)
+		log.Printf("AUTHZ_WS_SHADOW_PASS | mode=SHADOW(passed) | table=%s | op=%s | id=%s | ws=%s | note=probe_error(%v) | request_id=%s",
+			tableName, op, id, wsID, err, correlation.FromContext(ctx))
 		return nil
 	}
 
@@ -268,16 +276,16 @@ func (w *WorkspaceAwareOperations) scopeColumnLessByParent(ctx context.Context,
 	}
 
 	if w.enforce {
-		log.Printf("AUTHZ_WS_DENY | mode=ENFORCE | table=%s | op=%s | id=%s | actingWs=%s | derivedWs=%s | parentJoin=%s | wouldDeny=true",
-			tableName, op, id, wsID, derivedWs, parentJoinDesc)
+		log.Printf("AUTHZ_WS_DENY | mode=ENFORCE | table=%s | op=%s | id=%s | actingWs=%s | derivedWs=%s | parentJoin=%s | wouldDeny=true | request_id=%s",
+			tableName, op, id, wsID, derivedWs, parentJoinDesc, correlation.FromContext(ctx))
 		return model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
 	}
 
 	// SHADOW (default): log the would-be deny, but pass through so nothing breaks.
 	// Every AUTHZ_WS_SHADOW_DENY is a site where ENFORCE mode WOULD have blocked a
 	// cross-tenant by-id access. Grep these before flipping AUTHZ_ENFORCE on.
-	log.Printf("AUTHZ_WS_SHADOW_DENY | mode=SHADOW(allowed) | table=%s | op=%s | id=%s | actingWs=%s | derivedWs=%s | parentJoin=%s | wouldDeny=true",
-		tableName, op, id, wsID, derivedWs, parentJoinDesc)
+	log.Printf("AUTHZ_WS_SHADOW_DENY | mode=SHADOW(allowed) | table=%s | op=%s | id=%s | actingWs=%s | derivedWs=%s | parentJoin=%s | wouldDeny=true | request_id=%s",
+		tableName, op, id, wsID, derivedWs, parentJoinDesc, correlation.FromContext(ctx))
 	return nil
 }
 
@@ -339,7 +347,15 @@ func (w *WorkspaceAwareOperations) deriveWorkspaceViaParent(ctx context.Context,
 //
 //	dbOps := core.NewWorkspaceAwareOperations(db)
 type WorkspaceAwareOperations struct {
+	// inner is base wrapped in the result cache (a pass-through unless a cache
+	// is published through registry.SetDefaultCache) and in shadow reads (a
+	// pass-through unless a candidate is published through
+	// registry.SetDefaultShadow), and records revisions of the tables
+	// published through registry.SetDefaultHistory. All sit beneath the
+	// workspace layer so injected filters key List results and reach the
+	// candidate, and Read ownership checks still run on cached rows.
 	inner         interfaces.DatabaseOperation
+	base          interfaces.DatabaseOperation
 	db            *sql.DB
 	columnCache   map[string]map[string]bool // table name → column name → exists
 	columnCacheMu sync.RWMutex
@@ -358,8 +374,10 @@ var _ interfaces.DatabaseOperation = (*WorkspaceAwareOperations)(nil)
 // NewWorkspaceAwareOperations returns a DatabaseOperation that wraps a new
 // PostgresOperations instance with automatic workspace_id isolation.
 func NewWorkspaceAwareOperations(db *sql.DB) interfaces.DatabaseOperation {
+	base := NewPostgresOperations(db)
 	return &WorkspaceAwareOperations{
-		inner:       NewPostgresOperations(db),
+		inner:       dbhistory.NewHistoryOperations(dbshadow.NewShadowOperations(dbcache.NewCachedOperations(base), "postgresql")),
+		base:        base,
 		db:          db,
 		columnCache: make(map[string]map[string]bool),
 		enforce:     newWorkspaceEnforce(),
@@ -371,7 +389,8 @@ func NewWorkspaceAwareOperations(db *sql.DB) interfaces.DatabaseOperation {
 // (e.g. one created with NewPostgresOperationsWithAudit).
 func NewWorkspaceAwareOperationsFromInner(db *sql.DB, inner interfaces.DatabaseOperation) interfaces.DatabaseOperation {
 	return &WorkspaceAwareOperations{
-		inner:       inner,
+		inner:       dbhistory.NewHistoryOperations(dbshadow.NewShadowOperations(dbcache.NewCachedOperations(inner), "postgresql")),
+		base:        inner,
 		db:          db,
 		columnCache: make(map[string]map[string]bool),
 		enforce:     newWorkspaceEnforce(),
@@ -419,6 +438,16 @@ func (w *WorkspaceAwareOperations) List(ctx context.Context, tableName string, p
 	return w.inner.List(ctx, tableName, params)
 }
 
+// Count delegates to the inner Count with the same workspace_id filter as
+// List, so totals never include other workspaces' rows.
+func (w *WorkspaceAwareOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (int64, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		params = w.injectWorkspaceFilter(params, wsID)
+	}
+	return w.inner.Count(ctx, tableName, params)
+}
+
 // Create injects workspace_id into the data map before inserting, when the
 // context carries a workspace and the table has the column.
 func (w *WorkspaceAwareOperations) Create(ctx context.Context, tableName string, data map[string]any) (map[string]any, error) {
@@ -562,18 +591,120 @@ func (w *WorkspaceAwareOperations) HardDelete(ctx context.Context, tableName str
 	return w.inner.HardDelete(ctx, tableName, id)
 }
 
-// Query passes through to the inner operation. Injecting workspace filters
-// into QueryBuilder is non-trivial; callers that use Query are expected to
-// include workspace filtering themselves.
+// Restore verifies workspace ownership via a Read (which also finds
+// inactive rows), then delegates to the inner operation.
+func (w *WorkspaceAwareOperations) Restore(ctx context.Context, tableName string, id string) error {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		if _, err := w.Read(ctx, tableName, id); err != nil {
+			return err
+		}
+	} else if wsID != "" && columnLessTenantTables[tableName] {
+		// W2 step-2: column-less TENANT table (IDOR surface). SHADOW logs and
+		// passes through; ENFORCE returns 404 for a cross-tenant row.
+		if err := w.scopeColumnLessByParent(ctx, "restore", tableName, id, wsID); err != nil {
+			return err
+		}
+	}
+	return w.inner.Restore(ctx, tableName, id)
+}
+
+// ListDeleted delegates to the inner ListDeleted with the same workspace_id
+// filter as List.
+func (w *WorkspaceAwareOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		params = w.injectWorkspaceFilter(params, wsID)
+	}
+	return w.inner.ListDeleted(ctx, tableName, params)
+}
+
+// Query adds a workspace_id condition when the context carries a workspace
+// and the table has the column, like List.
 func (w *WorkspaceAwareOperations) Query(ctx context.Context, tableName string, query interfaces.QueryBuilder) ([]map[string]any, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		query = dbtenancy.ScopeQuery(query, wsID)
+	}
 	return w.inner.Query(ctx, tableName, query)
 }
 
-// QueryOne passes through to the inner operation (see Query).
+// QueryOne adds a workspace_id condition (see Query).
 func (w *WorkspaceAwareOperations) QueryOne(ctx context.Context, tableName string, query interfaces.QueryBuilder) (map[string]any, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		query = dbtenancy.ScopeQuery(query, wsID)
+	}
 	return w.inner.QueryOne(ctx, tableName, query)
 }
 
+// CreateMany injects workspace_id into every row (see Create).
+func (w *WorkspaceAwareOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		rows := make([]map[string]any, len(data))
+		for i, row := range data {
+			cloned := make(map[string]any, len(row)+1)
+			for k, v := range row {
+				cloned[k] = v
+			}
+			cloned["workspace_id"] = wsID
+			rows[i] = cloned
+		}
+		data = rows
+	}
+	return w.inner.CreateMany(ctx, tableName, data)
+}
+
+// UpdateMany verifies workspace ownership of every id with one query, strips
+// workspace_id from each payload, then delegates (see Update).
+func (w *WorkspaceAwareOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		ids := make([]string, len(updates))
+		stripped := make([]interfaces.BatchUpdate, len(updates))
+		for i, u := range updates {
+			ids[i] = u.ID
+			cloned := make(map[string]any, len(u.Data))
+			for k, v := range u.Data {
+				if k != "workspace_id" {
+					cloned[k] = v
+				}
+			}
+			stripped[i] = interfaces.BatchUpdate{ID: u.ID, Data: cloned}
+		}
+		if err := w.verifyOwnershipMany(ctx, tableName, ids, wsID); err != nil {
+			return nil, err
+		}
+		updates = stripped
+	} else if wsID != "" && columnLessTenantTables[tableName] {
+		for _, u := range updates {
+			if err := w.scopeColumnLessByParent(ctx, "update", tableName, u.ID, wsID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return w.inner.UpdateMany(ctx, tableName, updates)
+}
+
+// DeleteMany verifies workspace ownership of every id with one query, then
+// delegates the soft delete (see Delete).
+func (w *WorkspaceAwareOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		if err := w.verifyOwnershipMany(ctx, tableName, ids, wsID); err != nil {
+			return err
+		}
+	} else if wsID != "" && columnLessTenantTables[tableName] {
+		for _, id := range ids {
+			if err := w.scopeColumnLessByParent(ctx, "delete", tableName, id, wsID); err != nil {
+				return err
+			}
+		}
+	}
+	return w.inner.DeleteMany(ctx, tableName, ids)
+}
+
 // ── Optional interface methods (type-asserted by adapters) ───────────────────
 
 // GetDB returns the underlying *sql.DB so that adapters performing raw SQL
@@ -591,7 +722,7 @@ func (w *WorkspaceAwareOperations) GetExecutor(ctx context.Context) sqlexec.DBEx
 	type executorProvider interface {
 		GetExecutor(ctx context.Context) sqlexec.DBExecutor
 	}
-	if ep, ok := w.inner.(executorProvider); ok {
+	if ep, ok := w.base.(executorProvider); ok {
 		return ep.GetExecutor(ctx)
 	}
 	return w.db
@@ -610,6 +741,12 @@ func (w *WorkspaceAwareOperations) getWorkspaceID(ctx context.Context) string {
 // Results are cached with a read-preferred RWMutex; the first miss for a table
 // performs a live query against information_schema.columns.
 func (w *WorkspaceAwareOperations) tableHasWorkspaceColumn(ctx context.Context, tableName string) bool {
+	// Tables configured as global (CONFIG_TENANCY_GLOBAL_TABLES) are shared
+	// by every workspace even when they carry the column.
+	if registry.GetDefaultTenancy().GlobalTables[tableName] {
+		return false
+	}
+
 	w.columnCacheMu.RLock()
 	cols, cached := w.columnCache[tableName]
 	w.columnCacheMu.RUnlock()
@@ -693,3 +830,37 @@ func (w *WorkspaceAwareOperations) injectWorkspaceFilter(params *interfaces.List
 
 	return &cloned
 }
+
+// verifyOwnershipMany is the batch form of the Read-based ownership check:
+// every id must exist in tableName with workspace_id = wsID. NULL and
+// foreign-workspace rows count as missing, same 404 as Read.
+func (w *WorkspaceAwareOperations) verifyOwnershipMany(ctx context.Context, tableName string, ids []string, wsID string) error {
+	unique := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+	list := make([]string, 0, len(unique))
+	for id := range unique {
+		list = append(list, id)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT COUNT(*) FROM \"%s\" WHERE id = ANY($1) AND workspace_id = $2",
+		tableName,
+	)
+	var owned int
+	if err := w.GetExecutor(ctx).QueryRowContext(ctx, query, pq.Array(list), wsID).Scan(&owned); err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to verify record ownership: %v", err),
+			"POSTGRES_READ_FAILED",
+			500,
+		)
+	}
+	if owned != len(list) {
+		return model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return nil
+}
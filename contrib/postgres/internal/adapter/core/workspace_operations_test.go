@@ -42,9 +42,18 @@ func (s *stubInner) Delete(_ context.Context, _ string, _ string) error {
 func (s *stubInner) HardDelete(_ context.Context, _ string, _ string) error {
 	return s.deleteErr
 }
+func (s *stubInner) Restore(_ context.Context, _ string, _ string) error {
+	return s.updateErr
+}
+func (s *stubInner) ListDeleted(_ context.Context, _ string, _ *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return &interfaces.ListResult{}, nil
+}
 func (s *stubInner) List(_ context.Context, _ string, _ *interfaces.ListParams) (*interfaces.ListResult, error) {
 	return &interfaces.ListResult{}, nil
 }
+func (s *stubInner) Count(_ context.Context, _ string, _ *interfaces.ListParams) (int64, error) {
+	return 0, nil
+}
 func (s *stubInner) Create(_ context.Context, _ string, data map[string]any) (map[string]any, error) {
 	return data, nil
 }
@@ -54,6 +63,15 @@ func (s *stubInner) Query(_ context.Context, _ string, _ interfaces.QueryBuilder
 func (s *stubInner) QueryOne(_ context.Context, _ string, _ interfaces.QueryBuilder) (map[string]any, error) {
 	return nil, nil
 }
+func (s *stubInner) CreateMany(_ context.Context, _ string, data []map[string]any) ([]map[string]any, error) {
+	return data, nil
+}
+func (s *stubInner) UpdateMany(_ context.Context, _ string, _ []interfaces.BatchUpdate) ([]map[string]any, error) {
+	return nil, s.updateErr
+}
+func (s *stubInner) DeleteMany(_ context.Context, _ string, _ []string) error {
+	return s.deleteErr
+}
 
 // stubDBWithColumn simulates tableHasWorkspaceColumn returning true by pre-populating
 // the column cache. We use a real (offline) *sql.DB just for the struct — the cache
@@ -231,3 +249,24 @@ func TestDeleteRejectsNullWorkspaceIDViaRead(t *testing.T) {
 
 // Ensure the commonpb import (used by the real injectWorkspaceFilter) compiles.
 var _ = (*commonpb.TypedFilter)(nil)
+
+// ─── CreateMany: workspace injection ─────────────────────────────────────────
+
+func TestCreateManyInjectsWorkspaceIDWithoutMutatingInput(t *testing.T) {
+	w := newStubWorkspaceOps(&stubInner{}, true)
+	ctx := newCtxWithWorkspace("ws-abc")
+
+	input := []map[string]any{{"name": "a"}, {"name": "b", "workspace_id": "ws-other"}}
+	got, err := w.CreateMany(ctx, "test_table", input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, row := range got {
+		if row["workspace_id"] != "ws-abc" {
+			t.Errorf("row %d workspace_id = %v, want ws-abc", i, row["workspace_id"])
+		}
+	}
+	if _, ok := input[0]["workspace_id"]; ok {
+		t.Error("CreateMany mutated the caller's row")
+	}
+}
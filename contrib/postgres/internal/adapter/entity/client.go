@@ -440,8 +440,12 @@ func (r *PostgresClientRepository) GetClientListPageData(
 	}
 
 	// Build filter/search WHERE clauses ($1 reserved for workspace_id, start at $2).
-	// Search spans client name + internal_id + representative user name/email.
-	searchFields := []string{"c.name", "c.internal_id", "u.first_name", "u.last_name", "u.email_address"}
+	// Search spans client name + internal_id + representative user name/email,
+	// full-text so the GIN indexes of migration 000009 replace ILIKE scans.
+	searchFields := []string{
+		interfaces.FullText("c.name"), interfaces.FullText("c.internal_id"),
+		interfaces.FullText("u.first_name"), interfaces.FullText("u.last_name"), interfaces.FullText("u.email_address"),
+	}
 	filterClauses, filterArgs, nextIdx := postgresCore.BuildFilterWhere(req.Filters, req.Search, searchFields, 2)
 
 	whereSQL := "WHERE c.workspace_id = $1"
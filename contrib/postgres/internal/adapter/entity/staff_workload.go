@@ -0,0 +1,147 @@
+//go:build postgresql
+
+package entity
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports/domain"
+	internalregistry "github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+func init() {
+	internalregistry.RegisterStaffWorkloadQueryFactory(func(db any) any {
+		sqlDB, ok := db.(*sql.DB)
+		if !ok || sqlDB == nil {
+			return nil
+		}
+		return NewPostgresStaffWorkloadRepository(sqlDB)
+	})
+}
+
+// PostgresStaffWorkloadRepository implements domain.StaffWorkloadQueryService.
+// Every aggregate is computed in one statement so the operations dashboard
+// costs a single round trip regardless of headcount. The identity bridge is
+// the same one the assignee query uses:
+//
+//	staff.user_id  →  workspace_user.user_id  →  event_attendee.workspace_user_id
+//	staff.user_id  =  activity.assigned_to
+//	workspace_user.id  →  client_workspace_user.workspace_user_id
+//
+// Recommended indexes (beyond those on the individual tables):
+//   - CREATE INDEX idx_event_attendee_workspace_user_id ON event_attendee(workspace_user_id)
+//   - CREATE INDEX idx_activity_assigned_to ON activity(assigned_to) WHERE assigned_to IS NOT NULL
+type PostgresStaffWorkloadRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresStaffWorkloadRepository creates a new staff workload repository.
+func NewPostgresStaffWorkloadRepository(db *sql.DB) *PostgresStaffWorkloadRepository {
+	return &PostgresStaffWorkloadRepository{db: db}
+}
+
+// ListStaffWorkload returns one row per active staff member, including staff
+// with no scheduled time so the dashboard can surface idle capacity.
+//
+// Scheduled minutes clip each event to [$1, $2) so an event straddling the
+// range boundary only counts its in-range portion. Attendance is
+// de-duplicated per (staff, event) because a staff user can hold several
+// workspace_user rows.
+func (r *PostgresStaffWorkloadRepository) ListStaffWorkload(
+	ctx context.Context,
+	req *domain.StaffWorkloadRequest,
+) ([]*domain.StaffWorkloadRow, error) {
+	if req == nil || req.EndMillis <= req.StartMillis {
+		return nil, fmt.Errorf("a valid date range is required")
+	}
+
+	query := `
+		WITH members AS (
+			SELECT s.id AS staff_id, s.user_id, wu.id AS workspace_user_id
+			FROM staff s
+			JOIN workspace_user wu ON wu.user_id = s.user_id AND wu.active = true
+			WHERE s.active = true
+			  AND ($3::text = '' OR wu.workspace_id = $3::text)
+			  AND ($4::text = '' OR s.id = $4::text)
+		),
+		scheduled AS (
+			SELECT staff_id, SUM(minutes)::bigint AS minutes
+			FROM (
+				SELECT DISTINCT ON (m.staff_id, e.id)
+					m.staff_id,
+					GREATEST(
+						LEAST(e.end_date_time_utc, $2) - GREATEST(e.start_date_time_utc, $1),
+						0
+					) / 60000 AS minutes
+				FROM members m
+				JOIN event_attendee ea ON ea.workspace_user_id = m.workspace_user_id AND ea.active = true
+				JOIN event e ON e.id = ea.event_id AND e.active = true
+				WHERE e.start_date_time_utc < $2
+				  AND e.end_date_time_utc > $1
+			) attended
+			GROUP BY staff_id
+		),
+		open_tasks AS (
+			SELECT m.staff_id, COUNT(DISTINCT a.id) AS total
+			FROM (SELECT DISTINCT staff_id, user_id FROM members) m
+			JOIN activity a ON a.assigned_to = m.user_id
+			WHERE a.assigned_to IS NOT NULL
+			  AND a.status NOT IN ('completed', 'skipped', 'cancelled')
+			GROUP BY m.staff_id
+		),
+		clients AS (
+			SELECT m.staff_id, COUNT(DISTINCT cwu.client_id) AS total
+			FROM members m
+			JOIN client_workspace_user cwu ON cwu.workspace_user_id = m.workspace_user_id AND cwu.active = true
+			JOIN client c ON c.id = cwu.client_id AND c.active = true
+			GROUP BY m.staff_id
+		)
+		SELECT
+			s.id,
+			s.user_id,
+			COALESCE(u.first_name, ''),
+			COALESCE(u.last_name, ''),
+			COALESCE(sc.minutes, 0),
+			COALESCE(ot.total, 0),
+			COALESCE(cl.total, 0)
+		FROM staff s
+		LEFT JOIN "user" u ON u.id = s.user_id AND u.active = true
+		LEFT JOIN scheduled sc ON sc.staff_id = s.id
+		LEFT JOIN open_tasks ot ON ot.staff_id = s.id
+		LEFT JOIN clients cl ON cl.staff_id = s.id
+		WHERE s.id IN (SELECT staff_id FROM members)
+		ORDER BY u.last_name, u.first_name, s.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, req.StartMillis, req.EndMillis, req.WorkspaceID, req.StaffID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query staff workload: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*domain.StaffWorkloadRow, 0)
+	for rows.Next() {
+		row := &domain.StaffWorkloadRow{}
+		if err := rows.Scan(
+			&row.StaffID,
+			&row.UserID,
+			&row.FirstName,
+			&row.LastName,
+			&row.ScheduledMinutes,
+			&row.OpenTasks,
+			&row.AssignedClients,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan staff workload row: %w", err)
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate staff workload rows: %w", err)
+	}
+	return result, nil
+}
+
+// Compile-time interface check
+var _ domain.StaffWorkloadQueryService = (*PostgresStaffWorkloadRepository)(nil)
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	infraports "github.com/erniealice/espyna-golang/internal/application/ports/infrastructure"
+	"github.com/erniealice/espyna-golang/ports"
 	"github.com/erniealice/espyna-golang/registry"
 	entityid "github.com/erniealice/espyna-golang/registry/entityid"
 	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
@@ -67,16 +68,22 @@ func (r *PostgresIntegrationPaymentRepository) LogWebhook(ctx context.Context, r
 
 	rawDataJSON, _ := json.Marshal(data.RawData)
 
+	// Provider fee breakdown (see ports.PaymentRawFeeAmount). Providers that
+	// report no fee store fee 0 and net = gross, so report sums stay exact.
+	fees := ports.PaymentFeesFromRawData(data.Amount, data.Currency, data.RawData)
+
 	query := fmt.Sprintf(`INSERT INTO %s (
 		id, payment_id, provider_id, provider_ref, provider_payment_ref,
 		payment_status, amount, currency, payment_method, response_code,
-		order_ref, raw_data, content_type, action, active, date_created, received_at
-	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`, r.tableName)
+		order_ref, raw_data, content_type, action, active, date_created, received_at,
+		gross_amount, fee_amount, net_amount
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`, r.tableName)
 
 	_, err := r.db.ExecContext(ctx, query,
 		id, data.PaymentId, data.ProviderId, data.ProviderRef, data.ProviderPaymentRef,
 		data.PaymentStatus, data.Amount, data.Currency, data.PaymentMethod, data.ResponseCode,
 		data.OrderRef, rawDataJSON, data.ContentType, data.Action, true, now.Unix(), now,
+		fees.Gross, fees.Fee, fees.Net,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to log webhook: %w", err)
@@ -105,6 +112,9 @@ func (r *PostgresIntegrationPaymentRepository) LogWebhook(ctx context.Context, r
 				"order_ref":            data.OrderRef,
 				"content_type":         data.ContentType,
 				"action":               data.Action,
+				"gross_amount":         fees.Gross,
+				"fee_amount":           fees.Fee,
+				"net_amount":           fees.Net,
 			},
 		})
 	}
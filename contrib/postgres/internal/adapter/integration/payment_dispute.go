@@ -0,0 +1,264 @@
+//go:build postgresql
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	internalregistry "github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	"github.com/erniealice/espyna-golang/ports"
+	"github.com/google/uuid"
+)
+
+func init() {
+	internalregistry.RegisterPaymentDisputeRepositoryFactory(func(db any) any {
+		sqlDB, ok := db.(*sql.DB)
+		if !ok || sqlDB == nil {
+			return nil
+		}
+		return NewPostgresPaymentDisputeRepository(sqlDB)
+	})
+}
+
+// PostgresPaymentDisputeRepository implements PaymentDisputeRepository over
+// the payment_dispute table and the subscription / invoice dispute columns
+// added by migration 000005.
+type PostgresPaymentDisputeRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresPaymentDisputeRepository creates a new payment dispute repository.
+func NewPostgresPaymentDisputeRepository(db *sql.DB) *PostgresPaymentDisputeRepository {
+	return &PostgresPaymentDisputeRepository{db: db}
+}
+
+const paymentDisputeColumns = `id, provider_id, provider_dispute_ref, provider_payment_ref,
+	payment_id, subscription_id, invoice_id, status, provider_status, reason, stage,
+	amount, currency, evidence_due_at, opened_at, raw_data, date_modified`
+
+// UpsertDispute inserts the dispute or updates the row with the same
+// provider_id and provider_dispute_ref. Link columns only ever fill in:
+// a later webhook that omits them does not blank earlier values.
+func (r *PostgresPaymentDisputeRepository) UpsertDispute(
+	ctx context.Context,
+	dispute *ports.PaymentDispute,
+) (*ports.PaymentDispute, ports.PaymentDisputeStatus, error) {
+	if dispute == nil || dispute.ProviderID == "" || dispute.ProviderDisputeRef == "" {
+		return nil, "", fmt.Errorf("dispute provider and provider dispute reference are required")
+	}
+
+	rawDataJSON, err := json.Marshal(dispute.RawData)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal dispute raw data: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to begin dispute upsert: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous string
+	err = tx.QueryRowContext(ctx,
+		`SELECT status FROM payment_dispute WHERE provider_id = $1 AND provider_dispute_ref = $2 FOR UPDATE`,
+		dispute.ProviderID, dispute.ProviderDisputeRef,
+	).Scan(&previous)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, "", fmt.Errorf("failed to read existing dispute: %w", err)
+	}
+
+	query := `INSERT INTO payment_dispute (
+		id, provider_id, provider_dispute_ref, provider_payment_ref,
+		payment_id, subscription_id, invoice_id, status, provider_status, reason, stage,
+		amount, currency, evidence_due_at, opened_at, raw_data
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	ON CONFLICT (provider_id, provider_dispute_ref) DO UPDATE SET
+		provider_payment_ref = COALESCE(NULLIF(EXCLUDED.provider_payment_ref, ''), payment_dispute.provider_payment_ref),
+		payment_id           = COALESCE(NULLIF(EXCLUDED.payment_id, ''), payment_dispute.payment_id),
+		subscription_id      = COALESCE(NULLIF(EXCLUDED.subscription_id, ''), payment_dispute.subscription_id),
+		invoice_id           = COALESCE(NULLIF(EXCLUDED.invoice_id, ''), payment_dispute.invoice_id),
+		status               = EXCLUDED.status,
+		provider_status      = EXCLUDED.provider_status,
+		reason               = COALESCE(NULLIF(EXCLUDED.reason, ''), payment_dispute.reason),
+		stage                = COALESCE(NULLIF(EXCLUDED.stage, ''), payment_dispute.stage),
+		amount               = CASE WHEN EXCLUDED.amount <> 0 THEN EXCLUDED.amount ELSE payment_dispute.amount END,
+		currency             = COALESCE(NULLIF(EXCLUDED.currency, ''), payment_dispute.currency),
+		evidence_due_at      = COALESCE(EXCLUDED.evidence_due_at, payment_dispute.evidence_due_at),
+		opened_at            = COALESCE(payment_dispute.opened_at, EXCLUDED.opened_at),
+		raw_data             = EXCLUDED.raw_data,
+		date_modified        = NOW()
+	RETURNING ` + paymentDisputeColumns
+
+	row := tx.QueryRowContext(ctx, query,
+		uuid.New().String(),
+		dispute.ProviderID,
+		dispute.ProviderDisputeRef,
+		dispute.ProviderPaymentRef,
+		dispute.PaymentID,
+		dispute.SubscriptionID,
+		dispute.InvoiceID,
+		string(dispute.Status),
+		dispute.ProviderStatus,
+		dispute.Reason,
+		dispute.Stage,
+		dispute.Amount,
+		dispute.Currency,
+		nullTime(dispute.EvidenceDueAt),
+		nullTime(dispute.OpenedAt),
+		rawDataJSON,
+	)
+	stored, err := scanPaymentDispute(row)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to upsert dispute: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, "", fmt.Errorf("failed to commit dispute upsert: %w", err)
+	}
+	return stored, ports.PaymentDisputeStatus(previous), nil
+}
+
+// GetDispute returns the dispute with the given internal ID.
+func (r *PostgresPaymentDisputeRepository) GetDispute(ctx context.Context, id string) (*ports.PaymentDispute, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+paymentDisputeColumns+` FROM payment_dispute WHERE id = $1 AND active = true`, id)
+	dispute, err := scanPaymentDispute(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("dispute %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute: %w", err)
+	}
+	return dispute, nil
+}
+
+// ListDisputes returns disputes matching filter, most recently updated first.
+func (r *PostgresPaymentDisputeRepository) ListDisputes(
+	ctx context.Context,
+	filter ports.PaymentDisputeFilter,
+) ([]*ports.PaymentDispute, error) {
+	conditions := []string{"active = true"}
+	var args []any
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.ProviderID != "" {
+		add("provider_id = $%d", filter.ProviderID)
+	}
+	if filter.Status != "" {
+		add("status = $%d", string(filter.Status))
+	}
+	if filter.SubscriptionID != "" {
+		add("subscription_id = $%d", filter.SubscriptionID)
+	}
+	if filter.Unresolved {
+		conditions = append(conditions, "status NOT IN ('won', 'lost', 'closed')")
+	}
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 500
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM payment_dispute WHERE %s ORDER BY date_modified DESC LIMIT %d`,
+		paymentDisputeColumns, strings.Join(conditions, " AND "), limit)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query disputes: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*ports.PaymentDispute, 0)
+	for rows.Next() {
+		dispute, err := scanPaymentDispute(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dispute row: %w", err)
+		}
+		result = append(result, dispute)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dispute rows: %w", err)
+	}
+	return result, nil
+}
+
+// FlagDisputedRecords stamps dispute_id / dispute_status on the linked
+// subscription and invoice. The invoice is matched by InvoiceID, or by
+// PaymentID when the checkout used the invoice ID as its payment reference.
+func (r *PostgresPaymentDisputeRepository) FlagDisputedRecords(ctx context.Context, dispute *ports.PaymentDispute) error {
+	if dispute == nil || dispute.ID == "" {
+		return fmt.Errorf("a stored dispute is required")
+	}
+	if dispute.SubscriptionID != "" {
+		if _, err := r.db.ExecContext(ctx,
+			`UPDATE subscription SET dispute_id = $1, dispute_status = $2, date_modified = NOW() WHERE id = $3`,
+			dispute.ID, string(dispute.Status), dispute.SubscriptionID,
+		); err != nil {
+			return fmt.Errorf("failed to flag subscription %s: %w", dispute.SubscriptionID, err)
+		}
+	}
+	if dispute.InvoiceID != "" || dispute.PaymentID != "" {
+		if _, err := r.db.ExecContext(ctx,
+			`UPDATE invoice SET dispute_id = $1, dispute_status = $2, date_modified = NOW()
+			WHERE id IN (NULLIF($3, ''), NULLIF($4, ''))`,
+			dispute.ID, string(dispute.Status), dispute.InvoiceID, dispute.PaymentID,
+		); err != nil {
+			return fmt.Errorf("failed to flag invoice: %w", err)
+		}
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPaymentDispute(row rowScanner) (*ports.PaymentDispute, error) {
+	var (
+		d             ports.PaymentDispute
+		status        string
+		evidenceDueAt sql.NullTime
+		openedAt      sql.NullTime
+		rawData       []byte
+	)
+	if err := row.Scan(
+		&d.ID,
+		&d.ProviderID,
+		&d.ProviderDisputeRef,
+		&d.ProviderPaymentRef,
+		&d.PaymentID,
+		&d.SubscriptionID,
+		&d.InvoiceID,
+		&status,
+		&d.ProviderStatus,
+		&d.Reason,
+		&d.Stage,
+		&d.Amount,
+		&d.Currency,
+		&evidenceDueAt,
+		&openedAt,
+		&rawData,
+		&d.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	d.Status = ports.PaymentDisputeStatus(status)
+	d.EvidenceDueAt = evidenceDueAt.Time
+	d.OpenedAt = openedAt.Time
+	if len(rawData) > 0 {
+		_ = json.Unmarshal(rawData, &d.RawData)
+	}
+	return &d, nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// Compile-time interface check
+var _ ports.PaymentDisputeRepository = (*PostgresPaymentDisputeRepository)(nil)
@@ -0,0 +1,137 @@
+//go:build postgresql
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/erniealice/espyna-golang/contrib/postgres/migrations"
+	"github.com/erniealice/espyna-golang/ports"
+)
+
+// openDisputeDB applies the payment_dispute migration, plus minimal
+// subscription and invoice tables, in a throwaway schema.
+func openDisputeDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	// search_path is per connection.
+	db.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("_test_dispute_%d", time.Now().UnixNano())
+	up, err := migrations.ReadSQL("integration/000005_payment_dispute.up.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE SCHEMA ` + schema,
+		`SET search_path TO ` + schema,
+		`CREATE TABLE subscription (id TEXT PRIMARY KEY, date_modified TIMESTAMPTZ)`,
+		`CREATE TABLE invoice (id TEXT PRIMARY KEY, date_modified TIMESTAMPTZ)`,
+		up,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to set up schema: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		db.Exec(`DROP SCHEMA ` + schema + ` CASCADE`)
+		db.Close()
+	})
+	return db
+}
+
+func TestUpsertDispute_UpdatesInPlaceAndKeepsLinks(t *testing.T) {
+	db := openDisputeDB(t)
+	repo := NewPostgresPaymentDisputeRepository(db)
+	ctx := context.Background()
+
+	first, previous, err := repo.UpsertDispute(ctx, &ports.PaymentDispute{
+		ProviderID:         "paypal",
+		ProviderDisputeRef: "PP-D-1",
+		ProviderPaymentRef: "CAP-1",
+		SubscriptionID:     "sub-1",
+		Status:             ports.PaymentDisputeOpen,
+		Reason:             "MERCHANDISE_OR_SERVICE_NOT_RECEIVED",
+		Amount:             2550,
+		Currency:           "USD",
+		OpenedAt:           time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if previous != "" || first.ID == "" {
+		t.Fatalf("previous = %q, id = %q", previous, first.ID)
+	}
+
+	// An update that omits the links and amount keeps the stored values.
+	second, previous, err := repo.UpsertDispute(ctx, &ports.PaymentDispute{
+		ProviderID:         "paypal",
+		ProviderDisputeRef: "PP-D-1",
+		Status:             ports.PaymentDisputeLost,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if previous != ports.PaymentDisputeOpen || second.ID != first.ID {
+		t.Fatalf("previous = %q, id = %q (first %q)", previous, second.ID, first.ID)
+	}
+	if second.SubscriptionID != "sub-1" || second.ProviderPaymentRef != "CAP-1" || second.Amount != 2550 || second.Reason == "" {
+		t.Errorf("links were blanked: %+v", second)
+	}
+
+	unresolved, err := repo.ListDisputes(ctx, ports.PaymentDisputeFilter{Unresolved: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unresolved) != 0 {
+		t.Errorf("lost dispute listed as unresolved: %+v", unresolved)
+	}
+}
+
+func TestFlagDisputedRecords(t *testing.T) {
+	db := openDisputeDB(t)
+	repo := NewPostgresPaymentDisputeRepository(db)
+	ctx := context.Background()
+
+	if _, err := db.Exec(`INSERT INTO subscription (id) VALUES ('sub-1'); INSERT INTO invoice (id) VALUES ('inv-1')`); err != nil {
+		t.Fatal(err)
+	}
+	stored, _, err := repo.UpsertDispute(ctx, &ports.PaymentDispute{
+		ProviderID:         "paypal",
+		ProviderDisputeRef: "PP-D-1",
+		SubscriptionID:     "sub-1",
+		PaymentID:          "inv-1",
+		Status:             ports.PaymentDisputeEvidenceRequired,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.FlagDisputedRecords(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, table := range []string{"subscription", "invoice"} {
+		var id, status sql.NullString
+		if err := db.QueryRow(`SELECT dispute_id, dispute_status FROM `+table).Scan(&id, &status); err != nil {
+			t.Fatal(err)
+		}
+		if id.String != stored.ID || status.String != string(ports.PaymentDisputeEvidenceRequired) {
+			t.Errorf("%s flagged with %q / %q", table, id.String, status.String)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+//go:build postgresql
+
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports/domain"
+	internalregistry "github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+func init() {
+	internalregistry.RegisterPaymentFeeQueryFactory(func(db any) any {
+		sqlDB, ok := db.(*sql.DB)
+		if !ok || sqlDB == nil {
+			return nil
+		}
+		return NewPostgresPaymentFeeRepository(sqlDB)
+	})
+}
+
+// PostgresPaymentFeeRepository implements domain.PaymentFeeQueryService over
+// the integration_payment webhook log. It relies on the gross_amount /
+// fee_amount / net_amount columns added by migration 000004.
+type PostgresPaymentFeeRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresPaymentFeeRepository creates a new payment fee repository.
+func NewPostgresPaymentFeeRepository(db *sql.DB) *PostgresPaymentFeeRepository {
+	return &PostgresPaymentFeeRepository{db: db}
+}
+
+// ListPaymentFees returns one row per provider and currency.
+//
+// Success webhooks are de-duplicated per payment (payment_id, falling back
+// to provider_payment_ref) because PayPal notifies both the order and the
+// capture. The row carrying a reported fee wins so the breakdown from the
+// capture event is the one counted.
+func (r *PostgresPaymentFeeRepository) ListPaymentFees(
+	ctx context.Context,
+	req *domain.PaymentFeeReportRequest,
+) ([]*domain.PaymentFeeRow, error) {
+	if req == nil || !req.End.After(req.Start) {
+		return nil, fmt.Errorf("a valid date range is required")
+	}
+
+	query := `
+		WITH captured AS (
+			SELECT DISTINCT ON (provider_id, COALESCE(NULLIF(payment_id, ''), provider_payment_ref))
+				provider_id, currency, gross_amount, fee_amount, net_amount
+			FROM integration_payment
+			WHERE active = true
+			  AND action = 'success'
+			  AND received_at >= $1 AND received_at < $2
+			  AND ($3::text = '' OR provider_id = $3::text)
+			ORDER BY provider_id, COALESCE(NULLIF(payment_id, ''), provider_payment_ref),
+				fee_amount DESC, received_at DESC
+		),
+		refunded AS (
+			SELECT provider_id, currency, SUM(amount)::bigint AS total
+			FROM integration_payment
+			WHERE active = true
+			  AND action = 'refunded'
+			  AND received_at >= $1 AND received_at < $2
+			  AND ($3::text = '' OR provider_id = $3::text)
+			GROUP BY provider_id, currency
+		),
+		totals AS (
+			SELECT provider_id, currency,
+				COUNT(*) AS payments,
+				SUM(COALESCE(gross_amount, 0))::bigint AS gross,
+				SUM(fee_amount)::bigint AS fee,
+				SUM(COALESCE(net_amount, 0))::bigint AS net
+			FROM captured
+			GROUP BY provider_id, currency
+		)
+		SELECT
+			COALESCE(t.provider_id, rf.provider_id),
+			COALESCE(t.currency, rf.currency),
+			COALESCE(t.payments, 0),
+			COALESCE(t.gross, 0),
+			COALESCE(t.fee, 0),
+			COALESCE(t.net, 0),
+			COALESCE(rf.total, 0)
+		FROM totals t
+		FULL OUTER JOIN refunded rf ON rf.provider_id = t.provider_id AND rf.currency = t.currency
+		ORDER BY 1, 2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, req.Start, req.End, req.ProviderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment fees: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*domain.PaymentFeeRow, 0)
+	for rows.Next() {
+		row := &domain.PaymentFeeRow{}
+		if err := rows.Scan(
+			&row.ProviderID,
+			&row.Currency,
+			&row.Payments,
+			&row.GrossAmount,
+			&row.FeeAmount,
+			&row.NetAmount,
+			&row.RefundedAmount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan payment fee row: %w", err)
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate payment fee rows: %w", err)
+	}
+	return result, nil
+}
+
+// Compile-time interface check
+var _ domain.PaymentFeeQueryService = (*PostgresPaymentFeeRepository)(nil)
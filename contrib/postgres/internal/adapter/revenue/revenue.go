@@ -356,8 +356,9 @@ func (r *PostgresRevenueRepository) GetRevenueListPageData(
 		return nil, err
 	}
 
-	// Build parameterized WHERE clauses via shared helper ($1 is reserved for workspace_id, start at $2)
-	searchFields := []string{"rv.reference_number", "c.name"}
+	// Build parameterized WHERE clauses via shared helper ($1 is reserved for workspace_id, start at $2).
+	// Search is full-text, backed by the GIN indexes of migration 000009.
+	searchFields := []string{interfaces.FullText("rv.reference_number"), interfaces.FullText("c.name")}
 	filterClauses, filterArgs, nextIdx := postgresCore.BuildFilterWhere(req.Filters, req.Search, searchFields, 2)
 
 	var whereStr string
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"strings"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
 // PostgresMigrationService implements the MigrationService interface for PostgreSQL
@@ -63,6 +65,36 @@ func NewPostgresMigrationService(db *sql.DB, migrationsPath string) (ports.Migra
 	}, nil
 }
 
+// NewEmbeddedPostgresMigrationService creates a migration service backed by
+// an in-binary fs.FS (see contrib/postgres/migrations.FS) instead of a
+// directory on disk, so a deployed binary carries its own schema.
+func NewEmbeddedPostgresMigrationService(db *sql.DB, fsys fs.FS) (*PostgresMigrationService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is required")
+	}
+
+	src, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+
+	return &PostgresMigrationService{
+		migrate:        m,
+		db:             db,
+		migrationsPath: "embedded",
+	}, nil
+}
+
 // Up applies all pending migrations to bring the database to the latest version
 func (p *PostgresMigrationService) Up(ctx context.Context) error {
 	err := p.migrate.Up()
@@ -91,6 +123,20 @@ func (p *PostgresMigrationService) Down(ctx context.Context) error {
 	return nil
 }
 
+// Steps applies n migrations forward (n > 0) or rolls back -n (n < 0).
+func (p *PostgresMigrationService) Steps(ctx context.Context, n int) error {
+	err := p.migrate.Steps(n)
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return ports.NewMigrationError(
+			ports.MigrationErrCodeMigrationFailed,
+			fmt.Sprintf("failed to apply %d migration steps", n),
+			0,
+			err,
+		)
+	}
+	return nil
+}
+
 // Version returns the current migration version and whether the database is dirty
 func (p *PostgresMigrationService) Version(ctx context.Context) (uint, bool, error) {
 	version, dirty, err := p.migrate.Version()
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS client_workspace_user;
+DROP TABLE IF EXISTS client;
+DROP TABLE IF EXISTS staff;
+DROP TABLE IF EXISTS workspace_user;
+DROP TABLE IF EXISTS workspace;
+DROP TABLE IF EXISTS "user";
@@ -0,0 +1,82 @@
+-- Entity domain baseline: identity, tenancy and the client/staff records
+-- the rest of the schema hangs off. IF NOT EXISTS keeps this safe to apply
+-- to databases created before schema versioning existed.
+
+CREATE TABLE IF NOT EXISTS "user" (
+    id            TEXT PRIMARY KEY,
+    first_name    TEXT NOT NULL DEFAULT '',
+    last_name     TEXT NOT NULL DEFAULT '',
+    email_address TEXT NOT NULL DEFAULT '',
+    mobile_number TEXT NOT NULL DEFAULT '',
+    timezone      TEXT NOT NULL DEFAULT '',
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_user_email_address ON "user"(email_address);
+
+CREATE TABLE IF NOT EXISTS workspace (
+    id            TEXT PRIMARY KEY,
+    name          TEXT NOT NULL DEFAULT '',
+    description   TEXT NOT NULL DEFAULT '',
+    private       BOOLEAN NOT NULL DEFAULT false,
+    status        TEXT NOT NULL DEFAULT '',
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS workspace_user (
+    id            TEXT PRIMARY KEY,
+    workspace_id  TEXT NOT NULL REFERENCES workspace(id),
+    user_id       TEXT NOT NULL REFERENCES "user"(id),
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_workspace_user_workspace_id ON workspace_user(workspace_id);
+CREATE INDEX IF NOT EXISTS idx_workspace_user_user_id ON workspace_user(user_id);
+
+CREATE TABLE IF NOT EXISTS staff (
+    id            TEXT PRIMARY KEY,
+    user_id       TEXT NOT NULL REFERENCES "user"(id),
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_staff_user_id ON staff(user_id);
+
+CREATE TABLE IF NOT EXISTS client (
+    id               TEXT PRIMARY KEY,
+    user_id          TEXT REFERENCES "user"(id),
+    internal_id      TEXT NOT NULL DEFAULT '',
+    name             TEXT NOT NULL DEFAULT '',
+    street_address   TEXT NOT NULL DEFAULT '',
+    city             TEXT NOT NULL DEFAULT '',
+    province         TEXT NOT NULL DEFAULT '',
+    postal_code      TEXT NOT NULL DEFAULT '',
+    country          TEXT NOT NULL DEFAULT '',
+    website          TEXT NOT NULL DEFAULT '',
+    notes            TEXT NOT NULL DEFAULT '',
+    payment_term_id  TEXT,
+    billing_currency TEXT NOT NULL DEFAULT '',
+    status           TEXT NOT NULL DEFAULT '',
+    workspace_id     TEXT REFERENCES workspace(id),
+    active           BOOLEAN NOT NULL DEFAULT true,
+    date_created     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_client_active ON client(active) WHERE active = true;
+CREATE INDEX IF NOT EXISTS idx_client_user_id ON client(user_id);
+
+CREATE TABLE IF NOT EXISTS client_workspace_user (
+    id                TEXT PRIMARY KEY,
+    client_id         TEXT NOT NULL REFERENCES client(id),
+    workspace_user_id TEXT NOT NULL REFERENCES workspace_user(id),
+    is_owner          BOOLEAN NOT NULL DEFAULT false,
+    active            BOOLEAN NOT NULL DEFAULT true,
+    date_created      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_client_workspace_user_client_id ON client_workspace_user(client_id);
+CREATE INDEX IF NOT EXISTS idx_client_workspace_user_workspace_user_id ON client_workspace_user(workspace_user_id);
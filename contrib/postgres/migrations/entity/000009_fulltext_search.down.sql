@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_revenue_reference_number_fts;
+DROP INDEX IF EXISTS idx_user_email_address_fts;
+DROP INDEX IF EXISTS idx_user_last_name_fts;
+DROP INDEX IF EXISTS idx_user_first_name_fts;
+DROP INDEX IF EXISTS idx_client_internal_id_fts;
+DROP INDEX IF EXISTS idx_client_name_fts;
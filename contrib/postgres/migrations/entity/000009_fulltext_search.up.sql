@@ -0,0 +1,24 @@
+-- GIN indexes behind the full-text client and invoice (revenue) list
+-- search. Each expression must match the adapter's textVector exactly:
+-- to_tsvector('simple', coalesce(<column>, '')). Searches work without
+-- them, only slower. revenue is created outside these migrations, so its
+-- index is only built where the table exists.
+
+CREATE INDEX IF NOT EXISTS idx_client_name_fts
+    ON client USING GIN (to_tsvector('simple', coalesce(name, '')));
+CREATE INDEX IF NOT EXISTS idx_client_internal_id_fts
+    ON client USING GIN (to_tsvector('simple', coalesce(internal_id, '')));
+CREATE INDEX IF NOT EXISTS idx_user_first_name_fts
+    ON "user" USING GIN (to_tsvector('simple', coalesce(first_name, '')));
+CREATE INDEX IF NOT EXISTS idx_user_last_name_fts
+    ON "user" USING GIN (to_tsvector('simple', coalesce(last_name, '')));
+CREATE INDEX IF NOT EXISTS idx_user_email_address_fts
+    ON "user" USING GIN (to_tsvector('simple', coalesce(email_address, '')));
+
+DO $$
+BEGIN
+    IF to_regclass('revenue') IS NOT NULL THEN
+        CREATE INDEX IF NOT EXISTS idx_revenue_reference_number_fts
+            ON revenue USING GIN (to_tsvector('simple', coalesce(reference_number, '')));
+    END IF;
+END $$;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_integration_payment_received_at;
+
+ALTER TABLE IF EXISTS integration_payment
+    DROP COLUMN IF EXISTS net_amount,
+    DROP COLUMN IF EXISTS fee_amount,
+    DROP COLUMN IF EXISTS gross_amount;
@@ -0,0 +1,14 @@
+-- Provider fee tracking on payment webhook transactions. Rows logged before
+-- this migration have no breakdown, so they backfill as fee 0 / net = amount.
+
+ALTER TABLE IF EXISTS integration_payment
+    ADD COLUMN IF NOT EXISTS gross_amount BIGINT,
+    ADD COLUMN IF NOT EXISTS fee_amount   BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS net_amount   BIGINT;
+
+UPDATE integration_payment
+SET gross_amount = COALESCE(gross_amount, amount),
+    net_amount   = COALESCE(net_amount, amount - fee_amount)
+WHERE gross_amount IS NULL OR net_amount IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_integration_payment_received_at ON integration_payment(received_at);
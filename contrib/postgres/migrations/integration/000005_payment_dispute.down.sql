@@ -0,0 +1,9 @@
+ALTER TABLE IF EXISTS invoice
+    DROP COLUMN IF EXISTS dispute_status,
+    DROP COLUMN IF EXISTS dispute_id;
+
+ALTER TABLE IF EXISTS subscription
+    DROP COLUMN IF EXISTS dispute_status,
+    DROP COLUMN IF EXISTS dispute_id;
+
+DROP TABLE IF EXISTS payment_dispute;
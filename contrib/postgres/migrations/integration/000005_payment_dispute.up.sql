@@ -0,0 +1,36 @@
+-- Payment disputes / chargebacks reported by provider webhooks, plus the
+-- flag columns that surface an open dispute on the subscription and
+-- invoice it was raised against.
+
+CREATE TABLE IF NOT EXISTS payment_dispute (
+    id                   TEXT PRIMARY KEY,
+    provider_id          TEXT NOT NULL,
+    provider_dispute_ref TEXT NOT NULL,
+    provider_payment_ref TEXT NOT NULL DEFAULT '',
+    payment_id           TEXT NOT NULL DEFAULT '',
+    subscription_id      TEXT NOT NULL DEFAULT '',
+    invoice_id           TEXT NOT NULL DEFAULT '',
+    status               TEXT NOT NULL,
+    provider_status      TEXT NOT NULL DEFAULT '',
+    reason               TEXT NOT NULL DEFAULT '',
+    stage                TEXT NOT NULL DEFAULT '',
+    amount               BIGINT NOT NULL DEFAULT 0,
+    currency             TEXT NOT NULL DEFAULT '',
+    evidence_due_at      TIMESTAMPTZ,
+    opened_at            TIMESTAMPTZ,
+    raw_data             JSONB NOT NULL DEFAULT '{}'::jsonb,
+    active               BOOLEAN NOT NULL DEFAULT true,
+    date_created         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider_id, provider_dispute_ref)
+);
+CREATE INDEX IF NOT EXISTS idx_payment_dispute_status ON payment_dispute(status);
+CREATE INDEX IF NOT EXISTS idx_payment_dispute_subscription_id ON payment_dispute(subscription_id);
+
+ALTER TABLE IF EXISTS subscription
+    ADD COLUMN IF NOT EXISTS dispute_id     TEXT,
+    ADD COLUMN IF NOT EXISTS dispute_status TEXT;
+
+ALTER TABLE IF EXISTS invoice
+    ADD COLUMN IF NOT EXISTS dispute_id     TEXT,
+    ADD COLUMN IF NOT EXISTS dispute_status TEXT;
@@ -0,0 +1,14 @@
+-- Mobile device tokens registered for push notifications. The id is
+-- derived from the token, so re-registering a device updates its row.
+
+CREATE TABLE IF NOT EXISTS device_token (
+    id            TEXT PRIMARY KEY,
+    user_id       TEXT NOT NULL,
+    workspace_id  TEXT NOT NULL DEFAULT '',
+    platform      TEXT NOT NULL,
+    token         TEXT NOT NULL UNIQUE,
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_device_token_user_id ON device_token(user_id);
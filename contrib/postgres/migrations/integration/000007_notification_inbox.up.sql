@@ -0,0 +1,18 @@
+-- In-app notifications shown in the web app's inbox. read_at is set when
+-- the user marks the notification read.
+
+CREATE TABLE IF NOT EXISTS notification_inbox (
+    id            TEXT PRIMARY KEY,
+    user_id       TEXT NOT NULL,
+    workspace_id  TEXT NOT NULL DEFAULT '',
+    template      TEXT NOT NULL DEFAULT '',
+    subject       TEXT NOT NULL DEFAULT '',
+    body          TEXT NOT NULL DEFAULT '',
+    data          TEXT NOT NULL DEFAULT '{}',
+    is_read       BOOLEAN NOT NULL DEFAULT false,
+    read_at       TIMESTAMPTZ,
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_notification_inbox_user_read ON notification_inbox(user_id, is_read, date_created DESC);
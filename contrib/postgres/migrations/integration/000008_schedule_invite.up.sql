@@ -0,0 +1,19 @@
+-- Last calendar invite (.ics) emailed for each schedule, keyed by the
+-- provider's schedule ID. Updates and cancellations reuse uid with the next
+-- sequence so invitees' calendars replace the earlier invite.
+
+CREATE TABLE IF NOT EXISTS schedule_invite (
+    id             TEXT PRIMARY KEY,
+    uid            TEXT NOT NULL,
+    sequence       INTEGER NOT NULL DEFAULT 0,
+    summary        TEXT NOT NULL DEFAULT '',
+    location       TEXT NOT NULL DEFAULT '',
+    start_at       TEXT NOT NULL DEFAULT '',
+    end_at         TEXT NOT NULL DEFAULT '',
+    attendee_name  TEXT NOT NULL DEFAULT '',
+    attendee_email TEXT NOT NULL DEFAULT '',
+    status         TEXT NOT NULL DEFAULT 'confirmed',
+    active         BOOLEAN NOT NULL DEFAULT true,
+    date_created   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,22 @@
+-- Per-workspace log of served API requests, shown to workspace admins by
+-- GET /api/logs/tail. logged_at (epoch milliseconds) is stamped when a batch
+-- is written and is the tail's cursor; occurred_at is when the request
+-- completed. Rows older than the retention window are deleted by the writer.
+
+CREATE TABLE IF NOT EXISTS api_event_log (
+    id            TEXT PRIMARY KEY,
+    workspace_id  TEXT NOT NULL,
+    actor_id      TEXT NOT NULL DEFAULT '',
+    method        TEXT NOT NULL DEFAULT '',
+    path          TEXT NOT NULL DEFAULT '',
+    status        INTEGER NOT NULL DEFAULT 0,
+    latency_ms    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    request_id    TEXT NOT NULL DEFAULT '',
+    occurred_at   BIGINT NOT NULL DEFAULT 0,
+    logged_at     BIGINT NOT NULL DEFAULT 0,
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_api_event_log_workspace_logged ON api_event_log(workspace_id, logged_at DESC);
+CREATE INDEX IF NOT EXISTS idx_api_event_log_logged ON api_event_log(logged_at);
@@ -0,0 +1,26 @@
+-- Inventory of the provider credentials each environment and workspace has
+-- configured, reported by GET /api/credentials. fingerprint is a truncated
+-- SHA-256 of the secret, never the secret itself; backup is its AES-256-GCM
+-- sealed copy when a backup key is configured, and backup_key_id names that
+-- key. Times are epoch milliseconds; expires_at is 0 when unknown.
+
+CREATE TABLE IF NOT EXISTS credential_inventory (
+    id                 TEXT PRIMARY KEY,
+    environment        TEXT NOT NULL,
+    workspace_id       TEXT NOT NULL DEFAULT '',
+    provider           TEXT NOT NULL DEFAULT '',
+    name               TEXT NOT NULL,
+    kind               TEXT NOT NULL DEFAULT '',
+    fingerprint        TEXT NOT NULL DEFAULT '',
+    key_id             TEXT NOT NULL DEFAULT '',
+    rotated_at         BIGINT NOT NULL DEFAULT 0,
+    last_seen_at       BIGINT NOT NULL DEFAULT 0,
+    expires_at         BIGINT NOT NULL DEFAULT 0,
+    rotate_after_hours BIGINT NOT NULL DEFAULT 0,
+    backup             TEXT NOT NULL DEFAULT '',
+    backup_key_id      TEXT NOT NULL DEFAULT '',
+    active             BOOLEAN NOT NULL DEFAULT true,
+    date_created       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_credential_inventory_name ON credential_inventory(environment, workspace_id, name);
@@ -0,0 +1,41 @@
+-- Outbound webhooks. webhook_endpoint holds the URLs each workspace
+-- registered at /api/webhooks, with the secret that signs their deliveries
+-- and the comma-separated event types they receive ("*" for all).
+-- webhook_delivery logs every event sent to an endpoint; rows in status
+-- 'retrying' are picked up again once next_attempt_at has passed. Times are
+-- epoch milliseconds.
+
+CREATE TABLE IF NOT EXISTS webhook_endpoint (
+    id            TEXT PRIMARY KEY,
+    workspace_id  TEXT NOT NULL,
+    url           TEXT NOT NULL,
+    secret        TEXT NOT NULL,
+    events        TEXT NOT NULL DEFAULT '*',
+    active        BOOLEAN NOT NULL DEFAULT true,
+    created_at    BIGINT NOT NULL DEFAULT 0,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoint_workspace ON webhook_endpoint(workspace_id);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+    id              TEXT PRIMARY KEY,
+    event_id        TEXT NOT NULL,
+    event_type      TEXT NOT NULL,
+    workspace_id    TEXT NOT NULL,
+    endpoint_id     TEXT NOT NULL,
+    url             TEXT NOT NULL,
+    payload         TEXT NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    response_code   INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT NOT NULL DEFAULT '',
+    next_attempt_at BIGINT NOT NULL DEFAULT 0,
+    delivered_at    BIGINT NOT NULL DEFAULT 0,
+    created_at      BIGINT NOT NULL DEFAULT 0,
+    active          BOOLEAN NOT NULL DEFAULT true,
+    date_created    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_workspace ON webhook_delivery(workspace_id, endpoint_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_retry ON webhook_delivery(status, next_attempt_at);
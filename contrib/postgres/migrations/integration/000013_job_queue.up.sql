@@ -0,0 +1,21 @@
+-- Background job queue. Workers claim due rows (pending with run_at passed,
+-- or running with locked_until passed) with FOR UPDATE SKIP LOCKED; failed
+-- jobs go back to pending at their backoff time until max_attempts, then
+-- stay 'dead' until retried through /api/job-queue/retry.
+
+CREATE TABLE IF NOT EXISTS job_queue (
+    id            TEXT PRIMARY KEY,
+    kind          TEXT NOT NULL,
+    workspace_id  TEXT NOT NULL DEFAULT '',
+    payload       BYTEA,
+    status        TEXT NOT NULL DEFAULT 'pending',
+    attempts      INTEGER NOT NULL DEFAULT 0,
+    max_attempts  INTEGER NOT NULL DEFAULT 10,
+    run_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until  TIMESTAMPTZ,
+    last_error    TEXT NOT NULL DEFAULT '',
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_job_queue_due ON job_queue(kind, run_at) WHERE status IN ('pending', 'running');
+CREATE INDEX IF NOT EXISTS idx_job_queue_workspace ON job_queue(workspace_id, status, date_created DESC);
@@ -0,0 +1,18 @@
+-- Idempotency keys for POST requests. A client retrying with the same
+-- Idempotency-Key header (per workspace and route) gets the stored response
+-- back. status_code 0 marks a request still running, holding the key until
+-- expires_at; completed rows are replayed until expires_at, then replaced.
+
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    workspace_id    TEXT NOT NULL DEFAULT '',
+    route           TEXT NOT NULL,
+    idempotency_key TEXT NOT NULL,
+    fingerprint     TEXT NOT NULL,
+    status_code     INTEGER NOT NULL DEFAULT 0,
+    content_type    TEXT NOT NULL DEFAULT '',
+    body            BYTEA,
+    expires_at      TIMESTAMPTZ NOT NULL,
+    date_created    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (workspace_id, route, idempotency_key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_key_expires ON idempotency_key(expires_at);
@@ -0,0 +1,22 @@
+-- Revision history. With CONFIG_HISTORY_TABLES set, every create, update,
+-- soft delete and restore of a listed table's record adds a row here: the
+-- whole record after the write (snapshot, JSON) and the fields the write
+-- changed with their old and new values (changes, JSON). Revisions are
+-- numbered from 1 per record; the id is "<entity_type>:<entity_id>:<revision>"
+-- so two writers taking the same number collide.
+
+CREATE TABLE IF NOT EXISTS entity_revision (
+    id            TEXT PRIMARY KEY,
+    workspace_id  TEXT NOT NULL DEFAULT '',
+    entity_type   TEXT NOT NULL,
+    entity_id     TEXT NOT NULL,
+    revision      BIGINT NOT NULL,
+    action        TEXT NOT NULL,
+    snapshot      TEXT NOT NULL,
+    changes       TEXT NOT NULL DEFAULT '{}',
+    changed_by    TEXT NOT NULL DEFAULT '',
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_entity_revision_entity ON entity_revision(entity_type, entity_id, revision);
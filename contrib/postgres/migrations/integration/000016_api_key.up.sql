@@ -0,0 +1,23 @@
+-- API keys for machine-to-machine access (the apikey auth provider). A key
+-- "esk_<id>_<secret>" is looked up by id; secret_hash is the hex
+-- HMAC-SHA256 of the secret under CONFIG_APIKEY_PEPPER, never the secret.
+-- permissions are the comma-separated entity:action codes the key holds in
+-- its workspace. Keys are revoked (revoked_at) rather than deleted. Times
+-- are epoch milliseconds; 0 for never.
+
+CREATE TABLE IF NOT EXISTS api_key (
+    id            TEXT PRIMARY KEY,
+    workspace_id  TEXT NOT NULL,
+    name          TEXT NOT NULL DEFAULT '',
+    secret_hash   TEXT NOT NULL,
+    permissions   TEXT NOT NULL,
+    created_by    TEXT NOT NULL DEFAULT '',
+    created_at    BIGINT NOT NULL DEFAULT 0,
+    expires_at    BIGINT NOT NULL DEFAULT 0,
+    last_used_at  BIGINT NOT NULL DEFAULT 0,
+    revoked_at    BIGINT NOT NULL DEFAULT 0,
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_api_key_workspace ON api_key(workspace_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS auth_revoked_token;
+DROP TABLE IF EXISTS auth_refresh_token;
@@ -0,0 +1,39 @@
+-- Tokens of the jwt auth provider.
+--
+-- auth_refresh_token holds refresh tokens "rt_<id>_<secret>", looked up by
+-- id; secret_hash is the hex SHA-256 of the secret, never the secret. A
+-- token is single-use: used_at is set when it is rotated, and the tokens
+-- of one sign-in share family_id (the first token's id), so replaying a
+-- spent token revokes its whole family.
+--
+-- auth_revoked_token is the access token revocation list: "jti:<jti>" rows
+-- revoke one token, "user:<user id>" rows every token of the user issued
+-- before not_before (epoch seconds). Rows are needed only until
+-- expires_at. Other times are epoch milliseconds; 0 for never.
+
+CREATE TABLE IF NOT EXISTS auth_refresh_token (
+    id            TEXT PRIMARY KEY,
+    user_id       TEXT NOT NULL,
+    family_id     TEXT NOT NULL DEFAULT '',
+    secret_hash   TEXT NOT NULL,
+    created_at    BIGINT NOT NULL DEFAULT 0,
+    expires_at    BIGINT NOT NULL DEFAULT 0,
+    used_at       BIGINT NOT NULL DEFAULT 0,
+    revoked_at    BIGINT NOT NULL DEFAULT 0,
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_auth_refresh_token_user ON auth_refresh_token(user_id);
+CREATE INDEX IF NOT EXISTS idx_auth_refresh_token_family ON auth_refresh_token(family_id);
+
+CREATE TABLE IF NOT EXISTS auth_revoked_token (
+    id            TEXT PRIMARY KEY,
+    user_id       TEXT NOT NULL DEFAULT '',
+    not_before    BIGINT NOT NULL DEFAULT 0,
+    expires_at    BIGINT NOT NULL DEFAULT 0,
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_auth_revoked_token_expires ON auth_revoked_token(expires_at);
@@ -0,0 +1,17 @@
+-- External identities (OAuth2 / OpenID Connect sign-in) linked to users.
+-- id is "<provider>:<subject>", the provider's stable ID of the account;
+-- email_address is the verified email the identity had when it was linked.
+-- created_at is epoch milliseconds.
+
+CREATE TABLE IF NOT EXISTS user_identity (
+    id            TEXT PRIMARY KEY,
+    user_id       TEXT NOT NULL REFERENCES "user"(id),
+    provider      TEXT NOT NULL,
+    subject       TEXT NOT NULL,
+    email_address TEXT NOT NULL DEFAULT '',
+    created_at    BIGINT NOT NULL DEFAULT 0,
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_user_identity_user ON user_identity(user_id);
@@ -0,0 +1,22 @@
+-- SMS and WhatsApp messages sent through a messaging provider, with the
+-- delivery status its callbacks report. The id is derived from the provider
+-- and its message id, so a status callback updates the message's row.
+
+CREATE TABLE IF NOT EXISTS notification_log (
+    id                  TEXT PRIMARY KEY,
+    provider            TEXT NOT NULL,
+    provider_message_id TEXT NOT NULL,
+    channel             TEXT NOT NULL DEFAULT '',
+    workspace_id        TEXT NOT NULL DEFAULT '',
+    user_id             TEXT NOT NULL DEFAULT '',
+    template            TEXT NOT NULL DEFAULT '',
+    recipient           TEXT NOT NULL DEFAULT '',
+    status              TEXT NOT NULL,
+    error_code          TEXT NOT NULL DEFAULT '',
+    error_message       TEXT NOT NULL DEFAULT '',
+    active              BOOLEAN NOT NULL DEFAULT true,
+    date_created        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_message_id)
+);
+CREATE INDEX IF NOT EXISTS idx_notification_log_user_id ON notification_log(user_id);
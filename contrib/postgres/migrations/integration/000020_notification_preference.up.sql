@@ -0,0 +1,15 @@
+-- The channel each user wants notifications on, per template. An empty
+-- template is the user's default; channel 'none' turns notifications off.
+-- The id is derived from the user and template, so setting a preference
+-- again updates its row.
+
+CREATE TABLE IF NOT EXISTS notification_preference (
+    id            TEXT PRIMARY KEY,
+    user_id       TEXT NOT NULL,
+    template      TEXT NOT NULL DEFAULT '',
+    channel       TEXT NOT NULL,
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, template)
+);
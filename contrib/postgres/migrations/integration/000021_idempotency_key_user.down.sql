@@ -0,0 +1,5 @@
+-- Records live for hours at most; drop them rather than merge users' keys.
+DELETE FROM idempotency_key;
+ALTER TABLE idempotency_key DROP CONSTRAINT IF EXISTS idempotency_key_pkey;
+ALTER TABLE idempotency_key DROP COLUMN IF EXISTS user_id;
+ALTER TABLE idempotency_key ADD PRIMARY KEY (workspace_id, route, idempotency_key);
@@ -0,0 +1,6 @@
+-- Scope idempotency keys to the calling user as well as the workspace, so a
+-- key one user sent never replays the response stored for another.
+
+ALTER TABLE idempotency_key ADD COLUMN IF NOT EXISTS user_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE idempotency_key DROP CONSTRAINT IF EXISTS idempotency_key_pkey;
+ALTER TABLE idempotency_key ADD PRIMARY KEY (workspace_id, user_id, route, idempotency_key);
@@ -0,0 +1,194 @@
+//go:build postgresql
+
+// Package migrations embeds the versioned PostgreSQL schema, grouped by
+// domain directory (entity/, subscription/, workflow/, integration/).
+//
+// Files follow golang-migrate naming — {version}_{title}.up.sql and
+// {version}_{title}.down.sql — and share ONE global version sequence across
+// domains, tracked in the schema_migrations table. Domains only group files
+// for review; a new migration takes the next free version regardless of the
+// directory it lands in. [List] rejects duplicate versions and unpaired
+// files so a bad merge fails before anything touches the database.
+//
+// The migration service (contrib/postgres/internal/migration) runs these
+// through [FS]; cmd/migrate is the operator entry point.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed entity/*.sql subscription/*.sql workflow/*.sql integration/*.sql
+var embedded embed.FS
+
+// Domains lists the embedded domain directories in apply-review order.
+var Domains = []string{"entity", "subscription", "workflow", "integration"}
+
+// File is one versioned migration with both directions.
+type File struct {
+	Version uint
+	Title   string
+	Domain  string
+	// UpPath / DownPath are paths inside the embedded FS.
+	UpPath   string
+	DownPath string
+}
+
+// List returns every embedded migration sorted by version.
+func List() ([]File, error) {
+	byVersion := make(map[uint]*File)
+	for _, domain := range Domains {
+		entries, err := fs.ReadDir(embedded, domain)
+		if err != nil {
+			return nil, fmt.Errorf("migrations: read %s: %w", domain, err)
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			version, title, direction, err := parseName(e.Name())
+			if err != nil {
+				return nil, err
+			}
+			f, ok := byVersion[version]
+			if !ok {
+				f = &File{Version: version, Title: title, Domain: domain}
+				byVersion[version] = f
+			} else if f.Domain != domain || f.Title != title {
+				return nil, fmt.Errorf("migrations: version %d used by both %s/%s and %s/%s",
+					version, f.Domain, f.Title, domain, title)
+			}
+			full := path.Join(domain, e.Name())
+			if direction == "up" {
+				f.UpPath = full
+			} else {
+				f.DownPath = full
+			}
+		}
+	}
+
+	files := make([]File, 0, len(byVersion))
+	for _, f := range byVersion {
+		if f.UpPath == "" || f.DownPath == "" {
+			return nil, fmt.Errorf("migrations: %s/%d_%s is missing its up or down file", f.Domain, f.Version, f.Title)
+		}
+		files = append(files, *f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Version < files[j].Version })
+	return files, nil
+}
+
+// ReadSQL returns the contents of an embedded migration file.
+func ReadSQL(p string) (string, error) {
+	b, err := embedded.ReadFile(p)
+	if err != nil {
+		return "", fmt.Errorf("migrations: read %s: %w", p, err)
+	}
+	return string(b), nil
+}
+
+// Plan returns the migrations an up (steps > 0) or down (steps < 0) run
+// would apply from version current, in execution order. steps == 0 means
+// "all pending" for up and is treated as a single step for down.
+func Plan(files []File, current uint, steps int) []File {
+	var out []File
+	if steps >= 0 {
+		for _, f := range files {
+			if f.Version > current {
+				out = append(out, f)
+			}
+		}
+		if steps > 0 && len(out) > steps {
+			out = out[:steps]
+		}
+		return out
+	}
+	for i := len(files) - 1; i >= 0 && len(out) < -steps; i-- {
+		if files[i].Version <= current {
+			out = append(out, files[i])
+		}
+	}
+	return out
+}
+
+// FS returns a flat view of every domain directory, the layout
+// golang-migrate's iofs source expects (all files in one directory).
+func FS() (fs.FS, error) {
+	files, err := List()
+	if err != nil {
+		return nil, err
+	}
+	flat := flatFS{paths: make(map[string]string)}
+	for _, f := range files {
+		for _, p := range []string{f.UpPath, f.DownPath} {
+			flat.paths[path.Base(p)] = p
+		}
+	}
+	return flat, nil
+}
+
+func parseName(name string) (version uint, title, direction string, err error) {
+	base, ok := strings.CutSuffix(name, ".sql")
+	if !ok {
+		return 0, "", "", fmt.Errorf("migrations: %s is not a .sql file", name)
+	}
+	switch {
+	case strings.HasSuffix(base, ".up"):
+		direction, base = "up", strings.TrimSuffix(base, ".up")
+	case strings.HasSuffix(base, ".down"):
+		direction, base = "down", strings.TrimSuffix(base, ".down")
+	default:
+		return 0, "", "", fmt.Errorf("migrations: %s must end in .up.sql or .down.sql", name)
+	}
+	num, rest, ok := strings.Cut(base, "_")
+	if !ok {
+		return 0, "", "", fmt.Errorf("migrations: %s must be named {version}_{title}", name)
+	}
+	v, err := strconv.ParseUint(num, 10, 64)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("migrations: %s has a non-numeric version", name)
+	}
+	return uint(v), rest, direction, nil
+}
+
+// flatFS exposes the embedded domain directories as a single directory.
+type flatFS struct {
+	paths map[string]string // base name → embedded path
+}
+
+func (f flatFS) Open(name string) (fs.File, error) {
+	if name == "." {
+		return embedded.Open(".")
+	}
+	p, ok := f.paths[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return embedded.Open(p)
+}
+
+func (f flatFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	out := make([]fs.DirEntry, 0, len(f.paths))
+	for _, domain := range Domains {
+		entries, err := fs.ReadDir(embedded, domain)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if _, ok := f.paths[e.Name()]; ok {
+				out = append(out, e)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out, nil
+}
@@ -0,0 +1,86 @@
+//go:build postgresql
+
+package migrations
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestListEmbedded(t *testing.T) {
+	files, err := List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("List() returned no migrations")
+	}
+	seen := make(map[string]bool)
+	for i, f := range files {
+		if i > 0 && files[i-1].Version >= f.Version {
+			t.Errorf("versions not strictly increasing at %d: %d then %d", i, files[i-1].Version, f.Version)
+		}
+		for _, p := range []string{f.UpPath, f.DownPath} {
+			if _, err := ReadSQL(p); err != nil {
+				t.Errorf("ReadSQL(%s): %v", p, err)
+			}
+		}
+		seen[f.Domain] = true
+	}
+	for _, d := range Domains {
+		if !seen[d] {
+			t.Errorf("domain %s has no migrations", d)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	files := []File{{Version: 1}, {Version: 2}, {Version: 3}}
+
+	tests := []struct {
+		name    string
+		current uint
+		steps   int
+		want    []uint
+	}{
+		{"up all from zero", 0, 0, []uint{1, 2, 3}},
+		{"up one step", 1, 1, []uint{2}},
+		{"up nothing pending", 3, 0, nil},
+		{"down one", 3, -1, []uint{3}},
+		{"down two from middle", 2, -2, []uint{2, 1}},
+		{"down past zero", 1, -5, []uint{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Plan(files, tt.current, tt.steps)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Plan() = %d files, want %d", len(got), len(tt.want))
+			}
+			for i, f := range got {
+				if f.Version != tt.want[i] {
+					t.Errorf("Plan()[%d] = %d, want %d", i, f.Version, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFSIsFlat(t *testing.T) {
+	fsys, err := FS()
+	if err != nil {
+		t.Fatalf("FS() error: %v", err)
+	}
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		t.Fatalf("ReadDir error: %v", err)
+	}
+	files, _ := List()
+	if len(entries) != 2*len(files) {
+		t.Fatalf("flat FS has %d entries, want %d", len(entries), 2*len(files))
+	}
+	for _, e := range entries {
+		if _, err := fs.ReadFile(fsys, e.Name()); err != nil {
+			t.Errorf("ReadFile(%s): %v", e.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS invoice;
+DROP TABLE IF EXISTS subscription;
+DROP TABLE IF EXISTS price_plan;
+DROP TABLE IF EXISTS plan;
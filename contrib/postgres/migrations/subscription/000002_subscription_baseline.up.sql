@@ -0,0 +1,64 @@
+-- Subscription domain baseline: plans, their price plans, the client
+-- subscriptions that reference them, and invoices raised per subscription.
+
+CREATE TABLE IF NOT EXISTS plan (
+    id            TEXT PRIMARY KEY,
+    name          TEXT NOT NULL DEFAULT '',
+    description   TEXT NOT NULL DEFAULT '',
+    client_id     TEXT REFERENCES client(id),
+    billing_kind  TEXT NOT NULL DEFAULT '',
+    workspace_id  TEXT REFERENCES workspace(id),
+    active        BOOLEAN NOT NULL DEFAULT true,
+    date_created  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS price_plan (
+    id                  TEXT PRIMARY KEY,
+    plan_id             TEXT NOT NULL REFERENCES plan(id),
+    name                TEXT NOT NULL DEFAULT '',
+    description         TEXT NOT NULL DEFAULT '',
+    billing_amount      BIGINT NOT NULL DEFAULT 0,
+    billing_currency    TEXT NOT NULL DEFAULT '',
+    price_schedule_id   TEXT,
+    billing_kind        TEXT NOT NULL DEFAULT '',
+    amount_basis        TEXT NOT NULL DEFAULT '',
+    billing_cycle_value INTEGER NOT NULL DEFAULT 0,
+    billing_cycle_unit  TEXT NOT NULL DEFAULT '',
+    default_term_value  INTEGER NOT NULL DEFAULT 0,
+    default_term_unit   TEXT NOT NULL DEFAULT '',
+    client_id           TEXT REFERENCES client(id),
+    workspace_id        TEXT REFERENCES workspace(id),
+    active              BOOLEAN NOT NULL DEFAULT true,
+    date_created        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_price_plan_plan_id ON price_plan(plan_id);
+
+CREATE TABLE IF NOT EXISTS subscription (
+    id              TEXT PRIMARY KEY,
+    name            TEXT NOT NULL DEFAULT '',
+    code            TEXT NOT NULL DEFAULT '',
+    client_id       TEXT REFERENCES client(id),
+    price_plan_id   TEXT REFERENCES price_plan(id),
+    date_time_start TIMESTAMPTZ,
+    date_time_end   TIMESTAMPTZ,
+    workspace_id    TEXT REFERENCES workspace(id),
+    active          BOOLEAN NOT NULL DEFAULT true,
+    date_created    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_subscription_client_id ON subscription(client_id);
+CREATE INDEX IF NOT EXISTS idx_subscription_price_plan_id ON subscription(price_plan_id);
+
+CREATE TABLE IF NOT EXISTS invoice (
+    id              TEXT PRIMARY KEY,
+    invoice_number  TEXT NOT NULL DEFAULT '',
+    amount          BIGINT NOT NULL DEFAULT 0,
+    subscription_id TEXT REFERENCES subscription(id),
+    workspace_id    TEXT REFERENCES workspace(id),
+    active          BOOLEAN NOT NULL DEFAULT true,
+    date_created    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_invoice_subscription_id ON invoice(subscription_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS activity;
+DROP TABLE IF EXISTS stage;
+DROP TABLE IF EXISTS workflow;
@@ -0,0 +1,47 @@
+-- Workflow domain baseline: runtime workflow → stage → activity chain.
+-- activity.assigned_to holds a global user.id (see the assignee bridge in
+-- the postgres workflow adapter).
+
+CREATE TABLE IF NOT EXISTS workflow (
+    id                   TEXT PRIMARY KEY,
+    name                 TEXT NOT NULL DEFAULT '',
+    description          TEXT NOT NULL DEFAULT '',
+    status               TEXT NOT NULL DEFAULT '',
+    version              INTEGER NOT NULL DEFAULT 1,
+    workflow_template_id TEXT,
+    workspace_id         TEXT REFERENCES workspace(id),
+    active               BOOLEAN NOT NULL DEFAULT true,
+    date_created         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_workflow_workflow_template_id ON workflow(workflow_template_id);
+
+CREATE TABLE IF NOT EXISTS stage (
+    id                TEXT PRIMARY KEY,
+    workflow_id       TEXT NOT NULL REFERENCES workflow(id),
+    stage_template_id TEXT,
+    name              TEXT NOT NULL DEFAULT '',
+    status            TEXT NOT NULL DEFAULT '',
+    workspace_id      TEXT REFERENCES workspace(id),
+    active            BOOLEAN NOT NULL DEFAULT true,
+    date_created      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_stage_workflow_id ON stage(workflow_id);
+
+CREATE TABLE IF NOT EXISTS activity (
+    id                   TEXT PRIMARY KEY,
+    stage_id             TEXT NOT NULL REFERENCES stage(id),
+    activity_template_id TEXT,
+    name                 TEXT NOT NULL DEFAULT '',
+    description          TEXT NOT NULL DEFAULT '',
+    status               TEXT NOT NULL DEFAULT '',
+    priority             TEXT NOT NULL DEFAULT '',
+    assigned_to          TEXT,
+    workspace_id         TEXT REFERENCES workspace(id),
+    active               BOOLEAN NOT NULL DEFAULT true,
+    date_created         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    date_modified        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_activity_stage_id ON activity(stage_id);
+CREATE INDEX IF NOT EXISTS idx_activity_assigned_to ON activity(assigned_to) WHERE assigned_to IS NOT NULL;
@@ -0,0 +1,322 @@
+//go:build sqlite
+
+// Package sqlite is the SQLite adapter's self-registration entry point.
+//
+// init() registers three things with the espyna registry:
+//   - Provider factory (NewSQLiteAdapter)
+//   - BuildFromEnv builder (reads SQLITE_* env vars, returns initialized adapter)
+//   - TableConfigBuilder (buildSQLiteTableConfig — scans SQLITE_TABLE_* env vars via entityid.All)
+//
+// The generic DatabaseOperation factory is registered by the core package.
+// Entity repository adapters have not landed yet; until they do, CreateRepository
+// returns the registry's "no factory" error and the affected domains degrade the
+// same way they do on any provider without that entity.
+//
+// Schema bootstrap: with SQLITE_AUTO_MIGRATE=true (or auto_migrate in the map
+// config) Initialize creates a base table for every entityid.All entry, applies
+// *.sql files from SQLITE_MIGRATIONS_PATH, and lets the operations layer add
+// columns on first write.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/contrib/sqlite/internal/adapter/core"
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/ports"
+	"github.com/erniealice/espyna-golang/registry"
+	entityid "github.com/erniealice/espyna-golang/registry/entityid"
+	dbpb "github.com/erniealice/esqyma/pkg/schema/v1/infrastructure/database"
+	_ "modernc.org/sqlite"
+)
+
+// =============================================================================
+// Self-Registration - Adapter registers itself with the factory
+// =============================================================================
+
+func init() {
+	registry.RegisterDatabaseProvider(
+		"sqlite",
+		func() ports.DatabaseProvider {
+			return NewSQLiteAdapter()
+		},
+		transformConfig,
+	)
+	registry.RegisterDatabaseBuildFromEnv("sqlite", buildFromEnv)
+	registry.RegisterDatabaseTableConfigBuilder("sqlite", buildSQLiteTableConfig)
+}
+
+// buildSQLiteTableConfig creates table config from SQLITE_TABLE_* environment variables.
+func buildSQLiteTableConfig() *registry.TableConfig {
+	prefix := getEnv("SQLITE_TABLE_PREFIX", "")
+	overrides := make(map[string]string)
+	for _, entity := range entityid.All {
+		if val := os.Getenv("SQLITE_TABLE_" + strings.ToUpper(entity)); val != "" {
+			overrides[entity] = val
+		}
+	}
+	return registry.NewTableConfig(prefix, overrides)
+}
+
+// buildFromEnv creates and initializes a SQLite adapter from environment variables.
+func buildFromEnv() (ports.DatabaseProvider, error) {
+	inMemory := getEnvBool("SQLITE_IN_MEMORY", false)
+	path := getEnv("SQLITE_PATH", "espyna.db")
+
+	protoConfig := &dbpb.DatabaseProviderConfig{
+		Provider: dbpb.DatabaseProvider_DATABASE_PROVIDER_SQLITE,
+		Enabled:  true,
+		Config: &dbpb.DatabaseProviderConfig_Sqlite{
+			Sqlite: &dbpb.SQLiteConfig{
+				FilePath:           path,
+				InMemory:           inMemory,
+				JournalMode:        getEnv("SQLITE_JOURNAL_MODE", "WAL"),
+				Synchronous:        getEnv("SQLITE_SYNCHRONOUS", "NORMAL"),
+				MaxOpenConnections: int32(getEnvInt("SQLITE_MAX_CONNECTIONS", 4)),
+				EnableForeignKeys:  getEnvBool("SQLITE_FOREIGN_KEYS", true),
+				MigrationsPath:     getEnv("SQLITE_MIGRATIONS_PATH", ""),
+				AutoMigrate:        getEnvBool("SQLITE_AUTO_MIGRATE", false),
+			},
+		},
+	}
+
+	adapter := NewSQLiteAdapter()
+	if err := adapter.Initialize(protoConfig); err != nil {
+		return nil, fmt.Errorf("sqlite: failed to initialize: %w", err)
+	}
+	return adapter, nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// transformConfig converts raw config map to SQLite proto config.
+func transformConfig(rawConfig map[string]any) (*dbpb.DatabaseProviderConfig, error) {
+	return ports.NewDatabaseConfigAdapter().ConvertMapToProtoConfig("sqlite", rawConfig)
+}
+
+// =============================================================================
+// Adapter Implementation
+// =============================================================================
+
+// SQLiteAdapter implements DatabaseProvider and RepositoryProvider for SQLite.
+type SQLiteAdapter struct {
+	db        *sql.DB
+	config    *dbpb.SQLiteConfig
+	maxConns  int
+	enabled   bool
+	connected bool
+}
+
+// NewSQLiteAdapter creates a new SQLite database adapter.
+func NewSQLiteAdapter() *SQLiteAdapter {
+	return &SQLiteAdapter{
+		enabled: true,
+	}
+}
+
+// Name returns the provider name.
+func (a *SQLiteAdapter) Name() string {
+	return "sqlite"
+}
+
+// Initialize opens the SQLite database, applies connection pragmas, and runs
+// the schema bootstrap when AutoMigrate is set.
+func (a *SQLiteAdapter) Initialize(config *dbpb.DatabaseProviderConfig) error {
+	cfg := config.GetSqlite()
+	if cfg == nil {
+		return fmt.Errorf("sqlite adapter requires sqlite configuration")
+	}
+	if !cfg.InMemory && cfg.FilePath == "" {
+		return fmt.Errorf("sqlite adapter requires file_path when not in_memory")
+	}
+	a.config = cfg
+
+	db, err := sql.Open("sqlite", buildDSN(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	maxConns := int(cfg.MaxOpenConnections)
+	if maxConns <= 0 {
+		maxConns = 1
+	}
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(maxConns)
+	// In-memory databases vanish when the last connection closes.
+	if cfg.InMemory {
+		db.SetConnMaxLifetime(0)
+	} else {
+		db.SetConnMaxLifetime(30 * time.Minute)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to connect to SQLite: %w", err)
+	}
+
+	if cfg.AutoMigrate || cfg.MigrationsPath != "" {
+		tableConfig := buildSQLiteTableConfig()
+		tables := make([]string, 0, len(entityid.All))
+		if cfg.AutoMigrate {
+			for _, entity := range entityid.All {
+				tables = append(tables, tableConfig.TableName(entity))
+			}
+		}
+		if err := core.Bootstrap(ctx, db, core.BootstrapOptions{
+			Tables:         tables,
+			MigrationsPath: cfg.MigrationsPath,
+			AutoMigrate:    cfg.AutoMigrate,
+		}); err != nil {
+			db.Close()
+			return err
+		}
+	}
+
+	a.db = db
+	a.maxConns = maxConns
+	a.enabled = config.Enabled
+	a.connected = true
+
+	target := cfg.FilePath
+	if cfg.InMemory {
+		target = ":memory:"
+	}
+	log.Printf("✅ SQLite adapter opened %s (pool max=%d auto_migrate=%v)", target, maxConns, cfg.AutoMigrate)
+	return nil
+}
+
+// buildDSN renders the modernc.org/sqlite DSN. Pragmas are passed as
+// _pragma parameters so every pooled connection gets them, not just the first.
+func buildDSN(cfg *dbpb.SQLiteConfig) string {
+	pragmas := []string{"busy_timeout(5000)"}
+	if cfg.JournalMode != "" && !cfg.InMemory {
+		pragmas = append(pragmas, "journal_mode("+cfg.JournalMode+")")
+	}
+	if cfg.Synchronous != "" {
+		pragmas = append(pragmas, "synchronous("+cfg.Synchronous+")")
+	}
+	if cfg.EnableForeignKeys {
+		pragmas = append(pragmas, "foreign_keys(1)")
+	}
+	if cfg.CacheSizeKb > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("cache_size(-%d)", cfg.CacheSizeKb))
+	}
+
+	dsn := "file:" + cfg.FilePath + "?"
+	if cfg.InMemory {
+		// A named shared-cache memory DB is visible to every pooled connection.
+		dsn = "file:espyna?mode=memory&cache=shared&"
+	}
+	params := make([]string, len(pragmas))
+	for i, p := range pragmas {
+		params[i] = "_pragma=" + p
+	}
+	return dsn + strings.Join(params, "&")
+}
+
+// GetConnection returns the SQLite database connection.
+func (a *SQLiteAdapter) GetConnection() any {
+	return a.db
+}
+
+// Close closes the SQLite connection.
+func (a *SQLiteAdapter) Close() error {
+	if a.db != nil {
+		err := a.db.Close()
+		a.db = nil
+		a.connected = false
+		if err != nil {
+			return fmt.Errorf("failed to close SQLite connection: %w", err)
+		}
+		log.Println("✅ SQLite adapter closed")
+	}
+	return nil
+}
+
+// IsHealthy checks if the SQLite connection is healthy.
+func (a *SQLiteAdapter) IsHealthy(ctx context.Context) error {
+	if !a.enabled {
+		return fmt.Errorf("sqlite adapter is disabled")
+	}
+	if a.db == nil {
+		return fmt.Errorf("sqlite connection is nil")
+	}
+	if err := a.db.PingContext(ctx); err != nil {
+		a.connected = false
+		return fmt.Errorf("sqlite health check failed: %w", err)
+	}
+	a.connected = true
+	return nil
+}
+
+// IsEnabled returns whether this adapter is currently enabled.
+func (a *SQLiteAdapter) IsEnabled() bool {
+	return a.enabled
+}
+
+// MaxConns returns the configured pool cap. Implements ports.PoolSizer.
+func (a *SQLiteAdapter) MaxConns() int {
+	if a == nil {
+		return 0
+	}
+	return a.maxConns
+}
+
+// =============================================================================
+// RepositoryProvider Implementation - Delegates to Registry
+// =============================================================================
+
+// CreateRepository creates a repository by looking up the registered factory.
+func (a *SQLiteAdapter) CreateRepository(entityName string, conn any, tableName string) (any, error) {
+	return registry.CreateRepository("sqlite", entityName, conn, tableName)
+}
+
+// GetTransactionManager returns the SQLite transaction manager.
+func (a *SQLiteAdapter) GetTransactionManager() interfaces.TransactionManager {
+	if a.db == nil || !a.connected {
+		return nil
+	}
+	return core.NewSQLiteTransactionManager(a.db)
+}
+
+// HealthCheck checks if the SQLite adapter is healthy.
+func (a *SQLiteAdapter) HealthCheck(ctx context.Context) error {
+	return a.IsHealthy(ctx)
+}
+
+// Compile-time interface checks
+var _ ports.DatabaseProvider = (*SQLiteAdapter)(nil)
+var _ ports.PoolSizer = (*SQLiteAdapter)(nil)
+var _ ports.RepositoryProvider = (*SQLiteAdapter)(nil)
@@ -0,0 +1,1085 @@
+//go:build sqlite
+
+// Package core holds the SQLite adapter's generic CRUD operations. It mirrors
+// contrib/mysql/internal/adapter/core one-for-one, translated to SQLite
+// syntax: "?" placeholders, double-quoted identifiers, PRAGMA-based schema
+// introspection, and INTEGER (unix ms) timestamps.
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/database/model"
+	"github.com/erniealice/espyna-golang/database/operations"
+	sqlexec "github.com/erniealice/espyna-golang/database/sqlexec"
+	"github.com/erniealice/espyna-golang/registry"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+	"github.com/google/uuid"
+)
+
+// dbExecutor abstracts *sql.DB and *sql.Tx for uniform query execution.
+type dbExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func init() {
+	registry.RegisterDatabaseOperationsFactory("sqlite", func(conn any) (any, error) {
+		db, ok := conn.(*sql.DB)
+		if !ok {
+			return nil, fmt.Errorf("sqlite: expected *sql.DB, got %T", conn)
+		}
+		return NewSQLiteOperations(db), nil
+	})
+}
+
+// SQLiteOperations implements DatabaseOperation for SQLite.
+//
+// When autoMigrate is enabled (the schema bootstrap option), Create and
+// Update add any missing column with ALTER TABLE ... ADD COLUMN instead of
+// dropping it, so a freshly bootstrapped database converges on the shape the
+// use cases write without hand-written DDL.
+type SQLiteOperations struct {
+	db          *sql.DB
+	autoMigrate bool
+
+	// columnCache memoises PRAGMA table_info lookups (table → column → type).
+	// Invalidated per table whenever autoMigrate adds a column.
+	columnCache   map[string]map[string]string
+	columnCacheMu sync.RWMutex
+}
+
+// Compile-time interface checks.
+var _ interfaces.DatabaseOperation = (*SQLiteOperations)(nil)
+var _ interfaces.TransactionAware = (*SQLiteOperations)(nil)
+
+// NewSQLiteOperations creates a new SQLite operations instance.
+func NewSQLiteOperations(db *sql.DB) interfaces.DatabaseOperation {
+	return &SQLiteOperations{db: db, autoMigrate: IsAutoMigrate(db)}
+}
+
+// Create creates a new record in the specified table.
+//
+// The id is assigned app-side (UUID) and the row is SELECTed back by id so the
+// result reflects column defaults and normalisation.
+func (s *SQLiteOperations) Create(ctx context.Context, tableName string, data map[string]any) (map[string]any, error) {
+	if tableName == "" {
+		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+
+	data = normalizeKeys(data)
+
+	now := time.Now().UTC().UnixMilli()
+	if existing, ok := data["id"]; !ok || existing == nil || existing == "" {
+		data["id"] = generateUUID()
+	}
+	id := fmt.Sprintf("%v", data["id"])
+	data["active"] = true
+	data["date_created"] = now
+	data["date_modified"] = now
+
+	columnTypes, err := s.prepareColumns(ctx, tableName, data)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to get table columns: %v", err),
+			"SQLITE_SCHEMA_ERROR",
+			500,
+		)
+	}
+
+	columns := make([]string, 0, len(data))
+	placeholders := make([]string, 0, len(data))
+	values := make([]any, 0, len(data))
+	var skipped []string
+
+	for column, value := range data {
+		if _, ok := columnTypes[column]; !ok {
+			skipped = append(skipped, column)
+			continue
+		}
+		columns = append(columns, quoteIdent(column))
+		placeholders = append(placeholders, "?")
+		values = append(values, serializeValue(value))
+	}
+	if len(skipped) > 0 {
+		log.Printf("SQLiteOperations.Create: dropped %d unknown column(s) for table=%q skipped=%v", len(skipped), tableName, skipped)
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		quoteIdent(tableName),
+		strings.Join(columns, ", "),
+		strings.Join(placeholders, ", "),
+	)
+
+	if _, err := s.getExecutor(ctx).ExecContext(ctx, query, values...); err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to create record: %v", err),
+			"SQLITE_CREATE_FAILED",
+			500,
+		)
+	}
+
+	result, err := s.readByID(ctx, tableName, id, columnTypes)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to read created record: %v", err),
+			"SQLITE_CREATE_FAILED",
+			500,
+		)
+	}
+	return result, nil
+}
+
+// Read retrieves a record by ID from the specified table.
+func (s *SQLiteOperations) Read(ctx context.Context, tableName string, id string) (map[string]any, error) {
+	if tableName == "" {
+		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+	if id == "" {
+		return nil, model.NewDatabaseError("record ID is required", "MISSING_RECORD_ID", 400)
+	}
+
+	columnTypes, err := s.getTableColumnTypes(ctx, tableName)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to get table columns: %v", err),
+			"SQLITE_SCHEMA_ERROR",
+			500,
+		)
+	}
+
+	result, err := s.readByID(ctx, tableName, id, columnTypes)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+		}
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to read record: %v", err),
+			"SQLITE_READ_FAILED",
+			500,
+		)
+	}
+	return result, nil
+}
+
+// Update updates an existing record in the specified table.
+func (s *SQLiteOperations) Update(ctx context.Context, tableName string, id string, data map[string]any) (map[string]any, error) {
+	if tableName == "" {
+		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+	if id == "" {
+		return nil, model.NewDatabaseError("record ID is required", "MISSING_RECORD_ID", 400)
+	}
+
+	data = normalizeKeys(data)
+	// Preserve original creation data — callers round-trip the full proto,
+	// which may carry a zero or stale date_created.
+	delete(data, "date_created")
+	data["date_modified"] = time.Now().UTC().UnixMilli()
+
+	columnTypes, err := s.prepareColumns(ctx, tableName, data)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to get table columns: %v", err),
+			"SQLITE_SCHEMA_ERROR",
+			500,
+		)
+	}
+
+	setParts := make([]string, 0, len(data))
+	values := make([]any, 0, len(data)+1)
+	var skipped []string
+
+	for column, value := range data {
+		if column == "id" {
+			continue
+		}
+		if _, ok := columnTypes[column]; !ok {
+			skipped = append(skipped, column)
+			continue
+		}
+		setParts = append(setParts, fmt.Sprintf("%s = ?", quoteIdent(column)))
+		values = append(values, serializeValue(value))
+	}
+	if len(skipped) > 0 {
+		log.Printf("SQLiteOperations.Update: dropped %d unknown column(s) for table=%q id=%q skipped=%v", len(skipped), tableName, id, skipped)
+	}
+	values = append(values, id)
+
+	// No active filter — allows re-activating soft-deleted records.
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s = ?",
+		quoteIdent(tableName),
+		strings.Join(setParts, ", "),
+		quoteIdent("id"),
+	)
+
+	res, err := s.getExecutor(ctx).ExecContext(ctx, query, values...)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to update record: %v", err),
+			"SQLITE_UPDATE_FAILED",
+			500,
+		)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+
+	result, err := s.readByID(ctx, tableName, id, columnTypes)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to read updated record: %v", err),
+			"SQLITE_UPDATE_FAILED",
+			500,
+		)
+	}
+	return result, nil
+}
+
+// Delete deletes a record from the specified table (soft delete by default).
+func (s *SQLiteOperations) Delete(ctx context.Context, tableName string, id string) error {
+	if tableName == "" {
+		return model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+	if id == "" {
+		return model.NewDatabaseError("record ID is required", "MISSING_RECORD_ID", 400)
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s = 0, %s = ? WHERE %s = ?",
+		quoteIdent(tableName),
+		quoteIdent("active"),
+		quoteIdent("date_modified"),
+		quoteIdent("id"),
+	)
+
+	result, err := s.getExecutor(ctx).ExecContext(ctx, query, time.Now().UTC().UnixMilli(), id)
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to delete record: %v", err),
+			"SQLITE_DELETE_FAILED",
+			500,
+		)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to get affected rows: %v", err),
+			"SQLITE_DELETE_FAILED",
+			500,
+		)
+	}
+	if rowsAffected == 0 {
+		return model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return nil
+}
+
+// HardDelete permanently deletes a record from the specified table.
+func (s *SQLiteOperations) HardDelete(ctx context.Context, tableName string, id string) error {
+	if tableName == "" {
+		return model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+	if id == "" {
+		return model.NewDatabaseError("record ID is required", "MISSING_RECORD_ID", 400)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = ?", quoteIdent(tableName), quoteIdent("id"))
+	result, err := s.getExecutor(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to hard delete record: %v", err),
+			"SQLITE_HARD_DELETE_FAILED",
+			500,
+		)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to get affected rows: %v", err),
+			"SQLITE_HARD_DELETE_FAILED",
+			500,
+		)
+	}
+	if rowsAffected == 0 {
+		return model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return nil
+}
+
+// List retrieves records from the specified table with standardized params.
+func (s *SQLiteOperations) List(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	if tableName == "" {
+		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+	if _, ok := interfaces.CursorRequest(params); ok {
+		return nil, model.NewDatabaseError("cursor pagination is not supported by SQLite", "CURSOR_PAGINATION_UNSUPPORTED", 400)
+	}
+
+	columnTypes, err := s.getTableColumnTypes(ctx, tableName)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to get table columns: %v", err),
+			"SQLITE_SCHEMA_ERROR",
+			500,
+		)
+	}
+
+	// Default to active = 1 unless the caller supplies an explicit "active"
+	// BooleanFilter.
+	hasActiveFilter := false
+	if params != nil && params.Filters != nil {
+		for _, f := range params.Filters.Filters {
+			if f.GetField() == "active" {
+				if _, ok := f.FilterType.(*commonpb.TypedFilter_BooleanFilter); ok {
+					hasActiveFilter = true
+					break
+				}
+			}
+		}
+	}
+	var whereConditions []string
+	if !hasActiveFilter {
+		whereConditions = []string{fmt.Sprintf("%s = 1", quoteIdent("active"))}
+	}
+	values := []any{}
+
+	if params != nil && params.Filters != nil {
+		filterConditions, filterValues := buildFilterConditions(params.Filters)
+		whereConditions = append(whereConditions, filterConditions...)
+		values = append(values, filterValues...)
+	}
+
+	// Search — LIKE is case-insensitive for ASCII in SQLite, the closest
+	// equivalent of the postgres ILIKE block.
+	if params != nil && params.Search != nil && params.Search.Query != "" {
+		q := "%" + params.Search.Query + "%"
+		fields := params.Search.GetOptions().GetSearchFields()
+		if len(fields) == 0 {
+			return nil, model.NewDatabaseError(
+				"search requires SearchOptions.search_fields",
+				"MISSING_SEARCH_FIELDS",
+				400,
+			)
+		}
+		var likeClauses []string
+		for _, col := range fields {
+			values = append(values, q)
+			likeClauses = append(likeClauses, fmt.Sprintf("%s LIKE ?", quoteIdent(col)))
+		}
+		whereConditions = append(whereConditions, "("+strings.Join(likeClauses, " OR ")+")")
+	}
+	if len(whereConditions) == 0 {
+		whereConditions = []string{"1 = 1"}
+	}
+
+	orderByClause := fmt.Sprintf("ORDER BY %s DESC", quoteIdent("date_created"))
+	if params != nil && params.Sort != nil && len(params.Sort.Fields) > 0 {
+		orderByParts := make([]string, 0, len(params.Sort.Fields))
+		for _, sortField := range params.Sort.Fields {
+			direction := "ASC"
+			if sortField.Direction == commonpb.SortDirection_DESC {
+				direction = "DESC"
+			}
+			part := fmt.Sprintf("%s %s", quoteIdent(sortField.Field), direction)
+			switch sortField.NullOrder {
+			case commonpb.NullOrder_NULLS_FIRST:
+				part += " NULLS FIRST"
+			case commonpb.NullOrder_NULLS_LAST:
+				part += " NULLS LAST"
+			}
+			orderByParts = append(orderByParts, part)
+		}
+		orderByClause = "ORDER BY " + strings.Join(orderByParts, ", ")
+	}
+
+	countQuery := fmt.Sprintf(
+		"SELECT COUNT(*) FROM %s WHERE %s",
+		quoteIdent(tableName),
+		strings.Join(whereConditions, " AND "),
+	)
+	var totalItems int32
+	if err := s.getExecutor(ctx).QueryRowContext(ctx, countQuery, values...).Scan(&totalItems); err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to count records: %v", err),
+			"SQLITE_COUNT_FAILED",
+			500,
+		)
+	}
+
+	limit := int32(100)
+	offset := int32(0)
+	if params != nil && params.Pagination != nil {
+		if params.Pagination.Limit > 0 && params.Pagination.Limit <= 100 {
+			limit = params.Pagination.Limit
+		}
+		if offsetPagination := params.Pagination.GetOffset(); offsetPagination != nil {
+			if offsetPagination.Page > 0 {
+				offset = (offsetPagination.Page - 1) * limit
+			}
+		}
+	}
+
+	query := fmt.Sprintf(
+		"SELECT * FROM %s WHERE %s %s LIMIT ? OFFSET ?",
+		quoteIdent(tableName),
+		strings.Join(whereConditions, " AND "),
+		orderByClause,
+	)
+	results, err := s.queryRows(ctx, query, append(values, limit, offset), columnTypes)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to list records: %v", err),
+			"SQLITE_LIST_FAILED",
+			500,
+		)
+	}
+
+	currentPage := int32(1)
+	if offset > 0 && limit > 0 {
+		currentPage = (offset / limit) + 1
+	}
+	totalPages := (totalItems + limit - 1) / limit
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	return &interfaces.ListResult{
+		Data:  results,
+		Total: totalItems,
+		Pagination: &commonpb.PaginationResponse{
+			TotalItems:  totalItems,
+			CurrentPage: &currentPage,
+			TotalPages:  &totalPages,
+			HasNext:     currentPage < totalPages,
+			HasPrev:     currentPage > 1,
+		},
+	}, nil
+}
+
+// Query executes a structured query against the SQLite table.
+func (s *SQLiteOperations) Query(ctx context.Context, tableName string, queryBuilder interfaces.QueryBuilder) ([]map[string]any, error) {
+	if tableName == "" {
+		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
+	}
+	if queryBuilder == nil {
+		return nil, model.NewDatabaseError("query builder is required", "MISSING_QUERY_BUILDER", 400)
+	}
+
+	filter, err := queryBuilder.Build()
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to build query: %v", err),
+			"QUERY_BUILD_FAILED",
+			400,
+		)
+	}
+
+	columnTypes, err := s.getTableColumnTypes(ctx, tableName)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to get table columns: %v", err),
+			"SQLITE_SCHEMA_ERROR",
+			500,
+		)
+	}
+
+	whereConditions := []string{}
+	values := []any{}
+	for _, condition := range filter.Conditions {
+		col := quoteIdent(condition.Field)
+		switch condition.Operator {
+		case "==":
+			whereConditions = append(whereConditions, col+" = ?")
+			values = append(values, serializeValue(condition.Value))
+		case "!=", ">", "<", ">=", "<=", "LIKE":
+			whereConditions = append(whereConditions, fmt.Sprintf("%s %s ?", col, condition.Operator))
+			values = append(values, serializeValue(condition.Value))
+		case "in":
+			if valueSlice, ok := condition.Value.([]any); ok && len(valueSlice) > 0 {
+				placeholders := make([]string, len(valueSlice))
+				for i, val := range valueSlice {
+					placeholders[i] = "?"
+					values = append(values, val)
+				}
+				whereConditions = append(whereConditions, fmt.Sprintf("%s IN (%s)", col, strings.Join(placeholders, ", ")))
+			}
+		default:
+			return nil, model.NewDatabaseError(
+				fmt.Sprintf("unsupported operator: %s", condition.Operator),
+				"UNSUPPORTED_OPERATOR",
+				400,
+			)
+		}
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s", quoteIdent(tableName))
+	if len(whereConditions) > 0 {
+		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+	if len(filter.OrderBy) > 0 {
+		orderParts := make([]string, len(filter.OrderBy))
+		for i, orderBy := range filter.OrderBy {
+			direction := "ASC"
+			if !orderBy.Ascending {
+				direction = "DESC"
+			}
+			orderParts[i] = fmt.Sprintf("%s %s", quoteIdent(orderBy.Field), direction)
+		}
+		query += " ORDER BY " + strings.Join(orderParts, ", ")
+	} else {
+		query += fmt.Sprintf(" ORDER BY %s DESC", quoteIdent("date_created"))
+	}
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	results, err := s.queryRows(ctx, query, values, columnTypes)
+	if err != nil {
+		return nil, model.NewDatabaseError(
+			fmt.Sprintf("failed to execute query: %v", err),
+			"SQLITE_QUERY_FAILED",
+			500,
+		)
+	}
+	return results, nil
+}
+
+// QueryOne executes a structured query and returns the first result.
+func (s *SQLiteOperations) QueryOne(ctx context.Context, tableName string, queryBuilder interfaces.QueryBuilder) (map[string]any, error) {
+	results, err := s.Query(ctx, tableName, queryBuilder.Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, model.NewDatabaseError("no results found", "NO_RESULTS_FOUND", 404)
+	}
+	return results[0], nil
+}
+
+// CreateMany creates each row in turn. There is no multi-row path yet; run inside a context transaction for all-or-nothing behavior.
+func (s *SQLiteOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	return interfaces.CreateEach(ctx, s, tableName, data)
+}
+
+// UpdateMany updates each row in turn (see CreateMany).
+func (s *SQLiteOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	return interfaces.UpdateEach(ctx, s, tableName, updates)
+}
+
+// DeleteMany soft-deletes each row in turn (see CreateMany).
+func (s *SQLiteOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	return interfaces.DeleteEach(ctx, s, tableName, ids)
+}
+
+// Count counts through List, whose COUNT(*) uses the same filters (see
+// interfaces.CountFromList).
+func (s *SQLiteOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (int64, error) {
+	return interfaces.CountFromList(ctx, s, tableName, params)
+}
+
+// Restore re-activates a soft-deleted row through Update (see
+// interfaces.RestoreWithUpdate).
+func (s *SQLiteOperations) Restore(ctx context.Context, tableName string, id string) error {
+	return interfaces.RestoreWithUpdate(ctx, s, tableName, id)
+}
+
+// ListDeleted lists inactive rows through List, which honours an explicit
+// active filter (see interfaces.ListDeletedFromList).
+func (s *SQLiteOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return interfaces.ListDeletedFromList(ctx, s, tableName, params)
+}
+
+// Helper methods
+
+// readByID fetches a single row by id and scans it into a snake_case map.
+func (s *SQLiteOperations) readByID(ctx context.Context, tableName, id string, columnTypes map[string]string) (map[string]any, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", quoteIdent(tableName), quoteIdent("id"))
+	rows, err := s.queryRows(ctx, query, []any{id}, columnTypes)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return rows[0], nil
+}
+
+// queryRows runs query and scans every row, normalising values against the
+// declared column types (BOOLEAN columns come back from SQLite as 0/1).
+func (s *SQLiteOperations) queryRows(ctx context.Context, query string, args []any, columnTypes map[string]string) ([]map[string]any, error) {
+	rows, err := s.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []map[string]any
+	for rows.Next() {
+		values := make([]any, len(columns))
+		valuePtrs := make([]any, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, err
+		}
+		result := make(map[string]any, len(columns))
+		for i, column := range columns {
+			result[column] = normalizeValue(values[i], columnTypes[column])
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// prepareColumns returns the table's column types, first adding any column
+// present in data but missing from the table when autoMigrate is enabled.
+func (s *SQLiteOperations) prepareColumns(ctx context.Context, tableName string, data map[string]any) (map[string]string, error) {
+	columnTypes, err := s.getTableColumnTypes(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !s.autoMigrate {
+		return columnTypes, nil
+	}
+
+	added := false
+	for column, value := range data {
+		if _, ok := columnTypes[column]; ok || value == nil {
+			continue
+		}
+		ddl := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", quoteIdent(tableName), quoteIdent(column), columnTypeFor(value))
+		if _, err := s.getExecutor(ctx).ExecContext(ctx, ddl); err != nil {
+			return nil, fmt.Errorf("auto-migrate %s.%s: %w", tableName, column, err)
+		}
+		added = true
+	}
+	if !added {
+		return columnTypes, nil
+	}
+	s.invalidateColumns(tableName)
+	return s.getTableColumnTypes(ctx, tableName)
+}
+
+// getTableColumnTypes returns column-name → declared type (upper-cased) for a
+// table. A missing table yields an error so callers surface a schema problem
+// rather than silently inserting nothing.
+func (s *SQLiteOperations) getTableColumnTypes(ctx context.Context, tableName string) (map[string]string, error) {
+	s.columnCacheMu.RLock()
+	cached, ok := s.columnCache[tableName]
+	s.columnCacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	rows, err := s.getExecutor(ctx).QueryContext(ctx, "SELECT name, type FROM pragma_table_info(?)", tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := make(map[string]string)
+	for rows.Next() {
+		var name, dataType string
+		if err := rows.Scan(&name, &dataType); err != nil {
+			return nil, err
+		}
+		types[name] = strings.ToUpper(dataType)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("table %q does not exist", tableName)
+	}
+
+	// Don't cache lookups made inside a transaction: an auto-migrated column
+	// disappears again if the transaction rolls back.
+	if _, inTx := s.getExecutor(ctx).(*sql.Tx); inTx {
+		return types, nil
+	}
+	s.columnCacheMu.Lock()
+	if s.columnCache == nil {
+		s.columnCache = make(map[string]map[string]string)
+	}
+	s.columnCache[tableName] = types
+	s.columnCacheMu.Unlock()
+	return types, nil
+}
+
+func (s *SQLiteOperations) invalidateColumns(tableName string) {
+	s.columnCacheMu.Lock()
+	delete(s.columnCache, tableName)
+	s.columnCacheMu.Unlock()
+}
+
+// RunWithTransaction executes a function within a database transaction.
+func (s *SQLiteOperations) RunWithTransaction(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to begin transaction: %v", err),
+			"SQLITE_TRANSACTION_FAILED",
+			500,
+		)
+	}
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return model.NewDatabaseError(
+				fmt.Sprintf("transaction failed and rollback failed: %v, %v", err, rollbackErr),
+				"SQLITE_TRANSACTION_ROLLBACK_FAILED",
+				500,
+			)
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.NewDatabaseError(
+			fmt.Sprintf("failed to commit transaction: %v", err),
+			"SQLITE_TRANSACTION_COMMIT_FAILED",
+			500,
+		)
+	}
+	return nil
+}
+
+// WithTransaction returns a DatabaseOperation that routes all queries through
+// the transaction stored in ctx. Implements interfaces.TransactionAware.
+func (s *SQLiteOperations) WithTransaction(ctx context.Context) interfaces.DatabaseOperation {
+	return s
+}
+
+// SupportsTransactions implements interfaces.TransactionAware.
+func (s *SQLiteOperations) SupportsTransactions() bool {
+	return true
+}
+
+// GetDB returns the underlying database connection for raw-SQL repositories.
+func (s *SQLiteOperations) GetDB() *sql.DB {
+	return s.db
+}
+
+// getExecutor returns *sql.Tx if one is active in ctx, otherwise *sql.DB.
+func (s *SQLiteOperations) getExecutor(ctx context.Context) dbExecutor {
+	tx, ok := operations.GetTransactionFromContext(ctx)
+	if ok {
+		if sqliteTx, ok := tx.(*SQLiteTransaction); ok && sqliteTx.State() == interfaces.TransactionStatePending {
+			return sqliteTx.GetTx()
+		}
+	}
+	return s.db
+}
+
+// GetExecutor returns *sql.Tx if one is active in ctx, otherwise *sql.DB.
+// Entity adapters that build raw SQL must call this instead of holding their
+// own *sql.DB reference.
+func (s *SQLiteOperations) GetExecutor(ctx context.Context) sqlexec.DBExecutor {
+	return s.getExecutor(ctx)
+}
+
+// buildFilterConditions builds WHERE conditions from FilterRequest.
+func buildFilterConditions(filterReq *commonpb.FilterRequest) ([]string, []any) {
+	conditions := []string{}
+	values := []any{}
+
+	for _, filter := range filterReq.Filters {
+		col := quoteIdent(filter.Field)
+
+		switch ft := filter.FilterType.(type) {
+		case *commonpb.TypedFilter_StringFilter:
+			sf := ft.StringFilter
+			value := sf.Value
+			if !sf.CaseSensitive {
+				col = fmt.Sprintf("LOWER(%s)", col)
+				value = strings.ToLower(value)
+			}
+			switch sf.Operator {
+			case commonpb.StringOperator_STRING_EQUALS:
+				conditions = append(conditions, col+" = ?")
+				values = append(values, value)
+			case commonpb.StringOperator_STRING_NOT_EQUALS:
+				conditions = append(conditions, col+" != ?")
+				values = append(values, value)
+			case commonpb.StringOperator_STRING_CONTAINS:
+				conditions = append(conditions, col+" LIKE ?")
+				values = append(values, "%"+value+"%")
+			case commonpb.StringOperator_STRING_STARTS_WITH:
+				conditions = append(conditions, col+" LIKE ?")
+				values = append(values, value+"%")
+			case commonpb.StringOperator_STRING_ENDS_WITH:
+				conditions = append(conditions, col+" LIKE ?")
+				values = append(values, "%"+value)
+			case commonpb.StringOperator_STRING_REGEX:
+				// SQLite ships no REGEXP implementation by default; degrade
+				// to a substring match so dev queries still return rows.
+				conditions = append(conditions, col+" LIKE ?")
+				values = append(values, "%"+value+"%")
+			}
+
+		case *commonpb.TypedFilter_NumberFilter:
+			var operator string
+			switch ft.NumberFilter.Operator {
+			case commonpb.NumberOperator_NUMBER_EQUALS:
+				operator = "="
+			case commonpb.NumberOperator_NUMBER_NOT_EQUALS:
+				operator = "!="
+			case commonpb.NumberOperator_NUMBER_GREATER_THAN:
+				operator = ">"
+			case commonpb.NumberOperator_NUMBER_GREATER_THAN_OR_EQUAL:
+				operator = ">="
+			case commonpb.NumberOperator_NUMBER_LESS_THAN:
+				operator = "<"
+			case commonpb.NumberOperator_NUMBER_LESS_THAN_OR_EQUAL:
+				operator = "<="
+			}
+			if operator != "" {
+				conditions = append(conditions, fmt.Sprintf("%s %s ?", col, operator))
+				values = append(values, ft.NumberFilter.Value)
+			}
+
+		case *commonpb.TypedFilter_BooleanFilter:
+			conditions = append(conditions, col+" = ?")
+			values = append(values, ft.BooleanFilter.Value)
+
+		case *commonpb.TypedFilter_ListFilter:
+			lf := ft.ListFilter
+			if len(lf.Values) == 0 {
+				continue
+			}
+			placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(lf.Values)), ", ")
+			for _, v := range lf.Values {
+				values = append(values, v)
+			}
+			if lf.Operator == commonpb.ListOperator_LIST_NOT_IN {
+				conditions = append(conditions, fmt.Sprintf("%s NOT IN (%s)", col, placeholders))
+			} else {
+				conditions = append(conditions, fmt.Sprintf("%s IN (%s)", col, placeholders))
+			}
+
+		case *commonpb.TypedFilter_RangeFilter:
+			rf := ft.RangeFilter
+			minOp, maxOp := ">", "<"
+			if rf.IncludeMin {
+				minOp = ">="
+			}
+			if rf.IncludeMax {
+				maxOp = "<="
+			}
+			conditions = append(conditions, fmt.Sprintf("%s %s ?", col, minOp), fmt.Sprintf("%s %s ?", col, maxOp))
+			values = append(values, rf.Min, rf.Max)
+
+		case *commonpb.TypedFilter_DateFilter:
+			// Timestamps are stored as INTEGER unix ms; filter values are
+			// ISO dates, so convert them app-side.
+			df := ft.DateFilter
+			from := dateFilterMillis(df.Value)
+			switch df.Operator {
+			case commonpb.DateOperator_DATE_EQUALS:
+				conditions = append(conditions, fmt.Sprintf("%s >= ? AND %s < ?", col, col))
+				values = append(values, from, from+int64(24*time.Hour/time.Millisecond))
+			case commonpb.DateOperator_DATE_BEFORE:
+				conditions = append(conditions, col+" < ?")
+				values = append(values, from)
+			case commonpb.DateOperator_DATE_AFTER:
+				conditions = append(conditions, col+" > ?")
+				values = append(values, from)
+			case commonpb.DateOperator_DATE_BETWEEN:
+				if df.RangeEnd != nil && *df.RangeEnd != "" {
+					conditions = append(conditions, col+" BETWEEN ? AND ?")
+					values = append(values, from, dateFilterMillis(*df.RangeEnd))
+				}
+			}
+
+		case *commonpb.TypedFilter_MoneyFilter:
+			mf := ft.MoneyFilter
+			switch mf.Operator {
+			case commonpb.MoneyOperator_MONEY_EQUALS:
+				conditions = append(conditions, col+" = ?")
+				values = append(values, mf.Amount)
+			case commonpb.MoneyOperator_MONEY_LESS_THAN:
+				conditions = append(conditions, col+" < ?")
+				values = append(values, mf.Amount)
+			case commonpb.MoneyOperator_MONEY_GREATER_THAN:
+				conditions = append(conditions, col+" > ?")
+				values = append(values, mf.Amount)
+			case commonpb.MoneyOperator_MONEY_LESS_THAN_OR_EQUAL:
+				conditions = append(conditions, col+" <= ?")
+				values = append(values, mf.Amount)
+			case commonpb.MoneyOperator_MONEY_GREATER_THAN_OR_EQUAL:
+				conditions = append(conditions, col+" >= ?")
+				values = append(values, mf.Amount)
+			case commonpb.MoneyOperator_MONEY_BETWEEN:
+				conditions = append(conditions, col+" BETWEEN ? AND ?")
+				values = append(values, mf.Amount, mf.AmountTo)
+			}
+
+		case *commonpb.TypedFilter_StatusFilter:
+			sf := ft.StatusFilter
+			if len(sf.Values) > 0 {
+				for _, v := range sf.Values {
+					values = append(values, v)
+				}
+				conditions = append(conditions, fmt.Sprintf("%s IN (%s)", col, strings.TrimSuffix(strings.Repeat("?, ", len(sf.Values)), ", ")))
+			}
+		}
+	}
+
+	return conditions, values
+}
+
+// dateFilterMillis parses an RFC3339 timestamp or YYYY-MM-DD date into unix
+// ms. Unparseable values yield 0 so the comparison fails closed.
+func dateFilterMillis(value string) int64 {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC().UnixMilli()
+		}
+	}
+	return 0
+}
+
+// quoteIdent wraps name in double quotes, doubling any embedded quote.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// columnTypeFor picks the declared type for an auto-migrated column from the
+// first value written to it.
+func columnTypeFor(v any) string {
+	switch v.(type) {
+	case bool:
+		return "BOOLEAN"
+	case int, int32, int64, uint, uint32, uint64:
+		return "INTEGER"
+	case float32, float64:
+		return "REAL"
+	case map[string]any, []any:
+		return "JSON"
+	default:
+		return "TEXT"
+	}
+}
+
+// normalizeValue converts SQLite storage values to protobuf-compatible types.
+// BOOLEAN columns arrive as 0/1 integers, JSON columns as text/bytes.
+func normalizeValue(v any, declaredType string) any {
+	switch t := v.(type) {
+	case int64:
+		if strings.Contains(declaredType, "BOOL") {
+			return t != 0
+		}
+		return t
+	case time.Time:
+		if t.IsZero() {
+			return nil
+		}
+		return t.UnixMilli()
+	case []byte:
+		return normalizeText(string(t), declaredType)
+	case string:
+		return normalizeText(t, declaredType)
+	default:
+		return v
+	}
+}
+
+func normalizeText(s, declaredType string) any {
+	if declaredType == "JSON" || strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+		var parsed any
+		if err := json.Unmarshal([]byte(s), &parsed); err == nil {
+			switch parsed.(type) {
+			case map[string]any, []any:
+				return parsed
+			}
+		}
+	}
+	return s
+}
+
+// serializeValue converts map and slice values to JSON text so they can be
+// stored in JSON/TEXT columns. Primitive types pass through.
+func serializeValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		return string(b)
+	case time.Time:
+		return t.UTC().UnixMilli()
+	default:
+		return v
+	}
+}
+
+// generateUUID generates an application-side UUID for new rows.
+func generateUUID() string {
+	return uuid.NewString()
+}
+
+// normalizeKeys converts all map keys from camelCase to snake_case so that
+// protojson-marshaled data (camelCase) maps to SQLite column names.
+func normalizeKeys(data map[string]any) map[string]any {
+	result := make(map[string]any, len(data))
+	for key, value := range data {
+		result[camelToSnake(key)] = value
+	}
+	return result
+}
+
+// camelToSnake converts camelCase to snake_case.
+func camelToSnake(s string) string {
+	var result []rune
+	for i, r := range s {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			result = append(result, '_')
+		}
+		if r >= 'A' && r <= 'Z' {
+			result = append(result, r-'A'+'a')
+		} else {
+			result = append(result, r)
+		}
+	}
+	return string(result)
+}
+
+// snakeToCamel converts snake_case to camelCase so DB column names map to
+// protojson field names for protobuf unmarshalling.
+func snakeToCamel(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) > 0 {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// DenormalizeKeys converts all map keys from snake_case to camelCase so that
+// SQLite column names map to protojson field names for protobuf unmarshalling.
+// Exported for use by entity adapters that convert DB results to protobuf.
+func DenormalizeKeys(data map[string]any) map[string]any {
+	result := make(map[string]any, len(data))
+	for key, value := range data {
+		result[snakeToCamel(key)] = value
+	}
+	return result
+}
@@ -0,0 +1,196 @@
+//go:build sqlite
+
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/database/model"
+	"github.com/erniealice/espyna-golang/database/operations"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// openTestDB opens a private in-memory database with the given base tables.
+// One connection keeps every query on the same in-memory database.
+func openTestDB(t *testing.T, autoMigrate bool, tables ...string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := Bootstrap(context.Background(), db, BootstrapOptions{Tables: tables, AutoMigrate: autoMigrate}); err != nil {
+		t.Fatalf("bootstrap failed: %v", err)
+	}
+	return db
+}
+
+func errorCode(err error) string {
+	var dbErr *model.DatabaseError
+	if errors.As(err, &dbErr) {
+		return dbErr.Code
+	}
+	return ""
+}
+
+func TestCRUDRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	ops := NewSQLiteOperations(openTestDB(t, true, "widget"))
+
+	created, err := ops.Create(ctx, "widget", map[string]any{
+		"name":      "Bolt",
+		"unitCount": int64(3),
+		"tags":      []any{"steel", "m6"},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	id, _ := created["id"].(string)
+	if id == "" {
+		t.Fatalf("create assigned no id: %v", created)
+	}
+	if created["active"] != true || created["name"] != "Bolt" || created["unit_count"] != int64(3) {
+		t.Errorf("created = %v", created)
+	}
+	if tags, ok := created["tags"].([]any); !ok || len(tags) != 2 {
+		t.Errorf("tags = %#v", created["tags"])
+	}
+
+	updated, err := ops.Update(ctx, "widget", id, map[string]any{"name": "Hex bolt", "dateCreated": int64(0)})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated["name"] != "Hex bolt" || updated["date_created"] != created["date_created"] {
+		t.Errorf("updated = %v", updated)
+	}
+
+	if err := ops.Delete(ctx, "widget", id); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	deleted, err := ops.Read(ctx, "widget", id)
+	if err != nil || deleted["active"] != false {
+		t.Fatalf("soft-deleted row = %v, err = %v", deleted, err)
+	}
+	list, err := ops.List(ctx, "widget", nil)
+	if err != nil || len(list.Data) != 0 {
+		t.Fatalf("list after delete = %v, err = %v", list, err)
+	}
+	if err := ops.Restore(ctx, "widget", id); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	if err := ops.HardDelete(ctx, "widget", id); err != nil {
+		t.Fatalf("hard delete: %v", err)
+	}
+	if _, err := ops.Read(ctx, "widget", id); errorCode(err) != "RECORD_NOT_FOUND" {
+		t.Errorf("read after hard delete: %v", err)
+	}
+	if _, err := ops.Update(ctx, "widget", id, map[string]any{"name": "x"}); errorCode(err) != "RECORD_NOT_FOUND" {
+		t.Errorf("update of missing row: %v", err)
+	}
+}
+
+func TestCreateDropsUnknownColumnsWithoutAutoMigrate(t *testing.T) {
+	ops := NewSQLiteOperations(openTestDB(t, false, "widget"))
+
+	created, err := ops.Create(context.Background(), "widget", map[string]any{"name": "Bolt"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, ok := created["name"]; ok {
+		t.Errorf("unknown column was written: %v", created)
+	}
+}
+
+func TestCreateMissingTable(t *testing.T) {
+	ops := NewSQLiteOperations(openTestDB(t, true))
+
+	if _, err := ops.Create(context.Background(), "missing", map[string]any{"name": "Bolt"}); errorCode(err) != "SQLITE_SCHEMA_ERROR" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestListPaginationAndSearch(t *testing.T) {
+	ctx := context.Background()
+	ops := NewSQLiteOperations(openTestDB(t, true, "widget"))
+	for _, name := range []string{"Bolt", "Nut", "Washer"} {
+		if _, err := ops.Create(ctx, "widget", map[string]any{"name": name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page2, err := ops.List(ctx, "widget", &interfaces.ListParams{
+		Sort: &commonpb.SortRequest{Fields: []*commonpb.SortField{{Field: "name", Direction: commonpb.SortDirection_ASC}}},
+		Pagination: &commonpb.PaginationRequest{
+			Limit:  2,
+			Method: &commonpb.PaginationRequest_Offset{Offset: &commonpb.OffsetPagination{Page: 2}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page2.Total != 3 || len(page2.Data) != 1 || page2.Data[0]["name"] != "Washer" {
+		t.Fatalf("page 2 = %v (total %d)", page2.Data, page2.Total)
+	}
+	if !page2.Pagination.HasPrev || page2.Pagination.HasNext {
+		t.Errorf("pagination = %v", page2.Pagination)
+	}
+
+	found, err := ops.List(ctx, "widget", &interfaces.ListParams{
+		Search: &commonpb.SearchRequest{Query: "WASH", Options: &commonpb.SearchOptions{SearchFields: []string{"name"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found.Data) != 1 || found.Data[0]["name"] != "Washer" {
+		t.Errorf("search = %v", found.Data)
+	}
+
+	if _, err := ops.List(ctx, "widget", &interfaces.ListParams{Search: &commonpb.SearchRequest{Query: "x"}}); errorCode(err) != "MISSING_SEARCH_FIELDS" {
+		t.Errorf("search without fields: %v", err)
+	}
+}
+
+func TestQueryAndQueryOne(t *testing.T) {
+	ctx := context.Background()
+	ops := NewSQLiteOperations(openTestDB(t, true, "widget"))
+	for i, name := range []string{"Bolt", "Nut", "Washer"} {
+		if _, err := ops.Create(ctx, "widget", map[string]any{"name": name, "size": int64(i + 1)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := ops.Query(ctx, "widget", operations.NewQueryBuilder().
+		WhereIn("name", []any{"Bolt", "Washer"}).
+		OrderBy("size", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0]["name"] != "Washer" || rows[1]["name"] != "Bolt" {
+		t.Errorf("rows = %v", rows)
+	}
+
+	rows, err = ops.Query(ctx, "widget", operations.NewQueryBuilder().Where("size", ">=", int64(2)))
+	if err != nil || len(rows) != 2 {
+		t.Errorf("size >= 2: %v, err = %v", rows, err)
+	}
+
+	one, err := ops.QueryOne(ctx, "widget", operations.NewQueryBuilder().WhereEqualTo("name", "Nut"))
+	if err != nil || one["size"] != int64(2) {
+		t.Errorf("query one = %v, err = %v", one, err)
+	}
+	if _, err := ops.QueryOne(ctx, "widget", operations.NewQueryBuilder().WhereEqualTo("name", "Gear")); errorCode(err) != "NO_RESULTS_FOUND" {
+		t.Errorf("query one without match: %v", err)
+	}
+	if _, err := ops.Query(ctx, "widget", operations.NewQueryBuilder().Where("name", "~", "x")); errorCode(err) != "UNSUPPORTED_OPERATOR" {
+		t.Errorf("unsupported operator: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+//go:build sqlite
+
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BootstrapOptions configures the schema bootstrap run at adapter start-up.
+type BootstrapOptions struct {
+	// Tables lists the table names to create (if missing) with the base
+	// columns every espyna entity carries: id, active, date_created,
+	// date_modified. Typically every entityid.All entry run through the
+	// TableConfig.
+	Tables []string
+
+	// MigrationsPath is an optional directory of *.sql files applied in
+	// lexical order before the base tables are created. Each file is applied
+	// once; applied names are tracked in the schema_migrations table.
+	MigrationsPath string
+
+	// AutoMigrate lets SQLiteOperations add missing columns on write instead
+	// of dropping them, so the base tables grow into the shape the use cases
+	// need without hand-written DDL.
+	AutoMigrate bool
+}
+
+// autoMigrateDBs records which connections were bootstrapped with
+// AutoMigrate. The operations factory only receives the *sql.DB, so the flag
+// travels with the connection rather than through the registry signature.
+var autoMigrateDBs sync.Map
+
+// IsAutoMigrate reports whether db was bootstrapped with AutoMigrate.
+func IsAutoMigrate(db *sql.DB) bool {
+	_, ok := autoMigrateDBs.Load(db)
+	return ok
+}
+
+// Bootstrap creates the base tables and applies pending migration files.
+// It is idempotent and safe to run on every start.
+func Bootstrap(ctx context.Context, db *sql.DB, opts BootstrapOptions) error {
+	if opts.AutoMigrate {
+		autoMigrateDBs.Store(db, struct{}{})
+	}
+
+	// Migrations run first so hand-written CREATE TABLE statements win; the
+	// base tables below only fill in entities no migration defines.
+	if opts.MigrationsPath != "" {
+		if err := applyMigrations(ctx, db, opts.MigrationsPath); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range opts.Tables {
+		ddl := fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s (%s TEXT PRIMARY KEY, %s BOOLEAN NOT NULL DEFAULT 1, %s INTEGER, %s INTEGER)",
+			quoteIdent(table), quoteIdent("id"), quoteIdent("active"), quoteIdent("date_created"), quoteIdent("date_modified"),
+		)
+		if _, err := db.ExecContext(ctx, ddl); err != nil {
+			return fmt.Errorf("sqlite bootstrap: create table %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// applyMigrations runs every not-yet-applied *.sql file in dir, each inside
+// its own transaction.
+func applyMigrations(ctx context.Context, db *sql.DB, dir string) error {
+	if _, err := db.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS "schema_migrations" ("name" TEXT PRIMARY KEY, "applied_at" INTEGER NOT NULL DEFAULT (unixepoch() * 1000))`,
+	); err != nil {
+		return fmt.Errorf("sqlite bootstrap: create schema_migrations: %w", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return fmt.Errorf("sqlite bootstrap: list migrations: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		var exists int
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "schema_migrations" WHERE "name" = ?`, name).Scan(&exists); err != nil {
+			return fmt.Errorf("sqlite bootstrap: check %s: %w", name, err)
+		}
+		if exists > 0 {
+			continue
+		}
+
+		body, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("sqlite bootstrap: read %s: %w", name, err)
+		}
+		if strings.TrimSpace(string(body)) == "" {
+			continue
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("sqlite bootstrap: begin %s: %w", name, err)
+		}
+		if _, err := tx.ExecContext(ctx, string(body)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("sqlite bootstrap: apply %s: %w", name, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO "schema_migrations" ("name") VALUES (?)`, name); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("sqlite bootstrap: record %s: %w", name, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("sqlite bootstrap: commit %s: %w", name, err)
+		}
+		log.Printf("✅ SQLite migration applied: %s", name)
+	}
+	return nil
+}
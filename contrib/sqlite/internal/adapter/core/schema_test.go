@@ -0,0 +1,83 @@
+//go:build sqlite
+
+package core
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMigration(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func queryInt(t *testing.T, db *sql.DB, query string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query).Scan(&n); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return n
+}
+
+func TestBootstrap_AppliesMigrationsOnceBeforeBaseTables(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, false)
+	dir := t.TempDir()
+	writeMigration(t, dir, "001_widget.sql", `
+		CREATE TABLE widget (id TEXT PRIMARY KEY, active BOOLEAN NOT NULL DEFAULT 1, date_created INTEGER, date_modified INTEGER, name TEXT NOT NULL);
+		CREATE INDEX idx_widget_name ON widget (name);`)
+	writeMigration(t, dir, "002_seed.sql", `INSERT INTO widget (id, name) VALUES ('w-1', 'Bolt');`)
+	writeMigration(t, dir, "003_empty.sql", "  \n")
+
+	opts := BootstrapOptions{Tables: []string{"widget", "gadget"}, MigrationsPath: dir, AutoMigrate: true}
+	for i := 0; i < 2; i++ {
+		if err := Bootstrap(ctx, db, opts); err != nil {
+			t.Fatalf("bootstrap run %d: %v", i+1, err)
+		}
+	}
+
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM schema_migrations`); n != 2 {
+		t.Errorf("recorded migrations = %d, want 2", n)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM widget`); n != 1 {
+		t.Errorf("seed applied %d times", n)
+	}
+	// The migration's table wins over the base table.
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM pragma_table_info('widget') WHERE name = 'name'`); n != 1 {
+		t.Error("widget lost the migration's name column")
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM pragma_table_info('gadget')`); n != 4 {
+		t.Errorf("gadget base table has %d columns, want 4", n)
+	}
+	if !IsAutoMigrate(db) {
+		t.Error("AutoMigrate was not recorded for the connection")
+	}
+}
+
+func TestBootstrap_FailedMigrationIsNotRecorded(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, false)
+	dir := t.TempDir()
+	writeMigration(t, dir, "001_widget.sql", `CREATE TABLE widget (id TEXT PRIMARY KEY);`)
+	writeMigration(t, dir, "002_broken.sql", `INSERT INTO widget (id) VALUES ('w-1'); INSERT INTO missing_table VALUES (1);`)
+
+	if err := Bootstrap(ctx, db, BootstrapOptions{MigrationsPath: dir}); err == nil {
+		t.Fatal("expected the broken migration to fail")
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM schema_migrations`); n != 1 {
+		t.Errorf("recorded migrations = %d, want 1", n)
+	}
+	if n := queryInt(t, db, `SELECT COUNT(*) FROM widget`); n != 0 {
+		t.Error("the broken migration's first statement was not rolled back")
+	}
+	if IsAutoMigrate(db) {
+		t.Error("AutoMigrate recorded without the option")
+	}
+}
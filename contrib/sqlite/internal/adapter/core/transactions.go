@@ -0,0 +1,159 @@
+//go:build sqlite
+
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/database/operations"
+)
+
+// SQLiteTransactionManager implements interfaces.TransactionManager for SQLite.
+type SQLiteTransactionManager struct {
+	db *sql.DB
+}
+
+// NewSQLiteTransactionManager creates a new SQLiteTransactionManager.
+func NewSQLiteTransactionManager(db *sql.DB) interfaces.TransactionManager {
+	return &SQLiteTransactionManager{db: db}
+}
+
+// SQLiteTransaction implements interfaces.Transaction for SQLite.
+type SQLiteTransaction struct {
+	tx    *sql.Tx
+	db    *sql.DB
+	ctx   context.Context
+	state interfaces.TransactionState
+	id    string
+}
+
+// StartTransaction creates and begins a new transaction.
+func (tm *SQLiteTransactionManager) StartTransaction(ctx context.Context) (interfaces.Transaction, error) {
+	return tm.StartTransactionWithOptions(ctx, interfaces.DefaultTransactionOptions())
+}
+
+// StartTransactionWithOptions creates and begins a new transaction with options.
+func (tm *SQLiteTransactionManager) StartTransactionWithOptions(ctx context.Context, options interfaces.TransactionOptions) (interfaces.Transaction, error) {
+	txCtx := ctx
+	if options.Timeout > 0 {
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(options.Timeout)*time.Millisecond)
+		_ = cancel
+		txCtx = timeoutCtx
+	}
+
+	tx, err := tm.db.BeginTx(txCtx, &sql.TxOptions{ReadOnly: options.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin sqlite transaction: %w", err)
+	}
+
+	return &SQLiteTransaction{
+		tx:    tx,
+		db:    tm.db,
+		ctx:   txCtx,
+		state: interfaces.TransactionStatePending,
+		id:    generateSQLiteTransactionID(),
+	}, nil
+}
+
+// RunInTransaction executes fn within a transaction, committing on success and
+// rolling back on error.
+func (tm *SQLiteTransactionManager) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return tm.RunInTransactionWithOptions(ctx, interfaces.DefaultTransactionOptions(), fn)
+}
+
+// RunInTransactionWithOptions executes fn within a transaction with options.
+func (tm *SQLiteTransactionManager) RunInTransactionWithOptions(ctx context.Context, options interfaces.TransactionOptions, fn func(ctx context.Context) error) error {
+	tx, err := tm.StartTransactionWithOptions(ctx, options)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	txCtx := operations.WithTransaction(ctx, tx)
+
+	var fnErr error
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback(ctx)
+			panic(r)
+		}
+		if fnErr != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				fnErr = fmt.Errorf("transaction failed and rollback failed: %w (rollback: %v)", fnErr, rbErr)
+			}
+		} else {
+			if cmErr := tx.Commit(ctx); cmErr != nil {
+				fnErr = fmt.Errorf("transaction succeeded but commit failed: %w", cmErr)
+			}
+		}
+	}()
+
+	fnErr = fn(txCtx)
+	return fnErr
+}
+
+// GetTransaction retrieves the current transaction from context, if any.
+func (tm *SQLiteTransactionManager) GetTransaction(ctx context.Context) (interfaces.Transaction, bool) {
+	return operations.GetTransactionFromContext(ctx)
+}
+
+// SupportsTransactions returns true.
+func (tm *SQLiteTransactionManager) SupportsTransactions() bool { return true }
+
+// ── SQLiteTransaction methods ─────────────────────────────────────────────────
+
+// Begin is a no-op for SQLite: BeginTx already started the transaction.
+func (t *SQLiteTransaction) Begin(ctx context.Context) error {
+	if t.state != interfaces.TransactionStatePending {
+		return fmt.Errorf("sqlite transaction is not pending (state: %s)", t.state.String())
+	}
+	return nil
+}
+
+// Commit commits the transaction.
+func (t *SQLiteTransaction) Commit(ctx context.Context) error {
+	if t.state != interfaces.TransactionStatePending {
+		return fmt.Errorf("cannot commit sqlite transaction in state: %s", t.state.String())
+	}
+	if err := t.tx.Commit(); err != nil {
+		t.state = interfaces.TransactionStateRolledBack
+		return fmt.Errorf("failed to commit sqlite transaction: %w", err)
+	}
+	t.state = interfaces.TransactionStateCommitted
+	return nil
+}
+
+// Rollback rolls back the transaction. Safe to call multiple times.
+func (t *SQLiteTransaction) Rollback(ctx context.Context) error {
+	if t.state == interfaces.TransactionStateCommitted {
+		return fmt.Errorf("cannot rollback committed sqlite transaction")
+	}
+	if t.state == interfaces.TransactionStateRolledBack {
+		return nil
+	}
+	if err := t.tx.Rollback(); err != nil {
+		return fmt.Errorf("failed to rollback sqlite transaction: %w", err)
+	}
+	t.state = interfaces.TransactionStateRolledBack
+	return nil
+}
+
+// Context returns the context associated with this transaction.
+func (t *SQLiteTransaction) Context() context.Context { return t.ctx }
+
+// State returns the current state of the transaction.
+func (t *SQLiteTransaction) State() interfaces.TransactionState { return t.state }
+
+// GetTx returns the underlying *sql.Tx.
+func (t *SQLiteTransaction) GetTx() *sql.Tx { return t.tx }
+
+// ID returns the unique identifier for this transaction.
+func (t *SQLiteTransaction) ID() string { return t.id }
+
+// generateSQLiteTransactionID creates a unique transaction identifier.
+func generateSQLiteTransactionID() string {
+	return fmt.Sprintf("sqlite_tx_%d", time.Now().UnixNano())
+}
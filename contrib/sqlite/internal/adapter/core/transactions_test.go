@@ -0,0 +1,135 @@
+//go:build sqlite
+
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	interfaces "github.com/erniealice/espyna-golang/database/interfaces"
+)
+
+func countRows(t *testing.T, ops interfaces.DatabaseOperation, table string) int {
+	t.Helper()
+	n, err := ops.Count(context.Background(), table, nil)
+	if err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return int(n)
+}
+
+func TestRunInTransaction_Commits(t *testing.T) {
+	db := openTestDB(t, true, "widget")
+	ops := NewSQLiteOperations(db)
+	tm := NewSQLiteTransactionManager(db)
+
+	err := tm.RunInTransaction(context.Background(), func(ctx context.Context) error {
+		if _, ok := tm.GetTransaction(ctx); !ok {
+			t.Error("no transaction in context")
+		}
+		for _, name := range []string{"Bolt", "Nut"} {
+			if _, err := ops.Create(ctx, "widget", map[string]any{"name": name}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, ops, "widget"); n != 2 {
+		t.Errorf("rows = %d, want 2", n)
+	}
+}
+
+func TestRunInTransaction_RollsBackOnError(t *testing.T) {
+	db := openTestDB(t, true, "widget")
+	ops := NewSQLiteOperations(db)
+	tm := NewSQLiteTransactionManager(db)
+	sentinel := errors.New("intentional rollback")
+
+	err := tm.RunInTransaction(context.Background(), func(ctx context.Context) error {
+		// "color" is auto-migrated inside the transaction and rolled back
+		// with it.
+		if _, err := ops.Create(ctx, "widget", map[string]any{"name": "Bolt", "color": "red"}); err != nil {
+			return err
+		}
+		return sentinel
+	})
+	if !errors.Is(err, sentinel) {
+		t.Fatalf("err = %v, want the callback's error", err)
+	}
+	if n := countRows(t, ops, "widget"); n != 0 {
+		t.Errorf("rows = %d after rollback", n)
+	}
+
+	// The column cache must not remember the rolled-back column.
+	created, err := ops.Create(context.Background(), "widget", map[string]any{"name": "Nut", "color": "blue"})
+	if err != nil {
+		t.Fatalf("create after rollback: %v", err)
+	}
+	if created["color"] != "blue" {
+		t.Errorf("created = %v", created)
+	}
+}
+
+func TestRunInTransaction_RollsBackOnPanic(t *testing.T) {
+	db := openTestDB(t, true, "widget")
+	ops := NewSQLiteOperations(db)
+	tm := NewSQLiteTransactionManager(db)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic was swallowed")
+			}
+		}()
+		_ = tm.RunInTransaction(context.Background(), func(ctx context.Context) error {
+			if _, err := ops.Create(ctx, "widget", map[string]any{"name": "Bolt"}); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+
+	if n := countRows(t, ops, "widget"); n != 0 {
+		t.Errorf("rows = %d after panic", n)
+	}
+}
+
+func TestTransactionStateTransitions(t *testing.T) {
+	ctx := context.Background()
+	tm := NewSQLiteTransactionManager(openTestDB(t, false, "widget"))
+
+	tx, err := tm.StartTransaction(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.State() != interfaces.TransactionStatePending || tx.ID() == "" {
+		t.Fatalf("state = %s, id = %q", tx.State().String(), tx.ID())
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(ctx); err == nil {
+		t.Error("second commit succeeded")
+	}
+	if err := tx.Rollback(ctx); err == nil {
+		t.Error("rollback after commit succeeded")
+	}
+
+	tx, err = tm.StartTransaction(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Errorf("second rollback: %v", err)
+	}
+	if tx.State() != interfaces.TransactionStateRolledBack {
+		t.Errorf("state = %s", tx.State().String())
+	}
+}
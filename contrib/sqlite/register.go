@@ -0,0 +1,16 @@
+//go:build sqlite
+
+// Package sqlite registers the SQLite database adapter with espyna's registry.
+// Import this package with a blank identifier to enable SQLite support:
+//
+//	import _ "github.com/erniealice/espyna-golang/contrib/sqlite"
+//
+// SQLite targets local development and embedded single-node deployments. The
+// driver is modernc.org/sqlite (pure Go, no cgo), so the binary stays
+// cross-compilable. Select it with CONFIG_DATABASE_PROVIDER=sqlite.
+package sqlite
+
+import (
+	// Import triggers adapter's init() which self-registers with the registry.
+	_ "github.com/erniealice/espyna-golang/contrib/sqlite/internal/adapter"
+)
@@ -484,6 +484,9 @@ func (s *SQLServerOperations) List(ctx context.Context, tableName string, params
 	if tableName == "" {
 		return nil, model.NewDatabaseError("table name is required", "MISSING_TABLE_NAME", 400)
 	}
+	if _, ok := interfaces.CursorRequest(params); ok {
+		return nil, model.NewDatabaseError("cursor pagination is not supported by SQL Server", "CURSOR_PAGINATION_UNSUPPORTED", 400)
+	}
 
 	// Build WHERE clause.
 	// Default to active = true unless the caller supplies an explicit "active"
@@ -826,6 +829,39 @@ func (s *SQLServerOperations) QueryOne(ctx context.Context, tableName string, qu
 	return results[0], nil
 }
 
+// CreateMany creates each row in turn. There is no multi-row path yet; run inside a context transaction for all-or-nothing behavior.
+func (s *SQLServerOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	return interfaces.CreateEach(ctx, s, tableName, data)
+}
+
+// UpdateMany updates each row in turn (see CreateMany).
+func (s *SQLServerOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	return interfaces.UpdateEach(ctx, s, tableName, updates)
+}
+
+// DeleteMany soft-deletes each row in turn (see CreateMany).
+func (s *SQLServerOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	return interfaces.DeleteEach(ctx, s, tableName, ids)
+}
+
+// Count counts through List, whose COUNT(*) uses the same filters (see
+// interfaces.CountFromList).
+func (s *SQLServerOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (int64, error) {
+	return interfaces.CountFromList(ctx, s, tableName, params)
+}
+
+// Restore re-activates a soft-deleted row through Update (see
+// interfaces.RestoreWithUpdate).
+func (s *SQLServerOperations) Restore(ctx context.Context, tableName string, id string) error {
+	return interfaces.RestoreWithUpdate(ctx, s, tableName, id)
+}
+
+// ListDeleted lists inactive rows through List, which honours an explicit
+// active filter (see interfaces.ListDeletedFromList).
+func (s *SQLServerOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return interfaces.ListDeletedFromList(ctx, s, tableName, params)
+}
+
 // Helper methods
 
 // queryOneRow runs a row-returning statement (an INSERT/UPDATE with OUTPUT
@@ -1469,6 +1505,14 @@ func (w *WorkspaceAwareOperations) List(ctx context.Context, tableName string, p
 	return w.inner.List(ctx, tableName, params)
 }
 
+func (w *WorkspaceAwareOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (int64, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		params = w.injectWorkspaceFilter(params, wsID)
+	}
+	return w.inner.Count(ctx, tableName, params)
+}
+
 func (w *WorkspaceAwareOperations) Create(ctx context.Context, tableName string, data map[string]any) (map[string]any, error) {
 	wsID := w.getWorkspaceID(ctx)
 	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
@@ -1544,6 +1588,24 @@ func (w *WorkspaceAwareOperations) HardDelete(ctx context.Context, tableName str
 	return w.inner.HardDelete(ctx, tableName, id)
 }
 
+func (w *WorkspaceAwareOperations) Restore(ctx context.Context, tableName string, id string) error {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		if _, err := w.Read(ctx, tableName, id); err != nil {
+			return err
+		}
+	}
+	return w.inner.Restore(ctx, tableName, id)
+}
+
+func (w *WorkspaceAwareOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	wsID := w.getWorkspaceID(ctx)
+	if wsID != "" && w.tableHasWorkspaceColumn(ctx, tableName) {
+		params = w.injectWorkspaceFilter(params, wsID)
+	}
+	return w.inner.ListDeleted(ctx, tableName, params)
+}
+
 func (w *WorkspaceAwareOperations) Query(ctx context.Context, tableName string, query interfaces.QueryBuilder) ([]map[string]any, error) {
 	return w.inner.Query(ctx, tableName, query)
 }
@@ -1552,6 +1614,21 @@ func (w *WorkspaceAwareOperations) QueryOne(ctx context.Context, tableName strin
 	return w.inner.QueryOne(ctx, tableName, query)
 }
 
+// CreateMany creates each row in turn. Going through the decorator's own Create keeps the per-row workspace injection and ownership checks.
+func (w *WorkspaceAwareOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	return interfaces.CreateEach(ctx, w, tableName, data)
+}
+
+// UpdateMany updates each row in turn (see CreateMany).
+func (w *WorkspaceAwareOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	return interfaces.UpdateEach(ctx, w, tableName, updates)
+}
+
+// DeleteMany soft-deletes each row in turn (see CreateMany).
+func (w *WorkspaceAwareOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	return interfaces.DeleteEach(ctx, w, tableName, ids)
+}
+
 // ── Optional interface methods ───────────────────────────────────────────────
 
 // GetDB returns the underlying *sql.DB.
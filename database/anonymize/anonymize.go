@@ -0,0 +1,31 @@
+// Package anonymize re-exports the row anonymizer for use by contrib sub-modules and tools.
+package anonymize
+
+import (
+	internal "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/anonymize"
+)
+
+// Policy and rules
+type Policy = internal.Policy
+type Rule = internal.Rule
+type Strategy = internal.Strategy
+
+// Anonymizer
+type Anonymizer = internal.Anonymizer
+type FieldCount = internal.FieldCount
+
+const (
+	StrategyKeep    = internal.StrategyKeep
+	StrategyHash    = internal.StrategyHash
+	StrategyFaker   = internal.StrategyFaker
+	StrategyNullify = internal.StrategyNullify
+)
+
+var (
+	New           = internal.New
+	ParsePolicy   = internal.ParsePolicy
+	ParseRule     = internal.ParseRule
+	DefaultPolicy = internal.DefaultPolicy
+	FakerKinds    = internal.FakerKinds
+	ErrNoSalt     = internal.ErrNoSalt
+)
@@ -0,0 +1,16 @@
+// Package cache re-exports the database result cache decorator for use by contrib sub-modules.
+package cache
+
+import (
+	internal "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/cache"
+)
+
+// Cached operations
+type CachedOperations = internal.CachedOperations
+
+const KeyPrefix = internal.KeyPrefix
+
+var (
+	NewCachedOperations          = internal.NewCachedOperations
+	NewCachedOperationsWithCache = internal.NewCachedOperationsWithCache
+)
@@ -0,0 +1,20 @@
+// Package history re-exports the revision-history decorator for use by contrib sub-modules.
+package history
+
+import (
+	internal "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/history"
+)
+
+// Revision-recording operations
+type HistoryOperations = internal.HistoryOperations
+type Revision = internal.Revision
+type Change = internal.Change
+
+var (
+	NewHistoryOperations           = internal.NewHistoryOperations
+	NewHistoryOperationsWithPolicy = internal.NewHistoryOperationsWithPolicy
+
+	ListRevisions = internal.ListRevisions
+	ReadRevision  = internal.ReadRevision
+	ReadAt        = internal.ReadAt
+)
@@ -11,6 +11,61 @@ type (
 	TransactionAware  = internal.TransactionAware
 	ListParams        = internal.ListParams
 	ListResult        = internal.ListResult
+	BatchUpdate       = internal.BatchUpdate
+	TableRows         = internal.TableRows
+	AtomicCreator     = internal.AtomicCreator
+)
+
+// Batch fallbacks for backends without a native multi-row path
+var (
+	CreateEach = internal.CreateEach
+	UpdateEach = internal.UpdateEach
+	DeleteEach = internal.DeleteEach
+)
+
+// Count fallback for backends without a native count query
+var CountFromList = internal.CountFromList
+
+// Soft-delete recovery
+const ActiveField = internal.ActiveField
+
+var (
+	DeletedParams       = internal.DeletedParams
+	ListDeletedFromList = internal.ListDeletedFromList
+	RestoreWithUpdate   = internal.RestoreWithUpdate
+)
+
+// Cursor (keyset) pagination
+type Cursor = internal.Cursor
+
+const CursorKeyField = internal.CursorKeyField
+
+var (
+	CursorRequest    = internal.CursorRequest
+	CursorDescending = internal.CursorDescending
+	EncodeCursor     = internal.EncodeCursor
+	DecodeCursor     = internal.DecodeCursor
+	CursorPagination = internal.CursorPagination
+	PageByCursor     = internal.PageByCursor
+)
+
+// Full-text search
+type TextSearcher = internal.TextSearcher
+
+const FullTextPrefix = internal.FullTextPrefix
+
+var (
+	FullText       = internal.FullText
+	FullTextField  = internal.FullTextField
+	FullTextFilter = internal.FullTextFilter
+)
+
+// Dropped-field reporting
+type DroppedFields = internal.DroppedFields
+
+var (
+	WithDroppedFields   = internal.WithDroppedFields
+	ReportDroppedFields = internal.ReportDroppedFields
 )
 
 // Query types
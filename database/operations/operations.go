@@ -45,3 +45,9 @@ var (
 	GetTransactionManagerFromContext = internal.GetTransactionManagerFromContext
 	IsTransactionContext             = internal.IsTransactionContext
 )
+
+// Read routing helpers
+var (
+	WithPrimaryRead = internal.WithPrimaryRead
+	IsPrimaryRead   = internal.IsPrimaryRead
+)
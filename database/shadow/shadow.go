@@ -0,0 +1,15 @@
+// Package shadow re-exports the shadow-read decorator for use by contrib sub-modules.
+package shadow
+
+import (
+	internal "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/shadow"
+)
+
+// Shadowed operations
+type ShadowOperations = internal.ShadowOperations
+type Mismatch = internal.Mismatch
+
+var (
+	NewShadowOperations              = internal.NewShadowOperations
+	NewShadowOperationsWithCandidate = internal.NewShadowOperationsWithCandidate
+)
@@ -0,0 +1,18 @@
+// Package tenancy re-exports the workspace guard decorator for use by contrib sub-modules.
+package tenancy
+
+import (
+	internal "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/tenancy"
+)
+
+// Workspace-scoped operations
+type TenancyOperations = internal.TenancyOperations
+
+const WorkspaceField = internal.WorkspaceField
+
+var (
+	NewTenancyOperations           = internal.NewTenancyOperations
+	NewTenancyOperationsWithPolicy = internal.NewTenancyOperationsWithPolicy
+
+	ScopeQuery = internal.ScopeQuery
+)
@@ -14,10 +14,12 @@ require (
 	github.com/erniealice/espyna-golang/contrib/microsoft v0.1.0-alpha
 	github.com/erniealice/espyna-golang/contrib/paypal v0.1.0-alpha
 	github.com/erniealice/esqyma v0.1.0-alpha
+	github.com/goccy/go-yaml v1.18.0
 	github.com/google/cel-go v0.23.0
 	github.com/google/uuid v1.6.0
 	github.com/santhosh-tekuri/jsonschema/v6 v6.0.2
 	golang.org/x/crypto v0.47.0
+	google.golang.org/grpc v1.75.1
 	google.golang.org/protobuf v1.36.11
 )
 
@@ -85,7 +87,6 @@ require (
 	github.com/go-playground/universal-translator v0.18.1 // indirect
 	github.com/go-playground/validator/v10 v10.28.0 // indirect
 	github.com/goccy/go-json v0.10.5 // indirect
-	github.com/goccy/go-yaml v1.18.0 // indirect
 	github.com/gofiber/fiber/v2 v2.52.9 // indirect
 	github.com/gofiber/fiber/v3 v3.0.0-rc.2 // indirect
 	github.com/gofiber/schema v1.6.0 // indirect
@@ -144,5 +145,4 @@ require (
 	google.golang.org/genproto v0.0.0-20251002232023-7c0ddcbb5797 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20251002232023-7c0ddcbb5797 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251002232023-7c0ddcbb5797 // indirect
-	google.golang.org/grpc v1.75.1 // indirect
 )
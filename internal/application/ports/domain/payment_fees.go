@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PaymentFeeReportRequest scopes the provider fee aggregate. The range is
+// half-open [Start, End) on integration_payment.received_at.
+type PaymentFeeReportRequest struct {
+	Start time.Time
+	End   time.Time
+	// ProviderID optionally narrows the result to one provider ("paypal").
+	ProviderID string
+}
+
+// PaymentFeeRow is one provider/currency bucket of logged payment webhooks.
+// Amounts are minor units, the same unit as PaymentTransaction.Amount.
+type PaymentFeeRow struct {
+	ProviderID string
+	Currency   string
+	// Payments counts distinct successful payments. A provider that sends
+	// several success notifications for one payment (PayPal order + capture)
+	// is counted once.
+	Payments    int64
+	GrossAmount int64
+	FeeAmount   int64
+	NetAmount   int64
+	// RefundedAmount sums refunded webhooks in the range. Providers keep the
+	// original fee on most refunds, so it is not netted against FeeAmount.
+	RefundedAmount int64
+}
+
+// PaymentFeeQueryService is a read-only aggregate port over the payment
+// webhook log for gross/fee/net revenue reporting.
+type PaymentFeeQueryService interface {
+	ListPaymentFees(ctx context.Context, req *PaymentFeeReportRequest) ([]*PaymentFeeRow, error)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "context"
+
+// StaffWorkloadRequest scopes the staff workload aggregate. The range is
+// half-open [StartMillis, EndMillis) in epoch milliseconds, matching the
+// event table's *_date_time_utc columns.
+type StaffWorkloadRequest struct {
+	// WorkspaceID limits staff to members of the active workspace. Sourced
+	// from context, never from the wire. Empty means no workspace filter.
+	WorkspaceID string
+	// StaffID optionally narrows the result to a single staff record.
+	StaffID     string
+	StartMillis int64
+	EndMillis   int64
+}
+
+// StaffWorkloadRow is one staff member's raw aggregates for the range.
+// Capacity and utilization are derived by the use case, not the adapter.
+type StaffWorkloadRow struct {
+	StaffID   string
+	UserID    string
+	FirstName string
+	LastName  string
+	// ScheduledMinutes sums active event attendance, clipped to the range.
+	ScheduledMinutes int64
+	// OpenTasks counts workflow activities assigned to the staff user that
+	// are not completed, skipped, or cancelled.
+	OpenTasks int64
+	// AssignedClients counts distinct active clients linked to any of the
+	// staff user's workspace_user rows.
+	AssignedClients int64
+}
+
+// StaffWorkloadQueryService is a read-only aggregate port for the operations
+// dashboard. Implementations compute every row in a single database round
+// trip; the generic dbOps interface cannot express the multi-table join.
+type StaffWorkloadQueryService interface {
+	ListStaffWorkload(ctx context.Context, req *StaffWorkloadRequest) ([]*StaffWorkloadRow, error)
+}
@@ -53,12 +53,16 @@ const (
 
 // Storage types
 type (
-	StorageProvider           = infrastructure.StorageProvider
-	StorageCapability         = infrastructure.StorageCapability
-	StorageCapabilityProvider = infrastructure.StorageCapabilityProvider
-	StreamingStorageProvider  = infrastructure.StreamingStorageProvider
-	StorageError              = infrastructure.StorageError
-	StorageConfigAdapter      = infrastructure.StorageConfigAdapter
+	StorageProvider               = infrastructure.StorageProvider
+	StorageCapability             = infrastructure.StorageCapability
+	StorageCapabilityProvider     = infrastructure.StorageCapabilityProvider
+	StreamingStorageProvider      = infrastructure.StreamingStorageProvider
+	MultipartStorageProvider      = infrastructure.MultipartStorageProvider
+	ObjectDeletingStorageProvider = infrastructure.ObjectDeletingStorageProvider
+	MultipartUpload               = infrastructure.MultipartUpload
+	CompletedPart                 = infrastructure.CompletedPart
+	StorageError                  = infrastructure.StorageError
+	StorageConfigAdapter          = infrastructure.StorageConfigAdapter
 )
 
 // NewStorageConfigAdapter creates a new storage config adapter
@@ -109,6 +113,50 @@ type NoOpIDGenerator = infrastructure.NoOpIDGenerator
 // NewNoOpIDGenerator creates a fallback ID service
 var NewNoOpIDGenerator = infrastructure.NewNoOpIDGenerator
 
+// Clock types
+type Clock = infrastructure.Clock
+
+// SystemClock reports wall-clock time
+type SystemClock = infrastructure.SystemClock
+
+// TravelClock is a controllable clock for tests and sandboxes
+type TravelClock = infrastructure.TravelClock
+
+var (
+	NewSystemClock = infrastructure.NewSystemClock
+	NewTravelClock = infrastructure.NewTravelClock
+	ClockNow       = infrastructure.ClockNow
+)
+
+// Cache types
+type Cache = infrastructure.Cache
+
+// Event publisher types
+type EventPublisher = infrastructure.EventPublisher
+type EventMessage = infrastructure.EventMessage
+
+// Job queue types
+type (
+	JobQueue       = infrastructure.JobQueue
+	Job            = infrastructure.Job
+	JobStatus      = infrastructure.JobStatus
+	EnqueueRequest = infrastructure.EnqueueRequest
+	JobFilter      = infrastructure.JobFilter
+)
+
+const (
+	JobPending            = infrastructure.JobPending
+	JobRunning            = infrastructure.JobRunning
+	JobSucceeded          = infrastructure.JobSucceeded
+	JobDead               = infrastructure.JobDead
+	DefaultJobMaxAttempts = infrastructure.DefaultJobMaxAttempts
+)
+
+var (
+	ErrJobNotFound = infrastructure.ErrJobNotFound
+	ErrJobNotDead  = infrastructure.ErrJobNotDead
+)
+
 // Transaction types
 type Transactor = infrastructure.Transactor
 
@@ -163,19 +211,130 @@ type (
 // FromProtoMessage converts protobuf EmailMessage to EmailMessage
 var FromProtoMessage = integration.FromProtoMessage
 
+// Notification types
+type (
+	Notification            = integration.Notification
+	NotificationChannel     = integration.NotificationChannel
+	NotificationMessage     = integration.NotificationMessage
+	Notifier                = integration.Notifier
+	NotificationSender      = integration.NotificationSender
+	NotificationPreference  = integration.NotificationPreference
+	NotificationPreferences = integration.NotificationPreferences
+)
+
+// In-app notification inbox types
+type (
+	InboxNotification       = integration.InboxNotification
+	NotificationInbox       = integration.NotificationInbox
+	NotificationInboxFilter = integration.NotificationInboxFilter
+	NotificationInboxPage   = integration.NotificationInboxPage
+)
+
+// Push types
+type (
+	PushProvider     = integration.PushProvider
+	PushPlatform     = integration.PushPlatform
+	PushMessage      = integration.PushMessage
+	DeviceToken      = integration.DeviceToken
+	DeviceTokenStore = integration.DeviceTokenStore
+)
+
+var ErrPushTokenInvalid = integration.ErrPushTokenInvalid
+
+// Text messaging (SMS, WhatsApp) types
+type (
+	MessagingProvider    = integration.MessagingProvider
+	MessageStatus        = integration.MessageStatus
+	TextMessage          = integration.TextMessage
+	SentMessage          = integration.SentMessage
+	MessageStatusWebhook = integration.MessageStatusWebhook
+	MessageStatusUpdate  = integration.MessageStatusUpdate
+	NotificationLogEntry = integration.NotificationLogEntry
+	NotificationLog      = integration.NotificationLog
+)
+
+var ErrMessagingSignature = integration.ErrMessagingSignature
+
+// Message delivery states
+const (
+	MessageStatusQueued      = integration.MessageStatusQueued
+	MessageStatusSent        = integration.MessageStatusSent
+	MessageStatusDelivered   = integration.MessageStatusDelivered
+	MessageStatusRead        = integration.MessageStatusRead
+	MessageStatusUndelivered = integration.MessageStatusUndelivered
+	MessageStatusFailed      = integration.MessageStatusFailed
+)
+
+// Notification channels and push platforms
+const (
+	NotificationChannelEmail    = integration.NotificationChannelEmail
+	NotificationChannelPush     = integration.NotificationChannelPush
+	NotificationChannelInApp    = integration.NotificationChannelInApp
+	NotificationChannelSMS      = integration.NotificationChannelSMS
+	NotificationChannelWhatsApp = integration.NotificationChannelWhatsApp
+	NotificationChannelNone     = integration.NotificationChannelNone
+	PushPlatformAndroid         = integration.PushPlatformAndroid
+	PushPlatformIOS             = integration.PushPlatformIOS
+)
+
 // Payment types
 type (
-	PaymentProvider       = integration.PaymentProvider
-	PaymentWebhookResult  = integration.PaymentWebhookResult
-	CheckoutSessionParams = integration.CheckoutSessionParams
+	PaymentProvider            = integration.PaymentProvider
+	PaymentTransactionSearcher = integration.PaymentTransactionSearcher
+	PaymentWebhookResult       = integration.PaymentWebhookResult
+	CheckoutSessionParams      = integration.CheckoutSessionParams
+	PaymentFees                = integration.PaymentFees
 )
 
+// Payment dispute types
+type (
+	PaymentDispute           = integration.PaymentDispute
+	PaymentDisputeStatus     = integration.PaymentDisputeStatus
+	PaymentDisputeEvidence   = integration.PaymentDisputeEvidence
+	PaymentDisputeDocument   = integration.PaymentDisputeDocument
+	PaymentDisputeFilter     = integration.PaymentDisputeFilter
+	PaymentDisputeRepository = integration.PaymentDisputeRepository
+	DisputeProvider          = integration.DisputeProvider
+	DisputeEvidenceSubmitter = integration.DisputeEvidenceSubmitter
+)
+
+const (
+	PaymentDisputeOpen             = integration.PaymentDisputeOpen
+	PaymentDisputeEvidenceRequired = integration.PaymentDisputeEvidenceRequired
+	PaymentDisputeUnderReview      = integration.PaymentDisputeUnderReview
+	PaymentDisputeWon              = integration.PaymentDisputeWon
+	PaymentDisputeLost             = integration.PaymentDisputeLost
+	PaymentDisputeClosed           = integration.PaymentDisputeClosed
+)
+
+// Provider fee breakdown keys on PaymentTransaction.RawData
+const (
+	PaymentRawGrossAmount = integration.PaymentRawGrossAmount
+	PaymentRawFeeAmount   = integration.PaymentRawFeeAmount
+	PaymentRawNetAmount   = integration.PaymentRawNetAmount
+	PaymentRawFeeCurrency = integration.PaymentRawFeeCurrency
+)
+
+var PaymentFeesFromRawData = integration.PaymentFeesFromRawData
+
 // Scheduler types
 type (
-	SchedulerProvider       = integration.SchedulerProvider
-	ScheduleWebhookResult   = integration.ScheduleWebhookResult
-	CreateScheduleParams    = integration.CreateScheduleParams
-	CheckAvailabilityParams = integration.CheckAvailabilityParams
+	SchedulerProvider            = integration.SchedulerProvider
+	ScheduleWebhookResult        = integration.ScheduleWebhookResult
+	CreateScheduleParams         = integration.CreateScheduleParams
+	CheckAvailabilityParams      = integration.CheckAvailabilityParams
+	ScheduleInviteSender         = integration.ScheduleInviteSender
+	ScheduleInviteeLister        = integration.ScheduleInviteeLister
+	SchedulerWebhookManager      = integration.SchedulerWebhookManager
+	SchedulerWebhookSubscription = integration.SchedulerWebhookSubscription
+	SchedulerWebhookState        = integration.SchedulerWebhookState
+)
+
+// Scheduler webhook subscription states
+const (
+	SchedulerWebhookActive   = integration.SchedulerWebhookActive
+	SchedulerWebhookDisabled = integration.SchedulerWebhookDisabled
+	SchedulerWebhookMissing  = integration.SchedulerWebhookMissing
 )
 
 // Tabular types
@@ -0,0 +1,36 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is a byte-oriented key/value store with expiring entries, shared by
+// every instance of the server when backed by Redis. The database layer
+// uses it to keep Read and List results of read-heavy tables.
+//
+// Implementations must be safe for concurrent use. Callers treat a Cache
+// as best effort: an error means "go to the database", never a failed
+// request.
+type Cache interface {
+	// Name identifies the backend ("redis", "mock_cache").
+	Name() string
+
+	// Get returns the value under key. found is false when the key is
+	// missing or expired.
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+
+	// Set stores value under key for ttl. A ttl of zero or less stores
+	// nothing.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes keys; missing keys are ignored.
+	Delete(ctx context.Context, keys ...string) error
+
+	// Increment adds one to the counter under key (starting from zero) and
+	// returns the new value. Counters do not expire.
+	Increment(ctx context.Context, key string) (int64, error)
+
+	// Close releases the backend's connections.
+	Close() error
+}
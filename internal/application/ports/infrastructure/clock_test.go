@@ -0,0 +1,53 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTravelClock_Advance(t *testing.T) {
+	c := NewTravelClock()
+	before := time.Now()
+	got := c.Advance(48 * time.Hour)
+	if got.Sub(before) < 48*time.Hour {
+		t.Fatalf("Advance(48h) = %v, want at least 48h after %v", got, before)
+	}
+	if offset, frozen := c.Offset(); frozen || offset != 48*time.Hour {
+		t.Fatalf("Offset() = %v, %v; want 48h, false", offset, frozen)
+	}
+	if c.Now().Sub(time.Now()) < 47*time.Hour {
+		t.Fatalf("Now() does not keep the offset")
+	}
+}
+
+func TestTravelClock_SetAndReset(t *testing.T) {
+	c := NewTravelClock()
+	pinned := time.Date(2030, 1, 31, 9, 0, 0, 0, time.UTC)
+	c.Set(pinned)
+	if !c.Now().Equal(pinned) {
+		t.Fatalf("Now() = %v, want %v", c.Now(), pinned)
+	}
+	if got := c.Advance(24 * time.Hour); !got.Equal(pinned.Add(24 * time.Hour)) {
+		t.Fatalf("Advance on pinned clock = %v, want %v", got, pinned.Add(24*time.Hour))
+	}
+	if _, frozen := c.Offset(); !frozen {
+		t.Fatalf("Offset() reports unpinned after Set")
+	}
+
+	c.Reset()
+	if d := c.Now().Sub(time.Now()); d > time.Second || d < -time.Second {
+		t.Fatalf("Now() after Reset is %v from wall clock", d)
+	}
+}
+
+func TestClockNow_NilFallsBackToWallClock(t *testing.T) {
+	if d := time.Since(ClockNow(nil)); d > time.Second || d < 0 {
+		t.Fatalf("ClockNow(nil) is %v from wall clock", d)
+	}
+	pinned := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewTravelClock()
+	c.Set(pinned)
+	if !ClockNow(c).Equal(pinned) {
+		t.Fatalf("ClockNow(c) = %v, want %v", ClockNow(c), pinned)
+	}
+}
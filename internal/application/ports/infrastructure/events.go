@@ -0,0 +1,34 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+)
+
+// EventMessage is one domain event on its way to a message broker. Data is
+// the same JSON body outbound webhooks deliver; the other fields repeat
+// what brokers route and filter on without decoding it.
+type EventMessage struct {
+	ID          string
+	Type        string
+	WorkspaceID string
+	OccurredAt  time.Time
+	Data        []byte
+}
+
+// EventPublisher hands domain events to a message broker (Google Pub/Sub)
+// so other systems can subscribe to client.created, invoice.paid and the
+// like instead of polling the database.
+//
+// Implementations must be safe for concurrent use. Delivery is at least
+// once: subscribers drop redeliveries by EventMessage.ID.
+type EventPublisher interface {
+	// Name identifies the backend ("pubsub", "mock_events").
+	Name() string
+
+	// Publish returns once the broker has accepted msg.
+	Publish(ctx context.Context, msg EventMessage) error
+
+	// Close flushes pending messages and releases the connection.
+	Close() error
+}
@@ -0,0 +1,109 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// JobStatus is where a job is in its life.
+type JobStatus string
+
+const (
+	// JobPending waits for its RunAt.
+	JobPending JobStatus = "pending"
+	// JobRunning is held by a worker until its lease runs out.
+	JobRunning JobStatus = "running"
+	// JobSucceeded finished without error.
+	JobSucceeded JobStatus = "succeeded"
+	// JobDead used up its attempts, or failed permanently, and waits in
+	// the dead-letter state until retried by hand.
+	JobDead JobStatus = "dead"
+)
+
+// DefaultJobMaxAttempts applies to jobs enqueued without MaxAttempts.
+const DefaultJobMaxAttempts = 10
+
+var (
+	// ErrJobNotFound is returned for an unknown job ID.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotDead is returned when retrying a job that is not dead.
+	ErrJobNotDead = errors.New("job is not in the dead-letter state")
+)
+
+// Job is one unit of deferred work, such as generating a workspace's
+// invoices or syncing a sheet. Payload is opaque to the queue; the handler
+// registered for Kind decodes it.
+type Job struct {
+	ID          string
+	Kind        string
+	WorkspaceID string
+	Payload     []byte
+	Status      JobStatus
+	// Attempts counts the claims so far, the current one included.
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time
+	LastError   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// EnqueueRequest describes a job to add. A zero RunAt runs it as soon as a
+// worker is free; a zero MaxAttempts means DefaultJobMaxAttempts.
+type EnqueueRequest struct {
+	Kind        string
+	WorkspaceID string
+	Payload     []byte
+	RunAt       time.Time
+	MaxAttempts int
+}
+
+// JobFilter narrows List. Empty fields match everything; Limit defaults to
+// 100.
+type JobFilter struct {
+	WorkspaceID string
+	Kind        string
+	Status      JobStatus
+	Limit       int
+}
+
+// JobQueue stores deferred jobs and hands due ones to workers, across every
+// instance of the server when backed by the database.
+//
+// A claimed job is leased: if its worker does not complete or fail it
+// before the lease runs out, the job is claimed again. Handlers must
+// therefore be safe to run more than once. Complete and Fail only apply
+// to the claim they are given (matched on Attempts), so a worker that
+// overran its lease cannot overwrite the outcome of the next one.
+type JobQueue interface {
+	// Name identifies the backend ("memory", "postgres").
+	Name() string
+
+	// Enqueue adds a pending job.
+	Enqueue(ctx context.Context, req EnqueueRequest) (*Job, error)
+
+	// Claim marks up to limit due jobs of kind running for lease and
+	// returns them, oldest RunAt first. Due are pending jobs whose RunAt
+	// has passed and running jobs whose lease has run out.
+	Claim(ctx context.Context, kind string, limit int, lease time.Duration) ([]*Job, error)
+
+	// Complete marks a claimed job succeeded.
+	Complete(ctx context.Context, job *Job) error
+
+	// Fail records reason on a claimed job and makes it pending again at
+	// retryAt, or dead when retryAt is zero.
+	Fail(ctx context.Context, job *Job, reason string, retryAt time.Time) error
+
+	// Retry makes a dead job pending now with a fresh set of attempts.
+	Retry(ctx context.Context, id string) (*Job, error)
+
+	// Get returns one job.
+	Get(ctx context.Context, id string) (*Job, error)
+
+	// List returns the jobs matching filter, newest first.
+	List(ctx context.Context, filter JobFilter) ([]*Job, error)
+
+	// Close releases the backend's resources.
+	Close() error
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	pb "github.com/erniealice/esqyma/pkg/schema/v1/infrastructure/storage"
 )
@@ -67,12 +68,9 @@ type StorageProvider interface {
 
 	// TODO: Future operations (implement when proto contracts are ready)
 	// ListObjects(ctx context.Context, req *pb.ListObjectsRequest) (*pb.ListObjectsResponse, error)
-	// DeleteObject(ctx context.Context, req *pb.DeleteObjectRequest) (*pb.DeleteObjectResponse, error)
 	// GetObjectMetadata(ctx context.Context, req *pb.GetObjectMetadataRequest) (*pb.GetObjectMetadataResponse, error)
-	// InitiateMultipartUpload(ctx context.Context, req *pb.InitiateMultipartUploadRequest) (*pb.InitiateMultipartUploadResponse, error)
-	// UploadPart(ctx context.Context, req *pb.UploadPartRequest) (*pb.UploadPartResponse, error)
-	// CompleteMultipartUpload(ctx context.Context, req *pb.CompleteMultipartUploadRequest) (*pb.CompleteMultipartUploadResponse, error)
-	// AbortMultipartUpload(ctx context.Context, req *pb.AbortMultipartUploadRequest) (*pb.AbortMultipartUploadResponse, error)
+	// Multipart upload lives on the optional MultipartStorageProvider below, and
+	// object deletion on ObjectDeletingStorageProvider.
 }
 
 // StorageCapability represents features supported by a storage provider
@@ -160,6 +158,65 @@ type StreamingStorageProvider interface {
 	DownloadStream(ctx context.Context, req *pb.DownloadObjectRequest) (io.ReadCloser, *pb.DownloadObjectResponse, error)
 }
 
+// MultipartUpload identifies a multipart upload in progress. ContainerName and
+// ObjectKey are the logical names the upload was initiated with; UploadID is the
+// backend's handle for it.
+type MultipartUpload struct {
+	ContainerName string
+	ObjectKey     string
+	UploadID      string
+}
+
+// CompletedPart is an uploaded part, as CompleteMultipartUpload needs it.
+type CompletedPart struct {
+	PartNumber int32
+	ETag       string
+}
+
+// MultipartStorageProvider is an OPTIONAL capability sub-interface for objects too
+// large for a single request (StorageCapabilityMultipartUpload). Like
+// StreamingStorageProvider it extends StorageProvider without changing it, and
+// callers type-assert and fall back to UploadStream/UploadObject.
+//
+// Parts are numbered from 1 and, on S3 and compatible backends, every part but the
+// last must be at least 5 MiB. Parts may be sent through the adapter (UploadPart)
+// or straight from the client to a presigned URL (PresignUploadPart); the ETag
+// each part returns is handed back to CompleteMultipartUpload. An upload that is
+// never completed must be aborted or its parts stay billed.
+type MultipartStorageProvider interface {
+	StorageProvider
+
+	// InitiateMultipartUpload starts an upload. req carries the container/key/
+	// content-type/metadata envelope; its Content field is ignored.
+	InitiateMultipartUpload(ctx context.Context, req *pb.UploadObjectRequest) (*MultipartUpload, error)
+
+	// UploadPart uploads one part of size bytes from body.
+	UploadPart(ctx context.Context, upload *MultipartUpload, partNumber int32, body io.Reader, size int64) (*CompletedPart, error)
+
+	// PresignUploadPart returns a URL the client PUTs the part to directly; the
+	// ETag response header is the part's ETag.
+	PresignUploadPart(ctx context.Context, upload *MultipartUpload, partNumber int32, expiresIn time.Duration) (string, error)
+
+	// CompleteMultipartUpload assembles the parts, in part-number order, into the
+	// object.
+	CompleteMultipartUpload(ctx context.Context, upload *MultipartUpload, parts []CompletedPart) (*pb.UploadObjectResponse, error)
+
+	// AbortMultipartUpload discards the upload and its parts.
+	AbortMultipartUpload(ctx context.Context, upload *MultipartUpload) error
+}
+
+// ObjectDeletingStorageProvider is an OPTIONAL capability sub-interface for
+// deleting single objects, e.g. the files of attachments whose parent record was
+// purged. There is no proto contract for it yet, so it takes the logical container
+// and key, resolved against the request's workspace like UploadObject.
+type ObjectDeletingStorageProvider interface {
+	StorageProvider
+
+	// DeleteObject removes the object. Deleting an object that does not exist is
+	// not an error, so a retried cleanup succeeds.
+	DeleteObject(ctx context.Context, containerName, objectKey string) error
+}
+
 // StorageError represents storage-related errors
 type StorageError struct {
 	Code    string
@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"context"
+	"time"
+)
+
+// InboxNotification is a notification kept in a user's in-app inbox, the
+// list behind the web app's bell icon.
+type InboxNotification struct {
+	ID          string
+	UserID      string
+	WorkspaceID string
+	Template    string
+	Subject     string
+	Body        string
+	// Data carries extras for the web app, e.g. the link to open.
+	Data      map[string]string
+	Read      bool
+	CreatedAt time.Time
+	ReadAt    time.Time
+}
+
+// NotificationInboxFilter narrows an inbox listing. The zero value lists the newest
+// notifications, read or not.
+type NotificationInboxFilter struct {
+	UnreadOnly  bool
+	Template    string
+	WorkspaceID string
+	// Page is 1-based; Limit defaults to 20 and is capped at 100.
+	Page  int
+	Limit int
+}
+
+// NotificationInboxPage is one page of an inbox listing.
+type NotificationInboxPage struct {
+	Notifications []*InboxNotification
+	// Total counts the notifications matching the filter on all pages.
+	Total int
+}
+
+// NotificationInbox stores in-app notifications per user.
+type NotificationInbox interface {
+	// AddInboxNotification stores a new, unread notification.
+	AddInboxNotification(ctx context.Context, n *InboxNotification) error
+
+	// ListInboxNotifications returns the user's notifications, newest first.
+	ListInboxNotifications(ctx context.Context, userID string, filter NotificationInboxFilter) (*NotificationInboxPage, error)
+
+	// CountUnreadNotifications returns how many of the user's notifications
+	// are unread.
+	CountUnreadNotifications(ctx context.Context, userID string) (int, error)
+
+	// MarkNotificationsRead marks the given notifications of the user read.
+	// IDs of other users' notifications are ignored.
+	MarkNotificationsRead(ctx context.Context, userID string, ids []string) error
+
+	// MarkAllNotificationsRead marks every notification of the user read.
+	MarkAllNotificationsRead(ctx context.Context, userID string) error
+}
@@ -0,0 +1,116 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMessagingSignature is returned by MessagingProvider.ParseStatusWebhook
+// when a status callback does not carry the provider's valid signature.
+var ErrMessagingSignature = errors.New("messaging webhook signature is invalid")
+
+// MessageStatus is the delivery state of a text message, normalized across
+// providers.
+type MessageStatus string
+
+// Message delivery states, in the order a message normally goes through
+// them. Failed and undelivered are final.
+const (
+	MessageStatusQueued      MessageStatus = "queued"
+	MessageStatusSent        MessageStatus = "sent"
+	MessageStatusDelivered   MessageStatus = "delivered"
+	MessageStatusRead        MessageStatus = "read"
+	MessageStatusUndelivered MessageStatus = "undelivered"
+	MessageStatusFailed      MessageStatus = "failed"
+)
+
+// TextMessage is one SMS or WhatsApp message to one phone number.
+type TextMessage struct {
+	Channel NotificationChannel
+	// To is the recipient's phone number in E.164 form.
+	To   string
+	Body string
+	// Template names the notification template. Providers that keep
+	// pre-approved templates (required by WhatsApp outside a conversation)
+	// send the one configured for it instead of Body, filled with
+	// Variables.
+	Template  string
+	Variables map[string]string
+}
+
+// SentMessage is a message the provider accepted.
+type SentMessage struct {
+	// ID is the provider's message ID, which status callbacks refer to.
+	ID     string
+	Status MessageStatus
+}
+
+// MessageStatusWebhook is a raw delivery status callback from the provider.
+type MessageStatusWebhook struct {
+	Headers map[string]string
+	Body    []byte
+}
+
+// MessageStatusUpdate is a parsed delivery status callback.
+type MessageStatusUpdate struct {
+	MessageID    string
+	Status       MessageStatus
+	ErrorCode    string
+	ErrorMessage string
+	OccurredAt   time.Time
+}
+
+// MessagingProvider defines the contract for text messaging services such as
+// Twilio, delivering on the SMS and WhatsApp channels.
+type MessagingProvider interface {
+	// Name returns the name of the messaging provider (e.g., "twilio")
+	Name() string
+
+	// Channels returns the channels this provider is configured to send on
+	Channels() []NotificationChannel
+
+	// SendMessage sends a message on msg.Channel.
+	SendMessage(ctx context.Context, msg *TextMessage) (*SentMessage, error)
+
+	// ParseStatusWebhook verifies and parses a delivery status callback. It
+	// returns an error wrapping ErrMessagingSignature for an unsigned or
+	// forged request.
+	ParseStatusWebhook(ctx context.Context, req *MessageStatusWebhook) (*MessageStatusUpdate, error)
+
+	// IsEnabled returns whether this provider is currently enabled
+	IsEnabled() bool
+
+	// Close cleans up messaging provider resources
+	Close() error
+}
+
+// NotificationLogEntry records one message sent to a user and its latest
+// delivery state.
+type NotificationLogEntry struct {
+	ID                string
+	Provider          string
+	ProviderMessageID string
+	Channel           NotificationChannel
+	WorkspaceID       string
+	UserID            string
+	Template          string
+	Recipient         string
+	Status            MessageStatus
+	ErrorCode         string
+	ErrorMessage      string
+	SentAt            time.Time
+	UpdatedAt         time.Time
+}
+
+// NotificationLog keeps the messages sent through messaging providers and
+// their delivery states.
+type NotificationLog interface {
+	// RecordMessage stores a message the provider accepted.
+	RecordMessage(ctx context.Context, entry *NotificationLogEntry) error
+
+	// UpdateMessageStatus applies a status callback to the provider's
+	// message. An update older than the recorded state (callbacks arrive out
+	// of order) is ignored.
+	UpdateMessageStatus(ctx context.Context, provider string, update *MessageStatusUpdate) error
+}
@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"time"
+)
+
+// NotificationChannel is a delivery channel for notifications.
+type NotificationChannel string
+
+// Supported notification channels.
+const (
+	NotificationChannelEmail NotificationChannel = "email"
+	// NotificationChannelPush delivers to the user's registered mobile
+	// devices. Its recipient is the user ID.
+	NotificationChannelPush NotificationChannel = "push"
+	// NotificationChannelInApp stores the notification in the user's
+	// in-app inbox. Its recipient is the user ID.
+	NotificationChannelInApp NotificationChannel = "in_app"
+	// NotificationChannelSMS and NotificationChannelWhatsApp deliver text
+	// messages through a MessagingProvider. Their recipient is a phone
+	// number in E.164 form, e.g. +15551234567.
+	NotificationChannelSMS      NotificationChannel = "sms"
+	NotificationChannelWhatsApp NotificationChannel = "whatsapp"
+	// NotificationChannelNone is a preference only: the user receives no
+	// notifications of the template.
+	NotificationChannelNone NotificationChannel = "none"
+)
+
+// Notification is one event to tell a user about, e.g. a new conversation
+// post or a workflow assignment. Template identifies the kind of event and
+// selects its batching rule.
+type Notification struct {
+	WorkspaceID string
+	UserID      string
+	Channel     NotificationChannel
+	Template    string
+	// Recipient is the channel address, e.g. an email address.
+	Recipient string
+	Subject   string
+	TextBody  string
+	HTMLBody  string
+	// Data carries channel-specific extras, e.g. a push deep link.
+	Data       map[string]string
+	OccurredAt time.Time
+}
+
+// NotificationMessage is what a sender delivers: a single notification or
+// a digest of several.
+type NotificationMessage struct {
+	// UserID, WorkspaceID and Template are those of the notification, or of
+	// the notifications in a digest (Template is empty when they differ).
+	UserID      string
+	WorkspaceID string
+	Template    string
+	Recipient   string
+	Subject     string
+	TextBody    string
+	HTMLBody    string
+	Data        map[string]string
+}
+
+// Notifier accepts notifications for delivery. Implementations may send
+// immediately, hold notifications for a digest, or defer them to respect
+// frequency caps; Notify returning nil means the notification was accepted,
+// not that it was delivered.
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// NotificationSender delivers messages on one channel.
+type NotificationSender interface {
+	Channel() NotificationChannel
+	Send(ctx context.Context, msg *NotificationMessage) error
+}
+
+// NotificationPreference is the channel a user wants a template's
+// notifications on. An empty Template is the user's default for every
+// template without a preference of its own.
+type NotificationPreference struct {
+	UserID   string
+	Template string
+	Channel  NotificationChannel
+}
+
+// NotificationPreferences keeps users' channel preferences.
+type NotificationPreferences interface {
+	// NotificationChannel returns the channel userID wants template's
+	// notifications on, falling back to the user's default; it returns ""
+	// when the user has set neither.
+	NotificationChannel(ctx context.Context, userID, template string) (NotificationChannel, error)
+	// SetNotificationPreference saves a preference, replacing the user's
+	// preference for the same template.
+	SetNotificationPreference(ctx context.Context, pref *NotificationPreference) error
+	// ListNotificationPreferences returns the user's preferences.
+	ListNotificationPreferences(ctx context.Context, userID string) ([]*NotificationPreference, error)
+}
@@ -2,6 +2,7 @@ package integration
 
 import (
 	"context"
+	"time"
 
 	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
 )
@@ -53,6 +54,18 @@ type PaymentProvider interface {
 	GetSupportedCurrencies() []string
 }
 
+// PaymentTransactionSearcher is implemented by payment providers that can
+// list their transactions over a time window (PayPal transaction search).
+// It is optional: reconciliation type-asserts the PaymentProvider and falls
+// back to GetPaymentStatus per payment.
+type PaymentTransactionSearcher interface {
+	// SearchTransactions returns the transactions updated in [from, to).
+	// Each carries the provider's status, amount and references; PaymentId
+	// is the payment ID the checkout was created with, when the provider
+	// keeps it.
+	SearchTransactions(ctx context.Context, from, to time.Time) ([]*paymentpb.PaymentTransaction, error)
+}
+
 // PaymentWebhookResult represents the result of processing a payment webhook
 // This is a convenience type for use cases that need to act on webhook results
 type PaymentWebhookResult struct {
@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"context"
+	"time"
+)
+
+// PaymentDisputeStatus is the provider-neutral lifecycle of a dispute or
+// chargeback. Providers map their own states onto these values.
+type PaymentDisputeStatus string
+
+const (
+	// PaymentDisputeOpen — the customer opened a dispute; no seller action
+	// is requested yet.
+	PaymentDisputeOpen PaymentDisputeStatus = "open"
+	// PaymentDisputeEvidenceRequired — the provider is waiting for the
+	// seller to respond, usually before EvidenceDueAt.
+	PaymentDisputeEvidenceRequired PaymentDisputeStatus = "evidence_required"
+	// PaymentDisputeUnderReview — evidence is in and the provider is deciding.
+	PaymentDisputeUnderReview PaymentDisputeStatus = "under_review"
+	// PaymentDisputeWon — resolved in the seller's favour.
+	PaymentDisputeWon PaymentDisputeStatus = "won"
+	// PaymentDisputeLost — resolved in the customer's favour; funds reversed.
+	PaymentDisputeLost PaymentDisputeStatus = "lost"
+	// PaymentDisputeClosed — resolved with no clear winner (withdrawn,
+	// settled, or an outcome the provider did not report).
+	PaymentDisputeClosed PaymentDisputeStatus = "closed"
+)
+
+// Resolved reports whether the dispute has reached a final state.
+func (s PaymentDisputeStatus) Resolved() bool {
+	return s == PaymentDisputeWon || s == PaymentDisputeLost || s == PaymentDisputeClosed
+}
+
+// PaymentDispute is one dispute as last reported by the provider.
+type PaymentDispute struct {
+	// ID is the internal dispute ID. Repositories assign it on first upsert.
+	ID string
+
+	ProviderID         string
+	ProviderDisputeRef string
+	// ProviderPaymentRef is the provider's transaction/capture reference.
+	ProviderPaymentRef string
+
+	// PaymentID, SubscriptionID and InvoiceID link the dispute back to the
+	// records the checkout was created for. Any of them may be empty.
+	PaymentID      string
+	SubscriptionID string
+	InvoiceID      string
+
+	Status PaymentDisputeStatus
+	// ProviderStatus is the provider's own status string, kept for support.
+	ProviderStatus string
+	Reason         string
+	// Stage is the provider's dispute stage (inquiry, chargeback, ...).
+	Stage string
+
+	// Amount is in minor units, like PaymentTransaction.Amount.
+	Amount   int64
+	Currency string
+
+	EvidenceDueAt time.Time
+	OpenedAt      time.Time
+	UpdatedAt     time.Time
+
+	RawData map[string]string
+}
+
+// PaymentDisputeEvidence is the seller's response to a dispute.
+type PaymentDisputeEvidence struct {
+	// Type is a provider evidence type (e.g. PayPal's PROOF_OF_FULFILLMENT).
+	// Providers fall back to a generic type when empty.
+	Type           string
+	Notes          string
+	Carrier        string
+	TrackingNumber string
+	Documents      []PaymentDisputeDocument
+}
+
+// PaymentDisputeDocument is a file attached to dispute evidence.
+type PaymentDisputeDocument struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// DisputeProvider is implemented by payment providers that send dispute
+// webhooks. It is optional: use cases type-assert the PaymentProvider.
+type DisputeProvider interface {
+	// ParseDisputeWebhook returns the dispute carried by a webhook payload.
+	// ok is false when the payload is not a dispute event.
+	ParseDisputeWebhook(ctx context.Context, payload []byte) (dispute *PaymentDispute, ok bool, err error)
+}
+
+// DisputeEvidenceSubmitter is implemented by providers whose API accepts
+// dispute evidence. Providers without such an API do not implement it and
+// evidence must be submitted through their dashboard.
+type DisputeEvidenceSubmitter interface {
+	SubmitDisputeEvidence(ctx context.Context, providerDisputeRef string, evidence *PaymentDisputeEvidence) error
+}
+
+// PaymentDisputeFilter narrows ListDisputes. Zero values match everything.
+type PaymentDisputeFilter struct {
+	ProviderID     string
+	Status         PaymentDisputeStatus
+	SubscriptionID string
+	// Unresolved keeps only disputes that have not reached a final state.
+	Unresolved bool
+	Limit      int
+}
+
+// PaymentDisputeRepository stores disputes and flags the records they touch.
+type PaymentDisputeRepository interface {
+	// UpsertDispute inserts or updates the dispute keyed by provider and
+	// ProviderDisputeRef, and returns the stored dispute with its ID.
+	// previous is the status before this upsert ("" for a new dispute).
+	UpsertDispute(ctx context.Context, dispute *PaymentDispute) (stored *PaymentDispute, previous PaymentDisputeStatus, err error)
+
+	GetDispute(ctx context.Context, id string) (*PaymentDispute, error)
+
+	ListDisputes(ctx context.Context, filter PaymentDisputeFilter) ([]*PaymentDispute, error)
+
+	// FlagDisputedRecords stamps the dispute ID and status on the linked
+	// subscription and invoice so billing screens can surface them.
+	FlagDisputedRecords(ctx context.Context, dispute *PaymentDispute) error
+}
@@ -0,0 +1,79 @@
+package integration
+
+import "strconv"
+
+// Raw-data keys payment providers use to report the provider fee breakdown
+// on PaymentTransaction.RawData (and, by extension, LogWebhookData.RawData).
+// Values are integer minor units (cents/centavos) rendered as strings, the
+// same unit as PaymentTransaction.Amount.
+const (
+	PaymentRawGrossAmount = "gross_amount"
+	PaymentRawFeeAmount   = "fee_amount"
+	PaymentRawNetAmount   = "net_amount"
+	PaymentRawFeeCurrency = "fee_currency"
+)
+
+// PaymentFees is the gross/fee/net split of one captured payment.
+type PaymentFees struct {
+	Gross    int64
+	Fee      int64
+	Net      int64
+	Currency string
+	// Reported is true when the provider supplied a fee breakdown. When
+	// false, Fee is zero and Net equals Gross.
+	Reported bool
+}
+
+// PaymentFeesFromRawData reads the fee breakdown a provider stored in raw
+// data. amount is the transaction amount, used as gross when the provider
+// did not report one. Missing pieces are derived: net = gross - fee, or
+// fee = gross - net when only net was reported.
+func PaymentFeesFromRawData(amount int64, currency string, raw map[string]string) PaymentFees {
+	fees := PaymentFees{Gross: amount, Net: amount, Currency: currency}
+	if raw == nil {
+		return fees
+	}
+	gross, hasGross := parseMinorUnits(raw[PaymentRawGrossAmount])
+	fee, hasFee := parseMinorUnits(raw[PaymentRawFeeAmount])
+	net, hasNet := parseMinorUnits(raw[PaymentRawNetAmount])
+	if hasGross {
+		fees.Gross = gross
+	}
+	switch {
+	case hasFee && hasNet:
+		fees.Fee, fees.Net = fee, net
+	case hasFee:
+		fees.Fee, fees.Net = fee, fees.Gross-fee
+	case hasNet:
+		fees.Fee, fees.Net = fees.Gross-net, net
+	default:
+		fees.Net = fees.Gross
+		return fees
+	}
+	fees.Reported = true
+	if c := raw[PaymentRawFeeCurrency]; c != "" {
+		fees.Currency = c
+	}
+	return fees
+}
+
+// SetRawData writes the breakdown into raw using the shared keys.
+func (f PaymentFees) SetRawData(raw map[string]string) {
+	if raw == nil {
+		return
+	}
+	raw[PaymentRawGrossAmount] = strconv.FormatInt(f.Gross, 10)
+	raw[PaymentRawFeeAmount] = strconv.FormatInt(f.Fee, 10)
+	raw[PaymentRawNetAmount] = strconv.FormatInt(f.Net, 10)
+	if f.Currency != "" {
+		raw[PaymentRawFeeCurrency] = f.Currency
+	}
+}
+
+func parseMinorUnits(v string) (int64, bool) {
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	return n, err == nil
+}
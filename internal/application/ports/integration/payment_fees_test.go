@@ -0,0 +1,38 @@
+package integration
+
+import "testing"
+
+func TestPaymentFeesFromRawData(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  map[string]string
+		want PaymentFees
+	}{
+		{"no raw data", nil, PaymentFees{Gross: 1000, Net: 1000, Currency: "USD"}},
+		{"no breakdown", map[string]string{"event_id": "x"}, PaymentFees{Gross: 1000, Net: 1000, Currency: "USD"}},
+		{"fee only", map[string]string{PaymentRawFeeAmount: "59"}, PaymentFees{Gross: 1000, Fee: 59, Net: 941, Currency: "USD", Reported: true}},
+		{"net only", map[string]string{PaymentRawNetAmount: "941"}, PaymentFees{Gross: 1000, Fee: 59, Net: 941, Currency: "USD", Reported: true}},
+		{
+			"full breakdown overrides amount",
+			map[string]string{PaymentRawGrossAmount: "2000", PaymentRawFeeAmount: "88", PaymentRawNetAmount: "1912", PaymentRawFeeCurrency: "PHP"},
+			PaymentFees{Gross: 2000, Fee: 88, Net: 1912, Currency: "PHP", Reported: true},
+		},
+		{"unparseable fee ignored", map[string]string{PaymentRawFeeAmount: "0.59"}, PaymentFees{Gross: 1000, Net: 1000, Currency: "USD"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PaymentFeesFromRawData(1000, "USD", tt.raw); got != tt.want {
+				t.Errorf("PaymentFeesFromRawData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaymentFeesSetRawDataRoundTrip(t *testing.T) {
+	in := PaymentFees{Gross: 1500, Fee: 75, Net: 1425, Currency: "EUR", Reported: true}
+	raw := map[string]string{}
+	in.SetRawData(raw)
+	if got := PaymentFeesFromRawData(0, "", raw); got != in {
+		t.Errorf("round trip = %+v, want %+v", got, in)
+	}
+}
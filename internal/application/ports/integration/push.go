@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// PushPlatform is the operating system a device token belongs to.
+type PushPlatform string
+
+// Supported push platforms.
+const (
+	PushPlatformAndroid PushPlatform = "android"
+	PushPlatformIOS     PushPlatform = "ios"
+)
+
+// ErrPushTokenInvalid is returned by PushProvider.SendPush when the device
+// token is no longer valid (app uninstalled, token rotated). Callers should
+// forget the token.
+var ErrPushTokenInvalid = errors.New("push token is no longer valid")
+
+// PushMessage is one notification for one device.
+type PushMessage struct {
+	Token    string
+	Platform PushPlatform
+	Title    string
+	Body     string
+	// Data is delivered to the app alongside the alert, e.g. a deep link.
+	Data map[string]string
+	// Badge sets the app icon badge on iOS. Zero leaves it unchanged.
+	Badge int
+}
+
+// PushProvider defines the contract for mobile push services such as
+// Firebase Cloud Messaging and Apple Push Notification service.
+type PushProvider interface {
+	// Name returns the name of the push provider (e.g., "google_fcm", "apns")
+	Name() string
+
+	// Platforms returns the device platforms this provider delivers to
+	Platforms() []PushPlatform
+
+	// SendPush delivers a message to one device. It returns an error
+	// wrapping ErrPushTokenInvalid when the token should be discarded.
+	SendPush(ctx context.Context, msg *PushMessage) error
+
+	// IsEnabled returns whether this provider is currently enabled
+	IsEnabled() bool
+
+	// Close cleans up push provider resources
+	Close() error
+}
+
+// DeviceToken is a device registered by a user to receive push
+// notifications.
+type DeviceToken struct {
+	Token        string
+	UserID       string
+	WorkspaceID  string
+	Platform     PushPlatform
+	RegisteredAt time.Time
+}
+
+// DeviceTokenStore keeps the device tokens of each user.
+type DeviceTokenStore interface {
+	// RegisterDeviceToken saves the token, moving it to the given user if
+	// another user registered it before (shared or resold devices).
+	RegisterDeviceToken(ctx context.Context, token *DeviceToken) error
+
+	// UnregisterDeviceToken forgets the token. Unknown tokens are not an
+	// error.
+	UnregisterDeviceToken(ctx context.Context, token string) error
+
+	// ListDeviceTokens returns the tokens registered by the user.
+	ListDeviceTokens(ctx context.Context, userID string) ([]*DeviceToken, error)
+}
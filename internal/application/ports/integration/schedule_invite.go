@@ -0,0 +1,22 @@
+package integration
+
+import (
+	"context"
+
+	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
+)
+
+// ScheduleInviteSender emails calendar invites (.ics) to the invitees of
+// schedules, keeping their calendars in sync without the scheduler
+// provider's own emails.
+type ScheduleInviteSender interface {
+	// SendScheduleInvite sends an invite (METHOD:REQUEST) for a booked or
+	// changed schedule. previousScheduleID names the schedule it replaces
+	// when the provider gives a rescheduled booking a new ID, so the
+	// invitee's calendar moves the existing entry instead of adding one.
+	SendScheduleInvite(ctx context.Context, schedule *schedulerpb.Schedule, previousScheduleID string) error
+
+	// SendScheduleCancellation sends a cancellation (METHOD:CANCEL) for a
+	// schedule an invite was sent for. Other schedules are ignored.
+	SendScheduleCancellation(ctx context.Context, scheduleID string, reason string) error
+}
@@ -2,6 +2,7 @@ package integration
 
 import (
 	"context"
+	"time"
 
 	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
 )
@@ -55,6 +56,58 @@ type SchedulerProvider interface {
 	GetCapabilities() []schedulerpb.SchedulerCapability
 }
 
+// ScheduleInviteeLister is implemented by scheduler providers that can list
+// the invitees of an existing schedule. ListSchedules does not carry
+// invitees, so the history importer uses this to match them to clients.
+type ScheduleInviteeLister interface {
+	// ListScheduleInvitees returns every invitee of the schedule with the
+	// given provider schedule ID, including canceled ones
+	ListScheduleInvitees(ctx context.Context, providerScheduleID string) ([]*schedulerpb.InviteeInfo, error)
+}
+
+// SchedulerWebhookState is the state of a provider webhook subscription.
+type SchedulerWebhookState string
+
+// Webhook subscription states. A disabled subscription stopped receiving
+// events, e.g. after the provider gave up retrying failed deliveries.
+const (
+	SchedulerWebhookActive   SchedulerWebhookState = "active"
+	SchedulerWebhookDisabled SchedulerWebhookState = "disabled"
+	SchedulerWebhookMissing  SchedulerWebhookState = "missing"
+)
+
+// SchedulerWebhookSubscription describes the subscription delivering the
+// provider's booking events to the application's webhook.
+type SchedulerWebhookSubscription struct {
+	// ID is the provider's identifier of the subscription
+	ID          string
+	CallbackURL string
+	Events      []string
+	State       SchedulerWebhookState
+	CreatedAt   time.Time
+	// SigningKey is set only when registering or rotating generated a new
+	// key; it must be stored (e.g. as CALENDLY_WEBHOOK_SECRET) for the
+	// webhook signatures to verify after a restart.
+	SigningKey string
+}
+
+// SchedulerWebhookManager is implemented by scheduler providers that can
+// manage their webhook subscription, so deployments need no manual setup.
+type SchedulerWebhookManager interface {
+	// EnsureWebhookSubscription registers callbackURL (the configured URL
+	// when empty) for the provider's booking events, or verifies the
+	// existing subscription and recreates it when it is disabled or
+	// misses events.
+	EnsureWebhookSubscription(ctx context.Context, callbackURL string) (*SchedulerWebhookSubscription, error)
+	// RotateWebhookSecret replaces the subscription's signing key and
+	// returns the subscription with the new key.
+	RotateWebhookSecret(ctx context.Context) (*SchedulerWebhookSubscription, error)
+	// WebhookSubscriptionStatus returns the subscription for the configured
+	// callback URL, in state missing when none is registered. It returns
+	// nil when no callback URL is configured.
+	WebhookSubscriptionStatus(ctx context.Context) (*SchedulerWebhookSubscription, error)
+}
+
 // ScheduleWebhookResult represents the result of processing a scheduler webhook
 // This is a convenience type for use cases that need to act on webhook results
 type ScheduleWebhookResult struct {
@@ -152,6 +152,17 @@ type SpreadsheetExtensions interface {
 	RenameSheet(ctx context.Context, sourceId string, oldName string, newName string) error
 }
 
+// TabularRecordStreamer is implemented by providers that can read a large
+// table in chunks instead of loading it whole. Check for it by type
+// assertion like SpreadsheetExtensions.
+type TabularRecordStreamer interface {
+	// StreamRecords reads every row of a table in order, chunkRows at a
+	// time (the provider's default when 0), and calls fn with each chunk.
+	// It stops when the rows run out or fn returns an error, which it
+	// returns.
+	StreamRecords(ctx context.Context, sourceID, table string, chunkRows int, fn func([]*tabularpb.Record) error) error
+}
+
 // ==========================================================================
 // Helper Types
 // ==========================================================================
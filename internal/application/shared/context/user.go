@@ -64,10 +64,12 @@ const (
 // instead, and these individual writers will be dead code (deletable).
 
 func WithUserID(ctx context.Context, userID string) context.Context {
+	identity.NoteUserID(ctx, userID)
 	return context.WithValue(ctx, keyUserID, userID)
 }
 
 func WithWorkspaceID(ctx context.Context, wsID string) context.Context {
+	identity.NoteWorkspaceID(ctx, wsID)
 	return context.WithValue(ctx, keyWorkspaceID, wsID)
 }
 
@@ -0,0 +1,178 @@
+// Package domainevent carries the business events use cases announce after
+// a successful write, such as client.created or invoice.paid, to whatever
+// delivers them outside the process (the workspace webhook dispatcher) or
+// acts on them (the custom claims syncer).
+//
+// Use cases call Emit once their write has committed. Emit passes the event
+// to the Sink installed with SetSink, which must not block; without a Sink
+// it does nothing, so emitting costs nothing in deployments that do not
+// deliver events.
+//
+// Charter: stamps and forwards events only. MUST NOT import proto entity
+// types, DB drivers, adapter packages or anything under usecases/; the
+// payload is whatever the use case hands over. Consumers: entity/client,
+// entity/workspace_user_role, subscription/subscription,
+// subscription/invoice, revenue/revenue, workflow/workflow, workflow/stage,
+// integration/payment, integration/scheduler, and the orchestration engine.
+package domainevent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+)
+
+// Event types emitted by the use cases. The names are part of the webhook
+// contract: receivers subscribe to them, so they are never renamed.
+const (
+	ClientCreated       = "client.created"
+	ClientUpdated       = "client.updated"
+	ClientDeleted       = "client.deleted"
+	SubscriptionCreated = "subscription.created"
+	InvoiceCreated      = "invoice.created"
+	InvoicePaid         = "invoice.paid"
+	InvoiceOverdue      = "invoice.overdue"
+	ScheduleCreated     = "schedule.created"
+	ScheduleCancelled   = "schedule.cancelled"
+	WorkflowCreated     = "workflow.created"
+	StageCompleted      = "stage.completed"
+
+	WorkspaceUserRoleCreated = "workspace_user_role.created"
+	WorkspaceUserRoleUpdated = "workspace_user_role.updated"
+	WorkspaceUserRoleDeleted = "workspace_user_role.deleted"
+)
+
+// Types lists every event type, for validating subscriptions.
+var Types = []string{
+	ClientCreated, ClientUpdated, ClientDeleted,
+	SubscriptionCreated,
+	InvoiceCreated, InvoicePaid, InvoiceOverdue,
+	ScheduleCreated, ScheduleCancelled,
+	WorkflowCreated, StageCompleted,
+	WorkspaceUserRoleCreated, WorkspaceUserRoleUpdated, WorkspaceUserRoleDeleted,
+}
+
+// Event is one business event.
+type Event struct {
+	// ID is unique per event; receivers use it to drop redeliveries.
+	ID          string
+	Type        string
+	WorkspaceID string
+	ActorID     string
+	OccurredAt  time.Time
+	// Data is the entity the event is about, usually its proto message.
+	Data any
+}
+
+// Sink receives emitted events. PublishDomainEvent is called on the use
+// case's goroutine and must return quickly.
+type Sink interface {
+	PublishDomainEvent(Event)
+}
+
+// sinks maps names to installed sinks. It is replaced, never modified, so
+// Emit reads it without locking.
+var (
+	sinksMu sync.Mutex
+	sinks   atomic.Pointer[map[string]Sink]
+)
+
+// SetSink installs sink under name ("webhook", "events"), replacing the
+// sink installed under that name before; nil removes it. Every installed
+// sink receives every event.
+func SetSink(name string, sink Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+
+	next := make(map[string]Sink)
+	if current := sinks.Load(); current != nil {
+		for n, s := range *current {
+			next[n] = s
+		}
+	}
+	if sink == nil {
+		delete(next, name)
+	} else {
+		next[name] = sink
+	}
+	if len(next) == 0 {
+		sinks.Store(nil)
+		return
+	}
+	sinks.Store(&next)
+}
+
+// Enabled reports whether a sink is installed, so callers can skip
+// building a payload nobody receives.
+func Enabled() bool {
+	return sinks.Load() != nil
+}
+
+// Emit announces an event of eventType about data. The workspace and actor
+// come from ctx. Call it after the write has committed, never inside the
+// transaction: a rolled-back write must not be announced.
+func Emit(ctx context.Context, eventType string, data any) {
+	current := sinks.Load()
+	if current == nil {
+		return
+	}
+	e := Event{
+		ID:          newID(),
+		Type:        eventType,
+		WorkspaceID: contextutil.ExtractWorkspaceIDFromContext(ctx),
+		ActorID:     contextutil.ExtractUserIDFromContext(ctx),
+		OccurredAt:  time.Now().UTC(),
+		Data:        data,
+	}
+	for _, sink := range *current {
+		sink.PublishDomainEvent(e)
+	}
+}
+
+// envelope is the JSON form of an Event.
+type envelope struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	WorkspaceID string          `json:"workspace_id"`
+	ActorID     string          `json:"actor_id,omitempty"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// Marshal encodes e as the JSON body webhook receivers and event bus
+// subscribers get. Proto payloads use their proto field names.
+func Marshal(e Event) ([]byte, error) {
+	var data []byte
+	var err error
+	if msg, ok := e.Data.(proto.Message); ok {
+		data, err = protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	} else {
+		data, err = json.Marshal(e.Data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope{
+		ID:          e.ID,
+		Type:        e.Type,
+		WorkspaceID: e.WorkspaceID,
+		ActorID:     e.ActorID,
+		OccurredAt:  e.OccurredAt,
+		Data:        data,
+	})
+}
+
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return "evt_" + hex.EncodeToString(b[:])
+}
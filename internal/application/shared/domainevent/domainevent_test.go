@@ -0,0 +1,79 @@
+package domainevent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+)
+
+type recorder struct{ events []Event }
+
+func (r *recorder) PublishDomainEvent(e Event) { r.events = append(r.events, e) }
+
+func TestEmit(t *testing.T) {
+	ctx := contextutil.WithWorkspaceID(contextutil.WithUserID(context.Background(), "u-1"), "ws-1")
+
+	// Without a sink nothing happens.
+	Emit(ctx, ClientCreated, nil)
+
+	r := &recorder{}
+	SetSink("test", r)
+	defer SetSink("test", nil)
+	if !Enabled() {
+		t.Fatal("sink not installed")
+	}
+	Emit(ctx, ClientCreated, "payload")
+	Emit(ctx, ClientCreated, "payload")
+
+	if len(r.events) != 2 {
+		t.Fatalf("events = %+v", r.events)
+	}
+	e := r.events[0]
+	if e.Type != ClientCreated || e.WorkspaceID != "ws-1" || e.ActorID != "u-1" || e.Data != "payload" || e.OccurredAt.IsZero() {
+		t.Errorf("event = %+v", e)
+	}
+	if !strings.HasPrefix(e.ID, "evt_") || e.ID == r.events[1].ID {
+		t.Errorf("ids = %s, %s", e.ID, r.events[1].ID)
+	}
+}
+
+func TestSetSink_Named(t *testing.T) {
+	first, second := &recorder{}, &recorder{}
+	SetSink("first", first)
+	SetSink("second", second)
+	Emit(context.Background(), InvoicePaid, nil)
+
+	SetSink("first", nil)
+	Emit(context.Background(), InvoicePaid, nil)
+	if len(first.events) != 1 || len(second.events) != 2 {
+		t.Errorf("first = %d, second = %d events", len(first.events), len(second.events))
+	}
+	if first.events[0].ID != second.events[0].ID {
+		t.Error("sinks got different events")
+	}
+
+	SetSink("second", nil)
+	if Enabled() {
+		t.Error("enabled without sinks")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	raw, err := Marshal(Event{
+		ID:          "evt_1",
+		Type:        ClientCreated,
+		WorkspaceID: "ws-1",
+		OccurredAt:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Data:        map[string]string{"id": "client-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"id":"evt_1","type":"client.created","workspace_id":"ws-1","occurred_at":"2026-01-02T03:04:05Z","data":{"id":"client-1"}}`
+	if string(raw) != want {
+		t.Errorf("got  %s\nwant %s", raw, want)
+	}
+}
@@ -0,0 +1,21 @@
+package testutil
+
+import (
+	"context"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+)
+
+// disabledAuthorizer short-circuits the action gate (IsEnabled=false).
+type disabledAuthorizer struct{}
+
+func (disabledAuthorizer) HasPermission(context.Context, string, string) (bool, error) {
+	return true, nil
+}
+func (disabledAuthorizer) IsEnabled() bool { return false }
+
+// OpenActionGate returns an action gatekeeper that allows every action, for
+// tests of handlers and use cases whose subject is not authorization.
+func OpenActionGate() *actiongate.ActionGatekeeper {
+	return actiongate.NewActionGatekeeper(disabledAuthorizer{}, nil)
+}
@@ -119,21 +119,20 @@ func (uc *CreateAttachmentUseCase) executeCore(ctx context.Context, req *attachm
 //	(a) content-type allow-list resolved by module_key (DEFAULT-DENY: an
 //	    unregistered module_key rejects everything; the persisted content_type — which
 //	    the hybra handler derives from a magic-byte sniff, never the raw client
-//	    header — must be on the module's allow-list);
+//	    header — must be on the module's allow-list) and, when the row records
+//	    file_size_bytes, the module's MaxFileSizeBytes (see assertFilePolicy);
 //	(b) per-record MaxFileCount: count existing ACTIVE attachments for
 //	    (module_key, foreign_key) and reject when the count has reached the cap.
 //
-// Both checks fail with a translated error. This is the authoritative backstop; the
+// Every check fails with a translated error. This is the authoritative backstop; the
 // hybra upload handler may additionally short-circuit earlier for nicer UX.
 func (uc *CreateAttachmentUseCase) assertUploadPolicy(ctx context.Context, data *attachmentpb.Attachment) error {
 	moduleKey := data.GetModuleKey()
 	policy := policyFor(moduleKey)
 
-	// (a) content-type allow-list (default-deny).
-	if !policy.allowsContentType(data.GetContentType()) {
-		return errors.New(contextutil.GetTranslatedMessageWithContext(ctx, uc.services.Translator,
-			"attachment.validation.content_type_not_allowed",
-			"This file type is not permitted for this record [DEFAULT]"))
+	// (a) content-type allow-list (default-deny) and size cap.
+	if err := assertFilePolicy(ctx, uc.services.Translator, policy, data); err != nil {
+		return err
 	}
 
 	// (b) per-record count cap. 0 means "no cap enforced".
@@ -155,6 +154,24 @@ func (uc *CreateAttachmentUseCase) assertUploadPolicy(ctx context.Context, data
 	return nil
 }
 
+// assertFilePolicy checks the file an attachment row describes against policy:
+// its content type must be on the allow-list and its file_size_bytes, when
+// recorded, within MaxFileSizeBytes. Update runs it too, so a row cannot be edited
+// past the limits Create enforced.
+func assertFilePolicy(ctx context.Context, translator ports.Translator, policy Policy, data *attachmentpb.Attachment) error {
+	if !policy.allowsContentType(data.GetContentType()) {
+		return errors.New(contextutil.GetTranslatedMessageWithContext(ctx, translator,
+			"attachment.validation.content_type_not_allowed",
+			"This file type is not permitted for this record [DEFAULT]"))
+	}
+	if data.FileSizeBytes != nil && !policy.allowsFileSize(data.GetFileSizeBytes()) {
+		return errors.New(contextutil.GetTranslatedMessageWithContext(ctx, translator,
+			"attachment.validation.file_too_large",
+			"This file is larger than allowed for this record [DEFAULT]"))
+	}
+	return nil
+}
+
 // countActiveAttachments counts ACTIVE attachment rows for (moduleKey, foreignKey),
 // scoped to the caller's workspace when one is present in context (mirrors
 // list_attachments_by_entity's workspace backstop). Active-ness is filtered in Go
@@ -37,6 +37,11 @@ type Policy struct {
 	// MaxFileCount is the maximum number of ACTIVE attachments allowed on a single
 	// parent record (module_key, foreign_key). 0 means "no count cap enforced".
 	MaxFileCount int
+
+	// MaxFileSizeBytes caps the file_size_bytes recorded on the attachment row,
+	// which the upload handler takes from the bytes actually stored. 0 means "no
+	// size cap enforced".
+	MaxFileSizeBytes int64
 }
 
 // allowsContentType reports whether the (normalized) content type is on the
@@ -57,6 +62,15 @@ func (p Policy) allowsContentType(contentType string) bool {
 	return false
 }
 
+// allowsFileSize reports whether a file of size bytes fits the module's cap. A
+// negative size is never valid.
+func (p Policy) allowsFileSize(size int64) bool {
+	if size < 0 {
+		return false
+	}
+	return p.MaxFileSizeBytes == 0 || size <= p.MaxFileSizeBytes
+}
+
 // normalizeContentType lower-cases a MIME type and strips any parameters
 // (e.g. "text/plain; charset=utf-8" -> "text/plain").
 func normalizeContentType(ct string) string {
@@ -96,19 +110,22 @@ var imagesOnlyContentTypes = []string{
 
 // commonSafePolicy is documents + images with a 20-file per-record cap (W4: the
 // hybra builders left MaxFileCount at 0 with a "cap per module in W4" TODO; this
-// is the espyna-authoritative value).
+// is the espyna-authoritative value) and 25 MiB per file.
 func commonSafePolicy() Policy {
 	return Policy{
 		AllowedContentTypes: commonSafeContentTypes,
 		MaxFileCount:        20,
+		MaxFileSizeBytes:    25 << 20,
 	}
 }
 
-// imagesOnlyPolicy restricts a surface to images with a 10-file per-record cap.
+// imagesOnlyPolicy restricts a surface to images with a 10-file per-record cap
+// and 10 MiB per file.
 func imagesOnlyPolicy() Policy {
 	return Policy{
 		AllowedContentTypes: imagesOnlyContentTypes,
 		MaxFileCount:        10,
+		MaxFileSizeBytes:    10 << 20,
 	}
 }
 
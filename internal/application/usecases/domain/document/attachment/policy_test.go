@@ -0,0 +1,88 @@
+package attachment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+	attachmentpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/document/attachment"
+)
+
+// memAttachments stores attachment rows by ID.
+type memAttachments struct {
+	attachmentpb.AttachmentDomainServiceServer
+	rows    map[string]*attachmentpb.Attachment
+	updates int
+}
+
+func (m *memAttachments) ReadAttachment(_ context.Context, req *attachmentpb.ReadAttachmentRequest) (*attachmentpb.ReadAttachmentResponse, error) {
+	resp := &attachmentpb.ReadAttachmentResponse{Success: true}
+	if row, ok := m.rows[req.GetData().GetId()]; ok {
+		resp.Data = []*attachmentpb.Attachment{row}
+	}
+	return resp, nil
+}
+
+func (m *memAttachments) UpdateAttachment(_ context.Context, req *attachmentpb.UpdateAttachmentRequest) (*attachmentpb.UpdateAttachmentResponse, error) {
+	m.updates++
+	return &attachmentpb.UpdateAttachmentResponse{Success: true, Data: []*attachmentpb.Attachment{req.Data}}, nil
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestAssertFilePolicy(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name   string
+		module string
+		data   *attachmentpb.Attachment
+		ok     bool
+	}{
+		{"pdf", "client", &attachmentpb.Attachment{ContentType: ptr("application/pdf"), FileSizeBytes: ptr[int64](1 << 20)}, true},
+		{"size not recorded", "client", &attachmentpb.Attachment{ContentType: ptr("application/pdf")}, true},
+		{"at the cap", "client", &attachmentpb.Attachment{ContentType: ptr("application/pdf"), FileSizeBytes: ptr[int64](25 << 20)}, true},
+		{"over the cap", "client", &attachmentpb.Attachment{ContentType: ptr("application/pdf"), FileSizeBytes: ptr[int64](25<<20 + 1)}, false},
+		{"negative size", "client", &attachmentpb.Attachment{ContentType: ptr("application/pdf"), FileSizeBytes: ptr[int64](-1)}, false},
+		{"image cap", "product", &attachmentpb.Attachment{ContentType: ptr("image/png"), FileSizeBytes: ptr[int64](11 << 20)}, false},
+		{"type not allowed", "product", &attachmentpb.Attachment{ContentType: ptr("application/pdf"), FileSizeBytes: ptr[int64](1)}, false},
+		{"unregistered module", "nope", &attachmentpb.Attachment{ContentType: ptr("application/pdf")}, false},
+	}
+	for _, c := range cases {
+		err := assertFilePolicy(ctx, nil, policyFor(c.module), c.data)
+		if (err == nil) != c.ok {
+			t.Errorf("%s: err = %v", c.name, err)
+		}
+	}
+}
+
+func TestUpdateAttachment_FilePolicy(t *testing.T) {
+	repo := &memAttachments{rows: map[string]*attachmentpb.Attachment{
+		"att-1": {Id: "att-1", ModuleKey: "product", ContentType: ptr("image/png"), FileSizeBytes: ptr[int64](1 << 20)},
+	}}
+	uc := NewUpdateAttachmentUseCase(
+		UpdateAttachmentRepositories{Attachment: repo},
+		UpdateAttachmentServices{ActionGatekeeper: testutil.OpenActionGate()},
+	)
+	ctx := context.Background()
+
+	// The stored module_key decides the policy: a product takes images only.
+	if _, err := uc.Execute(ctx, &attachmentpb.UpdateAttachmentRequest{Data: &attachmentpb.Attachment{Id: "att-1", ContentType: ptr("application/pdf")}}); err == nil {
+		t.Error("content type outside the policy accepted")
+	}
+	if _, err := uc.Execute(ctx, &attachmentpb.UpdateAttachmentRequest{Data: &attachmentpb.Attachment{Id: "att-1", FileSizeBytes: ptr[int64](20 << 20)}}); err == nil {
+		t.Error("size over the cap accepted")
+	}
+	if repo.updates != 0 {
+		t.Fatalf("%d rejected updates reached the repository", repo.updates)
+	}
+
+	if _, err := uc.Execute(ctx, &attachmentpb.UpdateAttachmentRequest{Data: &attachmentpb.Attachment{Id: "att-1", Name: "cover.png"}}); err != nil {
+		t.Errorf("rename: %v", err)
+	}
+	if _, err := uc.Execute(ctx, &attachmentpb.UpdateAttachmentRequest{Data: &attachmentpb.Attachment{Id: "att-1", ContentType: ptr("image/webp")}}); err != nil {
+		t.Errorf("allowed content type: %v", err)
+	}
+	if _, err := uc.Execute(ctx, &attachmentpb.UpdateAttachmentRequest{Data: &attachmentpb.Attachment{Id: "missing", ContentType: ptr("image/png")}}); err == nil {
+		t.Error("missing attachment updated")
+	}
+}
@@ -76,6 +76,14 @@ func (uc *UpdateAttachmentUseCase) executeCore(ctx context.Context, req *attachm
 		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(ctx, uc.services.Translator, "attachment.validation.id_required", "Attachment ID is required [DEFAULT]"))
 	}
 
+	// A changed content type or size is held to the module's upload policy, as on
+	// create. The module_key comes from the stored row when the request omits it.
+	if req.Data.ContentType != nil || req.Data.FileSizeBytes != nil {
+		if err := uc.assertFilePolicy(ctx, req.Data); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set date_modified
 	now := time.Now()
 	req.Data.DateModified = &[]int64{now.UnixMilli()}[0]
@@ -83,3 +91,34 @@ func (uc *UpdateAttachmentUseCase) executeCore(ctx context.Context, req *attachm
 
 	return uc.repositories.Attachment.UpdateAttachment(ctx, req)
 }
+
+// assertFilePolicy checks the updated file fields against the upload policy,
+// filling in what the request leaves unchanged from the stored row.
+func (uc *UpdateAttachmentUseCase) assertFilePolicy(ctx context.Context, data *attachmentpb.Attachment) error {
+	readResp, err := uc.repositories.Attachment.ReadAttachment(ctx, &attachmentpb.ReadAttachmentRequest{
+		Data: &attachmentpb.Attachment{Id: data.Id},
+	})
+	if err != nil {
+		return err
+	}
+	if len(readResp.GetData()) == 0 || readResp.GetData()[0] == nil {
+		return errors.New(contextutil.GetTranslatedMessageWithContext(ctx, uc.services.Translator, "attachment.errors.not_found", "Attachment not found [DEFAULT]"))
+	}
+	stored := readResp.GetData()[0]
+
+	merged := &attachmentpb.Attachment{
+		ModuleKey:     stored.GetModuleKey(),
+		ContentType:   stored.ContentType,
+		FileSizeBytes: stored.FileSizeBytes,
+	}
+	if data.GetModuleKey() != "" {
+		merged.ModuleKey = data.GetModuleKey()
+	}
+	if data.ContentType != nil {
+		merged.ContentType = data.ContentType
+	}
+	if data.FileSizeBytes != nil {
+		merged.FileSizeBytes = data.FileSizeBytes
+	}
+	return assertFilePolicy(ctx, uc.services.Translator, policyFor(merged.GetModuleKey()), merged)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	"github.com/erniealice/espyna-golang/registry/entityid"
 	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
@@ -90,12 +91,31 @@ func (uc *CreateClientUseCase) Execute(ctx context.Context, req *clientpb.Create
 	enrichedClient := uc.applyBusinessLogic(req.Data)
 
 	// Use transaction service if available
+	var resp *clientpb.CreateClientResponse
+	var err error
 	if uc.services.Transactor != nil && uc.services.Transactor.SupportsTransactions() {
-		return uc.executeWithTransaction(ctx, enrichedClient)
+		resp, err = uc.executeWithTransaction(ctx, enrichedClient)
+	} else {
+		// Fallback to direct repository call
+		resp, err = uc.executeCore(ctx, enrichedClient)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, created := range resp.GetData() {
+		domainevent.Emit(ctx, domainevent.ClientCreated, created)
+	}
+	return resp, nil
+}
 
-	// Fallback to direct repository call
-	return uc.executeCore(ctx, enrichedClient)
+// Validate checks req against the rules Execute enforces, without writing
+// anything. Imports use it to preview rows in dry-run mode.
+func (uc *CreateClientUseCase) Validate(ctx context.Context, req *clientpb.CreateClientRequest) error {
+	if req == nil {
+		return errors.New(contextutil.GetTranslatedMessageWithContext(ctx, uc.services.Translator, "client.validation.request_required", "Request is required for clients [DEFAULT]"))
+	}
+	return uc.validateBusinessRules(ctx, req.Data)
 }
 
 // executeWithTransaction executes client creation within a transaction
@@ -7,6 +7,7 @@ import (
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	"github.com/erniealice/espyna-golang/registry/entityid"
 	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
 	clientpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/client"
@@ -86,5 +87,6 @@ func (uc *DeleteClientUseCase) Execute(ctx context.Context, req *clientpb.Delete
 		return nil, fmt.Errorf("%s: %w", translatedError, err)
 	}
 
+	domainevent.Emit(ctx, domainevent.ClientDeleted, &clientpb.Client{Id: req.Data.Id})
 	return resp, nil
 }
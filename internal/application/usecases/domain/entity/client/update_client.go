@@ -7,6 +7,7 @@ import (
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	"github.com/erniealice/espyna-golang/registry/entityid"
 	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
 	clientpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/client"
@@ -115,5 +116,8 @@ func (uc *UpdateClientUseCase) Execute(ctx context.Context, req *clientpb.Update
 		return nil, fmt.Errorf("%s: %w", translatedError, err)
 	}
 
+	for _, updated := range resp.GetData() {
+		domainevent.Emit(ctx, domainevent.ClientUpdated, updated)
+	}
 	return resp, nil
 }
@@ -83,6 +83,15 @@ func (uc *CreateLocationUseCase) Execute(ctx context.Context, req *locationpb.Cr
 	return uc.executeCore(ctx, req)
 }
 
+// Validate checks req against the rules Execute enforces, without writing
+// anything. Imports use it to preview rows in dry-run mode.
+func (uc *CreateLocationUseCase) Validate(ctx context.Context, req *locationpb.CreateLocationRequest) error {
+	if err := uc.validateInput(ctx, req); err != nil {
+		return err
+	}
+	return uc.validateBusinessRules(ctx, req.Data)
+}
+
 // executeWithTransaction executes location creation within a transaction
 func (uc *CreateLocationUseCase) executeWithTransaction(ctx context.Context, req *locationpb.CreateLocationRequest) (*locationpb.CreateLocationResponse, error) {
 	var result *locationpb.CreateLocationResponse
@@ -83,6 +83,15 @@ func (uc *CreateStaffUseCase) Execute(ctx context.Context, req *staffpb.CreateSt
 	return uc.executeCore(ctx, req)
 }
 
+// Validate checks req against the rules Execute enforces, without writing
+// anything. Imports use it to preview rows in dry-run mode.
+func (uc *CreateStaffUseCase) Validate(ctx context.Context, req *staffpb.CreateStaffRequest) error {
+	if err := uc.validateInput(ctx, req); err != nil {
+		return err
+	}
+	return uc.validateBusinessRules(ctx, req.Data)
+}
+
 // executeWithTransaction executes staff creation within a transaction
 func (uc *CreateStaffUseCase) executeWithTransaction(ctx context.Context, req *staffpb.CreateStaffRequest) (*staffpb.CreateStaffResponse, error) {
 	var result *staffpb.CreateStaffResponse
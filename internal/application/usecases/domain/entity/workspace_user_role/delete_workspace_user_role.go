@@ -8,6 +8,7 @@ import (
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	"github.com/erniealice/espyna-golang/registry/entityid"
 	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
 	rolepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/role"
@@ -58,12 +59,23 @@ func (uc *DeleteWorkspaceUserRoleUseCase) Execute(ctx context.Context, req *work
 	}
 
 	// Check if transaction service is available and supports transactions
+	var resp *workspaceuserrolepb.DeleteWorkspaceUserRoleResponse
+	var err error
 	if uc.services.Transactor != nil && uc.services.Transactor.SupportsTransactions() {
-		return uc.executeWithTransaction(ctx, req)
+		resp, err = uc.executeWithTransaction(ctx, req)
+	} else {
+		// Fallback to non-transactional execution
+		resp, err = uc.executeCore(ctx, req)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Fallback to non-transactional execution
-	return uc.executeCore(ctx, req)
+	domainevent.Emit(ctx, domainevent.WorkspaceUserRoleDeleted, &workspaceuserrolepb.WorkspaceUserRole{
+		Id:              req.Data.Id,
+		WorkspaceUserId: req.Data.WorkspaceUserId,
+	})
+	return resp, nil
 }
 
 // executeWithTransaction executes workspace user role deletion within a transaction
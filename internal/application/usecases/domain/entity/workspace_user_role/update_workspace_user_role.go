@@ -9,6 +9,7 @@ import (
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	"github.com/erniealice/espyna-golang/registry/entityid"
 	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
 	rolepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/role"
@@ -70,12 +71,22 @@ func (uc *UpdateWorkspaceUserRoleUseCase) Execute(ctx context.Context, req *work
 	}
 
 	// Determine if we should use transactions
+	var resp *workspaceuserrolepb.UpdateWorkspaceUserRoleResponse
+	var err error
 	if uc.shouldUseTransaction(ctx) {
-		return uc.executeWithTransaction(ctx, req)
+		resp, err = uc.executeWithTransaction(ctx, req)
+	} else {
+		// Execute without transaction (backward compatibility)
+		resp, err = uc.executeWithoutTransaction(ctx, req)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Execute without transaction (backward compatibility)
-	return uc.executeWithoutTransaction(ctx, req)
+	for _, updated := range resp.GetData() {
+		domainevent.Emit(ctx, domainevent.WorkspaceUserRoleUpdated, updated)
+	}
+	return resp, nil
 }
 
 // validateInput validates the input request
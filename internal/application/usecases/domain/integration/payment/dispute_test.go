@@ -0,0 +1,263 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	integrationPorts "github.com/erniealice/espyna-golang/internal/application/ports/integration"
+	emailpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/email"
+	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
+)
+
+// fakeDisputeProvider reports dispute, or parseErr, for every payload
+// except "not-a-dispute".
+type fakeDisputeProvider struct {
+	ports.PaymentProvider
+	dispute   *integrationPorts.PaymentDispute
+	parseErr  error
+	submitted string
+}
+
+func (p *fakeDisputeProvider) Name() string    { return "paypal" }
+func (p *fakeDisputeProvider) IsEnabled() bool { return true }
+func (p *fakeDisputeProvider) ParseDisputeWebhook(_ context.Context, payload []byte) (*integrationPorts.PaymentDispute, bool, error) {
+	if string(payload) == "not-a-dispute" {
+		return nil, false, nil
+	}
+	if p.parseErr != nil {
+		return nil, true, p.parseErr
+	}
+	d := *p.dispute
+	return &d, true, nil
+}
+func (p *fakeDisputeProvider) SubmitDisputeEvidence(_ context.Context, ref string, _ *integrationPorts.PaymentDisputeEvidence) error {
+	p.submitted = ref
+	return nil
+}
+func (p *fakeDisputeProvider) ProcessWebhook(context.Context, *paymentpb.ProcessWebhookRequest) (*paymentpb.ProcessWebhookResponse, error) {
+	return &paymentpb.ProcessWebhookResponse{
+		Success: true,
+		Data:    []*paymentpb.WebhookResult{{Action: "dispute"}},
+	}, nil
+}
+
+// plainProvider accepts neither dispute webhooks nor evidence.
+type plainProvider struct{ ports.PaymentProvider }
+
+func (plainProvider) Name() string { return "asiapay" }
+
+// memDisputes is an in-memory PaymentDisputeRepository keyed by provider ref.
+type memDisputes struct {
+	byRef     map[string]*integrationPorts.PaymentDispute
+	upsertErr error
+	flagErr   error
+	flagged   int
+}
+
+func newMemDisputes() *memDisputes {
+	return &memDisputes{byRef: map[string]*integrationPorts.PaymentDispute{}}
+}
+
+func (r *memDisputes) UpsertDispute(_ context.Context, d *integrationPorts.PaymentDispute) (*integrationPorts.PaymentDispute, integrationPorts.PaymentDisputeStatus, error) {
+	if r.upsertErr != nil {
+		return nil, "", r.upsertErr
+	}
+	var previous integrationPorts.PaymentDisputeStatus
+	if existing, ok := r.byRef[d.ProviderDisputeRef]; ok {
+		previous = existing.Status
+		d.ID = existing.ID
+	} else {
+		d.ID = "dsp-" + d.ProviderDisputeRef
+	}
+	stored := *d
+	r.byRef[d.ProviderDisputeRef] = &stored
+	return &stored, previous, nil
+}
+
+func (r *memDisputes) GetDispute(_ context.Context, id string) (*integrationPorts.PaymentDispute, error) {
+	for _, d := range r.byRef {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, errors.New("dispute not found")
+}
+
+func (r *memDisputes) ListDisputes(context.Context, integrationPorts.PaymentDisputeFilter) ([]*integrationPorts.PaymentDispute, error) {
+	return nil, nil
+}
+
+func (r *memDisputes) FlagDisputedRecords(context.Context, *integrationPorts.PaymentDispute) error {
+	r.flagged++
+	return r.flagErr
+}
+
+// recordingEmail captures sent notifications.
+type recordingEmail struct {
+	ports.EmailProvider
+	sent []*emailpb.SendEmailRequest
+}
+
+func (e *recordingEmail) IsEnabled() bool { return true }
+func (e *recordingEmail) SendEmail(_ context.Context, req *emailpb.SendEmailRequest) (*emailpb.SendEmailResponse, error) {
+	e.sent = append(e.sent, req)
+	return &emailpb.SendEmailResponse{Success: true}, nil
+}
+
+func newDispute(status integrationPorts.PaymentDisputeStatus) *integrationPorts.PaymentDispute {
+	return &integrationPorts.PaymentDispute{
+		ProviderID:         "paypal",
+		ProviderDisputeRef: "PP-D-1",
+		SubscriptionID:     "sub-1",
+		Status:             status,
+		Amount:             2500,
+		Currency:           "USD",
+	}
+}
+
+func newDisputeHandler(provider ports.PaymentProvider, repo integrationPorts.PaymentDisputeRepository, email ports.EmailProvider) *HandleDisputeWebhookUseCase {
+	return NewHandleDisputeWebhookUseCase(
+		HandleDisputeWebhookRepositories{PaymentDispute: repo},
+		HandleDisputeWebhookServices{Provider: provider, Email: email, NotifyEmails: []string{"finance@example.com"}},
+	)
+}
+
+func TestHandleDisputeWebhook_NotifiesOnStatusChange(t *testing.T) {
+	ctx := context.Background()
+	provider := &fakeDisputeProvider{dispute: newDispute(integrationPorts.PaymentDisputeOpen)}
+	repo := newMemDisputes()
+	email := &recordingEmail{}
+	uc := newDisputeHandler(provider, repo, email)
+
+	stored, handled, err := uc.Execute(ctx, []byte("created"))
+	if err != nil || !handled {
+		t.Fatalf("handled = %v, err = %v", handled, err)
+	}
+	if stored.ID != "dsp-PP-D-1" || repo.flagged != 1 {
+		t.Fatalf("stored = %+v, flagged = %d", stored, repo.flagged)
+	}
+	if len(email.sent) != 1 || !strings.HasPrefix(email.sent[0].GetData().GetSubject(), "New payment dispute: 25.00 USD") {
+		t.Fatalf("sent = %v", email.sent)
+	}
+
+	// The same status again is recorded but not re-announced.
+	if _, _, err := uc.Execute(ctx, []byte("updated")); err != nil {
+		t.Fatal(err)
+	}
+	if len(email.sent) != 1 {
+		t.Fatalf("unchanged status sent %d notifications", len(email.sent))
+	}
+
+	provider.dispute = newDispute(integrationPorts.PaymentDisputeLost)
+	if _, _, err := uc.Execute(ctx, []byte("resolved")); err != nil {
+		t.Fatal(err)
+	}
+	if len(email.sent) != 2 || !strings.HasPrefix(email.sent[1].GetData().GetSubject(), "Payment dispute lost") {
+		t.Fatalf("sent = %v", email.sent)
+	}
+}
+
+func TestHandleDisputeWebhook_FlagFailureIsTolerated(t *testing.T) {
+	repo := newMemDisputes()
+	repo.flagErr = errors.New("subscription missing")
+	uc := newDisputeHandler(&fakeDisputeProvider{dispute: newDispute(integrationPorts.PaymentDisputeOpen)}, repo, nil)
+
+	if _, handled, err := uc.Execute(context.Background(), []byte("created")); err != nil || !handled {
+		t.Fatalf("handled = %v, err = %v", handled, err)
+	}
+}
+
+func TestHandleDisputeWebhook_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	uc := newDisputeHandler(plainProvider{}, newMemDisputes(), nil)
+	if _, handled, err := uc.Execute(ctx, []byte("created")); handled || err != nil {
+		t.Errorf("provider without disputes: handled = %v, err = %v", handled, err)
+	}
+
+	uc = newDisputeHandler(&fakeDisputeProvider{}, newMemDisputes(), nil)
+	if _, handled, err := uc.Execute(ctx, []byte("not-a-dispute")); handled || err != nil {
+		t.Errorf("non-dispute payload: handled = %v, err = %v", handled, err)
+	}
+
+	uc = newDisputeHandler(&fakeDisputeProvider{parseErr: errors.New("bad json")}, newMemDisputes(), nil)
+	if _, handled, err := uc.Execute(ctx, []byte("created")); !handled || err == nil {
+		t.Errorf("parse error: handled = %v, err = %v", handled, err)
+	}
+
+	repo := newMemDisputes()
+	repo.upsertErr = errors.New("connection reset")
+	uc = newDisputeHandler(&fakeDisputeProvider{dispute: newDispute(integrationPorts.PaymentDisputeOpen)}, repo, nil)
+	if _, handled, err := uc.Execute(ctx, []byte("created")); !handled || err == nil {
+		t.Errorf("upsert error: handled = %v, err = %v", handled, err)
+	}
+}
+
+func TestProcessWebhook_FailsWhenDisputeWriteFails(t *testing.T) {
+	provider := &fakeDisputeProvider{dispute: newDispute(integrationPorts.PaymentDisputeOpen)}
+	repo := newMemDisputes()
+	repo.upsertErr = errors.New("connection reset")
+	uc := NewProcessWebhookUseCase(ProcessWebhookRepositories{}, ProcessWebhookServices{
+		Provider: provider,
+		Disputes: newDisputeHandler(provider, repo, nil),
+	})
+	req := &paymentpb.ProcessWebhookRequest{Data: &paymentpb.WebhookData{ProviderId: "paypal", Payload: []byte("created")}}
+
+	if _, err := uc.Execute(context.Background(), req); err == nil {
+		t.Fatal("expected an error so the provider redelivers the webhook")
+	}
+
+	repo.upsertErr = nil
+	resp, err := uc.Execute(context.Background(), req)
+	if err != nil || !resp.Success {
+		t.Fatalf("resp = %v, err = %v", resp, err)
+	}
+	if _, ok := repo.byRef["PP-D-1"]; !ok {
+		t.Fatal("redelivered dispute was not stored")
+	}
+}
+
+func TestSubmitDisputeEvidence(t *testing.T) {
+	ctx := context.Background()
+	evidence := &integrationPorts.PaymentDisputeEvidence{Notes: "Delivered on time"}
+	repo := newMemDisputes()
+	if _, _, err := repo.UpsertDispute(ctx, newDispute(integrationPorts.PaymentDisputeEvidenceRequired)); err != nil {
+		t.Fatal(err)
+	}
+	resolved := newDispute(integrationPorts.PaymentDisputeWon)
+	resolved.ProviderDisputeRef = "PP-D-2"
+	if _, _, err := repo.UpsertDispute(ctx, resolved); err != nil {
+		t.Fatal(err)
+	}
+
+	plain := NewSubmitDisputeEvidenceUseCase(SubmitDisputeEvidenceRepositories{PaymentDispute: repo}, SubmitDisputeEvidenceServices{Provider: plainProvider{}})
+	if plain.Supported() {
+		t.Error("provider without evidence API reported as supported")
+	}
+	if err := plain.Execute(ctx, "dsp-PP-D-1", evidence); err == nil {
+		t.Error("expected an error for a provider without evidence API")
+	}
+
+	provider := &fakeDisputeProvider{}
+	uc := NewSubmitDisputeEvidenceUseCase(SubmitDisputeEvidenceRepositories{PaymentDispute: repo}, SubmitDisputeEvidenceServices{Provider: provider})
+	if !uc.Supported() {
+		t.Fatal("expected evidence submission to be supported")
+	}
+	if err := uc.Execute(ctx, "dsp-PP-D-2", evidence); err == nil {
+		t.Error("expected an error for a resolved dispute")
+	}
+	if err := uc.Execute(ctx, "dsp-PP-D-1", evidence); err != nil {
+		t.Fatal(err)
+	}
+	if provider.submitted != "PP-D-1" {
+		t.Errorf("submitted ref = %q", provider.submitted)
+	}
+
+	repo.byRef["PP-D-1"].ProviderID = "stripe"
+	if err := uc.Execute(ctx, "dsp-PP-D-1", evidence); err == nil {
+		t.Error("expected an error for a dispute from another provider")
+	}
+}
@@ -0,0 +1,144 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	integrationPorts "github.com/erniealice/espyna-golang/internal/application/ports/integration"
+)
+
+// HandleDisputeWebhookRepositories groups all repository dependencies
+type HandleDisputeWebhookRepositories struct {
+	PaymentDispute integrationPorts.PaymentDisputeRepository
+}
+
+// HandleDisputeWebhookServices groups all service dependencies
+type HandleDisputeWebhookServices struct {
+	Provider ports.PaymentProvider
+	// Email and NotifyEmails are optional; without both, disputes are
+	// recorded and flagged but nobody is emailed.
+	Email        ports.EmailProvider
+	NotifyEmails []string
+}
+
+// HandleDisputeWebhookUseCase records a dispute reported by a provider
+// webhook, flags the subscription/invoice it was raised against, and emails
+// the finance recipients when the dispute is new or its status changed.
+type HandleDisputeWebhookUseCase struct {
+	repositories HandleDisputeWebhookRepositories
+	services     HandleDisputeWebhookServices
+}
+
+// NewHandleDisputeWebhookUseCase creates a new HandleDisputeWebhookUseCase
+func NewHandleDisputeWebhookUseCase(
+	repositories HandleDisputeWebhookRepositories,
+	services HandleDisputeWebhookServices,
+) *HandleDisputeWebhookUseCase {
+	return &HandleDisputeWebhookUseCase{
+		repositories: repositories,
+		services:     services,
+	}
+}
+
+// Execute handles a raw webhook payload. handled is false when the provider
+// does not report disputes or the payload is not a dispute event.
+func (uc *HandleDisputeWebhookUseCase) Execute(ctx context.Context, payload []byte) (dispute *integrationPorts.PaymentDispute, handled bool, err error) {
+	disputes, ok := uc.services.Provider.(integrationPorts.DisputeProvider)
+	if !ok {
+		return nil, false, nil
+	}
+	parsed, ok, err := disputes.ParseDisputeWebhook(ctx, payload)
+	if !ok {
+		return nil, false, err
+	}
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to parse dispute webhook: %w", err)
+	}
+	if uc.repositories.PaymentDispute == nil {
+		return parsed, true, fmt.Errorf("payment dispute repository is not available")
+	}
+
+	stored, previous, err := uc.repositories.PaymentDispute.UpsertDispute(ctx, parsed)
+	if err != nil {
+		return parsed, true, fmt.Errorf("failed to save dispute: %w", err)
+	}
+	log.Printf("⚠️ Payment dispute %s (%s %s): %s -> %s",
+		stored.ID, stored.ProviderID, stored.ProviderDisputeRef, previousLabel(previous), stored.Status)
+
+	// Flagging and notification are best effort: the dispute itself is
+	// stored, and the provider will resend updates.
+	if err := uc.repositories.PaymentDispute.FlagDisputedRecords(ctx, stored); err != nil {
+		log.Printf("❌ Failed to flag records for dispute %s: %v", stored.ID, err)
+	}
+	if previous != stored.Status {
+		uc.notify(ctx, stored, previous)
+	}
+	return stored, true, nil
+}
+
+func (uc *HandleDisputeWebhookUseCase) notify(ctx context.Context, d *integrationPorts.PaymentDispute, previous integrationPorts.PaymentDisputeStatus) {
+	if uc.services.Email == nil || !uc.services.Email.IsEnabled() || len(uc.services.NotifyEmails) == 0 {
+		return
+	}
+	subject, body := disputeNotification(d, previous)
+	msg := ports.EmailMessage{
+		To:       uc.services.NotifyEmails,
+		Subject:  subject,
+		TextBody: body,
+	}
+	resp, err := uc.services.Email.SendEmail(ctx, msg.ToProtoRequest())
+	if err != nil {
+		log.Printf("❌ Failed to send dispute notification for %s: %v", d.ID, err)
+		return
+	}
+	if resp != nil && !resp.Success {
+		log.Printf("❌ Dispute notification for %s was not sent: %s", d.ID, resp.GetError().GetMessage())
+	}
+}
+
+// disputeNotification renders the plain-text finance notification.
+func disputeNotification(d *integrationPorts.PaymentDispute, previous integrationPorts.PaymentDisputeStatus) (subject, body string) {
+	amount := fmt.Sprintf("%.2f %s", float64(d.Amount)/100, d.Currency)
+	switch {
+	case previous == "":
+		subject = fmt.Sprintf("New payment dispute: %s via %s", amount, d.ProviderID)
+	case d.Status.Resolved():
+		subject = fmt.Sprintf("Payment dispute %s: %s", d.Status, amount)
+	default:
+		subject = fmt.Sprintf("Payment dispute update (%s): %s", d.Status, amount)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dispute:        %s (%s ref %s)\n", d.ID, d.ProviderID, d.ProviderDisputeRef)
+	fmt.Fprintf(&b, "Status:         %s (was %s)\n", d.Status, previousLabel(previous))
+	fmt.Fprintf(&b, "Amount:         %s\n", amount)
+	if d.Reason != "" {
+		fmt.Fprintf(&b, "Reason:         %s\n", d.Reason)
+	}
+	if d.Stage != "" {
+		fmt.Fprintf(&b, "Stage:          %s\n", d.Stage)
+	}
+	if d.SubscriptionID != "" {
+		fmt.Fprintf(&b, "Subscription:   %s\n", d.SubscriptionID)
+	}
+	if d.PaymentID != "" {
+		fmt.Fprintf(&b, "Payment:        %s\n", d.PaymentID)
+	}
+	if d.ProviderPaymentRef != "" {
+		fmt.Fprintf(&b, "Transaction:    %s\n", d.ProviderPaymentRef)
+	}
+	if d.Status == integrationPorts.PaymentDisputeEvidenceRequired && !d.EvidenceDueAt.IsZero() {
+		fmt.Fprintf(&b, "\nEvidence is due by %s.\n", d.EvidenceDueAt.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	return subject, b.String()
+}
+
+func previousLabel(s integrationPorts.PaymentDisputeStatus) string {
+	if s == "" {
+		return "new"
+	}
+	return string(s)
+}
@@ -0,0 +1,48 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+
+	integrationPorts "github.com/erniealice/espyna-golang/internal/application/ports/integration"
+)
+
+// ListDisputesRepositories groups all repository dependencies
+type ListDisputesRepositories struct {
+	PaymentDispute integrationPorts.PaymentDisputeRepository
+}
+
+// ListDisputesServices groups all service dependencies
+type ListDisputesServices struct {
+	// No external services needed for listing
+}
+
+// ListDisputesUseCase lists stored payment disputes for finance review.
+type ListDisputesUseCase struct {
+	repositories ListDisputesRepositories
+	services     ListDisputesServices
+}
+
+// NewListDisputesUseCase creates a new ListDisputesUseCase
+func NewListDisputesUseCase(
+	repositories ListDisputesRepositories,
+	services ListDisputesServices,
+) *ListDisputesUseCase {
+	return &ListDisputesUseCase{
+		repositories: repositories,
+		services:     services,
+	}
+}
+
+// Available reports whether a dispute repository is wired.
+func (uc *ListDisputesUseCase) Available() bool {
+	return uc != nil && uc.repositories.PaymentDispute != nil
+}
+
+// Execute returns disputes matching filter, most recently updated first.
+func (uc *ListDisputesUseCase) Execute(ctx context.Context, filter integrationPorts.PaymentDisputeFilter) ([]*integrationPorts.PaymentDispute, error) {
+	if uc.repositories.PaymentDispute == nil {
+		return nil, fmt.Errorf("payment dispute repository is not available")
+	}
+	return uc.repositories.PaymentDispute.ListDisputes(ctx, filter)
+}
@@ -6,6 +6,8 @@ import (
 	"log"
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
 	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
 )
@@ -18,6 +20,8 @@ type ProcessWebhookRepositories struct {
 // ProcessWebhookServices groups all service dependencies
 type ProcessWebhookServices struct {
 	Provider ports.PaymentProvider
+	// Disputes handles dispute events (optional).
+	Disputes *HandleDisputeWebhookUseCase
 }
 
 // ProcessWebhookUseCase handles processing payment webhooks
@@ -80,5 +84,44 @@ func (uc *ProcessWebhookUseCase) Execute(ctx context.Context, req *paymentpb.Pro
 		log.Printf("   Action: %s", response.Data[0].Action)
 	}
 
+	if response.Success && uc.services.Disputes != nil && hasDisputeAction(response.Data) {
+		if _, _, err := uc.services.Disputes.Execute(ctx, req.Data.Payload); err != nil {
+			// Fail the webhook so the provider redelivers it; an
+			// acknowledged dispute event is never sent again.
+			log.Printf("❌ Failed to handle dispute webhook: %v", err)
+			return nil, fmt.Errorf("failed to handle dispute webhook: %w", err)
+		}
+	}
+
+	if response.Success {
+		for _, result := range response.Data {
+			emitPaid(ctx, result)
+		}
+	}
+
 	return response, nil
 }
+
+// emitPaid announces a successful payment as invoice.paid. Provider
+// webhooks arrive without a session, so the workspace comes from the
+// checkout's metadata when the context has none.
+func emitPaid(ctx context.Context, result *paymentpb.WebhookResult) {
+	if result.Status != paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS || result.Transaction == nil {
+		return
+	}
+	if contextutil.ExtractWorkspaceIDFromContext(ctx) == "" {
+		if workspaceID := result.Transaction.ProviderMetadata["workspace_id"]; workspaceID != "" {
+			ctx = contextutil.WithWorkspaceID(ctx, workspaceID)
+		}
+	}
+	domainevent.Emit(ctx, domainevent.InvoicePaid, result.Transaction)
+}
+
+func hasDisputeAction(results []*paymentpb.WebhookResult) bool {
+	for _, r := range results {
+		if r.Action == "dispute" {
+			return true
+		}
+	}
+	return false
+}
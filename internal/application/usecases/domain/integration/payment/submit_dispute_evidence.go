@@ -0,0 +1,76 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	integrationPorts "github.com/erniealice/espyna-golang/internal/application/ports/integration"
+)
+
+// SubmitDisputeEvidenceRepositories groups all repository dependencies
+type SubmitDisputeEvidenceRepositories struct {
+	PaymentDispute integrationPorts.PaymentDisputeRepository
+}
+
+// SubmitDisputeEvidenceServices groups all service dependencies
+type SubmitDisputeEvidenceServices struct {
+	Provider ports.PaymentProvider
+}
+
+// SubmitDisputeEvidenceUseCase forwards seller evidence for a stored
+// dispute to the provider, when the provider's API accepts evidence.
+type SubmitDisputeEvidenceUseCase struct {
+	repositories SubmitDisputeEvidenceRepositories
+	services     SubmitDisputeEvidenceServices
+}
+
+// NewSubmitDisputeEvidenceUseCase creates a new SubmitDisputeEvidenceUseCase
+func NewSubmitDisputeEvidenceUseCase(
+	repositories SubmitDisputeEvidenceRepositories,
+	services SubmitDisputeEvidenceServices,
+) *SubmitDisputeEvidenceUseCase {
+	return &SubmitDisputeEvidenceUseCase{
+		repositories: repositories,
+		services:     services,
+	}
+}
+
+// Supported reports whether the configured provider accepts evidence via API.
+func (uc *SubmitDisputeEvidenceUseCase) Supported() bool {
+	_, ok := uc.services.Provider.(integrationPorts.DisputeEvidenceSubmitter)
+	return ok && uc.repositories.PaymentDispute != nil
+}
+
+// Execute submits evidence for the dispute with the given internal ID.
+// The dispute status is left to the provider's next webhook.
+func (uc *SubmitDisputeEvidenceUseCase) Execute(ctx context.Context, disputeID string, evidence *integrationPorts.PaymentDisputeEvidence) error {
+	if disputeID == "" || evidence == nil {
+		return fmt.Errorf("dispute ID and evidence are required")
+	}
+	submitter, ok := uc.services.Provider.(integrationPorts.DisputeEvidenceSubmitter)
+	if !ok {
+		return fmt.Errorf("payment provider does not accept dispute evidence via API; respond in the provider dashboard")
+	}
+	if uc.repositories.PaymentDispute == nil {
+		return fmt.Errorf("payment dispute repository is not available")
+	}
+
+	dispute, err := uc.repositories.PaymentDispute.GetDispute(ctx, disputeID)
+	if err != nil {
+		return err
+	}
+	if dispute.ProviderID != uc.services.Provider.Name() {
+		return fmt.Errorf("dispute %s belongs to provider %s, not %s", disputeID, dispute.ProviderID, uc.services.Provider.Name())
+	}
+	if dispute.Status.Resolved() {
+		return fmt.Errorf("dispute %s is already %s", disputeID, dispute.Status)
+	}
+
+	if err := submitter.SubmitDisputeEvidence(ctx, dispute.ProviderDisputeRef, evidence); err != nil {
+		return fmt.Errorf("failed to submit dispute evidence: %w", err)
+	}
+	log.Printf("📎 Evidence submitted for dispute %s (%s)", dispute.ID, dispute.ProviderDisputeRef)
+	return nil
+}
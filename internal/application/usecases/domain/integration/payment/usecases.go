@@ -8,11 +8,15 @@ import (
 // PaymentRepositories groups all repository dependencies for payment use cases
 type PaymentRepositories struct {
 	IntegrationPayment integrationPorts.IntegrationPaymentRepository
+	PaymentDispute     integrationPorts.PaymentDisputeRepository
 }
 
 // PaymentServices groups all business service dependencies for payment use cases
 type PaymentServices struct {
 	Provider ports.PaymentProvider
+	// Email and DisputeNotifyEmails route dispute notifications to finance.
+	Email               ports.EmailProvider
+	DisputeNotifyEmails []string
 }
 
 // UseCases contains all payment integration use cases
@@ -23,6 +27,10 @@ type UseCases struct {
 	GetPaymentStatus *GetPaymentStatusUseCase
 	CheckHealth      *CheckHealthUseCase
 	GetCapabilities  *GetCapabilitiesUseCase
+
+	HandleDisputeWebhook  *HandleDisputeWebhookUseCase
+	SubmitDisputeEvidence *SubmitDisputeEvidenceUseCase
+	ListDisputes          *ListDisputesUseCase
 }
 
 // NewUseCases creates a new collection of payment integration use cases
@@ -36,9 +44,21 @@ func NewUseCases(
 		Provider: services.Provider,
 	}
 
+	handleDisputeWebhook := NewHandleDisputeWebhookUseCase(
+		HandleDisputeWebhookRepositories{
+			PaymentDispute: repositories.PaymentDispute,
+		},
+		HandleDisputeWebhookServices{
+			Provider:     services.Provider,
+			Email:        services.Email,
+			NotifyEmails: services.DisputeNotifyEmails,
+		},
+	)
+
 	processWebhookRepos := ProcessWebhookRepositories{}
 	processWebhookServices := ProcessWebhookServices{
 		Provider: services.Provider,
+		Disputes: handleDisputeWebhook,
 	}
 
 	logWebhookRepos := LogWebhookRepositories{
@@ -68,6 +88,16 @@ func NewUseCases(
 		GetPaymentStatus: NewGetPaymentStatusUseCase(getPaymentStatusRepos, getPaymentStatusServices),
 		CheckHealth:      NewCheckHealthUseCase(checkHealthRepos, checkHealthServices),
 		GetCapabilities:  NewGetCapabilitiesUseCase(getCapabilitiesRepos, getCapabilitiesServices),
+
+		HandleDisputeWebhook: handleDisputeWebhook,
+		SubmitDisputeEvidence: NewSubmitDisputeEvidenceUseCase(
+			SubmitDisputeEvidenceRepositories{PaymentDispute: repositories.PaymentDispute},
+			SubmitDisputeEvidenceServices{Provider: services.Provider},
+		),
+		ListDisputes: NewListDisputesUseCase(
+			ListDisputesRepositories{PaymentDispute: repositories.PaymentDispute},
+			ListDisputesServices{},
+		),
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
 	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
 )
@@ -18,6 +19,7 @@ type CancelScheduleRepositories struct {
 // CancelScheduleServices groups all service dependencies
 type CancelScheduleServices struct {
 	Provider ports.SchedulerProvider
+	Invites  ports.ScheduleInviteSender // optional
 }
 
 // CancelScheduleUseCase handles cancelling scheduled events
@@ -83,6 +85,20 @@ func (uc *CancelScheduleUseCase) Execute(ctx context.Context, req *schedulerpb.C
 
 	if response.Success {
 		log.Printf("✅ Schedule cancelled successfully")
+		if uc.services.Invites != nil {
+			inviteID := req.Data.ProviderScheduleId
+			if inviteID == "" {
+				inviteID = req.Data.ScheduleId
+			}
+			if err := uc.services.Invites.SendScheduleCancellation(ctx, inviteID, req.Data.Reason); err != nil {
+				log.Printf("❌ Failed to send calendar cancellation for %s: %v", inviteID, err)
+			}
+		}
+		domainevent.Emit(ctx, domainevent.ScheduleCancelled, &schedulerpb.Schedule{
+			Id:                 req.Data.ScheduleId,
+			ProviderScheduleId: req.Data.ProviderScheduleId,
+			Status:             schedulerpb.ScheduleStatus_SCHEDULE_STATUS_CANCELLED,
+		})
 	}
 
 	return response, nil
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
@@ -79,6 +80,37 @@ func (uc *CheckHealthUseCase) Execute(ctx context.Context, req *schedulerpb.Chec
 		}, nil
 	}
 
+	message := "Scheduler provider is healthy"
+
+	// Providers managing their webhook subscription report it too: a missing
+	// or disabled subscription means bookings silently stop syncing.
+	if manager, ok := uc.services.Provider.(ports.SchedulerWebhookManager); ok {
+		subscription, err := manager.WebhookSubscriptionStatus(ctx)
+		if err != nil {
+			log.Printf("❌ Scheduler webhook subscription status unavailable: %v", err)
+			return &schedulerpb.CheckSchedulerHealthResponse{
+				Success: false,
+				Error: &commonpb.Error{
+					Code:    "WEBHOOK_SUBSCRIPTION_UNAVAILABLE",
+					Message: err.Error(),
+				},
+			}, nil
+		}
+		if subscription != nil {
+			if subscription.State != ports.SchedulerWebhookActive {
+				log.Printf("❌ Scheduler webhook subscription %s for %s", subscription.State, subscription.CallbackURL)
+				return &schedulerpb.CheckSchedulerHealthResponse{
+					Success: false,
+					Error: &commonpb.Error{
+						Code:    "WEBHOOK_SUBSCRIPTION_INACTIVE",
+						Message: fmt.Sprintf("Webhook subscription for %s is %s", subscription.CallbackURL, subscription.State),
+					},
+				}, nil
+			}
+			message = fmt.Sprintf("%s; webhook subscription %s is active", message, subscription.ID)
+		}
+	}
+
 	log.Printf("✅ Scheduler provider healthy (latency: %dms)", latencyMs)
 
 	return &schedulerpb.CheckSchedulerHealthResponse{
@@ -88,7 +120,7 @@ func (uc *CheckHealthUseCase) Execute(ctx context.Context, req *schedulerpb.Chec
 				IsHealthy: true,
 				HealthStatus: &schedulerpb.SchedulerProviderHealthStatus{
 					IsHealthy: true,
-					Message:   "Scheduler provider is healthy",
+					Message:   message,
 					LatencyMs: latencyMs,
 					LastCheck: timestamppb.Now(),
 				},
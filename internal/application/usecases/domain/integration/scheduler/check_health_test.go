@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
+)
+
+// fakeWebhookProvider is a healthy provider reporting subscription.
+type fakeWebhookProvider struct {
+	ports.SchedulerProvider
+	subscription *ports.SchedulerWebhookSubscription
+}
+
+func (p *fakeWebhookProvider) Name() string                    { return "calendly" }
+func (p *fakeWebhookProvider) IsEnabled() bool                 { return true }
+func (p *fakeWebhookProvider) IsHealthy(context.Context) error { return nil }
+func (p *fakeWebhookProvider) EnsureWebhookSubscription(context.Context, string) (*ports.SchedulerWebhookSubscription, error) {
+	return p.subscription, nil
+}
+func (p *fakeWebhookProvider) RotateWebhookSecret(context.Context) (*ports.SchedulerWebhookSubscription, error) {
+	return p.subscription, nil
+}
+func (p *fakeWebhookProvider) WebhookSubscriptionStatus(context.Context) (*ports.SchedulerWebhookSubscription, error) {
+	return p.subscription, nil
+}
+
+func TestCheckHealth_WebhookSubscription(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name         string
+		subscription *ports.SchedulerWebhookSubscription
+		ok           bool
+		message      string
+	}{
+		{"no callback configured", nil, true, "Scheduler provider is healthy"},
+		{"active", &ports.SchedulerWebhookSubscription{ID: "sub-1", State: ports.SchedulerWebhookActive}, true, "webhook subscription sub-1 is active"},
+		{"disabled", &ports.SchedulerWebhookSubscription{ID: "sub-1", State: ports.SchedulerWebhookDisabled}, false, ""},
+		{"missing", &ports.SchedulerWebhookSubscription{State: ports.SchedulerWebhookMissing}, false, ""},
+	}
+	for _, c := range cases {
+		uc := NewCheckHealthUseCase(CheckHealthRepositories{}, CheckHealthServices{Provider: &fakeWebhookProvider{subscription: c.subscription}})
+		resp, err := uc.Execute(ctx, &schedulerpb.CheckSchedulerHealthRequest{})
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if resp.Success != c.ok {
+			t.Errorf("%s: success = %v, error = %v", c.name, resp.Success, resp.Error)
+			continue
+		}
+		if !c.ok {
+			if resp.Error.GetCode() != "WEBHOOK_SUBSCRIPTION_INACTIVE" {
+				t.Errorf("%s: code = %s", c.name, resp.Error.GetCode())
+			}
+			continue
+		}
+		if msg := resp.Data[0].HealthStatus.Message; !strings.Contains(msg, c.message) {
+			t.Errorf("%s: message = %q", c.name, msg)
+		}
+	}
+}
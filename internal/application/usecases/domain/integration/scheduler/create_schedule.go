@@ -18,6 +18,7 @@ type CreateScheduleRepositories struct {
 // CreateScheduleServices groups all service dependencies
 type CreateScheduleServices struct {
 	Provider ports.SchedulerProvider
+	Invites  ports.ScheduleInviteSender // optional
 }
 
 // CreateScheduleUseCase handles creating scheduled events
@@ -82,5 +83,13 @@ func (uc *CreateScheduleUseCase) Execute(ctx context.Context, req *schedulerpb.C
 		log.Printf("   Name: %s", response.Data[0].Name)
 	}
 
+	if response.Success && uc.services.Invites != nil {
+		for _, schedule := range response.Data {
+			if err := uc.services.Invites.SendScheduleInvite(ctx, schedule, ""); err != nil {
+				log.Printf("❌ Failed to send calendar invite for %s: %v", schedule.ProviderScheduleId, err)
+			}
+		}
+	}
+
 	return response, nil
 }
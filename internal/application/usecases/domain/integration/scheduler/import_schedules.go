@@ -0,0 +1,478 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+	clientpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/client"
+	userpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/user"
+	eventpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/event/event"
+	eventclientpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/event/event_client"
+	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
+)
+
+// importPageSize is the number of schedules requested per provider page.
+const importPageSize = 100
+
+// ErrImportUnavailable is returned when the importer is not wired or the
+// scheduler provider is disabled.
+var ErrImportUnavailable = errors.New("schedule import is not available")
+
+// ErrInvalidImportWindow is returned when the request has no start date or
+// the start is not before the end of the window.
+var ErrInvalidImportWindow = errors.New("import needs a start date in the past and before the end date")
+
+// ClientCreator creates a client together with its user. The entity
+// CreateClient use case satisfies it.
+type ClientCreator interface {
+	Execute(ctx context.Context, req *clientpb.CreateClientRequest) (*clientpb.CreateClientResponse, error)
+}
+
+// ImportSchedulesRepositories groups all repository dependencies
+type ImportSchedulesRepositories struct {
+	Client      clientpb.ClientDomainServiceServer
+	User        userpb.UserDomainServiceServer
+	Event       eventpb.EventDomainServiceServer
+	EventClient eventclientpb.EventClientDomainServiceServer
+}
+
+// ImportSchedulesServices groups all service dependencies
+type ImportSchedulesServices struct {
+	Provider         ports.SchedulerProvider
+	ActionGatekeeper *actiongate.ActionGatekeeper
+	IDGenerator      ports.IDGenerator
+	// CreateClient creates clients for unmatched invitees when the request
+	// asks for it (optional)
+	CreateClient ClientCreator
+}
+
+// ImportSchedulesRequest selects the provider history to import.
+type ImportSchedulesRequest struct {
+	// From is the earliest start time to import (required).
+	From time.Time
+	// To bounds the start times; zero or a future time means now, since
+	// only past schedules are imported.
+	To time.Time
+	// CreateMissingClients creates a client for every invitee whose email
+	// matches no existing client. Otherwise those invitees are reported in
+	// UnmatchedEmails and the event is imported without them.
+	CreateMissingClients bool
+	// IncludeCanceled also imports canceled schedules, as cancelled events.
+	IncludeCanceled bool
+}
+
+// ImportSchedulesFailure records a schedule that could not be imported.
+type ImportSchedulesFailure struct {
+	ProviderScheduleID string `json:"provider_schedule_id"`
+	Message            string `json:"message"`
+}
+
+// ImportSchedulesResult summarizes an import run.
+type ImportSchedulesResult struct {
+	// Scanned is the number of provider schedules in the window.
+	Scanned int `json:"scanned"`
+	// Imported is the number of events created by this run.
+	Imported int `json:"imported"`
+	// AlreadyImported counts schedules skipped because an earlier run
+	// created their event.
+	AlreadyImported int                      `json:"already_imported"`
+	ClientsMatched  int                      `json:"clients_matched"`
+	ClientsCreated  int                      `json:"clients_created"`
+	UnmatchedEmails []string                 `json:"unmatched_emails,omitempty"`
+	Failures        []ImportSchedulesFailure `json:"failures,omitempty"`
+}
+
+// ImportSchedulesUseCase backfills past provider schedules as events linked
+// to clients, so a workspace keeps its booking history when it starts using
+// espyna. Each schedule becomes one event whose ID is derived from the
+// provider schedule ID, which makes re-running the import safe. Invitees are
+// matched to clients by email.
+type ImportSchedulesUseCase struct {
+	repositories ImportSchedulesRepositories
+	services     ImportSchedulesServices
+}
+
+// NewImportSchedulesUseCase creates a new ImportSchedulesUseCase
+func NewImportSchedulesUseCase(
+	repositories ImportSchedulesRepositories,
+	services ImportSchedulesServices,
+) *ImportSchedulesUseCase {
+	return &ImportSchedulesUseCase{
+		repositories: repositories,
+		services:     services,
+	}
+}
+
+// Available reports whether the importer is wired to a provider and the
+// event and client repositories.
+func (uc *ImportSchedulesUseCase) Available() bool {
+	return uc != nil && uc.services.Provider != nil &&
+		uc.repositories.Event != nil && uc.repositories.EventClient != nil &&
+		uc.repositories.Client != nil && uc.repositories.User != nil
+}
+
+// Execute imports the provider schedules that started within the request
+// window. A schedule that fails is recorded in Failures and the run goes
+// on; an error is returned only when the provider listing itself fails, in
+// which case the result covers the pages imported so far.
+func (uc *ImportSchedulesUseCase) Execute(ctx context.Context, req *ImportSchedulesRequest) (*ImportSchedulesResult, error) {
+	if !uc.Available() || !uc.services.Provider.IsEnabled() {
+		return nil, ErrImportUnavailable
+	}
+	if err := uc.services.ActionGatekeeper.Check(ctx, &actiongate.CheckActionRequest{
+		Entity: entityid.Event,
+		Action: entityid.ActionCreate,
+	}); err != nil {
+		return nil, err
+	}
+	if req == nil || req.From.IsZero() {
+		return nil, ErrInvalidImportWindow
+	}
+	now := time.Now()
+	to := req.To
+	if to.IsZero() || to.After(now) {
+		to = now
+	}
+	if !req.From.Before(to) {
+		return nil, ErrInvalidImportWindow
+	}
+
+	log.Printf("📥 Importing %s schedules from %s to %s", uc.services.Provider.Name(),
+		req.From.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	run := &scheduleImport{
+		uc:          uc,
+		req:         req,
+		workspaceID: contextutil.ExtractWorkspaceIDFromContext(ctx),
+		clients:     make(map[string]string),
+		result:      &ImportSchedulesResult{},
+	}
+
+	filter := &schedulerpb.ScheduleListFilter{
+		FromDate: req.From.UTC().Format("2006-01-02"),
+		// The provider's end date is exclusive; include the last day.
+		ToDate: to.UTC().AddDate(0, 0, 1).Format("2006-01-02"),
+		Limit:  importPageSize,
+	}
+	if !req.IncludeCanceled {
+		filter.Status = "active"
+	}
+	for {
+		resp, err := uc.services.Provider.ListSchedules(ctx, &schedulerpb.ListSchedulesRequest{Data: filter})
+		if err == nil && !resp.GetSuccess() {
+			err = fmt.Errorf("%s", resp.GetError().GetMessage())
+		}
+		if err != nil {
+			log.Printf("❌ Schedule import stopped: %v", err)
+			return run.result, fmt.Errorf("failed to list schedules: %w", err)
+		}
+		for _, schedule := range resp.Data {
+			start, _, err := scheduleTimes(schedule)
+			if err != nil || start.Before(req.From) || !start.Before(to) {
+				continue
+			}
+			run.result.Scanned++
+			run.importSchedule(ctx, schedule)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		filter.PageToken = resp.NextPageToken
+	}
+
+	log.Printf("✅ Schedule import done: %d scanned, %d imported, %d already imported, %d failed",
+		run.result.Scanned, run.result.Imported, run.result.AlreadyImported, len(run.result.Failures))
+	return run.result, nil
+}
+
+// scheduleImport holds the state of one import run.
+type scheduleImport struct {
+	uc          *ImportSchedulesUseCase
+	req         *ImportSchedulesRequest
+	workspaceID string
+	// clients caches email → client ID ("" when unmatched) for the run.
+	clients map[string]string
+	result  *ImportSchedulesResult
+}
+
+func (r *scheduleImport) importSchedule(ctx context.Context, schedule *schedulerpb.Schedule) {
+	fail := func(err error) {
+		log.Printf("⚠️  Failed to import schedule %s: %v", schedule.ProviderScheduleId, err)
+		r.result.Failures = append(r.result.Failures, ImportSchedulesFailure{
+			ProviderScheduleID: schedule.ProviderScheduleId,
+			Message:            err.Error(),
+		})
+	}
+
+	eventID := importedEventID(schedule)
+	if eventID == "" {
+		fail(errors.New("schedule has no provider ID"))
+		return
+	}
+	existing, err := r.uc.repositories.Event.ReadEvent(ctx, &eventpb.ReadEventRequest{
+		Data: &eventpb.Event{Id: eventID},
+	})
+	if err == nil && existing != nil && len(existing.Data) > 0 {
+		r.result.AlreadyImported++
+		return
+	}
+
+	invitees, err := r.invitees(ctx, schedule)
+	if err != nil {
+		fail(err)
+		return
+	}
+	var clientIDs []string
+	for _, invitee := range invitees {
+		clientID, err := r.resolveClient(ctx, invitee)
+		if err != nil {
+			fail(err)
+			return
+		}
+		if clientID != "" {
+			clientIDs = append(clientIDs, clientID)
+		}
+	}
+
+	event, err := importedEvent(eventID, schedule)
+	if err != nil {
+		fail(err)
+		return
+	}
+	event.WorkspaceId = r.workspaceID
+	if _, err := r.uc.repositories.Event.CreateEvent(ctx, &eventpb.CreateEventRequest{Data: event}); err != nil {
+		fail(fmt.Errorf("failed to create event: %w", err))
+		return
+	}
+	r.result.Imported++
+
+	now := time.Now()
+	for _, clientID := range clientIDs {
+		link := &eventclientpb.EventClient{
+			Id:                 r.uc.generateID("event-client"),
+			EventId:            eventID,
+			ClientId:           clientID,
+			Active:             true,
+			DateCreated:        &[]int64{now.UnixMilli()}[0],
+			DateCreatedString:  &[]string{now.Format(time.RFC3339)}[0],
+			DateModified:       &[]int64{now.UnixMilli()}[0],
+			DateModifiedString: &[]string{now.Format(time.RFC3339)}[0],
+		}
+		if _, err := r.uc.repositories.EventClient.CreateEventClient(ctx, &eventclientpb.CreateEventClientRequest{Data: link}); err != nil {
+			fail(fmt.Errorf("failed to link client %s: %w", clientID, err))
+		}
+	}
+}
+
+// invitees returns the schedule's invitees, asking the provider when it can
+// list them.
+func (r *scheduleImport) invitees(ctx context.Context, schedule *schedulerpb.Schedule) ([]*schedulerpb.InviteeInfo, error) {
+	if lister, ok := r.uc.services.Provider.(ports.ScheduleInviteeLister); ok {
+		invitees, err := lister.ListScheduleInvitees(ctx, schedule.ProviderScheduleId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list invitees: %w", err)
+		}
+		return invitees, nil
+	}
+	if schedule.Invitee != nil {
+		return []*schedulerpb.InviteeInfo{schedule.Invitee}, nil
+	}
+	return nil, nil
+}
+
+// resolveClient returns the ID of the client with the invitee's email,
+// creating one when the request allows it. It returns "" for an invitee
+// that stays unmatched.
+func (r *scheduleImport) resolveClient(ctx context.Context, invitee *schedulerpb.InviteeInfo) (string, error) {
+	email := strings.ToLower(strings.TrimSpace(invitee.GetEmail()))
+	if email == "" {
+		return "", nil
+	}
+	if clientID, ok := r.clients[email]; ok {
+		return clientID, nil
+	}
+
+	clientID, err := r.uc.findClientByEmail(ctx, email)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case clientID != "":
+		r.result.ClientsMatched++
+	case r.req.CreateMissingClients && r.uc.services.CreateClient != nil:
+		first, last := splitInviteeName(invitee.GetName(), email)
+		resp, err := r.uc.services.CreateClient.Execute(ctx, &clientpb.CreateClientRequest{
+			Data: &clientpb.Client{
+				User: &userpb.User{
+					FirstName:    first,
+					LastName:     last,
+					EmailAddress: email,
+					MobileNumber: invitee.GetPhone(),
+				},
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create client for %s: %w", email, err)
+		}
+		if resp == nil || len(resp.Data) == 0 {
+			return "", fmt.Errorf("client creation for %s returned no data", email)
+		}
+		clientID = resp.Data[0].Id
+		r.result.ClientsCreated++
+	default:
+		r.result.UnmatchedEmails = append(r.result.UnmatchedEmails, email)
+	}
+	r.clients[email] = clientID
+	return clientID, nil
+}
+
+// findClientByEmail returns the ID of the client whose user has the email,
+// or "" when there is none.
+func (uc *ImportSchedulesUseCase) findClientByEmail(ctx context.Context, email string) (string, error) {
+	users, err := uc.repositories.User.ListUsers(ctx, &userpb.ListUsersRequest{
+		Filters: stringEqualsFilter("email_address", email),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search for user: %w", err)
+	}
+	if users == nil || len(users.Data) == 0 {
+		return "", nil
+	}
+	clients, err := uc.repositories.Client.ListClients(ctx, &clientpb.ListClientsRequest{
+		Filters: stringEqualsFilter("user_id", users.Data[0].Id),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search for client: %w", err)
+	}
+	if clients == nil || len(clients.Data) == 0 {
+		return "", nil
+	}
+	return clients.Data[0].Id, nil
+}
+
+func (uc *ImportSchedulesUseCase) generateID(prefix string) string {
+	if uc.services.IDGenerator != nil {
+		return uc.services.IDGenerator.GenerateID()
+	}
+	return fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano())
+}
+
+func stringEqualsFilter(field, value string) *commonpb.FilterRequest {
+	return &commonpb.FilterRequest{
+		Filters: []*commonpb.TypedFilter{
+			{
+				Field: field,
+				FilterType: &commonpb.TypedFilter_StringFilter{
+					StringFilter: &commonpb.StringFilter{
+						Value:    value,
+						Operator: commonpb.StringOperator_STRING_EQUALS,
+					},
+				},
+			},
+		},
+	}
+}
+
+// importedEventID derives the event ID for a provider schedule, e.g.
+// "calendly-<uuid>", so a schedule is only ever imported once.
+func importedEventID(schedule *schedulerpb.Schedule) string {
+	if schedule.ProviderScheduleId == "" {
+		return ""
+	}
+	provider := schedule.ProviderId
+	if provider == "" {
+		provider = "schedule"
+	}
+	return provider + "-" + schedule.ProviderScheduleId
+}
+
+// importedEvent maps a provider schedule to a new event. It bypasses the
+// CreateEvent use case on purpose: imported events are in the past.
+func importedEvent(id string, schedule *schedulerpb.Schedule) (*eventpb.Event, error) {
+	start, end, err := scheduleTimes(schedule)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	name := schedule.Name
+	if name == "" {
+		name = schedule.EventTypeName
+	}
+	if name == "" {
+		name = "Imported appointment"
+	}
+	timezone := schedule.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	status := eventpb.EventStatus_EVENT_STATUS_CONFIRMED
+	if schedule.Status == schedulerpb.ScheduleStatus_SCHEDULE_STATUS_CANCELLED {
+		status = eventpb.EventStatus_EVENT_STATUS_CANCELLED
+	}
+
+	event := &eventpb.Event{
+		Id:                 id,
+		Name:               name,
+		StartDateTimeUtc:   start.UnixMilli(),
+		EndDateTimeUtc:     end.UnixMilli(),
+		Timezone:           timezone,
+		Status:             status,
+		Active:             true,
+		DateCreated:        &[]int64{now.Unix()}[0],
+		DateCreatedString:  &[]string{now.Format(time.RFC3339)}[0],
+		DateModified:       &[]int64{now.Unix()}[0],
+		DateModifiedString: &[]string{now.Format(time.RFC3339)}[0],
+	}
+	if schedule.Description != "" {
+		event.Description = &schedule.Description
+	}
+	return event, nil
+}
+
+// scheduleTimes parses a schedule's start and end in its timezone (UTC when
+// unset). A missing end falls back to the duration, then to one hour.
+func scheduleTimes(schedule *schedulerpb.Schedule) (time.Time, time.Time, error) {
+	loc := time.UTC
+	if schedule.Timezone != "" {
+		if l, err := time.LoadLocation(schedule.Timezone); err == nil {
+			loc = l
+		}
+	}
+	start, err := time.ParseInLocation("2006-01-02 15:04", schedule.StartDate+" "+schedule.StartTime, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid schedule start %q %q", schedule.StartDate, schedule.StartTime)
+	}
+	end, err := time.ParseInLocation("2006-01-02 15:04", schedule.EndDate+" "+schedule.EndTime, loc)
+	if err != nil || !end.After(start) {
+		duration := time.Hour
+		if schedule.DurationMinutes > 0 {
+			duration = time.Duration(schedule.DurationMinutes) * time.Minute
+		}
+		end = start.Add(duration)
+	}
+	return start, end, nil
+}
+
+// splitInviteeName splits a display name into the first and last names a
+// client needs, using the email's local part for a missing part.
+func splitInviteeName(name, email string) (string, string) {
+	fields := strings.Fields(name)
+	local, _, _ := strings.Cut(email, "@")
+	switch len(fields) {
+	case 0:
+		return local, local
+	case 1:
+		return fields[0], local
+	default:
+		return strings.Join(fields[:len(fields)-1], " "), fields[len(fields)-1]
+	}
+}
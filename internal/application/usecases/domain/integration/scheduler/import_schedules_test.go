@@ -0,0 +1,193 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+	clientpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/client"
+	userpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/user"
+	eventpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/event/event"
+	eventclientpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/event/event_client"
+	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
+)
+
+// fakeHistoryProvider serves two pages of past schedules and their invitees.
+type fakeHistoryProvider struct {
+	ports.SchedulerProvider
+	pages    [][]*schedulerpb.Schedule
+	invitees map[string][]*schedulerpb.InviteeInfo
+}
+
+func (p *fakeHistoryProvider) Name() string    { return "calendly" }
+func (p *fakeHistoryProvider) IsEnabled() bool { return true }
+
+func (p *fakeHistoryProvider) ListSchedules(_ context.Context, req *schedulerpb.ListSchedulesRequest) (*schedulerpb.ListSchedulesResponse, error) {
+	page := 0
+	if req.Data.PageToken == "2" {
+		page = 1
+	}
+	resp := &schedulerpb.ListSchedulesResponse{Success: true, Data: p.pages[page]}
+	if page == 0 {
+		resp.NextPageToken = "2"
+	}
+	return resp, nil
+}
+
+func (p *fakeHistoryProvider) ListScheduleInvitees(_ context.Context, id string) ([]*schedulerpb.InviteeInfo, error) {
+	return p.invitees[id], nil
+}
+
+// fakeRecords keeps users, clients, events and links in maps.
+type fakeRecords struct {
+	clientpb.ClientDomainServiceServer
+	userpb.UserDomainServiceServer
+	eventpb.EventDomainServiceServer
+	eventclientpb.EventClientDomainServiceServer
+
+	userIDs  map[string]string // email → user ID
+	clientOf map[string]string // user ID → client ID
+	events   map[string]*eventpb.Event
+	links    []*eventclientpb.EventClient
+}
+
+func (f *fakeRecords) ListUsers(_ context.Context, req *userpb.ListUsersRequest) (*userpb.ListUsersResponse, error) {
+	id, ok := f.userIDs[req.Filters.Filters[0].GetStringFilter().Value]
+	if !ok {
+		return &userpb.ListUsersResponse{}, nil
+	}
+	return &userpb.ListUsersResponse{Data: []*userpb.User{{Id: id}}}, nil
+}
+
+func (f *fakeRecords) ListClients(_ context.Context, req *clientpb.ListClientsRequest) (*clientpb.ListClientsResponse, error) {
+	id, ok := f.clientOf[req.Filters.Filters[0].GetStringFilter().Value]
+	if !ok {
+		return &clientpb.ListClientsResponse{}, nil
+	}
+	return &clientpb.ListClientsResponse{Data: []*clientpb.Client{{Id: id}}}, nil
+}
+
+func (f *fakeRecords) ReadEvent(_ context.Context, req *eventpb.ReadEventRequest) (*eventpb.ReadEventResponse, error) {
+	if e, ok := f.events[req.Data.Id]; ok {
+		return &eventpb.ReadEventResponse{Data: []*eventpb.Event{e}}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeRecords) CreateEvent(_ context.Context, req *eventpb.CreateEventRequest) (*eventpb.CreateEventResponse, error) {
+	f.events[req.Data.Id] = req.Data
+	return &eventpb.CreateEventResponse{Data: []*eventpb.Event{req.Data}}, nil
+}
+
+func (f *fakeRecords) CreateEventClient(_ context.Context, req *eventclientpb.CreateEventClientRequest) (*eventclientpb.CreateEventClientResponse, error) {
+	f.links = append(f.links, req.Data)
+	return &eventclientpb.CreateEventClientResponse{}, nil
+}
+
+// Execute satisfies ClientCreator.
+func (f *fakeRecords) Execute(_ context.Context, req *clientpb.CreateClientRequest) (*clientpb.CreateClientResponse, error) {
+	user := req.Data.User
+	f.userIDs[user.EmailAddress] = "user-new"
+	f.clientOf["user-new"] = "client-new"
+	return &clientpb.CreateClientResponse{Data: []*clientpb.Client{{Id: "client-new"}}}, nil
+}
+
+func TestImportSchedules(t *testing.T) {
+	past := time.Now().AddDate(0, -1, 0).UTC()
+	schedule := func(id string, status schedulerpb.ScheduleStatus) *schedulerpb.Schedule {
+		return &schedulerpb.Schedule{
+			ProviderScheduleId: id,
+			ProviderId:         "calendly",
+			Name:               "Consultation",
+			Status:             status,
+			StartDate:          past.Format("2006-01-02"),
+			StartTime:          "09:00",
+			EndDate:            past.Format("2006-01-02"),
+			EndTime:            "09:30",
+			Timezone:           "UTC",
+		}
+	}
+	provider := &fakeHistoryProvider{
+		pages: [][]*schedulerpb.Schedule{
+			{schedule("ev1", schedulerpb.ScheduleStatus_SCHEDULE_STATUS_ACTIVE)},
+			{schedule("ev2", schedulerpb.ScheduleStatus_SCHEDULE_STATUS_CANCELLED)},
+		},
+		invitees: map[string][]*schedulerpb.InviteeInfo{
+			"ev1": {{Name: "Ana Cruz", Email: "Ana@Example.com"}, {Name: "Ben", Email: "ben@example.com"}},
+			"ev2": {{Name: "Ben", Email: "ben@example.com"}},
+		},
+	}
+	records := &fakeRecords{
+		userIDs:  map[string]string{"ana@example.com": "user-ana"},
+		clientOf: map[string]string{"user-ana": "client-ana"},
+		events:   map[string]*eventpb.Event{},
+	}
+	uc := NewImportSchedulesUseCase(
+		ImportSchedulesRepositories{Client: records, User: records, Event: records, EventClient: records},
+		ImportSchedulesServices{
+			Provider:         provider,
+			ActionGatekeeper: testutil.OpenActionGate(),
+		},
+	)
+	req := &ImportSchedulesRequest{From: past.AddDate(0, 0, -1)}
+
+	result, err := uc.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scanned != 2 || result.Imported != 2 || result.ClientsMatched != 1 {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.UnmatchedEmails) != 1 || result.UnmatchedEmails[0] != "ben@example.com" {
+		t.Errorf("unmatched = %v, want ben only once", result.UnmatchedEmails)
+	}
+	ev1 := records.events["calendly-ev1"]
+	if ev1 == nil || ev1.Status != eventpb.EventStatus_EVENT_STATUS_CONFIRMED ||
+		ev1.EndDateTimeUtc-ev1.StartDateTimeUtc != int64(30*time.Minute/time.Millisecond) {
+		t.Errorf("ev1 = %+v", ev1)
+	}
+	if ev2 := records.events["calendly-ev2"]; ev2 == nil || ev2.Status != eventpb.EventStatus_EVENT_STATUS_CANCELLED {
+		t.Errorf("ev2 = %+v", ev2)
+	}
+	if len(records.links) != 1 || records.links[0].ClientId != "client-ana" || records.links[0].EventId != "calendly-ev1" {
+		t.Errorf("links = %+v", records.links)
+	}
+
+	// A second run skips what was imported; with creation on, Ben is added
+	// only for schedules that are new.
+	uc.services.CreateClient = records
+	req.CreateMissingClients = true
+	result, err = uc.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 0 || result.AlreadyImported != 2 || result.ClientsCreated != 0 {
+		t.Errorf("second run = %+v", result)
+	}
+
+	delete(records.events, "calendly-ev2")
+	result, _ = uc.Execute(context.Background(), req)
+	if result.Imported != 1 || result.ClientsCreated != 1 || len(records.links) != 2 || records.links[1].ClientId != "client-new" {
+		t.Errorf("third run = %+v, links = %+v", result, records.links)
+	}
+
+	if _, err := uc.Execute(context.Background(), &ImportSchedulesRequest{From: time.Now().Add(time.Hour)}); !errors.Is(err, ErrInvalidImportWindow) {
+		t.Errorf("future window error = %v", err)
+	}
+}
+
+func TestSplitInviteeName(t *testing.T) {
+	tests := []struct{ name, first, last string }{
+		{"Ana Maria Cruz", "Ana Maria", "Cruz"},
+		{"Ben", "Ben", "ben.t"},
+		{"  ", "ben.t", "ben.t"},
+	}
+	for _, tt := range tests {
+		if first, last := splitInviteeName(tt.name, "ben.t@example.com"); first != tt.first || last != tt.last {
+			t.Errorf("splitInviteeName(%q) = %q, %q", tt.name, first, last)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"log"
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
 	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
 )
@@ -18,6 +20,7 @@ type ProcessWebhookRepositories struct {
 // ProcessWebhookServices groups all service dependencies
 type ProcessWebhookServices struct {
 	Provider ports.SchedulerProvider
+	Invites  ports.ScheduleInviteSender // optional
 }
 
 // ProcessWebhookUseCase handles processing scheduler webhooks
@@ -87,9 +90,75 @@ func (uc *ProcessWebhookUseCase) Execute(ctx context.Context, req *schedulerpb.P
 		}
 	}
 
+	if response.Success && uc.services.Invites != nil {
+		for _, result := range response.Data {
+			uc.sendInvite(ctx, result)
+		}
+	}
+
+	if response.Success {
+		for _, result := range response.Data {
+			emitScheduleEvent(ctx, result)
+		}
+	}
+
 	return response, nil
 }
 
+// emitScheduleEvent announces bookings and cancellations made on the
+// provider's side. A reschedule is announced once, as the new booking.
+// Provider webhooks arrive without a session, so the workspace comes from
+// the schedule's metadata when the context has none.
+func emitScheduleEvent(ctx context.Context, result *schedulerpb.SchedulerWebhookResult) {
+	schedule := result.Schedule
+	if schedule == nil {
+		return
+	}
+	eventType := ""
+	switch result.Action {
+	case "created", "rescheduled":
+		eventType = domainevent.ScheduleCreated
+	case "cancelled":
+		if result.IsReschedule {
+			return
+		}
+		eventType = domainevent.ScheduleCancelled
+	default:
+		return
+	}
+	if contextutil.ExtractWorkspaceIDFromContext(ctx) == "" {
+		if workspaceID := schedule.Metadata["workspace_id"]; workspaceID != "" {
+			ctx = contextutil.WithWorkspaceID(ctx, workspaceID)
+		}
+	}
+	domainevent.Emit(ctx, eventType, schedule)
+}
+
+// sendInvite keeps the invitee's calendar in step with the webhook. The
+// cancellation a reschedule starts with is skipped: the invite for the new
+// booking replaces the old event instead.
+func (uc *ProcessWebhookUseCase) sendInvite(ctx context.Context, result *schedulerpb.SchedulerWebhookResult) {
+	schedule := result.Schedule
+	if schedule == nil {
+		return
+	}
+	var err error
+	switch result.Action {
+	case "created", "rescheduled":
+		err = uc.services.Invites.SendScheduleInvite(ctx, schedule, result.OldScheduleId)
+	case "cancelled":
+		if result.IsReschedule {
+			return
+		}
+		err = uc.services.Invites.SendScheduleCancellation(ctx, schedule.ProviderScheduleId, "")
+	default:
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to send calendar %s notice for %s: %v", result.Action, schedule.ProviderScheduleId, err)
+	}
+}
+
 // ToWebhookResult converts the protobuf response to a convenience type
 func ToWebhookResult(response *schedulerpb.ProcessSchedulerWebhookResponse, err error) *ports.ScheduleWebhookResult {
 	if err != nil {
@@ -26,6 +26,9 @@ type SchedulerRepositories struct {
 // SchedulerServices groups all business service dependencies for scheduler use cases
 type SchedulerServices struct {
 	Provider ports.SchedulerProvider
+	// Invites emails calendar invites for booked, moved and cancelled
+	// schedules (optional)
+	Invites ports.ScheduleInviteSender
 }
 
 // UseCases contains all scheduler integration use cases
@@ -40,6 +43,10 @@ type UseCases struct {
 	GetEventType      *GetEventTypeUseCase
 	CheckHealth       *CheckHealthUseCase
 	GetCapabilities   *GetCapabilitiesUseCase
+	// ImportSchedules backfills provider history as events. It needs the
+	// event and client repositories, so it is wired by the composition
+	// layer and nil until then.
+	ImportSchedules *ImportSchedulesUseCase
 }
 
 // NewUseCases creates a new collection of scheduler integration use cases
@@ -51,11 +58,13 @@ func NewUseCases(
 	createScheduleRepos := CreateScheduleRepositories{}
 	createScheduleServices := CreateScheduleServices{
 		Provider: services.Provider,
+		Invites:  services.Invites,
 	}
 
 	cancelScheduleRepos := CancelScheduleRepositories{}
 	cancelScheduleServices := CancelScheduleServices{
 		Provider: services.Provider,
+		Invites:  services.Invites,
 	}
 
 	getScheduleRepos := GetScheduleRepositories{}
@@ -76,6 +85,7 @@ func NewUseCases(
 	processWebhookRepos := ProcessWebhookRepositories{}
 	processWebhookServices := ProcessWebhookServices{
 		Provider: services.Provider,
+		Invites:  services.Invites,
 	}
 
 	listEventTypesRepos := ListEventTypesRepositories{}
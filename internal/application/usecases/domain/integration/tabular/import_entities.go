@@ -0,0 +1,709 @@
+package tabular
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports/integration"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+	clientpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/client"
+	locationpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/location"
+	staffpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/staff"
+	tabularpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/tabular"
+)
+
+// importPageSize is the number of records requested per ReadRecords call
+// from providers that cannot stream.
+const importPageSize = 500
+
+// ErrImportUnavailable is returned when the importer is not wired, the
+// tabular provider is disabled, or nothing can create the mapped entity.
+var ErrImportUnavailable = errors.New("entity import is not available")
+
+// ErrInvalidImport is returned for a request without a source or with a
+// mapping that does not fit the entity schema.
+var ErrInvalidImport = errors.New("invalid import request")
+
+// ClientCreator creates clients; the entity CreateClient use case
+// satisfies it. Validate runs the same checks without writing.
+type ClientCreator interface {
+	Execute(ctx context.Context, req *clientpb.CreateClientRequest) (*clientpb.CreateClientResponse, error)
+	Validate(ctx context.Context, req *clientpb.CreateClientRequest) error
+}
+
+// StaffCreator creates staff; the entity CreateStaff use case satisfies it.
+type StaffCreator interface {
+	Execute(ctx context.Context, req *staffpb.CreateStaffRequest) (*staffpb.CreateStaffResponse, error)
+	Validate(ctx context.Context, req *staffpb.CreateStaffRequest) error
+}
+
+// LocationCreator creates locations; the entity CreateLocation use case
+// satisfies it.
+type LocationCreator interface {
+	Execute(ctx context.Context, req *locationpb.CreateLocationRequest) (*locationpb.CreateLocationResponse, error)
+	Validate(ctx context.Context, req *locationpb.CreateLocationRequest) error
+}
+
+// ImportEntitiesRepositories groups all repository dependencies
+type ImportEntitiesRepositories struct {
+	// No repositories needed - rows are written through the entity use cases
+}
+
+// ImportEntitiesServices groups all service dependencies. An entity can be
+// imported only when its creator is set.
+type ImportEntitiesServices struct {
+	Provider         integration.TabularSourceProvider
+	ActionGatekeeper *actiongate.ActionGatekeeper
+	CreateClient     ClientCreator
+	CreateStaff      StaffCreator
+	CreateLocation   LocationCreator
+}
+
+// ImportMapping declares how the columns of a table map to an entity.
+//
+//	{"entity": "client",
+//	 "columns": {"First Name": "user.first_name", "Email": "user.email_address", "City": "city"},
+//	 "required": ["user.email_address"],
+//	 "defaults": {"status": "prospect"}}
+type ImportMapping struct {
+	// Entity is the kind of record each row creates: client, staff or
+	// location.
+	Entity string `json:"entity"`
+	// Columns maps column headers to entity fields. Fields are dotted paths
+	// of proto or JSON field names ending in a scalar or enum field.
+	// Headers match case-insensitively and ignoring surrounding spaces;
+	// columns not listed are ignored.
+	Columns map[string]string `json:"columns"`
+	// Required lists fields a row must fill, from a column or a default.
+	Required []string `json:"required,omitempty"`
+	// Defaults fills fields a row leaves empty, written as cell text.
+	Defaults map[string]string `json:"defaults,omitempty"`
+}
+
+// ImportEntitiesRequest selects the table to import and how.
+type ImportEntitiesRequest struct {
+	// SourceID identifies the source (spreadsheet ID, Notion database).
+	SourceID string `json:"source_id"`
+	// Table is the sheet or table name; the provider default when empty.
+	Table   string        `json:"table,omitempty"`
+	Mapping ImportMapping `json:"mapping"`
+	// DryRun maps and validates every row without writing any.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ImportRowError reports a row that was skipped. A row can have several.
+type ImportRowError struct {
+	// Row is the 1-based row in the table; for sheets and other sources
+	// with a header row the header is row 1.
+	Row int `json:"row"`
+	// Column is the header of the cell at fault, when there is one.
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportedRow links a table row to the entity created from it.
+type ImportedRow struct {
+	Row int    `json:"row"`
+	ID  string `json:"id"`
+}
+
+// ImportEntitiesResult is the per-row report of an import run.
+type ImportEntitiesResult struct {
+	Entity string `json:"entity"`
+	DryRun bool   `json:"dry_run"`
+	// Rows is the number of data rows read; blank rows are not counted.
+	Rows int `json:"rows"`
+	// Valid counts the rows that passed validation.
+	Valid int `json:"valid"`
+	// Imported counts the entities created; always 0 in a dry run.
+	Imported int              `json:"imported"`
+	Created  []ImportedRow    `json:"created,omitempty"`
+	Errors   []ImportRowError `json:"errors,omitempty"`
+}
+
+// ImportEntitiesUseCase imports rows of a tabular source (a Google Sheet, a
+// Notion database) as clients, staff or locations. Columns are mapped to
+// entity fields by an ImportMapping; each row is converted to the entity,
+// validated and created through the entity's own create use case, so it
+// gets the same defaults and checks as one created through the API. A row
+// that fails is reported and skipped; the run goes on.
+type ImportEntitiesUseCase struct {
+	repositories ImportEntitiesRepositories
+	services     ImportEntitiesServices
+}
+
+// NewImportEntitiesUseCase creates a new ImportEntitiesUseCase
+func NewImportEntitiesUseCase(
+	repositories ImportEntitiesRepositories,
+	services ImportEntitiesServices,
+) *ImportEntitiesUseCase {
+	return &ImportEntitiesUseCase{
+		repositories: repositories,
+		services:     services,
+	}
+}
+
+// Available reports whether the importer has a provider and can create at
+// least one kind of entity.
+func (uc *ImportEntitiesUseCase) Available() bool {
+	return uc != nil && uc.services.Provider != nil &&
+		(uc.services.CreateClient != nil || uc.services.CreateStaff != nil || uc.services.CreateLocation != nil)
+}
+
+// Execute reads the table and imports its rows. Row failures are recorded
+// in the result; an error is returned when the request is invalid or the
+// provider read fails, in which case the result, if any, covers the rows
+// imported so far.
+func (uc *ImportEntitiesUseCase) Execute(ctx context.Context, req *ImportEntitiesRequest) (*ImportEntitiesResult, error) {
+	if !uc.Available() || !uc.services.Provider.IsEnabled() {
+		return nil, ErrImportUnavailable
+	}
+	if req == nil || req.SourceID == "" {
+		return nil, fmt.Errorf("%w: source_id is required", ErrInvalidImport)
+	}
+	mapping, err := uc.compileMapping(req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.services.ActionGatekeeper.Check(ctx, &actiongate.CheckActionRequest{
+		Entity: mapping.entity,
+		Action: entityid.ActionCreate,
+	}); err != nil {
+		return nil, err
+	}
+
+	log.Printf("📥 Importing %s rows from %s %s/%s (dry run: %v)",
+		mapping.entity, uc.services.Provider.Name(), req.SourceID, req.Table, req.DryRun)
+
+	run := &entityImport{
+		req:         req,
+		mapping:     mapping,
+		workspaceID: contextutil.ExtractWorkspaceIDFromContext(ctx),
+		result:      &ImportEntitiesResult{Entity: mapping.entity, DryRun: req.DryRun},
+	}
+	err = uc.readRecords(ctx, req, func(records []*tabularpb.Record) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := run.importRecord(ctx, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("❌ %s import stopped: %v", mapping.entity, err)
+		return run.result, err
+	}
+
+	log.Printf("✅ %s import done: %d rows, %d valid, %d imported, %d errors",
+		mapping.entity, run.result.Rows, run.result.Valid, run.result.Imported, len(run.result.Errors))
+	return run.result, nil
+}
+
+// readRecords calls fn with the records of the table in order, streaming
+// them from providers that can and paging through ReadRecords otherwise.
+func (uc *ImportEntitiesUseCase) readRecords(ctx context.Context, req *ImportEntitiesRequest, fn func([]*tabularpb.Record) error) error {
+	if streamer, ok := uc.services.Provider.(integration.TabularRecordStreamer); ok {
+		return streamer.StreamRecords(ctx, req.SourceID, req.Table, 0, fn)
+	}
+	for offset := int32(0); ; {
+		resp, err := uc.services.Provider.ReadRecords(ctx, &tabularpb.ReadRecordsRequest{
+			Data: &tabularpb.ReadRecordsData{
+				SourceId: req.SourceID,
+				Selection: &tabularpb.Selection{
+					Table:   req.Table,
+					Records: &tabularpb.RecordSelection{Limit: importPageSize, Offset: offset},
+				},
+			},
+		})
+		if err == nil && !resp.GetSuccess() {
+			err = fmt.Errorf("%s", resp.GetError().GetMessage())
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read records: %w", err)
+		}
+		var records []*tabularpb.Record
+		hasMore := false
+		for _, result := range resp.Data {
+			records = append(records, result.Records...)
+			hasMore = hasMore || result.HasMore
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		if err := fn(records); err != nil {
+			return err
+		}
+		if !hasMore {
+			return nil
+		}
+		offset += int32(len(records))
+	}
+}
+
+// =============================================================================
+// Mapping
+// =============================================================================
+
+// importTarget is an entity rows can be imported as.
+type importTarget struct {
+	newMessage func() proto.Message
+	validate   func(ctx context.Context, m proto.Message) error
+	// create returns the ID of the created entity.
+	create func(ctx context.Context, m proto.Message) (string, error)
+}
+
+func (uc *ImportEntitiesUseCase) target(entity string) *importTarget {
+	switch entity {
+	case entityid.Client:
+		creator := uc.services.CreateClient
+		if creator == nil {
+			return nil
+		}
+		return &importTarget{
+			newMessage: func() proto.Message { return &clientpb.Client{} },
+			validate: func(ctx context.Context, m proto.Message) error {
+				return creator.Validate(ctx, &clientpb.CreateClientRequest{Data: m.(*clientpb.Client)})
+			},
+			create: func(ctx context.Context, m proto.Message) (string, error) {
+				resp, err := creator.Execute(ctx, &clientpb.CreateClientRequest{Data: m.(*clientpb.Client)})
+				if err != nil {
+					return "", err
+				}
+				if len(resp.GetData()) > 0 {
+					return resp.Data[0].GetId(), nil
+				}
+				return m.(*clientpb.Client).GetId(), nil
+			},
+		}
+	case entityid.Staff:
+		creator := uc.services.CreateStaff
+		if creator == nil {
+			return nil
+		}
+		return &importTarget{
+			newMessage: func() proto.Message { return &staffpb.Staff{} },
+			validate: func(ctx context.Context, m proto.Message) error {
+				return creator.Validate(ctx, &staffpb.CreateStaffRequest{Data: m.(*staffpb.Staff)})
+			},
+			create: func(ctx context.Context, m proto.Message) (string, error) {
+				resp, err := creator.Execute(ctx, &staffpb.CreateStaffRequest{Data: m.(*staffpb.Staff)})
+				if err != nil {
+					return "", err
+				}
+				if len(resp.GetData()) > 0 {
+					return resp.Data[0].GetId(), nil
+				}
+				return m.(*staffpb.Staff).GetId(), nil
+			},
+		}
+	case entityid.Location:
+		creator := uc.services.CreateLocation
+		if creator == nil {
+			return nil
+		}
+		return &importTarget{
+			newMessage: func() proto.Message { return &locationpb.Location{} },
+			validate: func(ctx context.Context, m proto.Message) error {
+				return creator.Validate(ctx, &locationpb.CreateLocationRequest{Data: m.(*locationpb.Location)})
+			},
+			create: func(ctx context.Context, m proto.Message) (string, error) {
+				resp, err := creator.Execute(ctx, &locationpb.CreateLocationRequest{Data: m.(*locationpb.Location)})
+				if err != nil {
+					return "", err
+				}
+				if len(resp.GetData()) > 0 {
+					return resp.Data[0].GetId(), nil
+				}
+				return m.(*locationpb.Location).GetId(), nil
+			},
+		}
+	}
+	return nil
+}
+
+// fieldRef is a mapped entity field: its path as written in the mapping
+// and the field descriptors leading to it.
+type fieldRef struct {
+	name string
+	path []protoreflect.FieldDescriptor
+}
+
+// compiledMapping is an ImportMapping resolved against the entity schema.
+type compiledMapping struct {
+	entity string
+	target *importTarget
+	// columns maps normalized headers to their header and field.
+	columns  map[string]mappedColumn
+	required []fieldRef
+	defaults []defaultValue
+}
+
+type mappedColumn struct {
+	header string
+	field  fieldRef
+}
+
+type defaultValue struct {
+	field fieldRef
+	text  string
+}
+
+// compileMapping resolves every field of the mapping against the entity
+// schema, so a typo fails the request instead of every row.
+func (uc *ImportEntitiesUseCase) compileMapping(m ImportMapping) (*compiledMapping, error) {
+	entity := strings.ToLower(strings.TrimSpace(m.Entity))
+	switch entity {
+	case entityid.Client, entityid.Staff, entityid.Location:
+	default:
+		return nil, fmt.Errorf("%w: entity %q cannot be imported; use client, staff or location", ErrInvalidImport, m.Entity)
+	}
+	target := uc.target(entity)
+	if target == nil {
+		return nil, fmt.Errorf("%w: %s import is not configured", ErrImportUnavailable, entity)
+	}
+	if len(m.Columns) == 0 {
+		return nil, fmt.Errorf("%w: the mapping has no columns", ErrInvalidImport)
+	}
+
+	md := target.newMessage().ProtoReflect().Descriptor()
+	compiled := &compiledMapping{entity: entity, target: target, columns: make(map[string]mappedColumn, len(m.Columns))}
+	filled := make(map[protoreflect.FullName]bool)
+	for header, field := range m.Columns {
+		key := normalizeColumn(header)
+		if _, dup := compiled.columns[key]; dup {
+			return nil, fmt.Errorf("%w: column %q is mapped twice", ErrInvalidImport, header)
+		}
+		ref, err := resolveImportField(md, field)
+		if err != nil {
+			return nil, err
+		}
+		leaf := ref.path[len(ref.path)-1].FullName()
+		if filled[leaf] {
+			return nil, fmt.Errorf("%w: field %s is mapped from two columns", ErrInvalidImport, field)
+		}
+		filled[leaf] = true
+		compiled.columns[key] = mappedColumn{header: strings.TrimSpace(header), field: ref}
+	}
+	for field, text := range m.Defaults {
+		ref, err := resolveImportField(md, field)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := parseImportValue(ref.path[len(ref.path)-1], text); err != nil {
+			return nil, fmt.Errorf("%w: default for %s: %v", ErrInvalidImport, field, err)
+		}
+		compiled.defaults = append(compiled.defaults, defaultValue{field: ref, text: text})
+		filled[ref.path[len(ref.path)-1].FullName()] = true
+	}
+	for _, field := range m.Required {
+		ref, err := resolveImportField(md, field)
+		if err != nil {
+			return nil, err
+		}
+		if !filled[ref.path[len(ref.path)-1].FullName()] {
+			return nil, fmt.Errorf("%w: required field %s has no column or default", ErrInvalidImport, field)
+		}
+		compiled.required = append(compiled.required, ref)
+	}
+	return compiled, nil
+}
+
+// resolveImportField resolves a dotted field path, by proto or JSON name,
+// to a singular scalar or enum field, through singular message fields.
+func resolveImportField(md protoreflect.MessageDescriptor, field string) (fieldRef, error) {
+	ref := fieldRef{name: field}
+	segments := strings.Split(strings.TrimSpace(field), ".")
+	for i, segment := range segments {
+		fd := md.Fields().ByName(protoreflect.Name(segment))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(segment)
+		}
+		if fd == nil {
+			return fieldRef{}, fmt.Errorf("%w: %s has no field %q", ErrInvalidImport, md.Name(), segment)
+		}
+		if fd.IsList() || fd.IsMap() {
+			return fieldRef{}, fmt.Errorf("%w: %s is a list; only single values can be imported", ErrInvalidImport, field)
+		}
+		ref.path = append(ref.path, fd)
+		isMessage := fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind
+		if i < len(segments)-1 {
+			if !isMessage {
+				return fieldRef{}, fmt.Errorf("%w: %s: %s is not a message", ErrInvalidImport, field, segment)
+			}
+			md = fd.Message()
+			continue
+		}
+		if isMessage || fd.Kind() == protoreflect.BytesKind {
+			return fieldRef{}, fmt.Errorf("%w: %s is not a text, number, boolean or enum field", ErrInvalidImport, field)
+		}
+	}
+	return ref, nil
+}
+
+// =============================================================================
+// Rows
+// =============================================================================
+
+// entityImport holds the state of one import run.
+type entityImport struct {
+	req         *ImportEntitiesRequest
+	mapping     *compiledMapping
+	workspaceID string
+	// header holds the normalized headers of a source whose records carry
+	// positional values; the first record is the header row.
+	header []string
+	// read counts the records seen, the header row included.
+	read   int
+	result *ImportEntitiesResult
+}
+
+// importRecord imports one record. Only a source that lacks a required
+// column returns an error; row failures go to the result.
+func (r *entityImport) importRecord(ctx context.Context, record *tabularpb.Record) error {
+	r.read++
+	row := r.read
+
+	cells := make(map[string]*tabularpb.FieldValue)
+	if len(record.NamedValues) > 0 {
+		for name, value := range record.NamedValues {
+			cells[normalizeColumn(name)] = value
+		}
+	} else {
+		if r.header == nil {
+			return r.readHeader(record)
+		}
+		for i, value := range record.Values {
+			if i < len(r.header) {
+				cells[r.header[i]] = value
+			}
+		}
+	}
+
+	m := r.mapping.target.newMessage()
+	msg := m.ProtoReflect()
+	set := make(map[protoreflect.FullName]bool)
+	var rowErrors []ImportRowError
+	for key, column := range r.mapping.columns {
+		text, err := cellText(cells[key])
+		if err == nil {
+			if text = strings.TrimSpace(text); text == "" {
+				continue
+			}
+			err = setImportField(msg, column.field, text)
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: row, Column: column.header, Message: err.Error()})
+			continue
+		}
+		set[column.field.path[len(column.field.path)-1].FullName()] = true
+	}
+	if len(set) == 0 && len(rowErrors) == 0 {
+		// A blank row, such as the trailing rows of a sheet
+		return nil
+	}
+	r.result.Rows++
+	if len(rowErrors) > 0 {
+		// Columns are visited in map order
+		sort.Slice(rowErrors, func(i, j int) bool { return rowErrors[i].Column < rowErrors[j].Column })
+		r.result.Errors = append(r.result.Errors, rowErrors...)
+		return nil
+	}
+
+	for _, d := range r.mapping.defaults {
+		if leaf := d.field.path[len(d.field.path)-1].FullName(); !set[leaf] {
+			_ = setImportField(msg, d.field, d.text)
+			set[leaf] = true
+		}
+	}
+	var missing []string
+	for _, ref := range r.mapping.required {
+		if !set[ref.path[len(ref.path)-1].FullName()] {
+			missing = append(missing, ref.name)
+		}
+	}
+	if len(missing) > 0 {
+		r.fail(row, "missing "+strings.Join(missing, ", "))
+		return nil
+	}
+	if fd := msg.Descriptor().Fields().ByName("workspace_id"); fd != nil && fd.Kind() == protoreflect.StringKind &&
+		r.workspaceID != "" && !msg.Has(fd) {
+		msg.Set(fd, protoreflect.ValueOfString(r.workspaceID))
+	}
+
+	if r.req.DryRun {
+		if err := r.mapping.target.validate(ctx, m); err != nil {
+			r.fail(row, err.Error())
+			return nil
+		}
+		r.result.Valid++
+		return nil
+	}
+	id, err := r.mapping.target.create(ctx, m)
+	if err != nil {
+		r.fail(row, err.Error())
+		return nil
+	}
+	r.result.Valid++
+	r.result.Imported++
+	r.result.Created = append(r.result.Created, ImportedRow{Row: row, ID: id})
+	return nil
+}
+
+// readHeader takes the headers from the first record of a positional
+// source and checks every required field has its column.
+func (r *entityImport) readHeader(record *tabularpb.Record) error {
+	r.header = make([]string, len(record.Values))
+	present := make(map[string]bool, len(record.Values))
+	for i, value := range record.Values {
+		text, _ := cellText(value)
+		r.header[i] = normalizeColumn(text)
+		present[r.header[i]] = true
+	}
+
+	defaulted := make(map[protoreflect.FullName]bool)
+	for _, d := range r.mapping.defaults {
+		defaulted[d.field.path[len(d.field.path)-1].FullName()] = true
+	}
+	var missing []string
+	for key, column := range r.mapping.columns {
+		leaf := column.field.path[len(column.field.path)-1].FullName()
+		if present[key] || defaulted[leaf] {
+			continue
+		}
+		for _, ref := range r.mapping.required {
+			if ref.path[len(ref.path)-1].FullName() == leaf {
+				missing = append(missing, column.header)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: the table has no column %s for a required field", ErrInvalidImport, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (r *entityImport) fail(row int, message string) {
+	r.result.Errors = append(r.result.Errors, ImportRowError{Row: row, Message: message})
+}
+
+// setImportField converts text to the field's type and sets it, creating
+// the messages along the path.
+func setImportField(msg protoreflect.Message, ref fieldRef, text string) error {
+	for _, fd := range ref.path[:len(ref.path)-1] {
+		msg = msg.Mutable(fd).Message()
+	}
+	leaf := ref.path[len(ref.path)-1]
+	value, err := parseImportValue(leaf, text)
+	if err != nil {
+		return err
+	}
+	msg.Set(leaf, value)
+	return nil
+}
+
+// parseImportValue converts cell text to a value of the field's kind.
+func parseImportValue(fd protoreflect.FieldDescriptor, text string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(text), nil
+	case protoreflect.BoolKind:
+		switch strings.ToLower(text) {
+		case "true", "yes", "y", "1":
+			return protoreflect.ValueOfBool(true), nil
+		case "false", "no", "n", "0":
+			return protoreflect.ValueOfBool(false), nil
+		}
+		return protoreflect.Value{}, fmt.Errorf("%q is not true or false", text)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(text, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%q is not a whole number", text)
+		}
+		return protoreflect.ValueOfInt32(int32(n)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%q is not a whole number", text)
+		}
+		return protoreflect.ValueOfInt64(n), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(text, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%q is not a whole number of 0 or more", text)
+		}
+		return protoreflect.ValueOfUint32(uint32(n)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%q is not a whole number of 0 or more", text)
+		}
+		return protoreflect.ValueOfUint64(n), nil
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(text, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%q is not a number", text)
+		}
+		return protoreflect.ValueOfFloat32(float32(f)), nil
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%q is not a number", text)
+		}
+		return protoreflect.ValueOfFloat64(f), nil
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			if strings.EqualFold(string(values.Get(i).Name()), text) {
+				return protoreflect.ValueOfEnum(values.Get(i).Number()), nil
+			}
+		}
+		if n, err := strconv.ParseInt(text, 10, 32); err == nil && values.ByNumber(protoreflect.EnumNumber(n)) != nil {
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+		}
+		return protoreflect.Value{}, fmt.Errorf("%q is not a %s value", text, fd.Enum().Name())
+	}
+	return protoreflect.Value{}, fmt.Errorf("%s fields cannot be imported", fd.Kind())
+}
+
+// cellText returns the text of a cell. Cells holding an error, a formula
+// or structured data have none.
+func cellText(v *tabularpb.FieldValue) (string, error) {
+	switch v := v.GetValue().(type) {
+	case nil:
+		return "", nil
+	case *tabularpb.FieldValue_StringValue:
+		return v.StringValue, nil
+	case *tabularpb.FieldValue_IntegerValue:
+		return strconv.FormatInt(v.IntegerValue, 10), nil
+	case *tabularpb.FieldValue_FloatValue:
+		return strconv.FormatFloat(v.FloatValue, 'f', -1, 64), nil
+	case *tabularpb.FieldValue_BooleanValue:
+		return strconv.FormatBool(v.BooleanValue), nil
+	case *tabularpb.FieldValue_DateValue:
+		return v.DateValue, nil
+	case *tabularpb.FieldValue_DatetimeValue:
+		return v.DatetimeValue, nil
+	case *tabularpb.FieldValue_ErrorValue:
+		return "", fmt.Errorf("cell shows the error %s", v.ErrorValue)
+	case *tabularpb.FieldValue_FormulaValue:
+		return "", errors.New("cell holds a formula instead of its value")
+	}
+	return "", errors.New("cell holds structured data instead of a single value")
+}
+
+func normalizeColumn(header string) string {
+	return strings.ToLower(strings.TrimSpace(header))
+}
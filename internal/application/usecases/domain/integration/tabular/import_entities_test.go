@@ -0,0 +1,228 @@
+package tabular
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports/integration"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+	clientpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/client"
+	locationpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/location"
+	tabularpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/tabular"
+)
+
+func text(s string) *tabularpb.FieldValue {
+	return &tabularpb.FieldValue{Value: &tabularpb.FieldValue_StringValue{StringValue: s}}
+}
+
+// sheetProvider streams positional rows, header first, like Google Sheets.
+type sheetProvider struct {
+	integration.TabularSourceProvider
+	rows [][]string
+}
+
+func (p *sheetProvider) Name() string    { return "sheet" }
+func (p *sheetProvider) IsEnabled() bool { return true }
+
+func (p *sheetProvider) StreamRecords(_ context.Context, _, _ string, _ int, fn func([]*tabularpb.Record) error) error {
+	var records []*tabularpb.Record
+	for i, row := range p.rows {
+		record := &tabularpb.Record{Index: int64(i)}
+		for _, cell := range row {
+			record.Values = append(record.Values, text(cell))
+		}
+		records = append(records, record)
+	}
+	return fn(records)
+}
+
+// pagedProvider serves named values one record per page, like Notion.
+type pagedProvider struct {
+	integration.TabularSourceProvider
+	records []map[string]string
+}
+
+func (p *pagedProvider) Name() string    { return "paged" }
+func (p *pagedProvider) IsEnabled() bool { return true }
+
+func (p *pagedProvider) ReadRecords(_ context.Context, req *tabularpb.ReadRecordsRequest) (*tabularpb.ReadRecordsResponse, error) {
+	offset := int(req.Data.Selection.Records.Offset)
+	if offset >= len(p.records) {
+		return &tabularpb.ReadRecordsResponse{Success: true}, nil
+	}
+	record := &tabularpb.Record{NamedValues: map[string]*tabularpb.FieldValue{}}
+	for k, v := range p.records[offset] {
+		record.NamedValues[k] = text(v)
+	}
+	return &tabularpb.ReadRecordsResponse{Success: true, Data: []*tabularpb.ReadRecordsResult{{
+		Records: []*tabularpb.Record{record},
+		HasMore: offset+1 < len(p.records),
+	}}}, nil
+}
+
+// fakeClients creates clients whose user has an email.
+type fakeClients struct {
+	created []*clientpb.Client
+}
+
+func (f *fakeClients) Validate(_ context.Context, req *clientpb.CreateClientRequest) error {
+	if !strings.Contains(req.Data.GetUser().GetEmailAddress(), "@") {
+		return errors.New("Invalid email format")
+	}
+	return nil
+}
+
+func (f *fakeClients) Execute(ctx context.Context, req *clientpb.CreateClientRequest) (*clientpb.CreateClientResponse, error) {
+	if err := f.Validate(ctx, req); err != nil {
+		return nil, err
+	}
+	req.Data.Id = fmt.Sprintf("client-%d", len(f.created)+1)
+	f.created = append(f.created, req.Data)
+	return &clientpb.CreateClientResponse{Data: []*clientpb.Client{req.Data}}, nil
+}
+
+type fakeLocations struct {
+	created []*locationpb.Location
+}
+
+func (f *fakeLocations) Validate(context.Context, *locationpb.CreateLocationRequest) error {
+	return nil
+}
+
+func (f *fakeLocations) Execute(_ context.Context, req *locationpb.CreateLocationRequest) (*locationpb.CreateLocationResponse, error) {
+	req.Data.Id = fmt.Sprintf("loc-%d", len(f.created)+1)
+	f.created = append(f.created, req.Data)
+	return &locationpb.CreateLocationResponse{Data: []*locationpb.Location{req.Data}}, nil
+}
+
+func newImporter(provider integration.TabularSourceProvider, clients ClientCreator, locations LocationCreator) *ImportEntitiesUseCase {
+	return NewImportEntitiesUseCase(ImportEntitiesRepositories{}, ImportEntitiesServices{
+		Provider:         provider,
+		ActionGatekeeper: testutil.OpenActionGate(),
+		CreateClient:     clients,
+		CreateLocation:   locations,
+	})
+}
+
+var clientMapping = ImportMapping{
+	Entity: "client",
+	Columns: map[string]string{
+		"First Name":    "user.first_name",
+		"Last Name":     "user.lastName",
+		"E-mail":        "user.email_address",
+		"Credit Limit":  "credit_limit",
+		"Lead Time":     "lead_time_days",
+		"Ignored Notes": "notes",
+	},
+	Required: []string{"user.email_address"},
+	Defaults: map[string]string{"status": "prospect"},
+}
+
+var clientRows = [][]string{
+	{"first name ", "Last Name", "E-mail", "Credit Limit", "Lead Time"},
+	{"Ana", "Reyes", "ana@example.com", "50000", "3"},
+	{"Ben", "Cruz", "not-an-email", "", ""},
+	{"Cy", "Lim", "", "", ""},
+	{"", "", "", "", ""},
+	{"Dee", "Tan", "dee@example.com", "lots", "soon"},
+	{"Eve", "Go", "eve@example.com", "", ""},
+}
+
+func TestImportEntities_DryRun(t *testing.T) {
+	clients := &fakeClients{}
+	uc := newImporter(&sheetProvider{rows: clientRows}, clients, nil)
+
+	result, err := uc.Execute(context.Background(), &ImportEntitiesRequest{SourceID: "sheet-1", Mapping: clientMapping, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clients.created) != 0 {
+		t.Fatalf("dry run created %d clients", len(clients.created))
+	}
+	if result.Rows != 5 || result.Valid != 2 || result.Imported != 0 {
+		t.Fatalf("rows %d, valid %d, imported %d; want 5, 2, 0", result.Rows, result.Valid, result.Imported)
+	}
+	want := []ImportRowError{
+		{Row: 3, Message: "Invalid email format"},
+		{Row: 4, Message: "missing user.email_address"},
+		{Row: 6, Column: "Credit Limit", Message: `"lots" is not a whole number`},
+		{Row: 6, Column: "Lead Time", Message: `"soon" is not a whole number`},
+	}
+	if fmt.Sprint(result.Errors) != fmt.Sprint(want) {
+		t.Fatalf("errors =\n%v\nwant\n%v", result.Errors, want)
+	}
+}
+
+func TestImportEntities_CreatesThroughUseCase(t *testing.T) {
+	clients := &fakeClients{}
+	uc := newImporter(&sheetProvider{rows: clientRows}, clients, nil)
+	ctx := contextutil.WithWorkspaceID(context.Background(), "ws-1")
+
+	result, err := uc.Execute(ctx, &ImportEntitiesRequest{SourceID: "sheet-1", Mapping: clientMapping})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 2 || len(result.Errors) != 4 {
+		t.Fatalf("imported %d with %d errors; want 2 and 4", result.Imported, len(result.Errors))
+	}
+	if fmt.Sprint(result.Created) != "[{2 client-1} {7 client-2}]" {
+		t.Fatalf("created = %v", result.Created)
+	}
+	ana := clients.created[0]
+	if ana.GetUser().GetFirstName() != "Ana" || ana.GetCreditLimit() != 50000 || ana.GetLeadTimeDays() != 3 ||
+		ana.GetStatus() != "prospect" || ana.GetWorkspaceId() != "ws-1" {
+		t.Fatalf("client = %v", ana)
+	}
+}
+
+func TestImportEntities_PagesNamedValues(t *testing.T) {
+	locations := &fakeLocations{}
+	uc := newImporter(&pagedProvider{records: []map[string]string{
+		{"Name": "Main", "Address": "1 Rizal Ave"},
+		{"Name": "Annex", "Address": "2 Rizal Ave", "Unmapped": "x"},
+	}}, nil, locations)
+
+	result, err := uc.Execute(context.Background(), &ImportEntitiesRequest{SourceID: "db-1", Mapping: ImportMapping{
+		Entity:  "location",
+		Columns: map[string]string{"name": "name", "address": "address"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 2 || locations.created[1].GetName() != "Annex" || fmt.Sprint(result.Created) != "[{1 loc-1} {2 loc-2}]" {
+		t.Fatalf("result = %+v", result)
+	}
+}
+
+func TestImportEntities_InvalidMapping(t *testing.T) {
+	uc := newImporter(&sheetProvider{rows: clientRows}, &fakeClients{}, nil)
+	tests := map[string]ImportMapping{
+		"unknown entity":   {Entity: "invoice", Columns: map[string]string{"a": "id"}},
+		"unknown field":    {Entity: "client", Columns: map[string]string{"a": "user.phone_number_x"}},
+		"message field":    {Entity: "client", Columns: map[string]string{"a": "user"}},
+		"list field":       {Entity: "client", Columns: map[string]string{"a": "categories.name"}},
+		"unfilled require": {Entity: "client", Columns: map[string]string{"a": "notes"}, Required: []string{"city"}},
+		"bad default":      {Entity: "client", Columns: map[string]string{"a": "notes"}, Defaults: map[string]string{"credit_limit": "many"}},
+	}
+	for name, mapping := range tests {
+		if _, err := uc.Execute(context.Background(), &ImportEntitiesRequest{SourceID: "s", Mapping: mapping}); !errors.Is(err, ErrInvalidImport) {
+			t.Errorf("%s: err = %v, want ErrInvalidImport", name, err)
+		}
+	}
+
+	// A sheet without the column of a required field fails as a whole.
+	mapping := ImportMapping{Entity: "client", Columns: map[string]string{"Phone": "user.mobile_number"}, Required: []string{"user.mobile_number"}}
+	if _, err := uc.Execute(context.Background(), &ImportEntitiesRequest{SourceID: "s", Mapping: mapping}); !errors.Is(err, ErrInvalidImport) {
+		t.Errorf("missing column: err = %v, want ErrInvalidImport", err)
+	}
+
+	// Staff has no creator in this importer.
+	staff := ImportMapping{Entity: "staff", Columns: map[string]string{"a": "id"}}
+	if _, err := uc.Execute(context.Background(), &ImportEntitiesRequest{SourceID: "s", Mapping: staff}); !errors.Is(err, ErrImportUnavailable) {
+		t.Errorf("staff: err = %v, want ErrImportUnavailable", err)
+	}
+}
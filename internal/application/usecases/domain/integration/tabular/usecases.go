@@ -13,6 +13,7 @@
 //
 //   - WriteRecords: Full record write with complex Record structure and FieldValue types
 //   - WriteRecordSimple: Simplified single record write using google.protobuf.Struct for flat fields
+//   - ImportEntities: Bulk import of rows as clients, staff or locations via a column mapping
 //
 // All use cases are proto-based for consistency and maintainability.
 package tabular
@@ -45,6 +46,10 @@ type UseCases struct {
 	BatchExecute      *BatchExecuteUseCase
 	CheckHealth       *CheckHealthUseCase
 	GetCapabilities   *GetCapabilitiesUseCase
+	// ImportEntities imports rows as clients, staff or locations. It needs
+	// the entity create use cases, so it is wired by the composition layer
+	// and nil until then.
+	ImportEntities *ImportEntitiesUseCase
 }
 
 // NewUseCases creates a new collection of tabular integration use cases
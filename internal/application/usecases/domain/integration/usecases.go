@@ -53,6 +53,9 @@ func NewIntegrationUseCases(
 	schedulerProvider ports.SchedulerProvider,
 	tabularProvider ports.TabularSourceProvider,
 	integrationPaymentRepo integrationPorts.IntegrationPaymentRepository,
+	paymentDisputeRepo integrationPorts.PaymentDisputeRepository,
+	disputeNotifyEmails []string,
+	scheduleInvites ports.ScheduleInviteSender,
 ) *IntegrationUseCases {
 	var paymentUC *paymentUseCases.UseCases
 	var emailUC *emailUseCases.UseCases
@@ -63,9 +66,12 @@ func NewIntegrationUseCases(
 	if paymentProvider != nil {
 		paymentRepositories := paymentUseCases.PaymentRepositories{
 			IntegrationPayment: integrationPaymentRepo,
+			PaymentDispute:     paymentDisputeRepo,
 		}
 		paymentServices := paymentUseCases.PaymentServices{
-			Provider: paymentProvider,
+			Provider:            paymentProvider,
+			Email:               emailProvider,
+			DisputeNotifyEmails: disputeNotifyEmails,
 		}
 		paymentUC = paymentUseCases.NewUseCases(paymentRepositories, paymentServices)
 	}
@@ -84,6 +90,7 @@ func NewIntegrationUseCases(
 		schedulerRepositories := schedulerUseCases.SchedulerRepositories{}
 		schedulerServices := schedulerUseCases.SchedulerServices{
 			Provider: schedulerProvider,
+			Invites:  scheduleInvites,
 		}
 		schedulerUC = schedulerUseCases.NewUseCases(schedulerRepositories, schedulerServices)
 	}
@@ -22,6 +22,8 @@ type StampRequestSLABreachesServices struct {
 	ActionGatekeeper *actiongate.ActionGatekeeper
 	Transactor       ports.Transactor
 	Translator       ports.Translator
+	// Clock decides which SLAs are past due. nil means wall-clock time.
+	Clock ports.Clock
 }
 
 // StampRequestSLABreachesUseCase sweeps open requests past sla_due_at and
@@ -47,6 +49,15 @@ func NewStampRequestSLABreachesUseCase(repositories StampRequestSLABreachesRepos
 	return &StampRequestSLABreachesUseCase{repositories: repositories, services: services}
 }
 
+// SetClock installs the clock the sweep compares sla_due_at against. Safe
+// to call with nil — falls back to wall-clock time.
+func (uc *StampRequestSLABreachesUseCase) SetClock(clock ports.Clock) {
+	if uc == nil {
+		return
+	}
+	uc.services.Clock = clock
+}
+
 // StampRequestSLABreachesRequest is the Go-shaped input. The sweep is
 // workspace-scoped (workspace_id from context).
 type StampRequestSLABreachesRequest struct{}
@@ -73,7 +84,8 @@ func (uc *StampRequestSLABreachesUseCase) Execute(ctx context.Context, req *Stam
 		return &StampRequestSLABreachesResponse{StampedCount: 0}, nil
 	}
 
-	now := time.Now().UnixMilli()
+	clockNow := ports.ClockNow(uc.services.Clock)
+	now := clockNow.UnixMilli()
 	var toStamp []*work_requestpb.WorkRequest
 
 	for _, wr := range listResp.Data {
@@ -99,8 +111,8 @@ func (uc *StampRequestSLABreachesUseCase) Execute(ctx context.Context, req *Stam
 		for _, wr := range toStamp {
 			breachedAt := *wr.SlaDueAt
 			wr.SlaBreachedAt = &breachedAt
-			nowMilli := time.Now().UnixMilli()
-			nowStr := time.Now().Format(time.RFC3339)
+			nowMilli := now
+			nowStr := clockNow.Format(time.RFC3339)
 			wr.DateModified = &nowMilli
 			wr.DateModifiedString = &nowStr
 			if _, updateErr := uc.repositories.WorkRequest.UpdateWorkRequest(c, &work_requestpb.UpdateWorkRequestRequest{Data: wr}); updateErr != nil {
@@ -136,29 +136,32 @@ func (uc *CreateRevenueUseCase) executeCore(ctx context.Context, req *revenuepb.
 			Data: &paymenttermpb.PaymentTerm{Id: *req.Data.PaymentTermId},
 		})
 		if err == nil && len(ptResp.Data) > 0 {
-			pt := ptResp.Data[0]
-			baseDateStr := req.Data.GetRevenueDate()
-			baseDate, parseErr := time.Parse("2006-01-02", baseDateStr)
-			if parseErr == nil {
-				ptType := strings.ToLower(pt.Type)
-				var dueDateStr string
-				switch ptType {
-				case "net":
-					dueDateStr = baseDate.AddDate(0, 0, int(pt.NetDays)).Format("2006-01-02")
-				case "due_on_receipt", "cod":
-					dueDateStr = baseDateStr
-				case "proximate":
-					if day := int(pt.GetProximateDay()); day >= 1 && day <= 28 {
-						next := time.Date(baseDate.Year(), baseDate.Month()+1, day, 0, 0, 0, 0, time.UTC)
-						dueDateStr = next.Format("2006-01-02")
-					}
-				}
-				if dueDateStr != "" {
-					req.Data.DueDate = &dueDateStr
-				}
+			if dueDateStr := dueDateFromPaymentTerm(ptResp.Data[0], req.Data.GetRevenueDate()); dueDateStr != "" {
+				req.Data.DueDate = &dueDateStr
 			}
 		}
 	}
 
 	return uc.repositories.Revenue.CreateRevenue(ctx, req)
 }
+
+// dueDateFromPaymentTerm returns the YYYY-MM-DD due date of a revenue dated
+// revenueDate under payment term pt, or "" when the term or date does not
+// determine one.
+func dueDateFromPaymentTerm(pt *paymenttermpb.PaymentTerm, revenueDate string) string {
+	baseDate, err := time.Parse("2006-01-02", revenueDate)
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(pt.GetType()) {
+	case "net":
+		return baseDate.AddDate(0, 0, int(pt.GetNetDays())).Format("2006-01-02")
+	case "due_on_receipt", "cod":
+		return revenueDate
+	case "proximate":
+		if day := int(pt.GetProximateDay()); day >= 1 && day <= 28 {
+			return time.Date(baseDate.Year(), baseDate.Month()+1, day, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+		}
+	}
+	return ""
+}
@@ -74,6 +74,9 @@ type ListRevenueRunCandidatesServices struct {
 	// proto-typed IO. Wired by the composition root via
 	// serviceamortization.From(serviceUseCases).
 	Amortization *serviceamortization.ComputeNextDueTrancheUseCase
+	// Clock supplies "today" when the scope has no AsOfDate. nil means
+	// wall-clock time.
+	Clock ports.Clock
 }
 
 // ListRevenueRunCandidatesUseCase enumerates pending billing periods for the
@@ -98,6 +101,15 @@ func NewListRevenueRunCandidatesUseCase(
 	}
 }
 
+// SetClock installs the clock used to default AsOfDate. Safe to call with
+// nil — falls back to wall-clock time.
+func (uc *ListRevenueRunCandidatesUseCase) SetClock(clock ports.Clock) {
+	if uc == nil {
+		return
+	}
+	uc.services.Clock = clock
+}
+
 // Execute returns the list of un-invoiced period candidates for the scope.
 // When req.Limit == 0 the full result set is returned (no cursor used).
 //
@@ -151,7 +163,7 @@ func (uc *ListRevenueRunCandidatesUseCase) Execute(
 	// 3. Resolve AsOfDate — default to today (in the workspace's tz)
 	asOfDate := strings.TrimSpace(scope.AsOfDate)
 	if asOfDate == "" {
-		asOfDate = time.Now().In(loc).Format("2006-01-02")
+		asOfDate = ports.ClockNow(uc.services.Clock).In(loc).Format("2006-01-02")
 	}
 	asOfTime, err := time.ParseInLocation("2006-01-02", asOfDate, loc)
 	if err != nil {
@@ -108,6 +108,10 @@ type RecognizeRevenueFromSubscriptionServices struct {
 	// entity-layer package. Failure semantics are unchanged — the wrapper's
 	// ExecuteForRevenue is a thin pass-through to the entity compute.
 	ComputeTaxes ComputeTaxesForRevenueInvoker
+
+	// Clock supplies the default revenue date and billed_at stamps. nil
+	// means wall-clock time; sandboxes install a TravelClock via SetClock.
+	Clock ports.Clock
 }
 
 // MaterializeInstanceJobsForSubscriptionInvoker is the narrow contract for
@@ -187,6 +191,19 @@ func (uc *RecognizeRevenueFromSubscriptionUseCase) SetComputeTaxes(
 	uc.services.ComputeTaxes = invoker
 }
 
+// SetClock installs the clock used for revenue dates and billing stamps.
+// Safe to call with nil — falls back to wall-clock time.
+func (uc *RecognizeRevenueFromSubscriptionUseCase) SetClock(clock ports.Clock) {
+	if uc == nil {
+		return
+	}
+	uc.services.Clock = clock
+}
+
+func (uc *RecognizeRevenueFromSubscriptionUseCase) now() time.Time {
+	return ports.ClockNow(uc.services.Clock)
+}
+
 // Execute orchestrates the revenue recognition flow. The shape of the request
 // matches the CreateRevenueWithLineItems RPC; when dry_run is set the use case
 // returns a preview without writing.
@@ -467,7 +484,7 @@ func (uc *RecognizeRevenueFromSubscriptionUseCase) executeCore(
 	// 10. Build header per plan §3.4
 	revenueDate := strings.TrimSpace(req.GetRevenueDate())
 	if revenueDate == "" {
-		revenueDate = time.Now().UTC().Format("2006-01-02")
+		revenueDate = uc.now().UTC().Format("2006-01-02")
 	}
 
 	// Compute total based on amount basis.
@@ -1179,7 +1196,7 @@ func (uc *RecognizeRevenueFromSubscriptionUseCase) buildHeader(
 func (uc *RecognizeRevenueFromSubscriptionUseCase) persistRevenue(
 	ctx context.Context, header *revenuepb.Revenue,
 ) (*revenuepb.Revenue, error) {
-	now := time.Now()
+	now := uc.now()
 	if header.Id == "" && uc.services.IDGenerator != nil {
 		header.Id = uc.services.IDGenerator.GenerateID()
 	}
@@ -1538,7 +1555,7 @@ func (uc *RecognizeRevenueFromSubscriptionUseCase) executeMilestone(
 	// 6. Atomic write — Revenue header, line items, then BillingEvent mutation.
 	revenueDate := strings.TrimSpace(req.GetRevenueDate())
 	if revenueDate == "" {
-		revenueDate = time.Now().UTC().Format("2006-01-02")
+		revenueDate = uc.now().UTC().Format("2006-01-02")
 	}
 
 	header := uc.buildHeader(req, sub, pricePlan, priceSchedule, client, planCurrency, "", "", revenueDate, target, nil)
@@ -1574,7 +1591,7 @@ func (uc *RecognizeRevenueFromSubscriptionUseCase) executeMilestone(
 	mutated.BillableAmount = target
 	revenueID := createdRevenue.GetId()
 	mutated.RevenueId = &revenueID
-	billedAt := time.Now().UnixMilli()
+	billedAt := uc.now().UnixMilli()
 	mutated.BilledAt = &billedAt
 	if reason := strings.TrimSpace(req.GetPartialReason()); reason != "" {
 		mutated.Reason = &reason
@@ -1615,7 +1632,7 @@ func (uc *RecognizeRevenueFromSubscriptionUseCase) executeMilestone(
 		if uc.services.IDGenerator != nil {
 			child.Id = uc.services.IDGenerator.GenerateID()
 		}
-		now := time.Now()
+		now := uc.now()
 		dc := now.UnixMilli()
 		dcs := now.Format(time.RFC3339)
 		child.DateCreated = &dc
@@ -1763,7 +1780,7 @@ func (uc *RecognizeRevenueFromSubscriptionUseCase) executeAdHocPool(
 
 	revenueDate := strings.TrimSpace(req.GetRevenueDate())
 	if revenueDate == "" {
-		revenueDate = time.Now().UTC().Format("2006-01-02")
+		revenueDate = uc.now().UTC().Format("2006-01-02")
 	}
 
 	// buildHeader mixes the period suffix into the name + builds the notes
@@ -1935,7 +1952,7 @@ func (uc *RecognizeRevenueFromSubscriptionUseCase) executeAdHocPerCall(
 
 	revenueDate := strings.TrimSpace(req.GetRevenueDate())
 	if revenueDate == "" {
-		revenueDate = time.Now().UTC().Format("2006-01-02")
+		revenueDate = uc.now().UTC().Format("2006-01-02")
 	}
 
 	header := uc.buildHeader(req, sub, pricePlan, priceSchedule, client, planCurrency,
@@ -1970,7 +1987,7 @@ func (uc *RecognizeRevenueFromSubscriptionUseCase) executeAdHocPerCall(
 	mutated.BillableAmount = target
 	revenueID := createdRevenue.GetId()
 	mutated.RevenueId = &revenueID
-	billedAt := time.Now().UnixMilli()
+	billedAt := uc.now().UnixMilli()
 	mutated.BilledAt = &billedAt
 	if _, err := uc.repositories.BillingEvent.UpdateBillingEvent(
 		ctx, &billingeventpb.UpdateBillingEventRequest{Data: mutated},
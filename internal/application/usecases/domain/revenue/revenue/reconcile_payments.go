@@ -0,0 +1,524 @@
+package revenue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	infraports "github.com/erniealice/espyna-golang/internal/application/ports/infrastructure"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+	revenuepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue"
+	revenuepaymentpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue_payment"
+	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
+)
+
+// Mismatch kinds reported by ReconcilePayments.
+const (
+	// MismatchPaidNotRecorded — the provider captured the payment but the
+	// revenue is not settled. The only kind AutoCorrect fixes.
+	MismatchPaidNotRecorded = "paid_not_recorded"
+	// MismatchAmount — the provider captured a different amount than the
+	// revenue's total.
+	MismatchAmount = "amount_mismatch"
+	// MismatchReversed — the revenue is settled but the provider refunded
+	// or reversed the payment.
+	MismatchReversed = "reversed_at_provider"
+)
+
+// DefaultReconcileDays is how far back ReconcilePayments searches provider
+// transactions when the request sets no Since date.
+const DefaultReconcileDays = 30
+
+const settlementStatusFullySettled = "FULLY_SETTLED"
+
+// ReconcilePaymentsRequest scopes one reconciliation to a workspace.
+type ReconcilePaymentsRequest struct {
+	WorkspaceID string
+	// Since (YYYY-MM-DD) starts the provider transaction search; defaults
+	// to DefaultReconcileDays ago.
+	Since string
+	// AutoCorrect settles the revenues the provider reports paid, records
+	// the payment and writes an audit entry. Other mismatches are only
+	// reported: they need a person to decide.
+	AutoCorrect bool
+}
+
+// PaymentMismatch is one revenue whose local state disagrees with its
+// payment provider.
+type PaymentMismatch struct {
+	RevenueID        string
+	Provider         string
+	ProviderRef      string // the provider's transaction reference
+	Kind             string
+	SettlementStatus string // the revenue's, before any correction
+	ProviderStatus   paymentpb.PaymentStatus
+	Amount           int64 // the revenue's total
+	ProviderAmount   int64
+	Corrected        bool
+}
+
+// ReconcilePaymentsResult reports what one reconciliation found.
+type ReconcilePaymentsResult struct {
+	WorkspaceID string
+	Since       string
+	// Checked counts the revenues with a checkout compared to the provider;
+	// Unavailable those whose provider could not be asked.
+	Checked     int
+	Matched     int
+	Unavailable int
+	Mismatches  []PaymentMismatch
+	Corrected   int
+}
+
+// ReconcilePaymentsRepositories groups all repository dependencies.
+type ReconcilePaymentsRepositories struct {
+	Revenue        revenuepb.RevenueDomainServiceServer
+	RevenuePayment revenuepaymentpb.RevenuePaymentDomainServiceServer
+}
+
+// ReconcilePaymentsServices groups all business service dependencies.
+type ReconcilePaymentsServices struct {
+	Translator       ports.Translator
+	ActionGatekeeper *actiongate.ActionGatekeeper
+	IDGenerator      ports.IDGenerator
+	// Payment and PaymentProviders are asked for the status of each
+	// revenue's checkout, as in RunRecurringBillingServices.
+	Payment          ports.PaymentProvider
+	PaymentProviders map[string]ports.PaymentProvider
+	// Audit records every correction. Optional; nil disables the trail.
+	Audit infraports.AuditService
+	Clock ports.Clock
+}
+
+// ReconcilePaymentsUseCase compares the workspace's revenues that have a
+// checkout session with their payment provider. Providers implementing
+// ports.PaymentTransactionSearcher are searched once for the whole window;
+// revenues not found there, and revenues of other providers, are looked up
+// one by one with GetPaymentStatus. Revenue payments are matched on their
+// reference number, so a correction never records a payment twice.
+type ReconcilePaymentsUseCase struct {
+	repositories ReconcilePaymentsRepositories
+	services     ReconcilePaymentsServices
+}
+
+// NewReconcilePaymentsUseCase wires the use case.
+func NewReconcilePaymentsUseCase(
+	repositories ReconcilePaymentsRepositories,
+	services ReconcilePaymentsServices,
+) *ReconcilePaymentsUseCase {
+	return &ReconcilePaymentsUseCase{
+		repositories: repositories,
+		services:     services,
+	}
+}
+
+// SetClock installs the clock used for "now". Safe to call with nil.
+func (uc *ReconcilePaymentsUseCase) SetClock(clock ports.Clock) {
+	if uc == nil {
+		return
+	}
+	uc.services.Clock = clock
+}
+
+// SetPaymentProviders installs the providers payments are checked with.
+// The container creates them after the use cases. Safe to call with nil.
+func (uc *ReconcilePaymentsUseCase) SetPaymentProviders(primary ports.PaymentProvider, byName map[string]ports.PaymentProvider) {
+	if uc == nil {
+		return
+	}
+	uc.services.Payment = primary
+	uc.services.PaymentProviders = byName
+}
+
+// SetAuditService installs the audit trail corrections are written to.
+func (uc *ReconcilePaymentsUseCase) SetAuditService(audit infraports.AuditService) {
+	if uc == nil {
+		return
+	}
+	uc.services.Audit = audit
+}
+
+// Execute reconciles the workspace. It needs revenue:read, or
+// revenue:update with AutoCorrect. A provider that cannot be asked about a
+// revenue is counted as Unavailable, not returned as an error.
+func (uc *ReconcilePaymentsUseCase) Execute(ctx context.Context, req *ReconcilePaymentsRequest) (*ReconcilePaymentsResult, error) {
+	if req == nil {
+		req = &ReconcilePaymentsRequest{}
+	}
+	action := entityid.ActionRead
+	if req.AutoCorrect {
+		action = entityid.ActionUpdate
+	}
+	if err := uc.services.ActionGatekeeper.Check(ctx, &actiongate.CheckActionRequest{
+		Entity: entityRevenue,
+		Action: action,
+	}); err != nil {
+		return nil, err
+	}
+
+	workspaceID := strings.TrimSpace(req.WorkspaceID)
+	if workspaceID == "" {
+		workspaceID = contextutil.ExtractWorkspaceIDFromContext(ctx)
+	}
+	if workspaceID == "" {
+		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(
+			ctx, uc.services.Translator,
+			"revenue.validation.workspace_required",
+			"Payment reconciliation needs a workspace [DEFAULT]",
+		))
+	}
+	ctx = contextutil.WithWorkspaceID(ctx, workspaceID)
+
+	now := ports.ClockNow(uc.services.Clock).UTC()
+	since := now.AddDate(0, 0, -DefaultReconcileDays)
+	if s := strings.TrimSpace(req.Since); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, fmt.Errorf("payment reconciliation: since must be YYYY-MM-DD: %w", err)
+		}
+		since = t
+	}
+	result := &ReconcilePaymentsResult{WorkspaceID: workspaceID, Since: since.Format("2006-01-02")}
+
+	revenues, err := uc.listCheckedOutRevenues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := uc.listPayments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	searched := map[string]*transactionIndex{}
+	for _, rev := range revenues {
+		provider := uc.providerFor(rev)
+		if provider == nil {
+			result.Unavailable++
+			continue
+		}
+		index, ok := searched[provider.Name()]
+		if !ok {
+			index = searchTransactions(ctx, provider, since, now)
+			searched[provider.Name()] = index
+		}
+		tx := index.find(rev)
+		if tx == nil {
+			tx = lookupTransaction(ctx, provider, rev)
+		}
+		if tx == nil {
+			result.Unavailable++
+			continue
+		}
+		result.Checked++
+
+		mismatch := compareWithProvider(rev, tx, provider.Name())
+		if mismatch == nil {
+			result.Matched++
+			continue
+		}
+		if req.AutoCorrect && mismatch.Kind == MismatchPaidNotRecorded {
+			if err := uc.correct(ctx, rev, tx, provider.Name(), payments[rev.GetId()], workspaceID); err != nil {
+				log.Printf("payment reconciliation: correct revenue %s: %v", rev.GetId(), err)
+			} else {
+				mismatch.Corrected = true
+				result.Corrected++
+			}
+		}
+		result.Mismatches = append(result.Mismatches, *mismatch)
+	}
+	return result, nil
+}
+
+// listCheckedOutRevenues returns the active revenues with a checkout
+// session that are not cancelled.
+func (uc *ReconcilePaymentsUseCase) listCheckedOutRevenues(ctx context.Context) ([]*revenuepb.Revenue, error) {
+	resp, err := uc.repositories.Revenue.ListRevenues(ctx, &revenuepb.ListRevenuesRequest{
+		Filters: activeFilter(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("payment reconciliation: list revenues: %w", err)
+	}
+	var out []*revenuepb.Revenue
+	for _, rev := range resp.GetData() {
+		if rev.GetCheckoutSessionId() == "" || rev.GetStatus() == revenueStatusCancelled {
+			continue
+		}
+		out = append(out, rev)
+	}
+	return out, nil
+}
+
+// listPayments returns the workspace's revenue payments by revenue ID.
+func (uc *ReconcilePaymentsUseCase) listPayments(ctx context.Context) (map[string][]*revenuepaymentpb.RevenuePayment, error) {
+	byRevenue := map[string][]*revenuepaymentpb.RevenuePayment{}
+	if uc.repositories.RevenuePayment == nil {
+		return byRevenue, nil
+	}
+	resp, err := uc.repositories.RevenuePayment.ListRevenuePayments(ctx, &revenuepaymentpb.ListRevenuePaymentsRequest{
+		Filters: activeFilter(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("payment reconciliation: list revenue payments: %w", err)
+	}
+	for _, p := range resp.GetData() {
+		byRevenue[p.GetRevenueId()] = append(byRevenue[p.GetRevenueId()], p)
+	}
+	return byRevenue, nil
+}
+
+func activeFilter() *commonpb.FilterRequest {
+	return &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{{
+		Field: "active",
+		FilterType: &commonpb.TypedFilter_BooleanFilter{
+			BooleanFilter: &commonpb.BooleanFilter{Value: true},
+		},
+	}}}
+}
+
+// providerFor returns the revenue's own provider when it names one,
+// otherwise the primary provider; nil when neither is enabled.
+func (uc *ReconcilePaymentsUseCase) providerFor(rev *revenuepb.Revenue) ports.PaymentProvider {
+	provider := uc.services.Payment
+	if name := rev.GetPaymentProvider(); name != "" {
+		provider = uc.services.PaymentProviders[name]
+	}
+	if provider == nil || !provider.IsEnabled() {
+		return nil
+	}
+	return provider
+}
+
+// transactionIndex holds a provider's searched transactions by the
+// references a revenue may carry.
+type transactionIndex struct {
+	byRef map[string][]*paymentpb.PaymentTransaction
+}
+
+func searchTransactions(ctx context.Context, provider ports.PaymentProvider, from, to time.Time) *transactionIndex {
+	index := &transactionIndex{byRef: map[string][]*paymentpb.PaymentTransaction{}}
+	searcher, ok := provider.(ports.PaymentTransactionSearcher)
+	if !ok {
+		return index
+	}
+	txs, err := searcher.SearchTransactions(ctx, from, to)
+	if err != nil {
+		log.Printf("payment reconciliation: search %s transactions: %v", provider.Name(), err)
+		return index
+	}
+	for _, tx := range txs {
+		for _, ref := range []string{tx.GetPaymentId(), tx.GetSessionId(), tx.GetProviderRef()} {
+			if ref != "" {
+				index.byRef[ref] = append(index.byRef[ref], tx)
+			}
+		}
+	}
+	return index
+}
+
+// find returns the searched transaction that decides the revenue's state:
+// a refund outranks a capture, which outranks anything still open.
+func (i *transactionIndex) find(rev *revenuepb.Revenue) *paymentpb.PaymentTransaction {
+	txs := i.byRef[rev.GetId()]
+	if len(txs) == 0 {
+		txs = i.byRef[rev.GetCheckoutSessionId()]
+	}
+	var best *paymentpb.PaymentTransaction
+	for _, tx := range txs {
+		if best == nil || statusRank(tx.GetStatus()) > statusRank(best.GetStatus()) {
+			best = tx
+		}
+	}
+	return best
+}
+
+func statusRank(status paymentpb.PaymentStatus) int {
+	switch status {
+	case paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED, paymentpb.PaymentStatus_PAYMENT_STATUS_PARTIAL_REFUND:
+		return 2
+	case paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS:
+		return 1
+	}
+	return 0
+}
+
+// lookupTransaction asks the provider about the revenue's checkout.
+func lookupTransaction(ctx context.Context, provider ports.PaymentProvider, rev *revenuepb.Revenue) *paymentpb.PaymentTransaction {
+	resp, err := provider.GetPaymentStatus(ctx, &paymentpb.GetPaymentStatusRequest{
+		Data: &paymentpb.PaymentStatusLookup{
+			ProviderId:  provider.Name(),
+			PaymentId:   rev.GetId(),
+			ProviderRef: rev.GetCheckoutSessionId(),
+		},
+	})
+	if err != nil || !resp.GetSuccess() || len(resp.GetData()) == 0 {
+		if err == nil {
+			err = errors.New("no status returned")
+			if desc := resp.GetError().GetDescription(); desc != "" {
+				err = errors.New(desc)
+			}
+		}
+		log.Printf("payment reconciliation: %s status of revenue %s: %v", provider.Name(), rev.GetId(), err)
+		return nil
+	}
+	data := resp.GetData()[0]
+	tx := data.GetTransaction()
+	if tx == nil {
+		tx = &paymentpb.PaymentTransaction{}
+	}
+	tx.Status = data.GetStatus()
+	return tx
+}
+
+// compareWithProvider returns the mismatch between a revenue and its
+// provider transaction, or nil when they agree.
+func compareWithProvider(rev *revenuepb.Revenue, tx *paymentpb.PaymentTransaction, provider string) *PaymentMismatch {
+	settled := false
+	switch rev.GetSettlementStatus() {
+	case settlementStatusFullySettled, "CASH_RECEIVED_WHT_PENDING":
+		settled = true
+	}
+	mismatch := &PaymentMismatch{
+		RevenueID:        rev.GetId(),
+		Provider:         provider,
+		ProviderRef:      transactionRef(tx, rev),
+		SettlementStatus: rev.GetSettlementStatus(),
+		ProviderStatus:   tx.GetStatus(),
+		Amount:           rev.GetTotalAmount(),
+		ProviderAmount:   tx.GetAmount(),
+	}
+	switch tx.GetStatus() {
+	case paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS:
+		if settled {
+			return nil
+		}
+		// Status lookups do not always carry the amount; zero is unknown.
+		if tx.GetAmount() != 0 && tx.GetAmount() != rev.GetTotalAmount() {
+			mismatch.Kind = MismatchAmount
+		} else {
+			mismatch.Kind = MismatchPaidNotRecorded
+		}
+		return mismatch
+	case paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED, paymentpb.PaymentStatus_PAYMENT_STATUS_PARTIAL_REFUND:
+		if !settled {
+			return nil
+		}
+		mismatch.Kind = MismatchReversed
+		return mismatch
+	}
+	return nil
+}
+
+// transactionRef is the reference a revenue payment records for tx.
+func transactionRef(tx *paymentpb.PaymentTransaction, rev *revenuepb.Revenue) string {
+	for _, ref := range []string{tx.GetProviderPaymentRef(), tx.GetProviderRef()} {
+		if ref != "" {
+			return ref
+		}
+	}
+	return rev.GetCheckoutSessionId()
+}
+
+// correct settles a revenue the provider reports paid: it records the
+// payment unless a revenue payment already carries its reference, marks the
+// revenue fully settled (an overdue revenue goes back to complete) and
+// writes the change to the audit trail.
+func (uc *ReconcilePaymentsUseCase) correct(ctx context.Context, rev *revenuepb.Revenue, tx *paymentpb.PaymentTransaction, provider string, payments []*revenuepaymentpb.RevenuePayment, workspaceID string) error {
+	ref := transactionRef(tx, rev)
+	newData := map[string]any{"settlement_status": settlementStatusFullySettled}
+
+	recorded := false
+	for _, p := range payments {
+		if p.GetReferenceNumber() == ref {
+			recorded = true
+			break
+		}
+	}
+	if !recorded && uc.repositories.RevenuePayment != nil {
+		payment, err := uc.recordPayment(ctx, rev, tx, provider, ref)
+		if err != nil {
+			return err
+		}
+		newData["revenue_payment_id"] = payment.GetId()
+	}
+
+	oldData := map[string]any{"settlement_status": rev.GetSettlementStatus(), "status": rev.GetStatus()}
+	newData["status"] = rev.GetStatus()
+	settled := settlementStatusFullySettled
+	rev.SettlementStatus = &settled
+	if rev.GetStatus() == revenueStatusOverdue {
+		rev.Status = revenueStatusComplete
+		newData["status"] = revenueStatusComplete
+	}
+	if _, err := uc.repositories.Revenue.UpdateRevenue(ctx, &revenuepb.UpdateRevenueRequest{Data: rev}); err != nil {
+		return err
+	}
+
+	if err := infraports.DiffAndLog(ctx, uc.services.Audit, infraports.DiffAndLogRequest{
+		WorkspaceID:    workspaceID,
+		EntityType:     entityRevenue,
+		EntityID:       rev.GetId(),
+		Domain:         "centymo",
+		Action:         2, // UPDATE
+		PermissionCode: "revenue:update",
+		UseCase:        "ReconcilePayments",
+		Reason:         fmt.Sprintf("%s reports payment %s captured", provider, ref),
+		MethodName:     "ReconcilePayments",
+		OldData:        oldData,
+		NewData:        newData,
+	}); err != nil {
+		log.Printf("payment reconciliation: audit revenue %s: %v", rev.GetId(), err)
+	}
+	return nil
+}
+
+func (uc *ReconcilePaymentsUseCase) recordPayment(ctx context.Context, rev *revenuepb.Revenue, tx *paymentpb.PaymentTransaction, provider, ref string) (*revenuepaymentpb.RevenuePayment, error) {
+	now := ports.ClockNow(uc.services.Clock)
+	amount, currency := tx.GetAmount(), tx.GetCurrency()
+	if amount == 0 {
+		amount = rev.GetTotalAmount()
+	}
+	if currency == "" {
+		currency = rev.GetCurrency()
+	}
+	paidOn := now.Format("2006-01-02")
+	if at := tx.GetProcessedAt(); at != nil {
+		paidOn = at.AsTime().Format("2006-01-02")
+	}
+	status, collectionType := "completed", "sale"
+	notes := "Recorded by payment reconciliation"
+	payment := &revenuepaymentpb.RevenuePayment{
+		RevenueId:          rev.GetId(),
+		Amount:             amount,
+		Currency:           currency,
+		ReferenceNumber:    &ref,
+		CollectionType:     &collectionType,
+		Status:             &status,
+		Active:             true,
+		PaymentMethod:      &provider,
+		Notes:              &notes,
+		PaymentDate:        &paidOn,
+		DateCreated:        &[]int64{now.UnixMilli()}[0],
+		DateCreatedString:  &[]string{now.Format(time.RFC3339)}[0],
+		DateModified:       &[]int64{now.UnixMilli()}[0],
+		DateModifiedString: &[]string{now.Format(time.RFC3339)}[0],
+	}
+	if uc.services.IDGenerator != nil {
+		payment.Id = uc.services.IDGenerator.GenerateID()
+	}
+	resp, err := uc.repositories.RevenuePayment.CreateRevenuePayment(ctx, &revenuepaymentpb.CreateRevenuePaymentRequest{Data: payment})
+	if err != nil {
+		return nil, fmt.Errorf("record payment: %w", err)
+	}
+	if len(resp.GetData()) > 0 {
+		return resp.GetData()[0], nil
+	}
+	return payment, nil
+}
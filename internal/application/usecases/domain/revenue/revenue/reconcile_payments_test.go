@@ -0,0 +1,161 @@
+package revenue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+
+	revenuepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue"
+	revenuepaymentpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue_payment"
+	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
+)
+
+// openAuthorizer short-circuits the action gate (IsEnabled=false).
+type openAuthorizer struct{}
+
+func (openAuthorizer) HasPermission(context.Context, string, string) (bool, error) { return true, nil }
+func (openAuthorizer) IsEnabled() bool                                             { return false }
+
+// reconcilePaymentRepo keeps revenue payments in memory.
+type reconcilePaymentRepo struct {
+	revenuepaymentpb.RevenuePaymentDomainServiceServer
+	payments []*revenuepaymentpb.RevenuePayment
+}
+
+func (r *reconcilePaymentRepo) ListRevenuePayments(context.Context, *revenuepaymentpb.ListRevenuePaymentsRequest) (*revenuepaymentpb.ListRevenuePaymentsResponse, error) {
+	return &revenuepaymentpb.ListRevenuePaymentsResponse{Success: true, Data: r.payments}, nil
+}
+
+func (r *reconcilePaymentRepo) CreateRevenuePayment(_ context.Context, req *revenuepaymentpb.CreateRevenuePaymentRequest) (*revenuepaymentpb.CreateRevenuePaymentResponse, error) {
+	r.payments = append(r.payments, req.GetData())
+	return &revenuepaymentpb.CreateRevenuePaymentResponse{Success: true, Data: []*revenuepaymentpb.RevenuePayment{req.GetData()}}, nil
+}
+
+// searchingProvider answers transaction searches from searched and status
+// lookups from statuses, keyed by checkout session.
+type searchingProvider struct {
+	ports.PaymentProvider
+	searched []*paymentpb.PaymentTransaction
+	statuses map[string]paymentpb.PaymentStatus
+	searches int
+}
+
+func (p *searchingProvider) Name() string    { return "paypal" }
+func (p *searchingProvider) IsEnabled() bool { return true }
+
+func (p *searchingProvider) SearchTransactions(context.Context, time.Time, time.Time) ([]*paymentpb.PaymentTransaction, error) {
+	p.searches++
+	return p.searched, nil
+}
+
+func (p *searchingProvider) GetPaymentStatus(_ context.Context, req *paymentpb.GetPaymentStatusRequest) (*paymentpb.GetPaymentStatusResponse, error) {
+	status, ok := p.statuses[req.GetData().GetProviderRef()]
+	if !ok {
+		return &paymentpb.GetPaymentStatusResponse{}, nil
+	}
+	return &paymentpb.GetPaymentStatusResponse{Success: true, Data: []*paymentpb.PaymentStatusData{{Status: status}}}, nil
+}
+
+func TestReconcilePayments(t *testing.T) {
+	settled := "FULLY_SETTLED"
+	repo := newBillingRevenueRepo(
+		&revenuepb.Revenue{Id: "rev-paid", TotalAmount: 150000, Currency: "PHP", Status: "overdue", CheckoutSessionId: strPtr("ORDER-1")},
+		&revenuepb.Revenue{Id: "rev-short", TotalAmount: 90000, Currency: "PHP", Status: "complete", CheckoutSessionId: strPtr("ORDER-2")},
+		&revenuepb.Revenue{Id: "rev-refunded", TotalAmount: 50000, Status: "complete", SettlementStatus: &settled, CheckoutSessionId: strPtr("ORDER-3")},
+		&revenuepb.Revenue{Id: "rev-open", TotalAmount: 70000, Status: "complete", CheckoutSessionId: strPtr("ORDER-4")},
+		&revenuepb.Revenue{Id: "rev-known", TotalAmount: 30000, Status: "complete", CheckoutSessionId: strPtr("ORDER-5")},
+		&revenuepb.Revenue{Id: "rev-lost", TotalAmount: 10000, Status: "complete", CheckoutSessionId: strPtr("ORDER-6")},
+		&revenuepb.Revenue{Id: "rev-cancelled", TotalAmount: 10000, Status: "cancelled", CheckoutSessionId: strPtr("ORDER-7")},
+		&revenuepb.Revenue{Id: "rev-no-checkout", TotalAmount: 10000, Status: "complete"},
+	)
+	payments := &reconcilePaymentRepo{payments: []*revenuepaymentpb.RevenuePayment{
+		{Id: "pay-known", RevenueId: "rev-known", ReferenceNumber: strPtr("CAP-5")},
+	}}
+	paypal := &searchingProvider{
+		searched: []*paymentpb.PaymentTransaction{
+			{PaymentId: "rev-paid", ProviderPaymentRef: "CAP-1", Amount: 150000, Currency: "PHP", Status: paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS},
+			{PaymentId: "rev-short", ProviderPaymentRef: "CAP-2", Amount: 80000, Status: paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS},
+			{PaymentId: "rev-refunded", ProviderPaymentRef: "CAP-3", Amount: 50000, Status: paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS},
+			{PaymentId: "rev-refunded", ProviderPaymentRef: "REF-3", Amount: 50000, Status: paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED},
+			{PaymentId: "rev-known", ProviderPaymentRef: "CAP-5", Amount: 30000, Status: paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS},
+		},
+		// rev-open is not in the search yet; its order is still open.
+		statuses: map[string]paymentpb.PaymentStatus{"ORDER-4": paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING},
+	}
+	uc := NewReconcilePaymentsUseCase(
+		ReconcilePaymentsRepositories{Revenue: repo, RevenuePayment: payments},
+		ReconcilePaymentsServices{ActionGatekeeper: actiongate.NewActionGatekeeper(openAuthorizer{}, nil)},
+	)
+	uc.SetPaymentProviders(paypal, map[string]ports.PaymentProvider{"paypal": paypal})
+
+	ctx := contextutil.WithUserID(context.Background(), "u-1")
+	result, err := uc.Execute(ctx, &ReconcilePaymentsRequest{WorkspaceID: "ws-1", Since: "2026-03-01", AutoCorrect: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if paypal.searches != 1 {
+		t.Errorf("searches = %d, want one per provider", paypal.searches)
+	}
+	if result.Checked != 5 || result.Matched != 1 || result.Unavailable != 1 || result.Corrected != 2 {
+		t.Errorf("checked %d, matched %d, unavailable %d, corrected %d", result.Checked, result.Matched, result.Unavailable, result.Corrected)
+	}
+	kinds := map[string]PaymentMismatch{}
+	for _, m := range result.Mismatches {
+		kinds[m.RevenueID] = m
+	}
+	if m := kinds["rev-paid"]; m.Kind != MismatchPaidNotRecorded || !m.Corrected || m.ProviderRef != "CAP-1" {
+		t.Errorf("rev-paid = %+v", m)
+	}
+	if m := kinds["rev-short"]; m.Kind != MismatchAmount || m.Corrected || m.ProviderAmount != 80000 {
+		t.Errorf("rev-short = %+v", m)
+	}
+	if m := kinds["rev-refunded"]; m.Kind != MismatchReversed || m.Corrected {
+		t.Errorf("rev-refunded = %+v", m)
+	}
+	if m := kinds["rev-known"]; m.Kind != MismatchPaidNotRecorded || !m.Corrected {
+		t.Errorf("rev-known = %+v", m)
+	}
+
+	paid := repo.revenues["rev-paid"]
+	if paid.GetSettlementStatus() != "FULLY_SETTLED" || paid.GetStatus() != "complete" {
+		t.Errorf("rev-paid = settlement %q, status %q", paid.GetSettlementStatus(), paid.GetStatus())
+	}
+	if repo.revenues["rev-short"].GetSettlementStatus() != "" {
+		t.Errorf("amount mismatch was settled")
+	}
+	// Only rev-paid gains a payment; rev-known already had CAP-5 recorded.
+	if len(payments.payments) != 2 {
+		t.Fatalf("payments = %d", len(payments.payments))
+	}
+	if p := payments.payments[1]; p.GetRevenueId() != "rev-paid" || p.GetReferenceNumber() != "CAP-1" || p.GetAmount() != 150000 || p.GetCurrency() != "PHP" {
+		t.Errorf("recorded payment = %+v", p)
+	}
+}
+
+func TestReconcilePayments_ReportOnly(t *testing.T) {
+	repo := newBillingRevenueRepo(
+		&revenuepb.Revenue{Id: "rev-paid", TotalAmount: 150000, Status: "complete", CheckoutSessionId: strPtr("ORDER-1")},
+	)
+	paypal := &searchingProvider{statuses: map[string]paymentpb.PaymentStatus{"ORDER-1": paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS}}
+	uc := NewReconcilePaymentsUseCase(
+		ReconcilePaymentsRepositories{Revenue: repo, RevenuePayment: &reconcilePaymentRepo{}},
+		ReconcilePaymentsServices{ActionGatekeeper: actiongate.NewActionGatekeeper(openAuthorizer{}, nil)},
+	)
+	uc.SetPaymentProviders(paypal, nil)
+
+	result, err := uc.Execute(context.Background(), &ReconcilePaymentsRequest{WorkspaceID: "ws-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Mismatches) != 1 || result.Mismatches[0].Kind != MismatchPaidNotRecorded || result.Corrected != 0 {
+		t.Errorf("result = %+v", result)
+	}
+	if len(repo.updated) != 0 {
+		t.Errorf("report-only run updated %v", repo.updated)
+	}
+}
@@ -0,0 +1,397 @@
+package revenue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+
+	paymenttermpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/payment_term"
+	workspacepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/workspace"
+	revenuepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue"
+	revenuerunpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue_run"
+	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
+)
+
+// Revenue statuses set or honored by recurring billing. A revenue past its
+// due date in any other status becomes overdue.
+const (
+	revenueStatusOverdue   = "overdue"
+	revenueStatusComplete  = "complete"
+	revenueStatusCancelled = "cancelled"
+)
+
+// RecurringBillingRequest scopes one billing pass to a workspace.
+type RecurringBillingRequest struct {
+	WorkspaceID string
+	AsOfDate    string // YYYY-MM-DD; defaults to today in the workspace's timezone
+	// DefaultNetDays dates a revenue without a payment term this many days
+	// after its revenue date. Zero leaves such revenues without a due date,
+	// so they never become overdue.
+	DefaultNetDays int
+	// SkipOverdue leaves the overdue sweep out of the pass.
+	SkipOverdue bool
+	// Checkout redirect URLs passed to the payment provider.
+	SuccessURL string
+	FailureURL string
+	CancelURL  string
+}
+
+// RecurringBillingResult reports what one billing pass did.
+type RecurringBillingResult struct {
+	WorkspaceID string
+	AsOfDate    string
+	RunID       string   // the revenue run; empty when nothing was due
+	RevenueIDs  []string // revenues generated by this pass
+	Skipped     int
+	Errored     int
+	// Checkouts counts the checkout sessions opened for generated revenues,
+	// CheckoutsFailed those the payment provider refused.
+	Checkouts       int
+	CheckoutsFailed int
+	MarkedOverdue   []string
+}
+
+// RunRecurringBillingRepositories groups all repository dependencies.
+type RunRecurringBillingRepositories struct {
+	Revenue     revenuepb.RevenueDomainServiceServer
+	PaymentTerm paymenttermpb.PaymentTermDomainServiceServer
+	// Workspace resolves the workspace timezone for the default as-of date.
+	// Optional; falls back to UTC.
+	Workspace workspacepb.WorkspaceDomainServiceServer
+}
+
+// RunRecurringBillingServices groups all business service dependencies.
+type RunRecurringBillingServices struct {
+	Translator       ports.Translator
+	ActionGatekeeper *actiongate.ActionGatekeeper
+	// Payment opens checkout sessions for generated revenues. Optional;
+	// when nil or disabled, revenues are generated without one.
+	Payment ports.PaymentProvider
+	// PaymentProviders resolves a revenue's own payment_provider. Optional.
+	PaymentProviders map[string]ports.PaymentProvider
+	Clock            ports.Clock
+}
+
+// RunRecurringBillingUseCase bills a workspace's subscriptions on their
+// billing cadence. One pass:
+//
+//  1. lists the due billing periods (ListRevenueRunCandidates) — the price
+//     plan's billing cycle decides the cadence, monthly, weekly or custom;
+//  2. generates a revenue for every eligible period as one revenue run
+//     (GenerateRevenueRun), which is idempotent per period;
+//  3. dates each generated revenue from its payment term and opens a
+//     checkout session for it with the payment provider;
+//  4. marks revenues past their due date and not yet settled as overdue.
+//
+// Passes are safe to repeat: a period already billed is not a candidate
+// again, and a revenue already overdue or settled is left alone.
+type RunRecurringBillingUseCase struct {
+	repositories   RunRecurringBillingRepositories
+	services       RunRecurringBillingServices
+	listCandidates *ListRevenueRunCandidatesUseCase
+	generateRun    *GenerateRevenueRunUseCase
+}
+
+// NewRunRecurringBillingUseCase wires the use case.
+func NewRunRecurringBillingUseCase(
+	repositories RunRecurringBillingRepositories,
+	services RunRecurringBillingServices,
+	listCandidates *ListRevenueRunCandidatesUseCase,
+	generateRun *GenerateRevenueRunUseCase,
+) *RunRecurringBillingUseCase {
+	return &RunRecurringBillingUseCase{
+		repositories:   repositories,
+		services:       services,
+		listCandidates: listCandidates,
+		generateRun:    generateRun,
+	}
+}
+
+// SetClock installs the clock used for "today". Safe to call with nil —
+// falls back to wall-clock time.
+func (uc *RunRecurringBillingUseCase) SetClock(clock ports.Clock) {
+	if uc == nil {
+		return
+	}
+	uc.services.Clock = clock
+}
+
+// SetPaymentProviders installs the payment providers checkout sessions are
+// opened with. The container creates them after the use cases. Safe to call
+// with nil.
+func (uc *RunRecurringBillingUseCase) SetPaymentProviders(primary ports.PaymentProvider, byName map[string]ports.PaymentProvider) {
+	if uc == nil {
+		return
+	}
+	uc.services.Payment = primary
+	uc.services.PaymentProviders = byName
+}
+
+// Execute runs one billing pass. Per-revenue failures (a refused checkout,
+// a failed overdue update) are counted and logged, not returned; an error
+// means the pass could not run.
+func (uc *RunRecurringBillingUseCase) Execute(ctx context.Context, req *RecurringBillingRequest) (*RecurringBillingResult, error) {
+	if err := uc.services.ActionGatekeeper.Check(ctx, &actiongate.CheckActionRequest{
+		Entity: entityRevenue,
+		Action: entityid.ActionUpdate,
+	}); err != nil {
+		return nil, err
+	}
+	if req == nil {
+		req = &RecurringBillingRequest{}
+	}
+
+	workspaceID := strings.TrimSpace(req.WorkspaceID)
+	if workspaceID == "" {
+		workspaceID = contextutil.ExtractWorkspaceIDFromContext(ctx)
+	}
+	if workspaceID == "" {
+		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(
+			ctx, uc.services.Translator,
+			"revenue.validation.workspace_required",
+			"Recurring billing needs a workspace [DEFAULT]",
+		))
+	}
+	// Repositories scope reads and writes by the context's workspace.
+	ctx = contextutil.WithWorkspaceID(ctx, workspaceID)
+
+	asOfDate := strings.TrimSpace(req.AsOfDate)
+	if asOfDate == "" {
+		loc := resolveWorkspaceLocation(ctx, uc.repositories.Workspace, workspaceID)
+		asOfDate = ports.ClockNow(uc.services.Clock).In(loc).Format("2006-01-02")
+	}
+	result := &RecurringBillingResult{WorkspaceID: workspaceID, AsOfDate: asOfDate}
+
+	if err := uc.generate(ctx, req, result); err != nil {
+		return nil, err
+	}
+	if !req.SkipOverdue {
+		if err := uc.markOverdue(ctx, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// generate bills the due periods and prepares their revenues for payment.
+func (uc *RunRecurringBillingUseCase) generate(ctx context.Context, req *RecurringBillingRequest, result *RecurringBillingResult) error {
+	if uc.listCandidates == nil || uc.generateRun == nil {
+		return errors.New("recurring billing: revenue run use cases are not wired")
+	}
+	scope := &revenuerunpb.RevenueRunScope{WorkspaceId: &result.WorkspaceID, AsOfDate: &result.AsOfDate}
+	candidates, err := uc.listCandidates.Execute(ctx, &revenuerunpb.ListRevenueRunCandidatesRequest{Scope: scope})
+	if err != nil {
+		return fmt.Errorf("recurring billing: list candidates: %w", err)
+	}
+
+	var selections []*revenuerunpb.SelectedRevenueRunCandidate
+	for _, c := range candidates.GetData() {
+		if !c.GetEligible() || c.GetSourceKind() == revenuerunpb.RevenueRunSourceKind_REVENUE_RUN_SOURCE_KIND_ADVANCE_COLLECTION {
+			continue
+		}
+		selections = append(selections, &revenuerunpb.SelectedRevenueRunCandidate{
+			SubscriptionId: c.GetSubscriptionId(),
+			PeriodStart:    c.GetPeriodStart(),
+			PeriodEnd:      c.GetPeriodEnd(),
+			PeriodMarker:   c.GetPeriodMarker(),
+			SourceKind:     revenuerunpb.RevenueRunSourceKind_REVENUE_RUN_SOURCE_KIND_SUBSCRIPTION_CYCLE,
+		})
+	}
+	if len(selections) == 0 {
+		return nil
+	}
+
+	run, err := uc.generateRun.Execute(ctx, &revenuerunpb.GenerateRevenueRunRequest{
+		Scope:      scope,
+		Selections: &revenuerunpb.RevenueRunSelections{ExplicitList: selections},
+	})
+	if err != nil {
+		return fmt.Errorf("recurring billing: generate run: %w", err)
+	}
+	result.RunID = run.GetRun().GetId()
+
+	for _, attempt := range run.GetAttempts() {
+		switch attempt.GetOutcome() {
+		case revenuerunpb.RevenueRunAttemptOutcome_REVENUE_RUN_ATTEMPT_OUTCOME_CREATED:
+			if id := attempt.GetRevenueId(); id != "" {
+				result.RevenueIDs = append(result.RevenueIDs, id)
+				uc.prepareForPayment(ctx, req, id, result)
+			}
+		case revenuerunpb.RevenueRunAttemptOutcome_REVENUE_RUN_ATTEMPT_OUTCOME_SKIPPED:
+			result.Skipped++
+		default:
+			result.Errored++
+		}
+	}
+	return nil
+}
+
+// prepareForPayment sets the due date of a generated revenue and opens its
+// checkout session.
+func (uc *RunRecurringBillingUseCase) prepareForPayment(ctx context.Context, req *RecurringBillingRequest, revenueID string, result *RecurringBillingResult) {
+	rev := uc.readRevenue(ctx, revenueID)
+	if rev == nil {
+		return
+	}
+	changed := false
+	if rev.GetDueDate() == "" {
+		if due := uc.dueDate(ctx, rev, req.DefaultNetDays); due != "" {
+			rev.DueDate = &due
+			changed = true
+		}
+	}
+
+	provider := uc.paymentProviderFor(rev)
+	if provider != nil && rev.GetCheckoutSessionId() == "" && rev.GetTotalAmount() > 0 {
+		session, err := uc.openCheckout(ctx, provider, rev, result.WorkspaceID, req)
+		if err != nil {
+			result.CheckoutsFailed++
+			log.Printf("recurring billing: checkout for revenue %s: %v", revenueID, err)
+		} else {
+			// The provider's own session reference is what its status
+			// lookup takes, so reconciliation can find the payment.
+			sessionID, providerName := session.GetProviderSessionId(), provider.Name()
+			if sessionID == "" {
+				sessionID = session.GetId()
+			}
+			rev.CheckoutSessionId = &sessionID
+			rev.PaymentProvider = &providerName
+			result.Checkouts++
+			changed = true
+		}
+	}
+
+	if changed {
+		if _, err := uc.repositories.Revenue.UpdateRevenue(ctx, &revenuepb.UpdateRevenueRequest{Data: rev}); err != nil {
+			log.Printf("recurring billing: update revenue %s: %v", revenueID, err)
+		}
+	}
+}
+
+func (uc *RunRecurringBillingUseCase) openCheckout(ctx context.Context, provider ports.PaymentProvider, rev *revenuepb.Revenue, workspaceID string, req *RecurringBillingRequest) (*paymentpb.CheckoutSession, error) {
+	orderRef := rev.GetReferenceNumber()
+	if orderRef == "" {
+		orderRef = rev.GetId()
+	}
+	resp, err := provider.CreateCheckoutSession(ctx, &paymentpb.CreateCheckoutSessionRequest{
+		Data: &paymentpb.CheckoutSessionData{
+			ProviderId:     provider.Name(),
+			Amount:         rev.GetTotalAmount(),
+			Currency:       rev.GetCurrency(),
+			Description:    rev.GetName(),
+			PaymentId:      rev.GetId(),
+			SubscriptionId: rev.GetSubscriptionId(),
+			ClientId:       rev.GetClientId(),
+			OrderRef:       orderRef,
+			SuccessUrl:     req.SuccessURL,
+			FailureUrl:     req.FailureURL,
+			CancelUrl:      req.CancelURL,
+			// The payment webhook restores the workspace from the metadata.
+			Metadata: map[string]string{
+				"workspace_id": workspaceID,
+				"revenue_id":   rev.GetId(),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.GetSuccess() || len(resp.GetData()) == 0 {
+		return nil, fmt.Errorf("provider %s refused the checkout: %s", provider.Name(), resp.GetError().GetMessage())
+	}
+	return resp.GetData()[0], nil
+}
+
+// paymentProviderFor returns the revenue's own provider when it names one,
+// otherwise the primary provider; nil when neither is enabled.
+func (uc *RunRecurringBillingUseCase) paymentProviderFor(rev *revenuepb.Revenue) ports.PaymentProvider {
+	provider := uc.services.Payment
+	if name := rev.GetPaymentProvider(); name != "" {
+		if p := uc.services.PaymentProviders[name]; p != nil {
+			provider = p
+		}
+	}
+	if provider == nil || !provider.IsEnabled() {
+		return nil
+	}
+	return provider
+}
+
+// dueDate dates a revenue from its payment term, else defaultNetDays after
+// its revenue date.
+func (uc *RunRecurringBillingUseCase) dueDate(ctx context.Context, rev *revenuepb.Revenue, defaultNetDays int) string {
+	if id := rev.GetPaymentTermId(); id != "" && uc.repositories.PaymentTerm != nil {
+		resp, err := uc.repositories.PaymentTerm.ReadPaymentTerm(ctx, &paymenttermpb.ReadPaymentTermRequest{
+			Data: &paymenttermpb.PaymentTerm{Id: id},
+		})
+		if err == nil && len(resp.GetData()) > 0 {
+			if due := dueDateFromPaymentTerm(resp.GetData()[0], rev.GetRevenueDate()); due != "" {
+				return due
+			}
+		}
+	}
+	if defaultNetDays <= 0 {
+		return ""
+	}
+	return dueDateFromPaymentTerm(&paymenttermpb.PaymentTerm{Type: "net", NetDays: int32(defaultNetDays)}, rev.GetRevenueDate())
+}
+
+// markOverdue marks the workspace's revenues due before the as-of date as
+// overdue, unless they are settled, complete or cancelled, and announces
+// each as invoice.overdue.
+func (uc *RunRecurringBillingUseCase) markOverdue(ctx context.Context, result *RecurringBillingResult) error {
+	resp, err := uc.repositories.Revenue.ListRevenues(ctx, &revenuepb.ListRevenuesRequest{
+		Filters: activeFilter(),
+	})
+	if err != nil {
+		return fmt.Errorf("recurring billing: list revenues: %w", err)
+	}
+	for _, rev := range resp.GetData() {
+		if !isOverdue(rev, result.AsOfDate) {
+			continue
+		}
+		rev.Status = revenueStatusOverdue
+		if _, err := uc.repositories.Revenue.UpdateRevenue(ctx, &revenuepb.UpdateRevenueRequest{Data: rev}); err != nil {
+			log.Printf("recurring billing: mark revenue %s overdue: %v", rev.GetId(), err)
+			continue
+		}
+		result.MarkedOverdue = append(result.MarkedOverdue, rev.GetId())
+		domainevent.Emit(ctx, domainevent.InvoiceOverdue, rev)
+	}
+	return nil
+}
+
+// isOverdue reports whether rev, unpaid, was due before asOfDate. Dates are
+// YYYY-MM-DD, so they compare as strings.
+func isOverdue(rev *revenuepb.Revenue, asOfDate string) bool {
+	due := rev.GetDueDate()
+	if due == "" || due >= asOfDate {
+		return false
+	}
+	switch rev.GetStatus() {
+	case revenueStatusOverdue, revenueStatusComplete, revenueStatusCancelled:
+		return false
+	}
+	switch rev.GetSettlementStatus() {
+	case settlementStatusFullySettled, "CASH_RECEIVED_WHT_PENDING":
+		return false
+	}
+	return true
+}
+
+func (uc *RunRecurringBillingUseCase) readRevenue(ctx context.Context, id string) *revenuepb.Revenue {
+	resp, err := uc.repositories.Revenue.ReadRevenue(ctx, &revenuepb.ReadRevenueRequest{Data: &revenuepb.Revenue{Id: id}})
+	if err != nil || len(resp.GetData()) == 0 {
+		log.Printf("recurring billing: read revenue %s: %v", id, err)
+		return nil
+	}
+	return resp.GetData()[0]
+}
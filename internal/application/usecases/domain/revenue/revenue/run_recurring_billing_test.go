@@ -0,0 +1,174 @@
+package revenue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+
+	paymenttermpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/payment_term"
+	revenuepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue"
+	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
+)
+
+// billingRevenueRepo keeps revenues in memory and records updates.
+type billingRevenueRepo struct {
+	revenuepb.RevenueDomainServiceServer
+	revenues map[string]*revenuepb.Revenue
+	order    []string
+	updated  []string
+}
+
+func newBillingRevenueRepo(revenues ...*revenuepb.Revenue) *billingRevenueRepo {
+	r := &billingRevenueRepo{revenues: map[string]*revenuepb.Revenue{}}
+	for _, rev := range revenues {
+		r.revenues[rev.Id] = rev
+		r.order = append(r.order, rev.Id)
+	}
+	return r
+}
+
+func (r *billingRevenueRepo) ReadRevenue(_ context.Context, req *revenuepb.ReadRevenueRequest) (*revenuepb.ReadRevenueResponse, error) {
+	rev, ok := r.revenues[req.GetData().GetId()]
+	if !ok {
+		return &revenuepb.ReadRevenueResponse{}, nil
+	}
+	return &revenuepb.ReadRevenueResponse{Success: true, Data: []*revenuepb.Revenue{rev}}, nil
+}
+
+func (r *billingRevenueRepo) ListRevenues(context.Context, *revenuepb.ListRevenuesRequest) (*revenuepb.ListRevenuesResponse, error) {
+	resp := &revenuepb.ListRevenuesResponse{Success: true}
+	for _, id := range r.order {
+		resp.Data = append(resp.Data, r.revenues[id])
+	}
+	return resp, nil
+}
+
+func (r *billingRevenueRepo) UpdateRevenue(_ context.Context, req *revenuepb.UpdateRevenueRequest) (*revenuepb.UpdateRevenueResponse, error) {
+	r.revenues[req.GetData().GetId()] = req.GetData()
+	r.updated = append(r.updated, req.GetData().GetId())
+	return &revenuepb.UpdateRevenueResponse{Success: true}, nil
+}
+
+type billingPaymentTerms struct {
+	paymenttermpb.PaymentTermDomainServiceServer
+}
+
+func (billingPaymentTerms) ReadPaymentTerm(_ context.Context, req *paymenttermpb.ReadPaymentTermRequest) (*paymenttermpb.ReadPaymentTermResponse, error) {
+	if req.GetData().GetId() != "pt-net15" {
+		return &paymenttermpb.ReadPaymentTermResponse{}, nil
+	}
+	return &paymenttermpb.ReadPaymentTermResponse{Success: true, Data: []*paymenttermpb.PaymentTerm{
+		{Id: "pt-net15", Type: "net", NetDays: 15},
+	}}, nil
+}
+
+// checkoutProvider opens sessions, or refuses them with err.
+type checkoutProvider struct {
+	ports.PaymentProvider
+	name     string
+	err      error
+	requests []*paymentpb.CheckoutSessionData
+}
+
+func (p *checkoutProvider) Name() string    { return p.name }
+func (p *checkoutProvider) IsEnabled() bool { return true }
+func (p *checkoutProvider) CreateCheckoutSession(_ context.Context, req *paymentpb.CreateCheckoutSessionRequest) (*paymentpb.CreateCheckoutSessionResponse, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.requests = append(p.requests, req.GetData())
+	return &paymentpb.CreateCheckoutSessionResponse{Success: true, Data: []*paymentpb.CheckoutSession{
+		{Id: "cs-" + req.GetData().GetPaymentId(), CheckoutUrl: "https://pay.example/" + req.GetData().GetPaymentId()},
+	}}, nil
+}
+
+func TestRunRecurringBilling_PrepareForPayment(t *testing.T) {
+	repo := newBillingRevenueRepo(
+		&revenuepb.Revenue{Id: "rev-1", Name: "March", TotalAmount: 150000, Currency: "PHP", ClientId: "client-1",
+			RevenueDate: strPtr("2026-03-01"), PaymentTermId: strPtr("pt-net15"), SubscriptionId: strPtr("sub-1"), Status: "draft"},
+		&revenuepb.Revenue{Id: "rev-2", TotalAmount: 90000, Currency: "PHP", RevenueDate: strPtr("2026-03-01"),
+			PaymentProvider: strPtr("paypal"), Status: "draft"},
+		&revenuepb.Revenue{Id: "rev-free", TotalAmount: 0, RevenueDate: strPtr("2026-03-01"), Status: "draft"},
+	)
+	maya := &checkoutProvider{name: "maya"}
+	paypal := &checkoutProvider{name: "paypal", err: errors.New("gateway down")}
+	uc := NewRunRecurringBillingUseCase(
+		RunRecurringBillingRepositories{Revenue: repo, PaymentTerm: billingPaymentTerms{}},
+		RunRecurringBillingServices{},
+		nil, nil,
+	)
+	uc.SetPaymentProviders(maya, map[string]ports.PaymentProvider{"maya": maya, "paypal": paypal})
+
+	ctx := contextutil.WithWorkspaceID(context.Background(), "ws-1")
+	req := &RecurringBillingRequest{DefaultNetDays: 30, SuccessURL: "https://app.example/paid"}
+	result := &RecurringBillingResult{WorkspaceID: "ws-1"}
+	for _, id := range []string{"rev-1", "rev-2", "rev-free"} {
+		uc.prepareForPayment(ctx, req, id, result)
+	}
+
+	if result.Checkouts != 1 || result.CheckoutsFailed != 1 {
+		t.Errorf("checkouts = %d, failed = %d", result.Checkouts, result.CheckoutsFailed)
+	}
+	rev1 := repo.revenues["rev-1"]
+	if rev1.GetDueDate() != "2026-03-16" || rev1.GetCheckoutSessionId() != "cs-rev-1" || rev1.GetPaymentProvider() != "maya" {
+		t.Errorf("rev-1 = due %q, session %q, provider %q", rev1.GetDueDate(), rev1.GetCheckoutSessionId(), rev1.GetPaymentProvider())
+	}
+	if len(maya.requests) != 1 {
+		t.Fatalf("maya requests = %d", len(maya.requests))
+	}
+	if got := maya.requests[0]; got.GetAmount() != 150000 || got.GetMetadata()["workspace_id"] != "ws-1" || got.GetSuccessUrl() != "https://app.example/paid" {
+		t.Errorf("checkout request = %+v", got)
+	}
+	// The refused checkout still dates the revenue, from the default term.
+	if rev2 := repo.revenues["rev-2"]; rev2.GetDueDate() != "2026-03-31" || rev2.GetCheckoutSessionId() != "" {
+		t.Errorf("rev-2 = due %q, session %q", rev2.GetDueDate(), rev2.GetCheckoutSessionId())
+	}
+	if free := repo.revenues["rev-free"]; free.GetCheckoutSessionId() != "" {
+		t.Errorf("checkout opened for a zero amount")
+	}
+}
+
+func TestRunRecurringBilling_MarkOverdue(t *testing.T) {
+	repo := newBillingRevenueRepo(
+		&revenuepb.Revenue{Id: "past-due", DueDate: strPtr("2026-03-31"), Status: "draft"},
+		&revenuepb.Revenue{Id: "due-today", DueDate: strPtr("2026-04-01"), Status: "draft"},
+		&revenuepb.Revenue{Id: "no-due-date", Status: "draft"},
+		&revenuepb.Revenue{Id: "complete", DueDate: strPtr("2026-01-31"), Status: "complete"},
+		&revenuepb.Revenue{Id: "cancelled", DueDate: strPtr("2026-01-31"), Status: "cancelled"},
+		&revenuepb.Revenue{Id: "settled", DueDate: strPtr("2026-01-31"), Status: "draft", SettlementStatus: strPtr("FULLY_SETTLED")},
+		&revenuepb.Revenue{Id: "already", DueDate: strPtr("2026-01-31"), Status: "overdue"},
+	)
+	uc := NewRunRecurringBillingUseCase(RunRecurringBillingRepositories{Revenue: repo}, RunRecurringBillingServices{}, nil, nil)
+
+	result := &RecurringBillingResult{AsOfDate: "2026-04-01"}
+	if err := uc.markOverdue(context.Background(), result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.MarkedOverdue) != 1 || result.MarkedOverdue[0] != "past-due" {
+		t.Fatalf("marked = %v", result.MarkedOverdue)
+	}
+	if repo.revenues["past-due"].GetStatus() != "overdue" || len(repo.updated) != 1 {
+		t.Errorf("status = %q, updates = %v", repo.revenues["past-due"].GetStatus(), repo.updated)
+	}
+}
+
+func TestDueDateFromPaymentTerm(t *testing.T) {
+	for _, c := range []struct {
+		pt   *paymenttermpb.PaymentTerm
+		date string
+		want string
+	}{
+		{&paymenttermpb.PaymentTerm{Type: "NET", NetDays: 30}, "2026-01-15", "2026-02-14"},
+		{&paymenttermpb.PaymentTerm{Type: "due_on_receipt"}, "2026-01-15", "2026-01-15"},
+		{&paymenttermpb.PaymentTerm{Type: "proximate", ProximateDay: func() *int32 { d := int32(10); return &d }()}, "2026-01-15", "2026-02-10"},
+		{&paymenttermpb.PaymentTerm{Type: "net", NetDays: 30}, "", ""},
+		{&paymenttermpb.PaymentTerm{Type: "custom"}, "2026-01-15", ""},
+	} {
+		if got := dueDateFromPaymentTerm(c.pt, c.date); got != c.want {
+			t.Errorf("%s/%s = %q, want %q", c.pt.GetType(), c.date, got, c.want)
+		}
+	}
+}
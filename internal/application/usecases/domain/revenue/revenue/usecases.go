@@ -12,6 +12,7 @@ import (
 	jobtemplatephasepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/operation/job_template_phase"
 	revenuepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue"
 	revenuelineitempb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue_line_item"
+	revenuepaymentpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue_payment"
 	revenuerunpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue_run"
 	billingeventpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/subscription/billing_event"
 	priceplanpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/subscription/price_plan"
@@ -41,6 +42,10 @@ type RevenueRepositories struct {
 	// RevenueRun repo — used by ListRevenueRunCandidates and GenerateRevenueRun.
 	RevenueRun revenuerunpb.RevenueRunDomainServiceServer
 
+	// RevenuePayment repo — used by ReconcilePayments to match and record
+	// provider payments. Optional; when nil, corrections only settle.
+	RevenuePayment revenuepaymentpb.RevenuePaymentDomainServiceServer
+
 	// Milestone-billing branch (Phase C — milestone-billing plan §3).
 	// Optional — only required when MILESTONE PricePlans are billed.
 	BillingEvent     billingeventpb.BillingEventDomainServiceServer
@@ -91,6 +96,8 @@ type UseCases struct {
 	ListRevenueRunCandidates         *ListRevenueRunCandidatesUseCase
 	GenerateRevenueRun               *GenerateRevenueRunUseCase
 	RecomputeTaxes                   *RecomputeTaxesUseCase
+	RunRecurringBilling              *RunRecurringBillingUseCase
+	ReconcilePayments                *ReconcilePaymentsUseCase
 }
 
 // NewUseCases creates a new collection of revenue use cases
@@ -103,55 +110,61 @@ func NewUseCases(
 		PaymentTerm: repositories.PaymentTerm,
 	}
 	createServices := CreateRevenueServices{
-		Authorizer:  services.Authorizer,
-		Transactor:  services.Transactor,
-		Translator:  services.Translator,
-		IDGenerator: services.IDGenerator,
+		Authorizer:       services.Authorizer,
+		Transactor:       services.Transactor,
+		Translator:       services.Translator,
+		IDGenerator:      services.IDGenerator,
+		ActionGatekeeper: services.ActionGatekeeper,
 	}
 
 	readRepos := ReadRevenueRepositories{
 		Revenue: repositories.Revenue,
 	}
 	readServices := ReadRevenueServices{
-		Authorizer: services.Authorizer,
-		Transactor: services.Transactor,
-		Translator: services.Translator,
+		Authorizer:       services.Authorizer,
+		Transactor:       services.Transactor,
+		Translator:       services.Translator,
+		ActionGatekeeper: services.ActionGatekeeper,
 	}
 
 	updateRepos := UpdateRevenueRepositories{
 		Revenue: repositories.Revenue,
 	}
 	updateServices := UpdateRevenueServices{
-		Authorizer: services.Authorizer,
-		Transactor: services.Transactor,
-		Translator: services.Translator,
+		Authorizer:       services.Authorizer,
+		Transactor:       services.Transactor,
+		Translator:       services.Translator,
+		ActionGatekeeper: services.ActionGatekeeper,
 	}
 
 	deleteRepos := DeleteRevenueRepositories{
 		Revenue: repositories.Revenue,
 	}
 	deleteServices := DeleteRevenueServices{
-		Authorizer: services.Authorizer,
-		Transactor: services.Transactor,
-		Translator: services.Translator,
+		Authorizer:       services.Authorizer,
+		Transactor:       services.Transactor,
+		Translator:       services.Translator,
+		ActionGatekeeper: services.ActionGatekeeper,
 	}
 
 	listRepos := ListRevenuesRepositories{
 		Revenue: repositories.Revenue,
 	}
 	listServices := ListRevenuesServices{
-		Authorizer: services.Authorizer,
-		Transactor: services.Transactor,
-		Translator: services.Translator,
+		Authorizer:       services.Authorizer,
+		Transactor:       services.Transactor,
+		Translator:       services.Translator,
+		ActionGatekeeper: services.ActionGatekeeper,
 	}
 
 	getListPageDataRepos := GetRevenueListPageDataRepositories{
 		Revenue: repositories.Revenue,
 	}
 	getListPageDataServices := GetRevenueListPageDataServices{
-		Authorizer: services.Authorizer,
-		Transactor: services.Transactor,
-		Translator: services.Translator,
+		Authorizer:       services.Authorizer,
+		Transactor:       services.Transactor,
+		Translator:       services.Translator,
+		ActionGatekeeper: services.ActionGatekeeper,
 	}
 
 	recognizeRepos := RecognizeRevenueFromSubscriptionRepositories{
@@ -174,6 +187,7 @@ func NewUseCases(
 		IDGenerator:                            services.IDGenerator,
 		MaterializeInstanceJobsForSubscription: services.MaterializeInstanceJobsForSubscription,
 		ComputeTaxes:                           services.ComputeTaxes,
+		ActionGatekeeper:                       services.ActionGatekeeper,
 	}
 
 	recognizeUC := NewRecognizeRevenueFromSubscriptionUseCase(recognizeRepos, recognizeServices)
@@ -187,8 +201,9 @@ func NewUseCases(
 	// Plan B Phase 5a — thread TreasuryCollection through for the advance branch.
 	listCandidatesRepos.TreasuryCollection = repositories.TreasuryCollection
 	listCandidatesServices := ListRevenueRunCandidatesServices{
-		Authorizer: services.Authorizer,
-		Translator: services.Translator,
+		Authorizer:       services.Authorizer,
+		Translator:       services.Translator,
+		ActionGatekeeper: services.ActionGatekeeper,
 	}
 
 	generateRunRepos := GenerateRevenueRunRepositories{
@@ -198,21 +213,42 @@ func NewUseCases(
 		Workspace:    repositories.Workspace,
 	}
 	generateRunServices := GenerateRevenueRunServices{
-		Authorizer:  services.Authorizer,
-		Transactor:  services.Transactor,
-		Translator:  services.Translator,
-		IDGenerator: services.IDGenerator,
+		Authorizer:       services.Authorizer,
+		Transactor:       services.Transactor,
+		Translator:       services.Translator,
+		ActionGatekeeper: services.ActionGatekeeper,
+		IDGenerator:      services.IDGenerator,
 	}
 
 	recomputeTaxesUC := NewRecomputeTaxesUseCase(
 		RecomputeTaxesRepositories{Revenue: repositories.Revenue},
 		RecomputeTaxesServices{
-			Authorizer: services.Authorizer,
-			Translator: services.Translator,
+			Authorizer:       services.Authorizer,
+			Translator:       services.Translator,
+			ActionGatekeeper: services.ActionGatekeeper,
 		},
 		services.ComputeTaxes,
 	)
 
+	listCandidatesUC := NewListRevenueRunCandidatesUseCase(listCandidatesRepos, listCandidatesServices, recognizeUC)
+	generateRunUC := NewGenerateRevenueRunUseCase(generateRunRepos, generateRunServices, recognizeUC).WithAdvanceCollectionAmortizer(services.AmortizeAdvanceCollection)
+
+	// Payment providers are installed by the container after construction
+	// (SetPaymentProviders).
+	runRecurringBillingUC := NewRunRecurringBillingUseCase(
+		RunRecurringBillingRepositories{
+			Revenue:     repositories.Revenue,
+			PaymentTerm: repositories.PaymentTerm,
+			Workspace:   repositories.Workspace,
+		},
+		RunRecurringBillingServices{
+			Translator:       services.Translator,
+			ActionGatekeeper: services.ActionGatekeeper,
+		},
+		listCandidatesUC,
+		generateRunUC,
+	)
+
 	return &UseCases{
 		CreateRevenue:                    NewCreateRevenueUseCase(createRepos, createServices),
 		ReadRevenue:                      NewReadRevenueUseCase(readRepos, readServices),
@@ -221,8 +257,20 @@ func NewUseCases(
 		ListRevenues:                     NewListRevenuesUseCase(listRepos, listServices),
 		GetRevenueListPageData:           NewGetRevenueListPageDataUseCase(getListPageDataRepos, getListPageDataServices),
 		RecognizeRevenueFromSubscription: recognizeUC,
-		ListRevenueRunCandidates:         NewListRevenueRunCandidatesUseCase(listCandidatesRepos, listCandidatesServices, recognizeUC),
-		GenerateRevenueRun:               NewGenerateRevenueRunUseCase(generateRunRepos, generateRunServices, recognizeUC).WithAdvanceCollectionAmortizer(services.AmortizeAdvanceCollection),
+		ListRevenueRunCandidates:         listCandidatesUC,
+		GenerateRevenueRun:               generateRunUC,
 		RecomputeTaxes:                   recomputeTaxesUC,
+		RunRecurringBilling:              runRecurringBillingUC,
+		ReconcilePayments: NewReconcilePaymentsUseCase(
+			ReconcilePaymentsRepositories{
+				Revenue:        repositories.Revenue,
+				RevenuePayment: repositories.RevenuePayment,
+			},
+			ReconcilePaymentsServices{
+				Translator:       services.Translator,
+				ActionGatekeeper: services.ActionGatekeeper,
+				IDGenerator:      services.IDGenerator,
+			},
+		),
 	}
 }
@@ -134,6 +134,7 @@ func NewUseCases(
 			PaymentTerm:      repos.PaymentTerm,
 			Workspace:        repos.Workspace,
 			RevenueRun:       repos.RevenueRun,
+			RevenuePayment:   repos.RevenuePayment,
 
 			// Milestone-billing branch reads (Phase C).
 			BillingEvent:     repos.BillingEvent,
@@ -10,6 +10,7 @@ import (
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 	"github.com/erniealice/espyna-golang/registry/entityid"
 	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
 	invoicepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/subscription/invoice"
 )
@@ -94,6 +95,9 @@ func (uc *CreateInvoiceUseCase) Execute(ctx context.Context, req *invoicepb.Crea
 		return nil, err
 	}
 
+	for _, created := range response.GetData() {
+		domainevent.Emit(ctx, domainevent.InvoiceCreated, created)
+	}
 	return response, nil
 }
 
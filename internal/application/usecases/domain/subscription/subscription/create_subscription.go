@@ -13,6 +13,7 @@ import (
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 	"github.com/erniealice/espyna-golang/registry/entityid"
 	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
 )
 
@@ -109,6 +110,9 @@ func (uc *CreateSubscriptionUseCase) Execute(ctx context.Context, req *subscript
 		}
 	}
 
+	for _, created := range resp.GetData() {
+		domainevent.Emit(ctx, domainevent.SubscriptionCreated, created)
+	}
 	return resp, nil
 }
 
@@ -95,6 +95,10 @@ type MaterializeInstanceJobsForSubscriptionServices struct {
 	Translator  ports.Translator
 	ActionGatekeeper *actiongate.ActionGatekeeper
 	IDGenerator ports.IDGenerator
+
+	// Clock decides which cycles are due and stamps spawned Jobs. nil
+	// means wall-clock time.
+	Clock ports.Clock
 }
 
 // materializeInstanceJobsInternalRequest is the internal input contract.
@@ -146,6 +150,15 @@ func NewMaterializeInstanceJobsForSubscriptionUseCase(
 	}
 }
 
+// SetClock installs the clock that decides which cycles are due. Safe to
+// call with nil — falls back to wall-clock time.
+func (uc *MaterializeInstanceJobsForSubscriptionUseCase) SetClock(clock ports.Clock) {
+	if uc == nil {
+		return
+	}
+	uc.services.Clock = clock
+}
+
 // eligibleForInstanceSpawn reports whether the PricePlan's billing_kind
 // participates in the two-tier shell+instance Job model. Cyclic kinds
 // drive auto-spawn at cycle boundaries; AD_HOC kinds drive operator-requested
@@ -318,7 +331,7 @@ func (uc *MaterializeInstanceJobsForSubscriptionUseCase) executeInternal(
 		}, nil
 	}
 
-	now := time.Now()
+	now := ports.ClockNow(uc.services.Clock)
 	dc := now.UnixMilli()
 	dcs := now.Format(time.RFC3339)
 
@@ -69,6 +69,9 @@ type MaterializeJobsForSubscriptionServices struct {
 	// billing_rule_type == MILESTONE per plan §3.7. Errors propagate and
 	// roll back the entire spawn transaction.
 	MaterializeBillingEventsForJob MaterializeBillingEventsForJobInvoker
+
+	// Clock supplies the spawn timestamp. nil means wall-clock time.
+	Clock ports.Clock
 }
 
 // materializeJobsForSubscriptionInternalRequest is the internal input contract.
@@ -112,6 +115,15 @@ func NewMaterializeJobsForSubscriptionUseCase(
 	}
 }
 
+// SetClock installs the clock that stamps spawned Jobs. Safe to call with
+// nil — falls back to wall-clock time.
+func (uc *MaterializeJobsForSubscriptionUseCase) SetClock(clock ports.Clock) {
+	if uc == nil {
+		return
+	}
+	uc.services.Clock = clock
+}
+
 // Execute drives the full spawn flow per plan §3. The whole §3.3 → §3.7
 // chain runs in a single transaction.
 func (uc *MaterializeJobsForSubscriptionUseCase) Execute(
@@ -219,7 +231,7 @@ func (uc *MaterializeJobsForSubscriptionUseCase) Execute(
 		toSpawn = append(toSpawn, spawnEntry{templateID: childID, isRoot: false})
 	}
 
-	now := time.Now()
+	now := ports.ClockNow(uc.services.Clock)
 	dc := now.UnixMilli()
 	dcs := now.Format(time.RFC3339)
 
@@ -531,7 +543,7 @@ func (uc *MaterializeJobsForSubscriptionUseCase) executeCyclicShell(
 	plan *planpb.Plan,
 	relations []*jobtemplaterelationpb.JobTemplateRelation,
 ) (*materializeJobsForSubscriptionInternalResponse, error) {
-	now := time.Now()
+	now := ports.ClockNow(uc.services.Clock)
 	dc := now.UnixMilli()
 	dcs := now.Format(time.RFC3339)
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	"github.com/erniealice/espyna-golang/registry/entityid"
 	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
 	stagepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/workflow/stage"
@@ -82,7 +83,8 @@ func (uc *UpdateStageUseCase) Execute(ctx context.Context, req *stagepb.UpdateSt
 	}
 
 	// Existence validation
-	if err := uc.validateStageExists(ctx, req.Data.Id); err != nil {
+	existing, err := uc.validateStageExists(ctx, req.Data.Id)
+	if err != nil {
 		return nil, err
 	}
 
@@ -90,12 +92,32 @@ func (uc *UpdateStageUseCase) Execute(ctx context.Context, req *stagepb.UpdateSt
 	enrichedStage := uc.applyBusinessLogic(req.Data)
 
 	// Use transaction service if available
+	var resp *stagepb.UpdateStageResponse
 	if uc.services.Transactor != nil && uc.services.Transactor.SupportsTransactions() {
-		return uc.executeWithTransaction(ctx, enrichedStage)
+		resp, err = uc.executeWithTransaction(ctx, enrichedStage)
+	} else {
+		// Fallback to direct repository call
+		resp, err = uc.executeCore(ctx, enrichedStage)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Fallback to direct repository call
-	return uc.executeCore(ctx, enrichedStage)
+	// Announce the stage's completion once, on the update completing it
+	if !isCompleted(existing) && isCompleted(enrichedStage) {
+		completed := enrichedStage
+		if data := resp.GetData(); len(data) > 0 {
+			completed = data[0]
+		}
+		domainevent.Emit(ctx, domainevent.StageCompleted, completed)
+	}
+	return resp, nil
+}
+
+// isCompleted reports whether stage has a completion date or the completed
+// status.
+func isCompleted(stage *stagepb.Stage) bool {
+	return stage.GetDateCompleted() > 0 || stage.GetStatus() == "completed"
 }
 
 // executeWithTransaction executes stage update within a transaction
@@ -137,8 +159,9 @@ func (uc *UpdateStageUseCase) applyBusinessLogic(stage *stagepb.Stage) *stagepb.
 	return stage
 }
 
-// validateStageExists validates that the stage exists and is active
-func (uc *UpdateStageUseCase) validateStageExists(ctx context.Context, stageID string) error {
+// validateStageExists validates that the stage exists and is active, and
+// returns it
+func (uc *UpdateStageUseCase) validateStageExists(ctx context.Context, stageID string) (*stagepb.Stage, error) {
 	// Check stage exists
 	stageReadReq := &stagepb.ReadStageRequest{
 		Data: &stagepb.Stage{
@@ -147,18 +170,18 @@ func (uc *UpdateStageUseCase) validateStageExists(ctx context.Context, stageID s
 	}
 	stageRes, err := uc.repositories.Stage.ReadStage(ctx, stageReadReq)
 	if err != nil {
-		return errors.New(contextutil.GetTranslatedMessageWithContext(ctx, uc.services.Translator, "stage.errors.stage_not_found", "Stage not found [DEFAULT]"))
+		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(ctx, uc.services.Translator, "stage.errors.stage_not_found", "Stage not found [DEFAULT]"))
 	}
 	if stageRes == nil || len(stageRes.Data) == 0 {
-		return errors.New(contextutil.GetTranslatedMessageWithContext(ctx, uc.services.Translator, "stage.errors.stage_not_found", "Stage not found [DEFAULT]"))
+		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(ctx, uc.services.Translator, "stage.errors.stage_not_found", "Stage not found [DEFAULT]"))
 	}
 
 	// Business rule: Cannot update inactive stage
 	if !stageRes.Data[0].Active {
-		return errors.New(contextutil.GetTranslatedMessageWithContext(ctx, uc.services.Translator, "stage.errors.stage_inactive", "Cannot update inactive stage [DEFAULT]"))
+		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(ctx, uc.services.Translator, "stage.errors.stage_inactive", "Cannot update inactive stage [DEFAULT]"))
 	}
 
-	return nil
+	return stageRes.Data[0], nil
 }
 
 // validateBusinessRules enforces business constraints
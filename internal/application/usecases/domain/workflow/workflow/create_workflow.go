@@ -9,6 +9,7 @@ import (
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
 	"github.com/erniealice/espyna-golang/registry/entityid"
 	workflowpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/workflow/workflow"
@@ -89,12 +90,22 @@ func (uc *CreateWorkflowUseCase) Execute(ctx context.Context, req *workflowpb.Cr
 	enrichedWorkflow := uc.applyBusinessLogic(req.Data)
 
 	// Use transaction service if available
+	var resp *workflowpb.CreateWorkflowResponse
+	var err error
 	if uc.services.Transactor != nil && uc.services.Transactor.SupportsTransactions() {
-		return uc.executeWithTransaction(ctx, enrichedWorkflow)
+		resp, err = uc.executeWithTransaction(ctx, enrichedWorkflow)
+	} else {
+		// Fallback to direct repository call
+		resp, err = uc.executeCore(ctx, enrichedWorkflow)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Fallback to direct repository call
-	return uc.executeCore(ctx, enrichedWorkflow)
+	for _, created := range resp.GetData() {
+		domainevent.Emit(ctx, domainevent.WorkflowCreated, created)
+	}
+	return resp, nil
 }
 
 // executeWithTransaction executes workflow creation within a transaction
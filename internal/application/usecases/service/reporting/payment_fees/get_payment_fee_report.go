@@ -0,0 +1,184 @@
+package payment_fees
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/ports/domain"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// maxFeeReportRangeDays bounds the report range, same limit as the staff
+// workload report.
+const maxFeeReportRangeDays = 366
+
+// GetPaymentFeeReportRequest is the Go-only request shape. There is no
+// proto contract yet; the revenue dashboard calls Execute directly.
+type GetPaymentFeeReportRequest struct {
+	// StartMillis / EndMillis form the half-open report range in epoch
+	// milliseconds (UTC), applied to the webhook received time.
+	StartMillis int64
+	EndMillis   int64
+	// ProviderID optionally narrows the report to one provider.
+	ProviderID string
+}
+
+// PaymentFeeEntry is one provider/currency line. Amounts are minor units.
+type PaymentFeeEntry struct {
+	ProviderID     string  `json:"provider_id"`
+	Currency       string  `json:"currency"`
+	Payments       int64   `json:"payments"`
+	GrossAmount    int64   `json:"gross_amount"`
+	FeeAmount      int64   `json:"fee_amount"`
+	NetAmount      int64   `json:"net_amount"`
+	RefundedAmount int64   `json:"refunded_amount"`
+	FeePercent     float64 `json:"fee_percent"`
+}
+
+// CurrencyTotal sums every provider for one currency. Amounts in different
+// currencies are never added together.
+type CurrencyTotal struct {
+	Currency       string  `json:"currency"`
+	Payments       int64   `json:"payments"`
+	GrossAmount    int64   `json:"gross_amount"`
+	FeeAmount      int64   `json:"fee_amount"`
+	NetAmount      int64   `json:"net_amount"`
+	RefundedAmount int64   `json:"refunded_amount"`
+	FeePercent     float64 `json:"fee_percent"`
+}
+
+// GetPaymentFeeReportResponse carries the per-provider lines plus the
+// per-currency totals.
+type GetPaymentFeeReportResponse struct {
+	StartMillis int64              `json:"start_millis"`
+	EndMillis   int64              `json:"end_millis"`
+	Providers   []*PaymentFeeEntry `json:"providers"`
+	Totals      []*CurrencyTotal   `json:"totals"`
+}
+
+// GetPaymentFeeReportUseCase reports gross, provider fees and net revenue
+// per payment provider.
+type GetPaymentFeeReportUseCase struct {
+	reporter             reporter
+	authorizationService ports.Authorizer
+	translationService   ports.Translator
+	actionGatekeeper     *actiongate.ActionGatekeeper
+}
+
+// NewGetPaymentFeeReportUseCase wires the use case with nil-safe deps.
+func NewGetPaymentFeeReportUseCase(
+	r reporter,
+	authSvc ports.Authorizer,
+	i18nSvc ports.Translator,
+	actionGate *actiongate.ActionGatekeeper,
+) *GetPaymentFeeReportUseCase {
+	if i18nSvc == nil {
+		i18nSvc = ports.NewNoOpTranslator()
+	}
+	return &GetPaymentFeeReportUseCase{
+		reporter:             r,
+		authorizationService: authSvc,
+		translationService:   i18nSvc,
+		actionGatekeeper:     actionGate,
+	}
+}
+
+// Available reports whether a fee reporter is wired. Transports use it to
+// answer "not configured" without running the action gate.
+func (uc *GetPaymentFeeReportUseCase) Available() bool {
+	return uc != nil && uc.reporter != nil
+}
+
+// Execute runs the aggregate and derives fee percentages and per-currency
+// totals.
+func (uc *GetPaymentFeeReportUseCase) Execute(
+	ctx context.Context,
+	req *GetPaymentFeeReportRequest,
+) (*GetPaymentFeeReportResponse, error) {
+	if err := uc.actionGatekeeper.Check(ctx, &actiongate.CheckActionRequest{
+		Entity: "reports",
+		Action: entityid.ActionList,
+	}); err != nil {
+		return nil, err
+	}
+	if req == nil || req.EndMillis <= req.StartMillis {
+		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(
+			ctx, uc.translationService,
+			"reports.validation.invalid_date_range", "A valid date range is required [DEFAULT]"))
+	}
+	if time.Duration(req.EndMillis-req.StartMillis)*time.Millisecond > maxFeeReportRangeDays*24*time.Hour {
+		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(
+			ctx, uc.translationService,
+			"reports.validation.date_range_too_long", "Date range cannot exceed one year [DEFAULT]"))
+	}
+	if uc.reporter == nil {
+		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(
+			ctx, uc.translationService,
+			"reports.errors.reporter_unavailable", "Payment fee report is unavailable [DEFAULT]"))
+	}
+
+	rows, err := uc.reporter.ListPaymentFees(ctx, &domain.PaymentFeeReportRequest{
+		Start:      time.UnixMilli(req.StartMillis).UTC(),
+		End:        time.UnixMilli(req.EndMillis).UTC(),
+		ProviderID: req.ProviderID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buildFeeReport(req.StartMillis, req.EndMillis, rows), nil
+}
+
+// buildFeeReport maps adapter rows to the response and totals them per
+// currency, keeping currencies in first-seen order.
+func buildFeeReport(start, end int64, rows []*domain.PaymentFeeRow) *GetPaymentFeeReportResponse {
+	resp := &GetPaymentFeeReportResponse{
+		StartMillis: start,
+		EndMillis:   end,
+		Providers:   make([]*PaymentFeeEntry, 0, len(rows)),
+		Totals:      make([]*CurrencyTotal, 0),
+	}
+	byCurrency := make(map[string]*CurrencyTotal)
+	for _, row := range rows {
+		if row == nil {
+			continue
+		}
+		resp.Providers = append(resp.Providers, &PaymentFeeEntry{
+			ProviderID:     row.ProviderID,
+			Currency:       row.Currency,
+			Payments:       row.Payments,
+			GrossAmount:    row.GrossAmount,
+			FeeAmount:      row.FeeAmount,
+			NetAmount:      row.NetAmount,
+			RefundedAmount: row.RefundedAmount,
+			FeePercent:     feePercent(row.FeeAmount, row.GrossAmount),
+		})
+		total, ok := byCurrency[row.Currency]
+		if !ok {
+			total = &CurrencyTotal{Currency: row.Currency}
+			byCurrency[row.Currency] = total
+			resp.Totals = append(resp.Totals, total)
+		}
+		total.Payments += row.Payments
+		total.GrossAmount += row.GrossAmount
+		total.FeeAmount += row.FeeAmount
+		total.NetAmount += row.NetAmount
+		total.RefundedAmount += row.RefundedAmount
+	}
+	for _, total := range resp.Totals {
+		total.FeePercent = feePercent(total.FeeAmount, total.GrossAmount)
+	}
+	return resp
+}
+
+// feePercent returns fee/gross as a percentage rounded to two decimals.
+func feePercent(fee, gross int64) float64 {
+	if gross <= 0 {
+		return 0
+	}
+	return math.Round(float64(fee)/float64(gross)*10000) / 100
+}
@@ -0,0 +1,87 @@
+// Package payment_fees hosts the provider fee and net revenue report: gross
+// collected, provider fees withheld and net settled, per payment provider
+// and currency.
+//
+// Like the workload report, the aggregate SQL lives in its own query
+// repository resolved through `registry.GetPaymentFeeQueryFactory`, not on
+// the postgres `LedgerReportingAdapter`. Figures come from the payment
+// webhook log, where providers record their fee breakdown at capture time.
+//
+// Pattern compliance (same as the ledger report groups):
+//   - `reporter` interface UNEXPORTED.
+//   - `setReporter` UNEXPORTED.
+//   - `SetReporterFromAny(any) bool` returns true on success.
+//   - `Deps.Reporter any` (not typed) so the initializer can pass the raw
+//     factory output.
+package payment_fees
+
+import (
+	"context"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/ports/domain"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+)
+
+// reporter is the narrow port for the fee aggregate. The postgres
+// `PostgresPaymentFeeRepository` satisfies it structurally.
+type reporter interface {
+	ListPaymentFees(ctx context.Context, req *domain.PaymentFeeReportRequest) ([]*domain.PaymentFeeRow, error)
+}
+
+// Deps groups the construction-time dependencies. `Reporter` carries `any`
+// from the umbrella; the assertion happens inside this package.
+type Deps struct {
+	Reporter         any
+	Authorizer       ports.Authorizer
+	Translator       ports.Translator
+	ActionGatekeeper *actiongate.ActionGatekeeper
+}
+
+// UseCases aggregates every payment fee use case.
+type UseCases struct {
+	GetPaymentFeeReport *GetPaymentFeeReportUseCase
+}
+
+// setReporter rewires every use case to a non-nil reporter after
+// construction.
+//
+// **Unexported** — public rewire path is [SetReporterFromAny].
+func (u *UseCases) setReporter(r reporter) {
+	if u == nil {
+		return
+	}
+	if u.GetPaymentFeeReport != nil {
+		u.GetPaymentFeeReport.reporter = r
+	}
+}
+
+// SetReporterFromAny is the canonical wiring entry point. Returns `true` on
+// success, `false` when u is nil, v is nil, or v doesn't satisfy the
+// unexported `reporter` interface.
+func (u *UseCases) SetReporterFromAny(v any) bool {
+	if u == nil || v == nil {
+		return false
+	}
+	r, ok := v.(reporter)
+	if !ok {
+		return false
+	}
+	u.setReporter(r)
+	return true
+}
+
+// NewUseCases wires the payment fee sub-aggregate. `deps` may be nil; with
+// no reporter Execute returns the translated "reporter unavailable" error.
+func NewUseCases(deps *Deps) *UseCases {
+	if deps == nil {
+		deps = &Deps{}
+	}
+	var r reporter
+	if deps.Reporter != nil {
+		r, _ = deps.Reporter.(reporter)
+	}
+	return &UseCases{
+		GetPaymentFeeReport: NewGetPaymentFeeReportUseCase(r, deps.Authorizer, deps.Translator, deps.ActionGatekeeper),
+	}
+}
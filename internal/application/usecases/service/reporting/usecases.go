@@ -59,7 +59,9 @@ import (
 	"github.com/erniealice/espyna-golang/internal/application/usecases/service/reporting/ar_aging"
 	"github.com/erniealice/espyna-golang/internal/application/usecases/service/reporting/domain_specific"
 	"github.com/erniealice/espyna-golang/internal/application/usecases/service/reporting/gross_cashflow"
+	"github.com/erniealice/espyna-golang/internal/application/usecases/service/reporting/payment_fees"
 	"github.com/erniealice/espyna-golang/internal/application/usecases/service/reporting/statements"
+	"github.com/erniealice/espyna-golang/internal/application/usecases/service/reporting/workload"
 )
 
 // Deps is the dependency surface for the Reporting umbrella factory.
@@ -140,6 +142,24 @@ type Deps struct {
 	// consumers now thread typed closures off
 	// `useCases.Service.Reporting.<Group>` instead of the wrapper).
 	DomainSpecificReporter any
+
+	// WorkloadReporter carries the raw postgres staff workload query
+	// repository as `any`. Unlike the five ledger groups it is NOT the
+	// LedgerReportingAdapter — the initializer resolves it from
+	// `GetStaffWorkloadQueryFactory`. The unexported `workload.reporter`
+	// interface stays private to the leaf package.
+	//
+	// May be nil — `workload.NewUseCases` then returns the translated
+	// "reporter unavailable" error.
+	WorkloadReporter any
+
+	// PaymentFeesReporter carries the raw postgres payment fee query
+	// repository as `any`, resolved from `GetPaymentFeeQueryFactory`. Same
+	// shape as WorkloadReporter.
+	//
+	// May be nil — `payment_fees.NewUseCases` then returns the translated
+	// "reporter unavailable" error.
+	PaymentFeesReporter any
 }
 
 // ReportingUseCases aggregates every service-driven ledger reporting
@@ -186,6 +206,17 @@ type ReportingUseCases struct {
 	// PYEZA. Both retirements landed 2026-05-21 alongside the downstream
 	// rewires.
 	DomainSpecific *domain_specific.UseCases
+
+	// Workload hosts GetStaffWorkloadReport — per-staff scheduled hours,
+	// open workflow tasks, assigned clients and utilization for the
+	// operations dashboard. Not part of the ledger decomposition; backed by
+	// its own aggregate query repository.
+	Workload *workload.UseCases
+
+	// PaymentFees hosts GetPaymentFeeReport — gross, provider fees and net
+	// revenue per payment provider and currency, read from the payment
+	// webhook log.
+	PaymentFees *payment_fees.UseCases
 }
 
 // NewReportingUseCases constructs the umbrella aggregate. Initial body
@@ -241,5 +272,17 @@ func NewReportingUseCases(deps *Deps) *ReportingUseCases {
 			Translator:       deps.Translator,
 			ActionGatekeeper: deps.ActionGatekeeper,
 		}),
+		Workload: workload.NewUseCases(&workload.Deps{
+			Reporter:         deps.WorkloadReporter,
+			Authorizer:       deps.Authorizer,
+			Translator:       deps.Translator,
+			ActionGatekeeper: deps.ActionGatekeeper,
+		}),
+		PaymentFees: payment_fees.NewUseCases(&payment_fees.Deps{
+			Reporter:         deps.PaymentFeesReporter,
+			Authorizer:       deps.Authorizer,
+			Translator:       deps.Translator,
+			ActionGatekeeper: deps.ActionGatekeeper,
+		}),
 	}
 }
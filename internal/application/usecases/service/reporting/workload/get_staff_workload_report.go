@@ -0,0 +1,193 @@
+package workload
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/ports/domain"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// defaultCapacityHoursPerDay is the working-day capacity assumed when the
+// caller does not supply one.
+const defaultCapacityHoursPerDay = 8
+
+// maxWorkloadRangeDays bounds the report range so a typo in the query string
+// cannot turn into a multi-decade scan of the event table.
+const maxWorkloadRangeDays = 366
+
+// GetStaffWorkloadReportRequest is the Go-only request shape. There is no
+// proto contract yet; the operations dashboard calls Execute directly.
+type GetStaffWorkloadReportRequest struct {
+	// StartMillis / EndMillis form the half-open report range in epoch
+	// milliseconds (UTC).
+	StartMillis int64
+	EndMillis   int64
+	// StaffID optionally narrows the report to one staff member.
+	StaffID string
+	// CapacityHoursPerDay is the per-working-day capacity. Zero or negative
+	// means defaultCapacityHoursPerDay.
+	CapacityHoursPerDay float64
+}
+
+// StaffWorkloadEntry is one staff member's workload for the range.
+type StaffWorkloadEntry struct {
+	StaffID            string  `json:"staff_id"`
+	Name               string  `json:"name"`
+	ScheduledHours     float64 `json:"scheduled_hours"`
+	CapacityHours      float64 `json:"capacity_hours"`
+	UtilizationPercent float64 `json:"utilization_percent"`
+	OpenTasks          int64   `json:"open_tasks"`
+	AssignedClients    int64   `json:"assigned_clients"`
+}
+
+// GetStaffWorkloadReportResponse carries the per-staff rows plus the team
+// totals the dashboard header shows.
+type GetStaffWorkloadReportResponse struct {
+	StartMillis        int64                 `json:"start_millis"`
+	EndMillis          int64                 `json:"end_millis"`
+	WorkingDays        int                   `json:"working_days"`
+	Staff              []*StaffWorkloadEntry `json:"staff"`
+	TotalScheduled     float64               `json:"total_scheduled_hours"`
+	TotalCapacity      float64               `json:"total_capacity_hours"`
+	UtilizationPercent float64               `json:"utilization_percent"`
+}
+
+// GetStaffWorkloadReportUseCase computes scheduled hours, open workflow
+// tasks, assigned clients and utilization per staff member.
+type GetStaffWorkloadReportUseCase struct {
+	reporter             reporter
+	authorizationService ports.Authorizer
+	translationService   ports.Translator
+	actionGatekeeper     *actiongate.ActionGatekeeper
+}
+
+// NewGetStaffWorkloadReportUseCase wires the use case with nil-safe deps.
+func NewGetStaffWorkloadReportUseCase(
+	r reporter,
+	authSvc ports.Authorizer,
+	i18nSvc ports.Translator,
+	actionGate *actiongate.ActionGatekeeper,
+) *GetStaffWorkloadReportUseCase {
+	if i18nSvc == nil {
+		i18nSvc = ports.NewNoOpTranslator()
+	}
+	return &GetStaffWorkloadReportUseCase{
+		reporter:             r,
+		authorizationService: authSvc,
+		translationService:   i18nSvc,
+		actionGatekeeper:     actionGate,
+	}
+}
+
+// Available reports whether a workload reporter is wired. Transports use it
+// to answer "not configured" without running the action gate.
+func (uc *GetStaffWorkloadReportUseCase) Available() bool {
+	return uc != nil && uc.reporter != nil
+}
+
+// Execute runs the aggregate for the active workspace (from context) and
+// derives capacity and utilization. Capacity counts Monday–Friday UTC days
+// touched by the range.
+func (uc *GetStaffWorkloadReportUseCase) Execute(
+	ctx context.Context,
+	req *GetStaffWorkloadReportRequest,
+) (*GetStaffWorkloadReportResponse, error) {
+	if err := uc.actionGatekeeper.Check(ctx, &actiongate.CheckActionRequest{
+		Entity: "reports",
+		Action: entityid.ActionList,
+	}); err != nil {
+		return nil, err
+	}
+	if req == nil || req.EndMillis <= req.StartMillis {
+		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(
+			ctx, uc.translationService,
+			"reports.validation.invalid_date_range", "A valid date range is required [DEFAULT]"))
+	}
+	if time.Duration(req.EndMillis-req.StartMillis)*time.Millisecond > maxWorkloadRangeDays*24*time.Hour {
+		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(
+			ctx, uc.translationService,
+			"reports.validation.date_range_too_long", "Date range cannot exceed one year [DEFAULT]"))
+	}
+	if uc.reporter == nil {
+		return nil, errors.New(contextutil.GetTranslatedMessageWithContext(
+			ctx, uc.translationService,
+			"reports.errors.reporter_unavailable", "Staff workload report is unavailable [DEFAULT]"))
+	}
+
+	rows, err := uc.reporter.ListStaffWorkload(ctx, &domain.StaffWorkloadRequest{
+		WorkspaceID: contextutil.ExtractWorkspaceIDFromContext(ctx),
+		StaffID:     req.StaffID,
+		StartMillis: req.StartMillis,
+		EndMillis:   req.EndMillis,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	perDay := req.CapacityHoursPerDay
+	if perDay <= 0 {
+		perDay = defaultCapacityHoursPerDay
+	}
+	days := workingDays(time.UnixMilli(req.StartMillis), time.UnixMilli(req.EndMillis))
+	capacity := float64(days) * perDay
+
+	resp := &GetStaffWorkloadReportResponse{
+		StartMillis: req.StartMillis,
+		EndMillis:   req.EndMillis,
+		WorkingDays: days,
+		Staff:       make([]*StaffWorkloadEntry, 0, len(rows)),
+	}
+	for _, row := range rows {
+		scheduled := roundHours(float64(row.ScheduledMinutes) / 60)
+		resp.Staff = append(resp.Staff, &StaffWorkloadEntry{
+			StaffID:            row.StaffID,
+			Name:               strings.TrimSpace(row.FirstName + " " + row.LastName),
+			ScheduledHours:     scheduled,
+			CapacityHours:      capacity,
+			UtilizationPercent: utilization(scheduled, capacity),
+			OpenTasks:          row.OpenTasks,
+			AssignedClients:    row.AssignedClients,
+		})
+		resp.TotalScheduled += scheduled
+		resp.TotalCapacity += capacity
+	}
+	resp.TotalScheduled = roundHours(resp.TotalScheduled)
+	resp.UtilizationPercent = utilization(resp.TotalScheduled, resp.TotalCapacity)
+	return resp, nil
+}
+
+// workingDays counts Monday–Friday UTC calendar days that overlap the
+// half-open range [start, end).
+func workingDays(start, end time.Time) int {
+	if !end.After(start) {
+		return 0
+	}
+	day := time.Date(start.UTC().Year(), start.UTC().Month(), start.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	count := 0
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if wd := day.Weekday(); wd != time.Saturday && wd != time.Sunday {
+			count++
+		}
+	}
+	return count
+}
+
+// utilization returns scheduled/capacity as a percentage rounded to one
+// decimal place. Over-allocation is reported as-is (above 100).
+func utilization(scheduled, capacity float64) float64 {
+	if capacity <= 0 {
+		return 0
+	}
+	return math.Round(scheduled/capacity*1000) / 10
+}
+
+func roundHours(h float64) float64 {
+	return math.Round(h*100) / 100
+}
@@ -0,0 +1,41 @@
+package workload
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkingDays(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       int
+	}{
+		{"full week", day(2026, 10, 12), day(2026, 10, 19), 5},
+		{"weekend only", day(2026, 10, 17), day(2026, 10, 19), 0},
+		{"partial day counts", day(2026, 10, 12).Add(9 * time.Hour), day(2026, 10, 12).Add(17 * time.Hour), 1},
+		{"empty range", day(2026, 10, 12), day(2026, 10, 12), 0},
+		{"two weeks", day(2026, 10, 1), day(2026, 10, 15), 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := workingDays(tt.start, tt.end); got != tt.want {
+				t.Errorf("workingDays() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUtilization(t *testing.T) {
+	if got := utilization(20, 40); got != 50 {
+		t.Errorf("utilization(20, 40) = %v, want 50", got)
+	}
+	if got := utilization(45, 40); got != 112.5 {
+		t.Errorf("utilization(45, 40) = %v, want 112.5", got)
+	}
+	if got := utilization(10, 0); got != 0 {
+		t.Errorf("utilization(10, 0) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,89 @@
+// Package workload hosts the service-driven staff workload and capacity
+// report for the operations dashboard.
+//
+// Unlike the ledger report groups, the aggregate SQL does not live on the
+// postgres `LedgerReportingAdapter`; it is a separate query repository
+// resolved through `registry.GetStaffWorkloadQueryFactory` (same shape as
+// the assignee query bridge). The adapter returns raw per-staff aggregates
+// — scheduled minutes, open workflow tasks, assigned clients — and this
+// package derives capacity hours and utilization so the policy stays
+// dialect-neutral.
+//
+// Pattern compliance (same as the ledger report groups):
+//   - `reporter` interface UNEXPORTED.
+//   - `setReporter` UNEXPORTED.
+//   - `SetReporterFromAny(any) bool` returns true on success.
+//   - `Deps.Reporter any` (not typed) so the initializer can pass the raw
+//     factory output.
+package workload
+
+import (
+	"context"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/ports/domain"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+)
+
+// reporter is the narrow port for the workload aggregate. The postgres
+// `PostgresStaffWorkloadRepository` satisfies it structurally.
+type reporter interface {
+	ListStaffWorkload(ctx context.Context, req *domain.StaffWorkloadRequest) ([]*domain.StaffWorkloadRow, error)
+}
+
+// Deps groups the construction-time dependencies. `Reporter` carries `any`
+// from the umbrella; the assertion happens inside this package.
+type Deps struct {
+	Reporter         any
+	Authorizer       ports.Authorizer
+	Translator       ports.Translator
+	ActionGatekeeper *actiongate.ActionGatekeeper
+}
+
+// UseCases aggregates every workload use case.
+type UseCases struct {
+	GetStaffWorkloadReport *GetStaffWorkloadReportUseCase
+}
+
+// setReporter rewires every use case to a non-nil reporter after
+// construction.
+//
+// **Unexported** — public rewire path is [SetReporterFromAny].
+func (u *UseCases) setReporter(r reporter) {
+	if u == nil {
+		return
+	}
+	if u.GetStaffWorkloadReport != nil {
+		u.GetStaffWorkloadReport.reporter = r
+	}
+}
+
+// SetReporterFromAny is the canonical wiring entry point. Returns `true` on
+// success, `false` when u is nil, v is nil, or v doesn't satisfy the
+// unexported `reporter` interface.
+func (u *UseCases) SetReporterFromAny(v any) bool {
+	if u == nil || v == nil {
+		return false
+	}
+	r, ok := v.(reporter)
+	if !ok {
+		return false
+	}
+	u.setReporter(r)
+	return true
+}
+
+// NewUseCases wires the workload sub-aggregate. `deps` may be nil; with no
+// reporter Execute returns the translated "reporter unavailable" error.
+func NewUseCases(deps *Deps) *UseCases {
+	if deps == nil {
+		deps = &Deps{}
+	}
+	var r reporter
+	if deps.Reporter != nil {
+		r, _ = deps.Reporter.(reporter)
+	}
+	return &UseCases{
+		GetStaffWorkloadReport: NewGetStaffWorkloadReportUseCase(r, deps.Authorizer, deps.Translator, deps.ActionGatekeeper),
+	}
+}
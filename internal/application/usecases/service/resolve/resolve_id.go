@@ -0,0 +1,154 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// EntityReader is the minimal read port the resolver needs. It is satisfied
+// by the generic DatabaseOperation (Read returns an error for a missing row
+// or table, which the resolver treats as "not this entity").
+type EntityReader interface {
+	Read(ctx context.Context, tableName string, id string) (map[string]any, error)
+}
+
+// ErrIDNotFound is returned when no candidate entity holds the given ID.
+var ErrIDNotFound = errors.New("id not found in any entity")
+
+// summaryLabelFields are checked in order to pick a human-readable label.
+var summaryLabelFields = []string{"name", "display_name", "title", "label", "code", "reference_number", "email"}
+
+// ResolveIDRequest is the input for the ResolveID use case.
+type ResolveIDRequest struct {
+	ID string
+}
+
+// ResolveIDSummary is the minimal, type-agnostic view of the matched row.
+type ResolveIDSummary struct {
+	ID           string `json:"id"`
+	Label        string `json:"label,omitempty"`
+	Active       *bool  `json:"active,omitempty"`
+	DateCreated  any    `json:"date_created,omitempty"`
+	DateModified any    `json:"date_modified,omitempty"`
+}
+
+// ResolveIDResponse carries the resolved entity type and summary.
+type ResolveIDResponse struct {
+	EntityType string           `json:"entity_type"`
+	Table      string           `json:"table"`
+	MatchedBy  string           `json:"matched_by"` // "prefix" or "scan"
+	Summary    ResolveIDSummary `json:"summary"`
+}
+
+// ResolveIDUseCase identifies which entity type an ID belongs to.
+type ResolveIDUseCase struct {
+	repositories Repositories
+	services     Services
+}
+
+// NewResolveIDUseCase wires the use case.
+func NewResolveIDUseCase(repositories Repositories, services Services) *ResolveIDUseCase {
+	if len(repositories.Entities) == 0 {
+		repositories.Entities = entityid.All
+	}
+	return &ResolveIDUseCase{repositories: repositories, services: services}
+}
+
+// Execute looks the ID up by prefix first, then falls back to probing every
+// candidate entity in order. The first hit wins. The caller must hold read
+// permission on the resolved entity type; the summary is withheld otherwise.
+func (uc *ResolveIDUseCase) Execute(ctx context.Context, req *ResolveIDRequest) (*ResolveIDResponse, error) {
+	if req == nil || strings.TrimSpace(req.ID) == "" {
+		return nil, errors.New("id is required")
+	}
+	if uc.repositories.EntityReader == nil {
+		return nil, ErrIDNotFound
+	}
+	id := strings.TrimSpace(req.ID)
+
+	if entity, ok := uc.entityForPrefix(id); ok {
+		if resp := uc.probe(ctx, entity, id); resp != nil {
+			resp.MatchedBy = "prefix"
+			return uc.authorize(ctx, resp)
+		}
+	}
+
+	for _, entity := range uc.repositories.Entities {
+		if resp := uc.probe(ctx, entity, id); resp != nil {
+			resp.MatchedBy = "scan"
+			return uc.authorize(ctx, resp)
+		}
+	}
+	return nil, ErrIDNotFound
+}
+
+func (uc *ResolveIDUseCase) entityForPrefix(id string) (string, bool) {
+	if len(uc.repositories.Prefixes) == 0 {
+		return "", false
+	}
+	prefix, _, found := strings.Cut(id, "_")
+	if !found {
+		return "", false
+	}
+	entity, ok := uc.repositories.Prefixes[prefix]
+	return entity, ok
+}
+
+func (uc *ResolveIDUseCase) probe(ctx context.Context, entity, id string) *ResolveIDResponse {
+	table := entity
+	if uc.repositories.TableName != nil {
+		table = uc.repositories.TableName(entity)
+	}
+	row, err := uc.repositories.EntityReader.Read(ctx, table, id)
+	if err != nil || row == nil {
+		return nil
+	}
+	return &ResolveIDResponse{
+		EntityType: entity,
+		Table:      table,
+		Summary:    summarize(id, row),
+	}
+}
+
+func (uc *ResolveIDUseCase) authorize(ctx context.Context, resp *ResolveIDResponse) (*ResolveIDResponse, error) {
+	if err := uc.services.ActionGatekeeper.Check(ctx, &actiongate.CheckActionRequest{
+		Entity: resp.EntityType,
+		Action: entityid.ActionRead,
+	}); err != nil {
+		return nil, fmt.Errorf("resolved to %s: %w", resp.EntityType, err)
+	}
+	return resp, nil
+}
+
+// summarize extracts the type-agnostic fields every table carries plus the
+// first non-empty label-like column.
+func summarize(id string, row map[string]any) ResolveIDSummary {
+	s := ResolveIDSummary{ID: id}
+	if v, ok := row["id"].(string); ok && v != "" {
+		s.ID = v
+	}
+	for _, field := range summaryLabelFields {
+		if v, ok := row[field]; ok && v != nil {
+			if label := strings.TrimSpace(fmt.Sprint(v)); label != "" {
+				s.Label = label
+				break
+			}
+		}
+	}
+	if s.Label == "" {
+		first, _ := row["first_name"].(string)
+		last, _ := row["last_name"].(string)
+		s.Label = strings.TrimSpace(first + " " + last)
+	}
+	if v, ok := row["active"].(bool); ok {
+		s.Active = &v
+	}
+	s.DateCreated = row["date_created"]
+	s.DateModified = row["date_modified"]
+	return s
+}
@@ -0,0 +1,74 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+)
+
+// fakeReader serves rows keyed by "table/id" and counts Read calls.
+type fakeReader struct {
+	rows  map[string]map[string]any
+	reads int
+}
+
+func (f *fakeReader) Read(_ context.Context, table, id string) (map[string]any, error) {
+	f.reads++
+	if row, ok := f.rows[table+"/"+id]; ok {
+		return row, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func newTestUseCase(reader *fakeReader, prefixes map[string]string) *ResolveIDUseCase {
+	return NewResolveIDUseCase(
+		Repositories{
+			EntityReader: reader,
+			TableName:    func(entity string) string { return "t_" + entity },
+			Entities:     []string{"client", "invoice", "user"},
+			Prefixes:     prefixes,
+		},
+		Services{ActionGatekeeper: testutil.OpenActionGate()},
+	)
+}
+
+func TestResolveID_ScanFindsEntity(t *testing.T) {
+	reader := &fakeReader{rows: map[string]map[string]any{
+		"t_invoice/abc": {"id": "abc", "reference_number": "INV-001", "active": true},
+	}}
+	resp, err := newTestUseCase(reader, nil).Execute(context.Background(), &ResolveIDRequest{ID: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.EntityType != "invoice" || resp.Table != "t_invoice" || resp.MatchedBy != "scan" {
+		t.Fatalf("unexpected match: %+v", resp)
+	}
+	if resp.Summary.Label != "INV-001" || resp.Summary.Active == nil || !*resp.Summary.Active {
+		t.Fatalf("unexpected summary: %+v", resp.Summary)
+	}
+}
+
+func TestResolveID_PrefixSkipsScan(t *testing.T) {
+	reader := &fakeReader{rows: map[string]map[string]any{
+		"t_user/usr_1": {"first_name": "Ada", "last_name": "Lovelace"},
+	}}
+	resp, err := newTestUseCase(reader, map[string]string{"usr": "user"}).Execute(context.Background(), &ResolveIDRequest{ID: "usr_1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.MatchedBy != "prefix" || reader.reads != 1 {
+		t.Fatalf("expected single prefix probe, got matched_by=%s reads=%d", resp.MatchedBy, reader.reads)
+	}
+	if resp.Summary.Label != "Ada Lovelace" {
+		t.Fatalf("unexpected label %q", resp.Summary.Label)
+	}
+}
+
+func TestResolveID_NotFound(t *testing.T) {
+	_, err := newTestUseCase(&fakeReader{}, nil).Execute(context.Background(), &ResolveIDRequest{ID: "missing"})
+	if !errors.Is(err, ErrIDNotFound) {
+		t.Fatalf("expected ErrIDNotFound, got %v", err)
+	}
+}
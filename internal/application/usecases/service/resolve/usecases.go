@@ -0,0 +1,52 @@
+// Package resolve hosts the service-driven global ID resolution use cases.
+//
+// ResolveID answers "which entity does this bare ID belong to?" for support
+// tooling: customers paste IDs out of logs and screenshots, and operators
+// need the entity type plus a minimal summary without knowing which table
+// to look in.
+package resolve
+
+import (
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+)
+
+// UseCases aggregates every service-driven resolve use case.
+type UseCases struct {
+	ResolveID *ResolveIDUseCase
+}
+
+// Repositories groups infrastructure dependencies. EntityReader may be nil
+// when no database provider is registered — ResolveID then reports every
+// ID as not found.
+type Repositories struct {
+	EntityReader EntityReader
+
+	// TableName maps an entityid constant to its table/collection name
+	// (registry.TableConfig.TableName). Nil means identity.
+	TableName func(entity string) string
+
+	// Entities is the candidate list probed in order when the ID carries no
+	// recognised prefix. Defaults to entityid.All.
+	Entities []string
+
+	// Prefixes maps an ID prefix (the part before the first "_") to its
+	// entityid constant, e.g. {"cli": entityid.Client}. IDs whose prefix
+	// is known skip the full scan.
+	Prefixes map[string]string
+}
+
+// Services groups application services.
+type Services struct {
+	Authorizer       ports.Authorizer
+	Translator       ports.Translator
+	ActionGatekeeper *actiongate.ActionGatekeeper
+}
+
+// NewUseCases wires every resolve service use case from shared
+// dependencies.
+func NewUseCases(repositories Repositories, services Services) *UseCases {
+	return &UseCases{
+		ResolveID: NewResolveIDUseCase(repositories, services),
+	}
+}
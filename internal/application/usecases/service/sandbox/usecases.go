@@ -0,0 +1,53 @@
+// Package sandbox hosts service-driven use cases that only make sense in
+// sandbox and test environments.
+//
+// AdjustClock moves the business clock (the TravelClock installed when
+// LEAPFOR_SANDBOX_TIME_TRAVEL is set) so proration, cycle-job spawning,
+// SLA breaches and workflow timestamps can be exercised deterministically
+// without waiting for real time to pass.
+package sandbox
+
+import (
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+)
+
+// UseCases aggregates every service-driven sandbox use case.
+type UseCases struct {
+	GetClock    *GetClockUseCase
+	AdjustClock *AdjustClockUseCase
+}
+
+// ControllableClock is the part of ports.TravelClock the sandbox drives.
+// A plain SystemClock does not satisfy it, which keeps the clock use cases
+// unavailable outside sandboxes.
+type ControllableClock interface {
+	ports.Clock
+	Advance(d time.Duration) time.Time
+	Set(t time.Time)
+	Reset()
+	Offset() (offset time.Duration, frozen bool)
+}
+
+// Services groups application services. Clock is the platform clock; the
+// use cases report themselves unavailable unless it is controllable.
+type Services struct {
+	Clock            ports.Clock
+	ActionGatekeeper *actiongate.ActionGatekeeper
+}
+
+// NewUseCases wires every sandbox use case from shared dependencies.
+func NewUseCases(services Services) *UseCases {
+	return &UseCases{
+		GetClock:    NewGetClockUseCase(services),
+		AdjustClock: NewAdjustClockUseCase(services),
+	}
+}
+
+// controllable returns the clock when it can be moved, or nil.
+func (s Services) controllable() ControllableClock {
+	c, _ := s.Clock.(ControllableClock)
+	return c
+}
@@ -36,6 +36,8 @@ import (
 	"github.com/erniealice/espyna-golang/internal/application/usecases/service/dashboard"
 	"github.com/erniealice/espyna-golang/internal/application/usecases/service/performance"
 	"github.com/erniealice/espyna-golang/internal/application/usecases/service/reporting"
+	"github.com/erniealice/espyna-golang/internal/application/usecases/service/resolve"
+	"github.com/erniealice/espyna-golang/internal/application/usecases/service/sandbox"
 	"github.com/erniealice/espyna-golang/internal/application/usecases/service/security"
 	servicetax "github.com/erniealice/espyna-golang/internal/application/usecases/service/tax"
 )
@@ -60,11 +62,21 @@ type ServiceUseCases struct {
 	// wrapped from the shared package. Nil-safe: when unset, amortization
 	// computations degrade to nil.
 	Amortization *amortization.UseCases
+
+	// Global ID resolution (/api/resolve/{id}) — identifies which entity
+	// a bare ID belongs to for support tooling. Nil-safe: when unset, the
+	// endpoint responds 503.
+	Resolve *resolve.UseCases
+
+	// Sandbox clock control (/api/sandbox/clock) — moves the business clock
+	// when LEAPFOR_SANDBOX_TIME_TRAVEL is set. Nil-safe: when unset, the
+	// endpoint responds 503.
+	Sandbox *sandbox.UseCases
 }
 
 // NewServiceUseCases wires every service-driven sub-aggregate. All typed
-// fields (Audit, Security, Auth, Dashboard, Reporting, Tax, Amortization)
-// are passed explicitly.
+// fields (Audit, Security, Auth, Dashboard, Reporting, Tax, Amortization,
+// Resolve, Sandbox) are passed explicitly.
 //
 // Sub-aggregates may be nil when the relevant infrastructure provider is
 // unregistered.
@@ -82,6 +94,8 @@ func NewServiceUseCases(
 	perf *performance.UseCase,
 	tax *servicetax.UseCases,
 	amort *amortization.UseCases,
+	res *resolve.UseCases,
+	sbx *sandbox.UseCases,
 ) *ServiceUseCases {
 	return &ServiceUseCases{
 		Audit:        audit,
@@ -92,5 +106,7 @@ func NewServiceUseCases(
 		Performance:  perf,
 		Tax:          tax,
 		Amortization: amort,
+		Resolve:      res,
+		Sandbox:      sbx,
 	}
 }
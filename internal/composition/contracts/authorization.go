@@ -0,0 +1,352 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// ============================================================================
+// Route Authorization
+// ============================================================================
+
+// DefaultAuthorizationCacheTTL is how long a verdict is reused when the
+// config sets no CacheTTL.
+const DefaultAuthorizationCacheTTL = time.Minute
+
+// maxAuthorizationCacheEntries bounds the verdict cache; when full, expired
+// verdicts are dropped and, failing that, the cache starts over.
+const maxAuthorizationCacheEntries = 10000
+
+// DefaultAuthorizationPublicPaths are never checked: the health probe, the
+// Prometheus metrics and the OpenAPI document.
+var DefaultAuthorizationPublicPaths = []string{"/health", "/metrics", "/api/openapi.json"}
+
+// PermissionChecker answers whether a user holds a permission in a
+// workspace, through the roles the user has there. The application's
+// ports.Authorizer satisfies it.
+type PermissionChecker interface {
+	HasPermissionInWorkspace(ctx context.Context, userID, workspaceID, permission string) (bool, error)
+	IsEnabled() bool
+}
+
+// AuthorizationRequest identifies a request for RouteAuthorizer.Authorize.
+// Server adapters fill it from the request identity set by the
+// authentication middleware; the workspace falls back to the
+// WorkspaceHeader.
+type AuthorizationRequest struct {
+	Method      string
+	Path        string
+	UserID      string
+	WorkspaceID string
+}
+
+// AuthorizationDecision is the outcome of RouteAuthorizer.Authorize.
+type AuthorizationDecision struct {
+	Allowed bool
+	// Status is the HTTP status to refuse the request with: 401 without a
+	// user, 400 without a workspace, 403 without the permission and 500
+	// when the permissions could not be read.
+	Status int
+	// Permission is the permission code checked, empty when none applied.
+	Permission string
+	Reason     string
+}
+
+// RoutePermission derives the permission code a route needs from its
+// metadata: the resource as an entity ("role-permission" is
+// role_permission) and the operation as an action. Create, read, update,
+// delete and list map to themselves, page data and exports to read or
+// list, and other operations to read for GET routes and update otherwise.
+// It returns "" for routes without a resource.
+func RoutePermission(route *Route) string {
+	if route == nil || route.Metadata.Resource == "" {
+		return ""
+	}
+	entity := strings.ReplaceAll(route.Metadata.Resource, "-", "_")
+	var action string
+	switch route.Metadata.Operation {
+	case entityid.ActionCreate, entityid.ActionUpdate, entityid.ActionDelete:
+		action = route.Metadata.Operation
+	case entityid.ActionRead, "get-item-page-data":
+		action = entityid.ActionRead
+	case entityid.ActionList, "get-list-page-data", "export":
+		action = entityid.ActionList
+	default:
+		if route.Method == http.MethodGet {
+			action = entityid.ActionRead
+		} else {
+			action = entityid.ActionUpdate
+		}
+	}
+	return entityid.EntityPermission(entity, action)
+}
+
+// RouteAuthorizer enforces the permission each route maps to. Verdicts are
+// cached per user, workspace and permission for the configured TTL, so a
+// revoked role takes up to that long to apply; Invalidate drops them
+// early. A nil RouteAuthorizer allows everything.
+type RouteAuthorizer struct {
+	cfg       AuthorizationConfig
+	checker   PermissionChecker
+	routes    map[string]string // method + " " + path -> permission
+	overrides pathPatterns
+	public    pathPatterns
+	now       func() time.Time
+
+	mu    sync.Mutex
+	cache map[authorizationKey]authorizationVerdict
+}
+
+type authorizationKey struct {
+	userID, workspaceID, permission string
+}
+
+type authorizationVerdict struct {
+	allowed   bool
+	expiresAt time.Time
+}
+
+// NewRouteAuthorizer creates the authorizer for the installed routes,
+// checking permissions with checker. It returns nil when route
+// authorization is disabled.
+func NewRouteAuthorizer(cfg AuthorizationConfig, routes []*Route, checker PermissionChecker) (*RouteAuthorizer, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if checker == nil {
+		return nil, fmt.Errorf("route authorization needs an authorizer")
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultAuthorizationCacheTTL
+	}
+	a := &RouteAuthorizer{
+		cfg:     cfg,
+		checker: checker,
+		routes:  make(map[string]string),
+		now:     time.Now,
+		cache:   make(map[authorizationKey]authorizationVerdict),
+	}
+	byName := make(map[string]string)
+	paths := make(map[string]string)
+	for key, permission := range cfg.Permissions {
+		if strings.HasPrefix(key, "/") {
+			paths[key] = permission
+		} else {
+			byName[key] = permission
+		}
+	}
+	for _, route := range routes {
+		if route == nil {
+			continue
+		}
+		permission, ok := byName[route.Metadata.Name]
+		if !ok {
+			permission = RoutePermission(route)
+		}
+		if permission != "" {
+			a.routes[route.Method+" "+route.Path] = permission
+		}
+	}
+	a.overrides = newPathPatterns(paths)
+	public := make(map[string]string)
+	for _, path := range append(append([]string(nil), DefaultAuthorizationPublicPaths...), cfg.PublicPaths...) {
+		public[path] = path
+	}
+	a.public = newPathPatterns(public)
+	return a, nil
+}
+
+// Authorize decides whether the request may run. Preflight requests and
+// public paths always may; other requests need the permission their path
+// override or route maps to, in the request's workspace. While the checker
+// is disabled (a mock or dev authorizer) every mapped request may run.
+func (a *RouteAuthorizer) Authorize(ctx context.Context, req AuthorizationRequest) AuthorizationDecision {
+	if a == nil || req.Method == http.MethodOptions {
+		return AuthorizationDecision{Allowed: true}
+	}
+	if _, ok := a.public.match(req.Path); ok {
+		return AuthorizationDecision{Allowed: true}
+	}
+	permission, ok := a.overrides.match(req.Path)
+	if !ok {
+		permission = a.routes[req.Method+" "+req.Path]
+	}
+	if permission == "" {
+		if a.cfg.AllowUnmapped {
+			return AuthorizationDecision{Allowed: true}
+		}
+		return AuthorizationDecision{Status: http.StatusForbidden, Reason: "no permission is mapped to this route"}
+	}
+	if !a.checker.IsEnabled() {
+		return AuthorizationDecision{Allowed: true, Permission: permission}
+	}
+	if req.UserID == "" {
+		return AuthorizationDecision{Status: http.StatusUnauthorized, Permission: permission, Reason: "authentication required"}
+	}
+	if req.WorkspaceID == "" {
+		return AuthorizationDecision{Status: http.StatusBadRequest, Permission: permission, Reason: "workspace required"}
+	}
+
+	key := authorizationKey{userID: req.UserID, workspaceID: req.WorkspaceID, permission: permission}
+	allowed, cached := a.cached(key)
+	if !cached {
+		var err error
+		allowed, err = a.checker.HasPermissionInWorkspace(ctx, req.UserID, req.WorkspaceID, permission)
+		if err != nil {
+			log.Printf("AUTHZ_ROUTE_ERROR user=%s workspace=%s permission=%s: %v", req.UserID, req.WorkspaceID, permission, err)
+			return AuthorizationDecision{Status: http.StatusInternalServerError, Permission: permission, Reason: "permissions unavailable"}
+		}
+		a.store(key, allowed)
+	}
+	if !allowed {
+		log.Printf("AUTHZ_ROUTE_DENY user=%s workspace=%s permission=%s %s %s", req.UserID, req.WorkspaceID, permission, req.Method, req.Path)
+		return AuthorizationDecision{Status: http.StatusForbidden, Permission: permission, Reason: "missing permission " + permission}
+	}
+	return AuthorizationDecision{Allowed: true, Permission: permission}
+}
+
+// Invalidate drops the cached verdicts of a user in a workspace, after
+// their roles or a role's permissions change. An empty userID or
+// workspaceID matches every user or workspace.
+func (a *RouteAuthorizer) Invalidate(userID, workspaceID string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for key := range a.cache {
+		if (userID == "" || key.userID == userID) && (workspaceID == "" || key.workspaceID == workspaceID) {
+			delete(a.cache, key)
+		}
+	}
+}
+
+func (a *RouteAuthorizer) cached(key authorizationKey) (allowed, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	verdict, ok := a.cache[key]
+	if !ok || !a.now().Before(verdict.expiresAt) {
+		return false, false
+	}
+	return verdict.allowed, true
+}
+
+func (a *RouteAuthorizer) store(key authorizationKey, allowed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if len(a.cache) >= maxAuthorizationCacheEntries {
+		for k, verdict := range a.cache {
+			if !now.Before(verdict.expiresAt) {
+				delete(a.cache, k)
+			}
+		}
+		if len(a.cache) >= maxAuthorizationCacheEntries {
+			a.cache = make(map[authorizationKey]authorizationVerdict)
+		}
+	}
+	a.cache[key] = authorizationVerdict{allowed: allowed, expiresAt: now.Add(a.cfg.CacheTTL)}
+}
+
+// pathPatterns matches request paths against exact paths and prefixes
+// ending in *, the longest prefix winning.
+type pathPatterns struct {
+	exact    map[string]string
+	prefixes []string
+	values   map[string]string // prefix -> value
+}
+
+func newPathPatterns(patterns map[string]string) pathPatterns {
+	p := pathPatterns{exact: make(map[string]string), values: make(map[string]string)}
+	for key, value := range patterns {
+		if prefix, ok := strings.CutSuffix(key, "*"); ok {
+			p.prefixes = append(p.prefixes, prefix)
+			p.values[prefix] = value
+		} else {
+			p.exact[key] = value
+		}
+	}
+	sort.SliceStable(p.prefixes, func(i, j int) bool {
+		return len(p.prefixes[i]) > len(p.prefixes[j])
+	})
+	return p
+}
+
+func (p pathPatterns) match(path string) (string, bool) {
+	if value, ok := p.exact[path]; ok {
+		return value, true
+	}
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return p.values[prefix], true
+		}
+	}
+	return "", false
+}
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// LoadAuthorizationConfig overlays cfg with the route authorization
+// settings from the environment:
+//
+//	CONFIG_AUTHORIZATION_ENABLED=true
+//	CONFIG_AUTHORIZATION_CACHE_TTL=1m
+//	CONFIG_AUTHORIZATION_PUBLIC_PATHS=/auth/*,/api/client/history
+//	CONFIG_AUTHORIZATION_PERMISSIONS=entity.client.approve=client:manage,/api/reports/*=report:read
+//	CONFIG_AUTHORIZATION_ALLOW_UNMAPPED=false
+//
+// Public paths are added to the configured ones and permission entries are
+// merged.
+func LoadAuthorizationConfig(cfg *AuthorizationConfig, getenv func(string) string) error {
+	for name, target := range map[string]*bool{
+		"CONFIG_AUTHORIZATION_ENABLED":        &cfg.Enabled,
+		"CONFIG_AUTHORIZATION_ALLOW_UNMAPPED": &cfg.AllowUnmapped,
+	} {
+		if raw := strings.TrimSpace(getenv(name)); raw != "" {
+			v, err := strconv.ParseBool(raw)
+			if err != nil {
+				return fmt.Errorf("%s=%q is not a boolean", name, raw)
+			}
+			*target = v
+		}
+	}
+	if raw := strings.TrimSpace(getenv("CONFIG_AUTHORIZATION_CACHE_TTL")); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("CONFIG_AUTHORIZATION_CACHE_TTL=%q is not a positive duration", raw)
+		}
+		cfg.CacheTTL = d
+	}
+	for _, path := range strings.Split(getenv("CONFIG_AUTHORIZATION_PUBLIC_PATHS"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			cfg.PublicPaths = append(cfg.PublicPaths, path)
+		}
+	}
+	for _, entry := range strings.Split(getenv("CONFIG_AUTHORIZATION_PERMISSIONS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, permission, ok := strings.Cut(entry, "=")
+		key, permission = strings.TrimSpace(key), strings.TrimSpace(permission)
+		if !ok || key == "" || permission == "" {
+			return fmt.Errorf("CONFIG_AUTHORIZATION_PERMISSIONS: entry %q is not route=permission", entry)
+		}
+		if cfg.Permissions == nil {
+			cfg.Permissions = make(map[string]string)
+		}
+		cfg.Permissions[key] = permission
+	}
+	return nil
+}
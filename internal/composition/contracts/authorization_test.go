@@ -0,0 +1,164 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// grantChecker grants the permissions listed per user and workspace,
+// counting lookups.
+type grantChecker struct {
+	grants  map[string]bool // user/workspace/permission
+	enabled bool
+	err     error
+	calls   int
+}
+
+func (c *grantChecker) HasPermissionInWorkspace(_ context.Context, userID, workspaceID, permission string) (bool, error) {
+	c.calls++
+	return c.grants[userID+"/"+workspaceID+"/"+permission], c.err
+}
+
+func (c *grantChecker) IsEnabled() bool { return c.enabled }
+
+func testRoutes() []*Route {
+	route := func(method, path, resource, operation string) *Route {
+		return &Route{Method: method, Path: path, Metadata: RouteMetadata{
+			Name: "entity." + resource + "." + operation, Domain: "entity", Resource: resource, Operation: operation,
+		}}
+	}
+	return []*Route{
+		route(http.MethodPost, "/api/entity/client/create", "client", "create"),
+		route(http.MethodPost, "/api/entity/client/get-list-page-data", "client", "get-list-page-data"),
+		route(http.MethodPost, "/api/entity/role-permission/delete", "role-permission", "delete"),
+		route(http.MethodPost, "/api/entity/client/approve", "client", "approve"),
+	}
+}
+
+func TestRoutePermission(t *testing.T) {
+	want := []string{"client:create", "client:list", "role_permission:delete", "client:update"}
+	for i, route := range testRoutes() {
+		if got := RoutePermission(route); got != want[i] {
+			t.Errorf("%s = %q, want %q", route.Path, got, want[i])
+		}
+	}
+	if got := RoutePermission(&Route{Method: http.MethodGet, Metadata: RouteMetadata{Resource: "invoice", Operation: "download"}}); got != "invoice:read" {
+		t.Errorf("GET custom operation = %q", got)
+	}
+}
+
+func TestRouteAuthorizer_Enforces(t *testing.T) {
+	checker := &grantChecker{enabled: true, grants: map[string]bool{"u-1/ws-1/client:create": true}}
+	a, err := NewRouteAuthorizer(AuthorizationConfig{
+		Enabled:     true,
+		PublicPaths: []string{"/auth/*"},
+		Permissions: map[string]string{"entity.client.approve": "client:manage", "/api/reports/*": "report:read"},
+	}, testRoutes(), checker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	check := func(method, path, user, workspace string) AuthorizationDecision {
+		return a.Authorize(ctx, AuthorizationRequest{Method: method, Path: path, UserID: user, WorkspaceID: workspace})
+	}
+
+	if d := check(http.MethodPost, "/api/entity/client/create", "u-1", "ws-1"); !d.Allowed || d.Permission != "client:create" {
+		t.Errorf("granted = %+v", d)
+	}
+	if d := check(http.MethodPost, "/api/entity/client/create", "u-1", "ws-2"); d.Allowed || d.Status != http.StatusForbidden {
+		t.Errorf("other workspace = %+v", d)
+	}
+	if d := check(http.MethodPost, "/api/entity/client/approve", "u-1", "ws-1"); d.Allowed || d.Permission != "client:manage" {
+		t.Errorf("route name override = %+v", d)
+	}
+	if d := check(http.MethodGet, "/api/reports/sales", "u-1", "ws-1"); d.Permission != "report:read" {
+		t.Errorf("path override = %+v", d)
+	}
+	if d := check(http.MethodPost, "/api/entity/client/create", "", "ws-1"); d.Status != http.StatusUnauthorized {
+		t.Errorf("anonymous = %+v", d)
+	}
+	if d := check(http.MethodPost, "/api/entity/client/create", "u-1", ""); d.Status != http.StatusBadRequest {
+		t.Errorf("no workspace = %+v", d)
+	}
+	if d := check(http.MethodPost, "/api/custom/thing", "u-1", "ws-1"); d.Allowed || d.Status != http.StatusForbidden {
+		t.Errorf("unmapped = %+v, want denied by default", d)
+	}
+	for _, path := range []string{"/auth/login", "/health"} {
+		if d := check(http.MethodPost, path, "", ""); !d.Allowed {
+			t.Errorf("public %s = %+v", path, d)
+		}
+	}
+	if d := check(http.MethodOptions, "/api/entity/client/create", "", ""); !d.Allowed {
+		t.Errorf("preflight = %+v", d)
+	}
+}
+
+func TestRouteAuthorizer_CachesVerdicts(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	checker := &grantChecker{enabled: true, grants: map[string]bool{"u-1/ws-1/client:create": true}}
+	a, _ := NewRouteAuthorizer(AuthorizationConfig{Enabled: true, CacheTTL: time.Minute}, testRoutes(), checker)
+	a.now = func() time.Time { return now }
+	req := AuthorizationRequest{Method: http.MethodPost, Path: "/api/entity/client/create", UserID: "u-1", WorkspaceID: "ws-1"}
+	ctx := context.Background()
+
+	a.Authorize(ctx, req)
+	a.Authorize(ctx, req)
+	if checker.calls != 1 {
+		t.Fatalf("lookups = %d, want the verdict cached", checker.calls)
+	}
+	delete(checker.grants, "u-1/ws-1/client:create")
+	if d := a.Authorize(ctx, req); !d.Allowed {
+		t.Fatalf("within the TTL = %+v, want the cached grant", d)
+	}
+	a.Invalidate("u-1", "")
+	if d := a.Authorize(ctx, req); d.Allowed || checker.calls != 2 {
+		t.Fatalf("after invalidation = %+v (%d lookups)", d, checker.calls)
+	}
+	now = now.Add(2 * time.Minute)
+	a.Authorize(ctx, req)
+	if checker.calls != 3 {
+		t.Fatalf("lookups after expiry = %d", checker.calls)
+	}
+
+	checker.err = errors.New("database down")
+	a.Invalidate("", "")
+	if d := a.Authorize(ctx, req); d.Allowed || d.Status != http.StatusInternalServerError {
+		t.Fatalf("lookup error = %+v, want refused", d)
+	}
+}
+
+func TestRouteAuthorizer_DisabledChecker(t *testing.T) {
+	a, _ := NewRouteAuthorizer(AuthorizationConfig{Enabled: true}, testRoutes(), &grantChecker{})
+	if d := a.Authorize(context.Background(), AuthorizationRequest{Method: http.MethodPost, Path: "/api/entity/client/create"}); !d.Allowed {
+		t.Errorf("disabled checker = %+v", d)
+	}
+	if a, _ := NewRouteAuthorizer(AuthorizationConfig{}, testRoutes(), &grantChecker{}); a != nil {
+		t.Error("disabled config built an authorizer")
+	}
+	if _, err := NewRouteAuthorizer(AuthorizationConfig{Enabled: true}, nil, nil); err == nil {
+		t.Error("nil checker accepted")
+	}
+}
+
+func TestLoadAuthorizationConfig(t *testing.T) {
+	env := map[string]string{
+		"CONFIG_AUTHORIZATION_ENABLED":      "true",
+		"CONFIG_AUTHORIZATION_CACHE_TTL":    "30s",
+		"CONFIG_AUTHORIZATION_PUBLIC_PATHS": "/auth/*, /status",
+		"CONFIG_AUTHORIZATION_PERMISSIONS":  "entity.client.approve=client:manage,/api/reports/*=report:read",
+	}
+	var cfg AuthorizationConfig
+	if err := LoadAuthorizationConfig(&cfg, func(k string) string { return env[k] }); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Enabled || cfg.CacheTTL != 30*time.Second || len(cfg.PublicPaths) != 2 || cfg.Permissions["/api/reports/*"] != "report:read" {
+		t.Errorf("config = %+v", cfg)
+	}
+	env["CONFIG_AUTHORIZATION_PERMISSIONS"] = "entity.client.approve"
+	if err := LoadAuthorizationConfig(&cfg, func(k string) string { return env[k] }); err == nil {
+		t.Error("malformed permission entry accepted")
+	}
+}
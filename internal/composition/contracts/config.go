@@ -19,6 +19,10 @@ type Config struct {
 	RateLimit   RateLimitConfig `json:"rateLimit" yaml:"rateLimit"`
 	Timeout     time.Duration   `json:"timeout" yaml:"timeout"`
 
+	Idempotency IdempotencyConfig `json:"idempotency" yaml:"idempotency"`
+
+	Authorization AuthorizationConfig `json:"authorization" yaml:"authorization"`
+
 	// Feature flags
 	EnableMetrics     bool `json:"enableMetrics" yaml:"enableMetrics"`
 	EnableHealthCheck bool `json:"enableHealthCheck" yaml:"enableHealthCheck"`
@@ -51,24 +55,96 @@ type CORSConfig struct {
 // Rate Limiting Configuration
 // ============================================================================
 
-// RateLimitConfig represents rate limiting configuration
+// RateLimitConfig represents rate limiting configuration. Every limit is a
+// token bucket; a limit with zero RequestsPerMinute is off.
 type RateLimitConfig struct {
 	Enabled bool `json:"enabled" yaml:"enabled"`
 
-	// Global rate limit
+	// Global rate limit, shared by all requests
 	RequestsPerMinute int `json:"requestsPerMinute" yaml:"requestsPerMinute"`
 	BurstSize         int `json:"burstSize" yaml:"burstSize"`
 
-	// Per-endpoint rate limits
+	// Per-client rate limits
+	PerIP        EndpointRateLimit `json:"perIP" yaml:"perIP"`
+	PerWorkspace EndpointRateLimit `json:"perWorkspace" yaml:"perWorkspace"`
+
+	// Workspace quotas replacing PerWorkspace for single workspaces, by
+	// workspace ID
+	Workspaces map[string]EndpointRateLimit `json:"workspaces" yaml:"workspaces"`
+
+	// Per-endpoint rate limits, applied per client IP. Keys are request
+	// paths; a trailing * matches a prefix and the longest match wins.
 	Endpoints map[string]EndpointRateLimit `json:"endpoints" yaml:"endpoints"`
+
+	// Backend keeps the counters: "memory" (per instance, the default) or
+	// "redis" (shared by all instances through RedisURL)
+	Backend  string `json:"backend" yaml:"backend"`
+	RedisURL string `json:"redisURL" yaml:"redisURL"`
 }
 
-// EndpointRateLimit represents rate limit for specific endpoints
+// EndpointRateLimit represents a single rate limit: RequestsPerMinute
+// refill the bucket, which holds at most BurstSize requests (default
+// RequestsPerMinute).
 type EndpointRateLimit struct {
 	RequestsPerMinute int `json:"requestsPerMinute" yaml:"requestsPerMinute"`
 	BurstSize         int `json:"burstSize" yaml:"burstSize"`
 }
 
+// ============================================================================
+// Idempotency Configuration
+// ============================================================================
+
+// IdempotencyConfig represents idempotency key configuration for POST
+// requests. Zero durations and sizes take the Default* values.
+type IdempotencyConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// TTL is how long a response is replayed for its key
+	TTL time.Duration `json:"ttl" yaml:"ttl"`
+
+	// LockTimeout bounds how long a running request holds its key; after
+	// it a retry runs again (the first instance is assumed gone)
+	LockTimeout time.Duration `json:"lockTimeout" yaml:"lockTimeout"`
+
+	// MaxBodyBytes is the largest request or response body tracked
+	MaxBodyBytes int `json:"maxBodyBytes" yaml:"maxBodyBytes"`
+
+	// Backend keeps the records: "memory" (per instance, the default) or
+	// "postgres" (shared by all instances, in Table)
+	Backend string `json:"backend" yaml:"backend"`
+	Table   string `json:"table" yaml:"table"`
+}
+
+// ============================================================================
+// Authorization Configuration
+// ============================================================================
+
+// AuthorizationConfig represents route-level RBAC configuration. When
+// enabled every request needs the permission its route maps to, in the
+// caller's workspace; requests no permission maps to are refused unless
+// AllowUnmapped is set.
+type AuthorizationConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// CacheTTL is how long a user's verdict for a permission in a
+	// workspace is reused (default DefaultAuthorizationCacheTTL)
+	CacheTTL time.Duration `json:"cacheTTL" yaml:"cacheTTL"`
+
+	// PublicPaths are not checked: health probes, and handlers that check
+	// permissions themselves. A trailing * matches a prefix.
+	PublicPaths []string `json:"publicPaths" yaml:"publicPaths"`
+
+	// Permissions override the permission a route maps to, or map one the
+	// route table does not know. Keys are route names (entity.client.create)
+	// or request paths, where a trailing * matches a prefix and the longest
+	// match wins.
+	Permissions map[string]string `json:"permissions" yaml:"permissions"`
+
+	// AllowUnmapped lets requests no permission maps to through instead of
+	// refusing them
+	AllowUnmapped bool `json:"allowUnmapped" yaml:"allowUnmapped"`
+}
+
 // ============================================================================
 // Domain Configuration
 // ============================================================================
@@ -0,0 +1,460 @@
+package contracts
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ============================================================================
+// List Exports
+// ============================================================================
+
+// Every get-list-page-data route has an export route next to it:
+//
+//	POST /api/entity/client/export?format=xlsx&columns=id,name,client_type
+//
+// The body is the list request (search, filters, sort); pagination is
+// ignored and every matching record is written, page by page as it is
+// fetched, as a CSV or XLSX download. Columns are field names of the
+// record, dotted for nested messages ("user.email_address"); without
+// columns every scalar field is exported. Server adapters recognise export
+// routes by their RecordExporter handler.
+const (
+	ExportFormatParam  = "format"
+	ExportColumnsParam = "columns"
+)
+
+// ExportFormat is the file format of an export.
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportXLSX ExportFormat = "xlsx"
+)
+
+// ContentType returns the MIME type of the format.
+func (f ExportFormat) ContentType() string {
+	if f == ExportCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+var (
+	// ErrInvalidExport is wrapped by errors in the export format or columns.
+	ErrInvalidExport = errors.New("invalid export request")
+	// ErrExportOnly is returned when an export route is executed as a
+	// regular route by an adapter that cannot write files.
+	ErrExportOnly = errors.New("export routes must be served by an adapter that writes files")
+)
+
+// ExportOptions selects what an export writes.
+type ExportOptions struct {
+	Format ExportFormat
+	// Columns are dotted field paths; empty exports every scalar field.
+	Columns []string
+}
+
+// ParseExportOptions reads the format and columns query parameters. The
+// format defaults to xlsx.
+func ParseExportOptions(format, columns string) (ExportOptions, error) {
+	opts := ExportOptions{Format: ExportFormat(strings.ToLower(strings.TrimSpace(format)))}
+	switch opts.Format {
+	case "":
+		opts.Format = ExportXLSX
+	case ExportCSV, ExportXLSX:
+	default:
+		return opts, fmt.Errorf("%w: format %q, want csv or xlsx", ErrInvalidExport, format)
+	}
+	for _, column := range strings.Split(columns, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			opts.Columns = append(opts.Columns, column)
+		}
+	}
+	return opts, nil
+}
+
+// RecordExporter is implemented by export route handlers. StreamRecords
+// emits the records to write; RecordDescriptor describes them when known
+// before the first record (nil otherwise).
+type RecordExporter interface {
+	RecordStreamer
+	RecordDescriptor() protoreflect.MessageDescriptor
+	// ExportName names the download and its sheet, e.g. "client".
+	ExportName() string
+}
+
+// exportSource is a list route handler an export can page through.
+type exportSource interface {
+	ProtobufParser
+	RecordStreamer
+	MessageTypesDescriber
+}
+
+// ExportHandler serves an export route over a list route's handler.
+type ExportHandler struct {
+	list   exportSource
+	name   string
+	record protoreflect.MessageDescriptor
+}
+
+var _ RecordExporter = (*ExportHandler)(nil)
+
+// NewExportHandler creates the export route handler for a list route, named
+// name in download filenames. ok is false when list cannot be streamed.
+func NewExportHandler(list UseCaseHandler, name string) (_ *ExportHandler, ok bool) {
+	source, ok := list.(exportSource)
+	if !ok {
+		return nil, false
+	}
+	request, response := source.MessageTypes()
+	if request == nil || request.Fields().ByName("pagination") == nil {
+		return nil, false
+	}
+	h := &ExportHandler{list: source, name: name}
+	if response != nil {
+		field := recordsField(response)
+		if field == nil {
+			return nil, false
+		}
+		h.record = field.Message()
+	}
+	return h, true
+}
+
+// Execute reports ErrExportOnly: exports are written by server adapters
+// through StreamRecords.
+func (h *ExportHandler) Execute(context.Context, proto.Message) (proto.Message, error) {
+	return nil, ErrExportOnly
+}
+
+// ParseRequestFromJSON parses the list request.
+func (h *ExportHandler) ParseRequestFromJSON(jsonData []byte) (proto.Message, error) {
+	return h.list.ParseRequestFromJSON(jsonData)
+}
+
+// StreamRecords pages through the list route.
+func (h *ExportHandler) StreamRecords(ctx context.Context, req proto.Message, emit func(record proto.Message) error) error {
+	return h.list.StreamRecords(ctx, req, emit)
+}
+
+// MessageTypes describes the list request; the response is a file.
+func (h *ExportHandler) MessageTypes() (request, response protoreflect.MessageDescriptor) {
+	request, _ = h.list.MessageTypes()
+	return request, nil
+}
+
+// RecordDescriptor describes the exported records, or is nil when the list
+// response type is only known from an instance.
+func (h *ExportHandler) RecordDescriptor() protoreflect.MessageDescriptor {
+	return h.record
+}
+
+// ExportName returns the entity name the handler was created with.
+func (h *ExportHandler) ExportName() string {
+	return h.name
+}
+
+// ============================================================================
+// Export Writer
+// ============================================================================
+
+// exportFlushEvery is how many rows are written between flushes.
+const exportFlushEvery = 100
+
+// column is one resolved export column: the field path from the record.
+type column struct {
+	name string
+	path []protoreflect.FieldDescriptor
+}
+
+// rowWriter writes one file format.
+type rowWriter interface {
+	writeRow(cells []any, header bool) error
+	flush() error
+	close() error
+}
+
+// ExportWriter writes streamed records as rows of a CSV or XLSX file, with
+// a header row of column names. Nothing is written until the first record
+// (or Close), so until Started the caller can still answer with an error.
+type ExportWriter struct {
+	w       io.Writer
+	opts    ExportOptions
+	flushFn func()
+	name    string
+	columns []column
+	rows    rowWriter
+	count   int
+}
+
+// NewExportWriter writes exporter's records to w. When the exporter
+// describes its records, unknown columns are rejected here, before anything
+// is written; otherwise they are resolved against the first record. flush,
+// when non-nil, pushes buffered output to the client and is called
+// periodically.
+func NewExportWriter(w io.Writer, exporter RecordExporter, opts ExportOptions, flush func()) (*ExportWriter, error) {
+	ew := &ExportWriter{w: w, opts: opts, flushFn: flush, name: exporter.ExportName()}
+	if record := exporter.RecordDescriptor(); record != nil {
+		columns, err := resolveColumns(record, opts.Columns)
+		if err != nil {
+			return nil, err
+		}
+		ew.columns = columns
+	}
+	return ew, nil
+}
+
+// Filename names the download after the entity and t, e.g.
+// "client-20261016-1504.xlsx".
+func (e *ExportWriter) Filename(t time.Time) string {
+	return fmt.Sprintf("%s-%s.%s", e.name, t.UTC().Format("20060102-1504"), e.opts.Format)
+}
+
+// Started reports whether any output has been written.
+func (e *ExportWriter) Started() bool {
+	return e.rows != nil
+}
+
+// Count returns the records written.
+func (e *ExportWriter) Count() int {
+	return e.count
+}
+
+// Write writes one record as a row. It is the emit function for
+// StreamRecords.
+func (e *ExportWriter) Write(record proto.Message) error {
+	m := record.ProtoReflect()
+	if e.columns == nil {
+		columns, err := resolveColumns(m.Descriptor(), e.opts.Columns)
+		if err != nil {
+			return err
+		}
+		e.columns = columns
+	}
+	if err := e.start(); err != nil {
+		return err
+	}
+	cells := make([]any, len(e.columns))
+	for i, c := range e.columns {
+		cells[i] = cellValue(m, c.path)
+	}
+	if err := e.rows.writeRow(cells, false); err != nil {
+		return err
+	}
+	e.count++
+	if e.count%exportFlushEvery == 0 {
+		if err := e.rows.flush(); err != nil {
+			return err
+		}
+		if e.flushFn != nil {
+			e.flushFn()
+		}
+	}
+	return nil
+}
+
+// Close finishes the file. An export that failed after it started is left
+// unfinished instead, so the download is visibly truncated (an XLSX will
+// not open) rather than passing for a complete file.
+func (e *ExportWriter) Close(streamErr error) error {
+	if streamErr != nil && e.Started() {
+		return e.rows.flush()
+	}
+	if err := e.start(); err != nil {
+		return err
+	}
+	if err := e.rows.close(); err != nil {
+		return err
+	}
+	if e.flushFn != nil {
+		e.flushFn()
+	}
+	return nil
+}
+
+// start writes the file header and the header row.
+func (e *ExportWriter) start() error {
+	if e.rows != nil {
+		return nil
+	}
+	if e.opts.Format == ExportCSV {
+		e.rows = &csvRows{w: csv.NewWriter(e.w)}
+	} else {
+		rows, err := newXLSXRows(e.w, e.name)
+		if err != nil {
+			return err
+		}
+		e.rows = rows
+	}
+	// Without records or a descriptor the columns are unresolved; the
+	// header still lists the requested ones.
+	var header []any
+	for _, c := range e.columns {
+		header = append(header, c.name)
+	}
+	if e.columns == nil {
+		for _, name := range e.opts.Columns {
+			header = append(header, name)
+		}
+	}
+	return e.rows.writeRow(header, true)
+}
+
+// resolveColumns maps column names to field paths of md. Without names it
+// returns every singular scalar or enum field, in field order.
+func resolveColumns(md protoreflect.MessageDescriptor, names []string) ([]column, error) {
+	if len(names) == 0 {
+		var columns []column
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if !fd.IsList() && !fd.IsMap() && fd.Message() == nil {
+				columns = append(columns, column{name: string(fd.Name()), path: []protoreflect.FieldDescriptor{fd}})
+			}
+		}
+		return columns, nil
+	}
+	columns := make([]column, 0, len(names))
+	for _, name := range names {
+		var path []protoreflect.FieldDescriptor
+		current := md
+		for _, part := range strings.Split(name, ".") {
+			if current == nil {
+				return nil, fmt.Errorf("%w: column %q: %s is not a message", ErrInvalidExport, name, path[len(path)-1].Name())
+			}
+			fd := current.Fields().ByName(protoreflect.Name(part))
+			if fd == nil {
+				fd = current.Fields().ByJSONName(part)
+			}
+			if fd == nil {
+				return nil, fmt.Errorf("%w: column %q: %s has no field %s", ErrInvalidExport, name, current.Name(), part)
+			}
+			path = append(path, fd)
+			current = nil
+			if !fd.IsList() && !fd.IsMap() {
+				current = fd.Message()
+			}
+		}
+		columns = append(columns, column{name: name, path: path})
+	}
+	return columns, nil
+}
+
+// cellValue reads a column of a record: a bool, float64 or string, or nil
+// when a message on the path is unset. Enums are their names; messages,
+// lists and maps are their JSON.
+func cellValue(m protoreflect.Message, path []protoreflect.FieldDescriptor) any {
+	for i, fd := range path {
+		if i < len(path)-1 {
+			if !m.Has(fd) {
+				return nil
+			}
+			m = m.Get(fd).Message()
+			continue
+		}
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() && !m.Has(fd) {
+			return nil
+		}
+		if fd.IsList() || fd.IsMap() || fd.Message() != nil {
+			return fieldJSON(m, fd)
+		}
+		return scalarValue(fd, m.Get(fd))
+	}
+	return nil
+}
+
+func scalarValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return v.Bool()
+	case protoreflect.StringKind:
+		return v.String()
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return float64(v.Uint())
+	default:
+		return float64(v.Int())
+	}
+}
+
+// fieldJSON encodes a message, list or map field as compact JSON. Lists
+// and maps are encoded through a copy of their parent holding only the
+// field.
+func fieldJSON(m protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	marshal := protojson.MarshalOptions{UseProtoNames: true}
+	var raw []byte
+	var err error
+	if !fd.IsList() && !fd.IsMap() {
+		raw, err = marshal.Marshal(m.Get(fd).Message().Interface())
+	} else {
+		if !m.Has(fd) {
+			return ""
+		}
+		holder := m.New()
+		holder.Set(fd, m.Get(fd))
+		raw, err = marshal.Marshal(holder.Interface())
+	}
+	var compact bytes.Buffer
+	if err != nil || json.Compact(&compact, raw) != nil {
+		return ""
+	}
+	text := compact.String()
+	if fd.IsList() || fd.IsMap() {
+		// text is {"<field>":<value>}; keep the value.
+		_, value, _ := strings.Cut(text, ":")
+		text = strings.TrimSuffix(value, "}")
+	}
+	return text
+}
+
+// csvRows writes CSV rows.
+type csvRows struct {
+	w *csv.Writer
+}
+
+func (r *csvRows) writeRow(cells []any, _ bool) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case nil:
+		case string:
+			record[i] = v
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return r.w.Write(record)
+}
+
+func (r *csvRows) flush() error {
+	r.w.Flush()
+	return r.w.Error()
+}
+
+func (r *csvRows) close() error {
+	return r.flush()
+}
@@ -0,0 +1,241 @@
+package contracts
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const exportFile = `
+name: "test/export.proto" package: "test" syntax: "proto3"
+enum_type {
+  name: "Status"
+  value { name: "STATUS_UNSPECIFIED" number: 0 }
+  value { name: "STATUS_ACTIVE" number: 1 }
+}
+message_type {
+  name: "Owner"
+  field { name: "email" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "email" }
+}
+message_type {
+  name: "Invoice"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" }
+  field { name: "total_amount" number: 2 type: TYPE_DOUBLE label: LABEL_OPTIONAL json_name: "totalAmount" }
+  field { name: "paid" number: 3 type: TYPE_BOOL label: LABEL_OPTIONAL json_name: "paid" }
+  field { name: "status" number: 4 type: TYPE_ENUM label: LABEL_OPTIONAL type_name: ".test.Status" json_name: "status" }
+  field { name: "owner" number: 5 type: TYPE_MESSAGE label: LABEL_OPTIONAL type_name: ".test.Owner" json_name: "owner" }
+  field { name: "tags" number: 6 type: TYPE_STRING label: LABEL_REPEATED json_name: "tags" }
+}
+`
+
+func exportRecord(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	var fdp descriptorpb.FileDescriptorProto
+	if err := prototext.Unmarshal([]byte(exportFile), &fdp); err != nil {
+		t.Fatalf("parse descriptor: %v", err)
+	}
+	fd, err := protodesc.NewFile(&fdp, nil)
+	if err != nil {
+		t.Fatalf("build descriptor: %v", err)
+	}
+	return fd.Messages().ByName("Invoice")
+}
+
+func invoice(md protoreflect.MessageDescriptor, id string, amount float64, email string, tags ...string) proto.Message {
+	m := dynamicpb.NewMessage(md)
+	fields := md.Fields()
+	m.Set(fields.ByName("id"), protoreflect.ValueOfString(id))
+	m.Set(fields.ByName("total_amount"), protoreflect.ValueOfFloat64(amount))
+	m.Set(fields.ByName("paid"), protoreflect.ValueOfBool(amount > 100))
+	m.Set(fields.ByName("status"), protoreflect.ValueOfEnum(1))
+	if email != "" {
+		owner := m.Mutable(fields.ByName("owner")).Message()
+		owner.Set(owner.Descriptor().Fields().ByName("email"), protoreflect.ValueOfString(email))
+	}
+	list := m.Mutable(fields.ByName("tags")).List()
+	for _, tag := range tags {
+		list.Append(protoreflect.ValueOfString(tag))
+	}
+	return m
+}
+
+// fakeExporter is a RecordExporter over fixed records.
+type fakeExporter struct {
+	record  protoreflect.MessageDescriptor
+	records []proto.Message
+}
+
+func (f *fakeExporter) StreamRecords(_ context.Context, _ proto.Message, emit func(proto.Message) error) error {
+	for _, r := range f.records {
+		if err := emit(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeExporter) RecordDescriptor() protoreflect.MessageDescriptor { return f.record }
+func (f *fakeExporter) ExportName() string                               { return "invoice" }
+
+func export(t *testing.T, exporter RecordExporter, opts ExportOptions) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	ew, err := NewExportWriter(&buf, exporter, opts, nil)
+	if err != nil {
+		t.Fatalf("NewExportWriter: %v", err)
+	}
+	err = exporter.StreamRecords(context.Background(), nil, ew.Write)
+	if err := ew.Close(err); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseExportOptions(t *testing.T) {
+	opts, err := ParseExportOptions("", " id, owner.email ,")
+	if err != nil || opts.Format != ExportXLSX || strings.Join(opts.Columns, "|") != "id|owner.email" {
+		t.Fatalf("opts = %+v, %v", opts, err)
+	}
+	if opts, _ := ParseExportOptions("CSV", ""); opts.Format != ExportCSV {
+		t.Fatalf("format = %q, want csv", opts.Format)
+	}
+	if _, err := ParseExportOptions("pdf", ""); !errors.Is(err, ErrInvalidExport) {
+		t.Fatalf("err = %v, want ErrInvalidExport", err)
+	}
+}
+
+func TestExportWriter_CSV(t *testing.T) {
+	md := exportRecord(t)
+	exporter := &fakeExporter{record: md, records: []proto.Message{
+		invoice(md, "inv-1", 250.5, "a@example.com", "q1", "vip"),
+		invoice(md, "inv-2", 40, ""),
+	}}
+
+	got := string(export(t, exporter, ExportOptions{Format: ExportCSV, Columns: []string{"id", "totalAmount", "status", "owner.email", "tags"}}))
+	want := "id,totalAmount,status,owner.email,tags\n" +
+		`inv-1,250.5,STATUS_ACTIVE,a@example.com,"[""q1"",""vip""]"` + "\n" +
+		"inv-2,40,STATUS_ACTIVE,,\n"
+	if got != want {
+		t.Fatalf("csv =\n%s\nwant\n%s", got, want)
+	}
+
+	// Without columns every singular scalar field is exported.
+	got = string(export(t, exporter, ExportOptions{Format: ExportCSV}))
+	if header, _, _ := strings.Cut(got, "\n"); header != "id,total_amount,paid,status" {
+		t.Fatalf("default header = %q", header)
+	}
+}
+
+func TestExportWriter_RejectsUnknownColumns(t *testing.T) {
+	md := exportRecord(t)
+	_, err := NewExportWriter(io.Discard, &fakeExporter{record: md}, ExportOptions{Columns: []string{"owner.phone"}}, nil)
+	if !errors.Is(err, ErrInvalidExport) {
+		t.Fatalf("err = %v, want ErrInvalidExport", err)
+	}
+
+	// Without a descriptor the columns are checked on the first record,
+	// before anything is written.
+	var buf bytes.Buffer
+	ew, err := NewExportWriter(&buf, &fakeExporter{}, ExportOptions{Columns: []string{"id.x"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ew.Write(invoice(md, "inv-1", 1, "")); !errors.Is(err, ErrInvalidExport) || ew.Started() || buf.Len() > 0 {
+		t.Fatalf("Write err = %v, started %v, %d bytes", err, ew.Started(), buf.Len())
+	}
+}
+
+func TestExportWriter_XLSX(t *testing.T) {
+	md := exportRecord(t)
+	exporter := &fakeExporter{record: md, records: []proto.Message{
+		invoice(md, "inv-<1>", 250.5, "a@example.com"),
+		invoice(md, "inv-2", 40, ""),
+	}}
+	raw := export(t, exporter, ExportOptions{Format: ExportXLSX, Columns: []string{"id", "total_amount", "paid", "owner.email"}})
+
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		t.Fatalf("not a zip: %v", err)
+	}
+	var sheet []byte
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			r, _ := f.Open()
+			sheet, _ = io.ReadAll(r)
+			r.Close()
+		}
+	}
+	var doc struct {
+		Rows []struct {
+			Cells []struct {
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(sheet, &doc); err != nil {
+		t.Fatalf("sheet is not XML: %v\n%s", err, sheet)
+	}
+	if len(doc.Rows) != 3 {
+		t.Fatalf("rows = %d, want header and 2 records", len(doc.Rows))
+	}
+	first := doc.Rows[1].Cells
+	if first[0].Inline != "inv-<1>" || first[1].Value != "250.5" || first[2].Type != "b" || first[2].Value != "1" || first[3].Inline != "a@example.com" {
+		t.Fatalf("first record cells = %+v", first)
+	}
+	if doc.Rows[0].Cells[3].Inline != "owner.email" {
+		t.Fatalf("header = %+v", doc.Rows[0].Cells)
+	}
+}
+
+func TestExportWriter_FailureLeavesFileUnfinished(t *testing.T) {
+	md := exportRecord(t)
+	var buf bytes.Buffer
+	ew, err := NewExportWriter(&buf, &fakeExporter{record: md}, ExportOptions{Format: ExportXLSX}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ew.Write(invoice(md, "inv-1", 1, "")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ew.Close(errors.New("page 2 failed")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err == nil {
+		t.Fatal("a failed export produced a complete workbook")
+	}
+}
+
+func TestNewExportHandler(t *testing.T) {
+	file := listMessages(t)
+	list := NewGenericHandler[*dynamicpb.Message, *dynamicpb.Message](&pagedList{file: file, total: 3}, dynamicpb.NewMessage(file.Messages().ByName("ListRequest")))
+	h, ok := NewExportHandler(list, "record")
+	if !ok {
+		t.Fatal("list route not exportable")
+	}
+	if _, err := h.Execute(context.Background(), nil); !errors.Is(err, ErrExportOnly) {
+		t.Fatalf("Execute err = %v", err)
+	}
+	got := string(export(t, h, ExportOptions{Format: ExportCSV}))
+	if got != "id\nr0\nr1\nr2\n" {
+		t.Fatalf("csv = %q", got)
+	}
+
+	unpaged := NewGenericHandler[*dynamicpb.Message, *dynamicpb.Message](&pagedList{file: file}, dynamicpb.NewMessage(file.Messages().ByName("Record")))
+	if _, ok := NewExportHandler(unpaged, "record"); ok {
+		t.Fatal("unpaginated route reported exportable")
+	}
+}
@@ -3,9 +3,13 @@ package contracts
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/erniealice/espyna-golang/shared/tracing"
 )
 
 // ============================================================================
@@ -30,6 +34,12 @@ type ProtobufParser interface {
 	ParseRequestFromJSON(jsonData []byte) (proto.Message, error)
 }
 
+// MessageTypesDescriber is implemented by handlers that know their request
+// and response protobuf types, e.g. for API documentation.
+type MessageTypesDescriber interface {
+	MessageTypes() (request, response protoreflect.MessageDescriptor)
+}
+
 // ============================================================================
 // Generic Handler Implementation
 // ============================================================================
@@ -39,6 +49,7 @@ type ProtobufParser interface {
 type GenericHandler[Request proto.Message, Response proto.Message] struct {
 	executor         UseCaseExecutor[Request, Response]
 	requestPrototype Request
+	spanName         string
 }
 
 // NewGenericHandler creates a type-safe handler wrapper for any use case
@@ -50,10 +61,12 @@ func NewGenericHandler[Request proto.Message, Response proto.Message](
 	return &GenericHandler[Request, Response]{
 		executor:         executor,
 		requestPrototype: requestPrototype,
+		spanName:         "usecase " + strings.TrimPrefix(fmt.Sprintf("%T", executor), "*"),
 	}
 }
 
-// Execute implements the Handler interface by delegating to the typed use case
+// Execute implements the Handler interface by delegating to the typed use case,
+// traced as a span named after the use case type
 func (h *GenericHandler[Request, Response]) Execute(ctx context.Context, req proto.Message) (proto.Message, error) {
 	// Type assert the request to the specific protobuf type
 	typedReq, ok := req.(Request)
@@ -61,8 +74,10 @@ func (h *GenericHandler[Request, Response]) Execute(ctx context.Context, req pro
 		return nil, fmt.Errorf("invalid request type for use case: expected %T, got %T", *new(Request), req)
 	}
 
-	// Call the typed use case Execute method and capture both response and error
+	// Call the typed use case Execute method inside its trace span
+	ctx, span := tracing.Start(ctx, h.spanName)
 	response, err := h.executor.Execute(ctx, typedReq)
+	span.End(err)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +98,25 @@ func (h *GenericHandler[Request, Response]) ParseRequestFromJSON(jsonData []byte
 	return req, nil
 }
 
+// MessageTypes returns the descriptors of the use case's request and response.
+// The response is nil when its type can only be described by an instance,
+// as with dynamicpb messages.
+func (h *GenericHandler[Request, Response]) MessageTypes() (request, response protoreflect.MessageDescriptor) {
+	return h.requestPrototype.ProtoReflect().Descriptor(), zeroMessageDescriptor[Response]()
+}
+
+// zeroMessageDescriptor describes a message type from its nil pointer, which
+// generated messages support.
+func zeroMessageDescriptor[M proto.Message]() (md protoreflect.MessageDescriptor) {
+	defer func() {
+		if recover() != nil {
+			md = nil
+		}
+	}()
+	var zero M
+	return zero.ProtoReflect().Descriptor()
+}
+
 // ============================================================================
 // Builder Pattern Types
 // ============================================================================
@@ -0,0 +1,393 @@
+package contracts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sqlexec "github.com/erniealice/espyna-golang/database/sqlexec"
+)
+
+// ============================================================================
+// Idempotency Keys
+// ============================================================================
+
+// IdempotencyKeyHeader carries the client's key for a POST request. A
+// retry sent with the same key gets the first response back instead of
+// running the request again.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set to "true" on responses replayed from a
+// stored one.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength bounds the keys accepted; UUIDs and ULIDs fit
+// comfortably.
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyKey identifies a stored response: the client's key is scoped
+// to the caller's workspace and user and the route it was sent to, so one
+// caller's key never replays another's response.
+type IdempotencyKey struct {
+	WorkspaceID string
+	UserID      string
+	Route       string
+	Key         string
+}
+
+// IdempotentResponse is a response kept for replay.
+type IdempotentResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+	// Replayed marks a stored response being sent again; server adapters
+	// set IdempotentReplayedHeader for it.
+	Replayed bool
+}
+
+// IdempotencyRecord is what the store holds for a key: a request in
+// flight until Completed, then its response.
+type IdempotencyRecord struct {
+	Fingerprint string
+	Completed   bool
+	Response    IdempotentResponse
+	ExpiresAt   time.Time
+}
+
+// IdempotencyStore keeps idempotency records.
+type IdempotencyStore interface {
+	// Reserve claims key for a request with fingerprint until lockUntil.
+	// It returns nil when the claim was made, or the record holding the
+	// key. Expired records are replaced.
+	Reserve(ctx context.Context, key IdempotencyKey, fingerprint string, lockUntil time.Time) (*IdempotencyRecord, error)
+	// Complete stores the response of the claimed request, kept until
+	// expiresAt.
+	Complete(ctx context.Context, key IdempotencyKey, fingerprint string, resp IdempotentResponse, expiresAt time.Time) error
+	// Release drops the claim so the key can be used again.
+	Release(ctx context.Context, key IdempotencyKey, fingerprint string) error
+	Close() error
+}
+
+// IdempotencyRequest describes a request for Idempotency.Begin. Server
+// adapters fill it from the request and the IdempotencyKeyHeader, and
+// WorkspaceID and UserID from the authenticated identity; only a request
+// without a workspace in its identity falls back to the WorkspaceHeader.
+type IdempotencyRequest struct {
+	Method      string
+	Path        string
+	Query       string
+	WorkspaceID string
+	UserID      string
+	Key         string
+	Body        []byte
+}
+
+// Idempotency makes POST requests carrying an IdempotencyKeyHeader safe to
+// retry: the first request with a key runs and its response is stored for
+// the configured TTL; later requests with the key get that response back.
+// A nil Idempotency passes everything through.
+type Idempotency struct {
+	cfg   IdempotencyConfig
+	store IdempotencyStore
+	now   func() time.Time
+}
+
+// NewIdempotency creates the idempotency tracker with the store
+// cfg.Backend names; db is the container's database operations, needed by
+// the postgres backend. It returns nil when idempotency keys are disabled.
+func NewIdempotency(cfg IdempotencyConfig, db any) (*Idempotency, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	var store IdempotencyStore
+	switch strings.ToLower(cfg.Backend) {
+	case "", "memory":
+		store = NewMemoryIdempotencyStore()
+	case "postgres":
+		var exec sqlexec.DBExecutor
+		switch d := db.(type) {
+		case interface {
+			GetExecutor(ctx context.Context) sqlexec.DBExecutor
+		}:
+			exec = d.GetExecutor(context.Background())
+		case sqlexec.DBExecutor:
+			exec = d
+		}
+		if exec == nil {
+			return nil, fmt.Errorf("idempotency backend postgres needs the postgresql database provider")
+		}
+		store = NewPostgresIdempotencyStore(exec, cfg.Table)
+	default:
+		return nil, fmt.Errorf("unknown idempotency backend %q", cfg.Backend)
+	}
+	return NewIdempotencyWithStore(cfg, store), nil
+}
+
+// NewIdempotencyWithStore creates the tracker keeping its records in store.
+func NewIdempotencyWithStore(cfg IdempotencyConfig, store IdempotencyStore) *Idempotency {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultIdempotencyTTL
+	}
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = DefaultIdempotencyLockTimeout
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = DefaultIdempotencyMaxBodyBytes
+	}
+	return &Idempotency{cfg: cfg, store: store, now: time.Now}
+}
+
+// Tracks reports whether Begin applies to a request with this method and
+// key header; adapters skip reading the body otherwise.
+func (i *Idempotency) Tracks(method, key string) bool {
+	return i != nil && method == http.MethodPost && key != ""
+}
+
+// MaxBodyBytes is the largest request or response body tracked. Adapters
+// read at most one byte more of the request body.
+func (i *Idempotency) MaxBodyBytes() int {
+	if i == nil {
+		return 0
+	}
+	return i.cfg.MaxBodyBytes
+}
+
+// IdempotencyClaim is held by the request that runs under a key.
+type IdempotencyClaim struct {
+	i           *Idempotency
+	key         IdempotencyKey
+	fingerprint string
+}
+
+// Begin looks the request's key up. It returns a claim when the request
+// should run (call Finish with its response), a response to send instead
+// (a replay, or an error), or neither when the request is not tracked: not
+// a POST, no key, or a body over MaxBodyBytes.
+//
+// The errors are 400 for a malformed key, 409 while the first request with
+// the key is still running, 422 when the key was used for a different
+// request, and 503 when the store fails: unlike rate limiting this fails
+// closed, as running the request could duplicate it.
+func (i *Idempotency) Begin(ctx context.Context, req IdempotencyRequest) (*IdempotencyClaim, *IdempotentResponse) {
+	if !i.Tracks(req.Method, req.Key) || len(req.Body) > i.cfg.MaxBodyBytes {
+		return nil, nil
+	}
+	if !validIdempotencyKey(req.Key) {
+		return nil, idempotencyError(http.StatusBadRequest,
+			fmt.Sprintf("%s must be 1 to %d printable characters", IdempotencyKeyHeader, maxIdempotencyKeyLength))
+	}
+
+	key := IdempotencyKey{WorkspaceID: req.WorkspaceID, UserID: req.UserID, Route: req.Path, Key: req.Key}
+	fingerprint := idempotencyFingerprint(req)
+	record, err := i.store.Reserve(ctx, key, fingerprint, i.now().Add(i.cfg.LockTimeout))
+	if err != nil {
+		log.Printf("⚠️  Warning: idempotency store failed: %v", err)
+		return nil, idempotencyError(http.StatusServiceUnavailable, "Idempotency keys are unavailable, retry later")
+	}
+	switch {
+	case record == nil:
+		return &IdempotencyClaim{i: i, key: key, fingerprint: fingerprint}, nil
+	case record.Fingerprint != fingerprint:
+		return nil, idempotencyError(http.StatusUnprocessableEntity,
+			fmt.Sprintf("%s was already used for a different request", IdempotencyKeyHeader))
+	case !record.Completed:
+		return nil, idempotencyError(http.StatusConflict,
+			fmt.Sprintf("A request with this %s is still in progress", IdempotencyKeyHeader))
+	}
+	resp := record.Response
+	resp.Replayed = true
+	return nil, &resp
+}
+
+// Finish stores the claimed request's response for replay. Server errors
+// and responses over MaxBodyBytes are not stored; the key is released so
+// the client can retry.
+func (c *IdempotencyClaim) Finish(ctx context.Context, resp IdempotentResponse) {
+	if c == nil {
+		return
+	}
+	// The request may have been cancelled; its response is still final.
+	ctx = context.WithoutCancel(ctx)
+	var err error
+	if resp.Status >= http.StatusInternalServerError || len(resp.Body) > c.i.cfg.MaxBodyBytes {
+		err = c.i.store.Release(ctx, c.key, c.fingerprint)
+	} else {
+		resp.Replayed = false
+		err = c.i.store.Complete(ctx, c.key, c.fingerprint, resp, c.i.now().Add(c.i.cfg.TTL))
+	}
+	if err != nil {
+		log.Printf("⚠️  Warning: idempotency store failed for key %q on %s: %v", c.key.Key, c.key.Route, err)
+	}
+}
+
+// Close releases the store.
+func (i *Idempotency) Close() error {
+	if i == nil {
+		return nil
+	}
+	return i.store.Close()
+}
+
+// validIdempotencyKey accepts printable ASCII up to the length limit.
+func validIdempotencyKey(key string) bool {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// idempotencyFingerprint hashes what makes two requests the same.
+func idempotencyFingerprint(req IdempotencyRequest) string {
+	h := sha256.New()
+	for _, part := range []string{req.Method, req.Path, req.Query} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(req.Body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyError builds the JSON error response the adapters send.
+func idempotencyError(status int, message string) *IdempotentResponse {
+	body, _ := json.Marshal(map[string]any{"success": false, "error": message})
+	return &IdempotentResponse{Status: status, ContentType: "application/json", Body: body}
+}
+
+// ============================================================================
+// Memory Store
+// ============================================================================
+
+// memoryIdempotencyStore keeps records in process memory, so each instance
+// tracks its own keys.
+type memoryIdempotencyStore struct {
+	mu        sync.Mutex
+	records   map[IdempotencyKey]*IdempotencyRecord
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryIdempotencyStore creates a store keeping records in memory.
+func NewMemoryIdempotencyStore() IdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[IdempotencyKey]*IdempotencyRecord), now: time.Now}
+}
+
+func (s *memoryIdempotencyStore) Reserve(_ context.Context, key IdempotencyKey, fingerprint string, lockUntil time.Time) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	if r, ok := s.records[key]; ok && now.Before(r.ExpiresAt) {
+		held := *r
+		return &held, nil
+	}
+	s.records[key] = &IdempotencyRecord{Fingerprint: fingerprint, ExpiresAt: lockUntil}
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, key IdempotencyKey, fingerprint string, resp IdempotentResponse, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[key]
+	if !ok || r.Fingerprint != fingerprint || r.Completed {
+		return errors.New("idempotency key is no longer claimed")
+	}
+	r.Completed, r.Response, r.ExpiresAt = true, resp, expiresAt
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, key IdempotencyKey, fingerprint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.records[key]; ok && r.Fingerprint == fingerprint && !r.Completed {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// sweep drops expired records, once a minute.
+func (s *memoryIdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, r := range s.records {
+		if !now.Before(r.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
+
+func (s *memoryIdempotencyStore) Close() error {
+	return nil
+}
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// Idempotency defaults.
+const (
+	DefaultIdempotencyTTL          = 24 * time.Hour
+	DefaultIdempotencyLockTimeout  = time.Minute
+	DefaultIdempotencyMaxBodyBytes = 1 << 20
+)
+
+// LoadIdempotencyConfig overlays cfg with the idempotency key settings from
+// the environment:
+//
+//	CONFIG_IDEMPOTENCY_ENABLED=true
+//	CONFIG_IDEMPOTENCY_TTL=24h            (how long responses are replayed)
+//	CONFIG_IDEMPOTENCY_LOCK_TIMEOUT=1m    (longest a request holds its key)
+//	CONFIG_IDEMPOTENCY_MAX_BODY_BYTES=1048576
+//	CONFIG_IDEMPOTENCY_BACKEND=postgres   (memory or postgres)
+//	CONFIG_IDEMPOTENCY_TABLE=idempotency_key
+func LoadIdempotencyConfig(cfg *IdempotencyConfig, getenv func(string) string) error {
+	if raw := strings.TrimSpace(getenv("CONFIG_IDEMPOTENCY_ENABLED")); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("CONFIG_IDEMPOTENCY_ENABLED=%q is not a boolean", raw)
+		}
+		cfg.Enabled = enabled
+	}
+	for name, target := range map[string]*time.Duration{
+		"CONFIG_IDEMPOTENCY_TTL":          &cfg.TTL,
+		"CONFIG_IDEMPOTENCY_LOCK_TIMEOUT": &cfg.LockTimeout,
+	} {
+		if raw := strings.TrimSpace(getenv(name)); raw != "" {
+			d, err := time.ParseDuration(raw)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("%s=%q is not a positive duration", name, raw)
+			}
+			*target = d
+		}
+	}
+	if raw := strings.TrimSpace(getenv("CONFIG_IDEMPOTENCY_MAX_BODY_BYTES")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("CONFIG_IDEMPOTENCY_MAX_BODY_BYTES=%q is not a positive integer", raw)
+		}
+		cfg.MaxBodyBytes = n
+	}
+	if raw := strings.TrimSpace(getenv("CONFIG_IDEMPOTENCY_BACKEND")); raw != "" {
+		cfg.Backend = raw
+	}
+	if raw := strings.TrimSpace(getenv("CONFIG_IDEMPOTENCY_TABLE")); raw != "" {
+		cfg.Table = raw
+	}
+	return nil
+}
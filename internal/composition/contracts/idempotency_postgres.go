@@ -0,0 +1,126 @@
+package contracts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sqlexec "github.com/erniealice/espyna-golang/database/sqlexec"
+)
+
+// DefaultIdempotencyTable is the table holding idempotency records (see
+// the postgres integration migrations 000014_idempotency_key and
+// 000021_idempotency_key_user).
+const DefaultIdempotencyTable = "idempotency_key"
+
+// postgresIdempotencySweepInterval spaces the deletes of expired records.
+const postgresIdempotencySweepInterval = time.Hour
+
+// postgresIdempotencyStore keeps records in a table shared by every
+// instance. A row with status_code 0 is a request in flight.
+type postgresIdempotencyStore struct {
+	db    sqlexec.DBExecutor
+	table string
+	now   func() time.Time
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewPostgresIdempotencyStore creates a store on table
+// (DefaultIdempotencyTable when empty).
+func NewPostgresIdempotencyStore(db sqlexec.DBExecutor, table string) IdempotencyStore {
+	if table == "" {
+		table = DefaultIdempotencyTable
+	}
+	return &postgresIdempotencyStore{db: db, table: table, now: time.Now}
+}
+
+// Reserve inserts the claim, or takes over an expired row, in one
+// statement so concurrent requests with a key cannot both win.
+func (s *postgresIdempotencyStore) Reserve(ctx context.Context, key IdempotencyKey, fingerprint string, lockUntil time.Time) (*IdempotencyRecord, error) {
+	now := s.now().UTC()
+	s.sweep(ctx, now)
+
+	// A row released between the insert and the select is retried once.
+	for attempt := 0; attempt < 2; attempt++ {
+		res, err := s.db.ExecContext(ctx, fmt.Sprintf(`
+			INSERT INTO %[1]s (workspace_id, user_id, route, idempotency_key, fingerprint, status_code, expires_at, date_created)
+			VALUES ($1, $2, $3, $4, $5, 0, $6, $7)
+			ON CONFLICT (workspace_id, user_id, route, idempotency_key) DO UPDATE SET
+				fingerprint = EXCLUDED.fingerprint, status_code = 0, content_type = '', body = NULL,
+				expires_at = EXCLUDED.expires_at, date_created = EXCLUDED.date_created
+			WHERE %[1]s.expires_at <= $7`, s.table),
+			key.WorkspaceID, key.UserID, key.Route, key.Key, fingerprint, lockUntil.UTC(), now)
+		if err != nil {
+			return nil, fmt.Errorf("reserve idempotency key: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return nil, fmt.Errorf("reserve idempotency key: %w", err)
+		} else if n == 1 {
+			return nil, nil
+		}
+
+		var r IdempotencyRecord
+		err = s.db.QueryRowContext(ctx, fmt.Sprintf(`
+			SELECT fingerprint, status_code, content_type, body, expires_at FROM %s
+			WHERE workspace_id = $1 AND user_id = $2 AND route = $3 AND idempotency_key = $4`, s.table),
+			key.WorkspaceID, key.UserID, key.Route, key.Key,
+		).Scan(&r.Fingerprint, &r.Response.Status, &r.Response.ContentType, &r.Response.Body, &r.ExpiresAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read idempotency key: %w", err)
+		}
+		r.Completed = r.Response.Status != 0
+		return &r, nil
+	}
+	return nil, fmt.Errorf("reserve idempotency key: key %q keeps changing", key.Key)
+}
+
+func (s *postgresIdempotencyStore) Complete(ctx context.Context, key IdempotencyKey, fingerprint string, resp IdempotentResponse, expiresAt time.Time) error {
+	res, err := s.db.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s SET status_code = $6, content_type = $7, body = $8, expires_at = $9
+		WHERE workspace_id = $1 AND user_id = $2 AND route = $3 AND idempotency_key = $4 AND fingerprint = $5 AND status_code = 0`, s.table),
+		key.WorkspaceID, key.UserID, key.Route, key.Key, fingerprint, resp.Status, resp.ContentType, resp.Body, expiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("store idempotent response: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return errors.New("idempotency key is no longer claimed")
+	}
+	return nil
+}
+
+func (s *postgresIdempotencyStore) Release(ctx context.Context, key IdempotencyKey, fingerprint string) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE workspace_id = $1 AND user_id = $2 AND route = $3 AND idempotency_key = $4 AND fingerprint = $5 AND status_code = 0`, s.table),
+		key.WorkspaceID, key.UserID, key.Route, key.Key, fingerprint)
+	if err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// sweep deletes expired records, once an interval per instance. Failures
+// are left for the next sweep; expired rows are overwritten anyway.
+func (s *postgresIdempotencyStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < postgresIdempotencySweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+	_, _ = s.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE expires_at <= $1`, s.table), now)
+}
+
+// Close leaves the database open; it belongs to the database provider.
+func (s *postgresIdempotencyStore) Close() error {
+	return nil
+}
@@ -0,0 +1,122 @@
+package contracts
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryIdempotencyStore().(*memoryIdempotencyStore)
+	store.now = func() time.Time { return now }
+	idem := NewIdempotencyWithStore(IdempotencyConfig{Enabled: true, TTL: time.Hour}, store)
+	idem.now = store.now
+	ctx := context.Background()
+	req := IdempotencyRequest{Method: http.MethodPost, Path: "/api/revenue/create", WorkspaceID: "ws-1", UserID: "user-1", Key: "k-1", Body: []byte(`{"amount":100}`)}
+
+	claim, resp := idem.Begin(ctx, req)
+	if claim == nil || resp != nil {
+		t.Fatalf("first request = %v, %+v; want a claim", claim, resp)
+	}
+	if _, resp := idem.Begin(ctx, req); resp == nil || resp.Status != http.StatusConflict {
+		t.Fatalf("retry in flight = %+v, want 409", resp)
+	}
+	claim.Finish(ctx, IdempotentResponse{Status: http.StatusCreated, ContentType: "application/json", Body: []byte(`{"id":"rev-1"}`)})
+
+	_, resp = idem.Begin(ctx, req)
+	if resp == nil || !resp.Replayed || resp.Status != http.StatusCreated || string(resp.Body) != `{"id":"rev-1"}` {
+		t.Fatalf("retry = %+v, want the stored 201", resp)
+	}
+	changed := req
+	changed.Body = []byte(`{"amount":200}`)
+	if _, resp := idem.Begin(ctx, changed); resp == nil || resp.Status != http.StatusUnprocessableEntity {
+		t.Fatalf("reused key = %+v, want 422", resp)
+	}
+
+	// Keys are scoped to the workspace, user and route.
+	other := req
+	other.WorkspaceID = "ws-2"
+	if claim, _ := idem.Begin(ctx, other); claim == nil {
+		t.Fatal("key in another workspace was not claimed")
+	}
+	other = req
+	other.UserID = "user-2"
+	claim, resp = idem.Begin(ctx, other)
+	if claim == nil || resp != nil {
+		t.Fatalf("another user's identical request = %v, %+v; want its own claim, not the stored response", claim, resp)
+	}
+	other = req
+	other.Path = "/api/revenue/update"
+	if claim, _ := idem.Begin(ctx, other); claim == nil {
+		t.Fatal("key on another route was not claimed")
+	}
+
+	now = now.Add(time.Hour)
+	if claim, _ := idem.Begin(ctx, changed); claim == nil {
+		t.Fatal("expired key was not claimed again")
+	}
+}
+
+func TestIdempotency_ReleasesFailedRequests(t *testing.T) {
+	idem := NewIdempotencyWithStore(IdempotencyConfig{Enabled: true, MaxBodyBytes: 8}, NewMemoryIdempotencyStore())
+	ctx := context.Background()
+	req := IdempotencyRequest{Method: http.MethodPost, Path: "/api/pay", Key: "k-1"}
+
+	claim, _ := idem.Begin(ctx, req)
+	claim.Finish(ctx, IdempotentResponse{Status: http.StatusBadGateway})
+	claim, _ = idem.Begin(ctx, req)
+	if claim == nil {
+		t.Fatal("key was kept after a server error")
+	}
+	claim.Finish(ctx, IdempotentResponse{Status: http.StatusOK, Body: []byte("too long to keep")})
+	if claim, _ := idem.Begin(ctx, req); claim == nil {
+		t.Fatal("key was kept for a response over the size limit")
+	}
+}
+
+func TestIdempotency_Untracked(t *testing.T) {
+	idem := NewIdempotencyWithStore(IdempotencyConfig{Enabled: true, MaxBodyBytes: 4}, NewMemoryIdempotencyStore())
+	ctx := context.Background()
+
+	for name, req := range map[string]IdempotencyRequest{
+		"no key":   {Method: http.MethodPost, Path: "/a"},
+		"GET":      {Method: http.MethodGet, Path: "/a", Key: "k"},
+		"big body": {Method: http.MethodPost, Path: "/a", Key: "k", Body: []byte("12345")},
+	} {
+		if claim, resp := idem.Begin(ctx, req); claim != nil || resp != nil {
+			t.Errorf("%s: tracked (%v, %+v)", name, claim, resp)
+		}
+	}
+	if _, resp := idem.Begin(ctx, IdempotencyRequest{Method: http.MethodPost, Path: "/a", Key: "bad\nkey"}); resp == nil || resp.Status != http.StatusBadRequest {
+		t.Errorf("malformed key = %+v, want 400", resp)
+	}
+
+	var disabled *Idempotency
+	if claim, resp := disabled.Begin(ctx, IdempotencyRequest{Method: http.MethodPost, Key: "k"}); claim != nil || resp != nil {
+		t.Error("nil tracker tracked a request")
+	}
+}
+
+func TestLoadIdempotencyConfig(t *testing.T) {
+	env := map[string]string{
+		"CONFIG_IDEMPOTENCY_ENABLED": "true",
+		"CONFIG_IDEMPOTENCY_TTL":     "48h",
+		"CONFIG_IDEMPOTENCY_BACKEND": "postgres",
+	}
+	var cfg IdempotencyConfig
+	if err := LoadIdempotencyConfig(&cfg, func(k string) string { return env[k] }); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Enabled || cfg.TTL != 48*time.Hour || cfg.Backend != "postgres" || cfg.LockTimeout != 0 {
+		t.Fatalf("cfg = %+v", cfg)
+	}
+	env["CONFIG_IDEMPOTENCY_LOCK_TIMEOUT"] = "-1s"
+	if err := LoadIdempotencyConfig(&cfg, func(k string) string { return env[k] }); err == nil {
+		t.Fatal("negative lock timeout accepted")
+	}
+	if _, err := NewIdempotency(IdempotencyConfig{Enabled: true, Backend: "postgres"}, nil); err == nil {
+		t.Fatal("postgres backend without a database accepted")
+	}
+}
@@ -0,0 +1,413 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// Rate Limiting
+// ============================================================================
+
+// WorkspaceHeader names the workspace a request is made for; server
+// adapters read it to apply the workspace's quota.
+const WorkspaceHeader = "X-Workspace-ID"
+
+// Rate limit scopes, reported by RateLimitDecision.Scope.
+const (
+	RateLimitScopeGlobal    = "global"
+	RateLimitScopeEndpoint  = "endpoint"
+	RateLimitScopeIP        = "ip"
+	RateLimitScopeWorkspace = "workspace"
+)
+
+// RateLimitRequest identifies a request for rate limiting. Server adapters
+// fill it before routing, from the request path, the client IP as their
+// framework resolves it, and the WorkspaceHeader.
+type RateLimitRequest struct {
+	Path        string
+	ClientIP    string
+	WorkspaceID string
+}
+
+// RateLimitDecision is the outcome of RateLimiter.Allow.
+type RateLimitDecision struct {
+	Allowed bool
+	// Scope is the limit that refused the request or, when allowed, the
+	// one with the fewest requests left (empty when no limit applies).
+	Scope      string
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Headers returns the response headers describing the decision:
+// X-RateLimit-Limit and X-RateLimit-Remaining, plus Retry-After (seconds)
+// when refused.
+func (d RateLimitDecision) Headers() map[string]string {
+	if d.Scope == "" {
+		return nil
+	}
+	h := map[string]string{
+		"X-RateLimit-Limit":     strconv.Itoa(d.Limit),
+		"X-RateLimit-Remaining": strconv.Itoa(d.Remaining),
+	}
+	if !d.Allowed {
+		h["Retry-After"] = strconv.Itoa(int(math.Ceil(d.RetryAfter.Seconds())))
+	}
+	return h
+}
+
+// RateLimitResult is the outcome of taking a token from one bucket.
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keeps token buckets by key.
+type RateLimitStore interface {
+	// Take removes one token from the bucket at key, created full when
+	// missing, refilling it at limit.RequestsPerMinute up to its burst.
+	Take(ctx context.Context, key string, limit EndpointRateLimit) (RateLimitResult, error)
+	Close() error
+}
+
+// RateLimiter applies the endpoint, per-IP, per-workspace and global
+// limits of a RateLimitConfig. A nil RateLimiter allows everything.
+type RateLimiter struct {
+	cfg       RateLimitConfig
+	store     RateLimitStore
+	exact     map[string]string // path -> Endpoints key
+	prefixes  []string          // Endpoints keys ending in *, longest first
+	lastError atomic.Int64      // unix seconds of the last logged store error
+}
+
+// NewRateLimiter creates a limiter with the store cfg.Backend names. It
+// returns nil when rate limiting is disabled.
+func NewRateLimiter(cfg RateLimitConfig) (*RateLimiter, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	var store RateLimitStore
+	switch strings.ToLower(cfg.Backend) {
+	case "", "memory":
+		store = NewMemoryRateLimitStore()
+	case "redis":
+		if cfg.RedisURL == "" {
+			return nil, fmt.Errorf("rate limit backend redis needs a redis URL")
+		}
+		s, err := NewRedisRateLimitStore(cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		store = s
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q", cfg.Backend)
+	}
+	return NewRateLimiterWithStore(cfg, store), nil
+}
+
+// NewRateLimiterWithStore creates a limiter keeping its buckets in store.
+func NewRateLimiterWithStore(cfg RateLimitConfig, store RateLimitStore) *RateLimiter {
+	l := &RateLimiter{cfg: cfg, store: store, exact: make(map[string]string)}
+	for key := range cfg.Endpoints {
+		if prefix, ok := strings.CutSuffix(key, "*"); ok {
+			l.prefixes = append(l.prefixes, prefix)
+		} else {
+			l.exact[key] = key
+		}
+	}
+	sort.SliceStable(l.prefixes, func(i, j int) bool {
+		return len(l.prefixes[i]) > len(l.prefixes[j])
+	})
+	return l
+}
+
+// Allow takes a token from every limit that applies to the request. The
+// most specific limits are checked first, so a single noisy client is
+// refused before it drains the shared buckets. When the store fails the
+// request is allowed: an unreachable Redis must not take the API down.
+func (l *RateLimiter) Allow(ctx context.Context, req RateLimitRequest) RateLimitDecision {
+	if l == nil {
+		return RateLimitDecision{Allowed: true}
+	}
+	decision := RateLimitDecision{Allowed: true, Remaining: math.MaxInt}
+	check := func(scope, key string, limit EndpointRateLimit) bool {
+		if limit.RequestsPerMinute <= 0 {
+			return true
+		}
+		res, err := l.store.Take(ctx, key, limit)
+		if err != nil {
+			l.logStoreError(err)
+			return true
+		}
+		if !res.Allowed || res.Remaining < decision.Remaining {
+			decision = RateLimitDecision{
+				Allowed:    res.Allowed,
+				Scope:      scope,
+				Limit:      burst(limit),
+				Remaining:  res.Remaining,
+				RetryAfter: res.RetryAfter,
+			}
+		}
+		return res.Allowed
+	}
+
+	if pattern, ok := l.endpoint(req.Path); ok {
+		if !check(RateLimitScopeEndpoint, "endpoint:"+pattern+":"+req.ClientIP, l.cfg.Endpoints[pattern]) {
+			return decision
+		}
+	}
+	if req.ClientIP != "" && !check(RateLimitScopeIP, "ip:"+req.ClientIP, l.cfg.PerIP) {
+		return decision
+	}
+	if req.WorkspaceID != "" {
+		limit, ok := l.cfg.Workspaces[req.WorkspaceID]
+		if !ok {
+			limit = l.cfg.PerWorkspace
+		}
+		if !check(RateLimitScopeWorkspace, "workspace:"+req.WorkspaceID, limit) {
+			return decision
+		}
+	}
+	global := EndpointRateLimit{RequestsPerMinute: l.cfg.RequestsPerMinute, BurstSize: l.cfg.BurstSize}
+	if !check(RateLimitScopeGlobal, "global", global) {
+		return decision
+	}
+	if decision.Scope == "" {
+		decision.Remaining = 0
+	}
+	return decision
+}
+
+// Close releases the store.
+func (l *RateLimiter) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.store.Close()
+}
+
+// endpoint finds the Endpoints key matching path.
+func (l *RateLimiter) endpoint(path string) (string, bool) {
+	if key, ok := l.exact[path]; ok {
+		return key, true
+	}
+	for _, prefix := range l.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return prefix + "*", true
+		}
+	}
+	return "", false
+}
+
+// logStoreError logs store failures at most once a minute.
+func (l *RateLimiter) logStoreError(err error) {
+	now := time.Now().Unix()
+	last := l.lastError.Load()
+	if now-last >= 60 && l.lastError.CompareAndSwap(last, now) {
+		log.Printf("⚠️  Warning: rate limit store failed, allowing requests: %v", err)
+	}
+}
+
+// burst is the bucket size of a limit.
+func burst(limit EndpointRateLimit) int {
+	if limit.BurstSize > 0 {
+		return limit.BurstSize
+	}
+	return limit.RequestsPerMinute
+}
+
+// ============================================================================
+// Memory Store
+// ============================================================================
+
+// memoryRateLimitStore keeps buckets in process memory, so each instance
+// counts separately.
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Duration // time to refill from empty; idle buckets are swept after it
+}
+
+// NewMemoryRateLimitStore creates a store keeping buckets in memory.
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{buckets: make(map[string]*tokenBucket), now: time.Now}
+}
+
+func (s *memoryRateLimitStore) Take(_ context.Context, key string, limit EndpointRateLimit) (RateLimitResult, error) {
+	capacity := float64(burst(limit))
+	perSecond := float64(limit.RequestsPerMinute) / 60
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: capacity, last: now}
+		s.buckets[key] = b
+	}
+	b.full = time.Duration(capacity / perSecond * float64(time.Second))
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*perSecond)
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+		return RateLimitResult{Allowed: false, RetryAfter: wait}, nil
+	}
+	b.tokens--
+	return RateLimitResult{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+// sweep drops buckets idle long enough to be full again, once a minute.
+func (s *memoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if now.Sub(b.last) >= b.full {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func (s *memoryRateLimitStore) Close() error {
+	return nil
+}
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+// LoadRateLimitConfig overlays cfg with the rate limit settings from the
+// environment:
+//
+//	CONFIG_RATE_LIMIT_FILE=ratelimit.json      (RateLimitConfig as JSON)
+//	CONFIG_RATE_LIMIT_ENABLED=true
+//	CONFIG_RATE_LIMIT_GLOBAL=6000:500
+//	CONFIG_RATE_LIMIT_PER_IP=120:30
+//	CONFIG_RATE_LIMIT_PER_WORKSPACE=600:100
+//	CONFIG_RATE_LIMIT_WORKSPACES=ws_123=3000:300,ws_456=60
+//	CONFIG_RATE_LIMIT_ENDPOINTS=/auth/*=10,/integration/email/send=30:5
+//	CONFIG_RATE_LIMIT_BACKEND=redis
+//	CONFIG_RATE_LIMIT_REDIS_URL=redis://localhost:6379/0
+//
+// Limits are requests per minute with an optional :burst. The file is
+// read first and the variables override it; map entries are merged.
+func LoadRateLimitConfig(cfg *RateLimitConfig, getenv func(string) string) error {
+	if path := strings.TrimSpace(getenv("CONFIG_RATE_LIMIT_FILE")); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read rate limit file: %w", err)
+		}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return fmt.Errorf("parse rate limit file %s: %w", path, err)
+		}
+	}
+
+	if raw := strings.TrimSpace(getenv("CONFIG_RATE_LIMIT_ENABLED")); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("CONFIG_RATE_LIMIT_ENABLED=%q is not a boolean", raw)
+		}
+		cfg.Enabled = enabled
+	}
+	if raw := strings.TrimSpace(getenv("CONFIG_RATE_LIMIT_GLOBAL")); raw != "" {
+		limit, err := parseRateLimit(raw)
+		if err != nil {
+			return fmt.Errorf("CONFIG_RATE_LIMIT_GLOBAL: %w", err)
+		}
+		cfg.RequestsPerMinute, cfg.BurstSize = limit.RequestsPerMinute, limit.BurstSize
+	}
+	for name, target := range map[string]*EndpointRateLimit{
+		"CONFIG_RATE_LIMIT_PER_IP":        &cfg.PerIP,
+		"CONFIG_RATE_LIMIT_PER_WORKSPACE": &cfg.PerWorkspace,
+	} {
+		if raw := strings.TrimSpace(getenv(name)); raw != "" {
+			limit, err := parseRateLimit(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			*target = limit
+		}
+	}
+	for name, target := range map[string]*map[string]EndpointRateLimit{
+		"CONFIG_RATE_LIMIT_WORKSPACES": &cfg.Workspaces,
+		"CONFIG_RATE_LIMIT_ENDPOINTS":  &cfg.Endpoints,
+	} {
+		if err := parseRateLimitList(getenv(name), target); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if raw := strings.TrimSpace(getenv("CONFIG_RATE_LIMIT_BACKEND")); raw != "" {
+		cfg.Backend = raw
+	}
+	if raw := strings.TrimSpace(getenv("CONFIG_RATE_LIMIT_REDIS_URL")); raw != "" {
+		cfg.RedisURL = raw
+	}
+	return nil
+}
+
+// parseRateLimit reads "requests_per_minute[:burst]".
+func parseRateLimit(raw string) (EndpointRateLimit, error) {
+	rpmRaw, burstRaw, hasBurst := strings.Cut(strings.TrimSpace(raw), ":")
+	rpm, err := strconv.Atoi(strings.TrimSpace(rpmRaw))
+	if err != nil || rpm < 0 {
+		return EndpointRateLimit{}, fmt.Errorf("%q is not requests_per_minute[:burst]", raw)
+	}
+	limit := EndpointRateLimit{RequestsPerMinute: rpm}
+	if hasBurst {
+		b, err := strconv.Atoi(strings.TrimSpace(burstRaw))
+		if err != nil || b < 1 {
+			return EndpointRateLimit{}, fmt.Errorf("%q is not requests_per_minute[:burst]", raw)
+		}
+		limit.BurstSize = b
+	}
+	return limit, nil
+}
+
+// parseRateLimitList reads "key=limit,..." into target.
+func parseRateLimitList(raw string, target *map[string]EndpointRateLimit) error {
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("entry %q is not key=requests_per_minute[:burst]", entry)
+		}
+		limit, err := parseRateLimit(value)
+		if err != nil {
+			return err
+		}
+		if *target == nil {
+			*target = make(map[string]EndpointRateLimit)
+		}
+		(*target)[strings.TrimSpace(key)] = limit
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/erniealice/espyna-golang/shared/redis"
+)
+
+// redisRateLimitKeyPrefix namespaces the bucket keys.
+const redisRateLimitKeyPrefix = "espyna:ratelimit:"
+
+// redisRateLimitTimeout bounds a single bucket update.
+const redisRateLimitTimeout = 250 * time.Millisecond
+
+// tokenBucketScript refills and takes from a bucket atomically, on the
+// Redis clock so instances with drifting clocks agree. A bucket hash holds
+// tokens (fractional) and ts (ms); it expires once it would be full again.
+// ARGV: capacity, refill rate in tokens per ms. Returns {allowed,
+// remaining, retry_after_ms}.
+const tokenBucketScript = `
+redis.replicate_commands()
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+  tokens = capacity
+  ts = now
+end
+if now > ts then
+  tokens = math.min(capacity, tokens + (now - ts) * rate)
+end
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  wait = math.ceil((1 - tokens) / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate) + 1000)
+return {allowed, math.floor(tokens), wait}
+`
+
+// redisRateLimitStore keeps buckets in Redis, so every instance of the
+// server shares them.
+type redisRateLimitStore struct {
+	client *redis.Client
+}
+
+// NewRedisRateLimitStore creates a store keeping buckets in the Redis
+// server at url (redis://[user:password@]host:port/db).
+func NewRedisRateLimitStore(url string) (RateLimitStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return &redisRateLimitStore{client: redis.NewClient(opts)}, nil
+}
+
+func (s *redisRateLimitStore) Take(ctx context.Context, key string, limit EndpointRateLimit) (RateLimitResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, redisRateLimitTimeout)
+	defer cancel()
+
+	perMs := float64(limit.RequestsPerMinute) / 60000
+	reply, err := s.client.Do(ctx, "EVAL", tokenBucketScript, 1, redisRateLimitKeyPrefix+key,
+		strconv.Itoa(burst(limit)), strconv.FormatFloat(perMs, 'g', -1, 64))
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("redis rate limit: %w", err)
+	}
+	values, ok := reply.([]any)
+	if !ok || len(values) != 3 {
+		return RateLimitResult{}, fmt.Errorf("redis rate limit: unexpected reply %v", reply)
+	}
+	allowed, err1 := redis.Int64(values[0], nil)
+	remaining, err2 := redis.Int64(values[1], nil)
+	waitMs, err3 := redis.Int64(values[2], nil)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return RateLimitResult{}, fmt.Errorf("redis rate limit: unexpected reply %v", reply)
+	}
+	return RateLimitResult{
+		Allowed:    allowed == 1,
+		Remaining:  int(remaining),
+		RetryAfter: time.Duration(waitMs) * time.Millisecond,
+	}, nil
+}
+
+func (s *redisRateLimitStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,106 @@
+package contracts
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimitStore_TokenBucket(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryRateLimitStore().(*memoryRateLimitStore)
+	store.now = func() time.Time { return now }
+	limit := EndpointRateLimit{RequestsPerMinute: 60, BurstSize: 3} // 1 token/s
+	ctx := context.Background()
+
+	for i := 2; i >= 0; i-- {
+		res, _ := store.Take(ctx, "k", limit)
+		if !res.Allowed || res.Remaining != i {
+			t.Fatalf("take = %+v, want allowed with %d left", res, i)
+		}
+	}
+	res, _ := store.Take(ctx, "k", limit)
+	if res.Allowed || res.RetryAfter != time.Second {
+		t.Fatalf("empty bucket take = %+v, want refused for 1s", res)
+	}
+
+	now = now.Add(1500 * time.Millisecond)
+	if res, _ := store.Take(ctx, "k", limit); !res.Allowed {
+		t.Fatalf("take after refill = %+v, want allowed", res)
+	}
+	if res, _ := store.Take(ctx, "other", limit); !res.Allowed || res.Remaining != 2 {
+		t.Fatalf("separate key take = %+v, want a full bucket", res)
+	}
+}
+
+func TestRateLimiter_Scopes(t *testing.T) {
+	limiter := NewRateLimiterWithStore(RateLimitConfig{
+		Enabled:      true,
+		PerIP:        EndpointRateLimit{RequestsPerMinute: 60, BurstSize: 5},
+		PerWorkspace: EndpointRateLimit{RequestsPerMinute: 60, BurstSize: 2},
+		Workspaces:   map[string]EndpointRateLimit{"ws-big": {RequestsPerMinute: 600}},
+		Endpoints:    map[string]EndpointRateLimit{"/auth/*": {RequestsPerMinute: 1}},
+	}, NewMemoryRateLimitStore())
+	ctx := context.Background()
+
+	login := RateLimitRequest{Path: "/auth/login", ClientIP: "10.0.0.1"}
+	if d := limiter.Allow(ctx, login); !d.Allowed || d.Scope != RateLimitScopeEndpoint {
+		t.Fatalf("first login = %+v", d)
+	}
+	if d := limiter.Allow(ctx, login); d.Allowed || d.Scope != RateLimitScopeEndpoint || d.Headers()["Retry-After"] != "60" {
+		t.Fatalf("second login = %+v, want refused by the endpoint limit", d)
+	}
+	// The endpoint limit is per client.
+	if d := limiter.Allow(ctx, RateLimitRequest{Path: "/auth/login", ClientIP: "10.0.0.2"}); !d.Allowed {
+		t.Fatalf("login from another IP = %+v", d)
+	}
+
+	small := RateLimitRequest{Path: "/api/x", ClientIP: "10.0.0.3", WorkspaceID: "ws-small"}
+	limiter.Allow(ctx, small)
+	limiter.Allow(ctx, small)
+	if d := limiter.Allow(ctx, small); d.Allowed || d.Scope != RateLimitScopeWorkspace {
+		t.Fatalf("third workspace request = %+v, want refused by the workspace quota", d)
+	}
+	big := RateLimitRequest{Path: "/api/x", ClientIP: "10.0.0.4", WorkspaceID: "ws-big"}
+	for i := 0; i < 5; i++ {
+		if d := limiter.Allow(ctx, big); !d.Allowed {
+			t.Fatalf("request %d of the larger quota = %+v", i, d)
+		}
+	}
+	if d := limiter.Allow(ctx, big); d.Allowed || d.Scope != RateLimitScopeIP {
+		t.Fatalf("sixth request = %+v, want refused by the IP limit", d)
+	}
+
+	var disabled *RateLimiter
+	if d := disabled.Allow(ctx, login); !d.Allowed || d.Headers() != nil {
+		t.Fatalf("nil limiter = %+v", d)
+	}
+}
+
+func TestLoadRateLimitConfig(t *testing.T) {
+	env := map[string]string{
+		"CONFIG_RATE_LIMIT_ENABLED":       "true",
+		"CONFIG_RATE_LIMIT_GLOBAL":        "6000:500",
+		"CONFIG_RATE_LIMIT_PER_WORKSPACE": "600",
+		"CONFIG_RATE_LIMIT_WORKSPACES":    "ws_1=3000:300, ws_2=60",
+		"CONFIG_RATE_LIMIT_BACKEND":       "redis",
+	}
+	cfg := RateLimitConfig{PerIP: EndpointRateLimit{RequestsPerMinute: 100, BurstSize: 20}}
+	if err := LoadRateLimitConfig(&cfg, func(k string) string { return env[k] }); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Enabled || cfg.RequestsPerMinute != 6000 || cfg.BurstSize != 500 || cfg.Backend != "redis" {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	if cfg.PerIP.RequestsPerMinute != 100 || cfg.PerWorkspace != (EndpointRateLimit{RequestsPerMinute: 600}) {
+		t.Errorf("per-client limits = %+v, %+v", cfg.PerIP, cfg.PerWorkspace)
+	}
+	if cfg.Workspaces["ws_1"] != (EndpointRateLimit{RequestsPerMinute: 3000, BurstSize: 300}) || len(cfg.Workspaces) != 2 {
+		t.Errorf("workspaces = %+v", cfg.Workspaces)
+	}
+
+	env["CONFIG_RATE_LIMIT_ENDPOINTS"] = "/auth/*=fast"
+	if err := LoadRateLimitConfig(&cfg, func(k string) string { return env[k] }); err == nil {
+		t.Error("invalid endpoint limit was accepted")
+	}
+}
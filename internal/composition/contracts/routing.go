@@ -2,6 +2,7 @@ package contracts
 
 import (
 	"context"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 )
@@ -56,14 +57,15 @@ type LeapforCustomRoute = Route
 
 // RouteMetadata contains additional information about a route
 type RouteMetadata struct {
-	Name        string   // Unique route identifier (auto-generated)
-	Domain      string   // e.g., "entity", "event", "framework"
-	Resource    string   // e.g., "admin", "client", "user"
-	Operation   string   // e.g., "create", "read", "update", "delete", "list"
-	Description string   // Human-readable description
-	Tags        []string // e.g., ["admin", "public", "internal"]
-	Version     string   // API version
-	Deprecated  bool     // Whether the route is deprecated
+	Name        string        // Unique route identifier (auto-generated)
+	Domain      string        // e.g., "entity", "event", "framework"
+	Resource    string        // e.g., "admin", "client", "user"
+	Operation   string        // e.g., "create", "read", "update", "delete", "list"
+	Description string        // Human-readable description
+	Tags        []string      // e.g., ["admin", "public", "internal"]
+	Version     string        // API version
+	Deprecated  bool          // Whether the route is deprecated
+	Timeout     time.Duration // Time budget; zero means DefaultRouteTimeout
 }
 
 // ============================================================================
@@ -103,6 +105,7 @@ type RouteConfiguration struct {
 	Method  string
 	Path    string
 	Handler UseCaseHandler // Direct use case handler
+	Timeout time.Duration  // Time budget; zero means DefaultRouteTimeout
 }
 
 // ============================================================================
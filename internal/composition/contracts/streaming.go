@@ -0,0 +1,226 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ============================================================================
+// Streaming List Responses
+// ============================================================================
+
+// StreamQueryParam is the query parameter that asks a server adapter to
+// stream a list route: POST /api/.../get-list-page-data?stream=true returns
+// every page of records, written as they are fetched, instead of one
+// buffered page.
+const StreamQueryParam = "stream"
+
+// ErrStreamingUnsupported is returned by StreamRecords when the route's
+// request or response is not a paginated list.
+var ErrStreamingUnsupported = errors.New("route does not support streaming")
+
+// RecordStreamer is implemented by route handlers that can produce their
+// list results record by record. Server adapters write each record as emit
+// is called, so memory stays flat however many records the list has.
+type RecordStreamer interface {
+	StreamRecords(ctx context.Context, req proto.Message, emit func(record proto.Message) error) error
+}
+
+// streamPageSize is the page size StreamRecords requests: the largest page
+// the database adapters serve.
+const streamPageSize = 100
+
+// StreamRecords runs the use case once per page and emits each page's
+// records before fetching the next, so only one page is held at a time.
+// The request needs an offset "pagination" field and the response a
+// records list ("data" or "<entity>_list"); any page size or page number
+// the caller set is replaced. Authorization and validation run on every
+// page exactly as for a single list call.
+func (h *GenericHandler[Request, Response]) StreamRecords(ctx context.Context, req proto.Message, emit func(record proto.Message) error) error {
+	pageReq := proto.Clone(h.requestPrototype).(Request)
+	if req != nil {
+		typedReq, ok := req.(Request)
+		if !ok {
+			return fmt.Errorf("invalid request type for use case: expected %T, got %T", *new(Request), req)
+		}
+		pageReq = proto.Clone(typedReq).(Request)
+	}
+
+	m := pageReq.ProtoReflect()
+	paginationField := m.Descriptor().Fields().ByName("pagination")
+	if paginationField == nil || paginationField.Message() == nil {
+		return ErrStreamingUnsupported
+	}
+
+	for page := int32(1); ; page++ {
+		pagination, ok := offsetPage(m.NewField(paginationField).Message(), page)
+		if !ok {
+			return ErrStreamingUnsupported
+		}
+		m.Set(paginationField, protoreflect.ValueOfMessage(pagination))
+
+		resp, err := h.executor.Execute(ctx, pageReq)
+		if err != nil {
+			return err
+		}
+		records, hasNext, ok := recordPage(resp.ProtoReflect())
+		if !ok {
+			return ErrStreamingUnsupported
+		}
+		for i := 0; i < records.Len(); i++ {
+			if err := emit(records.Get(i).Message().Interface()); err != nil {
+				return err
+			}
+		}
+		// A short page ends the list even when has_next is wrongly set.
+		if !hasNext || records.Len() < streamPageSize {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// offsetPage fills a PaginationRequest for the given page.
+func offsetPage(pagination protoreflect.Message, page int32) (protoreflect.Message, bool) {
+	fields := pagination.Descriptor().Fields()
+	limit, offset := fields.ByName("limit"), fields.ByName("offset")
+	if limit == nil || limit.Kind() != protoreflect.Int32Kind || offset == nil || offset.Message() == nil {
+		return nil, false
+	}
+	offsetMsg := pagination.NewField(offset).Message()
+	pageField := offsetMsg.Descriptor().Fields().ByName("page")
+	if pageField == nil || pageField.Kind() != protoreflect.Int32Kind {
+		return nil, false
+	}
+	offsetMsg.Set(pageField, protoreflect.ValueOfInt32(page))
+	pagination.Set(limit, protoreflect.ValueOfInt32(streamPageSize))
+	pagination.Set(offset, protoreflect.ValueOfMessage(offsetMsg))
+	return pagination, true
+}
+
+// recordsField finds a list response's records: the "data" field, or else
+// the single repeated message field named "<entity>_list".
+func recordsField(md protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	fields := md.Fields()
+	if data := fields.ByName("data"); data != nil && data.IsList() && data.Message() != nil {
+		return data
+	}
+	var found protoreflect.FieldDescriptor
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.IsList() && fd.Message() != nil && strings.HasSuffix(string(fd.Name()), "_list") {
+			if found != nil {
+				return nil
+			}
+			found = fd
+		}
+	}
+	return found
+}
+
+// recordPage finds a list response's records and whether another page
+// follows.
+func recordPage(resp protoreflect.Message) (protoreflect.List, bool, bool) {
+	fields := resp.Descriptor().Fields()
+	records := recordsField(resp.Descriptor())
+	if records == nil {
+		return nil, false, false
+	}
+
+	hasNext := false
+	if paginationField := fields.ByName("pagination"); paginationField != nil && paginationField.Message() != nil {
+		pagination := resp.Get(paginationField).Message()
+		if hasNextField := pagination.Descriptor().Fields().ByName("has_next"); hasNextField != nil && hasNextField.Kind() == protoreflect.BoolKind {
+			hasNext = pagination.Get(hasNextField).Bool()
+		}
+	}
+	return resp.Get(records).List(), hasNext, true
+}
+
+// JSONStreamWriter writes streamed records as one JSON document:
+//
+//	{"data":[{...},{...}],"count":2,"success":true}
+//
+// Records are encoded like buffered responses. Once the first record is
+// written the status line is gone, so a later failure is reported in the
+// trailer as "success":false with an "error" message.
+type JSONStreamWriter struct {
+	w       io.Writer
+	flush   func()
+	started bool
+	count   int
+}
+
+// streamFlushEvery is how many records are written between flushes.
+const streamFlushEvery = 100
+
+// NewJSONStreamWriter writes to w. flush, when non-nil, pushes buffered
+// output to the client and is called periodically.
+func NewJSONStreamWriter(w io.Writer, flush func()) *JSONStreamWriter {
+	return &JSONStreamWriter{w: w, flush: flush}
+}
+
+// Started reports whether any output has been written. Until then the
+// caller can still answer with a regular error response.
+func (s *JSONStreamWriter) Started() bool {
+	return s.started
+}
+
+// Write encodes one record. It is the emit function for StreamRecords.
+func (s *JSONStreamWriter) Write(record proto.Message) error {
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode record %d: %w", s.count, err)
+	}
+	prefix := ","
+	if !s.started {
+		prefix = `{"data":[`
+		s.started = true
+	}
+	if _, err := io.WriteString(s.w, prefix); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(encoded); err != nil {
+		return err
+	}
+	s.count++
+	if s.flush != nil && s.count%streamFlushEvery == 0 {
+		s.flush()
+	}
+	return nil
+}
+
+// Close writes the trailer, reporting streamErr if the stream failed.
+func (s *JSONStreamWriter) Close(streamErr error) error {
+	if !s.started {
+		if _, err := io.WriteString(s.w, `{"data":[`); err != nil {
+			return err
+		}
+		s.started = true
+	}
+	trailer := map[string]any{"count": s.count, "success": streamErr == nil}
+	if streamErr != nil {
+		trailer["error"] = streamErr.Error()
+	}
+	encoded, err := json.Marshal(trailer)
+	if err != nil {
+		return err
+	}
+	// encoded is {"count":...}; splice its members after the data array.
+	if _, err := io.WriteString(s.w, "],"+string(encoded[1:])+"\n"); err != nil {
+		return err
+	}
+	if s.flush != nil {
+		s.flush()
+	}
+	return nil
+}
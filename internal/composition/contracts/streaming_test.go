@@ -0,0 +1,163 @@
+package contracts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// listFile mirrors the shape of the esqyma list-page-data messages.
+const listFile = `
+name: "test/list.proto" package: "test" syntax: "proto3"
+message_type {
+  name: "OffsetPagination"
+  field { name: "page" number: 1 type: TYPE_INT32 label: LABEL_OPTIONAL }
+}
+message_type {
+  name: "PaginationRequest"
+  field { name: "limit" number: 1 type: TYPE_INT32 label: LABEL_OPTIONAL }
+  field { name: "offset" number: 2 type: TYPE_MESSAGE label: LABEL_OPTIONAL type_name: ".test.OffsetPagination" oneof_index: 0 }
+  oneof_decl { name: "method" }
+}
+message_type {
+  name: "PaginationResponse"
+  field { name: "has_next" number: 1 type: TYPE_BOOL label: LABEL_OPTIONAL }
+}
+message_type {
+  name: "Record"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+}
+message_type {
+  name: "ListRequest"
+  field { name: "pagination" number: 1 type: TYPE_MESSAGE label: LABEL_OPTIONAL type_name: ".test.PaginationRequest" }
+}
+message_type {
+  name: "ListResponse"
+  field { name: "record_list" number: 1 type: TYPE_MESSAGE label: LABEL_REPEATED type_name: ".test.Record" }
+  field { name: "search_results" number: 2 type: TYPE_MESSAGE label: LABEL_REPEATED type_name: ".test.Record" }
+  field { name: "pagination" number: 3 type: TYPE_MESSAGE label: LABEL_OPTIONAL type_name: ".test.PaginationResponse" }
+  field { name: "success" number: 4 type: TYPE_BOOL label: LABEL_OPTIONAL }
+}
+`
+
+func listMessages(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+	var fdp descriptorpb.FileDescriptorProto
+	if err := prototext.Unmarshal([]byte(listFile), &fdp); err != nil {
+		t.Fatalf("parse descriptor: %v", err)
+	}
+	fd, err := protodesc.NewFile(&fdp, nil)
+	if err != nil {
+		t.Fatalf("build descriptor: %v", err)
+	}
+	return fd
+}
+
+// pagedList serves total records in whatever pages the request asks for.
+type pagedList struct {
+	file  protoreflect.FileDescriptor
+	total int
+	pages []string
+}
+
+func (l *pagedList) Execute(_ context.Context, req *dynamicpb.Message) (*dynamicpb.Message, error) {
+	msgs := l.file.Messages()
+	pagination := req.Get(msgs.ByName("ListRequest").Fields().ByName("pagination")).Message()
+	pFields := msgs.ByName("PaginationRequest").Fields()
+	limit := int(pagination.Get(pFields.ByName("limit")).Int())
+	page := int(pagination.Get(pFields.ByName("offset")).Message().Get(msgs.ByName("OffsetPagination").Fields().ByName("page")).Int())
+	l.pages = append(l.pages, fmt.Sprintf("%d@%d", page, limit))
+
+	respMD := msgs.ByName("ListResponse")
+	resp := dynamicpb.NewMessage(respMD)
+	list := resp.Mutable(respMD.Fields().ByName("record_list")).List()
+	for i := (page - 1) * limit; i < page*limit && i < l.total; i++ {
+		record := list.NewElement()
+		record.Message().Set(msgs.ByName("Record").Fields().ByName("id"), protoreflect.ValueOfString(fmt.Sprintf("r%d", i)))
+		list.Append(record)
+	}
+	// Noise the streamer must not mistake for records.
+	resp.Mutable(respMD.Fields().ByName("search_results")).List().Append(list.NewElement())
+
+	pageResp := resp.Mutable(respMD.Fields().ByName("pagination")).Message()
+	pageResp.Set(msgs.ByName("PaginationResponse").Fields().ByName("has_next"), protoreflect.ValueOfBool(page*limit < l.total))
+	return resp, nil
+}
+
+func TestStreamRecords_PagesThroughList(t *testing.T) {
+	file := listMessages(t)
+	source := &pagedList{file: file, total: 250}
+	handler := NewGenericHandler[*dynamicpb.Message, *dynamicpb.Message](source, dynamicpb.NewMessage(file.Messages().ByName("ListRequest")))
+
+	var ids []string
+	err := handler.StreamRecords(context.Background(), nil, func(record proto.Message) error {
+		m := record.ProtoReflect()
+		ids = append(ids, m.Get(m.Descriptor().Fields().ByName("id")).String())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamRecords: %v", err)
+	}
+	if fmt.Sprint(source.pages) != "[1@100 2@100 3@100]" {
+		t.Fatalf("pages requested = %v", source.pages)
+	}
+	if len(ids) != 250 || ids[0] != "r0" || ids[249] != "r249" {
+		t.Fatalf("streamed %d records, first %q", len(ids), ids[0])
+	}
+}
+
+func TestStreamRecords_RejectsUnpaginatedRequest(t *testing.T) {
+	file := listMessages(t)
+	handler := NewGenericHandler[*dynamicpb.Message, *dynamicpb.Message](&pagedList{file: file}, dynamicpb.NewMessage(file.Messages().ByName("Record")))
+	err := handler.StreamRecords(context.Background(), nil, func(proto.Message) error { return nil })
+	if !errors.Is(err, ErrStreamingUnsupported) {
+		t.Fatalf("err = %v, want ErrStreamingUnsupported", err)
+	}
+}
+
+func TestJSONStreamWriter_Document(t *testing.T) {
+	file := listMessages(t)
+	md := file.Messages().ByName("Record")
+	record := dynamicpb.NewMessage(md)
+	record.Set(md.Fields().ByName("id"), protoreflect.ValueOfString("r1"))
+
+	var buf bytes.Buffer
+	sw := NewJSONStreamWriter(&buf, nil)
+	if err := sw.Write(record); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := sw.Close(errors.New("page 2 failed")); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var doc struct {
+		Data    []json.RawMessage `json:"data"`
+		Count   int               `json:"count"`
+		Success bool              `json:"success"`
+		Error   string            `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("stream is not one JSON document: %v\n%s", err, buf.String())
+	}
+	if len(doc.Data) != 1 || doc.Count != 1 || doc.Success || doc.Error != "page 2 failed" {
+		t.Fatalf("document = %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := NewJSONStreamWriter(&buf, nil).Close(nil); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := buf.String(); got != "{\"data\":[],\"count\":0,\"success\":true}\n" {
+		t.Fatalf("empty stream = %q", got)
+	}
+}
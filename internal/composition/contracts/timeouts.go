@@ -0,0 +1,156 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ============================================================================
+// Route Time Budgets
+// ============================================================================
+
+// DefaultRouteTimeout is the time budget of a route that sets none.
+const DefaultRouteTimeout = 30 * time.Second
+
+// ErrRouteTimeout is returned by ExecuteRoute when a route runs past its
+// time budget. Server adapters answer it with 504 Gateway Timeout.
+var ErrRouteTimeout = errors.New("route timed out")
+
+// routeTimeouts is the budget configuration read from the environment:
+//
+//	CONFIG_ROUTE_TIMEOUT=20s
+//	CONFIG_ROUTE_TIMEOUTS=/integration/tabular/*=10s,/api/subscription/invoice/list=5s
+//
+// CONFIG_ROUTE_TIMEOUT replaces DefaultRouteTimeout. CONFIG_ROUTE_TIMEOUTS
+// overrides single routes by served path; a trailing * matches a prefix and
+// the longest match wins. Overrides beat the Timeout a route declares.
+type routeTimeouts struct {
+	defaultTimeout time.Duration
+	exact          map[string]time.Duration
+	prefixes       []timeoutPrefix // longest first
+}
+
+type timeoutPrefix struct {
+	prefix  string
+	timeout time.Duration
+}
+
+var (
+	envTimeoutsOnce sync.Once
+	envTimeouts     routeTimeouts
+)
+
+func loadRouteTimeouts() routeTimeouts {
+	envTimeoutsOnce.Do(func() {
+		var errs []error
+		envTimeouts, errs = parseRouteTimeouts(os.Getenv("CONFIG_ROUTE_TIMEOUT"), os.Getenv("CONFIG_ROUTE_TIMEOUTS"))
+		for _, err := range errs {
+			log.Printf("⚠️  Warning: ignoring route timeout setting: %v", err)
+		}
+	})
+	return envTimeouts
+}
+
+// parseRouteTimeouts reads the two settings, skipping invalid entries.
+func parseRouteTimeouts(defaultRaw, overridesRaw string) (routeTimeouts, []error) {
+	var errs []error
+	cfg := routeTimeouts{defaultTimeout: DefaultRouteTimeout, exact: make(map[string]time.Duration)}
+
+	if raw := strings.TrimSpace(defaultRaw); raw != "" {
+		if d, err := time.ParseDuration(raw); err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("CONFIG_ROUTE_TIMEOUT=%q is not a positive duration", raw))
+		} else {
+			cfg.defaultTimeout = d
+		}
+	}
+
+	for _, entry := range strings.Split(overridesRaw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		path, raw, ok := strings.Cut(entry, "=")
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if !ok || err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("CONFIG_ROUTE_TIMEOUTS entry %q is not path=duration", entry))
+			continue
+		}
+		path = strings.TrimSpace(path)
+		if prefix, isPrefix := strings.CutSuffix(path, "*"); isPrefix {
+			cfg.prefixes = append(cfg.prefixes, timeoutPrefix{prefix: prefix, timeout: d})
+		} else {
+			cfg.exact[path] = d
+		}
+	}
+	sort.SliceStable(cfg.prefixes, func(i, j int) bool {
+		return len(cfg.prefixes[i].prefix) > len(cfg.prefixes[j].prefix)
+	})
+	return cfg, errs
+}
+
+// budget resolves a route's timeout: environment override, then the
+// route's own Timeout, then the default.
+func (c routeTimeouts) budget(route *Route) time.Duration {
+	if d, ok := c.exact[route.Path]; ok {
+		return d
+	}
+	for _, p := range c.prefixes {
+		if strings.HasPrefix(route.Path, p.prefix) {
+			return p.timeout
+		}
+	}
+	if route.Metadata.Timeout > 0 {
+		return route.Metadata.Timeout
+	}
+	return c.defaultTimeout
+}
+
+// RouteBudget returns the time budget for a route.
+func RouteBudget(route *Route) time.Duration {
+	return loadRouteTimeouts().budget(route)
+}
+
+// WithRouteDeadline derives a context that expires when the route's budget
+// runs out. Database and provider calls made with it are cancelled at the
+// deadline.
+func WithRouteDeadline(ctx context.Context, route *Route) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, RouteBudget(route))
+}
+
+// ExecuteRoute runs the route's handler and returns ErrRouteTimeout as soon
+// as ctx's deadline passes, without waiting for a handler that is stuck in a
+// call that ignores ctx. The abandoned handler finishes in the background
+// against its cancelled context. ctx should come from WithRouteDeadline.
+func ExecuteRoute(ctx context.Context, route *Route, req proto.Message) (proto.Message, error) {
+	type result struct {
+		resp proto.Message
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := route.Handler.Execute(ctx, req)
+		done <- result{resp, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil && errors.Is(r.err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s: %v", ErrRouteTimeout, RouteBudget(route), r.err)
+		}
+		return r.resp, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrRouteTimeout, RouteBudget(route))
+		}
+		return nil, ctx.Err()
+	}
+}
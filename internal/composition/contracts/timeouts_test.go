@@ -0,0 +1,65 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseRouteTimeouts_Resolution(t *testing.T) {
+	cfg, errs := parseRouteTimeouts("20s", "/integration/tabular/*=10s, /integration/tabular/batch*=45s,/api/x/list=5s,bad,/y=-1s")
+	if len(errs) != 2 {
+		t.Fatalf("errs = %v, want the two bad entries", errs)
+	}
+
+	cases := []struct {
+		path    string
+		timeout time.Duration // declared by the route
+		want    time.Duration
+	}{
+		{"/api/x/list", 0, 5 * time.Second},
+		{"/api/x/list", time.Minute, 5 * time.Second}, // override beats the route
+		{"/integration/tabular/read", 15 * time.Second, 10 * time.Second},
+		{"/integration/tabular/batch", 0, 45 * time.Second}, // longest prefix wins
+		{"/api/y/list", 15 * time.Second, 15 * time.Second},
+		{"/api/y/list", 0, 20 * time.Second},
+	}
+	for _, c := range cases {
+		route := &Route{Path: c.path, Metadata: RouteMetadata{Timeout: c.timeout}}
+		if got := cfg.budget(route); got != c.want {
+			t.Errorf("budget(%s, %s) = %s, want %s", c.path, c.timeout, got, c.want)
+		}
+	}
+
+	if cfg, _ := parseRouteTimeouts("soon", ""); cfg.defaultTimeout != DefaultRouteTimeout {
+		t.Fatalf("invalid default replaced DefaultRouteTimeout: %s", cfg.defaultTimeout)
+	}
+}
+
+// stuckHandler blocks in a call that ignores ctx.
+type stuckHandler struct{ release chan struct{} }
+
+func (h stuckHandler) Execute(context.Context, proto.Message) (proto.Message, error) {
+	<-h.release
+	return nil, nil
+}
+
+func TestExecuteRoute_TimesOut(t *testing.T) {
+	h := stuckHandler{release: make(chan struct{})}
+	defer close(h.release)
+	route := &Route{Path: "/slow", Handler: h}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := ExecuteRoute(ctx, route, nil)
+	if !errors.Is(err, ErrRouteTimeout) {
+		t.Fatalf("err = %v, want ErrRouteTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("ExecuteRoute waited %s for a stuck handler", elapsed)
+	}
+}
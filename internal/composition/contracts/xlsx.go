@@ -0,0 +1,183 @@
+package contracts
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// xlsxRows writes a single-sheet XLSX workbook as rows arrive. The fixed
+// parts of the package go first; the worksheet is the last zip entry and is
+// compressed as it is written, so memory stays flat however many rows the
+// export has. Strings are inline (no shared string table, which would have
+// to be complete before the sheet) and the header row is bold and frozen.
+type xlsxRows struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+const (
+	// xlsxMaxRows is the row limit of a worksheet.
+	xlsxMaxRows = 1 << 20
+	// xlsxMaxCellText is the character limit of a cell.
+	xlsxMaxCellText = 32767
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// xlsxStyles has two cell formats: 0 plain and 1 bold for the header.
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
+
+const xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+	`<sheetData>`
+
+const xlsxSheetEnd = `</sheetData></worksheet>`
+
+func newXLSXRows(w io.Writer, sheetName string) (*xlsxRows, error) {
+	zw := zip.NewWriter(w)
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook(sheetName)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return nil, err
+		}
+	}
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	if _, err := sheet.WriteString(xlsxSheetStart); err != nil {
+		return nil, err
+	}
+	return &xlsxRows{zip: zw, sheet: sheet}, nil
+}
+
+func xlsxWorkbook(sheetName string) string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + xmlEscape(xlsxSheetName(sheetName)) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+}
+
+// xlsxSheetName makes name a valid worksheet name: at most 31 characters,
+// none of []:*?/\.
+func xlsxSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if name == "" {
+		return "Export"
+	}
+	return name
+}
+
+func (x *xlsxRows) writeRow(cells []any, header bool) error {
+	if x.row == xlsxMaxRows {
+		return fmt.Errorf("export exceeds the %d rows of an XLSX sheet; filter the list or export CSV", xlsxMaxRows)
+	}
+	x.row++
+	var b strings.Builder
+	b.WriteString(`<row r="`)
+	b.WriteString(strconv.Itoa(x.row))
+	b.WriteString(`">`)
+	style := ""
+	if header {
+		style = ` s="1"`
+	}
+	for _, cell := range cells {
+		switch v := cell.(type) {
+		case nil:
+			b.WriteString(`<c` + style + `/>`)
+		case bool:
+			value := "0"
+			if v {
+				value = "1"
+			}
+			b.WriteString(`<c t="b"` + style + `><v>` + value + `</v></c>`)
+		case float64:
+			b.WriteString(`<c` + style + `><v>` + strconv.FormatFloat(v, 'g', -1, 64) + `</v></c>`)
+		case string:
+			if runes := []rune(v); len(runes) > xlsxMaxCellText {
+				v = string(runes[:xlsxMaxCellText])
+			}
+			b.WriteString(`<c t="inlineStr"` + style + `><is><t xml:space="preserve">`)
+			b.WriteString(xmlEscape(v))
+			b.WriteString(`</t></is></c>`)
+		}
+	}
+	b.WriteString(`</row>`)
+	_, err := x.sheet.WriteString(b.String())
+	return err
+}
+
+func (x *xlsxRows) flush() error {
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Flush()
+}
+
+func (x *xlsxRows) close() error {
+	if _, err := x.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+// xmlEscape escapes text for XML, replacing characters XML cannot carry.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package core
+
+import "database/sql"
+
+// wireRecurringBilling installs the payment providers on the recurring
+// billing and payment reconciliation use cases: billing opens a checkout
+// session for every revenue it generates, reconciliation asks the provider
+// what became of it. The providers are created before the use cases but are
+// not part of the revenue repositories, so they are installed afterwards.
+// Reconciliation also writes its corrections to the audit trail when the
+// database provider has one (postgres builds).
+func (c *Container) wireRecurringBilling() {
+	uc := c.useCases
+	if uc == nil || uc.Revenue == nil || uc.Revenue.Revenue == nil {
+		return
+	}
+	uc.Revenue.Revenue.RunRecurringBilling.SetPaymentProviders(c.services.Payment, c.services.PaymentProviders)
+	uc.Revenue.Revenue.ReconcilePayments.SetPaymentProviders(c.services.Payment, c.services.PaymentProviders)
+
+	if c.providers == nil {
+		return
+	}
+	if dbProvider := c.providers.GetDatabaseProvider(); dbProvider != nil {
+		if connHolder, ok := dbProvider.(interface{ GetConnection() any }); ok {
+			if db, ok := connHolder.GetConnection().(*sql.DB); ok && db != nil {
+				if audit := auditServiceFromDB(db); audit != nil {
+					uc.Revenue.Revenue.ReconcilePayments.SetAuditService(audit)
+				}
+			}
+		}
+	}
+}
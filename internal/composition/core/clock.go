@@ -0,0 +1,61 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// sandboxTimeTravelEnv enables the controllable TravelClock and the
+// /api/sandbox/clock endpoint. Never set it in production: every billing,
+// job-spawn, SLA and workflow timestamp follows the travelled clock.
+const sandboxTimeTravelEnv = "LEAPFOR_SANDBOX_TIME_TRAVEL"
+
+// newPlatformClock returns a TravelClock when sandbox time travel is
+// enabled, otherwise the wall clock.
+func newPlatformClock() ports.Clock {
+	if strings.EqualFold(os.Getenv(sandboxTimeTravelEnv), "true") {
+		fmt.Printf("⏱️ Sandbox time travel enabled (%s) — business clock is controllable\n", sandboxTimeTravelEnv)
+		return ports.NewTravelClock()
+	}
+	return ports.NewSystemClock()
+}
+
+// wireClock installs the platform clock on the use cases whose business
+// logic depends on "now" — revenue recognition, run candidates, recurring
+// billing and payment reconciliation, cyclic job materialization, and the
+// SLA breach sweep. The workflow engine takes the clock at construction
+// (initializeWorkflowEngine).
+//
+// Every target is nil-safe; missing sub-aggregates are skipped.
+func (c *Container) wireClock() {
+	clock := c.services.Clock
+	uc := c.useCases
+	if clock == nil || uc == nil {
+		return
+	}
+
+	if uc.Revenue != nil && uc.Revenue.Revenue != nil {
+		uc.Revenue.Revenue.RecognizeRevenueFromSubscription.SetClock(clock)
+		uc.Revenue.Revenue.ListRevenueRunCandidates.SetClock(clock)
+		uc.Revenue.Revenue.RunRecurringBilling.SetClock(clock)
+		uc.Revenue.Revenue.ReconcilePayments.SetClock(clock)
+	}
+	if uc.Subscription != nil && uc.Subscription.Subscription != nil {
+		uc.Subscription.Subscription.MaterializeJobs.SetClock(clock)
+		uc.Subscription.Subscription.MaterializeInstanceJobs.SetClock(clock)
+	}
+	if uc.Operation != nil && uc.Operation.WorkRequest != nil {
+		uc.Operation.WorkRequest.StampRequestSLABreaches.SetClock(clock)
+	}
+}
+
+// GetClock returns the business clock shared by billing, scheduling and the
+// workflow engine.
+func (c *Container) GetClock() ports.Clock {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.services.Clock
+}
@@ -37,6 +37,7 @@ type RouteManager interface {
 // Platform holds all core infrastructure services with mock defaults
 type Platform struct {
 	Auth           contracts.Service           // Authentication/Authorization service
+	Authorizer     ports.Authorizer            // RBAC authorizer the use cases check permissions with
 	Storage        contracts.Service           // Storage service (files, uploads)
 	Metrics        contracts.Service           // Metrics and monitoring service
 	Logger         contracts.Service           // Logging service
@@ -49,12 +50,15 @@ type Platform struct {
 	Tabular        ports.TabularSourceProvider // Tabular data provider (Google Sheets, etc.)
 	WorkflowEngine        ports.WorkflowEngineService        // Orchestration engine service
 	WorkflowAssigneeQuery ports.WorkflowAssigneeQueryService // Engine identity bridge (read-only)
+	Clock                 ports.Clock                        // Business clock (SystemClock, or TravelClock in sandboxes)
 
 	// Multi-provider registries — all configured providers are active simultaneously.
 	// Legacy single fields above are set to the first provider for backwards compat.
 	PaymentProviders     map[string]ports.PaymentProvider
 	SchedulerProviders   map[string]ports.SchedulerProvider
 	FulfillmentProviders map[string]ports.FulfillmentProvider
+	PushProviders        map[string]ports.PushProvider
+	MessagingProviders   map[string]ports.MessagingProvider
 }
 
 // MockService provides a default mock implementation of the Service interface
@@ -96,6 +100,7 @@ func NewDefaultPlatform() *Platform {
 		// behavior — until Initialize() replaces it with a DB-backed adapter.
 		Transaction: ports.NewNoOpTransactor(),
 		IDGen:       NewMockService("mock-idgen"), // Placeholder - actual ID service created by provider
+		Clock:       newPlatformClock(),
 	}
 }
 
@@ -116,6 +121,9 @@ type Container struct {
 
 	// workflowEngineFactory creates engine on first use (lazy mode only)
 	workflowEngineFactory func() error
+
+	// shutdownTracing flushes spans on Close (see initTracing)
+	shutdownTracing func(context.Context) error
 }
 
 // Config holds the main container configuration.
@@ -254,6 +262,9 @@ func (c *Container) Initialize() error {
 
 	fmt.Printf("📦 Starting container initialization...\n")
 
+	// Start tracing first so provider initialization can be traced
+	c.initTracing()
+
 	// Initialize provider manager (providers read their own config from env)
 	// Table configuration is now obtained from the registry based on active database provider
 	fmt.Printf("🔧 Initializing provider manager...\n")
@@ -334,6 +345,32 @@ func (c *Container) Initialize() error {
 		fmt.Printf("✅ Fulfillment providers initialized: %v\n", names)
 	}
 
+	// Initialize push providers from environment (optional, comma-separated)
+	fmt.Printf("📱 Initializing push providers...\n")
+	if providers, err := integration.CreatePushProviders(); err != nil {
+		fmt.Printf("⚠️ Failed to initialize push providers: %v\n", err)
+	} else if len(providers) > 0 {
+		c.services.PushProviders = providers
+		names := make([]string, 0, len(providers))
+		for name := range providers {
+			names = append(names, name)
+		}
+		fmt.Printf("✅ Push providers initialized: %v\n", names)
+	}
+
+	// Initialize text messaging providers from environment (optional, comma-separated)
+	fmt.Printf("💬 Initializing messaging providers...\n")
+	if providers, err := integration.CreateMessagingProviders(); err != nil {
+		fmt.Printf("⚠️ Failed to initialize messaging providers: %v\n", err)
+	} else if len(providers) > 0 {
+		c.services.MessagingProviders = providers
+		names := make([]string, 0, len(providers))
+		for name := range providers {
+			names = append(names, name)
+		}
+		fmt.Printf("✅ Messaging providers initialized: %v\n", names)
+	}
+
 	// Initialize tabular provider from environment (Google Sheets, etc.)
 	fmt.Printf("📊 Initializing tabular provider...\n")
 	if provider, err := integration.CreateTabularProvider(); err != nil {
@@ -343,6 +380,9 @@ func (c *Container) Initialize() error {
 		fmt.Printf("✅ Tabular provider initialized: %s\n", provider.Name())
 	}
 
+	// Export provider health on /metrics
+	c.registerHealthProbes()
+
 	// Initialize the transaction port from the active DB adapter (provider-agnostic).
 	//
 	// This is the ONE Platform service NewDefaultPlatform leaves as a NoOp:
@@ -389,6 +429,8 @@ func (c *Container) Initialize() error {
 		return fmt.Errorf("failed to initialize use cases: %w", err)
 	}
 	fmt.Printf("✅ Use cases initialized: %v\n", c.useCases != nil)
+	c.wireClock()
+	c.wireRecurringBilling()
 
 	// Initialize workflow engine AFTER use cases are ready
 	if err := c.initializeWorkflowEngine(); err != nil {
@@ -400,6 +442,15 @@ func (c *Container) Initialize() error {
 	if c.config.RoutingConfig == nil {
 		c.config.RoutingConfig = routing.DefaultConfig()
 	}
+	if err := contracts.LoadRateLimitConfig(&c.config.RoutingConfig.RateLimit, os.Getenv); err != nil {
+		fmt.Printf("⚠️  Invalid rate limit configuration: %v\n", err)
+	}
+	if err := contracts.LoadIdempotencyConfig(&c.config.RoutingConfig.Idempotency, os.Getenv); err != nil {
+		fmt.Printf("⚠️  Invalid idempotency configuration: %v\n", err)
+	}
+	if err := contracts.LoadAuthorizationConfig(&c.config.RoutingConfig.Authorization, os.Getenv); err != nil {
+		fmt.Printf("⚠️  Invalid authorization configuration: %v\n", err)
+	}
 
 	// Unlock before creating routing composer since it calls GetUseCases() which needs a read lock
 	c.mu.Unlock()
@@ -452,7 +503,7 @@ func (c *Container) initializeWorkflowEngine() error {
 	case orchcontracts.ModeLate, orchcontracts.ModeEager, "": // Eager and Late are now the same
 		fmt.Printf("🚀 Initializing Workflow Engine (%s binding mode)...\n", c.config.WorkflowEngineMode)
 		engineUC, err := domain.InitializeWorkflowEngine(workflowRepos, authSvc, txSvc, i18nSvc, idSvc,
-			executorRegistry)
+			executorRegistry, c.services.Clock)
 		if err != nil {
 			return err
 		}
@@ -472,7 +523,7 @@ func (c *Container) initializeWorkflowEngine() error {
 				return nil // Already initialized
 			}
 			engineUC, err := domain.InitializeWorkflowEngine(workflowRepos, authSvc, txSvc, i18nSvc, idSvc,
-				executorRegistry)
+				executorRegistry, c.services.Clock)
 			if err != nil {
 				return err
 			}
@@ -644,6 +695,13 @@ func (c *Container) GetSchedulerProvider() ports.SchedulerProvider {
 	return c.services.Scheduler
 }
 
+// GetTabularProvider returns the tabular provider directly
+func (c *Container) GetTabularProvider() ports.TabularSourceProvider {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.services.Tabular
+}
+
 // GetPaymentProviders returns all registered payment providers
 func (c *Container) GetPaymentProviders() map[string]ports.PaymentProvider {
 	c.mu.RLock()
@@ -695,6 +753,20 @@ func (c *Container) GetFulfillmentProviderByName(name string) ports.FulfillmentP
 	return c.services.FulfillmentProviders[name]
 }
 
+// GetPushProviders returns all registered push providers
+func (c *Container) GetPushProviders() map[string]ports.PushProvider {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.services.PushProviders
+}
+
+// GetMessagingProviders returns all registered text messaging providers
+func (c *Container) GetMessagingProviders() map[string]ports.MessagingProvider {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.services.MessagingProviders
+}
+
 // GetDBTableConfig returns the database table configuration directly
 func (c *Container) GetDBTableConfig() *registry.TableConfig {
 	if c.providers == nil {
@@ -813,6 +885,22 @@ func (c *Container) GetUseCases() *usecases.Aggregate {
 	return c.useCases
 }
 
+// GetTransactor returns the transaction port wired from the active database
+// adapter, or the NoOp fallback when the adapter has no transactions.
+func (c *Container) GetTransactor() ports.Transactor {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.services.Transaction
+}
+
+// GetAuthorizer returns the RBAC authorizer the use cases check permissions
+// with, for route-level authorization. It is nil before Initialize.
+func (c *Container) GetAuthorizer() ports.Authorizer {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.services.Authorizer
+}
+
 // GetWorkflowEngine returns the workflow engine service.
 // This is the orchestration engine, managed as a first-class container service.
 func (c *Container) GetWorkflowEngine() ports.WorkflowEngineService {
@@ -955,5 +1043,24 @@ func (c *Container) Close() error {
 		}
 	}
 
+	// Close push providers
+	for name, provider := range c.services.PushProviders {
+		if err := provider.Close(); err != nil {
+			return fmt.Errorf("failed to close push provider %s: %w", name, err)
+		}
+	}
+
+	// Close messaging providers
+	for name, provider := range c.services.MessagingProviders {
+		if err := provider.Close(); err != nil {
+			return fmt.Errorf("failed to close messaging provider %s: %w", name, err)
+		}
+	}
+
+	// Flush buffered trace spans
+	if err := c.closeTracing(); err != nil {
+		return fmt.Errorf("failed to shut down tracing: %w", err)
+	}
+
 	return nil
 }
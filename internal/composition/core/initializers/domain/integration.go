@@ -15,6 +15,9 @@ func InitializeIntegration(
 	schedulerProvider ports.SchedulerProvider,
 	tabularProvider ports.TabularSourceProvider,
 	integrationPaymentRepo integrationPorts.IntegrationPaymentRepository,
+	paymentDisputeRepo integrationPorts.PaymentDisputeRepository,
+	disputeNotifyEmails []string,
+	scheduleInvites ports.ScheduleInviteSender,
 ) *integration.IntegrationUseCases {
 	return integration.NewIntegrationUseCases(
 		paymentProvider,
@@ -22,5 +25,8 @@ func InitializeIntegration(
 		schedulerProvider,
 		tabularProvider,
 		integrationPaymentRepo,
+		paymentDisputeRepo,
+		disputeNotifyEmails,
+		scheduleInvites,
 	)
 }
@@ -136,6 +136,7 @@ func InitializeWorkflowEngine(
 	i18nSvc ports.Translator,
 	idSvc ports.IDGenerator,
 	executorRegistry ports.ExecutorRegistry,
+	clock ports.Clock,
 ) (ports.WorkflowEngineService, error) {
 	engineUC := engineUseCases.NewUseCases(
 		engineUseCases.EngineRepositories{
@@ -152,6 +153,7 @@ func InitializeWorkflowEngine(
 			Translator:       i18nSvc,
 			IDGenerator:      idSvc,
 			ExecutorRegistry: executorRegistry,
+			Clock:            clock,
 		},
 	)
 
@@ -91,6 +91,34 @@ func buildLedgerReportingAdapter(db *sql.DB) any {
 	return factory(db, tableConfig)
 }
 
+// buildStaffWorkloadQuery creates the staff workload aggregate repository
+// from the registry factory. Returns nil when no SQL provider or no factory
+// is available (mock / non-postgres builds).
+func buildStaffWorkloadQuery(db *sql.DB) any {
+	if db == nil {
+		return nil
+	}
+	factory, ok := internalregistry.GetStaffWorkloadQueryFactory()
+	if !ok || factory == nil {
+		return nil
+	}
+	return factory(db)
+}
+
+// buildPaymentFeeQuery creates the payment fee aggregate repository from the
+// registry factory. Returns nil when no SQL provider or no factory is
+// available.
+func buildPaymentFeeQuery(db *sql.DB) any {
+	if db == nil {
+		return nil
+	}
+	factory, ok := internalregistry.GetPaymentFeeQueryFactory()
+	if !ok || factory == nil {
+		return nil
+	}
+	return factory(db)
+}
+
 // initServiceReporting wires the service-layer Reporting umbrella sub-aggregate.
 //
 // 20260614 — the adapter is now built internally via
@@ -114,6 +142,8 @@ func initServiceReporting(
 	actionGate *actiongate.ActionGatekeeper,
 ) *reportingusecases.ReportingUseCases {
 	rawAdapter := buildLedgerReportingAdapter(db)
+	workloadQuery := buildStaffWorkloadQuery(db)
+	paymentFeeQuery := buildPaymentFeeQuery(db)
 
 	reportingDeps := &reportingusecases.Deps{
 		DB:                     db,
@@ -125,6 +155,8 @@ func initServiceReporting(
 		GrossCashFlowReporter:  rawAdapter,
 		StatementsReporter:     rawAdapter,
 		DomainSpecificReporter: rawAdapter,
+		WorkloadReporter:       workloadQuery,
+		PaymentFeesReporter:    paymentFeeQuery,
 	}
 	rpt := reportingusecases.NewReportingUseCases(reportingDeps)
 
@@ -160,5 +192,17 @@ func initServiceReporting(
 		}
 	}
 
+	if workloadQuery != nil && rpt.Workload != nil {
+		if ok := rpt.Workload.SetReporterFromAny(workloadQuery); !ok {
+			log.Printf("WARN: Workload reporter assertion failed; %T does not satisfy workload.reporter — staff workload report will be unavailable. Check postgres PostgresStaffWorkloadRepository method signatures.", workloadQuery)
+		}
+	}
+
+	if paymentFeeQuery != nil && rpt.PaymentFees != nil {
+		if ok := rpt.PaymentFees.SetReporterFromAny(paymentFeeQuery); !ok {
+			log.Printf("WARN: Payment fee reporter assertion failed; %T does not satisfy payment_fees.reporter — payment fee report will be unavailable. Check postgres PostgresPaymentFeeRepository method signatures.", paymentFeeQuery)
+		}
+	}
+
 	return rpt
 }
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	resolveusecases "github.com/erniealice/espyna-golang/internal/application/usecases/service/resolve"
+	internalregistry "github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+// initServiceResolve wires the service-layer Resolve sub-aggregate. dbOps is
+// the generic DatabaseOperation from the container; when it is nil (or does
+// not expose Read) the resolver reports every ID as not found.
+func initServiceResolve(dbOps any, tableConfig *internalregistry.TableConfig, authSvc ports.Authorizer, i18nSvc ports.Translator, actionGate *actiongate.ActionGatekeeper) *resolveusecases.UseCases {
+	reader, _ := dbOps.(resolveusecases.EntityReader)
+	return resolveusecases.NewUseCases(
+		resolveusecases.Repositories{
+			EntityReader: reader,
+			TableName:    tableConfig.TableName,
+		},
+		resolveusecases.Services{
+			Authorizer:       authSvc,
+			Translator:       i18nSvc,
+			ActionGatekeeper: actionGate,
+		},
+	)
+}
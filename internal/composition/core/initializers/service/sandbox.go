@@ -0,0 +1,17 @@
+package service
+
+import (
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	sandboxusecases "github.com/erniealice/espyna-golang/internal/application/usecases/service/sandbox"
+)
+
+// initServiceSandbox wires the service-layer Sandbox sub-aggregate. clock is
+// the platform clock; the sandbox use cases stay unavailable unless it is a
+// TravelClock (LEAPFOR_SANDBOX_TIME_TRAVEL=true).
+func initServiceSandbox(clock ports.Clock, actionGate *actiongate.ActionGatekeeper) *sandboxusecases.UseCases {
+	return sandboxusecases.NewUseCases(sandboxusecases.Services{
+		Clock:            clock,
+		ActionGatekeeper: actionGate,
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/tax/compute_taxes_for_revenue"
 	svcusecases "github.com/erniealice/espyna-golang/internal/application/usecases/service"
 	"github.com/erniealice/espyna-golang/internal/composition/providers/domain"
+	internalregistry "github.com/erniealice/espyna-golang/internal/infrastructure/registry"
 )
 
 // InitializeAll wires every service-driven use case sub-aggregate.
@@ -35,7 +36,9 @@ import (
 // converted from dynamic-registry pattern to typed fields.
 //
 // db may be nil when no SQL provider is in play; in that case the use
-// cases degrade gracefully (return empty responses).
+// cases degrade gracefully (return empty responses). dbOps is the
+// container's generic DatabaseOperation (any provider) used by Resolve.
+// clock is the platform clock driven by the Sandbox sub-aggregate.
 //
 // Note: the entityAuth *entityauth.UseCases parameter from the OLD
 // InitializeService signature is REMOVED — Option B builds it internally
@@ -58,6 +61,9 @@ func InitializeAll(
 	fulfillmentRepos *domain.FulfillmentRepositories,
 	scheduleEntityDash *eventdashboard.GetScheduleDashboardPageDataUseCase,
 	entityComputeTaxes *compute_taxes_for_revenue.ComputeTaxesForRevenueUseCase,
+	dbOps any,
+	tableConfig *internalregistry.TableConfig,
+	clock ports.Clock,
 ) (*svcusecases.ServiceUseCases, error) {
 	auditUC := initServiceAudit(db, authSvc, i18nSvc, actionGate)
 	securityUC := initServiceSecurity(db, i18nSvc)
@@ -70,6 +76,10 @@ func InitializeAll(
 	taxUC := initServiceTax(entityComputeTaxes)
 	// Amortization (20260604 v1) — pure computation service.
 	amortUC := initServiceAmortization()
+	// Global ID resolution — probes entity tables through the generic ops.
+	resolveUC := initServiceResolve(dbOps, tableConfig, authSvc, i18nSvc, actionGate)
+	// Sandbox clock control — inert unless the platform clock is a TravelClock.
+	sandboxUC := initServiceSandbox(clock, actionGate)
 
-	return svcusecases.NewServiceUseCases(auditUC, securityUC, authUC, dashboardUC, reportingUC, performanceUC, taxUC, amortUC), nil
+	return svcusecases.NewServiceUseCases(auditUC, securityUC, authUC, dashboardUC, reportingUC, performanceUC, taxUC, amortUC, resolveUC, sandboxUC), nil
 }
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"context"
+	"errors"
+
+	"github.com/erniealice/espyna-golang/shared/metrics"
+)
+
+var errTabularDisabled = errors.New("tabular provider is disabled")
+
+// registerHealthProbes exports the health of the database and of the
+// payment, scheduler and tabular providers as the espyna_provider_up gauge.
+// Probes run when /metrics is scraped, never on the request path.
+func (c *Container) registerHealthProbes() {
+	if db := c.providers.GetDatabaseProvider(); db != nil {
+		metrics.RegisterProbe("database", db.Name(), db.Health)
+	}
+	for name, p := range c.services.PaymentProviders {
+		metrics.RegisterProbe("payment", name, p.IsHealthy)
+	}
+	for name, p := range c.services.SchedulerProviders {
+		metrics.RegisterProbe("scheduler", name, p.IsHealthy)
+	}
+	if t := c.services.Tabular; t != nil {
+		metrics.RegisterProbe("tabular", t.Name(), func(ctx context.Context) error {
+			if !t.IsEnabled() {
+				return errTabularDisabled
+			}
+			return t.IsHealthy(ctx)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/erniealice/espyna-golang/shared/tracing"
+)
+
+// tracingShutdownTimeout bounds flushing buffered spans on Close.
+const tracingShutdownTimeout = 5 * time.Second
+
+// initTracing starts exporting spans when the binary is built with the otel
+// tag and an OTLP endpoint is configured (see shared/tracing). A failure
+// leaves tracing off rather than failing the container.
+func (c *Container) initTracing() {
+	shutdown, err := tracing.Setup(context.Background())
+	if err != nil {
+		fmt.Printf("⚠️ Failed to initialize tracing: %v\n", err)
+		return
+	}
+	c.shutdownTracing = shutdown
+	if tracing.Enabled() {
+		fmt.Printf("✅ OpenTelemetry tracing enabled\n")
+	}
+}
+
+// closeTracing flushes buffered spans.
+func (c *Container) closeTracing() error {
+	if c.shutdownTracing == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+	err := c.shutdownTracing(ctx)
+	c.shutdownTracing = nil
+	return err
+}
@@ -22,6 +22,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/erniealice/espyna-golang/internal/composition/providers"
 
@@ -37,6 +38,8 @@ import (
 	mockAuth "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/auth/mock"
 	// Production (non-mock) RBAC Authorizer — the Layer-4 use-case backstop.
 	rbacauth "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/auth/rbac"
+	dbifaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	scheduleinvite "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/scheduler/invite"
 	internalregistry "github.com/erniealice/espyna-golang/internal/infrastructure/registry"
 
 	// Domain use cases (for proper initialization)
@@ -52,6 +55,8 @@ import (
 	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/fulfillment"
 	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/funding"
 	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/integration"
+	schedulerUseCase "github.com/erniealice/espyna-golang/internal/application/usecases/domain/integration/scheduler"
+	tabularUseCase "github.com/erniealice/espyna-golang/internal/application/usecases/domain/integration/tabular"
 	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/inventory"
 	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/ledger"
 	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/operation"
@@ -267,6 +272,17 @@ func (uci *UseCaseInitializer) InitializeAll(container *Container) error {
 	// These are provider-based use cases, not domain-based
 	integrationUC := uci.initializeIntegrationUseCases(container)
 
+	// Schedule history import writes clients and events, so it is wired
+	// here once the entity domain is up rather than by NewIntegrationUseCases.
+	if integrationUC != nil && integrationUC.Scheduler != nil {
+		integrationUC.Scheduler.ImportSchedules = uci.initializeScheduleImport(container, entityUC)
+	}
+	// Tabular entity import writes through the entity create use cases for
+	// the same reason.
+	if integrationUC != nil && integrationUC.Tabular != nil {
+		integrationUC.Tabular.ImportEntities = uci.initializeTabularImport(container, entityUC)
+	}
+
 	// 20260518-hexagonal-strict-adherence Phase 1.D — service-driven
 	// use cases (audit query; reporting; auth; security per Q7).
 	// Resolves the raw *sql.DB from the database provider so the audit
@@ -1134,6 +1150,8 @@ func (uci *UseCaseInitializer) getServices(container *Container) (
 	//      so getServices propagates a hard boot-fail (Q-AWS2 = C). This makes
 	//      the "silently booted with allow-all" state impossible for a
 	//      password / non-dev build.
+	//   4. Whichever was chosen, an auth provider with its own machine
+	//      principals (the apikey provider) wraps it to answer for those.
 
 	// 1. Provider already an Authorizer?
 	if authProvider := uci.providerManager.GetAuthProvider(); authProvider != nil {
@@ -1163,6 +1181,26 @@ func (uci *UseCaseInitializer) getServices(container *Container) (
 		}
 	}
 
+	// 4. An auth provider issuing its own machine principals (API keys)
+	//    answers for them and leaves everyone else to authSvc. It reads
+	//    the principals from the active database.
+	if authProvider := uci.providerManager.GetAuthProvider(); authProvider != nil {
+		var raw any = authProvider
+		if w, ok := authProvider.(interface{ Provider() interface{} }); ok {
+			raw = w.Provider()
+		}
+		if scoper, ok := raw.(interface {
+			SetOperations(ops dbifaces.DatabaseOperation)
+			ScopeAuthorizer(inner ports.Authorizer) ports.Authorizer
+		}); ok {
+			if ops, ok := container.GetDatabaseOperations().(dbifaces.DatabaseOperation); ok && ops != nil {
+				scoper.SetOperations(ops)
+			}
+			authSvc = scoper.ScopeAuthorizer(authSvc)
+			fmt.Printf("🔐 API key principals scoped by the auth provider: %T\n", raw)
+		}
+	}
+
 	// Get ID service from provider manager
 	if idProvider := uci.providerManager.GetIDProvider(); idProvider != nil {
 		// Check if the provider has a GetIDService method (IDProviderWrapper)
@@ -1184,6 +1222,12 @@ func (uci *UseCaseInitializer) getServices(container *Container) (
 		fmt.Printf("🆔 Created NoOp ID service (no provider): %T\n", idSvc)
 	}
 
+	// Keep the first authorizer for route-level authorization, so routes
+	// and use cases check the same permissions.
+	if container.services.Authorizer == nil {
+		container.services.Authorizer = authSvc
+	}
+
 	txSvc, _ = container.services.Transaction.(ports.Transactor)
 
 	// P6 (E5): translation provider system retired. Use the port-level NoOp
@@ -1247,6 +1291,67 @@ func allowAllFallbackPermitted() bool {
 	return os.Getenv("CONFIG_AUTH_PROVIDER") == "mock"
 }
 
+// resolvePaymentDisputeRepository builds the dispute repository from the
+// registry factory. Returns nil for non-SQL providers or when no adapter
+// registered a factory. Same DB-handle extraction as resolvePermissionQuery.
+func (uci *UseCaseInitializer) resolvePaymentDisputeRepository() ports.PaymentDisputeRepository {
+	var sqlDB *sql.DB
+	if dbProvider := uci.providerManager.GetDatabaseProvider(); dbProvider != nil {
+		if connHolder, ok := dbProvider.(interface{ GetConnection() any }); ok {
+			if conn := connHolder.GetConnection(); conn != nil {
+				if db, ok := conn.(*sql.DB); ok {
+					sqlDB = db
+				}
+			}
+		}
+	}
+	if sqlDB == nil {
+		return nil
+	}
+	factory, ok := internalregistry.GetPaymentDisputeRepositoryFactory()
+	if !ok || factory == nil {
+		return nil
+	}
+	if repo, ok := factory(sqlDB).(ports.PaymentDisputeRepository); ok {
+		return repo
+	}
+	return nil
+}
+
+// resolveScheduleInviteSender builds the .ics invite sender when
+// LEAPFOR_INTEGRATION_SCHEDULER_SEND_INVITES is enabled and both a scheduler
+// and an email provider are configured. Invites are recorded through the
+// database operations when there are any, otherwise in memory.
+func (uci *UseCaseInitializer) resolveScheduleInviteSender(container *Container, emailProvider ports.EmailProvider, schedulerProvider ports.SchedulerProvider) ports.ScheduleInviteSender {
+	if os.Getenv("LEAPFOR_INTEGRATION_SCHEDULER_SEND_INVITES") != "true" || schedulerProvider == nil {
+		return nil
+	}
+	if emailProvider == nil {
+		fmt.Printf("⚠️  Schedule invites enabled but no email provider is configured\n")
+		return nil
+	}
+	ops, _ := container.GetDatabaseOperations().(dbifaces.DatabaseOperation)
+	if ops == nil {
+		fmt.Printf("⚠️  No database operations for schedule invites; invite sequences are kept in memory\n")
+	}
+	fmt.Printf("📨 Schedule invites enabled\n")
+	return scheduleinvite.NewSender(emailProvider, ops, scheduleinvite.Config{
+		OrganizerEmail: os.Getenv("LEAPFOR_INTEGRATION_SCHEDULER_INVITE_ORGANIZER_EMAIL"),
+		OrganizerName:  os.Getenv("LEAPFOR_INTEGRATION_SCHEDULER_INVITE_ORGANIZER_NAME"),
+	})
+}
+
+// splitEmailList parses a comma-separated list of addresses, dropping blanks.
+func splitEmailList(v string) []string {
+	var out []string
+	for _, addr := range strings.Split(v, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			out = append(out, addr)
+		}
+	}
+	return out
+}
+
 // initializeIntegrationUseCases initializes integration use cases (email, payment, scheduler providers)
 // These are external provider integrations, not domain-based use cases
 func (uci *UseCaseInitializer) initializeIntegrationUseCases(container *Container) *integration.IntegrationUseCases {
@@ -1290,8 +1395,18 @@ func (uci *UseCaseInitializer) initializeIntegrationUseCases(container *Containe
 		}
 	}
 
+	// Dispute tracking needs the postgres dispute repository; without it
+	// dispute webhooks are still logged but not recorded or flagged.
+	paymentDisputeRepo := uci.resolvePaymentDisputeRepository()
+	if paymentProvider != nil && paymentDisputeRepo == nil {
+		fmt.Printf("⚠️  No payment dispute repository; dispute webhooks will not be tracked\n")
+	}
+	disputeNotifyEmails := splitEmailList(os.Getenv("LEAPFOR_INTEGRATION_PAYMENT_DISPUTE_NOTIFY_EMAILS"))
+
+	scheduleInvites := uci.resolveScheduleInviteSender(container, emailProvider, schedulerProvider)
+
 	// Create integration use cases with available providers
-	integrationUC := integration.NewIntegrationUseCases(paymentProvider, emailProvider, schedulerProvider, tabularProvider, integrationPaymentRepo)
+	integrationUC := integration.NewIntegrationUseCases(paymentProvider, emailProvider, schedulerProvider, tabularProvider, integrationPaymentRepo, paymentDisputeRepo, disputeNotifyEmails, scheduleInvites)
 
 	if integrationUC != nil {
 		routeCount := 0
@@ -1316,6 +1431,77 @@ func (uci *UseCaseInitializer) initializeIntegrationUseCases(container *Containe
 	return integrationUC
 }
 
+// initializeScheduleImport builds the scheduler history importer over the
+// entity and event repositories. Missing clients are created through the
+// entity CreateClient use case so they get its defaults and checks.
+func (uci *UseCaseInitializer) initializeScheduleImport(container *Container, entityUC *entity.EntityUseCases) *schedulerUseCase.ImportSchedulesUseCase {
+	dbProvider := uci.providerManager.GetDatabaseProvider()
+	tableConfig := uci.providerManager.GetDBTableConfig()
+	entityRepos, err := repodomain.NewEntityRepositories(dbProvider, tableConfig)
+	if err != nil {
+		fmt.Printf("⚠️  Schedule import unavailable: %v\n", err)
+		return nil
+	}
+	eventRepos, err := repodomain.NewEventRepositories(dbProvider, tableConfig)
+	if err != nil {
+		fmt.Printf("⚠️  Schedule import unavailable: %v\n", err)
+		return nil
+	}
+	authSvc, _, i18nSvc, idSvc, err := uci.getServices(container)
+	if err != nil {
+		fmt.Printf("⚠️  Schedule import unavailable: %v\n", err)
+		return nil
+	}
+
+	services := schedulerUseCase.ImportSchedulesServices{
+		Provider:         container.services.Scheduler,
+		ActionGatekeeper: actiongate.NewActionGatekeeper(authSvc, i18nSvc),
+		IDGenerator:      idSvc,
+	}
+	if entityUC != nil && entityUC.Client != nil && entityUC.Client.CreateClient != nil {
+		services.CreateClient = entityUC.Client.CreateClient
+	}
+	fmt.Printf("📥 Schedule import wired (client creation: %v)\n", services.CreateClient != nil)
+
+	return schedulerUseCase.NewImportSchedulesUseCase(schedulerUseCase.ImportSchedulesRepositories{
+		Client:      entityRepos.Client,
+		User:        entityRepos.User,
+		Event:       eventRepos.Event,
+		EventClient: eventRepos.EventClient,
+	}, services)
+}
+
+// initializeTabularImport builds the tabular entity importer. Rows are
+// created through the entity create use cases, so an entity whose use case
+// is missing cannot be imported.
+func (uci *UseCaseInitializer) initializeTabularImport(container *Container, entityUC *entity.EntityUseCases) *tabularUseCase.ImportEntitiesUseCase {
+	authSvc, _, i18nSvc, _, err := uci.getServices(container)
+	if err != nil {
+		fmt.Printf("⚠️  Tabular import unavailable: %v\n", err)
+		return nil
+	}
+
+	services := tabularUseCase.ImportEntitiesServices{
+		Provider:         container.services.Tabular,
+		ActionGatekeeper: actiongate.NewActionGatekeeper(authSvc, i18nSvc),
+	}
+	if entityUC != nil {
+		if entityUC.Client != nil && entityUC.Client.CreateClient != nil {
+			services.CreateClient = entityUC.Client.CreateClient
+		}
+		if entityUC.Staff != nil && entityUC.Staff.CreateStaff != nil {
+			services.CreateStaff = entityUC.Staff.CreateStaff
+		}
+		if entityUC.Location != nil && entityUC.Location.CreateLocation != nil {
+			services.CreateLocation = entityUC.Location.CreateLocation
+		}
+	}
+	fmt.Printf("📥 Tabular import wired (client: %v, staff: %v, location: %v)\n",
+		services.CreateClient != nil, services.CreateStaff != nil, services.CreateLocation != nil)
+
+	return tabularUseCase.NewImportEntitiesUseCase(tabularUseCase.ImportEntitiesRepositories{}, services)
+}
+
 // materializeBillingEventsAdapter adapts the MaterializeBillingEventsForJob
 // use case to the narrow MaterializeBillingEventsForJobInvoker interface
 // consumed by MaterializeJobsForSubscription (plan §3.7). The adapter
@@ -1485,7 +1671,7 @@ func (uci *UseCaseInitializer) initializeServiceUseCases(container *Container, e
 		}
 	}
 
-	svcUC, err := initservice.InitializeAll(sqlDB, authSvc, i18nSvc, txSvc, idSvc, actiongate.NewActionGatekeeper(authSvc, i18nSvc), entityRepos, ledgerReposForSvc, payrollReposForSvc, treasuryReposForSvc, expenditureReposForSvc, operationReposForSvc, productReposForSvc, fulfillmentReposForSvc, scheduleEntityDash, entityComputeTaxes)
+	svcUC, err := initservice.InitializeAll(sqlDB, authSvc, i18nSvc, txSvc, idSvc, actiongate.NewActionGatekeeper(authSvc, i18nSvc), entityRepos, ledgerReposForSvc, payrollReposForSvc, treasuryReposForSvc, expenditureReposForSvc, operationReposForSvc, productReposForSvc, fulfillmentReposForSvc, scheduleEntityDash, entityComputeTaxes, container.GetDatabaseOperations(), uci.providerManager.GetDBTableConfig(), container.services.Clock)
 	if err != nil {
 		fmt.Printf("❌ Failed to initialize service-driven use cases: %v\n", err)
 		return &service.ServiceUseCases{}, err
@@ -16,24 +16,28 @@ import (
 //   - "password" → Password/session auth provider
 //   - "mock"     → Mock auth provider (dev/test)
 //   - "firebase" → Firebase Auth provider
+//   - "apikey"   → API keys, in front of CONFIG_APIKEY_FALLBACK_PROVIDER
+//     (registered under -tags apikey_auth)
+//   - "jwt"      → Self-issued access/refresh tokens against the user table
+//     (registered under -tags jwt_auth)
 //
-// Retired aliases (db_auth, mock_auth, noop, jwt, etc.) return an error
+// Retired aliases (db_auth, mock_auth, noop, jwt_auth, etc.) return an error
 // directing the operator to the canonical token.
 func CreateAuthProvider() (contracts.Provider, error) {
 	providerName := strings.ToLower(os.Getenv("CONFIG_AUTH_PROVIDER"))
 
 	// Canonical tokens only — no alias normalization.
 	switch providerName {
-	case "password", "mock", "firebase":
+	case "password", "mock", "firebase", "jwt":
 		// accepted as-is
 	case "db_auth":
 		// One-release deprecation: map to "password" with a warning.
 		fmt.Fprintf(os.Stderr, "DEPRECATED: CONFIG_AUTH_PROVIDER=db_auth is retired — use 'password' instead\n")
 		providerName = "password"
-	case "password_auth", "firebase_auth", "mock_auth", "noop", "jwt", "jwt_auth":
-		return nil, fmt.Errorf("CONFIG_AUTH_PROVIDER=%q is retired; use one of: password, mock, firebase", providerName)
+	case "password_auth", "firebase_auth", "mock_auth", "noop", "jwt_auth":
+		return nil, fmt.Errorf("CONFIG_AUTH_PROVIDER=%q is retired; use one of: password, mock, firebase, jwt", providerName)
 	case "":
-		return nil, fmt.Errorf("CONFIG_AUTH_PROVIDER is empty; set one of: password, mock, firebase")
+		return nil, fmt.Errorf("CONFIG_AUTH_PROVIDER is empty; set one of: password, mock, firebase, jwt")
 	default:
 		// Future providers (oidc, etc.) pass through to the registry.
 	}
@@ -0,0 +1,135 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/composition/contracts"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+// CacheProviderAdapter wraps a Cache to implement the contracts.Provider interface
+type CacheProviderAdapter struct {
+	cache  ports.Cache
+	policy registry.CachePolicy
+	name   string
+}
+
+// NewCacheProviderAdapter creates a new CacheProviderAdapter
+func NewCacheProviderAdapter(cache ports.Cache, policy registry.CachePolicy, name string) *CacheProviderAdapter {
+	return &CacheProviderAdapter{
+		cache:  cache,
+		policy: policy,
+		name:   name,
+	}
+}
+
+// Type returns the provider type
+func (p *CacheProviderAdapter) Type() contracts.ProviderType {
+	return contracts.ProviderTypeCache
+}
+
+// Name returns the provider name
+func (p *CacheProviderAdapter) Name() string {
+	return p.name
+}
+
+// Initialize initializes the provider
+func (p *CacheProviderAdapter) Initialize(config interface{}) error {
+	return nil
+}
+
+// Health checks provider health with a read of a key that never exists
+func (p *CacheProviderAdapter) Health(ctx context.Context) error {
+	if p.cache == nil {
+		return fmt.Errorf("cache not initialized")
+	}
+	_, _, err := p.cache.Get(ctx, "espyna:health")
+	return err
+}
+
+// Close closes the provider and releases resources
+func (p *CacheProviderAdapter) Close() error {
+	if p.cache == nil {
+		return nil
+	}
+	return p.cache.Close()
+}
+
+// GetCache returns the underlying cache
+func (p *CacheProviderAdapter) GetCache() ports.Cache {
+	return p.cache
+}
+
+// GetPolicy returns the per-table caching policy
+func (p *CacheProviderAdapter) GetPolicy() registry.CachePolicy {
+	return p.policy
+}
+
+// CreateCacheProvider creates the database result cache from the environment.
+// Caching is optional: with CONFIG_CACHE_PROVIDER unset it returns nil, nil.
+//
+//   - CONFIG_CACHE_PROVIDER: "redis" (requires the redis_cache build tag) or
+//     "mock_cache" (in-memory, requires the mock_cache build tag)
+//   - CONFIG_CACHE_DEFAULT_TTL: TTL for tables not listed below, e.g. "30s".
+//     Unset or 0 caches only the listed tables.
+//   - CONFIG_CACHE_TABLE_TTLS: per-table TTLs, e.g. "client=5m,product=1m,
+//     journal_entry=0" (0 leaves a table out)
+func CreateCacheProvider() (contracts.Provider, error) {
+	providerName := strings.ToLower(strings.TrimSpace(os.Getenv("CONFIG_CACHE_PROVIDER")))
+	if providerName == "" {
+		return nil, nil
+	}
+
+	policy, err := LoadCachePolicy(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := registry.BuildCacheProviderFromEnv(providerName)
+	if err != nil {
+		return nil, fmt.Errorf("cache provider %q not registered (is the build tag present?): %w", providerName, err)
+	}
+
+	fmt.Printf("🗄️  Created cache provider: %s (default TTL %s, %d table override(s))\n",
+		cache.Name(), policy.DefaultTTL, len(policy.TableTTLs))
+
+	return NewCacheProviderAdapter(cache, policy, providerName), nil
+}
+
+// LoadCachePolicy reads CONFIG_CACHE_DEFAULT_TTL and CONFIG_CACHE_TABLE_TTLS.
+func LoadCachePolicy(getenv func(string) string) (registry.CachePolicy, error) {
+	var policy registry.CachePolicy
+
+	if raw := strings.TrimSpace(getenv("CONFIG_CACHE_DEFAULT_TTL")); raw != "" {
+		ttl, err := time.ParseDuration(raw)
+		if err != nil || ttl < 0 {
+			return policy, fmt.Errorf("CONFIG_CACHE_DEFAULT_TTL=%q is not a duration like 30s", raw)
+		}
+		policy.DefaultTTL = ttl
+	}
+
+	raw := strings.TrimSpace(getenv("CONFIG_CACHE_TABLE_TTLS"))
+	if raw == "" {
+		return policy, nil
+	}
+	policy.TableTTLs = make(map[string]time.Duration)
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		table, value, ok := strings.Cut(item, "=")
+		table = strings.TrimSpace(table)
+		ttl, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || table == "" || err != nil || ttl < 0 {
+			return policy, fmt.Errorf("CONFIG_CACHE_TABLE_TTLS entry %q is not table=duration", item)
+		}
+		policy.TableTTLs[table] = ttl
+	}
+	return policy, nil
+}
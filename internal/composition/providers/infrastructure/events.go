@@ -0,0 +1,113 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/composition/contracts"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/events"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+// eventsDrainTimeout bounds how long Close waits for queued events to reach
+// the broker.
+const eventsDrainTimeout = 10 * time.Second
+
+// EventPublisherProviderAdapter wraps an EventPublisher and the forwarder
+// feeding it to implement the contracts.Provider interface
+type EventPublisherProviderAdapter struct {
+	publisher ports.EventPublisher
+	forwarder *events.Forwarder
+	name      string
+}
+
+// NewEventPublisherProviderAdapter creates a new EventPublisherProviderAdapter
+// and starts forwarding to publisher
+func NewEventPublisherProviderAdapter(publisher ports.EventPublisher, name string) *EventPublisherProviderAdapter {
+	return &EventPublisherProviderAdapter{
+		publisher: publisher,
+		forwarder: events.NewForwarder(publisher, 0, 0),
+		name:      name,
+	}
+}
+
+// Type returns the provider type
+func (p *EventPublisherProviderAdapter) Type() contracts.ProviderType {
+	return contracts.ProviderTypeMessage
+}
+
+// Name returns the provider name
+func (p *EventPublisherProviderAdapter) Name() string {
+	return p.name
+}
+
+// Initialize initializes the provider
+func (p *EventPublisherProviderAdapter) Initialize(config interface{}) error {
+	return nil
+}
+
+// Health reports failed publishes; the broker clients reconnect on their
+// own, so there is nothing to probe
+func (p *EventPublisherProviderAdapter) Health(ctx context.Context) error {
+	if p.publisher == nil {
+		return fmt.Errorf("event publisher not initialized")
+	}
+	if stats := p.forwarder.Stats(); stats.Failed > 0 || stats.Dropped > 0 {
+		return fmt.Errorf("event publisher %s: %d failed and %d dropped event(s)", p.name, stats.Failed, stats.Dropped)
+	}
+	return nil
+}
+
+// Close publishes the queued events, then closes the publisher
+func (p *EventPublisherProviderAdapter) Close() error {
+	if p.publisher == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), eventsDrainTimeout)
+	defer cancel()
+	drainErr := p.forwarder.Close(ctx)
+	if err := p.publisher.Close(); err != nil {
+		return err
+	}
+	if drainErr != nil {
+		return fmt.Errorf("queued events not published: %w", drainErr)
+	}
+	return nil
+}
+
+// GetPublisher returns the underlying publisher
+func (p *EventPublisherProviderAdapter) GetPublisher() ports.EventPublisher {
+	return p.publisher
+}
+
+// GetForwarder returns the domain event sink feeding the publisher
+func (p *EventPublisherProviderAdapter) GetForwarder() *events.Forwarder {
+	return p.forwarder
+}
+
+// CreateEventPublisherProvider creates the domain event publisher from the
+// environment. Publishing is optional: with CONFIG_EVENTS_PROVIDER unset it
+// returns nil, nil.
+//
+//   - CONFIG_EVENTS_PROVIDER: "pubsub" (Google Pub/Sub, requires the pubsub
+//     build tag) or "mock_events" (in-memory, requires the mock_events
+//     build tag)
+func CreateEventPublisherProvider() (contracts.Provider, error) {
+	providerName := strings.ToLower(strings.TrimSpace(os.Getenv("CONFIG_EVENTS_PROVIDER")))
+	if providerName == "" {
+		return nil, nil
+	}
+
+	publisher, err := registry.BuildEventPublisherFromEnv(providerName)
+	if err != nil {
+		return nil, fmt.Errorf("event publisher %q not registered (is the build tag present?): %w", providerName, err)
+	}
+
+	fmt.Printf("📣 Created event publisher: %s\n", publisher.Name())
+
+	return NewEventPublisherProviderAdapter(publisher, providerName), nil
+}
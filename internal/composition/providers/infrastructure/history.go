@@ -0,0 +1,28 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+// LoadHistoryPolicy reads which tables keep a revision history. History is
+// optional: with CONFIG_HISTORY_TABLES unset no table does.
+//
+//   - CONFIG_HISTORY_TABLES: tables whose writes are recorded, e.g.
+//     "client,subscription,workflow_instance"
+//   - CONFIG_HISTORY_REVISION_TABLE: the table revisions are stored in
+//     (default entity_revision)
+//
+// The policy is published with registry.SetDefaultHistory.
+func LoadHistoryPolicy(getenv func(string) string) (registry.HistoryPolicy, error) {
+	policy := registry.HistoryPolicy{RevisionTable: strings.TrimSpace(getenv("CONFIG_HISTORY_REVISION_TABLE"))}
+	if tables := shadowList(getenv("CONFIG_HISTORY_TABLES")); len(tables) > 0 {
+		policy.Tables = tables
+	}
+	if policy.Tables[policy.Table()] {
+		return policy, fmt.Errorf("CONFIG_HISTORY_TABLES names the revision table %q", policy.Table())
+	}
+	return policy, nil
+}
@@ -0,0 +1,117 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/composition/contracts"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+// CreateShadowDatabase connects the candidate database that sampled reads
+// are replayed against, to compare it with the primary before a cutover.
+// Shadowing is optional: with CONFIG_DATABASE_SHADOW_PROVIDER unset it
+// returns a nil provider.
+//
+//   - CONFIG_DATABASE_SHADOW_PROVIDER: the candidate, e.g. "postgresql"
+//     while primary is "firestore". It reads its own settings (POSTGRES_*,
+//     FIRESTORE_*) like a primary would.
+//   - CONFIG_DATABASE_SHADOW_SAMPLE_RATE: the fraction of reads replayed,
+//     0 to 1 (default 1)
+//   - CONFIG_DATABASE_SHADOW_TABLES: tables to shadow, e.g. "client,invoice"
+//     (default every table)
+//   - CONFIG_DATABASE_SHADOW_TIMEOUT: bound on each candidate read (default
+//     5s)
+//   - CONFIG_DATABASE_SHADOW_MAX_IN_FLIGHT: concurrent candidate reads
+//     before further reads are skipped (default 32)
+//   - CONFIG_DATABASE_SHADOW_IGNORE_FIELDS: fields left out of comparisons,
+//     e.g. "date_created,date_modified"
+//
+// The returned operations are the candidate's DatabaseOperation, to publish
+// with registry.SetDefaultShadow; the provider must be closed on shutdown.
+func CreateShadowDatabase(primary string) (contracts.Provider, any, registry.ShadowPolicy, error) {
+	candidateName := strings.ToLower(strings.TrimSpace(os.Getenv("CONFIG_DATABASE_SHADOW_PROVIDER")))
+	if candidateName == "" {
+		return nil, nil, registry.ShadowPolicy{}, nil
+	}
+	if candidateName == strings.ToLower(primary) {
+		return nil, nil, registry.ShadowPolicy{}, fmt.Errorf("CONFIG_DATABASE_SHADOW_PROVIDER=%q is the primary database provider", candidateName)
+	}
+
+	policy, err := LoadShadowPolicy(os.Getenv)
+	if err != nil {
+		return nil, nil, policy, err
+	}
+	policy.Candidate = candidateName
+
+	candidate, err := registry.BuildDatabaseProviderFromEnv(candidateName)
+	if err != nil {
+		return nil, nil, policy, fmt.Errorf("shadow database provider %q (is the build tag present?): %w", candidateName, err)
+	}
+	ops, err := registry.CreateDatabaseOperations(candidateName, candidate.GetConnection())
+	if err != nil {
+		_ = candidate.Close()
+		return nil, nil, policy, fmt.Errorf("shadow database provider %q: %w", candidateName, err)
+	}
+
+	fmt.Printf("🪞 Shadowing %s reads on %s (sample rate %g, %s)\n",
+		primary, candidateName, policy.SampleRate, shadowTablesLabel(policy))
+
+	return NewProviderWrapper(candidate, contracts.ProviderTypeDatabase), ops, policy, nil
+}
+
+// LoadShadowPolicy reads the CONFIG_DATABASE_SHADOW_* sampling and
+// comparison settings.
+func LoadShadowPolicy(getenv func(string) string) (registry.ShadowPolicy, error) {
+	policy := registry.ShadowPolicy{SampleRate: 1}
+
+	if raw := strings.TrimSpace(getenv("CONFIG_DATABASE_SHADOW_SAMPLE_RATE")); raw != "" {
+		rate, err := strconv.ParseFloat(raw, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return policy, fmt.Errorf("CONFIG_DATABASE_SHADOW_SAMPLE_RATE=%q is not a fraction between 0 and 1", raw)
+		}
+		policy.SampleRate = rate
+	}
+	if raw := strings.TrimSpace(getenv("CONFIG_DATABASE_SHADOW_TIMEOUT")); raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			return policy, fmt.Errorf("CONFIG_DATABASE_SHADOW_TIMEOUT=%q is not a duration like 5s", raw)
+		}
+		policy.Timeout = timeout
+	}
+	if raw := strings.TrimSpace(getenv("CONFIG_DATABASE_SHADOW_MAX_IN_FLIGHT")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return policy, fmt.Errorf("CONFIG_DATABASE_SHADOW_MAX_IN_FLIGHT=%q is not a positive number", raw)
+		}
+		policy.MaxInFlight = n
+	}
+	if tables := shadowList(getenv("CONFIG_DATABASE_SHADOW_TABLES")); len(tables) > 0 {
+		policy.Tables = tables
+	}
+	if fields := shadowList(getenv("CONFIG_DATABASE_SHADOW_IGNORE_FIELDS")); len(fields) > 0 {
+		policy.IgnoreFields = fields
+	}
+	return policy, nil
+}
+
+// shadowList reads a comma-separated list into a set.
+func shadowList(raw string) map[string]bool {
+	set := make(map[string]bool)
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			set[item] = true
+		}
+	}
+	return set
+}
+
+func shadowTablesLabel(policy registry.ShadowPolicy) string {
+	if policy.Tables == nil {
+		return "all tables"
+	}
+	return fmt.Sprintf("%d table(s)", len(policy.Tables))
+}
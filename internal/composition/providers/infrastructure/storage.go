@@ -14,8 +14,8 @@ import (
 //
 // Uses CONFIG_STORAGE_PROVIDER environment variable to select which provider to use.
 // The accepted (authoritative) values match the registered factory names:
-//   - "gcs"             → Google Cloud Storage provider (build tag: gcs)
-//   - "aws_storage"     → AWS S3 provider (build tag: aws_storage)
+//   - "gcs"             → Google Cloud Storage provider (build tag: gcs or gcs_storage)
+//   - "aws_storage"     → AWS S3 / S3-compatible provider (build tag: aws_storage or s3_storage)
 //   - "azure_storage"   → Azure Blob Storage provider (build tag: azure_storage)
 //   - "local_storage"   → Local filesystem storage provider (build tag: local_storage)
 //   - "mock_storage"    → Mock storage provider
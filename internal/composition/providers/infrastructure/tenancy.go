@@ -0,0 +1,34 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+// LoadTenancyPolicy reads the workspace guard's settings. SQL backends scope
+// tables with a workspace_id column regardless; the switch turns the guard
+// on for schemaless backends (Firestore).
+//
+//   - CONFIG_TENANCY_ENABLED: scope every non-global table to the request's
+//     workspace (default false)
+//   - CONFIG_TENANCY_GLOBAL_TABLES: tables shared by every workspace, in
+//     addition to registry.DefaultGlobalTables, e.g. "currency,country"
+//
+// The policy is published with registry.SetDefaultTenancy.
+func LoadTenancyPolicy(getenv func(string) string) (registry.TenancyPolicy, error) {
+	var policy registry.TenancyPolicy
+	if raw := strings.TrimSpace(getenv("CONFIG_TENANCY_ENABLED")); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return policy, fmt.Errorf("invalid CONFIG_TENANCY_ENABLED %q: %w", raw, err)
+		}
+		policy.Enabled = enabled
+	}
+	if tables := shadowList(getenv("CONFIG_TENANCY_GLOBAL_TABLES")); len(tables) > 0 {
+		policy.GlobalTables = tables
+	}
+	return policy, nil
+}
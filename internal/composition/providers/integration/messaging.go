@@ -0,0 +1,46 @@
+package integration
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports/integration"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+// CreateMessagingProviders creates all text messaging providers specified in
+// CONFIG_MESSAGING_PROVIDER. Supports comma-separated values:
+//   - "twilio" → Twilio Programmable Messaging (SMS and WhatsApp)
+//
+// Messaging is optional: an empty CONFIG_MESSAGING_PROVIDER returns no
+// providers and no error. Returns a map keyed by provider name.
+func CreateMessagingProviders() (map[string]integration.MessagingProvider, error) {
+	raw := strings.ToLower(strings.TrimSpace(os.Getenv("CONFIG_MESSAGING_PROVIDER")))
+	if raw == "" {
+		return nil, nil
+	}
+
+	providers := make(map[string]integration.MessagingProvider)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		provider, err := registry.BuildMessagingProviderFromEnv(name)
+		if err != nil {
+			fmt.Printf("warning: failed to initialize messaging provider '%s': %v\n", name, err)
+			continue
+		}
+		if provider != nil {
+			providers[name] = provider
+		}
+	}
+
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no messaging providers could be initialized from CONFIG_MESSAGING_PROVIDER=%s (available: %v)", raw, registry.ListAvailableMessagingBuildFromEnv())
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,47 @@
+package integration
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports/integration"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+// CreatePushProviders creates all push providers specified in CONFIG_PUSH_PROVIDER.
+// Supports comma-separated values (e.g., "google_fcm,apns"):
+//   - "google_fcm" → Firebase Cloud Messaging (Android, and iOS through Firebase)
+//   - "apns"       → Apple Push Notification service, token-based auth (iOS)
+//
+// Push is optional: an empty CONFIG_PUSH_PROVIDER returns no providers and no
+// error. Returns a map keyed by provider name.
+func CreatePushProviders() (map[string]integration.PushProvider, error) {
+	raw := strings.ToLower(strings.TrimSpace(os.Getenv("CONFIG_PUSH_PROVIDER")))
+	if raw == "" {
+		return nil, nil
+	}
+
+	providers := make(map[string]integration.PushProvider)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		provider, err := registry.BuildPushProviderFromEnv(name)
+		if err != nil {
+			fmt.Printf("warning: failed to initialize push provider '%s': %v\n", name, err)
+			continue
+		}
+		if provider != nil {
+			providers[name] = provider
+		}
+	}
+
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no push providers could be initialized from CONFIG_PUSH_PROVIDER=%s (available: %v)", raw, registry.ListAvailablePushBuildFromEnv())
+	}
+
+	return providers, nil
+}
@@ -15,6 +15,7 @@ import (
 // Uses CONFIG_TABULAR_PROVIDER environment variable to select which provider to use:
 //   - "google_sheets" -> Google Sheets provider
 //   - "csv" -> CSV file provider
+//   - "notion" -> Notion databases provider
 //   - "mock_tabular" -> Mock tabular provider
 //
 // No aliases — the canonical token must be used verbatim. Unknown or empty values
@@ -24,10 +24,14 @@ package providers
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
 	"github.com/erniealice/espyna-golang/internal/composition/contracts"
+	"github.com/erniealice/espyna-golang/internal/composition/providers/infrastructure"
 	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
 )
 
@@ -45,6 +49,8 @@ type Manager struct {
 
 	// Additional providers
 	cacheProvider   contracts.Provider
+	shadowProvider  contracts.Provider // candidate database for shadow reads
+	eventsProvider  contracts.Provider // domain event publisher (Pub/Sub)
 	metricsProvider contracts.Provider
 	loggerProvider  contracts.Provider
 	tracingProvider contracts.Provider
@@ -124,10 +130,92 @@ func (m *Manager) initializeCoreProviders() error {
 		return fmt.Errorf("failed to create ID provider: %w", err)
 	}
 	m.idProvider = idProvider
+	publishIDGenerator(idProvider)
+
+	// The database result cache is optional (CONFIG_CACHE_PROVIDER unset)
+	cacheProvider, err := infrastructure.CreateCacheProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create cache provider: %w", err)
+	}
+	if cacheProvider != nil {
+		m.cacheProvider = cacheProvider
+		publishCache(cacheProvider)
+	}
+
+	// Shadow reads against a candidate database are optional
+	// (CONFIG_DATABASE_SHADOW_PROVIDER unset)
+	shadowProvider, candidateOps, shadowPolicy, err := infrastructure.CreateShadowDatabase(dbProvider.Name())
+	if err != nil {
+		return fmt.Errorf("failed to create shadow database provider: %w", err)
+	}
+	if shadowProvider != nil {
+		m.shadowProvider = shadowProvider
+		registry.SetDefaultShadow(candidateOps, shadowPolicy)
+	}
+
+	// Revision history is optional (CONFIG_HISTORY_TABLES unset)
+	historyPolicy, err := infrastructure.LoadHistoryPolicy(os.Getenv)
+	if err != nil {
+		return fmt.Errorf("failed to load history policy: %w", err)
+	}
+	if len(historyPolicy.Tables) > 0 {
+		registry.SetDefaultHistory(historyPolicy)
+		fmt.Printf("📜 Recording revision history for %d table(s) in %s\n", len(historyPolicy.Tables), historyPolicy.Table())
+	}
+
+	// The workspace guard always applies to SQL tables with a workspace_id
+	// column; CONFIG_TENANCY_ENABLED extends it to schemaless backends
+	tenancyPolicy, err := infrastructure.LoadTenancyPolicy(os.Getenv)
+	if err != nil {
+		return fmt.Errorf("failed to load tenancy policy: %w", err)
+	}
+	registry.SetDefaultTenancy(tenancyPolicy)
+
+	// Publishing domain events to a broker is optional
+	// (CONFIG_EVENTS_PROVIDER unset)
+	eventsProvider, err := infrastructure.CreateEventPublisherProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create event publisher: %w", err)
+	}
+	if eventsProvider != nil {
+		m.eventsProvider = eventsProvider
+		publishEvents(eventsProvider)
+	}
 
 	return nil
 }
 
+// publishIDGenerator hands the ID provider's generator to the registry so
+// database adapters mint primary keys with it instead of their own fallback.
+func publishIDGenerator(provider contracts.Provider) {
+	if wrapper, ok := provider.(interface{ GetIDService() ports.IDGenerator }); ok {
+		registry.SetDefaultIDGenerator(wrapper.GetIDService())
+		return
+	}
+	gen, _ := provider.(ports.IDGenerator)
+	registry.SetDefaultIDGenerator(gen)
+}
+
+// publishCache hands the cache and its per-table policy to the registry so
+// database adapters serve Read and List from it.
+func publishCache(provider contracts.Provider) {
+	if wrapper, ok := provider.(*infrastructure.CacheProviderAdapter); ok {
+		registry.SetDefaultCache(wrapper.GetCache(), wrapper.GetPolicy())
+	}
+}
+
+// eventsSinkName is the name the event publisher's forwarder is installed
+// under in domainevent, next to the webhook dispatcher.
+const eventsSinkName = "events"
+
+// publishEvents makes the event publisher a receiver of the domain events
+// use cases emit after successful writes.
+func publishEvents(provider contracts.Provider) {
+	if wrapper, ok := provider.(*infrastructure.EventPublisherProviderAdapter); ok {
+		domainevent.SetSink(eventsSinkName, wrapper.GetForwarder())
+	}
+}
+
 // Initialize initializes all providers
 func (m *Manager) Initialize() error {
 	m.mu.Lock()
@@ -143,6 +231,7 @@ func (m *Manager) Initialize() error {
 	providers := []contracts.Provider{
 		m.serverProvider,
 		m.cacheProvider,
+		m.eventsProvider,
 		m.metricsProvider,
 		m.loggerProvider,
 		m.tracingProvider,
@@ -241,6 +330,7 @@ func (m *Manager) SetIDProvider(provider contracts.Provider) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.idProvider = provider
+	publishIDGenerator(provider)
 }
 
 // GetDBTableConfig returns the database table configuration
@@ -283,6 +373,11 @@ func (m *Manager) Close() error {
 
 	var errors []error
 
+	// Stop emitting to the event publisher before it drains and closes
+	if m.eventsProvider != nil {
+		domainevent.SetSink(eventsSinkName, nil)
+	}
+
 	// Close all providers
 	providers := []contracts.Provider{
 		m.databaseProvider,
@@ -291,6 +386,8 @@ func (m *Manager) Close() error {
 		m.serverProvider,
 		m.idProvider,
 		m.cacheProvider,
+		m.shadowProvider,
+		m.eventsProvider,
 		m.metricsProvider,
 		m.loggerProvider,
 		m.tracingProvider,
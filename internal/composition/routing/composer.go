@@ -121,6 +121,7 @@ func (c *Composer) initializeRoutes() error {
 							Domain:    domainConfig.Domain,
 							Resource:  resource,
 							Operation: operation,
+							Timeout:   routeConfig.Timeout,
 						},
 					}
 					if err := c.routeManager.RegisterRoute(route); err != nil {
@@ -17,6 +17,7 @@ func GetAllDomainConfigurations(useCases *usecases.Aggregate, engineService port
 		domain.ConfigureEntityDomain(useCases.Entity),
 		domain.ConfigureEventDomain(useCases.Event),
 		domain.ConfigureCommunicationDomain(useCases.Communication),
+		domain.ConfigureDocumentDomain(useCases.Document),
 		domain.ConfigureFulfillmentDomain(useCases.Fulfillment),
 		domain.ConfigureIntegrationDomain(useCases.Integration),
 		domain.ConfigureInventoryDomain(useCases.Inventory),
@@ -60,5 +61,9 @@ func GetAllDomainConfigurations(useCases *usecases.Aggregate, engineService port
 		}
 	}
 
+	for i := range configs {
+		configs[i].Routes = withExportRoutes(configs[i].Routes)
+	}
+
 	return configs
 }
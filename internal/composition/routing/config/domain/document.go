@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"fmt"
+
+	documentuc "github.com/erniealice/espyna-golang/internal/application/usecases/domain/document"
+	"github.com/erniealice/espyna-golang/internal/composition/contracts"
+
+	attachmentpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/document/attachment"
+)
+
+// ConfigureDocumentDomain configures routes for the Document domain. Attachments
+// sit under /api/storage/attachment: they are the records that tie stored objects
+// to the entity (module_key + foreign_key) they belong to.
+func ConfigureDocumentDomain(documentUseCases *documentuc.UseCases) contracts.DomainRouteConfiguration {
+	if documentUseCases == nil {
+		fmt.Printf("Document use cases is NIL\n")
+		return contracts.DomainRouteConfiguration{
+			Domain:  "document",
+			Prefix:  "/storage",
+			Enabled: false,
+			Routes:  []contracts.RouteConfiguration{},
+		}
+	}
+
+	routes := []contracts.RouteConfiguration{}
+
+	// Attachment routes. Create enforces the module's upload policy (content
+	// type, size, per-record count); list takes module_key/foreign_key filters.
+	if documentUseCases.Attachment != nil {
+		routes = append(routes,
+			contracts.RouteConfiguration{Method: "POST", Path: "/api/storage/attachment/create", Handler: contracts.NewGenericHandler(documentUseCases.Attachment.CreateAttachment, &attachmentpb.CreateAttachmentRequest{})},
+			contracts.RouteConfiguration{Method: "POST", Path: "/api/storage/attachment/read", Handler: contracts.NewGenericHandler(documentUseCases.Attachment.ReadAttachment, &attachmentpb.ReadAttachmentRequest{})},
+			contracts.RouteConfiguration{Method: "POST", Path: "/api/storage/attachment/update", Handler: contracts.NewGenericHandler(documentUseCases.Attachment.UpdateAttachment, &attachmentpb.UpdateAttachmentRequest{})},
+			contracts.RouteConfiguration{Method: "POST", Path: "/api/storage/attachment/delete", Handler: contracts.NewGenericHandler(documentUseCases.Attachment.DeleteAttachment, &attachmentpb.DeleteAttachmentRequest{})},
+			contracts.RouteConfiguration{Method: "POST", Path: "/api/storage/attachment/list", Handler: contracts.NewGenericHandler(documentUseCases.Attachment.ListAttachments, &attachmentpb.ListAttachmentsRequest{})},
+		)
+	}
+
+	return contracts.DomainRouteConfiguration{
+		Domain:  "document",
+		Prefix:  "/storage",
+		Enabled: len(routes) > 0,
+		Routes:  routes,
+	}
+}
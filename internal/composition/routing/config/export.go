@@ -0,0 +1,45 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/composition/contracts"
+)
+
+// listPageDataSuffix ends the path of every list page route.
+const listPageDataSuffix = "/get-list-page-data"
+
+// exportTimeout is the time budget of an export route, which reads every
+// page of the list. CONFIG_ROUTE_TIMEOUTS can still override it per path.
+const exportTimeout = 5 * time.Minute
+
+// withExportRoutes adds POST /api/{domain}/{entity}/export after every
+// get-list-page-data route that can be streamed (see
+// contracts.ExportHandler). A domain that already declares the export path
+// keeps its own route.
+func withExportRoutes(routes []contracts.RouteConfiguration) []contracts.RouteConfiguration {
+	declared := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		declared[route.Path] = true
+	}
+	out := make([]contracts.RouteConfiguration, 0, len(routes))
+	for _, route := range routes {
+		out = append(out, route)
+		prefix, ok := strings.CutSuffix(route.Path, listPageDataSuffix)
+		if !ok || declared[prefix+"/export"] {
+			continue
+		}
+		handler, ok := contracts.NewExportHandler(route.Handler, prefix[strings.LastIndex(prefix, "/")+1:])
+		if !ok {
+			continue
+		}
+		out = append(out, contracts.RouteConfiguration{
+			Method:  "POST",
+			Path:    prefix + "/export",
+			Handler: handler,
+			Timeout: exportTimeout,
+		})
+	}
+	return out
+}
@@ -1,4 +1,4 @@
-//go:build asiapay || paypal || maya
+//go:build asiapay || paypal || maya || mock_payment
 
 // Package integration provides HTTP routing configuration for integration use cases.
 //
@@ -37,7 +37,8 @@ import (
 var _ ports.PaymentProvider = nil
 
 // ConfigurePaymentIntegration configures routes for payment provider integration
-// This is only compiled when the 'asiapay' build tag is present
+// This is only compiled when a payment provider build tag (asiapay, paypal,
+// maya or mock_payment) is present
 func ConfigurePaymentIntegration(
 	_ ports.PaymentProvider, // Kept for backward compatibility
 	integration *integrationuc.IntegrationUseCases,
@@ -1,4 +1,4 @@
-//go:build !asiapay && !paypal && !maya
+//go:build !asiapay && !paypal && !maya && !mock_payment
 
 package integration
 
@@ -11,7 +11,7 @@ import (
 // Ensure ports is used (for interface compatibility)
 var _ ports.PaymentProvider = nil
 
-// ConfigurePaymentIntegration stub for when asiapay build tag is not present
+// ConfigurePaymentIntegration stub for when no payment provider build tag is present
 func ConfigurePaymentIntegration(
 	_ ports.PaymentProvider,
 	_ *integrationuc.IntegrationUseCases,
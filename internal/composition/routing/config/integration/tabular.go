@@ -23,6 +23,8 @@
 package integration
 
 import (
+	"time"
+
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 	integrationuc "github.com/erniealice/espyna-golang/internal/application/usecases/domain/integration"
 	"github.com/erniealice/espyna-golang/internal/composition/contracts"
@@ -32,6 +34,11 @@ import (
 // Ensure ports is used (for interface compatibility)
 var _ ports.TabularSourceProvider = nil
 
+// tabularRouteTimeout is the time budget of the tabular routes. Sheets API
+// calls can stall, so they fail fast with 504 instead of holding a worker
+// for the full default budget. CONFIG_ROUTE_TIMEOUTS can still override it.
+const tabularRouteTimeout = 15 * time.Second
+
 // ConfigureTabularIntegration configures routes for tabular integration
 // This is only compiled when both 'google' and 'googlesheets' build tags are present
 //
@@ -59,6 +66,7 @@ func ConfigureTabularIntegration(
 			Method:  "POST",
 			Path:    "/integration/tabular/read",
 			Handler: contracts.NewGenericHandler(integration.Tabular.ReadRecords, &tabularpb.ReadRecordsRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -68,6 +76,7 @@ func ConfigureTabularIntegration(
 			Method:  "POST",
 			Path:    "/integration/tabular/write",
 			Handler: contracts.NewGenericHandler(integration.Tabular.WriteRecords, &tabularpb.WriteRecordsRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -77,6 +86,7 @@ func ConfigureTabularIntegration(
 			Method:  "POST",
 			Path:    "/integration/tabular/write-simple",
 			Handler: contracts.NewGenericHandler(integration.Tabular.WriteRecordSimple, &tabularpb.WriteRecordSimpleRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -86,6 +96,7 @@ func ConfigureTabularIntegration(
 			Method:  "POST",
 			Path:    "/integration/tabular/update",
 			Handler: contracts.NewGenericHandler(integration.Tabular.UpdateRecords, &tabularpb.UpdateRecordsRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -95,6 +106,7 @@ func ConfigureTabularIntegration(
 			Method:  "POST",
 			Path:    "/integration/tabular/delete",
 			Handler: contracts.NewGenericHandler(integration.Tabular.DeleteRecords, &tabularpb.DeleteRecordsRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -104,6 +116,7 @@ func ConfigureTabularIntegration(
 			Method:  "POST",
 			Path:    "/integration/tabular/search",
 			Handler: contracts.NewGenericHandler(integration.Tabular.SearchRecords, &tabularpb.SearchRecordsRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -113,6 +126,7 @@ func ConfigureTabularIntegration(
 			Method:  "POST",
 			Path:    "/integration/tabular/schema",
 			Handler: contracts.NewGenericHandler(integration.Tabular.GetSchema, &tabularpb.GetSchemaRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -122,6 +136,7 @@ func ConfigureTabularIntegration(
 			Method:  "POST",
 			Path:    "/integration/tabular/source",
 			Handler: contracts.NewGenericHandler(integration.Tabular.GetSource, &tabularpb.GetSourceRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -131,6 +146,7 @@ func ConfigureTabularIntegration(
 			Method:  "POST",
 			Path:    "/integration/tabular/tables",
 			Handler: contracts.NewGenericHandler(integration.Tabular.ListTables, &tabularpb.ListTablesRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -140,6 +156,7 @@ func ConfigureTabularIntegration(
 			Method:  "POST",
 			Path:    "/integration/tabular/batch",
 			Handler: contracts.NewGenericHandler(integration.Tabular.BatchExecute, &tabularpb.BatchExecuteRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -149,6 +166,7 @@ func ConfigureTabularIntegration(
 			Method:  "GET",
 			Path:    "/integration/tabular/health",
 			Handler: contracts.NewGenericHandler(integration.Tabular.CheckHealth, &tabularpb.CheckHealthRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -158,6 +176,7 @@ func ConfigureTabularIntegration(
 			Method:  "GET",
 			Path:    "/integration/tabular/capabilities",
 			Handler: contracts.NewGenericHandler(integration.Tabular.GetCapabilities, &tabularpb.GetCapabilitiesRequest{}),
+			Timeout: tabularRouteTimeout,
 		})
 	}
 
@@ -392,10 +392,12 @@ func DefaultConfig() *Config {
 			MaxAge:           86400,
 		},
 		RateLimit: RateLimitConfig{
-			Enabled:           false,
-			RequestsPerMinute: 100,
-			BurstSize:         20,
-			Endpoints:         make(map[string]EndpointRateLimit),
+			Enabled: false,
+			PerIP: EndpointRateLimit{
+				RequestsPerMinute: 100,
+				BurstSize:         20,
+			},
+			Endpoints: make(map[string]EndpointRateLimit),
 		},
 		Timeout:           30 * time.Second,
 		EnableMetrics:     true,
@@ -0,0 +1,294 @@
+// Package openapi generates an OpenAPI 3 document from the composed routes.
+//
+// Every route is declared through contracts.RouteConfiguration with a typed
+// use case handler, so request and response schemas come straight from the
+// protobuf descriptors. Server adapters serve the document at Path; the
+// server command can also write it to a file (see cmd/server -openapi).
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/erniealice/espyna-golang/internal/composition/contracts"
+)
+
+// Path is where server adapters serve the generated document.
+const Path = "/api/openapi.json"
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.0.3"
+
+// Info describes the API in the document header.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// DefaultInfo is used when Generate is given an empty Info.
+var DefaultInfo = Info{Title: "Espyna API", Version: "v1"}
+
+// Document is an OpenAPI 3 document.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+// Components holds the shared schemas operations refer to.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Operation is one method on one path.
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// RequestBody is an operation's JSON request body.
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response is one status code's response.
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType wraps the schema of a body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the subset of the OpenAPI schema object the generator emits.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	EnumNames            []string           `json:"x-enum-varnames,omitempty"`
+}
+
+// errorSchemaName is the component describing adapter error bodies.
+const errorSchemaName = "Error"
+
+// Generate builds the document for routes. Routes whose handler does not
+// describe its message types get a free-form object schema.
+func Generate(routes []*contracts.Route, info Info) *Document {
+	if info.Title == "" {
+		info = DefaultInfo
+	}
+	g := &generator{schemas: map[string]*Schema{
+		errorSchemaName: {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"error":      {Type: "string"},
+				"details":    {Type: "string"},
+				"route_name": {Type: "string"},
+			},
+		},
+	}}
+	doc := &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      make(map[string]map[string]*Operation),
+		Components: Components{Schemas: g.schemas},
+	}
+
+	for _, route := range routes {
+		if route == nil || route.Path == "" {
+			continue
+		}
+		method := strings.ToLower(route.Method)
+		if doc.Paths[route.Path] == nil {
+			doc.Paths[route.Path] = make(map[string]*Operation)
+		}
+		doc.Paths[route.Path][method] = g.operation(route)
+	}
+	return doc
+}
+
+// Marshal generates the document for routes and encodes it as indented JSON.
+func Marshal(routes []*contracts.Route, info Info) ([]byte, error) {
+	data, err := json.MarshalIndent(Generate(routes, info), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+	return data, nil
+}
+
+type generator struct {
+	schemas map[string]*Schema
+}
+
+func (g *generator) operation(route *contracts.Route) *Operation {
+	op := &Operation{
+		OperationID: route.Metadata.Name,
+		Summary:     route.Metadata.Description,
+		Deprecated:  route.Metadata.Deprecated,
+		Responses: map[string]*Response{
+			"400": g.errorResponse("Invalid request body"),
+			"500": g.errorResponse("Use case failed"),
+			"504": g.errorResponse("Route time budget exceeded"),
+		},
+	}
+	if route.Metadata.Domain != "" {
+		op.Tags = []string{route.Metadata.Domain}
+	}
+
+	var request, response protoreflect.MessageDescriptor
+	if describer, ok := route.Handler.(contracts.MessageTypesDescriber); ok {
+		request, response = describer.MessageTypes()
+	}
+
+	if route.Method != "GET" && route.Method != "DELETE" {
+		op.RequestBody = &RequestBody{Content: jsonContent(g.messageRef(request))}
+	}
+	op.Responses["200"] = &Response{
+		Description: "Success",
+		Content:     jsonContent(g.messageRef(response)),
+	}
+	return op
+}
+
+func (g *generator) errorResponse(description string) *Response {
+	return &Response{
+		Description: description,
+		Content:     jsonContent(&Schema{Ref: componentRef(errorSchemaName)}),
+	}
+}
+
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
+
+func componentRef(name string) string {
+	return "#/components/schemas/" + name
+}
+
+// messageRef returns a reference to md's component schema, adding it and
+// every message it uses on first sight.
+func (g *generator) messageRef(md protoreflect.MessageDescriptor) *Schema {
+	if md == nil {
+		return &Schema{Type: "object"}
+	}
+	if wkt := wellKnownSchema(md); wkt != nil {
+		return wkt
+	}
+	name := string(md.FullName())
+	if _, seen := g.schemas[name]; !seen {
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		g.schemas[name] = schema // registered first so recursive messages terminate
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			schema.Properties[string(fd.Name())] = g.fieldSchema(fd)
+		}
+	}
+	return &Schema{Ref: componentRef(name)}
+}
+
+func (g *generator) fieldSchema(fd protoreflect.FieldDescriptor) *Schema {
+	switch {
+	case fd.IsMap():
+		return &Schema{Type: "object", AdditionalProperties: g.singularSchema(fd.MapValue())}
+	case fd.IsList():
+		return &Schema{Type: "array", Items: g.singularSchema(fd)}
+	default:
+		return g.singularSchema(fd)
+	}
+}
+
+// singularSchema maps one value of fd. Field names are the proto names,
+// which both protojson requests and the adapters' JSON responses use;
+// 64-bit integers and enums are numbers, which both accept.
+func (g *generator) singularSchema(fd protoreflect.FieldDescriptor) *Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.StringKind:
+		return &Schema{Type: "string"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &Schema{Type: "integer", Format: "int64"}
+	case protoreflect.FloatKind:
+		return &Schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &Schema{Type: "number", Format: "double"}
+	case protoreflect.EnumKind:
+		return enumSchema(fd.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return g.messageRef(fd.Message())
+	}
+	return &Schema{}
+}
+
+func enumSchema(ed protoreflect.EnumDescriptor) *Schema {
+	values := ed.Values()
+	schema := &Schema{Type: "integer", Format: "int32", Description: string(ed.FullName())}
+	type value struct {
+		number protoreflect.EnumNumber
+		name   string
+	}
+	sorted := make([]value, values.Len())
+	for i := range sorted {
+		sorted[i] = value{values.Get(i).Number(), string(values.Get(i).Name())}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].number < sorted[j].number })
+	for _, v := range sorted {
+		schema.Enum = append(schema.Enum, int32(v.number))
+		schema.EnumNames = append(schema.EnumNames, v.name)
+	}
+	return schema
+}
+
+// wellKnownSchema maps the google.protobuf types with a special JSON form.
+func wellKnownSchema(md protoreflect.MessageDescriptor) *Schema {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration":
+		return &Schema{Type: "string", Description: "duration, e.g. \"1.5s\""}
+	case "google.protobuf.Struct":
+		return &Schema{Type: "object"}
+	case "google.protobuf.Value", "google.protobuf.Any":
+		return &Schema{}
+	case "google.protobuf.ListValue":
+		return &Schema{Type: "array", Items: &Schema{}}
+	case "google.protobuf.StringValue":
+		return &Schema{Type: "string"}
+	case "google.protobuf.BoolValue":
+		return &Schema{Type: "boolean"}
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return &Schema{Type: "number"}
+	case "google.protobuf.BytesValue":
+		return &Schema{Type: "string", Format: "byte"}
+	}
+	return nil
+}
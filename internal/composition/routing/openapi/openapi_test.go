@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/erniealice/espyna-golang/internal/composition/contracts"
+)
+
+// describeUseCase uses generated messages that nest recursively and carry
+// enums and repeated fields.
+type describeUseCase struct{}
+
+func (describeUseCase) Execute(context.Context, *descriptorpb.DescriptorProto) (*descriptorpb.FileDescriptorProto, error) {
+	return nil, nil
+}
+
+func TestGenerate_SchemasFromHandlerDescriptors(t *testing.T) {
+	routes := []*contracts.Route{{
+		Method:  "POST",
+		Path:    "/api/schema/describe",
+		Handler: contracts.NewGenericHandler(describeUseCase{}, &descriptorpb.DescriptorProto{}),
+		Metadata: contracts.RouteMetadata{
+			Name:   "entity.schema.describe",
+			Domain: "entity",
+		},
+	}}
+
+	doc := Generate(routes, Info{})
+	if doc.OpenAPI != Version || doc.Info != DefaultInfo {
+		t.Fatalf("header = %s %+v", doc.OpenAPI, doc.Info)
+	}
+	op := doc.Paths["/api/schema/describe"]["post"]
+	if op == nil || op.OperationID != "entity.schema.describe" || len(op.Tags) != 1 || op.Tags[0] != "entity" {
+		t.Fatalf("operation = %+v", op)
+	}
+	if ref := op.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/google.protobuf.DescriptorProto" {
+		t.Fatalf("request schema = %q", ref)
+	}
+	if ref := op.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/google.protobuf.FileDescriptorProto" {
+		t.Fatalf("response schema = %q", ref)
+	}
+	if op.Responses["504"] == nil {
+		t.Fatal("missing 504 response")
+	}
+
+	message := doc.Components.Schemas["google.protobuf.DescriptorProto"]
+	if nested := message.Properties["nested_type"]; nested.Type != "array" || nested.Items.Ref != "#/components/schemas/google.protobuf.DescriptorProto" {
+		t.Fatalf("recursive field = %+v", nested)
+	}
+	field := doc.Components.Schemas["google.protobuf.FieldDescriptorProto"]
+	if typ := field.Properties["type"]; typ.Type != "integer" || len(typ.Enum) != len(typ.EnumNames) || typ.EnumNames[0] != "TYPE_DOUBLE" {
+		t.Fatalf("enum field = %+v", typ)
+	}
+	if number := field.Properties["number"]; number.Type != "integer" || number.Format != "int32" {
+		t.Fatalf("int32 field = %+v", number)
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("document does not encode: %v", err)
+	}
+}
+
+func TestGenerate_UndescribedHandler(t *testing.T) {
+	doc := Generate([]*contracts.Route{{Method: "POST", Path: "/custom"}}, DefaultInfo)
+	op := doc.Paths["/custom"]["post"]
+	if schema := op.RequestBody.Content["application/json"].Schema; schema.Type != "object" || schema.Ref != "" {
+		t.Fatalf("request schema = %+v", schema)
+	}
+}
@@ -0,0 +1,273 @@
+package apikey
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	authpb "github.com/erniealice/esqyma/pkg/schema/v1/infrastructure/auth"
+)
+
+// memOps keeps the rows of one table; List honours string equality filters.
+type memOps struct {
+	interfaces.DatabaseOperation
+	rows    map[string]map[string]any
+	nextID  int
+	updates int
+}
+
+func newMemOps() *memOps {
+	return &memOps{rows: map[string]map[string]any{}}
+}
+
+func (o *memOps) Create(_ context.Context, _ string, data map[string]any) (map[string]any, error) {
+	o.nextID++
+	row := map[string]any{"id": fmt.Sprintf("key-%d", o.nextID)}
+	for k, v := range data {
+		row[k] = v
+	}
+	o.rows[row["id"].(string)] = row
+	return row, nil
+}
+
+func (o *memOps) Read(_ context.Context, _ string, id string) (map[string]any, error) {
+	row, ok := o.rows[id]
+	if !ok {
+		return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return row, nil
+}
+
+func (o *memOps) Update(_ context.Context, _ string, id string, data map[string]any) (map[string]any, error) {
+	o.updates++
+	for k, v := range data {
+		o.rows[id][k] = v
+	}
+	return o.rows[id], nil
+}
+
+func (o *memOps) List(_ context.Context, _ string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	var rows []map[string]any
+	for _, row := range o.rows {
+		match := true
+		for _, f := range params.Filters.Filters {
+			if row[f.Field] != f.GetStringFilter().GetValue() {
+				match = false
+			}
+		}
+		if match {
+			rows = append(rows, row)
+		}
+	}
+	return &interfaces.ListResult{Data: rows}, nil
+}
+
+// grants is an inner authorizer holding a fixed permission set per user.
+type grants map[string][]string
+
+func (g grants) has(userID, permission string) bool {
+	for _, p := range g[userID] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func (g grants) HasPermission(_ context.Context, userID, permission string) (bool, error) {
+	return g.has(userID, permission), nil
+}
+func (g grants) HasGlobalPermission(_ context.Context, userID, permission string) (bool, error) {
+	return g.has(userID, permission), nil
+}
+func (g grants) HasPermissionInWorkspace(_ context.Context, userID, _, permission string) (bool, error) {
+	return g.has(userID, permission), nil
+}
+func (g grants) GetUserRoles(context.Context, string) ([]string, error) { return nil, nil }
+func (g grants) GetUserRolesInWorkspace(context.Context, string, string) ([]string, error) {
+	return nil, nil
+}
+func (g grants) GetUserWorkspaces(context.Context, string) ([]string, error) { return nil, nil }
+func (g grants) GetUserPermissionCodes(_ context.Context, userID string) ([]string, error) {
+	return g[userID], nil
+}
+func (g grants) IsEnabled() bool { return true }
+
+func TestStore_Lifecycle(t *testing.T) {
+	ops := newMemOps()
+	store := NewStore(ops, "", []byte("pepper"))
+	ctx := context.Background()
+
+	key, err := store.Create(ctx, "ws-1", "billing sync", []string{"invoice:list", " client:read", "invoice:list"}, time.Time{}, "ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key.Token, TokenPrefix+key.ID+"_") || fmt.Sprint(key.Permissions) != "[client:read invoice:list]" {
+		t.Fatalf("created %+v", key)
+	}
+	if hash := ops.rows[key.ID]["secret_hash"].(string); strings.Contains(key.Token, hash) {
+		t.Error("secret stored in the clear")
+	}
+
+	got, err := store.Verify(ctx, key.Token)
+	if err != nil || got.WorkspaceID != "ws-1" || got.LastUsedAt.IsZero() {
+		t.Fatalf("Verify = %+v, %v", got, err)
+	}
+	if _, err := store.Verify(ctx, key.Token); err != nil || ops.updates != 1 {
+		t.Errorf("second use = %v (%d updates), want last_used_at left alone", err, ops.updates)
+	}
+	if _, err := store.Verify(ctx, key.Token+"0"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("wrong secret = %v", err)
+	}
+	if _, err := NewStore(ops, "", []byte("other")).Verify(ctx, key.Token); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("other pepper = %v", err)
+	}
+
+	if _, err := store.Rotate(ctx, "ws-2", key.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("rotate from another workspace = %v", err)
+	}
+	rotated, err := store.Rotate(ctx, "ws-1", key.ID)
+	if err != nil || rotated.Token == key.Token {
+		t.Fatalf("Rotate = %+v, %v", rotated, err)
+	}
+	if _, err := store.Verify(ctx, key.Token); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("old token after rotation = %v", err)
+	}
+	if _, err := store.Verify(ctx, rotated.Token); err != nil {
+		t.Errorf("new token = %v", err)
+	}
+
+	if err := store.Revoke(ctx, "ws-1", key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Verify(ctx, rotated.Token); !errors.Is(err, ErrRevoked) {
+		t.Errorf("revoked key = %v", err)
+	}
+	if keys, _ := store.List(ctx, "ws-1"); len(keys) != 1 || keys[0].RevokedAt.IsZero() || keys[0].Token != "" {
+		t.Errorf("List = %+v", keys)
+	}
+}
+
+func TestStore_Expiry(t *testing.T) {
+	store := NewStore(newMemOps(), "", nil)
+	ctx := context.Background()
+	now := time.Now()
+	if _, err := store.Create(ctx, "ws-1", "", []string{"client:read"}, now.Add(-time.Minute), ""); !errors.Is(err, ErrInvalidExpiry) {
+		t.Errorf("past expiry = %v", err)
+	}
+	if _, err := store.Create(ctx, "ws-1", "", []string{"client:*"}, time.Time{}, ""); !errors.Is(err, ErrInvalidPermission) {
+		t.Errorf("wildcard = %v", err)
+	}
+	key, err := store.Create(ctx, "ws-1", "", []string{"client:read"}, now.Add(time.Hour), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if _, err := store.Verify(ctx, key.Token); !errors.Is(err, ErrExpired) {
+		t.Errorf("expired key = %v", err)
+	}
+}
+
+func TestAdapter_VerifyAndScope(t *testing.T) {
+	a := NewAPIKeyAuthAdapter(nil, "", nil, 0)
+	_ = a.Initialize(&authpb.ProviderConfig{Enabled: true})
+	a.SetOperations(newMemOps())
+	ctx := context.Background()
+	key, err := a.Store().Create(ctx, "ws-1", "sync", []string{"client:read"}, time.Time{}, "ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, _ := a.VerifyToken(ctx, &authpb.ValidateJwtTokenRequest{Token: "Bearer " + key.Token})
+	if !resp.IsValid || resp.Identity.Id != "apikey:"+key.ID || resp.Token.CustomClaims[ClaimWorkspaceID] != "ws-1" {
+		t.Fatalf("VerifyToken = %+v", resp)
+	}
+	if resp, _ := a.VerifyToken(ctx, &authpb.ValidateJwtTokenRequest{Token: "eyJhbGciOi"}); resp.IsValid {
+		t.Error("non-key token accepted without a fallback")
+	}
+
+	authz := a.ScopeAuthorizer(grants{"ada": {"client:read", "client:delete"}})
+	principal := key.Principal()
+	inWS := func(ws string) context.Context { return contextutil.WithWorkspaceID(ctx, ws) }
+	for _, c := range []struct {
+		ctx        context.Context
+		user, perm string
+		want       bool
+	}{
+		{inWS("ws-1"), principal, "client:read", true},
+		{inWS("ws-1"), principal, "client:delete", false},
+		{inWS("ws-2"), principal, "client:read", false},
+		{ctx, principal, "client:read", true},
+		{inWS("ws-1"), "ada", "client:delete", true},
+	} {
+		if got, _ := authz.HasPermission(c.ctx, c.user, c.perm); got != c.want {
+			t.Errorf("HasPermission(%s, %s) = %v, want %v", c.user, c.perm, got, c.want)
+		}
+	}
+
+	_ = a.Store().Revoke(ctx, "ws-1", key.ID)
+	a.keys = map[string]cachedKey{} // past the cache
+	if got, _ := authz.HasPermission(inWS("ws-1"), principal, "client:read"); got {
+		t.Error("revoked key still authorized")
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	store := NewStore(newMemOps(), "", nil)
+	authz := grants{"ada": {"api_key:create", "api_key:list", "api_key:update", "api_key:delete", "client:read"}}
+	h := NewHandlers(store, actiongate.NewActionGatekeeper(authz, nil))
+	as := func(user string) context.Context {
+		return contextutil.WithWorkspaceID(contextutil.WithUserID(context.Background(), user), "ws-1")
+	}
+	call := func(ctx context.Context, method, target, body string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		handler := h.Keys
+		if strings.HasPrefix(target, RotatePath) {
+			handler = h.Rotate
+		}
+		handler(rec, httptest.NewRequest(method, target, bytes.NewBufferString(body)).WithContext(ctx))
+		var resp map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, resp
+	}
+
+	if code, _ := call(as("ada"), http.MethodPost, KeysPath, `{"name": "x", "permissions": ["client:delete"]}`); code != http.StatusForbidden {
+		t.Errorf("granting a permission not held = %d, want 403", code)
+	}
+	code, resp := call(as("ada"), http.MethodPost, KeysPath, `{"name": "x", "permissions": ["client:read"]}`)
+	data, _ := resp["data"].(map[string]any)
+	if code != http.StatusCreated || !IsToken(fmt.Sprint(data["key"])) {
+		t.Fatalf("create = %d %v", code, resp)
+	}
+	id := fmt.Sprint(data["id"])
+
+	if code, resp := call(as("ada"), http.MethodGet, KeysPath, ``); code != http.StatusOK || strings.Contains(fmt.Sprint(resp), TokenPrefix) {
+		t.Errorf("list = %d %v", code, resp)
+	}
+	if code, _ := call(as("apikey:"+id), http.MethodGet, KeysPath, ``); code != http.StatusForbidden {
+		t.Errorf("a key managing keys = %d, want 403", code)
+	}
+	if code, resp := call(as("ada"), http.MethodPost, RotatePath+"?id="+id, ``); code != http.StatusOK || fmt.Sprint(resp["data"].(map[string]any)["key"]) == fmt.Sprint(data["key"]) {
+		t.Errorf("rotate = %d %v", code, resp)
+	}
+	if code, _ := call(as("ada"), http.MethodDelete, KeysPath+"?id="+id, ``); code != http.StatusOK {
+		t.Errorf("revoke = %d", code)
+	}
+	if code, _ := call(as("ada"), http.MethodPost, RotatePath+"?id="+id, ``); code != http.StatusConflict {
+		t.Errorf("rotate revoked = %d, want 409", code)
+	}
+}
+
+var _ ports.Authorizer = grants(nil)
@@ -0,0 +1,228 @@
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// The key management endpoints, all scoped to the caller's workspace:
+//
+//	GET    KeysPath          the workspace's keys, without secrets
+//	POST   KeysPath          issue {"name", "permissions", "expires_at"};
+//	                         the answer holds the key, shown only once
+//	DELETE KeysPath?id=...   revoke a key
+//	POST   RotatePath?id=... replace a key's secret; the answer holds the
+//	                         new key, and the old one stops working
+//
+// Each needs the matching api_key permission: list, create, delete,
+// update. Issuing a key also needs every permission it is to carry, so no
+// one hands out more than they hold, and keys cannot manage keys.
+const (
+	KeysPath   = "/api/api-keys"
+	RotatePath = "/api/api-keys/rotate"
+)
+
+// entityAPIKey is the permission entity of the keys.
+const entityAPIKey = "api_key"
+
+type keyJSON struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	CreatedBy   string   `json:"created_by,omitempty"`
+	CreatedAt   int64    `json:"created_at"`
+	ExpiresAt   int64    `json:"expires_at,omitempty"`
+	LastUsedAt  int64    `json:"last_used_at,omitempty"`
+	RevokedAt   int64    `json:"revoked_at,omitempty"`
+	Key         string   `json:"key,omitempty"`
+}
+
+type createRequest struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	// ExpiresAt is RFC 3339; empty for a key that does not expire.
+	ExpiresAt string `json:"expires_at"`
+}
+
+// Handlers serves the key management endpoints.
+type Handlers struct {
+	store *Store
+	gate  *actiongate.ActionGatekeeper
+}
+
+// NewHandlers creates the endpoint handlers over store. gate decides who
+// may manage the workspace's keys.
+func NewHandlers(store *Store, gate *actiongate.ActionGatekeeper) *Handlers {
+	return &Handlers{store: store, gate: gate}
+}
+
+// Keys lists, issues or revokes keys by method.
+func (h *Handlers) Keys(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.list(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	case http.MethodDelete:
+		h.revoke(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+	}
+}
+
+// Rotate replaces a key's secret.
+func (h *Handlers) Rotate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return
+	}
+	workspaceID, ok := h.allowed(w, r, entityid.ActionUpdate)
+	if !ok {
+		return
+	}
+	key, err := h.store.Rotate(r.Context(), workspaceID, r.URL.Query().Get("id"))
+	if errors.Is(err, ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	if errors.Is(err, ErrRevoked) {
+		writeJSON(w, http.StatusConflict, map[string]any{"success": false, "error": "revoked or expired keys cannot be rotated"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": toKeyJSON(*key)})
+}
+
+func (h *Handlers) list(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.allowed(w, r, entityid.ActionList)
+	if !ok {
+		return
+	}
+	keys, err := h.store.List(r.Context(), workspaceID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	data := make([]keyJSON, 0, len(keys))
+	for _, k := range keys {
+		data = append(data, toKeyJSON(k))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": data})
+}
+
+func (h *Handlers) create(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.allowed(w, r, entityid.ActionCreate)
+	if !ok {
+		return
+	}
+	var req createRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "invalid JSON body"})
+		return
+	}
+	var expiresAt time.Time
+	if req.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "expires_at must be an RFC 3339 time"})
+			return
+		}
+		expiresAt = t
+	}
+
+	ctx := r.Context()
+	for _, p := range req.Permissions {
+		entity, action, _ := strings.Cut(strings.TrimSpace(p), ":")
+		if entity == "" || action == "" {
+			continue // left for Create to refuse
+		}
+		if err := h.gate.Check(ctx, &actiongate.CheckActionRequest{Entity: entity, Action: action}); err != nil {
+			writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": "cannot grant a permission you do not hold: " + p})
+			return
+		}
+	}
+
+	key, err := h.store.Create(ctx, workspaceID, req.Name, req.Permissions, expiresAt, contextutil.ExtractUserIDFromContext(ctx))
+	if errors.Is(err, ErrNoPermissions) || errors.Is(err, ErrInvalidPermission) || errors.Is(err, ErrInvalidExpiry) {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"success": true, "data": toKeyJSON(*key)})
+}
+
+func (h *Handlers) revoke(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.allowed(w, r, entityid.ActionDelete)
+	if !ok {
+		return
+	}
+	err := h.store.Revoke(r.Context(), workspaceID, r.URL.Query().Get("id"))
+	if errors.Is(err, ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+// allowed checks the caller, a user rather than a key, may take action on
+// the keys of their workspace, writing the refusal when not.
+func (h *Handlers) allowed(w http.ResponseWriter, r *http.Request, action string) (string, bool) {
+	ctx := r.Context()
+	userID := contextutil.ExtractUserIDFromContext(ctx)
+	if userID == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return "", false
+	}
+	if _, isKey := KeyID(userID); isKey {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": "api keys cannot manage api keys"})
+		return "", false
+	}
+	workspaceID := contextutil.ExtractWorkspaceIDFromContext(ctx)
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "workspace required"})
+		return "", false
+	}
+	if err := h.gate.Check(ctx, &actiongate.CheckActionRequest{Entity: entityAPIKey, Action: action}); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": err.Error()})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+func toKeyJSON(k Key) keyJSON {
+	return keyJSON{
+		ID:          k.ID,
+		Name:        k.Name,
+		Permissions: k.Permissions,
+		CreatedBy:   k.CreatedBy,
+		CreatedAt:   millis(k.CreatedAt),
+		ExpiresAt:   millis(k.ExpiresAt),
+		LastUsedAt:  millis(k.LastUsedAt),
+		RevokedAt:   millis(k.RevokedAt),
+		Key:         k.Token,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,427 @@
+// Package apikey authenticates machine-to-machine callers with per-workspace
+// API keys, next to the user-facing auth provider (Firebase, password or
+// mock) it falls back to for every other token.
+//
+// A key is "esk_<id>_<secret>". Only an HMAC-SHA256 of the secret is
+// stored, keyed with an optional server-side pepper, so the key table alone
+// does not let anyone call the API. Each key belongs to one workspace and
+// carries the permission codes it may use there: its principal,
+// "apikey:<id>", holds exactly those, whatever roles its creator has. Keys
+// can expire, are revoked rather than deleted, and record when they were
+// last used.
+package apikey
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	"github.com/erniealice/espyna-golang/shared/identity"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// DefaultTable holds the keys (see the postgres integration migration
+// 000016_api_key).
+const DefaultTable = "api_key"
+
+// TokenPrefix starts every key, so keys are told apart from the fallback
+// provider's tokens without a lookup.
+const TokenPrefix = "esk_"
+
+// PrincipalPrefix starts the user ID of a key's principal.
+const PrincipalPrefix = "apikey:"
+
+// DefaultTouchInterval is how stale last_used_at may get before a use
+// rewrites it.
+const DefaultTouchInterval = time.Minute
+
+// listLimit bounds the rows one read returns.
+const listLimit = 1000
+
+// Errors of the Store. Verify reports every unusable key as ErrInvalidKey,
+// ErrRevoked or ErrExpired; the management calls report another
+// workspace's key as ErrNotFound.
+var (
+	ErrInvalidKey        = errors.New("invalid api key")
+	ErrRevoked           = errors.New("api key has been revoked")
+	ErrExpired           = errors.New("api key has expired")
+	ErrNotFound          = errors.New("api key not found")
+	ErrNoPermissions     = errors.New("api keys need at least one permission")
+	ErrInvalidPermission = errors.New("api key permissions are entity:action codes")
+	ErrInvalidExpiry     = errors.New("api key expiry must be in the future")
+)
+
+// Key is a workspace's API key.
+type Key struct {
+	ID          string
+	WorkspaceID string
+	Name        string
+	// Permissions are the "entity:action" codes the key may use.
+	Permissions []string
+	CreatedBy   string
+	CreatedAt   time.Time
+	// ExpiresAt is zero for a key that does not expire.
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	RevokedAt  time.Time
+	// Token is the full key. Create and Rotate return it; it is not
+	// stored and cannot be read back.
+	Token string
+}
+
+// Principal returns the user ID the key acts as.
+func (k Key) Principal() string {
+	return PrincipalPrefix + k.ID
+}
+
+// Usable reports whether the key is neither revoked nor expired at now.
+func (k Key) Usable(now time.Time) bool {
+	return k.RevokedAt.IsZero() && (k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt))
+}
+
+// Allows reports whether the key carries permission.
+func (k Key) Allows(permission string) bool {
+	for _, p := range k.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyID returns the key ID of an API key principal.
+func KeyID(userID string) (string, bool) {
+	id, ok := strings.CutPrefix(userID, PrincipalPrefix)
+	return id, ok && id != ""
+}
+
+// IsToken reports whether token has the shape of an API key.
+func IsToken(token string) bool {
+	_, _, ok := parseToken(token)
+	return ok
+}
+
+// Store keeps the keys.
+type Store struct {
+	ops    interfaces.DatabaseOperation
+	table  string
+	pepper []byte
+	// TouchInterval is how stale last_used_at may get before Verify
+	// rewrites it; DefaultTouchInterval when zero.
+	TouchInterval time.Duration
+	now           func() time.Time
+}
+
+// NewStore creates the key store on table (DefaultTable when empty).
+// pepper keys the secret hashes; changing it invalidates every key.
+func NewStore(ops interfaces.DatabaseOperation, table string, pepper []byte) *Store {
+	if table == "" {
+		table = DefaultTable
+	}
+	return &Store{ops: ops, table: table, pepper: pepper, now: time.Now}
+}
+
+// Create issues a key for workspaceID, returned with its token.
+func (s *Store) Create(ctx context.Context, workspaceID, name string, permissions []string, expiresAt time.Time, createdBy string) (*Key, error) {
+	if workspaceID == "" {
+		return nil, errors.New("api keys belong to a workspace")
+	}
+	permissions, err := normalizePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	if !expiresAt.IsZero() && !expiresAt.After(now) {
+		return nil, ErrInvalidExpiry
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	row, err := s.ops.Create(ctx, s.table, map[string]any{
+		"workspace_id": workspaceID,
+		"name":         strings.TrimSpace(name),
+		"secret_hash":  s.hash(secret),
+		"permissions":  strings.Join(permissions, ","),
+		"created_by":   createdBy,
+		"created_at":   now.UnixMilli(),
+		"expires_at":   millis(expiresAt),
+		"last_used_at": int64(0),
+		"revoked_at":   int64(0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create api key: %w", err)
+	}
+	key := &Key{
+		ID:          str(row["id"]),
+		WorkspaceID: workspaceID,
+		Name:        strings.TrimSpace(name),
+		Permissions: permissions,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		ExpiresAt:   fromMillis(millis(expiresAt)),
+	}
+	key.Token = TokenPrefix + key.ID + "_" + secret
+	return key, nil
+}
+
+// Rotate gives a usable key of workspaceID a new secret, returned in its
+// token. The old token stops working at once.
+func (s *Store) Rotate(ctx context.Context, workspaceID, id string) (*Key, error) {
+	key, err := s.get(ctx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !key.Usable(s.now()) {
+		return nil, ErrRevoked
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ops.Update(ctx, s.table, key.ID, map[string]any{"secret_hash": s.hash(secret)}); err != nil {
+		return nil, fmt.Errorf("rotate api key: %w", err)
+	}
+	key.Token = TokenPrefix + key.ID + "_" + secret
+	return key, nil
+}
+
+// Revoke stops a key of workspaceID from working. The row stays, for the
+// record; revoking twice is not an error.
+func (s *Store) Revoke(ctx context.Context, workspaceID, id string) error {
+	key, err := s.get(ctx, workspaceID, id)
+	if err != nil {
+		return err
+	}
+	if !key.RevokedAt.IsZero() {
+		return nil
+	}
+	if _, err := s.ops.Update(ctx, s.table, key.ID, map[string]any{"revoked_at": s.now().UTC().UnixMilli()}); err != nil {
+		return fmt.Errorf("revoke api key: %w", err)
+	}
+	return nil
+}
+
+// List returns the keys of workspaceID, oldest first.
+func (s *Store) List(ctx context.Context, workspaceID string) ([]Key, error) {
+	result, err := s.ops.List(ctx, s.table, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{
+			stringEquals("workspace_id", workspaceID),
+		}},
+		Pagination: firstPage(listLimit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	keys := []Key{}
+	for _, row := range result.Data {
+		// Guard against providers that ignore the filter.
+		if k := keyFromRow(row); k.WorkspaceID == workspaceID {
+			keys = append(keys, k)
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// Get returns a key by ID, whichever workspace it belongs to. It does not
+// check that the key is usable.
+func (s *Store) Get(ctx context.Context, id string) (*Key, error) {
+	row, err := s.ops.Read(systemContext(ctx), s.table, id)
+	if err != nil || row == nil {
+		return nil, ErrNotFound
+	}
+	key := keyFromRow(row)
+	return &key, nil
+}
+
+// Verify returns the key token stands for, when it is usable, and records
+// the use. It runs before the request has an identity, so it reads across
+// workspaces.
+func (s *Store) Verify(ctx context.Context, token string) (*Key, error) {
+	id, secret, ok := parseToken(token)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	ctx = systemContext(ctx)
+	row, err := s.ops.Read(ctx, s.table, id)
+	if err != nil || row == nil {
+		return nil, ErrInvalidKey
+	}
+	if !hmac.Equal([]byte(str(row["secret_hash"])), []byte(s.hash(secret))) {
+		return nil, ErrInvalidKey
+	}
+	key := keyFromRow(row)
+	now := s.now()
+	if !key.RevokedAt.IsZero() {
+		return nil, ErrRevoked
+	}
+	if !key.Usable(now) {
+		return nil, ErrExpired
+	}
+	s.touch(ctx, &key, now)
+	return &key, nil
+}
+
+// touch records a use, at most once per TouchInterval so a busy key does
+// not write on every request. A failed write is logged, not returned.
+func (s *Store) touch(ctx context.Context, key *Key, now time.Time) {
+	interval := s.TouchInterval
+	if interval <= 0 {
+		interval = DefaultTouchInterval
+	}
+	if now.Sub(key.LastUsedAt) < interval {
+		return
+	}
+	if _, err := s.ops.Update(ctx, s.table, key.ID, map[string]any{"last_used_at": now.UTC().UnixMilli()}); err != nil {
+		log.Printf("[AUTH] failed to record use of api key %s: %v", key.ID, err)
+		return
+	}
+	key.LastUsedAt = now.UTC()
+}
+
+// get returns one key of workspaceID.
+func (s *Store) get(ctx context.Context, workspaceID, id string) (*Key, error) {
+	if id == "" {
+		return nil, ErrNotFound
+	}
+	row, err := s.ops.Read(ctx, s.table, id)
+	if err != nil || row == nil {
+		return nil, ErrNotFound
+	}
+	// Another workspace's key is reported as missing, not forbidden.
+	if k := keyFromRow(row); k.WorkspaceID == workspaceID {
+		return &k, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (s *Store) hash(secret string) string {
+	mac := hmac.New(sha256.New, s.pepper)
+	mac.Write([]byte(secret))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseToken splits "esk_<id>_<secret>". The secret is hex, so the last
+// underscore ends the ID.
+func parseToken(token string) (id, secret string, ok bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(token), TokenPrefix)
+	if !ok {
+		return "", "", false
+	}
+	i := strings.LastIndexByte(rest, '_')
+	if i <= 0 || i == len(rest)-1 {
+		return "", "", false
+	}
+	return rest[:i], rest[i+1:], true
+}
+
+// normalizePermissions checks and dedupes the requested codes.
+func normalizePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var out []string
+	for _, p := range permissions {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[p] {
+			continue
+		}
+		entity, action, ok := strings.Cut(p, ":")
+		if !ok || entity == "" || action == "" || strings.ContainsAny(p, ", *") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPermission, p)
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	if len(out) == 0 {
+		return nil, ErrNoPermissions
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func newSecret() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate api key secret: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// systemContext carries an identity without a workspace, so the
+// workspace-aware database layer neither panics nor scopes the read.
+func systemContext(ctx context.Context) context.Context {
+	return identity.WithRequestIdentity(ctx, &identity.RequestIdentity{})
+}
+
+func keyFromRow(row map[string]any) Key {
+	var permissions []string
+	for _, p := range strings.Split(str(row["permissions"]), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			permissions = append(permissions, p)
+		}
+	}
+	return Key{
+		ID:          str(row["id"]),
+		WorkspaceID: str(row["workspace_id"]),
+		Name:        str(row["name"]),
+		Permissions: permissions,
+		CreatedBy:   str(row["created_by"]),
+		CreatedAt:   fromMillis(row["created_at"]),
+		ExpiresAt:   fromMillis(row["expires_at"]),
+		LastUsedAt:  fromMillis(row["last_used_at"]),
+		RevokedAt:   fromMillis(row["revoked_at"]),
+	}
+}
+
+func millis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromMillis(v any) time.Time {
+	if ms := int64(operations.Number(v)); ms > 0 {
+		return time.UnixMilli(ms).UTC()
+	}
+	return time.Time{}
+}
+
+func firstPage(limit int32) *commonpb.PaginationRequest {
+	return &commonpb.PaginationRequest{
+		Limit: limit,
+		Method: &commonpb.PaginationRequest_Offset{
+			Offset: &commonpb.OffsetPagination{Page: 1},
+		},
+	}
+}
+
+func stringEquals(field, value string) *commonpb.TypedFilter {
+	return &commonpb.TypedFilter{
+		Field: field,
+		FilterType: &commonpb.TypedFilter_StringFilter{
+			StringFilter: &commonpb.StringFilter{
+				Value:         value,
+				Operator:      commonpb.StringOperator_STRING_EQUALS,
+				CaseSensitive: true,
+			},
+		},
+	}
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
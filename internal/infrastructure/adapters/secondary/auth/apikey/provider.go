@@ -0,0 +1,285 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	authpb "github.com/erniealice/esqyma/pkg/schema/v1/infrastructure/auth"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Claims the adapter puts on a key's token, for middleware that needs the
+// key's workspace before the authorizer is asked.
+const (
+	ClaimWorkspaceID = "workspace_id"
+	ClaimPermissions = "permissions"
+)
+
+// keyCacheTTL is how long the authorizer trusts a key Verify loaded. Verify
+// itself always reads the key, so a revoked key stops authenticating at
+// once.
+const keyCacheTTL = 30 * time.Second
+
+type cachedKey struct {
+	key      Key
+	loadedAt time.Time
+}
+
+// APIKeyAuthAdapter implements ports.AuthProvider and ports.AuthService for
+// API keys. Tokens that are not API keys go to the fallback provider, when
+// there is one.
+type APIKeyAuthAdapter struct {
+	fallback      ports.AuthProvider
+	table         string
+	pepper        []byte
+	touchInterval time.Duration
+	enabled       bool
+
+	mu    sync.RWMutex
+	store *Store
+	keys  map[string]cachedKey
+	now   func() time.Time
+}
+
+// NewAPIKeyAuthAdapter creates the adapter over fallback (nil for API keys
+// only). The caller must invoke SetOperations and Initialize before use.
+func NewAPIKeyAuthAdapter(fallback ports.AuthProvider, table string, pepper []byte, touchInterval time.Duration) *APIKeyAuthAdapter {
+	return &APIKeyAuthAdapter{
+		fallback:      fallback,
+		table:         table,
+		pepper:        pepper,
+		touchInterval: touchInterval,
+		keys:          make(map[string]cachedKey),
+		now:           time.Now,
+	}
+}
+
+// Name returns the provider name.
+func (a *APIKeyAuthAdapter) Name() string {
+	return "apikey"
+}
+
+// Initialize sets up the adapter with proto-based configuration.
+func (a *APIKeyAuthAdapter) Initialize(config *authpb.ProviderConfig) error {
+	if config == nil {
+		return fmt.Errorf("configuration is required")
+	}
+	a.enabled = config.Enabled
+	if a.enabled {
+		log.Println("[OK] API key auth provider initialized")
+	} else {
+		log.Println("[AUTH] API key auth is disabled")
+	}
+	return nil
+}
+
+// SetOperations injects the database the keys live in, and hands it on to
+// a fallback provider that wants one.
+func (a *APIKeyAuthAdapter) SetOperations(ops dbinterfaces.DatabaseOperation) {
+	store := NewStore(ops, a.table, a.pepper)
+	store.TouchInterval = a.touchInterval
+	a.mu.Lock()
+	a.store = store
+	a.mu.Unlock()
+	if settable, ok := a.fallback.(interface {
+		SetOperations(ops dbinterfaces.DatabaseOperation)
+	}); ok {
+		settable.SetOperations(ops)
+	}
+}
+
+// Store returns the key store; nil until SetOperations.
+func (a *APIKeyAuthAdapter) Store() *Store {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.store
+}
+
+// Fallback returns the provider other tokens go to, for the operations
+// only it offers (password login, custom claims); nil when there is none.
+func (a *APIKeyAuthAdapter) Fallback() ports.AuthProvider {
+	return a.fallback
+}
+
+// GetAuthService returns the authentication service (returns itself).
+func (a *APIKeyAuthAdapter) GetAuthService() ports.AuthService {
+	if !a.enabled {
+		return nil
+	}
+	return a
+}
+
+// IsHealthy returns nil when the keys can be read and the fallback is
+// healthy.
+func (a *APIKeyAuthAdapter) IsHealthy(ctx context.Context) error {
+	if !a.enabled {
+		return fmt.Errorf("api key provider is not enabled")
+	}
+	if a.Store() == nil {
+		return fmt.Errorf("apikey: database operations not initialised")
+	}
+	if a.fallback != nil {
+		return a.fallback.IsHealthy(ctx)
+	}
+	return nil
+}
+
+// Close closes the fallback provider.
+func (a *APIKeyAuthAdapter) Close() error {
+	if a.enabled {
+		log.Println("[AUTH] Closing API key auth provider")
+		a.enabled = false
+	}
+	if a.fallback != nil {
+		return a.fallback.Close()
+	}
+	return nil
+}
+
+// IsEnabled returns whether the adapter is enabled.
+func (a *APIKeyAuthAdapter) IsEnabled() bool {
+	return a.enabled
+}
+
+// =============================================================================
+// AuthService interface
+// =============================================================================
+
+// VerifyToken validates an API key, or hands any other token to the
+// fallback provider. A valid key's identity is the service principal
+// "apikey:<id>"; its token carries the key's workspace and permissions as
+// claims.
+func (a *APIKeyAuthAdapter) VerifyToken(ctx context.Context, req *authpb.ValidateJwtTokenRequest) (*authpb.ValidateJwtTokenResponse, error) {
+	if !a.enabled {
+		return invalid(authpb.ValidationErrorType_VALIDATION_ERROR_TYPE_UNSPECIFIED, "Authentication service is disabled"), nil
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(req.GetToken(), "Bearer "))
+	if !IsToken(token) {
+		if service := a.fallbackService(); service != nil {
+			return service.VerifyToken(ctx, req)
+		}
+		return invalid(authpb.ValidationErrorType_VALIDATION_ERROR_TYPE_MALFORMED, "Token is not an API key"), nil
+	}
+
+	key, err := a.Verify(ctx, token)
+	if err != nil {
+		kind := authpb.ValidationErrorType_VALIDATION_ERROR_TYPE_MALFORMED
+		if err == ErrExpired || err == ErrRevoked {
+			kind = authpb.ValidationErrorType_VALIDATION_ERROR_TYPE_EXPIRED
+		}
+		return invalid(kind, err.Error()), nil
+	}
+
+	jwtToken := &authpb.JwtToken{
+		Token:     token,
+		TokenType: "ApiKey",
+		IssuedAt:  timestamppb.New(key.CreatedAt),
+		Subject:   key.Principal(),
+		Provider:  authpb.Provider_PROVIDER_CUSTOM,
+		CustomClaims: map[string]string{
+			ClaimWorkspaceID: key.WorkspaceID,
+			ClaimPermissions: strings.Join(key.Permissions, ","),
+		},
+	}
+	if !key.ExpiresAt.IsZero() {
+		jwtToken.ExpiresAt = timestamppb.New(key.ExpiresAt)
+	}
+	return &authpb.ValidateJwtTokenResponse{
+		IsValid: true,
+		Token:   jwtToken,
+		Identity: &authpb.Identity{
+			Id:          key.Principal(),
+			Type:        authpb.IdentityType_IDENTITY_TYPE_SERVICE,
+			Provider:    authpb.Provider_PROVIDER_CUSTOM,
+			DisplayName: key.Name,
+			IsActive:    true,
+			CreatedAt:   timestamppb.New(key.CreatedAt),
+		},
+	}, nil
+}
+
+// Verify returns the usable key token stands for.
+func (a *APIKeyAuthAdapter) Verify(ctx context.Context, token string) (*Key, error) {
+	store := a.Store()
+	if store == nil {
+		return nil, fmt.Errorf("apikey: database operations not initialised")
+	}
+	key, err := store.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	a.keys[key.ID] = cachedKey{key: *key, loadedAt: a.now()}
+	a.mu.Unlock()
+	return key, nil
+}
+
+// GetProviderName implements the AuthService interface.
+func (a *APIKeyAuthAdapter) GetProviderName() string {
+	return "apikey"
+}
+
+// ChangePassword is the fallback provider's; API keys have no password.
+func (a *APIKeyAuthAdapter) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error {
+	if _, ok := KeyID(userID); ok {
+		return fmt.Errorf("api keys have no password")
+	}
+	if service := a.fallbackService(); service != nil {
+		return service.ChangePassword(ctx, userID, oldPassword, newPassword)
+	}
+	return fmt.Errorf("apikey: no fallback provider for password changes")
+}
+
+// ScopeAuthorizer returns an authorizer answering for API key principals
+// from their keys and asking inner about everyone else.
+func (a *APIKeyAuthAdapter) ScopeAuthorizer(inner ports.Authorizer) ports.Authorizer {
+	return &scopedAuthorizer{inner: inner, key: a.key}
+}
+
+// key returns the key behind a principal: the one Verify loaded, while
+// fresh, else a new read.
+func (a *APIKeyAuthAdapter) key(ctx context.Context, id string) (*Key, error) {
+	a.mu.RLock()
+	cached, ok := a.keys[id]
+	a.mu.RUnlock()
+	if ok && a.now().Sub(cached.loadedAt) < keyCacheTTL {
+		return &cached.key, nil
+	}
+	store := a.Store()
+	if store == nil {
+		return nil, fmt.Errorf("apikey: database operations not initialised")
+	}
+	key, err := store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	a.keys[id] = cachedKey{key: *key, loadedAt: a.now()}
+	a.mu.Unlock()
+	return key, nil
+}
+
+func (a *APIKeyAuthAdapter) fallbackService() ports.AuthService {
+	if a.fallback == nil {
+		return nil
+	}
+	return a.fallback.GetAuthService()
+}
+
+func invalid(kind authpb.ValidationErrorType, message string) *authpb.ValidateJwtTokenResponse {
+	return &authpb.ValidateJwtTokenResponse{
+		IsValid:          false,
+		ErrorMessage:     message,
+		ValidationErrors: []*authpb.ValidationError{{Type: kind, Message: message}},
+	}
+}
+
+// Compile-time checks that APIKeyAuthAdapter satisfies both interfaces.
+var _ ports.AuthProvider = (*APIKeyAuthAdapter)(nil)
+var _ ports.AuthService = (*APIKeyAuthAdapter)(nil)
@@ -0,0 +1,83 @@
+//go:build apikey_auth
+
+package apikey
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	authpb "github.com/erniealice/esqyma/pkg/schema/v1/infrastructure/auth"
+)
+
+// =============================================================================
+// Self-Registration - Adapter registers itself with the factory
+// =============================================================================
+
+func init() {
+	registry.RegisterAuthProvider(
+		"apikey",
+		func() ports.AuthProvider {
+			return NewAPIKeyAuthAdapter(nil, "", nil, 0)
+		},
+		transformConfig,
+	)
+	registry.RegisterAuthBuildFromEnv("apikey", buildFromEnv)
+}
+
+// buildFromEnv creates an APIKeyAuthAdapter from environment variables:
+//
+//	CONFIG_APIKEY_FALLBACK_PROVIDER  the provider for every other token
+//	                                 (firebase, password, mock); none when empty
+//	CONFIG_APIKEY_PEPPER             the server-side key of the secret hashes
+//	CONFIG_APIKEY_TABLE              the key table (api_key)
+//	CONFIG_APIKEY_TOUCH_INTERVAL     how often last_used_at is rewritten (1m)
+//
+// Like the password provider it opens no database connection; the keys'
+// database is injected later via SetOperations.
+func buildFromEnv() (ports.AuthProvider, error) {
+	var fallback ports.AuthProvider
+	if name := strings.ToLower(strings.TrimSpace(os.Getenv("CONFIG_APIKEY_FALLBACK_PROVIDER"))); name != "" {
+		if name == "apikey" {
+			return nil, fmt.Errorf("apikey: CONFIG_APIKEY_FALLBACK_PROVIDER cannot be apikey")
+		}
+		provider, err := registry.BuildAuthProviderFromEnv(name)
+		if err != nil {
+			return nil, fmt.Errorf("apikey: failed to create fallback provider %q: %w", name, err)
+		}
+		fallback = provider
+	}
+
+	touchInterval := DefaultTouchInterval
+	if v := os.Getenv("CONFIG_APIKEY_TOUCH_INTERVAL"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("apikey: invalid CONFIG_APIKEY_TOUCH_INTERVAL %q", v)
+		}
+		touchInterval = parsed
+	}
+
+	adapter := NewAPIKeyAuthAdapter(fallback, os.Getenv("CONFIG_APIKEY_TABLE"), []byte(os.Getenv("CONFIG_APIKEY_PEPPER")), touchInterval)
+	config, _ := transformConfig(nil)
+	if err := adapter.Initialize(config); err != nil {
+		return nil, fmt.Errorf("apikey: failed to initialize: %w", err)
+	}
+	return adapter, nil
+}
+
+// transformConfig converts a raw config map to the API key proto config.
+func transformConfig(rawConfig map[string]any) (*authpb.ProviderConfig, error) {
+	return &authpb.ProviderConfig{
+		Enabled:     true,
+		Provider:    authpb.Provider_PROVIDER_CUSTOM,
+		DisplayName: "API Key Auth",
+		Config: &authpb.ProviderConfig_CustomConfig{
+			CustomConfig: &authpb.CustomProviderConfig{
+				ProviderName: "apikey",
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,486 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	dbinterfaces "github.com/erniealice/espyna-golang/database/interfaces"
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	authpb "github.com/erniealice/esqyma/pkg/schema/v1/infrastructure/auth"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Defaults for Config's zero values.
+const (
+	DefaultIssuer      = "espyna"
+	DefaultAccessTTL   = 15 * time.Minute
+	DefaultRefreshTTL  = 30 * 24 * time.Hour
+	DefaultMaxAttempts = 5
+	DefaultLockout     = 15 * time.Minute
+)
+
+// MinSecretLength is the shortest signing secret accepted, in bytes.
+const MinSecretLength = 32
+
+const (
+	// bcryptCost and minPasswordLength match the password provider, so
+	// either provider accepts the other's hashes.
+	bcryptCost        = 12
+	minPasswordLength = 8
+
+	userTable       = "user"
+	tokenTypeBearer = "Bearer"
+)
+
+// errNotInitialised is returned before SetOperations.
+var errNotInitialised = errors.New("jwt: database operations not initialised")
+
+// ErrInvalidCredentials is returned for a wrong email or password, an
+// inactive user and a locked account alike, so callers cannot tell them
+// apart.
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// dummyHash is compared against when the email is unknown, so a login takes
+// as long whether or not the user exists.
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("espyna-jwt-dummy"), bcryptCost)
+	return hash
+})
+
+// Config configures the adapter.
+type Config struct {
+	// Secret signs access tokens; at least MinSecretLength bytes.
+	Secret []byte
+	// PreviousSecrets still verify, while tokens they signed live out
+	// their TTL after a secret rotation.
+	PreviousSecrets [][]byte
+	Issuer          string
+	Audience        string
+	AccessTTL       time.Duration
+	RefreshTTL      time.Duration
+	// MaxAttempts failed logins lock an account for Lockout.
+	MaxAttempts int
+	Lockout     time.Duration
+	// RefreshTable and RevokedTable default to DefaultRefreshTable and
+	// DefaultRevokedTable.
+	RefreshTable string
+	RevokedTable string
+}
+
+// TokenPair is what a login or refresh returns.
+type TokenPair struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`
+}
+
+// JWTAuthAdapter implements ports.AuthProvider and ports.AuthService with
+// self-issued tokens.
+type JWTAuthAdapter struct {
+	config  Config
+	codec   *Codec
+	enabled bool
+
+	mu    sync.RWMutex
+	ops   dbinterfaces.DatabaseOperation
+	store *Store
+	now   func() time.Time
+}
+
+// NewJWTAuthAdapter creates the adapter. The caller must invoke
+// SetOperations and Initialize before use.
+func NewJWTAuthAdapter(config Config) (*JWTAuthAdapter, error) {
+	if len(config.Secret) < MinSecretLength {
+		return nil, fmt.Errorf("jwt: the signing secret must be at least %d bytes", MinSecretLength)
+	}
+	if config.Issuer == "" {
+		config.Issuer = DefaultIssuer
+	}
+	if config.AccessTTL <= 0 {
+		config.AccessTTL = DefaultAccessTTL
+	}
+	if config.RefreshTTL <= 0 {
+		config.RefreshTTL = DefaultRefreshTTL
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.Lockout <= 0 {
+		config.Lockout = DefaultLockout
+	}
+	return &JWTAuthAdapter{
+		config: config,
+		codec:  NewCodec(config.Secret, config.PreviousSecrets, config.Issuer, config.Audience),
+		now:    time.Now,
+	}, nil
+}
+
+// Name returns the provider name.
+func (a *JWTAuthAdapter) Name() string {
+	return "jwt"
+}
+
+// Initialize sets up the adapter with proto-based configuration.
+func (a *JWTAuthAdapter) Initialize(config *authpb.ProviderConfig) error {
+	if config == nil {
+		return fmt.Errorf("configuration is required")
+	}
+	a.enabled = config.Enabled
+	if a.enabled {
+		log.Println("[OK] JWT auth provider initialized")
+	} else {
+		log.Println("[AUTH] JWT auth is disabled")
+	}
+	return nil
+}
+
+// SetOperations injects the database holding the users and tokens.
+func (a *JWTAuthAdapter) SetOperations(ops dbinterfaces.DatabaseOperation) {
+	store := NewStore(ops, a.config.RefreshTable, a.config.RevokedTable)
+	store.now = a.now
+	a.mu.Lock()
+	a.ops, a.store = ops, store
+	a.mu.Unlock()
+}
+
+// Store returns the token store; nil until SetOperations.
+func (a *JWTAuthAdapter) Store() *Store {
+	_, store := a.deps()
+	return store
+}
+
+// GetAuthService returns the authentication service (returns itself).
+func (a *JWTAuthAdapter) GetAuthService() ports.AuthService {
+	if !a.enabled {
+		return nil
+	}
+	return a
+}
+
+// IsHealthy returns nil when the adapter is enabled and has a database.
+func (a *JWTAuthAdapter) IsHealthy(ctx context.Context) error {
+	if !a.enabled {
+		return fmt.Errorf("jwt provider is not enabled")
+	}
+	if ops, _ := a.deps(); ops == nil {
+		return errNotInitialised
+	}
+	return nil
+}
+
+// Close disables the adapter.
+func (a *JWTAuthAdapter) Close() error {
+	if a.enabled {
+		log.Println("[AUTH] Closing JWT auth provider")
+		a.enabled = false
+	}
+	return nil
+}
+
+// IsEnabled returns whether the adapter is enabled.
+func (a *JWTAuthAdapter) IsEnabled() bool {
+	return a.enabled
+}
+
+// =============================================================================
+// AuthService interface
+// =============================================================================
+
+// VerifyToken validates an access token: its signature, issuer, audience,
+// expiry and the revocation list. The identity comes from the token's
+// claims; the user table is not read.
+func (a *JWTAuthAdapter) VerifyToken(ctx context.Context, req *authpb.ValidateJwtTokenRequest) (*authpb.ValidateJwtTokenResponse, error) {
+	if !a.enabled {
+		return invalid(authpb.ValidationErrorType_VALIDATION_ERROR_TYPE_UNSPECIFIED, "Authentication service is disabled"), nil
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(req.GetToken(), "Bearer "))
+	claims, err := a.Verify(ctx, token)
+	if err != nil {
+		kind := authpb.ValidationErrorType_VALIDATION_ERROR_TYPE_MALFORMED
+		if errors.Is(err, ErrTokenExpired) || errors.Is(err, ErrTokenRevoked) {
+			kind = authpb.ValidationErrorType_VALIDATION_ERROR_TYPE_EXPIRED
+		}
+		return invalid(kind, err.Error()), nil
+	}
+
+	issuedAt := time.Unix(claims.IssuedAt, 0)
+	return &authpb.ValidateJwtTokenResponse{
+		IsValid: true,
+		Token: &authpb.JwtToken{
+			Token:     token,
+			TokenType: tokenTypeBearer,
+			IssuedAt:  timestamppb.New(issuedAt),
+			ExpiresAt: timestamppb.New(time.Unix(claims.ExpiresAt, 0)),
+			Issuer:    claims.Issuer,
+			Subject:   claims.Subject,
+			Provider:  authpb.Provider_PROVIDER_CUSTOM,
+		},
+		Identity: &authpb.Identity{
+			Id:       claims.Subject,
+			Type:     authpb.IdentityType_IDENTITY_TYPE_USER,
+			Provider: authpb.Provider_PROVIDER_CUSTOM,
+			Email:    claims.Email,
+			IsActive: true,
+		},
+	}, nil
+}
+
+// Verify returns the claims of a valid, unrevoked access token.
+func (a *JWTAuthAdapter) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims, err := a.codec.Parse(token, a.now())
+	if err != nil {
+		return nil, err
+	}
+	_, store := a.deps()
+	if store == nil {
+		return nil, errNotInitialised
+	}
+	if store.Revoked(ctx, claims) {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// GetProviderName implements the AuthService interface.
+func (a *JWTAuthAdapter) GetProviderName() string {
+	return "jwt"
+}
+
+// Authenticate checks email and password against the user table and
+// starts a session: a new refresh token family and its first access token.
+// Failed attempts count towards the account's lockout, as with the
+// password provider.
+func (a *JWTAuthAdapter) Authenticate(ctx context.Context, email, password string) (*TokenPair, error) {
+	ops, store := a.deps()
+	if ops == nil {
+		return nil, errNotInitialised
+	}
+	sys := systemContext(ctx)
+	row, err := ops.QueryOne(sys, userTable,
+		dbinterfaces.NewQueryBuilder().
+			WhereEqualTo("email_address", strings.TrimSpace(email)).
+			WhereEqualTo("active", true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up user: %w", err)
+	}
+	if row == nil {
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+
+	userID := str(row["id"])
+	if lockedUntil := timeValue(row["locked_until"]); !lockedUntil.IsZero() && a.now().Before(lockedUntil) {
+		return nil, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(str(row["password_hash"])), []byte(password)); err != nil {
+		a.recordFailedAttempt(ctx, userID, int(operations.Number(row["failed_login_attempts"])))
+		return nil, ErrInvalidCredentials
+	}
+	if operations.Number(row["failed_login_attempts"]) > 0 {
+		if _, err := ops.Update(sys, userTable, userID, map[string]any{
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+		}); err != nil {
+			log.Printf("[AUTH] failed to reset login attempt counter for user %s: %v", userID, err)
+		}
+	}
+
+	refresh, err := store.Issue(ctx, userID, "", a.config.RefreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	return a.pair(userID, str(row["email_address"]), refresh)
+}
+
+// Refresh rotates a refresh token and returns a new pair. Presenting a
+// token that was already rotated revokes its session and returns
+// ErrRefreshReused.
+func (a *JWTAuthAdapter) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	ops, store := a.deps()
+	if ops == nil {
+		return nil, errNotInitialised
+	}
+	userID, next, err := store.Rotate(ctx, refreshToken, a.config.RefreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	row, err := ops.Read(systemContext(ctx), userTable, userID)
+	if err != nil || row == nil {
+		return nil, ErrInvalidCredentials
+	}
+	if active, _ := row["active"].(bool); !active {
+		_ = store.RevokeRefresh(ctx, next)
+		return nil, ErrInvalidCredentials
+	}
+	return a.pair(userID, str(row["email_address"]), next)
+}
+
+// Revoke signs a session out: the access token goes on the revocation list
+// and the refresh token's family is revoked. Either may be empty.
+func (a *JWTAuthAdapter) Revoke(ctx context.Context, accessToken, refreshToken string) error {
+	_, store := a.deps()
+	if store == nil {
+		return errNotInitialised
+	}
+	if accessToken != "" {
+		claims, err := a.codec.Parse(accessToken, a.now())
+		if errors.Is(err, ErrTokenExpired) {
+			claims = nil // dead already
+		} else if err != nil {
+			return err
+		}
+		if claims != nil {
+			if err := store.RevokeAccess(ctx, claims); err != nil {
+				return err
+			}
+		}
+	}
+	if refreshToken != "" {
+		return store.RevokeRefresh(ctx, refreshToken)
+	}
+	return nil
+}
+
+// RevokeUser signs userID out of every session.
+func (a *JWTAuthAdapter) RevokeUser(ctx context.Context, userID string) error {
+	_, store := a.deps()
+	if store == nil {
+		return errNotInitialised
+	}
+	return store.RevokeUser(ctx, userID, a.config.AccessTTL)
+}
+
+// ChangePassword updates the password for an authenticated user after
+// checking oldPassword. Sessions are kept.
+func (a *JWTAuthAdapter) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error {
+	ops, _ := a.deps()
+	if ops == nil {
+		return errNotInitialised
+	}
+	sys := systemContext(ctx)
+	row, err := ops.Read(sys, userTable, userID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	if row == nil {
+		return fmt.Errorf("user not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(str(row["password_hash"])), []byte(oldPassword)); err != nil {
+		return fmt.Errorf("current password is incorrect")
+	}
+	hash, err := a.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if _, err := ops.Update(sys, userTable, userID, map[string]any{"password_hash": hash}); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
+
+// HashPassword hashes a plaintext password the way Authenticate expects.
+func (a *JWTAuthAdapter) HashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// =============================================================================
+// Internal helpers
+// =============================================================================
+
+func (a *JWTAuthAdapter) deps() (dbinterfaces.DatabaseOperation, *Store) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.ops, a.store
+}
+
+// pair signs an access token for the user to go with refresh.
+func (a *JWTAuthAdapter) pair(userID, email, refresh string) (*TokenPair, error) {
+	jti, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	now := a.now()
+	access, err := a.codec.Sign(Claims{
+		ID:        jti,
+		Subject:   userID,
+		Email:     email,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(a.config.AccessTTL).Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sign access token: %w", err)
+	}
+	return &TokenPair{
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		TokenType:        tokenTypeBearer,
+		ExpiresIn:        int64(a.config.AccessTTL / time.Second),
+		RefreshExpiresIn: int64(a.config.RefreshTTL / time.Second),
+	}, nil
+}
+
+// recordFailedAttempt counts a failed login and locks the account at
+// MaxAttempts. It is read-modify-write, like the password provider's
+// fallback path.
+func (a *JWTAuthAdapter) recordFailedAttempt(ctx context.Context, userID string, attempts int) {
+	ops, _ := a.deps()
+	attempts++
+	data := map[string]any{"failed_login_attempts": attempts}
+	if attempts >= a.config.MaxAttempts {
+		data["locked_until"] = a.now().Add(a.config.Lockout)
+	}
+	if _, err := ops.Update(systemContext(ctx), userTable, userID, data); err != nil {
+		log.Printf("[AUTH] failed to update login attempt counter for user %s: %v", userID, err)
+	}
+}
+
+func newID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate token id: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// timeValue reads a TIMESTAMPTZ column, which may be nil.
+func timeValue(v any) time.Time {
+	switch t := v.(type) {
+	case time.Time:
+		return t
+	case string:
+		parsed, _ := time.Parse(time.RFC3339, t)
+		return parsed
+	}
+	return time.Time{}
+}
+
+func invalid(kind authpb.ValidationErrorType, message string) *authpb.ValidateJwtTokenResponse {
+	return &authpb.ValidateJwtTokenResponse{
+		IsValid:          false,
+		ErrorMessage:     message,
+		ValidationErrors: []*authpb.ValidationError{{Type: kind, Message: message}},
+	}
+}
+
+// Compile-time checks that JWTAuthAdapter satisfies both interfaces.
+var _ ports.AuthProvider = (*JWTAuthAdapter)(nil)
+var _ ports.AuthService = (*JWTAuthAdapter)(nil)
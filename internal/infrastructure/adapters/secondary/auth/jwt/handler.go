@@ -0,0 +1,138 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// The token endpoints. They authenticate by what they are sent, so they
+// sit outside the authentication middleware:
+//
+//	POST TokenPath    {"email", "password"}: a new session's TokenPair
+//	POST RefreshPath  {"refresh_token"}: the next TokenPair; the token sent
+//	                  is spent
+//	POST RevokePath   {"refresh_token"} with the access token as Bearer,
+//	                  either optional: signs the session out
+const (
+	TokenPath   = "/auth/token"
+	RefreshPath = "/auth/token/refresh"
+	RevokePath  = "/auth/token/revoke"
+)
+
+// maxBodyBytes bounds a token request body.
+const maxBodyBytes = 1 << 16
+
+type tokenRequest struct {
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Handlers serves the token endpoints.
+type Handlers struct {
+	adapter *JWTAuthAdapter
+}
+
+// NewHandlers creates the endpoint handlers over adapter.
+func NewHandlers(adapter *JWTAuthAdapter) *Handlers {
+	return &Handlers{adapter: adapter}
+}
+
+// Token signs a user in with email and password.
+func (h *Handlers) Token(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.Email == "" || req.Password == "" {
+		writeError(w, http.StatusBadRequest, "email and password are required")
+		return
+	}
+	pair, err := h.adapter.Authenticate(r.Context(), req.Email, req.Password)
+	if errors.Is(err, ErrInvalidCredentials) {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("[AUTH] jwt login failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "sign-in failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": pair})
+}
+
+// Refresh exchanges a refresh token for the next pair.
+func (h *Handlers) Refresh(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.RefreshToken == "" {
+		writeError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+	pair, err := h.adapter.Refresh(r.Context(), req.RefreshToken)
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": pair})
+	case errors.Is(err, ErrInvalidToken), errors.Is(err, ErrTokenExpired), errors.Is(err, ErrTokenRevoked),
+		errors.Is(err, ErrRefreshReused), errors.Is(err, ErrInvalidCredentials):
+		writeError(w, http.StatusUnauthorized, err.Error())
+	default:
+		log.Printf("[AUTH] jwt refresh failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "refresh failed")
+	}
+}
+
+// Revoke signs a session out. Revoking an unknown or expired token
+// succeeds: either way the token no longer works.
+func (h *Handlers) Revoke(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeRequest(w, r)
+	if !ok {
+		return
+	}
+	access := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	if access == "" && req.RefreshToken == "" {
+		writeError(w, http.StatusBadRequest, "an access or refresh token is required")
+		return
+	}
+	err := h.adapter.Revoke(r.Context(), access, req.RefreshToken)
+	if err != nil && !errors.Is(err, ErrInvalidToken) {
+		log.Printf("[AUTH] jwt revoke failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "revoke failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+// decodeRequest reads a POST body; an empty body is an empty request.
+func decodeRequest(w http.ResponseWriter, r *http.Request) (tokenRequest, bool) {
+	var req tokenRequest
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return req, false
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return req, false
+		}
+	}
+	req.Email = strings.TrimSpace(req.Email)
+	req.RefreshToken = strings.TrimSpace(req.RefreshToken)
+	return req, true
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"success": false, "error": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,271 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	authpb "github.com/erniealice/esqyma/pkg/schema/v1/infrastructure/auth"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memOps keeps rows per table; List honours string equality filters and
+// QueryOne returns the active user.
+type memOps struct {
+	interfaces.DatabaseOperation
+	tables map[string]map[string]map[string]any
+	nextID int
+}
+
+func newMemOps() *memOps {
+	return &memOps{tables: map[string]map[string]map[string]any{}}
+}
+
+func (o *memOps) table(name string) map[string]map[string]any {
+	if o.tables[name] == nil {
+		o.tables[name] = map[string]map[string]any{}
+	}
+	return o.tables[name]
+}
+
+func (o *memOps) Create(_ context.Context, table string, data map[string]any) (map[string]any, error) {
+	row := map[string]any{}
+	for k, v := range data {
+		row[k] = v
+	}
+	if row["id"] == nil {
+		o.nextID++
+		row["id"] = fmt.Sprintf("row-%d", o.nextID)
+	}
+	id := row["id"].(string)
+	if _, exists := o.table(table)[id]; exists {
+		return nil, model.NewDatabaseError("duplicate key", "DUPLICATE", 409)
+	}
+	o.table(table)[id] = row
+	return row, nil
+}
+
+func (o *memOps) Read(_ context.Context, table, id string) (map[string]any, error) {
+	row, ok := o.table(table)[id]
+	if !ok {
+		return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return row, nil
+}
+
+func (o *memOps) Update(_ context.Context, table, id string, data map[string]any) (map[string]any, error) {
+	row := o.table(table)[id]
+	for k, v := range data {
+		row[k] = v
+	}
+	return row, nil
+}
+
+func (o *memOps) List(_ context.Context, table string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	var rows []map[string]any
+	for _, row := range o.table(table) {
+		match := true
+		for _, f := range params.Filters.Filters {
+			if row[f.Field] != f.GetStringFilter().GetValue() {
+				match = false
+			}
+		}
+		if match {
+			rows = append(rows, row)
+		}
+	}
+	return &interfaces.ListResult{Data: rows}, nil
+}
+
+func (o *memOps) QueryOne(_ context.Context, table string, _ interfaces.QueryBuilder) (map[string]any, error) {
+	// The tests keep one user per table; the adapter filters by email and
+	// active, which the tests flip directly.
+	for _, row := range o.table(table) {
+		if active, _ := row["active"].(bool); active {
+			return row, nil
+		}
+	}
+	return nil, nil
+}
+
+var secret = []byte(strings.Repeat("s", MinSecretLength))
+
+func newAdapter(t *testing.T) (*JWTAuthAdapter, *memOps) {
+	t.Helper()
+	a, err := NewJWTAuthAdapter(Config{Secret: secret, Audience: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = a.Initialize(&authpb.ProviderConfig{Enabled: true})
+	ops := newMemOps()
+	a.SetOperations(ops)
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	ops.table(userTable)["ada"] = map[string]any{
+		"id": "ada", "email_address": "ada@example.com", "password_hash": string(hash), "active": true,
+	}
+	return a, ops
+}
+
+func TestCodec(t *testing.T) {
+	now := time.Now()
+	k := NewCodec(secret, nil, "espyna", "app")
+	token, err := k.Sign(Claims{ID: "j1", Subject: "ada", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, err := k.Parse(token, now); err != nil || c.Subject != "ada" || c.Issuer != "espyna" {
+		t.Fatalf("Parse = %+v, %v", c, err)
+	}
+	if _, err := k.Parse(token, now.Add(2*time.Minute)); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expired = %v", err)
+	}
+	if _, err := NewCodec(secret, nil, "espyna", "other").Parse(token, now); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("wrong audience = %v", err)
+	}
+
+	rotated := NewCodec([]byte(strings.Repeat("n", MinSecretLength)), [][]byte{secret}, "espyna", "app")
+	if _, err := rotated.Parse(token, now); err != nil {
+		t.Errorf("previous secret = %v", err)
+	}
+	if _, err := NewCodec([]byte(strings.Repeat("n", MinSecretLength)), nil, "espyna", "app").Parse(token, now); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("wrong secret = %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	none := encode([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + parts[1] + "." + parts[2]
+	if _, err := k.Parse(none, now); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("alg none = %v", err)
+	}
+}
+
+func TestAdapter_LoginRefreshRevoke(t *testing.T) {
+	a, ops := newAdapter(t)
+	ctx := context.Background()
+
+	if _, err := a.Authenticate(ctx, "ada@example.com", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("wrong password = %v", err)
+	}
+	if got := ops.table(userTable)["ada"]["failed_login_attempts"]; got != 1 {
+		t.Errorf("failed_login_attempts = %v", got)
+	}
+	pair, err := a.Authenticate(ctx, "ada@example.com", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, _ := a.VerifyToken(ctx, &authpb.ValidateJwtTokenRequest{Token: "Bearer " + pair.AccessToken})
+	if !resp.IsValid || resp.Identity.Id != "ada" || resp.Identity.Email != "ada@example.com" {
+		t.Fatalf("VerifyToken = %+v", resp)
+	}
+
+	next, err := a.Refresh(ctx, pair.RefreshToken)
+	if err != nil || next.RefreshToken == pair.RefreshToken {
+		t.Fatalf("Refresh = %+v, %v", next, err)
+	}
+	// Replaying the spent token revokes the family, the successor too.
+	if _, err := a.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrRefreshReused) {
+		t.Errorf("reuse = %v", err)
+	}
+	if _, err := a.Refresh(ctx, next.RefreshToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("successor after reuse = %v", err)
+	}
+
+	second, _ := a.Authenticate(ctx, "ada@example.com", "correct horse")
+	if err := a.Revoke(ctx, second.AccessToken, second.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Verify(ctx, second.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("revoked access token = %v", err)
+	}
+	if _, err := a.Refresh(ctx, second.RefreshToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("revoked refresh token = %v", err)
+	}
+	if _, err := a.Verify(ctx, pair.AccessToken); err != nil {
+		t.Errorf("other session's access token = %v", err)
+	}
+}
+
+func TestAdapter_RevokeUserAndLockout(t *testing.T) {
+	a, ops := newAdapter(t)
+	ctx := context.Background()
+	now := time.Now()
+	a.now = func() time.Time { return now }
+	a.store.now = a.now
+
+	pair, _ := a.Authenticate(ctx, "ada@example.com", "correct horse")
+	if err := a.RevokeUser(ctx, "ada"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Verify(ctx, pair.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("access token after RevokeUser = %v", err)
+	}
+	if _, err := a.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("refresh token after RevokeUser = %v", err)
+	}
+	now = now.Add(2 * time.Second)
+	fresh, _ := a.Authenticate(ctx, "ada@example.com", "correct horse")
+	if _, err := a.Verify(ctx, fresh.AccessToken); err != nil {
+		t.Errorf("token issued after RevokeUser = %v", err)
+	}
+
+	for i := 0; i < DefaultMaxAttempts; i++ {
+		_, _ = a.Authenticate(ctx, "ada@example.com", "wrong")
+	}
+	if _, err := a.Authenticate(ctx, "ada@example.com", "correct horse"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("locked account = %v", err)
+	}
+	now = now.Add(DefaultLockout + time.Second)
+	if _, err := a.Authenticate(ctx, "ada@example.com", "correct horse"); err != nil {
+		t.Errorf("after lockout = %v", err)
+	}
+	if got := ops.table(userTable)["ada"]["failed_login_attempts"]; got != 0 {
+		t.Errorf("failed_login_attempts after login = %v", got)
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	a, _ := newAdapter(t)
+	h := NewHandlers(a)
+	call := func(handler http.HandlerFunc, body, bearer string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodPost, TokenPath, bytes.NewBufferString(body))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		var resp map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, resp
+	}
+
+	if code, _ := call(h.Token, `{"email": "ada@example.com", "password": "nope"}`, ""); code != http.StatusUnauthorized {
+		t.Errorf("bad login = %d", code)
+	}
+	code, resp := call(h.Token, `{"email": "ada@example.com", "password": "correct horse"}`, "")
+	data, _ := resp["data"].(map[string]any)
+	if code != http.StatusOK || data["token_type"] != "Bearer" {
+		t.Fatalf("login = %d %v", code, resp)
+	}
+	code, resp = call(h.Refresh, fmt.Sprintf(`{"refresh_token": %q}`, data["refresh_token"]), "")
+	next, _ := resp["data"].(map[string]any)
+	if code != http.StatusOK {
+		t.Fatalf("refresh = %d %v", code, resp)
+	}
+	if code, _ := call(h.Refresh, `{"refresh_token": "rt_x_y"}`, ""); code != http.StatusUnauthorized {
+		t.Errorf("unknown refresh token = %d", code)
+	}
+	if code, _ := call(h.Revoke, fmt.Sprintf(`{"refresh_token": %q}`, next["refresh_token"]), fmt.Sprint(next["access_token"])); code != http.StatusOK {
+		t.Errorf("revoke = %d", code)
+	}
+	if _, err := a.Verify(context.Background(), fmt.Sprint(next["access_token"])); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("access token after revoke = %v", err)
+	}
+}
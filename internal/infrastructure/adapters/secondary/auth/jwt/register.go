@@ -0,0 +1,93 @@
+//go:build jwt_auth
+
+package jwt
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	authpb "github.com/erniealice/esqyma/pkg/schema/v1/infrastructure/auth"
+)
+
+// =============================================================================
+// Self-Registration - Adapter registers itself with the factory
+// =============================================================================
+
+func init() {
+	registry.RegisterAuthProvider(
+		"jwt",
+		func() ports.AuthProvider {
+			adapter, err := buildFromEnv()
+			if err != nil {
+				panic(fmt.Sprintf("FATAL: %v", err))
+			}
+			return adapter
+		},
+		transformConfig,
+	)
+	registry.RegisterAuthBuildFromEnv("jwt", buildFromEnv)
+}
+
+// buildFromEnv creates a JWTAuthAdapter from environment variables:
+//
+//	CONFIG_JWT_SECRET           the HS256 signing secret, at least 32 bytes
+//	CONFIG_JWT_PREVIOUS_SECRET  the secret before the last rotation; its
+//	                            tokens still verify
+//	CONFIG_JWT_ISSUER           the iss claim (espyna)
+//	CONFIG_JWT_AUDIENCE         the aud claim; none when empty
+//	CONFIG_JWT_ACCESS_TTL       access token lifetime (15m)
+//	CONFIG_JWT_REFRESH_TTL      refresh token lifetime (720h)
+//
+// Like the password provider it opens no database connection; the users'
+// and tokens' database is injected later via SetOperations.
+func buildFromEnv() (ports.AuthProvider, error) {
+	config := Config{
+		Secret:   []byte(os.Getenv("CONFIG_JWT_SECRET")),
+		Issuer:   os.Getenv("CONFIG_JWT_ISSUER"),
+		Audience: os.Getenv("CONFIG_JWT_AUDIENCE"),
+	}
+	if previous := os.Getenv("CONFIG_JWT_PREVIOUS_SECRET"); previous != "" {
+		config.PreviousSecrets = [][]byte{[]byte(previous)}
+	}
+	for name, target := range map[string]*time.Duration{
+		"CONFIG_JWT_ACCESS_TTL":  &config.AccessTTL,
+		"CONFIG_JWT_REFRESH_TTL": &config.RefreshTTL,
+	} {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("jwt: invalid %s %q", name, v)
+		}
+		*target = parsed
+	}
+
+	adapter, err := NewJWTAuthAdapter(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w (CONFIG_JWT_SECRET)", err)
+	}
+	protoConfig, _ := transformConfig(nil)
+	if err := adapter.Initialize(protoConfig); err != nil {
+		return nil, fmt.Errorf("jwt: failed to initialize: %w", err)
+	}
+	return adapter, nil
+}
+
+// transformConfig converts a raw config map to the JWT proto config.
+func transformConfig(rawConfig map[string]any) (*authpb.ProviderConfig, error) {
+	return &authpb.ProviderConfig{
+		Enabled:     true,
+		Provider:    authpb.Provider_PROVIDER_CUSTOM,
+		DisplayName: "JWT Auth",
+		Config: &authpb.ProviderConfig_CustomConfig{
+			CustomConfig: &authpb.CustomProviderConfig{
+				ProviderName: "jwt",
+			},
+		},
+	}, nil
+}
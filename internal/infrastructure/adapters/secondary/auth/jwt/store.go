@@ -0,0 +1,313 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	"github.com/erniealice/espyna-golang/shared/identity"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// DefaultRefreshTable and DefaultRevokedTable hold the refresh tokens and
+// the access token revocation list (see the postgres integration migration
+// 000017_auth_token).
+const (
+	DefaultRefreshTable = "auth_refresh_token"
+	DefaultRevokedTable = "auth_revoked_token"
+)
+
+// refreshPrefix starts every refresh token.
+const refreshPrefix = "rt_"
+
+// listLimit bounds the rows one read returns.
+const listLimit = 1000
+
+// ErrRefreshReused is returned for a refresh token that was already
+// rotated; its family has been revoked.
+var ErrRefreshReused = errors.New("refresh token was already used; its sessions have been revoked")
+
+// Store keeps the refresh tokens and the revocation list.
+type Store struct {
+	ops          interfaces.DatabaseOperation
+	refreshTable string
+	revokedTable string
+	now          func() time.Time
+}
+
+// NewStore creates the token store on the given tables (the defaults when
+// empty).
+func NewStore(ops interfaces.DatabaseOperation, refreshTable, revokedTable string) *Store {
+	if refreshTable == "" {
+		refreshTable = DefaultRefreshTable
+	}
+	if revokedTable == "" {
+		revokedTable = DefaultRevokedTable
+	}
+	return &Store{ops: ops, refreshTable: refreshTable, revokedTable: revokedTable, now: time.Now}
+}
+
+// refreshToken is a stored refresh token.
+type refreshToken struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	Hash      string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+	RevokedAt time.Time
+}
+
+// Issue creates a refresh token for userID valid for ttl. An empty
+// familyID starts a new family, i.e. a new sign-in.
+func (s *Store) Issue(ctx context.Context, userID, familyID string, ttl time.Duration) (string, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return "", err
+	}
+	now := s.now().UTC()
+	row, err := s.ops.Create(systemContext(ctx), s.refreshTable, map[string]any{
+		"user_id":     userID,
+		"family_id":   familyID,
+		"secret_hash": hashSecret(secret),
+		"created_at":  now.UnixMilli(),
+		"expires_at":  now.Add(ttl).UnixMilli(),
+		"used_at":     int64(0),
+		"revoked_at":  int64(0),
+	})
+	if err != nil {
+		return "", fmt.Errorf("issue refresh token: %w", err)
+	}
+	id := str(row["id"])
+	if familyID == "" {
+		// The first token of a family names it.
+		if _, err := s.ops.Update(systemContext(ctx), s.refreshTable, id, map[string]any{"family_id": id}); err != nil {
+			return "", fmt.Errorf("issue refresh token: %w", err)
+		}
+	}
+	return refreshPrefix + id + "_" + secret, nil
+}
+
+// Rotate spends token and issues its successor in the same family, valid
+// for ttl, returning the user and the new token. A token spent before
+// revokes the family and yields ErrRefreshReused.
+func (s *Store) Rotate(ctx context.Context, token string, ttl time.Duration) (userID, next string, err error) {
+	rt, err := s.lookup(ctx, token)
+	if err != nil {
+		return "", "", err
+	}
+	now := s.now()
+	if !rt.UsedAt.IsZero() {
+		if err := s.RevokeFamily(ctx, rt.FamilyID); err != nil {
+			return "", "", err
+		}
+		return "", "", ErrRefreshReused
+	}
+	if !rt.RevokedAt.IsZero() {
+		return "", "", ErrTokenRevoked
+	}
+	if !now.Before(rt.ExpiresAt) {
+		return "", "", ErrTokenExpired
+	}
+	if _, err := s.ops.Update(systemContext(ctx), s.refreshTable, rt.ID, map[string]any{"used_at": now.UTC().UnixMilli()}); err != nil {
+		return "", "", fmt.Errorf("rotate refresh token: %w", err)
+	}
+	next, err = s.Issue(ctx, rt.UserID, rt.FamilyID, ttl)
+	if err != nil {
+		return "", "", err
+	}
+	return rt.UserID, next, nil
+}
+
+// RevokeRefresh revokes the family of token: the sign-in it belongs to.
+func (s *Store) RevokeRefresh(ctx context.Context, token string) error {
+	rt, err := s.lookup(ctx, token)
+	if err != nil {
+		return err
+	}
+	return s.RevokeFamily(ctx, rt.FamilyID)
+}
+
+// RevokeFamily revokes every live token of a family.
+func (s *Store) RevokeFamily(ctx context.Context, familyID string) error {
+	return s.revokeWhere(ctx, "family_id", familyID)
+}
+
+// RevokeAccess puts an access token on the revocation list until it
+// expires.
+func (s *Store) RevokeAccess(ctx context.Context, c *Claims) error {
+	_, err := s.ops.Create(systemContext(ctx), s.revokedTable, map[string]any{
+		"id":         "jti:" + c.ID,
+		"user_id":    c.Subject,
+		"not_before": int64(0),
+		"expires_at": time.Unix(c.ExpiresAt, 0).Add(leeway).UnixMilli(),
+	})
+	if err != nil && !s.isRevoked(ctx, "jti:"+c.ID) {
+		return fmt.Errorf("revoke access token: %w", err)
+	}
+	return nil
+}
+
+// RevokeUser signs userID out everywhere: every refresh token is revoked
+// and every access token issued so far stops verifying.
+func (s *Store) RevokeUser(ctx context.Context, userID string, accessTTL time.Duration) error {
+	if err := s.revokeWhere(ctx, "user_id", userID); err != nil {
+		return err
+	}
+	now := s.now().UTC()
+	// Tokens carry whole seconds; cutting at the next second leaves none of
+	// this second's tokens behind.
+	notBefore := now.Truncate(time.Second).Add(time.Second)
+	data := map[string]any{
+		"user_id":    userID,
+		"not_before": notBefore.Unix(),
+		"expires_at": now.Add(accessTTL + leeway).UnixMilli(),
+	}
+	sys := systemContext(ctx)
+	id := "user:" + userID
+	if _, err := s.ops.Read(sys, s.revokedTable, id); err == nil {
+		_, err = s.ops.Update(sys, s.revokedTable, id, data)
+		if err != nil {
+			return fmt.Errorf("revoke user tokens: %w", err)
+		}
+		return nil
+	}
+	data["id"] = id
+	if _, err := s.ops.Create(sys, s.revokedTable, data); err != nil {
+		return fmt.Errorf("revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+// Revoked reports whether an access token is on the revocation list,
+// itself or through its user's cut-off.
+func (s *Store) Revoked(ctx context.Context, c *Claims) bool {
+	if s.isRevoked(ctx, "jti:"+c.ID) {
+		return true
+	}
+	row, err := s.ops.Read(systemContext(ctx), s.revokedTable, "user:"+c.Subject)
+	if err != nil || row == nil {
+		return false
+	}
+	return c.IssuedAt < int64(operations.Number(row["not_before"]))
+}
+
+func (s *Store) isRevoked(ctx context.Context, id string) bool {
+	row, err := s.ops.Read(systemContext(ctx), s.revokedTable, id)
+	return err == nil && row != nil
+}
+
+// lookup returns the stored token, checking its secret.
+func (s *Store) lookup(ctx context.Context, token string) (*refreshToken, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(token), refreshPrefix)
+	i := strings.LastIndexByte(rest, '_')
+	if !ok || i <= 0 || i == len(rest)-1 {
+		return nil, ErrInvalidToken
+	}
+	row, err := s.ops.Read(systemContext(ctx), s.refreshTable, rest[:i])
+	if err != nil || row == nil {
+		return nil, ErrInvalidToken
+	}
+	rt := refreshFromRow(row)
+	if subtle.ConstantTimeCompare([]byte(rt.Hash), []byte(hashSecret(rest[i+1:]))) != 1 {
+		return nil, ErrInvalidToken
+	}
+	return &rt, nil
+}
+
+func (s *Store) revokeWhere(ctx context.Context, field, value string) error {
+	sys := systemContext(ctx)
+	result, err := s.ops.List(sys, s.refreshTable, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{
+			stringEquals(field, value),
+		}},
+		Pagination: firstPage(listLimit),
+	})
+	if err != nil {
+		return fmt.Errorf("list refresh tokens: %w", err)
+	}
+	now := s.now().UTC().UnixMilli()
+	for _, row := range result.Data {
+		if str(row[field]) != value || operations.Number(row["revoked_at"]) > 0 {
+			continue
+		}
+		if _, err := s.ops.Update(sys, s.refreshTable, str(row["id"]), map[string]any{"revoked_at": now}); err != nil {
+			return fmt.Errorf("revoke refresh token: %w", err)
+		}
+	}
+	return nil
+}
+
+func refreshFromRow(row map[string]any) refreshToken {
+	return refreshToken{
+		ID:        str(row["id"]),
+		UserID:    str(row["user_id"]),
+		FamilyID:  str(row["family_id"]),
+		Hash:      str(row["secret_hash"]),
+		ExpiresAt: fromMillis(row["expires_at"]),
+		UsedAt:    fromMillis(row["used_at"]),
+		RevokedAt: fromMillis(row["revoked_at"]),
+	}
+}
+
+// hashSecret needs no salt or pepper: the secrets are 256 random bits.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func newSecret() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate refresh token: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// systemContext carries an identity without a workspace: tokens are
+// issued and checked before the request has one.
+func systemContext(ctx context.Context) context.Context {
+	return identity.WithRequestIdentity(ctx, &identity.RequestIdentity{})
+}
+
+func fromMillis(v any) time.Time {
+	if ms := int64(operations.Number(v)); ms > 0 {
+		return time.UnixMilli(ms).UTC()
+	}
+	return time.Time{}
+}
+
+func firstPage(limit int32) *commonpb.PaginationRequest {
+	return &commonpb.PaginationRequest{
+		Limit: limit,
+		Method: &commonpb.PaginationRequest_Offset{
+			Offset: &commonpb.OffsetPagination{Page: 1},
+		},
+	}
+}
+
+func stringEquals(field, value string) *commonpb.TypedFilter {
+	return &commonpb.TypedFilter{
+		Field: field,
+		FilterType: &commonpb.TypedFilter_StringFilter{
+			StringFilter: &commonpb.StringFilter{
+				Value:         value,
+				Operator:      commonpb.StringOperator_STRING_EQUALS,
+				CaseSensitive: true,
+			},
+		},
+	}
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
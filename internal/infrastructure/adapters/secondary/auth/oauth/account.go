@@ -0,0 +1,175 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// DefaultIdentityTable links provider identities to users (see the
+// postgres integration migration 000018_user_identity).
+const DefaultIdentityTable = "user_identity"
+
+// Provisioning decides what happens to an identity no user is linked to
+// and no user shares the verified email of.
+type Provisioning struct {
+	// Disabled refuses such identities with ErrProvisionRefused: only
+	// existing users can sign in.
+	Disabled bool
+	// DomainWorkspaces maps an email domain to the workspace its new users
+	// join.
+	DomainWorkspaces map[string]string
+	// DefaultWorkspaceID is the workspace other new users join; none when
+	// empty.
+	DefaultWorkspaceID string
+	// AllowedDomains, when set, limits provisioning to these email domains
+	// and those of DomainWorkspaces.
+	AllowedDomains []string
+}
+
+// Accounts resolves identities to users, linking and provisioning as
+// needed.
+type Accounts struct {
+	ops           interfaces.DatabaseOperation
+	identityTable string
+	provisioning  Provisioning
+	now           func() time.Time
+}
+
+// NewAccounts creates the resolver on the identity table (the default when
+// empty).
+func NewAccounts(ops interfaces.DatabaseOperation, identityTable string, provisioning Provisioning) *Accounts {
+	if identityTable == "" {
+		identityTable = DefaultIdentityTable
+	}
+	return &Accounts{ops: ops, identityTable: identityTable, provisioning: provisioning, now: time.Now}
+}
+
+// Resolve returns the user id signs in as. In order: the user the identity
+// is linked to; the active user with the identity's verified email, who is
+// then linked; a new user, provisioned with a workspace_user and linked.
+// created reports the last case.
+func (a *Accounts) Resolve(ctx context.Context, id *Identity) (userID string, created bool, err error) {
+	sys := systemContext(ctx)
+	linkID := id.Provider + ":" + id.Subject
+	if row, err := a.ops.Read(sys, a.identityTable, linkID); err == nil && row != nil {
+		userID := str(row["user_id"])
+		user, err := a.ops.Read(sys, "user", userID)
+		if err != nil || user == nil {
+			return "", false, fmt.Errorf("oauth: linked user %s not found", userID)
+		}
+		if active, _ := user["active"].(bool); !active {
+			return "", false, ErrProvisionRefused
+		}
+		return userID, false, nil
+	}
+
+	if id.Email == "" || !id.EmailVerified {
+		return "", false, ErrEmailUnverified
+	}
+	user, err := a.ops.QueryOne(sys, "user", interfaces.NewQueryBuilder().
+		WhereEqualTo("email_address", id.Email).
+		WhereEqualTo("active", true))
+	if err != nil {
+		return "", false, fmt.Errorf("oauth: look up user: %w", err)
+	}
+	if user != nil {
+		userID = str(user["id"])
+	} else {
+		workspaceID, ok := a.workspaceFor(id.Email)
+		if !ok {
+			return "", false, ErrProvisionRefused
+		}
+		if userID, err = a.provision(sys, id, workspaceID); err != nil {
+			return "", false, err
+		}
+		created = true
+	}
+
+	if _, err := a.ops.Create(sys, a.identityTable, map[string]any{
+		"id":            linkID,
+		"user_id":       userID,
+		"provider":      id.Provider,
+		"subject":       id.Subject,
+		"email_address": id.Email,
+		"created_at":    a.now().UTC().UnixMilli(),
+	}); err != nil {
+		// A concurrent first sign-in of the same identity linked it first.
+		if row, readErr := a.ops.Read(sys, a.identityTable, linkID); readErr == nil && row != nil {
+			if created {
+				log.Printf("[AUTH] oauth: user %s provisioned for %s lost a concurrent sign-in", userID, linkID)
+			}
+			return str(row["user_id"]), false, nil
+		}
+		return "", false, fmt.Errorf("oauth: link identity: %w", err)
+	}
+	return userID, created, nil
+}
+
+// workspaceFor returns the workspace a new user with email joins, and
+// whether one may be provisioned at all.
+func (a *Accounts) workspaceFor(email string) (string, bool) {
+	p := a.provisioning
+	if p.Disabled {
+		return "", false
+	}
+	domain := email[strings.LastIndexByte(email, '@')+1:]
+	if ws, ok := p.DomainWorkspaces[domain]; ok {
+		return ws, true
+	}
+	if len(p.AllowedDomains) > 0 {
+		allowed := false
+		for _, d := range p.AllowedDomains {
+			allowed = allowed || strings.EqualFold(d, domain)
+		}
+		if !allowed {
+			return "", false
+		}
+	}
+	return p.DefaultWorkspaceID, true
+}
+
+// provision creates the user and, with a workspace, its workspace_user.
+func (a *Accounts) provision(ctx context.Context, id *Identity, workspaceID string) (string, error) {
+	first, last := id.GivenName, id.FamilyName
+	if first == "" && last == "" {
+		first, last, _ = strings.Cut(strings.TrimSpace(id.Name), " ")
+	}
+	user, err := a.ops.Create(ctx, "user", map[string]any{
+		"first_name":    first,
+		"last_name":     last,
+		"email_address": id.Email,
+		"active":        true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("oauth: create user: %w", err)
+	}
+	userID := str(user["id"])
+	if workspaceID != "" {
+		if _, err := a.ops.Create(ctx, "workspace_user", map[string]any{
+			"workspace_id": workspaceID,
+			"user_id":      userID,
+			"active":       true,
+		}); err != nil {
+			return "", fmt.Errorf("oauth: add user %s to workspace %s: %w", userID, workspaceID, err)
+		}
+	}
+	log.Printf("[AUTH] oauth: provisioned user %s from %s (workspace %q)", userID, id.Provider, workspaceID)
+	return userID, nil
+}
+
+// systemContext carries an identity without a workspace: sign-in happens
+// before the request has one.
+func systemContext(ctx context.Context) context.Context {
+	return identity.WithRequestIdentity(ctx, &identity.RequestIdentity{})
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,89 @@
+package oauth
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ConfigFromEnv reads the flow's configuration:
+//
+//	CONFIG_OAUTH_REDIRECT_URL           absolute URL of CallbackPath
+//	CONFIG_OAUTH_STATE_SECRET           signs the state cookie, 32+ bytes
+//	CONFIG_OAUTH_GOOGLE_CLIENT_ID       Google, when set
+//	CONFIG_OAUTH_GOOGLE_CLIENT_SECRET
+//	CONFIG_OAUTH_MICROSOFT_CLIENT_ID    Microsoft, when set
+//	CONFIG_OAUTH_MICROSOFT_CLIENT_SECRET
+//	CONFIG_OAUTH_MICROSOFT_TENANT       tenant ID or domain (organizations)
+//	CONFIG_OAUTH_AUTO_PROVISION         create unknown users (true)
+//	CONFIG_OAUTH_DEFAULT_WORKSPACE_ID   workspace new users join
+//	CONFIG_OAUTH_DOMAIN_WORKSPACES      per domain: example.com=ws-1,...
+//	CONFIG_OAUTH_ALLOWED_DOMAINS        limit provisioning: example.com,...
+//	CONFIG_OAUTH_SECURE_COOKIE          Secure state cookie (true)
+//	CONFIG_OAUTH_SUCCESS_URL            after sign-in (/)
+//	CONFIG_OAUTH_FAILURE_URL            after a failure (/auth/login)
+//
+// It returns ok false when no provider is configured.
+func ConfigFromEnv() (config Config, ok bool, err error) {
+	if id := os.Getenv("CONFIG_OAUTH_GOOGLE_CLIENT_ID"); id != "" {
+		config.Providers = append(config.Providers, Google(id, os.Getenv("CONFIG_OAUTH_GOOGLE_CLIENT_SECRET")))
+	}
+	if id := os.Getenv("CONFIG_OAUTH_MICROSOFT_CLIENT_ID"); id != "" {
+		config.Providers = append(config.Providers, Microsoft(
+			os.Getenv("CONFIG_OAUTH_MICROSOFT_TENANT"), id, os.Getenv("CONFIG_OAUTH_MICROSOFT_CLIENT_SECRET")))
+	}
+	if len(config.Providers) == 0 {
+		return config, false, nil
+	}
+
+	config.RedirectURL = os.Getenv("CONFIG_OAUTH_REDIRECT_URL")
+	config.StateSecret = []byte(os.Getenv("CONFIG_OAUTH_STATE_SECRET"))
+	config.SuccessURL = os.Getenv("CONFIG_OAUTH_SUCCESS_URL")
+	config.FailureURL = os.Getenv("CONFIG_OAUTH_FAILURE_URL")
+	if config.SecureCookie, err = envBool("CONFIG_OAUTH_SECURE_COOKIE", true); err != nil {
+		return config, true, err
+	}
+	provision, err := envBool("CONFIG_OAUTH_AUTO_PROVISION", true)
+	if err != nil {
+		return config, true, err
+	}
+	config.Provisioning = Provisioning{
+		Disabled:           !provision,
+		DefaultWorkspaceID: strings.TrimSpace(os.Getenv("CONFIG_OAUTH_DEFAULT_WORKSPACE_ID")),
+		AllowedDomains:     splitList(os.Getenv("CONFIG_OAUTH_ALLOWED_DOMAINS")),
+	}
+	if v := os.Getenv("CONFIG_OAUTH_DOMAIN_WORKSPACES"); v != "" {
+		config.Provisioning.DomainWorkspaces = map[string]string{}
+		for _, pair := range splitList(v) {
+			domain, ws, found := strings.Cut(pair, "=")
+			if !found || strings.TrimSpace(domain) == "" || strings.TrimSpace(ws) == "" {
+				return config, true, fmt.Errorf("oauth: invalid CONFIG_OAUTH_DOMAIN_WORKSPACES entry %q", pair)
+			}
+			config.Provisioning.DomainWorkspaces[strings.ToLower(strings.TrimSpace(domain))] = strings.TrimSpace(ws)
+		}
+	}
+	return config, true, nil
+}
+
+func envBool(name string, fallback bool) (bool, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fallback, fmt.Errorf("oauth: invalid %s %q", name, v)
+	}
+	return b, nil
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,258 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+)
+
+// The flow endpoints, outside the authentication middleware:
+//
+//	GET LoginPath?provider=google&return_to=/path  redirect to the provider
+//	GET CallbackPath                               the provider's redirect
+//	                                               back; Config.RedirectURL
+//
+// A finished sign-in redirects to return_to (a local path) or
+// Config.SuccessURL; a failed one to Config.FailureURL with an error code:
+// oauth_denied, oauth_state, oauth_unverified_email, oauth_no_account or
+// oauth_failed.
+const (
+	LoginPath    = "/auth/oauth/login"
+	CallbackPath = "/auth/oauth/callback"
+)
+
+// stateCookie carries the flow state; stateTTL bounds how long a user may
+// take at the provider.
+const (
+	stateCookie = "espyna_oauth_state"
+	stateTTL    = 10 * time.Minute
+)
+
+// SessionStarter starts the signed-in user's session on the response, e.g.
+// by creating a session and setting its cookie.
+type SessionStarter func(w http.ResponseWriter, r *http.Request, userID string) error
+
+// Config configures the flow.
+type Config struct {
+	Providers []*Provider
+	// RedirectURL is the absolute URL of CallbackPath, as registered with
+	// every provider.
+	RedirectURL string
+	// StateSecret signs the state cookie; at least 32 bytes.
+	StateSecret []byte
+	// SecureCookie sets the state cookie's Secure flag.
+	SecureCookie bool
+	// SuccessURL and FailureURL default to "/" and "/auth/login".
+	SuccessURL string
+	FailureURL string
+	// IdentityTable defaults to DefaultIdentityTable.
+	IdentityTable string
+	Provisioning  Provisioning
+	// HTTPClient calls the token endpoints; a 10 second client by default.
+	HTTPClient *http.Client
+}
+
+// Flow runs the authorization-code flow.
+type Flow struct {
+	config    Config
+	providers map[string]*Provider
+	accounts  *Accounts
+	start     SessionStarter
+	now       func() time.Time
+}
+
+// NewFlow creates the flow on the database holding the users; start opens
+// the session of whoever signs in.
+func NewFlow(config Config, ops interfaces.DatabaseOperation, start SessionStarter) (*Flow, error) {
+	if len(config.StateSecret) < 32 {
+		return nil, fmt.Errorf("oauth: the state secret must be at least 32 bytes")
+	}
+	if _, err := url.ParseRequestURI(config.RedirectURL); err != nil || !strings.Contains(config.RedirectURL, "://") {
+		return nil, fmt.Errorf("oauth: the redirect URL must be absolute: %q", config.RedirectURL)
+	}
+	if ops == nil || start == nil {
+		return nil, fmt.Errorf("oauth: database operations and a session starter are required")
+	}
+	if len(config.Providers) == 0 {
+		return nil, fmt.Errorf("oauth: no identity provider is configured")
+	}
+	providers := make(map[string]*Provider, len(config.Providers))
+	for _, p := range config.Providers {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+		providers[p.Name] = p
+	}
+	if config.SuccessURL == "" {
+		config.SuccessURL = "/"
+	}
+	if config.FailureURL == "" {
+		config.FailureURL = "/auth/login"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Flow{
+		config:    config,
+		providers: providers,
+		accounts:  NewAccounts(ops, config.IdentityTable, config.Provisioning),
+		start:     start,
+		now:       time.Now,
+	}, nil
+}
+
+// Providers returns the names of the configured providers.
+func (f *Flow) Providers() []string {
+	names := make([]string, 0, len(f.config.Providers))
+	for _, p := range f.config.Providers {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Login redirects to the provider named by the provider parameter.
+func (f *Flow) Login(w http.ResponseWriter, r *http.Request) {
+	p, ok := f.providers[r.URL.Query().Get("provider")]
+	if !ok {
+		http.Error(w, ErrUnknownProvider.Error(), http.StatusNotFound)
+		return
+	}
+	state, err := newFlowState(p.Name, localPath(r.URL.Query().Get("return_to")), f.now().Add(stateTTL))
+	if err != nil {
+		log.Printf("[AUTH] oauth: %v", err)
+		http.Error(w, "sign-in unavailable", http.StatusInternalServerError)
+		return
+	}
+	sealed, err := sealState(f.config.StateSecret, state)
+	if err != nil {
+		log.Printf("[AUTH] oauth: seal state: %v", err)
+		http.Error(w, "sign-in unavailable", http.StatusInternalServerError)
+		return
+	}
+	// Lax, not Strict: the cookie must come back on the provider's
+	// cross-site redirect to the callback.
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    sealed,
+		Path:     "/auth/oauth/",
+		MaxAge:   int(stateTTL / time.Second),
+		HttpOnly: true,
+		Secure:   f.config.SecureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.ClientID},
+		"redirect_uri":          {f.config.RedirectURL},
+		"scope":                 {strings.Join(p.Scopes, " ")},
+		"state":                 {state.State},
+		"nonce":                 {state.Nonce},
+		"code_challenge":        {state.challenge()},
+		"code_challenge_method": {"S256"},
+	}
+	for k, v := range p.AuthParams {
+		params[k] = v
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, p.AuthURL+"?"+params.Encode(), http.StatusFound)
+}
+
+// Callback finishes the flow: it checks the state, redeems the code,
+// resolves the user and starts the session.
+func (f *Flow) Callback(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	cookie, _ := r.Cookie(stateCookie)
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Path:     "/auth/oauth/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   f.config.SecureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		f.fail(w, r, "oauth_denied", fmt.Errorf("provider returned %s", query.Get("error")))
+		return
+	}
+	if cookie == nil {
+		f.fail(w, r, "oauth_state", ErrInvalidState)
+		return
+	}
+	state, err := openState(f.config.StateSecret, cookie.Value, f.now())
+	if err != nil || subtle.ConstantTimeCompare([]byte(state.State), []byte(query.Get("state"))) != 1 {
+		f.fail(w, r, "oauth_state", ErrInvalidState)
+		return
+	}
+	p, ok := f.providers[state.Provider]
+	if !ok || query.Get("code") == "" {
+		f.fail(w, r, "oauth_state", ErrInvalidState)
+		return
+	}
+
+	userID, err := f.signIn(r.Context(), p, query.Get("code"), state)
+	if err != nil {
+		f.fail(w, r, failureCode(err), err)
+		return
+	}
+	if err := f.start(w, r, userID); err != nil {
+		f.fail(w, r, "oauth_failed", fmt.Errorf("start session for %s: %w", userID, err))
+		return
+	}
+	target := state.ReturnTo
+	if target == "" {
+		target = f.config.SuccessURL
+	}
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// signIn redeems code and resolves the user it stands for.
+func (f *Flow) signIn(ctx context.Context, p *Provider, code string, state *flowState) (string, error) {
+	id, err := exchange(ctx, f.config.HTTPClient, p, code, state.Verifier, f.config.RedirectURL, state.Nonce, f.now())
+	if err != nil {
+		return "", err
+	}
+	userID, _, err := f.accounts.Resolve(ctx, id)
+	return userID, err
+}
+
+func (f *Flow) fail(w http.ResponseWriter, r *http.Request, code string, err error) {
+	log.Printf("[AUTH] oauth sign-in failed (%s): %v", code, err)
+	target, _ := url.Parse(f.config.FailureURL)
+	q := target.Query()
+	q.Set("error", code)
+	target.RawQuery = q.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func failureCode(err error) string {
+	switch {
+	case errors.Is(err, ErrEmailUnverified):
+		return "oauth_unverified_email"
+	case errors.Is(err, ErrProvisionRefused):
+		return "oauth_no_account"
+	}
+	return "oauth_failed"
+}
+
+// localPath returns p when it is a path on this site, else "": return_to
+// must not send the user elsewhere.
+func localPath(p string) string {
+	if !strings.HasPrefix(p, "/") || strings.HasPrefix(p, "//") || strings.HasPrefix(p, "/\\") {
+		return ""
+	}
+	if u, err := url.Parse(p); err != nil || u.Host != "" || u.Scheme != "" {
+		return ""
+	}
+	return p
+}
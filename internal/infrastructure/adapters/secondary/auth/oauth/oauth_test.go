@@ -0,0 +1,231 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+)
+
+// memOps keeps rows per table. QueryOne returns the user whose email is
+// the one expected; the fake does not read the query builder.
+type memOps struct {
+	interfaces.DatabaseOperation
+	tables map[string]map[string]map[string]any
+	nextID int
+	lookup string
+}
+
+func newMemOps() *memOps {
+	return &memOps{tables: map[string]map[string]map[string]any{}}
+}
+
+func (o *memOps) table(name string) map[string]map[string]any {
+	if o.tables[name] == nil {
+		o.tables[name] = map[string]map[string]any{}
+	}
+	return o.tables[name]
+}
+
+func (o *memOps) Create(_ context.Context, table string, data map[string]any) (map[string]any, error) {
+	row := map[string]any{}
+	for k, v := range data {
+		row[k] = v
+	}
+	if row["id"] == nil {
+		o.nextID++
+		row["id"] = fmt.Sprintf("%s-%d", table, o.nextID)
+	}
+	if _, exists := o.table(table)[row["id"].(string)]; exists {
+		return nil, model.NewDatabaseError("duplicate key", "DUPLICATE", 409)
+	}
+	o.table(table)[row["id"].(string)] = row
+	return row, nil
+}
+
+func (o *memOps) Read(_ context.Context, table, id string) (map[string]any, error) {
+	row, ok := o.table(table)[id]
+	if !ok {
+		return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return row, nil
+}
+
+func (o *memOps) QueryOne(_ context.Context, table string, _ interfaces.QueryBuilder) (map[string]any, error) {
+	for _, row := range o.table(table) {
+		if row["email_address"] == o.lookup {
+			return row, nil
+		}
+	}
+	return nil, nil
+}
+
+// expect sets the email QueryOne looks for.
+func (o *memOps) expect(email string) { o.lookup = email }
+
+// idToken is an unsigned ID token with the given claims.
+func idToken(claims map[string]any) string {
+	body, _ := json.Marshal(claims)
+	return "eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString(body) + ".sig"
+}
+
+func googleClaims(nonce string) map[string]any {
+	return map[string]any{
+		"iss": "https://accounts.google.com", "sub": "g-1", "aud": "client", "exp": time.Now().Add(time.Hour).Unix(),
+		"nonce": nonce, "email": "Ada@Example.com", "email_verified": true, "given_name": "Ada", "family_name": "Lovelace",
+	}
+}
+
+func TestParseIDToken(t *testing.T) {
+	g := Google("client", "secret")
+	claims := googleClaims("n")
+	if id, err := parseIDToken(g, idToken(claims), "n", time.Now()); err != nil || id.Email != "ada@example.com" || !id.EmailVerified {
+		t.Fatalf("parse = %+v, %v", id, err)
+	}
+	for name, mutate := range map[string]func(map[string]any){
+		"issuer":   func(c map[string]any) { c["iss"] = "https://evil.example" },
+		"audience": func(c map[string]any) { c["aud"] = []string{"other"} },
+		"expired":  func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"nonce":    func(c map[string]any) { c["nonce"] = "replayed" },
+	} {
+		c := googleClaims("n")
+		mutate(c)
+		if _, err := parseIDToken(g, idToken(c), "n", time.Now()); !errors.Is(err, ErrInvalidIDToken) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+
+	tid := "72f988bf-86f1-41af-91ab-2d7cd011db47"
+	ms := Microsoft(tid, "client", "secret")
+	c := map[string]any{"iss": "https://login.microsoftonline.com/" + tid + "/v2.0", "tid": tid, "sub": "m-1", "aud": "client",
+		"exp": time.Now().Add(time.Hour).Unix(), "nonce": "n", "email": "ada@example.com", "xms_edov": "true"}
+	if id, err := parseIDToken(ms, idToken(c), "n", time.Now()); err != nil || !id.EmailVerified {
+		t.Errorf("microsoft = %+v, %v", id, err)
+	}
+	other := "00000000-0000-0000-0000-000000000000"
+	c["tid"], c["iss"] = other, "https://login.microsoftonline.com/"+other+"/v2.0"
+	if _, err := parseIDToken(ms, idToken(c), "n", time.Now()); !errors.Is(err, ErrInvalidIDToken) {
+		t.Errorf("other tenant = %v", err)
+	}
+}
+
+func TestAccounts_Resolve(t *testing.T) {
+	ctx := context.Background()
+	ops := newMemOps()
+	accounts := NewAccounts(ops, "", Provisioning{DomainWorkspaces: map[string]string{"example.com": "ws-1"}, AllowedDomains: []string{"corp.test"}})
+	id := &Identity{Provider: "google", Subject: "g-1", Email: "ada@example.com", EmailVerified: true, GivenName: "Ada"}
+
+	ops.expect(id.Email)
+	userID, created, err := accounts.Resolve(ctx, id)
+	if err != nil || !created {
+		t.Fatalf("first sign-in = %q %v %v", userID, created, err)
+	}
+	if len(ops.table("workspace_user")) != 1 || ops.table("user_identity")["google:g-1"]["user_id"] != userID {
+		t.Errorf("provisioned %v", ops.tables)
+	}
+	again, created, _ := accounts.Resolve(ctx, id)
+	if again != userID || created {
+		t.Errorf("second sign-in = %q %v", again, created)
+	}
+
+	// A Microsoft identity with the same verified email links to the user.
+	ms := &Identity{Provider: "microsoft", Subject: "m-1", Email: "ada@example.com", EmailVerified: true}
+	if linked, created, err := accounts.Resolve(ctx, ms); linked != userID || created || err != nil {
+		t.Errorf("link by email = %q %v %v", linked, created, err)
+	}
+	unverified := &Identity{Provider: "microsoft", Subject: "m-2", Email: "ada@example.com"}
+	if _, _, err := accounts.Resolve(ctx, unverified); !errors.Is(err, ErrEmailUnverified) {
+		t.Errorf("unverified email = %v", err)
+	}
+	ops.expect("eve@elsewhere.test")
+	if _, _, err := accounts.Resolve(ctx, &Identity{Provider: "google", Subject: "g-2", Email: "eve@elsewhere.test", EmailVerified: true}); !errors.Is(err, ErrProvisionRefused) {
+		t.Errorf("domain not allowed = %v", err)
+	}
+	ops.table("user")[userID]["active"] = false
+	if _, _, err := accounts.Resolve(ctx, id); !errors.Is(err, ErrProvisionRefused) {
+		t.Errorf("deactivated user = %v", err)
+	}
+}
+
+func TestFlow(t *testing.T) {
+	var nonce, challenge string
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if r.Form.Get("code") != "the-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": idToken(googleClaims(nonce))})
+	}))
+	defer idp.Close()
+
+	google := Google("client", "secret")
+	google.TokenURL = idp.URL
+	ops := newMemOps()
+	ops.expect("ada@example.com")
+	var started string
+	flow, err := NewFlow(Config{
+		Providers:   []*Provider{google},
+		RedirectURL: "https://app.example.com" + CallbackPath,
+		StateSecret: []byte(strings.Repeat("k", 32)),
+	}, ops, func(w http.ResponseWriter, r *http.Request, userID string) error {
+		started = userID
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	flow.Login(rec, httptest.NewRequest(http.MethodGet, LoginPath+"?provider=google&return_to=/dashboard", nil))
+	location, _ := url.Parse(rec.Header().Get("Location"))
+	params := location.Query()
+	nonce, challenge = params.Get("nonce"), params.Get("code_challenge")
+	cookie := rec.Result().Cookies()[0]
+	if rec.Code != http.StatusFound || params.Get("code_challenge_method") != "S256" || cookie.Name != stateCookie {
+		t.Fatalf("login = %d %s", rec.Code, location)
+	}
+
+	callback := func(query string, withCookie bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, CallbackPath+"?"+query, nil)
+		if withCookie {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		flow.Callback(rec, req)
+		return rec
+	}
+	if rec := callback("code=the-code&state=forged", true); !strings.Contains(rec.Header().Get("Location"), "error=oauth_state") {
+		t.Errorf("forged state -> %s", rec.Header().Get("Location"))
+	}
+	if rec := callback("code=the-code&state="+params.Get("state"), false); !strings.Contains(rec.Header().Get("Location"), "error=oauth_state") {
+		t.Errorf("missing cookie -> %s", rec.Header().Get("Location"))
+	}
+	rec = callback("code=the-code&state="+params.Get("state"), true)
+	if rec.Header().Get("Location") != "/dashboard" || started == "" {
+		t.Fatalf("callback -> %d %s (started %q)", rec.Code, rec.Header().Get("Location"), started)
+	}
+}
+
+func TestLocalPath(t *testing.T) {
+	for in, want := range map[string]string{
+		"/a?b=c": "/a?b=c", "//evil.example": "", "/\\evil.example": "", "https://evil.example": "", "": "",
+	} {
+		if got := localPath(in); got != want {
+			t.Errorf("localPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package oauth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// flowState is what the state cookie carries between the redirect to the
+// provider and the callback.
+type flowState struct {
+	Provider string `json:"p"`
+	State    string `json:"s"`
+	Nonce    string `json:"n"`
+	Verifier string `json:"v"`
+	ReturnTo string `json:"r,omitempty"`
+	Expires  int64  `json:"e"`
+}
+
+// newFlowState starts a flow with fresh random state, nonce and PKCE
+// verifier.
+func newFlowState(provider, returnTo string, expires time.Time) (*flowState, error) {
+	var values [3]string
+	for i := range values {
+		v, err := randomString(32)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return &flowState{
+		Provider: provider,
+		State:    values[0],
+		Nonce:    values[1],
+		Verifier: values[2],
+		ReturnTo: returnTo,
+		Expires:  expires.Unix(),
+	}, nil
+}
+
+// challenge is the S256 PKCE challenge of the verifier.
+func (s *flowState) challenge() string {
+	sum := sha256.Sum256([]byte(s.Verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// sealState signs s for the cookie.
+func sealState(secret []byte, s *flowState) (string, error) {
+	body, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(body)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(sign(secret, payload)), nil
+}
+
+// openState checks a cookie value's signature and expiry at now.
+func openState(secret []byte, value string, now time.Time) (*flowState, error) {
+	payload, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return nil, ErrInvalidState
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(raw, sign(secret, payload)) {
+		return nil, ErrInvalidState
+	}
+	body, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidState
+	}
+	var s flowState
+	if err := json.Unmarshal(body, &s); err != nil || now.Unix() > s.Expires {
+		return nil, ErrInvalidState
+	}
+	return &s, nil
+}
+
+func sign(secret []byte, payload string) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
+
+// randomString returns n random bytes, base64url-encoded: 43 characters
+// for 32 bytes, the length RFC 7636 asks of a verifier.
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("oauth: generate state: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,150 @@
+package oauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// leeway absorbs clock skew with the provider.
+const leeway = time.Minute
+
+// Identity is who the provider signed in.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Name          string
+}
+
+// idClaims are the ID token claims read.
+type idClaims struct {
+	Issuer     string          `json:"iss"`
+	Subject    string          `json:"sub"`
+	Audience   json.RawMessage `json:"aud"`
+	Expires    int64           `json:"exp"`
+	Nonce      string          `json:"nonce"`
+	TenantID   string          `json:"tid"`
+	Email      string          `json:"email"`
+	Verified   flexBool        `json:"email_verified"`
+	DomainOwn  flexBool        `json:"xms_edov"`
+	GivenName  string          `json:"given_name"`
+	FamilyName string          `json:"family_name"`
+	Name       string          `json:"name"`
+}
+
+// flexBool reads a boolean claim some providers send as a string.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true", "1":
+		*b = true
+	default:
+		*b = false
+	}
+	return nil
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchange redeems an authorization code at p's token endpoint and returns
+// the identity in the ID token.
+func exchange(ctx context.Context, client *http.Client, p *Provider, code, verifier, redirectURL, nonce string, now time.Time) (*Identity, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"client_id":     {p.ClientID},
+		"client_secret": {p.ClientSecret},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth: %s token request: %w", p.Name, err)
+	}
+	defer resp.Body.Close()
+
+	var body tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("oauth: %s token response: %w", p.Name, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return nil, fmt.Errorf("oauth: %s token request failed (%d): %s %s", p.Name, resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+	return parseIDToken(p, body.IDToken, nonce, now)
+}
+
+// parseIDToken checks an ID token taken from p's token endpoint; its
+// signature is not checked (see the package doc).
+func parseIDToken(p *Provider, token, nonce string, now time.Time) (*Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidIDToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidIDToken
+	}
+	var c idClaims
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidIDToken
+	}
+	switch {
+	case c.Subject == "":
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	case !p.ValidIssuer(c.Issuer, c.TenantID):
+		return nil, fmt.Errorf("%w: issuer %q", ErrInvalidIDToken, c.Issuer)
+	case !hasAudience(c.Audience, p.ClientID):
+		return nil, fmt.Errorf("%w: audience", ErrInvalidIDToken)
+	case now.After(time.Unix(c.Expires, 0).Add(leeway)):
+		return nil, fmt.Errorf("%w: expired", ErrInvalidIDToken)
+	case c.Nonce != nonce:
+		return nil, fmt.Errorf("%w: nonce", ErrInvalidIDToken)
+	}
+	return &Identity{
+		Provider:      p.Name,
+		Subject:       c.Subject,
+		Email:         strings.ToLower(strings.TrimSpace(c.Email)),
+		EmailVerified: bool(c.Verified) || bool(c.DomainOwn),
+		GivenName:     c.GivenName,
+		FamilyName:    c.FamilyName,
+		Name:          c.Name,
+	}, nil
+}
+
+// hasAudience reads aud, a string or an array of strings.
+func hasAudience(aud json.RawMessage, clientID string) bool {
+	var one string
+	if json.Unmarshal(aud, &one) == nil {
+		return one == clientID
+	}
+	var many []string
+	if json.Unmarshal(aud, &many) == nil {
+		for _, a := range many {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/revenue/revenue"
+	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
+)
+
+// fakeBiller records its passes; block, when set, holds a pass until closed.
+type fakeBiller struct {
+	mu       sync.Mutex
+	requests []revenue.RecurringBillingRequest
+	users    []string
+	started  chan struct{}
+	block    chan struct{}
+	fail     map[string]error
+}
+
+func (b *fakeBiller) Execute(ctx context.Context, req *revenue.RecurringBillingRequest) (*revenue.RecurringBillingResult, error) {
+	b.mu.Lock()
+	b.requests = append(b.requests, *req)
+	b.users = append(b.users, contextutil.ExtractUserIDFromContext(ctx))
+	b.mu.Unlock()
+	if b.block != nil {
+		close(b.started)
+		<-b.block
+	}
+	if err := b.fail[req.WorkspaceID]; err != nil {
+		return nil, err
+	}
+	return &revenue.RecurringBillingResult{
+		WorkspaceID: contextutil.ExtractWorkspaceIDFromContext(ctx),
+		AsOfDate:    req.AsOfDate,
+		RevenueIDs:  []string{"rev-" + req.WorkspaceID},
+	}, nil
+}
+
+func TestScheduler_RunAll(t *testing.T) {
+	biller := &fakeBiller{fail: map[string]error{"ws-1": errors.New("boom")}}
+	s := NewScheduler(biller, Config{
+		Workspaces:  func(context.Context) ([]string, error) { return []string{"ws-1", "ws-2"}, nil },
+		RunAsUserID: "billing-bot",
+		Template:    revenue.RecurringBillingRequest{WorkspaceID: "ignored", DefaultNetDays: 30},
+	})
+	s.RunAll(context.Background())
+
+	if len(biller.requests) != 2 {
+		t.Fatalf("passes = %d, want both workspaces despite the failure", len(biller.requests))
+	}
+	for i, ws := range []string{"ws-1", "ws-2"} {
+		if r := biller.requests[i]; r.WorkspaceID != ws || r.DefaultNetDays != 30 || biller.users[i] != "billing-bot" {
+			t.Errorf("pass %d = %+v as %q", i, r, biller.users[i])
+		}
+	}
+}
+
+func TestHandlers_Run(t *testing.T) {
+	biller := &fakeBiller{}
+	s := NewScheduler(biller, Config{})
+	h := NewHandlers(s, testutil.OpenActionGate())
+	userCtx := contextutil.WithWorkspaceID(contextutil.WithUserID(context.Background(), "u-1"), "ws-1")
+
+	call := func(ctx context.Context, target string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		h.Run(rec, httptest.NewRequest(http.MethodPost, target, nil).WithContext(ctx))
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+
+	code, out := call(userCtx, RunPath+"?as_of=2026-04-01")
+	if code != http.StatusOK {
+		t.Fatalf("status %d: %v", code, out)
+	}
+	data, _ := out["data"].(map[string]any)
+	if data["workspace_id"] != "ws-1" || data["as_of_date"] != "2026-04-01" {
+		t.Errorf("data = %v", data)
+	}
+	if overdue, ok := data["marked_overdue"].([]any); !ok || len(overdue) != 0 {
+		t.Errorf("marked_overdue = %v", data["marked_overdue"])
+	}
+
+	if code, _ := call(userCtx, RunPath+"?as_of=April"); code != http.StatusBadRequest {
+		t.Errorf("bad as_of status = %d", code)
+	}
+	if code, _ := call(context.Background(), RunPath); code != http.StatusUnauthorized {
+		t.Errorf("anonymous status = %d", code)
+	}
+
+	// A pass started while another runs is refused, not queued.
+	biller.started, biller.block = make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = s.RunWorkspace(userCtx, "ws-2", "")
+	}()
+	<-biller.started
+	if code, _ := call(userCtx, RunPath); code != http.StatusConflict {
+		t.Errorf("concurrent run status = %d", code)
+	}
+	close(biller.block)
+	<-done
+}
+
+// fakeReconciler reports one mismatch, corrected when asked to.
+type fakeReconciler struct {
+	requests []revenue.ReconcilePaymentsRequest
+}
+
+func (f *fakeReconciler) Execute(ctx context.Context, req *revenue.ReconcilePaymentsRequest) (*revenue.ReconcilePaymentsResult, error) {
+	f.requests = append(f.requests, *req)
+	return &revenue.ReconcilePaymentsResult{
+		WorkspaceID: contextutil.ExtractWorkspaceIDFromContext(ctx),
+		Since:       req.Since,
+		Checked:     2,
+		Matched:     1,
+		Mismatches: []revenue.PaymentMismatch{{
+			RevenueID:      "rev-1",
+			Provider:       "paypal",
+			ProviderRef:    "ORDER-1",
+			Kind:           revenue.MismatchPaidNotRecorded,
+			ProviderStatus: paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS,
+			Corrected:      req.AutoCorrect,
+		}},
+	}, nil
+}
+
+func TestScheduler_RunAllReconciles(t *testing.T) {
+	reconciler := &fakeReconciler{}
+	s := NewScheduler(&fakeBiller{}, Config{
+		Workspaces:           func(context.Context) ([]string, error) { return []string{"ws-1", "ws-2"}, nil },
+		Reconciler:           reconciler,
+		ReconcileAutoCorrect: true,
+	})
+	s.RunAll(context.Background())
+
+	if len(reconciler.requests) != 2 {
+		t.Fatalf("reconciliations = %d, want one per workspace", len(reconciler.requests))
+	}
+	if r := reconciler.requests[1]; r.WorkspaceID != "ws-2" || !r.AutoCorrect {
+		t.Errorf("reconciliation = %+v", r)
+	}
+}
+
+func TestHandlers_Reconcile(t *testing.T) {
+	userCtx := contextutil.WithWorkspaceID(contextutil.WithUserID(context.Background(), "u-1"), "ws-1")
+	call := func(h *Handlers, target string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		h.Reconcile(rec, httptest.NewRequest(http.MethodPost, target, nil).WithContext(userCtx))
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+	gate := testutil.OpenActionGate()
+
+	if code, _ := call(NewHandlers(NewScheduler(&fakeBiller{}, Config{}), gate), ReconcilePath); code != http.StatusNotImplemented {
+		t.Errorf("no reconciler status = %d", code)
+	}
+
+	reconciler := &fakeReconciler{}
+	h := NewHandlers(NewScheduler(&fakeBiller{}, Config{Reconciler: reconciler}), gate)
+	code, out := call(h, ReconcilePath+"?since=2026-03-01&auto_correct=true")
+	if code != http.StatusOK {
+		t.Fatalf("status %d: %v", code, out)
+	}
+	data, _ := out["data"].(map[string]any)
+	if data["workspace_id"] != "ws-1" || data["since"] != "2026-03-01" {
+		t.Errorf("data = %v", data)
+	}
+	mismatches, _ := data["mismatches"].([]any)
+	if len(mismatches) != 1 {
+		t.Fatalf("mismatches = %v", data["mismatches"])
+	}
+	m, _ := mismatches[0].(map[string]any)
+	if m["kind"] != revenue.MismatchPaidNotRecorded || m["provider_status"] != "success" || m["corrected"] != true {
+		t.Errorf("mismatch = %v", m)
+	}
+
+	if code, _ := call(h, ReconcilePath+"?since=March"); code != http.StatusBadRequest {
+		t.Errorf("bad since status = %d", code)
+	}
+}
@@ -0,0 +1,203 @@
+package billing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// RunPath serves POST: a billing pass for the caller's workspace, as of
+// ?as_of=YYYY-MM-DD or today. It needs revenue:update.
+const RunPath = "/api/billing/run"
+
+// ReconcilePath serves POST: a payment reconciliation of the caller's
+// workspace, from ?since=YYYY-MM-DD or the default window. It needs
+// revenue:read, or revenue:update with ?auto_correct=true.
+const ReconcilePath = "/api/billing/reconcile"
+
+type resultJSON struct {
+	WorkspaceID     string   `json:"workspace_id"`
+	AsOfDate        string   `json:"as_of_date"`
+	RunID           string   `json:"run_id,omitempty"`
+	RevenueIDs      []string `json:"revenue_ids"`
+	Skipped         int      `json:"skipped"`
+	Errored         int      `json:"errored"`
+	Checkouts       int      `json:"checkouts"`
+	CheckoutsFailed int      `json:"checkouts_failed"`
+	MarkedOverdue   []string `json:"marked_overdue"`
+}
+
+type reconcileJSON struct {
+	WorkspaceID string         `json:"workspace_id"`
+	Since       string         `json:"since"`
+	Checked     int            `json:"checked"`
+	Matched     int            `json:"matched"`
+	Unavailable int            `json:"unavailable"`
+	Corrected   int            `json:"corrected"`
+	Mismatches  []mismatchJSON `json:"mismatches"`
+}
+
+type mismatchJSON struct {
+	RevenueID        string `json:"revenue_id"`
+	Provider         string `json:"provider"`
+	ProviderRef      string `json:"provider_ref"`
+	Kind             string `json:"kind"`
+	SettlementStatus string `json:"settlement_status"`
+	ProviderStatus   string `json:"provider_status"`
+	Amount           int64  `json:"amount"`
+	ProviderAmount   int64  `json:"provider_amount"`
+	Corrected        bool   `json:"corrected"`
+}
+
+// Handlers serve RunPath and ReconcilePath.
+type Handlers struct {
+	scheduler *Scheduler
+	gate      *actiongate.ActionGatekeeper
+}
+
+// NewHandlers creates the handlers over scheduler.
+func NewHandlers(scheduler *Scheduler, gate *actiongate.ActionGatekeeper) *Handlers {
+	return &Handlers{scheduler: scheduler, gate: gate}
+}
+
+// Run runs the billing pass.
+func (h *Handlers) Run(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.authorize(w, r, entityid.ActionUpdate)
+	if !ok {
+		return
+	}
+	asOf := r.URL.Query().Get("as_of")
+	if !validDate(asOf) {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "as_of must be YYYY-MM-DD"})
+		return
+	}
+
+	result, err := h.scheduler.RunWorkspace(r.Context(), workspaceID, asOf)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	out := resultJSON{
+		WorkspaceID:     result.WorkspaceID,
+		AsOfDate:        result.AsOfDate,
+		RunID:           result.RunID,
+		RevenueIDs:      result.RevenueIDs,
+		Skipped:         result.Skipped,
+		Errored:         result.Errored,
+		Checkouts:       result.Checkouts,
+		CheckoutsFailed: result.CheckoutsFailed,
+		MarkedOverdue:   result.MarkedOverdue,
+	}
+	if out.RevenueIDs == nil {
+		out.RevenueIDs = []string{}
+	}
+	if out.MarkedOverdue == nil {
+		out.MarkedOverdue = []string{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": out})
+}
+
+// Reconcile runs the payment reconciliation.
+func (h *Handlers) Reconcile(w http.ResponseWriter, r *http.Request) {
+	autoCorrect, _ := strconv.ParseBool(r.URL.Query().Get("auto_correct"))
+	action := entityid.ActionRead
+	if autoCorrect {
+		action = entityid.ActionUpdate
+	}
+	workspaceID, ok := h.authorize(w, r, action)
+	if !ok {
+		return
+	}
+	since := r.URL.Query().Get("since")
+	if !validDate(since) {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "since must be YYYY-MM-DD"})
+		return
+	}
+
+	result, err := h.scheduler.ReconcileWorkspace(r.Context(), workspaceID, since, autoCorrect)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	out := reconcileJSON{
+		WorkspaceID: result.WorkspaceID,
+		Since:       result.Since,
+		Checked:     result.Checked,
+		Matched:     result.Matched,
+		Unavailable: result.Unavailable,
+		Corrected:   result.Corrected,
+		Mismatches:  []mismatchJSON{},
+	}
+	for _, m := range result.Mismatches {
+		out.Mismatches = append(out.Mismatches, mismatchJSON{
+			RevenueID:        m.RevenueID,
+			Provider:         m.Provider,
+			ProviderRef:      m.ProviderRef,
+			Kind:             m.Kind,
+			SettlementStatus: m.SettlementStatus,
+			ProviderStatus:   strings.ToLower(strings.TrimPrefix(m.ProviderStatus.String(), "PAYMENT_STATUS_")),
+			Amount:           m.Amount,
+			ProviderAmount:   m.ProviderAmount,
+			Corrected:        m.Corrected,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": out})
+}
+
+// authorize checks the method, the caller and revenue:<action> in the
+// caller's workspace, writing the error response when one fails.
+func (h *Handlers) authorize(w http.ResponseWriter, r *http.Request, action string) (string, bool) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return "", false
+	}
+	ctx := r.Context()
+	if contextutil.ExtractUserIDFromContext(ctx) == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return "", false
+	}
+	workspaceID := contextutil.ExtractWorkspaceIDFromContext(ctx)
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "workspace required"})
+		return "", false
+	}
+	if err := h.gate.Check(ctx, &actiongate.CheckActionRequest{Entity: "revenue", Action: action}); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": err.Error()})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+// validDate accepts an empty date or YYYY-MM-DD.
+func validDate(date string) bool {
+	if date == "" {
+		return true
+	}
+	_, err := time.Parse("2006-01-02", date)
+	return err == nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrBusy):
+		writeJSON(w, http.StatusConflict, map[string]any{"success": false, "error": err.Error()})
+	case errors.Is(err, ErrNoReconciler):
+		writeJSON(w, http.StatusNotImplemented, map[string]any{"success": false, "error": err.Error()})
+	default:
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
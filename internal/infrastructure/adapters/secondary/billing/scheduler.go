@@ -0,0 +1,178 @@
+// Package billing runs recurring billing passes and payment reconciliation
+// on a schedule and on demand.
+package billing
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/usecases/domain/revenue/revenue"
+)
+
+// DefaultInterval is how often Run bills when Config.Interval is unset.
+const DefaultInterval = time.Hour
+
+// ErrBusy is returned by RunWorkspace and ReconcileWorkspace while another
+// pass is running.
+var ErrBusy = errors.New("a billing run is already in progress")
+
+// ErrNoReconciler is returned by ReconcileWorkspace when the scheduler has
+// no Reconciler.
+var ErrNoReconciler = errors.New("payment reconciliation is not configured")
+
+// Biller runs one billing pass; revenue.RunRecurringBillingUseCase.
+type Biller interface {
+	Execute(ctx context.Context, req *revenue.RecurringBillingRequest) (*revenue.RecurringBillingResult, error)
+}
+
+// Reconciler compares a workspace's payments with its payment providers;
+// revenue.ReconcilePaymentsUseCase.
+type Reconciler interface {
+	Execute(ctx context.Context, req *revenue.ReconcilePaymentsRequest) (*revenue.ReconcilePaymentsResult, error)
+}
+
+// Config tunes the scheduler.
+type Config struct {
+	// Interval between scheduled passes (default DefaultInterval).
+	Interval time.Duration
+	// Workspaces returns the workspaces a scheduled pass bills.
+	Workspaces func(ctx context.Context) ([]string, error)
+	// RunAsUserID is the user scheduled passes act as; it needs
+	// revenue:create, revenue:update and subscription:read in every billed
+	// workspace. Passes started over the API act as the caller.
+	RunAsUserID string
+	// Template is copied into every pass's request; its WorkspaceID and
+	// AsOfDate are ignored.
+	Template revenue.RecurringBillingRequest
+	// Reconciler, when set, reconciles each workspace's payments after its
+	// scheduled billing pass and serves ReconcileWorkspace. Scheduled
+	// reconciliations correct what they can when ReconcileAutoCorrect is
+	// set, and only report otherwise.
+	Reconciler           Reconciler
+	ReconcileAutoCorrect bool
+}
+
+// Scheduler runs billing passes, one at a time.
+type Scheduler struct {
+	biller Biller
+	config Config
+	mu     sync.Mutex
+}
+
+// NewScheduler creates a scheduler over biller.
+func NewScheduler(biller Biller, config Config) *Scheduler {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Scheduler{biller: biller, config: config}
+}
+
+// Run bills every workspace right away and then every interval, until ctx
+// is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		s.RunAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunAll bills, then reconciles, every workspace of Config.Workspaces as
+// Config.RunAsUserID, logging each workspace's outcome. A failing workspace
+// does not stop the others.
+func (s *Scheduler) RunAll(ctx context.Context) {
+	if s.config.Workspaces == nil {
+		return
+	}
+	workspaces, err := s.config.Workspaces(ctx)
+	if err != nil {
+		log.Printf("billing: list workspaces: %v", err)
+		return
+	}
+	if s.config.RunAsUserID != "" {
+		ctx = contextutil.WithUserID(ctx, s.config.RunAsUserID)
+	}
+	for _, workspaceID := range workspaces {
+		if ctx.Err() != nil {
+			return
+		}
+		s.billWorkspace(ctx, workspaceID)
+		if s.config.Reconciler != nil {
+			s.reconcileWorkspace(ctx, workspaceID)
+		}
+	}
+}
+
+func (s *Scheduler) billWorkspace(ctx context.Context, workspaceID string) {
+	result, err := s.RunWorkspace(ctx, workspaceID, "")
+	if err != nil {
+		log.Printf("billing: workspace %s: %v", workspaceID, err)
+		return
+	}
+	if len(result.RevenueIDs) > 0 || len(result.MarkedOverdue) > 0 || result.Errored > 0 {
+		log.Printf("billing: workspace %s as of %s: %d generated, %d errored, %d checkouts (%d failed), %d overdue",
+			workspaceID, result.AsOfDate, len(result.RevenueIDs), result.Errored,
+			result.Checkouts, result.CheckoutsFailed, len(result.MarkedOverdue))
+	}
+}
+
+func (s *Scheduler) reconcileWorkspace(ctx context.Context, workspaceID string) {
+	result, err := s.ReconcileWorkspace(ctx, workspaceID, "", s.config.ReconcileAutoCorrect)
+	if err != nil {
+		log.Printf("billing: reconcile workspace %s: %v", workspaceID, err)
+		return
+	}
+	for _, m := range result.Mismatches {
+		log.Printf("billing: workspace %s revenue %s: %s at %s (ref %s, corrected %v)",
+			workspaceID, m.RevenueID, m.Kind, m.Provider, m.ProviderRef, m.Corrected)
+	}
+}
+
+// RunWorkspace runs one billing pass for the workspace as of asOfDate
+// (YYYY-MM-DD, empty for today), acting as the context's user. It returns
+// ErrBusy rather than waiting for another pass to finish.
+func (s *Scheduler) RunWorkspace(ctx context.Context, workspaceID, asOfDate string) (*revenue.RecurringBillingResult, error) {
+	if !s.mu.TryLock() {
+		return nil, ErrBusy
+	}
+	defer s.mu.Unlock()
+
+	req := s.config.Template
+	req.WorkspaceID = workspaceID
+	req.AsOfDate = asOfDate
+	return s.biller.Execute(contextutil.WithWorkspaceID(ctx, workspaceID), &req)
+}
+
+// ReconcileWorkspace reconciles the workspace's payments with their
+// providers from since (YYYY-MM-DD, empty for the default window), acting
+// as the context's user. Like RunWorkspace it returns ErrBusy rather than
+// waiting.
+func (s *Scheduler) ReconcileWorkspace(ctx context.Context, workspaceID, since string, autoCorrect bool) (*revenue.ReconcilePaymentsResult, error) {
+	if s.config.Reconciler == nil {
+		return nil, ErrNoReconciler
+	}
+	if !s.mu.TryLock() {
+		return nil, ErrBusy
+	}
+	defer s.mu.Unlock()
+
+	return s.config.Reconciler.Execute(contextutil.WithWorkspaceID(ctx, workspaceID), &revenue.ReconcilePaymentsRequest{
+		WorkspaceID: workspaceID,
+		Since:       since,
+		AutoCorrect: autoCorrect,
+	})
+}
+
+var (
+	_ Biller     = (*revenue.RunRecurringBillingUseCase)(nil)
+	_ Reconciler = (*revenue.ReconcilePaymentsUseCase)(nil)
+)
@@ -0,0 +1,20 @@
+//go:build mock_cache
+
+package mock
+
+import (
+	"log"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+func init() {
+	registry.RegisterCacheProviderFactory("mock_cache", func() ports.Cache {
+		return NewCache()
+	})
+	registry.RegisterCacheBuildFromEnv("mock_cache", func() (ports.Cache, error) {
+		return NewCache(), nil
+	})
+	log.Printf("[MockCache] Registered with cache registry")
+}
@@ -0,0 +1,121 @@
+// Package mock provides an in-memory Cache for tests and single-instance
+// development. Registration as the "mock_cache" provider lives in
+// adapter.go behind the mock_cache build tag; the cache itself is always
+// available so tests can construct it directly.
+package mock
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time // zero for counters, which never expire
+}
+
+// Cache keeps entries in a map guarded by a mutex. Expired entries are
+// dropped lazily when read.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]entry
+	now     func() time.Time
+}
+
+var _ ports.Cache = (*Cache)(nil)
+
+// NewCache creates an empty in-memory cache.
+func NewCache() *Cache {
+	return &Cache{entries: make(map[string]entry), now: time.Now}
+}
+
+// SetClock replaces the time source, letting tests expire entries without
+// sleeping.
+func (c *Cache) SetClock(now func() time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func (c *Cache) Name() string {
+	return "mock_cache"
+}
+
+func (c *Cache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.lookup(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), e.value...), true, nil
+}
+
+func (c *Cache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry{value: append([]byte(nil), value...), expiresAt: c.now().Add(ttl)}
+	return nil
+}
+
+func (c *Cache) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+func (c *Cache) Increment(_ context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var n int64
+	if e, ok := c.lookup(key); ok {
+		var err error
+		if n, err = strconv.ParseInt(string(e.value), 10, 64); err != nil {
+			return 0, err
+		}
+	}
+	n++
+	c.entries[key] = entry{value: []byte(strconv.FormatInt(n, 10))}
+	return n, nil
+}
+
+func (c *Cache) Close() error {
+	return nil
+}
+
+// Len reports how many live entries the cache holds.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for key := range c.entries {
+		if _, ok := c.lookup(key); ok {
+			n++
+		}
+	}
+	return n
+}
+
+// lookup returns the live entry under key, dropping it when expired. The
+// caller holds mu.
+func (c *Cache) lookup(key string) (entry, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return entry{}, false
+	}
+	if !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt) {
+		delete(c.entries, key)
+		return entry{}, false
+	}
+	return e, true
+}
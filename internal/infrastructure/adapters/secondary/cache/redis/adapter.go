@@ -0,0 +1,130 @@
+//go:build redis_cache
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	"github.com/erniealice/espyna-golang/shared/redis"
+)
+
+// =============================================================================
+// Self-Registration - Adapter registers itself with the factory
+// =============================================================================
+
+func init() {
+	registry.RegisterCacheBuildFromEnv("redis", buildFromEnv)
+	log.Printf("[RedisCache] Registered with cache registry")
+}
+
+// buildFromEnv creates a Redis cache from CONFIG_CACHE_REDIS_URL
+// (redis://[user:password@]host:port/db).
+func buildFromEnv() (ports.Cache, error) {
+	url := strings.TrimSpace(os.Getenv("CONFIG_CACHE_REDIS_URL"))
+	if url == "" {
+		return nil, fmt.Errorf("CONFIG_CACHE_REDIS_URL is required for the redis cache")
+	}
+	return NewCache(url)
+}
+
+// =============================================================================
+// Adapter Implementation
+// =============================================================================
+
+// commandTimeout bounds a single cache command; a slow cache must not slow
+// down the database path it is meant to spare.
+const commandTimeout = 250 * time.Millisecond
+
+// Cache stores entries in Redis with native expiry (SET PX) and counters
+// with INCR.
+type Cache struct {
+	client *redis.Client
+}
+
+var _ ports.Cache = (*Cache)(nil)
+
+// NewCache creates a cache on the Redis server at url.
+func NewCache(url string) (*Cache, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{client: redis.NewClient(opts)}, nil
+}
+
+func (c *Cache) Name() string {
+	return "redis"
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	reply, err := c.client.Do(ctx, "GET", key)
+	if errors.Is(err, redis.ErrNil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("redis cache: %w", err)
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis cache: unexpected reply %T for GET", reply)
+	}
+	return value, true, nil
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ms := ttl.Milliseconds()
+	if ms <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	if _, err := c.client.Do(ctx, "SET", key, value, "PX", ms); err != nil {
+		return fmt.Errorf("redis cache: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	args := make([]any, 0, len(keys)+1)
+	args = append(args, "DEL")
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	if _, err := c.client.Do(ctx, args...); err != nil {
+		return fmt.Errorf("redis cache: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) Increment(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	n, err := redis.Int64(c.client.Do(ctx, "INCR", key))
+	if err != nil {
+		return 0, fmt.Errorf("redis cache: %w", err)
+	}
+	return n, nil
+}
+
+func (c *Cache) Close() error {
+	return c.client.Close()
+}
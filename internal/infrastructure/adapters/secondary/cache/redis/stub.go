@@ -0,0 +1,3 @@
+// Package redis provides the Redis-backed Cache shared by every server
+// instance. The actual adapter is in adapter.go with build constraints.
+package redis
@@ -0,0 +1,128 @@
+// Package capabilities reports what the active integration providers can do,
+// in one shape for every integration, so a frontend can render features from
+// the deployment's providers instead of assuming a particular one.
+package capabilities
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// The integrations reported, in response order.
+const (
+	IntegrationPayment   = "payment"
+	IntegrationTabular   = "tabular"
+	IntegrationScheduler = "scheduler"
+	IntegrationStorage   = "storage"
+	IntegrationMessaging = "messaging"
+)
+
+// Integrations lists the integrations in response order.
+var Integrations = []string{IntegrationPayment, IntegrationTabular, IntegrationScheduler, IntegrationStorage, IntegrationMessaging}
+
+// Providers are the active providers of a deployment. Any of them may be
+// nil or empty.
+type Providers struct {
+	Payment   map[string]ports.PaymentProvider
+	Scheduler map[string]ports.SchedulerProvider
+	Tabular   ports.TabularSourceProvider
+	// Storage is the storage provider as the container holds it; it is
+	// reported when it implements GetCapabilities, directly or through a
+	// provider wrapper.
+	Storage any
+	Email   ports.EmailProvider
+	Push    map[string]ports.PushProvider
+}
+
+// Provider is one provider's normalized capabilities. Capabilities are
+// lower snake case names without the integration prefix, so
+// PAYMENT_CAPABILITY_RECURRING is "recurring"; push platforms are reported
+// as "push_<platform>".
+type Provider struct {
+	Integration  string
+	Name         string
+	Enabled      bool
+	Capabilities []string
+}
+
+type storageCapabilities interface {
+	Name() string
+	IsEnabled() bool
+	GetCapabilities() []ports.StorageCapability
+}
+
+// Collect returns the providers with their capabilities, by integration in
+// Integrations order and then by name.
+func Collect(p Providers) []Provider {
+	var out []Provider
+	for _, name := range sortedKeys(p.Payment) {
+		pp := p.Payment[name]
+		out = append(out, Provider{IntegrationPayment, name, pp.IsEnabled(), enumNames(pp.GetCapabilities())})
+	}
+	if t := p.Tabular; t != nil {
+		out = append(out, Provider{IntegrationTabular, t.Name(), t.IsEnabled(), enumNames(t.GetCapabilities())})
+	}
+	for _, name := range sortedKeys(p.Scheduler) {
+		sp := p.Scheduler[name]
+		out = append(out, Provider{IntegrationScheduler, name, sp.IsEnabled(), enumNames(sp.GetCapabilities())})
+	}
+	if s := storageProvider(p.Storage); s != nil {
+		caps := make([]string, 0)
+		for _, c := range s.GetCapabilities() {
+			caps = append(caps, string(c))
+		}
+		out = append(out, Provider{IntegrationStorage, s.Name(), s.IsEnabled(), caps})
+	}
+	if e := p.Email; e != nil {
+		out = append(out, Provider{IntegrationMessaging, e.Name(), e.IsEnabled(), enumNames(e.GetCapabilities())})
+	}
+	for _, name := range sortedKeys(p.Push) {
+		pp := p.Push[name]
+		caps := make([]string, 0)
+		for _, platform := range pp.Platforms() {
+			caps = append(caps, "push_"+string(platform))
+		}
+		out = append(out, Provider{IntegrationMessaging, name, pp.IsEnabled(), caps})
+	}
+	return out
+}
+
+// storageProvider unwraps the container's storage provider.
+func storageProvider(provider any) storageCapabilities {
+	if s, ok := provider.(storageCapabilities); ok {
+		return s
+	}
+	if wrapper, ok := provider.(interface{ Provider() interface{} }); ok {
+		if s, ok := wrapper.Provider().(storageCapabilities); ok {
+			return s
+		}
+	}
+	return nil
+}
+
+// enumNames normalizes capability enum values such as
+// SCHEDULER_CAPABILITY_WEBHOOKS to "webhooks", leaving out UNSPECIFIED.
+func enumNames[E interface{ String() string }](values []E) []string {
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		name := v.String()
+		if _, after, ok := strings.Cut(name, "_CAPABILITY_"); ok {
+			name = after
+		}
+		if name = strings.ToLower(name); name != "unspecified" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
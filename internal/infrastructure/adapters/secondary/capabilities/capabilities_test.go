@@ -0,0 +1,172 @@
+package capabilities
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	paymentpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/payment"
+	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
+)
+
+type fakePayment struct {
+	ports.PaymentProvider
+	enabled bool
+}
+
+func (p fakePayment) IsEnabled() bool { return p.enabled }
+func (fakePayment) GetCapabilities() []paymentpb.PaymentCapability {
+	return []paymentpb.PaymentCapability{
+		paymentpb.PaymentCapability_PAYMENT_CAPABILITY_UNSPECIFIED,
+		paymentpb.PaymentCapability_PAYMENT_CAPABILITY_RECURRING,
+		paymentpb.PaymentCapability_PAYMENT_CAPABILITY_3DS,
+	}
+}
+
+type fakeScheduler struct{ ports.SchedulerProvider }
+
+func (fakeScheduler) IsEnabled() bool { return true }
+func (fakeScheduler) GetCapabilities() []schedulerpb.SchedulerCapability {
+	return []schedulerpb.SchedulerCapability{schedulerpb.SchedulerCapability_SCHEDULER_CAPABILITY_WEBHOOKS}
+}
+
+type fakeStorage struct{}
+
+func (fakeStorage) Name() string    { return "local" }
+func (fakeStorage) IsEnabled() bool { return true }
+func (fakeStorage) GetCapabilities() []ports.StorageCapability {
+	return []ports.StorageCapability{ports.StorageCapabilityUpload}
+}
+
+// wrapped hides the storage provider the way the container's wrapper does.
+type wrapped struct{ inner any }
+
+func (w wrapped) Provider() interface{} { return w.inner }
+
+type fakePush struct{ ports.PushProvider }
+
+func (fakePush) IsEnabled() bool { return true }
+func (fakePush) Platforms() []ports.PushPlatform {
+	return []ports.PushPlatform{ports.PushPlatformIOS}
+}
+
+// workspaceAuthorizer grants the permissions of the workspace in the context.
+type workspaceAuthorizer map[string][]string
+
+func (a workspaceAuthorizer) HasPermission(ctx context.Context, _, permission string) (bool, error) {
+	for _, p := range a[contextutil.ExtractWorkspaceIDFromContext(ctx)] {
+		if p == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (workspaceAuthorizer) IsEnabled() bool { return true }
+
+func TestCollect(t *testing.T) {
+	got := Collect(Providers{
+		Payment: map[string]ports.PaymentProvider{
+			"paypal": fakePayment{enabled: false},
+			"maya":   fakePayment{enabled: true},
+		},
+		Scheduler: map[string]ports.SchedulerProvider{"calendly": fakeScheduler{}},
+		Storage:   wrapped{fakeStorage{}},
+		Push:      map[string]ports.PushProvider{"fcm": fakePush{}},
+	})
+	want := []struct {
+		integration, name string
+		caps              []string
+	}{
+		{"payment", "maya", []string{"recurring", "3ds"}},
+		{"payment", "paypal", []string{"recurring", "3ds"}},
+		{"scheduler", "calendly", []string{"webhooks"}},
+		{"storage", "local", []string{"upload"}},
+		{"messaging", "fcm", []string{"push_ios"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Integration != w.integration || g.Name != w.name || len(g.Capabilities) != len(w.caps) {
+			t.Fatalf("[%d] = %+v, want %v", i, g, w)
+		}
+		for j := range w.caps {
+			if g.Capabilities[j] != w.caps[j] {
+				t.Errorf("[%d] capabilities = %v, want %v", i, g.Capabilities, w.caps)
+			}
+		}
+	}
+}
+
+func TestHandler_StatusPerWorkspace(t *testing.T) {
+	authz := workspaceAuthorizer{
+		"ws-1": {"integration_payment:read", "integration_scheduler:read"},
+		"ws-2": {"integration_scheduler:read"},
+	}
+	h := NewHandler(Providers{
+		Payment: map[string]ports.PaymentProvider{
+			"maya":   fakePayment{enabled: true},
+			"paypal": fakePayment{enabled: false},
+		},
+		Scheduler: map[string]ports.SchedulerProvider{"calendly": fakeScheduler{}},
+	}, actiongate.NewActionGatekeeper(authz, nil))
+
+	statuses := func(workspaceID string) map[string]string {
+		t.Helper()
+		ctx := contextutil.WithWorkspaceID(contextutil.WithUserID(context.Background(), "u-1"), workspaceID)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil).WithContext(ctx))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", rec.Code, rec.Body)
+		}
+		var out struct {
+			Data struct {
+				Integrations []providerJSON `json:"integrations"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]string{}
+		for _, p := range out.Data.Integrations {
+			if p.Enabled != (p.Status == StatusEnabled) {
+				t.Errorf("%+v: enabled disagrees with status", p)
+			}
+			got[p.Integration+"/"+p.Provider] = p.Status
+		}
+		return got
+	}
+
+	want := map[string]string{
+		"payment/maya":       StatusEnabled,
+		"payment/paypal":     StatusDisabled,
+		"tabular/":           StatusNotConfigured,
+		"scheduler/calendly": StatusEnabled,
+		"storage/":           StatusNotConfigured,
+		"messaging/":         StatusNotConfigured,
+	}
+	if got := statuses("ws-1"); len(got) != len(want) {
+		t.Fatalf("ws-1 = %v", got)
+	} else {
+		for k, v := range want {
+			if got[k] != v {
+				t.Errorf("ws-1 %s = %q, want %q", k, got[k], v)
+			}
+		}
+	}
+	if got := statuses("ws-2"); got["payment/maya"] != StatusNotPermitted || got["scheduler/calendly"] != StatusEnabled {
+		t.Errorf("ws-2 = %v", got)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous status = %d", rec.Code)
+	}
+}
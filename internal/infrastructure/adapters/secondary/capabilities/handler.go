@@ -0,0 +1,105 @@
+package capabilities
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// Path serves GET: every integration with its providers, capabilities and
+// status in the caller's workspace.
+const Path = "/api/integrations/capabilities"
+
+// The status of a provider in a workspace. Only StatusEnabled features
+// should be rendered.
+const (
+	StatusEnabled = "enabled"
+	// StatusNotConfigured is reported, without a provider, for an
+	// integration the deployment has no provider for.
+	StatusNotConfigured = "not_configured"
+	StatusDisabled      = "provider_disabled"
+	// StatusNotPermitted means the caller lacks integration_<name>:read
+	// in the workspace, which is how a workspace's roles switch an
+	// integration off.
+	StatusNotPermitted = "not_permitted"
+)
+
+type providerJSON struct {
+	Integration  string   `json:"integration"`
+	Provider     string   `json:"provider,omitempty"`
+	Enabled      bool     `json:"enabled"`
+	Status       string   `json:"status"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// Handler serves Path.
+type Handler struct {
+	providers []Provider
+	gate      *actiongate.ActionGatekeeper
+}
+
+// NewHandler creates the handler over the deployment's providers. gate
+// decides, per integration, whether the caller's workspace may use it.
+func NewHandler(providers Providers, gate *actiongate.ActionGatekeeper) *Handler {
+	return &Handler{providers: Collect(providers), gate: gate}
+}
+
+// ServeHTTP lists the capabilities.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return
+	}
+	ctx := r.Context()
+	if contextutil.ExtractUserIDFromContext(ctx) == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return
+	}
+	workspaceID := contextutil.ExtractWorkspaceIDFromContext(ctx)
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "workspace required"})
+		return
+	}
+
+	data := make([]providerJSON, 0, len(h.providers)+len(Integrations))
+	for _, integration := range Integrations {
+		permitted := h.gate.Check(ctx, &actiongate.CheckActionRequest{
+			Entity: "integration_" + integration,
+			Action: entityid.ActionRead,
+		}) == nil
+		found := false
+		for _, p := range h.providers {
+			if p.Integration != integration {
+				continue
+			}
+			found = true
+			out := providerJSON{Integration: integration, Provider: p.Name, Status: StatusEnabled, Capabilities: p.Capabilities}
+			switch {
+			case !p.Enabled:
+				out.Status = StatusDisabled
+			case !permitted:
+				out.Status = StatusNotPermitted
+			default:
+				out.Enabled = true
+			}
+			data = append(data, out)
+		}
+		if !found {
+			data = append(data, providerJSON{Integration: integration, Status: StatusNotConfigured, Capabilities: []string{}})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": map[string]any{
+		"workspace_id": workspaceID,
+		"integrations": data,
+	}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,118 @@
+package claimsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// SyncClaimsPath serves POST. With {"user_id": ...} the member's claims are
+// rewritten from the database; without, every member of the caller's
+// workspace is. It needs workspace_user_role:update.
+const SyncClaimsPath = "/api/entity/workspace-user-role/sync-claims"
+
+type requestJSON struct {
+	UserID string `json:"user_id"`
+}
+
+// Handler serves SyncClaimsPath.
+type Handler struct {
+	syncer *Syncer
+	gate   *actiongate.ActionGatekeeper
+}
+
+// NewHandler creates the handler over syncer.
+func NewHandler(syncer *Syncer, gate *actiongate.ActionGatekeeper) *Handler {
+	return &Handler{syncer: syncer, gate: gate}
+}
+
+// ServeHTTP syncs the requested member, or the whole workspace.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	var req requestJSON
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "invalid JSON body"})
+		return
+	}
+
+	ctx := r.Context()
+	if req.UserID == "" {
+		result, err := h.syncer.SyncWorkspace(ctx, workspaceID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": result})
+		return
+	}
+
+	result, err := h.syncUser(ctx, workspaceID, req.UserID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	if result == nil {
+		writeJSON(w, http.StatusNotFound, map[string]any{"success": false, "error": "user is not a member of this workspace"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": result})
+}
+
+// syncUser syncs a member of workspaceID; nil when userID is not one.
+func (h *Handler) syncUser(ctx context.Context, workspaceID, userID string) (*Result, error) {
+	member, err := h.syncer.Member(ctx, workspaceID, userID)
+	if err != nil || !member {
+		return nil, err
+	}
+	changed, err := h.syncer.Sync(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Synced: []string{}, Unchanged: []string{}}
+	if changed {
+		result.Synced = append(result.Synced, userID)
+	} else {
+		result.Unchanged = append(result.Unchanged, userID)
+	}
+	return result, nil
+}
+
+// authorize checks the method, the caller and workspace_user_role:update in
+// the caller's workspace, writing the error response when one fails.
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return "", false
+	}
+	ctx := r.Context()
+	if contextutil.ExtractUserIDFromContext(ctx) == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return "", false
+	}
+	workspaceID := contextutil.ExtractWorkspaceIDFromContext(ctx)
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "workspace required"})
+		return "", false
+	}
+	if err := h.gate.Check(ctx, &actiongate.CheckActionRequest{Entity: entityid.WorkspaceUserRole, Action: entityid.ActionUpdate}); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": err.Error()})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,442 @@
+// Package claimsync keeps each user's roles and permissions in the identity
+// provider's custom claims (see shared/authclaims), so frontends and the
+// authentication middleware can make coarse checks without a database hit.
+// The syncer rewrites a user's claims when their workspace roles change, as
+// announced by the workspace_user_role domain events, and on demand.
+package claimsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/shared/authclaims"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+const (
+	// DefaultQueueSize bounds the role changes waiting for Run.
+	DefaultQueueSize = 256
+	// listLimit bounds each lookup's page; a user with more workspaces,
+	// roles or grants than this gets a partial set.
+	listLimit = 1000
+)
+
+// Store reads and writes a user's custom claims at the identity provider.
+// The Firebase auth adapter implements it.
+type Store interface {
+	GetCustomUserClaims(ctx context.Context, uid string) (map[string]any, error)
+	SetCustomUserClaims(ctx context.Context, uid string, claims map[string]any) error
+}
+
+// Tables names the tables the claim set is read from.
+type Tables struct {
+	WorkspaceUser     string // default workspace_user
+	WorkspaceUserRole string // default workspace_user_role
+	Role              string // default role
+	RolePermission    string // default role_permission
+	Permission        string // default permission
+}
+
+// Config tunes the syncer.
+type Config struct {
+	Tables Tables
+	// QueueSize bounds the role changes waiting for Run (default
+	// DefaultQueueSize); changes beyond it are dropped and logged.
+	QueueSize int
+	// Budget is the size the user's custom claims may take, as JSON
+	// (default authclaims.MaxBytes).
+	Budget int
+}
+
+// Result reports a workspace sync.
+type Result struct {
+	// Synced lists the users whose claims were rewritten.
+	Synced []string `json:"synced"`
+	// Unchanged lists the users whose claims were already current.
+	Unchanged []string `json:"unchanged"`
+	// Failed maps users whose sync failed to the error.
+	Failed map[string]string `json:"failed,omitempty"`
+}
+
+// change is a queued role change.
+type change struct {
+	workspaceUserID string
+	assignmentID    string
+}
+
+// Syncer writes users' claim sets from the database to the Store.
+type Syncer struct {
+	ops    interfaces.DatabaseOperation
+	store  Store
+	config Config
+	queue  chan change
+}
+
+// NewSyncer creates a syncer reading ops and writing store.
+func NewSyncer(ops interfaces.DatabaseOperation, store Store, config Config) *Syncer {
+	t := &config.Tables
+	for _, d := range []struct {
+		field *string
+		name  string
+	}{
+		{&t.WorkspaceUser, "workspace_user"},
+		{&t.WorkspaceUserRole, "workspace_user_role"},
+		{&t.Role, "role"},
+		{&t.RolePermission, "role_permission"},
+		{&t.Permission, "permission"},
+	} {
+		if *d.field == "" {
+			*d.field = d.name
+		}
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultQueueSize
+	}
+	if config.Budget <= 0 {
+		config.Budget = authclaims.MaxBytes
+	}
+	return &Syncer{ops: ops, store: store, config: config, queue: make(chan change, config.QueueSize)}
+}
+
+// ── Worker ───────────────────────────────────────────────────────────────────
+
+// PublishDomainEvent queues the user of a workspace_user_role event for
+// Run; other events are ignored. It never blocks.
+func (s *Syncer) PublishDomainEvent(e domainevent.Event) {
+	switch e.Type {
+	case domainevent.WorkspaceUserRoleCreated, domainevent.WorkspaceUserRoleUpdated, domainevent.WorkspaceUserRoleDeleted:
+	default:
+		return
+	}
+	assignment, ok := e.Data.(interface {
+		GetId() string
+		GetWorkspaceUserId() string
+	})
+	if !ok {
+		return
+	}
+	select {
+	case s.queue <- change{workspaceUserID: assignment.GetWorkspaceUserId(), assignmentID: assignment.GetId()}:
+	default:
+		log.Printf("⚠️  claimsync: queue full, dropped %s for role assignment %s", e.Type, assignment.GetId())
+	}
+}
+
+// Run syncs the users of queued role changes until ctx is done.
+func (s *Syncer) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case c := <-s.queue:
+			userID, err := s.userOf(ctx, c)
+			if err == nil {
+				_, err = s.Sync(ctx, userID)
+			}
+			if err != nil {
+				log.Printf("⚠️  claimsync: role assignment %s: %v", c.assignmentID, err)
+			}
+		}
+	}
+}
+
+// userOf finds the user a role change is about. A deleted assignment that
+// did not carry its workspace user is looked up by ID.
+func (s *Syncer) userOf(ctx context.Context, c change) (string, error) {
+	ctx = systemContext(ctx)
+	if c.workspaceUserID == "" {
+		row, err := s.ops.Read(ctx, s.config.Tables.WorkspaceUserRole, c.assignmentID)
+		if err != nil {
+			return "", fmt.Errorf("read role assignment: %w", err)
+		}
+		c.workspaceUserID = str(row["workspace_user_id"])
+	}
+	row, err := s.ops.Read(ctx, s.config.Tables.WorkspaceUser, c.workspaceUserID)
+	if err != nil {
+		return "", fmt.Errorf("read workspace user %s: %w", c.workspaceUserID, err)
+	}
+	userID := str(row["user_id"])
+	if userID == "" {
+		return "", fmt.Errorf("workspace user %s has no user", c.workspaceUserID)
+	}
+	return userID, nil
+}
+
+// ── Sync ─────────────────────────────────────────────────────────────────────
+
+// Sync writes userID's claim set under authclaims.Key, keeping the user's
+// other custom claims. changed is false when the claims were current and
+// nothing was written, so tokens are not refreshed needlessly.
+func (s *Syncer) Sync(ctx context.Context, userID string) (changed bool, err error) {
+	claims, err := s.Build(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	current, err := s.store.GetCustomUserClaims(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("read custom claims of %s: %w", userID, err)
+	}
+	next := make(map[string]any, len(current)+1)
+	for k, v := range current {
+		if k != authclaims.Key {
+			next[k] = v
+		}
+	}
+	others, _ := json.Marshal(next)
+	budget := s.config.Budget - len(others)
+	next[authclaims.Key] = claims.Fit(budget)
+
+	if existing, ok := authclaims.Parse(current[authclaims.Key]); ok && sameJSON(existing, next[authclaims.Key]) {
+		return false, nil
+	}
+	if err := s.store.SetCustomUserClaims(ctx, userID, next); err != nil {
+		return false, fmt.Errorf("write custom claims of %s: %w", userID, err)
+	}
+	return true, nil
+}
+
+// SyncWorkspace syncs every member of workspaceID. A failing user does not
+// stop the others.
+func (s *Syncer) SyncWorkspace(ctx context.Context, workspaceID string) (Result, error) {
+	members, err := s.list(ctx, s.config.Tables.WorkspaceUser, "workspace_id", workspaceID)
+	if err != nil {
+		return Result{}, fmt.Errorf("list workspace users: %w", err)
+	}
+	result := Result{Synced: []string{}, Unchanged: []string{}}
+	seen := map[string]bool{}
+	for _, member := range members {
+		userID := str(member["user_id"])
+		if userID == "" || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		changed, err := s.Sync(ctx, userID)
+		switch {
+		case err != nil:
+			if result.Failed == nil {
+				result.Failed = map[string]string{}
+			}
+			result.Failed[userID] = err.Error()
+		case changed:
+			result.Synced = append(result.Synced, userID)
+		default:
+			result.Unchanged = append(result.Unchanged, userID)
+		}
+	}
+	return result, nil
+}
+
+// Member reports whether userID belongs to workspaceID.
+func (s *Syncer) Member(ctx context.Context, workspaceID, userID string) (bool, error) {
+	members, err := s.list(ctx, s.config.Tables.WorkspaceUser, "user_id", userID)
+	if err != nil {
+		return false, fmt.Errorf("list workspace users: %w", err)
+	}
+	for _, member := range members {
+		if str(member["workspace_id"]) == workspaceID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Build reads userID's roles and permissions in each of their workspaces.
+// Permissions are the ALLOW grants of the user's roles net of their DENY
+// grants.
+func (s *Syncer) Build(ctx context.Context, userID string) (authclaims.Claims, error) {
+	t := s.config.Tables
+	memberships, err := s.list(ctx, t.WorkspaceUser, "user_id", userID)
+	if err != nil {
+		return authclaims.Claims{}, fmt.Errorf("list workspaces of %s: %w", userID, err)
+	}
+
+	roles := map[string]string{}       // role ID → name
+	grants := map[string][2][]string{} // role ID → ALLOW, DENY codes
+	codes := map[string]string{}       // permission ID → code
+	claims := authclaims.Claims{Workspaces: map[string]authclaims.Workspace{}}
+	for _, membership := range memberships {
+		workspaceID := str(membership["workspace_id"])
+		if workspaceID == "" {
+			continue
+		}
+		assignments, err := s.list(ctx, t.WorkspaceUserRole, "workspace_user_id", str(membership["id"]))
+		if err != nil {
+			return authclaims.Claims{}, fmt.Errorf("list roles of workspace user %s: %w", str(membership["id"]), err)
+		}
+		names := map[string]bool{}
+		allow, deny := map[string]bool{}, map[string]bool{}
+		for _, assignment := range assignments {
+			roleID := str(assignment["role_id"])
+			if roleID == "" {
+				continue
+			}
+			if _, ok := roles[roleID]; !ok {
+				if roles[roleID], err = s.roleName(ctx, roleID); err != nil {
+					return authclaims.Claims{}, err
+				}
+				if grants[roleID], err = s.roleGrants(ctx, roleID, codes); err != nil {
+					return authclaims.Claims{}, err
+				}
+			}
+			if roles[roleID] == "" {
+				continue
+			}
+			names[roles[roleID]] = true
+			for _, code := range grants[roleID][0] {
+				allow[code] = true
+			}
+			for _, code := range grants[roleID][1] {
+				deny[code] = true
+			}
+		}
+		for code := range deny {
+			delete(allow, code)
+		}
+		ws := claims.Workspaces[workspaceID]
+		ws.Roles = merge(ws.Roles, names)
+		ws.Permissions = merge(ws.Permissions, allow)
+		claims.Workspaces[workspaceID] = ws
+	}
+	return claims, nil
+}
+
+// roleName returns the role's name; empty for an inactive role, whose
+// grants do not count.
+func (s *Syncer) roleName(ctx context.Context, roleID string) (string, error) {
+	row, err := s.ops.Read(systemContext(ctx), s.config.Tables.Role, roleID)
+	if err != nil {
+		return "", fmt.Errorf("read role %s: %w", roleID, err)
+	}
+	if !active(row) {
+		return "", nil
+	}
+	return str(row["name"]), nil
+}
+
+// roleGrants returns the permission codes the role allows and denies,
+// caching permission codes in codes.
+func (s *Syncer) roleGrants(ctx context.Context, roleID string, codes map[string]string) ([2][]string, error) {
+	var grants [2][]string
+	rows, err := s.list(ctx, s.config.Tables.RolePermission, "role_id", roleID)
+	if err != nil {
+		return grants, fmt.Errorf("list permissions of role %s: %w", roleID, err)
+	}
+	for _, row := range rows {
+		permissionID := str(row["permission_id"])
+		if permissionID == "" {
+			continue
+		}
+		code, ok := codes[permissionID]
+		if !ok {
+			permission, err := s.ops.Read(systemContext(ctx), s.config.Tables.Permission, permissionID)
+			if err != nil {
+				return grants, fmt.Errorf("read permission %s: %w", permissionID, err)
+			}
+			if active(permission) {
+				code = str(permission["permission_code"])
+			}
+			codes[permissionID] = code
+		}
+		if code == "" {
+			continue
+		}
+		if denies(row["permission_type"]) {
+			grants[1] = append(grants[1], code)
+		} else {
+			grants[0] = append(grants[0], code)
+		}
+	}
+	return grants, nil
+}
+
+// list returns the active rows of table whose field equals value.
+func (s *Syncer) list(ctx context.Context, table, field, value string) ([]map[string]any, error) {
+	result, err := s.ops.List(systemContext(ctx), table, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{{
+			Field: field,
+			FilterType: &commonpb.TypedFilter_StringFilter{
+				StringFilter: &commonpb.StringFilter{
+					Value:         value,
+					Operator:      commonpb.StringOperator_STRING_EQUALS,
+					CaseSensitive: true,
+				},
+			},
+		}}},
+		Pagination: &commonpb.PaginationRequest{
+			Limit:  listLimit,
+			Method: &commonpb.PaginationRequest_Offset{Offset: &commonpb.OffsetPagination{Page: 1}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]map[string]any, 0, len(result.Data))
+	for _, row := range result.Data {
+		// Guard against providers that ignore the filter.
+		if str(row[field]) == value && active(row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// systemContext reads across workspaces: a user's claims cover all of
+// them, so the workspace guard must not confine the lookups to the
+// caller's.
+func systemContext(ctx context.Context) context.Context {
+	return identity.WithRequestIdentity(ctx, &identity.RequestIdentity{})
+}
+
+// denies reports whether a permission_type value, stored as the enum name
+// or number, is DENY.
+func denies(v any) bool {
+	switch v := v.(type) {
+	case string:
+		return strings.HasSuffix(strings.ToUpper(v), "DENY") || v == "2"
+	case nil:
+		return false
+	default:
+		return fmt.Sprint(v) == "2"
+	}
+}
+
+func sameJSON(a, b any) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
+
+func active(row map[string]any) bool {
+	v, ok := row["active"].(bool)
+	return !ok || v
+}
+
+// merge adds the set's members to list, sorted and without duplicates.
+func merge(list []string, set map[string]bool) []string {
+	for _, v := range list {
+		set[v] = true
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	merged := make([]string, 0, len(set))
+	for v := range set {
+		merged = append(merged, v)
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
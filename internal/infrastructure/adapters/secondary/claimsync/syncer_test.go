@@ -0,0 +1,225 @@
+package claimsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	workspaceuserrolepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/workspace_user_role"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	"github.com/erniealice/espyna-golang/shared/authclaims"
+)
+
+// memOps keeps rows per table; List honours string equality filters.
+type memOps struct {
+	interfaces.DatabaseOperation
+	tables map[string]map[string]map[string]any
+}
+
+func (o *memOps) Read(_ context.Context, table string, id string) (map[string]any, error) {
+	row, ok := o.tables[table][id]
+	if !ok {
+		return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return row, nil
+}
+
+func (o *memOps) List(_ context.Context, table string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	var rows []map[string]any
+	for _, row := range o.tables[table] {
+		match := true
+		for _, f := range params.Filters.Filters {
+			if row[f.Field] != f.GetStringFilter().GetValue() {
+				match = false
+			}
+		}
+		if match {
+			rows = append(rows, row)
+		}
+	}
+	return &interfaces.ListResult{Data: rows}, nil
+}
+
+// memStore keeps custom claims per user, counting writes.
+type memStore struct {
+	mu     sync.Mutex
+	claims map[string]map[string]any
+	writes int
+}
+
+func (s *memStore) GetCustomUserClaims(_ context.Context, uid string) (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Round-trip through JSON, as the provider does.
+	encoded, _ := json.Marshal(s.claims[uid])
+	var claims map[string]any
+	_ = json.Unmarshal(encoded, &claims)
+	return claims, nil
+}
+
+func (s *memStore) SetCustomUserClaims(_ context.Context, uid string, claims map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.claims[uid] = claims
+	s.writes++
+	return nil
+}
+
+func (s *memStore) rbac(uid string) authclaims.Claims {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, _ := authclaims.Parse(s.claims[uid][authclaims.Key])
+	return c
+}
+
+// fixture: Ada is an admin in ws-1 and a viewer in ws-2, Bob a viewer in
+// ws-1. Admins may create and delete clients, but a DENY grant takes
+// client:delete back; viewers read clients.
+func fixture() (*memOps, *memStore) {
+	rows := func(list ...map[string]any) map[string]map[string]any {
+		out := map[string]map[string]any{}
+		for _, row := range list {
+			out[row["id"].(string)] = row
+		}
+		return out
+	}
+	ops := &memOps{tables: map[string]map[string]map[string]any{
+		"workspace_user": rows(
+			map[string]any{"id": "wu-1", "workspace_id": "ws-1", "user_id": "ada", "active": true},
+			map[string]any{"id": "wu-2", "workspace_id": "ws-2", "user_id": "ada", "active": true},
+			map[string]any{"id": "wu-3", "workspace_id": "ws-1", "user_id": "bob", "active": true},
+		),
+		"workspace_user_role": rows(
+			map[string]any{"id": "a-1", "workspace_user_id": "wu-1", "role_id": "admin"},
+			map[string]any{"id": "a-2", "workspace_user_id": "wu-2", "role_id": "viewer"},
+			map[string]any{"id": "a-3", "workspace_user_id": "wu-1", "role_id": "retired"},
+			map[string]any{"id": "a-4", "workspace_user_id": "wu-3", "role_id": "viewer"},
+		),
+		"role": rows(
+			map[string]any{"id": "admin", "name": "Admin", "active": true},
+			map[string]any{"id": "viewer", "name": "Viewer", "active": true},
+			map[string]any{"id": "retired", "name": "Retired", "active": false},
+		),
+		"role_permission": rows(
+			map[string]any{"id": "g-1", "role_id": "admin", "permission_id": "p-create", "permission_type": "PERMISSION_TYPE_ALLOW"},
+			map[string]any{"id": "g-2", "role_id": "admin", "permission_id": "p-delete", "permission_type": "PERMISSION_TYPE_ALLOW"},
+			map[string]any{"id": "g-3", "role_id": "admin", "permission_id": "p-delete", "permission_type": "PERMISSION_TYPE_DENY"},
+			map[string]any{"id": "g-4", "role_id": "viewer", "permission_id": "p-read", "permission_type": int64(1)},
+			map[string]any{"id": "g-5", "role_id": "retired", "permission_id": "p-manage"},
+		),
+		"permission": rows(
+			map[string]any{"id": "p-create", "permission_code": "client:create"},
+			map[string]any{"id": "p-delete", "permission_code": "client:delete"},
+			map[string]any{"id": "p-read", "permission_code": "client:read"},
+			map[string]any{"id": "p-manage", "permission_code": "client:manage"},
+		),
+	}}
+	store := &memStore{claims: map[string]map[string]any{
+		"ada": {"tier": "gold", authclaims.Key: map[string]any{"ws": map[string]any{"ws-9": map[string]any{}}}},
+	}}
+	return ops, store
+}
+
+func TestSyncer_WritesRolesAndPermissions(t *testing.T) {
+	ops, store := fixture()
+	s := NewSyncer(ops, store, Config{})
+
+	changed, err := s.Sync(context.Background(), "ada")
+	if err != nil || !changed {
+		t.Fatalf("Sync = %v, %v", changed, err)
+	}
+	got := store.rbac("ada")
+	want := authclaims.Claims{Workspaces: map[string]authclaims.Workspace{
+		"ws-1": {Roles: []string{"Admin"}, Permissions: []string{"client:create"}},
+		"ws-2": {Roles: []string{"Viewer"}, Permissions: []string{"client:read"}},
+	}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("claims = %v, want %v", got, want)
+	}
+	if store.claims["ada"]["tier"] != "gold" {
+		t.Errorf("other claims lost: %v", store.claims["ada"])
+	}
+
+	if changed, err := s.Sync(context.Background(), "ada"); err != nil || changed || store.writes != 1 {
+		t.Errorf("resync = %v, %v (%d writes), want no write", changed, err, store.writes)
+	}
+}
+
+func TestSyncer_TruncatesToBudget(t *testing.T) {
+	ops, store := fixture()
+	s := NewSyncer(ops, store, Config{Budget: 120})
+	if _, err := s.Sync(context.Background(), "ada"); err != nil {
+		t.Fatal(err)
+	}
+	got := store.rbac("ada")
+	if !got.Truncated {
+		t.Fatalf("claims = %+v, want truncated", got)
+	}
+	if _, known := got.HasPermission("ws-1", "client:create"); known {
+		t.Error("truncated claims answered a permission check")
+	}
+}
+
+func TestSyncer_RunSyncsRoleChanges(t *testing.T) {
+	ops, store := fixture()
+	s := NewSyncer(ops, store, Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	s.PublishDomainEvent(domainevent.Event{Type: domainevent.ClientCreated, Data: &workspaceuserrolepb.WorkspaceUserRole{Id: "a-4"}})
+	delete(ops.tables["workspace_user_role"], "a-4")
+	s.PublishDomainEvent(domainevent.Event{Type: domainevent.WorkspaceUserRoleDeleted, Data: &workspaceuserrolepb.WorkspaceUserRole{Id: "a-4", WorkspaceUserId: "wu-3"}})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for store.rbac("bob").Workspaces == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	got := store.rbac("bob")
+	if ws, ok := got.Workspaces["ws-1"]; !ok || ws.Roles != nil {
+		t.Errorf("bob's claims after unassigning = %+v", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ops, store := fixture()
+	h := NewHandler(NewSyncer(ops, store, Config{}), testutil.OpenActionGate())
+	userCtx := contextutil.WithWorkspaceID(contextutil.WithUserID(context.Background(), "admin-1"), "ws-2")
+	post := func(body string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, SyncClaimsPath, bytes.NewBufferString(body)).WithContext(userCtx))
+		var resp map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, resp
+	}
+
+	if code, _ := post(`{"user_id": "bob"}`); code != http.StatusNotFound {
+		t.Errorf("member of another workspace = %d, want 404", code)
+	}
+	if code, resp := post(`{"user_id": "ada"}`); code != http.StatusOK || fmt.Sprint(resp["data"]) != "map[synced:[ada] unchanged:[]]" {
+		t.Errorf("sync ada = %d %v", code, resp)
+	}
+	if code, resp := post(``); code != http.StatusOK || fmt.Sprint(resp["data"]) != "map[synced:[] unchanged:[ada]]" {
+		t.Errorf("sync workspace = %d %v", code, resp)
+	}
+	if _, ok := store.claims["bob"]; ok {
+		t.Error("workspace sync reached a user of another workspace")
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, SyncClaimsPath, nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous = %d", rec.Code)
+	}
+}
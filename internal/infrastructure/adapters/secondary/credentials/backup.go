@@ -0,0 +1,103 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// ErrNoBackupKey is returned by Backup when no backup key is configured.
+var ErrNoBackupKey = errors.New("no credential backup key is configured")
+
+// Bundle is an export of the encrypted credential copies of an
+// environment. It holds no plaintext and is safe to store with other
+// backups; the backup key opens it.
+type Bundle struct {
+	Environment string        `json:"environment"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Entries     []BundleEntry `json:"entries"`
+}
+
+// BundleEntry is one sealed credential.
+type BundleEntry struct {
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	Provider    string `json:"provider"`
+	Name        string `json:"name"`
+	Kind        Kind   `json:"kind"`
+	KeyID       string `json:"key_id,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	// BackupKeyID names the key that opens Sealed.
+	BackupKeyID string `json:"backup_key_id"`
+	Sealed      string `json:"sealed"`
+}
+
+// Backup exports the encrypted copies of every credential recorded in
+// this environment, across workspaces. Credentials recorded before the
+// backup key was configured have no copy until their next scan.
+func (inv *Inventory) Backup(ctx context.Context) (*Bundle, error) {
+	if inv.config.Sealer == nil {
+		return nil, ErrNoBackupKey
+	}
+	result, err := inv.ops.List(ctx, inv.config.Table, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{
+			stringEquals("environment", inv.config.Environment),
+		}},
+		Pagination: firstPage(listLimit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list credentials: %w", err)
+	}
+	bundle := &Bundle{Environment: inv.config.Environment, CreatedAt: inv.now().UTC(), Entries: []BundleEntry{}}
+	for _, row := range result.Data {
+		c := fromRow(row)
+		if c.Environment != inv.config.Environment || c.sealed == "" {
+			continue
+		}
+		bundle.Entries = append(bundle.Entries, BundleEntry{
+			WorkspaceID: c.WorkspaceID,
+			Provider:    c.Provider,
+			Name:        c.Name,
+			Kind:        c.Kind,
+			KeyID:       c.KeyID,
+			Fingerprint: c.Fingerprint,
+			BackupKeyID: c.BackupKeyID,
+			Sealed:      c.sealed,
+		})
+	}
+	return bundle, nil
+}
+
+// Restored is a credential recovered from a Bundle.
+type Restored struct {
+	WorkspaceID string
+	Provider    string
+	Name        string
+	Secret      []byte
+}
+
+// OpenBundle decrypts the entries of bundle that sealer opens. Entries
+// sealed under another key are skipped and counted in the error, so a
+// bundle spanning a key rotation is opened with each key in turn.
+func OpenBundle(bundle *Bundle, sealer *Sealer) ([]Restored, error) {
+	var restored []Restored
+	skipped := 0
+	for _, e := range bundle.Entries {
+		if e.BackupKeyID != sealer.KeyID() {
+			skipped++
+			continue
+		}
+		secret, err := sealer.Open(e.Sealed, sealAAD(bundle.Environment, e.WorkspaceID, e.Name))
+		if err != nil {
+			return restored, fmt.Errorf("open %s: %w", e.Name, err)
+		}
+		restored = append(restored, Restored{WorkspaceID: e.WorkspaceID, Provider: e.Provider, Name: e.Name, Secret: secret})
+	}
+	if skipped > 0 {
+		return restored, fmt.Errorf("%d of %d entries were sealed with another key: %w", skipped, len(bundle.Entries), ErrWrongKey)
+	}
+	return restored, nil
+}
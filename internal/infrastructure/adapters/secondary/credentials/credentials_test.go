@@ -0,0 +1,294 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+)
+
+// memOps stores rows in memory and honours the string filters the
+// inventory uses.
+type memOps struct {
+	interfaces.DatabaseOperation
+	rows   []map[string]any
+	nextID int
+}
+
+func (o *memOps) Create(_ context.Context, _ string, data map[string]any) (map[string]any, error) {
+	o.nextID++
+	row := map[string]any{"id": fmt.Sprintf("c%d", o.nextID)}
+	for field, value := range data {
+		row[field] = value
+	}
+	o.rows = append(o.rows, row)
+	return row, nil
+}
+
+func (o *memOps) Update(_ context.Context, _ string, id string, data map[string]any) (map[string]any, error) {
+	for _, row := range o.rows {
+		if row["id"] == id {
+			for field, value := range data {
+				row[field] = value
+			}
+			return row, nil
+		}
+	}
+	return nil, fmt.Errorf("no row %s", id)
+}
+
+func (o *memOps) List(_ context.Context, _ string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	var data []map[string]any
+rows:
+	for _, row := range o.rows {
+		for _, f := range params.Filters.GetFilters() {
+			if s := f.GetStringFilter(); s != nil && str(row[f.Field]) != s.Value {
+				continue rows
+			}
+		}
+		data = append(data, row)
+	}
+	if limit := int(params.Pagination.GetLimit()); limit > 0 && len(data) > limit {
+		data = data[:limit]
+	}
+	return &interfaces.ListResult{Data: data}, nil
+}
+
+func testSealer(t *testing.T, seed byte) *Sealer {
+	t.Helper()
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = seed + byte(i)
+	}
+	s, err := NewSealer(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newTestInventory(config Config, env map[string]string, now *time.Time) (*Inventory, *memOps) {
+	ops := &memOps{}
+	inv := NewInventory(ops, config, func(name string) string { return env[name] })
+	inv.now = func() time.Time { return *now }
+	return inv, ops
+}
+
+var testSources = []Source{
+	{Provider: "paypal", Name: "PAYPAL_SECRET", Kind: KindClientSecret, KeyIDVar: "PAYPAL_ID", RotateAfter: 365 * day},
+	{Provider: "calendly", Name: "CALENDLY_PAT", Kind: KindAccessToken},
+	{Provider: "microsoft", Name: "MS_TOKEN", Kind: KindAccessToken, ExpiryVar: "MS_TOKEN_EXPIRY"},
+	{Provider: "notion", Name: "NOTION_KEY", Kind: KindAPIKey},
+}
+
+func TestInventory_ScanTracksRotation(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	env := map[string]string{
+		"PAYPAL_SECRET":   "secret-1",
+		"PAYPAL_ID":       "client-abc",
+		"CALENDLY_PAT":    "pat-1",
+		"MS_TOKEN":        "token",
+		"MS_TOKEN_EXPIRY": "2026-01-10",
+	}
+	inv, ops := newTestInventory(Config{
+		Environment: "production",
+		Sources:     testSources,
+		Expiries:    map[string]time.Time{"CALENDLY_PAT": now.Add(400 * day)},
+		RotateAfter: map[string]time.Duration{"*": 30 * day},
+	}, env, &now)
+
+	result, err := inv.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Recorded != 3 || len(result.Rotated) != 0 || len(ops.rows) != 3 {
+		t.Fatalf("first scan = %+v with %d rows", result, len(ops.rows))
+	}
+	for _, row := range ops.rows {
+		if strings.Contains(fmt.Sprint(row), "secret-1") || strings.Contains(fmt.Sprint(row), "pat-1") {
+			t.Fatalf("row stores a secret: %v", row)
+		}
+	}
+
+	// Sixty days on: the token has expired, the PAT is past the default
+	// period, PayPal keeps its own year.
+	now = now.Add(60 * day)
+	env["PAYPAL_SECRET"] = "secret-2"
+	result, err = inv.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Recorded != 3 || fmt.Sprint(result.Rotated) != "[PAYPAL_SECRET]" || len(ops.rows) != 3 {
+		t.Fatalf("second scan = %+v with %d rows", result, len(ops.rows))
+	}
+
+	credentials, err := inv.List(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range credentials {
+		got = append(got, fmt.Sprintf("%s:%s:%d", c.Name, inv.Status(c), int(c.Age(now)/day)))
+	}
+	if want := "[CALENDLY_PAT:rotation_due:60 MS_TOKEN:expired:60 PAYPAL_SECRET:ok:0]"; fmt.Sprint(got) != want {
+		t.Fatalf("inventory = %v, want %s", got, want)
+	}
+	if credentials[2].KeyID != "client-abc" {
+		t.Errorf("paypal key ID = %q", credentials[2].KeyID)
+	}
+
+	report, err := inv.RotationDue(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Due) != 2 || report.Due[0].Name != "MS_TOKEN" || report.Due[1].Name != "CALENDLY_PAT" {
+		t.Fatalf("report = %+v", report.Due)
+	}
+}
+
+func TestInventory_WorkspaceCredentials(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	inv, _ := newTestInventory(Config{Environment: "production", Sources: []Source{}}, nil, &now)
+	ctx := context.Background()
+	for _, ws := range []string{"ws-1", "ws-2"} {
+		if err := inv.Observe(ctx, Observation{WorkspaceID: ws, Provider: "calendly", Name: "calendly_pat", Kind: KindAccessToken, Secret: []byte(ws)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := inv.Observe(ctx, Observation{Name: "nothing"}); err == nil {
+		t.Error("observing without a secret succeeded")
+	}
+	credentials, err := inv.List(ctx, "ws-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(credentials) != 1 || credentials[0].WorkspaceID != "ws-2" {
+		t.Fatalf("ws-2 sees %+v", credentials)
+	}
+}
+
+func TestInventory_BackupRoundTrip(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	env := map[string]string{"NOTION_KEY": "notion-secret", "CALENDLY_PAT": "pat-1"}
+	sealer := testSealer(t, 1)
+	inv, _ := newTestInventory(Config{Environment: "staging", Sources: testSources, Sealer: sealer}, env, &now)
+	if _, err := inv.Scan(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, err := inv.Backup(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(bundle)
+	if strings.Contains(string(raw), "notion-secret") || len(bundle.Entries) != 2 {
+		t.Fatalf("bundle = %s", raw)
+	}
+	restored, err := OpenBundle(bundle, sealer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := map[string]string{}
+	for _, r := range restored {
+		secrets[r.Name] = string(r.Secret)
+	}
+	if secrets["NOTION_KEY"] != "notion-secret" || secrets["CALENDLY_PAT"] != "pat-1" {
+		t.Fatalf("restored = %v", secrets)
+	}
+
+	if _, err := OpenBundle(bundle, testSealer(t, 2)); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("other key: err = %v, want ErrWrongKey", err)
+	}
+	// A sealed value moved onto another credential does not open.
+	bundle.Entries[0].Sealed, bundle.Entries[1].Sealed = bundle.Entries[1].Sealed, bundle.Entries[0].Sealed
+	if _, err := OpenBundle(bundle, sealer); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("swapped entries: err = %v, want ErrWrongKey", err)
+	}
+
+	unsealed, _ := newTestInventory(Config{Sources: testSources}, env, &now)
+	if _, err := unsealed.Backup(context.Background()); !errors.Is(err, ErrNoBackupKey) {
+		t.Errorf("no key: err = %v, want ErrNoBackupKey", err)
+	}
+}
+
+func TestParseSealerKey(t *testing.T) {
+	hexKey := strings.Repeat("ab", 32)
+	a, err := ParseSealerKey(hexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseSealerKey("q6urq6urq6urq6urq6urq6urq6urq6urq6urq6urq6s=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.KeyID() != b.KeyID() {
+		t.Errorf("hex and base64 of one key differ: %s, %s", a.KeyID(), b.KeyID())
+	}
+	if _, err := ParseSealerKey("c2hvcnQ="); err == nil {
+		t.Error("a short key was accepted")
+	}
+}
+
+func TestSource_ServiceAccountKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sa.json")
+	if err := os.WriteFile(path, []byte(`{"type":"service_account","client_email":"bot@proj.iam.gserviceaccount.com","private_key_id":"k1"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	issued := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, issued, issued); err != nil {
+		t.Fatal(err)
+	}
+	source := Source{Provider: "google", Name: "GOOGLE_APPLICATION_CREDENTIALS", Kind: KindServiceAccountKey, File: true}
+	o, ok, err := source.read(func(string) string { return path })
+	if err != nil || !ok {
+		t.Fatalf("read = %v, %v", ok, err)
+	}
+	if o.KeyID != "bot@proj.iam.gserviceaccount.com/k1" || !o.IssuedAt.Equal(issued) || !strings.Contains(string(o.Secret), "service_account") {
+		t.Fatalf("observation = %+v", o)
+	}
+
+	if _, _, err := source.read(func(string) string { return path + ".missing" }); err == nil {
+		t.Error("a missing key file was not reported")
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	inv, _ := newTestInventory(Config{Environment: "production", Sources: testSources, RotateAfter: map[string]time.Duration{"*": day}},
+		map[string]string{"NOTION_KEY": "k", "CALENDLY_PAT": "p"}, &now)
+	if _, err := inv.Scan(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * day)
+	_ = inv.Observe(context.Background(), Observation{Provider: "notion", Name: "NOTION_KEY", Kind: KindAPIKey, Secret: []byte("k2")})
+	h := NewHandlers(inv, testutil.OpenActionGate())
+
+	serve := func(ctx context.Context, handler http.HandlerFunc, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx))
+		return rec
+	}
+	if rec := serve(context.Background(), h.List, ListPath); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous status = %d, want 401", rec.Code)
+	}
+	ctx := contextutil.WithUserID(context.Background(), "user-1")
+	rec := serve(ctx, h.List, ListPath+"?due=true")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"name":"CALENDLY_PAT"`) ||
+		strings.Contains(rec.Body.String(), `"name":"NOTION_KEY"`) || !strings.Contains(rec.Body.String(), `"status":"rotation_due"`) {
+		t.Errorf("due = %d %s", rec.Code, rec.Body)
+	}
+	if rec := serve(ctx, h.Backup, BackupPath); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("backup without a key = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,154 @@
+package credentials
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// The inventory endpoints:
+//
+//	GET ListPath            the platform's credentials and the current
+//	                        workspace's, with age, expiry and status
+//	GET ListPath?due=true   only those expired, expiring or due for
+//	                        rotation, most urgent first
+//	GET BackupPath          the encrypted backup bundle of the environment
+//
+// Listing needs credential:list; the backup, which spans workspaces,
+// needs credential:manage. Neither ever returns a secret in the clear.
+const (
+	ListPath   = "/api/credentials"
+	BackupPath = "/api/credentials/backup"
+)
+
+// entityCredential is the permission entity of the inventory.
+const entityCredential = "credential"
+
+type credentialJSON struct {
+	WorkspaceID   string `json:"workspace_id,omitempty"`
+	Provider      string `json:"provider"`
+	Name          string `json:"name"`
+	Kind          Kind   `json:"kind"`
+	Fingerprint   string `json:"fingerprint"`
+	KeyID         string `json:"key_id,omitempty"`
+	RotatedAt     int64  `json:"rotated_at"`
+	LastSeenAt    int64  `json:"last_seen_at"`
+	ExpiresAt     int64  `json:"expires_at,omitempty"`
+	AgeDays       int    `json:"age_days"`
+	RotateAfter   int    `json:"rotate_after_days"`
+	RotationDueAt int64  `json:"rotation_due_at"`
+	Status        Status `json:"status"`
+	BackedUp      bool   `json:"backed_up"`
+}
+
+// Handlers serves the inventory endpoints.
+type Handlers struct {
+	inventory *Inventory
+	gate      *actiongate.ActionGatekeeper
+}
+
+// NewHandlers creates the endpoint handlers over inventory. gate decides
+// who may read it.
+func NewHandlers(inventory *Inventory, gate *actiongate.ActionGatekeeper) *Handlers {
+	return &Handlers{inventory: inventory, gate: gate}
+}
+
+// List returns the inventory, or with due=true its rotation report.
+func (h *Handlers) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !h.allowed(w, r, entityid.ActionList) {
+		return
+	}
+	workspaceID := contextutil.ExtractWorkspaceIDFromContext(ctx)
+	var credentials []Credential
+	if due := r.URL.Query().Get("due"); due == "true" || due == "1" {
+		report, err := h.inventory.RotationDue(ctx, workspaceID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+			return
+		}
+		credentials = report.Due
+	} else {
+		var err error
+		if credentials, err = h.inventory.List(ctx, workspaceID); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+			return
+		}
+	}
+	now := h.inventory.now()
+	data := make([]credentialJSON, 0, len(credentials))
+	for _, c := range credentials {
+		data = append(data, toJSON(c, now, h.inventory.Status(c)))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "environment": h.inventory.Environment(), "data": data})
+}
+
+// Backup returns the encrypted backup bundle as a download.
+func (h *Handlers) Backup(w http.ResponseWriter, r *http.Request) {
+	if !h.allowed(w, r, entityid.ActionManage) {
+		return
+	}
+	bundle, err := h.inventory.Backup(r.Context())
+	if errors.Is(err, ErrNoBackupKey) {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="credentials-`+bundle.Environment+`-`+bundle.CreatedAt.Format("20060102")+`.json"`)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(bundle)
+}
+
+// allowed checks the caller may take action on the inventory, writing the
+// refusal when not.
+func (h *Handlers) allowed(w http.ResponseWriter, r *http.Request, action string) bool {
+	ctx := r.Context()
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return false
+	}
+	if contextutil.ExtractUserIDFromContext(ctx) == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return false
+	}
+	if err := h.gate.Check(ctx, &actiongate.CheckActionRequest{Entity: entityCredential, Action: action}); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func toJSON(c Credential, now time.Time, status Status) credentialJSON {
+	return credentialJSON{
+		WorkspaceID:   c.WorkspaceID,
+		Provider:      c.Provider,
+		Name:          c.Name,
+		Kind:          c.Kind,
+		Fingerprint:   c.Fingerprint,
+		KeyID:         c.KeyID,
+		RotatedAt:     millis(c.RotatedAt),
+		LastSeenAt:    millis(c.LastSeenAt),
+		ExpiresAt:     millis(c.ExpiresAt),
+		AgeDays:       int(c.Age(now) / day),
+		RotateAfter:   int(c.RotateAfter / day),
+		RotationDueAt: millis(c.RotationDueAt()),
+		Status:        status,
+		BackedUp:      c.BackupKeyID != "",
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
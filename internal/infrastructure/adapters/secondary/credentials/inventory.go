@@ -0,0 +1,488 @@
+// Package credentials keeps the credential inventory: which provider
+// credentials each environment and workspace has configured, how old they
+// are, when they expire where that is known, and which are due for
+// rotation.
+//
+// A Scan reads the credentials the adapters use from the environment (see
+// DefaultSources) and records a fingerprint of each, never the secret
+// itself. A credential's age counts from the scan that first saw its
+// current fingerprint, or from the modification time of its key file, so
+// rotating a key resets it. Credentials held elsewhere, such as a
+// workspace's own provider token, are recorded through Observe.
+//
+// With a backup key configured, every recorded credential also gets an
+// encrypted copy (AES-256-GCM, see Sealer) so an environment can be
+// rebuilt after losing its secret store; Backup exports the copies and
+// OpenBundle decrypts them offline.
+package credentials
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// DefaultTable is the table holding the inventory (see the postgres
+// integration migration 000011_credential_inventory).
+const DefaultTable = "credential_inventory"
+
+// DefaultExpiryWarning is how long before its expiry a credential is
+// reported as expiring.
+const DefaultExpiryWarning = 14 * 24 * time.Hour
+
+// listLimit bounds the rows one inventory read returns.
+const listLimit = 1000
+
+// Status is where a credential stands in its lifecycle.
+type Status string
+
+const (
+	StatusOK          Status = "ok"
+	StatusRotationDue Status = "rotation_due"
+	StatusExpiring    Status = "expiring"
+	StatusExpired     Status = "expired"
+)
+
+// Observation is a credential seen in use.
+type Observation struct {
+	// WorkspaceID scopes a workspace's own credential; empty for the
+	// platform's.
+	WorkspaceID string
+	Provider    string
+	Name        string
+	Kind        Kind
+	// Secret is fingerprinted and, with a backup key, sealed; it is not
+	// stored in the clear.
+	Secret []byte
+	// KeyID is the public identifier paired with the secret, if any.
+	KeyID string
+	// IssuedAt dates the credential when known; otherwise its age counts
+	// from the first observation of its current value.
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// RotateAfter is the rotation period; DefaultRotateAfter when zero.
+	RotateAfter time.Duration
+}
+
+// Credential is an inventory entry.
+type Credential struct {
+	ID          string
+	Environment string
+	WorkspaceID string
+	Provider    string
+	Name        string
+	Kind        Kind
+	// Fingerprint is a truncated SHA-256 of the secret, to tell values
+	// apart without revealing them.
+	Fingerprint string
+	KeyID       string
+	RotatedAt   time.Time
+	LastSeenAt  time.Time
+	// ExpiresAt is zero when the expiry is unknown.
+	ExpiresAt   time.Time
+	RotateAfter time.Duration
+	// BackupKeyID names the key of the encrypted copy; empty without one.
+	BackupKeyID string
+
+	sealed string
+}
+
+// Age is how long the credential has had its current value.
+func (c Credential) Age(now time.Time) time.Duration {
+	return now.Sub(c.RotatedAt)
+}
+
+// RotationDueAt is when the credential should be rotated by.
+func (c Credential) RotationDueAt() time.Time {
+	due := c.RotatedAt.Add(c.RotateAfter)
+	if !c.ExpiresAt.IsZero() && c.ExpiresAt.Before(due) {
+		return c.ExpiresAt
+	}
+	return due
+}
+
+// Status reports the credential's state at now, warning of expiry within
+// the warning window.
+func (c Credential) Status(now time.Time, warning time.Duration) Status {
+	switch {
+	case !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt):
+		return StatusExpired
+	case !c.ExpiresAt.IsZero() && now.Add(warning).After(c.ExpiresAt):
+		return StatusExpiring
+	case c.RotateAfter > 0 && c.Age(now) >= c.RotateAfter:
+		return StatusRotationDue
+	}
+	return StatusOK
+}
+
+// Config tunes an Inventory.
+type Config struct {
+	// Table holds the inventory; DefaultTable when empty.
+	Table string
+	// Environment labels the credentials this process sees, e.g.
+	// "production".
+	Environment string
+	// Sources are the environment credentials a scan reads;
+	// DefaultSources when nil.
+	Sources []Source
+	// Sealer encrypts backup copies; without one nothing is backed up.
+	Sealer *Sealer
+	// Expiries declares expiries the providers do not publish, by
+	// credential name (a Calendly PAT's, say).
+	Expiries map[string]time.Time
+	// RotateAfter overrides rotation periods by credential name; the "*"
+	// entry applies to every credential without its own.
+	RotateAfter map[string]time.Duration
+	// ExpiryWarning is the window in which an expiring credential is
+	// reported; DefaultExpiryWarning when zero.
+	ExpiryWarning time.Duration
+}
+
+// Inventory records credentials in a table through the generic database
+// operations.
+type Inventory struct {
+	ops    interfaces.DatabaseOperation
+	config Config
+	getenv func(string) string
+	now    func() time.Time
+}
+
+// NewInventory creates an inventory over ops.
+func NewInventory(ops interfaces.DatabaseOperation, config Config, getenv func(string) string) *Inventory {
+	if config.Table == "" {
+		config.Table = DefaultTable
+	}
+	if config.Environment == "" {
+		config.Environment = "default"
+	}
+	if config.Sources == nil {
+		config.Sources = DefaultSources
+	}
+	if config.ExpiryWarning <= 0 {
+		config.ExpiryWarning = DefaultExpiryWarning
+	}
+	return &Inventory{ops: ops, config: config, getenv: getenv, now: time.Now}
+}
+
+// Environment is the label of the credentials this inventory scans.
+func (inv *Inventory) Environment() string {
+	return inv.config.Environment
+}
+
+// ScanResult reports a scan.
+type ScanResult struct {
+	// Recorded counts the credentials found and recorded.
+	Recorded int `json:"recorded"`
+	// Rotated lists the credentials whose value changed since the last
+	// scan.
+	Rotated []string `json:"rotated,omitempty"`
+	// Errors lists the credentials that are configured but unreadable,
+	// such as a key file that is missing.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Scan records every configured source.
+func (inv *Inventory) Scan(ctx context.Context) (*ScanResult, error) {
+	result := &ScanResult{}
+	for _, source := range inv.config.Sources {
+		o, ok, err := source.read(inv.getenv)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		if !ok {
+			continue
+		}
+		rotated, err := inv.observe(ctx, o)
+		if err != nil {
+			return result, err
+		}
+		result.Recorded++
+		if rotated {
+			result.Rotated = append(result.Rotated, o.Name)
+		}
+	}
+	return result, nil
+}
+
+// Observe records a credential seen in use.
+func (inv *Inventory) Observe(ctx context.Context, o Observation) error {
+	if o.Name == "" || len(o.Secret) == 0 {
+		return fmt.Errorf("credential observation needs a name and a secret")
+	}
+	_, err := inv.observe(ctx, o)
+	return err
+}
+
+// observe upserts the inventory row of o and reports whether its value
+// changed since it was last recorded.
+func (inv *Inventory) observe(ctx context.Context, o Observation) (bool, error) {
+	now := inv.now()
+	sum := sha256.Sum256(o.Secret)
+	fingerprint := hex.EncodeToString(sum[:8])
+	if expires, ok := inv.config.Expiries[o.Name]; ok && o.ExpiresAt.IsZero() {
+		o.ExpiresAt = expires
+	}
+	rotateAfter := o.RotateAfter
+	if d, ok := inv.config.RotateAfter[o.Name]; ok {
+		rotateAfter = d
+	} else if d, ok := inv.config.RotateAfter["*"]; ok && rotateAfter == 0 {
+		rotateAfter = d
+	}
+	if rotateAfter <= 0 {
+		rotateAfter = DefaultRotateAfter
+	}
+
+	existing, err := inv.find(ctx, o.WorkspaceID, o.Name)
+	if err != nil {
+		return false, err
+	}
+	row := map[string]any{
+		"environment":        inv.config.Environment,
+		"workspace_id":       o.WorkspaceID,
+		"provider":           o.Provider,
+		"name":               o.Name,
+		"kind":               string(o.Kind),
+		"fingerprint":        fingerprint,
+		"key_id":             o.KeyID,
+		"last_seen_at":       now.UnixMilli(),
+		"expires_at":         millis(o.ExpiresAt),
+		"rotate_after_hours": int64(rotateAfter / time.Hour),
+	}
+	changed := existing == nil || existing.Fingerprint != fingerprint
+	if changed {
+		issued := o.IssuedAt
+		if issued.IsZero() || issued.After(now) || existing != nil {
+			// A new value of a known credential dates from now, whatever
+			// its file says; a copied key file keeps its old mtime.
+			issued = now
+		}
+		row["rotated_at"] = issued.UnixMilli()
+	}
+	if sealer := inv.config.Sealer; sealer != nil && (changed || existing.BackupKeyID != sealer.KeyID()) {
+		sealed, err := sealer.Seal(o.Secret, sealAAD(inv.config.Environment, o.WorkspaceID, o.Name))
+		if err != nil {
+			return false, fmt.Errorf("seal %s: %w", o.Name, err)
+		}
+		row["backup"] = sealed
+		row["backup_key_id"] = sealer.KeyID()
+	} else if changed {
+		// An old backup does not hold the new value.
+		row["backup"] = ""
+		row["backup_key_id"] = ""
+	}
+
+	if existing == nil {
+		if _, err := inv.ops.Create(ctx, inv.config.Table, row); err != nil {
+			return false, fmt.Errorf("record credential %s: %w", o.Name, err)
+		}
+		return false, nil
+	}
+	if _, err := inv.ops.Update(ctx, inv.config.Table, existing.ID, row); err != nil {
+		return false, fmt.Errorf("update credential %s: %w", o.Name, err)
+	}
+	if changed {
+		log.Printf("🔑 Credential %s changed; its age restarts", o.Name)
+	}
+	return changed, nil
+}
+
+// find returns the inventory entry of a credential, or nil.
+func (inv *Inventory) find(ctx context.Context, workspaceID, name string) (*Credential, error) {
+	result, err := inv.ops.List(ctx, inv.config.Table, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{
+			stringEquals("environment", inv.config.Environment),
+			stringEquals("workspace_id", workspaceID),
+			stringEquals("name", name),
+		}},
+		Pagination: firstPage(1),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find credential %s: %w", name, err)
+	}
+	for _, row := range result.Data {
+		// Guard against providers that ignore the filter.
+		if c := fromRow(row); c.Environment == inv.config.Environment && c.WorkspaceID == workspaceID && c.Name == name {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+// List returns the platform's credentials in this environment and, when
+// workspaceID is set, that workspace's, ordered by provider and name.
+func (inv *Inventory) List(ctx context.Context, workspaceID string) ([]Credential, error) {
+	result, err := inv.ops.List(ctx, inv.config.Table, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{
+			stringEquals("environment", inv.config.Environment),
+		}},
+		Pagination: firstPage(listLimit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list credentials: %w", err)
+	}
+	credentials := []Credential{}
+	for _, row := range result.Data {
+		c := fromRow(row)
+		if c.Environment != inv.config.Environment || (c.WorkspaceID != "" && c.WorkspaceID != workspaceID) {
+			continue
+		}
+		credentials = append(credentials, c)
+	}
+	sort.Slice(credentials, func(i, j int) bool {
+		a, b := credentials[i], credentials[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.WorkspaceID < b.WorkspaceID
+	})
+	return credentials, nil
+}
+
+// RotationReport lists the credentials that need attention.
+type RotationReport struct {
+	Environment string
+	GeneratedAt time.Time
+	// Due holds the expired, expiring and rotation-due credentials, most
+	// urgent first.
+	Due []Credential
+}
+
+// RotationDue reports the credentials of List that are expired, expire
+// within the warning window, or are past their rotation period.
+func (inv *Inventory) RotationDue(ctx context.Context, workspaceID string) (*RotationReport, error) {
+	credentials, err := inv.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	now := inv.now()
+	report := &RotationReport{Environment: inv.config.Environment, GeneratedAt: now}
+	for _, c := range credentials {
+		if c.Status(now, inv.config.ExpiryWarning) != StatusOK {
+			report.Due = append(report.Due, c)
+		}
+	}
+	sort.SliceStable(report.Due, func(i, j int) bool {
+		return report.Due[i].RotationDueAt().Before(report.Due[j].RotationDueAt())
+	})
+	return report, nil
+}
+
+// Status reports c's state under this inventory's warning window.
+func (inv *Inventory) Status(c Credential) Status {
+	return c.Status(inv.now(), inv.config.ExpiryWarning)
+}
+
+// Run scans at start and then every interval until ctx is done, logging a
+// rotation reminder for every platform credential that needs attention.
+func (inv *Inventory) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		inv.scanAndRemind(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (inv *Inventory) scanAndRemind(ctx context.Context) {
+	result, err := inv.Scan(ctx)
+	if err != nil {
+		log.Printf("credentials: scan: %v", err)
+		return
+	}
+	for _, e := range result.Errors {
+		log.Printf("⚠️  credentials: %s", e)
+	}
+	report, err := inv.RotationDue(ctx, "")
+	if err != nil {
+		log.Printf("credentials: rotation report: %v", err)
+		return
+	}
+	for _, c := range report.Due {
+		switch inv.Status(c) {
+		case StatusExpired:
+			log.Printf("🔑 %s credential %s (%s) expired on %s", c.Provider, c.Name, inv.config.Environment, c.ExpiresAt.Format("2006-01-02"))
+		case StatusExpiring:
+			log.Printf("🔑 %s credential %s (%s) expires on %s; rotate it", c.Provider, c.Name, inv.config.Environment, c.ExpiresAt.Format("2006-01-02"))
+		default:
+			log.Printf("🔑 %s credential %s (%s) is %d days old; rotation was due %s", c.Provider, c.Name, inv.config.Environment,
+				int(c.Age(report.GeneratedAt)/(24*time.Hour)), c.RotationDueAt().Format("2006-01-02"))
+		}
+	}
+}
+
+func fromRow(row map[string]any) Credential {
+	return Credential{
+		ID:          str(row["id"]),
+		Environment: str(row["environment"]),
+		WorkspaceID: str(row["workspace_id"]),
+		Provider:    str(row["provider"]),
+		Name:        str(row["name"]),
+		Kind:        Kind(str(row["kind"])),
+		Fingerprint: str(row["fingerprint"]),
+		KeyID:       str(row["key_id"]),
+		RotatedAt:   fromMillis(row["rotated_at"]),
+		LastSeenAt:  fromMillis(row["last_seen_at"]),
+		ExpiresAt:   fromMillis(row["expires_at"]),
+		RotateAfter: time.Duration(operations.Number(row["rotate_after_hours"])) * time.Hour,
+		BackupKeyID: str(row["backup_key_id"]),
+		sealed:      str(row["backup"]),
+	}
+}
+
+func millis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromMillis(v any) time.Time {
+	if ms := int64(operations.Number(v)); ms > 0 {
+		return time.UnixMilli(ms).UTC()
+	}
+	return time.Time{}
+}
+
+func firstPage(limit int32) *commonpb.PaginationRequest {
+	return &commonpb.PaginationRequest{
+		Limit: limit,
+		Method: &commonpb.PaginationRequest_Offset{
+			Offset: &commonpb.OffsetPagination{Page: 1},
+		},
+	}
+}
+
+func stringEquals(field, value string) *commonpb.TypedFilter {
+	return &commonpb.TypedFilter{
+		Field: field,
+		FilterType: &commonpb.TypedFilter_StringFilter{
+			StringFilter: &commonpb.StringFilter{
+				Value:         value,
+				Operator:      commonpb.StringOperator_STRING_EQUALS,
+				CaseSensitive: true,
+			},
+		},
+	}
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
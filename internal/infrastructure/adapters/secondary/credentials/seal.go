@@ -0,0 +1,101 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// sealedPrefix marks the format of sealed values, so the format can change
+// without misreading older backups.
+const sealedPrefix = "v1:"
+
+// ErrWrongKey is returned when a sealed value was sealed under another key
+// or has been tampered with.
+var ErrWrongKey = errors.New("backup was sealed with a different key or is corrupt")
+
+// Sealer encrypts credential backups with AES-256-GCM. Each value is
+// bound to the credential it belongs to, so a sealed value copied onto
+// another credential's row does not open.
+type Sealer struct {
+	aead  cipher.AEAD
+	keyID string
+}
+
+// NewSealer creates a Sealer over a 32-byte key.
+func NewSealer(key []byte) (*Sealer, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("backup key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(key)
+	return &Sealer{aead: aead, keyID: hex.EncodeToString(sum[:4])}, nil
+}
+
+// ParseSealerKey creates a Sealer from a key written as base64 or as 64
+// hex digits (e.g. the output of openssl rand -base64 32).
+func ParseSealerKey(encoded string) (*Sealer, error) {
+	encoded = strings.TrimSpace(encoded)
+	if key, err := hex.DecodeString(encoded); err == nil && len(key) == 32 {
+		return NewSealer(key)
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(encoded); err == nil {
+			return NewSealer(key)
+		}
+	}
+	return nil, errors.New("backup key is neither base64 nor hex")
+}
+
+// KeyID identifies the key without revealing it, so backups record which
+// key opens them.
+func (s *Sealer) KeyID() string {
+	return s.keyID
+}
+
+// Seal encrypts plaintext for the credential named by aad.
+func (s *Sealer) Seal(plaintext, aad []byte) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, plaintext, aad)
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value sealed for the credential named by aad.
+func (s *Sealer) Open(sealed string, aad []byte) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(sealed, sealedPrefix)
+	if !ok {
+		return nil, errors.New("not a sealed backup value")
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) < s.aead.NonceSize() {
+		return nil, ErrWrongKey
+	}
+	nonce, ciphertext := raw[:s.aead.NonceSize()], raw[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plaintext, nil
+}
+
+// sealAAD names a credential for sealing: its environment, workspace and
+// name.
+func sealAAD(environment, workspaceID, name string) []byte {
+	return []byte(environment + "\x00" + workspaceID + "\x00" + name)
+}
@@ -0,0 +1,174 @@
+package credentials
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind classifies a credential.
+type Kind string
+
+const (
+	KindAPIKey            Kind = "api_key"
+	KindClientSecret      Kind = "client_secret"
+	KindAccessToken       Kind = "access_token"
+	KindRefreshToken      Kind = "refresh_token"
+	KindWebhookSecret     Kind = "webhook_secret"
+	KindServiceAccountKey Kind = "service_account_key"
+	KindPrivateKey        Kind = "private_key"
+	KindPassword          Kind = "password"
+	KindSigningSecret     Kind = "signing_secret"
+)
+
+// DefaultRotateAfter is the rotation period of a credential whose source
+// does not set one.
+const DefaultRotateAfter = 180 * 24 * time.Hour
+
+// maxKeyFileBytes bounds the key files a scan reads.
+const maxKeyFileBytes = 1 << 20
+
+// Source is a credential the platform reads from its environment.
+type Source struct {
+	// Provider is the integration using the credential, e.g. "paypal".
+	Provider string
+	// Name is the environment variable holding the credential, or the path
+	// of its key file when File is set.
+	Name string
+	Kind Kind
+	File bool
+	// KeyIDVar names a variable holding the public identifier paired with
+	// the secret (a client ID, an access key ID), shown in the inventory.
+	KeyIDVar string
+	// ExpiryVar names a variable holding the credential's expiry, for
+	// providers that publish one.
+	ExpiryVar string
+	// RotateAfter is the recommended rotation period; DefaultRotateAfter
+	// when zero.
+	RotateAfter time.Duration
+}
+
+const day = 24 * time.Hour
+
+// DefaultSources lists the provider credentials the adapters read.
+var DefaultSources = []Source{
+	{Provider: "paypal", Name: "LEAPFOR_INTEGRATION_PAYMENT_PAYPAL_CLIENT_SECRET", Kind: KindClientSecret, KeyIDVar: "LEAPFOR_INTEGRATION_PAYMENT_PAYPAL_CLIENT_ID", RotateAfter: 365 * day},
+	{Provider: "maya", Name: "LEAPFOR_INTEGRATION_PAYMENT_MAYA_SECRET_KEY", Kind: KindAPIKey, KeyIDVar: "LEAPFOR_INTEGRATION_PAYMENT_MAYA_PUBLIC_KEY", RotateAfter: 365 * day},
+	{Provider: "asiapay", Name: "LEAPFOR_INTEGRATION_PAYMENT_ASIAPAY_SECURE_SECRET", Kind: KindSigningSecret, RotateAfter: 365 * day},
+	{Provider: "calendly", Name: "CALENDLY_PERSONAL_ACCESS_TOKEN", Kind: KindAccessToken, RotateAfter: 180 * day},
+	{Provider: "calendly", Name: "CALENDLY_WEBHOOK_SECRET", Kind: KindWebhookSecret, RotateAfter: 365 * day},
+	{Provider: "microsoft", Name: "LEAPFOR_INTEGRATION_EMAIL_MICROSOFT_CLIENT_SECRET", Kind: KindClientSecret, KeyIDVar: "LEAPFOR_INTEGRATION_EMAIL_MICROSOFT_CLIENT_ID", RotateAfter: 180 * day},
+	{Provider: "microsoft", Name: "LEAPFOR_INTEGRATION_EMAIL_MICROSOFT_REFRESH_TOKEN", Kind: KindRefreshToken, RotateAfter: 90 * day},
+	{Provider: "microsoft", Name: "LEAPFOR_INTEGRATION_EMAIL_MICROSOFT_ACCESS_TOKEN", Kind: KindAccessToken, ExpiryVar: "LEAPFOR_INTEGRATION_EMAIL_MICROSOFT_TOKEN_EXPIRY"},
+	{Provider: "google", Name: "GOOGLE_APPLICATION_CREDENTIALS", Kind: KindServiceAccountKey, File: true, RotateAfter: 90 * day},
+	{Provider: "firestore", Name: "FIRESTORE_CREDENTIALS_PATH", Kind: KindServiceAccountKey, File: true, RotateAfter: 90 * day},
+	{Provider: "googlesheets", Name: "LEAPFOR_INTEGRATION_TABULAR_GOOGLESHEETS_SERVICE_ACCOUNT_KEY_PATH", Kind: KindServiceAccountKey, File: true, RotateAfter: 90 * day},
+	{Provider: "gmail", Name: "LEAPFOR_INTEGRATION_EMAIL_GMAIL_SERVICE_ACCOUNT_KEY_PATH", Kind: KindServiceAccountKey, File: true, RotateAfter: 90 * day},
+	{Provider: "notion", Name: "LEAPFOR_INTEGRATION_TABULAR_NOTION_API_KEY", Kind: KindAPIKey, RotateAfter: 365 * day},
+	{Provider: "s3", Name: "STORAGE_S3_SECRET_ACCESS_KEY", Kind: KindAPIKey, KeyIDVar: "STORAGE_S3_ACCESS_KEY_ID", RotateAfter: 90 * day},
+	{Provider: "sftp", Name: "LEAPFOR_INTEGRATION_INGESTION_SFTP_PASSWORD", Kind: KindPassword, RotateAfter: 90 * day},
+	{Provider: "sftp", Name: "LEAPFOR_INTEGRATION_INGESTION_SFTP_PRIVATE_KEY_FILE", Kind: KindPrivateKey, File: true, RotateAfter: 365 * day},
+	{Provider: "apns", Name: "APNS_PRIVATE_KEY", Kind: KindPrivateKey, KeyIDVar: "APNS_KEY_ID", RotateAfter: 365 * day},
+	{Provider: "apns", Name: "APNS_PRIVATE_KEY_PATH", Kind: KindPrivateKey, File: true, KeyIDVar: "APNS_KEY_ID", RotateAfter: 365 * day},
+	{Provider: "password_auth", Name: "PASSWORD_AUTH_RESET_TOKEN_SECRET", Kind: KindSigningSecret, RotateAfter: 365 * day},
+	{Provider: "api", Name: "X_API_KEY", Kind: KindAPIKey, RotateAfter: 180 * day},
+	{Provider: "scheduler", Name: "X_API_KEY_SCHEDULER", Kind: KindAPIKey, RotateAfter: 180 * day},
+}
+
+// read returns the observation of a source, or false when the source is
+// not configured.
+func (s Source) read(getenv func(string) string) (Observation, bool, error) {
+	value := strings.TrimSpace(getenv(s.Name))
+	if value == "" {
+		return Observation{}, false, nil
+	}
+	o := Observation{
+		Provider:    s.Provider,
+		Name:        s.Name,
+		Kind:        s.Kind,
+		Secret:      []byte(value),
+		RotateAfter: s.RotateAfter,
+	}
+	if s.KeyIDVar != "" {
+		o.KeyID = strings.TrimSpace(getenv(s.KeyIDVar))
+	}
+	if s.ExpiryVar != "" {
+		if raw := strings.TrimSpace(getenv(s.ExpiryVar)); raw != "" {
+			expires, err := ParseExpiry(raw)
+			if err != nil {
+				return Observation{}, false, fmt.Errorf("%s: %w", s.ExpiryVar, err)
+			}
+			o.ExpiresAt = expires
+		}
+	}
+	if s.File {
+		if err := o.readKeyFile(value); err != nil {
+			return Observation{}, false, fmt.Errorf("%s: %w", s.Name, err)
+		}
+	}
+	return o, true, nil
+}
+
+// readKeyFile replaces the observed path with the file's content and
+// dates the credential by the file. Service-account keys give their key
+// ID; certificates their expiry.
+func (o *Observation) readKeyFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Size() > maxKeyFileBytes {
+		return fmt.Errorf("%s is not a key file (%d bytes)", path, info.Size())
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	o.Secret = content
+	o.IssuedAt = info.ModTime()
+
+	var account struct {
+		PrivateKeyID string `json:"private_key_id"`
+		ClientEmail  string `json:"client_email"`
+	}
+	if json.Unmarshal(content, &account) == nil && account.PrivateKeyID != "" {
+		o.KeyID = account.PrivateKeyID
+		if account.ClientEmail != "" {
+			o.KeyID = account.ClientEmail + "/" + account.PrivateKeyID
+		}
+		return nil
+	}
+	for rest := content; ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			return nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			o.ExpiresAt = cert.NotAfter
+			return nil
+		}
+	}
+}
+
+// ParseExpiry reads an expiry written as an RFC 3339 time, a date
+// (YYYY-MM-DD, the start of that day in UTC) or Unix seconds.
+func ParseExpiry(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, nil
+	}
+	if secs, err := strconv.ParseInt(raw, 10, 64); err == nil && secs > 0 {
+		return time.Unix(secs, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("expiry %q is not an RFC 3339 time, a date or Unix seconds", raw)
+}
@@ -0,0 +1,104 @@
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrNoSalt is returned for an anonymizer without a salt: unsalted hashes
+// of emails or tax IDs can be reversed by hashing candidate values.
+var ErrNoSalt = errors.New("anonymization needs a salt of at least 16 bytes")
+
+// Anonymizer applies a Policy to rows.
+type Anonymizer struct {
+	policy *Policy
+	salt   []byte
+
+	mu    sync.Mutex
+	stats map[string]int
+}
+
+// New creates an anonymizer for policy. The salt keys every hash and fake:
+// keep it secret, and use the same salt across runs for fakes that stay
+// stable between refreshes of staging.
+func New(policy *Policy, salt []byte) (*Anonymizer, error) {
+	if len(salt) < 16 {
+		return nil, ErrNoSalt
+	}
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	return &Anonymizer{policy: policy, salt: append([]byte(nil), salt...), stats: map[string]int{}}, nil
+}
+
+// Row returns an anonymized copy of a row of table; row is not modified.
+func (a *Anonymizer) Row(table string, row map[string]any) map[string]any {
+	out := make(map[string]any, len(row))
+	for field, value := range row {
+		rule := a.policy.Rule(table, field)
+		if rule.Strategy == StrategyKeep {
+			out[field] = value
+			continue
+		}
+		out[field] = a.Value(rule, value)
+		a.mu.Lock()
+		a.stats[table+"."+field]++
+		a.mu.Unlock()
+	}
+	return out
+}
+
+// Value applies rule to one value. Hashing and faking only apply to text;
+// other values under those rules are dropped, as are all values under
+// nullify. Empty strings stay empty.
+func (a *Anonymizer) Value(rule Rule, value any) any {
+	switch rule.Strategy {
+	case StrategyKeep:
+		return value
+	case StrategyNullify:
+		return nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	if s == "" {
+		return s
+	}
+	if rule.Strategy == StrategyHash {
+		return "h_" + hex.EncodeToString(a.digest("hash", s)[:12])
+	}
+	return fakers[rule.Kind](a.digest("faker", s))
+}
+
+// digest keys the hash with the salt. The purpose keeps a hashed column
+// from revealing which fakes share an input.
+func (a *Anonymizer) digest(purpose, value string) []byte {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(purpose))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// FieldCount is how many values of one field were rewritten.
+type FieldCount struct {
+	Field string
+	Count int
+}
+
+// Stats reports the rewritten values per "table.field", sorted by field.
+func (a *Anonymizer) Stats() []FieldCount {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	counts := make([]FieldCount, 0, len(a.stats))
+	for field, n := range a.stats {
+		counts = append(counts, FieldCount{Field: field, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Field < counts[j].Field })
+	return counts
+}
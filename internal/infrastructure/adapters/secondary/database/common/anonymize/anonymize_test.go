@@ -0,0 +1,126 @@
+package anonymize
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var testSalt = []byte("0123456789abcdef-staging")
+
+func TestAnonymizer_Row(t *testing.T) {
+	a, err := New(DefaultPolicy(), testSalt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := map[string]any{
+		"id":            "client-1",
+		"workspace_id":  "ws-1",
+		"name":          "Maria Clara Santos",
+		"email":         "maria@real.example.ph",
+		"tax_id":        "123-456-789",
+		"notes":         "Prefers calls after 6pm at home",
+		"credit_limit":  int64(50000),
+		"mobile_number": "",
+	}
+	out := a.Row("client", row)
+
+	if out["id"] != "client-1" || out["workspace_id"] != "ws-1" || out["credit_limit"] != int64(50000) {
+		t.Errorf("kept fields changed: %v", out)
+	}
+	if out["notes"] != nil || out["mobile_number"] != "" {
+		t.Errorf("notes = %v, mobile_number = %q", out["notes"], out["mobile_number"])
+	}
+	if email, _ := out["email"].(string); !regexp.MustCompile(`^[a-z ]+\.[a-z ]+\.[0-9a-f]{6}@example\.com$`).MatchString(email) {
+		t.Errorf("email = %q", email)
+	}
+	if name, _ := out["name"].(string); name == row["name"] || len(strings.Fields(name)) < 2 {
+		t.Errorf("name = %q", name)
+	}
+	if tax, _ := out["tax_id"].(string); !strings.HasPrefix(tax, "h_") || len(tax) != 26 {
+		t.Errorf("tax_id = %q", tax)
+	}
+	if row["email"] != "maria@real.example.ph" {
+		t.Error("input row was modified")
+	}
+
+	// The same input gets the same output in any table and run, a
+	// different salt another one.
+	again, _ := New(DefaultPolicy(), testSalt)
+	if user := again.Row("user", map[string]any{"email_address": "maria@real.example.ph"}); user["email_address"] != out["email"] {
+		t.Errorf("email across tables: %v vs %v", user["email_address"], out["email"])
+	}
+	other, _ := New(DefaultPolicy(), []byte("another-salt-0123456789"))
+	if other.Row("client", row)["tax_id"] == out["tax_id"] {
+		t.Error("hash does not depend on the salt")
+	}
+
+	stats := a.Stats()
+	if len(stats) != 5 || stats[0].Field != "client.email" || stats[0].Count != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy([]byte(`
+fields:
+  notes: keep
+tables:
+  client:
+    name: faker:company
+    website: nullify
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct{ table, field, want string }{
+		{"client", "name", "faker:company"},
+		{"client", "website", "nullify"},
+		{"client", "notes", "keep"},
+		{"user", "email_address", "faker:email"},
+		{"location", "name", "keep"},
+	} {
+		if got := p.Rule(c.table, c.field).String(); got != c.want {
+			t.Errorf("%s.%s = %s, want %s", c.table, c.field, got, c.want)
+		}
+	}
+
+	bare, err := ParsePolicy([]byte(`{"builtins": false, "fields": {"email": "hash"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bare.Rule("user", "email_address").Strategy != StrategyKeep || bare.Rule("user", "email").Strategy != StrategyHash {
+		t.Errorf("builtins were merged: %+v", bare)
+	}
+
+	for _, raw := range []string{
+		`fields: {email: faker:shoe_size}`,
+		`fields: {email: scramble}`,
+		`fields: {email: hash:x}`,
+		`tables: {client: {id: hash}}`,
+	} {
+		if _, err := ParsePolicy([]byte(raw)); err == nil {
+			t.Errorf("%s: no error", raw)
+		}
+	}
+}
+
+func TestNew_RequiresSalt(t *testing.T) {
+	if _, err := New(nil, []byte("short")); !errors.Is(err, ErrNoSalt) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestFakers(t *testing.T) {
+	a, _ := New(nil, testSalt)
+	for _, kind := range FakerKinds() {
+		v, _ := a.Value(Rule{Strategy: StrategyFaker, Kind: kind}, "input").(string)
+		if v == "" || v == "input" {
+			t.Errorf("%s = %q", kind, v)
+		}
+	}
+	if v := a.Value(Rule{Strategy: StrategyFaker, Kind: "email"}, int64(7)); v != nil {
+		t.Errorf("non-text value faked as %v", v)
+	}
+}
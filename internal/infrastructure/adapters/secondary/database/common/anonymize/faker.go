@@ -0,0 +1,72 @@
+package anonymize
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// A faker turns the keyed hash of a value into a stand-in of its kind. The
+// digest is 32 bytes; fakers read it from the front and never need more.
+type faker func(digest []byte) string
+
+var fakers = map[string]faker{
+	"first_name": func(d []byte) string { return pick(firstNames, d[0:]) },
+	"last_name":  func(d []byte) string { return pick(lastNames, d[4:]) },
+	"name": func(d []byte) string {
+		return pick(firstNames, d[0:]) + " " + pick(lastNames, d[4:])
+	},
+	// Emails stay unique per input through the hash suffix, so unique
+	// indexes on them hold in the copy.
+	"email": func(d []byte) string {
+		return strings.ToLower(pick(firstNames, d[0:])+"."+pick(lastNames, d[4:])) + "." + hex.EncodeToString(d[8:11]) + "@example.com"
+	},
+	// Numbers in the 555 exchange are reserved for fiction.
+	"phone": func(d []byte) string {
+		return fmt.Sprintf("+1555%07d", binary.BigEndian.Uint32(d[8:])%10_000_000)
+	},
+	"address": func(d []byte) string {
+		return fmt.Sprintf("%d %s %s", 1+binary.BigEndian.Uint16(d[8:])%9999, pick(streets, d[12:]), pick(streetTypes, d[16:]))
+	},
+	"city":    func(d []byte) string { return pick(cities, d[8:]) },
+	"company": func(d []byte) string { return pick(lastNames, d[4:]) + " " + pick(companySuffixes, d[8:]) },
+	"username": func(d []byte) string {
+		return strings.ToLower(pick(firstNames, d[0:])) + hex.EncodeToString(d[8:10])
+	},
+	"text": func(d []byte) string { return "Redacted " + hex.EncodeToString(d[8:14]) },
+	// Dates fall between 1950 and 2005, for birth dates.
+	"date": func(d []byte) string {
+		n := binary.BigEndian.Uint32(d[8:])
+		return fmt.Sprintf("%04d-%02d-%02d", 1950+n%56, 1+(n/56)%12, 1+(n/672)%28)
+	},
+}
+
+func pick(list []string, d []byte) string {
+	return list[binary.BigEndian.Uint32(d)%uint32(len(list))]
+}
+
+var firstNames = []string{
+	"Alex", "Bea", "Carlo", "Dana", "Elena", "Felix", "Gina", "Hugo", "Iris", "Jon",
+	"Kara", "Leo", "Maya", "Nico", "Olga", "Paolo", "Quinn", "Rosa", "Sam", "Tess",
+	"Uma", "Vic", "Wren", "Xavi", "Yara", "Zed", "Aria", "Ben", "Cora", "Dino",
+}
+
+var lastNames = []string{
+	"Abad", "Bautista", "Cruz", "Diaz", "Evans", "Flores", "Garcia", "Hale", "Ibarra", "Jones",
+	"Kim", "Lopez", "Mendoza", "Novak", "Ortiz", "Perez", "Quinto", "Reyes", "Santos", "Torres",
+	"Uy", "Valdez", "Walsh", "Xu", "Yap", "Zamora", "Aquino", "Brooks", "Castro", "Dela Cruz",
+}
+
+var streets = []string{
+	"Acacia", "Banyan", "Cedar", "Dahlia", "Elm", "Fern", "Ginger", "Hazel", "Ivy", "Juniper",
+	"Kamagong", "Laurel", "Magnolia", "Narra", "Orchid", "Pine", "Rosewood", "Sampaguita", "Tamarind", "Willow",
+}
+
+var streetTypes = []string{"Street", "Avenue", "Road", "Lane", "Drive", "Way"}
+
+var cities = []string{
+	"Springfield", "Riverton", "Lakeside", "Fairview", "Greenville", "Hillcrest", "Maplewood", "Oakridge",
+}
+
+var companySuffixes = []string{"Holdings", "Trading", "Services", "Group", "Ventures", "Partners", "Labs", "Co."}
@@ -0,0 +1,223 @@
+// Package anonymize rewrites the personal data in database rows so a copy
+// of production can be loaded into staging: real names, emails, phone
+// numbers and tax IDs are replaced before the rows leave the process that
+// read them.
+//
+// A Policy says, per table and field, which strategy applies:
+//
+//	hash          a keyed hash of the value (HMAC-SHA256 with the salt):
+//	              equal inputs stay equal, so lookups and joins on the
+//	              column still work, but the value cannot be read back
+//	faker:<kind>  a realistic stand-in of the kind (see FakerKinds), chosen
+//	              deterministically from the keyed hash, so the same person
+//	              gets the same fake name in every table and every run
+//	nullify       the value is dropped
+//	keep          the value is copied as is
+//
+// Fields without a rule are kept. Row IDs and workspace IDs are never
+// rewritten, so references between copied rows stay intact.
+package anonymize
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Strategy is how a field is anonymized.
+type Strategy string
+
+const (
+	StrategyKeep    Strategy = "keep"
+	StrategyHash    Strategy = "hash"
+	StrategyFaker   Strategy = "faker"
+	StrategyNullify Strategy = "nullify"
+)
+
+// protected are the fields no rule may rewrite.
+var protected = map[string]bool{"id": true, "workspace_id": true}
+
+// Rule is the treatment of one field, written "hash", "nullify", "keep" or
+// "faker:<kind>".
+type Rule struct {
+	Strategy Strategy
+	// Kind is the faker kind; empty for the other strategies.
+	Kind string
+}
+
+// ParseRule reads a rule in its written form.
+func ParseRule(raw string) (Rule, error) {
+	raw = strings.TrimSpace(raw)
+	name, kind, _ := strings.Cut(raw, ":")
+	switch Strategy(name) {
+	case StrategyKeep, StrategyHash, StrategyNullify:
+		if kind != "" {
+			return Rule{}, fmt.Errorf("rule %q: only faker takes a kind", raw)
+		}
+		return Rule{Strategy: Strategy(name)}, nil
+	case StrategyFaker:
+		if _, ok := fakers[kind]; !ok {
+			return Rule{}, fmt.Errorf("rule %q: unknown faker kind (want one of %s)", raw, strings.Join(FakerKinds(), ", "))
+		}
+		return Rule{Strategy: StrategyFaker, Kind: kind}, nil
+	}
+	return Rule{}, fmt.Errorf("unknown rule %q (want hash, faker:<kind>, nullify or keep)", raw)
+}
+
+// String returns the written form of r.
+func (r Rule) String() string {
+	if r.Strategy == StrategyFaker {
+		return string(r.Strategy) + ":" + r.Kind
+	}
+	return string(r.Strategy)
+}
+
+// UnmarshalJSON reads the written form.
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("a rule is a string such as \"hash\" or \"faker:email\"")
+	}
+	parsed, err := ParseRule(raw)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// MarshalJSON writes the written form.
+func (r Rule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// Policy is the declarative anonymization of a dataset, as JSON or the
+// equivalent YAML:
+//
+//	builtins: true         # start from DefaultPolicy (the default)
+//	fields:                # any table
+//	  notes: nullify
+//	tables:
+//	  client:
+//	    name: faker:company
+//	    tax_id: hash
+//	  payment_dispute:
+//	    evidence: nullify
+//
+// A table rule wins over a field rule, which wins over the built-in one.
+type Policy struct {
+	// Builtins merges DefaultPolicy under the rules of the file; nil is
+	// true.
+	Builtins *bool                      `json:"builtins,omitempty"`
+	Fields   map[string]Rule            `json:"fields,omitempty"`
+	Tables   map[string]map[string]Rule `json:"tables,omitempty"`
+}
+
+// DefaultPolicy covers the personal data in the standard schema: user and
+// client contact details, tax identifiers, credentials and free-text
+// notes.
+func DefaultPolicy() *Policy {
+	faker := func(kind string) Rule { return Rule{Strategy: StrategyFaker, Kind: kind} }
+	hash := Rule{Strategy: StrategyHash}
+	nullify := Rule{Strategy: StrategyNullify}
+	return &Policy{
+		Fields: map[string]Rule{
+			"first_name":           faker("first_name"),
+			"last_name":            faker("last_name"),
+			"email":                faker("email"),
+			"email_address":        faker("email"),
+			"attendee_email":       faker("email"),
+			"attendee_name":        faker("name"),
+			"mobile_number":        faker("phone"),
+			"phone":                faker("phone"),
+			"phone_number":         faker("phone"),
+			"street_address":       faker("address"),
+			"tax_id":               hash,
+			"registration_number":  hash,
+			"password_hash":        nullify,
+			"password_reset_token": nullify,
+			"token":                nullify,
+			"secret":               nullify,
+			"notes":                nullify,
+		},
+		Tables: map[string]map[string]Rule{
+			// A client's name is a person's as often as a company's.
+			"client": {"name": faker("name")},
+		},
+	}
+}
+
+// ParsePolicy reads a policy file, JSON or YAML, merged over DefaultPolicy
+// unless it sets builtins to false.
+func ParsePolicy(data []byte) (*Policy, error) {
+	raw, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("anonymization policy: %w", err)
+	}
+	var file Policy
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("anonymization policy: %w", err)
+	}
+	for field := range file.Fields {
+		if protected[field] {
+			return nil, fmt.Errorf("anonymization policy: %s cannot be rewritten", field)
+		}
+	}
+	for table, fields := range file.Tables {
+		for field := range fields {
+			if protected[field] {
+				return nil, fmt.Errorf("anonymization policy: %s.%s cannot be rewritten", table, field)
+			}
+		}
+	}
+	if file.Builtins != nil && !*file.Builtins {
+		return &file, nil
+	}
+	return DefaultPolicy().merge(&file), nil
+}
+
+// merge returns p overlaid with the rules of over.
+func (p *Policy) merge(over *Policy) *Policy {
+	out := &Policy{Builtins: over.Builtins, Fields: map[string]Rule{}, Tables: map[string]map[string]Rule{}}
+	for _, src := range []*Policy{p, over} {
+		for field, rule := range src.Fields {
+			out.Fields[field] = rule
+		}
+		for table, fields := range src.Tables {
+			if out.Tables[table] == nil {
+				out.Tables[table] = map[string]Rule{}
+			}
+			for field, rule := range fields {
+				out.Tables[table][field] = rule
+			}
+		}
+	}
+	return out
+}
+
+// Rule returns the rule of table's field; fields without one are kept.
+func (p *Policy) Rule(table, field string) Rule {
+	if protected[field] {
+		return Rule{Strategy: StrategyKeep}
+	}
+	if rule, ok := p.Tables[table][field]; ok {
+		return rule
+	}
+	if rule, ok := p.Fields[field]; ok {
+		return rule
+	}
+	return Rule{Strategy: StrategyKeep}
+}
+
+// FakerKinds lists the faker kinds, sorted.
+func FakerKinds() []string {
+	kinds := make([]string, 0, len(fakers))
+	for kind := range fakers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
@@ -0,0 +1,344 @@
+// Package cache provides a DatabaseOperation decorator that keeps Read,
+// List and Count results in a ports.Cache (Redis in production) and drops
+// them when the table is written through the same decorator.
+//
+// Invalidation is generational: every table has a counter in the cache,
+// result keys embed its current value, and any Create/Update/Delete bumps
+// it so earlier entries are never read again and simply expire. This works
+// across server instances without tracking which keys a write affected.
+//
+// Only writes made through DatabaseOperation invalidate. Repositories that
+// also write with raw SQL should leave their tables out of the cache
+// policy, which is why caching is per table (see registry.CachePolicy).
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+// KeyPrefix namespaces every key the decorator writes.
+const KeyPrefix = "espyna:db:"
+
+// writeHold is how long a table stays uncacheable after a write made inside
+// a transaction. The generation bump happens before the commit, so without
+// the hold a concurrent read could cache the pre-commit row under the new
+// generation.
+const writeHold = time.Minute
+
+// CachedOperations wraps a DatabaseOperation with read-through caching of
+// Read, List and Count. Query, QueryOne, everything inside a transaction
+// and primary reads (operations.WithPrimaryRead) go straight to the inner
+// operations.
+//
+// Results are stored as JSON, so a cached row comes back with JSON types
+// (timestamps as strings, numbers as float64). Repositories that round-trip
+// rows through protojson — the common path — see no difference.
+//
+// Place the decorator beneath any workspace scoping: List keys include the
+// filters the scoping layer injects, and Read ownership checks still run on
+// cached rows.
+type CachedOperations struct {
+	inner interfaces.DatabaseOperation
+
+	// cache and policy are fixed when set; otherwise the registry's default
+	// cache is read on every call.
+	cache  ports.Cache
+	policy *registry.CachePolicy
+}
+
+// Ensure CachedOperations satisfies the full DatabaseOperation interface at
+// compile time.
+var _ interfaces.DatabaseOperation = (*CachedOperations)(nil)
+
+// NewCachedOperations wraps inner with the cache published through
+// registry.SetDefaultCache. Until one is published (or when caching is not
+// configured) every call passes straight through.
+func NewCachedOperations(inner interfaces.DatabaseOperation) interfaces.DatabaseOperation {
+	return &CachedOperations{inner: inner}
+}
+
+// NewCachedOperationsWithCache wraps inner with a specific cache and policy.
+func NewCachedOperationsWithCache(inner interfaces.DatabaseOperation, cache ports.Cache, policy registry.CachePolicy) interfaces.DatabaseOperation {
+	return &CachedOperations{inner: inner, cache: cache, policy: &policy}
+}
+
+// Inner returns the wrapped operations.
+func (c *CachedOperations) Inner() interfaces.DatabaseOperation {
+	return c.inner
+}
+
+// ── Reads ────────────────────────────────────────────────────────────────────
+
+// Read serves a cached row when one exists for the table's current
+// generation, otherwise reads through and caches the row.
+func (c *CachedOperations) Read(ctx context.Context, tableName string, id string) (map[string]any, error) {
+	cache, ttl := c.active(ctx, tableName)
+	if cache == nil {
+		return c.inner.Read(ctx, tableName, id)
+	}
+	gen, ok := c.generation(ctx, cache, tableName)
+	if !ok {
+		return c.inner.Read(ctx, tableName, id)
+	}
+	key := resultKey(tableName, gen, "read", id)
+
+	var row map[string]any
+	if c.lookup(ctx, cache, key, &row) {
+		return row, nil
+	}
+	row, err := c.inner.Read(ctx, tableName, id)
+	if err != nil || row == nil {
+		return row, err
+	}
+	c.store(ctx, cache, tableName, key, row, ttl)
+	return row, nil
+}
+
+// List serves a cached page when one exists for the same parameters and the
+// table's current generation, otherwise lists through and caches the page.
+func (c *CachedOperations) List(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	cache, ttl := c.active(ctx, tableName)
+	if cache == nil {
+		return c.inner.List(ctx, tableName, params)
+	}
+	gen, ok := c.generation(ctx, cache, tableName)
+	if !ok {
+		return c.inner.List(ctx, tableName, params)
+	}
+	digest, ok := listDigest(params)
+	if !ok {
+		return c.inner.List(ctx, tableName, params)
+	}
+	key := resultKey(tableName, gen, "list", digest)
+
+	var result interfaces.ListResult
+	if c.lookup(ctx, cache, key, &result) {
+		return &result, nil
+	}
+	fresh, err := c.inner.List(ctx, tableName, params)
+	if err != nil || fresh == nil {
+		return fresh, err
+	}
+	c.store(ctx, cache, tableName, key, fresh, ttl)
+	return fresh, nil
+}
+
+// Count serves a cached count when one exists for the same parameters and
+// the table's current generation, otherwise counts through and caches it.
+func (c *CachedOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (int64, error) {
+	cache, ttl := c.active(ctx, tableName)
+	if cache == nil {
+		return c.inner.Count(ctx, tableName, params)
+	}
+	gen, ok := c.generation(ctx, cache, tableName)
+	if !ok {
+		return c.inner.Count(ctx, tableName, params)
+	}
+	digest, ok := listDigest(params)
+	if !ok {
+		return c.inner.Count(ctx, tableName, params)
+	}
+	key := resultKey(tableName, gen, "count", digest)
+
+	var count int64
+	if c.lookup(ctx, cache, key, &count) {
+		return count, nil
+	}
+	count, err := c.inner.Count(ctx, tableName, params)
+	if err != nil {
+		return 0, err
+	}
+	c.store(ctx, cache, tableName, key, count, ttl)
+	return count, nil
+}
+
+// ListDeleted passes through; deleted records are listed too rarely to
+// cache.
+func (c *CachedOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return c.inner.ListDeleted(ctx, tableName, params)
+}
+
+// Query passes through; arbitrary query builders are not cached.
+func (c *CachedOperations) Query(ctx context.Context, tableName string, query interfaces.QueryBuilder) ([]map[string]any, error) {
+	return c.inner.Query(ctx, tableName, query)
+}
+
+// QueryOne passes through; arbitrary query builders are not cached.
+func (c *CachedOperations) QueryOne(ctx context.Context, tableName string, query interfaces.QueryBuilder) (map[string]any, error) {
+	return c.inner.QueryOne(ctx, tableName, query)
+}
+
+// ── Writes ───────────────────────────────────────────────────────────────────
+
+// Create writes through and invalidates the table.
+func (c *CachedOperations) Create(ctx context.Context, tableName string, data map[string]any) (map[string]any, error) {
+	defer c.invalidate(ctx, tableName)
+	return c.inner.Create(ctx, tableName, data)
+}
+
+// Update writes through and invalidates the table.
+func (c *CachedOperations) Update(ctx context.Context, tableName string, id string, data map[string]any) (map[string]any, error) {
+	defer c.invalidate(ctx, tableName)
+	return c.inner.Update(ctx, tableName, id, data)
+}
+
+// Delete writes through and invalidates the table.
+func (c *CachedOperations) Delete(ctx context.Context, tableName string, id string) error {
+	defer c.invalidate(ctx, tableName)
+	return c.inner.Delete(ctx, tableName, id)
+}
+
+// HardDelete writes through and invalidates the table.
+func (c *CachedOperations) HardDelete(ctx context.Context, tableName string, id string) error {
+	defer c.invalidate(ctx, tableName)
+	return c.inner.HardDelete(ctx, tableName, id)
+}
+
+// Restore writes through and invalidates the table.
+func (c *CachedOperations) Restore(ctx context.Context, tableName string, id string) error {
+	defer c.invalidate(ctx, tableName)
+	return c.inner.Restore(ctx, tableName, id)
+}
+
+// CreateMany writes through and invalidates the table.
+func (c *CachedOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	defer c.invalidate(ctx, tableName)
+	return c.inner.CreateMany(ctx, tableName, data)
+}
+
+// UpdateMany writes through and invalidates the table.
+func (c *CachedOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	defer c.invalidate(ctx, tableName)
+	return c.inner.UpdateMany(ctx, tableName, updates)
+}
+
+// DeleteMany writes through and invalidates the table.
+func (c *CachedOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	defer c.invalidate(ctx, tableName)
+	return c.inner.DeleteMany(ctx, tableName, ids)
+}
+
+// ── Helpers ──────────────────────────────────────────────────────────────────
+
+// current returns the cache and policy in effect for this call.
+func (c *CachedOperations) current() (ports.Cache, registry.CachePolicy) {
+	if c.cache != nil {
+		return c.cache, *c.policy
+	}
+	return registry.GetDefaultCache()
+}
+
+// active returns the cache and TTL for reading tableName, or a nil cache
+// when the read must go to the database: no cache, an uncached table, or a
+// transaction in progress.
+func (c *CachedOperations) active(ctx context.Context, tableName string) (ports.Cache, time.Duration) {
+	cache, policy := c.current()
+	if cache == nil {
+		return nil, 0
+	}
+	ttl := policy.TTL(tableName)
+	if ttl <= 0 || operations.IsTransactionContext(ctx) || operations.IsPrimaryRead(ctx) {
+		return nil, 0
+	}
+	return cache, ttl
+}
+
+// generation returns the table's current generation. ok is false when the
+// cache cannot be reached.
+func (c *CachedOperations) generation(ctx context.Context, cache ports.Cache, tableName string) (string, bool) {
+	value, found, err := cache.Get(ctx, generationKey(tableName))
+	if err != nil {
+		log.Printf("⚠️ db cache: generation of %s unavailable: %v", tableName, err)
+		return "", false
+	}
+	if !found {
+		return "0", true
+	}
+	return string(value), true
+}
+
+// lookup decodes the entry under key into dest, reporting whether it was a
+// usable hit.
+func (c *CachedOperations) lookup(ctx context.Context, cache ports.Cache, key string, dest any) bool {
+	value, found, err := cache.Get(ctx, key)
+	if err != nil || !found {
+		return false
+	}
+	return json.Unmarshal(value, dest) == nil
+}
+
+// store caches value under key unless the table is held after a
+// transactional write.
+func (c *CachedOperations) store(ctx context.Context, cache ports.Cache, tableName, key string, value any, ttl time.Duration) {
+	if _, held, err := cache.Get(ctx, holdKey(tableName)); err != nil || held {
+		return
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if err := cache.Set(ctx, key, encoded, ttl); err != nil {
+		log.Printf("⚠️ db cache: store in %s failed: %v", tableName, err)
+	}
+}
+
+// invalidate bumps the table's generation. Writes inside a transaction also
+// hold the table (see writeHold). Tables outside the policy are skipped.
+func (c *CachedOperations) invalidate(ctx context.Context, tableName string) {
+	cache, policy := c.current()
+	if cache == nil || policy.TTL(tableName) <= 0 {
+		return
+	}
+	if operations.IsTransactionContext(ctx) {
+		if err := cache.Set(ctx, holdKey(tableName), []byte("1"), writeHold); err != nil {
+			log.Printf("⚠️ db cache: hold of %s failed: %v", tableName, err)
+		}
+	}
+	if _, err := cache.Increment(ctx, generationKey(tableName)); err != nil {
+		// Stale entries now live until their TTL runs out.
+		log.Printf("⚠️ db cache: invalidation of %s failed: %v", tableName, err)
+	}
+}
+
+func generationKey(tableName string) string {
+	return KeyPrefix + tableName + ":gen"
+}
+
+func holdKey(tableName string) string {
+	return KeyPrefix + tableName + ":hold"
+}
+
+func resultKey(tableName, gen, kind, suffix string) string {
+	return KeyPrefix + tableName + ":" + gen + ":" + kind + ":" + suffix
+}
+
+// listDigest hashes the list parameters. ok is false when they cannot be
+// encoded, in which case the list is not cached.
+func listDigest(params *interfaces.ListParams) (string, bool) {
+	h := sha256.New()
+	if params != nil {
+		opts := proto.MarshalOptions{Deterministic: true}
+		for _, part := range []proto.Message{params.Search, params.Filters, params.Sort, params.Pagination} {
+			encoded, err := opts.Marshal(part)
+			if err != nil {
+				return "", false
+			}
+			h.Write([]byte(strconv.Itoa(len(encoded)) + ":"))
+			h.Write(encoded)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/cache/mock"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// countingOps serves fixed rows and counts the reads reaching it.
+type countingOps struct {
+	interfaces.DatabaseOperation
+	name  string
+	reads int
+	lists int
+}
+
+func (o *countingOps) Read(_ context.Context, _ string, id string) (map[string]any, error) {
+	o.reads++
+	return map[string]any{"id": id, "name": o.name}, nil
+}
+
+func (o *countingOps) List(_ context.Context, _ string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	o.lists++
+	return &interfaces.ListResult{
+		Data:       []map[string]any{{"id": "c1", "name": o.name}},
+		Pagination: &commonpb.PaginationResponse{TotalItems: 1},
+		Total:      1,
+	}, nil
+}
+
+func (o *countingOps) Update(_ context.Context, _ string, id string, data map[string]any) (map[string]any, error) {
+	o.name = data["name"].(string)
+	return map[string]any{"id": id, "name": o.name}, nil
+}
+
+// pendingTx is a Transaction that stays pending.
+type pendingTx struct{ interfaces.Transaction }
+
+func (pendingTx) State() interfaces.TransactionState { return interfaces.TransactionStatePending }
+
+func TestCachedOperations_ReadThroughAndInvalidate(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingOps{name: "Ana"}
+	store := mock.NewCache()
+	ops := NewCachedOperationsWithCache(inner, store, registry.CachePolicy{
+		TableTTLs: map[string]time.Duration{"client": time.Minute},
+	})
+
+	for i := 0; i < 3; i++ {
+		row, err := ops.Read(ctx, "client", "c1")
+		if err != nil || row["name"] != "Ana" {
+			t.Fatalf("read %d = %v, %v", i, row, err)
+		}
+	}
+	if inner.reads != 1 {
+		t.Errorf("inner reads = %d, want 1", inner.reads)
+	}
+	ops.Read(operations.WithPrimaryRead(ctx), "client", "c1")
+	if inner.reads != 2 {
+		t.Errorf("a primary read was served from the cache")
+	}
+
+	page := &interfaces.ListParams{Pagination: &commonpb.PaginationRequest{Limit: 10}}
+	ops.List(ctx, "client", page)
+	result, _ := ops.List(ctx, "client", page)
+	if inner.lists != 1 || result.Total != 1 || result.Pagination.GetTotalItems() != 1 {
+		t.Errorf("lists = %d, result = %+v", inner.lists, result)
+	}
+	ops.List(ctx, "client", &interfaces.ListParams{Pagination: &commonpb.PaginationRequest{Limit: 20}})
+	if inner.lists != 2 {
+		t.Errorf("a different page was served from the cache")
+	}
+
+	if _, err := ops.Update(ctx, "client", "c1", map[string]any{"name": "Ben"}); err != nil {
+		t.Fatal(err)
+	}
+	if row, _ := ops.Read(ctx, "client", "c1"); row["name"] != "Ben" || inner.reads != 3 {
+		t.Errorf("read after update = %v (inner reads %d), want a fresh row", row, inner.reads)
+	}
+	if result, _ := ops.List(ctx, "client", page); result.Data[0]["name"] != "Ben" {
+		t.Errorf("list after update = %v, want a fresh page", result.Data)
+	}
+}
+
+func TestCachedOperations_Policy(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := mock.NewCache()
+	store.SetClock(func() time.Time { return now })
+	inner := &countingOps{name: "Ana"}
+	ops := NewCachedOperationsWithCache(inner, store, registry.CachePolicy{
+		DefaultTTL: time.Minute,
+		TableTTLs:  map[string]time.Duration{"client": 5 * time.Second, "journal_entry": 0},
+	})
+
+	ops.Read(ctx, "client", "c1")
+	ops.Read(ctx, "client", "c1")
+	now = now.Add(6 * time.Second)
+	ops.Read(ctx, "client", "c1")
+	if inner.reads != 2 {
+		t.Errorf("client reads = %d, want the per-table TTL to expire the row", inner.reads)
+	}
+
+	inner.reads = 0
+	ops.Read(ctx, "product", "p1")
+	ops.Read(ctx, "product", "p1")
+	ops.Read(ctx, "journal_entry", "j1")
+	ops.Read(ctx, "journal_entry", "j1")
+	if inner.reads != 3 {
+		t.Errorf("reads = %d, want product cached by the default TTL and journal_entry never", inner.reads)
+	}
+
+	// Inside a transaction reads go to the database, and a write holds the
+	// table so nothing is cached until the transaction is long over.
+	inner.reads = 0
+	txCtx := operations.WithTransaction(ctx, pendingTx{})
+	ops.Read(txCtx, "product", "p2")
+	ops.Update(txCtx, "product", "p2", map[string]any{"name": "Ben"})
+	ops.Read(ctx, "product", "p2")
+	ops.Read(ctx, "product", "p2")
+	if inner.reads != 3 {
+		t.Errorf("reads = %d, want every read to reach the database", inner.reads)
+	}
+	now = now.Add(writeHold)
+	ops.Read(ctx, "product", "p2")
+	ops.Read(ctx, "product", "p2")
+	if inner.reads != 4 {
+		t.Errorf("reads after the hold = %d, want caching back on", inner.reads)
+	}
+}
+
+func TestCachedOperations_DefaultCache(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingOps{name: "Ana"}
+	ops := NewCachedOperations(inner)
+
+	ops.Read(ctx, "client", "c1")
+	ops.Read(ctx, "client", "c1")
+	if inner.reads != 2 {
+		t.Fatalf("reads = %d, want pass-through without a published cache", inner.reads)
+	}
+
+	registry.SetDefaultCache(mock.NewCache(), registry.CachePolicy{DefaultTTL: time.Minute})
+	defer registry.SetDefaultCache(nil, registry.CachePolicy{})
+	ops.Read(ctx, "client", "c1")
+	ops.Read(ctx, "client", "c1")
+	if inner.reads != 3 {
+		t.Errorf("reads = %d, want the published cache used", inner.reads)
+	}
+}
@@ -0,0 +1,384 @@
+// Package history provides a DatabaseOperation decorator that records a
+// revision of a record after every write to the tables named in the history
+// policy, and the reads that list those revisions and return a record as it
+// stood at a given revision or time.
+//
+// A revision holds the full record after the write (the snapshot) and the
+// fields that write changed, with their old and new values. Revisions live
+// in one table (registry.DefaultRevisionTable unless the policy names
+// another), numbered from 1 per record. They are written through the
+// wrapped operations, so a write inside a transaction records its revision
+// in the same transaction.
+//
+// Recording is best effort: a revision that cannot be written is logged
+// and the write it describes still succeeds. Updates that change nothing
+// but the modification time record nothing, and HardDelete records nothing
+// either; the revisions of a purged record are kept.
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+// Actions recorded on a revision.
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+)
+
+// recordAttempts bounds the tries at writing a revision; a concurrent write
+// to the same record can take the revision number first.
+const recordAttempts = 3
+
+// ignoredFields change on every write and are left out of Changes.
+var ignoredFields = map[string]bool{
+	"date_modified":        true,
+	"date_modified_string": true,
+}
+
+// HistoryOperations wraps a DatabaseOperation and records a revision after
+// each successful Create, Update, Delete and Restore, single or batched, of
+// a recorded table. Reads pass through.
+//
+// Place the decorator beneath any workspace scoping, like the cache: the
+// revision table is written directly, and its rows carry the workspace_id
+// of the record they describe.
+type HistoryOperations struct {
+	inner interfaces.DatabaseOperation
+
+	// policy is fixed when set; otherwise the registry's default history is
+	// read on every call.
+	policy *registry.HistoryPolicy
+}
+
+// Ensure HistoryOperations satisfies the full DatabaseOperation interface
+// at compile time.
+var _ interfaces.DatabaseOperation = (*HistoryOperations)(nil)
+
+// NewHistoryOperations wraps inner with the policy published through
+// registry.SetDefaultHistory. Until one is published every call passes
+// straight through.
+func NewHistoryOperations(inner interfaces.DatabaseOperation) *HistoryOperations {
+	return &HistoryOperations{inner: inner}
+}
+
+// NewHistoryOperationsWithPolicy wraps inner with a specific policy.
+func NewHistoryOperationsWithPolicy(inner interfaces.DatabaseOperation, policy registry.HistoryPolicy) *HistoryOperations {
+	return &HistoryOperations{inner: inner, policy: &policy}
+}
+
+// Inner returns the wrapped operations.
+func (h *HistoryOperations) Inner() interfaces.DatabaseOperation {
+	return h.inner
+}
+
+// ── Reads ────────────────────────────────────────────────────────────────────
+
+// Read passes through.
+func (h *HistoryOperations) Read(ctx context.Context, tableName string, id string) (map[string]any, error) {
+	return h.inner.Read(ctx, tableName, id)
+}
+
+// List passes through.
+func (h *HistoryOperations) List(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return h.inner.List(ctx, tableName, params)
+}
+
+// ListDeleted passes through.
+func (h *HistoryOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return h.inner.ListDeleted(ctx, tableName, params)
+}
+
+// Count passes through.
+func (h *HistoryOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (int64, error) {
+	return h.inner.Count(ctx, tableName, params)
+}
+
+// Query passes through.
+func (h *HistoryOperations) Query(ctx context.Context, tableName string, query interfaces.QueryBuilder) ([]map[string]any, error) {
+	return h.inner.Query(ctx, tableName, query)
+}
+
+// QueryOne passes through.
+func (h *HistoryOperations) QueryOne(ctx context.Context, tableName string, query interfaces.QueryBuilder) (map[string]any, error) {
+	return h.inner.QueryOne(ctx, tableName, query)
+}
+
+// ── Writes ───────────────────────────────────────────────────────────────────
+
+// Create creates the record and records its first revision.
+func (h *HistoryOperations) Create(ctx context.Context, tableName string, data map[string]any) (map[string]any, error) {
+	row, err := h.inner.Create(ctx, tableName, data)
+	if err != nil {
+		return row, err
+	}
+	if policy, ok := h.records(tableName); ok {
+		h.record(ctx, policy, tableName, ActionCreate, nil, row)
+	}
+	return row, nil
+}
+
+// Update updates the record and records the fields it changed. Some
+// providers return only the updated fields, so the snapshot is the record
+// read before the update with the result laid over it.
+func (h *HistoryOperations) Update(ctx context.Context, tableName string, id string, data map[string]any) (map[string]any, error) {
+	policy, ok := h.records(tableName)
+	if !ok {
+		return h.inner.Update(ctx, tableName, id, data)
+	}
+	before := h.before(ctx, tableName, id)
+	row, err := h.inner.Update(ctx, tableName, id, data)
+	if err != nil {
+		return row, err
+	}
+	h.record(ctx, policy, tableName, ActionUpdate, before, merge(before, row))
+	return row, nil
+}
+
+// Delete soft-deletes the record and records it as inactive.
+func (h *HistoryOperations) Delete(ctx context.Context, tableName string, id string) error {
+	return h.setActive(ctx, tableName, id, ActionDelete, h.inner.Delete)
+}
+
+// Restore restores the record and records it as active.
+func (h *HistoryOperations) Restore(ctx context.Context, tableName string, id string) error {
+	return h.setActive(ctx, tableName, id, ActionRestore, h.inner.Restore)
+}
+
+// HardDelete passes through; the record's revisions are kept.
+func (h *HistoryOperations) HardDelete(ctx context.Context, tableName string, id string) error {
+	return h.inner.HardDelete(ctx, tableName, id)
+}
+
+// CreateMany creates the records and records each one's first revision.
+func (h *HistoryOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	rows, err := h.inner.CreateMany(ctx, tableName, data)
+	if err != nil {
+		return rows, err
+	}
+	if policy, ok := h.records(tableName); ok {
+		for _, row := range rows {
+			h.record(ctx, policy, tableName, ActionCreate, nil, row)
+		}
+	}
+	return rows, nil
+}
+
+// UpdateMany updates the records and records the fields each update
+// changed.
+func (h *HistoryOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	policy, ok := h.records(tableName)
+	if !ok {
+		return h.inner.UpdateMany(ctx, tableName, updates)
+	}
+	before := make(map[string]map[string]any, len(updates))
+	for _, update := range updates {
+		before[update.ID] = h.before(ctx, tableName, update.ID)
+	}
+	rows, err := h.inner.UpdateMany(ctx, tableName, updates)
+	if err != nil {
+		return rows, err
+	}
+	for _, row := range rows {
+		id, _ := row["id"].(string)
+		h.record(ctx, policy, tableName, ActionUpdate, before[id], merge(before[id], row))
+	}
+	return rows, nil
+}
+
+// DeleteMany soft-deletes the records and records each one as inactive.
+func (h *HistoryOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	policy, ok := h.records(tableName)
+	if !ok {
+		return h.inner.DeleteMany(ctx, tableName, ids)
+	}
+	before := make([]map[string]any, len(ids))
+	for i, id := range ids {
+		before[i] = h.before(ctx, tableName, id)
+	}
+	if err := h.inner.DeleteMany(ctx, tableName, ids); err != nil {
+		return err
+	}
+	for i, id := range ids {
+		h.record(ctx, policy, tableName, ActionDelete, before[i], withActive(before[i], id, false))
+	}
+	return nil
+}
+
+// setActive runs a soft delete or restore and records the record with its
+// new active flag.
+func (h *HistoryOperations) setActive(ctx context.Context, tableName, id, action string, write func(context.Context, string, string) error) error {
+	policy, ok := h.records(tableName)
+	if !ok {
+		return write(ctx, tableName, id)
+	}
+	before := h.before(ctx, tableName, id)
+	if err := write(ctx, tableName, id); err != nil {
+		return err
+	}
+	h.record(ctx, policy, tableName, action, before, withActive(before, id, action == ActionRestore))
+	return nil
+}
+
+// ── Recording ────────────────────────────────────────────────────────────────
+
+// records returns the policy when writes to tableName are recorded.
+func (h *HistoryOperations) records(tableName string) (registry.HistoryPolicy, bool) {
+	policy := registry.GetDefaultHistory()
+	if h.policy != nil {
+		policy = *h.policy
+	}
+	return policy, policy.Records(tableName)
+}
+
+// before reads the record ahead of a write, past any result cache. A
+// record that cannot be read is treated as unknown.
+func (h *HistoryOperations) before(ctx context.Context, tableName, id string) map[string]any {
+	row, err := h.inner.Read(operations.WithPrimaryRead(ctx), tableName, id)
+	if err != nil {
+		return nil
+	}
+	return normalize(row)
+}
+
+// record writes the revision describing a write that took the record from
+// before to after, numbering it after the record's latest revision.
+func (h *HistoryOperations) record(ctx context.Context, policy registry.HistoryPolicy, tableName, action string, before, after map[string]any) {
+	snapshot := normalize(after)
+	id, _ := snapshot["id"].(string)
+	if id == "" {
+		return
+	}
+	changes := Diff(before, snapshot)
+	if action == ActionUpdate && before != nil && len(changes) == 0 {
+		return
+	}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		log.Printf("⚠️ history: %s %s: encode snapshot: %v", tableName, id, err)
+		return
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		log.Printf("⚠️ history: %s %s: encode changes: %v", tableName, id, err)
+		return
+	}
+	workspaceID, _ := snapshot["workspace_id"].(string)
+	if workspaceID == "" {
+		workspaceID = contextutil.ExtractWorkspaceIDFromContext(ctx)
+	}
+
+	revisionTable := policy.Table()
+	for attempt := 1; ; attempt++ {
+		revision, err := latestRevision(ctx, h.inner, revisionTable, tableName, id)
+		if err == nil {
+			revision++
+			_, err = h.inner.Create(ctx, revisionTable, map[string]any{
+				"id":           revisionID(tableName, id, revision),
+				"workspace_id": workspaceID,
+				"entity_type":  tableName,
+				"entity_id":    id,
+				"revision":     revision,
+				"action":       action,
+				"snapshot":     string(snapshotJSON),
+				"changes":      string(changesJSON),
+				"changed_by":   contextutil.ExtractUserIDFromContext(ctx),
+			})
+		}
+		if err == nil {
+			return
+		}
+		if attempt == recordAttempts {
+			log.Printf("⚠️ history: %s %s %s: record revision: %v", action, tableName, id, err)
+			return
+		}
+	}
+}
+
+// revisionID keys a revision by record and number, so two writers taking
+// the same number collide instead of both succeeding.
+func revisionID(tableName, id string, revision int64) string {
+	return fmt.Sprintf("%s:%s:%d", tableName, id, revision)
+}
+
+// Diff returns the fields whose values differ between two snapshots,
+// leaving out the modification time. A nil before reports every field of
+// after as changed from nothing.
+func Diff(before, after map[string]any) map[string]Change {
+	changes := map[string]Change{}
+	for field, to := range after {
+		if ignoredFields[field] {
+			continue
+		}
+		if from, ok := before[field]; !ok || !reflect.DeepEqual(from, to) {
+			changes[field] = Change{From: before[field], To: to}
+		}
+	}
+	for field, from := range before {
+		if _, ok := after[field]; !ok && !ignoredFields[field] {
+			changes[field] = Change{From: from}
+		}
+	}
+	return changes
+}
+
+// ChangedFields returns the sorted names of the changed fields.
+func ChangedFields(changes map[string]Change) []string {
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// merge lays the fields of row over before.
+func merge(before, row map[string]any) map[string]any {
+	merged := make(map[string]any, len(before)+len(row))
+	for field, value := range before {
+		merged[field] = value
+	}
+	for field, value := range normalize(row) {
+		merged[field] = value
+	}
+	return merged
+}
+
+// withActive copies before with the active flag set.
+func withActive(before map[string]any, id string, active bool) map[string]any {
+	after := map[string]any{"id": id}
+	for field, value := range before {
+		after[field] = value
+	}
+	after[interfaces.ActiveField] = active
+	return after
+}
+
+// normalize copies row through JSON, so snapshots and their comparisons
+// hold the same types whatever Go types a provider returns.
+func normalize(row map[string]any) map[string]any {
+	if row == nil {
+		return nil
+	}
+	raw, err := json.Marshal(row)
+	if err != nil {
+		return map[string]any{"id": row["id"]}
+	}
+	var copied map[string]any
+	if err := json.Unmarshal(raw, &copied); err != nil {
+		return map[string]any{"id": row["id"]}
+	}
+	return copied
+}
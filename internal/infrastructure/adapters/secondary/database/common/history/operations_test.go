@@ -0,0 +1,234 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+var start = time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+// memOps keeps rows per table. Every write advances the clock a minute and
+// Update returns only the fields written, like Firestore.
+type memOps struct {
+	interfaces.DatabaseOperation
+	tables map[string]map[string]map[string]any
+	clock  time.Time
+	// beforeCreate, when set, runs ahead of each revision write.
+	beforeCreate func()
+}
+
+func newOps() *memOps {
+	return &memOps{tables: map[string]map[string]map[string]any{}, clock: start}
+}
+
+func (o *memOps) tick() int64 {
+	o.clock = o.clock.Add(time.Minute)
+	return o.clock.UnixMilli()
+}
+
+func (o *memOps) Create(_ context.Context, table string, data map[string]any) (map[string]any, error) {
+	if hook := o.beforeCreate; hook != nil && table == registry.DefaultRevisionTable {
+		o.beforeCreate = nil
+		hook()
+		o.beforeCreate = hook
+	}
+	if o.tables[table] == nil {
+		o.tables[table] = map[string]map[string]any{}
+	}
+	id := data["id"].(string)
+	if _, ok := o.tables[table][id]; ok {
+		return nil, errors.New("duplicate id")
+	}
+	row := map[string]any{"active": true, "date_created": o.tick()}
+	for k, v := range data {
+		row[k] = v
+	}
+	o.tables[table][id] = row
+	return row, nil
+}
+
+func (o *memOps) Read(_ context.Context, table string, id string) (map[string]any, error) {
+	row, ok := o.tables[table][id]
+	if !ok {
+		return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return row, nil
+}
+
+func (o *memOps) Update(_ context.Context, table string, id string, data map[string]any) (map[string]any, error) {
+	row, ok := o.tables[table][id]
+	if !ok {
+		return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	result := map[string]any{"id": id, "date_modified": o.tick()}
+	for k, v := range data {
+		row[k], result[k] = v, v
+	}
+	row["date_modified"] = result["date_modified"]
+	return result, nil
+}
+
+func (o *memOps) Delete(ctx context.Context, table string, id string) error {
+	_, err := o.Update(ctx, table, id, map[string]any{"active": false})
+	return err
+}
+
+func (o *memOps) Restore(ctx context.Context, table string, id string) error {
+	_, err := o.Update(ctx, table, id, map[string]any{"active": true})
+	return err
+}
+
+// Query honours == conditions, one order by and the limit.
+func (o *memOps) Query(_ context.Context, table string, query interfaces.QueryBuilder) ([]map[string]any, error) {
+	filter, err := query.Build()
+	if err != nil {
+		return nil, err
+	}
+	var rows []map[string]any
+	for _, row := range o.tables[table] {
+		match := true
+		for _, c := range filter.Conditions {
+			if fmt.Sprint(row[c.Field]) != fmt.Sprint(c.Value) {
+				match = false
+			}
+		}
+		if match {
+			rows = append(rows, row)
+		}
+	}
+	for _, order := range filter.OrderBy {
+		sort.Slice(rows, func(i, j int) bool {
+			a, _ := toInt64(rows[i][order.Field])
+			b, _ := toInt64(rows[j][order.Field])
+			return (a < b) == order.Ascending
+		})
+	}
+	if filter.Limit > 0 && len(rows) > filter.Limit {
+		rows = rows[:filter.Limit]
+	}
+	return rows, nil
+}
+
+func newHistory(ops *memOps) *HistoryOperations {
+	return NewHistoryOperationsWithPolicy(ops, registry.HistoryPolicy{Tables: map[string]bool{"client": true}})
+}
+
+func TestHistory_RecordsWrites(t *testing.T) {
+	ops := newOps()
+	h := newHistory(ops)
+	ctx := contextutil.WithWorkspaceID(contextutil.WithUserID(context.Background(), "u-1"), "ws-1")
+
+	if _, err := h.Create(ctx, "client", map[string]any{"id": "c1", "workspace_id": "ws-1", "name": "Ada", "tier": "gold"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Update(ctx, "client", "c1", map[string]any{"name": "Ada L."}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Update(ctx, "client", "c1", map[string]any{"name": "Ada L."}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Delete(ctx, "client", "c1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Restore(ctx, "client", "c1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Create(ctx, "invoice", map[string]any{"id": "i1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	revisions, err := ListRevisions(ctx, ops, registry.DefaultRevisionTable, "client", "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actions []string
+	for _, r := range revisions {
+		actions = append(actions, fmt.Sprintf("%d:%s", r.Revision, r.Action))
+	}
+	if fmt.Sprint(actions) != "[4:restore 3:delete 2:update 1:create]" {
+		t.Fatalf("revisions = %v", actions)
+	}
+	if len(mustList(t, ops, "invoice", "i1")) != 0 {
+		t.Error("unrecorded table has revisions")
+	}
+
+	update := revisions[2]
+	if fields := ChangedFields(update.Changes); fmt.Sprint(fields) != "[name]" {
+		t.Errorf("update changed %v", fields)
+	}
+	if update.Changes["name"].From != "Ada" || update.Snapshot["tier"] != "gold" || update.Snapshot["name"] != "Ada L." {
+		t.Errorf("update revision = %+v", update)
+	}
+	if update.ChangedBy != "u-1" || update.WorkspaceID != "ws-1" {
+		t.Errorf("update by %q in %q", update.ChangedBy, update.WorkspaceID)
+	}
+	if revisions[1].Snapshot["active"] != false || revisions[0].Snapshot["active"] != true {
+		t.Errorf("delete/restore snapshots = %v / %v", revisions[1].Snapshot, revisions[0].Snapshot)
+	}
+}
+
+func TestHistory_PointInTime(t *testing.T) {
+	ops := newOps()
+	h := newHistory(ops)
+	ctx := context.Background()
+	_, _ = h.Create(ctx, "client", map[string]any{"id": "c1", "name": "v1"})
+	_, _ = h.Update(ctx, "client", "c1", map[string]any{"name": "v2"})
+	afterV2 := ops.clock
+	_, _ = h.Update(ctx, "client", "c1", map[string]any{"name": "v3"})
+
+	r, err := ReadRevision(ctx, ops, registry.DefaultRevisionTable, "client", "c1", 2)
+	if err != nil || r.Snapshot["name"] != "v2" {
+		t.Fatalf("revision 2 = %+v, %v", r, err)
+	}
+	r, err = ReadAt(ctx, ops, registry.DefaultRevisionTable, "client", "c1", afterV2.Add(30*time.Second))
+	if err != nil || r.Snapshot["name"] != "v2" {
+		t.Fatalf("at = %+v, %v", r, err)
+	}
+	var dbErr *model.DatabaseError
+	if _, err := ReadAt(ctx, ops, registry.DefaultRevisionTable, "client", "c1", start); !errors.As(err, &dbErr) || dbErr.HTTPStatus != 404 {
+		t.Errorf("before creation: %v", err)
+	}
+	if _, err := ReadRevision(ctx, ops, registry.DefaultRevisionTable, "client", "c1", 9); !errors.As(err, &dbErr) || dbErr.HTTPStatus != 404 {
+		t.Errorf("missing revision: %v", err)
+	}
+}
+
+func TestHistory_RetriesTakenRevision(t *testing.T) {
+	ops := newOps()
+	h := newHistory(ops)
+	ctx := context.Background()
+	_, _ = h.Create(ctx, "client", map[string]any{"id": "c1", "name": "v1"})
+	// A concurrent writer takes revision 2 between the number being read
+	// and the revision being written.
+	taken := false
+	ops.beforeCreate = func() {
+		if !taken {
+			taken = true
+			_, _ = ops.Create(ctx, registry.DefaultRevisionTable, map[string]any{
+				"id": revisionID("client", "c1", 2), "entity_type": "client", "entity_id": "c1", "revision": int64(2),
+			})
+		}
+	}
+	_, _ = h.Update(ctx, "client", "c1", map[string]any{"name": "v2"})
+	if _, ok := ops.tables[registry.DefaultRevisionTable][revisionID("client", "c1", 3)]; !ok {
+		t.Error("update did not take revision 3")
+	}
+}
+
+func mustList(t *testing.T, ops *memOps, table, id string) []Revision {
+	t.Helper()
+	revisions, err := ListRevisions(context.Background(), ops, registry.DefaultRevisionTable, table, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return revisions
+}
@@ -0,0 +1,189 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+)
+
+// MaxRevisions bounds the revisions ListRevisions returns, newest first.
+const MaxRevisions = 1000
+
+// Change is a field's value before and after a write. From is nil for a
+// field the write added, To for one it removed.
+type Change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Revision is a record as one write left it.
+type Revision struct {
+	EntityType  string            `json:"entity_type"`
+	EntityID    string            `json:"entity_id"`
+	WorkspaceID string            `json:"workspace_id,omitempty"`
+	Revision    int64             `json:"revision"`
+	Action      string            `json:"action"`
+	Snapshot    map[string]any    `json:"snapshot"`
+	Changes     map[string]Change `json:"changes"`
+	ChangedBy   string            `json:"changed_by,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// ListRevisions returns the revisions of a record of tableName stored in
+// revisionTable, newest first.
+func ListRevisions(ctx context.Context, ops interfaces.DatabaseOperation, revisionTable, tableName, id string) ([]Revision, error) {
+	rows, err := ops.Query(ctx, revisionTable, interfaces.NewQueryBuilder().
+		WhereEqualTo("entity_type", tableName).
+		WhereEqualTo("entity_id", id).
+		OrderBy("revision", false).
+		Limit(MaxRevisions))
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]Revision, 0, len(rows))
+	for _, row := range rows {
+		revision, err := parseRevision(row)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// ReadRevision returns a record's revision by number, or a not-found
+// database error.
+func ReadRevision(ctx context.Context, ops interfaces.DatabaseOperation, revisionTable, tableName, id string, revision int64) (*Revision, error) {
+	rows, err := ops.Query(ctx, revisionTable, interfaces.NewQueryBuilder().
+		WhereEqualTo("entity_type", tableName).
+		WhereEqualTo("entity_id", id).
+		WhereEqualTo("revision", revision).
+		Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, notFound(id, fmt.Sprintf("revision %d", revision))
+	}
+	parsed, err := parseRevision(rows[0])
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// ReadAt returns the revision a record was at, at time at: the latest one
+// recorded no later. It returns a not-found database error when the record
+// did not exist yet, or has no revisions.
+func ReadAt(ctx context.Context, ops interfaces.DatabaseOperation, revisionTable, tableName, id string, at time.Time) (*Revision, error) {
+	revisions, err := ListRevisions(ctx, ops, revisionTable, tableName, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, revision := range revisions {
+		if !revision.CreatedAt.After(at) {
+			return &revision, nil
+		}
+	}
+	return nil, notFound(id, "a revision at "+at.UTC().Format(time.RFC3339))
+}
+
+// latestRevision returns the number of a record's latest revision, 0 when
+// it has none.
+func latestRevision(ctx context.Context, ops interfaces.DatabaseOperation, revisionTable, tableName, id string) (int64, error) {
+	rows, err := ops.Query(ctx, revisionTable, interfaces.NewQueryBuilder().
+		WhereEqualTo("entity_type", tableName).
+		WhereEqualTo("entity_id", id).
+		OrderBy("revision", false).
+		Limit(1))
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+	n, _ := toInt64(rows[0]["revision"])
+	return n, nil
+}
+
+// parseRevision decodes a row of the revision table.
+func parseRevision(row map[string]any) (Revision, error) {
+	revision := Revision{
+		EntityType:  stringValue(row["entity_type"]),
+		EntityID:    stringValue(row["entity_id"]),
+		WorkspaceID: stringValue(row["workspace_id"]),
+		Action:      stringValue(row["action"]),
+		ChangedBy:   stringValue(row["changed_by"]),
+	}
+	revision.Revision, _ = toInt64(row["revision"])
+	revision.CreatedAt, _ = toTime(row["date_created"])
+	if err := json.Unmarshal([]byte(stringValue(row["snapshot"])), &revision.Snapshot); err != nil {
+		return revision, fmt.Errorf("revision %d of %s %s: snapshot: %w", revision.Revision, revision.EntityType, revision.EntityID, err)
+	}
+	if raw := stringValue(row["changes"]); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &revision.Changes); err != nil {
+			return revision, fmt.Errorf("revision %d of %s %s: changes: %w", revision.Revision, revision.EntityType, revision.EntityID, err)
+		}
+	}
+	return revision, nil
+}
+
+func notFound(id, what string) error {
+	return model.NewDatabaseError(fmt.Sprintf("%s has no %s", id, what), "REVISION_NOT_FOUND", 404)
+}
+
+// stringValue reads a text column, which some scanners return as a
+// pointer or bytes.
+func stringValue(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case *string:
+		if s != nil {
+			return *s
+		}
+	case []byte:
+		return string(s)
+	}
+	return ""
+}
+
+func toInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case float64:
+		return int64(n), true
+	case string:
+		i, err := strconv.ParseInt(n, 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// toTime reads date_created, which backends store as unix milliseconds or
+// a timestamp.
+func toTime(v any) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, !t.IsZero()
+	case *time.Time:
+		if t != nil {
+			return *t, !t.IsZero()
+		}
+	case string:
+		if parsed, err := time.Parse(time.RFC3339Nano, t); err == nil {
+			return parsed, true
+		}
+	}
+	if ms, ok := toInt64(v); ok && ms > 0 {
+		return time.UnixMilli(ms), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,67 @@
+package interfaces
+
+import (
+	"context"
+	"fmt"
+)
+
+// BatchUpdate is one row of an UpdateMany call.
+type BatchUpdate struct {
+	ID   string
+	Data map[string]any
+}
+
+// CreateEach implements CreateMany as a loop over Create. Backends without a
+// multi-row insert path use it; callers wanting atomicity run it inside a
+// context transaction.
+func CreateEach(ctx context.Context, op DatabaseOperation, tableName string, data []map[string]any) ([]map[string]any, error) {
+	results := make([]map[string]any, 0, len(data))
+	for i, row := range data {
+		result, err := op.Create(ctx, tableName, row)
+		if err != nil {
+			return nil, fmt.Errorf("create %s[%d]: %w", tableName, i, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// UpdateEach implements UpdateMany as a loop over Update.
+func UpdateEach(ctx context.Context, op DatabaseOperation, tableName string, updates []BatchUpdate) ([]map[string]any, error) {
+	results := make([]map[string]any, 0, len(updates))
+	for _, u := range updates {
+		result, err := op.Update(ctx, tableName, u.ID, u.Data)
+		if err != nil {
+			return nil, fmt.Errorf("update %s %s: %w", tableName, u.ID, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// DeleteEach implements DeleteMany as a loop over Delete.
+func DeleteEach(ctx context.Context, op DatabaseOperation, tableName string, ids []string) error {
+	for _, id := range ids {
+		if err := op.Delete(ctx, tableName, id); err != nil {
+			return fmt.Errorf("delete %s %s: %w", tableName, id, err)
+		}
+	}
+	return nil
+}
+
+// TableRows is a set of rows destined for one table, used by CreateAtomic.
+type TableRows struct {
+	Table string
+	Rows  []map[string]any
+}
+
+// AtomicCreator is implemented by backends that can create rows across
+// several tables in a single all-or-nothing commit without a context
+// transaction. Firestore implements it with one batched write because its
+// operations do not join context transactions; SQL backends get the same
+// guarantee from the Transactor and do not need it.
+type AtomicCreator interface {
+	// CreateAtomic creates every row, stamped like Create, and commits them
+	// together. On error nothing is written. Results mirror writes.
+	CreateAtomic(ctx context.Context, writes []TableRows) ([]TableRows, error)
+}
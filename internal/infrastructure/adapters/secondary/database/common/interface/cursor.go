@@ -0,0 +1,240 @@
+package interfaces
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// Cursor (keyset) pagination
+//
+// A caller asks for cursor pages by setting the Cursor method on
+// ListParams.Pagination; an empty token requests the first page. Backends
+// that support it (Postgres, Firestore, mock) order by (date_created, id)
+// and resume strictly after the row the token points at, so pages stay
+// stable while rows are inserted and a deep page costs the same as the
+// first. The token for the next page is returned in ListResult.NextCursor
+// and the PaginationResponse's next_cursor, and is empty on the last page.
+//
+// Rows come newest first unless the request sorts by date_created
+// ascending. Any other sort cannot be followed by a keyset and is rejected.
+
+// CursorKeyField is the field cursor pages are ordered by, with id breaking
+// ties between rows created in the same instant.
+const CursorKeyField = "date_created"
+
+// Cursor is a decoded cursor token: the position of the last row of a page.
+type Cursor struct {
+	// Key is the row's CursorKeyField value, typed as the backend returned it
+	// (time.Time, int64, float64 or string) so it can be bound as a query
+	// parameter again.
+	Key any
+	ID  string
+}
+
+type cursorToken struct {
+	Kind string `json:"t"`
+	Key  string `json:"k"`
+	ID   string `json:"i"`
+}
+
+// CursorRequest reports whether params ask for cursor pagination and, if so,
+// the token to resume after (empty for the first page).
+func CursorRequest(params *ListParams) (token string, ok bool) {
+	if params == nil || params.Pagination == nil {
+		return "", false
+	}
+	cursor := params.Pagination.GetCursor()
+	if cursor == nil {
+		return "", false
+	}
+	return cursor.Token, true
+}
+
+// CursorDescending reports the direction of a cursor listing: descending
+// unless the only sort field is CursorKeyField ascending.
+func CursorDescending(params *ListParams) (bool, error) {
+	if params == nil || params.Sort == nil || len(params.Sort.Fields) == 0 {
+		return true, nil
+	}
+	fields := params.Sort.Fields
+	if len(fields) > 1 || fields[0].Field != CursorKeyField {
+		return false, fmt.Errorf("cursor pagination orders by %s; sort by another field with offset pagination", CursorKeyField)
+	}
+	return fields[0].Direction != commonpb.SortDirection_ASC, nil
+}
+
+// EncodeCursor returns the token that resumes after row.
+func EncodeCursor(row map[string]any) (string, error) {
+	id := fmt.Sprint(row["id"])
+	if row["id"] == nil || id == "" {
+		return "", fmt.Errorf("cursor row has no id")
+	}
+
+	token := cursorToken{ID: id}
+	switch key := row[CursorKeyField].(type) {
+	case time.Time:
+		token.Kind, token.Key = "time", key.UTC().Format(time.RFC3339Nano)
+	case int64:
+		token.Kind, token.Key = "int", fmt.Sprint(key)
+	case int:
+		token.Kind, token.Key = "int", fmt.Sprint(key)
+	case int32:
+		token.Kind, token.Key = "int", fmt.Sprint(key)
+	case float64:
+		token.Kind, token.Key = "float", fmt.Sprint(key)
+	case string:
+		token.Kind, token.Key = "string", key
+	case []byte:
+		token.Kind, token.Key = "string", string(key)
+	default:
+		return "", fmt.Errorf("cursor row has unsupported %s %T", CursorKeyField, key)
+	}
+
+	raw, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor parses a token produced by EncodeCursor.
+func DecodeCursor(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor token")
+	}
+	var t cursorToken
+	if err := json.Unmarshal(raw, &t); err != nil || t.ID == "" {
+		return Cursor{}, fmt.Errorf("invalid cursor token")
+	}
+
+	cursor := Cursor{ID: t.ID}
+	switch t.Kind {
+	case "time":
+		key, err := time.Parse(time.RFC3339Nano, t.Key)
+		if err != nil {
+			return Cursor{}, fmt.Errorf("invalid cursor token")
+		}
+		cursor.Key = key
+	case "int":
+		var key int64
+		if _, err := fmt.Sscan(t.Key, &key); err != nil {
+			return Cursor{}, fmt.Errorf("invalid cursor token")
+		}
+		cursor.Key = key
+	case "float":
+		var key float64
+		if _, err := fmt.Sscan(t.Key, &key); err != nil {
+			return Cursor{}, fmt.Errorf("invalid cursor token")
+		}
+		cursor.Key = key
+	case "string":
+		cursor.Key = t.Key
+	default:
+		return Cursor{}, fmt.Errorf("invalid cursor token")
+	}
+	return cursor, nil
+}
+
+// CursorPagination builds the pagination response for a cursor page. last
+// is the final row of the page (only its date_created and id are read) and
+// more reports whether rows follow it; backends fetch one extra row to find
+// out.
+func CursorPagination(last map[string]any, token string, more bool, total int32) (*commonpb.PaginationResponse, string, error) {
+	response := &commonpb.PaginationResponse{
+		TotalItems: total,
+		HasNext:    more,
+		HasPrev:    token != "",
+	}
+	if !more || last == nil {
+		return response, "", nil
+	}
+	next, err := EncodeCursor(last)
+	if err != nil {
+		return nil, "", err
+	}
+	response.NextCursor = &next
+	return response, next, nil
+}
+
+// PageByCursor returns the cursor page of rows for backends that hold rows in
+// memory: rows are ordered by (date_created, id), those up to the token are
+// skipped and at most limit are returned.
+func PageByCursor(rows []map[string]any, token string, limit int, descending bool) (page []map[string]any, more bool, err error) {
+	sorted := append([]map[string]any(nil), rows...)
+	less := func(a, b map[string]any) bool {
+		if c := compareCursorKeys(a[CursorKeyField], b[CursorKeyField]); c != 0 {
+			return c < 0
+		}
+		return fmt.Sprint(a["id"]) < fmt.Sprint(b["id"])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if descending {
+			return less(sorted[j], sorted[i])
+		}
+		return less(sorted[i], sorted[j])
+	})
+
+	if token != "" {
+		cursor, err := DecodeCursor(token)
+		if err != nil {
+			return nil, false, err
+		}
+		position := map[string]any{CursorKeyField: cursor.Key, "id": cursor.ID}
+		start := len(sorted)
+		for i, row := range sorted {
+			if (descending && less(row, position)) || (!descending && less(position, row)) {
+				start = i
+				break
+			}
+		}
+		sorted = sorted[start:]
+	}
+
+	if limit > 0 && len(sorted) > limit {
+		return sorted[:limit], true, nil
+	}
+	return sorted, false, nil
+}
+
+// compareCursorKeys orders CursorKeyField values of the same kind; numbers
+// of different Go types compare by value and anything else by its text.
+func compareCursorKeys(a, b any) int {
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Compare(tb)
+		}
+	}
+	if fa, ok := cursorNumber(a); ok {
+		if fb, ok := cursorNumber(b); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func cursorNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,81 @@
+package interfaces
+
+import (
+	"testing"
+	"time"
+
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 1, 8, 0, 0, 123456000, time.UTC)
+	for _, key := range []any{at, int64(1772352000123), 12.5, "2026-03-01 08:00:00.123456+00"} {
+		token, err := EncodeCursor(map[string]any{CursorKeyField: key, "id": "r1"})
+		if err != nil {
+			t.Fatalf("encode %T: %v", key, err)
+		}
+		cursor, err := DecodeCursor(token)
+		if err != nil {
+			t.Fatalf("decode %T: %v", key, err)
+		}
+		if cursor.ID != "r1" || compareCursorKeys(cursor.Key, key) != 0 {
+			t.Errorf("cursor = %+v, want key %v", cursor, key)
+		}
+	}
+
+	if _, err := EncodeCursor(map[string]any{CursorKeyField: int64(1)}); err == nil {
+		t.Error("encoded a row without an id")
+	}
+	if _, err := DecodeCursor("not a token"); err == nil {
+		t.Error("decoded a malformed token")
+	}
+}
+
+func TestCursorDescending(t *testing.T) {
+	sortBy := func(fields ...*commonpb.SortField) *ListParams {
+		return &ListParams{Sort: &commonpb.SortRequest{Fields: fields}}
+	}
+	if desc, err := CursorDescending(nil); err != nil || !desc {
+		t.Errorf("default = %t, %v; want descending", desc, err)
+	}
+	if desc, err := CursorDescending(sortBy(&commonpb.SortField{Field: CursorKeyField, Direction: commonpb.SortDirection_ASC})); err != nil || desc {
+		t.Errorf("date_created ASC = %t, %v; want ascending", desc, err)
+	}
+	if _, err := CursorDescending(sortBy(&commonpb.SortField{Field: "name"})); err == nil {
+		t.Error("accepted a sort by another field")
+	}
+}
+
+func TestPageByCursor(t *testing.T) {
+	rows := []map[string]any{
+		{"id": "b", CursorKeyField: int64(2)},
+		{"id": "a", CursorKeyField: int64(1)},
+		{"id": "c", CursorKeyField: int64(2)},
+		{"id": "d", CursorKeyField: int64(3)},
+	}
+	var ids []string
+	token := ""
+	for {
+		page, more, err := PageByCursor(rows, token, 3, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, row := range page {
+			ids = append(ids, row["id"].(string))
+		}
+		response, next, err := CursorPagination(page[len(page)-1], token, more, int32(len(rows)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.HasNext != more || response.HasPrev != (token != "") || response.GetNextCursor() != next {
+			t.Errorf("pagination = %v", response)
+		}
+		if !more {
+			break
+		}
+		token = next
+	}
+	if got := len(ids); got != 4 || ids[0] != "d" || ids[1] != "c" || ids[2] != "b" || ids[3] != "a" {
+		t.Errorf("ids = %v, want d c b a", ids)
+	}
+}
@@ -0,0 +1,78 @@
+package interfaces
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Dropped fields
+//
+// A backend with a fixed set of columns, like Postgres, cannot store a field
+// it has no column for. It reports such fields to a DroppedFields collector
+// on the context, so a caller that cares (an import, a migration check) can
+// see what a write lost instead of finding out on the next read.
+
+type droppedFieldsKey struct{}
+
+// DroppedFields collects the fields writes under a context dropped, by
+// table. It is safe for concurrent use.
+type DroppedFields struct {
+	mu     sync.Mutex
+	tables map[string]map[string]bool
+}
+
+// WithDroppedFields returns a context whose writes report dropped fields to
+// the returned collector.
+func WithDroppedFields(ctx context.Context) (context.Context, *DroppedFields) {
+	d := &DroppedFields{tables: make(map[string]map[string]bool)}
+	return context.WithValue(ctx, droppedFieldsKey{}, d), d
+}
+
+// ReportDroppedFields records fields a write to table dropped. It does
+// nothing when the context has no collector.
+func ReportDroppedFields(ctx context.Context, table string, fields []string) {
+	d, _ := ctx.Value(droppedFieldsKey{}).(*DroppedFields)
+	if d == nil || len(fields) == 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.tables[table] == nil {
+		d.tables[table] = make(map[string]bool)
+	}
+	for _, field := range fields {
+		d.tables[table][field] = true
+	}
+}
+
+// Fields returns the sorted fields dropped from table.
+func (d *DroppedFields) Fields(table string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fields := make([]string, 0, len(d.tables[table]))
+	for field := range d.tables[table] {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Tables returns the sorted tables that dropped fields.
+func (d *DroppedFields) Tables() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tables := make([]string, 0, len(d.tables))
+	for table := range d.tables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// Empty reports whether no write dropped a field.
+func (d *DroppedFields) Empty() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.tables) == 0
+}
@@ -0,0 +1,30 @@
+package interfaces
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDroppedFields(t *testing.T) {
+	ReportDroppedFields(context.Background(), "client", []string{"nickname"})
+
+	ctx, dropped := WithDroppedFields(context.Background())
+	if !dropped.Empty() {
+		t.Fatal("a new collector is not empty")
+	}
+	ReportDroppedFields(ctx, "client", []string{"nickname", "fax"})
+	ReportDroppedFields(ctx, "client", []string{"nickname"})
+	ReportDroppedFields(ctx, "user", nil)
+	ReportDroppedFields(ctx, "product", []string{"sku_legacy"})
+
+	if got := dropped.Tables(); !reflect.DeepEqual(got, []string{"client", "product"}) {
+		t.Errorf("Tables() = %v", got)
+	}
+	if got := dropped.Fields("client"); !reflect.DeepEqual(got, []string{"fax", "nickname"}) {
+		t.Errorf("Fields(client) = %v", got)
+	}
+	if got := dropped.Fields("user"); len(got) != 0 {
+		t.Errorf("Fields(user) = %v, want none", got)
+	}
+}
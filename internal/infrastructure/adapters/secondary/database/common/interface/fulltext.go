@@ -0,0 +1,61 @@
+package interfaces
+
+import (
+	"context"
+	"strings"
+
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// Full-text search
+//
+// TypedFilter has no full-text flag, so a string filter asks for full-text
+// matching through its field: FullTextPrefix followed by the column. The
+// filter then matches rows whose column contains every word of the value,
+// with the last letters of each word free ("ana san" finds "Ana Santos").
+// The same prefix on a SearchOptions.search_fields entry, or on a search
+// field an adapter declares, searches that field the same way.
+//
+// Only backends that implement TextSearcher understand the marker; check
+// for it before sending one. Postgres matches marked fields with
+// tsvector/tsquery and falls back to ILIKE when the value has no words or
+// full-text search is turned off.
+
+// FullTextPrefix marks a filter or search field as full-text.
+const FullTextPrefix = "fts:"
+
+// FullText marks field as full-text.
+func FullText(field string) string {
+	if strings.HasPrefix(field, FullTextPrefix) {
+		return field
+	}
+	return FullTextPrefix + field
+}
+
+// FullTextField returns the column of a field and whether it is marked
+// full-text.
+func FullTextField(field string) (column string, ok bool) {
+	return strings.CutPrefix(field, FullTextPrefix)
+}
+
+// FullTextFilter returns a filter matching the words of query against
+// field.
+func FullTextFilter(field, query string) *commonpb.TypedFilter {
+	return &commonpb.TypedFilter{
+		Field: FullText(field),
+		FilterType: &commonpb.TypedFilter_StringFilter{
+			StringFilter: &commonpb.StringFilter{
+				Value:    query,
+				Operator: commonpb.StringOperator_STRING_CONTAINS,
+			},
+		},
+	}
+}
+
+// TextSearcher is implemented by backends with native full-text search.
+type TextSearcher interface {
+	// SearchText lists the rows of table whose fields contain the words of
+	// query, best matches first, narrowed and paged by params like List
+	// (params.Search is ignored). Cursor pagination is not supported.
+	SearchText(ctx context.Context, table string, fields []string, query string, params *ListParams) (*ListResult, error)
+}
@@ -20,6 +20,10 @@ type ListResult struct {
 	Data       []map[string]any
 	Pagination *commonpb.PaginationResponse
 	Total      int32
+
+	// NextCursor resumes after the last row of a cursor page (see
+	// CursorRequest). It is empty for offset pages and on the last page.
+	NextCursor string
 }
 
 // DatabaseOperation defines the common database operations interface
@@ -29,11 +33,39 @@ type DatabaseOperation interface {
 	Update(ctx context.Context, tableName string, id string, data map[string]any) (map[string]any, error)
 	Delete(ctx context.Context, tableName string, id string) error
 	HardDelete(ctx context.Context, tableName string, id string) error
+
+	// List pages by offset, or by cursor when params.Pagination uses the
+	// Cursor method. Postgres, Firestore and the mock support cursors; other
+	// backends reject them (see cursor.go).
 	List(ctx context.Context, tableName string, params *ListParams) (*ListResult, error)
 
+	// Count returns how many records List would match for params, ignoring
+	// sort and pagination. Postgres runs SELECT COUNT(*) and Firestore an
+	// aggregation query, so no rows are read; other backends use
+	// CountFromList.
+	Count(ctx context.Context, tableName string, params *ListParams) (int64, error)
+
+	// Restore undoes a soft Delete, making the record active again and
+	// stamping date_modified. Restoring an active record is not an error.
+	Restore(ctx context.Context, tableName string, id string) error
+
+	// ListDeleted lists soft-deleted (inactive) records with List's filters,
+	// sort and pagination. Backends without a dedicated path use
+	// ListDeletedFromList (see softdelete.go).
+	ListDeleted(ctx context.Context, tableName string, params *ListParams) (*ListResult, error)
+
 	// Query-based operations for composite keys and complex queries
 	Query(ctx context.Context, tableName string, query QueryBuilder) ([]map[string]any, error)
 	QueryOne(ctx context.Context, tableName string, query QueryBuilder) (map[string]any, error)
+
+	// Batch operations. Results come back in input order. Postgres applies a
+	// batch with multi-row statements in one transaction (joining the context
+	// transaction when there is one); Firestore uses a BulkWriter, which is
+	// NOT atomic. Other backends loop over the single-row methods (see
+	// CreateEach) and are only atomic inside a context transaction.
+	CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error)
+	UpdateMany(ctx context.Context, tableName string, updates []BatchUpdate) ([]map[string]any, error)
+	DeleteMany(ctx context.Context, tableName string, ids []string) error
 }
 
 // TransactionAware extends DatabaseOperation with transaction-aware behavior
@@ -48,3 +80,18 @@ type TransactionAware interface {
 	// SupportsTransactions indicates if this repository can participate in transactions
 	SupportsTransactions() bool
 }
+
+// CountFromList implements Count with a one-row List and its Total. Backends
+// whose List already counts with the same filters use it.
+func CountFromList(ctx context.Context, op DatabaseOperation, tableName string, params *ListParams) (int64, error) {
+	page := &ListParams{Pagination: &commonpb.PaginationRequest{Limit: 1}}
+	if params != nil {
+		page.Search = params.Search
+		page.Filters = params.Filters
+	}
+	result, err := op.List(ctx, tableName, page)
+	if err != nil {
+		return 0, err
+	}
+	return int64(result.Total), nil
+}
@@ -0,0 +1,50 @@
+package interfaces
+
+import (
+	"context"
+
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// ActiveField is the soft-delete flag: Delete sets it to false, Restore back
+// to true, and List only returns active records unless params carry an
+// explicit "active" BooleanFilter.
+const ActiveField = "active"
+
+// DeletedParams returns a copy of params selecting soft-deleted records: any
+// "active" filter is replaced by active = false. params is not modified.
+func DeletedParams(params *ListParams) *ListParams {
+	deleted := &ListParams{}
+	filters := &commonpb.FilterRequest{}
+	if params != nil {
+		*deleted = *params
+		if params.Filters != nil {
+			filters.Logic = params.Filters.Logic
+			for _, f := range params.Filters.Filters {
+				if f.GetField() != ActiveField {
+					filters.Filters = append(filters.Filters, f)
+				}
+			}
+		}
+	}
+	filters.Filters = append(filters.Filters, &commonpb.TypedFilter{
+		Field:      ActiveField,
+		FilterType: &commonpb.TypedFilter_BooleanFilter{BooleanFilter: &commonpb.BooleanFilter{Value: false}},
+	})
+	deleted.Filters = filters
+	return deleted
+}
+
+// ListDeletedFromList implements ListDeleted with List and DeletedParams.
+// Every backend's List honours an explicit active filter in place of its
+// default active = true.
+func ListDeletedFromList(ctx context.Context, op DatabaseOperation, tableName string, params *ListParams) (*ListResult, error) {
+	return op.List(ctx, tableName, DeletedParams(params))
+}
+
+// RestoreWithUpdate implements Restore with an Update setting active to
+// true; Update finds inactive records and stamps date_modified.
+func RestoreWithUpdate(ctx context.Context, op DatabaseOperation, tableName string, id string) error {
+	_, err := op.Update(ctx, tableName, id, map[string]any{ActiveField: true})
+	return err
+}
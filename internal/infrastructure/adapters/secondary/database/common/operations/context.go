@@ -16,6 +16,10 @@ const (
 
 	// TransactionManagerContextKey is used to store TransactionManager in context
 	TransactionManagerContextKey contextKey = "transaction_manager"
+
+	// PrimaryReadContextKey marks a context whose reads must see the latest
+	// writes
+	PrimaryReadContextKey contextKey = "primary_read"
 )
 
 // WithTransaction adds a transaction to the context
@@ -49,6 +53,20 @@ func IsTransactionContext(ctx context.Context) bool {
 	return tx.State() == interfaces.TransactionStatePending
 }
 
+// WithPrimaryRead routes the reads made with the returned context to the
+// source of truth: past the result cache and, on backends with read
+// replicas, to the primary. Use it to read back data just written, such as
+// when an import verifies what it stored.
+func WithPrimaryRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, PrimaryReadContextKey, true)
+}
+
+// IsPrimaryRead reports whether the context asks for primary reads.
+func IsPrimaryRead(ctx context.Context) bool {
+	primary, _ := ctx.Value(PrimaryReadContextKey).(bool)
+	return primary
+}
+
 // generateTransactionID creates a unique transaction identifier
 func generateTransactionID() string {
 	return fmt.Sprintf("tx_%s", generateUUID())
@@ -186,10 +186,43 @@ func ParseTimestamp(timestampStr string) (int64, error) {
 	return 0, fmt.Errorf("unable to parse timestamp: %s", timestampStr)
 }
 
+// Number reads a numeric column of a database row as a float64, whatever
+// integer or float type the provider returned it as. Anything else is 0.
+func Number(v any) float64 {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	}
+	return 0
+}
+
+// mapUnmarshalOptions decodes database rows: columns without a matching
+// protobuf field are ignored.
+var mapUnmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+
 // ConvertMapToProtobuf converts a map[string]any to any protobuf message using protojson
 // This is a generic helper that can be used across all repositories
-// It handles automatic field filtering (DiscardUnknown: true) and provides consistent conversion logic
+// It handles automatic field filtering (DiscardUnknown: true) and provides consistent conversion logic.
+// Rows made of plain scalars and nested messages are decoded directly without the JSON
+// round-trip; anything else falls back to protojson with identical results.
 func ConvertMapToProtobuf[T proto.Message](data map[string]any, target T) (T, error) {
+	if err := decodeDirect(data, target); err == nil {
+		return target, nil
+	}
+	return convertMapToProtobufJSON(data, target)
+}
+
+// convertMapToProtobufJSON is the protojson round-trip ConvertMapToProtobuf
+// falls back to.
+func convertMapToProtobufJSON[T proto.Message](data map[string]any, target T) (T, error) {
 	// Convert map to JSON bytes
 	jsonBytes, err := json.Marshal(data)
 	if err != nil {
@@ -197,11 +230,7 @@ func ConvertMapToProtobuf[T proto.Message](data map[string]any, target T) (T, er
 	}
 
 	// Use protojson to unmarshal directly into protobuf target
-	unmarshalOptions := protojson.UnmarshalOptions{
-		DiscardUnknown: true, // Automatically ignores fields not present in protobuf
-	}
-
-	if err := unmarshalOptions.Unmarshal(jsonBytes, target); err != nil {
+	if err := mapUnmarshalOptions.Unmarshal(jsonBytes, target); err != nil {
 		return target, fmt.Errorf("failed to unmarshal JSON to protobuf [ConvertMapToProtobuf]: %w", err)
 	}
 
@@ -0,0 +1,340 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Direct map → protobuf decoding.
+//
+// ConvertMapToProtobuf used to json.Marshal every row and parse it back with
+// protojson, which dominated CPU on list endpoints returning 100-row pages.
+// decodeMessage sets fields straight from the map through protoreflect,
+// looking fields up in a per-descriptor index built once. It covers what
+// database rows hold: scalars, enums, repeated scalars and nested
+// messages. Anything else (maps, oneofs, well-known types, values that need
+// JSON coercion) returns errNeedsJSON and the caller falls back to protojson,
+// so results and errors stay identical to the protojson path.
+
+// errNeedsJSON reports a value the direct decoder leaves to protojson.
+var errNeedsJSON = errors.New("value requires protojson decoding")
+
+// fieldIndex resolves both JSON (camelCase) and proto (snake_case) field
+// names, as protojson does.
+type fieldIndex map[string]protoreflect.FieldDescriptor
+
+// fieldIndexes caches a fieldIndex per message descriptor.
+var fieldIndexes sync.Map // protoreflect.MessageDescriptor -> fieldIndex
+
+func fieldsOf(md protoreflect.MessageDescriptor) fieldIndex {
+	if cached, ok := fieldIndexes.Load(md); ok {
+		return cached.(fieldIndex)
+	}
+	fields := md.Fields()
+	index := make(fieldIndex, fields.Len()*2)
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		index[string(fd.Name())] = fd
+		index[fd.JSONName()] = fd
+	}
+	cached, _ := fieldIndexes.LoadOrStore(md, index)
+	return cached.(fieldIndex)
+}
+
+// decodeMessage sets m's fields from data. Unknown keys are ignored and nil
+// values leave the field unset, matching DiscardUnknown protojson.
+func decodeMessage(m protoreflect.Message, data map[string]any) error {
+	index := fieldsOf(m.Descriptor())
+	for key, value := range data {
+		fd, ok := index[key]
+		if !ok || value == nil {
+			continue
+		}
+		// protojson rejects a field given under both of its names.
+		if name := string(fd.Name()); name != fd.JSONName() {
+			other := name
+			if key == name {
+				other = fd.JSONName()
+			}
+			if _, dup := data[other]; dup {
+				return errNeedsJSON
+			}
+		}
+		if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+			return errNeedsJSON
+		}
+		if err := decodeField(m, fd, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeField(m protoreflect.Message, fd protoreflect.FieldDescriptor, value any) error {
+	switch {
+	case fd.IsMap():
+		return errNeedsJSON
+	case fd.IsList():
+		items, ok := value.([]any)
+		if !ok {
+			return errNeedsJSON
+		}
+		list := m.Mutable(fd).List()
+		for _, item := range items {
+			if item == nil {
+				return errNeedsJSON
+			}
+			if fd.Message() != nil {
+				elem := list.NewElement()
+				if err := decodeNested(elem.Message(), item); err != nil {
+					return err
+				}
+				list.Append(elem)
+				continue
+			}
+			v, err := scalarValue(fd, item)
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+		}
+		return nil
+	case fd.Message() != nil:
+		return decodeNested(m.Mutable(fd).Message(), value)
+	default:
+		v, err := scalarValue(fd, value)
+		if err != nil {
+			return err
+		}
+		m.Set(fd, v)
+		return nil
+	}
+}
+
+// decodeNested decodes a nested message. Well-known types have custom JSON
+// forms, so they go through protojson.
+func decodeNested(m protoreflect.Message, value any) error {
+	if strings.HasPrefix(string(m.Descriptor().FullName()), "google.protobuf.") {
+		return errNeedsJSON
+	}
+	data, ok := value.(map[string]any)
+	if !ok {
+		return errNeedsJSON
+	}
+	return decodeMessage(m, data)
+}
+
+// scalarValue converts a Go value as database drivers return it into the
+// field's kind, declining anything protojson would coerce or reject.
+func scalarValue(fd protoreflect.FieldDescriptor, value any) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		// json.Marshal rewrites invalid UTF-8, so leave that to the JSON path.
+		if s, ok := value.(string); ok && utf8.ValidString(s) {
+			return protoreflect.ValueOfString(s), nil
+		}
+	case protoreflect.BoolKind:
+		if b, ok := value.(bool); ok {
+			return protoreflect.ValueOfBool(b), nil
+		}
+	case protoreflect.BytesKind:
+		if b, ok := value.([]byte); ok {
+			return protoreflect.ValueOfBytes(bytes.Clone(b)), nil
+		}
+	case protoreflect.EnumKind:
+		if fd.Enum().FullName() == "google.protobuf.NullValue" {
+			break
+		}
+		if name, ok := value.(string); ok {
+			if ev := fd.Enum().Values().ByName(protoreflect.Name(name)); ev != nil {
+				return protoreflect.ValueOfEnum(ev.Number()), nil
+			}
+			break
+		}
+		if n, ok := toInt(value, 32); ok {
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n, ok := toInt(value, 32); ok {
+			return protoreflect.ValueOfInt32(int32(n)), nil
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if n, ok := toInt(value, 64); ok {
+			return protoreflect.ValueOfInt64(n), nil
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n, ok := toUint(value, 32); ok {
+			return protoreflect.ValueOfUint32(uint32(n)), nil
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n, ok := toUint(value, 64); ok {
+			return protoreflect.ValueOfUint64(n), nil
+		}
+	case protoreflect.FloatKind:
+		if f, ok := toFloat(value); ok && math.Abs(f) <= math.MaxFloat32 {
+			return protoreflect.ValueOfFloat32(float32(f)), nil
+		}
+	case protoreflect.DoubleKind:
+		if f, ok := toFloat(value); ok {
+			return protoreflect.ValueOfFloat64(f), nil
+		}
+	}
+	return protoreflect.Value{}, errNeedsJSON
+}
+
+// toInt reads an integer that fits in bits. Whole floats are accepted, as
+// protojson accepts 5.0 for an integer field; quoted numbers are parsed.
+func toInt(value any, bits int) (int64, bool) {
+	var n int64
+	switch v := value.(type) {
+	case int:
+		n = int64(v)
+	case int8:
+		n = int64(v)
+	case int16:
+		n = int64(v)
+	case int32:
+		n = int64(v)
+	case int64:
+		n = v
+	case uint8:
+		n = int64(v)
+	case uint16:
+		n = int64(v)
+	case uint32:
+		n = int64(v)
+	case uint:
+		if uint64(v) > math.MaxInt64 {
+			return 0, false
+		}
+		n = int64(v)
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, false
+		}
+		n = int64(v)
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false
+		}
+		n = int64(v)
+	case float32:
+		return toInt(float64(v), bits)
+	case json.Number:
+		return toInt(string(v), bits)
+	case string:
+		if !isPlainInteger(v) {
+			return 0, false
+		}
+		parsed, err := strconv.ParseInt(v, 10, bits)
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+	if bits == 32 && (n < math.MinInt32 || n > math.MaxInt32) {
+		return 0, false
+	}
+	return n, true
+}
+
+// toUint is toInt for unsigned fields.
+func toUint(value any, bits int) (uint64, bool) {
+	switch v := value.(type) {
+	case uint:
+		return fitsUint(uint64(v), bits)
+	case uint64:
+		return fitsUint(v, bits)
+	case json.Number:
+		return toUint(string(v), bits)
+	case string:
+		if !isPlainInteger(v) {
+			return 0, false
+		}
+		parsed, err := strconv.ParseUint(v, 10, bits)
+		return parsed, err == nil
+	default:
+		n, ok := toInt(value, 64)
+		if !ok || n < 0 {
+			return 0, false
+		}
+		return fitsUint(uint64(n), bits)
+	}
+}
+
+// isPlainInteger reports whether s is an integer in JSON number syntax, the
+// form protojson.Marshal writes 64-bit integers in. Exponents, signs and
+// leading zeros are left to protojson.
+func isPlainInteger(s string) bool {
+	digits := strings.TrimPrefix(s, "-")
+	if digits == "" || (len(digits) > 1 && digits[0] == '0') {
+		return false
+	}
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func fitsUint(n uint64, bits int) (uint64, bool) {
+	if bits == 32 && n > math.MaxUint32 {
+		return 0, false
+	}
+	return n, true
+}
+
+// toFloat reads a finite number. NaN and infinities only have string forms
+// in protojson, so they take the JSON path.
+func toFloat(value any) (float64, bool) {
+	var f float64
+	switch v := value.(type) {
+	case float64:
+		f = v
+	case float32:
+		f = float64(v)
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		f = parsed
+	case string:
+		return 0, false
+	default:
+		n, ok := toInt(value, 64)
+		if !ok {
+			if u, isUint := value.(uint64); isUint {
+				return float64(u), true
+			}
+			return 0, false
+		}
+		f = float64(n)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+// decodeDirect decodes data into target without the JSON round-trip.
+func decodeDirect(data map[string]any, target proto.Message) error {
+	m := target.ProtoReflect()
+	if !m.IsValid() {
+		return errNeedsJSON
+	}
+	proto.Reset(target)
+	if err := decodeMessage(m, data); err != nil {
+		return err
+	}
+	return proto.CheckInitialized(target)
+}
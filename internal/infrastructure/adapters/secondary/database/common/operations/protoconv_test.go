@@ -0,0 +1,186 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// testRecord builds a message shaped like the domain records repositories
+// return: scalars, audit timestamps, an enum, a repeated field, a nested
+// message and a oneof.
+func testRecord(t testing.TB) protoreflect.MessageDescriptor {
+	t.Helper()
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   typ.Enum(),
+			Label:  label.Enum(),
+		}
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+
+	status := field("status", 7, descriptorpb.FieldDescriptorProto_TYPE_ENUM, optional)
+	status.TypeName = proto.String(".test.Status")
+	address := field("billing_address", 9, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional)
+	address.TypeName = proto.String(".test.Address")
+	email := field("email", 10, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional)
+	email.OneofIndex = proto.Int32(0)
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test/record.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("STATUS_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("STATUS_PAID"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("Address"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("city", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional)},
+			},
+			{
+				Name: proto.String("Record"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional),
+					field("amount", 2, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, optional),
+					field("quantity", 3, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional),
+					field("date_created", 4, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional),
+					field("date_created_string", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional),
+					field("active", 6, descriptorpb.FieldDescriptorProto_TYPE_BOOL, optional),
+					status,
+					field("tags", 8, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated),
+					address,
+					email,
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("contact")}},
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("build descriptor: %v", err)
+	}
+	return fd.Messages().ByName("Record")
+}
+
+// testRow is a record row as a database adapter hands it over.
+func testRow(i int) map[string]any {
+	return map[string]any{
+		"id":                  fmt.Sprintf("rec-%03d", i),
+		"amount":              2500.5,
+		"quantity":            int64(3),
+		"date_created":        "1760572800000", // protojson writes int64 as a string
+		"date_created_string": "2025-10-16T00:00:00Z",
+		"active":              true,
+		"status":              "STATUS_PAID",
+		"tags":                []any{"monthly", "coaching"},
+		"billing_address":     map[string]any{"city": "Manila"},
+		"workspace_id":        "ws-1", // column without a field
+		"date_modified":       nil,
+	}
+}
+
+func TestConvertMapToProtobuf_MatchesProtojson(t *testing.T) {
+	md := testRecord(t)
+	rows := map[string]map[string]any{
+		"typical row":       testRow(1),
+		"camelCase keys":    {"dateCreated": int64(1760572800000), "billingAddress": map[string]any{"city": "Cebu"}},
+		"enum by number":    {"status": float64(1)},
+		"unknown enum name": {"status": "STATUS_REFUNDED"},
+		"whole float int":   {"quantity": float64(7)},
+		"oneof":             {"email": "a@example.com"},
+		"json.Number":       {"amount": json.Number("12.5"), "date_created": json.Number("42")},
+		"fractional int":    {"quantity": 1.5},
+		"int32 overflow":    {"quantity": int64(1) << 40},
+		"wrong type":        {"active": "yes"},
+		"both names":        {"date_created": int64(1), "dateCreated": int64(2)},
+		"leading zero":      {"date_created": "007"},
+		"null list item":    {"tags": []any{"a", nil}},
+	}
+	for name, row := range rows {
+		t.Run(name, func(t *testing.T) {
+			want, wantErr := convertMapToProtobufJSON(row, dynamicpb.NewMessage(md))
+			got, gotErr := ConvertMapToProtobuf(row, dynamicpb.NewMessage(md))
+			if (gotErr == nil) != (wantErr == nil) {
+				t.Fatalf("err = %v, protojson err = %v", gotErr, wantErr)
+			}
+			if wantErr == nil && !proto.Equal(got, want) {
+				t.Fatalf("got %v, protojson gives %v", got, want)
+			}
+		})
+	}
+}
+
+func TestConvertMapToProtobuf_DirectPathCoversTypicalRow(t *testing.T) {
+	if err := decodeDirect(testRow(1), dynamicpb.NewMessage(testRecord(t))); err != nil {
+		t.Fatalf("typical row fell back to protojson: %v", err)
+	}
+}
+
+// Benchmarks for the conversions done on every repository call. A list
+// endpoint converts a 100-row page.
+//
+//	go test ./internal/infrastructure/adapters/secondary/database/common/operations -bench . -benchmem
+
+const benchPageSize = 100
+
+func benchPage() []map[string]any {
+	page := make([]map[string]any, benchPageSize)
+	for i := range page {
+		page[i] = testRow(i)
+	}
+	return page
+}
+
+func BenchmarkConvertSliceToProtobuf(b *testing.B) {
+	md, page := testRecord(b), benchPage()
+	factory := func() *dynamicpb.Message { return dynamicpb.NewMessage(md) }
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, errs := ConvertSliceToProtobuf(page, factory); len(errs) > 0 {
+			b.Fatal(errs[0])
+		}
+	}
+}
+
+func BenchmarkConvertSliceToProtobuf_Protojson(b *testing.B) {
+	md, page := testRecord(b), benchPage()
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, row := range page {
+			if _, err := convertMapToProtobufJSON(row, dynamicpb.NewMessage(md)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkConvertProtobufToMap(b *testing.B) {
+	md := testRecord(b)
+	msg := dynamicpb.NewMessage(md)
+	if err := protojson.Unmarshal([]byte(`{"id":"rec-001","amount":2500.5,"dateCreated":"1760572800000","status":"STATUS_PAID","tags":["monthly"]}`), msg); err != nil {
+		b.Fatal(err)
+	}
+	mapper := NewProtobufMapper()
+	b.ReportAllocs()
+	for b.Loop() {
+		for range benchPageSize {
+			if _, err := mapper.ConvertProtobufToMap(msg); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
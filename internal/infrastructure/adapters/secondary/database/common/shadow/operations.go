@@ -0,0 +1,502 @@
+// Package shadow provides a DatabaseOperation decorator that replays
+// sampled reads against a candidate database, such as Postgres during a
+// Firestore to Postgres migration, and reports where the candidate's
+// results differ from the primary's.
+//
+// The caller always gets the primary's result. The candidate read runs in
+// the background after the primary returns, bounded by a timeout and a cap
+// on reads in flight, so a slow or failing candidate never slows a request.
+// Every comparison is counted in espyna_db_shadow_reads_total by outcome;
+// mismatches are also logged with the row IDs and field names that differ,
+// never the values.
+//
+// Writes are not mirrored: keeping the candidate in step is the migration
+// tooling's job. Reads inside a transaction and primary reads
+// (operations.WithPrimaryRead) are not shadowed, since the candidate cannot
+// see uncommitted or just-written rows.
+package shadow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	"github.com/erniealice/espyna-golang/shared/metrics"
+)
+
+const (
+	// DefaultTimeout bounds a candidate read when the policy sets none.
+	DefaultTimeout = 5 * time.Second
+	// DefaultMaxInFlight caps concurrent candidate reads when the policy
+	// sets no cap.
+	DefaultMaxInFlight = 32
+	// maxReported bounds the IDs and fields one mismatch log line names.
+	maxReported = 10
+)
+
+// Outcomes of a shadowed read, as counted in the metrics.
+const (
+	OutcomeMatch    = "match"
+	OutcomeMismatch = "mismatch"
+	OutcomeError    = "error"
+	OutcomeSkipped  = "skipped"
+)
+
+// shadowContextKey marks the context of a candidate read, so a candidate
+// whose own operations carry this decorator does not shadow again.
+type shadowContextKey struct{}
+
+// Mismatch describes a shadowed read whose results differ.
+type Mismatch struct {
+	Primary   string
+	Candidate string
+	Operation string
+	Table     string
+	// Differences lists what differs: row IDs missing on either side,
+	// fields whose values differ, and differing counts or errors.
+	Differences []string
+}
+
+// ShadowOperations wraps a DatabaseOperation and replays sampled Read, List
+// and Count calls against a candidate. Everything else passes through.
+type ShadowOperations struct {
+	inner   interfaces.DatabaseOperation
+	primary string
+
+	// candidate and policy are fixed when set; otherwise the registry's
+	// default shadow is read on every call.
+	candidate interfaces.DatabaseOperation
+	policy    *registry.ShadowPolicy
+
+	// OnMismatch, when set, receives every mismatch in addition to the log.
+	OnMismatch func(Mismatch)
+
+	inFlight atomic.Int64
+	pending  sync.WaitGroup
+	sample   func() float64
+}
+
+// Ensure ShadowOperations satisfies the full DatabaseOperation interface at
+// compile time.
+var _ interfaces.DatabaseOperation = (*ShadowOperations)(nil)
+
+// NewShadowOperations wraps inner, the operations of the primary provider
+// named primary, with the candidate published through
+// registry.SetDefaultShadow. Until one is published every call passes
+// straight through.
+func NewShadowOperations(inner interfaces.DatabaseOperation, primary string) *ShadowOperations {
+	return &ShadowOperations{inner: inner, primary: primary, sample: rand.Float64}
+}
+
+// NewShadowOperationsWithCandidate wraps inner with a specific candidate and
+// policy.
+func NewShadowOperationsWithCandidate(inner interfaces.DatabaseOperation, primary string, candidate interfaces.DatabaseOperation, policy registry.ShadowPolicy) *ShadowOperations {
+	return &ShadowOperations{inner: inner, primary: primary, candidate: candidate, policy: &policy, sample: rand.Float64}
+}
+
+// Inner returns the wrapped operations.
+func (s *ShadowOperations) Inner() interfaces.DatabaseOperation {
+	return s.inner
+}
+
+// Wait blocks until the candidate reads in flight have been compared.
+func (s *ShadowOperations) Wait() {
+	s.pending.Wait()
+}
+
+// ── Reads ────────────────────────────────────────────────────────────────────
+
+// Read reads from the primary and, when sampled, compares the candidate's
+// row.
+func (s *ShadowOperations) Read(ctx context.Context, tableName string, id string) (map[string]any, error) {
+	row, err := s.inner.Read(ctx, tableName, id)
+	candidate, policy, ok := s.active(ctx, tableName)
+	if !ok {
+		return row, err
+	}
+	want := result{err: err, rows: []map[string]any{normalize(row, policy)}}
+	s.replay(ctx, candidate, policy, "read", tableName, want, func(ctx context.Context) (result, error) {
+		row, err := candidate.Read(ctx, tableName, id)
+		return result{err: err, rows: []map[string]any{normalize(row, policy)}}, err
+	})
+	return row, err
+}
+
+// List lists from the primary and, when sampled, compares the candidate's
+// page.
+func (s *ShadowOperations) List(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	page, err := s.inner.List(ctx, tableName, params)
+	candidate, policy, ok := s.active(ctx, tableName)
+	if !ok || isCursorPage(params) {
+		// Cursors are provider-specific; the candidate cannot resume one.
+		return page, err
+	}
+	want := listResult(page, err, policy, params)
+	s.replay(ctx, candidate, policy, "list", tableName, want, func(ctx context.Context) (result, error) {
+		page, err := candidate.List(ctx, tableName, params)
+		return listResult(page, err, policy, params), err
+	})
+	return page, err
+}
+
+// Count counts on the primary and, when sampled, compares the candidate's
+// count.
+func (s *ShadowOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (int64, error) {
+	count, err := s.inner.Count(ctx, tableName, params)
+	candidate, policy, ok := s.active(ctx, tableName)
+	if !ok {
+		return count, err
+	}
+	want := result{err: err, count: count, counted: true}
+	s.replay(ctx, candidate, policy, "count", tableName, want, func(ctx context.Context) (result, error) {
+		count, err := candidate.Count(ctx, tableName, params)
+		return result{err: err, count: count, counted: true}, err
+	})
+	return count, err
+}
+
+// ListDeleted passes through; deleted records are not replayed.
+func (s *ShadowOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return s.inner.ListDeleted(ctx, tableName, params)
+}
+
+// Query passes through; query builders are not replayed.
+func (s *ShadowOperations) Query(ctx context.Context, tableName string, query interfaces.QueryBuilder) ([]map[string]any, error) {
+	return s.inner.Query(ctx, tableName, query)
+}
+
+// QueryOne passes through; query builders are not replayed.
+func (s *ShadowOperations) QueryOne(ctx context.Context, tableName string, query interfaces.QueryBuilder) (map[string]any, error) {
+	return s.inner.QueryOne(ctx, tableName, query)
+}
+
+// ── Writes ───────────────────────────────────────────────────────────────────
+
+// Create writes to the primary only.
+func (s *ShadowOperations) Create(ctx context.Context, tableName string, data map[string]any) (map[string]any, error) {
+	return s.inner.Create(ctx, tableName, data)
+}
+
+// Update writes to the primary only.
+func (s *ShadowOperations) Update(ctx context.Context, tableName string, id string, data map[string]any) (map[string]any, error) {
+	return s.inner.Update(ctx, tableName, id, data)
+}
+
+// Delete writes to the primary only.
+func (s *ShadowOperations) Delete(ctx context.Context, tableName string, id string) error {
+	return s.inner.Delete(ctx, tableName, id)
+}
+
+// HardDelete writes to the primary only.
+func (s *ShadowOperations) HardDelete(ctx context.Context, tableName string, id string) error {
+	return s.inner.HardDelete(ctx, tableName, id)
+}
+
+// Restore writes to the primary only.
+func (s *ShadowOperations) Restore(ctx context.Context, tableName string, id string) error {
+	return s.inner.Restore(ctx, tableName, id)
+}
+
+// CreateMany writes to the primary only.
+func (s *ShadowOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	return s.inner.CreateMany(ctx, tableName, data)
+}
+
+// UpdateMany writes to the primary only.
+func (s *ShadowOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	return s.inner.UpdateMany(ctx, tableName, updates)
+}
+
+// DeleteMany writes to the primary only.
+func (s *ShadowOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	return s.inner.DeleteMany(ctx, tableName, ids)
+}
+
+// ── Replay ───────────────────────────────────────────────────────────────────
+
+// result is a read's outcome reduced to comparable form.
+type result struct {
+	err     error
+	rows    []map[string]any
+	ordered bool
+	// partial marks a full page of an unsorted list, whose rows each
+	// provider may pick differently.
+	partial bool
+	total   int64
+	count   int64
+	counted bool
+}
+
+// active returns the candidate and policy for a read of tableName, or false
+// when the read is not shadowed.
+func (s *ShadowOperations) active(ctx context.Context, tableName string) (interfaces.DatabaseOperation, registry.ShadowPolicy, bool) {
+	candidate, policy := s.candidate, registry.ShadowPolicy{}
+	if s.policy != nil {
+		policy = *s.policy
+	} else {
+		published, p := registry.GetDefaultShadow()
+		candidate, _ = published.(interfaces.DatabaseOperation)
+		policy = p
+	}
+	if candidate == nil || !policy.Shadows(tableName) || ctx.Value(shadowContextKey{}) != nil ||
+		operations.IsTransactionContext(ctx) || operations.IsPrimaryRead(ctx) {
+		return nil, policy, false
+	}
+	if policy.SampleRate < 1 && s.sample() >= policy.SampleRate {
+		return nil, policy, false
+	}
+	return candidate, policy, true
+}
+
+// replay runs read against the candidate in the background and compares
+// its result with want.
+func (s *ShadowOperations) replay(ctx context.Context, candidate interfaces.DatabaseOperation, policy registry.ShadowPolicy, operation, tableName string, want result, read func(context.Context) (result, error)) {
+	name := candidateName(policy)
+	limit := int64(policy.MaxInFlight)
+	if limit <= 0 {
+		limit = DefaultMaxInFlight
+	}
+	if s.inFlight.Add(1) > limit {
+		s.inFlight.Add(-1)
+		metrics.ObserveShadowRead(name, operation, tableName, OutcomeSkipped)
+		return
+	}
+	timeout := policy.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	// The candidate read keeps the request's values (workspace, user) but
+	// not its cancellation: the request is usually done by the time it runs.
+	// It also bypasses any result cache the candidate shares with the primary.
+	shadowCtx := operations.WithPrimaryRead(context.WithValue(context.WithoutCancel(ctx), shadowContextKey{}, true))
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		defer s.inFlight.Add(-1)
+		readCtx, cancel := context.WithTimeout(shadowCtx, timeout)
+		defer cancel()
+
+		got, err := read(readCtx)
+		if isNotFound(err) {
+			// A row the candidate lacks is a difference, not a failure.
+			got.err, err = nil, nil
+		}
+		if err != nil && want.err == nil {
+			log.Printf("⚠️ db shadow: %s %s on %s failed: %v", operation, tableName, name, err)
+			metrics.ObserveShadowRead(name, operation, tableName, OutcomeError)
+			return
+		}
+		differences := compare(want, got)
+		if len(differences) == 0 {
+			metrics.ObserveShadowRead(name, operation, tableName, OutcomeMatch)
+			return
+		}
+		metrics.ObserveShadowRead(name, operation, tableName, OutcomeMismatch)
+		mismatch := Mismatch{Primary: s.primary, Candidate: name, Operation: operation, Table: tableName, Differences: differences}
+		log.Printf("⚠️ db shadow: %s %s differs between %s and %s: %s",
+			operation, tableName, s.primary, name, strings.Join(differences, "; "))
+		if s.OnMismatch != nil {
+			s.OnMismatch(mismatch)
+		}
+	}()
+}
+
+// isNotFound reports whether err is a provider's not-found error.
+func isNotFound(err error) bool {
+	var dbErr *model.DatabaseError
+	return errors.As(err, &dbErr) && dbErr.HTTPStatus == http.StatusNotFound
+}
+
+func candidateName(policy registry.ShadowPolicy) string {
+	if policy.Candidate == "" {
+		return "candidate"
+	}
+	return policy.Candidate
+}
+
+func isCursorPage(params *interfaces.ListParams) bool {
+	_, ok := interfaces.CursorRequest(params)
+	return ok
+}
+
+// listResult reduces a page to comparable form. The primary's page is
+// copied before the caller can change it.
+func listResult(page *interfaces.ListResult, err error, policy registry.ShadowPolicy, params *interfaces.ListParams) result {
+	r := result{err: err}
+	var limit int32
+	if params != nil {
+		r.ordered = len(params.Sort.GetFields()) > 0
+		limit = params.Pagination.GetLimit()
+	}
+	if page == nil {
+		return r
+	}
+	r.total = int64(page.Total)
+	for _, row := range page.Data {
+		r.rows = append(r.rows, normalize(row, policy))
+	}
+	if !r.ordered && limit > 0 && len(r.rows) >= int(limit) {
+		r.partial = true
+	}
+	return r
+}
+
+// normalize copies row through JSON, so values compare the same whatever
+// Go types a provider returns (int64 or float64, time.Time or its RFC 3339
+// text), and drops the ignored fields.
+func normalize(row map[string]any, policy registry.ShadowPolicy) map[string]any {
+	if row == nil {
+		return nil
+	}
+	raw, err := json.Marshal(row)
+	if err != nil {
+		return map[string]any{"_unencodable": err.Error()}
+	}
+	var copied map[string]any
+	if err := json.Unmarshal(raw, &copied); err != nil {
+		return map[string]any{"_unencodable": err.Error()}
+	}
+	for field := range policy.IgnoreFields {
+		delete(copied, field)
+	}
+	return copied
+}
+
+// compare lists the differences between the primary's result and the
+// candidate's.
+func compare(want, got result) []string {
+	if isNotFound(want.err) {
+		want.err = nil
+	}
+	switch {
+	case want.err != nil && got.err != nil:
+		return nil
+	case want.err != nil:
+		return []string{fmt.Sprintf("primary failed (%v) but candidate succeeded", want.err)}
+	case want.counted:
+		if want.count != got.count {
+			return []string{fmt.Sprintf("count %d != %d", want.count, got.count)}
+		}
+		return nil
+	}
+
+	var differences []string
+	if want.total > 0 && got.total > 0 && want.total != got.total {
+		differences = append(differences, fmt.Sprintf("total %d != %d", want.total, got.total))
+	}
+	wantByID, wantOrder := byID(want.rows)
+	gotByID, gotOrder := byID(got.rows)
+
+	var missing, extra, changed []string
+	for _, id := range wantOrder {
+		row, ok := gotByID[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		if fields := diffFields(wantByID[id], row); len(fields) > 0 {
+			changed = append(changed, id+" ("+strings.Join(fields, ", ")+")")
+		}
+	}
+	for _, id := range gotOrder {
+		if _, ok := wantByID[id]; !ok {
+			extra = append(extra, id)
+		}
+	}
+	if want.partial || got.partial {
+		// Only the rows both providers picked compare.
+		missing, extra = nil, nil
+	}
+	if len(missing) > 0 {
+		differences = append(differences, "missing from candidate: "+list(missing))
+	}
+	if len(extra) > 0 {
+		differences = append(differences, "only in candidate: "+list(extra))
+	}
+	if len(changed) > 0 {
+		differences = append(differences, "fields differ: "+list(changed))
+	}
+	if want.ordered && len(differences) == 0 && !reflect.DeepEqual(wantOrder, gotOrder) {
+		differences = append(differences, "rows in a different order")
+	}
+	return differences
+}
+
+// byID indexes rows by id. Rows without one are keyed by position.
+func byID(rows []map[string]any) (map[string]map[string]any, []string) {
+	index := make(map[string]map[string]any, len(rows))
+	order := make([]string, 0, len(rows))
+	for i, row := range rows {
+		if row == nil {
+			continue
+		}
+		id := fmt.Sprint(row["id"])
+		if row["id"] == nil {
+			id = fmt.Sprintf("#%d", i)
+		}
+		index[id] = row
+		order = append(order, id)
+	}
+	return index, order
+}
+
+// diffFields names the fields whose values differ. A field one side lacks
+// and the other holds as null or its zero value is not a difference:
+// providers disagree on storing empty columns.
+func diffFields(a, b map[string]any) []string {
+	var fields []string
+	for field, av := range a {
+		if bv, ok := b[field]; ok || !isEmpty(av) {
+			if !reflect.DeepEqual(av, bv) && !(isEmpty(av) && isEmpty(bv)) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	for field, bv := range b {
+		if _, ok := a[field]; !ok && !isEmpty(bv) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func list(items []string) string {
+	if len(items) > maxReported {
+		return strings.Join(items[:maxReported], ", ") + fmt.Sprintf(" and %d more", len(items)-maxReported)
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,207 @@
+package shadow
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// tableOps serves fixed rows and counts the reads reaching it.
+type tableOps struct {
+	interfaces.DatabaseOperation
+	rows  []map[string]any
+	mu    sync.Mutex
+	reads int
+	block chan struct{}
+}
+
+func (o *tableOps) Read(ctx context.Context, _ string, id string) (map[string]any, error) {
+	o.mu.Lock()
+	o.reads++
+	o.mu.Unlock()
+	if o.block != nil {
+		select {
+		case <-o.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	for _, row := range o.rows {
+		if row["id"] == id {
+			return row, nil
+		}
+	}
+	return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+}
+
+func (o *tableOps) List(_ context.Context, _ string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	data := o.rows
+	if limit := int(params.Pagination.GetLimit()); limit > 0 && len(data) > limit {
+		data = data[:limit]
+	}
+	return &interfaces.ListResult{Data: data, Total: int32(len(o.rows))}, nil
+}
+
+func (o *tableOps) Count(context.Context, string, *interfaces.ListParams) (int64, error) {
+	return int64(len(o.rows)), nil
+}
+
+func (o *tableOps) Update(_ context.Context, _ string, id string, data map[string]any) (map[string]any, error) {
+	return data, nil
+}
+
+type mismatches struct {
+	mu  sync.Mutex
+	got []Mismatch
+}
+
+func (m *mismatches) record(x Mismatch) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.got = append(m.got, x)
+}
+
+func newShadow(primary, candidate *tableOps, policy registry.ShadowPolicy) (*ShadowOperations, *mismatches) {
+	if policy.SampleRate == 0 {
+		policy.SampleRate = 1
+	}
+	policy.Candidate = "postgresql"
+	s := NewShadowOperationsWithCandidate(primary, "firestore", candidate, policy)
+	m := &mismatches{}
+	s.OnMismatch = m.record
+	return s, m
+}
+
+func TestShadowOperations_Read(t *testing.T) {
+	ctx := context.Background()
+	primary := &tableOps{rows: []map[string]any{
+		{"id": "c1", "name": "Ana", "credit_limit": int64(500), "date_modified": "2026-01-01T00:00:00Z"},
+		{"id": "c2", "name": "Ben", "notes": ""},
+		{"id": "c3", "name": "Cy"},
+	}}
+	candidate := &tableOps{rows: []map[string]any{
+		{"id": "c1", "name": "Ana", "credit_limit": float64(500), "date_modified": "2026-01-01 00:00:00"},
+		{"id": "c2", "name": "Benjamin"},
+	}}
+	s, m := newShadow(primary, candidate, registry.ShadowPolicy{IgnoreFields: map[string]bool{"date_modified": true}})
+
+	for _, id := range []string{"c1", "c2", "c3", "c4"} {
+		row, err := s.Read(ctx, "client", id)
+		if id != "c4" && (err != nil || row["id"] != id) {
+			t.Fatalf("read %s = %v, %v", id, row, err)
+		}
+	}
+	s.Wait()
+	if len(m.got) != 2 {
+		t.Fatalf("mismatches = %+v, want c2 and c3", m.got)
+	}
+	var all []string
+	for _, x := range m.got {
+		all = append(all, x.Differences...)
+	}
+	joined := strings.Join(all, "; ")
+	if !strings.Contains(joined, "c2 (name)") || !strings.Contains(joined, "missing from candidate: c3") {
+		t.Errorf("differences = %s", joined)
+	}
+	if m.got[0].Primary != "firestore" || m.got[0].Candidate != "postgresql" || m.got[0].Table != "client" {
+		t.Errorf("mismatch = %+v", m.got[0])
+	}
+}
+
+func TestShadowOperations_List(t *testing.T) {
+	ctx := context.Background()
+	primary := &tableOps{rows: []map[string]any{{"id": "a"}, {"id": "b"}, {"id": "c"}}}
+	candidate := &tableOps{rows: []map[string]any{{"id": "b"}, {"id": "a"}, {"id": "d"}}}
+	s, m := newShadow(primary, candidate, registry.ShadowPolicy{})
+
+	// A sorted list compares membership and order.
+	sorted := &interfaces.ListParams{Sort: &commonpb.SortRequest{Fields: []*commonpb.SortField{{Field: "id"}}}}
+	if _, err := s.List(ctx, "client", sorted); err != nil {
+		t.Fatal(err)
+	}
+	s.Wait()
+	if len(m.got) != 1 || strings.Join(m.got[0].Differences, "; ") != "missing from candidate: c; only in candidate: d" {
+		t.Fatalf("sorted mismatches = %+v", m.got)
+	}
+
+	// An unsorted full page only compares the rows both sides returned.
+	m.got = nil
+	page := &interfaces.ListParams{Pagination: &commonpb.PaginationRequest{Limit: 2}}
+	if _, err := s.List(ctx, "client", page); err != nil {
+		t.Fatal(err)
+	}
+	s.Wait()
+	if len(m.got) != 0 {
+		t.Fatalf("unsorted page mismatches = %+v", m.got)
+	}
+
+	if n, _ := s.Count(ctx, "client", nil); n != 3 {
+		t.Fatalf("count = %d", n)
+	}
+	s.Wait()
+	if len(m.got) != 0 {
+		t.Fatalf("count mismatches = %+v", m.got)
+	}
+}
+
+func TestShadowOperations_Skips(t *testing.T) {
+	primary := &tableOps{rows: []map[string]any{{"id": "c1"}}}
+	candidate := &tableOps{rows: []map[string]any{{"id": "c1"}}}
+	s, _ := newShadow(primary, candidate, registry.ShadowPolicy{Tables: map[string]bool{"client": true}})
+	ctx := context.Background()
+
+	_, _ = s.Read(ctx, "invoice", "c1")
+	_, _ = s.Read(operations.WithPrimaryRead(ctx), "client", "c1")
+	_, _ = s.Read(context.WithValue(ctx, shadowContextKey{}, true), "client", "c1")
+	_, _ = s.Update(ctx, "client", "c1", map[string]any{"name": "x"})
+	s.Wait()
+	if candidate.reads != 0 {
+		t.Fatalf("candidate read %d times, want 0", candidate.reads)
+	}
+
+	s.sample = func() float64 { return 0.9 }
+	s.policy.SampleRate = 0.5
+	_, _ = s.Read(ctx, "client", "c1")
+	s.sample = func() float64 { return 0.1 }
+	_, _ = s.Read(ctx, "client", "c1")
+	s.Wait()
+	if candidate.reads != 1 {
+		t.Fatalf("sampled reads = %d, want 1", candidate.reads)
+	}
+
+	// Nothing is published in the registry: a default decorator passes
+	// through.
+	if _, err := NewShadowOperations(primary, "firestore").Read(ctx, "client", "c1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShadowOperations_BoundsInFlight(t *testing.T) {
+	primary := &tableOps{rows: []map[string]any{{"id": "c1"}}}
+	candidate := &tableOps{rows: []map[string]any{{"id": "c1"}}, block: make(chan struct{})}
+	s, _ := newShadow(primary, candidate, registry.ShadowPolicy{MaxInFlight: 1, Timeout: time.Second})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := s.Read(ctx, "client", "c1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("reads waited %s on the candidate", elapsed)
+	}
+	close(candidate.block)
+	s.Wait()
+	if candidate.reads != 1 {
+		t.Fatalf("candidate reads = %d, want 1 with the rest skipped", candidate.reads)
+	}
+}
@@ -0,0 +1,317 @@
+// Package tenancy provides a DatabaseOperation decorator that confines every
+// read and write of a tenant table to the workspace of the request identity,
+// for backends without a schema to tell tenant tables apart (Firestore). SQL
+// adapters have their own workspace layer, which finds tenant tables by
+// their workspace_id column.
+//
+// Within a workspace, lists, counts and queries get a workspace_id filter,
+// creates are stamped with the workspace, and reads, updates and deletes by
+// id fail with not found for a row of another workspace or of none. Calls
+// without a request identity (service-to-service work, migrations) and
+// calls on the policy's global tables pass through.
+package tenancy
+
+import (
+	"context"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	"github.com/erniealice/espyna-golang/shared/identity"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// WorkspaceField holds the workspace a row belongs to.
+const WorkspaceField = "workspace_id"
+
+// TenancyOperations wraps a DatabaseOperation and scopes the tables the
+// tenancy policy scopes to the request's workspace.
+//
+// Place the decorator above the cache, shadow and history decorators, so
+// the filters it adds key cached results and revisions are written for
+// rows it already checked.
+type TenancyOperations struct {
+	inner interfaces.DatabaseOperation
+
+	// policy is fixed when set; otherwise the registry's default tenancy is
+	// read on every call.
+	policy *registry.TenancyPolicy
+}
+
+// Ensure TenancyOperations satisfies the full DatabaseOperation interface
+// at compile time.
+var _ interfaces.DatabaseOperation = (*TenancyOperations)(nil)
+
+// NewTenancyOperations wraps inner with the policy published through
+// registry.SetDefaultTenancy. Until an enabled one is published every call
+// passes straight through.
+func NewTenancyOperations(inner interfaces.DatabaseOperation) *TenancyOperations {
+	return &TenancyOperations{inner: inner}
+}
+
+// NewTenancyOperationsWithPolicy wraps inner with a specific policy.
+func NewTenancyOperationsWithPolicy(inner interfaces.DatabaseOperation, policy registry.TenancyPolicy) *TenancyOperations {
+	return &TenancyOperations{inner: inner, policy: &policy}
+}
+
+// Inner returns the wrapped operations.
+func (t *TenancyOperations) Inner() interfaces.DatabaseOperation {
+	return t.inner
+}
+
+// ── Reads ────────────────────────────────────────────────────────────────────
+
+// Read returns the row when it belongs to the request's workspace, and a
+// not-found error otherwise.
+func (t *TenancyOperations) Read(ctx context.Context, tableName string, id string) (map[string]any, error) {
+	row, err := t.inner.Read(ctx, tableName, id)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceID, ok := t.scope(ctx, tableName); ok && !owned(row, workspaceID) {
+		return nil, errNotFound
+	}
+	return row, nil
+}
+
+// List filters by the request's workspace.
+func (t *TenancyOperations) List(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	if workspaceID, ok := t.scope(ctx, tableName); ok {
+		params = withWorkspaceFilter(params, workspaceID)
+	}
+	return t.inner.List(ctx, tableName, params)
+}
+
+// ListDeleted filters by the request's workspace.
+func (t *TenancyOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	if workspaceID, ok := t.scope(ctx, tableName); ok {
+		params = withWorkspaceFilter(params, workspaceID)
+	}
+	return t.inner.ListDeleted(ctx, tableName, params)
+}
+
+// Count filters by the request's workspace.
+func (t *TenancyOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (int64, error) {
+	if workspaceID, ok := t.scope(ctx, tableName); ok {
+		params = withWorkspaceFilter(params, workspaceID)
+	}
+	return t.inner.Count(ctx, tableName, params)
+}
+
+// Query adds a workspace_id condition.
+func (t *TenancyOperations) Query(ctx context.Context, tableName string, query interfaces.QueryBuilder) ([]map[string]any, error) {
+	if workspaceID, ok := t.scope(ctx, tableName); ok {
+		query = ScopeQuery(query, workspaceID)
+	}
+	return t.inner.Query(ctx, tableName, query)
+}
+
+// QueryOne adds a workspace_id condition.
+func (t *TenancyOperations) QueryOne(ctx context.Context, tableName string, query interfaces.QueryBuilder) (map[string]any, error) {
+	if workspaceID, ok := t.scope(ctx, tableName); ok {
+		query = ScopeQuery(query, workspaceID)
+	}
+	return t.inner.QueryOne(ctx, tableName, query)
+}
+
+// ── Writes ───────────────────────────────────────────────────────────────────
+
+// Create stamps the row with the request's workspace.
+func (t *TenancyOperations) Create(ctx context.Context, tableName string, data map[string]any) (map[string]any, error) {
+	if workspaceID, ok := t.scope(ctx, tableName); ok {
+		data = stamp(data, workspaceID)
+	}
+	return t.inner.Create(ctx, tableName, data)
+}
+
+// CreateMany stamps every row with the request's workspace.
+func (t *TenancyOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	return t.inner.CreateMany(ctx, tableName, t.Stamp(ctx, tableName, data))
+}
+
+// Update checks the row belongs to the request's workspace and keeps its
+// workspace_id from changing.
+func (t *TenancyOperations) Update(ctx context.Context, tableName string, id string, data map[string]any) (map[string]any, error) {
+	if _, ok := t.scope(ctx, tableName); ok {
+		if _, err := t.Read(ctx, tableName, id); err != nil {
+			return nil, err
+		}
+		data = withoutWorkspace(data)
+	}
+	return t.inner.Update(ctx, tableName, id, data)
+}
+
+// UpdateMany checks every row belongs to the request's workspace and keeps
+// their workspace_id from changing.
+func (t *TenancyOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	if _, ok := t.scope(ctx, tableName); ok {
+		scoped := make([]interfaces.BatchUpdate, len(updates))
+		for i, u := range updates {
+			if _, err := t.Read(ctx, tableName, u.ID); err != nil {
+				return nil, err
+			}
+			scoped[i] = interfaces.BatchUpdate{ID: u.ID, Data: withoutWorkspace(u.Data)}
+		}
+		updates = scoped
+	}
+	return t.inner.UpdateMany(ctx, tableName, updates)
+}
+
+// Delete checks the row belongs to the request's workspace.
+func (t *TenancyOperations) Delete(ctx context.Context, tableName string, id string) error {
+	if err := t.check(ctx, tableName, id); err != nil {
+		return err
+	}
+	return t.inner.Delete(ctx, tableName, id)
+}
+
+// DeleteMany checks every row belongs to the request's workspace.
+func (t *TenancyOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	for _, id := range ids {
+		if err := t.check(ctx, tableName, id); err != nil {
+			return err
+		}
+	}
+	return t.inner.DeleteMany(ctx, tableName, ids)
+}
+
+// Restore checks the row belongs to the request's workspace.
+func (t *TenancyOperations) Restore(ctx context.Context, tableName string, id string) error {
+	if err := t.check(ctx, tableName, id); err != nil {
+		return err
+	}
+	return t.inner.Restore(ctx, tableName, id)
+}
+
+// HardDelete checks the row belongs to the request's workspace.
+func (t *TenancyOperations) HardDelete(ctx context.Context, tableName string, id string) error {
+	if err := t.check(ctx, tableName, id); err != nil {
+		return err
+	}
+	return t.inner.HardDelete(ctx, tableName, id)
+}
+
+// Stamp returns rows stamped with the request's workspace when tableName is
+// scoped, for backends' own write paths such as CreateAtomic. The caller's
+// rows are not modified.
+func (t *TenancyOperations) Stamp(ctx context.Context, tableName string, rows []map[string]any) []map[string]any {
+	workspaceID, ok := t.scope(ctx, tableName)
+	if !ok {
+		return rows
+	}
+	stamped := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		stamped[i] = stamp(row, workspaceID)
+	}
+	return stamped
+}
+
+// ── Helpers ──────────────────────────────────────────────────────────────────
+
+var errNotFound = model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+
+// scope returns the request's workspace when calls on tableName are
+// confined to it.
+func (t *TenancyOperations) scope(ctx context.Context, tableName string) (string, bool) {
+	policy := registry.GetDefaultTenancy()
+	if t.policy != nil {
+		policy = *t.policy
+	}
+	if !policy.Scopes(tableName) {
+		return "", false
+	}
+	id, ok := identity.FromContext(ctx)
+	if !ok || id.WorkspaceID == "" {
+		return "", false
+	}
+	return id.WorkspaceID, true
+}
+
+// check verifies the row belongs to the request's workspace.
+func (t *TenancyOperations) check(ctx context.Context, tableName, id string) error {
+	if _, ok := t.scope(ctx, tableName); !ok {
+		return nil
+	}
+	_, err := t.Read(ctx, tableName, id)
+	return err
+}
+
+// owned reports whether row belongs to workspaceID. Rows without a
+// workspace belong to none.
+func owned(row map[string]any, workspaceID string) bool {
+	rowWorkspace, _ := row[WorkspaceField].(string)
+	return rowWorkspace == workspaceID
+}
+
+func stamp(row map[string]any, workspaceID string) map[string]any {
+	stamped := make(map[string]any, len(row)+1)
+	for k, v := range row {
+		stamped[k] = v
+	}
+	stamped[WorkspaceField] = workspaceID
+	return stamped
+}
+
+func withoutWorkspace(data map[string]any) map[string]any {
+	if _, ok := data[WorkspaceField]; !ok {
+		return data
+	}
+	stripped := make(map[string]any, len(data))
+	for k, v := range data {
+		if k != WorkspaceField {
+			stripped[k] = v
+		}
+	}
+	return stripped
+}
+
+// withWorkspaceFilter returns a copy of params with a workspace_id filter
+// prepended; params itself is not modified.
+func withWorkspaceFilter(params *interfaces.ListParams, workspaceID string) *interfaces.ListParams {
+	filter := &commonpb.TypedFilter{
+		Field: WorkspaceField,
+		FilterType: &commonpb.TypedFilter_StringFilter{
+			StringFilter: &commonpb.StringFilter{
+				Value:         workspaceID,
+				Operator:      commonpb.StringOperator_STRING_EQUALS,
+				CaseSensitive: true,
+			},
+		},
+	}
+	var scoped interfaces.ListParams
+	if params != nil {
+		scoped = *params
+	}
+	filters := []*commonpb.TypedFilter{filter}
+	var logic commonpb.FilterLogic
+	if scoped.Filters != nil {
+		filters = append(filters, scoped.Filters.Filters...)
+		logic = scoped.Filters.Logic
+	}
+	scoped.Filters = &commonpb.FilterRequest{Filters: filters, Logic: logic}
+	return &scoped
+}
+
+// ScopeQuery returns query with a workspace_id condition added when built.
+// The caller's builder is left as it was.
+func ScopeQuery(query interfaces.QueryBuilder, workspaceID string) interfaces.QueryBuilder {
+	return scopedQuery{QueryBuilder: query, workspaceID: workspaceID}
+}
+
+// scopedQuery adds a workspace_id condition to the query it wraps when
+// built.
+type scopedQuery struct {
+	interfaces.QueryBuilder
+	workspaceID string
+}
+
+func (q scopedQuery) Build() (interfaces.QueryFilter, error) {
+	filter, err := q.QueryBuilder.Build()
+	if err != nil {
+		return filter, err
+	}
+	conditions := make([]interfaces.QueryCondition, 0, len(filter.Conditions)+1)
+	conditions = append(conditions, filter.Conditions...)
+	filter.Conditions = append(conditions, interfaces.QueryCondition{Field: WorkspaceField, Operator: "==", Value: q.workspaceID})
+	return filter, nil
+}
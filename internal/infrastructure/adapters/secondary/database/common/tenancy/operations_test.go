@@ -0,0 +1,205 @@
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// memOps keeps rows per table; List honours string equality filters and
+// Query == conditions, like a backend would.
+type memOps struct {
+	interfaces.DatabaseOperation
+	tables map[string]map[string]map[string]any
+}
+
+func newOps() *memOps {
+	return &memOps{tables: map[string]map[string]map[string]any{}}
+}
+
+func (o *memOps) Create(_ context.Context, table string, data map[string]any) (map[string]any, error) {
+	if o.tables[table] == nil {
+		o.tables[table] = map[string]map[string]any{}
+	}
+	row := map[string]any{}
+	for k, v := range data {
+		row[k] = v
+	}
+	o.tables[table][data["id"].(string)] = row
+	return row, nil
+}
+
+func (o *memOps) Read(_ context.Context, table string, id string) (map[string]any, error) {
+	row, ok := o.tables[table][id]
+	if !ok {
+		return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return row, nil
+}
+
+func (o *memOps) Update(ctx context.Context, table string, id string, data map[string]any) (map[string]any, error) {
+	row, err := o.Read(ctx, table, id)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range data {
+		row[k] = v
+	}
+	return row, nil
+}
+
+func (o *memOps) Delete(ctx context.Context, table string, id string) error {
+	_, err := o.Update(ctx, table, id, map[string]any{"active": false})
+	return err
+}
+
+func (o *memOps) List(_ context.Context, table string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	var rows []map[string]any
+	for _, row := range o.tables[table] {
+		match := true
+		if params != nil && params.Filters != nil {
+			for _, f := range params.Filters.Filters {
+				if row[f.Field] != f.GetStringFilter().GetValue() {
+					match = false
+				}
+			}
+		}
+		if match {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i]["id"].(string) < rows[j]["id"].(string) })
+	return &interfaces.ListResult{Data: rows}, nil
+}
+
+func (o *memOps) Query(_ context.Context, table string, query interfaces.QueryBuilder) ([]map[string]any, error) {
+	filter, err := query.Build()
+	if err != nil {
+		return nil, err
+	}
+	var rows []map[string]any
+	for _, row := range o.tables[table] {
+		match := true
+		for _, c := range filter.Conditions {
+			if fmt.Sprint(row[c.Field]) != fmt.Sprint(c.Value) {
+				match = false
+			}
+		}
+		if match {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func in(workspaceID string) context.Context {
+	return identity.WithRequestIdentity(context.Background(), &identity.RequestIdentity{UserID: "u-1", WorkspaceID: workspaceID})
+}
+
+func ids(rows []map[string]any) string {
+	var out []string
+	for _, row := range rows {
+		out = append(out, row["id"].(string))
+	}
+	sort.Strings(out)
+	return fmt.Sprint(out)
+}
+
+func isNotFound(err error) bool {
+	var dbErr *model.DatabaseError
+	return errors.As(err, &dbErr) && dbErr.HTTPStatus == 404
+}
+
+func TestTenancy_IsolatesWorkspaces(t *testing.T) {
+	ops := newOps()
+	guard := NewTenancyOperationsWithPolicy(ops, registry.TenancyPolicy{Enabled: true})
+	ws1, ws2 := in("ws-1"), in("ws-2")
+
+	if _, err := guard.Create(ws1, "client", map[string]any{"id": "c1", "name": "Ada", "workspace_id": "ws-2"}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = guard.Create(ws2, "client", map[string]any{"id": "c2", "name": "Bob"})
+	if got := ops.tables["client"]["c1"]["workspace_id"]; got != "ws-1" {
+		t.Fatalf("c1 stamped with %v, want the request's workspace", got)
+	}
+
+	page, err := guard.List(ws1, "client", nil)
+	if err != nil || ids(page.Data) != "[c1]" {
+		t.Errorf("ws-1 list = %v, %v", page, err)
+	}
+	rows, _ := guard.Query(ws2, "client", interfaces.NewQueryBuilder().WhereEqualTo("name", "Ada"))
+	if ids(rows) != "[]" {
+		t.Errorf("ws-2 query for a ws-1 row = %v", ids(rows))
+	}
+	if rows, _ := guard.Query(ws1, "client", interfaces.NewQueryBuilder().WhereEqualTo("name", "Ada")); ids(rows) != "[c1]" {
+		t.Errorf("ws-1 query = %v", ids(rows))
+	}
+
+	if _, err := guard.Read(ws1, "client", "c2"); !isNotFound(err) {
+		t.Errorf("read across workspaces: %v", err)
+	}
+	if _, err := guard.Update(ws1, "client", "c2", map[string]any{"name": "taken"}); !isNotFound(err) {
+		t.Errorf("update across workspaces: %v", err)
+	}
+	if err := guard.Delete(ws1, "client", "c2"); !isNotFound(err) {
+		t.Errorf("delete across workspaces: %v", err)
+	}
+	if ops.tables["client"]["c2"]["name"] != "Bob" || ops.tables["client"]["c2"]["active"] != nil {
+		t.Errorf("c2 changed from another workspace: %v", ops.tables["client"]["c2"])
+	}
+
+	if _, err := guard.Update(ws1, "client", "c1", map[string]any{"name": "Ada L.", "workspace_id": "ws-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if row := ops.tables["client"]["c1"]; row["name"] != "Ada L." || row["workspace_id"] != "ws-1" {
+		t.Errorf("update moved the row: %v", row)
+	}
+
+	// A row written without a workspace belongs to none.
+	ops.tables["client"]["c3"] = map[string]any{"id": "c3"}
+	if _, err := guard.Read(ws1, "client", "c3"); !isNotFound(err) {
+		t.Errorf("read of a row without workspace: %v", err)
+	}
+}
+
+func TestTenancy_QueryLeavesBuilderAlone(t *testing.T) {
+	query := interfaces.NewQueryBuilder().WhereEqualTo("name", "Ada")
+	scoped := ScopeQuery(query, "ws-1")
+	filter, _ := scoped.Build()
+	if len(filter.Conditions) != 2 || filter.Conditions[1].Field != WorkspaceField {
+		t.Errorf("scoped conditions = %v", filter.Conditions)
+	}
+	if original, _ := query.Build(); len(original.Conditions) != 1 {
+		t.Errorf("caller's builder gained %v", original.Conditions)
+	}
+}
+
+func TestTenancy_PassesThrough(t *testing.T) {
+	ops := newOps()
+	ops.tables["client"] = map[string]map[string]any{"c2": {"id": "c2", "workspace_id": "ws-2"}}
+	ops.tables["user"] = map[string]map[string]any{"u2": {"id": "u2"}}
+	ops.tables["currency"] = map[string]map[string]any{"php": {"id": "php"}}
+
+	guard := NewTenancyOperationsWithPolicy(ops, registry.TenancyPolicy{Enabled: true, GlobalTables: map[string]bool{"currency": true}})
+	if _, err := guard.Read(in("ws-1"), "user", "u2"); err != nil {
+		t.Errorf("default global table: %v", err)
+	}
+	if _, err := guard.Read(in("ws-1"), "currency", "php"); err != nil {
+		t.Errorf("configured global table: %v", err)
+	}
+	if _, err := guard.Read(context.Background(), "client", "c2"); err != nil {
+		t.Errorf("call without identity: %v", err)
+	}
+
+	off := NewTenancyOperationsWithPolicy(ops, registry.TenancyPolicy{})
+	if _, err := off.Read(in("ws-1"), "client", "c2"); err != nil {
+		t.Errorf("disabled policy: %v", err)
+	}
+}
@@ -109,8 +109,29 @@ func (m *MockOperations) HardDelete(ctx context.Context, tableName string, id st
 	return m.Delete(ctx, tableName, id)
 }
 
+// Restore sets active back to true on a record (records removed by Delete
+// are gone for good in the mock store)
+func (m *MockOperations) Restore(ctx context.Context, tableName string, id string) error {
+	_, err := m.Update(ctx, tableName, id, map[string]any{interfaces.ActiveField: true})
+	return err
+}
+
 // List retrieves all records from a table in the mock data store with pagination support
 func (m *MockOperations) List(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return m.list(tableName, params, nil)
+}
+
+// ListDeleted lists the records whose active field is false, paginated like
+// List
+func (m *MockOperations) ListDeleted(ctx context.Context, tableName string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return m.list(tableName, params, func(record map[string]any) bool {
+		active, ok := record[interfaces.ActiveField].(bool)
+		return ok && !active
+	})
+}
+
+// list pages the table's records that keep accepts (all when nil)
+func (m *MockOperations) list(tableName string, params *interfaces.ListParams, keep func(map[string]any) bool) (*interfaces.ListResult, error) {
 	businessType := "default"
 	var results []map[string]any
 
@@ -118,6 +139,9 @@ func (m *MockOperations) List(ctx context.Context, tableName string, params *int
 	if table, exists := m.data[businessType][tableName]; exists {
 		for _, record := range table {
 			if recordMap, ok := record.(map[string]any); ok {
+				if keep != nil && !keep(recordMap) {
+					continue
+				}
 				// Basic filtering support (simplified for mock)
 				// In a full implementation, we would parse the TypedFilter oneof fields
 				// For mock purposes, we skip complex filtering and return all records
@@ -140,6 +164,32 @@ func (m *MockOperations) List(ctx context.Context, tableName string, params *int
 		HasPrev:    false,
 	}
 
+	// Cursor pagination orders by (date_created, id) like the real backends
+	if token, ok := interfaces.CursorRequest(params); ok {
+		descending, err := interfaces.CursorDescending(params)
+		if err != nil {
+			return nil, model.NewDatabaseError(err.Error(), "INVALID_CURSOR_SORT", 400)
+		}
+		page, more, err := interfaces.PageByCursor(results, token, int(params.Pagination.Limit), descending)
+		if err != nil {
+			return nil, model.NewDatabaseError(err.Error(), "INVALID_CURSOR", 400)
+		}
+		var last map[string]any
+		if len(page) > 0 {
+			last = page[len(page)-1]
+		}
+		paginationResponse, next, err := interfaces.CursorPagination(last, token, more, total)
+		if err != nil {
+			return nil, model.NewDatabaseError(err.Error(), "MOCK_LIST_FAILED", 500)
+		}
+		return &interfaces.ListResult{
+			Data:       page,
+			Pagination: paginationResponse,
+			Total:      total,
+			NextCursor: next,
+		}, nil
+	}
+
 	// Apply pagination if provided
 	if params != nil && params.Pagination != nil {
 		limit := params.Pagination.Limit
@@ -191,6 +241,11 @@ func (m *MockOperations) List(ctx context.Context, tableName string, params *int
 	}, nil
 }
 
+// Count counts through List (see interfaces.CountFromList).
+func (m *MockOperations) Count(ctx context.Context, tableName string, params *interfaces.ListParams) (int64, error) {
+	return interfaces.CountFromList(ctx, m, tableName, params)
+}
+
 // Query is a simplified implementation for mock operations
 func (m *MockOperations) Query(ctx context.Context, tableName string, queryBuilder interfaces.QueryBuilder) ([]map[string]any, error) {
 	// This is a simplified implementation and does not support complex queries
@@ -213,3 +268,18 @@ func (m *MockOperations) QueryOne(ctx context.Context, tableName string, queryBu
 	}
 	return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
 }
+
+// CreateMany creates each row in turn. The mock store has no transactions; a failing row leaves earlier rows in place.
+func (m *MockOperations) CreateMany(ctx context.Context, tableName string, data []map[string]any) ([]map[string]any, error) {
+	return interfaces.CreateEach(ctx, m, tableName, data)
+}
+
+// UpdateMany updates each row in turn (see CreateMany).
+func (m *MockOperations) UpdateMany(ctx context.Context, tableName string, updates []interfaces.BatchUpdate) ([]map[string]any, error) {
+	return interfaces.UpdateEach(ctx, m, tableName, updates)
+}
+
+// DeleteMany deletes each row in turn (see CreateMany).
+func (m *MockOperations) DeleteMany(ctx context.Context, tableName string, ids []string) error {
+	return interfaces.DeleteEach(ctx, m, tableName, ids)
+}
@@ -0,0 +1,245 @@
+package eventlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// The tail endpoint of the signed-in user's current workspace:
+//
+//	GET TailPath?limit=100&min_status=400   the newest events, as JSON
+//	GET TailPath?follow=true                the newest events, then each new
+//	                                        one as it is logged, as
+//	                                        server-sent events
+//
+// Every event carries its cursor; after=<cursor> returns the events logged
+// since, oldest first. A follower that reconnects resumes from the
+// Last-Event-ID its browser sends, which may repeat the events of that
+// last cursor but never skips any. Reading the log needs api_log:list.
+const TailPath = "/api/logs/tail"
+
+// entityAPILog is the permission entity of the API event log.
+const entityAPILog = "api_log"
+
+const (
+	// pollInterval is how often a follower reads new events.
+	pollInterval = 2 * time.Second
+	// heartbeatInterval keeps idle follow streams open through proxies.
+	heartbeatInterval = 15 * time.Second
+)
+
+type eventJSON struct {
+	ID        string  `json:"id"`
+	Cursor    int64   `json:"cursor"`
+	Time      int64   `json:"time"`
+	ActorID   string  `json:"actor_id,omitempty"`
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	RequestID string  `json:"request_id,omitempty"`
+}
+
+// Handlers serves the tail endpoint for workspace admins.
+type Handlers struct {
+	log  *DatabaseLog
+	gate *actiongate.ActionGatekeeper
+	poll time.Duration
+}
+
+// NewHandlers creates the endpoint handlers over log. gate decides who may
+// read a workspace's log.
+func NewHandlers(log *DatabaseLog, gate *actiongate.ActionGatekeeper) *Handlers {
+	return &Handlers{log: log, gate: gate, poll: pollInterval}
+}
+
+// Tail returns or follows the current workspace's API events.
+func (h *Handlers) Tail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if contextutil.ExtractUserIDFromContext(ctx) == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return
+	}
+	workspaceID := contextutil.ExtractWorkspaceIDFromContext(ctx)
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "select a workspace first"})
+		return
+	}
+	if err := h.gate.Check(ctx, &actiongate.CheckActionRequest{Entity: entityAPILog, Action: entityid.ActionList}); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	q, follow, err := parseTail(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+
+	if follow {
+		h.follow(w, r, workspaceID, q)
+		return
+	}
+	events, err := h.log.Tail(ctx, workspaceID, q)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	data := make([]eventJSON, 0, len(events))
+	cursor := q.After
+	for _, e := range events {
+		data = append(data, toJSON(e))
+		cursor = max(cursor, e.LoggedAt)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": data, "cursor": cursor})
+}
+
+// follow streams the events as server-sent events until the client leaves.
+func (h *Handlers) follow(w http.ResponseWriter, r *http.Request, workspaceID string, q TailQuery) {
+	ctx := r.Context()
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		// The server cannot stream; the client sees an empty stream and
+		// falls back to polling the JSON tail.
+		return
+	}
+
+	stream := &followStream{w: w, cursor: q.After, seen: make(map[string]bool)}
+	if q.After == 0 {
+		stream.cursor = h.log.now().UnixMilli()
+	}
+	events, err := h.log.Tail(ctx, workspaceID, q)
+	if err != nil || stream.send(events) != nil || rc.Flush() != nil {
+		return
+	}
+
+	poll := time.NewTicker(h.poll)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-poll.C:
+			events, err := h.log.Tail(ctx, workspaceID, TailQuery{After: stream.cursor, MinStatus: q.MinStatus, Limit: maxTailLimit})
+			if err != nil {
+				if ctx.Err() == nil {
+					_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", jsonString(err.Error()))
+				}
+				return
+			}
+			if err := stream.send(events); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// followStream writes events past its cursor once each. seen holds the IDs
+// already sent at the cursor, which the next poll returns again.
+type followStream struct {
+	w      http.ResponseWriter
+	cursor int64
+	seen   map[string]bool
+}
+
+func (s *followStream) send(events []LoggedEvent) error {
+	for _, e := range events {
+		if e.LoggedAt < s.cursor || (e.LoggedAt == s.cursor && s.seen[e.ID]) {
+			continue
+		}
+		if e.LoggedAt > s.cursor {
+			s.cursor = e.LoggedAt
+			clear(s.seen)
+		}
+		s.seen[e.ID] = true
+		raw, err := json.Marshal(toJSON(e))
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(s.w, "id: %d\nevent: api_event\ndata: %s\n\n", e.LoggedAt, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseTail reads the tail query. A follower's Last-Event-ID stands in for
+// after.
+func parseTail(r *http.Request) (TailQuery, bool, error) {
+	values := r.URL.Query()
+	var q TailQuery
+	if v := values.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxTailLimit {
+			return q, false, fmt.Errorf("limit must be between 1 and %d", maxTailLimit)
+		}
+		q.Limit = n
+	}
+	if v := values.Get("min_status"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 100 || n > 599 {
+			return q, false, fmt.Errorf("min_status must be an HTTP status")
+		}
+		q.MinStatus = n
+	}
+	after := values.Get("after")
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		after = id
+	}
+	if after != "" {
+		n, err := strconv.ParseInt(after, 10, 64)
+		if err != nil || n < 0 {
+			return q, false, fmt.Errorf("after must be a cursor from an earlier event")
+		}
+		q.After = n
+	}
+	follow := values.Get("follow") == "true" || values.Get("follow") == "1" ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	return q, follow, nil
+}
+
+func toJSON(e LoggedEvent) eventJSON {
+	return eventJSON{
+		ID:        e.ID,
+		Cursor:    e.LoggedAt,
+		Time:      e.Time.UnixMilli(),
+		ActorID:   e.ActorID,
+		Method:    e.Method,
+		Path:      e.Path,
+		Status:    e.Status,
+		LatencyMS: float64(e.Latency.Microseconds()) / 1000,
+		RequestID: e.RequestID,
+	}
+}
+
+func jsonString(s string) string {
+	raw, _ := json.Marshal(s)
+	return string(raw)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,306 @@
+// Package eventlog keeps the per-workspace API event log: every request the
+// server adapters hand to shared/apilog, stored in a table through the
+// generic database operations for a retention window, and served to
+// workspace admins by the tail endpoint (see TailPath).
+package eventlog
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	"github.com/erniealice/espyna-golang/shared/apilog"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// DefaultTable is the table holding API events (see the postgres
+// integration migration 000010_api_event_log).
+const DefaultTable = "api_event_log"
+
+// DefaultRetention is how long events are kept when no retention is given.
+const DefaultRetention = 14 * 24 * time.Hour
+
+const (
+	// bufferSize bounds the events waiting for the writer. Requests never
+	// wait on the log; events arriving with the buffer full are dropped.
+	bufferSize = 4096
+	// flushInterval is how often the writer stores buffered events.
+	flushInterval = time.Second
+	// pruneInterval is how often the writer deletes expired events.
+	pruneInterval = time.Hour
+	// pruneBatch bounds the events one prune pass reads at a time.
+	pruneBatch = 500
+
+	defaultTailLimit = 100
+	maxTailLimit     = 500
+)
+
+// DatabaseLog stores API events in a table. It is an apilog.Sink: requests
+// only enqueue their event, and Run writes the queue in batches.
+type DatabaseLog struct {
+	ops       interfaces.DatabaseOperation
+	table     string
+	retention time.Duration
+	events    chan apilog.Event
+	dropped   atomic.Int64
+	now       func() time.Time
+}
+
+var _ apilog.Sink = (*DatabaseLog)(nil)
+
+// NewDatabaseLog creates a log on table (DefaultTable when empty) keeping
+// events for retention (DefaultRetention when 0).
+func NewDatabaseLog(ops interfaces.DatabaseOperation, table string, retention time.Duration) *DatabaseLog {
+	if table == "" {
+		table = DefaultTable
+	}
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &DatabaseLog{
+		ops:       ops,
+		table:     table,
+		retention: retention,
+		events:    make(chan apilog.Event, bufferSize),
+		now:       time.Now,
+	}
+}
+
+// RecordAPIEvent queues e for the writer, dropping it when the queue is full.
+func (l *DatabaseLog) RecordAPIEvent(e apilog.Event) {
+	select {
+	case l.events <- e:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Run writes queued events every second and deletes expired ones every
+// hour until ctx is done, then writes what is still queued.
+func (l *DatabaseLog) Run(ctx context.Context) {
+	flush := time.NewTicker(flushInterval)
+	defer flush.Stop()
+	prune := time.NewTicker(pruneInterval)
+	defer prune.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := l.Flush(context.WithoutCancel(ctx)); err != nil {
+				log.Printf("eventlog: final flush: %v", err)
+			}
+			return
+		case <-flush.C:
+			if err := l.Flush(ctx); err != nil {
+				log.Printf("eventlog: flush: %v", err)
+			}
+			if n := l.dropped.Swap(0); n > 0 {
+				log.Printf("eventlog: dropped %d event(s), the write queue was full", n)
+			}
+		case <-prune.C:
+			if n, err := l.Prune(ctx); err != nil {
+				log.Printf("eventlog: prune: %v", err)
+			} else if n > 0 {
+				log.Printf("eventlog: deleted %d event(s) older than %s", n, l.retention)
+			}
+		}
+	}
+}
+
+// Flush writes the queued events in one batch. Every maxTailLimit events
+// share a logged_at, the next ones take the following millisecond, so a
+// follower reading at most maxTailLimit events per cursor never stalls.
+func (l *DatabaseLog) Flush(ctx context.Context) error {
+	var rows []map[string]any
+	loggedAt := l.now().UnixMilli()
+drain:
+	for len(rows) < bufferSize {
+		select {
+		case e := <-l.events:
+			rows = append(rows, toRow(e, loggedAt+int64(len(rows)/maxTailLimit)))
+		default:
+			break drain
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	if _, err := l.ops.CreateMany(ctx, l.table, rows); err != nil {
+		return fmt.Errorf("store %d api events: %w", len(rows), err)
+	}
+	return nil
+}
+
+// TailQuery selects the events a tail returns.
+type TailQuery struct {
+	// After, when set, returns events logged at or after this cursor
+	// (epoch milliseconds), oldest first. Without it the tail returns the
+	// newest events, also oldest first.
+	After int64
+	// MinStatus keeps only responses with at least this status (400 for
+	// failures only).
+	MinStatus int
+	// Limit bounds the events returned (100 by default, at most 500).
+	Limit int
+}
+
+// Tail returns a workspace's events with their logged_at cursors.
+func (l *DatabaseLog) Tail(ctx context.Context, workspaceID string, q TailQuery) ([]LoggedEvent, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultTailLimit
+	}
+	limit = min(limit, maxTailLimit)
+
+	filters := []*commonpb.TypedFilter{stringEquals("workspace_id", workspaceID)}
+	if q.After > 0 {
+		filters = append(filters, numberFilter("logged_at", commonpb.NumberOperator_NUMBER_GREATER_THAN_OR_EQUAL, float64(q.After)))
+	}
+	if q.MinStatus > 0 {
+		filters = append(filters, numberFilter("status", commonpb.NumberOperator_NUMBER_GREATER_THAN_OR_EQUAL, float64(q.MinStatus)))
+	}
+	direction := commonpb.SortDirection_DESC
+	if q.After > 0 {
+		direction = commonpb.SortDirection_ASC
+	}
+
+	result, err := l.ops.List(ctx, l.table, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: filters},
+		Sort: &commonpb.SortRequest{
+			Fields: []*commonpb.SortField{
+				{Field: "logged_at", Direction: direction},
+				{Field: "occurred_at", Direction: direction},
+			},
+		},
+		Pagination: &commonpb.PaginationRequest{
+			Limit: int32(limit),
+			Method: &commonpb.PaginationRequest_Offset{
+				Offset: &commonpb.OffsetPagination{Page: 1},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list api events: %w", err)
+	}
+
+	events := []LoggedEvent{}
+	if result == nil {
+		return events, nil
+	}
+	for _, row := range result.Data {
+		// Guard against providers that ignore the filter.
+		if str(row["workspace_id"]) != workspaceID {
+			continue
+		}
+		events = append(events, fromRow(row))
+	}
+	if direction == commonpb.SortDirection_DESC {
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+	return events, nil
+}
+
+// Prune deletes events logged before the retention window and returns how
+// many it deleted.
+func (l *DatabaseLog) Prune(ctx context.Context) (int, error) {
+	cutoff := l.now().Add(-l.retention).UnixMilli()
+	deleted := 0
+	for {
+		result, err := l.ops.List(ctx, l.table, &interfaces.ListParams{
+			Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{
+				numberFilter("logged_at", commonpb.NumberOperator_NUMBER_LESS_THAN, float64(cutoff)),
+			}},
+			Pagination: &commonpb.PaginationRequest{
+				Limit: pruneBatch,
+				Method: &commonpb.PaginationRequest_Offset{
+					Offset: &commonpb.OffsetPagination{Page: 1},
+				},
+			},
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("list expired api events: %w", err)
+		}
+		if result == nil || len(result.Data) == 0 {
+			return deleted, nil
+		}
+		for _, row := range result.Data {
+			if err := l.ops.HardDelete(ctx, l.table, str(row["id"])); err != nil {
+				return deleted, fmt.Errorf("delete api event %s: %w", str(row["id"]), err)
+			}
+			deleted++
+		}
+		if len(result.Data) < pruneBatch {
+			return deleted, nil
+		}
+	}
+}
+
+// LoggedEvent is a stored event and the cursor it was logged under.
+type LoggedEvent struct {
+	apilog.Event
+	LoggedAt int64
+}
+
+func toRow(e apilog.Event, loggedAt int64) map[string]any {
+	return map[string]any{
+		"workspace_id": e.WorkspaceID,
+		"actor_id":     e.ActorID,
+		"method":       e.Method,
+		"path":         e.Path,
+		"status":       e.Status,
+		"latency_ms":   float64(e.Latency.Microseconds()) / 1000,
+		"request_id":   e.RequestID,
+		"occurred_at":  e.Time.UnixMilli(),
+		"logged_at":    loggedAt,
+	}
+}
+
+func fromRow(row map[string]any) LoggedEvent {
+	return LoggedEvent{
+		Event: apilog.Event{
+			ID:          str(row["id"]),
+			Time:        time.UnixMilli(int64(operations.Number(row["occurred_at"]))).UTC(),
+			WorkspaceID: str(row["workspace_id"]),
+			ActorID:     str(row["actor_id"]),
+			Method:      str(row["method"]),
+			Path:        str(row["path"]),
+			Status:      int(operations.Number(row["status"])),
+			Latency:     time.Duration(operations.Number(row["latency_ms"]) * float64(time.Millisecond)),
+			RequestID:   str(row["request_id"]),
+		},
+		LoggedAt: int64(operations.Number(row["logged_at"])),
+	}
+}
+
+func stringEquals(field, value string) *commonpb.TypedFilter {
+	return &commonpb.TypedFilter{
+		Field: field,
+		FilterType: &commonpb.TypedFilter_StringFilter{
+			StringFilter: &commonpb.StringFilter{
+				Value:         value,
+				Operator:      commonpb.StringOperator_STRING_EQUALS,
+				CaseSensitive: true,
+			},
+		},
+	}
+}
+
+func numberFilter(field string, op commonpb.NumberOperator, value float64) *commonpb.TypedFilter {
+	return &commonpb.TypedFilter{
+		Field: field,
+		FilterType: &commonpb.TypedFilter_NumberFilter{
+			NumberFilter: &commonpb.NumberFilter{Value: value, Operator: op},
+		},
+	}
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
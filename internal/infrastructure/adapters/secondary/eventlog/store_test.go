@@ -0,0 +1,240 @@
+package eventlog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	"github.com/erniealice/espyna-golang/shared/apilog"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// memOps stores rows in memory and honours the filters and sort the log
+// uses.
+type memOps struct {
+	interfaces.DatabaseOperation
+	rows   []map[string]any
+	nextID int
+}
+
+func (o *memOps) CreateMany(_ context.Context, _ string, rows []map[string]any) ([]map[string]any, error) {
+	for _, row := range rows {
+		o.nextID++
+		stored := map[string]any{"id": fmt.Sprintf("e%d", o.nextID)}
+		for field, value := range row {
+			stored[field] = value
+		}
+		o.rows = append(o.rows, stored)
+	}
+	return rows, nil
+}
+
+func (o *memOps) List(_ context.Context, _ string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	var data []map[string]any
+rows:
+	for _, row := range o.rows {
+		for _, f := range params.Filters.GetFilters() {
+			if s := f.GetStringFilter(); s != nil && str(row[f.Field]) != s.Value {
+				continue rows
+			}
+			if n := f.GetNumberFilter(); n != nil {
+				v := operations.Number(row[f.Field])
+				switch n.Operator {
+				case commonpb.NumberOperator_NUMBER_GREATER_THAN_OR_EQUAL:
+					if v < n.Value {
+						continue rows
+					}
+				case commonpb.NumberOperator_NUMBER_LESS_THAN:
+					if v >= n.Value {
+						continue rows
+					}
+				}
+			}
+		}
+		data = append(data, row)
+	}
+	fields := params.Sort.GetFields()
+	for i := len(fields) - 1; i >= 0; i-- {
+		s := fields[i]
+		desc := s.Direction == commonpb.SortDirection_DESC
+		sort.SliceStable(data, func(i, j int) bool {
+			if desc {
+				return operations.Number(data[i][s.Field]) > operations.Number(data[j][s.Field])
+			}
+			return operations.Number(data[i][s.Field]) < operations.Number(data[j][s.Field])
+		})
+	}
+	if limit := int(params.Pagination.GetLimit()); limit > 0 && len(data) > limit {
+		data = data[:limit]
+	}
+	return &interfaces.ListResult{Data: data}, nil
+}
+
+func (o *memOps) HardDelete(_ context.Context, _ string, id string) error {
+	for i, row := range o.rows {
+		if row["id"] == id {
+			o.rows = append(o.rows[:i], o.rows[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no row %s", id)
+}
+
+func newTestLog(now *time.Time) (*DatabaseLog, *memOps) {
+	ops := &memOps{}
+	l := NewDatabaseLog(ops, "", 0)
+	l.now = func() time.Time { return *now }
+	return l, ops
+}
+
+var eventClock = time.UnixMilli(1_600_000_000_000)
+
+func event(workspace, path string, status int) apilog.Event {
+	eventClock = eventClock.Add(time.Millisecond)
+	return apilog.Event{
+		Time:        eventClock,
+		WorkspaceID: workspace,
+		ActorID:     "user-1",
+		Method:      http.MethodGet,
+		Path:        path,
+		Status:      status,
+		Latency:     1500 * time.Microsecond,
+	}
+}
+
+func paths(events []LoggedEvent) string {
+	var out []string
+	for _, e := range events {
+		out = append(out, e.Path)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestDatabaseLog_Tail(t *testing.T) {
+	ctx := context.Background()
+	now := time.UnixMilli(1_700_000_000_000)
+	l, _ := newTestLog(&now)
+
+	l.RecordAPIEvent(event("ws-1", "/a", 200))
+	l.RecordAPIEvent(event("ws-2", "/other", 200))
+	l.RecordAPIEvent(event("ws-1", "/b", 404))
+	if err := l.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Second)
+	l.RecordAPIEvent(event("ws-1", "/c", 500))
+	if err := l.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := l.Tail(ctx, "ws-1", TailQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := paths(events); got != "/a,/b,/c" {
+		t.Fatalf("tail = %s, want /a,/b,/c", got)
+	}
+	if e := events[0]; e.ActorID != "user-1" || e.Latency != 1500*time.Microsecond || e.Status != 200 {
+		t.Errorf("event = %+v", e)
+	}
+
+	events, _ = l.Tail(ctx, "ws-1", TailQuery{Limit: 2})
+	if got := paths(events); got != "/b,/c" {
+		t.Errorf("newest 2 = %s, want /b,/c", got)
+	}
+	events, _ = l.Tail(ctx, "ws-1", TailQuery{MinStatus: 400})
+	if got := paths(events); got != "/b,/c" {
+		t.Errorf("failures = %s, want /b,/c", got)
+	}
+	events, _ = l.Tail(ctx, "ws-1", TailQuery{After: now.UnixMilli()})
+	if got := paths(events); got != "/c" {
+		t.Errorf("after cursor = %s, want /c", got)
+	}
+}
+
+func TestDatabaseLog_FlushSpreadsLargeBatches(t *testing.T) {
+	now := time.UnixMilli(1_700_000_000_000)
+	l, ops := newTestLog(&now)
+	for range maxTailLimit + 1 {
+		l.RecordAPIEvent(event("ws-1", "/a", 200))
+	}
+	if err := l.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	first, last := operations.Number(ops.rows[0]["logged_at"]), operations.Number(ops.rows[maxTailLimit]["logged_at"])
+	if last != first+1 {
+		t.Errorf("logged_at of event %d = %v, want %v", maxTailLimit+1, last, first+1)
+	}
+}
+
+func TestDatabaseLog_Prune(t *testing.T) {
+	ctx := context.Background()
+	now := time.UnixMilli(1_700_000_000_000)
+	l, ops := newTestLog(&now)
+
+	l.RecordAPIEvent(event("ws-1", "/old", 200))
+	_ = l.Flush(ctx)
+	now = now.Add(DefaultRetention)
+	l.RecordAPIEvent(event("ws-1", "/new", 200))
+	_ = l.Flush(ctx)
+	now = now.Add(time.Hour)
+
+	n, err := l.Prune(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("Prune = %d, %v; want 1", n, err)
+	}
+	if len(ops.rows) != 1 || ops.rows[0]["path"] != "/new" {
+		t.Errorf("rows after prune = %v", ops.rows)
+	}
+}
+
+func TestHandlers_Tail(t *testing.T) {
+	now := time.UnixMilli(1_700_000_000_000)
+	l, _ := newTestLog(&now)
+	l.RecordAPIEvent(event("ws-1", "/a", 200))
+	_ = l.Flush(context.Background())
+	h := NewHandlers(l, testutil.OpenActionGate())
+
+	serve := func(ctx context.Context, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Tail(rec, httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx))
+		return rec
+	}
+
+	if rec := serve(context.Background(), TailPath); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous status = %d, want 401", rec.Code)
+	}
+	ctx := contextutil.WithUserID(context.Background(), "user-1")
+	if rec := serve(ctx, TailPath); rec.Code != http.StatusBadRequest {
+		t.Errorf("no workspace status = %d, want 400", rec.Code)
+	}
+	ctx = contextutil.WithWorkspaceID(ctx, "ws-1")
+	if rec := serve(ctx, TailPath+"?limit=9999"); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad limit status = %d, want 400", rec.Code)
+	}
+	rec := serve(ctx, TailPath)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"path":"/a"`) {
+		t.Errorf("tail = %d %s", rec.Code, rec.Body)
+	}
+
+	// A follower gets the backlog as server-sent events and stops with the
+	// request.
+	followCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	rec = serve(followCtx, TailPath+"?follow=true&after=1")
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("follow content type = %q", got)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "event: api_event\n") || !strings.Contains(body, `"path":"/a"`) {
+		t.Errorf("follow body = %q", body)
+	}
+}
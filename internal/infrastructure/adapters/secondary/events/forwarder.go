@@ -0,0 +1,139 @@
+// Package events forwards the domain events use cases emit to an
+// EventPublisher, so a message broker such as Google Pub/Sub carries them
+// to other systems. The publishers live in the subpackages (mock) and in
+// contrib/google (pubsub).
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+)
+
+// Forwarder defaults.
+const (
+	DefaultQueueSize = 1024
+	DefaultTimeout   = 10 * time.Second
+)
+
+// Forwarder is a domainevent.Sink that publishes events from a single
+// background goroutine, in the order they were emitted. Use cases never
+// wait for the broker: when the queue is full the event is dropped and
+// counted.
+type Forwarder struct {
+	publisher ports.EventPublisher
+	timeout   time.Duration
+	queue     chan domainevent.Event
+	done      chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+
+	published atomic.Int64
+	dropped   atomic.Int64
+	failed    atomic.Int64
+}
+
+var _ domainevent.Sink = (*Forwarder)(nil)
+
+// NewForwarder starts forwarding to publisher. queueSize and timeout (the
+// bound on each Publish) fall back to the defaults when zero.
+func NewForwarder(publisher ports.EventPublisher, queueSize int, timeout time.Duration) *Forwarder {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	f := &Forwarder{
+		publisher: publisher,
+		timeout:   timeout,
+		queue:     make(chan domainevent.Event, queueSize),
+		done:      make(chan struct{}),
+	}
+	go f.run()
+	return f
+}
+
+// PublishDomainEvent queues e without blocking.
+func (f *Forwarder) PublishDomainEvent(e domainevent.Event) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.closed {
+		return
+	}
+	select {
+	case f.queue <- e:
+	default:
+		if f.dropped.Add(1)%100 == 1 {
+			log.Printf("events: queue full, dropped %s event %s (%d dropped so far)", e.Type, e.ID, f.dropped.Load())
+		}
+	}
+}
+
+func (f *Forwarder) run() {
+	defer close(f.done)
+	for e := range f.queue {
+		f.forward(e)
+	}
+}
+
+func (f *Forwarder) forward(e domainevent.Event) {
+	data, err := domainevent.Marshal(e)
+	if err != nil {
+		f.failed.Add(1)
+		log.Printf("events: encode %s event %s: %v", e.Type, e.ID, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+	err = f.publisher.Publish(ctx, ports.EventMessage{
+		ID:          e.ID,
+		Type:        e.Type,
+		WorkspaceID: e.WorkspaceID,
+		OccurredAt:  e.OccurredAt,
+		Data:        data,
+	})
+	if err != nil {
+		f.failed.Add(1)
+		log.Printf("events: publish %s event %s to %s: %v", e.Type, e.ID, f.publisher.Name(), err)
+		return
+	}
+	f.published.Add(1)
+}
+
+// Close stops accepting events and waits until the queued ones have been
+// published or ctx is done. It does not close the publisher.
+func (f *Forwarder) Close(ctx context.Context) error {
+	f.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		close(f.queue)
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats counts the events published, dropped on a full queue, and failed
+// to encode or publish.
+type Stats struct {
+	Published int64
+	Dropped   int64
+	Failed    int64
+}
+
+// Stats returns the counts since the forwarder started.
+func (f *Forwarder) Stats() Stats {
+	return Stats{Published: f.published.Load(), Dropped: f.dropped.Load(), Failed: f.failed.Load()}
+}
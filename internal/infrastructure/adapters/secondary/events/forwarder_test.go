@@ -0,0 +1,100 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/events/mock"
+)
+
+func TestForwarder_PublishesInOrder(t *testing.T) {
+	publisher := mock.NewPublisher()
+	var paid []string
+	publisher.Subscribe(domainevent.InvoicePaid, func(m ports.EventMessage) { paid = append(paid, m.ID) })
+
+	f := NewForwarder(publisher, 0, 0)
+	for _, e := range []domainevent.Event{
+		{ID: "evt_1", Type: domainevent.ClientCreated, WorkspaceID: "ws-1", Data: map[string]string{"id": "client-1"}},
+		{ID: "evt_2", Type: domainevent.InvoicePaid, WorkspaceID: "ws-1"},
+	} {
+		f.PublishDomainEvent(e)
+	}
+	if err := f.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	f.PublishDomainEvent(domainevent.Event{ID: "evt_3"}) // after Close: ignored
+
+	messages := publisher.Messages()
+	if len(messages) != 2 || messages[0].ID != "evt_1" || messages[1].ID != "evt_2" {
+		t.Fatalf("messages = %+v", messages)
+	}
+	var body struct {
+		Type string            `json:"type"`
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(messages[0].Data, &body); err != nil || body.Type != domainevent.ClientCreated || body.Data["id"] != "client-1" {
+		t.Errorf("body = %s (%v)", messages[0].Data, err)
+	}
+	if messages[0].WorkspaceID != "ws-1" || messages[0].Type != domainevent.ClientCreated {
+		t.Errorf("message = %+v", messages[0])
+	}
+	if len(paid) != 1 || paid[0] != "evt_2" {
+		t.Errorf("invoice.paid subscriber got %v", paid)
+	}
+	if s := f.Stats(); s.Published != 2 || s.Dropped != 0 || s.Failed != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestForwarder_CountsFailures(t *testing.T) {
+	publisher := mock.NewPublisher()
+	publisher.FailWith(errors.New("broker down"))
+
+	f := NewForwarder(publisher, 0, time.Second)
+	f.PublishDomainEvent(domainevent.Event{ID: "evt_1", Type: domainevent.ClientDeleted})
+	if err := f.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if s := f.Stats(); s.Failed != 1 || s.Published != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+// blocking signals started and holds every Publish until release is
+// closed.
+type blocking struct {
+	*mock.Publisher
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blocking) Publish(ctx context.Context, msg ports.EventMessage) error {
+	b.started <- struct{}{}
+	<-b.release
+	return b.Publisher.Publish(ctx, msg)
+}
+
+func TestForwarder_DropsWhenFull(t *testing.T) {
+	publisher := &blocking{Publisher: mock.NewPublisher(), started: make(chan struct{}, 2), release: make(chan struct{})}
+	f := NewForwarder(publisher, 1, 0)
+
+	// One event is taken by the worker, one waits in the queue, the rest
+	// are dropped; none of this blocks the caller.
+	f.PublishDomainEvent(domainevent.Event{ID: "evt", Type: domainevent.ClientUpdated})
+	<-publisher.started
+	for i := 0; i < 9; i++ {
+		f.PublishDomainEvent(domainevent.Event{ID: "evt", Type: domainevent.ClientUpdated})
+	}
+	close(publisher.release)
+	if err := f.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if s := f.Stats(); s.Published != 2 || s.Dropped != 8 {
+		t.Errorf("stats = %+v", s)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build mock_events
+
+package mock
+
+import (
+	"log"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+func init() {
+	registry.RegisterEventPublisherFactory("mock_events", func() ports.EventPublisher {
+		return NewPublisher()
+	})
+	registry.RegisterEventPublisherBuildFromEnv("mock_events", func() (ports.EventPublisher, error) {
+		return NewPublisher(), nil
+	})
+	log.Printf("[MockEvents] Registered with event publisher registry")
+}
@@ -0,0 +1,96 @@
+// Package mock provides an in-memory EventPublisher for tests and local
+// development. Registration as the "mock_events" provider lives in
+// adapter.go behind the mock_events build tag; the publisher itself is
+// always available so tests can construct it directly.
+package mock
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// ErrClosed is returned by Publish after Close.
+var ErrClosed = errors.New("mock_events: publisher is closed")
+
+// Publisher keeps every published message and hands it to the subscribers
+// of its type, synchronously on the publishing goroutine.
+type Publisher struct {
+	mu          sync.Mutex
+	messages    []ports.EventMessage
+	subscribers map[string][]func(ports.EventMessage)
+	failWith    error
+	closed      bool
+}
+
+var _ ports.EventPublisher = (*Publisher)(nil)
+
+// NewPublisher creates a publisher without messages or subscribers.
+func NewPublisher() *Publisher {
+	return &Publisher{subscribers: make(map[string][]func(ports.EventMessage))}
+}
+
+func (p *Publisher) Name() string {
+	return "mock_events"
+}
+
+func (p *Publisher) Publish(_ context.Context, msg ports.EventMessage) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrClosed
+	}
+	if p.failWith != nil {
+		err := p.failWith
+		p.mu.Unlock()
+		return err
+	}
+	msg.Data = append([]byte(nil), msg.Data...)
+	p.messages = append(p.messages, msg)
+	handlers := append(p.subscribers[msg.Type], p.subscribers[""]...)
+	p.mu.Unlock()
+
+	for _, handle := range handlers {
+		handle(msg)
+	}
+	return nil
+}
+
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+// Subscribe calls handle with every message of eventType published from
+// now on; an empty eventType receives all of them.
+func (p *Publisher) Subscribe(eventType string, handle func(ports.EventMessage)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subscribers[eventType] = append(p.subscribers[eventType], handle)
+}
+
+// Messages returns the messages published so far, oldest first.
+func (p *Publisher) Messages() []ports.EventMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]ports.EventMessage(nil), p.messages...)
+}
+
+// FailWith makes Publish return err until it is called again with nil,
+// for testing how callers handle an unavailable broker.
+func (p *Publisher) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failWith = err
+}
+
+// Reset drops the recorded messages; subscribers stay.
+func (p *Publisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = nil
+}
@@ -0,0 +1,150 @@
+// Package history serves the revision history the database history
+// decorator records: the revisions of a record, and the record as it stood
+// at a given revision or time.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	dbhistory "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/history"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// Path is an entity's history route, serving GET with ?id=: the record's
+// revisions, newest first. With &revision=N it returns that revision, and
+// with &at= an RFC 3339 time the revision current at that time. It needs
+// <entity>:read.
+func Path(entity string) string { return "/api/" + entity + "/history" }
+
+// Handlers serve Path.
+type Handlers struct {
+	ops           interfaces.DatabaseOperation
+	revisionTable string
+	gate          *actiongate.ActionGatekeeper
+}
+
+// NewHandlers creates the handlers reading the revisions ops stores in
+// revisionTable.
+func NewHandlers(ops interfaces.DatabaseOperation, revisionTable string, gate *actiongate.ActionGatekeeper) *Handlers {
+	return &Handlers{ops: ops, revisionTable: revisionTable, gate: gate}
+}
+
+// History returns the handler for the revisions of the entity's records in
+// table.
+func (h *Handlers) History(entity, table string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workspaceID, ok := h.authorize(w, r, entity)
+		if !ok {
+			return
+		}
+		query := r.URL.Query()
+		id := query.Get("id")
+		if id == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "id is required"})
+			return
+		}
+		revisionParam, atParam := query.Get("revision"), query.Get("at")
+		if revisionParam != "" && atParam != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "revision and at are exclusive"})
+			return
+		}
+
+		ctx := r.Context()
+		var revision *dbhistory.Revision
+		var err error
+		switch {
+		case revisionParam != "":
+			n, parseErr := strconv.ParseInt(revisionParam, 10, 64)
+			if parseErr != nil || n < 1 {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "revision must be a positive number"})
+				return
+			}
+			revision, err = dbhistory.ReadRevision(ctx, h.ops, h.revisionTable, table, id, n)
+		case atParam != "":
+			at, parseErr := time.Parse(time.RFC3339, atParam)
+			if parseErr != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "at must be an RFC 3339 time"})
+				return
+			}
+			revision, err = dbhistory.ReadAt(ctx, h.ops, h.revisionTable, table, id, at)
+		default:
+			revisions, err := dbhistory.ListRevisions(ctx, h.ops, h.revisionTable, table, id)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			if len(revisions) == 0 || !inWorkspace(revisions[0], workspaceID) {
+				writeError(w, errNotFound)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": revisions})
+			return
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if !inWorkspace(*revision, workspaceID) {
+			writeError(w, errNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": revision})
+	}
+}
+
+var errNotFound = model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+
+// inWorkspace reports whether the revision's record belongs to the
+// caller's workspace; revisions of tables without workspace_id belong to
+// every workspace.
+func inWorkspace(revision dbhistory.Revision, workspaceID string) bool {
+	return revision.WorkspaceID == "" || revision.WorkspaceID == workspaceID
+}
+
+// authorize checks the method, the caller and <entity>:read in the caller's
+// workspace, writing the error response when one fails.
+func (h *Handlers) authorize(w http.ResponseWriter, r *http.Request, entity string) (string, bool) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return "", false
+	}
+	ctx := r.Context()
+	if contextutil.ExtractUserIDFromContext(ctx) == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return "", false
+	}
+	workspaceID := contextutil.ExtractWorkspaceIDFromContext(ctx)
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "workspace required"})
+		return "", false
+	}
+	if err := h.gate.Check(ctx, &actiongate.CheckActionRequest{Entity: entity, Action: entityid.ActionRead}); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": err.Error()})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var dbErr *model.DatabaseError
+	if errors.As(err, &dbErr) && dbErr.HTTPStatus >= 400 {
+		writeJSON(w, dbErr.HTTPStatus, map[string]any{"success": false, "error": dbErr.Message})
+		return
+	}
+	writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
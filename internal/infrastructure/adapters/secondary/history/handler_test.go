@@ -0,0 +1,105 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+)
+
+// revisionOps serves fixed revision rows, newest first, matching the
+// entity_id and revision conditions.
+type revisionOps struct {
+	interfaces.DatabaseOperation
+	rows []map[string]any
+}
+
+func (o *revisionOps) Query(_ context.Context, _ string, query interfaces.QueryBuilder) ([]map[string]any, error) {
+	filter, err := query.Build()
+	if err != nil {
+		return nil, err
+	}
+	var rows []map[string]any
+	for _, row := range o.rows {
+		match := true
+		for _, c := range filter.Conditions {
+			if fmt.Sprint(row[c.Field]) != fmt.Sprint(c.Value) {
+				match = false
+			}
+		}
+		if match {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+var created = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func revisionRow(id, workspaceID string, n int64, name string) map[string]any {
+	return map[string]any{
+		"entity_type":  "client",
+		"entity_id":    id,
+		"workspace_id": workspaceID,
+		"revision":     n,
+		"action":       "update",
+		"snapshot":     `{"id":"` + id + `","name":"` + name + `"}`,
+		"changes":      `{}`,
+		"date_created": created.Add(time.Duration(n) * time.Hour),
+	}
+}
+
+func TestHistoryHandler(t *testing.T) {
+	ops := &revisionOps{rows: []map[string]any{
+		revisionRow("c1", "ws-1", 2, "v2"),
+		revisionRow("c1", "ws-1", 1, "v1"),
+		revisionRow("c2", "ws-2", 1, "other"),
+	}}
+	h := NewHandlers(ops, "entity_revision", testutil.OpenActionGate())
+	handler := h.History("client", "client")
+	userCtx := contextutil.WithWorkspaceID(contextutil.WithUserID(context.Background(), "u-1"), "ws-1")
+
+	call := func(query string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/api/client/history?"+query, nil).WithContext(userCtx))
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+	snapshotName := func(out map[string]any) any {
+		data, _ := out["data"].(map[string]any)
+		snapshot, _ := data["snapshot"].(map[string]any)
+		return snapshot["name"]
+	}
+
+	code, out := call("id=c1")
+	if list, _ := out["data"].([]any); code != http.StatusOK || len(list) != 2 {
+		t.Errorf("list = %d %v", code, out)
+	}
+	if code, out := call("id=c1&revision=1"); code != http.StatusOK || snapshotName(out) != "v1" {
+		t.Errorf("revision 1 = %d %v", code, out)
+	}
+	at := created.Add(90 * time.Minute).Format(time.RFC3339)
+	if code, out := call("id=c1&at=" + at); code != http.StatusOK || snapshotName(out) != "v1" {
+		t.Errorf("at %s = %d %v", at, code, out)
+	}
+	if code, _ := call("id=c2"); code != http.StatusNotFound {
+		t.Errorf("another workspace's record = %d", code)
+	}
+	if code, _ := call("id=c1&revision=0"); code != http.StatusBadRequest {
+		t.Errorf("revision 0 = %d", code)
+	}
+	if code, _ := call("id=c1&revision=1&at=" + at); code != http.StatusBadRequest {
+		t.Errorf("revision with at = %d", code)
+	}
+	if code, _ := call(""); code != http.StatusBadRequest {
+		t.Errorf("missing id = %d", code)
+	}
+}
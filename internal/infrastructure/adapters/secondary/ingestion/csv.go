@@ -0,0 +1,122 @@
+package ingestion
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// utf8BOM prefixes the CSV files Excel and many school systems export.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// parseCSV maps the rows of raw to fields following profile. Rows missing a
+// required field, or with a different column count than the header, are
+// returned as RowErrors; an error means the file as a whole is unusable.
+func parseCSV(profile *Profile, raw []byte) ([]map[string]any, []RowError, error) {
+	raw = bytes.TrimPrefix(raw, utf8BOM)
+	if !utf8.Valid(raw) {
+		return nil, nil, fmt.Errorf("file is not UTF-8 text")
+	}
+
+	reader := csv.NewReader(bytes.NewReader(raw))
+	if profile.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(profile.Delimiter)
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("read header: %w", err)
+	}
+
+	// fields[i] is the field column i maps to, "" when unmapped.
+	columns := make(map[string]string, len(profile.Columns))
+	for column, field := range profile.Columns {
+		columns[normalizeHeader(column)] = field
+	}
+	fields := make([]string, len(header))
+	present := make(map[string]bool, len(header))
+	for i, column := range header {
+		if field, ok := columns[normalizeHeader(column)]; ok {
+			fields[i] = field
+			present[field] = true
+		}
+	}
+	var missing []string
+	for _, field := range profile.Required {
+		if !present[field] {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("no column maps to required field(s) %s", strings.Join(missing, ", "))
+	}
+
+	var rows []map[string]any
+	var rowErrors []RowError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, err
+			}
+			rowErrors = append(rowErrors, RowError{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		if len(record) != len(header) {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: fmt.Sprintf("has %d columns, the header has %d", len(record), len(header))})
+			continue
+		}
+
+		row := make(map[string]any, len(profile.Columns)+len(profile.Defaults))
+		for i, value := range record {
+			if fields[i] == "" {
+				continue
+			}
+			if value = strings.TrimSpace(value); value != "" {
+				row[fields[i]] = value
+			}
+		}
+		for field, value := range profile.Defaults {
+			if _, ok := row[field]; !ok {
+				row[field] = value
+			}
+		}
+		if message := checkRequired(profile, row); message != "" {
+			rowErrors = append(rowErrors, RowError{Line: line, Message: message})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrors, nil
+}
+
+func checkRequired(profile *Profile, row map[string]any) string {
+	var empty []string
+	for _, field := range profile.Required {
+		if _, ok := row[field]; !ok {
+			empty = append(empty, field)
+		}
+	}
+	if len(empty) == 0 {
+		return ""
+	}
+	return "missing " + strings.Join(empty, ", ")
+}
+
+func normalizeHeader(column string) string {
+	return strings.ToLower(strings.TrimSpace(column))
+}
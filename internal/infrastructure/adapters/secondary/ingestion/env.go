@@ -0,0 +1,37 @@
+package ingestion
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// SFTPConfigFromEnv reads the SFTP drop folder settings from the
+// LEAPFOR_INTEGRATION_INGESTION_SFTP_* variables (see .env.example). It
+// returns ok=false when no address is set.
+func SFTPConfigFromEnv() (config SFTPConfig, ok bool, err error) {
+	config = SFTPConfig{
+		Addr:                  os.Getenv("LEAPFOR_INTEGRATION_INGESTION_SFTP_ADDR"),
+		User:                  os.Getenv("LEAPFOR_INTEGRATION_INGESTION_SFTP_USER"),
+		Password:              os.Getenv("LEAPFOR_INTEGRATION_INGESTION_SFTP_PASSWORD"),
+		HostKey:               os.Getenv("LEAPFOR_INTEGRATION_INGESTION_SFTP_HOST_KEY"),
+		InsecureIgnoreHostKey: os.Getenv("LEAPFOR_INTEGRATION_INGESTION_SFTP_INSECURE_IGNORE_HOST_KEY") == "true",
+		Dir:                   os.Getenv("LEAPFOR_INTEGRATION_INGESTION_SFTP_DIR"),
+		ProcessedDir:          os.Getenv("LEAPFOR_INTEGRATION_INGESTION_SFTP_PROCESSED_DIR"),
+		FailedDir:             os.Getenv("LEAPFOR_INTEGRATION_INGESTION_SFTP_FAILED_DIR"),
+	}
+	if config.Addr == "" {
+		return SFTPConfig{}, false, nil
+	}
+	if file := os.Getenv("LEAPFOR_INTEGRATION_INGESTION_SFTP_PRIVATE_KEY_FILE"); file != "" {
+		if config.PrivateKey, err = os.ReadFile(file); err != nil {
+			return SFTPConfig{}, false, fmt.Errorf("sftp private key: %w", err)
+		}
+	}
+	if v := os.Getenv("LEAPFOR_INTEGRATION_INGESTION_SFTP_TIMEOUT"); v != "" {
+		if config.Timeout, err = time.ParseDuration(v); err != nil {
+			return SFTPConfig{}, false, fmt.Errorf("LEAPFOR_INTEGRATION_INGESTION_SFTP_TIMEOUT: %w", err)
+		}
+	}
+	return config, true, nil
+}
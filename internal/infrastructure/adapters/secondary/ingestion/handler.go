@@ -0,0 +1,95 @@
+package ingestion
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DropPath is the upload endpoint for systems that push their exports
+// instead of writing to the SFTP folder:
+//
+//	POST DropPath?filename=students_20260301.csv   (raw CSV body)
+//	POST DropPath                                   (multipart, field "file")
+//
+// The file name selects the profile as it does for SFTP files. The response
+// is the Report; 422 when no profile matches or the file is unusable.
+const DropPath = "/api/ingestion/drop"
+
+// Handler serves the drop endpoint. It must sit behind the authentication
+// middleware.
+type Handler struct {
+	ingestor *Ingestor
+}
+
+// NewHandler creates the drop endpoint over ingestor.
+func NewHandler(ingestor *Ingestor) *Handler {
+	return &Handler{ingestor: ingestor}
+}
+
+// ServeHTTP ingests one uploaded file.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return
+	}
+	// Leave room for the multipart envelope around the file.
+	r.Body = http.MaxBytesReader(w, r.Body, h.ingestor.maxBytes+64<<10)
+
+	name, body, err := uploadedFile(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	defer body.Close()
+
+	report, err := h.ingestor.Ingest(r.Context(), name, body)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": report})
+	case errors.As(err, &tooLarge) || errors.Is(err, ErrFileTooLarge):
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]any{"success": false, "error": ErrFileTooLarge.Error()})
+	case report == nil:
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"success": false, "error": err.Error()})
+	default:
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error(), "data": report})
+	}
+}
+
+// uploadedFile returns the name and content of the upload, from a multipart
+// "file" field or the raw body and the filename query parameter.
+func uploadedFile(r *http.Request) (string, io.ReadCloser, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			return "", nil, err
+		}
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				return "", nil, errors.New(`multipart body has no "file" field`)
+			}
+			if part.FormName() == "file" && part.FileName() != "" {
+				return part.FileName(), part, nil
+			}
+			part.Close()
+		}
+	}
+
+	name := strings.TrimSpace(r.URL.Query().Get("filename"))
+	if name == "" {
+		return "", nil, errors.New("filename query parameter is required")
+	}
+	return name, r.Body, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,232 @@
+// Package ingestion imports CSV exports that other systems drop off, such as
+// the nightly student and guardian files a school information system writes
+// to an SFTP folder.
+//
+// Each file is matched by name to a Profile, which says where its rows go
+// and how its columns map to fields. Files arrive either through a Watcher
+// polling an SFTP directory or through the drop endpoint (see Handler).
+// Either way the rows are validated against the profile and handed to an
+// Importer; rows that fail validation are reported by line and skipped.
+package ingestion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+)
+
+// DefaultMaxFileBytes bounds the size of one ingested file.
+const DefaultMaxFileBytes = 32 << 20
+
+// ErrNoProfile is returned for a file whose name matches no profile.
+var ErrNoProfile = errors.New("no ingestion profile matches the file name")
+
+// ErrFileTooLarge is returned for a file over the configured size limit.
+var ErrFileTooLarge = errors.New("file exceeds the ingestion size limit")
+
+// Profile describes one kind of file and how its rows are imported.
+type Profile struct {
+	// Name identifies the profile in reports and logs.
+	Name string `json:"name"`
+	// Pattern is a path.Match glob on the file's base name, e.g.
+	// "students_*.csv". The first matching profile wins.
+	Pattern string `json:"pattern"`
+	// Table receives the rows.
+	Table string `json:"table"`
+	// WorkspaceID is stamped on every row as workspace_id when set.
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	// Delimiter is the field separator; a comma when empty.
+	Delimiter string `json:"delimiter,omitempty"`
+	// Columns maps CSV headers to field names. Headers match
+	// case-insensitively and ignoring surrounding spaces; columns not listed
+	// are ignored.
+	Columns map[string]string `json:"columns"`
+	// Required lists fields that must be present and non-empty in a row.
+	Required []string `json:"required,omitempty"`
+	// Defaults fills fields a row leaves empty.
+	Defaults map[string]any `json:"defaults,omitempty"`
+	// Verify reads the imported rows back from the primary database and
+	// reports fields that did not persist as written (see Verification).
+	Verify bool `json:"verify,omitempty"`
+}
+
+// Config is the set of profiles and limits an Ingestor works with.
+type Config struct {
+	Profiles []Profile `json:"profiles"`
+	// MaxFileBytes bounds one file; DefaultMaxFileBytes when zero.
+	MaxFileBytes int64 `json:"max_file_bytes,omitempty"`
+}
+
+// LoadConfig reads a Config from a JSON or YAML file.
+func LoadConfig(file string) (Config, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return Config{}, err
+	}
+	if ext := strings.ToLower(path.Ext(file)); ext == ".yaml" || ext == ".yml" {
+		if raw, err = yaml.YAMLToJSON(raw); err != nil {
+			return Config{}, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	var config Config
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", file, err)
+	}
+	return config, nil
+}
+
+// Importer writes the valid rows of a file. It returns how many rows were
+// imported; on error the file counts as failed.
+type Importer interface {
+	Import(ctx context.Context, profile *Profile, rows []map[string]any) (int, error)
+}
+
+// DatabaseImporter creates rows in the profile's table through the generic
+// database operations, in one CreateMany batch per file. It is a Verifier.
+type DatabaseImporter struct {
+	ops interfaces.DatabaseOperation
+}
+
+// NewDatabaseImporter creates an Importer writing through ops.
+func NewDatabaseImporter(ops interfaces.DatabaseOperation) *DatabaseImporter {
+	return &DatabaseImporter{ops: ops}
+}
+
+// Import creates rows in profile.Table and sets each row's id to the id of
+// the created row, so the rows can be verified.
+func (d *DatabaseImporter) Import(ctx context.Context, profile *Profile, rows []map[string]any) (int, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if profile.WorkspaceID != "" {
+		for _, row := range rows {
+			row["workspace_id"] = profile.WorkspaceID
+		}
+	}
+	created, err := d.ops.CreateMany(ctx, profile.Table, rows)
+	if err != nil {
+		return 0, fmt.Errorf("import into %s: %w", profile.Table, err)
+	}
+	if len(created) == len(rows) {
+		for i, row := range created {
+			if id, ok := row["id"]; ok {
+				rows[i]["id"] = id
+			}
+		}
+	}
+	return len(created), nil
+}
+
+// RowError reports a row skipped by validation.
+type RowError struct {
+	// Line is the 1-based line of the row in the file, the header being 1.
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Report summarizes the ingestion of one file.
+type Report struct {
+	File     string     `json:"file"`
+	Profile  string     `json:"profile"`
+	Rows     int        `json:"rows"`
+	Imported int        `json:"imported"`
+	Errors   []RowError `json:"errors,omitempty"`
+	// Verification is set when the profile verifies imports.
+	Verification *Verification `json:"verification,omitempty"`
+}
+
+// Ingestor matches files to profiles and imports their rows.
+type Ingestor struct {
+	profiles []Profile
+	importer Importer
+	maxBytes int64
+}
+
+// New creates an Ingestor. Every profile needs a name, a valid pattern, a
+// table and at least one column.
+func New(config Config, importer Importer) (*Ingestor, error) {
+	if importer == nil {
+		return nil, fmt.Errorf("ingestion requires an importer")
+	}
+	if len(config.Profiles) == 0 {
+		return nil, fmt.Errorf("ingestion requires at least one profile")
+	}
+	for i, p := range config.Profiles {
+		if p.Name == "" || p.Pattern == "" || p.Table == "" || len(p.Columns) == 0 {
+			return nil, fmt.Errorf("profile %d: name, pattern, table and columns are required", i)
+		}
+		if _, err := path.Match(p.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("profile %s: invalid pattern %q", p.Name, p.Pattern)
+		}
+		if len([]rune(p.Delimiter)) > 1 {
+			return nil, fmt.Errorf("profile %s: delimiter must be one character", p.Name)
+		}
+	}
+	maxBytes := config.MaxFileBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxFileBytes
+	}
+	return &Ingestor{profiles: config.Profiles, importer: importer, maxBytes: maxBytes}, nil
+}
+
+// Match returns the profile for a file name, or nil.
+func (i *Ingestor) Match(fileName string) *Profile {
+	base := path.Base(strings.ReplaceAll(fileName, `\`, "/"))
+	for n := range i.profiles {
+		if ok, _ := path.Match(i.profiles[n].Pattern, base); ok {
+			return &i.profiles[n]
+		}
+	}
+	return nil
+}
+
+// Ingest imports the file read from r. Invalid rows are reported and
+// skipped; an error means nothing was imported: no profile matched, the
+// file could not be parsed, or the importer failed.
+func (i *Ingestor) Ingest(ctx context.Context, fileName string, r io.Reader) (*Report, error) {
+	profile := i.Match(fileName)
+	if profile == nil {
+		return nil, ErrNoProfile
+	}
+	raw, err := io.ReadAll(io.LimitReader(r, i.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	if int64(len(raw)) > i.maxBytes {
+		return nil, fmt.Errorf("%s: %w", fileName, ErrFileTooLarge)
+	}
+	rows, rowErrors, err := parseCSV(profile, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	report := &Report{
+		File:    path.Base(fileName),
+		Profile: profile.Name,
+		Rows:    len(rows) + len(rowErrors),
+		Errors:  rowErrors,
+	}
+	imported, err := i.importer.Import(ctx, profile, rows)
+	if err != nil {
+		return report, err
+	}
+	report.Imported = imported
+
+	if verifier, ok := i.importer.(Verifier); ok && profile.Verify && imported > 0 {
+		verification, err := verifier.Verify(ctx, profile, rows)
+		if err != nil {
+			verification = &Verification{Error: err.Error()}
+		}
+		report.Verification = verification
+	}
+	return report, nil
+}
@@ -0,0 +1,257 @@
+package ingestion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+)
+
+// recordingImporter keeps what it was asked to import.
+type recordingImporter struct {
+	profile string
+	rows    []map[string]any
+	err     error
+}
+
+func (r *recordingImporter) Import(ctx context.Context, profile *Profile, rows []map[string]any) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	r.profile = profile.Name
+	r.rows = append(r.rows, rows...)
+	return len(rows), nil
+}
+
+func testConfig() Config {
+	return Config{Profiles: []Profile{
+		{
+			Name:    "students",
+			Pattern: "students_*.csv",
+			Table:   "client",
+			Columns: map[string]string{
+				"Student No":    "internal_id",
+				"Last Name":     "last_name",
+				"First Name":    "first_name",
+				"Email Address": "email",
+			},
+			Required: []string{"internal_id", "last_name"},
+			Defaults: map[string]any{"client_type": "student"},
+		},
+		{
+			Name:      "guardians",
+			Pattern:   "guardians_*.txt",
+			Table:     "guardian",
+			Delimiter: "|",
+			Columns:   map[string]string{"id": "internal_id"},
+		},
+	}}
+}
+
+func newTestIngestor(t *testing.T, importer Importer) *Ingestor {
+	t.Helper()
+	ingestor, err := New(testConfig(), importer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ingestor
+}
+
+func TestIngest_MapsColumnsAndReportsBadRows(t *testing.T) {
+	importer := &recordingImporter{}
+	ingestor := newTestIngestor(t, importer)
+
+	file := "\xEF\xBB\xBF student no ,LAST NAME,First Name,Email Address,Grade\n" +
+		"S-001,Santos,Ana,ana@example.com,7\n" +
+		"S-002,,Ben,,8\n" +
+		"S-003,Reyes\n" +
+		"S-004,Cruz,Carla,,9\n"
+	report, err := ingestor.Ingest(context.Background(), "inbox/students_20260301.csv", strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Profile != "students" || report.File != "students_20260301.csv" || report.Rows != 4 || report.Imported != 2 {
+		t.Errorf("report = %+v", report)
+	}
+	if len(report.Errors) != 2 || report.Errors[0].Line != 3 || report.Errors[0].Message != "missing last_name" || report.Errors[1].Line != 4 {
+		t.Errorf("errors = %+v, want lines 3 and 4", report.Errors)
+	}
+	want := map[string]any{"internal_id": "S-001", "last_name": "Santos", "first_name": "Ana", "email": "ana@example.com", "client_type": "student"}
+	if len(importer.rows) != 2 || !equalRows(importer.rows[0], want) {
+		t.Errorf("first row = %v, want %v", importer.rows[0], want)
+	}
+	if _, ok := importer.rows[1]["email"]; ok {
+		t.Errorf("empty email was imported: %v", importer.rows[1])
+	}
+}
+
+func TestIngest_FileLevelFailures(t *testing.T) {
+	ingestor := newTestIngestor(t, &recordingImporter{})
+	ctx := context.Background()
+
+	if _, err := ingestor.Ingest(ctx, "teachers.csv", strings.NewReader("id\n1\n")); !errors.Is(err, ErrNoProfile) {
+		t.Errorf("unmatched file: err = %v", err)
+	}
+	if _, err := ingestor.Ingest(ctx, "students_1.csv", strings.NewReader("Student No,First Name\nS-1,Ana\n")); err == nil {
+		t.Error("imported a file without a last name column")
+	}
+
+	report, err := ingestor.Ingest(ctx, "guardians_1.txt", strings.NewReader("id|name\nG-1|Maria\n"))
+	if err != nil || report.Imported != 1 {
+		t.Errorf("pipe-delimited file = %+v, %v", report, err)
+	}
+
+	failing := newTestIngestor(t, &recordingImporter{err: errors.New("database down")})
+	report, err = failing.Ingest(ctx, "guardians_1.txt", strings.NewReader("id\nG-1\n"))
+	if err == nil || report == nil || report.Imported != 0 {
+		t.Errorf("importer failure = %+v, %v; want the report and the error", report, err)
+	}
+}
+
+func TestNew_ValidatesProfiles(t *testing.T) {
+	for name, profile := range map[string]Profile{
+		"no table":      {Name: "a", Pattern: "*.csv", Columns: map[string]string{"a": "a"}},
+		"bad pattern":   {Name: "a", Pattern: "[", Table: "t", Columns: map[string]string{"a": "a"}},
+		"bad delimiter": {Name: "a", Pattern: "*.csv", Table: "t", Delimiter: ";;", Columns: map[string]string{"a": "a"}},
+	} {
+		if _, err := New(Config{Profiles: []Profile{profile}}, &recordingImporter{}); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	importer := &recordingImporter{}
+	handler := NewHandler(newTestIngestor(t, importer))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, DropPath+"?filename=guardians_1.txt", strings.NewReader("id\nG-1\nG-2\n")))
+	if rec.Code != http.StatusOK || importer.profile != "guardians" || len(importer.rows) != 2 {
+		t.Fatalf("raw upload = %d %s", rec.Code, rec.Body)
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "students_2.csv")
+	part.Write([]byte("Student No,Last Name\nS-9,Lim\n"))
+	form.Close()
+	req := httptest.NewRequest(http.MethodPost, DropPath, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var resp struct {
+		Data Report `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK || resp.Data.Profile != "students" || resp.Data.Imported != 1 {
+		t.Errorf("multipart upload = %d %+v %v", rec.Code, resp, err)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, DropPath+"?filename=other.csv", strings.NewReader("id\n1\n")))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("unmatched upload = %d, want 422", rec.Code)
+	}
+}
+
+func equalRows(a, b map[string]any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// droppingOps stores rows without the columns its table lacks, the way the
+// Postgres adapter drops unknown fields, and loses row "c2".
+type droppingOps struct {
+	interfaces.DatabaseOperation
+	rows         map[string]map[string]any
+	primaryReads int
+}
+
+func (o *droppingOps) CreateMany(_ context.Context, _ string, rows []map[string]any) ([]map[string]any, error) {
+	created := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		id := "c" + string(rune('1'+i))
+		stored := map[string]any{"id": id}
+		for field, value := range row {
+			if field != "client_type" {
+				stored[field] = value
+			}
+		}
+		stored["internal_id"] = strings.TrimPrefix(stored["internal_id"].(string), "S-")
+		o.rows[id] = stored
+		created[i] = stored
+	}
+	delete(o.rows, "c2")
+	return created, nil
+}
+
+func (o *droppingOps) Read(ctx context.Context, _ string, id string) (map[string]any, error) {
+	if operations.IsPrimaryRead(ctx) {
+		o.primaryReads++
+	}
+	row, ok := o.rows[id]
+	if !ok {
+		return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return row, nil
+}
+
+func TestIngest_VerifiesImportedRows(t *testing.T) {
+	ops := &droppingOps{rows: map[string]map[string]any{}}
+	config := testConfig()
+	config.Profiles[0].Verify = true
+	ingestor, err := New(config, NewDatabaseImporter(ops))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := "Student No,Last Name\nS-001,Santos\nS-002,Reyes\n"
+	report, err := ingestor.Ingest(context.Background(), "students_1.csv", strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := report.Verification
+	if !v.Diverged() || v.Checked != 1 || v.MissingRows != 1 || ops.primaryReads != 2 || len(v.Fields) != 2 {
+		t.Fatalf("verification = %+v (primary reads %d)", v, ops.primaryReads)
+	}
+	if f := v.Fields[0]; f.Field != "client_type" || f.Dropped != 1 || f.ExampleID != "c1" || f.Wrote != "student" {
+		t.Errorf("dropped field = %+v", f)
+	}
+	if f := v.Fields[1]; f.Field != "internal_id" || f.Changed != 1 || f.Read != "001" {
+		t.Errorf("changed field = %+v", f)
+	}
+}
+
+func TestSameValue(t *testing.T) {
+	for _, tt := range []struct {
+		wrote, read any
+		want        bool
+	}{
+		{"7", float64(7), true},
+		{"7.50", 7.5, true},
+		{"true", true, true},
+		{"", nil, true},
+		{"Ana", "ana", false},
+		{"7", nil, false},
+	} {
+		if got := sameValue(tt.wrote, tt.read); got != tt.want {
+			t.Errorf("sameValue(%v, %v) = %v", tt.wrote, tt.read, got)
+		}
+	}
+}
@@ -0,0 +1,457 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTPConfig locates the drop folder on an SFTP server.
+type SFTPConfig struct {
+	// Addr is host:port; port 22 is assumed when missing.
+	Addr     string
+	User     string
+	Password string
+	// PrivateKey is a PEM private key, used instead of or besides Password.
+	PrivateKey []byte
+	// HostKey is the server's public key in authorized_keys format. It is
+	// required unless InsecureIgnoreHostKey is set.
+	HostKey               string
+	InsecureIgnoreHostKey bool
+	// Dir is the folder polled for new files.
+	Dir string
+	// ProcessedDir and FailedDir receive files after ingestion; they default
+	// to the "processed" and "failed" subfolders of Dir.
+	ProcessedDir string
+	FailedDir    string
+	Timeout      time.Duration
+}
+
+func (c SFTPConfig) clientConfig() (*ssh.ClientConfig, error) {
+	if c.Addr == "" || c.User == "" || c.Dir == "" {
+		return nil, fmt.Errorf("sftp requires an address, a user and a directory")
+	}
+	var auth []ssh.AuthMethod
+	if len(c.PrivateKey) > 0 {
+		signer, err := ssh.ParsePrivateKey(c.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("sftp private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if c.Password != "" {
+		auth = append(auth, ssh.Password(c.Password))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("sftp requires a password or a private key")
+	}
+
+	hostKey := ssh.InsecureIgnoreHostKey()
+	if !c.InsecureIgnoreHostKey {
+		if c.HostKey == "" {
+			return nil, fmt.Errorf("sftp requires the server host key")
+		}
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(c.HostKey))
+		if err != nil {
+			return nil, fmt.Errorf("sftp host key: %w", err)
+		}
+		hostKey = ssh.FixedHostKey(key)
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &ssh.ClientConfig{User: c.User, Auth: auth, HostKeyCallback: hostKey, Timeout: timeout}, nil
+}
+
+// dialSFTP opens an SSH connection and starts the sftp subsystem on it.
+func dialSFTP(ctx context.Context, config SFTPConfig) (remoteDir, error) {
+	clientConfig, err := config.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	addr := config.Addr
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+
+	dialer := net.Dialer{Timeout: clientConfig.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("sftp: dial %s: %w", addr, err)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, clientConfig)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("sftp: handshake with %s: %w", addr, err)
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	session, err := client.NewSession()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("sftp: open session: %w", err)
+	}
+	w, err := session.StdinPipe()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	r, err := session.StdoutPipe()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("sftp: start subsystem: %w", err)
+	}
+
+	c, err := newSFTPClient(r, w, client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// SFTP version 3 packet types and status codes (draft-ietf-secsh-filexfer-02),
+// the version every server supports.
+const (
+	fxpInit     = 1
+	fxpVersion  = 2
+	fxpOpen     = 3
+	fxpClose    = 4
+	fxpRead     = 5
+	fxpOpendir  = 11
+	fxpReaddir  = 12
+	fxpMkdir    = 14
+	fxpRename   = 18
+	fxpStatus   = 101
+	fxpHandle   = 102
+	fxpData     = 103
+	fxpName     = 104
+	fxfRead     = 0x01
+	fxOK        = 0
+	fxEOF       = 1
+	attrSize    = 0x01
+	attrUIDGID  = 0x02
+	attrPerms   = 0x04
+	attrTimes   = 0x08
+	attrExtend  = 0x80000000
+	sftpVersion = 3
+
+	// readChunk is the read request size; servers are only required to
+	// honour 32 KiB.
+	readChunk = 32 << 10
+	// maxPacket bounds an incoming packet.
+	maxPacket = 256 << 10
+)
+
+// statusError is an SSH_FXP_STATUS other than OK.
+type statusError struct {
+	code    uint32
+	message string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("sftp: status %d: %s", e.code, e.message)
+}
+
+// remoteFile is one directory entry.
+type remoteFile struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+// remoteDir is the subset of SFTP the watcher uses.
+type remoteDir interface {
+	readDir(dir string) ([]remoteFile, error)
+	readFile(file string, max int64) ([]byte, error)
+	rename(from, to string) error
+	mkdir(dir string) error
+	Close() error
+}
+
+// sftpClient is a minimal synchronous SFTP v3 client: one request in flight
+// at a time, which is plenty for moving a few export files per poll.
+type sftpClient struct {
+	mu     sync.Mutex
+	r      io.Reader
+	w      io.Writer
+	closer io.Closer
+	nextID uint32
+}
+
+func newSFTPClient(r io.Reader, w io.Writer, closer io.Closer) (*sftpClient, error) {
+	c := &sftpClient{r: r, w: w, closer: closer}
+	if err := c.send(fxpInit, uint32(sftpVersion)); err != nil {
+		return nil, err
+	}
+	typ, _, err := c.recv()
+	if err != nil {
+		return nil, err
+	}
+	if typ != fxpVersion {
+		return nil, fmt.Errorf("sftp: unexpected packet %d during init", typ)
+	}
+	return c, nil
+}
+
+// Close ends the SSH connection.
+func (c *sftpClient) Close() error {
+	if c.closer == nil {
+		return nil
+	}
+	return c.closer.Close()
+}
+
+// send writes one packet. Fields are uint32, uint64, string or []byte.
+func (c *sftpClient) send(typ byte, fields ...any) error {
+	payload := []byte{typ}
+	for _, f := range fields {
+		switch v := f.(type) {
+		case uint32:
+			payload = binary.BigEndian.AppendUint32(payload, v)
+		case uint64:
+			payload = binary.BigEndian.AppendUint64(payload, v)
+		case string:
+			payload = binary.BigEndian.AppendUint32(payload, uint32(len(v)))
+			payload = append(payload, v...)
+		case []byte:
+			payload = binary.BigEndian.AppendUint32(payload, uint32(len(v)))
+			payload = append(payload, v...)
+		default:
+			panic(fmt.Sprintf("sftp: unsupported field %T", f))
+		}
+	}
+	packet := binary.BigEndian.AppendUint32(nil, uint32(len(payload)))
+	if _, err := c.w.Write(append(packet, payload...)); err != nil {
+		return fmt.Errorf("sftp: write: %w", err)
+	}
+	return nil
+}
+
+func (c *sftpClient) recv() (byte, *packetReader, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(c.r, length[:]); err != nil {
+		return 0, nil, fmt.Errorf("sftp: read: %w", err)
+	}
+	n := binary.BigEndian.Uint32(length[:])
+	if n == 0 || n > maxPacket {
+		return 0, nil, fmt.Errorf("sftp: invalid packet length %d", n)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return 0, nil, fmt.Errorf("sftp: read: %w", err)
+	}
+	return payload[0], &packetReader{buf: payload[1:]}, nil
+}
+
+// call sends a request and returns the response body after its ID. A
+// STATUS response other than OK is returned as a *statusError.
+func (c *sftpClient) call(typ byte, fields ...any) (byte, *packetReader, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextID++
+	id := c.nextID
+	if err := c.send(typ, append([]any{id}, fields...)...); err != nil {
+		return 0, nil, err
+	}
+	respType, p, err := c.recv()
+	if err != nil {
+		return 0, nil, err
+	}
+	if got := p.uint32(); got != id || p.err != nil {
+		return 0, nil, fmt.Errorf("sftp: response id %d does not match request %d", got, id)
+	}
+	if respType == fxpStatus {
+		code := p.uint32()
+		message := p.string()
+		if code != fxOK {
+			return 0, nil, &statusError{code: code, message: message}
+		}
+	}
+	return respType, p, p.err
+}
+
+func (c *sftpClient) handle(typ byte, fields ...any) (string, error) {
+	respType, p, err := c.call(typ, fields...)
+	if err != nil {
+		return "", err
+	}
+	if respType != fxpHandle {
+		return "", fmt.Errorf("sftp: expected a handle, got packet %d", respType)
+	}
+	return p.string(), p.err
+}
+
+func (c *sftpClient) closeHandle(handle string) {
+	_, _, _ = c.call(fxpClose, handle)
+}
+
+func (c *sftpClient) readDir(dir string) ([]remoteFile, error) {
+	handle, err := c.handle(fxpOpendir, dir)
+	if err != nil {
+		return nil, fmt.Errorf("sftp: open %s: %w", dir, err)
+	}
+	defer c.closeHandle(handle)
+
+	var files []remoteFile
+	for {
+		respType, p, err := c.call(fxpReaddir, handle)
+		var status *statusError
+		if errors.As(err, &status) && status.code == fxEOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sftp: read %s: %w", dir, err)
+		}
+		if respType != fxpName {
+			return nil, fmt.Errorf("sftp: expected names, got packet %d", respType)
+		}
+		for n := p.uint32(); n > 0 && p.err == nil; n-- {
+			name := p.string()
+			p.string() // longname
+			size, mode := p.attrs()
+			if name == "." || name == ".." {
+				continue
+			}
+			files = append(files, remoteFile{name: name, size: int64(size), isDir: mode&0o170000 == 0o040000})
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+	}
+}
+
+func (c *sftpClient) readFile(file string, max int64) ([]byte, error) {
+	handle, err := c.handle(fxpOpen, file, uint32(fxfRead), uint32(0))
+	if err != nil {
+		return nil, fmt.Errorf("sftp: open %s: %w", file, err)
+	}
+	defer c.closeHandle(handle)
+
+	var data []byte
+	for {
+		respType, p, err := c.call(fxpRead, handle, uint64(len(data)), uint32(readChunk))
+		var status *statusError
+		if errors.As(err, &status) && status.code == fxEOF {
+			return data, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sftp: read %s: %w", file, err)
+		}
+		if respType != fxpData {
+			return nil, fmt.Errorf("sftp: expected data, got packet %d", respType)
+		}
+		chunk := p.bytes()
+		if p.err != nil {
+			return nil, p.err
+		}
+		data = append(data, chunk...)
+		if int64(len(data)) > max {
+			return nil, ErrFileTooLarge
+		}
+	}
+}
+
+func (c *sftpClient) rename(from, to string) error {
+	if _, _, err := c.call(fxpRename, from, to); err != nil {
+		return fmt.Errorf("sftp: rename %s: %w", from, err)
+	}
+	return nil
+}
+
+// mkdir creates dir, succeeding when it already exists.
+func (c *sftpClient) mkdir(dir string) error {
+	_, _, err := c.call(fxpMkdir, dir, uint32(0))
+	if err == nil {
+		return nil
+	}
+	if _, statErr := c.readDir(dir); statErr == nil {
+		return nil
+	}
+	return fmt.Errorf("sftp: mkdir %s: %w", dir, err)
+}
+
+// packetReader decodes response fields, remembering the first error.
+type packetReader struct {
+	buf []byte
+	err error
+}
+
+func (p *packetReader) take(n int) []byte {
+	if p.err != nil {
+		return nil
+	}
+	if len(p.buf) < n {
+		p.err = fmt.Errorf("sftp: short packet")
+		return nil
+	}
+	b := p.buf[:n]
+	p.buf = p.buf[n:]
+	return b
+}
+
+func (p *packetReader) uint32() uint32 {
+	if b := p.take(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (p *packetReader) uint64() uint64 {
+	if b := p.take(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (p *packetReader) bytes() []byte {
+	return p.take(int(p.uint32()))
+}
+
+func (p *packetReader) string() string {
+	return string(p.bytes())
+}
+
+// attrs decodes an ATTRS block, keeping the size and permissions.
+func (p *packetReader) attrs() (size uint64, mode uint32) {
+	flags := p.uint32()
+	if flags&attrSize != 0 {
+		size = p.uint64()
+	}
+	if flags&attrUIDGID != 0 {
+		p.uint32()
+		p.uint32()
+	}
+	if flags&attrPerms != 0 {
+		mode = p.uint32()
+	}
+	if flags&attrTimes != 0 {
+		p.uint32()
+		p.uint32()
+	}
+	if flags&attrExtend != 0 {
+		for n := p.uint32(); n > 0 && p.err == nil; n-- {
+			p.string()
+			p.string()
+		}
+	}
+	return size, mode
+}
+
+// joinRemote joins SFTP path elements, which always use forward slashes.
+func joinRemote(dir, name string) string {
+	return strings.TrimSuffix(dir, "/") + "/" + name
+}
@@ -0,0 +1,112 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+)
+
+// Verifier is implemented by importers that can read back the rows they
+// imported. Ingest calls it after a successful import when the profile
+// sets Verify, and puts the outcome in the report.
+type Verifier interface {
+	Verify(ctx context.Context, profile *Profile, rows []map[string]any) (*Verification, error)
+}
+
+// Verification reports how imported rows read back. A backend that drops
+// fields it has no column for, as Postgres does, shows up here as a
+// dropped field instead of going unnoticed.
+type Verification struct {
+	// Checked is the number of rows read back.
+	Checked int `json:"checked"`
+	// MissingRows counts rows that could not be found again.
+	MissingRows int `json:"missing_rows,omitempty"`
+	// Fields lists the fields that diverged, by name.
+	Fields []FieldDivergence `json:"fields,omitempty"`
+	// Error is set when the rows could not be read back.
+	Error string `json:"error,omitempty"`
+}
+
+// FieldDivergence is a field that read back differently than it was
+// written in some rows.
+type FieldDivergence struct {
+	Field string `json:"field"`
+	// Dropped counts rows where the field was not stored at all.
+	Dropped int `json:"dropped,omitempty"`
+	// Changed counts rows where the stored value differs.
+	Changed int `json:"changed,omitempty"`
+	// Example is the first diverging row.
+	ExampleID string `json:"example_id"`
+	Wrote     any    `json:"wrote"`
+	Read      any    `json:"read,omitempty"`
+}
+
+// Diverged reports whether any row or field read back differently.
+func (v *Verification) Diverged() bool {
+	return v != nil && (v.MissingRows > 0 || len(v.Fields) > 0 || v.Error != "")
+}
+
+// Verify reads every imported row back from the primary, past any result
+// cache, and compares it with what was written. Values are compared as
+// text, and as numbers when both sides are numeric, since backends return
+// their own types for the strings a CSV provides.
+func (d *DatabaseImporter) Verify(ctx context.Context, profile *Profile, rows []map[string]any) (*Verification, error) {
+	ctx = operations.WithPrimaryRead(ctx)
+	v := &Verification{}
+	fields := make(map[string]*FieldDivergence)
+	for _, row := range rows {
+		id, _ := row["id"].(string)
+		if id == "" {
+			continue
+		}
+		stored, err := d.ops.Read(ctx, profile.Table, id)
+		if dbErr, ok := model.GetDatabaseError(err); ok && dbErr.HTTPStatus == http.StatusNotFound {
+			v.MissingRows++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("verify %s %s: %w", profile.Table, id, err)
+		}
+		v.Checked++
+		for field, wrote := range row {
+			read, found := stored[field]
+			if found && sameValue(wrote, read) {
+				continue
+			}
+			f := fields[field]
+			if f == nil {
+				f = &FieldDivergence{Field: field, ExampleID: id, Wrote: wrote, Read: read}
+				fields[field] = f
+			}
+			if found {
+				f.Changed++
+			} else {
+				f.Dropped++
+			}
+		}
+	}
+	for _, f := range fields {
+		v.Fields = append(v.Fields, *f)
+	}
+	sort.Slice(v.Fields, func(i, j int) bool { return v.Fields[i].Field < v.Fields[j].Field })
+	return v, nil
+}
+
+// sameValue compares a written value with the one read back.
+func sameValue(wrote, read any) bool {
+	if read == nil {
+		return wrote == nil || fmt.Sprint(wrote) == ""
+	}
+	a, b := fmt.Sprint(wrote), fmt.Sprint(read)
+	if a == b {
+		return true
+	}
+	x, errX := strconv.ParseFloat(a, 64)
+	y, errY := strconv.ParseFloat(b, 64)
+	return errX == nil && errY == nil && x == y
+}
@@ -0,0 +1,138 @@
+package ingestion
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Watcher polls an SFTP folder and ingests the files that match a profile.
+// A file is picked up once its size is unchanged between two polls, so
+// uploads still in progress are left alone. Ingested files move to the
+// processed folder, files that could not be ingested to the failed folder,
+// both renamed with a UTC timestamp prefix. Files matching no profile and
+// dot-files are never touched.
+type Watcher struct {
+	ingestor *Ingestor
+	config   SFTPConfig
+	dial     func(ctx context.Context) (remoteDir, error)
+	// sizes holds each candidate's size at the previous poll.
+	sizes map[string]int64
+	now   func() time.Time
+}
+
+// NewWatcher creates a Watcher of the SFTP folder in config.
+func NewWatcher(ingestor *Ingestor, config SFTPConfig) (*Watcher, error) {
+	if ingestor == nil {
+		return nil, fmt.Errorf("watcher requires an ingestor")
+	}
+	if _, err := config.clientConfig(); err != nil {
+		return nil, err
+	}
+	if config.ProcessedDir == "" {
+		config.ProcessedDir = joinRemote(config.Dir, "processed")
+	}
+	if config.FailedDir == "" {
+		config.FailedDir = joinRemote(config.Dir, "failed")
+	}
+	return &Watcher{
+		ingestor: ingestor,
+		config:   config,
+		dial:     func(ctx context.Context) (remoteDir, error) { return dialSFTP(ctx, config) },
+		sizes:    make(map[string]int64),
+		now:      time.Now,
+	}, nil
+}
+
+// Run polls every interval until ctx is done.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.Poll(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("⚠️  Warning: sftp ingestion poll failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll connects once, ingests every settled file and returns their reports.
+// A file whose ingestion failed has a report only when its rows were read.
+func (w *Watcher) Poll(ctx context.Context) ([]*Report, error) {
+	dir, err := w.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	files, err := dir.readDir(w.config.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []*Report
+	sizes := make(map[string]int64)
+	for _, file := range files {
+		if file.isDir || strings.HasPrefix(file.name, ".") || w.ingestor.Match(file.name) == nil {
+			continue
+		}
+		if previous, ok := w.sizes[file.name]; !ok || previous != file.size {
+			sizes[file.name] = file.size
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return reports, err
+		}
+
+		report, err := w.ingest(ctx, dir, file.name)
+		if report != nil {
+			reports = append(reports, report)
+		}
+		if err != nil {
+			return reports, err
+		}
+	}
+	w.sizes = sizes
+	return reports, nil
+}
+
+// ingest reads, imports and moves one file. The error is non-nil only when
+// the server could not be read or written; a file that fails to import is
+// moved to the failed folder and logged.
+func (w *Watcher) ingest(ctx context.Context, dir remoteDir, name string) (*Report, error) {
+	source := joinRemote(w.config.Dir, name)
+	raw, err := dir.readFile(source, w.ingestor.maxBytes)
+	var report *Report
+	if err == nil {
+		report, err = w.ingestor.Ingest(ctx, name, bytes.NewReader(raw))
+	} else if !errors.Is(err, ErrFileTooLarge) {
+		return nil, err
+	}
+
+	target := w.config.ProcessedDir
+	if err != nil {
+		target = w.config.FailedDir
+		log.Printf("⚠️  Warning: sftp ingestion of %s failed: %v", name, err)
+	} else {
+		log.Printf("📥 Ingested %s with profile %s: %d of %d rows imported, %d skipped",
+			name, report.Profile, report.Imported, report.Rows, len(report.Errors))
+		if v := report.Verification; v.Diverged() {
+			log.Printf("⚠️  Warning: %s read back differently than imported: %d rows missing, %d fields diverged %s",
+				name, v.MissingRows, len(v.Fields), v.Error)
+		}
+	}
+
+	if err := dir.mkdir(target); err != nil {
+		return report, err
+	}
+	moved := joinRemote(target, w.now().UTC().Format("20060102T150405Z")+"-"+name)
+	return report, dir.rename(source, moved)
+}
@@ -0,0 +1,193 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSFTPServer answers the SFTP v3 requests the client sends, over an
+// in-memory file tree.
+type fakeSFTPServer struct {
+	mu      sync.Mutex
+	files   map[string][]byte
+	dirs    map[string]bool
+	handles map[string]string
+	listed  map[string]bool
+}
+
+func newFakeSFTPServer(files map[string]string) *fakeSFTPServer {
+	s := &fakeSFTPServer{files: map[string][]byte{}, dirs: map[string]bool{"/in": true}, handles: map[string]string{}, listed: map[string]bool{}}
+	for name, content := range files {
+		s.files[name] = []byte(content)
+	}
+	return s
+}
+
+// dial connects a new client to the server over pipes.
+func (s *fakeSFTPServer) dial(ctx context.Context) (remoteDir, error) {
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	go s.serve(serverR, serverW)
+	return newSFTPClient(clientR, clientW, clientW)
+}
+
+func (s *fakeSFTPServer) serve(r io.Reader, w io.WriteCloser) {
+	defer w.Close()
+	client := &sftpClient{r: r, w: w}
+	for {
+		typ, p, err := client.recv()
+		if err != nil {
+			return
+		}
+		if typ == fxpInit {
+			client.send(fxpVersion, uint32(sftpVersion))
+			continue
+		}
+		id := p.uint32()
+		status := func(code uint32) { client.send(fxpStatus, id, code, "", "") }
+
+		s.mu.Lock()
+		switch typ {
+		case fxpOpendir:
+			dir := p.string()
+			s.handles["d"+dir] = dir
+			s.listed[dir] = false
+			client.send(fxpHandle, id, "d"+dir)
+		case fxpReaddir:
+			dir := s.handles[p.string()]
+			if s.listed[dir] {
+				status(fxEOF)
+				break
+			}
+			s.listed[dir] = true
+			var names []string
+			for name := range s.files {
+				if strings.HasPrefix(name, dir+"/") && !strings.Contains(name[len(dir)+1:], "/") {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			payload := []any{id, uint32(len(names) + 1), ".", "", uint32(attrPerms), uint32(0o040755)}
+			for _, name := range names {
+				payload = append(payload, name[len(dir)+1:], "", uint32(attrSize|attrPerms), uint64(len(s.files[name])), uint32(0o100644))
+			}
+			client.send(fxpName, payload...)
+		case fxpOpen:
+			file := p.string()
+			if _, ok := s.files[file]; !ok {
+				status(2)
+				break
+			}
+			s.handles["f"+file] = file
+			client.send(fxpHandle, id, "f"+file)
+		case fxpRead:
+			data := s.files[s.handles[p.string()]]
+			offset, length := p.uint64(), p.uint32()
+			if offset >= uint64(len(data)) {
+				status(fxEOF)
+				break
+			}
+			end := min(offset+uint64(length), uint64(len(data)))
+			client.send(fxpData, id, data[offset:end])
+		case fxpClose:
+			status(fxOK)
+		case fxpMkdir:
+			s.dirs[p.string()] = true
+			status(fxOK)
+		case fxpRename:
+			from, to := p.string(), p.string()
+			s.files[to] = s.files[from]
+			delete(s.files, from)
+			status(fxOK)
+		default:
+			status(8) // SSH_FX_OP_UNSUPPORTED
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *fakeSFTPServer) names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for name := range s.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestWatcher_IngestsSettledFilesAndMovesThem(t *testing.T) {
+	big := "id\n" + strings.Repeat("G-1\n", 20000) // several read chunks
+	server := newFakeSFTPServer(map[string]string{
+		"/in/guardians_1.txt":  big,
+		"/in/students_1.csv":   "Student No\nS-1\n",
+		"/in/notes.csv":        "ignored",
+		"/in/.guardians_2.txt": "id\nG-2\n",
+	})
+	importer := &recordingImporter{}
+	watcher, err := NewWatcher(newTestIngestor(t, importer), SFTPConfig{Addr: "sftp.example.com", User: "sis", Password: "pw", InsecureIgnoreHostKey: true, Dir: "/in"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher.dial = server.dial
+	watcher.now = func() time.Time { return time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	// The first poll only records sizes.
+	if reports, err := watcher.Poll(ctx); err != nil || len(reports) != 0 {
+		t.Fatalf("first poll = %v, %v; want nothing ingested", reports, err)
+	}
+
+	reports, err := watcher.Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Imported != 20000 || len(importer.rows) != 20000 {
+		t.Errorf("reports = %+v, want the guardians file imported", reports)
+	}
+	want := []string{
+		"/in/.guardians_2.txt",
+		"/in/failed/20260301T020000Z-students_1.csv", // no last name column
+		"/in/notes.csv",
+		"/in/processed/20260301T020000Z-guardians_1.txt",
+	}
+	if got := server.names(); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestWatcher_WaitsForGrowingFiles(t *testing.T) {
+	server := newFakeSFTPServer(map[string]string{"/in/guardians_1.txt": "id\n"})
+	importer := &recordingImporter{}
+	watcher, err := NewWatcher(newTestIngestor(t, importer), SFTPConfig{Addr: "h", User: "u", Password: "p", InsecureIgnoreHostKey: true, Dir: "/in"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher.dial = server.dial
+	ctx := context.Background()
+
+	watcher.Poll(ctx)
+	server.mu.Lock()
+	server.files["/in/guardians_1.txt"] = []byte("id\nG-1\n")
+	server.mu.Unlock()
+	if reports, _ := watcher.Poll(ctx); len(reports) != 0 {
+		t.Fatalf("ingested a file still being written: %+v", reports)
+	}
+	if reports, _ := watcher.Poll(ctx); len(reports) != 1 || reports[0].Imported != 1 {
+		t.Errorf("settled file = %+v", reports)
+	}
+}
+
+func TestPacketReader_ShortPacket(t *testing.T) {
+	p := &packetReader{buf: binary.BigEndian.AppendUint32(nil, 10)}
+	if p.string(); p.err == nil {
+		t.Error("decoded a string longer than the packet")
+	}
+}
@@ -0,0 +1,168 @@
+package jobqueue
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// The job queue endpoints, scoped to the caller's workspace:
+//
+//	GET  JobsPath              the workspace's jobs, newest first;
+//	                           ?status=dead lists the dead letters,
+//	                           ?kind=... and ?limit=... narrow it
+//	POST RetryPath?id=...      run a dead job again with fresh attempts
+//
+// Listing needs job_queue:list, retrying job_queue:update.
+const (
+	JobsPath  = "/api/job-queue"
+	RetryPath = "/api/job-queue/retry"
+)
+
+// entityJobQueue is the permission entity of the endpoints.
+const entityJobQueue = "job_queue"
+
+const maxListLimit = 500
+
+type jobJSON struct {
+	ID          string          `json:"id"`
+	Kind        string          `json:"kind"`
+	Status      ports.JobStatus `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       int64           `json:"run_at"`
+	LastError   string          `json:"last_error,omitempty"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	CreatedAt   int64           `json:"created_at"`
+	UpdatedAt   int64           `json:"updated_at"`
+}
+
+// Handlers serves the job queue endpoints.
+type Handlers struct {
+	queue ports.JobQueue
+	gate  *actiongate.ActionGatekeeper
+}
+
+// NewHandlers creates the endpoint handlers over queue. gate decides who
+// may see and retry the workspace's jobs.
+func NewHandlers(queue ports.JobQueue, gate *actiongate.ActionGatekeeper) *Handlers {
+	return &Handlers{queue: queue, gate: gate}
+}
+
+// Jobs lists the workspace's jobs.
+func (h *Handlers) Jobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return
+	}
+	workspaceID, ok := h.allowed(w, r, entityid.ActionList)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	filter := ports.JobFilter{
+		WorkspaceID: workspaceID,
+		Kind:        query.Get("kind"),
+		Status:      ports.JobStatus(query.Get("status")),
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "limit must be a positive number"})
+			return
+		}
+		filter.Limit = min(limit, maxListLimit)
+	}
+	jobs, err := h.queue.List(r.Context(), filter)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	data := make([]jobJSON, 0, len(jobs))
+	for _, job := range jobs {
+		data = append(data, toJobJSON(job))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": data})
+}
+
+// Retry makes a dead job of the workspace pending again.
+func (h *Handlers) Retry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return
+	}
+	workspaceID, ok := h.allowed(w, r, entityid.ActionUpdate)
+	if !ok {
+		return
+	}
+	id := r.URL.Query().Get("id")
+	job, err := h.queue.Get(r.Context(), id)
+	if err == nil && job.WorkspaceID != workspaceID {
+		err = ports.ErrJobNotFound
+	}
+	if err == nil {
+		job, err = h.queue.Retry(r.Context(), id)
+	}
+	switch {
+	case errors.Is(err, ports.ErrJobNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]any{"success": false, "error": err.Error()})
+	case errors.Is(err, ports.ErrJobNotDead):
+		writeJSON(w, http.StatusConflict, map[string]any{"success": false, "error": err.Error()})
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+	default:
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": toJobJSON(job)})
+	}
+}
+
+// allowed checks the caller may take action on the jobs of their
+// workspace, writing the refusal when not.
+func (h *Handlers) allowed(w http.ResponseWriter, r *http.Request, action string) (string, bool) {
+	ctx := r.Context()
+	if contextutil.ExtractUserIDFromContext(ctx) == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return "", false
+	}
+	workspaceID := contextutil.ExtractWorkspaceIDFromContext(ctx)
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "workspace required"})
+		return "", false
+	}
+	if err := h.gate.Check(ctx, &actiongate.CheckActionRequest{Entity: entityJobQueue, Action: action}); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": err.Error()})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+// toJobJSON shows JSON payloads inline and leaves other payloads out.
+func toJobJSON(job *ports.Job) jobJSON {
+	out := jobJSON{
+		ID:          job.ID,
+		Kind:        job.Kind,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt.UnixMilli(),
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt.UnixMilli(),
+		UpdatedAt:   job.UpdatedAt.UnixMilli(),
+	}
+	if json.Valid(job.Payload) {
+		out.Payload = job.Payload
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,254 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/jobqueue/memory"
+)
+
+// clock is a settable time source shared by the queue and the runner.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestRunner(config Config) (*Runner, *memory.Queue, *clock) {
+	c := &clock{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	queue := memory.NewQueue()
+	queue.SetClock(c.Now)
+	r := NewRunner(queue, config)
+	r.now = c.Now
+	return r, queue, c
+}
+
+func TestRunner_RetriesThenDeadLetters(t *testing.T) {
+	r, queue, c := newTestRunner(Config{BaseBackoff: time.Minute, MaxAttempts: 3})
+	ctx := context.Background()
+
+	var payloads []string
+	r.Handle("invoice.generate", func(ctx context.Context, job *ports.Job) error {
+		payloads = append(payloads, string(job.Payload)+"@"+contextutil.ExtractWorkspaceIDFromContext(ctx))
+		return errors.New("ledger locked")
+	}, 1)
+
+	job, err := r.Enqueue(ctx, "invoice.generate", "ws-1", map[string]string{"subscription_id": "sub-1"}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := r.RunDue(ctx); n != 1 {
+		t.Fatalf("first run started %d jobs", n)
+	}
+	if n := r.RunDue(ctx); n != 0 {
+		t.Fatalf("retried before the backoff: %d", n)
+	}
+
+	// Backoff doubles: one minute after the first failure, two after the
+	// second; the third failure is the last attempt.
+	c.Advance(time.Minute)
+	r.RunDue(ctx)
+	c.Advance(time.Minute)
+	if n := r.RunDue(ctx); n != 0 {
+		t.Fatal("second backoff did not double")
+	}
+	c.Advance(time.Minute)
+	r.RunDue(ctx)
+
+	got, _ := queue.Get(ctx, job.ID)
+	if got.Status != ports.JobDead || got.Attempts != 3 || got.LastError != "ledger locked" {
+		t.Fatalf("job = %+v", got)
+	}
+	if len(payloads) != 3 || payloads[0] != `{"subscription_id":"sub-1"}@ws-1` {
+		t.Errorf("handler saw %v", payloads)
+	}
+
+	dead, _ := queue.List(ctx, ports.JobFilter{Status: ports.JobDead})
+	if len(dead) != 1 {
+		t.Fatalf("dead letters = %d", len(dead))
+	}
+	if _, err := queue.Retry(ctx, job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if n := r.RunDue(ctx); n != 1 {
+		t.Fatalf("retried job did not run: %d", n)
+	}
+}
+
+func TestRunner_CompletesAndDeadLettersPermanentErrors(t *testing.T) {
+	r, queue, _ := newTestRunner(Config{})
+	ctx := context.Background()
+
+	r.Handle("sheet.sync", func(ctx context.Context, job *ports.Job) error {
+		if string(job.Payload) == "bad" {
+			return Permanent(errors.New("unknown sheet"))
+		}
+		if string(job.Payload) == "panic" {
+			panic("boom")
+		}
+		return nil
+	}, 0)
+	ok, _ := r.Enqueue(ctx, "sheet.sync", "ws-1", []byte("good"), time.Time{})
+	bad, _ := r.Enqueue(ctx, "sheet.sync", "ws-1", []byte("bad"), time.Time{})
+	panicking, _ := r.Enqueue(ctx, "sheet.sync", "ws-1", []byte("panic"), time.Time{})
+	other, _ := r.Enqueue(ctx, "unhandled", "ws-1", nil, time.Time{})
+
+	if n := r.RunDue(ctx); n != 3 {
+		t.Fatalf("ran %d jobs", n)
+	}
+	for id, want := range map[string]ports.JobStatus{
+		ok.ID:        ports.JobSucceeded,
+		bad.ID:       ports.JobDead,
+		panicking.ID: ports.JobPending,
+		other.ID:     ports.JobPending,
+	} {
+		if job, _ := queue.Get(ctx, id); job.Status != want {
+			t.Errorf("%s: status %s, want %s (%s)", job.Payload, job.Status, want, job.LastError)
+		}
+	}
+	if job, _ := queue.Get(ctx, panicking.ID); job.LastError != "panic: boom" {
+		t.Errorf("panic recorded as %q", job.LastError)
+	}
+}
+
+func TestRunner_ConcurrencyLimit(t *testing.T) {
+	r, _, _ := newTestRunner(Config{Workers: 8})
+	ctx := context.Background()
+
+	var running, peak atomic.Int32
+	entered := make(chan struct{}, 5)
+	release := make(chan struct{})
+	r.Handle("export", func(context.Context, *ports.Job) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		entered <- struct{}{}
+		<-release
+		running.Add(-1)
+		return nil
+	}, 2)
+	for i := 0; i < 5; i++ {
+		r.Enqueue(ctx, "export", "ws-1", nil, time.Time{})
+	}
+
+	if n := r.poll(ctx); n != 2 {
+		t.Fatalf("started %d jobs with a limit of 2", n)
+	}
+	<-entered
+	<-entered
+	if n := r.poll(ctx); n != 0 {
+		t.Fatalf("started %d more while the limit was reached", n)
+	}
+	close(release)
+	r.wg.Wait()
+	if n := r.RunDue(ctx); n != 2 {
+		t.Fatalf("second round started %d", n)
+	}
+	if peak.Load() != 2 {
+		t.Errorf("peak concurrency %d", peak.Load())
+	}
+}
+
+func TestMemoryQueue_ReclaimsExpiredLease(t *testing.T) {
+	_, queue, c := newTestRunner(Config{})
+	ctx := context.Background()
+
+	job, _ := queue.Enqueue(ctx, ports.EnqueueRequest{Kind: "k"})
+	first, _ := queue.Claim(ctx, "k", 10, time.Minute)
+	if len(first) != 1 {
+		t.Fatal("not claimed")
+	}
+	if again, _ := queue.Claim(ctx, "k", 10, time.Minute); len(again) != 0 {
+		t.Fatal("claimed twice within the lease")
+	}
+	c.Advance(2 * time.Minute)
+	second, _ := queue.Claim(ctx, "k", 10, time.Minute)
+	if len(second) != 1 || second[0].Attempts != 2 {
+		t.Fatalf("reclaim = %+v", second)
+	}
+
+	// The stale worker's outcome no longer applies.
+	queue.Complete(ctx, first[0])
+	if got, _ := queue.Get(ctx, job.ID); got.Status != ports.JobRunning {
+		t.Errorf("stale completion applied: %s", got.Status)
+	}
+	queue.Complete(ctx, second[0])
+	if got, _ := queue.Get(ctx, job.ID); got.Status != ports.JobSucceeded {
+		t.Errorf("status = %s", got.Status)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for failures, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 10: 10 * time.Second} {
+		if got := Backoff(time.Second, 10*time.Second, failures); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	queue := memory.NewQueue()
+	ctx := context.Background()
+	dead, _ := queue.Enqueue(ctx, ports.EnqueueRequest{Kind: "sync", WorkspaceID: "ws-1", Payload: []byte(`{"sheet":"a"}`), MaxAttempts: 1})
+	claimed, _ := queue.Claim(ctx, "sync", 1, time.Minute)
+	queue.Fail(ctx, claimed[0], "quota exceeded", time.Time{})
+	foreign, _ := queue.Enqueue(ctx, ports.EnqueueRequest{Kind: "sync", WorkspaceID: "ws-2"})
+
+	h := NewHandlers(queue, testutil.OpenActionGate())
+	userCtx := contextutil.WithWorkspaceID(contextutil.WithUserID(ctx, "u-1"), "ws-1")
+	call := func(ctx context.Context, handler http.HandlerFunc, method, target string) (int, map[string]any) {
+		req := httptest.NewRequest(method, target, nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+
+	if code, _ := call(ctx, h.Jobs, http.MethodGet, JobsPath); code != http.StatusUnauthorized {
+		t.Errorf("anonymous list = %d", code)
+	}
+	code, out := call(userCtx, h.Jobs, http.MethodGet, JobsPath+"?status=dead")
+	data, _ := out["data"].([]any)
+	if code != http.StatusOK || len(data) != 1 {
+		t.Fatalf("list = %d %v", code, out)
+	}
+	if job := data[0].(map[string]any); job["id"] != dead.ID || job["last_error"] != "quota exceeded" || job["payload"].(map[string]any)["sheet"] != "a" {
+		t.Errorf("dead letter = %v", job)
+	}
+
+	if code, _ := call(userCtx, h.Retry, http.MethodPost, RetryPath+"?id="+foreign.ID); code != http.StatusNotFound {
+		t.Errorf("retry of another workspace's job = %d", code)
+	}
+	if code, out := call(userCtx, h.Retry, http.MethodPost, RetryPath+"?id="+dead.ID); code != http.StatusOK || out["data"].(map[string]any)["status"] != "pending" {
+		t.Errorf("retry = %d %v", code, out)
+	}
+	if code, _ := call(userCtx, h.Retry, http.MethodPost, RetryPath+"?id="+dead.ID); code != http.StatusConflict {
+		t.Errorf("retry of a pending job = %d", code)
+	}
+}
@@ -0,0 +1,220 @@
+// Package memory provides an in-memory JobQueue for tests and single
+// instance development. Jobs do not survive a restart and are not shared
+// between instances; use the postgres queue for anything else.
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+type entry struct {
+	job         ports.Job
+	lockedUntil time.Time
+}
+
+// Queue keeps jobs in a map guarded by a mutex.
+type Queue struct {
+	mu   sync.Mutex
+	jobs map[string]*entry
+	now  func() time.Time
+}
+
+var _ ports.JobQueue = (*Queue)(nil)
+
+// NewQueue creates an empty queue.
+func NewQueue() *Queue {
+	return &Queue{jobs: make(map[string]*entry), now: time.Now}
+}
+
+// SetClock replaces the time source, letting tests make jobs due without
+// sleeping.
+func (q *Queue) SetClock(now func() time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.now = now
+}
+
+func (q *Queue) Name() string {
+	return "memory"
+}
+
+func (q *Queue) Enqueue(_ context.Context, req ports.EnqueueRequest) (*ports.Job, error) {
+	if req.Kind == "" {
+		return nil, fmt.Errorf("job kind is required")
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	job := ports.Job{
+		ID:          newID(),
+		Kind:        req.Kind,
+		WorkspaceID: req.WorkspaceID,
+		Payload:     append([]byte(nil), req.Payload...),
+		Status:      ports.JobPending,
+		MaxAttempts: req.MaxAttempts,
+		RunAt:       req.RunAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = ports.DefaultJobMaxAttempts
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = now
+	}
+	q.jobs[job.ID] = &entry{job: job}
+	return copyJob(job), nil
+}
+
+func (q *Queue) Claim(_ context.Context, kind string, limit int, lease time.Duration) ([]*ports.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	var due []*entry
+	for _, e := range q.jobs {
+		if e.job.Kind != kind {
+			continue
+		}
+		pending := e.job.Status == ports.JobPending && !e.job.RunAt.After(now)
+		expired := e.job.Status == ports.JobRunning && e.lockedUntil.Before(now)
+		if pending || expired {
+			due = append(due, e)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].job.RunAt.Before(due[j].job.RunAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*ports.Job, 0, len(due))
+	for _, e := range due {
+		e.job.Status = ports.JobRunning
+		e.job.Attempts++
+		e.job.UpdatedAt = now
+		e.lockedUntil = now.Add(lease)
+		claimed = append(claimed, copyJob(e.job))
+	}
+	return claimed, nil
+}
+
+func (q *Queue) Complete(_ context.Context, job *ports.Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	e, ok := q.claimed(job)
+	if !ok {
+		return nil
+	}
+	e.job.Status = ports.JobSucceeded
+	e.job.LastError = ""
+	e.job.UpdatedAt = q.now()
+	return nil
+}
+
+func (q *Queue) Fail(_ context.Context, job *ports.Job, reason string, retryAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	e, ok := q.claimed(job)
+	if !ok {
+		return nil
+	}
+	e.job.LastError = reason
+	e.job.UpdatedAt = q.now()
+	if retryAt.IsZero() {
+		e.job.Status = ports.JobDead
+		return nil
+	}
+	e.job.Status = ports.JobPending
+	e.job.RunAt = retryAt
+	return nil
+}
+
+// claimed finds the entry job was claimed from, if that claim still holds.
+func (q *Queue) claimed(job *ports.Job) (*entry, bool) {
+	e, ok := q.jobs[job.ID]
+	if !ok || e.job.Status != ports.JobRunning || e.job.Attempts != job.Attempts {
+		return nil, false
+	}
+	return e, true
+}
+
+func (q *Queue) Retry(_ context.Context, id string) (*ports.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	e, ok := q.jobs[id]
+	if !ok {
+		return nil, ports.ErrJobNotFound
+	}
+	if e.job.Status != ports.JobDead {
+		return nil, ports.ErrJobNotDead
+	}
+	now := q.now()
+	e.job.Status = ports.JobPending
+	e.job.Attempts = 0
+	e.job.RunAt = now
+	e.job.UpdatedAt = now
+	return copyJob(e.job), nil
+}
+
+func (q *Queue) Get(_ context.Context, id string) (*ports.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	e, ok := q.jobs[id]
+	if !ok {
+		return nil, ports.ErrJobNotFound
+	}
+	return copyJob(e.job), nil
+}
+
+func (q *Queue) List(_ context.Context, filter ports.JobFilter) ([]*ports.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var jobs []*ports.Job
+	for _, e := range q.jobs {
+		if (filter.WorkspaceID != "" && e.job.WorkspaceID != filter.WorkspaceID) ||
+			(filter.Kind != "" && e.job.Kind != filter.Kind) ||
+			(filter.Status != "" && e.job.Status != filter.Status) {
+			continue
+		}
+		jobs = append(jobs, copyJob(e.job))
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID > jobs[j].ID
+	})
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+func (q *Queue) Close() error {
+	return nil
+}
+
+func copyJob(job ports.Job) *ports.Job {
+	job.Payload = append([]byte(nil), job.Payload...)
+	return &job
+}
+
+func newID() string {
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return "job_" + hex.EncodeToString(b[:])
+}
@@ -0,0 +1,219 @@
+// Package postgres provides the JobQueue shared by every server instance,
+// stored in the job_queue table (see the postgres integration migration
+// 000013_job_queue). Workers claim due jobs with SELECT ... FOR UPDATE
+// SKIP LOCKED, so instances polling at the same time never claim the
+// same job.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	sqlexec "github.com/erniealice/espyna-golang/database/sqlexec"
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+)
+
+// DefaultTable is the table holding jobs.
+const DefaultTable = "job_queue"
+
+const columns = `id, kind, workspace_id, payload, status, attempts, max_attempts, run_at, last_error, date_created, date_modified`
+
+// Queue stores jobs in a Postgres table through a raw SQL executor.
+type Queue struct {
+	db    sqlexec.DBExecutor
+	table string
+	now   func() time.Time
+}
+
+var _ ports.JobQueue = (*Queue)(nil)
+
+// NewQueue creates a queue on table (DefaultTable when empty). db is
+// usually the *sql.DB of the postgres database provider.
+func NewQueue(db sqlexec.DBExecutor, table string) *Queue {
+	if table == "" {
+		table = DefaultTable
+	}
+	return &Queue{db: db, table: table, now: time.Now}
+}
+
+func (q *Queue) Name() string {
+	return "postgres"
+}
+
+func (q *Queue) Enqueue(ctx context.Context, req ports.EnqueueRequest) (*ports.Job, error) {
+	if req.Kind == "" {
+		return nil, fmt.Errorf("job kind is required")
+	}
+	now := q.now().UTC()
+	runAt := req.RunAt.UTC()
+	if req.RunAt.IsZero() {
+		runAt = now
+	}
+	maxAttempts := req.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = ports.DefaultJobMaxAttempts
+	}
+	query := `INSERT INTO ` + q.table + ` (id, kind, workspace_id, payload, status, attempts, max_attempts, run_at, last_error, date_created, date_modified)
+		VALUES ($1, $2, $3, $4, $5, 0, $6, $7, '', $8, $8)
+		RETURNING ` + columns
+	row := q.db.QueryRowContext(ctx, query, newID(), req.Kind, req.WorkspaceID, req.Payload, string(ports.JobPending), maxAttempts, runAt, now)
+	job, err := scanJob(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue %s job: %w", req.Kind, err)
+	}
+	return job, nil
+}
+
+func (q *Queue) Claim(ctx context.Context, kind string, limit int, lease time.Duration) ([]*ports.Job, error) {
+	now := q.now().UTC()
+	query := `UPDATE ` + q.table + `
+		   SET status = 'running', attempts = attempts + 1, locked_until = $3, date_modified = $4
+		 WHERE id IN (
+			SELECT id FROM ` + q.table + `
+			 WHERE kind = $1
+			   AND ((status = 'pending' AND run_at <= $4) OR (status = 'running' AND locked_until < $4))
+			 ORDER BY run_at
+			 LIMIT $2
+			   FOR UPDATE SKIP LOCKED)
+		RETURNING ` + columns
+	rows, err := q.db.QueryContext(ctx, query, kind, limit, now.Add(lease), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim %s jobs: %w", kind, err)
+	}
+	return scanJobs(rows)
+}
+
+func (q *Queue) Complete(ctx context.Context, job *ports.Job) error {
+	query := `UPDATE ` + q.table + `
+		   SET status = 'succeeded', last_error = '', locked_until = NULL, date_modified = $3
+		 WHERE id = $1 AND attempts = $2 AND status = 'running'`
+	if _, err := q.db.ExecContext(ctx, query, job.ID, job.Attempts, q.now().UTC()); err != nil {
+		return fmt.Errorf("failed to complete job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (q *Queue) Fail(ctx context.Context, job *ports.Job, reason string, retryAt time.Time) error {
+	now := q.now().UTC()
+	status, runAt := ports.JobPending, retryAt.UTC()
+	if retryAt.IsZero() {
+		status, runAt = ports.JobDead, now
+	}
+	query := `UPDATE ` + q.table + `
+		   SET status = $3, run_at = $4, last_error = $5, locked_until = NULL, date_modified = $6
+		 WHERE id = $1 AND attempts = $2 AND status = 'running'`
+	if _, err := q.db.ExecContext(ctx, query, job.ID, job.Attempts, string(status), runAt, reason, now); err != nil {
+		return fmt.Errorf("failed to record failure of job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (q *Queue) Retry(ctx context.Context, id string) (*ports.Job, error) {
+	now := q.now().UTC()
+	query := `UPDATE ` + q.table + `
+		   SET status = 'pending', attempts = 0, run_at = $2, date_modified = $2
+		 WHERE id = $1 AND status = 'dead'
+		RETURNING ` + columns
+	job, err := scanJob(q.db.QueryRowContext(ctx, query, id, now))
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, getErr := q.Get(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, ports.ErrJobNotDead
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry job %s: %w", id, err)
+	}
+	return job, nil
+}
+
+func (q *Queue) Get(ctx context.Context, id string) (*ports.Job, error) {
+	job, err := scanJob(q.db.QueryRowContext(ctx, `SELECT `+columns+` FROM `+q.table+` WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ports.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job %s: %w", id, err)
+	}
+	return job, nil
+}
+
+func (q *Queue) List(ctx context.Context, filter ports.JobFilter) ([]*ports.Job, error) {
+	var where []string
+	var args []any
+	add := func(column, value string) {
+		if value != "" {
+			args = append(args, value)
+			where = append(where, fmt.Sprintf("%s = $%d", column, len(args)))
+		}
+	}
+	add("workspace_id", filter.WorkspaceID)
+	add("kind", filter.Kind)
+	add("status", string(filter.Status))
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	query := `SELECT ` + columns + ` FROM ` + q.table
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(` ORDER BY date_created DESC, id DESC LIMIT $%d`, len(args))
+
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return scanJobs(rows)
+}
+
+// Close leaves the connection to the database provider that owns it.
+func (q *Queue) Close() error {
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row scanner) (*ports.Job, error) {
+	var job ports.Job
+	var status string
+	if err := row.Scan(&job.ID, &job.Kind, &job.WorkspaceID, &job.Payload, &status, &job.Attempts,
+		&job.MaxAttempts, &job.RunAt, &job.LastError, &job.CreatedAt, &job.UpdatedAt); err != nil {
+		return nil, err
+	}
+	job.Status = ports.JobStatus(status)
+	return &job, nil
+}
+
+func scanJobs(rows *sql.Rows) ([]*ports.Job, error) {
+	defer rows.Close()
+	var jobs []*ports.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// newID mints the job's primary key with the container's ID generator,
+// falling back to a random UUID.
+func newID() string {
+	if gen := registry.GetDefaultIDGenerator(); gen != nil {
+		return gen.GenerateID()
+	}
+	return uuid.NewString()
+}
@@ -0,0 +1,296 @@
+// Package jobqueue runs deferred work, such as invoice generation or
+// tabular sync, from a JobQueue: handlers register per job kind with a
+// concurrency limit, failed jobs are retried with exponential backoff, and
+// jobs out of attempts wait in the dead-letter state, listed and retried
+// over the API (see JobsPath). The queues live in the subpackages: memory
+// for tests and a single instance, postgres for everything else.
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+)
+
+// Runner defaults.
+const (
+	DefaultWorkers      = 4
+	DefaultPollInterval = 5 * time.Second
+	DefaultLease        = 5 * time.Minute
+	DefaultBaseBackoff  = 30 * time.Second
+	DefaultMaxBackoff   = 6 * time.Hour
+)
+
+// Config tunes a Runner; zero fields take the defaults.
+type Config struct {
+	// Workers bounds the jobs running at once, across all kinds.
+	Workers int
+	// PollInterval is how often the queue is checked for due jobs while
+	// no job finishes.
+	PollInterval time.Duration
+	// Lease bounds a job's run; a job still held when it runs out is
+	// claimed again.
+	Lease time.Duration
+	// BaseBackoff is the wait after the first failure, doubling after
+	// each further one up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// MaxAttempts applies to jobs enqueued through the runner without
+	// their own; zero leaves it to the queue (DefaultJobMaxAttempts).
+	MaxAttempts int
+}
+
+// Handler does the work of one job. The context carries the job's
+// workspace and is cancelled when the lease runs out. An error retries the
+// job later, unless it is wrapped with Permanent.
+type Handler func(ctx context.Context, job *ports.Job) error
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying, such as a payload that does
+// not decode: the job goes straight to the dead-letter state.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+type registration struct {
+	handler Handler
+	limit   int
+	running int
+}
+
+// Runner claims due jobs from a queue and runs them on a bounded pool of
+// goroutines.
+type Runner struct {
+	queue  ports.JobQueue
+	config Config
+	now    func() time.Time
+
+	mu       sync.Mutex
+	handlers map[string]*registration
+	running  int
+	wake     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewRunner creates a runner over queue.
+func NewRunner(queue ports.JobQueue, config Config) *Runner {
+	if config.Workers <= 0 {
+		config.Workers = DefaultWorkers
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultLease
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = DefaultBaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+	return &Runner{
+		queue:    queue,
+		config:   config,
+		now:      time.Now,
+		handlers: make(map[string]*registration),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Queue returns the queue the runner works from.
+func (r *Runner) Queue() ports.JobQueue {
+	return r.queue
+}
+
+// Handle registers handler for jobs of kind, running at most concurrency
+// of them at once (zero for no limit beyond Config.Workers). Jobs of kinds
+// without a handler stay in the queue.
+func (r *Runner) Handle(kind string, handler Handler, concurrency int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if concurrency <= 0 {
+		concurrency = r.config.Workers
+	}
+	r.handlers[kind] = &registration{handler: handler, limit: concurrency}
+}
+
+// Enqueue adds a job of kind for workspaceID that runs at runAt, or as
+// soon as possible when runAt is zero. payload is stored as is when it is
+// []byte and as JSON otherwise.
+func (r *Runner) Enqueue(ctx context.Context, kind, workspaceID string, payload any, runAt time.Time) (*ports.Job, error) {
+	raw, ok := payload.([]byte)
+	if !ok && payload != nil {
+		var err error
+		if raw, err = json.Marshal(payload); err != nil {
+			return nil, fmt.Errorf("failed to encode %s job payload: %w", kind, err)
+		}
+	}
+	job, err := r.queue.Enqueue(ctx, ports.EnqueueRequest{
+		Kind:        kind,
+		WorkspaceID: workspaceID,
+		Payload:     raw,
+		RunAt:       runAt,
+		MaxAttempts: r.config.MaxAttempts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if runAt.IsZero() || !runAt.After(r.now()) {
+		r.signal()
+	}
+	return job, nil
+}
+
+// Run works the queue until ctx is done, then waits for the running jobs.
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		r.poll(ctx)
+		select {
+		case <-ctx.Done():
+			r.wg.Wait()
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// RunDue claims the jobs due now that fit the free workers, runs them and
+// waits for them to finish. It returns how many ran.
+func (r *Runner) RunDue(ctx context.Context) int {
+	n := r.poll(ctx)
+	r.wg.Wait()
+	return n
+}
+
+// poll claims due jobs for the free slots of each kind and starts them.
+func (r *Runner) poll(ctx context.Context) int {
+	r.mu.Lock()
+	kinds := make([]string, 0, len(r.handlers))
+	for kind := range r.handlers {
+		kinds = append(kinds, kind)
+	}
+	r.mu.Unlock()
+	sort.Strings(kinds)
+
+	started := 0
+	for _, kind := range kinds {
+		if ctx.Err() != nil {
+			break
+		}
+		r.mu.Lock()
+		reg := r.handlers[kind]
+		free := min(reg.limit-reg.running, r.config.Workers-r.running)
+		r.mu.Unlock()
+		if free <= 0 {
+			continue
+		}
+
+		jobs, err := r.queue.Claim(ctx, kind, free, r.config.Lease)
+		if err != nil {
+			log.Printf("jobqueue: %v", err)
+			continue
+		}
+		for _, job := range jobs {
+			r.mu.Lock()
+			reg.running++
+			r.running++
+			r.mu.Unlock()
+			r.wg.Add(1)
+			go r.run(reg, job)
+			started++
+		}
+	}
+	return started
+}
+
+func (r *Runner) run(reg *registration, job *ports.Job) {
+	defer func() {
+		r.mu.Lock()
+		reg.running--
+		r.running--
+		r.mu.Unlock()
+		r.wg.Done()
+		r.signal()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.config.Lease)
+	defer cancel()
+	if job.WorkspaceID != "" {
+		ctx = contextutil.WithWorkspaceID(ctx, job.WorkspaceID)
+	}
+
+	err := call(ctx, reg.handler, job)
+	// The outcome is written with a fresh context: a handler that ran out
+	// its lease still gets its failure recorded.
+	outcomeCtx, cancelOutcome := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelOutcome()
+	if err == nil {
+		if err := r.queue.Complete(outcomeCtx, job); err != nil {
+			log.Printf("jobqueue: %v", err)
+		}
+		return
+	}
+
+	var retryAt time.Time
+	var permanent permanentError
+	if !errors.As(err, &permanent) && job.Attempts < job.MaxAttempts {
+		retryAt = r.now().Add(Backoff(r.config.BaseBackoff, r.config.MaxBackoff, job.Attempts))
+	}
+	if retryAt.IsZero() {
+		log.Printf("jobqueue: %s job %s is dead after %d attempt(s): %v", job.Kind, job.ID, job.Attempts, err)
+	}
+	if err := r.queue.Fail(outcomeCtx, job, err.Error(), retryAt); err != nil {
+		log.Printf("jobqueue: %v", err)
+	}
+}
+
+// call runs handler, turning a panic into an error so one bad job cannot
+// take the process down.
+func call(ctx context.Context, handler Handler, job *ports.Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// signal wakes Run to claim more jobs.
+func (r *Runner) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Backoff is the wait before the retry after the given number of failed
+// attempts: base, doubling per further failure, capped at max.
+func Backoff(base, max time.Duration, failures int) time.Duration {
+	wait := base
+	for i := 1; i < failures; i++ {
+		wait *= 2
+		if wait >= max {
+			return max
+		}
+	}
+	return min(wait, max)
+}
@@ -0,0 +1,57 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	emailpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/email"
+)
+
+// EmailSender delivers notification messages through an email provider.
+type EmailSender struct {
+	provider ports.EmailProvider
+	from     string
+}
+
+var _ ports.NotificationSender = (*EmailSender)(nil)
+
+// NewEmailSender creates a sender for the email channel. from may be empty
+// to use the provider's default sender.
+func NewEmailSender(provider ports.EmailProvider, from string) *EmailSender {
+	return &EmailSender{provider: provider, from: from}
+}
+
+// Channel returns the email channel.
+func (s *EmailSender) Channel() ports.NotificationChannel {
+	return ports.NotificationChannelEmail
+}
+
+// Send emails msg to its recipient.
+func (s *EmailSender) Send(ctx context.Context, msg *ports.NotificationMessage) error {
+	if s.provider == nil || !s.provider.IsEnabled() {
+		return errors.New("email provider is not enabled")
+	}
+	data := &emailpb.EmailData{
+		To:       []*emailpb.EmailAddress{{Address: msg.Recipient}},
+		Subject:  msg.Subject,
+		TextBody: msg.TextBody,
+		HtmlBody: msg.HTMLBody,
+	}
+	if s.from != "" {
+		data.From = &emailpb.EmailAddress{Address: s.from}
+	}
+
+	resp, err := s.provider.SendEmail(ctx, &emailpb.SendEmailRequest{Data: data})
+	if err != nil {
+		return err
+	}
+	if resp != nil && !resp.Success {
+		if resp.Error != nil {
+			return fmt.Errorf("email provider %s: %s", s.provider.Name(), resp.Error.Message)
+		}
+		return fmt.Errorf("email provider %s rejected the message", s.provider.Name())
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+// Package digest batches notifications into digests and enforces
+// per-channel frequency caps before handing messages to channel senders.
+//
+// Each notification template has a Rule: Immediate templates are sent one
+// by one, Digest templates are held per (user, channel, group) and sent as
+// one message when the hourly or daily window closes, or earlier once
+// MaxBatch notifications have piled up. A channel Cap limits how many
+// messages a user receives per period; immediate notifications over the cap
+// are folded into the user's digest instead of being dropped.
+//
+// Pending digests are held in memory. Run flushes them on a ticker and
+// flushes everything on shutdown.
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// Engine implements ports.Notifier with digesting and frequency caps.
+// It is safe for concurrent use.
+type Engine struct {
+	config  Config
+	clock   ports.Clock
+	senders map[ports.NotificationChannel]ports.NotificationSender
+
+	mu      sync.Mutex
+	pending map[bucketKey]*bucket
+	sent    map[capKey][]time.Time
+}
+
+var _ ports.Notifier = (*Engine)(nil)
+
+type bucketKey struct {
+	userID  string
+	channel ports.NotificationChannel
+	group   string
+}
+
+type capKey struct {
+	userID  string
+	channel ports.NotificationChannel
+}
+
+// bucket is one pending digest.
+type bucket struct {
+	recipient string
+	subject   string
+	due       time.Time
+	items     []*ports.Notification
+}
+
+// New creates an Engine delivering through senders, one per channel. A nil
+// clock uses wall-clock time.
+func New(config Config, clock ports.Clock, senders ...ports.NotificationSender) *Engine {
+	e := &Engine{
+		config:  config,
+		clock:   clock,
+		senders: make(map[ports.NotificationChannel]ports.NotificationSender, len(senders)),
+		pending: make(map[bucketKey]*bucket),
+		sent:    make(map[capKey][]time.Time),
+	}
+	for _, s := range senders {
+		e.senders[s.Channel()] = s
+	}
+	return e
+}
+
+// Notify sends n now or holds it for a digest, following its template's
+// rule and the channel cap.
+func (e *Engine) Notify(ctx context.Context, n *ports.Notification) error {
+	if n == nil || n.UserID == "" || n.Recipient == "" {
+		return errors.New("notification requires a user and a recipient")
+	}
+	if _, ok := e.senders[n.Channel]; !ok {
+		return fmt.Errorf("no sender for notification channel %q", n.Channel)
+	}
+	now := ports.ClockNow(e.clock)
+	if n.OccurredAt.IsZero() {
+		copied := *n
+		copied.OccurredAt = now
+		n = &copied
+	}
+	rule := e.config.rule(n.Template)
+	ck := capKey{n.UserID, n.Channel}
+
+	e.mu.Lock()
+	if rule.Mode == Immediate {
+		if e.reserve(ck, now) {
+			e.mu.Unlock()
+			err := e.senders[n.Channel].Send(ctx, single(n))
+			if err != nil {
+				e.release(ck, now)
+			}
+			return err
+		}
+		// Over the cap: the notification waits for the user's next digest,
+		// which goes out no later than when the cap allows another message.
+		e.enqueue(n, rule, e.capFreesAt(ck, now))
+		e.mu.Unlock()
+		return nil
+	}
+
+	b := e.enqueue(n, rule, now.Truncate(rule.Window).Add(rule.Window))
+	full := rule.MaxBatch > 0 && len(b.items) >= rule.MaxBatch
+	if full {
+		b.due = now
+	}
+	e.mu.Unlock()
+
+	if full {
+		return e.Flush(ctx)
+	}
+	return nil
+}
+
+// enqueue adds n to its digest, creating it due at due, or moving an
+// existing digest's due time earlier. Callers hold e.mu.
+func (e *Engine) enqueue(n *ports.Notification, rule Rule, due time.Time) *bucket {
+	key := bucketKey{n.UserID, n.Channel, rule.Group}
+	b := e.pending[key]
+	if b == nil {
+		b = &bucket{subject: rule.Subject, due: due}
+		e.pending[key] = b
+	}
+	if due.Before(b.due) {
+		b.due = due
+	}
+	b.recipient = n.Recipient
+	b.items = append(b.items, n)
+	return b
+}
+
+// Flush sends every digest that is due, deferring those whose user is at
+// the channel cap. Digests that fail to send stay pending for the next
+// flush.
+func (e *Engine) Flush(ctx context.Context) error {
+	return e.flush(ctx, false)
+}
+
+// FlushAll sends every pending digest now, ignoring windows and caps. Run
+// calls it on shutdown so nothing held in memory is lost.
+func (e *Engine) FlushAll(ctx context.Context) error {
+	return e.flush(ctx, true)
+}
+
+func (e *Engine) flush(ctx context.Context, all bool) error {
+	now := ports.ClockNow(e.clock)
+	type ready struct {
+		key bucketKey
+		b   *bucket
+	}
+	var batch []ready
+
+	e.mu.Lock()
+	for key, b := range e.pending {
+		if !all && b.due.After(now) {
+			continue
+		}
+		ck := capKey{key.userID, key.channel}
+		if !all && !e.reserve(ck, now) {
+			b.due = e.capFreesAt(ck, now)
+			continue
+		}
+		delete(e.pending, key)
+		batch = append(batch, ready{key, b})
+	}
+	e.mu.Unlock()
+
+	var errs []error
+	for _, r := range batch {
+		if err := e.senders[r.key.channel].Send(ctx, render(r.b)); err != nil {
+			errs = append(errs, fmt.Errorf("digest for user %s on %s: %w", r.key.userID, r.key.channel, err))
+			e.requeue(r.key, r.b, now)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// requeue puts a digest that failed to send back in front of anything that
+// arrived meanwhile.
+func (e *Engine) requeue(key bucketKey, b *bucket, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.release(capKey{key.userID, key.channel}, now)
+	if current := e.pending[key]; current != nil {
+		b.items = append(b.items, current.items...)
+		b.recipient = current.recipient
+	}
+	e.pending[key] = b
+}
+
+// Pending returns the number of notifications held for digests.
+func (e *Engine) Pending() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n := 0
+	for _, b := range e.pending {
+		n += len(b.items)
+	}
+	return n
+}
+
+// Run flushes due digests every interval until ctx is done, then flushes
+// everything still pending.
+func (e *Engine) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := e.FlushAll(context.WithoutCancel(ctx)); err != nil {
+				log.Printf("⚠️  Warning: notification digests lost on shutdown: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if err := e.Flush(ctx); err != nil {
+				log.Printf("⚠️  Warning: notification digest flush failed: %v", err)
+			}
+		}
+	}
+}
+
+// reserve records a send for the cap if the user is under it. Callers hold
+// e.mu.
+func (e *Engine) reserve(key capKey, now time.Time) bool {
+	c, capped := e.config.Caps[key.channel]
+	if !capped || c.Max <= 0 || c.Per <= 0 {
+		return true
+	}
+	times := e.sent[key][:0]
+	for _, t := range e.sent[key] {
+		if now.Sub(t) < c.Per {
+			times = append(times, t)
+		}
+	}
+	if len(times) >= c.Max {
+		e.sent[key] = times
+		return false
+	}
+	e.sent[key] = append(times, now)
+	return true
+}
+
+// release undoes a reservation whose send failed. Callers hold e.mu.
+func (e *Engine) release(key capKey, at time.Time) {
+	times := e.sent[key]
+	for i := len(times) - 1; i >= 0; i-- {
+		if times[i].Equal(at) {
+			e.sent[key] = append(times[:i], times[i+1:]...)
+			return
+		}
+	}
+}
+
+// capFreesAt is when the user may receive another message on the channel.
+// Callers hold e.mu after reserve has pruned the send log.
+func (e *Engine) capFreesAt(key capKey, now time.Time) time.Time {
+	times := e.sent[key]
+	if len(times) == 0 {
+		return now
+	}
+	return times[0].Add(e.config.Caps[key.channel].Per)
+}
+
+func single(n *ports.Notification) *ports.NotificationMessage {
+	return &ports.NotificationMessage{
+		UserID:      n.UserID,
+		WorkspaceID: n.WorkspaceID,
+		Template:    n.Template,
+		Recipient:   n.Recipient,
+		Subject:     n.Subject,
+		TextBody:    n.TextBody,
+		HTMLBody:    n.HTMLBody,
+		Data:        n.Data,
+	}
+}
+
+// render builds a digest message, oldest notification first. A digest of
+// one is sent as that notification.
+func render(b *bucket) *ports.NotificationMessage {
+	if len(b.items) == 1 {
+		return single(b.items[0])
+	}
+	items := append([]*ports.Notification(nil), b.items...)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].OccurredAt.Before(items[j].OccurredAt) })
+
+	var text, body strings.Builder
+	body.WriteString("<ul>\n")
+	for _, n := range items {
+		fmt.Fprintf(&text, "- %s\n", n.Subject)
+		if n.TextBody != "" {
+			fmt.Fprintf(&text, "  %s\n", strings.ReplaceAll(strings.TrimSpace(n.TextBody), "\n", "\n  "))
+		}
+		text.WriteString("\n")
+
+		content := n.HTMLBody
+		if content == "" {
+			content = html.EscapeString(n.TextBody)
+		}
+		fmt.Fprintf(&body, "<li><strong>%s</strong><div>%s</div></li>\n", html.EscapeString(n.Subject), content)
+	}
+	body.WriteString("</ul>\n")
+
+	template := items[0].Template
+	for _, n := range items[1:] {
+		if n.Template != template {
+			template = ""
+			break
+		}
+	}
+
+	return &ports.NotificationMessage{
+		UserID:      items[0].UserID,
+		WorkspaceID: items[0].WorkspaceID,
+		Template:    template,
+		Recipient:   b.recipient,
+		Subject:     fmt.Sprintf(b.subject, len(items)),
+		TextBody:    strings.TrimRight(text.String(), "\n") + "\n",
+		HTMLBody:    body.String(),
+	}
+}
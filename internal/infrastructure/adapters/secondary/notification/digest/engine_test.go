@@ -0,0 +1,95 @@
+package digest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+type recordingSender struct{ sent []*ports.NotificationMessage }
+
+func (s *recordingSender) Channel() ports.NotificationChannel { return ports.NotificationChannelEmail }
+
+func (s *recordingSender) Send(_ context.Context, msg *ports.NotificationMessage) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func newTestEngine(config Config) (*Engine, *recordingSender, *ports.TravelClock) {
+	clock := ports.NewTravelClock()
+	clock.Set(time.Date(2026, 10, 16, 9, 15, 0, 0, time.UTC))
+	sender := &recordingSender{}
+	return New(config, clock, sender), sender, clock
+}
+
+func notify(t *testing.T, e *Engine, template, subject string) {
+	t.Helper()
+	err := e.Notify(context.Background(), &ports.Notification{
+		UserID: "u1", Channel: ports.NotificationChannelEmail, Template: template,
+		Recipient: "u1@example.com", Subject: subject, TextBody: subject + " body",
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+}
+
+func TestEngine_HourlyDigest(t *testing.T) {
+	e, sender, clock := newTestEngine(Config{Rules: map[string]Rule{
+		"post.new": {Mode: Digest, Window: Hourly, Subject: "%d new messages"},
+	}})
+	for _, s := range []string{"Ana replied", "Ben replied", "Cy replied"} {
+		notify(t, e, "post.new", s)
+	}
+	if err := e.Flush(context.Background()); err != nil || len(sender.sent) != 0 {
+		t.Fatalf("sent %d before the window closed (err %v)", len(sender.sent), err)
+	}
+
+	clock.Set(time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC))
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(sender.sent) != 1 || e.Pending() != 0 {
+		t.Fatalf("sent %d digests, %d pending", len(sender.sent), e.Pending())
+	}
+	msg := sender.sent[0]
+	if msg.Subject != "3 new messages" || msg.Recipient != "u1@example.com" ||
+		!strings.Contains(msg.TextBody, "- Ben replied\n  Ben replied body") || !strings.Contains(msg.HTMLBody, "<strong>Cy replied</strong>") {
+		t.Fatalf("digest = %+v", msg)
+	}
+}
+
+func TestEngine_MaxBatchSendsEarly(t *testing.T) {
+	e, sender, _ := newTestEngine(Config{Default: Rule{Mode: Digest, Window: Daily, MaxBatch: 2}})
+	notify(t, e, "any", "one")
+	notify(t, e, "other", "two")
+	if len(sender.sent) != 1 || sender.sent[0].Subject != "You have 2 new notifications" {
+		t.Fatalf("sent = %+v", sender.sent)
+	}
+}
+
+func TestEngine_CapFoldsIntoDigest(t *testing.T) {
+	e, sender, clock := newTestEngine(Config{
+		Caps: map[ports.NotificationChannel]Cap{ports.NotificationChannelEmail: {Max: 1, Per: time.Hour}},
+	})
+	notify(t, e, "invoice.paid", "first")
+	notify(t, e, "invoice.paid", "second")
+	notify(t, e, "invoice.paid", "third")
+	if len(sender.sent) != 1 || e.Pending() != 2 {
+		t.Fatalf("sent %d, pending %d; want 1 sent and 2 held by the cap", len(sender.sent), e.Pending())
+	}
+
+	clock.Advance(30 * time.Minute)
+	if err := e.Flush(context.Background()); err != nil || len(sender.sent) != 1 {
+		t.Fatalf("cap released early: sent %d (err %v)", len(sender.sent), err)
+	}
+	clock.Advance(30 * time.Minute)
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(sender.sent) != 2 || sender.sent[1].Subject != "You have 2 new notifications" {
+		t.Fatalf("sent = %+v", sender.sent)
+	}
+}
@@ -0,0 +1,79 @@
+package digest
+
+import (
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// Mode is how notifications of a template are delivered.
+type Mode int
+
+const (
+	// Immediate sends each notification on its own, subject to the
+	// channel's frequency cap.
+	Immediate Mode = iota
+	// Digest holds notifications and sends them as one message per user,
+	// channel and group when the window closes or MaxBatch is reached.
+	Digest
+)
+
+// Common digest windows. Windows are aligned to UTC: an hourly digest goes
+// out on the hour, a daily one at UTC midnight.
+const (
+	Hourly = time.Hour
+	Daily  = 24 * time.Hour
+)
+
+// Rule is the batching rule of one notification template.
+type Rule struct {
+	Mode Mode
+	// Window is how long a digest collects notifications. Defaults to Hourly.
+	Window time.Duration
+	// MaxBatch sends the digest early once it holds this many
+	// notifications. Zero means no limit.
+	MaxBatch int
+	// Group names the digest the template joins. Templates with the same
+	// group share one digest; the empty group is the user's general digest.
+	Group string
+	// Subject is the digest subject, formatted with the notification count
+	// (e.g. "You have %d new messages"). Defaults to DefaultDigestSubject.
+	Subject string
+}
+
+// DefaultDigestSubject is the subject of digests whose rule sets none.
+const DefaultDigestSubject = "You have %d new notifications"
+
+// Cap limits how many messages one user receives on a channel: at most Max
+// messages in any Per period. Notifications over the cap are folded into
+// the user's next digest instead of being dropped.
+type Cap struct {
+	Max int
+	Per time.Duration
+}
+
+// Config configures an Engine.
+type Config struct {
+	// Rules maps a notification template to its rule.
+	Rules map[string]Rule
+	// Default applies to templates without a rule. The zero value sends
+	// immediately.
+	Default Rule
+	// Caps maps a channel to its frequency cap. Channels without one are
+	// uncapped.
+	Caps map[ports.NotificationChannel]Cap
+}
+
+func (c Config) rule(template string) Rule {
+	r, ok := c.Rules[template]
+	if !ok {
+		r = c.Default
+	}
+	if r.Window <= 0 {
+		r.Window = Hourly
+	}
+	if r.Subject == "" {
+		r.Subject = DefaultDigestSubject
+	}
+	return r
+}
@@ -0,0 +1,295 @@
+// Package dispatch turns business events into notifications. A Rule per
+// event type resolves who the event concerns from the entity it is about,
+// picks each recipient's channel from their preferences (email, SMS, none),
+// renders the rule's templates and hands the notification to the Notifier,
+// retrying failed deliveries with backoff.
+//
+// The Dispatcher is a domainevent.Sink: events it has a rule for are queued
+// and delivered by Run, off the use case's goroutine.
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+const (
+	// DefaultQueueSize bounds the events waiting for Run.
+	DefaultQueueSize = 256
+	// DefaultAttempts and DefaultBackoff are the retry policy's defaults.
+	DefaultAttempts = 3
+	DefaultBackoff  = 2 * time.Second
+)
+
+// Recipient is someone an event concerns: a user, or an outside party such
+// as a booking's invitee, known only by address.
+type Recipient struct {
+	UserID string
+	Name   string
+	Email  string
+	Phone  string
+}
+
+// Resolver finds the recipients of an event.
+type Resolver func(ctx context.Context, e domainevent.Event) ([]Recipient, error)
+
+// Rule turns events of one type into notifications.
+type Rule struct {
+	// Template names the kind of notification; preferences and digest
+	// rules are keyed by it. Defaults to the event type.
+	Template   string
+	Recipients Resolver
+	// Channel is used for recipients without a preference (default email).
+	Channel ports.NotificationChannel
+	// Subject and Text are text/template sources, HTML an html/template
+	// source, executed with TemplateData. HTML is optional.
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// RetryPolicy spaces the attempts to deliver a notification: the wait
+// doubles from Backoff after each failure.
+type RetryPolicy struct {
+	Attempts int
+	Backoff  time.Duration
+}
+
+// Config configures a Dispatcher.
+type Config struct {
+	// Rules maps event types to their rule; see DefaultRules.
+	Rules map[string]Rule
+	Retry RetryPolicy
+	// QueueSize bounds the events waiting for Run (default
+	// DefaultQueueSize); events beyond it are dropped and logged.
+	QueueSize int
+}
+
+// Dispatcher delivers notifications for business events.
+type Dispatcher struct {
+	notifier ports.Notifier
+	prefs    ports.NotificationPreferences
+	rules    map[string]*compiledRule
+	retry    RetryPolicy
+	queue    chan domainevent.Event
+	// wait sleeps between attempts; tests replace it.
+	wait func(ctx context.Context, d time.Duration) error
+}
+
+// NewDispatcher creates a dispatcher delivering through notifier. prefs may
+// be nil, in which case every recipient gets each rule's default channel.
+// It fails when a rule's templates do not parse.
+func NewDispatcher(notifier ports.Notifier, prefs ports.NotificationPreferences, config Config) (*Dispatcher, error) {
+	if notifier == nil {
+		return nil, errors.New("dispatch: a notifier is required")
+	}
+	rules := make(map[string]*compiledRule, len(config.Rules))
+	for eventType, rule := range config.Rules {
+		compiled, err := compile(eventType, rule)
+		if err != nil {
+			return nil, err
+		}
+		rules[eventType] = compiled
+	}
+	if config.Retry.Attempts <= 0 {
+		config.Retry.Attempts = DefaultAttempts
+	}
+	if config.Retry.Backoff <= 0 {
+		config.Retry.Backoff = DefaultBackoff
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultQueueSize
+	}
+	return &Dispatcher{
+		notifier: notifier,
+		prefs:    prefs,
+		rules:    rules,
+		retry:    config.Retry,
+		queue:    make(chan domainevent.Event, config.QueueSize),
+		wait:     sleep,
+	}, nil
+}
+
+// ── Worker ───────────────────────────────────────────────────────────────────
+
+// PublishDomainEvent queues events that have a rule for Run; other events
+// are ignored. It never blocks.
+func (d *Dispatcher) PublishDomainEvent(e domainevent.Event) {
+	if _, ok := d.rules[e.Type]; !ok {
+		return
+	}
+	select {
+	case d.queue <- e:
+	default:
+		log.Printf("⚠️  dispatch: queue full, dropped %s event %s", e.Type, e.ID)
+	}
+}
+
+// Run delivers the notifications of queued events until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-d.queue:
+			if err := d.Dispatch(ctx, e); err != nil {
+				log.Printf("⚠️  dispatch: %s event %s: %v", e.Type, e.ID, err)
+			}
+		}
+	}
+}
+
+// ── Dispatch ─────────────────────────────────────────────────────────────────
+
+// Dispatch notifies the recipients of e. Recipients are resolved in the
+// event's workspace. A recipient whose delivery fails after every attempt
+// does not stop the others; the failures are returned together.
+func (d *Dispatcher) Dispatch(ctx context.Context, e domainevent.Event) error {
+	rule, ok := d.rules[e.Type]
+	if !ok {
+		return nil
+	}
+	if _, ok := identity.FromContext(ctx); !ok {
+		ctx = identity.WithRequestIdentity(ctx, &identity.RequestIdentity{WorkspaceID: e.WorkspaceID})
+	}
+	recipients, err := rule.recipients(ctx, e)
+	if err != nil {
+		return fmt.Errorf("resolve recipients: %w", err)
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+	data, err := payload(e)
+	if err != nil {
+		return fmt.Errorf("decode event data: %w", err)
+	}
+
+	var failed []error
+	for _, r := range recipients {
+		n, err := d.notification(ctx, rule, e, data, r)
+		if err != nil {
+			failed = append(failed, err)
+			continue
+		}
+		if n == nil {
+			continue
+		}
+		if err := d.deliver(ctx, n); err != nil {
+			failed = append(failed, fmt.Errorf("notify %s: %w", n.Recipient, err))
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// notification builds r's notification of e, or returns nil when r opted
+// out of the template or has no address on any usable channel.
+func (d *Dispatcher) notification(ctx context.Context, rule *compiledRule, e domainevent.Event, data map[string]any, r Recipient) (*ports.Notification, error) {
+	channel := d.channel(ctx, rule, r)
+	if channel == ports.NotificationChannelNone {
+		return nil, nil
+	}
+	recipient := address(channel, r)
+	if recipient == "" && channel != rule.channel {
+		// The preferred channel has no address, e.g. SMS for a user without
+		// a mobile number: fall back to the rule's channel.
+		channel, recipient = rule.channel, address(rule.channel, r)
+	}
+	if recipient == "" {
+		return nil, nil
+	}
+	userID := r.UserID
+	if userID == "" {
+		// Outside recipients are keyed by address for digests and caps.
+		userID = recipient
+	}
+
+	rendered, err := rule.render(TemplateData{
+		Type:        e.Type,
+		EventID:     e.ID,
+		WorkspaceID: e.WorkspaceID,
+		OccurredAt:  e.OccurredAt,
+		Data:        data,
+		Recipient:   r,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ports.Notification{
+		WorkspaceID: e.WorkspaceID,
+		UserID:      userID,
+		Channel:     channel,
+		Template:    rule.template,
+		Recipient:   recipient,
+		Subject:     rendered.subject,
+		TextBody:    rendered.text,
+		HTMLBody:    rendered.html,
+		Data:        variables(data),
+		OccurredAt:  e.OccurredAt,
+	}, nil
+}
+
+// channel returns r's preferred channel for the rule's template, or the
+// rule's channel when r has no preference or it cannot be read.
+func (d *Dispatcher) channel(ctx context.Context, rule *compiledRule, r Recipient) ports.NotificationChannel {
+	if d.prefs == nil || r.UserID == "" {
+		return rule.channel
+	}
+	channel, err := d.prefs.NotificationChannel(ctx, r.UserID, rule.template)
+	if err != nil {
+		log.Printf("⚠️  dispatch: preferences of %s: %v", r.UserID, err)
+		return rule.channel
+	}
+	if channel == "" {
+		return rule.channel
+	}
+	return channel
+}
+
+// address returns r's address on channel.
+func address(channel ports.NotificationChannel, r Recipient) string {
+	switch channel {
+	case ports.NotificationChannelEmail:
+		return r.Email
+	case ports.NotificationChannelSMS, ports.NotificationChannelWhatsApp:
+		return r.Phone
+	case ports.NotificationChannelPush, ports.NotificationChannelInApp:
+		return r.UserID
+	}
+	return ""
+}
+
+// deliver hands n to the notifier, retrying with backoff.
+func (d *Dispatcher) deliver(ctx context.Context, n *ports.Notification) error {
+	backoff := d.retry.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = d.notifier.Notify(ctx, n); err == nil {
+			return nil
+		}
+		if attempt >= d.retry.Attempts {
+			return fmt.Errorf("after %d attempts: %w", attempt, err)
+		}
+		if waitErr := d.wait(ctx, backoff); waitErr != nil {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
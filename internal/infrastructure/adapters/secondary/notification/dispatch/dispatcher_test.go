@@ -0,0 +1,158 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	revenuepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/revenue/revenue"
+	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+)
+
+// memoryOps keeps rows per table.
+type memoryOps struct {
+	interfaces.DatabaseOperation
+	tables map[string]map[string]map[string]any
+}
+
+func (o *memoryOps) Read(_ context.Context, table, id string) (map[string]any, error) {
+	row, ok := o.tables[table][id]
+	if !ok {
+		return nil, fmt.Errorf("%s %s not found", table, id)
+	}
+	return row, nil
+}
+
+func (o *memoryOps) Create(_ context.Context, table string, data map[string]any) (map[string]any, error) {
+	if o.tables[table] == nil {
+		o.tables[table] = map[string]map[string]any{}
+	}
+	o.tables[table][data["id"].(string)] = data
+	return data, nil
+}
+
+func (o *memoryOps) Update(_ context.Context, table, id string, data map[string]any) (map[string]any, error) {
+	for k, v := range data {
+		o.tables[table][id][k] = v
+	}
+	return o.tables[table][id], nil
+}
+
+// flakyNotifier fails the first failures notifications.
+type flakyNotifier struct {
+	failures int
+	calls    int
+	got      []*ports.Notification
+}
+
+func (n *flakyNotifier) Notify(_ context.Context, notification *ports.Notification) error {
+	n.calls++
+	if n.calls <= n.failures {
+		return errors.New("provider unavailable")
+	}
+	n.got = append(n.got, notification)
+	return nil
+}
+
+func newTestDispatcher(t *testing.T, notifier ports.Notifier, ops *memoryOps, retry RetryPolicy) *Dispatcher {
+	t.Helper()
+	d, err := NewDispatcher(notifier, NewDatabasePreferences(ops, ""), Config{Rules: DefaultRules(ops, Tables{}), Retry: retry})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.wait = func(context.Context, time.Duration) error { return nil }
+	return d
+}
+
+func TestDispatch_Preferences(t *testing.T) {
+	ops := &memoryOps{tables: map[string]map[string]map[string]any{
+		"client": {
+			"cli-1": {"id": "cli-1", "user_id": "usr-1"},
+			"cli-2": {"id": "cli-2", "user_id": "usr-2"},
+			"cli-3": {"id": "cli-3", "name": "Acme", "email": "ap@acme.test"},
+		},
+		"user": {
+			"usr-1": {"id": "usr-1", "first_name": "Ana", "email_address": "ana@example.com", "mobile_number": "+15550101"},
+			"usr-2": {"id": "usr-2", "first_name": "Ben", "email_address": "ben@example.com"},
+		},
+	}}
+	prefs := NewDatabasePreferences(ops, "")
+	ctx := context.Background()
+	_ = prefs.SetNotificationPreference(ctx, &ports.NotificationPreference{UserID: "usr-1", Template: "invoice.overdue", Channel: ports.NotificationChannelSMS})
+	// Ben prefers SMS by default but has no mobile number: email it is.
+	_ = prefs.SetNotificationPreference(ctx, &ports.NotificationPreference{UserID: "usr-2", Channel: ports.NotificationChannelSMS})
+	notifier := &flakyNotifier{}
+	d := newTestDispatcher(t, notifier, ops, RetryPolicy{})
+
+	for _, client := range []string{"cli-1", "cli-2", "cli-3"} {
+		ref := "INV-" + client
+		err := d.Dispatch(ctx, domainevent.Event{Type: domainevent.InvoiceOverdue, WorkspaceID: "ws-1", Data: &revenuepb.Revenue{
+			ClientId: client, ReferenceNumber: &ref, DueDate: ptr("2026-09-30"),
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(notifier.got) != 3 {
+		t.Fatalf("%d notifications, want 3", len(notifier.got))
+	}
+	sms, email, outside := notifier.got[0], notifier.got[1], notifier.got[2]
+	if sms.Channel != ports.NotificationChannelSMS || sms.Recipient != "+15550101" || sms.UserID != "usr-1" ||
+		sms.Subject != "Invoice INV-cli-1 is overdue" || !strings.Contains(sms.TextBody, "due on 2026-09-30") ||
+		sms.Data["due_date"] != "2026-09-30" || sms.Template != "invoice.overdue" || sms.WorkspaceID != "ws-1" {
+		t.Errorf("sms = %+v", sms)
+	}
+	if email.Channel != ports.NotificationChannelEmail || email.Recipient != "ben@example.com" {
+		t.Errorf("fallback = %+v", email)
+	}
+	if outside.Recipient != "ap@acme.test" || outside.UserID != "ap@acme.test" || !strings.HasPrefix(outside.TextBody, "Hi Acme,") {
+		t.Errorf("client without user = %+v", outside)
+	}
+
+	// Opting out of the template silences it.
+	_ = prefs.SetNotificationPreference(ctx, &ports.NotificationPreference{UserID: "usr-1", Template: "invoice.overdue", Channel: ports.NotificationChannelNone})
+	_ = d.Dispatch(ctx, domainevent.Event{Type: domainevent.InvoiceOverdue, Data: &revenuepb.Revenue{ClientId: "cli-1"}})
+	if len(notifier.got) != 3 {
+		t.Errorf("opted-out user notified: %+v", notifier.got[3])
+	}
+}
+
+func TestDispatch_Retry(t *testing.T) {
+	booking := domainevent.Event{Type: domainevent.ScheduleCreated, Data: &schedulerpb.Schedule{
+		Name: "Intro call", StartDate: "2026-10-20", StartTime: "09:00",
+		Invitee: &schedulerpb.InviteeInfo{Name: "Cy", Email: "cy@example.com"},
+	}}
+	ops := &memoryOps{tables: map[string]map[string]map[string]any{}}
+
+	notifier := &flakyNotifier{failures: 2}
+	if err := newTestDispatcher(t, notifier, ops, RetryPolicy{Attempts: 3}).Dispatch(context.Background(), booking); err != nil {
+		t.Fatalf("delivery within the attempts failed: %v", err)
+	}
+	if len(notifier.got) != 1 || notifier.got[0].Subject != "Booking confirmed: Intro call" ||
+		notifier.got[0].TextBody != "Hi Cy, your booking Intro call is confirmed for 2026-10-20 09:00." {
+		t.Errorf("got %+v", notifier.got)
+	}
+
+	notifier = &flakyNotifier{failures: 5}
+	if err := newTestDispatcher(t, notifier, ops, RetryPolicy{Attempts: 3}).Dispatch(context.Background(), booking); err == nil || notifier.calls != 3 {
+		t.Errorf("err = %v after %d calls, want a failure after 3", err, notifier.calls)
+	}
+}
+
+func TestNewDispatcher_RejectsBadTemplate(t *testing.T) {
+	_, err := NewDispatcher(&flakyNotifier{}, nil, Config{Rules: map[string]Rule{
+		domainevent.InvoiceOverdue: {Recipients: ScheduleInvitee, Subject: "{{.Data.name"},
+	}})
+	if err == nil {
+		t.Error("unparsable template accepted")
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
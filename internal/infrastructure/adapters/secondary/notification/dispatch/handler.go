@@ -0,0 +1,87 @@
+package dispatch
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+)
+
+// Preference endpoints for the signed-in user:
+//
+//	GET  PreferencesPath
+//	POST PreferencesPath  {"template": "invoice.overdue", "channel": "sms"}
+//
+// An empty template sets the user's default; channel "none" turns the
+// template's notifications off.
+const PreferencesPath = "/api/notification/preferences"
+
+type preferenceRequest struct {
+	Template string `json:"template"`
+	Channel  string `json:"channel"`
+}
+
+// Handlers serves the preference endpoints.
+type Handlers struct {
+	prefs ports.NotificationPreferences
+}
+
+// NewHandlers creates the endpoint handlers over prefs.
+func NewHandlers(prefs ports.NotificationPreferences) *Handlers {
+	return &Handlers{prefs: prefs}
+}
+
+// List returns the signed-in user's preferences.
+func (h *Handlers) List(w http.ResponseWriter, r *http.Request) {
+	userID := contextutil.ExtractUserIDFromContext(r.Context())
+	if userID == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return
+	}
+	prefs, err := h.prefs.ListNotificationPreferences(r.Context(), userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	data := make([]map[string]string, 0, len(prefs))
+	for _, p := range prefs {
+		data = append(data, map[string]string{"template": p.Template, "channel": string(p.Channel)})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": data})
+}
+
+// Set saves one preference of the signed-in user.
+func (h *Handlers) Set(w http.ResponseWriter, r *http.Request) {
+	userID := contextutil.ExtractUserIDFromContext(r.Context())
+	if userID == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return
+	}
+	var req preferenceRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "invalid JSON body"})
+		return
+	}
+	channel := ports.NotificationChannel(req.Channel)
+	if !validChannel(channel) {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "unknown channel"})
+		return
+	}
+	err := h.prefs.SetNotificationPreference(r.Context(), &ports.NotificationPreference{
+		UserID:   userID,
+		Template: req.Template,
+		Channel:  channel,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
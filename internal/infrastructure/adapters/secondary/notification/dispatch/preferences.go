@@ -0,0 +1,155 @@
+package dispatch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+)
+
+// DefaultPreferenceTable is the table holding channel preferences (see the
+// postgres integration migration 000020_notification_preference).
+const DefaultPreferenceTable = "notification_preference"
+
+// maxPreferencesPerUser bounds one user's preference listing.
+const maxPreferencesPerUser = 200
+
+// PreferenceChannels are the channels a preference may name.
+var PreferenceChannels = []ports.NotificationChannel{
+	ports.NotificationChannelEmail,
+	ports.NotificationChannelSMS,
+	ports.NotificationChannelWhatsApp,
+	ports.NotificationChannelPush,
+	ports.NotificationChannelInApp,
+	ports.NotificationChannelNone,
+}
+
+// DatabasePreferences keeps channel preferences in a table through the
+// generic database operations, so it works on every database provider.
+type DatabasePreferences struct {
+	ops   interfaces.DatabaseOperation
+	table string
+}
+
+var _ ports.NotificationPreferences = (*DatabasePreferences)(nil)
+
+// NewDatabasePreferences creates a store on table (DefaultPreferenceTable
+// when empty).
+func NewDatabasePreferences(ops interfaces.DatabaseOperation, table string) *DatabasePreferences {
+	if table == "" {
+		table = DefaultPreferenceTable
+	}
+	return &DatabasePreferences{ops: ops, table: table}
+}
+
+// preferenceID derives the row id from the user and template, so setting a
+// preference twice updates one row.
+func preferenceID(userID, template string) string {
+	sum := sha256.Sum256([]byte(userID + "\x00" + template))
+	return "ntp_" + hex.EncodeToString(sum[:16])
+}
+
+func validChannel(channel ports.NotificationChannel) bool {
+	for _, c := range PreferenceChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationChannel returns the user's channel for template, else the
+// user's default, else "".
+func (p *DatabasePreferences) NotificationChannel(ctx context.Context, userID, template string) (ports.NotificationChannel, error) {
+	if userID == "" {
+		return "", nil
+	}
+	keys := []string{template, ""}
+	if template == "" {
+		keys = keys[1:]
+	}
+	for _, key := range keys {
+		row, err := p.ops.Read(ctx, p.table, preferenceID(userID, key))
+		if err != nil || row == nil {
+			continue
+		}
+		if channel := ports.NotificationChannel(str(row["channel"])); channel != "" {
+			return channel, nil
+		}
+	}
+	return "", nil
+}
+
+// SetNotificationPreference saves pref, replacing the user's preference for
+// its template.
+func (p *DatabasePreferences) SetNotificationPreference(ctx context.Context, pref *ports.NotificationPreference) error {
+	if pref == nil || pref.UserID == "" {
+		return fmt.Errorf("notification preference requires a user")
+	}
+	if !validChannel(pref.Channel) {
+		return fmt.Errorf("unknown notification channel %q", pref.Channel)
+	}
+	id := preferenceID(pref.UserID, pref.Template)
+	data := map[string]any{
+		"user_id":  pref.UserID,
+		"template": pref.Template,
+		"channel":  string(pref.Channel),
+	}
+	if _, err := p.ops.Read(ctx, p.table, id); err == nil {
+		if _, err := p.ops.Update(ctx, p.table, id, data); err != nil {
+			return fmt.Errorf("update notification preference: %w", err)
+		}
+		return nil
+	}
+	data["id"] = id
+	if _, err := p.ops.Create(ctx, p.table, data); err != nil {
+		return fmt.Errorf("create notification preference: %w", err)
+	}
+	return nil
+}
+
+// ListNotificationPreferences returns the user's preferences.
+func (p *DatabasePreferences) ListNotificationPreferences(ctx context.Context, userID string) ([]*ports.NotificationPreference, error) {
+	result, err := p.ops.List(ctx, p.table, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{{
+			Field: "user_id",
+			FilterType: &commonpb.TypedFilter_StringFilter{
+				StringFilter: &commonpb.StringFilter{
+					Value:         userID,
+					Operator:      commonpb.StringOperator_STRING_EQUALS,
+					CaseSensitive: true,
+				},
+			},
+		}}},
+		Pagination: &commonpb.PaginationRequest{
+			Limit: maxPreferencesPerUser,
+			Method: &commonpb.PaginationRequest_Offset{
+				Offset: &commonpb.OffsetPagination{Page: 1},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list notification preferences: %w", err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+	prefs := make([]*ports.NotificationPreference, 0, len(result.Data))
+	for _, row := range result.Data {
+		// Guard against providers that ignore the filter.
+		if str(row["user_id"]) != userID {
+			continue
+		}
+		prefs = append(prefs, &ports.NotificationPreference{
+			UserID:   userID,
+			Template: str(row["template"]),
+			Channel:  ports.NotificationChannel(str(row["channel"])),
+		})
+	}
+	return prefs, nil
+}
@@ -0,0 +1,140 @@
+package dispatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+)
+
+// TemplateData is what a rule's templates are executed with. Data is the
+// event's entity as JSON with its proto field names, e.g. {{.Data.due_date}}.
+type TemplateData struct {
+	Type        string
+	EventID     string
+	WorkspaceID string
+	OccurredAt  time.Time
+	Data        map[string]any
+	Recipient   Recipient
+}
+
+// compiledRule is a Rule with its defaults applied and templates parsed.
+type compiledRule struct {
+	template   string
+	channel    ports.NotificationChannel
+	recipients Resolver
+	subject    *template.Template
+	text       *template.Template
+	html       *htmltemplate.Template
+}
+
+type rendered struct {
+	subject, text, html string
+}
+
+func compile(eventType string, rule Rule) (*compiledRule, error) {
+	if rule.Recipients == nil {
+		return nil, fmt.Errorf("dispatch: rule for %s has no recipient resolver", eventType)
+	}
+	if rule.Subject == "" && rule.Text == "" {
+		return nil, fmt.Errorf("dispatch: rule for %s has no subject or text", eventType)
+	}
+	c := &compiledRule{template: rule.Template, channel: rule.Channel, recipients: rule.Recipients}
+	if c.template == "" {
+		c.template = eventType
+	}
+	if c.channel == "" {
+		c.channel = ports.NotificationChannelEmail
+	}
+	var err error
+	if c.subject, err = template.New("subject").Parse(rule.Subject); err != nil {
+		return nil, fmt.Errorf("dispatch: %s subject: %w", eventType, err)
+	}
+	if c.text, err = template.New("text").Parse(rule.Text); err != nil {
+		return nil, fmt.Errorf("dispatch: %s text: %w", eventType, err)
+	}
+	if rule.HTML != "" {
+		if c.html, err = htmltemplate.New("html").Parse(rule.HTML); err != nil {
+			return nil, fmt.Errorf("dispatch: %s html: %w", eventType, err)
+		}
+	}
+	return c, nil
+}
+
+func (c *compiledRule) render(data TemplateData) (*rendered, error) {
+	var out rendered
+	var b bytes.Buffer
+	if err := c.subject.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("render %s subject: %w", c.template, err)
+	}
+	out.subject = b.String()
+	b.Reset()
+	if err := c.text.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("render %s text: %w", c.template, err)
+	}
+	out.text = b.String()
+	if c.html != nil {
+		b.Reset()
+		if err := c.html.Execute(&b, data); err != nil {
+			return nil, fmt.Errorf("render %s html: %w", c.template, err)
+		}
+		out.html = b.String()
+	}
+	if out.text == "" {
+		out.text = out.subject
+	}
+	return &out, nil
+}
+
+// payload decodes the event's entity the way webhook receivers get it.
+func payload(e domainevent.Event) (map[string]any, error) {
+	if e.Data == nil {
+		return map[string]any{}, nil
+	}
+	raw, err := domainevent.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, err
+	}
+	data := map[string]any{}
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			// Not an object: expose it as .Data.value.
+			var value any
+			_ = json.Unmarshal(envelope.Data, &value)
+			return map[string]any{"value": value}, nil
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// variables flattens data's top-level scalars into the notification's Data,
+// which SMS and WhatsApp templates take their variables from.
+func variables(data map[string]any) map[string]string {
+	vars := make(map[string]string, len(data))
+	for k, v := range data {
+		switch v := v.(type) {
+		case string:
+			vars[k] = v
+		case float64:
+			vars[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			vars[k] = strconv.FormatBool(v)
+		}
+	}
+	return vars
+}
@@ -0,0 +1,169 @@
+package dispatch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+)
+
+// Tables names the tables recipients are resolved from.
+type Tables struct {
+	Client   string // default client
+	User     string // default user
+	Workflow string // default workflow
+}
+
+func (t Tables) withDefaults() Tables {
+	if t.Client == "" {
+		t.Client = "client"
+	}
+	if t.User == "" {
+		t.User = "user"
+	}
+	if t.Workflow == "" {
+		t.Workflow = "workflow"
+	}
+	return t
+}
+
+// DefaultRules returns the rules for the events the platform notifies about
+// out of the box:
+//
+//	schedule.created  the invitee of the booking (schedule.booked)
+//	invoice.overdue   the user of the invoice's client (invoice.overdue)
+//	stage.completed   the user who created the stage's workflow
+//	                  (workflow.stage_completed)
+func DefaultRules(ops interfaces.DatabaseOperation, tables Tables) map[string]Rule {
+	return map[string]Rule{
+		domainevent.ScheduleCreated: {
+			Template:   "schedule.booked",
+			Recipients: ScheduleInvitee,
+			Subject:    "Booking confirmed: {{.Data.name}}",
+			Text: "Hi {{.Recipient.Name}}, your booking {{.Data.name}} is confirmed for " +
+				"{{.Data.start_date}} {{.Data.start_time}}{{with .Data.timezone}} ({{.}}){{end}}.",
+		},
+		domainevent.InvoiceOverdue: {
+			Template:   "invoice.overdue",
+			Recipients: RevenueClient(ops, tables),
+			Subject:    "Invoice {{or .Data.reference_number .Data.name}} is overdue",
+			Text: "Hi {{.Recipient.Name}}, invoice {{or .Data.reference_number .Data.name}} " +
+				"was due on {{.Data.due_date}} and is still unpaid.",
+		},
+		domainevent.StageCompleted: {
+			Template:   "workflow.stage_completed",
+			Recipients: WorkflowOwner(ops, tables),
+			Subject:    "Stage completed: {{.Data.name}}",
+			Text:       "The stage {{.Data.name}} of your workflow was completed.",
+		},
+	}
+}
+
+// ScheduleInvitee resolves a schedule event to the booking's invitee.
+func ScheduleInvitee(_ context.Context, e domainevent.Event) ([]Recipient, error) {
+	schedule, ok := e.Data.(interface {
+		GetInvitee() *schedulerpb.InviteeInfo
+	})
+	if !ok || schedule.GetInvitee() == nil {
+		return nil, nil
+	}
+	invitee := schedule.GetInvitee()
+	if invitee.GetEmail() == "" && invitee.GetPhone() == "" {
+		return nil, nil
+	}
+	return []Recipient{{Name: invitee.GetName(), Email: invitee.GetEmail(), Phone: invitee.GetPhone()}}, nil
+}
+
+// RevenueClient resolves an invoice event to its client: the client's user
+// when it has one, the client's own email otherwise.
+func RevenueClient(ops interfaces.DatabaseOperation, tables Tables) Resolver {
+	tables = tables.withDefaults()
+	return func(ctx context.Context, e domainevent.Event) ([]Recipient, error) {
+		revenue, ok := e.Data.(interface{ GetClientId() string })
+		if !ok || revenue.GetClientId() == "" {
+			return nil, nil
+		}
+		client, err := ops.Read(ctx, tables.Client, revenue.GetClientId())
+		if err != nil {
+			return nil, fmt.Errorf("read client %s: %w", revenue.GetClientId(), err)
+		}
+		if userID := str(client["user_id"]); userID != "" {
+			r, err := user(ctx, ops, tables, userID)
+			if err != nil {
+				return nil, err
+			}
+			if r.Email == "" {
+				r.Email = str(client["email"])
+			}
+			return []Recipient{r}, nil
+		}
+		r := Recipient{Name: fullName(client), Email: str(client["email"])}
+		if r.Name == "" {
+			r.Name = str(client["name"])
+		}
+		if r.Email == "" {
+			return nil, nil
+		}
+		return []Recipient{r}, nil
+	}
+}
+
+// WorkflowOwner resolves a stage event to the user who created the stage's
+// workflow.
+func WorkflowOwner(ops interfaces.DatabaseOperation, tables Tables) Resolver {
+	tables = tables.withDefaults()
+	return func(ctx context.Context, e domainevent.Event) ([]Recipient, error) {
+		stage, ok := e.Data.(interface{ GetWorkflowId() string })
+		if !ok || stage.GetWorkflowId() == "" {
+			return nil, nil
+		}
+		workflow, err := ops.Read(ctx, tables.Workflow, stage.GetWorkflowId())
+		if err != nil {
+			return nil, fmt.Errorf("read workflow %s: %w", stage.GetWorkflowId(), err)
+		}
+		owner := str(workflow["created_by"])
+		if owner == "" {
+			return nil, nil
+		}
+		r, err := user(ctx, ops, tables, owner)
+		if err != nil {
+			return nil, err
+		}
+		return []Recipient{r}, nil
+	}
+}
+
+// user reads the recipient details of userID.
+func user(ctx context.Context, ops interfaces.DatabaseOperation, tables Tables, userID string) (Recipient, error) {
+	row, err := ops.Read(ctx, tables.User, userID)
+	if err != nil {
+		return Recipient{}, fmt.Errorf("read user %s: %w", userID, err)
+	}
+	return Recipient{
+		UserID: userID,
+		Name:   fullName(row),
+		Email:  str(row["email_address"]),
+		Phone:  str(row["mobile_number"]),
+	}, nil
+}
+
+func fullName(row map[string]any) string {
+	return strings.TrimSpace(str(row["first_name"]) + " " + str(row["last_name"]))
+}
+
+// str reads a text column, which some providers return as a pointer.
+func str(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case *string:
+		if v != nil {
+			return *v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+package inbox
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+)
+
+// Inbox endpoints for the signed-in user:
+//
+//	POST ListPath         {"unread_only": true, "template": "", "workspace_id": "", "page": 1, "limit": 20}
+//	GET  UnreadCountPath
+//	POST MarkReadPath     {"ids": ["..."]}
+//	POST MarkAllReadPath
+//
+// The list body is optional; every response carries the unread count so the
+// bell badge stays current.
+const (
+	ListPath        = "/api/notification/inbox/list"
+	UnreadCountPath = "/api/notification/inbox/unread-count"
+	MarkReadPath    = "/api/notification/inbox/mark-read"
+	MarkAllReadPath = "/api/notification/inbox/mark-all-read"
+)
+
+// maxMarkIDs bounds one mark-read request.
+const maxMarkIDs = 100
+
+type listRequest struct {
+	UnreadOnly  bool   `json:"unread_only"`
+	Template    string `json:"template"`
+	WorkspaceID string `json:"workspace_id"`
+	Page        int    `json:"page"`
+	Limit       int    `json:"limit"`
+}
+
+type markReadRequest struct {
+	IDs []string `json:"ids"`
+}
+
+type notificationJSON struct {
+	ID          string            `json:"id"`
+	WorkspaceID string            `json:"workspace_id,omitempty"`
+	Template    string            `json:"template,omitempty"`
+	Subject     string            `json:"subject"`
+	Body        string            `json:"body"`
+	Data        map[string]string `json:"data,omitempty"`
+	Read        bool              `json:"read"`
+	CreatedAt   int64             `json:"created_at,omitempty"`
+	ReadAt      int64             `json:"read_at,omitempty"`
+}
+
+// Handlers serves the inbox endpoints for the web app.
+type Handlers struct {
+	inbox ports.NotificationInbox
+}
+
+// NewHandlers creates the endpoint handlers over inbox.
+func NewHandlers(inbox ports.NotificationInbox) *Handlers {
+	return &Handlers{inbox: inbox}
+}
+
+// List returns one page of the signed-in user's notifications.
+func (h *Handlers) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	var req listRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "invalid JSON body"})
+		return
+	}
+
+	page, err := h.inbox.ListInboxNotifications(r.Context(), userID, ports.NotificationInboxFilter{
+		UnreadOnly:  req.UnreadOnly,
+		Template:    req.Template,
+		WorkspaceID: req.WorkspaceID,
+		Page:        req.Page,
+		Limit:       req.Limit,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	unread, err := h.inbox.CountUnreadNotifications(r.Context(), userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+
+	data := make([]notificationJSON, 0, len(page.Notifications))
+	for _, n := range page.Notifications {
+		data = append(data, toJSON(n))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"success":      true,
+		"data":         data,
+		"total":        page.Total,
+		"unread_count": unread,
+	})
+}
+
+// UnreadCount returns the signed-in user's unread count for the bell badge.
+func (h *Handlers) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	h.writeUnread(w, r, userID)
+}
+
+// MarkRead marks the given notifications of the signed-in user read.
+func (h *Handlers) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	var req markReadRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "invalid JSON body"})
+		return
+	}
+	if len(req.IDs) == 0 || len(req.IDs) > maxMarkIDs {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "ids must list 1 to 100 notifications"})
+		return
+	}
+	if err := h.inbox.MarkNotificationsRead(r.Context(), userID, req.IDs); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	h.writeUnread(w, r, userID)
+}
+
+// MarkAllRead marks every notification of the signed-in user read.
+func (h *Handlers) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	if err := h.inbox.MarkAllNotificationsRead(r.Context(), userID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	h.writeUnread(w, r, userID)
+}
+
+func (h *Handlers) writeUnread(w http.ResponseWriter, r *http.Request, userID string) {
+	unread, err := h.inbox.CountUnreadNotifications(r.Context(), userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "unread_count": unread})
+}
+
+func requireUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := contextutil.ExtractUserIDFromContext(r.Context())
+	if userID == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return "", false
+	}
+	return userID, true
+}
+
+func toJSON(n *ports.InboxNotification) notificationJSON {
+	out := notificationJSON{
+		ID:          n.ID,
+		WorkspaceID: n.WorkspaceID,
+		Template:    n.Template,
+		Subject:     n.Subject,
+		Body:        n.Body,
+		Data:        n.Data,
+		Read:        n.Read,
+	}
+	if !n.CreatedAt.IsZero() {
+		out.CreatedAt = n.CreatedAt.UnixMilli()
+	}
+	if !n.ReadAt.IsZero() {
+		out.ReadAt = n.ReadAt.UnixMilli()
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,52 @@
+// Package inbox keeps in-app notifications, the list behind the web app's
+// bell icon.
+//
+// Sender is the in-app channel of the notification engine: every message it
+// is given, single or digest, becomes one unread entry in the recipient
+// user's inbox. DatabaseInbox stores the entries and Handlers serves the
+// endpoints the web app lists, counts and marks them read with.
+package inbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// Sender delivers notification messages to users' inboxes.
+type Sender struct {
+	inbox ports.NotificationInbox
+}
+
+var _ ports.NotificationSender = (*Sender)(nil)
+
+// NewSender creates an in-app sender over inbox.
+func NewSender(inbox ports.NotificationInbox) *Sender {
+	return &Sender{inbox: inbox}
+}
+
+// Channel returns the in-app channel.
+func (s *Sender) Channel() ports.NotificationChannel {
+	return ports.NotificationChannelInApp
+}
+
+// Send adds msg to the inbox of the user in msg.UserID, or in msg.Recipient
+// for messages built without it.
+func (s *Sender) Send(ctx context.Context, msg *ports.NotificationMessage) error {
+	userID := msg.UserID
+	if userID == "" {
+		userID = msg.Recipient
+	}
+	if userID == "" {
+		return fmt.Errorf("in-app notification has no recipient user")
+	}
+	return s.inbox.AddInboxNotification(ctx, &ports.InboxNotification{
+		UserID:      userID,
+		WorkspaceID: msg.WorkspaceID,
+		Template:    msg.Template,
+		Subject:     msg.Subject,
+		Body:        msg.TextBody,
+		Data:        msg.Data,
+	})
+}
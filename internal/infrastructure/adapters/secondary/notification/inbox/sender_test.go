@@ -0,0 +1,64 @@
+package inbox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+type memoryInbox struct{ added []*ports.InboxNotification }
+
+func (m *memoryInbox) AddInboxNotification(_ context.Context, n *ports.InboxNotification) error {
+	m.added = append(m.added, n)
+	return nil
+}
+
+func (m *memoryInbox) ListInboxNotifications(context.Context, string, ports.NotificationInboxFilter) (*ports.NotificationInboxPage, error) {
+	return &ports.NotificationInboxPage{}, nil
+}
+
+func (m *memoryInbox) CountUnreadNotifications(context.Context, string) (int, error) { return 0, nil }
+func (m *memoryInbox) MarkNotificationsRead(context.Context, string, []string) error { return nil }
+func (m *memoryInbox) MarkAllNotificationsRead(context.Context, string) error        { return nil }
+
+func TestSenderStoresMessage(t *testing.T) {
+	store := &memoryInbox{}
+	sender := NewSender(store)
+	err := sender.Send(context.Background(), &ports.NotificationMessage{
+		UserID:      "u1",
+		WorkspaceID: "w1",
+		Template:    "workflow.assigned",
+		Recipient:   "u1",
+		Subject:     "New assignment",
+		TextBody:    "Review the invoice",
+		Data:        map[string]string{"link": "/workflow/1"},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(store.added) != 1 {
+		t.Fatalf("added %d notifications, want 1", len(store.added))
+	}
+	n := store.added[0]
+	if n.UserID != "u1" || n.WorkspaceID != "w1" || n.Template != "workflow.assigned" {
+		t.Errorf("notification = %+v", n)
+	}
+	if n.Body != "Review the invoice" || n.Data["link"] != "/workflow/1" || n.Read {
+		t.Errorf("notification = %+v", n)
+	}
+}
+
+func TestSenderFallsBackToRecipient(t *testing.T) {
+	store := &memoryInbox{}
+	sender := NewSender(store)
+	if err := sender.Send(context.Background(), &ports.NotificationMessage{Recipient: "u2", Subject: "Hi"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if store.added[0].UserID != "u2" {
+		t.Errorf("UserID = %q, want u2", store.added[0].UserID)
+	}
+	if err := sender.Send(context.Background(), &ports.NotificationMessage{Subject: "Hi"}); err == nil {
+		t.Error("Send without a user succeeded")
+	}
+}
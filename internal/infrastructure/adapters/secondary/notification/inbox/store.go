@@ -0,0 +1,254 @@
+package inbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// DefaultTable is the table holding inbox notifications (see the postgres
+// integration migration 000007_notification_inbox).
+const DefaultTable = "notification_inbox"
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// DatabaseInbox keeps inbox notifications in a table through the generic
+// database operations, so it works on every database provider.
+type DatabaseInbox struct {
+	ops   interfaces.DatabaseOperation
+	table string
+}
+
+var _ ports.NotificationInbox = (*DatabaseInbox)(nil)
+
+// NewDatabaseInbox creates an inbox on table (DefaultTable when empty).
+func NewDatabaseInbox(ops interfaces.DatabaseOperation, table string) *DatabaseInbox {
+	if table == "" {
+		table = DefaultTable
+	}
+	return &DatabaseInbox{ops: ops, table: table}
+}
+
+// AddInboxNotification stores n unread. The database assigns its ID.
+func (s *DatabaseInbox) AddInboxNotification(ctx context.Context, n *ports.InboxNotification) error {
+	if n == nil || n.UserID == "" {
+		return fmt.Errorf("inbox notification requires a user")
+	}
+	data := "{}"
+	if len(n.Data) > 0 {
+		raw, err := json.Marshal(n.Data)
+		if err != nil {
+			return fmt.Errorf("encode inbox notification data: %w", err)
+		}
+		data = string(raw)
+	}
+	row := map[string]any{
+		"user_id":      n.UserID,
+		"workspace_id": n.WorkspaceID,
+		"template":     n.Template,
+		"subject":      n.Subject,
+		"body":         n.Body,
+		"data":         data,
+		"is_read":      false,
+	}
+	if n.ID != "" {
+		row["id"] = n.ID
+	}
+	created, err := s.ops.Create(ctx, s.table, row)
+	if err != nil {
+		return fmt.Errorf("create inbox notification: %w", err)
+	}
+	if id := str(created["id"]); id != "" {
+		n.ID = id
+	}
+	return nil
+}
+
+// ListInboxNotifications returns one page of the user's notifications,
+// newest first.
+func (s *DatabaseInbox) ListInboxNotifications(ctx context.Context, userID string, filter ports.NotificationInboxFilter) (*ports.NotificationInboxPage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+
+	filters := []*commonpb.TypedFilter{stringEquals("user_id", userID)}
+	if filter.UnreadOnly {
+		filters = append(filters, boolEquals("is_read", false))
+	}
+	if filter.Template != "" {
+		filters = append(filters, stringEquals("template", filter.Template))
+	}
+	if filter.WorkspaceID != "" {
+		filters = append(filters, stringEquals("workspace_id", filter.WorkspaceID))
+	}
+
+	result, err := s.ops.List(ctx, s.table, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: filters},
+		Sort: &commonpb.SortRequest{
+			Fields: []*commonpb.SortField{{
+				Field:     "date_created",
+				Direction: commonpb.SortDirection_DESC,
+			}},
+		},
+		Pagination: &commonpb.PaginationRequest{
+			Limit: int32(limit),
+			Method: &commonpb.PaginationRequest_Offset{
+				Offset: &commonpb.OffsetPagination{Page: int32(page)},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list inbox notifications: %w", err)
+	}
+
+	out := &ports.NotificationInboxPage{Notifications: []*ports.InboxNotification{}}
+	if result == nil {
+		return out, nil
+	}
+	out.Total = int(result.Total)
+	for _, row := range result.Data {
+		// Guard against providers that ignore the filter.
+		if str(row["user_id"]) != userID {
+			continue
+		}
+		out.Notifications = append(out.Notifications, fromRow(row))
+	}
+	return out, nil
+}
+
+// CountUnreadNotifications counts the user's unread notifications.
+func (s *DatabaseInbox) CountUnreadNotifications(ctx context.Context, userID string) (int, error) {
+	page, err := s.ListInboxNotifications(ctx, userID, ports.NotificationInboxFilter{UnreadOnly: true, Limit: 1})
+	if err != nil {
+		return 0, err
+	}
+	return page.Total, nil
+}
+
+// MarkNotificationsRead marks the listed notifications of the user read.
+func (s *DatabaseInbox) MarkNotificationsRead(ctx context.Context, userID string, ids []string) error {
+	now := time.Now().UTC()
+	for _, id := range ids {
+		row, err := s.ops.Read(ctx, s.table, id)
+		if err != nil || str(row["user_id"]) != userID || isRead(row["is_read"]) {
+			continue
+		}
+		if _, err := s.ops.Update(ctx, s.table, id, map[string]any{"is_read": true, "read_at": now}); err != nil {
+			return fmt.Errorf("mark inbox notification %s read: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// MarkAllNotificationsRead marks every unread notification of the user
+// read, a page at a time.
+func (s *DatabaseInbox) MarkAllNotificationsRead(ctx context.Context, userID string) error {
+	for {
+		// Always the first page: marked rows drop out of the unread filter.
+		page, err := s.ListInboxNotifications(ctx, userID, ports.NotificationInboxFilter{UnreadOnly: true, Limit: maxPageSize})
+		if err != nil {
+			return err
+		}
+		if len(page.Notifications) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(page.Notifications))
+		for _, n := range page.Notifications {
+			ids = append(ids, n.ID)
+		}
+		if err := s.MarkNotificationsRead(ctx, userID, ids); err != nil {
+			return err
+		}
+		if len(page.Notifications) < maxPageSize {
+			return nil
+		}
+	}
+}
+
+func fromRow(row map[string]any) *ports.InboxNotification {
+	n := &ports.InboxNotification{
+		ID:          str(row["id"]),
+		UserID:      str(row["user_id"]),
+		WorkspaceID: str(row["workspace_id"]),
+		Template:    str(row["template"]),
+		Subject:     str(row["subject"]),
+		Body:        str(row["body"]),
+		Read:        isRead(row["is_read"]),
+		CreatedAt:   timeValue(row["date_created"]),
+		ReadAt:      timeValue(row["read_at"]),
+	}
+	switch data := row["data"].(type) {
+	case string:
+		_ = json.Unmarshal([]byte(data), &n.Data)
+	case []byte:
+		_ = json.Unmarshal(data, &n.Data)
+	case map[string]any:
+		n.Data = make(map[string]string, len(data))
+		for k, v := range data {
+			n.Data[k] = fmt.Sprint(v)
+		}
+	}
+	return n
+}
+
+func stringEquals(field, value string) *commonpb.TypedFilter {
+	return &commonpb.TypedFilter{
+		Field: field,
+		FilterType: &commonpb.TypedFilter_StringFilter{
+			StringFilter: &commonpb.StringFilter{
+				Value:         value,
+				Operator:      commonpb.StringOperator_STRING_EQUALS,
+				CaseSensitive: true,
+			},
+		},
+	}
+}
+
+func boolEquals(field string, value bool) *commonpb.TypedFilter {
+	return &commonpb.TypedFilter{
+		Field: field,
+		FilterType: &commonpb.TypedFilter_BooleanFilter{
+			BooleanFilter: &commonpb.BooleanFilter{Value: value},
+		},
+	}
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func isRead(v any) bool {
+	b, _ := v.(bool)
+	return b
+}
+
+// timeValue reads a timestamp column, which providers return as a time or
+// as epoch milliseconds.
+func timeValue(v any) time.Time {
+	switch t := v.(type) {
+	case time.Time:
+		return t
+	case int64:
+		return time.UnixMilli(t)
+	case float64:
+		return time.UnixMilli(int64(t))
+	}
+	return time.Time{}
+}
@@ -0,0 +1,72 @@
+package messaging
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// StatusPath returns the path of the provider's delivery status webhook,
+// the path of the status callback URL configured on the provider.
+func StatusPath(provider string) string {
+	return "/api/webhooks/messaging/" + provider + "/status"
+}
+
+// maxCallbackBytes bounds a status callback body; Twilio's are under 1KB.
+const maxCallbackBytes = 64 << 10
+
+// Handlers serves a provider's delivery status webhook.
+type Handlers struct {
+	provider ports.MessagingProvider
+	log      ports.NotificationLog
+}
+
+// NewHandlers creates the webhook handler recording provider's status
+// callbacks in notificationLog.
+func NewHandlers(provider ports.MessagingProvider, notificationLog ports.NotificationLog) *Handlers {
+	return &Handlers{provider: provider, log: notificationLog}
+}
+
+// Status verifies a status callback and records the new status. Callbacks
+// failing verification get 403; the provider retries other failures.
+func (h *Handlers) Status(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCallbackBytes))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "invalid body"})
+		return
+	}
+	headers := make(map[string]string, len(r.Header))
+	for name := range r.Header {
+		headers[name] = r.Header.Get(name)
+	}
+
+	update, err := h.provider.ParseStatusWebhook(r.Context(), &ports.MessageStatusWebhook{Headers: headers, Body: body})
+	if errors.Is(err, ports.ErrMessagingSignature) {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": "invalid signature"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	if err := h.log.UpdateMessageStatus(r.Context(), h.provider.Name(), update); err != nil {
+		log.Printf("⚠️  Warning: failed to record %s status of message %s: %v", h.provider.Name(), update.MessageID, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": "failed to record status"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,113 @@
+package messaging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+)
+
+// DefaultLogTable is the table logging sent messages (see the postgres
+// integration migration 000019_notification_log).
+const DefaultLogTable = "notification_log"
+
+// DatabaseLog keeps the notification log in a table through the generic
+// database operations, so it works on every database provider.
+type DatabaseLog struct {
+	ops   interfaces.DatabaseOperation
+	table string
+}
+
+var _ ports.NotificationLog = (*DatabaseLog)(nil)
+
+// NewDatabaseLog creates a log on table (DefaultLogTable when empty).
+func NewDatabaseLog(ops interfaces.DatabaseOperation, table string) *DatabaseLog {
+	if table == "" {
+		table = DefaultLogTable
+	}
+	return &DatabaseLog{ops: ops, table: table}
+}
+
+// entryID derives the row id from the provider's message id, so a status
+// callback finds the row without a lookup by column.
+func entryID(provider, messageID string) string {
+	sum := sha256.Sum256([]byte(provider + ":" + messageID))
+	return "ntl_" + hex.EncodeToString(sum[:16])
+}
+
+// statusRank orders delivery states; callbacks can arrive out of order and a
+// later state must not be overwritten by an earlier one.
+func statusRank(status ports.MessageStatus) int {
+	switch status {
+	case ports.MessageStatusQueued:
+		return 1
+	case ports.MessageStatusSent:
+		return 2
+	case ports.MessageStatusDelivered, ports.MessageStatusUndelivered, ports.MessageStatusFailed:
+		return 3
+	case ports.MessageStatusRead:
+		return 4
+	}
+	return 0
+}
+
+// RecordMessage logs a message the provider accepted.
+func (l *DatabaseLog) RecordMessage(ctx context.Context, entry *ports.NotificationLogEntry) error {
+	if entry == nil || entry.Provider == "" || entry.ProviderMessageID == "" {
+		return fmt.Errorf("notification log entry requires a provider and a message id")
+	}
+	data := map[string]any{
+		"id":                  entryID(entry.Provider, entry.ProviderMessageID),
+		"provider":            entry.Provider,
+		"provider_message_id": entry.ProviderMessageID,
+		"channel":             string(entry.Channel),
+		"workspace_id":        entry.WorkspaceID,
+		"user_id":             entry.UserID,
+		"template":            entry.Template,
+		"recipient":           entry.Recipient,
+		"status":              string(entry.Status),
+		"error_code":          entry.ErrorCode,
+		"error_message":       entry.ErrorMessage,
+	}
+	if _, err := l.ops.Create(ctx, l.table, data); err != nil {
+		return fmt.Errorf("create notification log entry: %w", err)
+	}
+	return nil
+}
+
+// UpdateMessageStatus moves the logged message to the update's status. An
+// update older than the logged status is ignored; a message missing from
+// the log, e.g. because logging it failed, is logged from the update.
+func (l *DatabaseLog) UpdateMessageStatus(ctx context.Context, provider string, update *ports.MessageStatusUpdate) error {
+	if update == nil || update.MessageID == "" {
+		return fmt.Errorf("status update requires a message id")
+	}
+	id := entryID(provider, update.MessageID)
+	row, err := l.ops.Read(ctx, l.table, id)
+	if err != nil || row == nil {
+		return l.RecordMessage(ctx, &ports.NotificationLogEntry{
+			Provider:          provider,
+			ProviderMessageID: update.MessageID,
+			Status:            update.Status,
+			ErrorCode:         update.ErrorCode,
+			ErrorMessage:      update.ErrorMessage,
+		})
+	}
+
+	current, _ := row["status"].(string)
+	if statusRank(update.Status) < statusRank(ports.MessageStatus(current)) {
+		return nil
+	}
+	data := map[string]any{
+		"status":        string(update.Status),
+		"error_code":    update.ErrorCode,
+		"error_message": update.ErrorMessage,
+	}
+	if _, err := l.ops.Update(ctx, l.table, id, data); err != nil {
+		return fmt.Errorf("update notification log entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Package messaging delivers notifications as SMS and WhatsApp messages.
+//
+// Sender is the SMS or WhatsApp channel of the notification engine: it
+// sends each message through the provider serving the channel and records
+// it in the notification log. Handlers serves the webhook the provider posts
+// delivery status callbacks to, which move the logged message through
+// queued, sent, delivered and read, or to failed.
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// Sender delivers notification messages on one text channel.
+type Sender struct {
+	channel  ports.NotificationChannel
+	provider ports.MessagingProvider
+	log      ports.NotificationLog
+}
+
+var _ ports.NotificationSender = (*Sender)(nil)
+
+// NewSender creates the sender for channel over provider. notificationLog
+// may be nil, in which case messages are not logged.
+func NewSender(channel ports.NotificationChannel, provider ports.MessagingProvider, notificationLog ports.NotificationLog) *Sender {
+	return &Sender{channel: channel, provider: provider, log: notificationLog}
+}
+
+// NewSenders creates a sender for every channel the enabled providers serve,
+// the first provider listed winning a channel several serve.
+func NewSenders(notificationLog ports.NotificationLog, providers ...ports.MessagingProvider) []ports.NotificationSender {
+	var senders []ports.NotificationSender
+	taken := map[ports.NotificationChannel]bool{}
+	for _, p := range providers {
+		if p == nil || !p.IsEnabled() {
+			continue
+		}
+		for _, channel := range p.Channels() {
+			if !taken[channel] {
+				taken[channel] = true
+				senders = append(senders, NewSender(channel, p, notificationLog))
+			}
+		}
+	}
+	return senders
+}
+
+// Channel returns the sender's channel.
+func (s *Sender) Channel() ports.NotificationChannel {
+	return s.channel
+}
+
+// Send sends msg to the phone number in msg.Recipient. The template's
+// variables are the message data. A failure to log the accepted message
+// does not fail the send.
+func (s *Sender) Send(ctx context.Context, msg *ports.NotificationMessage) error {
+	if msg.Recipient == "" {
+		return fmt.Errorf("%s: message has no recipient", s.channel)
+	}
+	body := msg.TextBody
+	if body == "" {
+		body = msg.Subject
+	}
+	sent, err := s.provider.SendMessage(ctx, &ports.TextMessage{
+		Channel:   s.channel,
+		To:        msg.Recipient,
+		Body:      body,
+		Template:  msg.Template,
+		Variables: msg.Data,
+	})
+	if err != nil {
+		return err
+	}
+	if s.log == nil {
+		return nil
+	}
+
+	now := time.Now()
+	entry := &ports.NotificationLogEntry{
+		Provider:          s.provider.Name(),
+		ProviderMessageID: sent.ID,
+		Channel:           s.channel,
+		WorkspaceID:       msg.WorkspaceID,
+		UserID:            msg.UserID,
+		Template:          msg.Template,
+		Recipient:         msg.Recipient,
+		Status:            sent.Status,
+		SentAt:            now,
+		UpdatedAt:         now,
+	}
+	if err := s.log.RecordMessage(ctx, entry); err != nil {
+		log.Printf("⚠️  Warning: failed to log %s message %s: %v", s.channel, sent.ID, err)
+	}
+	return nil
+}
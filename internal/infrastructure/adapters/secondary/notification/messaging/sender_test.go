@@ -0,0 +1,139 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+)
+
+// memoryOps keeps one table's rows by id.
+type memoryOps struct {
+	interfaces.DatabaseOperation
+	rows map[string]map[string]any
+}
+
+func (o *memoryOps) Read(_ context.Context, _, id string) (map[string]any, error) {
+	row, ok := o.rows[id]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", id)
+	}
+	return row, nil
+}
+
+func (o *memoryOps) Create(_ context.Context, _ string, data map[string]any) (map[string]any, error) {
+	o.rows[data["id"].(string)] = data
+	return data, nil
+}
+
+func (o *memoryOps) Update(_ context.Context, _, id string, data map[string]any) (map[string]any, error) {
+	for k, v := range data {
+		o.rows[id][k] = v
+	}
+	return o.rows[id], nil
+}
+
+type fakeProvider struct {
+	channels []ports.NotificationChannel
+	sent     []*ports.TextMessage
+	update   *ports.MessageStatusUpdate
+}
+
+func (p *fakeProvider) Name() string                          { return "fake" }
+func (p *fakeProvider) Channels() []ports.NotificationChannel { return p.channels }
+func (p *fakeProvider) IsEnabled() bool                       { return true }
+func (p *fakeProvider) Close() error                          { return nil }
+
+func (p *fakeProvider) SendMessage(_ context.Context, msg *ports.TextMessage) (*ports.SentMessage, error) {
+	p.sent = append(p.sent, msg)
+	return &ports.SentMessage{ID: fmt.Sprintf("SM%d", len(p.sent)), Status: ports.MessageStatusQueued}, nil
+}
+
+func (p *fakeProvider) ParseStatusWebhook(_ context.Context, req *ports.MessageStatusWebhook) (*ports.MessageStatusUpdate, error) {
+	if req.Headers["X-Signature"] != "ok" {
+		return nil, fmt.Errorf("fake: %w", ports.ErrMessagingSignature)
+	}
+	return p.update, nil
+}
+
+func TestSender_SendsAndLogs(t *testing.T) {
+	ops := &memoryOps{rows: map[string]map[string]any{}}
+	provider := &fakeProvider{channels: []ports.NotificationChannel{ports.NotificationChannelSMS, ports.NotificationChannelWhatsApp}}
+	senders := NewSenders(NewDatabaseLog(ops, ""), provider, &fakeProvider{channels: []ports.NotificationChannel{ports.NotificationChannelSMS}})
+	if len(senders) != 2 {
+		t.Fatalf("%d senders, want one per channel", len(senders))
+	}
+
+	err := senders[1].Send(context.Background(), &ports.NotificationMessage{
+		Recipient: "+15550100", Subject: "Task assigned", Template: "workflow.assigned",
+		Data: map[string]string{"task": "Review"}, UserID: "u1", WorkspaceID: "ws-1",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	sent := provider.sent[0]
+	if sent.Channel != ports.NotificationChannelWhatsApp || sent.Body != "Task assigned" || sent.Variables["task"] != "Review" {
+		t.Errorf("sent %+v", sent)
+	}
+	row := ops.rows[entryID("fake", "SM1")]
+	if row["channel"] != "whatsapp" || row["user_id"] != "u1" || row["status"] != "queued" || row["template"] != "workflow.assigned" {
+		t.Errorf("logged %v", row)
+	}
+
+	if err := senders[0].Send(context.Background(), &ports.NotificationMessage{Subject: "x"}); err == nil {
+		t.Error("message without recipient sent")
+	}
+}
+
+func TestDatabaseLog_StatusOnlyMovesForward(t *testing.T) {
+	ops := &memoryOps{rows: map[string]map[string]any{}}
+	l := NewDatabaseLog(ops, "")
+	ctx := context.Background()
+	_ = l.RecordMessage(ctx, &ports.NotificationLogEntry{Provider: "fake", ProviderMessageID: "SM1", Status: ports.MessageStatusQueued})
+
+	for _, s := range []ports.MessageStatus{ports.MessageStatusDelivered, ports.MessageStatusSent, ports.MessageStatusQueued} {
+		if err := l.UpdateMessageStatus(ctx, "fake", &ports.MessageStatusUpdate{MessageID: "SM1", Status: s}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := ops.rows[entryID("fake", "SM1")]["status"]; got != "delivered" {
+		t.Errorf("status = %v, want delivered", got)
+	}
+
+	// A callback for a message missing from the log records it.
+	_ = l.UpdateMessageStatus(ctx, "fake", &ports.MessageStatusUpdate{MessageID: "SM2", Status: ports.MessageStatusFailed, ErrorCode: "30003"})
+	if row := ops.rows[entryID("fake", "SM2")]; row["status"] != "failed" || row["error_code"] != "30003" {
+		t.Errorf("missing message logged as %v", row)
+	}
+}
+
+func TestHandlers_Status(t *testing.T) {
+	ops := &memoryOps{rows: map[string]map[string]any{}}
+	provider := &fakeProvider{update: &ports.MessageStatusUpdate{MessageID: "SM9", Status: ports.MessageStatusRead}}
+	h := NewHandlers(provider, NewDatabaseLog(ops, ""))
+
+	post := func(signature string) int {
+		req := httptest.NewRequest(http.MethodPost, StatusPath("fake"), strings.NewReader("MessageSid=SM9"))
+		req.Header.Set("X-Signature", signature)
+		rec := httptest.NewRecorder()
+		h.Status(rec, req)
+		return rec.Code
+	}
+	if code := post("forged"); code != http.StatusForbidden {
+		t.Errorf("forged callback: status %d", code)
+	}
+	if len(ops.rows) != 0 {
+		t.Fatal("forged callback recorded")
+	}
+	if code := post("ok"); code != http.StatusOK {
+		t.Errorf("callback: status %d", code)
+	}
+	if ops.rows[entryID("fake", "SM9")]["status"] != "read" {
+		t.Errorf("logged %v", ops.rows)
+	}
+}
@@ -0,0 +1,398 @@
+// Package twilio sends SMS and WhatsApp messages through Twilio
+// Programmable Messaging and verifies the delivery status callbacks Twilio
+// posts back.
+package twilio
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	"github.com/erniealice/espyna-golang/shared/correlation"
+)
+
+// =============================================================================
+// Self-Registration - Adapter registers itself with the factory
+// =============================================================================
+
+func init() {
+	registry.RegisterMessagingProviderFactory("twilio", func() ports.MessagingProvider {
+		return &Provider{}
+	})
+	registry.RegisterMessagingBuildFromEnv("twilio", buildFromEnv)
+}
+
+const (
+	defaultBaseURL = "https://api.twilio.com"
+
+	// whatsAppPrefix marks WhatsApp addresses in Twilio's To and From.
+	whatsAppPrefix = "whatsapp:"
+
+	// SignatureHeader carries the signature of Twilio's webhook requests.
+	SignatureHeader = "X-Twilio-Signature"
+)
+
+// buildFromEnv creates a Twilio provider from environment variables:
+//
+//	TWILIO_ACCOUNT_SID            account SID (AC...)
+//	TWILIO_AUTH_TOKEN             auth token; also verifies status callbacks
+//	TWILIO_API_KEY_SID            optional API key (SK...) to call the API with
+//	TWILIO_API_KEY_SECRET         instead of the auth token
+//	TWILIO_SMS_FROM               sender number for SMS, in E.164 form
+//	TWILIO_MESSAGING_SERVICE_SID  or a messaging service (MG...) choosing the
+//	                              SMS sender
+//	TWILIO_WHATSAPP_FROM          WhatsApp sender number, in E.164 form
+//	TWILIO_STATUS_CALLBACK_URL    public URL of the status webhook (see
+//	                              messaging.StatusPath); no callbacks when empty
+//	TWILIO_CONTENT_TEMPLATES      notification template to Content SID (HX...),
+//	                              e.g. "workflow.assigned=HX12,invoice.due=HX34"
+func buildFromEnv() (ports.MessagingProvider, error) {
+	templates, err := parseTemplates(os.Getenv("TWILIO_CONTENT_TEMPLATES"))
+	if err != nil {
+		return nil, err
+	}
+	return New(Config{
+		AccountSID:          os.Getenv("TWILIO_ACCOUNT_SID"),
+		AuthToken:           os.Getenv("TWILIO_AUTH_TOKEN"),
+		APIKeySID:           os.Getenv("TWILIO_API_KEY_SID"),
+		APIKeySecret:        os.Getenv("TWILIO_API_KEY_SECRET"),
+		SMSFrom:             os.Getenv("TWILIO_SMS_FROM"),
+		MessagingServiceSID: os.Getenv("TWILIO_MESSAGING_SERVICE_SID"),
+		WhatsAppFrom:        os.Getenv("TWILIO_WHATSAPP_FROM"),
+		StatusCallbackURL:   os.Getenv("TWILIO_STATUS_CALLBACK_URL"),
+		Templates:           templates,
+	})
+}
+
+// parseTemplates reads "name=HX...,name=HX..." pairs.
+func parseTemplates(raw string) (map[string]string, error) {
+	templates := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, sid, ok := strings.Cut(pair, "=")
+		name, sid = strings.TrimSpace(name), strings.TrimSpace(sid)
+		if !ok || name == "" || sid == "" {
+			return nil, fmt.Errorf("twilio: TWILIO_CONTENT_TEMPLATES: %q is not template=ContentSid", pair)
+		}
+		templates[name] = sid
+	}
+	return templates, nil
+}
+
+// Config configures a Provider.
+type Config struct {
+	AccountSID   string
+	AuthToken    string
+	APIKeySID    string
+	APIKeySecret string
+
+	SMSFrom             string
+	MessagingServiceSID string
+	WhatsAppFrom        string
+
+	StatusCallbackURL string
+	// Templates maps notification templates to Content API template SIDs.
+	Templates map[string]string
+
+	// BaseURL overrides the API host (tests).
+	BaseURL string
+}
+
+// Provider implements ports.MessagingProvider for Twilio.
+type Provider struct {
+	accountSID   string
+	authToken    string
+	username     string
+	password     string
+	smsFrom      string
+	serviceSID   string
+	whatsAppFrom string
+	callbackURL  string
+	templates    map[string]string
+	baseURL      string
+	client       *http.Client
+	enabled      bool
+}
+
+var _ ports.MessagingProvider = (*Provider)(nil)
+
+// New creates a Twilio provider. It needs the account SID, the auth token
+// and a sender for at least one channel.
+func New(config Config) (*Provider, error) {
+	if config.AccountSID == "" || config.AuthToken == "" {
+		return nil, errors.New("twilio: TWILIO_ACCOUNT_SID and TWILIO_AUTH_TOKEN are required")
+	}
+	if config.SMSFrom == "" && config.MessagingServiceSID == "" && config.WhatsAppFrom == "" {
+		return nil, errors.New("twilio: set TWILIO_SMS_FROM, TWILIO_MESSAGING_SERVICE_SID or TWILIO_WHATSAPP_FROM")
+	}
+	if (config.APIKeySID == "") != (config.APIKeySecret == "") {
+		return nil, errors.New("twilio: TWILIO_API_KEY_SID and TWILIO_API_KEY_SECRET go together")
+	}
+
+	p := &Provider{
+		accountSID:   config.AccountSID,
+		authToken:    config.AuthToken,
+		username:     config.AccountSID,
+		password:     config.AuthToken,
+		smsFrom:      config.SMSFrom,
+		serviceSID:   config.MessagingServiceSID,
+		whatsAppFrom: strings.TrimPrefix(config.WhatsAppFrom, whatsAppPrefix),
+		callbackURL:  config.StatusCallbackURL,
+		templates:    config.Templates,
+		baseURL:      strings.TrimRight(config.BaseURL, "/"),
+		client:       &http.Client{Timeout: 15 * time.Second, Transport: correlation.NewTransport(nil)},
+		enabled:      true,
+	}
+	if config.APIKeySID != "" {
+		p.username, p.password = config.APIKeySID, config.APIKeySecret
+	}
+	if p.baseURL == "" {
+		p.baseURL = defaultBaseURL
+	}
+
+	log.Printf("✅ Twilio messaging provider initialized (channels: %v)", p.Channels())
+	return p, nil
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "twilio"
+}
+
+// Channels returns the channels a sender is configured for.
+func (p *Provider) Channels() []ports.NotificationChannel {
+	var channels []ports.NotificationChannel
+	if p.smsFrom != "" || p.serviceSID != "" {
+		channels = append(channels, ports.NotificationChannelSMS)
+	}
+	if p.whatsAppFrom != "" {
+		channels = append(channels, ports.NotificationChannelWhatsApp)
+	}
+	return channels
+}
+
+// IsEnabled returns whether the provider is configured.
+func (p *Provider) IsEnabled() bool {
+	return p.enabled
+}
+
+// Close releases idle connections.
+func (p *Provider) Close() error {
+	if p.client != nil {
+		p.client.CloseIdleConnections()
+	}
+	return nil
+}
+
+// form builds the Messages API parameters for msg.
+func (p *Provider) form(msg *ports.TextMessage) (url.Values, error) {
+	form := url.Values{}
+	switch msg.Channel {
+	case ports.NotificationChannelSMS:
+		form.Set("To", msg.To)
+		if p.serviceSID != "" {
+			form.Set("MessagingServiceSid", p.serviceSID)
+		} else if p.smsFrom != "" {
+			form.Set("From", p.smsFrom)
+		} else {
+			return nil, errors.New("twilio: no SMS sender configured")
+		}
+	case ports.NotificationChannelWhatsApp:
+		if p.whatsAppFrom == "" {
+			return nil, errors.New("twilio: no WhatsApp sender configured")
+		}
+		form.Set("To", whatsAppPrefix+strings.TrimPrefix(msg.To, whatsAppPrefix))
+		form.Set("From", whatsAppPrefix+p.whatsAppFrom)
+	default:
+		return nil, fmt.Errorf("twilio: unsupported channel %q", msg.Channel)
+	}
+
+	if sid, ok := p.templates[msg.Template]; ok && msg.Template != "" {
+		form.Set("ContentSid", sid)
+		if len(msg.Variables) > 0 {
+			variables, err := json.Marshal(msg.Variables)
+			if err != nil {
+				return nil, fmt.Errorf("twilio: encode template variables: %w", err)
+			}
+			form.Set("ContentVariables", string(variables))
+		}
+	} else if msg.Body != "" {
+		form.Set("Body", msg.Body)
+	} else {
+		return nil, errors.New("twilio: message has no body and no configured template")
+	}
+
+	if p.callbackURL != "" {
+		form.Set("StatusCallback", p.callbackURL)
+	}
+	return form, nil
+}
+
+// SendMessage sends msg through the Messages API.
+func (p *Provider) SendMessage(ctx context.Context, msg *ports.TextMessage) (*ports.SentMessage, error) {
+	if !p.enabled {
+		return nil, errors.New("twilio: provider is not enabled")
+	}
+	if msg == nil || msg.To == "" {
+		return nil, errors.New("twilio: message needs a recipient")
+	}
+	form, err := p.form(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := p.baseURL + "/2010-04-01/Accounts/" + url.PathEscape(p.accountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("twilio: create request: %w", err)
+	}
+	req.SetBasicAuth(p.username, p.password)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("twilio: send: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode/100 != 2 {
+		var failure struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(raw, &failure)
+		return nil, fmt.Errorf("twilio: status %d: error %d: %s", resp.StatusCode, failure.Code, failure.Message)
+	}
+
+	var created struct {
+		SID    string `json:"sid"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(raw, &created); err != nil || created.SID == "" {
+		return nil, fmt.Errorf("twilio: unexpected response: %s", raw)
+	}
+	return &ports.SentMessage{ID: created.SID, Status: mapStatus(created.Status)}, nil
+}
+
+// ParseStatusWebhook verifies the callback's signature against the
+// configured status callback URL and returns its status.
+func (p *Provider) ParseStatusWebhook(ctx context.Context, req *ports.MessageStatusWebhook) (*ports.MessageStatusUpdate, error) {
+	if req == nil {
+		return nil, errors.New("twilio: empty status callback")
+	}
+	params, err := url.ParseQuery(string(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("twilio: parse status callback: %w", err)
+	}
+	if p.callbackURL == "" || !ValidSignature(p.authToken, p.callbackURL, params, header(req.Headers, SignatureHeader)) {
+		return nil, fmt.Errorf("twilio: %w", ports.ErrMessagingSignature)
+	}
+
+	sid := params.Get("MessageSid")
+	if sid == "" {
+		sid = params.Get("SmsSid")
+	}
+	if sid == "" {
+		return nil, errors.New("twilio: status callback without MessageSid")
+	}
+	status := params.Get("MessageStatus")
+	if status == "" {
+		status = params.Get("SmsStatus")
+	}
+	update := &ports.MessageStatusUpdate{
+		MessageID:    sid,
+		Status:       mapStatus(status),
+		ErrorCode:    params.Get("ErrorCode"),
+		ErrorMessage: params.Get("ErrorMessage"),
+		OccurredAt:   time.Now(),
+	}
+	if update.Status == "" {
+		return nil, fmt.Errorf("twilio: unknown message status %q", status)
+	}
+	return update, nil
+}
+
+// header looks up name in headers case-insensitively.
+func header(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// Signature computes Twilio's request signature: the base64 HMAC-SHA1,
+// keyed with the auth token, of the URL followed by every POST parameter's
+// name and value, sorted by name.
+func Signature(authToken, callbackURL string, params url.Values) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(callbackURL)
+	for _, name := range names {
+		values := append([]string(nil), params[name]...)
+		sort.Strings(values)
+		for _, v := range values {
+			b.WriteString(name)
+			b.WriteString(v)
+		}
+	}
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(b.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidSignature reports whether signature is Twilio's signature of the
+// request.
+func ValidSignature(authToken, callbackURL string, params url.Values, signature string) bool {
+	if signature == "" {
+		return false
+	}
+	return hmac.Equal([]byte(Signature(authToken, callbackURL, params)), []byte(signature))
+}
+
+// mapStatus normalizes a Twilio message status; it returns "" for statuses
+// of inbound messages and unknown ones.
+func mapStatus(status string) ports.MessageStatus {
+	switch status {
+	case "accepted", "scheduled", "queued", "sending":
+		return ports.MessageStatusQueued
+	case "sent":
+		return ports.MessageStatusSent
+	case "delivered":
+		return ports.MessageStatusDelivered
+	case "read":
+		return ports.MessageStatusRead
+	case "undelivered":
+		return ports.MessageStatusUndelivered
+	case "failed", "canceled":
+		return ports.MessageStatusFailed
+	}
+	return ""
+}
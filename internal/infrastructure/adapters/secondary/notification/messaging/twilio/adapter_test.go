@@ -0,0 +1,83 @@
+package twilio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+func TestSendMessage_WhatsAppTemplate(t *testing.T) {
+	var form url.Values
+	var path, user string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		form, _ = url.ParseQuery(string(body))
+		path = r.URL.Path
+		user, _, _ = r.BasicAuth()
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"sid":"SM123","status":"accepted"}`))
+	}))
+	defer server.Close()
+
+	p, err := New(Config{
+		AccountSID: "AC1", AuthToken: "secret", SMSFrom: "+15550000", WhatsAppFrom: "whatsapp:+15550001",
+		StatusCallbackURL: "https://app.example.com/cb", Templates: map[string]string{"invoice.due": "HX9"},
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent, err := p.SendMessage(context.Background(), &ports.TextMessage{
+		Channel: ports.NotificationChannelWhatsApp, To: "+15550100",
+		Template: "invoice.due", Variables: map[string]string{"1": "INV-7"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.ID != "SM123" || sent.Status != ports.MessageStatusQueued {
+		t.Errorf("sent = %+v", sent)
+	}
+	if path != "/2010-04-01/Accounts/AC1/Messages.json" || user != "AC1" {
+		t.Errorf("path %s, user %s", path, user)
+	}
+	if form.Get("To") != "whatsapp:+15550100" || form.Get("From") != "whatsapp:+15550001" ||
+		form.Get("ContentSid") != "HX9" || form.Get("ContentVariables") != `{"1":"INV-7"}` ||
+		form.Get("Body") != "" || form.Get("StatusCallback") != "https://app.example.com/cb" {
+		t.Errorf("form = %v", form)
+	}
+}
+
+func TestParseStatusWebhook(t *testing.T) {
+	const callback = "https://app.example.com/api/webhooks/messaging/twilio/status"
+	p, err := New(Config{AccountSID: "AC1", AuthToken: "secret", SMSFrom: "+15550000", StatusCallbackURL: callback})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"undelivered"}, "ErrorCode": {"30003"}}
+	body := []byte(params.Encode())
+
+	update, err := p.ParseStatusWebhook(context.Background(), &ports.MessageStatusWebhook{
+		Headers: map[string]string{"x-twilio-signature": Signature("secret", callback, params)},
+		Body:    body,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.MessageID != "SM123" || update.Status != ports.MessageStatusUndelivered || update.ErrorCode != "30003" {
+		t.Errorf("update = %+v", update)
+	}
+
+	_, err = p.ParseStatusWebhook(context.Background(), &ports.MessageStatusWebhook{
+		Headers: map[string]string{SignatureHeader: Signature("other", callback, params)},
+		Body:    body,
+	})
+	if !errors.Is(err, ports.ErrMessagingSignature) {
+		t.Errorf("forged callback: err = %v", err)
+	}
+}
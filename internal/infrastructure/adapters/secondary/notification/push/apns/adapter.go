@@ -0,0 +1,286 @@
+// Package apns sends push notifications to iOS devices through the Apple
+// Push Notification service, authenticating with a signing key (.p8) rather
+// than a per-app certificate.
+package apns
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	"github.com/erniealice/espyna-golang/shared/correlation"
+)
+
+// =============================================================================
+// Self-Registration - Adapter registers itself with the factory
+// =============================================================================
+
+func init() {
+	registry.RegisterPushProviderFactory("apns", func() ports.PushProvider {
+		return &Provider{}
+	})
+	registry.RegisterPushBuildFromEnv("apns", buildFromEnv)
+}
+
+const (
+	productionHost = "https://api.push.apple.com"
+	sandboxHost    = "https://api.sandbox.push.apple.com"
+
+	// Apple rejects provider tokens older than an hour and throttles
+	// refreshing them more than every 20 minutes.
+	tokenLifetime = 50 * time.Minute
+)
+
+// buildFromEnv creates an APNs provider from environment variables:
+//
+//	APNS_KEY_ID            key ID of the signing key
+//	APNS_TEAM_ID           Apple developer team ID
+//	APNS_BUNDLE_ID         app bundle ID, sent as the topic
+//	APNS_PRIVATE_KEY       PEM contents of the .p8 key (\n escapes allowed)
+//	APNS_PRIVATE_KEY_PATH  or the path to the .p8 file
+//	APNS_PRODUCTION        "true" for the production gateway (default sandbox)
+func buildFromEnv() (ports.PushProvider, error) {
+	keyPEM := strings.ReplaceAll(os.Getenv("APNS_PRIVATE_KEY"), "\\n", "\n")
+	if keyPEM == "" {
+		if path := os.Getenv("APNS_PRIVATE_KEY_PATH"); path != "" {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("apns: read private key: %w", err)
+			}
+			keyPEM = string(raw)
+		}
+	}
+	return New(Config{
+		KeyID:      os.Getenv("APNS_KEY_ID"),
+		TeamID:     os.Getenv("APNS_TEAM_ID"),
+		BundleID:   os.Getenv("APNS_BUNDLE_ID"),
+		PrivateKey: keyPEM,
+		Production: os.Getenv("APNS_PRODUCTION") == "true",
+	})
+}
+
+// Config configures a Provider.
+type Config struct {
+	KeyID      string
+	TeamID     string
+	BundleID   string
+	PrivateKey string // PEM-encoded PKCS#8 P-256 key
+	Production bool
+}
+
+// Provider implements ports.PushProvider for APNs.
+type Provider struct {
+	keyID    string
+	teamID   string
+	bundleID string
+	key      *ecdsa.PrivateKey
+	host     string
+	client   *http.Client
+	enabled  bool
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+var _ ports.PushProvider = (*Provider)(nil)
+
+// New creates an APNs provider.
+func New(config Config) (*Provider, error) {
+	if config.KeyID == "" || config.TeamID == "" || config.BundleID == "" {
+		return nil, errors.New("apns: APNS_KEY_ID, APNS_TEAM_ID and APNS_BUNDLE_ID are required")
+	}
+	key, err := parseKey(config.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	host := sandboxHost
+	if config.Production {
+		host = productionHost
+	}
+
+	log.Printf("✅ APNs push provider initialized (bundle: %s, production: %v)", config.BundleID, config.Production)
+	return &Provider{
+		keyID:    config.KeyID,
+		teamID:   config.TeamID,
+		bundleID: config.BundleID,
+		key:      key,
+		host:     host,
+		client:   &http.Client{Timeout: 15 * time.Second, Transport: correlation.NewTransport(nil)},
+		enabled:  true,
+	}, nil
+}
+
+func parseKey(keyPEM string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, errors.New("apns: private key is missing or not PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("apns: parse private key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("apns: private key is not an ECDSA key")
+	}
+	return key, nil
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "apns"
+}
+
+// Platforms returns iOS.
+func (p *Provider) Platforms() []ports.PushPlatform {
+	return []ports.PushPlatform{ports.PushPlatformIOS}
+}
+
+// IsEnabled returns whether the provider is configured.
+func (p *Provider) IsEnabled() bool {
+	return p.enabled
+}
+
+// Close releases idle connections.
+func (p *Provider) Close() error {
+	if p.client != nil {
+		p.client.CloseIdleConnections()
+	}
+	return nil
+}
+
+type alert struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type aps struct {
+	Alert alert  `json:"alert"`
+	Sound string `json:"sound,omitempty"`
+	Badge *int   `json:"badge,omitempty"`
+}
+
+// payload builds the notification body: the aps dictionary plus the
+// message data as top-level custom keys.
+func payload(msg *ports.PushMessage) ([]byte, error) {
+	body := make(map[string]any, len(msg.Data)+1)
+	for k, v := range msg.Data {
+		body[k] = v
+	}
+	a := aps{Alert: alert{Title: msg.Title, Body: msg.Body}, Sound: "default"}
+	if msg.Badge > 0 {
+		badge := msg.Badge
+		a.Badge = &badge
+	}
+	body["aps"] = a
+	return json.Marshal(body)
+}
+
+// SendPush delivers msg to one device.
+func (p *Provider) SendPush(ctx context.Context, msg *ports.PushMessage) error {
+	if !p.enabled {
+		return errors.New("apns: provider is not enabled")
+	}
+	body, err := payload(msg)
+	if err != nil {
+		return fmt.Errorf("apns: encode payload: %w", err)
+	}
+	token, err := p.providerToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.host+"/3/device/"+msg.Token, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("apns: create request: %w", err)
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apns-topic", p.bundleID)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("apns: send: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var failure struct {
+		Reason string `json:"reason"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	_ = json.Unmarshal(raw, &failure)
+
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		failure.Reason == "BadDeviceToken",
+		failure.Reason == "Unregistered",
+		failure.Reason == "DeviceTokenNotForTopic":
+		return fmt.Errorf("apns: %s: %w", failure.Reason, ports.ErrPushTokenInvalid)
+	case failure.Reason == "ExpiredProviderToken":
+		p.mu.Lock()
+		p.token = ""
+		p.mu.Unlock()
+	}
+	return fmt.Errorf("apns: status %d: %s", resp.StatusCode, failure.Reason)
+}
+
+// providerToken returns the signed JWT authenticating requests, reusing it
+// until it nears expiry.
+func (p *Provider) providerToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.token != "" && now.Sub(p.issuedAt) < tokenLifetime {
+		return p.token, nil
+	}
+	token, err := signToken(p.key, p.keyID, p.teamID, now)
+	if err != nil {
+		return "", err
+	}
+	p.token, p.issuedAt = token, now
+	return token, nil
+}
+
+// signToken builds an ES256 JWT with the key ID header and the team as
+// issuer.
+func signToken(key *ecdsa.PrivateKey, keyID, teamID string, now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "ES256", "kid": keyID})
+	claims, _ := json.Marshal(map[string]any{"iss": teamID, "iat": now.Unix()})
+	enc := base64.RawURLEncoding
+	signing := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signing))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("apns: sign provider token: %w", err)
+	}
+	// JWS encodes the signature as fixed-width r || s.
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return signing + "." + enc.EncodeToString(sig), nil
+}
@@ -0,0 +1,105 @@
+package push
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+)
+
+// Device token endpoints, both POST with a JSON body:
+//
+//	{"token": "<FCM registration token or APNs device token>", "platform": "ios"}
+//
+// The token belongs to the signed-in user; unregister only needs the token.
+const (
+	RegisterPath   = "/api/notification/device-token/register"
+	UnregisterPath = "/api/notification/device-token/unregister"
+)
+
+// maxTokenLength rejects payloads that cannot be device tokens (FCM tokens
+// are about 160 characters, APNs tokens 64).
+const maxTokenLength = 4096
+
+type deviceTokenRequest struct {
+	Token    string `json:"token"`
+	Platform string `json:"platform"`
+}
+
+// Handlers serves the device token endpoints for the mobile app.
+type Handlers struct {
+	store ports.DeviceTokenStore
+}
+
+// NewHandlers creates the endpoint handlers over store.
+func NewHandlers(store ports.DeviceTokenStore) *Handlers {
+	return &Handlers{store: store}
+}
+
+// Register saves the device token of the signed-in user.
+func (h *Handlers) Register(w http.ResponseWriter, r *http.Request) {
+	userID := contextutil.ExtractUserIDFromContext(r.Context())
+	if userID == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return
+	}
+	req, ok := decodeRequest(w, r)
+	if !ok {
+		return
+	}
+	platform := ports.PushPlatform(req.Platform)
+	if platform != ports.PushPlatformAndroid && platform != ports.PushPlatformIOS {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "platform must be android or ios"})
+		return
+	}
+
+	err := h.store.RegisterDeviceToken(r.Context(), &ports.DeviceToken{
+		Token:       req.Token,
+		UserID:      userID,
+		WorkspaceID: contextutil.ExtractWorkspaceIDFromContext(r.Context()),
+		Platform:    platform,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+// Unregister forgets a device token, e.g. when the user signs out on the
+// device.
+func (h *Handlers) Unregister(w http.ResponseWriter, r *http.Request) {
+	if contextutil.ExtractUserIDFromContext(r.Context()) == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return
+	}
+	req, ok := decodeRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := h.store.UnregisterDeviceToken(r.Context(), req.Token); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request) (*deviceTokenRequest, bool) {
+	var req deviceTokenRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "invalid JSON body"})
+		return nil, false
+	}
+	if req.Token == "" || len(req.Token) > maxTokenLength {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "token is required"})
+		return nil, false
+	}
+	return &req, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
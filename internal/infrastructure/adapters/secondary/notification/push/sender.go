@@ -0,0 +1,100 @@
+// Package push delivers notifications to users' mobile devices.
+//
+// Sender is the push channel of the notification engine: it looks up the
+// recipient user's registered device tokens and sends the message to each
+// through the provider serving the device's platform, forgetting tokens the
+// provider reports as invalid. DatabaseTokenStore keeps the tokens and
+// Handlers serves the endpoints the mobile app registers them with.
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// Sender delivers notification messages to a user's devices.
+type Sender struct {
+	store     ports.DeviceTokenStore
+	providers map[ports.PushPlatform]ports.PushProvider
+}
+
+var _ ports.NotificationSender = (*Sender)(nil)
+
+// NewSender creates a push sender over the token store and providers. Each
+// platform is served by the most specific enabled provider declaring it, so
+// APNs takes iOS devices when both it and FCM are configured.
+func NewSender(store ports.DeviceTokenStore, providers ...ports.PushProvider) *Sender {
+	enabled := make([]ports.PushProvider, 0, len(providers))
+	for _, p := range providers {
+		if p != nil && p.IsEnabled() {
+			enabled = append(enabled, p)
+		}
+	}
+	sort.SliceStable(enabled, func(i, j int) bool {
+		ni, nj := len(enabled[i].Platforms()), len(enabled[j].Platforms())
+		if ni != nj {
+			return ni < nj
+		}
+		return enabled[i].Name() < enabled[j].Name()
+	})
+
+	s := &Sender{store: store, providers: make(map[ports.PushPlatform]ports.PushProvider)}
+	for _, p := range enabled {
+		for _, platform := range p.Platforms() {
+			if _, taken := s.providers[platform]; !taken {
+				s.providers[platform] = p
+			}
+		}
+	}
+	return s
+}
+
+// Channel returns the push channel.
+func (s *Sender) Channel() ports.NotificationChannel {
+	return ports.NotificationChannelPush
+}
+
+// Send pushes msg to every device of the user in msg.Recipient. It succeeds
+// when the message reached at least one device, or the user has none.
+func (s *Sender) Send(ctx context.Context, msg *ports.NotificationMessage) error {
+	tokens, err := s.store.ListDeviceTokens(ctx, msg.Recipient)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	delivered := 0
+	for _, t := range tokens {
+		provider, ok := s.providers[t.Platform]
+		if !ok {
+			continue
+		}
+		err := provider.SendPush(ctx, &ports.PushMessage{
+			Token:    t.Token,
+			Platform: t.Platform,
+			Title:    msg.Subject,
+			Body:     msg.TextBody,
+			Data:     msg.Data,
+		})
+		switch {
+		case err == nil:
+			delivered++
+		case errors.Is(err, ports.ErrPushTokenInvalid):
+			if err := s.store.UnregisterDeviceToken(ctx, t.Token); err != nil {
+				log.Printf("⚠️  Warning: failed to forget invalid push token of user %s: %v", t.UserID, err)
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+		}
+	}
+
+	if delivered == 0 && len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
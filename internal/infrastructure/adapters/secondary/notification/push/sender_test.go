@@ -0,0 +1,115 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+type memoryStore struct{ tokens map[string]*ports.DeviceToken }
+
+func (s *memoryStore) RegisterDeviceToken(_ context.Context, t *ports.DeviceToken) error {
+	s.tokens[t.Token] = t
+	return nil
+}
+
+func (s *memoryStore) UnregisterDeviceToken(_ context.Context, token string) error {
+	delete(s.tokens, token)
+	return nil
+}
+
+func (s *memoryStore) ListDeviceTokens(_ context.Context, userID string) ([]*ports.DeviceToken, error) {
+	var out []*ports.DeviceToken
+	for _, t := range s.tokens {
+		if t.UserID == userID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+type fakeProvider struct {
+	name      string
+	platforms []ports.PushPlatform
+	invalid   map[string]bool
+	fail      bool
+	sent      []string
+}
+
+func (p *fakeProvider) Name() string                    { return p.name }
+func (p *fakeProvider) Platforms() []ports.PushPlatform { return p.platforms }
+func (p *fakeProvider) IsEnabled() bool                 { return true }
+func (p *fakeProvider) Close() error                    { return nil }
+
+func (p *fakeProvider) SendPush(_ context.Context, msg *ports.PushMessage) error {
+	if p.invalid[msg.Token] {
+		return fmt.Errorf("gone: %w", ports.ErrPushTokenInvalid)
+	}
+	if p.fail {
+		return errors.New("unavailable")
+	}
+	p.sent = append(p.sent, msg.Token)
+	return nil
+}
+
+func newStore(tokens ...*ports.DeviceToken) *memoryStore {
+	s := &memoryStore{tokens: map[string]*ports.DeviceToken{}}
+	for _, t := range tokens {
+		s.tokens[t.Token] = t
+	}
+	return s
+}
+
+func TestSender_RoutesByPlatform(t *testing.T) {
+	store := newStore(
+		&ports.DeviceToken{Token: "a1", UserID: "u1", Platform: ports.PushPlatformAndroid},
+		&ports.DeviceToken{Token: "i1", UserID: "u1", Platform: ports.PushPlatformIOS},
+		&ports.DeviceToken{Token: "i2", UserID: "u2", Platform: ports.PushPlatformIOS},
+	)
+	fcm := &fakeProvider{name: "google_fcm", platforms: []ports.PushPlatform{ports.PushPlatformAndroid, ports.PushPlatformIOS}}
+	apns := &fakeProvider{name: "apns", platforms: []ports.PushPlatform{ports.PushPlatformIOS}}
+
+	err := NewSender(store, fcm, apns).Send(context.Background(), &ports.NotificationMessage{Recipient: "u1", Subject: "hi"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(fcm.sent) != 1 || fcm.sent[0] != "a1" {
+		t.Errorf("fcm sent %v, want [a1]", fcm.sent)
+	}
+	if len(apns.sent) != 1 || apns.sent[0] != "i1" {
+		t.Errorf("apns sent %v, want [i1]", apns.sent)
+	}
+}
+
+func TestSender_ForgetsInvalidTokens(t *testing.T) {
+	store := newStore(
+		&ports.DeviceToken{Token: "old", UserID: "u1", Platform: ports.PushPlatformAndroid},
+		&ports.DeviceToken{Token: "new", UserID: "u1", Platform: ports.PushPlatformAndroid},
+	)
+	fcm := &fakeProvider{name: "google_fcm", platforms: []ports.PushPlatform{ports.PushPlatformAndroid}, invalid: map[string]bool{"old": true}}
+
+	if err := NewSender(store, fcm).Send(context.Background(), &ports.NotificationMessage{Recipient: "u1"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if _, ok := store.tokens["old"]; ok {
+		t.Error("invalid token was not unregistered")
+	}
+	if _, ok := store.tokens["new"]; !ok {
+		t.Error("valid token was unregistered")
+	}
+}
+
+func TestSender_FailsWhenNothingDelivered(t *testing.T) {
+	store := newStore(&ports.DeviceToken{Token: "a1", UserID: "u1", Platform: ports.PushPlatformAndroid})
+	fcm := &fakeProvider{name: "google_fcm", platforms: []ports.PushPlatform{ports.PushPlatformAndroid}, fail: true}
+
+	if err := NewSender(store, fcm).Send(context.Background(), &ports.NotificationMessage{Recipient: "u1"}); err == nil {
+		t.Fatal("Send succeeded although no device was reached")
+	}
+	if err := NewSender(store).Send(context.Background(), &ports.NotificationMessage{Recipient: "u2"}); err != nil {
+		t.Fatalf("Send to user without devices: %v", err)
+	}
+}
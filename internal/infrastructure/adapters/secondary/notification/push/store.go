@@ -0,0 +1,148 @@
+package push
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// DefaultTokenTable is the table holding device tokens (see the postgres
+// integration migration 000006_device_token).
+const DefaultTokenTable = "device_token"
+
+// maxDevicesPerUser bounds one user's token listing; a user rarely has more
+// than a few devices.
+const maxDevicesPerUser = 100
+
+// DatabaseTokenStore keeps device tokens in a table through the generic
+// database operations, so it works on every database provider.
+type DatabaseTokenStore struct {
+	ops   interfaces.DatabaseOperation
+	table string
+}
+
+var _ ports.DeviceTokenStore = (*DatabaseTokenStore)(nil)
+
+// NewDatabaseTokenStore creates a store on table (DefaultTokenTable when
+// empty).
+func NewDatabaseTokenStore(ops interfaces.DatabaseOperation, table string) *DatabaseTokenStore {
+	if table == "" {
+		table = DefaultTokenTable
+	}
+	return &DatabaseTokenStore{ops: ops, table: table}
+}
+
+// tokenID derives the row id from the token so registering a token twice
+// updates one row.
+func tokenID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "dvt_" + hex.EncodeToString(sum[:16])
+}
+
+// RegisterDeviceToken saves the token for its user, taking it over from any
+// user who registered it before.
+func (s *DatabaseTokenStore) RegisterDeviceToken(ctx context.Context, token *ports.DeviceToken) error {
+	if token == nil || token.Token == "" || token.UserID == "" {
+		return fmt.Errorf("device token requires a token and a user")
+	}
+	id := tokenID(token.Token)
+	data := map[string]any{
+		"user_id":      token.UserID,
+		"workspace_id": token.WorkspaceID,
+		"platform":     string(token.Platform),
+		"token":        token.Token,
+	}
+
+	if _, err := s.ops.Read(ctx, s.table, id); err == nil {
+		if _, err := s.ops.Update(ctx, s.table, id, data); err != nil {
+			return fmt.Errorf("update device token: %w", err)
+		}
+		return nil
+	}
+	data["id"] = id
+	if _, err := s.ops.Create(ctx, s.table, data); err != nil {
+		return fmt.Errorf("create device token: %w", err)
+	}
+	return nil
+}
+
+// UnregisterDeviceToken deletes the token's row. Unknown tokens are ignored.
+func (s *DatabaseTokenStore) UnregisterDeviceToken(ctx context.Context, token string) error {
+	id := tokenID(token)
+	if _, err := s.ops.Read(ctx, s.table, id); err != nil {
+		return nil
+	}
+	if err := s.ops.HardDelete(ctx, s.table, id); err != nil {
+		return fmt.Errorf("delete device token: %w", err)
+	}
+	return nil
+}
+
+// ListDeviceTokens returns the user's tokens.
+func (s *DatabaseTokenStore) ListDeviceTokens(ctx context.Context, userID string) ([]*ports.DeviceToken, error) {
+	result, err := s.ops.List(ctx, s.table, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{{
+			Field: "user_id",
+			FilterType: &commonpb.TypedFilter_StringFilter{
+				StringFilter: &commonpb.StringFilter{
+					Value:         userID,
+					Operator:      commonpb.StringOperator_STRING_EQUALS,
+					CaseSensitive: true,
+				},
+			},
+		}}},
+		Pagination: &commonpb.PaginationRequest{
+			Limit: maxDevicesPerUser,
+			Method: &commonpb.PaginationRequest_Offset{
+				Offset: &commonpb.OffsetPagination{Page: 1},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list device tokens: %w", err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+
+	tokens := make([]*ports.DeviceToken, 0, len(result.Data))
+	for _, row := range result.Data {
+		// Guard against providers that ignore the filter.
+		if str(row["user_id"]) != userID || str(row["token"]) == "" {
+			continue
+		}
+		tokens = append(tokens, &ports.DeviceToken{
+			Token:        str(row["token"]),
+			UserID:       userID,
+			WorkspaceID:  str(row["workspace_id"]),
+			Platform:     ports.PushPlatform(str(row["platform"])),
+			RegisteredAt: timeValue(row["date_created"]),
+		})
+	}
+	return tokens, nil
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// timeValue reads a timestamp column, which providers return as a time or
+// as epoch milliseconds.
+func timeValue(v any) time.Time {
+	switch t := v.(type) {
+	case time.Time:
+		return t
+	case int64:
+		return time.UnixMilli(t)
+	case float64:
+		return time.UnixMilli(int64(t))
+	}
+	return time.Time{}
+}
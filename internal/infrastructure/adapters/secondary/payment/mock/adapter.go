@@ -4,6 +4,7 @@ package mock
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"time"
@@ -161,27 +162,63 @@ func (p *MockPaymentProvider) ProcessWebhook(ctx context.Context, req *paymentpb
 		}, nil
 	}
 
+	// The payload is optional; tests send one to simulate a provider callback
+	// for a specific payment.
+	var payload mockWebhookPayload
+	if len(data.Payload) > 0 {
+		if err := json.Unmarshal(data.Payload, &payload); err != nil {
+			return &paymentpb.ProcessWebhookResponse{
+				Success: false,
+				Error: &commonpb.Error{
+					Code:        "WEBHOOK_PARSE_ERROR",
+					Description: fmt.Sprintf("Failed to parse webhook payload: %v", err),
+					Category:    commonpb.ErrorCategory_ERROR_CATEGORY_VALIDATION,
+				},
+			}, nil
+		}
+	}
+
+	status := paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS
+	action := "success"
+	if payload.Status == "failed" {
+		status = paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED
+		action = "failure"
+	}
+
 	transaction := &paymentpb.PaymentTransaction{
 		Id:          fmt.Sprintf("txn_%d", time.Now().UnixNano()),
 		ProviderId:  "mock",
-		Status:      paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS,
+		Status:      status,
+		Amount:      int64(payload.Amount * 100), // Convert to cents
+		Currency:    payload.Currency,
+		PaymentId:   payload.PaymentID,
 		ProcessedAt: timestamppb.Now(),
 		RawData:     map[string]string{"mock": "true"},
 	}
 
 	p.transactions[transaction.Id] = transaction
-	log.Printf("🔔 Mock webhook processed: %s", transaction.Id)
+	log.Printf("🔔 Mock webhook processed: %s -> %s", transaction.Id, action)
 
 	return &paymentpb.ProcessWebhookResponse{
 		Success: true,
-		Data: &paymentpb.WebhookResult{
+		Data: []*paymentpb.WebhookResult{{
 			Transaction: transaction,
-			Status:      paymentpb.PaymentStatus_PAYMENT_STATUS_SUCCESS,
-			Action:      "success",
-		},
+			Status:      status,
+			Action:      action,
+			PaymentId:   payload.PaymentID,
+		}},
 	}, nil
 }
 
+// mockWebhookPayload is the callback body the mock provider understands.
+// Status is "success" (default) or "failed"; Amount is in major units.
+type mockWebhookPayload struct {
+	PaymentID string  `json:"payment_id"`
+	Status    string  `json:"status"`
+	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency"`
+}
+
 func (p *MockPaymentProvider) GetPaymentStatus(ctx context.Context, req *paymentpb.GetPaymentStatusRequest) (*paymentpb.GetPaymentStatusResponse, error) {
 	if !p.enabled {
 		return nil, fmt.Errorf("Mock payment provider is not initialized")
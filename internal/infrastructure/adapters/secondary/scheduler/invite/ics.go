@@ -0,0 +1,138 @@
+package invite
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Method is the iTIP method of a calendar object (RFC 5546).
+type Method string
+
+// Supported iTIP methods.
+const (
+	MethodRequest Method = "REQUEST"
+	MethodCancel  Method = "CANCEL"
+)
+
+// prodID identifies the producer of the calendar objects.
+const prodID = "-//Espyna//Schedule Invites//EN"
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding.
+const maxLineOctets = 75
+
+// Person is an organizer or attendee.
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Event is one meeting. UID stays the same across updates of the meeting,
+// and Sequence grows with each update so calendars apply the latest.
+type Event struct {
+	UID         string
+	Sequence    int
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	Organizer   Person
+	Attendees   []Person
+	// Stamp is when the calendar object was created (DTSTAMP).
+	Stamp time.Time
+}
+
+// Calendar renders the event as an RFC 5545 calendar object carrying the
+// given iTIP method, with CRLF line endings and long lines folded.
+func Calendar(method Method, e *Event) []byte {
+	var b strings.Builder
+	line := func(s string) { writeFolded(&b, s) }
+
+	line("BEGIN:VCALENDAR")
+	line("PRODID:" + prodID)
+	line("VERSION:2.0")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:" + string(method))
+	line("BEGIN:VEVENT")
+	line("UID:" + e.UID)
+	line("SEQUENCE:" + strconv.Itoa(e.Sequence))
+	line("DTSTAMP:" + formatTime(e.Stamp))
+	line("DTSTART:" + formatTime(e.Start))
+	line("DTEND:" + formatTime(e.End))
+	line("SUMMARY:" + escapeText(e.Summary))
+	if e.Description != "" {
+		line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	if e.Location != "" {
+		line("LOCATION:" + escapeText(e.Location))
+	}
+	if e.URL != "" {
+		line("URL:" + e.URL)
+	}
+	if e.Organizer.Email != "" {
+		line("ORGANIZER" + commonName(e.Organizer.Name) + ":mailto:" + e.Organizer.Email)
+	}
+	for _, a := range e.Attendees {
+		line("ATTENDEE" + commonName(a.Name) + ";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:" + a.Email)
+	}
+	if method == MethodCancel {
+		line("STATUS:CANCELLED")
+	} else {
+		line("STATUS:CONFIRMED")
+	}
+	line("TRANSP:OPAQUE")
+	line("END:VEVENT")
+	line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// ContentType is the MIME type of a calendar object with the given method.
+func ContentType(method Method) string {
+	return "text/calendar; charset=UTF-8; method=" + string(method)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11).
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	return textEscaper.Replace(s)
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// commonName renders the CN parameter, quoted because names often contain
+// commas. Quoted parameter values cannot contain double quotes.
+func commonName(name string) string {
+	name = strings.NewReplacer(`"`, "", "\r", "", "\n", " ").Replace(name)
+	if name == "" {
+		return ""
+	}
+	return `;CN="` + name + `"`
+}
+
+// writeFolded writes a content line, folding it into continuation lines
+// (starting with a space) of at most 75 octets without splitting a UTF-8
+// character.
+func writeFolded(b *strings.Builder, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// The leading space counts toward the continuation line's length.
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
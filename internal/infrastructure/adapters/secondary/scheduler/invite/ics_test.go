@@ -0,0 +1,70 @@
+package invite
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEvent() *Event {
+	start := time.Date(2026, 3, 9, 14, 30, 0, 0, time.FixedZone("PHT", 8*3600))
+	return &Event{
+		UID:       "evt-1@espyna",
+		Sequence:  2,
+		Summary:   "Consult; follow-up, part 1",
+		Location:  "Room 4",
+		Start:     start,
+		End:       start.Add(30 * time.Minute),
+		Organizer: Person{Name: "Clinic, Front Desk", Email: "desk@example.com"},
+		Attendees: []Person{{Name: "Ana", Email: "ana@example.com"}},
+		Stamp:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestCalendarRequest(t *testing.T) {
+	out := string(Calendar(MethodRequest, testEvent()))
+
+	for _, want := range []string{
+		"METHOD:REQUEST\r\n",
+		"UID:evt-1@espyna\r\n",
+		"SEQUENCE:2\r\n",
+		"DTSTART:20260309T063000Z\r\n",
+		"DTEND:20260309T070000Z\r\n",
+		"SUMMARY:Consult\\; follow-up\\, part 1\r\n",
+		"ORGANIZER;CN=\"Clinic, Front Desk\":mailto:desk@example.com\r\n",
+		"STATUS:CONFIRMED\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Errorf("calendar does not end with END:VCALENDAR")
+	}
+}
+
+func TestCalendarCancel(t *testing.T) {
+	out := string(Calendar(MethodCancel, testEvent()))
+	if !strings.Contains(out, "METHOD:CANCEL\r\n") || !strings.Contains(out, "STATUS:CANCELLED\r\n") {
+		t.Errorf("cancel calendar lacks method or status:\n%s", out)
+	}
+	if got := ContentType(MethodCancel); got != "text/calendar; charset=UTF-8; method=CANCEL" {
+		t.Errorf("ContentType = %q", got)
+	}
+}
+
+func TestCalendarFoldsLongLines(t *testing.T) {
+	e := testEvent()
+	e.Description = strings.Repeat("ñ", 100)
+	out := string(Calendar(MethodRequest, e))
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, "DESCRIPTION:"+e.Description+"\r\n") {
+		t.Errorf("description does not unfold to the original")
+	}
+}
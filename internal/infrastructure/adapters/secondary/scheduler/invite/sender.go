@@ -0,0 +1,260 @@
+// Package invite emails calendar invites for schedules, so invitees'
+// calendars follow bookings, reschedules and cancellations without relying
+// on the scheduler provider's own emails.
+//
+// Sender renders each change as an RFC 5545 calendar object, METHOD:REQUEST
+// for booked and moved schedules and METHOD:CANCEL for cancelled ones, and
+// sends it as an .ics attachment through the email provider. The last
+// invite of every schedule is recorded (its UID, SEQUENCE and meeting
+// details), so each update carries a higher sequence and supersedes the
+// previous one in the invitee's calendar.
+package invite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	schedulerpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/scheduler"
+)
+
+// Config identifies the organizer the invites come from.
+type Config struct {
+	// OrganizerEmail is the ORGANIZER of the events and the From address
+	// (empty sends from the email provider's default).
+	OrganizerEmail string
+	OrganizerName  string
+}
+
+// Sender emails calendar invites through an email provider.
+type Sender struct {
+	email  ports.EmailProvider
+	store  store
+	config Config
+	now    func() time.Time
+}
+
+var _ ports.ScheduleInviteSender = (*Sender)(nil)
+
+// NewSender creates an invite sender. Invites are recorded in
+// DefaultTable through ops, or in memory when ops is nil, in which case
+// updates after a restart start a new sequence.
+func NewSender(email ports.EmailProvider, ops interfaces.DatabaseOperation, config Config) *Sender {
+	s := &Sender{email: email, config: config, now: time.Now}
+	if ops != nil {
+		s.store = &databaseStore{ops: ops, table: DefaultTable}
+	} else {
+		s.store = newMemoryStore()
+	}
+	return s
+}
+
+// SendScheduleInvite sends METHOD:REQUEST for the schedule. Each call for
+// the same schedule, or for one replacing previousScheduleID, reuses the
+// event's UID with the next sequence number.
+func (s *Sender) SendScheduleInvite(ctx context.Context, schedule *schedulerpb.Schedule, previousScheduleID string) error {
+	invitee := schedule.GetInvitee()
+	if invitee.GetEmail() == "" {
+		return nil
+	}
+	key := scheduleKey(schedule)
+	if key == "" {
+		return errors.New("schedule has no ID")
+	}
+	start, end, err := scheduleTimes(schedule)
+	if err != nil {
+		return err
+	}
+
+	rec, err := s.store.get(ctx, key)
+	if err != nil {
+		return err
+	}
+	switch {
+	case rec != nil:
+		rec.Sequence++
+	case previousScheduleID != "":
+		previous, err := s.store.get(ctx, previousScheduleID)
+		if err != nil {
+			return err
+		}
+		if previous != nil {
+			rec = &record{UID: previous.UID, Sequence: previous.Sequence + 1}
+		}
+	}
+	if rec == nil {
+		rec = &record{UID: uidFor(key)}
+	}
+	rec.Summary = schedule.GetName()
+	rec.Location = scheduleLocation(schedule)
+	rec.Start, rec.End = start, end
+	rec.AttendeeName, rec.AttendeeEmail = invitee.GetName(), invitee.GetEmail()
+	rec.Cancelled = false
+
+	event := s.event(rec)
+	event.Description = scheduleDescription(schedule)
+	event.URL = schedule.GetLocation().GetJoinUrl()
+	subject := fmt.Sprintf("Invitation: %s @ %s", rec.Summary, formatWhen(start))
+	body := fmt.Sprintf("You are invited to %s.\n\nWhen: %s\n", rec.Summary, formatWhen(start))
+	if rec.Location != "" {
+		body += fmt.Sprintf("Where: %s\n", rec.Location)
+	}
+	if event.Description != "" {
+		body += "\n" + event.Description + "\n"
+	}
+	if err := s.send(ctx, MethodRequest, event, subject, body); err != nil {
+		return err
+	}
+	return s.store.put(ctx, key, rec)
+}
+
+// SendScheduleCancellation sends METHOD:CANCEL for a schedule an invite
+// was sent for.
+func (s *Sender) SendScheduleCancellation(ctx context.Context, scheduleID string, reason string) error {
+	rec, err := s.store.get(ctx, scheduleID)
+	if err != nil || rec == nil || rec.Cancelled {
+		return err
+	}
+	rec.Sequence++
+	rec.Cancelled = true
+
+	event := s.event(rec)
+	event.Description = reason
+	when := formatWhen(rec.Start)
+	subject := fmt.Sprintf("Cancelled: %s @ %s", rec.Summary, when)
+	body := fmt.Sprintf("%s on %s has been cancelled.\n", rec.Summary, when)
+	if reason != "" {
+		body += fmt.Sprintf("\nReason: %s\n", reason)
+	}
+	if err := s.send(ctx, MethodCancel, event, subject, body); err != nil {
+		return err
+	}
+	return s.store.put(ctx, scheduleID, rec)
+}
+
+func (s *Sender) event(rec *record) *Event {
+	return &Event{
+		UID:       rec.UID,
+		Sequence:  rec.Sequence,
+		Summary:   rec.Summary,
+		Location:  rec.Location,
+		Start:     rec.Start,
+		End:       rec.End,
+		Organizer: Person{Name: s.config.OrganizerName, Email: s.config.OrganizerEmail},
+		Attendees: []Person{{Name: rec.AttendeeName, Email: rec.AttendeeEmail}},
+		Stamp:     s.now(),
+	}
+}
+
+func (s *Sender) send(ctx context.Context, method Method, event *Event, subject, body string) error {
+	if s.email == nil || !s.email.IsEnabled() {
+		return errors.New("email provider is not available")
+	}
+	data := Calendar(method, event)
+	name := "invite.ics"
+	if method == MethodCancel {
+		name = "cancel.ics"
+	}
+	msg := ports.EmailMessage{
+		From:     s.config.OrganizerEmail,
+		To:       []string{event.Attendees[0].Email},
+		Subject:  subject,
+		TextBody: body,
+		Attachments: []ports.EmailAttachment{{
+			Name:        name,
+			ContentType: ContentType(method),
+			Size:        int64(len(data)),
+			Data:        data,
+		}},
+	}
+	resp, err := s.email.SendEmail(ctx, msg.ToProtoRequest())
+	if err != nil {
+		return fmt.Errorf("send %s invite: %w", strings.ToLower(string(method)), err)
+	}
+	if resp != nil && !resp.Success {
+		return fmt.Errorf("send %s invite: %s", strings.ToLower(string(method)), resp.GetError().GetMessage())
+	}
+	return nil
+}
+
+// scheduleKey is the ID the provider knows the schedule by, which its
+// webhooks and cancellations carry too.
+func scheduleKey(schedule *schedulerpb.Schedule) string {
+	if id := schedule.GetProviderScheduleId(); id != "" {
+		return id
+	}
+	return schedule.GetId()
+}
+
+// uidFor derives the event UID from the schedule ID, so it is stable even
+// when the invite record is lost.
+func uidFor(scheduleID string) string {
+	return scheduleID + "@espyna"
+}
+
+// scheduleTimes reads the start and end in the schedule's time zone, or UTC
+// when it has none (as in provider webhooks). The invitee's time zone is
+// not used: it is where they are, not what the times are written in. A
+// missing end falls back to the duration.
+func scheduleTimes(schedule *schedulerpb.Schedule) (start, end time.Time, err error) {
+	loc := time.UTC
+	if tz := schedule.GetTimezone(); tz != "" {
+		if l, lerr := time.LoadLocation(tz); lerr == nil {
+			loc = l
+		}
+	}
+	start, err = time.ParseInLocation("2006-01-02 15:04", schedule.GetStartDate()+" "+schedule.GetStartTime(), loc)
+	if err != nil {
+		return start, end, fmt.Errorf("schedule start: %w", err)
+	}
+	if schedule.GetEndTime() != "" {
+		endDate := schedule.GetEndDate()
+		if endDate == "" {
+			endDate = schedule.GetStartDate()
+		}
+		end, err = time.ParseInLocation("2006-01-02 15:04", endDate+" "+schedule.GetEndTime(), loc)
+		if err != nil {
+			return start, end, fmt.Errorf("schedule end: %w", err)
+		}
+	} else if d := schedule.GetDurationMinutes(); d > 0 {
+		end = start.Add(time.Duration(d) * time.Minute)
+	} else {
+		return start, end, errors.New("schedule has no end time or duration")
+	}
+	if !end.After(start) {
+		return start, end, errors.New("schedule ends before it starts")
+	}
+	return start, end, nil
+}
+
+func scheduleLocation(schedule *schedulerpb.Schedule) string {
+	loc := schedule.GetLocation()
+	if loc.GetLocation() != "" {
+		return loc.GetLocation()
+	}
+	return loc.GetJoinUrl()
+}
+
+func scheduleDescription(schedule *schedulerpb.Schedule) string {
+	var lines []string
+	if url := schedule.GetLocation().GetJoinUrl(); url != "" {
+		lines = append(lines, "Join: "+url)
+	}
+	if url := schedule.GetRescheduleUrl(); url != "" {
+		lines = append(lines, "Reschedule: "+url)
+	}
+	if url := schedule.GetCancelUrl(); url != "" {
+		lines = append(lines, "Cancel: "+url)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatWhen renders a start time in its own time zone for subjects and
+// bodies.
+func formatWhen(start time.Time) string {
+	return start.Format("Mon Jan 2, 2006 15:04 MST")
+}
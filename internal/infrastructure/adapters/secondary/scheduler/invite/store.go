@@ -0,0 +1,151 @@
+package invite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+)
+
+// DefaultTable is the table recording sent invites (see the postgres
+// integration migration 000008_schedule_invite).
+const DefaultTable = "schedule_invite"
+
+// record is the last invite sent for a schedule: what a cancellation or
+// the next update must refer to.
+type record struct {
+	UID           string
+	Sequence      int
+	Summary       string
+	Location      string
+	Start         time.Time
+	End           time.Time
+	AttendeeName  string
+	AttendeeEmail string
+	Cancelled     bool
+}
+
+// store keeps invite records by schedule ID.
+type store interface {
+	// get returns nil when no invite was sent for the schedule.
+	get(ctx context.Context, scheduleID string) (*record, error)
+	put(ctx context.Context, scheduleID string, r *record) error
+}
+
+// databaseStore keeps records in a table through the generic database
+// operations, so it works on every database provider.
+type databaseStore struct {
+	ops   interfaces.DatabaseOperation
+	table string
+}
+
+func (s *databaseStore) get(ctx context.Context, scheduleID string) (*record, error) {
+	row, err := s.ops.Read(ctx, s.table, scheduleID)
+	if err != nil || row == nil {
+		// Providers report a missing row as an error.
+		return nil, nil
+	}
+	r := &record{
+		UID:           str(row["uid"]),
+		Sequence:      integer(row["sequence"]),
+		Summary:       str(row["summary"]),
+		Location:      str(row["location"]),
+		AttendeeName:  str(row["attendee_name"]),
+		AttendeeEmail: str(row["attendee_email"]),
+		Cancelled:     str(row["status"]) == statusCancelled,
+	}
+	r.Start, _ = time.Parse(time.RFC3339, str(row["start_at"]))
+	r.End, _ = time.Parse(time.RFC3339, str(row["end_at"]))
+	if r.UID == "" {
+		return nil, nil
+	}
+	return r, nil
+}
+
+func (s *databaseStore) put(ctx context.Context, scheduleID string, r *record) error {
+	status := statusConfirmed
+	if r.Cancelled {
+		status = statusCancelled
+	}
+	data := map[string]any{
+		"uid":            r.UID,
+		"sequence":       r.Sequence,
+		"summary":        r.Summary,
+		"location":       r.Location,
+		"start_at":       r.Start.UTC().Format(time.RFC3339),
+		"end_at":         r.End.UTC().Format(time.RFC3339),
+		"attendee_name":  r.AttendeeName,
+		"attendee_email": r.AttendeeEmail,
+		"status":         status,
+	}
+	if _, err := s.ops.Read(ctx, s.table, scheduleID); err == nil {
+		if _, err := s.ops.Update(ctx, s.table, scheduleID, data); err != nil {
+			return fmt.Errorf("update invite of schedule %s: %w", scheduleID, err)
+		}
+		return nil
+	}
+	data["id"] = scheduleID
+	if _, err := s.ops.Create(ctx, s.table, data); err != nil {
+		return fmt.Errorf("record invite of schedule %s: %w", scheduleID, err)
+	}
+	return nil
+}
+
+const (
+	statusConfirmed = "confirmed"
+	statusCancelled = "cancelled"
+)
+
+// memoryStore keeps records for the life of the process, for setups
+// without a database.
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]record
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: map[string]record{}}
+}
+
+func (s *memoryStore) get(_ context.Context, scheduleID string) (*record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[scheduleID]
+	if !ok {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+func (s *memoryStore) put(_ context.Context, scheduleID string, r *record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[scheduleID] = *r
+	return nil
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// integer reads a numeric column, which providers return as various types.
+func integer(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
@@ -4,7 +4,10 @@ package mock
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
@@ -23,6 +26,10 @@ func init() {
 // MockSchedulerAdapter provides a mock implementation of SchedulerProvider
 type MockSchedulerAdapter struct {
 	enabled bool
+
+	mu           sync.Mutex
+	webhookURL   string
+	subscription *ports.SchedulerWebhookSubscription
 }
 
 // NewMockSchedulerAdapter creates a new mock scheduler adapter
@@ -39,6 +46,11 @@ func (a *MockSchedulerAdapter) Name() string {
 // Initialize sets up the mock adapter
 func (a *MockSchedulerAdapter) Initialize(config *schedulerpb.SchedulerProviderConfig) error {
 	a.enabled = true
+	if config != nil {
+		a.mu.Lock()
+		a.webhookURL = config.WebhookUrl
+		a.mu.Unlock()
+	}
 	log.Printf("[MockSchedulerAdapter] Initialized")
 	return nil
 }
@@ -161,6 +173,12 @@ func (a *MockSchedulerAdapter) ListSchedules(ctx context.Context, req *scheduler
 	}, nil
 }
 
+// ListScheduleInvitees lists mock invitees (none)
+func (a *MockSchedulerAdapter) ListScheduleInvitees(ctx context.Context, providerScheduleID string) ([]*schedulerpb.InviteeInfo, error) {
+	log.Printf("[MockSchedulerAdapter] ListScheduleInvitees called for %s", providerScheduleID)
+	return []*schedulerpb.InviteeInfo{}, nil
+}
+
 // CheckAvailability checks mock available time slots
 func (a *MockSchedulerAdapter) CheckAvailability(ctx context.Context, req *schedulerpb.CheckAvailabilityRequest) (*schedulerpb.CheckAvailabilityResponse, error) {
 	if req.Data == nil {
@@ -278,3 +296,62 @@ func (a *MockSchedulerAdapter) GetEventType(ctx context.Context, req *schedulerp
 		},
 	}, nil
 }
+
+// EnsureWebhookSubscription registers an in-memory subscription for callbackURL
+// (the configured URL when empty)
+func (a *MockSchedulerAdapter) EnsureWebhookSubscription(ctx context.Context, callbackURL string) (*ports.SchedulerWebhookSubscription, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if callbackURL != "" {
+		a.webhookURL = callbackURL
+	}
+	if a.webhookURL == "" {
+		return nil, fmt.Errorf("no webhook callback URL configured")
+	}
+	if a.subscription == nil || a.subscription.CallbackURL != a.webhookURL {
+		a.subscription = &ports.SchedulerWebhookSubscription{
+			ID:          fmt.Sprintf("mock-webhook-%d", time.Now().UnixNano()),
+			CallbackURL: a.webhookURL,
+			Events:      []string{"invitee.created", "invitee.canceled"},
+			State:       ports.SchedulerWebhookActive,
+			CreatedAt:   time.Now(),
+		}
+		log.Printf("[MockSchedulerAdapter] Registered webhook subscription for %s", a.webhookURL)
+	}
+	current := *a.subscription
+	return &current, nil
+}
+
+// RotateWebhookSecret returns the subscription with a new mock signing key
+func (a *MockSchedulerAdapter) RotateWebhookSecret(ctx context.Context) (*ports.SchedulerWebhookSubscription, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.subscription == nil {
+		return nil, fmt.Errorf("no webhook subscription registered")
+	}
+	rotated := *a.subscription
+	rotated.SigningKey = fmt.Sprintf("mock-signing-key-%d", time.Now().UnixNano())
+	return &rotated, nil
+}
+
+// WebhookSubscriptionStatus returns the in-memory subscription for the
+// configured callback URL, in state missing until one is registered, and nil
+// when no callback URL is configured
+func (a *MockSchedulerAdapter) WebhookSubscriptionStatus(ctx context.Context) (*ports.SchedulerWebhookSubscription, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.webhookURL == "" {
+		return nil, nil
+	}
+	if a.subscription == nil || a.subscription.CallbackURL != a.webhookURL {
+		return &ports.SchedulerWebhookSubscription{
+			CallbackURL: a.webhookURL,
+			State:       ports.SchedulerWebhookMissing,
+		}, nil
+	}
+	current := *a.subscription
+	return &current, nil
+}
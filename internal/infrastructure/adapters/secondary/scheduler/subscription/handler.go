@@ -0,0 +1,145 @@
+// Package subscription serves the admin endpoints managing the scheduler
+// provider's webhook subscription.
+package subscription
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// The subscription endpoints:
+//
+//	GET  StatusPath   the subscription for the configured callback URL
+//	POST EnsurePath   register or repair it; body {"callback_url": ...}
+//	                  optional, the configured URL when omitted
+//	POST RotatePath   recreate it with a new signing key
+//
+// Reading the status needs scheduler_webhook:list, changing it
+// scheduler_webhook:manage. A signing key is returned only when ensuring or
+// rotating generated one, the one time it can be read.
+const (
+	StatusPath = "/api/integration/scheduler/webhook-subscription"
+	EnsurePath = StatusPath + "/ensure"
+	RotatePath = StatusPath + "/rotate-secret"
+)
+
+// entitySchedulerWebhook is the permission entity of the subscription.
+const entitySchedulerWebhook = "scheduler_webhook"
+
+type subscriptionJSON struct {
+	ID          string                      `json:"id,omitempty"`
+	CallbackURL string                      `json:"callback_url"`
+	Events      []string                    `json:"events,omitempty"`
+	State       ports.SchedulerWebhookState `json:"state"`
+	CreatedAt   int64                       `json:"created_at,omitempty"`
+	SigningKey  string                      `json:"signing_key,omitempty"`
+}
+
+// Handlers serves the subscription endpoints.
+type Handlers struct {
+	manager ports.SchedulerWebhookManager
+	gate    *actiongate.ActionGatekeeper
+}
+
+// NewHandlers creates the endpoint handlers over manager. gate decides who
+// may read and change the subscription.
+func NewHandlers(manager ports.SchedulerWebhookManager, gate *actiongate.ActionGatekeeper) *Handlers {
+	return &Handlers{manager: manager, gate: gate}
+}
+
+// Status returns the current subscription.
+func (h *Handlers) Status(w http.ResponseWriter, r *http.Request) {
+	if !h.allowed(w, r, http.MethodGet, entityid.ActionList) {
+		return
+	}
+	subscription, err := h.manager.WebhookSubscriptionStatus(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	if subscription == nil {
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "configured": false})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "configured": true, "data": toJSON(subscription)})
+}
+
+// Ensure registers the subscription or repairs it.
+func (h *Handlers) Ensure(w http.ResponseWriter, r *http.Request) {
+	if !h.allowed(w, r, http.MethodPost, entityid.ActionManage) {
+		return
+	}
+	var body struct {
+		CallbackURL string `json:"callback_url"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "invalid request body"})
+			return
+		}
+	}
+	subscription, err := h.manager.EnsureWebhookSubscription(r.Context(), body.CallbackURL)
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": toJSON(subscription)})
+}
+
+// Rotate replaces the subscription's signing key.
+func (h *Handlers) Rotate(w http.ResponseWriter, r *http.Request) {
+	if !h.allowed(w, r, http.MethodPost, entityid.ActionManage) {
+		return
+	}
+	subscription, err := h.manager.RotateWebhookSecret(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": toJSON(subscription)})
+}
+
+// allowed checks the request uses method and the caller may take action on
+// the subscription, writing the refusal when not.
+func (h *Handlers) allowed(w http.ResponseWriter, r *http.Request, method, action string) bool {
+	ctx := r.Context()
+	if r.Method != method {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return false
+	}
+	if contextutil.ExtractUserIDFromContext(ctx) == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return false
+	}
+	if err := h.gate.Check(ctx, &actiongate.CheckActionRequest{Entity: entitySchedulerWebhook, Action: action}); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func toJSON(s *ports.SchedulerWebhookSubscription) subscriptionJSON {
+	out := subscriptionJSON{
+		ID:          s.ID,
+		CallbackURL: s.CallbackURL,
+		Events:      s.Events,
+		State:       s.State,
+		SigningKey:  s.SigningKey,
+	}
+	if !s.CreatedAt.IsZero() {
+		out.CreatedAt = s.CreatedAt.UnixMilli()
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
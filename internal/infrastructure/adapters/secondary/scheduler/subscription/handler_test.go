@@ -0,0 +1,86 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+)
+
+// fakeManager keeps one subscription and counts key rotations.
+type fakeManager struct {
+	subscription *ports.SchedulerWebhookSubscription
+	rotations    int
+}
+
+func (m *fakeManager) EnsureWebhookSubscription(_ context.Context, callbackURL string) (*ports.SchedulerWebhookSubscription, error) {
+	m.subscription = &ports.SchedulerWebhookSubscription{ID: "sub-1", CallbackURL: callbackURL, State: ports.SchedulerWebhookActive, SigningKey: "generated"}
+	return m.subscription, nil
+}
+
+func (m *fakeManager) RotateWebhookSecret(context.Context) (*ports.SchedulerWebhookSubscription, error) {
+	m.rotations++
+	rotated := *m.subscription
+	rotated.SigningKey = "rotated"
+	return &rotated, nil
+}
+
+func (m *fakeManager) WebhookSubscriptionStatus(context.Context) (*ports.SchedulerWebhookSubscription, error) {
+	if m.subscription == nil {
+		return nil, nil
+	}
+	current := *m.subscription
+	current.SigningKey = ""
+	return &current, nil
+}
+
+func TestHandlers(t *testing.T) {
+	manager := &fakeManager{}
+	h := NewHandlers(manager, testutil.OpenActionGate())
+	ctx := contextutil.WithUserID(context.Background(), "user-1")
+
+	serve := func(ctx context.Context, handler http.HandlerFunc, method, target, body string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(method, target, strings.NewReader(body)).WithContext(ctx))
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+
+	if code, _ := serve(context.Background(), h.Status, http.MethodGet, StatusPath, ""); code != http.StatusUnauthorized {
+		t.Errorf("anonymous status = %d", code)
+	}
+	if code, _ := serve(ctx, h.Ensure, http.MethodGet, EnsurePath, ""); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET ensure = %d", code)
+	}
+	if code, out := serve(ctx, h.Status, http.MethodGet, StatusPath, ""); code != http.StatusOK || out["configured"] != false {
+		t.Errorf("unconfigured status = %d %v", code, out)
+	}
+
+	code, out := serve(ctx, h.Ensure, http.MethodPost, EnsurePath, `{"callback_url":"https://app.example.com/hook"}`)
+	data, _ := out["data"].(map[string]any)
+	if code != http.StatusOK || data["callback_url"] != "https://app.example.com/hook" || data["signing_key"] != "generated" {
+		t.Errorf("ensure = %d %v", code, out)
+	}
+
+	code, out = serve(ctx, h.Status, http.MethodGet, StatusPath, "")
+	data, _ = out["data"].(map[string]any)
+	if code != http.StatusOK || data["state"] != "active" {
+		t.Errorf("status = %d %v", code, out)
+	}
+	if _, ok := data["signing_key"]; ok {
+		t.Error("status returned the signing key")
+	}
+
+	code, out = serve(ctx, h.Rotate, http.MethodPost, RotatePath, "")
+	data, _ = out["data"].(map[string]any)
+	if code != http.StatusOK || data["signing_key"] != "rotated" || manager.rotations != 1 {
+		t.Errorf("rotate = %d %v", code, out)
+	}
+}
@@ -0,0 +1,147 @@
+package softdelete
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// ObjectDeleter deletes stored objects; storage providers implementing
+// ports.ObjectDeletingStorageProvider satisfy it.
+type ObjectDeleter interface {
+	DeleteObject(ctx context.Context, containerName, objectKey string) error
+}
+
+// Attachments removes the attachments of purged records. An attachment row
+// points at its parent by module_key (the parent's entity) and foreign_key
+// rather than a foreign key, so nothing else would delete it, and its stored
+// object would outlive both.
+type Attachments struct {
+	ops     interfaces.DatabaseOperation
+	objects ObjectDeleter
+	table   string
+	// entities maps a table to its entity, the attachments' module_key.
+	entities map[string]string
+}
+
+// NewAttachments creates the cascade over the attachment table. tables maps
+// the entities whose records have attachments to their tables. objects may be
+// nil when the storage provider cannot delete objects; the rows are removed
+// all the same.
+func NewAttachments(ops interfaces.DatabaseOperation, objects ObjectDeleter, table string, tables map[string]string) *Attachments {
+	entities := make(map[string]string, len(tables))
+	for entity, t := range tables {
+		entities[t] = entity
+	}
+	return &Attachments{ops: ops, objects: objects, table: table, entities: entities}
+}
+
+// OnPurge is a Config.OnPurge hook removing the attachments of the record
+// purged from table. Purging attachment rows themselves cascades nothing.
+func (a *Attachments) OnPurge(ctx context.Context, table, id string) {
+	entity, ok := a.entities[table]
+	if !ok || table == a.table {
+		return
+	}
+	removed, err := a.Remove(ctx, entity, id)
+	if err != nil {
+		log.Printf("softdelete: attachments of %s %s: %v", entity, id, err)
+	}
+	if removed > 0 {
+		log.Printf("softdelete: %s %s: %d attachments removed", entity, id, removed)
+	}
+}
+
+// Remove deletes the stored objects and rows of the attachments of the
+// entity's record id, active or not, and returns how many it removed. A row
+// whose object could not be deleted is kept, so a later purge retries it.
+func (a *Attachments) Remove(ctx context.Context, entity, id string) (int, error) {
+	rows, err := a.attachments(ctx, entity, id)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	var failed error
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+		attachmentID, _ := row["id"].(string)
+		if attachmentID == "" {
+			continue
+		}
+		if err := a.deleteObject(ctx, row); err != nil {
+			failed = fmt.Errorf("attachment %s: %w", attachmentID, err)
+			continue
+		}
+		if err := a.ops.HardDelete(ctx, a.table, attachmentID); err != nil {
+			failed = fmt.Errorf("attachment %s: %w", attachmentID, err)
+			continue
+		}
+		removed++
+	}
+	return removed, failed
+}
+
+// deleteObject deletes a row's stored object. The object is resolved in the
+// row's workspace when ctx carries none, as on a scheduled purge.
+func (a *Attachments) deleteObject(ctx context.Context, row map[string]any) error {
+	key, _ := deref(row["storage_key"]).(string)
+	if a.objects == nil || key == "" {
+		return nil
+	}
+	container, _ := deref(row["storage_container"]).(string)
+	if _, ok := identity.FromContext(ctx); !ok {
+		if workspaceID, _ := deref(row["workspace_id"]).(string); workspaceID != "" {
+			ctx = identity.WithRequestIdentity(ctx, &identity.RequestIdentity{WorkspaceID: workspaceID})
+		}
+	}
+	return a.objects.DeleteObject(ctx, container, key)
+}
+
+// attachments lists the attachment rows of the entity's record id, active
+// and soft-deleted.
+func (a *Attachments) attachments(ctx context.Context, entity, id string) ([]map[string]any, error) {
+	params := &interfaces.ListParams{Filters: &commonpb.FilterRequest{
+		Logic: commonpb.FilterLogic_AND,
+		Filters: []*commonpb.TypedFilter{
+			stringEquals("module_key", entity),
+			stringEquals("foreign_key", id),
+		},
+	}}
+	var rows []map[string]any
+	for _, list := range []func(context.Context, string, *interfaces.ListParams) (*interfaces.ListResult, error){a.ops.List, a.ops.ListDeleted} {
+		for page := int32(1); ; page++ {
+			params.Pagination = &commonpb.PaginationRequest{
+				Limit:  pageSize,
+				Method: &commonpb.PaginationRequest_Offset{Offset: &commonpb.OffsetPagination{Page: page}},
+			}
+			result, err := list(ctx, a.table, params)
+			if err != nil {
+				return nil, fmt.Errorf("list attachments: %w", err)
+			}
+			rows = append(rows, result.Data...)
+			if len(result.Data) < pageSize || len(rows) >= maxPurge {
+				break
+			}
+		}
+	}
+	return rows, nil
+}
+
+func stringEquals(field, value string) *commonpb.TypedFilter {
+	return &commonpb.TypedFilter{
+		Field: field,
+		FilterType: &commonpb.TypedFilter_StringFilter{
+			StringFilter: &commonpb.StringFilter{
+				Operator: commonpb.StringOperator_STRING_EQUALS,
+				Value:    value,
+			},
+		},
+	}
+}
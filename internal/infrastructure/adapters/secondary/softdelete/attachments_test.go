@@ -0,0 +1,120 @@
+package softdelete
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// tablesOps keeps rows per table and applies string-equality filters.
+type tablesOps struct {
+	interfaces.DatabaseOperation
+	tables map[string]map[string]map[string]any
+}
+
+func (o *tablesOps) Read(_ context.Context, table, id string) (map[string]any, error) {
+	return o.tables[table][id], nil
+}
+
+func (o *tablesOps) HardDelete(_ context.Context, table, id string) error {
+	delete(o.tables[table], id)
+	return nil
+}
+
+func (o *tablesOps) List(_ context.Context, table string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return o.list(table, params, true), nil
+}
+
+func (o *tablesOps) ListDeleted(_ context.Context, table string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	return o.list(table, params, false), nil
+}
+
+func (o *tablesOps) list(table string, params *interfaces.ListParams, active bool) *interfaces.ListResult {
+	result := &interfaces.ListResult{}
+	if params.Pagination.GetOffset().Page > 1 {
+		return result
+	}
+rows:
+	for _, row := range o.tables[table] {
+		if Active(row) != active {
+			continue
+		}
+		for _, f := range params.Filters.GetFilters() {
+			if row[f.Field] != f.GetStringFilter().Value {
+				continue rows
+			}
+		}
+		result.Data = append(result.Data, row)
+	}
+	return result
+}
+
+// objectStore records deleted objects with the workspace they resolved in;
+// deleting a key in fail fails.
+type objectStore struct {
+	deleted map[string]string
+	fail    map[string]bool
+}
+
+func (s *objectStore) DeleteObject(ctx context.Context, container, key string) error {
+	if s.fail[key] {
+		return errors.New("backend unavailable")
+	}
+	id, _ := identity.FromContext(ctx)
+	s.deleted[container+"/"+key] = id.WorkspaceID
+	return nil
+}
+
+func attachmentRow(id, moduleKey, foreignKey, key string, active bool) map[string]any {
+	return map[string]any{
+		"id": id, "module_key": moduleKey, "foreign_key": foreignKey, "workspace_id": "ws-1",
+		"storage_container": "files", "storage_key": key, "active": active,
+	}
+}
+
+func TestAttachments_OnPurge(t *testing.T) {
+	ops := &tablesOps{tables: map[string]map[string]map[string]any{
+		"client": {"cli-1": row("cli-1", "ws-1", false, 40)},
+		"attachment": {
+			"att-1": attachmentRow("att-1", "client", "cli-1", "a/1.pdf", true),
+			"att-2": attachmentRow("att-2", "client", "cli-1", "a/2.pdf", false),
+			"att-3": attachmentRow("att-3", "client", "cli-1", "a/3.pdf", true),
+			"att-4": attachmentRow("att-4", "client", "cli-2", "a/4.pdf", true),
+			"att-5": attachmentRow("att-5", "invoice", "cli-1", "a/5.pdf", true),
+		},
+	}}
+	objects := &objectStore{deleted: map[string]string{}, fail: map[string]bool{"a/3.pdf": true}}
+	cascade := NewAttachments(ops, objects, "attachment", map[string]string{"client": "client", "attachment": "attachment"})
+	p := NewPurger(ops, Config{Now: func() time.Time { return now }, OnPurge: cascade.OnPurge})
+
+	if err := p.Purge(context.Background(), "client", "cli-1", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	attachments := ops.tables["attachment"]
+	for _, id := range []string{"att-1", "att-2"} {
+		if _, ok := attachments[id]; ok {
+			t.Errorf("%s was not removed", id)
+		}
+	}
+	// att-3's object could not be deleted, so its row stays for a retry; the
+	// other client's and the other entity's attachments are untouched.
+	for _, id := range []string{"att-3", "att-4", "att-5"} {
+		if _, ok := attachments[id]; !ok {
+			t.Errorf("%s was removed", id)
+		}
+	}
+	if len(objects.deleted) != 2 || objects.deleted["files/a/1.pdf"] != "ws-1" || objects.deleted["files/a/2.pdf"] != "ws-1" {
+		t.Errorf("deleted objects = %v", objects.deleted)
+	}
+
+	// Purging an attachment row cascades nothing.
+	cascade.OnPurge(context.Background(), "attachment", "att-3")
+	if _, ok := attachments["att-3"]; !ok {
+		t.Error("attachment purge cascaded")
+	}
+}
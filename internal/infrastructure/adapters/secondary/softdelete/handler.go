@@ -0,0 +1,172 @@
+package softdelete
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// RestorePath is an entity's restore route, serving POST with {"id": ...}:
+// the soft-deleted record of the caller's workspace is made active again
+// and returned. It needs <entity>:update.
+func RestorePath(entity string) string { return "/api/" + entity + "/restore" }
+
+// PurgePath is an entity's purge route, serving POST. With {"id": ...} the
+// soft-deleted record is hard-deleted whatever its age; without, every
+// record of the caller's workspace deleted longer than the retention
+// period is. It needs <entity>:manage.
+func PurgePath(entity string) string { return "/api/" + entity + "/purge" }
+
+type requestJSON struct {
+	ID string `json:"id"`
+}
+
+type purgeJSON struct {
+	Before string   `json:"before,omitempty"`
+	Purged []string `json:"purged"`
+	Failed int      `json:"failed"`
+}
+
+// Handlers serve RestorePath and PurgePath.
+type Handlers struct {
+	ops    interfaces.DatabaseOperation
+	purger *Purger
+	gate   *actiongate.ActionGatekeeper
+}
+
+// NewHandlers creates the handlers over ops, purging with purger.
+func NewHandlers(ops interfaces.DatabaseOperation, purger *Purger, gate *actiongate.ActionGatekeeper) *Handlers {
+	return &Handlers{ops: ops, purger: purger, gate: gate}
+}
+
+// Restore returns the handler restoring the entity's records in table.
+func (h *Handlers) Restore(entity, table string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workspaceID, ok := h.authorize(w, r, entity, entityid.ActionUpdate)
+		if !ok {
+			return
+		}
+		req, ok := readRequest(w, r)
+		if !ok {
+			return
+		}
+		if req.ID == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "id is required"})
+			return
+		}
+
+		ctx := r.Context()
+		if _, err := Find(ctx, h.ops, table, req.ID, workspaceID); err != nil {
+			writeError(w, err)
+			return
+		}
+		if err := h.ops.Restore(ctx, table, req.ID); err != nil {
+			writeError(w, err)
+			return
+		}
+		row, err := h.ops.Read(ctx, table, req.ID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": row})
+	}
+}
+
+// Purge returns the handler purging the entity's records in table.
+func (h *Handlers) Purge(entity, table string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workspaceID, ok := h.authorize(w, r, entity, entityid.ActionManage)
+		if !ok {
+			return
+		}
+		req, ok := readRequest(w, r)
+		if !ok {
+			return
+		}
+
+		ctx := r.Context()
+		if req.ID != "" {
+			if err := h.purger.Purge(ctx, table, req.ID, workspaceID); err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": purgeJSON{Purged: []string{req.ID}}})
+			return
+		}
+		before := h.purger.Cutoff()
+		result, err := h.purger.PurgeTable(ctx, table, before, workspaceID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": purgeJSON{
+			Before: before.UTC().Format(time.RFC3339),
+			Purged: result.Purged,
+			Failed: result.Failed,
+		}})
+	}
+}
+
+// authorize checks the method, the caller and <entity>:<action> in the
+// caller's workspace, writing the error response when one fails.
+func (h *Handlers) authorize(w http.ResponseWriter, r *http.Request, entity, action string) (string, bool) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return "", false
+	}
+	ctx := r.Context()
+	if contextutil.ExtractUserIDFromContext(ctx) == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return "", false
+	}
+	workspaceID := contextutil.ExtractWorkspaceIDFromContext(ctx)
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "workspace required"})
+		return "", false
+	}
+	if err := h.gate.Check(ctx, &actiongate.CheckActionRequest{Entity: entity, Action: action}); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": err.Error()})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+// readRequest decodes the optional JSON body.
+func readRequest(w http.ResponseWriter, r *http.Request) (requestJSON, bool) {
+	var req requestJSON
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "invalid JSON body"})
+		return req, false
+	}
+	return req, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var dbErr *model.DatabaseError
+	switch {
+	case errors.Is(err, ErrBusy):
+		writeJSON(w, http.StatusConflict, map[string]any{"success": false, "error": err.Error()})
+	case errors.Is(err, ErrNotDeleted):
+		writeJSON(w, http.StatusConflict, map[string]any{"success": false, "error": err.Error()})
+	case errors.As(err, &dbErr) && dbErr.HTTPStatus >= 400:
+		writeJSON(w, dbErr.HTTPStatus, map[string]any{"success": false, "error": dbErr.Message})
+	default:
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,325 @@
+// Package softdelete restores soft-deleted records and purges them for good
+// once they have stayed deleted longer than a retention period, on a
+// schedule and on demand.
+package softdelete
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+)
+
+const (
+	// DefaultRetention is how long a record stays restorable when
+	// Config.Retention is unset.
+	DefaultRetention = 30 * 24 * time.Hour
+	// DefaultInterval is how often Run purges when Config.Interval is unset.
+	DefaultInterval = 24 * time.Hour
+	// maxPurge bounds the records one table's purge deletes; the rest wait
+	// for the next pass.
+	maxPurge = 10000
+	// pageSize is the ListDeleted page size.
+	pageSize = 100
+)
+
+// ErrBusy is returned by PurgeTable while another purge is running.
+var ErrBusy = errors.New("a purge is already in progress")
+
+// ErrNotDeleted is returned when a purge targets an active record.
+var ErrNotDeleted = errors.New("record is not deleted")
+
+// Config tunes the purger.
+type Config struct {
+	// Retention is how long a soft-deleted record is kept, measured from its
+	// date_modified, which Delete stamps (default DefaultRetention).
+	Retention time.Duration
+	// Interval between scheduled purges (default DefaultInterval).
+	Interval time.Duration
+	// Tables maps the entities Run purges to their tables.
+	Tables map[string]string
+	// Now returns the current time (default time.Now).
+	Now func() time.Time
+	// OnPurge, when set, is called after each record is hard-deleted, to
+	// remove what depends on it without a foreign key, e.g. its attachments
+	// (see Attachments.OnPurge).
+	OnPurge func(ctx context.Context, table, id string)
+}
+
+// PurgeResult reports one table's purge.
+type PurgeResult struct {
+	Table  string
+	Before time.Time
+	// Purged lists the hard-deleted record IDs.
+	Purged []string
+	// Failed counts records whose hard delete failed, e.g. because another
+	// table still references them.
+	Failed int
+}
+
+// Purger hard-deletes soft-deleted records past their retention, one
+// table at a time.
+type Purger struct {
+	ops    interfaces.DatabaseOperation
+	config Config
+	mu     sync.Mutex
+}
+
+// NewPurger creates a purger over ops.
+func NewPurger(ops interfaces.DatabaseOperation, config Config) *Purger {
+	if config.Retention <= 0 {
+		config.Retention = DefaultRetention
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &Purger{ops: ops, config: config}
+}
+
+// Cutoff returns the date_modified before which deleted records are purged.
+func (p *Purger) Cutoff() time.Time {
+	return p.config.Now().Add(-p.config.Retention)
+}
+
+// Run purges every table right away and then every interval, until ctx is
+// done. It returns at once when Config.Tables is empty.
+func (p *Purger) Run(ctx context.Context) {
+	if len(p.config.Tables) == 0 {
+		return
+	}
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+	for {
+		p.PurgeAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeAll purges every table of Config.Tables in entity order, logging
+// each table's outcome. A failing table does not stop the others.
+func (p *Purger) PurgeAll(ctx context.Context) {
+	entities := make([]string, 0, len(p.config.Tables))
+	for entity := range p.config.Tables {
+		entities = append(entities, entity)
+	}
+	sort.Strings(entities)
+	before := p.Cutoff()
+	for _, entity := range entities {
+		if ctx.Err() != nil {
+			return
+		}
+		result, err := p.PurgeTable(ctx, p.config.Tables[entity], before, "")
+		if err != nil {
+			log.Printf("softdelete: purge %s: %v", entity, err)
+			continue
+		}
+		if len(result.Purged) > 0 || result.Failed > 0 {
+			log.Printf("softdelete: %s: %d purged, %d failed (deleted before %s)",
+				entity, len(result.Purged), result.Failed, before.UTC().Format(time.RFC3339))
+		}
+	}
+}
+
+// PurgeTable hard-deletes the table's records deleted before before,
+// oldest first, restricted to the workspace's records when workspaceID is
+// set. It returns ErrBusy rather than waiting for another purge to finish.
+func (p *Purger) PurgeTable(ctx context.Context, table string, before time.Time, workspaceID string) (*PurgeResult, error) {
+	if !p.mu.TryLock() {
+		return nil, ErrBusy
+	}
+	defer p.mu.Unlock()
+
+	ids, err := p.expired(ctx, table, before, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	result := &PurgeResult{Table: table, Before: before, Purged: []string{}}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err := p.ops.HardDelete(ctx, table, id); err != nil {
+			log.Printf("softdelete: purge %s %s: %v", table, id, err)
+			result.Failed++
+			continue
+		}
+		p.purged(ctx, table, id)
+		result.Purged = append(result.Purged, id)
+	}
+	return result, nil
+}
+
+// expired lists the IDs of the table's records deleted before before. The
+// deleted records are read oldest first, so the scan stops at the first
+// one deleted since; it is finished before anything is deleted, which would
+// shift the pages.
+func (p *Purger) expired(ctx context.Context, table string, before time.Time, workspaceID string) ([]string, error) {
+	params := &interfaces.ListParams{
+		Sort: &commonpb.SortRequest{Fields: []*commonpb.SortField{
+			{Field: "date_modified", Direction: commonpb.SortDirection_ASC},
+		}},
+	}
+	var ids []string
+	for page := int32(1); ; page++ {
+		params.Pagination = &commonpb.PaginationRequest{
+			Limit:  pageSize,
+			Method: &commonpb.PaginationRequest_Offset{Offset: &commonpb.OffsetPagination{Page: page}},
+		}
+		result, err := p.ops.ListDeleted(ctx, table, params)
+		if err != nil {
+			return nil, fmt.Errorf("list deleted %s: %w", table, err)
+		}
+		for _, row := range result.Data {
+			deletedAt, ok := DeletedAt(row)
+			if !ok {
+				continue
+			}
+			if !deletedAt.Before(before) {
+				return ids, nil
+			}
+			id, _ := row["id"].(string)
+			if id == "" || !InWorkspace(row, workspaceID) {
+				continue
+			}
+			if ids = append(ids, id); len(ids) >= maxPurge {
+				return ids, nil
+			}
+		}
+		if len(result.Data) < pageSize {
+			return ids, nil
+		}
+	}
+}
+
+// Purge hard-deletes one soft-deleted record of the table, whatever its
+// age. It returns ErrNotDeleted for an active record, and the database's
+// not-found error for a missing one or, when workspaceID is set, another
+// workspace's.
+func (p *Purger) Purge(ctx context.Context, table, id, workspaceID string) error {
+	row, err := Find(ctx, p.ops, table, id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if Active(row) {
+		return ErrNotDeleted
+	}
+	if err := p.ops.HardDelete(ctx, table, id); err != nil {
+		return err
+	}
+	p.purged(ctx, table, id)
+	return nil
+}
+
+// purged runs Config.OnPurge for a hard-deleted record.
+func (p *Purger) purged(ctx context.Context, table, id string) {
+	if p.config.OnPurge != nil {
+		p.config.OnPurge(ctx, table, id)
+	}
+}
+
+// Find reads a record, deleted or not, treating another workspace's record
+// as missing when workspaceID is set.
+func Find(ctx context.Context, ops interfaces.DatabaseOperation, table, id, workspaceID string) (map[string]any, error) {
+	row, err := ops.Read(ctx, table, id)
+	if err != nil {
+		return nil, err
+	}
+	if !InWorkspace(row, workspaceID) {
+		return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return row, nil
+}
+
+// InWorkspace reports whether row may be touched from workspaceID: any row
+// when workspaceID is empty or the table has no workspace_id, otherwise
+// only the workspace's own rows.
+func InWorkspace(row map[string]any, workspaceID string) bool {
+	if workspaceID == "" {
+		return true
+	}
+	value, ok := row["workspace_id"]
+	if !ok {
+		return true
+	}
+	id, _ := deref(value).(string)
+	return id == workspaceID
+}
+
+// Active reports whether row is active; SQLite stores the flag as 0 or 1.
+func Active(row map[string]any) bool {
+	switch v := deref(row[interfaces.ActiveField]).(type) {
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case int:
+		return v != 0
+	}
+	return false
+}
+
+// DeletedAt returns when a soft-deleted row was deleted: its date_modified,
+// which backends store as unix milliseconds or a timestamp.
+func DeletedAt(row map[string]any) (time.Time, bool) {
+	switch v := deref(row["date_modified"]).(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case int64:
+		return time.UnixMilli(v), v > 0
+	case int:
+		return time.UnixMilli(int64(v)), v > 0
+	case int32:
+		return time.UnixMilli(int64(v)), v > 0
+	case float64:
+		return time.UnixMilli(int64(v)), v > 0
+	case string:
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.UnixMilli(ms), ms > 0
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// deref unwraps the pointers some scanners return for nullable columns.
+func deref(v any) any {
+	switch p := v.(type) {
+	case *bool:
+		if p != nil {
+			return *p
+		}
+	case *int64:
+		if p != nil {
+			return *p
+		}
+	case *string:
+		if p != nil {
+			return *p
+		}
+	case *time.Time:
+		if p != nil {
+			return *p
+		}
+	default:
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package softdelete
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+)
+
+// memOps keeps one table's rows by ID; HardDelete fails for IDs in pinned.
+type memOps struct {
+	interfaces.DatabaseOperation
+	rows   map[string]map[string]any
+	pinned map[string]bool
+	lists  int
+}
+
+func (o *memOps) Read(_ context.Context, _ string, id string) (map[string]any, error) {
+	row, ok := o.rows[id]
+	if !ok {
+		return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+	}
+	return row, nil
+}
+
+func (o *memOps) Restore(_ context.Context, _ string, id string) error {
+	o.rows[id]["active"] = true
+	return nil
+}
+
+func (o *memOps) HardDelete(_ context.Context, _ string, id string) error {
+	if o.pinned[id] {
+		return errors.New("still referenced")
+	}
+	delete(o.rows, id)
+	return nil
+}
+
+// ListDeleted pages the inactive rows by date_modified, oldest first.
+func (o *memOps) ListDeleted(_ context.Context, _ string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	o.lists++
+	var deleted []map[string]any
+	for _, row := range o.rows {
+		if !Active(row) {
+			deleted = append(deleted, row)
+		}
+	}
+	sort.Slice(deleted, func(i, j int) bool {
+		return deleted[i]["date_modified"].(int64) < deleted[j]["date_modified"].(int64)
+	})
+	limit := int(params.Pagination.Limit)
+	start := (int(params.Pagination.GetOffset().Page) - 1) * limit
+	if start > len(deleted) {
+		start = len(deleted)
+	}
+	end := min(start+limit, len(deleted))
+	return &interfaces.ListResult{Data: deleted[start:end], Total: int32(len(deleted))}, nil
+}
+
+var now = time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func row(id, workspaceID string, active bool, deletedDaysAgo int) map[string]any {
+	return map[string]any{
+		"id":            id,
+		"workspace_id":  workspaceID,
+		"active":        active,
+		"date_modified": now.AddDate(0, 0, -deletedDaysAgo).UnixMilli(),
+	}
+}
+
+func newOps(rows ...map[string]any) *memOps {
+	o := &memOps{rows: map[string]map[string]any{}, pinned: map[string]bool{}}
+	for _, r := range rows {
+		o.rows[r["id"].(string)] = r
+	}
+	return o
+}
+
+func TestPurger_PurgeTable(t *testing.T) {
+	ops := newOps(
+		row("old-1", "ws-1", false, 40),
+		row("old-2", "ws-2", false, 35),
+		row("pinned", "ws-1", false, 31),
+		row("recent", "ws-1", false, 5),
+		row("live", "ws-1", true, 90),
+	)
+	ops.pinned["pinned"] = true
+	p := NewPurger(ops, Config{Now: func() time.Time { return now }})
+
+	result, err := p.PurgeTable(context.Background(), "client", p.Cutoff(), "ws-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Purged) != 1 || result.Purged[0] != "old-1" || result.Failed != 1 {
+		t.Errorf("workspace purge = %+v", result)
+	}
+
+	result, err = p.PurgeTable(context.Background(), "client", p.Cutoff(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Purged) != 1 || result.Purged[0] != "old-2" {
+		t.Errorf("purge = %+v", result)
+	}
+	for _, id := range []string{"pinned", "recent", "live"} {
+		if _, ok := ops.rows[id]; !ok {
+			t.Errorf("%s was purged", id)
+		}
+	}
+}
+
+func TestPurger_StopsAtRetention(t *testing.T) {
+	var rows []map[string]any
+	for i := range 250 {
+		rows = append(rows, row(time.Duration(i).String(), "", false, 1))
+	}
+	ops := newOps(rows...)
+	p := NewPurger(ops, Config{Now: func() time.Time { return now }})
+	result, err := p.PurgeTable(context.Background(), "client", p.Cutoff(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Purged) != 0 || ops.lists != 1 {
+		t.Errorf("purged %d after %d pages, want none after the first", len(result.Purged), ops.lists)
+	}
+}
+
+func TestDeletedAt(t *testing.T) {
+	want := time.UnixMilli(1767225600000)
+	for _, v := range []any{int64(1767225600000), float64(1767225600000), "1767225600000", want.Format(time.RFC3339Nano), &want} {
+		if got, ok := DeletedAt(map[string]any{"date_modified": v}); !ok || !got.Equal(want) {
+			t.Errorf("DeletedAt(%T) = %v, %v", v, got, ok)
+		}
+	}
+	if _, ok := DeletedAt(map[string]any{"date_modified": nil}); ok {
+		t.Error("DeletedAt(nil) reported a time")
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	ops := newOps(row("gone", "ws-1", false, 2), row("other", "ws-2", false, 2), row("live", "ws-1", true, 0))
+	p := NewPurger(ops, Config{Now: func() time.Time { return now }})
+	h := NewHandlers(ops, p, testutil.OpenActionGate())
+	userCtx := contextutil.WithWorkspaceID(contextutil.WithUserID(context.Background(), "u-1"), "ws-1")
+
+	call := func(handler http.HandlerFunc, body string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body)).WithContext(userCtx))
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+
+	if code, _ := call(h.Restore("client", "client"), `{"id":"other"}`); code != http.StatusNotFound {
+		t.Errorf("restore from another workspace = %d", code)
+	}
+	if code, _ := call(h.Purge("client", "client"), `{"id":"live"}`); code != http.StatusConflict {
+		t.Errorf("purge of an active record = %d", code)
+	}
+	code, out := call(h.Restore("client", "client"), `{"id":"gone"}`)
+	if data, _ := out["data"].(map[string]any); code != http.StatusOK || data["active"] != true {
+		t.Errorf("restore = %d %v", code, out)
+	}
+	if code, _ := call(h.Purge("client", "client"), `{"id":"gone"}`); code != http.StatusConflict {
+		t.Errorf("purge of a restored record = %d", code)
+	}
+	ops.rows["gone"]["active"] = false
+	if code, _ := call(h.Purge("client", "client"), `{"id":"gone"}`); code != http.StatusOK || ops.rows["gone"] != nil {
+		t.Errorf("purge = %d", code)
+	}
+	if code, _ := call(h.Purge("client", "client"), `{`); code != http.StatusBadRequest {
+		t.Errorf("malformed body = %d", code)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"mime"
 	"path/filepath"
+	"strings"
 )
 
 // GenerateObjectID creates a unique identifier for an object
@@ -18,3 +19,28 @@ func DetectContentType(path string) string {
 	contentType := mime.TypeByExtension(filepath.Ext(path))
 	return contentType
 }
+
+// SanitizeDownloadFilename derives a safe Content-Disposition filename. It prefers
+// the server-supplied display name, falls back to the object key's basename, strips
+// any path separators and control/quote characters, and defaults to "download".
+func SanitizeDownloadFilename(name, objectKey string) string {
+	if name == "" {
+		name = filepath.Base(objectKey)
+	}
+	name = filepath.Base(name)
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r < 0x20:
+			return -1
+		case r == '"' || r == '\\' || r == '/':
+			return -1
+		default:
+			return r
+		}
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." {
+		return "download"
+	}
+	return name
+}
@@ -0,0 +1,17 @@
+package common
+
+import "testing"
+
+func TestSanitizeDownloadFilename(t *testing.T) {
+	for _, tc := range []struct{ name, key, want string }{
+		{"report.pdf", "a/b/c", "report.pdf"},
+		{"", "ws/files/invoice.pdf", "invoice.pdf"},
+		{"../../etc/passwd", "k", "passwd"},
+		{"evil\"\r\n.html", "k", "evil.html"},
+		{"..", "k", "download"},
+	} {
+		if got := SanitizeDownloadFilename(tc.name, tc.key); got != tc.want {
+			t.Errorf("SanitizeDownloadFilename(%q, %q) = %q, want %q", tc.name, tc.key, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// Isolation says how a cloud adapter keeps one workspace's objects apart from
+// another's.
+type Isolation string
+
+const (
+	// IsolationNone stores objects under the container and key as given.
+	IsolationNone Isolation = "none"
+	// IsolationPrefix stores objects under WorkspacePrefix/<workspace>/ in the
+	// container.
+	IsolationPrefix Isolation = "prefix"
+	// IsolationBucket stores each workspace's objects in its own bucket, named by
+	// WorkspaceScope.BucketTemplate.
+	IsolationBucket Isolation = "bucket"
+)
+
+// WorkspacePrefix is the first key segment under prefix isolation.
+const WorkspacePrefix = "workspaces"
+
+// WorkspacePlaceholder is replaced by the workspace ID in a bucket template.
+const WorkspacePlaceholder = "{workspace}"
+
+var (
+	workspaceIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	bucketNamePattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+)
+
+// ParseIsolation reads an isolation mode; empty means IsolationNone.
+func ParseIsolation(v string) (Isolation, error) {
+	switch Isolation(strings.ToLower(strings.TrimSpace(v))) {
+	case "", IsolationNone:
+		return IsolationNone, nil
+	case IsolationPrefix:
+		return IsolationPrefix, nil
+	case IsolationBucket:
+		return IsolationBucket, nil
+	}
+	return "", fmt.Errorf("unknown storage isolation %q (use none, prefix or bucket)", v)
+}
+
+// WorkspaceScope maps the logical container and key a caller uses to where the
+// object is stored for the request's workspace. Callers keep working with
+// logical names; only the adapter sees the scoped ones.
+type WorkspaceScope struct {
+	Mode Isolation
+	// BucketTemplate names a workspace's bucket under IsolationBucket, e.g.
+	// "acme-files-{workspace}". Workspace IDs are lower-cased to fit bucket
+	// naming rules.
+	BucketTemplate string
+}
+
+// Validate checks the scope's configuration.
+func (s WorkspaceScope) Validate() error {
+	if s.Mode == IsolationBucket && !strings.Contains(s.BucketTemplate, WorkspacePlaceholder) {
+		return fmt.Errorf("bucket isolation needs a bucket template containing %s", WorkspacePlaceholder)
+	}
+	return nil
+}
+
+// Enabled reports whether objects are scoped to a workspace.
+func (s WorkspaceScope) Enabled() bool {
+	return s.Mode == IsolationPrefix || s.Mode == IsolationBucket
+}
+
+// Resolve returns the container and key to store a logical object under. With
+// isolation on, the request must carry a workspace: an object is never stored
+// unscoped by accident.
+func (s WorkspaceScope) Resolve(ctx context.Context, container, key string) (string, string, error) {
+	if !s.Enabled() {
+		return container, key, nil
+	}
+	id, ok := identity.FromContext(ctx)
+	if !ok || id.WorkspaceID == "" {
+		return "", "", fmt.Errorf("storage is isolated per workspace but the request has no workspace")
+	}
+	if !workspaceIDPattern.MatchString(id.WorkspaceID) {
+		return "", "", fmt.Errorf("workspace ID %q cannot scope storage", id.WorkspaceID)
+	}
+	if s.Mode == IsolationPrefix {
+		return container, WorkspacePrefix + "/" + id.WorkspaceID + "/" + key, nil
+	}
+	bucket := strings.ReplaceAll(s.BucketTemplate, WorkspacePlaceholder, strings.ToLower(strings.ReplaceAll(id.WorkspaceID, "_", "-")))
+	if !bucketNamePattern.MatchString(bucket) {
+		return "", "", fmt.Errorf("bucket name %q for workspace %s is invalid", bucket, id.WorkspaceID)
+	}
+	return bucket, key, nil
+}
@@ -0,0 +1,51 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+func TestParseIsolation(t *testing.T) {
+	for in, want := range map[string]Isolation{"": IsolationNone, "none": IsolationNone, "Prefix": IsolationPrefix, " bucket ": IsolationBucket} {
+		if got, err := ParseIsolation(in); err != nil || got != want {
+			t.Errorf("ParseIsolation(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseIsolation("tenant"); err == nil {
+		t.Error("unknown mode accepted")
+	}
+}
+
+func TestWorkspaceScope_Resolve(t *testing.T) {
+	ctx := identity.WithRequestIdentity(context.Background(), &identity.RequestIdentity{WorkspaceID: "WS_1"})
+
+	bucket, key, err := WorkspaceScope{}.Resolve(context.Background(), "files", "a/b.pdf")
+	if err != nil || bucket != "files" || key != "a/b.pdf" {
+		t.Errorf("none = %q %q %v", bucket, key, err)
+	}
+
+	prefix := WorkspaceScope{Mode: IsolationPrefix}
+	if bucket, key, err := prefix.Resolve(ctx, "files", "a/b.pdf"); err != nil || bucket != "files" || key != "workspaces/WS_1/a/b.pdf" {
+		t.Errorf("prefix = %q %q %v", bucket, key, err)
+	}
+	if _, _, err := prefix.Resolve(context.Background(), "files", "a/b.pdf"); err == nil {
+		t.Error("prefix without a workspace resolved")
+	}
+	traversal := identity.WithRequestIdentity(context.Background(), &identity.RequestIdentity{WorkspaceID: "../ws-2"})
+	if _, _, err := prefix.Resolve(traversal, "files", "a/b.pdf"); err == nil {
+		t.Error("workspace ID with a path resolved")
+	}
+
+	perBucket := WorkspaceScope{Mode: IsolationBucket, BucketTemplate: "acme-{workspace}"}
+	if err := perBucket.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if bucket, key, err := perBucket.Resolve(ctx, "files", "a/b.pdf"); err != nil || bucket != "acme-ws-1" || key != "a/b.pdf" {
+		t.Errorf("bucket = %q %q %v", bucket, key, err)
+	}
+	if err := (WorkspaceScope{Mode: IsolationBucket, BucketTemplate: "acme"}).Validate(); err == nil {
+		t.Error("template without placeholder accepted")
+	}
+}
@@ -0,0 +1,52 @@
+package common
+
+import (
+	"context"
+
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// Metadata keys cloud adapters stamp on uploaded objects, so an object can be
+// traced to its workspace and owning record from the bucket alone (audits,
+// lifecycle rules, cost reports).
+const (
+	MetadataWorkspaceID = "workspace_id"
+	MetadataEntityType  = "entity_type"
+	MetadataEntityID    = "entity_id"
+)
+
+type objectOwnerKey struct{}
+
+type objectOwner struct {
+	entityType string
+	entityID   string
+}
+
+// WithObjectOwner records the entity the objects uploaded with ctx belong to,
+// e.g. ("client", "cli_123").
+func WithObjectOwner(ctx context.Context, entityType, entityID string) context.Context {
+	return context.WithValue(ctx, objectOwnerKey{}, objectOwner{entityType: entityType, entityID: entityID})
+}
+
+// TagMetadata returns a copy of metadata tagged with the request's workspace and
+// the owner set by WithObjectOwner. The workspace always comes from the request
+// identity, never from the caller's metadata; an owner the caller put in
+// metadata is kept when ctx has none.
+func TagMetadata(ctx context.Context, metadata map[string]string) map[string]string {
+	tagged := make(map[string]string, len(metadata)+3)
+	for k, v := range metadata {
+		tagged[k] = v
+	}
+	delete(tagged, MetadataWorkspaceID)
+	if id, ok := identity.FromContext(ctx); ok && id.WorkspaceID != "" {
+		tagged[MetadataWorkspaceID] = id.WorkspaceID
+	}
+	if owner, ok := ctx.Value(objectOwnerKey{}).(objectOwner); ok {
+		tagged[MetadataEntityType] = owner.entityType
+		tagged[MetadataEntityID] = owner.entityID
+	}
+	if len(tagged) == 0 {
+		return nil
+	}
+	return tagged
+}
@@ -0,0 +1,37 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+func TestTagMetadata(t *testing.T) {
+	if got := TagMetadata(context.Background(), nil); got != nil {
+		t.Errorf("untagged = %v", got)
+	}
+
+	ctx := identity.WithRequestIdentity(context.Background(), &identity.RequestIdentity{WorkspaceID: "ws-1"})
+	ctx = WithObjectOwner(ctx, "client", "cli-1")
+	in := map[string]string{"source": "upload", MetadataWorkspaceID: "ws-2"}
+	got := TagMetadata(ctx, in)
+	want := map[string]string{"source": "upload", MetadataWorkspaceID: "ws-1", MetadataEntityType: "client", MetadataEntityID: "cli-1"}
+	if len(got) != len(want) {
+		t.Fatalf("tagged = %v", got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+	if in[MetadataWorkspaceID] != "ws-2" {
+		t.Error("caller's metadata was modified")
+	}
+
+	// Without a workspace in the request the caller cannot claim one.
+	spoofed := TagMetadata(context.Background(), map[string]string{MetadataWorkspaceID: "ws-2", MetadataEntityID: "cli-2"})
+	if _, ok := spoofed[MetadataWorkspaceID]; ok || spoofed[MetadataEntityID] != "cli-2" {
+		t.Errorf("spoofed = %v", spoofed)
+	}
+}
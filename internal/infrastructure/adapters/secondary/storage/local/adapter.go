@@ -508,6 +508,27 @@ func (p *LocalStorageProvider) DeleteContainer(ctx context.Context, req *pb.Dele
 	}, nil
 }
 
+// DeleteObject removes an object's file. A missing file is not an error.
+func (p *LocalStorageProvider) DeleteObject(ctx context.Context, containerName, objectKey string) error {
+	if !p.enabled {
+		return ports.NewStorageError(ports.StorageErrorCodeProviderError, "provider not initialized", nil)
+	}
+	if containerName == "" || objectKey == "" {
+		return ports.NewStorageError(ports.StorageErrorCodeInvalidPath, "missing required fields", nil)
+	}
+
+	containerPath := filepath.Join(p.basePath, sanitizePath(containerName))
+	objectPath := filepath.Join(containerPath, sanitizePath(objectKey))
+	if !isPathWithinBase(objectPath, p.basePath) || objectPath == containerPath {
+		return ports.NewStorageError(ports.StorageErrorCodeInvalidPath, "invalid path", nil)
+	}
+
+	if err := os.Remove(objectPath); err != nil && !os.IsNotExist(err) {
+		return ports.NewStorageError(ports.StorageErrorCodeDeleteFailed, "delete failed", err)
+	}
+	return nil
+}
+
 // IsHealthy checks if the storage service is available
 func (p *LocalStorageProvider) IsHealthy(ctx context.Context) error {
 	if !p.enabled {
@@ -544,10 +565,11 @@ func (p *LocalStorageProvider) IsEnabled() bool {
 // Streaming tier (StreamingStorageProvider) + capability discovery
 // =============================================================================
 
-// Compile-time assertions that local implements both optional sub-interfaces.
+// Compile-time assertions that local implements the optional sub-interfaces.
 var (
-	_ ports.StreamingStorageProvider  = (*LocalStorageProvider)(nil)
-	_ ports.StorageCapabilityProvider = (*LocalStorageProvider)(nil)
+	_ ports.StreamingStorageProvider      = (*LocalStorageProvider)(nil)
+	_ ports.StorageCapabilityProvider     = (*LocalStorageProvider)(nil)
+	_ ports.ObjectDeletingStorageProvider = (*LocalStorageProvider)(nil)
 )
 
 // UploadStream validates/sanitizes the path (reusing the same guards as
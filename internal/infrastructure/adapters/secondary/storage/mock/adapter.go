@@ -395,6 +395,21 @@ func (p *MockStorageProvider) DeleteContainer(ctx context.Context, req *pb.Delet
 	}, nil
 }
 
+// DeleteObject removes an object from memory. A missing object is not an error.
+func (p *MockStorageProvider) DeleteObject(ctx context.Context, containerName, key string) error {
+	if !p.enabled {
+		return ports.NewStorageError(ports.StorageErrorCodeProviderError, "not initialized", nil)
+	}
+	if containerName == "" || key == "" {
+		return ports.NewStorageError(ports.StorageErrorCodeInvalidPath, "missing fields", nil)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.objects, objectKey(containerName, key))
+	return nil
+}
+
 // IsHealthy checks if the mock storage service is available
 func (p *MockStorageProvider) IsHealthy(ctx context.Context) error {
 	if !p.enabled {
@@ -426,10 +441,11 @@ func (p *MockStorageProvider) IsEnabled() bool {
 // Streaming tier (StreamingStorageProvider) + capability discovery
 // =============================================================================
 
-// Compile-time assertions that mock implements both optional sub-interfaces.
+// Compile-time assertions that mock implements the optional sub-interfaces.
 var (
-	_ ports.StreamingStorageProvider  = (*MockStorageProvider)(nil)
-	_ ports.StorageCapabilityProvider = (*MockStorageProvider)(nil)
+	_ ports.StreamingStorageProvider      = (*MockStorageProvider)(nil)
+	_ ports.StorageCapabilityProvider     = (*MockStorageProvider)(nil)
+	_ ports.ObjectDeletingStorageProvider = (*MockStorageProvider)(nil)
 )
 
 // UploadStream io.ReadAll's body into the in-memory map. The mock has no real I/O,
@@ -95,6 +95,16 @@ func TestMockStorageProvider(t *testing.T) {
 		t.Errorf("Downloaded data doesn't match uploaded data")
 	}
 
+	// Test delete object (twice: a missing object is not an error)
+	for i := 0; i < 2; i++ {
+		if err := mockProvider.DeleteObject(ctx, "mock-container", "new/file.txt"); err != nil {
+			t.Fatalf("Failed to delete object: %v", err)
+		}
+	}
+	if mockProvider.GetObjectCount() != 0 {
+		t.Errorf("Expected 0 files after delete, got %d", mockProvider.GetObjectCount())
+	}
+
 	// Test clear data
 	mockProvider.ClearAll()
 	if mockProvider.GetObjectCount() != 0 {
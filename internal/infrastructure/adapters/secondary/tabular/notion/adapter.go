@@ -0,0 +1,966 @@
+// Package notion provides Notion databases as a tabular data source. Each
+// database is a table: its pages are records and its properties are fields.
+// Filters and sorts are translated to Notion query filters, so matching
+// happens on Notion's side rather than after reading the whole database.
+//
+// A source ID is a database ID (the 32-character ID in the database URL).
+// Requests that only carry a table name use it as the database ID instead.
+// With no ID at all, GetSource and ListTables list every database shared
+// with the integration.
+package notion
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports/integration"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/registry"
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/metrics"
+	"github.com/erniealice/espyna-golang/shared/tracing"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+	tabularpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/tabular"
+)
+
+// =============================================================================
+// Self-Registration - Adapter registers itself with the factory
+// =============================================================================
+
+func init() {
+	registry.RegisterTabularProvider(
+		"notion",
+		func() integration.TabularSourceProvider {
+			return NewNotionProvider()
+		},
+		transformConfig,
+	)
+	registry.RegisterTabularBuildFromEnv("notion", buildFromEnv)
+}
+
+// buildFromEnv creates and initializes a Notion provider from environment variables:
+//
+//	LEAPFOR_INTEGRATION_TABULAR_NOTION_API_KEY      internal integration secret
+//	LEAPFOR_INTEGRATION_TABULAR_NOTION_TIMEOUT      request timeout in seconds (default 30)
+//	LEAPFOR_INTEGRATION_TABULAR_NOTION_MAX_RETRIES  retries of rate-limited calls (default 3)
+//	LEAPFOR_INTEGRATION_TABULAR_NOTION_API_URL      API base URL (default https://api.notion.com/v1)
+func buildFromEnv() (integration.TabularSourceProvider, error) {
+	timeout := 30
+	if t, err := strconv.Atoi(os.Getenv("LEAPFOR_INTEGRATION_TABULAR_NOTION_TIMEOUT")); err == nil && t > 0 {
+		timeout = t
+	}
+	retries := 3
+	if r, err := strconv.Atoi(os.Getenv("LEAPFOR_INTEGRATION_TABULAR_NOTION_MAX_RETRIES")); err == nil && r >= 0 {
+		retries = r
+	}
+
+	config := &tabularpb.TabularProviderConfig{
+		ProviderId:     "notion",
+		ProviderType:   tabularpb.TabularProviderType_TABULAR_PROVIDER_TYPE_REST_API,
+		Enabled:        true,
+		TimeoutSeconds: int32(timeout),
+		MaxRetries:     int32(retries),
+		Auth: &tabularpb.TabularProviderConfig_ApiKeyAuth{
+			ApiKeyAuth: &tabularpb.ApiKeyAuth{
+				ApiKey: os.Getenv("LEAPFOR_INTEGRATION_TABULAR_NOTION_API_KEY"),
+			},
+		},
+		Settings: map[string]string{},
+	}
+	if apiURL := os.Getenv("LEAPFOR_INTEGRATION_TABULAR_NOTION_API_URL"); apiURL != "" {
+		config.Settings["api_url"] = apiURL
+	}
+
+	p := NewNotionProvider()
+	if err := p.Initialize(config); err != nil {
+		return nil, fmt.Errorf("notion: failed to initialize: %w", err)
+	}
+	return p, nil
+}
+
+// transformConfig transforms raw config map to TabularProviderConfig
+func transformConfig(rawConfig map[string]any) (*tabularpb.TabularProviderConfig, error) {
+	config := &tabularpb.TabularProviderConfig{
+		ProviderId:     "notion",
+		ProviderType:   tabularpb.TabularProviderType_TABULAR_PROVIDER_TYPE_REST_API,
+		Enabled:        true,
+		TimeoutSeconds: 30,
+		MaxRetries:     3,
+		Settings:       map[string]string{},
+	}
+
+	apiKey, _ := rawConfig["api_key"].(string)
+	config.Auth = &tabularpb.TabularProviderConfig_ApiKeyAuth{
+		ApiKeyAuth: &tabularpb.ApiKeyAuth{ApiKey: apiKey},
+	}
+	if apiURL, ok := rawConfig["api_url"].(string); ok {
+		config.Settings["api_url"] = apiURL
+	}
+
+	if timeout, ok := rawConfig["timeout_seconds"].(int); ok {
+		config.TimeoutSeconds = int32(timeout)
+	} else if timeout, ok := rawConfig["timeout_seconds"].(float64); ok {
+		config.TimeoutSeconds = int32(timeout)
+	}
+	if retries, ok := rawConfig["max_retries"].(int); ok {
+		config.MaxRetries = int32(retries)
+	} else if retries, ok := rawConfig["max_retries"].(float64); ok {
+		config.MaxRetries = int32(retries)
+	}
+
+	return config, nil
+}
+
+// =============================================================================
+// Notion Provider Implementation
+// =============================================================================
+
+// schemaTTL is how long a database's property schema is reused before it is
+// fetched again. Every read and write needs the schema to translate values,
+// and Notion allows an integration about three requests a second.
+const schemaTTL = time.Minute
+
+type cachedSchema struct {
+	schema    *databaseSchema
+	fetchedAt time.Time
+}
+
+// NotionProvider provides Notion databases as a tabular data source
+type NotionProvider struct {
+	mu      sync.RWMutex
+	enabled bool
+	config  *tabularpb.TabularProviderConfig
+	client  *client
+	schemas map[string]cachedSchema
+	logger  *slog.Logger
+}
+
+var _ integration.TabularSourceProvider = (*NotionProvider)(nil)
+
+// NewNotionProvider creates a new Notion tabular provider
+func NewNotionProvider() *NotionProvider {
+	return &NotionProvider{
+		schemas: make(map[string]cachedSchema),
+		logger:  slog.New(correlation.NewHandler(slog.Default().Handler())).With("provider", "notion"),
+	}
+}
+
+// =============================================================================
+// Lifecycle Methods
+// =============================================================================
+
+// Name returns the unique identifier of this provider
+func (p *NotionProvider) Name() string {
+	return "notion"
+}
+
+// Initialize sets up the Notion provider with the given configuration
+func (p *NotionProvider) Initialize(config *tabularpb.TabularProviderConfig) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	apiKey := strings.TrimSpace(config.GetApiKeyAuth().GetApiKey())
+	if apiKey == "" {
+		return fmt.Errorf("notion: an integration secret (api_key_auth.api_key) is required")
+	}
+
+	timeout := 30 * time.Second
+	if config.TimeoutSeconds > 0 {
+		timeout = time.Duration(config.TimeoutSeconds) * time.Second
+	}
+	baseURL := defaultBaseURL
+	if apiURL := config.GetSettings()["api_url"]; apiURL != "" {
+		baseURL = strings.TrimRight(apiURL, "/")
+	}
+
+	p.config = config
+	p.client = &client{
+		http:    &http.Client{Timeout: timeout},
+		baseURL: baseURL,
+		token:   apiKey,
+		retries: int(config.MaxRetries),
+	}
+	p.schemas = make(map[string]cachedSchema)
+	p.enabled = config.Enabled
+
+	p.logger.Info("Notion tabular provider initialized", "api_url", baseURL)
+	return nil
+}
+
+// IsEnabled returns whether this provider is currently enabled
+func (p *NotionProvider) IsEnabled() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.enabled
+}
+
+// IsHealthy checks if the Notion provider is available
+func (p *NotionProvider) IsHealthy(ctx context.Context) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.enabled {
+		return fmt.Errorf("notion: provider is not enabled")
+	}
+	if p.client == nil {
+		return fmt.Errorf("notion: client is not initialized")
+	}
+	return nil
+}
+
+// Close cleans up Notion provider resources
+func (p *NotionProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.enabled = false
+	p.client = nil
+	p.schemas = make(map[string]cachedSchema)
+
+	p.logger.Info("Notion tabular provider closed")
+	return nil
+}
+
+// =============================================================================
+// Metadata Methods
+// =============================================================================
+
+// GetCapabilities returns the list of capabilities supported by this provider
+func (p *NotionProvider) GetCapabilities() []tabularpb.TabularCapability {
+	return []tabularpb.TabularCapability{
+		tabularpb.TabularCapability_TABULAR_CAPABILITY_READ,
+		tabularpb.TabularCapability_TABULAR_CAPABILITY_WRITE,
+		tabularpb.TabularCapability_TABULAR_CAPABILITY_UPDATE,
+		tabularpb.TabularCapability_TABULAR_CAPABILITY_DELETE,
+		tabularpb.TabularCapability_TABULAR_CAPABILITY_SEARCH,
+		tabularpb.TabularCapability_TABULAR_CAPABILITY_SCHEMA,
+		tabularpb.TabularCapability_TABULAR_CAPABILITY_BATCH_OPERATIONS,
+		tabularpb.TabularCapability_TABULAR_CAPABILITY_MULTIPLE_TABLES,
+	}
+}
+
+// GetProviderType returns the type of this provider
+func (p *NotionProvider) GetProviderType() tabularpb.TabularProviderType {
+	return tabularpb.TabularProviderType_TABULAR_PROVIDER_TYPE_REST_API
+}
+
+// =============================================================================
+// Core CRUD Operations
+// =============================================================================
+
+// startCall traces a Notion call as a client span.
+func startCall(ctx context.Context, operation, table string) (context.Context, tracing.Span) {
+	return tracing.StartClient(ctx, "notion "+operation,
+		tracing.String("tabular.provider", "notion"),
+		tracing.String("tabular.operation", operation),
+		tracing.String("tabular.table", table),
+	)
+}
+
+// observe records a Notion call in the tabular operation metrics and ends
+// its span.
+func observe(span tracing.Span, operation, table string, start time.Time, success bool, err error) {
+	metrics.ObserveTabularOperation("notion", operation, table, start, err != nil || !success)
+	if err == nil && !success {
+		err = fmt.Errorf("notion %s failed", operation)
+	}
+	span.End(err)
+}
+
+// ReadRecords queries pages from a Notion database. Without a filter on the
+// selection the whole database is paged through. TotalCount is exact only
+// when HasMore is false; Notion does not report totals, so otherwise it
+// counts the records up to and including this page.
+func (p *NotionProvider) ReadRecords(ctx context.Context, req *tabularpb.ReadRecordsRequest) (reply *tabularpb.ReadRecordsResponse, opErr error) {
+	data := req.GetData()
+	dbID := databaseID(data.GetSourceId(), data.GetSelection().GetTable())
+	ctx, span := startCall(ctx, "read", dbID)
+	defer func(start time.Time) {
+		observe(span, "read", dbID, start, reply.GetSuccess(), opErr)
+	}(time.Now())
+
+	c := p.activeClient()
+	if c == nil {
+		return &tabularpb.ReadRecordsResponse{Success: false, Error: notInitialized()}, nil
+	}
+	if data == nil || dbID == "" {
+		return &tabularpb.ReadRecordsResponse{Success: false, Error: invalidRequest("source_id (the Notion database ID) is required")}, nil
+	}
+
+	schema, err := p.schema(ctx, c, dbID)
+	if err != nil {
+		return &tabularpb.ReadRecordsResponse{Success: false, Error: failure("READ_FAILED", err)}, nil
+	}
+
+	selection := data.GetSelection().GetRecords()
+	var (
+		records []*tabularpb.Record
+		hasMore bool
+		offset  = int(selection.GetOffset())
+	)
+	if ids := selection.GetRecordIds(); len(ids) > 0 {
+		records, err = p.readPagesByID(ctx, c, schema, ids)
+		offset = 0
+	} else {
+		limit := int(selection.GetLimit())
+		if r := selection.GetIndexRange(); r != nil {
+			offset, limit = int(r.Start), int(r.End-r.Start)
+		}
+		records, hasMore, err = p.queryRecords(ctx, c, schema, dbID, selection.GetFilter(), data.GetSortBy(), offset, limit)
+	}
+	if err != nil {
+		p.logger.ErrorContext(ctx, "Failed to read from Notion", "error", err, "database_id", dbID)
+		return &tabularpb.ReadRecordsResponse{Success: false, Error: selectionError("READ_FAILED", err)}, nil
+	}
+	selectFields(schema, records, data.GetSelection().GetFields())
+
+	result := &tabularpb.ReadRecordsResult{
+		Records:    records,
+		TotalCount: int64(offset + len(records)),
+		HasMore:    hasMore,
+		NextOffset: int32(offset + len(records)),
+	}
+	if data.IncludeSchema {
+		result.Schema = schema.tableSchema()
+	}
+
+	p.logger.InfoContext(ctx, "Read records from Notion", "database_id", dbID, "count", len(records))
+
+	return &tabularpb.ReadRecordsResponse{
+		Success: true,
+		Data:    []*tabularpb.ReadRecordsResult{result},
+	}, nil
+}
+
+// WriteRecords creates one page per record in a Notion database. Notion has
+// no bulk insert, so a failure part-way leaves the earlier pages created;
+// the error says how many were written.
+func (p *NotionProvider) WriteRecords(ctx context.Context, req *tabularpb.WriteRecordsRequest) (reply *tabularpb.WriteRecordsResponse, opErr error) {
+	data := req.GetData()
+	dbID := databaseID(data.GetSourceId(), data.GetTable())
+	ctx, span := startCall(ctx, "write", dbID)
+	defer func(start time.Time) {
+		observe(span, "write", dbID, start, reply.GetSuccess(), opErr)
+	}(time.Now())
+
+	c := p.activeClient()
+	if c == nil {
+		return &tabularpb.WriteRecordsResponse{Success: false, Error: notInitialized()}, nil
+	}
+	if data == nil || dbID == "" {
+		return &tabularpb.WriteRecordsResponse{Success: false, Error: invalidRequest("source_id (the Notion database ID) is required")}, nil
+	}
+
+	schema, err := p.schema(ctx, c, dbID)
+	if err != nil {
+		return &tabularpb.WriteRecordsResponse{Success: false, Error: failure("WRITE_FAILED", err)}, nil
+	}
+
+	// Convert every record before creating any page so that a bad value does
+	// not leave half the batch written.
+	payloads := make([]map[string]any, len(data.Records))
+	for i, record := range data.Records {
+		if payloads[i], err = recordToProperties(schema, record); err != nil {
+			return &tabularpb.WriteRecordsResponse{Success: false, Error: invalidRequest(fmt.Sprintf("record %d: %v", i, err))}, nil
+		}
+	}
+
+	result := &tabularpb.WriteRecordsResult{Location: dbID}
+	for i, properties := range payloads {
+		pg, err := c.createPage(ctx, dbID, properties)
+		if err != nil {
+			p.logger.ErrorContext(ctx, "Failed to create Notion page", "error", err, "database_id", dbID, "written", i)
+			return &tabularpb.WriteRecordsResponse{
+				Success: false,
+				Error:   failure("WRITE_FAILED", fmt.Errorf("record %d (after %d written): %w", i, i, err)),
+			}, nil
+		}
+		result.RecordsWritten++
+		if data.GetOptions().GetReturnRecords() {
+			result.WrittenRecords = append(result.WrittenRecords, pageToRecord(schema, pg, int64(i)))
+		}
+	}
+
+	p.logger.InfoContext(ctx, "Wrote records to Notion", "database_id", dbID, "count", result.RecordsWritten)
+
+	return &tabularpb.WriteRecordsResponse{
+		Success: true,
+		Data:    []*tabularpb.WriteRecordsResult{result},
+	}, nil
+}
+
+// UpdateRecords patches the selected pages. The selection must name record
+// IDs or carry a filter; updating a whole database by omission is refused.
+// Field updates apply to every selected page, while replacement records are
+// paired with the selected pages in order.
+func (p *NotionProvider) UpdateRecords(ctx context.Context, req *tabularpb.UpdateRecordsRequest) (reply *tabularpb.UpdateRecordsResponse, opErr error) {
+	data := req.GetData()
+	dbID := databaseID(data.GetSourceId(), data.GetSelection().GetTable())
+	ctx, span := startCall(ctx, "update", dbID)
+	defer func(start time.Time) {
+		observe(span, "update", dbID, start, reply.GetSuccess(), opErr)
+	}(time.Now())
+
+	c := p.activeClient()
+	if c == nil {
+		return &tabularpb.UpdateRecordsResponse{Success: false, Error: notInitialized()}, nil
+	}
+	if data == nil || dbID == "" {
+		return &tabularpb.UpdateRecordsResponse{Success: false, Error: invalidRequest("source_id (the Notion database ID) is required")}, nil
+	}
+
+	schema, err := p.schema(ctx, c, dbID)
+	if err != nil {
+		return &tabularpb.UpdateRecordsResponse{Success: false, Error: failure("UPDATE_FAILED", err)}, nil
+	}
+
+	var shared map[string]any
+	if len(data.ReplacementRecords) == 0 {
+		if shared, err = updatesToProperties(schema, data.Updates); err != nil {
+			return &tabularpb.UpdateRecordsResponse{Success: false, Error: invalidRequest(err.Error())}, nil
+		}
+	}
+
+	ids, err := p.selectedIDs(ctx, c, schema, dbID, data.GetSelection().GetRecords())
+	if err != nil {
+		return &tabularpb.UpdateRecordsResponse{Success: false, Error: selectionError("UPDATE_FAILED", err)}, nil
+	}
+	if len(data.ReplacementRecords) > 0 && len(data.ReplacementRecords) != len(ids) {
+		return &tabularpb.UpdateRecordsResponse{
+			Success: false,
+			Error:   invalidRequest(fmt.Sprintf("%d replacement records for %d selected records", len(data.ReplacementRecords), len(ids))),
+		}, nil
+	}
+
+	payloads := make([]map[string]any, len(ids))
+	for i := range ids {
+		if shared != nil {
+			payloads[i] = shared
+			continue
+		}
+		if payloads[i], err = recordToProperties(schema, data.ReplacementRecords[i]); err != nil {
+			return &tabularpb.UpdateRecordsResponse{Success: false, Error: invalidRequest(fmt.Sprintf("replacement record %d: %v", i, err))}, nil
+		}
+	}
+
+	result := &tabularpb.UpdateRecordsResult{RecordsMatched: int32(len(ids))}
+	for i, id := range ids {
+		if err := c.updatePage(ctx, id, payloads[i]); err != nil {
+			p.logger.ErrorContext(ctx, "Failed to update Notion page", "error", err, "page_id", id)
+			return &tabularpb.UpdateRecordsResponse{
+				Success: false,
+				Error:   failure("UPDATE_FAILED", fmt.Errorf("page %s (after %d updated): %w", id, result.RecordsUpdated, err)),
+			}, nil
+		}
+		result.RecordsUpdated++
+	}
+
+	p.logger.InfoContext(ctx, "Updated records in Notion", "database_id", dbID, "count", result.RecordsUpdated)
+
+	return &tabularpb.UpdateRecordsResponse{
+		Success: true,
+		Data:    []*tabularpb.UpdateRecordsResult{result},
+	}, nil
+}
+
+// DeleteRecords archives the selected pages, which moves them to the Notion
+// trash. As with updates, the selection must name record IDs or carry a
+// filter. ShiftRemaining has no meaning for Notion and is ignored.
+func (p *NotionProvider) DeleteRecords(ctx context.Context, req *tabularpb.DeleteRecordsRequest) (reply *tabularpb.DeleteRecordsResponse, opErr error) {
+	data := req.GetData()
+	dbID := databaseID(data.GetSourceId(), data.GetSelection().GetTable())
+	ctx, span := startCall(ctx, "delete", dbID)
+	defer func(start time.Time) {
+		observe(span, "delete", dbID, start, reply.GetSuccess(), opErr)
+	}(time.Now())
+
+	c := p.activeClient()
+	if c == nil {
+		return &tabularpb.DeleteRecordsResponse{Success: false, Error: notInitialized()}, nil
+	}
+	if data == nil || dbID == "" {
+		return &tabularpb.DeleteRecordsResponse{Success: false, Error: invalidRequest("source_id (the Notion database ID) is required")}, nil
+	}
+
+	schema, err := p.schema(ctx, c, dbID)
+	if err != nil {
+		return &tabularpb.DeleteRecordsResponse{Success: false, Error: failure("DELETE_FAILED", err)}, nil
+	}
+	ids, err := p.selectedIDs(ctx, c, schema, dbID, data.GetSelection().GetRecords())
+	if err != nil {
+		return &tabularpb.DeleteRecordsResponse{Success: false, Error: selectionError("DELETE_FAILED", err)}, nil
+	}
+
+	result := &tabularpb.DeleteRecordsResult{}
+	for _, id := range ids {
+		if err := c.archivePage(ctx, id); err != nil {
+			p.logger.ErrorContext(ctx, "Failed to archive Notion page", "error", err, "page_id", id)
+			return &tabularpb.DeleteRecordsResponse{
+				Success: false,
+				Error:   failure("DELETE_FAILED", fmt.Errorf("page %s (after %d deleted): %w", id, result.RecordsDeleted, err)),
+			}, nil
+		}
+		result.RecordsDeleted++
+	}
+
+	p.logger.InfoContext(ctx, "Archived records in Notion", "database_id", dbID, "count", result.RecordsDeleted)
+
+	return &tabularpb.DeleteRecordsResponse{
+		Success: true,
+		Data:    []*tabularpb.DeleteRecordsResult{result},
+	}, nil
+}
+
+// =============================================================================
+// Query Operations
+// =============================================================================
+
+// SearchRecords runs a filtered query against a Notion database. The filter
+// is translated to a Notion filter; operators Notion cannot express (regex
+// matching, for one) are rejected rather than applied after the fact.
+func (p *NotionProvider) SearchRecords(ctx context.Context, req *tabularpb.SearchRecordsRequest) (reply *tabularpb.SearchRecordsResponse, opErr error) {
+	data := req.GetData()
+	dbID := databaseID(data.GetSourceId(), data.GetTable())
+	ctx, span := startCall(ctx, "search", dbID)
+	defer func(start time.Time) {
+		observe(span, "search", dbID, start, reply.GetSuccess(), opErr)
+	}(time.Now())
+
+	c := p.activeClient()
+	if c == nil {
+		return &tabularpb.SearchRecordsResponse{Success: false, Error: notInitialized()}, nil
+	}
+	if data == nil || dbID == "" {
+		return &tabularpb.SearchRecordsResponse{Success: false, Error: invalidRequest("source_id (the Notion database ID) is required")}, nil
+	}
+
+	schema, err := p.schema(ctx, c, dbID)
+	if err != nil {
+		return &tabularpb.SearchRecordsResponse{Success: false, Error: failure("SEARCH_FAILED", err)}, nil
+	}
+
+	offset := int(data.Offset)
+	records, hasMore, err := p.queryRecords(ctx, c, schema, dbID, data.Filter, data.SortBy, offset, int(data.Limit))
+	if err != nil {
+		return &tabularpb.SearchRecordsResponse{Success: false, Error: selectionError("SEARCH_FAILED", err)}, nil
+	}
+	selectFields(schema, records, data.Fields)
+
+	p.logger.InfoContext(ctx, "Searched records in Notion", "database_id", dbID, "count", len(records))
+
+	return &tabularpb.SearchRecordsResponse{
+		Success: true,
+		Data: []*tabularpb.SearchRecordsResult{
+			{
+				Records:    records,
+				TotalCount: int64(offset + len(records)),
+				HasMore:    hasMore,
+			},
+		},
+	}, nil
+}
+
+// =============================================================================
+// Schema Operations
+// =============================================================================
+
+// GetSchema returns a database's properties as fields
+func (p *NotionProvider) GetSchema(ctx context.Context, req *tabularpb.GetSchemaRequest) (*tabularpb.GetSchemaResponse, error) {
+	c := p.activeClient()
+	if c == nil {
+		return &tabularpb.GetSchemaResponse{Success: false, Error: notInitialized()}, nil
+	}
+	data := req.GetData()
+	dbID := databaseID(data.GetSourceId(), data.GetTable())
+	if dbID == "" {
+		return &tabularpb.GetSchemaResponse{Success: false, Error: invalidRequest("source_id (the Notion database ID) is required")}, nil
+	}
+
+	p.invalidateSchema(dbID)
+	schema, err := p.schema(ctx, c, dbID)
+	if err != nil {
+		return &tabularpb.GetSchemaResponse{Success: false, Error: failure("SCHEMA_FAILED", err)}, nil
+	}
+
+	return &tabularpb.GetSchemaResponse{
+		Success: true,
+		Data: []*tabularpb.GetSchemaResult{
+			{
+				Source:      sourceFromDatabase(schema.db, false),
+				TableSchema: schema.tableSchema(),
+			},
+		},
+	}, nil
+}
+
+// GetSource returns a database as a source with itself as the only table.
+// Without a source ID it returns one source per shared database.
+func (p *NotionProvider) GetSource(ctx context.Context, req *tabularpb.GetSourceRequest) (*tabularpb.GetSourceResponse, error) {
+	c := p.activeClient()
+	if c == nil {
+		return &tabularpb.GetSourceResponse{Success: false, Error: notInitialized()}, nil
+	}
+	data := req.GetData()
+
+	databases, err := p.databases(ctx, c, data.GetSourceId())
+	if err != nil {
+		return &tabularpb.GetSourceResponse{Success: false, Error: failure("SOURCE_FAILED", err)}, nil
+	}
+
+	sources := make([]*tabularpb.Source, len(databases))
+	for i, db := range databases {
+		sources[i] = sourceFromDatabase(db, data.GetIncludeTables())
+	}
+	return &tabularpb.GetSourceResponse{Success: true, Data: sources}, nil
+}
+
+// ListTables returns the database named by the source ID, or every database
+// shared with the integration when none is given.
+func (p *NotionProvider) ListTables(ctx context.Context, req *tabularpb.ListTablesRequest) (*tabularpb.ListTablesResponse, error) {
+	c := p.activeClient()
+	if c == nil {
+		return &tabularpb.ListTablesResponse{Success: false, Error: notInitialized()}, nil
+	}
+
+	databases, err := p.databases(ctx, c, req.GetData().GetSourceId())
+	if err != nil {
+		return &tabularpb.ListTablesResponse{Success: false, Error: failure("LIST_FAILED", err)}, nil
+	}
+
+	tables := make([]*tabularpb.Table, len(databases))
+	for i, db := range databases {
+		tables[i] = tableFromDatabase(db, int32(i))
+	}
+	return &tabularpb.ListTablesResponse{Success: true, Data: tables}, nil
+}
+
+// =============================================================================
+// Batch Operations
+// =============================================================================
+
+// BatchExecute runs the operations one after another. Notion has no
+// transactions, so transactional batches are refused.
+func (p *NotionProvider) BatchExecute(ctx context.Context, req *tabularpb.BatchExecuteRequest) (*tabularpb.BatchExecuteResponse, error) {
+	if !p.IsEnabled() {
+		return &tabularpb.BatchExecuteResponse{Success: false, Error: notInitialized()}, nil
+	}
+	data := req.GetData()
+	if data == nil {
+		return &tabularpb.BatchExecuteResponse{Success: false, Error: invalidRequest("Request data is required")}, nil
+	}
+	if data.Transactional {
+		return &tabularpb.BatchExecuteResponse{Success: false, Error: invalidRequest("Notion does not support transactional batches")}, nil
+	}
+
+	result := &tabularpb.BatchExecuteResult{}
+	for _, op := range data.Operations {
+		var opErr *commonpb.Error
+		switch opData := op.Operation.(type) {
+		case *tabularpb.BatchOperation_Write:
+			resp, _ := p.WriteRecords(ctx, &tabularpb.WriteRecordsRequest{Data: opData.Write})
+			opErr = resp.GetError()
+		case *tabularpb.BatchOperation_Update:
+			resp, _ := p.UpdateRecords(ctx, &tabularpb.UpdateRecordsRequest{Data: opData.Update})
+			opErr = resp.GetError()
+		case *tabularpb.BatchOperation_Delete:
+			resp, _ := p.DeleteRecords(ctx, &tabularpb.DeleteRecordsRequest{Data: opData.Delete})
+			opErr = resp.GetError()
+		default:
+			opErr = invalidRequest("unknown operation type")
+		}
+
+		result.Results = append(result.Results, &tabularpb.BatchOperationResult{
+			OperationId: op.OperationId,
+			Success:     opErr == nil,
+			Error:       opErr,
+		})
+		if opErr == nil {
+			result.SuccessCount++
+			continue
+		}
+		result.FailureCount++
+		if data.FailFast {
+			break
+		}
+	}
+
+	p.logger.InfoContext(ctx, "Batch executed operations",
+		"source_id", data.SourceId,
+		"total", len(data.Operations),
+		"success", result.SuccessCount,
+		"failures", result.FailureCount,
+	)
+
+	return &tabularpb.BatchExecuteResponse{
+		Success: result.FailureCount == 0,
+		Data:    []*tabularpb.BatchExecuteResult{result},
+	}, nil
+}
+
+// =============================================================================
+// Health & Capabilities
+// =============================================================================
+
+// CheckHealth performs a detailed health check. A deep check verifies the
+// integration secret against the Notion API.
+func (p *NotionProvider) CheckHealth(ctx context.Context, req *tabularpb.CheckHealthRequest) (*tabularpb.CheckHealthResponse, error) {
+	err := p.IsHealthy(ctx)
+	if err == nil && req.GetData().GetDeepCheck() {
+		err = p.activeClient().me(ctx)
+	}
+	if err != nil {
+		return &tabularpb.CheckHealthResponse{
+			Success: true,
+			Data: []*tabularpb.HealthStatus{
+				{
+					IsHealthy: false,
+					Message:   err.Error(),
+					Details: map[string]string{
+						"provider": "notion",
+						"status":   "error",
+					},
+				},
+			},
+		}, nil
+	}
+
+	return &tabularpb.CheckHealthResponse{
+		Success: true,
+		Data: []*tabularpb.HealthStatus{
+			{
+				IsHealthy: true,
+				Message:   "Notion tabular provider is healthy",
+				Details: map[string]string{
+					"provider": "notion",
+					"status":   "operational",
+				},
+			},
+		},
+	}, nil
+}
+
+// GetCapabilitiesInfo returns detailed capability information
+func (p *NotionProvider) GetCapabilitiesInfo(ctx context.Context, req *tabularpb.GetCapabilitiesRequest) (*tabularpb.GetCapabilitiesResponse, error) {
+	return &tabularpb.GetCapabilitiesResponse{
+		Success: true,
+		Data: []*tabularpb.ProviderCapabilities{
+			{
+				ProviderId:           "notion",
+				ProviderType:         tabularpb.TabularProviderType_TABULAR_PROVIDER_TYPE_REST_API,
+				Capabilities:         p.GetCapabilities(),
+				MaxRecordsPerRequest: maxPageSize, // Notion query page size; reads page through
+			},
+		},
+	}, nil
+}
+
+// =============================================================================
+// Helper Methods
+// =============================================================================
+
+// activeClient returns the API client, or nil when the provider is not
+// enabled.
+func (p *NotionProvider) activeClient() *client {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if !p.enabled {
+		return nil
+	}
+	return p.client
+}
+
+// schema returns a database's schema, fetching it when not cached within
+// schemaTTL.
+func (p *NotionProvider) schema(ctx context.Context, c *client, dbID string) (*databaseSchema, error) {
+	p.mu.RLock()
+	cached, ok := p.schemas[dbID]
+	p.mu.RUnlock()
+	if ok && time.Since(cached.fetchedAt) < schemaTTL {
+		return cached.schema, nil
+	}
+
+	db, err := c.getDatabase(ctx, dbID)
+	if err != nil {
+		return nil, err
+	}
+	schema := newDatabaseSchema(db)
+
+	p.mu.Lock()
+	p.schemas[dbID] = cachedSchema{schema: schema, fetchedAt: time.Now()}
+	p.mu.Unlock()
+	return schema, nil
+}
+
+func (p *NotionProvider) invalidateSchema(dbID string) {
+	p.mu.Lock()
+	delete(p.schemas, dbID)
+	p.mu.Unlock()
+}
+
+// databases returns the database with the given ID, or every shared
+// database when the ID is empty.
+func (p *NotionProvider) databases(ctx context.Context, c *client, dbID string) ([]*database, error) {
+	if dbID == "" {
+		return c.searchDatabases(ctx)
+	}
+	schema, err := p.schema(ctx, c, dbID)
+	if err != nil {
+		return nil, err
+	}
+	return []*database{schema.db}, nil
+}
+
+// queryRecords translates the filter and sorts and queries the database.
+func (p *NotionProvider) queryRecords(ctx context.Context, c *client, schema *databaseSchema, dbID string, filter *tabularpb.FilterGroup, sortBy []*tabularpb.SortSpec, offset, limit int) ([]*tabularpb.Record, bool, error) {
+	notionFilter, err := translateFilterGroup(schema, filter)
+	if err != nil {
+		return nil, false, &translationError{err}
+	}
+	sorts, err := translateSorts(schema, sortBy)
+	if err != nil {
+		return nil, false, &translationError{err}
+	}
+
+	pages, hasMore, err := c.queryDatabase(ctx, dbID, notionFilter, sorts, offset, limit)
+	if err != nil {
+		return nil, false, err
+	}
+	records := make([]*tabularpb.Record, len(pages))
+	for i, pg := range pages {
+		records[i] = pageToRecord(schema, pg, int64(offset+i))
+	}
+	return records, hasMore, nil
+}
+
+// readPagesByID retrieves pages one by one, skipping archived pages.
+func (p *NotionProvider) readPagesByID(ctx context.Context, c *client, schema *databaseSchema, ids []string) ([]*tabularpb.Record, error) {
+	records := make([]*tabularpb.Record, 0, len(ids))
+	for _, id := range ids {
+		pg, err := c.getPage(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if pg.Archived {
+			continue
+		}
+		records = append(records, pageToRecord(schema, pg, int64(len(records))))
+	}
+	return records, nil
+}
+
+// selectedIDs resolves a record selection to page IDs for an update or
+// delete.
+func (p *NotionProvider) selectedIDs(ctx context.Context, c *client, schema *databaseSchema, dbID string, selection *tabularpb.RecordSelection) ([]string, error) {
+	if ids := selection.GetRecordIds(); len(ids) > 0 {
+		return ids, nil
+	}
+	if len(selection.GetFilter().GetFilters()) == 0 && len(selection.GetFilter().GetGroups()) == 0 {
+		return nil, &translationError{fmt.Errorf("selection needs record_ids or a filter")}
+	}
+	records, _, err := p.queryRecords(ctx, c, schema, dbID, selection.GetFilter(), nil, int(selection.GetOffset()), int(selection.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.Id
+	}
+	return ids, nil
+}
+
+// updatesToProperties builds one properties payload from field updates.
+func updatesToProperties(schema *databaseSchema, updates []*tabularpb.FieldUpdate) (map[string]any, error) {
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("updates or replacement_records are required")
+	}
+	properties := make(map[string]any, len(updates))
+	for _, update := range updates {
+		_, byIndex := update.GetField().(*tabularpb.FieldUpdate_FieldIndex)
+		name, prop, err := schema.resolve(update.GetFieldName(), update.GetFieldIndex(), byIndex)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := propertyPayload(prop, update.GetValue())
+		if err != nil {
+			return nil, err
+		}
+		properties[name] = payload
+	}
+	return properties, nil
+}
+
+// translationError marks a request that cannot be expressed for Notion, as
+// opposed to a failed API call.
+type translationError struct{ err error }
+
+func (e *translationError) Error() string { return e.err.Error() }
+func (e *translationError) Unwrap() error { return e.err }
+
+// selectionError reports translation problems as invalid requests and
+// anything else under code.
+func selectionError(code string, err error) *commonpb.Error {
+	if _, ok := err.(*translationError); ok {
+		return &commonpb.Error{Code: "INVALID_FILTER", Message: err.Error()}
+	}
+	return failure(code, err)
+}
+
+func sourceFromDatabase(db *database, includeTables bool) *tabularpb.Source {
+	source := &tabularpb.Source{
+		Id:           db.ID,
+		Name:         plainText(db.Title),
+		Url:          db.URL,
+		ProviderType: tabularpb.TabularProviderType_TABULAR_PROVIDER_TYPE_REST_API,
+		Metadata:     map[string]string{"provider": "notion"},
+	}
+	if !db.CreatedTime.IsZero() {
+		source.CreatedAt = timestamppb.New(db.CreatedTime)
+	}
+	if !db.LastEditedTime.IsZero() {
+		source.UpdatedAt = timestamppb.New(db.LastEditedTime)
+	}
+	if includeTables {
+		source.Tables = []*tabularpb.Table{tableFromDatabase(db, 0)}
+	}
+	return source
+}
+
+func tableFromDatabase(db *database, position int32) *tabularpb.Table {
+	return &tabularpb.Table{
+		Id:       db.ID,
+		Name:     plainText(db.Title),
+		Schema:   newDatabaseSchema(db).tableSchema(),
+		Position: position,
+		Hidden:   db.Archived,
+		Metadata: map[string]string{"url": db.URL},
+	}
+}
+
+// databaseID picks the database a request addresses: the source ID, or the
+// table name when no source ID is given.
+func databaseID(sourceID, table string) string {
+	if sourceID != "" {
+		return sourceID
+	}
+	return table
+}
+
+func notInitialized() *commonpb.Error {
+	return &commonpb.Error{Code: "NOT_INITIALIZED", Message: "Notion tabular provider is not initialized"}
+}
+
+func invalidRequest(message string) *commonpb.Error {
+	return &commonpb.Error{Code: "INVALID_REQUEST", Message: message}
+}
+
+func failure(code string, err error) *commonpb.Error {
+	return &commonpb.Error{Code: code, Message: err.Error()}
+}
@@ -0,0 +1,129 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	tabularpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/tabular"
+)
+
+// fakeNotion serves one database of five pages, two per query page, and
+// rate-limits the first query.
+func fakeNotion(t *testing.T) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+	var queries []map[string]any
+	var limited atomic.Bool
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /databases/db1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"db1","title":[{"plain_text":"Deals"}],"properties":{
+			"Name":{"id":"title","name":"Name","type":"title"},
+			"Amount":{"id":"a","name":"Amount","type":"number"}}}`)
+	})
+	mux.HandleFunc("POST /databases/db1/query", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Notion-Version") != apiVersion || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if !limited.Swap(true) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"status":429,"code":"rate_limited","message":"slow down"}`)
+			return
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		queries = append(queries, body)
+
+		start := 0
+		if cursor, ok := body["start_cursor"].(string); ok {
+			fmt.Sscanf(cursor, "c%d", &start)
+		}
+		var results []string
+		for i := start; i < min(start+2, 5); i++ {
+			results = append(results, fmt.Sprintf(`{"id":"p%d","properties":{"Name":{"type":"title","title":[{"plain_text":"Deal %d"}]},"Amount":{"type":"number","number":%d}}}`, i, i, i*10))
+		}
+		next := "null"
+		if start+2 < 5 {
+			next = fmt.Sprintf(`"c%d"`, start+2)
+		}
+		fmt.Fprintf(w, `{"results":[%s],"has_more":%t,"next_cursor":%s}`, strings.Join(results, ","), start+2 < 5, next)
+	})
+	mux.HandleFunc("PATCH /pages/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	})
+	return httptest.NewServer(mux), &queries
+}
+
+func newTestProvider(t *testing.T, url string) *NotionProvider {
+	t.Helper()
+	p := NewNotionProvider()
+	err := p.Initialize(&tabularpb.TabularProviderConfig{
+		Enabled:    true,
+		MaxRetries: 1,
+		Auth:       &tabularpb.TabularProviderConfig_ApiKeyAuth{ApiKeyAuth: &tabularpb.ApiKeyAuth{ApiKey: "secret"}},
+		Settings:   map[string]string{"api_url": url},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestReadRecords_PagesWithOffsetAndLimit(t *testing.T) {
+	server, queries := fakeNotion(t)
+	defer server.Close()
+	p := newTestProvider(t, server.URL)
+
+	resp, err := p.ReadRecords(context.Background(), &tabularpb.ReadRecordsRequest{Data: &tabularpb.ReadRecordsData{
+		SourceId: "db1",
+		Selection: &tabularpb.Selection{Records: &tabularpb.RecordSelection{
+			Offset: 1,
+			Limit:  2,
+			Filter: &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{
+				byName("Amount", tabularpb.FilterOperator_FILTER_OPERATOR_GREATER_THAN, str("-1")),
+			}},
+		}},
+	}})
+	if err != nil || !resp.Success {
+		t.Fatalf("read = %v, %v", resp, err)
+	}
+	result := resp.Data[0]
+	if len(result.Records) != 2 || result.Records[0].Id != "p1" || result.Records[1].Id != "p2" {
+		t.Fatalf("records = %v, want p1 and p2", result.Records)
+	}
+	if !result.HasMore || result.NextOffset != 3 || result.Records[1].Index != 2 {
+		t.Errorf("result = has_more %t, next %d, index %d", result.HasMore, result.NextOffset, result.Records[1].Index)
+	}
+	if len(*queries) != 2 || (*queries)[0]["filter"] == nil || (*queries)[1]["start_cursor"] != "c2" {
+		t.Errorf("queries = %v, want the filter sent and the second page fetched by cursor", *queries)
+	}
+}
+
+func TestUpdateRecords_RequiresSelection(t *testing.T) {
+	server, _ := fakeNotion(t)
+	defer server.Close()
+	p := newTestProvider(t, server.URL)
+
+	resp, _ := p.UpdateRecords(context.Background(), &tabularpb.UpdateRecordsRequest{Data: &tabularpb.UpdateRecordsData{
+		SourceId: "db1",
+		Updates:  []*tabularpb.FieldUpdate{{Field: &tabularpb.FieldUpdate_FieldName{FieldName: "Amount"}, Value: str("5")}},
+	}})
+	if resp.Success || resp.GetError().GetCode() != "INVALID_FILTER" {
+		t.Fatalf("update without a selection = %v, want it refused", resp)
+	}
+
+	resp, _ = p.UpdateRecords(context.Background(), &tabularpb.UpdateRecordsRequest{Data: &tabularpb.UpdateRecordsData{
+		SourceId:  "db1",
+		Selection: &tabularpb.Selection{Records: &tabularpb.RecordSelection{RecordIds: []string{"p1", "p3"}}},
+		Updates:   []*tabularpb.FieldUpdate{{Field: &tabularpb.FieldUpdate_FieldName{FieldName: "Amount"}, Value: str("5")}},
+	}})
+	if !resp.Success || resp.Data[0].RecordsUpdated != 2 {
+		t.Errorf("update by IDs = %v", resp)
+	}
+}
@@ -0,0 +1,237 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBaseURL = "https://api.notion.com/v1"
+
+	// apiVersion pins the Notion-Version header the request and response
+	// shapes in this package were written against.
+	apiVersion = "2022-06-28"
+
+	// maxPageSize is the most results Notion returns per query call.
+	maxPageSize = 100
+
+	// maxResponseBytes bounds how much of a response body is read.
+	maxResponseBytes = 10 << 20
+)
+
+// apiError is the error object Notion returns with non-2xx responses.
+type apiError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("notion: %s (HTTP %d): %s", e.Code, e.Status, e.Message)
+}
+
+// client is a minimal Notion REST client. Rate-limited calls (HTTP 429) are
+// retried after the Retry-After delay Notion sends, up to retries times.
+type client struct {
+	http    *http.Client
+	baseURL string
+	token   string
+	retries int
+}
+
+// do sends a JSON request and decodes the response into out when non-nil.
+func (c *client) do(ctx context.Context, method, path string, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("notion: encode %s %s: %w", method, path, err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("notion: %s %s: %w", method, path, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Notion-Version", apiVersion)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return fmt.Errorf("notion: %s %s: %w", method, path, err)
+		}
+		raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("notion: read %s %s: %w", method, path, err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.retries {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retryAfter(resp.Header.Get("Retry-After"))):
+			}
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			apiErr := &apiError{Status: resp.StatusCode}
+			if json.Unmarshal(raw, apiErr) != nil || apiErr.Message == "" {
+				apiErr.Message = strings.TrimSpace(string(raw))
+			}
+			apiErr.Status = resp.StatusCode
+			return apiErr
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.Unmarshal(raw, out); err != nil {
+			return fmt.Errorf("notion: decode %s %s: %w", method, path, err)
+		}
+		return nil
+	}
+}
+
+// retryAfter parses a Retry-After header in seconds, defaulting to one
+// second.
+func retryAfter(header string) time.Duration {
+	if seconds, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return time.Second
+}
+
+// getDatabase retrieves a database and its property schema.
+func (c *client) getDatabase(ctx context.Context, id string) (*database, error) {
+	var db database
+	if err := c.do(ctx, http.MethodGet, "/databases/"+id, nil, &db); err != nil {
+		return nil, err
+	}
+	return &db, nil
+}
+
+// getPage retrieves a single page.
+func (c *client) getPage(ctx context.Context, id string) (*page, error) {
+	var pg page
+	if err := c.do(ctx, http.MethodGet, "/pages/"+id, nil, &pg); err != nil {
+		return nil, err
+	}
+	return &pg, nil
+}
+
+// queryDatabase pages through a database query, skipping offset matches and
+// collecting up to limit (all when limit is 0). hasMore reports whether
+// further matches exist past the ones returned.
+func (c *client) queryDatabase(ctx context.Context, id string, filter map[string]any, sorts []map[string]any, offset, limit int) (pages []*page, hasMore bool, err error) {
+	skipped := 0
+	cursor := ""
+	for {
+		body := map[string]any{"page_size": maxPageSize}
+		if filter != nil {
+			body["filter"] = filter
+		}
+		if len(sorts) > 0 {
+			body["sorts"] = sorts
+		}
+		if cursor != "" {
+			body["start_cursor"] = cursor
+		}
+
+		var resp queryResponse
+		if err := c.do(ctx, http.MethodPost, "/databases/"+id+"/query", body, &resp); err != nil {
+			return nil, false, err
+		}
+		for _, raw := range resp.Results {
+			if skipped < offset {
+				skipped++
+				continue
+			}
+			if limit > 0 && len(pages) == limit {
+				return pages, true, nil
+			}
+			var pg page
+			if err := json.Unmarshal(raw, &pg); err != nil {
+				return nil, false, fmt.Errorf("notion: decode page: %w", err)
+			}
+			pages = append(pages, &pg)
+		}
+
+		if !resp.HasMore || resp.NextCursor == nil {
+			return pages, false, nil
+		}
+		if limit > 0 && len(pages) == limit {
+			return pages, true, nil
+		}
+		cursor = *resp.NextCursor
+	}
+}
+
+// searchDatabases lists every database shared with the integration.
+func (c *client) searchDatabases(ctx context.Context) ([]*database, error) {
+	var databases []*database
+	cursor := ""
+	for {
+		body := map[string]any{
+			"page_size": maxPageSize,
+			"filter":    map[string]any{"property": "object", "value": "database"},
+		}
+		if cursor != "" {
+			body["start_cursor"] = cursor
+		}
+
+		var resp queryResponse
+		if err := c.do(ctx, http.MethodPost, "/search", body, &resp); err != nil {
+			return nil, err
+		}
+		for _, raw := range resp.Results {
+			var db database
+			if err := json.Unmarshal(raw, &db); err != nil {
+				return nil, fmt.Errorf("notion: decode database: %w", err)
+			}
+			databases = append(databases, &db)
+		}
+		if !resp.HasMore || resp.NextCursor == nil {
+			return databases, nil
+		}
+		cursor = *resp.NextCursor
+	}
+}
+
+// createPage adds a row to a database.
+func (c *client) createPage(ctx context.Context, databaseID string, properties map[string]any) (*page, error) {
+	body := map[string]any{
+		"parent":     map[string]any{"database_id": databaseID},
+		"properties": properties,
+	}
+	var pg page
+	if err := c.do(ctx, http.MethodPost, "/pages", body, &pg); err != nil {
+		return nil, err
+	}
+	return &pg, nil
+}
+
+// updatePage patches a page's properties.
+func (c *client) updatePage(ctx context.Context, id string, properties map[string]any) error {
+	return c.do(ctx, http.MethodPatch, "/pages/"+id, map[string]any{"properties": properties}, nil)
+}
+
+// archivePage moves a page to the trash. Notion has no hard delete through
+// the API; archived pages can be restored from the workspace for 30 days.
+func (c *client) archivePage(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPatch, "/pages/"+id, map[string]any{"archived": true}, nil)
+}
+
+// me returns the integration's bot user, which doubles as a credentials
+// check.
+func (c *client) me(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/users/me", nil, nil)
+}
@@ -0,0 +1,937 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	tabularpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/tabular"
+)
+
+// =============================================================================
+// Notion API Objects
+// =============================================================================
+
+// database is a Notion database as returned by GET /v1/databases/{id}.
+type database struct {
+	ID             string                    `json:"id"`
+	Title          []richText                `json:"title"`
+	Description    []richText                `json:"description"`
+	URL            string                    `json:"url"`
+	Properties     map[string]propertySchema `json:"properties"`
+	CreatedTime    time.Time                 `json:"created_time"`
+	LastEditedTime time.Time                 `json:"last_edited_time"`
+	Archived       bool                      `json:"archived"`
+}
+
+// propertySchema is one column of a database.
+type propertySchema struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Select      *optionList `json:"select,omitempty"`
+	Status      *optionList `json:"status,omitempty"`
+	MultiSelect *optionList `json:"multi_select,omitempty"`
+}
+
+type optionList struct {
+	Options []option `json:"options"`
+}
+
+type option struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+type richText struct {
+	PlainText string `json:"plain_text"`
+}
+
+// page is a database row. Properties stay raw until converted so that types
+// this adapter does not model (rollups) can be passed on as JSON.
+type page struct {
+	ID             string                     `json:"id"`
+	URL            string                     `json:"url"`
+	CreatedTime    time.Time                  `json:"created_time"`
+	LastEditedTime time.Time                  `json:"last_edited_time"`
+	Archived       bool                       `json:"archived"`
+	Properties     map[string]json.RawMessage `json:"properties"`
+}
+
+// queryResponse is one page of results from a database query or a search.
+type queryResponse struct {
+	Results    []json.RawMessage `json:"results"`
+	HasMore    bool              `json:"has_more"`
+	NextCursor *string           `json:"next_cursor"`
+}
+
+// propertyValue is the value of one property on a page. Only the member
+// named by Type is set.
+type propertyValue struct {
+	Type           string      `json:"type"`
+	Title          []richText  `json:"title"`
+	RichText       []richText  `json:"rich_text"`
+	Number         *float64    `json:"number"`
+	Checkbox       bool        `json:"checkbox"`
+	Select         *option     `json:"select"`
+	Status         *option     `json:"status"`
+	MultiSelect    []option    `json:"multi_select"`
+	Date           *dateValue  `json:"date"`
+	URL            *string     `json:"url"`
+	Email          *string     `json:"email"`
+	PhoneNumber    *string     `json:"phone_number"`
+	People         []user      `json:"people"`
+	Relation       []reference `json:"relation"`
+	Files          []file      `json:"files"`
+	CreatedTime    string      `json:"created_time"`
+	LastEditedTime string      `json:"last_edited_time"`
+	CreatedBy      *user       `json:"created_by"`
+	LastEditedBy   *user       `json:"last_edited_by"`
+	Formula        *formula    `json:"formula"`
+	UniqueID       *uniqueID   `json:"unique_id"`
+}
+
+type dateValue struct {
+	Start string  `json:"start"`
+	End   *string `json:"end"`
+}
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type reference struct {
+	ID string `json:"id"`
+}
+
+type file struct {
+	Name string `json:"name"`
+}
+
+type formula struct {
+	Type    string     `json:"type"`
+	String  *string    `json:"string"`
+	Number  *float64   `json:"number"`
+	Boolean *bool      `json:"boolean"`
+	Date    *dateValue `json:"date"`
+}
+
+type uniqueID struct {
+	Prefix *string  `json:"prefix"`
+	Number *float64 `json:"number"`
+}
+
+// =============================================================================
+// Schema
+// =============================================================================
+
+// databaseSchema is a database's properties in field order: the title
+// property first, then the rest by name. Notion itself keeps no column
+// order in the API, so this order is what field indices refer to.
+type databaseSchema struct {
+	db    *database
+	names []string
+}
+
+func newDatabaseSchema(db *database) *databaseSchema {
+	names := make([]string, 0, len(db.Properties))
+	for name := range db.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ti := db.Properties[names[i]].Type == "title"
+		tj := db.Properties[names[j]].Type == "title"
+		if ti != tj {
+			return ti
+		}
+		return names[i] < names[j]
+	})
+	return &databaseSchema{db: db, names: names}
+}
+
+func (s *databaseSchema) property(name string) (propertySchema, bool) {
+	prop, ok := s.db.Properties[name]
+	return prop, ok
+}
+
+// resolve returns the property named by name or, when name is empty, the
+// one at index.
+func (s *databaseSchema) resolve(name string, index int32, byIndex bool) (string, propertySchema, error) {
+	if byIndex {
+		if index < 0 || int(index) >= len(s.names) {
+			return "", propertySchema{}, fmt.Errorf("field index %d is out of range (%d properties)", index, len(s.names))
+		}
+		name = s.names[index]
+	}
+	prop, ok := s.property(name)
+	if !ok {
+		return "", propertySchema{}, fmt.Errorf("database has no property %q", name)
+	}
+	return name, prop, nil
+}
+
+// tableSchema describes the database as a TableSchema.
+func (s *databaseSchema) tableSchema() *tabularpb.TableSchema {
+	fields := make([]*tabularpb.Field, 0, len(s.names))
+	for i, name := range s.names {
+		prop := s.db.Properties[name]
+		field := &tabularpb.Field{
+			Index:     int32(i),
+			Name:      name,
+			FieldType: schemaFieldType(prop.Type),
+			Required:  prop.Type == "title",
+			Metadata: map[string]string{
+				"notion_type": prop.Type,
+				"notion_id":   prop.ID,
+			},
+		}
+		if options := prop.options(); len(options) > 0 {
+			field.Constraints = &tabularpb.FieldConstraints{AllowedValues: options}
+		}
+		fields = append(fields, field)
+	}
+	return &tabularpb.TableSchema{
+		Id:                s.db.ID,
+		Name:              plainText(s.db.Title),
+		Description:       plainText(s.db.Description),
+		Fields:            fields,
+		PrimaryKeyIndices: []int32{0},
+		Metadata:          map[string]string{"url": s.db.URL},
+	}
+}
+
+func (p propertySchema) options() []string {
+	var list *optionList
+	switch p.Type {
+	case "select":
+		list = p.Select
+	case "status":
+		list = p.Status
+	case "multi_select":
+		list = p.MultiSelect
+	}
+	if list == nil {
+		return nil
+	}
+	names := make([]string, len(list.Options))
+	for i, o := range list.Options {
+		names[i] = o.Name
+	}
+	return names
+}
+
+// schemaFieldType maps a Notion property type to the field type its values
+// are read as.
+func schemaFieldType(propertyType string) tabularpb.FieldType {
+	switch propertyType {
+	case "number":
+		return tabularpb.FieldType_FIELD_TYPE_FLOAT
+	case "checkbox":
+		return tabularpb.FieldType_FIELD_TYPE_BOOLEAN
+	case "date":
+		return tabularpb.FieldType_FIELD_TYPE_DATE
+	case "created_time", "last_edited_time":
+		return tabularpb.FieldType_FIELD_TYPE_DATETIME
+	case "formula":
+		return tabularpb.FieldType_FIELD_TYPE_FORMULA
+	case "rollup":
+		return tabularpb.FieldType_FIELD_TYPE_JSON
+	default:
+		return tabularpb.FieldType_FIELD_TYPE_STRING
+	}
+}
+
+// =============================================================================
+// Pages to Records
+// =============================================================================
+
+// pageToRecord converts a page to a record. Values follow the schema's field
+// order; NamedValues holds every property the page carries.
+func pageToRecord(schema *databaseSchema, pg *page, index int64) *tabularpb.Record {
+	named := make(map[string]*tabularpb.FieldValue, len(pg.Properties))
+	for name, raw := range pg.Properties {
+		named[name] = propertyToFieldValue(raw)
+	}
+	values := make([]*tabularpb.FieldValue, len(schema.names))
+	for i, name := range schema.names {
+		if fv, ok := named[name]; ok {
+			values[i] = fv
+		} else {
+			values[i] = nullValue()
+		}
+	}
+	record := &tabularpb.Record{
+		Index:       index,
+		Id:          pg.ID,
+		Values:      values,
+		NamedValues: named,
+		Metadata:    map[string]string{"url": pg.URL},
+	}
+	if !pg.CreatedTime.IsZero() {
+		record.CreatedAt = timestamppb.New(pg.CreatedTime)
+	}
+	if !pg.LastEditedTime.IsZero() {
+		record.UpdatedAt = timestamppb.New(pg.LastEditedTime)
+	}
+	return record
+}
+
+// propertyToFieldValue converts one page property. Lists (multi-select,
+// people, relations, files) become comma-separated strings; rollups are
+// passed on as JSON.
+func propertyToFieldValue(raw json.RawMessage) *tabularpb.FieldValue {
+	var v propertyValue
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return &tabularpb.FieldValue{
+			FieldType: tabularpb.FieldType_FIELD_TYPE_ERROR,
+			Value:     &tabularpb.FieldValue_ErrorValue{ErrorValue: err.Error()},
+			RawValue:  string(raw),
+		}
+	}
+
+	switch v.Type {
+	case "title":
+		return stringValue(plainText(v.Title))
+	case "rich_text":
+		return stringValue(plainText(v.RichText))
+	case "number":
+		if v.Number == nil {
+			return nullValue()
+		}
+		return floatValue(*v.Number)
+	case "checkbox":
+		return &tabularpb.FieldValue{
+			FieldType: tabularpb.FieldType_FIELD_TYPE_BOOLEAN,
+			Value:     &tabularpb.FieldValue_BooleanValue{BooleanValue: v.Checkbox},
+		}
+	case "select":
+		return optionValue(v.Select)
+	case "status":
+		return optionValue(v.Status)
+	case "multi_select":
+		names := make([]string, len(v.MultiSelect))
+		for i, o := range v.MultiSelect {
+			names[i] = o.Name
+		}
+		return listValue(names)
+	case "date":
+		return dateFieldValue(v.Date)
+	case "url":
+		return optionalString(v.URL)
+	case "email":
+		return optionalString(v.Email)
+	case "phone_number":
+		return optionalString(v.PhoneNumber)
+	case "people":
+		names := make([]string, len(v.People))
+		for i, u := range v.People {
+			names[i] = u.Name
+		}
+		return listValue(names)
+	case "relation":
+		ids := make([]string, len(v.Relation))
+		for i, r := range v.Relation {
+			ids[i] = r.ID
+		}
+		return listValue(ids)
+	case "files":
+		names := make([]string, len(v.Files))
+		for i, f := range v.Files {
+			names[i] = f.Name
+		}
+		return listValue(names)
+	case "created_time":
+		return datetimeValue(v.CreatedTime)
+	case "last_edited_time":
+		return datetimeValue(v.LastEditedTime)
+	case "created_by":
+		return userValue(v.CreatedBy)
+	case "last_edited_by":
+		return userValue(v.LastEditedBy)
+	case "formula":
+		return formulaValue(v.Formula)
+	case "unique_id":
+		if v.UniqueID == nil || v.UniqueID.Number == nil {
+			return nullValue()
+		}
+		id := strconv.FormatFloat(*v.UniqueID.Number, 'f', -1, 64)
+		if v.UniqueID.Prefix != nil && *v.UniqueID.Prefix != "" {
+			id = *v.UniqueID.Prefix + "-" + id
+		}
+		return stringValue(id)
+	default:
+		return jsonValue(raw)
+	}
+}
+
+func formulaValue(f *formula) *tabularpb.FieldValue {
+	if f == nil {
+		return nullValue()
+	}
+	switch f.Type {
+	case "string":
+		return optionalString(f.String)
+	case "number":
+		if f.Number == nil {
+			return nullValue()
+		}
+		return floatValue(*f.Number)
+	case "boolean":
+		if f.Boolean == nil {
+			return nullValue()
+		}
+		return &tabularpb.FieldValue{
+			FieldType: tabularpb.FieldType_FIELD_TYPE_BOOLEAN,
+			Value:     &tabularpb.FieldValue_BooleanValue{BooleanValue: *f.Boolean},
+		}
+	case "date":
+		return dateFieldValue(f.Date)
+	default:
+		return nullValue()
+	}
+}
+
+// dateFieldValue reads a date property. Dates without a time become DATE
+// values; a range keeps its start as the value and shows both ends.
+func dateFieldValue(d *dateValue) *tabularpb.FieldValue {
+	if d == nil || d.Start == "" {
+		return nullValue()
+	}
+	var fv *tabularpb.FieldValue
+	if len(d.Start) == len("2006-01-02") {
+		fv = &tabularpb.FieldValue{
+			FieldType: tabularpb.FieldType_FIELD_TYPE_DATE,
+			Value:     &tabularpb.FieldValue_DateValue{DateValue: d.Start},
+		}
+	} else {
+		fv = datetimeValue(d.Start)
+	}
+	if d.End != nil && *d.End != "" {
+		fv.DisplayValue = d.Start + " → " + *d.End
+		fv.RawValue = d.Start + "/" + *d.End
+	}
+	return fv
+}
+
+func stringValue(s string) *tabularpb.FieldValue {
+	return &tabularpb.FieldValue{
+		FieldType: tabularpb.FieldType_FIELD_TYPE_STRING,
+		Value:     &tabularpb.FieldValue_StringValue{StringValue: s},
+	}
+}
+
+func optionalString(s *string) *tabularpb.FieldValue {
+	if s == nil {
+		return nullValue()
+	}
+	return stringValue(*s)
+}
+
+func optionValue(o *option) *tabularpb.FieldValue {
+	if o == nil {
+		return nullValue()
+	}
+	return stringValue(o.Name)
+}
+
+func userValue(u *user) *tabularpb.FieldValue {
+	if u == nil {
+		return nullValue()
+	}
+	if u.Name != "" {
+		return stringValue(u.Name)
+	}
+	return stringValue(u.ID)
+}
+
+func listValue(items []string) *tabularpb.FieldValue {
+	if len(items) == 0 {
+		return nullValue()
+	}
+	return stringValue(strings.Join(items, ", "))
+}
+
+func floatValue(f float64) *tabularpb.FieldValue {
+	return &tabularpb.FieldValue{
+		FieldType: tabularpb.FieldType_FIELD_TYPE_FLOAT,
+		Value:     &tabularpb.FieldValue_FloatValue{FloatValue: f},
+	}
+}
+
+func datetimeValue(s string) *tabularpb.FieldValue {
+	if s == "" {
+		return nullValue()
+	}
+	return &tabularpb.FieldValue{
+		FieldType: tabularpb.FieldType_FIELD_TYPE_DATETIME,
+		Value:     &tabularpb.FieldValue_DatetimeValue{DatetimeValue: s},
+	}
+}
+
+func jsonValue(raw json.RawMessage) *tabularpb.FieldValue {
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err == nil {
+		if s, err := structpb.NewStruct(m); err == nil {
+			return &tabularpb.FieldValue{
+				FieldType: tabularpb.FieldType_FIELD_TYPE_JSON,
+				Value:     &tabularpb.FieldValue_JsonValue{JsonValue: s},
+				RawValue:  string(raw),
+			}
+		}
+	}
+	return &tabularpb.FieldValue{FieldType: tabularpb.FieldType_FIELD_TYPE_NULL, RawValue: string(raw)}
+}
+
+func nullValue() *tabularpb.FieldValue {
+	return &tabularpb.FieldValue{FieldType: tabularpb.FieldType_FIELD_TYPE_NULL}
+}
+
+func plainText(parts []richText) string {
+	var b strings.Builder
+	for _, part := range parts {
+		b.WriteString(part.PlainText)
+	}
+	return b.String()
+}
+
+// =============================================================================
+// Records to Page Properties
+// =============================================================================
+
+// maxTextLength is the most characters Notion accepts in one rich text
+// object; longer strings are split across several.
+const maxTextLength = 2000
+
+// recordToProperties builds the properties payload for creating or updating
+// a page. NamedValues take precedence; positional Values are used when a
+// record has no names.
+func recordToProperties(schema *databaseSchema, record *tabularpb.Record) (map[string]any, error) {
+	values := record.GetNamedValues()
+	if len(values) == 0 && len(record.GetValues()) > 0 {
+		if len(record.GetValues()) > len(schema.names) {
+			return nil, fmt.Errorf("record has %d values but the database has %d properties", len(record.GetValues()), len(schema.names))
+		}
+		values = make(map[string]*tabularpb.FieldValue, len(record.GetValues()))
+		for i, fv := range record.GetValues() {
+			values[schema.names[i]] = fv
+		}
+	}
+
+	properties := make(map[string]any, len(values))
+	for name, fv := range values {
+		prop, ok := schema.property(name)
+		if !ok {
+			return nil, fmt.Errorf("database has no property %q", name)
+		}
+		payload, err := propertyPayload(prop, fv)
+		if err != nil {
+			return nil, err
+		}
+		properties[name] = payload
+	}
+	return properties, nil
+}
+
+// propertyPayload converts a value to the JSON Notion expects for the
+// property's type. A null value clears the property.
+func propertyPayload(prop propertySchema, fv *tabularpb.FieldValue) (map[string]any, error) {
+	isNull := fv == nil || fv.Value == nil
+	s := strings.TrimSpace(fieldString(fv))
+
+	switch prop.Type {
+	case "title", "rich_text":
+		return map[string]any{prop.Type: textPayload(fieldString(fv))}, nil
+	case "number":
+		if isNull || s == "" {
+			return map[string]any{"number": nil}, nil
+		}
+		n, err := fieldNumber(fv)
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %w", prop.Name, err)
+		}
+		return map[string]any{"number": n}, nil
+	case "checkbox":
+		if isNull || s == "" {
+			return map[string]any{"checkbox": false}, nil
+		}
+		b, err := fieldBool(fv)
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %w", prop.Name, err)
+		}
+		return map[string]any{"checkbox": b}, nil
+	case "select", "status":
+		if isNull || s == "" {
+			return map[string]any{prop.Type: nil}, nil
+		}
+		return map[string]any{prop.Type: map[string]any{"name": s}}, nil
+	case "multi_select":
+		items := []map[string]any{}
+		for _, name := range splitList(s) {
+			items = append(items, map[string]any{"name": name})
+		}
+		return map[string]any{"multi_select": items}, nil
+	case "relation":
+		items := []map[string]any{}
+		for _, id := range splitList(s) {
+			items = append(items, map[string]any{"id": id})
+		}
+		return map[string]any{"relation": items}, nil
+	case "date":
+		if isNull || s == "" {
+			return map[string]any{"date": nil}, nil
+		}
+		date := map[string]any{"start": s}
+		if start, end, ok := strings.Cut(fv.GetRawValue(), "/"); ok && start == s {
+			date["end"] = end
+		}
+		return map[string]any{"date": date}, nil
+	case "url", "email", "phone_number":
+		if isNull || s == "" {
+			return map[string]any{prop.Type: nil}, nil
+		}
+		return map[string]any{prop.Type: s}, nil
+	default:
+		return nil, fmt.Errorf("property %q (%s) cannot be written", prop.Name, prop.Type)
+	}
+}
+
+func textPayload(s string) []map[string]any {
+	parts := []map[string]any{}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		n := min(len(runes), maxTextLength)
+		parts = append(parts, map[string]any{
+			"type": "text",
+			"text": map[string]any{"content": string(runes[:n])},
+		})
+		runes = runes[n:]
+	}
+	return parts
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// =============================================================================
+// Filters and Sorts
+// =============================================================================
+
+// filterCategory groups property types that accept the same Notion filter
+// conditions.
+func filterCategory(propertyType string) string {
+	switch propertyType {
+	case "title", "rich_text", "url", "email", "phone_number":
+		return "text"
+	case "number", "unique_id":
+		return "number"
+	case "checkbox":
+		return "checkbox"
+	case "select", "status":
+		return "select"
+	case "multi_select", "people", "relation":
+		return "list"
+	case "date", "created_time", "last_edited_time":
+		return "date"
+	default:
+		return ""
+	}
+}
+
+// conditions maps each operator to the Notion condition it becomes, per
+// filter category. Emptiness checks are handled separately.
+var conditions = map[string]map[tabularpb.FilterOperator]string{
+	"text": {
+		tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS:       "equals",
+		tabularpb.FilterOperator_FILTER_OPERATOR_NOT_EQUALS:   "does_not_equal",
+		tabularpb.FilterOperator_FILTER_OPERATOR_CONTAINS:     "contains",
+		tabularpb.FilterOperator_FILTER_OPERATOR_NOT_CONTAINS: "does_not_contain",
+		tabularpb.FilterOperator_FILTER_OPERATOR_STARTS_WITH:  "starts_with",
+		tabularpb.FilterOperator_FILTER_OPERATOR_ENDS_WITH:    "ends_with",
+	},
+	"number": {
+		tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS:                 "equals",
+		tabularpb.FilterOperator_FILTER_OPERATOR_NOT_EQUALS:             "does_not_equal",
+		tabularpb.FilterOperator_FILTER_OPERATOR_GREATER_THAN:           "greater_than",
+		tabularpb.FilterOperator_FILTER_OPERATOR_GREATER_THAN_OR_EQUALS: "greater_than_or_equal_to",
+		tabularpb.FilterOperator_FILTER_OPERATOR_LESS_THAN:              "less_than",
+		tabularpb.FilterOperator_FILTER_OPERATOR_LESS_THAN_OR_EQUALS:    "less_than_or_equal_to",
+	},
+	"checkbox": {
+		tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS:     "equals",
+		tabularpb.FilterOperator_FILTER_OPERATOR_NOT_EQUALS: "does_not_equal",
+	},
+	"select": {
+		tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS:     "equals",
+		tabularpb.FilterOperator_FILTER_OPERATOR_NOT_EQUALS: "does_not_equal",
+	},
+	"list": {
+		tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS:       "contains",
+		tabularpb.FilterOperator_FILTER_OPERATOR_CONTAINS:     "contains",
+		tabularpb.FilterOperator_FILTER_OPERATOR_NOT_EQUALS:   "does_not_contain",
+		tabularpb.FilterOperator_FILTER_OPERATOR_NOT_CONTAINS: "does_not_contain",
+	},
+	"date": {
+		tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS:                 "equals",
+		tabularpb.FilterOperator_FILTER_OPERATOR_GREATER_THAN:           "after",
+		tabularpb.FilterOperator_FILTER_OPERATOR_GREATER_THAN_OR_EQUALS: "on_or_after",
+		tabularpb.FilterOperator_FILTER_OPERATOR_LESS_THAN:              "before",
+		tabularpb.FilterOperator_FILTER_OPERATOR_LESS_THAN_OR_EQUALS:    "on_or_before",
+	},
+}
+
+// translateFilterGroup converts a filter group to a Notion compound filter.
+// It returns nil for an empty group. Notion nests compound filters at most
+// two levels deep and rejects deeper groups.
+func translateFilterGroup(schema *databaseSchema, group *tabularpb.FilterGroup) (map[string]any, error) {
+	if group == nil {
+		return nil, nil
+	}
+	var clauses []map[string]any
+	for _, f := range group.GetFilters() {
+		clause, err := translateFilter(schema, f)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+	for _, g := range group.GetGroups() {
+		clause, err := translateFilterGroup(schema, g)
+		if err != nil {
+			return nil, err
+		}
+		if clause != nil {
+			clauses = append(clauses, clause)
+		}
+	}
+	if group.GetOperator() == tabularpb.LogicalOperator_LOGICAL_OPERATOR_OR {
+		return compound("or", clauses), nil
+	}
+	return compound("and", clauses), nil
+}
+
+// translateFilter converts one filter to a Notion property filter. Notion
+// text matching is always case-insensitive, so CaseSensitive is ignored.
+func translateFilter(schema *databaseSchema, f *tabularpb.Filter) (map[string]any, error) {
+	name, prop, err := schema.resolve(f.GetFieldName(), f.GetFieldIndex(), isIndexFilter(f))
+	if err != nil {
+		return nil, err
+	}
+	category := filterCategory(prop.Type)
+	if category == "" {
+		return nil, fmt.Errorf("property %q (%s) cannot be filtered", name, prop.Type)
+	}
+
+	clause := func(condition string, value any) map[string]any {
+		return map[string]any{"property": name, prop.Type: map[string]any{condition: value}}
+	}
+	valueClause := func(op tabularpb.FilterOperator, fv *tabularpb.FieldValue) (map[string]any, error) {
+		condition, ok := conditions[category][op]
+		if !ok {
+			return nil, fmt.Errorf("operator %s is not supported on %s property %q", op, prop.Type, name)
+		}
+		value, err := filterValue(category, fv)
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %w", name, err)
+		}
+		return clause(condition, value), nil
+	}
+
+	switch op := f.GetOperator(); op {
+	case tabularpb.FilterOperator_FILTER_OPERATOR_IS_NULL, tabularpb.FilterOperator_FILTER_OPERATOR_IS_EMPTY:
+		if category == "checkbox" {
+			return clause("equals", false), nil
+		}
+		return clause("is_empty", true), nil
+	case tabularpb.FilterOperator_FILTER_OPERATOR_IS_NOT_NULL, tabularpb.FilterOperator_FILTER_OPERATOR_IS_NOT_EMPTY:
+		if category == "checkbox" {
+			return clause("equals", true), nil
+		}
+		return clause("is_not_empty", true), nil
+	case tabularpb.FilterOperator_FILTER_OPERATOR_IN, tabularpb.FilterOperator_FILTER_OPERATOR_NOT_IN:
+		each, joiner := tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS, "or"
+		if op == tabularpb.FilterOperator_FILTER_OPERATOR_NOT_IN {
+			each, joiner = tabularpb.FilterOperator_FILTER_OPERATOR_NOT_EQUALS, "and"
+		}
+		if len(f.GetValues()) == 0 {
+			return nil, fmt.Errorf("operator %s on property %q needs values", op, name)
+		}
+		clauses := make([]map[string]any, 0, len(f.GetValues()))
+		for _, fv := range f.GetValues() {
+			c, err := valueClause(each, fv)
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, c)
+		}
+		return compound(joiner, clauses), nil
+	case tabularpb.FilterOperator_FILTER_OPERATOR_BETWEEN:
+		if len(f.GetValues()) != 2 {
+			return nil, fmt.Errorf("operator BETWEEN on property %q needs two values", name)
+		}
+		low, err := valueClause(tabularpb.FilterOperator_FILTER_OPERATOR_GREATER_THAN_OR_EQUALS, f.GetValues()[0])
+		if err != nil {
+			return nil, err
+		}
+		high, err := valueClause(tabularpb.FilterOperator_FILTER_OPERATOR_LESS_THAN_OR_EQUALS, f.GetValues()[1])
+		if err != nil {
+			return nil, err
+		}
+		return compound("and", []map[string]any{low, high}), nil
+	default:
+		return valueClause(op, f.GetValue())
+	}
+}
+
+func isIndexFilter(f *tabularpb.Filter) bool {
+	_, ok := f.GetField().(*tabularpb.Filter_FieldIndex)
+	return ok
+}
+
+// compound joins clauses under "and" or "or", unwrapping a single clause.
+func compound(joiner string, clauses []map[string]any) map[string]any {
+	switch len(clauses) {
+	case 0:
+		return nil
+	case 1:
+		return clauses[0]
+	}
+	return map[string]any{joiner: clauses}
+}
+
+// filterValue converts a filter operand to the JSON type the category
+// expects.
+func filterValue(category string, fv *tabularpb.FieldValue) (any, error) {
+	switch category {
+	case "number":
+		return fieldNumber(fv)
+	case "checkbox":
+		return fieldBool(fv)
+	default:
+		s := fieldString(fv)
+		if s == "" {
+			return nil, fmt.Errorf("filter value is empty")
+		}
+		return s, nil
+	}
+}
+
+// translateSorts converts sort specs to Notion property sorts.
+func translateSorts(schema *databaseSchema, specs []*tabularpb.SortSpec) ([]map[string]any, error) {
+	sorts := make([]map[string]any, 0, len(specs))
+	for _, spec := range specs {
+		_, byIndex := spec.GetField().(*tabularpb.SortSpec_FieldIndex)
+		name, _, err := schema.resolve(spec.GetFieldName(), spec.GetFieldIndex(), byIndex)
+		if err != nil {
+			return nil, err
+		}
+		direction := "ascending"
+		if spec.GetDirection() == tabularpb.SortDirection_SORT_DIRECTION_DESCENDING {
+			direction = "descending"
+		}
+		sorts = append(sorts, map[string]any{"property": name, "direction": direction})
+	}
+	return sorts, nil
+}
+
+// =============================================================================
+// Field Values
+// =============================================================================
+
+// fieldString extracts a string from a FieldValue.
+func fieldString(fv *tabularpb.FieldValue) string {
+	if fv == nil {
+		return ""
+	}
+	switch v := fv.Value.(type) {
+	case *tabularpb.FieldValue_StringValue:
+		return v.StringValue
+	case *tabularpb.FieldValue_IntegerValue:
+		return strconv.FormatInt(v.IntegerValue, 10)
+	case *tabularpb.FieldValue_FloatValue:
+		return strconv.FormatFloat(v.FloatValue, 'f', -1, 64)
+	case *tabularpb.FieldValue_BooleanValue:
+		return strconv.FormatBool(v.BooleanValue)
+	case *tabularpb.FieldValue_DateValue:
+		return v.DateValue
+	case *tabularpb.FieldValue_DatetimeValue:
+		return v.DatetimeValue
+	default:
+		if fv.DisplayValue != "" {
+			return fv.DisplayValue
+		}
+		return fv.RawValue
+	}
+}
+
+func fieldNumber(fv *tabularpb.FieldValue) (float64, error) {
+	switch v := fv.GetValue().(type) {
+	case *tabularpb.FieldValue_IntegerValue:
+		return float64(v.IntegerValue), nil
+	case *tabularpb.FieldValue_FloatValue:
+		return v.FloatValue, nil
+	}
+	s := strings.TrimSpace(fieldString(fv))
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	return n, nil
+}
+
+func fieldBool(fv *tabularpb.FieldValue) (bool, error) {
+	if v, ok := fv.GetValue().(*tabularpb.FieldValue_BooleanValue); ok {
+		return v.BooleanValue, nil
+	}
+	s := strings.TrimSpace(fieldString(fv))
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("%q is not a boolean", s)
+	}
+	return b, nil
+}
+
+// selectFields keeps only the selected fields of each record.
+func selectFields(schema *databaseSchema, records []*tabularpb.Record, sel *tabularpb.FieldSelection) {
+	if sel == nil || (len(sel.GetNames()) == 0 && len(sel.GetIndices()) == 0) {
+		return
+	}
+	listed := make(map[string]bool)
+	for _, name := range sel.GetNames() {
+		listed[name] = true
+	}
+	for _, i := range sel.GetIndices() {
+		if i >= 0 && int(i) < len(schema.names) {
+			listed[schema.names[i]] = true
+		}
+	}
+	keep := func(name string) bool { return listed[name] != sel.GetExcludeMode() }
+
+	for _, record := range records {
+		values := make([]*tabularpb.FieldValue, 0, len(record.Values))
+		for i, name := range schema.names {
+			if keep(name) && i < len(record.Values) {
+				values = append(values, record.Values[i])
+			}
+		}
+		record.Values = values
+		for name := range record.NamedValues {
+			if !keep(name) {
+				delete(record.NamedValues, name)
+			}
+		}
+	}
+}
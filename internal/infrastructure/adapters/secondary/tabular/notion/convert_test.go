@@ -0,0 +1,216 @@
+package notion
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	tabularpb "github.com/erniealice/esqyma/pkg/schema/v1/integration/tabular"
+)
+
+func testSchema() *databaseSchema {
+	return newDatabaseSchema(&database{
+		ID: "db1",
+		Properties: map[string]propertySchema{
+			"Name":   {ID: "title", Name: "Name", Type: "title"},
+			"Amount": {ID: "a", Name: "Amount", Type: "number"},
+			"Paid":   {ID: "p", Name: "Paid", Type: "checkbox"},
+			"Stage":  {ID: "s", Name: "Stage", Type: "status", Status: &optionList{Options: []option{{Name: "Lead"}, {Name: "Won"}}}},
+			"Tags":   {ID: "t", Name: "Tags", Type: "multi_select"},
+			"Due":    {ID: "d", Name: "Due", Type: "date"},
+			"Total":  {ID: "f", Name: "Total", Type: "formula"},
+		},
+	})
+}
+
+func str(s string) *tabularpb.FieldValue { return stringValue(s) }
+
+func byName(name string, op tabularpb.FilterOperator, value *tabularpb.FieldValue, values ...*tabularpb.FieldValue) *tabularpb.Filter {
+	return &tabularpb.Filter{Field: &tabularpb.Filter_FieldName{FieldName: name}, Operator: op, Value: value, Values: values}
+}
+
+func asJSON(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestSchemaOrder(t *testing.T) {
+	schema := testSchema()
+	want := []string{"Name", "Amount", "Due", "Paid", "Stage", "Tags", "Total"}
+	if !reflect.DeepEqual(schema.names, want) {
+		t.Fatalf("names = %v, want the title first and then by name", schema.names)
+	}
+	fields := schema.tableSchema().Fields
+	if !fields[0].Required || fields[1].FieldType != tabularpb.FieldType_FIELD_TYPE_FLOAT {
+		t.Errorf("fields = %v", fields[:2])
+	}
+	if got := fields[4].GetConstraints().GetAllowedValues(); !reflect.DeepEqual(got, []string{"Lead", "Won"}) {
+		t.Errorf("status options = %v", got)
+	}
+}
+
+func TestTranslateFilterGroup(t *testing.T) {
+	schema := testSchema()
+	tests := []struct {
+		name  string
+		group *tabularpb.FilterGroup
+		want  string
+	}{
+		{
+			name: "single clause is not wrapped",
+			group: &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{
+				byName("Name", tabularpb.FilterOperator_FILTER_OPERATOR_STARTS_WITH, str("Acme")),
+			}},
+			want: `{"property":"Name","title":{"starts_with":"Acme"}}`,
+		},
+		{
+			name: "and with typed operands",
+			group: &tabularpb.FilterGroup{
+				Operator: tabularpb.LogicalOperator_LOGICAL_OPERATOR_AND,
+				Filters: []*tabularpb.Filter{
+					byName("Amount", tabularpb.FilterOperator_FILTER_OPERATOR_GREATER_THAN_OR_EQUALS, str("100.5")),
+					byName("Paid", tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS, str("false")),
+					byName("Due", tabularpb.FilterOperator_FILTER_OPERATOR_LESS_THAN, str("2026-01-31")),
+				},
+			},
+			want: `{"and":[{"number":{"greater_than_or_equal_to":100.5},"property":"Amount"},{"checkbox":{"equals":false},"property":"Paid"},{"date":{"before":"2026-01-31"},"property":"Due"}]}`,
+		},
+		{
+			name: "or with a nested group, by index",
+			group: &tabularpb.FilterGroup{
+				Operator: tabularpb.LogicalOperator_LOGICAL_OPERATOR_OR,
+				Filters: []*tabularpb.Filter{{
+					Field:    &tabularpb.Filter_FieldIndex{FieldIndex: 5},
+					Operator: tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS,
+					Value:    str("vip"),
+				}},
+				Groups: []*tabularpb.FilterGroup{{Filters: []*tabularpb.Filter{
+					byName("Stage", tabularpb.FilterOperator_FILTER_OPERATOR_IS_EMPTY, nil),
+					byName("Paid", tabularpb.FilterOperator_FILTER_OPERATOR_IS_NOT_NULL, nil),
+				}}},
+			},
+			want: `{"or":[{"multi_select":{"contains":"vip"},"property":"Tags"},{"and":[{"property":"Stage","status":{"is_empty":true}},{"checkbox":{"equals":true},"property":"Paid"}]}]}`,
+		},
+		{
+			name: "in and between expand to compounds",
+			group: &tabularpb.FilterGroup{Filters: []*tabularpb.Filter{
+				byName("Stage", tabularpb.FilterOperator_FILTER_OPERATOR_IN, nil, str("Lead"), str("Won")),
+				byName("Amount", tabularpb.FilterOperator_FILTER_OPERATOR_BETWEEN, nil, str("1"), str("9")),
+			}},
+			want: `{"and":[{"or":[{"property":"Stage","status":{"equals":"Lead"}},{"property":"Stage","status":{"equals":"Won"}}]},{"and":[{"number":{"greater_than_or_equal_to":1},"property":"Amount"},{"number":{"less_than_or_equal_to":9},"property":"Amount"}]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := translateFilterGroup(schema, tt.group)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := asJSON(t, got); s != tt.want {
+				t.Errorf("filter =\n%s\nwant\n%s", s, tt.want)
+			}
+		})
+	}
+
+	if got, err := translateFilterGroup(schema, &tabularpb.FilterGroup{}); err != nil || got != nil {
+		t.Errorf("empty group = %v, %v; want nil", got, err)
+	}
+}
+
+func TestTranslateFilterRejects(t *testing.T) {
+	schema := testSchema()
+	for name, f := range map[string]*tabularpb.Filter{
+		"regex":            byName("Name", tabularpb.FilterOperator_FILTER_OPERATOR_MATCHES_REGEX, str("^A")),
+		"unknown property": byName("Owner", tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS, str("x")),
+		"formula":          byName("Total", tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS, str("1")),
+		"bad number":       byName("Amount", tabularpb.FilterOperator_FILTER_OPERATOR_EQUALS, str("ten")),
+		"range on select":  byName("Stage", tabularpb.FilterOperator_FILTER_OPERATOR_GREATER_THAN, str("Lead")),
+	} {
+		if _, err := translateFilter(schema, f); err == nil {
+			t.Errorf("%s: translated without error", name)
+		}
+	}
+}
+
+func TestPageToRecord(t *testing.T) {
+	raw := `{
+		"id": "page-1",
+		"url": "https://www.notion.so/page-1",
+		"created_time": "2026-03-01T08:00:00.000Z",
+		"last_edited_time": "2026-03-02T08:00:00.000Z",
+		"properties": {
+			"Name": {"type": "title", "title": [{"plain_text": "Acme "}, {"plain_text": "Corp"}]},
+			"Amount": {"type": "number", "number": 1250},
+			"Paid": {"type": "checkbox", "checkbox": true},
+			"Stage": {"type": "status", "status": {"name": "Won"}},
+			"Tags": {"type": "multi_select", "multi_select": [{"name": "vip"}, {"name": "ph"}]},
+			"Due": {"type": "date", "date": {"start": "2026-03-15", "end": null}},
+			"Total": {"type": "formula", "formula": {"type": "number", "number": 1400}},
+			"Lines": {"type": "rollup", "rollup": {"type": "number", "number": 3}}
+		}
+	}`
+	var pg page
+	if err := json.Unmarshal([]byte(raw), &pg); err != nil {
+		t.Fatal(err)
+	}
+	record := pageToRecord(testSchema(), &pg, 7)
+
+	if record.Id != "page-1" || record.Index != 7 || record.GetCreatedAt().AsTime().Day() != 1 {
+		t.Errorf("record header = %v", record)
+	}
+	named := record.NamedValues
+	if named["Name"].GetStringValue() != "Acme Corp" ||
+		named["Amount"].GetFloatValue() != 1250 ||
+		!named["Paid"].GetBooleanValue() ||
+		named["Stage"].GetStringValue() != "Won" ||
+		named["Tags"].GetStringValue() != "vip, ph" ||
+		named["Due"].GetDateValue() != "2026-03-15" ||
+		named["Total"].GetFloatValue() != 1400 {
+		t.Errorf("named values = %v", named)
+	}
+	if named["Lines"].GetJsonValue().GetFields()["type"].GetStringValue() != "rollup" {
+		t.Errorf("rollup = %v, want it passed on as JSON", named["Lines"])
+	}
+	if len(record.Values) != 7 || record.Values[0].GetStringValue() != "Acme Corp" {
+		t.Errorf("values = %v, want them in schema order", record.Values)
+	}
+}
+
+func TestRecordToProperties(t *testing.T) {
+	schema := testSchema()
+	props, err := recordToProperties(schema, &tabularpb.Record{NamedValues: map[string]*tabularpb.FieldValue{
+		"Name":   str("Acme"),
+		"Amount": str("42"),
+		"Paid":   {Value: &tabularpb.FieldValue_BooleanValue{BooleanValue: true}},
+		"Tags":   str("vip, ph ,"),
+		"Stage":  nullValue(),
+		"Due":    {Value: &tabularpb.FieldValue_DateValue{DateValue: "2026-04-01"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Amount":{"number":42},"Due":{"date":{"start":"2026-04-01"}},"Name":{"title":[{"text":{"content":"Acme"},"type":"text"}]},"Paid":{"checkbox":true},"Stage":{"status":null},"Tags":{"multi_select":[{"name":"vip"},{"name":"ph"}]}}`
+	if got := asJSON(t, props); got != want {
+		t.Errorf("properties =\n%s\nwant\n%s", got, want)
+	}
+
+	// Positional values follow the schema order.
+	props, err = recordToProperties(schema, &tabularpb.Record{Values: []*tabularpb.FieldValue{str("Beta"), str("7")}})
+	if err != nil || asJSON(t, props["Amount"]) != `{"number":7}` {
+		t.Errorf("positional = %v, %v", props, err)
+	}
+
+	if _, err := recordToProperties(schema, &tabularpb.Record{NamedValues: map[string]*tabularpb.FieldValue{"Total": str("1")}}); err == nil {
+		t.Error("wrote a formula property")
+	}
+
+	long := strings.Repeat("é", maxTextLength+5)
+	if parts := textPayload(long); len(parts) != 2 {
+		t.Errorf("long text split into %d parts, want 2", len(parts))
+	}
+}
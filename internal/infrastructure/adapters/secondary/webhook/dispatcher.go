@@ -0,0 +1,373 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// Delivery defaults.
+const (
+	DefaultMaxAttempts = 8
+	DefaultBaseBackoff = 30 * time.Second
+	DefaultMaxBackoff  = 6 * time.Hour
+	DefaultTimeout     = 10 * time.Second
+	DefaultQueueSize   = 256
+	DefaultWorkers     = 4
+)
+
+// DeliveryStatus is where a delivery stands.
+type DeliveryStatus string
+
+const (
+	// StatusPending is a delivery not yet attempted.
+	StatusPending DeliveryStatus = "pending"
+	// StatusDelivered is a delivery the endpoint answered with 2xx.
+	StatusDelivered DeliveryStatus = "delivered"
+	// StatusRetrying is a failed delivery waiting for NextAttemptAt.
+	StatusRetrying DeliveryStatus = "retrying"
+	// StatusFailed is a delivery that ran out of attempts, or whose
+	// endpoint was removed.
+	StatusFailed DeliveryStatus = "failed"
+)
+
+// Delivery is one event sent to one endpoint, with its attempts so far.
+type Delivery struct {
+	ID            string
+	EventID       string
+	EventType     string
+	WorkspaceID   string
+	EndpointID    string
+	URL           string
+	Status        DeliveryStatus
+	Attempts      int
+	ResponseCode  int
+	LastError     string
+	NextAttemptAt time.Time
+	DeliveredAt   time.Time
+	CreatedAt     time.Time
+
+	payload string
+}
+
+// Config tunes a Dispatcher; zero fields take the defaults.
+type Config struct {
+	DeliveryTable string
+	// MaxAttempts counts the first attempt.
+	MaxAttempts int
+	// BaseBackoff is the wait after the first failure; it doubles with
+	// every further failure up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Timeout bounds each request to an endpoint.
+	Timeout time.Duration
+	// QueueSize bounds the events waiting for a worker; events emitted
+	// while it is full are dropped and logged.
+	QueueSize int
+	Workers   int
+	Client    *http.Client
+}
+
+// Dispatcher delivers domain events to the registered endpoints. It is the
+// domainevent.Sink; Run does the delivering.
+type Dispatcher struct {
+	store  *Store
+	ops    interfaces.DatabaseOperation
+	config Config
+	client *http.Client
+	queue  chan domainevent.Event
+	now    func() time.Time
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher delivering to the endpoints of store
+// and logging deliveries through ops.
+func NewDispatcher(ops interfaces.DatabaseOperation, store *Store, config Config) *Dispatcher {
+	if config.DeliveryTable == "" {
+		config.DeliveryTable = DefaultDeliveryTable
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = DefaultBaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultQueueSize
+	}
+	if config.Workers <= 0 {
+		config.Workers = DefaultWorkers
+	}
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+	return &Dispatcher{
+		store:  store,
+		ops:    ops,
+		config: config,
+		client: client,
+		queue:  make(chan domainevent.Event, config.QueueSize),
+		now:    time.Now,
+	}
+}
+
+// Store returns the endpoint store.
+func (d *Dispatcher) Store() *Store {
+	return d.store
+}
+
+// PublishDomainEvent queues e for delivery without blocking. Events
+// without a workspace have no endpoints and are skipped.
+func (d *Dispatcher) PublishDomainEvent(e domainevent.Event) {
+	if e.WorkspaceID == "" {
+		return
+	}
+	select {
+	case d.queue <- e:
+	default:
+		log.Printf("⚠️  webhook: queue full, dropped %s event %s for workspace %s", e.Type, e.ID, e.WorkspaceID)
+	}
+}
+
+// Run delivers queued events, and every pollInterval retries the failed
+// deliveries that are due, until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context, pollInterval time.Duration) {
+	for i := 0; i < d.config.Workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case e := <-d.queue:
+					d.dispatch(ctx, e)
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			d.wg.Wait()
+			return
+		case <-ticker.C:
+			if _, err := d.RetryDue(ctx); err != nil {
+				log.Printf("webhook: retry: %v", err)
+			}
+		}
+	}
+}
+
+// dispatch logs a delivery of e for each subscribed endpoint and makes its
+// first attempt.
+func (d *Dispatcher) dispatch(ctx context.Context, e domainevent.Event) {
+	endpoints, err := d.store.matching(ctx, e.WorkspaceID, e.Type)
+	if err != nil {
+		log.Printf("webhook: %s event %s: %v", e.Type, e.ID, err)
+		return
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+	body, err := domainevent.Marshal(e)
+	if err != nil {
+		log.Printf("webhook: encode %s event %s: %v", e.Type, e.ID, err)
+		return
+	}
+	for _, endpoint := range endpoints {
+		row, err := d.ops.Create(ctx, d.config.DeliveryTable, map[string]any{
+			"event_id":        e.ID,
+			"event_type":      e.Type,
+			"workspace_id":    e.WorkspaceID,
+			"endpoint_id":     endpoint.ID,
+			"url":             endpoint.URL,
+			"payload":         string(body),
+			"status":          string(StatusPending),
+			"attempts":        0,
+			"response_code":   0,
+			"last_error":      "",
+			"next_attempt_at": d.now().UnixMilli(),
+			"delivered_at":    0,
+			"created_at":      d.now().UnixMilli(),
+		})
+		if err != nil {
+			log.Printf("webhook: log %s delivery to %s: %v", e.Type, endpoint.URL, err)
+			continue
+		}
+		d.attempt(ctx, deliveryFromRow(row), endpoint)
+	}
+}
+
+// RetryDue makes the next attempt of every delivery whose backoff has
+// elapsed and reports how many it attempted.
+func (d *Dispatcher) RetryDue(ctx context.Context) (int, error) {
+	result, err := d.ops.List(ctx, d.config.DeliveryTable, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{
+			stringEquals("status", string(StatusRetrying)),
+		}},
+		Pagination: firstPage(listLimit),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list due webhook deliveries: %w", err)
+	}
+	now := d.now()
+	attempted := 0
+	for _, row := range result.Data {
+		delivery := deliveryFromRow(row)
+		if delivery.Status != StatusRetrying || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		endpoint, err := d.store.get(ctx, delivery.WorkspaceID, delivery.EndpointID)
+		if err != nil || !endpoint.Active {
+			d.record(ctx, delivery, map[string]any{
+				"status":     string(StatusFailed),
+				"last_error": "endpoint removed or disabled",
+			})
+			continue
+		}
+		d.attempt(ctx, delivery, *endpoint)
+		attempted++
+	}
+	return attempted, nil
+}
+
+// attempt sends delivery to endpoint once and records the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, delivery Delivery, endpoint Endpoint) {
+	attempts := delivery.Attempts + 1
+	code, err := d.send(ctx, delivery, endpoint)
+	update := map[string]any{
+		"attempts":      attempts,
+		"response_code": code,
+		"last_error":    "",
+	}
+	switch {
+	case err == nil:
+		update["status"] = string(StatusDelivered)
+		update["delivered_at"] = d.now().UnixMilli()
+	case attempts >= d.config.MaxAttempts:
+		update["status"] = string(StatusFailed)
+		update["last_error"] = err.Error()
+		log.Printf("❌ webhook: %s event %s to %s failed after %d attempts: %v", delivery.EventType, delivery.EventID, endpoint.URL, attempts, err)
+	default:
+		update["status"] = string(StatusRetrying)
+		update["last_error"] = err.Error()
+		update["next_attempt_at"] = d.now().Add(d.Backoff(attempts)).UnixMilli()
+	}
+	d.record(ctx, delivery, update)
+}
+
+// send POSTs the delivery's payload, signed with the endpoint's secret.
+func (d *Dispatcher) send(ctx context.Context, delivery Delivery, endpoint Endpoint) (int, error) {
+	body := []byte(delivery.payload)
+	ctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Espyna-Webhooks/1.0")
+	req.Header.Set(EventTypeHeader, delivery.EventType)
+	req.Header.Set(EventIDHeader, delivery.EventID)
+	req.Header.Set(DeliveryIDHeader, delivery.ID)
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, d.now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) record(ctx context.Context, delivery Delivery, update map[string]any) {
+	if _, err := d.ops.Update(ctx, d.config.DeliveryTable, delivery.ID, update); err != nil {
+		log.Printf("webhook: record delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// Backoff is the wait before the attempt after the given number of failed
+// attempts: BaseBackoff, doubling each time, capped at MaxBackoff.
+func (d *Dispatcher) Backoff(failures int) time.Duration {
+	wait := d.config.BaseBackoff
+	for i := 1; i < failures; i++ {
+		wait *= 2
+		if wait >= d.config.MaxBackoff {
+			return d.config.MaxBackoff
+		}
+	}
+	return wait
+}
+
+// Deliveries returns the delivery log of workspaceID, newest first,
+// limited to one endpoint when endpointID is set.
+func (d *Dispatcher) Deliveries(ctx context.Context, workspaceID, endpointID string) ([]Delivery, error) {
+	filters := []*commonpb.TypedFilter{stringEquals("workspace_id", workspaceID)}
+	if endpointID != "" {
+		filters = append(filters, stringEquals("endpoint_id", endpointID))
+	}
+	result, err := d.ops.List(ctx, d.config.DeliveryTable, &interfaces.ListParams{
+		Filters:    &commonpb.FilterRequest{Filters: filters},
+		Pagination: firstPage(listLimit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	deliveries := []Delivery{}
+	for _, row := range result.Data {
+		delivery := deliveryFromRow(row)
+		if delivery.WorkspaceID != workspaceID || (endpointID != "" && delivery.EndpointID != endpointID) {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	return deliveries, nil
+}
+
+func deliveryFromRow(row map[string]any) Delivery {
+	return Delivery{
+		ID:            str(row["id"]),
+		EventID:       str(row["event_id"]),
+		EventType:     str(row["event_type"]),
+		WorkspaceID:   str(row["workspace_id"]),
+		EndpointID:    str(row["endpoint_id"]),
+		URL:           str(row["url"]),
+		Status:        DeliveryStatus(str(row["status"])),
+		Attempts:      int(operations.Number(row["attempts"])),
+		ResponseCode:  int(operations.Number(row["response_code"])),
+		LastError:     str(row["last_error"]),
+		NextAttemptAt: fromMillis(row["next_attempt_at"]),
+		DeliveredAt:   fromMillis(row["delivered_at"]),
+		CreatedAt:     fromMillis(row["created_at"]),
+		payload:       str(row["payload"]),
+	}
+}
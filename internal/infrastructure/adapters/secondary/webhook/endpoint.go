@@ -0,0 +1,299 @@
+// Package webhook delivers domain events to the HTTP endpoints workspaces
+// register, so their own systems hear about new clients, paid invoices or
+// cancelled schedules without polling the API.
+//
+// A workspace registers an endpoint URL and the event types it wants; it
+// receives a signing secret once, at registration. The Dispatcher is the
+// domainevent.Sink: each event is queued, POSTed as JSON to every matching
+// endpoint of its workspace with an HMAC-SHA256 signature (see Sign and
+// Verify), and retried with exponential backoff until the endpoint
+// answers 2xx or the attempts run out. Every delivery is recorded in the
+// delivery log, which is also what the retries are driven from, so a
+// restart does not lose them.
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/operations"
+	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
+)
+
+// DefaultEndpointTable and DefaultDeliveryTable hold the registered
+// endpoints and the delivery log (see the postgres integration migration
+// 000012_webhook).
+const (
+	DefaultEndpointTable = "webhook_endpoint"
+	DefaultDeliveryTable = "webhook_delivery"
+)
+
+// listLimit bounds the rows one read returns.
+const listLimit = 1000
+
+// Validation errors of Register.
+var (
+	ErrInvalidURL       = errors.New("webhook url must be an absolute https url")
+	ErrUnknownEventType = errors.New("unknown webhook event type")
+	ErrNotFound         = errors.New("webhook endpoint not found")
+)
+
+// Endpoint is a workspace's registered receiver.
+type Endpoint struct {
+	ID          string
+	WorkspaceID string
+	URL         string
+	// Secret signs the deliveries. It is returned by Register only; lists
+	// leave it out.
+	Secret string
+	// Events are the subscribed event types; "*" subscribes to all.
+	Events    []string
+	Active    bool
+	CreatedAt time.Time
+}
+
+// Subscribes reports whether e receives events of eventType.
+func (e Endpoint) Subscribes(eventType string) bool {
+	for _, t := range e.Events {
+		if t == "*" || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Store keeps the registered endpoints.
+type Store struct {
+	ops   interfaces.DatabaseOperation
+	table string
+	// AllowHTTP accepts plain http URLs, for local development.
+	AllowHTTP bool
+	now       func() time.Time
+}
+
+// NewStore creates the endpoint store on table (DefaultEndpointTable when
+// empty).
+func NewStore(ops interfaces.DatabaseOperation, table string) *Store {
+	if table == "" {
+		table = DefaultEndpointTable
+	}
+	return &Store{ops: ops, table: table, now: time.Now}
+}
+
+// Register adds an endpoint for workspaceID receiving events, and returns
+// it with its newly generated secret.
+func (s *Store) Register(ctx context.Context, workspaceID, rawURL string, events []string) (*Endpoint, error) {
+	if workspaceID == "" {
+		return nil, errors.New("webhook endpoints belong to a workspace")
+	}
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" || (u.Scheme != "https" && !(s.AllowHTTP && u.Scheme == "http")) {
+		return nil, ErrInvalidURL
+	}
+	events, err = normalizeEvents(events)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	row, err := s.ops.Create(ctx, s.table, map[string]any{
+		"workspace_id": workspaceID,
+		"url":          u.String(),
+		"secret":       secret,
+		"events":       strings.Join(events, ","),
+		"active":       true,
+		"created_at":   now.UnixMilli(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("register webhook endpoint: %w", err)
+	}
+	return &Endpoint{
+		ID:          str(row["id"]),
+		WorkspaceID: workspaceID,
+		URL:         u.String(),
+		Secret:      secret,
+		Events:      events,
+		Active:      true,
+		CreatedAt:   now,
+	}, nil
+}
+
+// List returns the endpoints of workspaceID, oldest first, without their
+// secrets.
+func (s *Store) List(ctx context.Context, workspaceID string) ([]Endpoint, error) {
+	endpoints, err := s.list(ctx, workspaceID)
+	for i := range endpoints {
+		endpoints[i].Secret = ""
+	}
+	return endpoints, err
+}
+
+// Delete removes an endpoint of workspaceID. Its delivery log stays.
+func (s *Store) Delete(ctx context.Context, workspaceID, id string) error {
+	endpoint, err := s.get(ctx, workspaceID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.ops.HardDelete(ctx, s.table, endpoint.ID); err != nil {
+		return fmt.Errorf("delete webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+// matching returns the active endpoints of workspaceID subscribed to
+// eventType, with their secrets.
+func (s *Store) matching(ctx context.Context, workspaceID, eventType string) ([]Endpoint, error) {
+	endpoints, err := s.list(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	var matched []Endpoint
+	for _, e := range endpoints {
+		if e.Active && e.Subscribes(eventType) {
+			matched = append(matched, e)
+		}
+	}
+	return matched, nil
+}
+
+// get returns one endpoint of workspaceID with its secret.
+func (s *Store) get(ctx context.Context, workspaceID, id string) (*Endpoint, error) {
+	row, err := s.ops.Read(ctx, s.table, id)
+	if err != nil || row == nil {
+		return nil, ErrNotFound
+	}
+	// Another workspace's endpoint is reported as missing, not forbidden.
+	if e := endpointFromRow(row); e.WorkspaceID == workspaceID {
+		return &e, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (s *Store) list(ctx context.Context, workspaceID string) ([]Endpoint, error) {
+	result, err := s.ops.List(ctx, s.table, &interfaces.ListParams{
+		Filters: &commonpb.FilterRequest{Filters: []*commonpb.TypedFilter{
+			stringEquals("workspace_id", workspaceID),
+		}},
+		Pagination: firstPage(listLimit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list webhook endpoints: %w", err)
+	}
+	endpoints := []Endpoint{}
+	for _, row := range result.Data {
+		// Guard against providers that ignore the filter.
+		if e := endpointFromRow(row); e.WorkspaceID == workspaceID {
+			endpoints = append(endpoints, e)
+		}
+	}
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return endpoints[i].CreatedAt.Before(endpoints[j].CreatedAt)
+	})
+	return endpoints, nil
+}
+
+// normalizeEvents checks the requested event types against
+// domainevent.Types; none subscribes to all.
+func normalizeEvents(events []string) ([]string, error) {
+	known := make(map[string]bool, len(domainevent.Types))
+	for _, t := range domainevent.Types {
+		known[t] = true
+	}
+	seen := make(map[string]bool)
+	var out []string
+	for _, t := range events {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		if t != "*" && !known[t] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, t)
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	if len(out) == 0 || seen["*"] {
+		return []string{"*"}, nil
+	}
+	return out, nil
+}
+
+func newSecret() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b[:]), nil
+}
+
+func endpointFromRow(row map[string]any) Endpoint {
+	active, _ := row["active"].(bool)
+	var events []string
+	for _, t := range strings.Split(str(row["events"]), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			events = append(events, t)
+		}
+	}
+	return Endpoint{
+		ID:          str(row["id"]),
+		WorkspaceID: str(row["workspace_id"]),
+		URL:         str(row["url"]),
+		Secret:      str(row["secret"]),
+		Events:      events,
+		Active:      active,
+		CreatedAt:   fromMillis(row["created_at"]),
+	}
+}
+
+func millis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromMillis(v any) time.Time {
+	if ms := int64(operations.Number(v)); ms > 0 {
+		return time.UnixMilli(ms).UTC()
+	}
+	return time.Time{}
+}
+
+func firstPage(limit int32) *commonpb.PaginationRequest {
+	return &commonpb.PaginationRequest{
+		Limit: limit,
+		Method: &commonpb.PaginationRequest_Offset{
+			Offset: &commonpb.OffsetPagination{Page: 1},
+		},
+	}
+}
+
+func stringEquals(field, value string) *commonpb.TypedFilter {
+	return &commonpb.TypedFilter{
+		Field: field,
+		FilterType: &commonpb.TypedFilter_StringFilter{
+			StringFilter: &commonpb.StringFilter{
+				Value:         value,
+				Operator:      commonpb.StringOperator_STRING_EQUALS,
+				CaseSensitive: true,
+			},
+		},
+	}
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
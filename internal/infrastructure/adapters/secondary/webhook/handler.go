@@ -0,0 +1,221 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/actiongate"
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// The webhook endpoints, all scoped to the caller's workspace:
+//
+//	GET    EndpointsPath            the registered endpoints, without secrets
+//	POST   EndpointsPath            register {"url", "events"}; the answer
+//	                                holds the signing secret, shown only once
+//	DELETE EndpointsPath?id=...     remove an endpoint
+//	GET    DeliveriesPath           the delivery log, newest first;
+//	                                ?endpoint_id=... narrows it
+//
+// Each needs the matching webhook permission: list, create, delete.
+const (
+	EndpointsPath  = "/api/webhooks"
+	DeliveriesPath = "/api/webhooks/deliveries"
+)
+
+// entityWebhook is the permission entity of the endpoints.
+const entityWebhook = "webhook"
+
+type endpointJSON struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt int64    `json:"created_at"`
+}
+
+type deliveryJSON struct {
+	ID            string         `json:"id"`
+	EventID       string         `json:"event_id"`
+	EventType     string         `json:"event_type"`
+	EndpointID    string         `json:"endpoint_id"`
+	URL           string         `json:"url"`
+	Status        DeliveryStatus `json:"status"`
+	Attempts      int            `json:"attempts"`
+	ResponseCode  int            `json:"response_code,omitempty"`
+	LastError     string         `json:"last_error,omitempty"`
+	NextAttemptAt int64          `json:"next_attempt_at,omitempty"`
+	DeliveredAt   int64          `json:"delivered_at,omitempty"`
+	CreatedAt     int64          `json:"created_at"`
+}
+
+type registerRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// Handlers serves the webhook endpoints.
+type Handlers struct {
+	dispatcher *Dispatcher
+	gate       *actiongate.ActionGatekeeper
+}
+
+// NewHandlers creates the endpoint handlers over dispatcher. gate decides
+// who may manage the workspace's webhooks.
+func NewHandlers(dispatcher *Dispatcher, gate *actiongate.ActionGatekeeper) *Handlers {
+	return &Handlers{dispatcher: dispatcher, gate: gate}
+}
+
+// Endpoints lists, registers or removes endpoints by method.
+func (h *Handlers) Endpoints(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.list(w, r)
+	case http.MethodPost:
+		h.register(w, r)
+	case http.MethodDelete:
+		h.remove(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+	}
+}
+
+// Deliveries returns the workspace's delivery log.
+func (h *Handlers) Deliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"success": false, "error": "method not allowed"})
+		return
+	}
+	workspaceID, ok := h.allowed(w, r, entityid.ActionList)
+	if !ok {
+		return
+	}
+	deliveries, err := h.dispatcher.Deliveries(r.Context(), workspaceID, r.URL.Query().Get("endpoint_id"))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	data := make([]deliveryJSON, 0, len(deliveries))
+	for _, d := range deliveries {
+		data = append(data, deliveryJSON{
+			ID:            d.ID,
+			EventID:       d.EventID,
+			EventType:     d.EventType,
+			EndpointID:    d.EndpointID,
+			URL:           d.URL,
+			Status:        d.Status,
+			Attempts:      d.Attempts,
+			ResponseCode:  d.ResponseCode,
+			LastError:     d.LastError,
+			NextAttemptAt: nextAttempt(d),
+			DeliveredAt:   millis(d.DeliveredAt),
+			CreatedAt:     millis(d.CreatedAt),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": data})
+}
+
+func (h *Handlers) list(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.allowed(w, r, entityid.ActionList)
+	if !ok {
+		return
+	}
+	endpoints, err := h.dispatcher.store.List(r.Context(), workspaceID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	data := make([]endpointJSON, 0, len(endpoints))
+	for _, e := range endpoints {
+		data = append(data, toEndpointJSON(e))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "data": data})
+}
+
+func (h *Handlers) register(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.allowed(w, r, entityid.ActionCreate)
+	if !ok {
+		return
+	}
+	var req registerRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "invalid JSON body"})
+		return
+	}
+	endpoint, err := h.dispatcher.store.Register(r.Context(), workspaceID, req.URL, req.Events)
+	if errors.Is(err, ErrInvalidURL) || errors.Is(err, ErrUnknownEventType) {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"success": true, "data": toEndpointJSON(*endpoint)})
+}
+
+func (h *Handlers) remove(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.allowed(w, r, entityid.ActionDelete)
+	if !ok {
+		return
+	}
+	err := h.dispatcher.store.Delete(r.Context(), workspaceID, r.URL.Query().Get("id"))
+	if errors.Is(err, ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+// allowed checks the caller may take action on the webhooks of their
+// workspace, writing the refusal when not.
+func (h *Handlers) allowed(w http.ResponseWriter, r *http.Request, action string) (string, bool) {
+	ctx := r.Context()
+	if contextutil.ExtractUserIDFromContext(ctx) == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "error": "authentication required"})
+		return "", false
+	}
+	workspaceID := contextutil.ExtractWorkspaceIDFromContext(ctx)
+	if workspaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error": "workspace required"})
+		return "", false
+	}
+	if err := h.gate.Check(ctx, &actiongate.CheckActionRequest{Entity: entityWebhook, Action: action}); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]any{"success": false, "error": err.Error()})
+		return "", false
+	}
+	return workspaceID, true
+}
+
+func toEndpointJSON(e Endpoint) endpointJSON {
+	return endpointJSON{
+		ID:        e.ID,
+		URL:       e.URL,
+		Events:    e.Events,
+		Active:    e.Active,
+		Secret:    e.Secret,
+		CreatedAt: millis(e.CreatedAt),
+	}
+}
+
+// nextAttempt is reported only while a retry is pending.
+func nextAttempt(d Delivery) int64 {
+	if d.Status != StatusRetrying {
+		return 0
+	}
+	return millis(d.NextAttemptAt)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Delivery headers. SignatureHeader carries "t=<unix seconds>,v1=<hex>",
+// where v1 is the HMAC-SHA256, keyed with the endpoint's secret, of the
+// timestamp, a ".", and the raw body. Receivers recompute it (Verify does)
+// and drop deliveries whose EventIDHeader they have already seen:
+// retries resend the same event.
+const (
+	SignatureHeader  = "Espyna-Signature"
+	EventTypeHeader  = "Espyna-Event"
+	EventIDHeader    = "Espyna-Event-Id"
+	DeliveryIDHeader = "Espyna-Delivery"
+)
+
+// DefaultTolerance is how old a signature Verify accepts.
+const DefaultTolerance = 5 * time.Minute
+
+// ErrBadSignature is returned by Verify for a missing, malformed, stale or
+// wrong signature.
+var ErrBadSignature = errors.New("webhook signature does not verify")
+
+// Sign returns the SignatureHeader value for body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + signature(secret, ts, body)
+}
+
+// Verify checks header, a SignatureHeader value, against body. Signatures
+// older than tolerance (DefaultTolerance when zero) are refused, so a
+// captured delivery cannot be replayed later.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sigs = append(sigs, value)
+		}
+	}
+	sent, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return ErrBadSignature
+	}
+	if age := now.Sub(time.Unix(sent, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp outside %s", ErrBadSignature, tolerance)
+	}
+	want := signature(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(want)) {
+			return nil
+		}
+	}
+	return ErrBadSignature
+}
+
+func signature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,333 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	contextutil "github.com/erniealice/espyna-golang/internal/application/shared/context"
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	"github.com/erniealice/espyna-golang/internal/application/shared/testutil"
+	interfaces "github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/interface"
+	"github.com/erniealice/espyna-golang/internal/infrastructure/adapters/secondary/database/common/model"
+	clientpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/entity/client"
+)
+
+// memOps stores rows per table in memory and honours the string filters
+// the package uses.
+type memOps struct {
+	interfaces.DatabaseOperation
+	mu     sync.Mutex
+	tables map[string][]map[string]any
+	nextID int
+}
+
+func newMemOps() *memOps {
+	return &memOps{tables: map[string][]map[string]any{}}
+}
+
+func (o *memOps) Create(_ context.Context, table string, data map[string]any) (map[string]any, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.nextID++
+	row := map[string]any{"id": fmt.Sprintf("r%d", o.nextID)}
+	for field, value := range data {
+		row[field] = value
+	}
+	o.tables[table] = append(o.tables[table], row)
+	return row, nil
+}
+
+func (o *memOps) Read(_ context.Context, table string, id string) (map[string]any, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, row := range o.tables[table] {
+		if row["id"] == id {
+			return row, nil
+		}
+	}
+	return nil, model.NewDatabaseError("record not found", "RECORD_NOT_FOUND", 404)
+}
+
+func (o *memOps) Update(_ context.Context, table string, id string, data map[string]any) (map[string]any, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, row := range o.tables[table] {
+		if row["id"] == id {
+			for field, value := range data {
+				row[field] = value
+			}
+			return row, nil
+		}
+	}
+	return nil, fmt.Errorf("no row %s", id)
+}
+
+func (o *memOps) HardDelete(_ context.Context, table string, id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	rows := o.tables[table]
+	for i, row := range rows {
+		if row["id"] == id {
+			o.tables[table] = append(rows[:i], rows[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no row %s", id)
+}
+
+func (o *memOps) List(_ context.Context, table string, params *interfaces.ListParams) (*interfaces.ListResult, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var data []map[string]any
+rows:
+	for _, row := range o.tables[table] {
+		for _, f := range params.Filters.GetFilters() {
+			if s := f.GetStringFilter(); s != nil && str(row[f.Field]) != s.Value {
+				continue rows
+			}
+		}
+		data = append(data, row)
+	}
+	return &interfaces.ListResult{Data: data}, nil
+}
+
+// receiver is an endpoint answering with the queued status codes, then
+// 200, and keeping what it received.
+type receiver struct {
+	mu       sync.Mutex
+	codes    []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+	code := http.StatusOK
+	if len(rc.codes) > 0 {
+		code, rc.codes = rc.codes[0], rc.codes[1:]
+	}
+	w.WriteHeader(code)
+}
+
+func newDispatcher(ops *memOps, config Config) (*Dispatcher, *time.Time) {
+	store := NewStore(ops, "")
+	store.AllowHTTP = true
+	d := NewDispatcher(ops, store, config)
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+	store.now = d.now
+	return d, &now
+}
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	body := []byte(`{"id":"evt_1"}`)
+	header := Sign("whsec_a", now, body)
+
+	if err := Verify("whsec_a", header, body, now.Add(time.Minute), 0); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	for name, err := range map[string]error{
+		"wrong secret": Verify("whsec_b", header, body, now, 0),
+		"changed body": Verify("whsec_a", header, []byte(`{"id":"evt_2"}`), now, 0),
+		"stale":        Verify("whsec_a", header, body, now.Add(time.Hour), 0),
+		"malformed":    Verify("whsec_a", "v1=abc", body, now, 0),
+	} {
+		if !errors.Is(err, ErrBadSignature) {
+			t.Errorf("%s: err = %v, want ErrBadSignature", name, err)
+		}
+	}
+}
+
+func TestStore_Register(t *testing.T) {
+	ops := newMemOps()
+	store := NewStore(ops, "")
+	ctx := context.Background()
+
+	if _, err := store.Register(ctx, "ws-1", "http://example.com/hook", nil); !errors.Is(err, ErrInvalidURL) {
+		t.Fatalf("plain http err = %v", err)
+	}
+	if _, err := store.Register(ctx, "ws-1", "https://example.com/hook", []string{"client.exploded"}); !errors.Is(err, ErrUnknownEventType) {
+		t.Fatalf("unknown event err = %v", err)
+	}
+	e, err := store.Register(ctx, "ws-1", "https://example.com/hook", []string{domainevent.ClientCreated, domainevent.ClientCreated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(e.Secret, "whsec_") || len(e.Events) != 1 || !e.Subscribes(domainevent.ClientCreated) || e.Subscribes(domainevent.InvoicePaid) {
+		t.Fatalf("endpoint = %+v", e)
+	}
+
+	listed, err := store.List(ctx, "ws-1")
+	if err != nil || len(listed) != 1 || listed[0].Secret != "" {
+		t.Fatalf("list = %+v, %v", listed, err)
+	}
+	if others, _ := store.List(ctx, "ws-2"); len(others) != 0 {
+		t.Fatalf("ws-2 sees %+v", others)
+	}
+	if err := store.Delete(ctx, "ws-2", e.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("cross-workspace delete err = %v", err)
+	}
+	if err := store.Delete(ctx, "ws-1", e.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDispatcher_DeliversWithRetries(t *testing.T) {
+	rc := &receiver{codes: []int{http.StatusInternalServerError, http.StatusBadGateway}}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	ops := newMemOps()
+	d, now := newDispatcher(ops, Config{})
+	ctx := context.Background()
+	endpoint, err := d.Store().Register(ctx, "ws-1", server.URL, []string{domainevent.ClientCreated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Store().Register(ctx, "ws-2", server.URL, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	d.dispatch(ctx, domainevent.Event{
+		ID: "evt_1", Type: domainevent.ClientCreated, WorkspaceID: "ws-1", OccurredAt: *now,
+		Data: &clientpb.Client{Id: "client-1", InternalId: "C-001"},
+	})
+	d.dispatch(ctx, domainevent.Event{ID: "evt_2", Type: domainevent.InvoicePaid, WorkspaceID: "ws-1"})
+
+	deliveries, _ := d.Deliveries(ctx, "ws-1", "")
+	if len(deliveries) != 1 || deliveries[0].Status != StatusRetrying || deliveries[0].ResponseCode != 500 ||
+		!deliveries[0].NextAttemptAt.Equal(now.Add(DefaultBaseBackoff)) {
+		t.Fatalf("after first attempt = %+v", deliveries)
+	}
+
+	// Not due yet, then due twice: 30s, then 60s after the second failure.
+	if n, _ := d.RetryDue(ctx); n != 0 {
+		t.Fatalf("retried %d before the backoff elapsed", n)
+	}
+	*now = now.Add(DefaultBaseBackoff)
+	if n, _ := d.RetryDue(ctx); n != 1 {
+		t.Fatalf("retried %d, want 1", n)
+	}
+	deliveries, _ = d.Deliveries(ctx, "ws-1", endpoint.ID)
+	if deliveries[0].Attempts != 2 || !deliveries[0].NextAttemptAt.Equal(now.Add(2*DefaultBaseBackoff)) {
+		t.Fatalf("after second attempt = %+v", deliveries[0])
+	}
+	*now = now.Add(2 * DefaultBaseBackoff)
+	_, _ = d.RetryDue(ctx)
+	deliveries, _ = d.Deliveries(ctx, "ws-1", "")
+	if deliveries[0].Status != StatusDelivered || deliveries[0].Attempts != 3 {
+		t.Fatalf("after third attempt = %+v", deliveries[0])
+	}
+
+	// Every attempt resent the same signed event.
+	if len(rc.requests) != 3 {
+		t.Fatalf("endpoint received %d requests", len(rc.requests))
+	}
+	last := rc.requests[2]
+	if err := Verify(endpoint.Secret, last.Header.Get(SignatureHeader), rc.bodies[2], *now, 0); err != nil {
+		t.Fatalf("signature: %v", err)
+	}
+	if last.Header.Get(EventIDHeader) != "evt_1" || last.Header.Get(EventTypeHeader) != domainevent.ClientCreated {
+		t.Errorf("headers = %v", last.Header)
+	}
+	var body struct {
+		ID   string         `json:"id"`
+		Type string         `json:"type"`
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(rc.bodies[2], &body); err != nil || body.ID != "evt_1" || body.Data["internal_id"] != "C-001" {
+		t.Fatalf("body = %s, %v", rc.bodies[2], err)
+	}
+}
+
+func TestDispatcher_GivesUp(t *testing.T) {
+	rc := &receiver{codes: []int{500, 500, 500}}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	ops := newMemOps()
+	d, now := newDispatcher(ops, Config{MaxAttempts: 2, BaseBackoff: time.Minute})
+	ctx := context.Background()
+	endpoint, _ := d.Store().Register(ctx, "ws-1", server.URL, nil)
+
+	d.dispatch(ctx, domainevent.Event{ID: "evt_1", Type: domainevent.ScheduleCancelled, WorkspaceID: "ws-1"})
+	*now = now.Add(time.Minute)
+	_, _ = d.RetryDue(ctx)
+	deliveries, _ := d.Deliveries(ctx, "ws-1", "")
+	if deliveries[0].Status != StatusFailed || deliveries[0].Attempts != 2 || deliveries[0].LastError == "" {
+		t.Fatalf("delivery = %+v", deliveries[0])
+	}
+
+	// A retry whose endpoint was removed fails without a request.
+	d.dispatch(ctx, domainevent.Event{ID: "evt_2", Type: domainevent.ScheduleCancelled, WorkspaceID: "ws-1"})
+	_ = d.Store().Delete(ctx, "ws-1", endpoint.ID)
+	*now = now.Add(time.Hour)
+	if n, _ := d.RetryDue(ctx); n != 0 || len(rc.requests) != 3 {
+		t.Fatalf("retried %d, endpoint received %d", n, len(rc.requests))
+	}
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := NewDispatcher(newMemOps(), NewStore(newMemOps(), ""), Config{BaseBackoff: time.Minute, MaxBackoff: 10 * time.Minute})
+	for failures, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 4: 8 * time.Minute, 5: 10 * time.Minute, 20: 10 * time.Minute} {
+		if got := d.Backoff(failures); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	ops := newMemOps()
+	d, _ := newDispatcher(ops, Config{})
+	h := NewHandlers(d, testutil.OpenActionGate())
+	ctx := contextutil.WithWorkspaceID(contextutil.WithUserID(context.Background(), "u-1"), "ws-1")
+
+	call := func(ctx context.Context, handler http.HandlerFunc, method, target, body string) (int, map[string]any) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body)).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+
+	if code, _ := call(context.Background(), h.Endpoints, http.MethodGet, EndpointsPath, ""); code != http.StatusUnauthorized {
+		t.Fatalf("anonymous list = %d", code)
+	}
+	if code, _ := call(ctx, h.Endpoints, http.MethodPost, EndpointsPath, `{"url":"ftp://x"}`); code != http.StatusBadRequest {
+		t.Fatalf("bad url = %d", code)
+	}
+	code, out := call(ctx, h.Endpoints, http.MethodPost, EndpointsPath, `{"url":"https://example.com/hook","events":["invoice.paid"]}`)
+	created, _ := out["data"].(map[string]any)
+	if code != http.StatusCreated || created["secret"] == nil {
+		t.Fatalf("register = %d %v", code, out)
+	}
+	code, out = call(ctx, h.Endpoints, http.MethodGet, EndpointsPath, "")
+	listed, _ := out["data"].([]any)
+	if code != http.StatusOK || len(listed) != 1 || listed[0].(map[string]any)["secret"] != nil {
+		t.Fatalf("list = %d %v", code, out)
+	}
+	if code, _ := call(ctx, h.Deliveries, http.MethodGet, DeliveriesPath, ""); code != http.StatusOK {
+		t.Fatalf("deliveries = %d", code)
+	}
+	if code, _ := call(ctx, h.Endpoints, http.MethodDelete, EndpointsPath+"?id=nope", ""); code != http.StatusNotFound {
+		t.Fatalf("delete missing = %d", code)
+	}
+	if code, _ := call(ctx, h.Endpoints, http.MethodDelete, EndpointsPath+"?id="+created["id"].(string), ""); code != http.StatusOK {
+		t.Fatalf("delete = %d", code)
+	}
+}
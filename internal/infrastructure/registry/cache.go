@@ -0,0 +1,108 @@
+package registry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// =============================================================================
+// Cache Config Type
+// =============================================================================
+
+// CacheProviderConfig is a simple config for cache providers (no protobuf needed)
+type CacheProviderConfig struct {
+	Provider string `json:"provider"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// =============================================================================
+// Cache Factory Registry Instance
+// =============================================================================
+
+var cacheRegistry = NewFactoryRegistry[ports.Cache, *CacheProviderConfig]("cache")
+
+// =============================================================================
+// Cache Provider Functions
+// =============================================================================
+
+func RegisterCacheProviderFactory(name string, factory func() ports.Cache) {
+	cacheRegistry.RegisterFactory(name, factory)
+}
+
+func GetCacheProviderFactory(name string) (func() ports.Cache, bool) {
+	return cacheRegistry.GetFactory(name)
+}
+
+func ListAvailableCacheProviderFactories() []string {
+	return cacheRegistry.ListFactories()
+}
+
+func RegisterCacheBuildFromEnv(name string, builder func() (ports.Cache, error)) {
+	cacheRegistry.RegisterBuildFromEnv(name, builder)
+}
+
+func GetCacheBuildFromEnv(name string) (func() (ports.Cache, error), bool) {
+	return cacheRegistry.GetBuildFromEnv(name)
+}
+
+func BuildCacheProviderFromEnv(name string) (ports.Cache, error) {
+	return cacheRegistry.BuildFromEnv(name)
+}
+
+func ListAvailableCacheBuildFromEnv() []string {
+	return cacheRegistry.ListBuildFromEnv()
+}
+
+// =============================================================================
+// Default Cache
+// =============================================================================
+//
+// Like the default ID generator, the cache reaches database adapters
+// through the registry: their operations are built by factories that only
+// receive a connection. The provider manager publishes the cache and its
+// per-table policy here; adapters read both on every call, so publishing
+// after repositories are built still takes effect.
+
+// CachePolicy decides which tables are cached and for how long.
+type CachePolicy struct {
+	// DefaultTTL applies to tables without an entry in TableTTLs. Zero
+	// caches only the tables listed there.
+	DefaultTTL time.Duration
+	// TableTTLs overrides DefaultTTL per table name. A zero entry turns
+	// caching off for that table.
+	TableTTLs map[string]time.Duration
+}
+
+// TTL returns how long results from tableName are kept; zero means the
+// table is not cached.
+func (p CachePolicy) TTL(tableName string) time.Duration {
+	if ttl, ok := p.TableTTLs[tableName]; ok {
+		return ttl
+	}
+	return p.DefaultTTL
+}
+
+var defaultCache = struct {
+	cache  ports.Cache
+	policy CachePolicy
+	mutex  sync.RWMutex
+}{}
+
+// SetDefaultCache publishes the process-wide cache and its policy. Passing
+// a nil cache turns database caching off.
+func SetDefaultCache(cache ports.Cache, policy CachePolicy) {
+	defaultCache.mutex.Lock()
+	defer defaultCache.mutex.Unlock()
+	defaultCache.cache = cache
+	defaultCache.policy = policy
+}
+
+// GetDefaultCache returns the published cache and policy. The cache is nil
+// when none is set.
+func GetDefaultCache() (ports.Cache, CachePolicy) {
+	defaultCache.mutex.RLock()
+	defer defaultCache.mutex.RUnlock()
+	return defaultCache.cache, defaultCache.policy
+}
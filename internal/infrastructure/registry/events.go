@@ -0,0 +1,46 @@
+package registry
+
+import (
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// =============================================================================
+// Event Publisher Factory Registry Instance
+// =============================================================================
+//
+// Event publishers configure themselves from the environment only; there is
+// no proto provider config, so the registry carries no config transformers.
+
+var eventPublisherRegistry = NewFactoryRegistry[ports.EventPublisher, any]("events")
+
+// =============================================================================
+// Event Publisher Provider Functions
+// =============================================================================
+
+func RegisterEventPublisherFactory(name string, factory func() ports.EventPublisher) {
+	eventPublisherRegistry.RegisterFactory(name, factory)
+}
+
+func GetEventPublisherFactory(name string) (func() ports.EventPublisher, bool) {
+	return eventPublisherRegistry.GetFactory(name)
+}
+
+func ListAvailableEventPublisherFactories() []string {
+	return eventPublisherRegistry.ListFactories()
+}
+
+func RegisterEventPublisherBuildFromEnv(name string, builder func() (ports.EventPublisher, error)) {
+	eventPublisherRegistry.RegisterBuildFromEnv(name, builder)
+}
+
+func GetEventPublisherBuildFromEnv(name string) (func() (ports.EventPublisher, error), bool) {
+	return eventPublisherRegistry.GetBuildFromEnv(name)
+}
+
+func BuildEventPublisherFromEnv(name string) (ports.EventPublisher, error) {
+	return eventPublisherRegistry.BuildFromEnv(name)
+}
+
+func ListAvailableEventPublisherBuildFromEnv() []string {
+	return eventPublisherRegistry.ListBuildFromEnv()
+}
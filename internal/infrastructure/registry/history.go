@@ -0,0 +1,58 @@
+package registry
+
+import "sync"
+
+// =============================================================================
+// Default History
+// =============================================================================
+//
+// Revision history keeps a snapshot of a record after every write, so its
+// past states can be listed and read back. Like the default cache and
+// shadow, the policy reaches database adapters through the registry: the
+// provider manager publishes it here and adapters read it on every call.
+
+// DefaultRevisionTable stores revisions when HistoryPolicy.RevisionTable is
+// unset.
+const DefaultRevisionTable = "entity_revision"
+
+// HistoryPolicy decides which tables keep a revision history.
+type HistoryPolicy struct {
+	// Tables lists the tables whose writes are recorded; none are by
+	// default.
+	Tables map[string]bool
+	// RevisionTable stores the revisions (default DefaultRevisionTable).
+	RevisionTable string
+}
+
+// Records reports whether writes to tableName are recorded.
+func (p HistoryPolicy) Records(tableName string) bool {
+	return p.Tables[tableName] && tableName != p.Table()
+}
+
+// Table returns the table revisions are stored in.
+func (p HistoryPolicy) Table() string {
+	if p.RevisionTable == "" {
+		return DefaultRevisionTable
+	}
+	return p.RevisionTable
+}
+
+var defaultHistory = struct {
+	policy HistoryPolicy
+	mutex  sync.RWMutex
+}{}
+
+// SetDefaultHistory publishes the history policy. A policy without tables
+// turns history off.
+func SetDefaultHistory(policy HistoryPolicy) {
+	defaultHistory.mutex.Lock()
+	defer defaultHistory.mutex.Unlock()
+	defaultHistory.policy = policy
+}
+
+// GetDefaultHistory returns the published history policy.
+func GetDefaultHistory() HistoryPolicy {
+	defaultHistory.mutex.RLock()
+	defer defaultHistory.mutex.RUnlock()
+	return defaultHistory.policy
+}
@@ -1,6 +1,8 @@
 package registry
 
 import (
+	"sync"
+
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 )
 
@@ -74,3 +76,36 @@ func RegisterIDProvider(name string, factory func() ports.IDGenerator, transform
 		RegisterIDConfigTransformer(name, transformer)
 	}
 }
+
+// =============================================================================
+// Default ID Generator
+// =============================================================================
+//
+// Database adapters build their operations from init()-registered factories
+// that only receive a connection, so they cannot be handed the container's
+// ID service directly. The provider manager publishes the active generator
+// here once the ID provider is built; adapters read it at Create time.
+
+var defaultIDGenerator = struct {
+	gen   ports.IDGenerator
+	mutex sync.RWMutex
+}{}
+
+// SetDefaultIDGenerator publishes the process-wide ID generator. Passing nil
+// clears it, sending adapters back to their own fallback.
+func SetDefaultIDGenerator(gen ports.IDGenerator) {
+	defaultIDGenerator.mutex.Lock()
+	defer defaultIDGenerator.mutex.Unlock()
+	defaultIDGenerator.gen = gen
+}
+
+// GetDefaultIDGenerator returns the published ID generator, or nil when none
+// is set or the published one is disabled (e.g. the NoOp fallback).
+func GetDefaultIDGenerator() ports.IDGenerator {
+	defaultIDGenerator.mutex.RLock()
+	defer defaultIDGenerator.mutex.RUnlock()
+	if defaultIDGenerator.gen == nil || !defaultIDGenerator.gen.IsEnabled() {
+		return nil
+	}
+	return defaultIDGenerator.gen
+}
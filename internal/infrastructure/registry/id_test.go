@@ -0,0 +1,45 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/ports"
+)
+
+// fixedIDGenerator returns id and reports enabled.
+type fixedIDGenerator struct {
+	id      string
+	enabled bool
+}
+
+func (g *fixedIDGenerator) GenerateID() string                        { return g.id }
+func (g *fixedIDGenerator) GenerateIDWithPrefix(prefix string) string { return prefix + "_" + g.id }
+func (g *fixedIDGenerator) IsEnabled() bool                           { return g.enabled }
+func (g *fixedIDGenerator) GetProviderInfo() string                   { return "fixed" }
+
+func TestDefaultIDGenerator(t *testing.T) {
+	t.Cleanup(func() { SetDefaultIDGenerator(nil) })
+
+	if gen := GetDefaultIDGenerator(); gen != nil {
+		t.Fatalf("default generator = %v before one is published", gen)
+	}
+
+	enabled := &fixedIDGenerator{id: "0190a6b2-7c3e-7000-8000-000000000001", enabled: true}
+	SetDefaultIDGenerator(enabled)
+	if gen := GetDefaultIDGenerator(); gen != ports.IDGenerator(enabled) {
+		t.Errorf("default generator = %v, want the published one", gen)
+	}
+
+	// A disabled generator (e.g. the NoOp fallback) is hidden so adapters
+	// use their own fallback.
+	SetDefaultIDGenerator(&fixedIDGenerator{id: "ignored", enabled: false})
+	if gen := GetDefaultIDGenerator(); gen != nil {
+		t.Errorf("disabled generator was returned: %v", gen)
+	}
+
+	SetDefaultIDGenerator(enabled)
+	SetDefaultIDGenerator(nil)
+	if gen := GetDefaultIDGenerator(); gen != nil {
+		t.Errorf("default generator = %v after clearing", gen)
+	}
+}
@@ -0,0 +1,46 @@
+package registry
+
+import (
+	"github.com/erniealice/espyna-golang/internal/application/ports/integration"
+)
+
+// =============================================================================
+// Messaging Factory Registry Instance
+// =============================================================================
+//
+// Messaging providers configure themselves from the environment only; there is no
+// proto provider config, so the registry carries no config transformers.
+
+var messagingRegistry = NewFactoryRegistry[integration.MessagingProvider, any]("messaging")
+
+// =============================================================================
+// Messaging Provider Functions
+// =============================================================================
+
+func RegisterMessagingProviderFactory(name string, factory func() integration.MessagingProvider) {
+	messagingRegistry.RegisterFactory(name, factory)
+}
+
+func GetMessagingProviderFactory(name string) (func() integration.MessagingProvider, bool) {
+	return messagingRegistry.GetFactory(name)
+}
+
+func ListAvailableMessagingProviderFactories() []string {
+	return messagingRegistry.ListFactories()
+}
+
+func RegisterMessagingBuildFromEnv(name string, builder func() (integration.MessagingProvider, error)) {
+	messagingRegistry.RegisterBuildFromEnv(name, builder)
+}
+
+func GetMessagingBuildFromEnv(name string) (func() (integration.MessagingProvider, error), bool) {
+	return messagingRegistry.GetBuildFromEnv(name)
+}
+
+func BuildMessagingProviderFromEnv(name string) (integration.MessagingProvider, error) {
+	return messagingRegistry.BuildFromEnv(name)
+}
+
+func ListAvailableMessagingBuildFromEnv() []string {
+	return messagingRegistry.ListBuildFromEnv()
+}
@@ -0,0 +1,47 @@
+package registry
+
+import "sync"
+
+// =============================================================================
+// Payment Dispute Repository Factory Registry
+// =============================================================================
+//
+// Provides self-registration for the PaymentDisputeRepository
+// implementation. The postgres adapter registers its concrete
+// PostgresPaymentDisputeRepository at init() time via
+// RegisterPaymentDisputeRepositoryFactory, and the integration initializer
+// resolves it at runtime without importing the build-tagged adapter.
+//
+// Same `any`-typed shape as the payment fee query factory
+// (payment_fee_query.go).
+//
+// =============================================================================
+
+// paymentDisputeRepositoryRegistry holds the registered dispute repository factory.
+var paymentDisputeRepositoryRegistry = struct {
+	factory func(db any) any
+	mutex   sync.RWMutex
+}{}
+
+// RegisterPaymentDisputeRepositoryFactory registers a factory for creating a
+// PaymentDisputeRepository from a database connection.
+// Called from init() in contrib/postgres/internal/adapter/integration/payment_dispute.go.
+func RegisterPaymentDisputeRepositoryFactory(factory func(db any) any) {
+	paymentDisputeRepositoryRegistry.mutex.Lock()
+	defer paymentDisputeRepositoryRegistry.mutex.Unlock()
+
+	if factory == nil {
+		panic("RegisterPaymentDisputeRepositoryFactory: factory is nil")
+	}
+	paymentDisputeRepositoryRegistry.factory = factory
+}
+
+// GetPaymentDisputeRepositoryFactory retrieves the registered dispute
+// repository factory. Returns (factory, true) if registered, (nil, false)
+// otherwise.
+func GetPaymentDisputeRepositoryFactory() (func(db any) any, bool) {
+	paymentDisputeRepositoryRegistry.mutex.RLock()
+	defer paymentDisputeRepositoryRegistry.mutex.RUnlock()
+
+	return paymentDisputeRepositoryRegistry.factory, paymentDisputeRepositoryRegistry.factory != nil
+}
@@ -0,0 +1,46 @@
+package registry
+
+import "sync"
+
+// =============================================================================
+// Payment Fee Query Factory Registry
+// =============================================================================
+//
+// Provides self-registration for the PaymentFeeQueryService implementation.
+// The postgres adapter registers its concrete PostgresPaymentFeeRepository
+// at init() time via RegisterPaymentFeeQueryFactory, and the reporting
+// initializer resolves it at runtime without importing the build-tagged
+// adapter.
+//
+// Same `any`-typed shape as the staff workload query factory
+// (workload_query.go).
+//
+// =============================================================================
+
+// paymentFeeQueryRegistry holds the registered payment fee query factory.
+var paymentFeeQueryRegistry = struct {
+	factory func(db any) any
+	mutex   sync.RWMutex
+}{}
+
+// RegisterPaymentFeeQueryFactory registers a factory for creating a
+// PaymentFeeQueryService from a database connection.
+// Called from init() in contrib/postgres/internal/adapter/integration/payment_fee_report.go.
+func RegisterPaymentFeeQueryFactory(factory func(db any) any) {
+	paymentFeeQueryRegistry.mutex.Lock()
+	defer paymentFeeQueryRegistry.mutex.Unlock()
+
+	if factory == nil {
+		panic("RegisterPaymentFeeQueryFactory: factory is nil")
+	}
+	paymentFeeQueryRegistry.factory = factory
+}
+
+// GetPaymentFeeQueryFactory retrieves the registered payment fee query
+// factory. Returns (factory, true) if registered, (nil, false) otherwise.
+func GetPaymentFeeQueryFactory() (func(db any) any, bool) {
+	paymentFeeQueryRegistry.mutex.RLock()
+	defer paymentFeeQueryRegistry.mutex.RUnlock()
+
+	return paymentFeeQueryRegistry.factory, paymentFeeQueryRegistry.factory != nil
+}
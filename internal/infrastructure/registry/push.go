@@ -0,0 +1,46 @@
+package registry
+
+import (
+	"github.com/erniealice/espyna-golang/internal/application/ports/integration"
+)
+
+// =============================================================================
+// Push Factory Registry Instance
+// =============================================================================
+//
+// Push providers configure themselves from the environment only; there is no
+// proto provider config, so the registry carries no config transformers.
+
+var pushRegistry = NewFactoryRegistry[integration.PushProvider, any]("push")
+
+// =============================================================================
+// Push Provider Functions
+// =============================================================================
+
+func RegisterPushProviderFactory(name string, factory func() integration.PushProvider) {
+	pushRegistry.RegisterFactory(name, factory)
+}
+
+func GetPushProviderFactory(name string) (func() integration.PushProvider, bool) {
+	return pushRegistry.GetFactory(name)
+}
+
+func ListAvailablePushProviderFactories() []string {
+	return pushRegistry.ListFactories()
+}
+
+func RegisterPushBuildFromEnv(name string, builder func() (integration.PushProvider, error)) {
+	pushRegistry.RegisterBuildFromEnv(name, builder)
+}
+
+func GetPushBuildFromEnv(name string) (func() (integration.PushProvider, error), bool) {
+	return pushRegistry.GetBuildFromEnv(name)
+}
+
+func BuildPushProviderFromEnv(name string) (integration.PushProvider, error) {
+	return pushRegistry.BuildFromEnv(name)
+}
+
+func ListAvailablePushBuildFromEnv() []string {
+	return pushRegistry.ListBuildFromEnv()
+}
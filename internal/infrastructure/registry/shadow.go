@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Default Shadow
+// =============================================================================
+//
+// Shadow reads replay reads served by the primary database against a
+// candidate provider, such as Postgres while Firestore is still primary,
+// and report where the two disagree. Like the default cache, the candidate
+// reaches database adapters through the registry: the provider manager
+// publishes it here and adapters read it on every call.
+
+// ShadowPolicy decides which reads are shadowed and how results compare.
+type ShadowPolicy struct {
+	// Candidate is the name of the candidate provider, e.g. "postgresql".
+	Candidate string
+	// Tables limits shadowing to the listed tables; nil shadows every
+	// table.
+	Tables map[string]bool
+	// SampleRate is the fraction of reads shadowed, from 0 to 1.
+	SampleRate float64
+	// Timeout bounds each candidate read.
+	Timeout time.Duration
+	// MaxInFlight bounds concurrent candidate reads; reads past it are
+	// skipped rather than queued.
+	MaxInFlight int
+	// IgnoreFields are left out of comparisons, such as timestamps the
+	// providers store at different precisions.
+	IgnoreFields map[string]bool
+}
+
+// Shadows reports whether reads of tableName are shadowed at all.
+func (p ShadowPolicy) Shadows(tableName string) bool {
+	if p.SampleRate <= 0 {
+		return false
+	}
+	return p.Tables == nil || p.Tables[tableName]
+}
+
+var defaultShadow = struct {
+	candidate any
+	policy    ShadowPolicy
+	mutex     sync.RWMutex
+}{}
+
+// SetDefaultShadow publishes the candidate database operations (a
+// DatabaseOperation) and the shadow policy. Passing a nil candidate turns
+// shadowing off.
+func SetDefaultShadow(candidate any, policy ShadowPolicy) {
+	defaultShadow.mutex.Lock()
+	defer defaultShadow.mutex.Unlock()
+	defaultShadow.candidate = candidate
+	defaultShadow.policy = policy
+}
+
+// GetDefaultShadow returns the published candidate and policy. The
+// candidate is nil when shadowing is off.
+func GetDefaultShadow() (any, ShadowPolicy) {
+	defaultShadow.mutex.RLock()
+	defer defaultShadow.mutex.RUnlock()
+	return defaultShadow.candidate, defaultShadow.policy
+}
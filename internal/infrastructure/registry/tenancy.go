@@ -0,0 +1,67 @@
+package registry
+
+import "sync"
+
+// =============================================================================
+// Default Tenancy
+// =============================================================================
+//
+// The workspace guard scopes every read and write of a tenant table to the
+// workspace of the request identity, so a use case that forgets a
+// workspace filter cannot reach another workspace's rows. SQL adapters
+// scope the tables that have a workspace_id column, except the configured
+// global tables; schemaless ones (Firestore) scope every table but the
+// default and configured global tables once the policy is enabled. Like
+// the default cache and history, the
+// policy reaches database adapters through the registry: the provider
+// manager publishes it here and adapters read it on every call.
+
+// DefaultGlobalTables are shared by every workspace and never scoped.
+var DefaultGlobalTables = map[string]bool{
+	"workspace":           true,
+	"user":                true,
+	"session":             true,
+	"workspace_user_role": true,
+	"account_group":       true,
+}
+
+// TenancyPolicy decides which tables the workspace guard scopes.
+type TenancyPolicy struct {
+	// Enabled turns the guard on for backends that cannot tell from their
+	// schema which tables belong to a workspace. SQL backends scope the
+	// tables with a workspace_id column either way.
+	Enabled bool
+	// GlobalTables are shared by every workspace, in addition to
+	// DefaultGlobalTables. SQL backends leave them unscoped even when they
+	// have a workspace_id column.
+	GlobalTables map[string]bool
+}
+
+// Global reports whether tableName is shared by every workspace.
+func (p TenancyPolicy) Global(tableName string) bool {
+	return DefaultGlobalTables[tableName] || p.GlobalTables[tableName]
+}
+
+// Scopes reports whether a schemaless backend scopes tableName.
+func (p TenancyPolicy) Scopes(tableName string) bool {
+	return p.Enabled && !p.Global(tableName)
+}
+
+var defaultTenancy = struct {
+	policy TenancyPolicy
+	mutex  sync.RWMutex
+}{}
+
+// SetDefaultTenancy publishes the tenancy policy.
+func SetDefaultTenancy(policy TenancyPolicy) {
+	defaultTenancy.mutex.Lock()
+	defer defaultTenancy.mutex.Unlock()
+	defaultTenancy.policy = policy
+}
+
+// GetDefaultTenancy returns the published tenancy policy.
+func GetDefaultTenancy() TenancyPolicy {
+	defaultTenancy.mutex.RLock()
+	defer defaultTenancy.mutex.RUnlock()
+	return defaultTenancy.policy
+}
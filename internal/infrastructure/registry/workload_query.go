@@ -0,0 +1,45 @@
+package registry
+
+import "sync"
+
+// =============================================================================
+// Staff Workload Query Factory Registry
+// =============================================================================
+//
+// Provides self-registration for the StaffWorkloadQueryService
+// implementation. The postgres adapter registers its concrete
+// PostgresStaffWorkloadRepository at init() time via
+// RegisterStaffWorkloadQueryFactory, and the reporting initializer resolves
+// it at runtime without importing the build-tagged adapter.
+//
+// Same `any`-typed shape as the assignee query factory (assignee_query.go).
+//
+// =============================================================================
+
+// staffWorkloadQueryRegistry holds the registered staff workload query factory.
+var staffWorkloadQueryRegistry = struct {
+	factory func(db any) any
+	mutex   sync.RWMutex
+}{}
+
+// RegisterStaffWorkloadQueryFactory registers a factory for creating a
+// StaffWorkloadQueryService from a database connection.
+// Called from init() in contrib/postgres/internal/adapter/entity/staff_workload.go.
+func RegisterStaffWorkloadQueryFactory(factory func(db any) any) {
+	staffWorkloadQueryRegistry.mutex.Lock()
+	defer staffWorkloadQueryRegistry.mutex.Unlock()
+
+	if factory == nil {
+		panic("RegisterStaffWorkloadQueryFactory: factory is nil")
+	}
+	staffWorkloadQueryRegistry.factory = factory
+}
+
+// GetStaffWorkloadQueryFactory retrieves the registered staff workload query
+// factory. Returns (factory, true) if registered, (nil, false) otherwise.
+func GetStaffWorkloadQueryFactory() (func(db any) any, bool) {
+	staffWorkloadQueryRegistry.mutex.RLock()
+	defer staffWorkloadQueryRegistry.mutex.RUnlock()
+
+	return staffWorkloadQueryRegistry.factory, staffWorkloadQueryRegistry.factory != nil
+}
@@ -135,7 +135,7 @@ func (uc *AdvanceWorkflowUseCase) Execute(ctx context.Context, req *enginepb.Adv
 		}
 
 		// Create activity instances from templates
-		now := time.Now()
+		now := uc.services.now()
 		for _, activityTemplate := range activityTemplates {
 			activityID := uc.services.IDGenerator.GenerateID()
 			activity := &activitypb.Activity{
@@ -187,8 +187,7 @@ func (uc *AdvanceWorkflowUseCase) Execute(ctx context.Context, req *enginepb.Adv
 
 	// 4. Current Stage is Done. Close it.
 	if currentStage.Status != "completed" {
-		currentStage.Status = "completed"
-		uc.repositories.Stage.UpdateStage(ctx, &stagepb.UpdateStageRequest{Data: currentStage})
+		completeStage(ctx, uc.repositories.Stage, currentStage)
 	}
 
 	// 5. Find Next Stage Template via cache
@@ -236,7 +235,7 @@ func (uc *AdvanceWorkflowUseCase) Execute(ctx context.Context, req *enginepb.Adv
 
 	// 6. Create Next Stage
 	newStageId := uc.services.IDGenerator.GenerateID()
-	now := time.Now()
+	now := uc.services.now()
 	newStage := &stagepb.Stage{
 		Id:              newStageId,
 		WorkflowId:      workflow.Id,
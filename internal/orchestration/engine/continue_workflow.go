@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
 
 	commonpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/common"
 	activitypb "github.com/erniealice/esqyma/pkg/schema/v1/domain/workflow/activity"
@@ -154,7 +153,7 @@ func (uc *ContinueWorkflowUseCase) Execute(ctx context.Context, req *enginepb.Co
 	uc.repositories.Workflow.UpdateWorkflow(ctx, &workflowpb.UpdateWorkflowRequest{Data: workflow})
 
 	// 8. Mark activity as completed
-	now := time.Now()
+	now := uc.services.now()
 	activity.Status = "completed"
 	activity.DateCompleted = &[]int64{now.UnixMilli()}[0]
 
@@ -183,8 +182,7 @@ func (uc *ContinueWorkflowUseCase) Execute(ctx context.Context, req *enginepb.Co
 		})
 		if stageRes != nil && len(stageRes.Data) > 0 {
 			stage := stageRes.Data[0]
-			stage.Status = "completed"
-			uc.repositories.Stage.UpdateStage(ctx, &stagepb.UpdateStageRequest{Data: stage})
+			completeStage(ctx, uc.repositories.Stage, stage)
 
 			// Try to create next stage
 			nextStage := uc.createNextStage(ctx, workflow, stage.StageTemplateId)
@@ -308,7 +306,7 @@ func (uc *ContinueWorkflowUseCase) createNextStage(ctx context.Context, workflow
 	}
 
 	// Create new stage
-	now := time.Now()
+	now := uc.services.now()
 	newStage := &stagepb.Stage{
 		Id:              uc.services.IDGenerator.GenerateID(),
 		WorkflowId:      workflow.Id,
@@ -340,7 +338,7 @@ func (uc *ContinueWorkflowUseCase) createStageActivities(ctx context.Context, st
 	}
 
 	var firstPendingId string
-	now := time.Now()
+	now := uc.services.now()
 
 	for _, template := range activityTemplates {
 		activity := &activitypb.Activity{
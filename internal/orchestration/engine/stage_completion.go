@@ -0,0 +1,25 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	stagepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/workflow/stage"
+)
+
+// completeStage marks stage completed through the repository. The engine
+// completes stages directly rather than through the UpdateStage use case, so
+// it announces stage.completed itself, once, on the update completing it.
+func completeStage(ctx context.Context, stages stagepb.StageDomainServiceServer, stage *stagepb.Stage) {
+	alreadyCompleted := stage.GetStatus() == "completed" || stage.GetDateCompleted() > 0
+	stage.Status = "completed"
+	res, err := stages.UpdateStage(ctx, &stagepb.UpdateStageRequest{Data: stage})
+	if err != nil || alreadyCompleted {
+		return
+	}
+	completed := stage
+	if data := res.GetData(); len(data) > 0 {
+		completed = data[0]
+	}
+	domainevent.Emit(ctx, domainevent.StageCompleted, completed)
+}
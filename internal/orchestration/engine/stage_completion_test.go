@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erniealice/espyna-golang/internal/application/shared/domainevent"
+	activitypb "github.com/erniealice/esqyma/pkg/schema/v1/domain/workflow/activity"
+	stagepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/workflow/stage"
+	stagetemplatepb "github.com/erniealice/esqyma/pkg/schema/v1/domain/workflow/stage_template"
+	workflowpb "github.com/erniealice/esqyma/pkg/schema/v1/domain/workflow/workflow"
+	enginepb "github.com/erniealice/esqyma/pkg/schema/v1/orchestration/engine"
+)
+
+// oneStageWorkflow is a workflow whose only stage has all its activities
+// done; the stage is stored by pointer so updates stick.
+type oneStageWorkflow struct {
+	workflowpb.WorkflowDomainServiceServer
+	stagepb.StageDomainServiceServer
+	activitypb.ActivityDomainServiceServer
+	stagetemplatepb.StageTemplateDomainServiceServer
+
+	stage        *stagepb.Stage
+	stageUpdates int
+}
+
+func (f *oneStageWorkflow) ReadWorkflow(context.Context, *workflowpb.ReadWorkflowRequest) (*workflowpb.ReadWorkflowResponse, error) {
+	templateID := "wft-1"
+	return &workflowpb.ReadWorkflowResponse{Success: true, Data: []*workflowpb.Workflow{{Id: "wf-1", WorkflowTemplateId: &templateID}}}, nil
+}
+
+func (f *oneStageWorkflow) UpdateWorkflow(_ context.Context, req *workflowpb.UpdateWorkflowRequest) (*workflowpb.UpdateWorkflowResponse, error) {
+	return &workflowpb.UpdateWorkflowResponse{Success: true, Data: []*workflowpb.Workflow{req.Data}}, nil
+}
+
+func (f *oneStageWorkflow) ListStages(context.Context, *stagepb.ListStagesRequest) (*stagepb.ListStagesResponse, error) {
+	return &stagepb.ListStagesResponse{Success: true, Data: []*stagepb.Stage{f.stage}}, nil
+}
+
+func (f *oneStageWorkflow) UpdateStage(_ context.Context, req *stagepb.UpdateStageRequest) (*stagepb.UpdateStageResponse, error) {
+	f.stageUpdates++
+	f.stage = req.Data
+	return &stagepb.UpdateStageResponse{Success: true, Data: []*stagepb.Stage{req.Data}}, nil
+}
+
+func (f *oneStageWorkflow) ListActivities(context.Context, *activitypb.ListActivitiesRequest) (*activitypb.ListActivitiesResponse, error) {
+	return &activitypb.ListActivitiesResponse{Success: true, Data: []*activitypb.Activity{{Id: "act-1", StageId: f.stage.Id, Status: "completed"}}}, nil
+}
+
+func (f *oneStageWorkflow) ListStageTemplates(context.Context, *stagetemplatepb.ListStageTemplatesRequest) (*stagetemplatepb.ListStageTemplatesResponse, error) {
+	order := int32(1)
+	return &stagetemplatepb.ListStageTemplatesResponse{Success: true, Data: []*stagetemplatepb.StageTemplate{{Id: "st-1", OrderIndex: &order}}}, nil
+}
+
+// recordingSink keeps the events published to it.
+type recordingSink struct{ events []domainevent.Event }
+
+func (s *recordingSink) PublishDomainEvent(e domainevent.Event) { s.events = append(s.events, e) }
+
+func TestAdvanceWorkflow_EmitsStageCompleted(t *testing.T) {
+	sink := &recordingSink{}
+	domainevent.SetSink("test", sink)
+	defer domainevent.SetSink("test", nil)
+
+	f := &oneStageWorkflow{stage: &stagepb.Stage{Id: "stg-1", WorkflowId: "wf-1", StageTemplateId: "st-1", Status: "in_progress"}}
+	repos := EngineRepositories{Workflow: f, Stage: f, Activity: f, StageTemplate: f}
+	uc := NewAdvanceWorkflowUseCase(repos, EngineServices{}, NewTemplateCache(repos))
+	ctx := context.Background()
+
+	resp, err := uc.Execute(ctx, &enginepb.AdvanceWorkflowRequest{WorkflowId: "wf-1"})
+	if err != nil || !resp.WorkflowCompleted {
+		t.Fatalf("advance = %+v, %v", resp, err)
+	}
+	if len(sink.events) != 1 || sink.events[0].Type != domainevent.StageCompleted {
+		t.Fatalf("events = %+v, want one stage.completed", sink.events)
+	}
+	if stage, _ := sink.events[0].Data.(*stagepb.Stage); stage.GetId() != "stg-1" || stage.GetStatus() != "completed" {
+		t.Errorf("event data = %+v", sink.events[0].Data)
+	}
+
+	// Advancing again finds the stage completed: no second update or event.
+	if _, err := uc.Execute(ctx, &enginepb.AdvanceWorkflowRequest{WorkflowId: "wf-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if f.stageUpdates != 1 || len(sink.events) != 1 {
+		t.Errorf("after re-advancing: %d stage updates, %d events", f.stageUpdates, len(sink.events))
+	}
+}
+
+func TestCompleteStage_AlreadyCompleted(t *testing.T) {
+	sink := &recordingSink{}
+	domainevent.SetSink("test", sink)
+	defer domainevent.SetSink("test", nil)
+
+	f := &oneStageWorkflow{}
+	completeStage(context.Background(), f, &stagepb.Stage{Id: "stg-1", Status: "completed"})
+	if f.stageUpdates != 1 || len(sink.events) != 0 {
+		t.Errorf("%d updates, %d events; want the update without an event", f.stageUpdates, len(sink.events))
+	}
+}
@@ -67,7 +67,7 @@ func (uc *StartWorkflowFromTemplateUseCase) Execute(ctx context.Context, req *en
 	// 3. Create Workflow instance
 	t2 := time.Now()
 	workflowID := uc.services.IDGenerator.GenerateID()
-	now := time.Now()
+	now := uc.services.now()
 
 	// Wrap input under "input" key for YAML workflow JSONPath access ($.input.field)
 	wrappedContextJson := fmt.Sprintf(`{"input":%s}`, validatedInputJson)
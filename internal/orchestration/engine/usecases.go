@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"time"
 
 	"github.com/erniealice/espyna-golang/internal/application/ports"
 
@@ -31,6 +32,15 @@ type EngineServices struct {
 	Translator       ports.Translator
 	IDGenerator      ports.IDGenerator
 	ExecutorRegistry ports.ExecutorRegistry
+
+	// Clock stamps workflow, stage and activity timestamps. nil means
+	// wall-clock time.
+	Clock ports.Clock
+}
+
+// now returns the engine's current time from Clock.
+func (s EngineServices) now() time.Time {
+	return ports.ClockNow(s.Clock)
 }
 
 // EngineUseCases contains all workflow engine-related use cases and implements
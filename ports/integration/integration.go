@@ -9,6 +9,7 @@ import (
 type (
 	TabularSourceProvider = internal.TabularSourceProvider
 	SpreadsheetExtensions = internal.SpreadsheetExtensions
+	TabularRecordStreamer = internal.TabularRecordStreamer
 	TabularOptions        = internal.TabularOptions
 	TabularRecord         = internal.TabularRecord
 	TabularSelection      = internal.TabularSelection
@@ -22,8 +23,32 @@ type (
 	PaymentProvider              = internal.PaymentProvider
 	PaymentWebhookResult         = internal.PaymentWebhookResult
 	CheckoutSessionParams        = internal.CheckoutSessionParams
+	PaymentFees                  = internal.PaymentFees
 )
 
+// Payment dispute types
+type (
+	PaymentDispute           = internal.PaymentDispute
+	PaymentDisputeStatus     = internal.PaymentDisputeStatus
+	PaymentDisputeEvidence   = internal.PaymentDisputeEvidence
+	PaymentDisputeDocument   = internal.PaymentDisputeDocument
+	PaymentDisputeFilter     = internal.PaymentDisputeFilter
+	PaymentDisputeRepository = internal.PaymentDisputeRepository
+	DisputeProvider          = internal.DisputeProvider
+	DisputeEvidenceSubmitter = internal.DisputeEvidenceSubmitter
+)
+
+const (
+	PaymentDisputeOpen             = internal.PaymentDisputeOpen
+	PaymentDisputeEvidenceRequired = internal.PaymentDisputeEvidenceRequired
+	PaymentDisputeUnderReview      = internal.PaymentDisputeUnderReview
+	PaymentDisputeWon              = internal.PaymentDisputeWon
+	PaymentDisputeLost             = internal.PaymentDisputeLost
+	PaymentDisputeClosed           = internal.PaymentDisputeClosed
+)
+
+var PaymentFeesFromRawData = internal.PaymentFeesFromRawData
+
 // Email types
 type (
 	EmailProvider = internal.EmailProvider
@@ -57,12 +57,16 @@ const (
 
 // Storage types
 type (
-	StorageProvider           = internal.StorageProvider
-	StorageCapability         = internal.StorageCapability
-	StorageCapabilityProvider = internal.StorageCapabilityProvider
-	StreamingStorageProvider  = internal.StreamingStorageProvider
-	StorageError              = internal.StorageError
-	StorageConfigAdapter      = internal.StorageConfigAdapter
+	StorageProvider               = internal.StorageProvider
+	StorageCapability             = internal.StorageCapability
+	StorageCapabilityProvider     = internal.StorageCapabilityProvider
+	StreamingStorageProvider      = internal.StreamingStorageProvider
+	MultipartStorageProvider      = internal.MultipartStorageProvider
+	ObjectDeletingStorageProvider = internal.ObjectDeletingStorageProvider
+	MultipartUpload               = internal.MultipartUpload
+	CompletedPart                 = internal.CompletedPart
+	StorageError                  = internal.StorageError
+	StorageConfigAdapter          = internal.StorageConfigAdapter
 )
 
 var NewStorageConfigAdapter = internal.NewStorageConfigAdapter
@@ -107,6 +111,46 @@ type NoOpIDGenerator = internal.NoOpIDGenerator
 
 var NewNoOpIDGenerator = internal.NewNoOpIDGenerator
 
+// Clock types
+type Clock = internal.Clock
+type SystemClock = internal.SystemClock
+type TravelClock = internal.TravelClock
+
+var (
+	NewSystemClock = internal.NewSystemClock
+	NewTravelClock = internal.NewTravelClock
+	ClockNow       = internal.ClockNow
+)
+
+// Cache types
+type Cache = internal.Cache
+
+// Event publisher types
+type EventPublisher = internal.EventPublisher
+type EventMessage = internal.EventMessage
+
+// Job queue types
+type (
+	JobQueue       = internal.JobQueue
+	Job            = internal.Job
+	JobStatus      = internal.JobStatus
+	EnqueueRequest = internal.EnqueueRequest
+	JobFilter      = internal.JobFilter
+)
+
+const (
+	JobPending            = internal.JobPending
+	JobRunning            = internal.JobRunning
+	JobSucceeded          = internal.JobSucceeded
+	JobDead               = internal.JobDead
+	DefaultJobMaxAttempts = internal.DefaultJobMaxAttempts
+)
+
+var (
+	ErrJobNotFound = internal.ErrJobNotFound
+	ErrJobNotDead  = internal.ErrJobNotDead
+)
+
 // Transaction types
 type Transactor = internal.Transactor
 type NoOpTransactor = internal.NoOpTransactor
@@ -150,19 +194,129 @@ type (
 
 var FromProtoMessage = internal.FromProtoMessage
 
+// Notification types
+type (
+	Notification            = internal.Notification
+	NotificationChannel     = internal.NotificationChannel
+	NotificationMessage     = internal.NotificationMessage
+	Notifier                = internal.Notifier
+	NotificationSender      = internal.NotificationSender
+	NotificationPreference  = internal.NotificationPreference
+	NotificationPreferences = internal.NotificationPreferences
+)
+
+// In-app notification inbox types
+type (
+	InboxNotification       = internal.InboxNotification
+	NotificationInbox       = internal.NotificationInbox
+	NotificationInboxFilter = internal.NotificationInboxFilter
+	NotificationInboxPage   = internal.NotificationInboxPage
+)
+
+// Push types
+type (
+	PushProvider     = internal.PushProvider
+	PushPlatform     = internal.PushPlatform
+	PushMessage      = internal.PushMessage
+	DeviceToken      = internal.DeviceToken
+	DeviceTokenStore = internal.DeviceTokenStore
+)
+
+var ErrPushTokenInvalid = internal.ErrPushTokenInvalid
+
+// Text messaging (SMS, WhatsApp) types
+type (
+	MessagingProvider    = internal.MessagingProvider
+	MessageStatus        = internal.MessageStatus
+	TextMessage          = internal.TextMessage
+	SentMessage          = internal.SentMessage
+	MessageStatusWebhook = internal.MessageStatusWebhook
+	MessageStatusUpdate  = internal.MessageStatusUpdate
+	NotificationLogEntry = internal.NotificationLogEntry
+	NotificationLog      = internal.NotificationLog
+)
+
+var ErrMessagingSignature = internal.ErrMessagingSignature
+
+// Message delivery states
+const (
+	MessageStatusQueued      = internal.MessageStatusQueued
+	MessageStatusSent        = internal.MessageStatusSent
+	MessageStatusDelivered   = internal.MessageStatusDelivered
+	MessageStatusRead        = internal.MessageStatusRead
+	MessageStatusUndelivered = internal.MessageStatusUndelivered
+	MessageStatusFailed      = internal.MessageStatusFailed
+)
+
+const (
+	NotificationChannelEmail    = internal.NotificationChannelEmail
+	NotificationChannelPush     = internal.NotificationChannelPush
+	NotificationChannelInApp    = internal.NotificationChannelInApp
+	NotificationChannelSMS      = internal.NotificationChannelSMS
+	NotificationChannelWhatsApp = internal.NotificationChannelWhatsApp
+	NotificationChannelNone     = internal.NotificationChannelNone
+	PushPlatformAndroid         = internal.PushPlatformAndroid
+	PushPlatformIOS             = internal.PushPlatformIOS
+)
+
 // Payment types
 type (
-	PaymentProvider       = internal.PaymentProvider
-	PaymentWebhookResult  = internal.PaymentWebhookResult
-	CheckoutSessionParams = internal.CheckoutSessionParams
+	PaymentProvider            = internal.PaymentProvider
+	PaymentTransactionSearcher = internal.PaymentTransactionSearcher
+	PaymentWebhookResult       = internal.PaymentWebhookResult
+	CheckoutSessionParams      = internal.CheckoutSessionParams
+	PaymentFees                = internal.PaymentFees
+)
+
+// Payment dispute types
+type (
+	PaymentDispute           = internal.PaymentDispute
+	PaymentDisputeStatus     = internal.PaymentDisputeStatus
+	PaymentDisputeEvidence   = internal.PaymentDisputeEvidence
+	PaymentDisputeDocument   = internal.PaymentDisputeDocument
+	PaymentDisputeFilter     = internal.PaymentDisputeFilter
+	PaymentDisputeRepository = internal.PaymentDisputeRepository
+	DisputeProvider          = internal.DisputeProvider
+	DisputeEvidenceSubmitter = internal.DisputeEvidenceSubmitter
+)
+
+const (
+	PaymentDisputeOpen             = internal.PaymentDisputeOpen
+	PaymentDisputeEvidenceRequired = internal.PaymentDisputeEvidenceRequired
+	PaymentDisputeUnderReview      = internal.PaymentDisputeUnderReview
+	PaymentDisputeWon              = internal.PaymentDisputeWon
+	PaymentDisputeLost             = internal.PaymentDisputeLost
+	PaymentDisputeClosed           = internal.PaymentDisputeClosed
+)
+
+// Provider fee breakdown keys on PaymentTransaction.RawData
+const (
+	PaymentRawGrossAmount = internal.PaymentRawGrossAmount
+	PaymentRawFeeAmount   = internal.PaymentRawFeeAmount
+	PaymentRawNetAmount   = internal.PaymentRawNetAmount
+	PaymentRawFeeCurrency = internal.PaymentRawFeeCurrency
 )
 
+var PaymentFeesFromRawData = internal.PaymentFeesFromRawData
+
 // Scheduler types
 type (
-	SchedulerProvider       = internal.SchedulerProvider
-	ScheduleWebhookResult   = internal.ScheduleWebhookResult
-	CreateScheduleParams    = internal.CreateScheduleParams
-	CheckAvailabilityParams = internal.CheckAvailabilityParams
+	SchedulerProvider            = internal.SchedulerProvider
+	ScheduleWebhookResult        = internal.ScheduleWebhookResult
+	CreateScheduleParams         = internal.CreateScheduleParams
+	CheckAvailabilityParams      = internal.CheckAvailabilityParams
+	ScheduleInviteSender         = internal.ScheduleInviteSender
+	ScheduleInviteeLister        = internal.ScheduleInviteeLister
+	SchedulerWebhookManager      = internal.SchedulerWebhookManager
+	SchedulerWebhookSubscription = internal.SchedulerWebhookSubscription
+	SchedulerWebhookState        = internal.SchedulerWebhookState
+)
+
+// Scheduler webhook subscription states
+const (
+	SchedulerWebhookActive   = internal.SchedulerWebhookActive
+	SchedulerWebhookDisabled = internal.SchedulerWebhookDisabled
+	SchedulerWebhookMissing  = internal.SchedulerWebhookMissing
 )
 
 // Tabular types
@@ -10,8 +10,11 @@
 //   - Storage: provider factory, config transformer, BuildFromEnv
 //   - Auth: provider factory, config transformer, BuildFromEnv
 //   - Email: provider factory, config transformer, BuildFromEnv
+//   - Push: provider factory, BuildFromEnv
 //   - Tabular: provider factory, config transformer, BuildFromEnv
 //   - Server: provider factory, BuildFromEnv
+//   - Cache: provider factory, BuildFromEnv, default cache for database adapters
+//   - Event Publisher: provider factory, BuildFromEnv
 //   - Ledger Reporting: factory for ledger report generators
 //
 // Note: entityid constants live in registry/entityid/ (separate package, no dependency on this one).
@@ -186,6 +189,38 @@ var (
 	ListAvailableEmailProviderFactories = internal.ListAvailableEmailProviderFactories
 )
 
+// =============================================================================
+// Push Provider Registry
+// =============================================================================
+
+var (
+	RegisterPushProviderFactory = internal.RegisterPushProviderFactory
+	GetPushProviderFactory      = internal.GetPushProviderFactory
+
+	RegisterPushBuildFromEnv      = internal.RegisterPushBuildFromEnv
+	GetPushBuildFromEnv           = internal.GetPushBuildFromEnv
+	BuildPushProviderFromEnv      = internal.BuildPushProviderFromEnv
+	ListAvailablePushBuildFromEnv = internal.ListAvailablePushBuildFromEnv
+
+	ListAvailablePushProviderFactories = internal.ListAvailablePushProviderFactories
+)
+
+// =============================================================================
+// Messaging Provider Registry
+// =============================================================================
+
+var (
+	RegisterMessagingProviderFactory = internal.RegisterMessagingProviderFactory
+	GetMessagingProviderFactory      = internal.GetMessagingProviderFactory
+
+	RegisterMessagingBuildFromEnv      = internal.RegisterMessagingBuildFromEnv
+	GetMessagingBuildFromEnv           = internal.GetMessagingBuildFromEnv
+	BuildMessagingProviderFromEnv      = internal.BuildMessagingProviderFromEnv
+	ListAvailableMessagingBuildFromEnv = internal.ListAvailableMessagingBuildFromEnv
+
+	ListAvailableMessagingProviderFactories = internal.ListAvailableMessagingProviderFactories
+)
+
 // =============================================================================
 // Scheduler Provider Registry
 // =============================================================================
@@ -264,6 +299,90 @@ var (
 	ListAvailableIDBuildFromEnv = internal.ListAvailableIDBuildFromEnv
 
 	ListAvailableIDProviderFactories = internal.ListAvailableIDProviderFactories
+
+	// Database adapters read the container's ID generator through these
+	// when minting primary keys on Create.
+	SetDefaultIDGenerator = internal.SetDefaultIDGenerator
+	GetDefaultIDGenerator = internal.GetDefaultIDGenerator
+)
+
+// =============================================================================
+// Cache Provider Registry
+// =============================================================================
+
+type CacheProviderConfig = internal.CacheProviderConfig
+type CachePolicy = internal.CachePolicy
+
+var (
+	RegisterCacheProviderFactory        = internal.RegisterCacheProviderFactory
+	GetCacheProviderFactory             = internal.GetCacheProviderFactory
+	ListAvailableCacheProviderFactories = internal.ListAvailableCacheProviderFactories
+
+	RegisterCacheBuildFromEnv      = internal.RegisterCacheBuildFromEnv
+	GetCacheBuildFromEnv           = internal.GetCacheBuildFromEnv
+	BuildCacheProviderFromEnv      = internal.BuildCacheProviderFromEnv
+	ListAvailableCacheBuildFromEnv = internal.ListAvailableCacheBuildFromEnv
+
+	// Database adapters consult the published cache on Read and List.
+	SetDefaultCache = internal.SetDefaultCache
+	GetDefaultCache = internal.GetDefaultCache
+)
+
+// =============================================================================
+// Event Publisher Registry
+// =============================================================================
+
+var (
+	RegisterEventPublisherFactory        = internal.RegisterEventPublisherFactory
+	GetEventPublisherFactory             = internal.GetEventPublisherFactory
+	ListAvailableEventPublisherFactories = internal.ListAvailableEventPublisherFactories
+
+	RegisterEventPublisherBuildFromEnv      = internal.RegisterEventPublisherBuildFromEnv
+	GetEventPublisherBuildFromEnv           = internal.GetEventPublisherBuildFromEnv
+	BuildEventPublisherFromEnv              = internal.BuildEventPublisherFromEnv
+	ListAvailableEventPublisherBuildFromEnv = internal.ListAvailableEventPublisherBuildFromEnv
+)
+
+// =============================================================================
+// Shadow Reads
+// =============================================================================
+
+type ShadowPolicy = internal.ShadowPolicy
+
+var (
+	// Database adapters replay sampled reads against the published candidate.
+	SetDefaultShadow = internal.SetDefaultShadow
+	GetDefaultShadow = internal.GetDefaultShadow
+)
+
+// =============================================================================
+// Revision History
+// =============================================================================
+
+type HistoryPolicy = internal.HistoryPolicy
+
+const DefaultRevisionTable = internal.DefaultRevisionTable
+
+var (
+	// Database adapters record a revision after each write to the published
+	// tables.
+	SetDefaultHistory = internal.SetDefaultHistory
+	GetDefaultHistory = internal.GetDefaultHistory
+)
+
+// =============================================================================
+// Workspace Tenancy
+// =============================================================================
+
+type TenancyPolicy = internal.TenancyPolicy
+
+var DefaultGlobalTables = internal.DefaultGlobalTables
+
+var (
+	// Database adapters confine tenant tables to the request's workspace,
+	// leaving the published global tables alone.
+	SetDefaultTenancy = internal.SetDefaultTenancy
+	GetDefaultTenancy = internal.GetDefaultTenancy
 )
 
 // =============================================================================
@@ -0,0 +1,80 @@
+// Package roletemplate declares the roles every workspace is expected to
+// have and the permission codes each one grants.
+//
+// The templates are the source of truth for the permission model. Changing
+// them does not touch existing workspaces by itself; cmd/permsync diffs the
+// templates against each workspace's role and role_permission records and
+// rolls the additions and removals out in bulk.
+package roletemplate
+
+import (
+	"sort"
+
+	"github.com/erniealice/espyna-golang/registry/entityid"
+)
+
+// Template is one role and the permission codes it grants.
+type Template struct {
+	// Name identifies the role within a workspace. Workspace roles are
+	// matched to templates by name.
+	Name        string
+	Description string
+	// Permissions are permission codes ("client:read"), sorted.
+	Permissions []string
+}
+
+// Role names of the built-in templates.
+const (
+	Owner  = "Owner"
+	Staff  = "Staff"
+	Viewer = "Viewer"
+)
+
+// Templates lists the built-in role templates.
+var Templates = []Template{
+	{
+		Name:        Owner,
+		Description: "Full access to every entity in the workspace",
+		Permissions: grants(entityid.All,
+			entityid.ActionCreate, entityid.ActionRead, entityid.ActionUpdate,
+			entityid.ActionDelete, entityid.ActionList, entityid.ActionManage),
+	},
+	{
+		Name:        Staff,
+		Description: "Day-to-day work: create, view and edit, but not delete or manage",
+		Permissions: grants(entityid.All,
+			entityid.ActionCreate, entityid.ActionRead, entityid.ActionUpdate, entityid.ActionList),
+	},
+	{
+		Name:        Viewer,
+		Description: "Read-only access",
+		Permissions: grants(entityid.All, entityid.ActionRead, entityid.ActionList),
+	},
+}
+
+// ByName returns the template with the given role name.
+func ByName(name string) (Template, bool) {
+	for _, t := range Templates {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Template{}, false
+}
+
+// grants builds the sorted permission codes for every entity and action.
+func grants(entities []string, actions ...string) []string {
+	seen := make(map[string]bool, len(entities)*len(actions))
+	codes := make([]string, 0, len(entities)*len(actions))
+	for _, entity := range entities {
+		for _, action := range actions {
+			code := entityid.EntityPermission(entity, action)
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -87,6 +87,7 @@ func build(reg *Registry) error {
 		}
 
 		reg.put(table, Classify(md))
+		reg.putFields(table, fieldNames(md))
 		return true
 	})
 
@@ -118,6 +119,16 @@ func AssertMinimumCoverage() error {
 	return nil
 }
 
+// fieldNames returns the snake_case names of every field of a message.
+func fieldNames(md protoreflect.MessageDescriptor) []string {
+	fields := md.Fields()
+	names := make([]string, fields.Len())
+	for i := range names {
+		names[i] = string(fields.Get(i).Name())
+	}
+	return names
+}
+
 // tableOptions reads the (options.v1.table) MessageOptions extension and returns
 // the resolved table name when table == true. Resolution: table_name override if
 // non-empty, else the message name lowered to snake_case.
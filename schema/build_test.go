@@ -89,3 +89,23 @@ func TestGlobalBuildIdempotent(t *testing.T) {
 		t.Errorf("Global.ColByName(job, date_created) = %+v ok=%v, want IsBigintMillis", c, ok)
 	}
 }
+
+// TestRegistryHasField confirms every message field is known, including the
+// ignored mirrors and relations Classify leaves out of the column set.
+func TestRegistryHasField(t *testing.T) {
+	reg := NewRegistry()
+	if err := build(reg); err != nil {
+		t.Fatalf("build returned error: %v", err)
+	}
+	for _, field := range []string{"name", "date_created_string", "client", "predecessor_job_ids"} {
+		if has, ok := reg.HasField("job", field); !has || !ok {
+			t.Errorf("HasField(job, %s) = %v, %v; want a known field", field, has, ok)
+		}
+	}
+	if has, ok := reg.HasField("job", "nickname"); has || !ok {
+		t.Errorf("HasField(job, nickname) = %v, %v; want an unknown field of a known table", has, ok)
+	}
+	if _, ok := reg.HasField("no_such_table", "name"); ok {
+		t.Error("HasField reported an unknown table as known")
+	}
+}
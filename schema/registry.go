@@ -12,11 +12,13 @@ import "sort"
 // per-dialect boot-shot validator (drift reconcile).
 type Registry struct {
 	tables map[string][]ColumnInfo
+	// fields holds every field name of each table's message, columns or not.
+	fields map[string]map[string]bool
 }
 
 // NewRegistry returns an empty Registry ready to be populated by Build().
 func NewRegistry() *Registry {
-	return &Registry{tables: make(map[string][]ColumnInfo)}
+	return &Registry{tables: make(map[string][]ColumnInfo), fields: make(map[string]map[string]bool)}
 }
 
 // put stores the classified column set under a resolved table name. Used by
@@ -25,6 +27,23 @@ func (r *Registry) put(table string, cols []ColumnInfo) {
 	r.tables[table] = cols
 }
 
+// putFields stores the names of every field of a table's message.
+func (r *Registry) putFields(table string, names []string) {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	r.fields[table] = set
+}
+
+// HasField reports whether the table's message declares a field, whether or
+// not it is persisted as a column (computed *_string mirrors and relations
+// are not). ok is false when the table is unknown.
+func (r *Registry) HasField(table, field string) (has, ok bool) {
+	set, ok := r.fields[table]
+	return set[field], ok
+}
+
 // ColsFor returns the column set for a resolved table name and whether it is
 // known to the registry.
 func (r *Registry) ColsFor(table string) ([]ColumnInfo, bool) {
@@ -76,3 +95,6 @@ func Tables() []string { return Global.Tables() }
 
 // ColByName is the package-level convenience wrapper over Global.ColByName.
 func ColByName(table, col string) (ColumnInfo, bool) { return Global.ColByName(table, col) }
+
+// HasField is the package-level convenience wrapper over Global.HasField.
+func HasField(table, field string) (has, ok bool) { return Global.HasField(table, field) }
@@ -0,0 +1,80 @@
+// Package apilog hands every served API request of a workspace to the
+// workspace's API event log, so workspace admins can see what their
+// integrations sent and what the API answered.
+//
+// Server adapters install an identity.Observer on the request before their
+// middleware chain runs and call Record once the request completes. Record
+// passes the event to the Sink installed with SetSink, which must not block;
+// without a Sink it does nothing. Requests that end without a workspace are
+// not recorded.
+//
+// Layer: Shared Adapter Toolkit (L4), like shared/correlation. Depends only
+// on the Go standard library and the other shared packages.
+package apilog
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+// Event is one served request.
+type Event struct {
+	// ID is assigned by the log that stores the event.
+	ID          string
+	Time        time.Time
+	WorkspaceID string
+	ActorID     string
+	Method      string
+	Path        string
+	Status      int
+	Latency     time.Duration
+	RequestID   string
+}
+
+// Sink receives recorded events. RecordAPIEvent is called on the request's
+// goroutine and must return quickly.
+type Sink interface {
+	RecordAPIEvent(Event)
+}
+
+type sinkHolder struct{ sink Sink }
+
+var current atomic.Pointer[sinkHolder]
+
+// SetSink installs the sink events go to; nil stops recording.
+func SetSink(sink Sink) {
+	if sink == nil {
+		current.Store(nil)
+		return
+	}
+	current.Store(&sinkHolder{sink: sink})
+}
+
+// Record sends one completed request to the sink. who is the Observer the
+// adapter installed on the request; ctx carries the correlation ID. method
+// and path are copied, since some frameworks reuse their buffers.
+func Record(ctx context.Context, who *identity.Observer, method, path string, status int, latency time.Duration) {
+	h := current.Load()
+	if h == nil {
+		return
+	}
+	workspaceID := who.WorkspaceID()
+	if workspaceID == "" {
+		return
+	}
+	h.sink.RecordAPIEvent(Event{
+		Time:        time.Now().UTC(),
+		WorkspaceID: workspaceID,
+		ActorID:     who.UserID(),
+		Method:      strings.Clone(method),
+		Path:        strings.Clone(path),
+		Status:      status,
+		Latency:     latency,
+		RequestID:   correlation.FromContext(ctx),
+	})
+}
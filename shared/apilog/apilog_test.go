@@ -0,0 +1,40 @@
+package apilog
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erniealice/espyna-golang/shared/correlation"
+	"github.com/erniealice/espyna-golang/shared/identity"
+)
+
+type sliceSink []Event
+
+func (s *sliceSink) RecordAPIEvent(e Event) { *s = append(*s, e) }
+
+func TestRecord(t *testing.T) {
+	ctx, who := identity.Observe(correlation.WithID(context.Background(), "req-1"))
+	Record(ctx, who, http.MethodGet, "/api/client/list", http.StatusOK, time.Millisecond)
+
+	sink := &sliceSink{}
+	SetSink(sink)
+	defer SetSink(nil)
+
+	Record(ctx, who, http.MethodGet, "/api/client/list", http.StatusOK, time.Millisecond)
+	if len(*sink) != 0 {
+		t.Fatalf("recorded a request without a workspace: %+v", *sink)
+	}
+
+	identity.WithRequestIdentity(ctx, &identity.RequestIdentity{UserID: "u1", WorkspaceID: "w1"})
+	Record(ctx, who, http.MethodPost, "/api/client/create", http.StatusBadRequest, 12*time.Millisecond)
+	if len(*sink) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(*sink))
+	}
+	e := (*sink)[0]
+	if e.WorkspaceID != "w1" || e.ActorID != "u1" || e.Method != http.MethodPost || e.Path != "/api/client/create" ||
+		e.Status != http.StatusBadRequest || e.Latency != 12*time.Millisecond || e.RequestID != "req-1" || e.Time.IsZero() {
+		t.Errorf("event = %+v", e)
+	}
+}
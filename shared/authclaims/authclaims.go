@@ -0,0 +1,119 @@
+// Package authclaims defines the compact role and permission set kept in an
+// identity provider's custom claims (Firebase Auth), so frontends and the
+// authentication middleware can make coarse checks from a verified token
+// without a database lookup. The claims sync worker writes it whenever a
+// user's workspace roles change; the database stays authoritative.
+//
+// Custom claims are small (Firebase caps them at 1000 bytes), so a set that
+// does not fit loses its permissions first and then its workspaces, and is
+// marked truncated. A check the truncated set cannot answer reports so, and
+// the caller falls back to the authorizer.
+//
+// Layer: Shared Adapter Toolkit (L4), like shared/identity. Depends only on
+// the Go standard library.
+package authclaims
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// Key is the custom claim holding the set.
+const Key = "rbac"
+
+// MaxBytes is the size Firebase allows for a user's custom claims, encoded
+// as JSON.
+const MaxBytes = 1000
+
+// Claims is a user's roles and permissions per workspace.
+type Claims struct {
+	// Workspaces maps workspace IDs to the user's access there.
+	Workspaces map[string]Workspace `json:"ws,omitempty"`
+	// Truncated is set when the set did not fit: permissions are left out,
+	// and so are the workspaces when even the roles did not fit.
+	Truncated bool `json:"trunc,omitempty"`
+}
+
+// Workspace is the user's access in one workspace.
+type Workspace struct {
+	// Roles are role names, sorted.
+	Roles []string `json:"r,omitempty"`
+	// Permissions are permission codes (client:create), ALLOW grants net of
+	// DENY grants, sorted.
+	Permissions []string `json:"p,omitempty"`
+}
+
+// Parse reads the set from a claim value: the decoded JSON object token
+// verifiers return, or its JSON encoding. ok is false when v holds no set.
+func Parse(v any) (Claims, bool) {
+	var raw []byte
+	switch v := v.(type) {
+	case nil:
+		return Claims{}, false
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return Claims{}, false
+		}
+		raw = encoded
+	}
+	var c Claims
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return Claims{}, false
+	}
+	return c, true
+}
+
+// HasRole reports whether the user holds role in workspaceID. known is
+// false when the set was truncated too far to tell.
+func (c Claims) HasRole(workspaceID, role string) (has, known bool) {
+	if c.Truncated && c.Workspaces == nil {
+		return false, false
+	}
+	return slices.Contains(c.Workspaces[workspaceID].Roles, role), true
+}
+
+// HasPermission reports whether the user holds permission in workspaceID.
+// known is false when the set was truncated.
+func (c Claims) HasPermission(workspaceID, permission string) (has, known bool) {
+	if c.Truncated {
+		return false, false
+	}
+	return slices.Contains(c.Workspaces[workspaceID].Permissions, permission), true
+}
+
+// Member reports whether the user belongs to workspaceID. known is false
+// when the set was truncated too far to tell.
+func (c Claims) Member(workspaceID string) (member, known bool) {
+	if c.Truncated && c.Workspaces == nil {
+		return false, false
+	}
+	_, member = c.Workspaces[workspaceID]
+	return member, true
+}
+
+// Fit returns c trimmed to fit in budget bytes of JSON: whole when it fits,
+// else without permissions, else without workspaces.
+func (c Claims) Fit(budget int) Claims {
+	if size(c) <= budget {
+		return c
+	}
+	rolesOnly := Claims{Workspaces: make(map[string]Workspace, len(c.Workspaces)), Truncated: true}
+	for id, ws := range c.Workspaces {
+		rolesOnly.Workspaces[id] = Workspace{Roles: ws.Roles}
+	}
+	if size(rolesOnly) <= budget {
+		return rolesOnly
+	}
+	return Claims{Truncated: true}
+}
+
+// size is the length of c's JSON encoding under Key.
+func size(c Claims) int {
+	encoded, _ := json.Marshal(map[string]Claims{Key: c})
+	return len(encoded)
+}
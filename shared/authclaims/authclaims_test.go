@@ -0,0 +1,81 @@
+package authclaims
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestClaims_Checks(t *testing.T) {
+	c := Claims{Workspaces: map[string]Workspace{
+		"ws-1": {Roles: []string{"admin"}, Permissions: []string{"client:create"}},
+	}}
+	if has, known := c.HasPermission("ws-1", "client:create"); !has || !known {
+		t.Errorf("granted permission = %v, %v", has, known)
+	}
+	if has, known := c.HasPermission("ws-2", "client:create"); has || !known {
+		t.Errorf("other workspace = %v, %v", has, known)
+	}
+	if has, known := c.HasRole("ws-1", "admin"); !has || !known {
+		t.Errorf("role = %v, %v", has, known)
+	}
+
+	rolesOnly := Claims{Workspaces: map[string]Workspace{"ws-1": {Roles: []string{"admin"}}}, Truncated: true}
+	if _, known := rolesOnly.HasPermission("ws-1", "client:create"); known {
+		t.Error("truncated set answered a permission check")
+	}
+	if has, known := rolesOnly.HasRole("ws-1", "admin"); !has || !known {
+		t.Errorf("truncated set role = %v, %v", has, known)
+	}
+	if _, known := (Claims{Truncated: true}).Member("ws-1"); known {
+		t.Error("empty truncated set answered a membership check")
+	}
+}
+
+func TestParse(t *testing.T) {
+	want := Claims{Workspaces: map[string]Workspace{"ws-1": {Roles: []string{"admin"}}}}
+	encoded, _ := json.Marshal(want)
+	var decoded any
+	_ = json.Unmarshal(encoded, &decoded)
+
+	for _, v := range []any{string(encoded), decoded} {
+		got, ok := Parse(v)
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("Parse(%T) = %v, %v", v, got, ok)
+		}
+	}
+	if _, ok := Parse(nil); ok {
+		t.Error("Parse(nil) found a set")
+	}
+	if _, ok := Parse("not json"); ok {
+		t.Error("Parse accepted malformed JSON")
+	}
+}
+
+func TestClaims_Fit(t *testing.T) {
+	c := Claims{Workspaces: map[string]Workspace{}}
+	for i := 0; i < 10; i++ {
+		perms := make([]string, 10)
+		for j := range perms {
+			perms[j] = fmt.Sprintf("entity_%d:update", j)
+		}
+		c.Workspaces[fmt.Sprintf("ws-%02d", i)] = Workspace{Roles: []string{"staff"}, Permissions: perms}
+	}
+
+	if got := c.Fit(1 << 20); got.Truncated || len(got.Workspaces["ws-00"].Permissions) != 10 {
+		t.Errorf("set within budget changed: %+v", got.Workspaces["ws-00"])
+	}
+	got := c.Fit(MaxBytes)
+	if !got.Truncated || len(got.Workspaces) != 10 || got.Workspaces["ws-00"].Permissions != nil {
+		t.Errorf("over budget = %+v", got)
+	}
+	if size(got) > MaxBytes {
+		t.Errorf("fitted size %d > %d", size(got), MaxBytes)
+	}
+	if got := c.Fit(20); !got.Truncated || got.Workspaces != nil {
+		t.Errorf("tiny budget = %+v", got)
+	}
+	if len(c.Workspaces["ws-00"].Permissions) != 10 {
+		t.Error("Fit modified its receiver")
+	}
+}
@@ -0,0 +1,207 @@
+// Package correlation carries a per-request correlation ID from the server
+// adapters through use cases into downstream adapters, and provides the
+// structured JSON request log every server adapter emits.
+//
+// Server middleware assigns the ID once per request — accepting a
+// well-formed X-Request-ID from the caller or generating one — stores it on
+// the context with WithID and echoes it in the response header. Anything
+// holding that context can read it back with FromContext. Loggers built on
+// NewHandler add it to every record logged with a *Context method
+// (slog.InfoContext and friends), so database, payment and tabular log lines
+// can be joined to the request that caused them. Transport stamps it on
+// outbound HTTP calls to third-party providers.
+//
+// Layer: Shared Adapter Toolkit (L4), like shared/identity. Depends only on
+// the Go standard library.
+package correlation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Header is the HTTP header carrying the correlation ID, in requests and
+// responses.
+const Header = "X-Request-ID"
+
+// MetadataKey is the gRPC metadata key carrying the correlation ID.
+const MetadataKey = "x-request-id"
+
+// LogKey is the attribute name of the correlation ID in log records.
+const LogKey = "request_id"
+
+// maxIDLength bounds caller-supplied IDs so a client cannot bloat every log
+// line of its request.
+const maxIDLength = 128
+
+type contextKey struct{}
+
+// New returns a fresh random correlation ID.
+func New() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Accept returns incoming when it is a usable correlation ID — non-empty, at
+// most 128 characters of letters, digits and "-_.:" — and a new ID
+// otherwise. Upstream proxies and callers can thereby thread their own ID
+// through, but cannot inject arbitrary text into the logs.
+func Accept(incoming string) string {
+	if incoming == "" || len(incoming) > maxIDLength {
+		return New()
+	}
+	for i := 0; i < len(incoming); i++ {
+		c := incoming[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return New()
+		}
+	}
+	return incoming
+}
+
+// WithID stores the correlation ID on the context.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the correlation ID stored on the context, or "" when
+// the context did not come from a request.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// =============================================================================
+// Logging
+// =============================================================================
+
+// Handler is a slog.Handler that adds the context's correlation ID to each
+// record before passing it on.
+type Handler struct {
+	next slog.Handler
+}
+
+// NewHandler wraps next so records logged with a request context carry its
+// correlation ID under LogKey. Wrapping a Handler again returns it as is.
+func NewHandler(next slog.Handler) slog.Handler {
+	if h, ok := next.(*Handler); ok {
+		return h
+	}
+	return &Handler{next: next}
+}
+
+// Enabled reports whether the wrapped handler handles records at level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the correlation ID, if any, and passes the record on.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if id := FromContext(ctx); id != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String(LogKey, id))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a Handler whose wrapped handler has the attributes.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup returns a Handler whose wrapped handler has the group open.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name)}
+}
+
+// NewJSONLogger returns a logger writing JSON lines to w that includes
+// correlation IDs.
+func NewJSONLogger(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(NewHandler(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})))
+}
+
+var installOnce sync.Once
+
+// InstallDefault makes a correlation-aware JSON logger on stderr the slog
+// default, at the level named by CONFIG_LOG_LEVEL (debug, info, warn or
+// error; info when unset). Output of the standard log package goes through
+// it too. Server adapters call it on Initialize; only the first call has an
+// effect.
+func InstallDefault() {
+	installOnce.Do(func() {
+		slog.SetDefault(NewJSONLogger(os.Stderr, levelFromEnv(os.Getenv("CONFIG_LOG_LEVEL"))))
+	})
+}
+
+func levelFromEnv(raw string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(raw))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// LogRequest writes the access log line of one served request to the slog
+// default logger: method, path, status and latency, plus the correlation ID
+// from ctx. 5xx responses log at error level and 4xx at warn.
+func LogRequest(ctx context.Context, method, path string, status int, latency time.Duration) {
+	level := slog.LevelInfo
+	switch {
+	case status >= 500:
+		level = slog.LevelError
+	case status >= 400:
+		level = slog.LevelWarn
+	}
+	slog.LogAttrs(ctx, level, "request",
+		slog.String("method", method),
+		slog.String("path", path),
+		slog.Int("status", status),
+		slog.Float64("latency_ms", float64(latency.Microseconds())/1000),
+	)
+}
+
+// =============================================================================
+// Outbound propagation
+// =============================================================================
+
+// Transport is an http.RoundTripper that sets the Header on outbound
+// requests whose context carries a correlation ID, so provider-side logs
+// and support tickets can be matched to ours.
+type Transport struct {
+	// Base performs the request. Nil uses http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base (nil for http.DefaultTransport).
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// RoundTrip sets the correlation header, unless the caller already did, and
+// sends the request.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if id := FromContext(req.Context()); id != "" && req.Header.Get(Header) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(Header, id)
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,86 @@
+package correlation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAccept(t *testing.T) {
+	t.Parallel()
+
+	if got := Accept("req-123_abc.def:1"); got != "req-123_abc.def:1" {
+		t.Errorf("Accept(valid) = %q, want it unchanged", got)
+	}
+	for _, bad := range []string{"", "has space", "quote\"", "line\nbreak", strings.Repeat("a", maxIDLength+1)} {
+		got := Accept(bad)
+		if got == bad || len(got) != 32 {
+			t.Errorf("Accept(%q) = %q, want a generated ID", bad, got)
+		}
+	}
+	if New() == New() {
+		t.Error("New returned the same ID twice")
+	}
+}
+
+func TestHandler_AddsIDFromContext(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := NewJSONLogger(&buf, slog.LevelInfo).With("provider", "test")
+
+	logger.InfoContext(WithID(context.Background(), "abc"), "with id")
+	logger.InfoContext(context.Background(), "without id")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d log lines, want 2", len(lines))
+	}
+	var first, second map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatal(err)
+	}
+	if first[LogKey] != "abc" || first["provider"] != "test" {
+		t.Errorf("first record = %v, want request_id=abc and provider=test", first)
+	}
+	if _, ok := second[LogKey]; ok {
+		t.Errorf("second record = %v, want no request_id", second)
+	}
+
+	h := NewHandler(slog.NewJSONHandler(&buf, nil))
+	if NewHandler(h) != h {
+		t.Error("NewHandler wrapped a Handler twice")
+	}
+}
+
+type recordingTransport struct{ header string }
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.header = req.Header.Get(Header)
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestTransport_PropagatesID(t *testing.T) {
+	t.Parallel()
+
+	base := &recordingTransport{}
+	client := &http.Client{Transport: NewTransport(base)}
+
+	req, _ := http.NewRequestWithContext(WithID(context.Background(), "abc"), http.MethodGet, "http://provider.test", nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if base.header != "abc" {
+		t.Errorf("outbound %s = %q, want %q", Header, base.header, "abc")
+	}
+	if req.Header.Get(Header) != "" {
+		t.Error("Transport modified the caller's request")
+	}
+}
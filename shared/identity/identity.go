@@ -92,8 +92,10 @@ type RequestIdentity struct {
 // WithRequestIdentity stores the identity on the context atomically. This is
 // the ONLY writer — middleware calls this once per request. The struct is
 // stored by pointer so subsequent reads via Must/Require share the same
-// allocation (no copy per context.Value call).
+// allocation (no copy per context.Value call). An Observer on ctx (see
+// Observe) is told about the new identity.
 func WithRequestIdentity(ctx context.Context, id *RequestIdentity) context.Context {
+	observe(ctx, id)
 	return context.WithValue(ctx, contextKey{}, id)
 }
 
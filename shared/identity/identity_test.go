@@ -164,3 +164,30 @@ func TestDefaultSessionCookieName(t *testing.T) {
 		t.Errorf("DefaultSessionCookieName = %q, want %q", DefaultSessionCookieName, "ichizen_session")
 	}
 }
+
+func TestObserve(t *testing.T) {
+	t.Parallel()
+
+	var nilObserver *Observer
+	if nilObserver.UserID() != "" || nilObserver.WorkspaceID() != "" {
+		t.Fatal("nil Observer reported an identity")
+	}
+	NoteWorkspaceID(context.Background(), "ws-ignored")
+
+	ctx, o := Observe(context.Background())
+	inner := WithRequestIdentity(ctx, &RequestIdentity{UserID: "user-1", WorkspaceID: "ws-session"})
+	if o.UserID() != "user-1" || o.WorkspaceID() != "ws-session" {
+		t.Fatalf("observed %q/%q after WithRequestIdentity", o.UserID(), o.WorkspaceID())
+	}
+
+	// The workspace-path middleware overrides the workspace through the
+	// legacy writer; the override wins until a new identity is stored.
+	NoteWorkspaceID(inner, "ws-url")
+	if o.WorkspaceID() != "ws-url" || o.UserID() != "user-1" {
+		t.Errorf("observed %q/%q after NoteWorkspaceID", o.UserID(), o.WorkspaceID())
+	}
+	WithRequestIdentity(inner, &RequestIdentity{UserID: "user-2"})
+	if o.UserID() != "user-2" || o.WorkspaceID() != "" {
+		t.Errorf("observed %q/%q after a second identity", o.UserID(), o.WorkspaceID())
+	}
+}
@@ -0,0 +1,96 @@
+package identity
+
+import (
+	"context"
+	"sync"
+)
+
+// observerKey holds the Observer a server adapter installed on the request.
+type observerKey struct{}
+
+// Observer reports the identity a request ended up with to code that runs
+// outside the middleware which set it. An access logger installed before
+// the session middleware cannot read the identity from its own context, so
+// it installs an Observer instead and reads it once the request completes.
+type Observer struct {
+	mu          sync.Mutex
+	id          *RequestIdentity
+	userID      string
+	workspaceID string
+}
+
+// Observe returns a context whose identity writes, by WithRequestIdentity or
+// the legacy NoteUserID/NoteWorkspaceID writers, are reported to the
+// returned Observer.
+func Observe(ctx context.Context) (context.Context, *Observer) {
+	o := &Observer{}
+	return context.WithValue(ctx, observerKey{}, o), o
+}
+
+// NoteUserID reports a user ID written outside RequestIdentity to the
+// context's Observer, if any.
+func NoteUserID(ctx context.Context, userID string) {
+	if o := observerFrom(ctx); o != nil {
+		o.mu.Lock()
+		o.userID = userID
+		o.mu.Unlock()
+	}
+}
+
+// NoteWorkspaceID reports a workspace ID written outside RequestIdentity
+// (the workspace-path middleware overrides the session's workspace this
+// way) to the context's Observer, if any.
+func NoteWorkspaceID(ctx context.Context, workspaceID string) {
+	if o := observerFrom(ctx); o != nil {
+		o.mu.Lock()
+		o.workspaceID = workspaceID
+		o.mu.Unlock()
+	}
+}
+
+// UserID returns the last user ID written, or "".
+func (o *Observer) UserID() string {
+	if o == nil {
+		return ""
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.userID != "" {
+		return o.userID
+	}
+	if o.id != nil {
+		return o.id.UserID
+	}
+	return ""
+}
+
+// WorkspaceID returns the last workspace ID written, or "".
+func (o *Observer) WorkspaceID() string {
+	if o == nil {
+		return ""
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.workspaceID != "" {
+		return o.workspaceID
+	}
+	if o.id != nil {
+		return o.id.WorkspaceID
+	}
+	return ""
+}
+
+func observerFrom(ctx context.Context) *Observer {
+	o, _ := ctx.Value(observerKey{}).(*Observer)
+	return o
+}
+
+// observe records id as the latest identity. It replaces earlier legacy
+// notes, which a full identity supersedes.
+func observe(ctx context.Context, id *RequestIdentity) {
+	if o := observerFrom(ctx); o != nil {
+		o.mu.Lock()
+		o.id, o.userID, o.workspaceID = id, "", ""
+		o.mu.Unlock()
+	}
+}
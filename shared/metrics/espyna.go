@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	requestsTotal = Default.NewCounter("espyna_http_requests_total",
+		"Requests served, by route and status code.", "method", "route", "status")
+	requestDuration = Default.NewHistogram("espyna_http_request_duration_seconds",
+		"Time to serve a request, by route.", DefaultBuckets, "method", "route")
+	dbDuration = Default.NewHistogram("espyna_db_operation_duration_seconds",
+		"Duration of database operations, by table or collection.", DefaultBuckets, "provider", "operation", "table")
+	dbErrors = Default.NewCounter("espyna_db_operation_errors_total",
+		"Database operations that returned an error, by table or collection.", "provider", "operation", "table")
+	tabularDuration = Default.NewHistogram("espyna_tabular_operation_duration_seconds",
+		"Duration of tabular provider operations, by sheet.", DefaultBuckets, "provider", "operation", "table")
+	tabularErrors = Default.NewCounter("espyna_tabular_operation_errors_total",
+		"Tabular provider operations that failed, by sheet.", "provider", "operation", "table")
+	droppedFields = Default.NewCounter("espyna_db_dropped_fields_total",
+		"Fields a write discarded because the table has no column for them, by table.", "provider", "operation", "table")
+	shadowReads = Default.NewCounter("espyna_db_shadow_reads_total",
+		"Reads replayed against a candidate database, by outcome (match, mismatch, error, skipped).", "candidate", "operation", "table", "outcome")
+)
+
+// Handler serves the Default registry.
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// RegisterProbe adds a provider health check to the Default registry.
+func RegisterProbe(kind, name string, check func(context.Context) error) {
+	Default.RegisterProbe(kind, name, check)
+}
+
+// ObserveRequest records a served request. route is the route pattern the
+// request matched, not the raw path; pass "" when nothing matched.
+func ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	if route == "" {
+		route = UnmatchedRoute
+	}
+	requestsTotal.Inc(method, route, strconv.Itoa(status))
+	requestDuration.Observe(elapsed.Seconds(), method, route)
+}
+
+// ObserveDBOperation records a database operation that started at start.
+// err points at the operation's error result so the call can be deferred
+// at the top of the operation:
+//
+//	defer metrics.ObserveDBOperation("postgresql", "list", tableName, time.Now(), &err)
+func ObserveDBOperation(provider, operation, table string, start time.Time, err *error) {
+	dbDuration.Observe(time.Since(start).Seconds(), provider, operation, table)
+	if err != nil && *err != nil {
+		dbErrors.Inc(provider, operation, table)
+	}
+}
+
+// ObserveTabularOperation records a tabular provider operation that started
+// at start and whether it failed.
+func ObserveTabularOperation(provider, operation, table string, start time.Time, failed bool) {
+	tabularDuration.Observe(time.Since(start).Seconds(), provider, operation, table)
+	if failed {
+		tabularErrors.Inc(provider, operation, table)
+	}
+}
+
+// ObserveDroppedFields records fields a write discarded because the table
+// has no column for them, which usually means the schema has drifted from
+// the code.
+func ObserveDroppedFields(provider, operation, table string, n int) {
+	if n > 0 {
+		droppedFields.Add(float64(n), provider, operation, table)
+	}
+}
+
+// ObserveShadowRead records the outcome of a read replayed against the
+// candidate database during a migration.
+func ObserveShadowRead(candidate, operation, table, outcome string) {
+	shadowReads.Inc(candidate, operation, table, outcome)
+}
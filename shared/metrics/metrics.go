@@ -0,0 +1,335 @@
+// Package metrics collects request, database and provider metrics and
+// serves them at /metrics in the Prometheus text exposition format.
+//
+// Server adapters record every request with ObserveRequest, database
+// operations record their duration per table with ObserveDBOperation, and
+// tabular providers with ObserveTabularOperation. Provider health is probed
+// when the endpoint is scraped: RegisterProbe adds a check whose result is
+// exported as the espyna_provider_up gauge, so an outage shows up without
+// waiting for a request to fail.
+//
+// Exported series:
+//
+//	espyna_http_requests_total{method,route,status}
+//	espyna_http_request_duration_seconds{method,route}
+//	espyna_db_operation_duration_seconds{provider,operation,table}
+//	espyna_db_operation_errors_total{provider,operation,table}
+//	espyna_tabular_operation_duration_seconds{provider,operation,table}
+//	espyna_tabular_operation_errors_total{provider,operation,table}
+//	espyna_provider_up{kind,name}
+//
+// Layer: Shared Adapter Toolkit (L4), like shared/correlation. Depends only
+// on the Go standard library.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Path is where server adapters serve the metrics.
+const Path = "/metrics"
+
+// ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// UnmatchedRoute labels requests that matched no route, so scans of random
+// paths do not create a series each.
+const UnmatchedRoute = "unmatched"
+
+// probeTimeout bounds each health probe run during a scrape.
+const probeTimeout = 5 * time.Second
+
+// DefaultBuckets are the histogram upper bounds in seconds, from 5ms to 10s.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// =============================================================================
+// Registry
+// =============================================================================
+
+type collector interface {
+	write(w io.Writer)
+}
+
+// Registry holds metrics and health probes and writes them in the text
+// exposition format.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	probes     []probe
+}
+
+type probe struct {
+	kind, name string
+	check      func(context.Context) error
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default is the registry the package-level functions record to.
+var Default = NewRegistry()
+
+// NewCounter creates a counter family with the given label names.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{name: name, help: help, labels: labels, series: map[string]*counterSeries{}}
+	r.mu.Lock()
+	r.collectors = append(r.collectors, c)
+	r.mu.Unlock()
+	return c
+}
+
+// NewHistogram creates a histogram family with the given buckets (upper
+// bounds in ascending order) and label names.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{name: name, help: help, labels: labels, buckets: buckets, series: map[string]*histogramSeries{}}
+	r.mu.Lock()
+	r.collectors = append(r.collectors, h)
+	r.mu.Unlock()
+	return h
+}
+
+// RegisterProbe adds a health check exported as espyna_provider_up{kind,
+// name}: 1 when check returns nil, 0 otherwise. Registering the same kind
+// and name again replaces the check.
+func (r *Registry) RegisterProbe(kind, name string, check func(context.Context) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, p := range r.probes {
+		if p.kind == kind && p.name == name {
+			r.probes[i].check = check
+			return
+		}
+	}
+	r.probes = append(r.probes, probe{kind: kind, name: name, check: check})
+}
+
+// Write runs the health probes and writes all metrics to w.
+func (r *Registry) Write(ctx context.Context, w io.Writer) {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	probes := append([]probe(nil), r.probes...)
+	r.mu.Unlock()
+
+	for _, c := range collectors {
+		c.write(w)
+	}
+	writeProbes(ctx, w, probes)
+}
+
+// Handler serves the registry in the text exposition format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		r.Write(req.Context(), w)
+	})
+}
+
+// writeProbes runs the probes concurrently and writes the gauge.
+func writeProbes(ctx context.Context, w io.Writer, probes []probe) {
+	if len(probes) == 0 {
+		return
+	}
+	sort.Slice(probes, func(i, j int) bool {
+		if probes[i].kind != probes[j].kind {
+			return probes[i].kind < probes[j].kind
+		}
+		return probes[i].name < probes[j].name
+	})
+	up := make([]int, len(probes))
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Add(1)
+		go func(i int, p probe) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+			defer cancel()
+			if p.check(ctx) == nil {
+				up[i] = 1
+			}
+		}(i, p)
+	}
+	wg.Wait()
+
+	fmt.Fprintf(w, "# HELP espyna_provider_up Whether the provider passed its health check (1) or not (0).\n")
+	fmt.Fprintf(w, "# TYPE espyna_provider_up gauge\n")
+	for i, p := range probes {
+		fmt.Fprintf(w, "espyna_provider_up%s %d\n", labelPairs([]string{"kind", "name"}, []string{p.kind, p.name}, "", ""), up[i])
+	}
+}
+
+// =============================================================================
+// Counter
+// =============================================================================
+
+// Counter is a family of monotonically increasing values.
+type Counter struct {
+	name, help string
+	labels     []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	values []string
+	count  float64
+}
+
+// Add increases the series with the given label values by v.
+func (c *Counter) Add(v float64, values ...string) {
+	key := seriesKey(values)
+	c.mu.Lock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{values: append([]string(nil), values...)}
+		c.series[key] = s
+	}
+	s.count += v
+	c.mu.Unlock()
+}
+
+// Inc increases the series with the given label values by one.
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		fmt.Fprintf(w, "%s%s %s\n", c.name, labelPairs(c.labels, s.values, "", ""), formatFloat(s.count))
+	}
+}
+
+// =============================================================================
+// Histogram
+// =============================================================================
+
+// Histogram is a family of bucketed observations, typically durations in
+// seconds.
+type Histogram struct {
+	name, help string
+	labels     []string
+	buckets    []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// Observe records v in the series with the given label values.
+func (h *Histogram) Observe(v float64, values ...string) {
+	key := seriesKey(values)
+	h.mu.Lock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{values: append([]string(nil), values...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+	h.mu.Unlock()
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, labelPairs(h.labels, s.values, "le", formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, labelPairs(h.labels, s.values, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, labelPairs(h.labels, s.values, "", ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, labelPairs(h.labels, s.values, "", ""), s.count)
+	}
+}
+
+// =============================================================================
+// Formatting
+// =============================================================================
+
+func seriesKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// labelPairs renders {name="value",...}, with an optional extra pair such as
+// the histogram bucket bound.
+func labelPairs(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(value))
+		b.WriteByte('"')
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(extraName)
+		b.WriteString(`="`)
+		b.WriteString(extraValue)
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, +1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
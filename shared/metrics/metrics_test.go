@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WritesExpositionFormat(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_requests_total", "Requests.", "route", "status")
+	h := r.NewHistogram("test_duration_seconds", "Duration.", []float64{0.1, 1}, "table")
+	c.Inc("/api/client/list", "200")
+	c.Inc("/api/client/list", "200")
+	c.Inc(`/a"b`, "500")
+	h.Observe(0.05, "client")
+	h.Observe(0.5, "client")
+	h.Observe(3, "client")
+	r.RegisterProbe("payment", "paypal", func(context.Context) error { return nil })
+	r.RegisterProbe("payment", "maya", func(context.Context) error { return errors.New("down") })
+
+	var out strings.Builder
+	r.Write(context.Background(), &out)
+	got := out.String()
+
+	for _, want := range []string{
+		"# TYPE test_requests_total counter\n",
+		`test_requests_total{route="/api/client/list",status="200"} 2` + "\n",
+		`test_requests_total{route="/a\"b",status="500"} 1` + "\n",
+		"# TYPE test_duration_seconds histogram\n",
+		`test_duration_seconds_bucket{table="client",le="0.1"} 1` + "\n",
+		`test_duration_seconds_bucket{table="client",le="1"} 2` + "\n",
+		`test_duration_seconds_bucket{table="client",le="+Inf"} 3` + "\n",
+		`test_duration_seconds_sum{table="client"} 3.55` + "\n",
+		`test_duration_seconds_count{table="client"} 3` + "\n",
+		`espyna_provider_up{kind="payment",name="maya"} 0` + "\n",
+		`espyna_provider_up{kind="payment",name="paypal"} 1` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output lacks %q\n%s", want, got)
+		}
+	}
+}
+
+func TestObserveRequest_LabelsUnmatchedRoutes(t *testing.T) {
+	ObserveRequest("GET", "", 404, 0)
+
+	var out strings.Builder
+	Default.Write(context.Background(), &out)
+	if want := `espyna_http_requests_total{method="GET",route="unmatched",status="404"} 1`; !strings.Contains(out.String(), want) {
+		t.Errorf("output lacks %q", want)
+	}
+}
@@ -0,0 +1,357 @@
+// Package redis is a small Redis client speaking RESP2 over pooled
+// connections. It covers what espyna's own features need from Redis, such
+// as shared rate limit counters, without pulling a client library into
+// every build: commands are sent as-is with Do and replies come back as
+// plain Go values.
+//
+// Replies map to:
+//
+//	simple string  string
+//	error          Error
+//	integer        int64
+//	bulk string    []byte (nil reply: ErrNil)
+//	array          []any
+//
+// Layer: Shared Adapter Toolkit (L4), like shared/metrics. Depends only on
+// the Go standard library.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNil is returned by Do when the reply is a nil bulk string or array,
+// such as GET of a missing key.
+var ErrNil = errors.New("redis: nil reply")
+
+// ErrClosed is returned by Do after Close.
+var ErrClosed = errors.New("redis: client closed")
+
+// Error is an error reply from the server, such as a wrong command or a
+// failing script. The connection stays usable.
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+// Options configures a Client.
+type Options struct {
+	// Addr is host:port (default localhost:6379).
+	Addr     string
+	Username string
+	Password string
+	DB       int
+	// TLS dials with TLS (rediss:// URLs).
+	TLS bool
+	// DialTimeout bounds connecting and the AUTH/SELECT handshake
+	// (default 5s).
+	DialTimeout time.Duration
+	// PoolSize is the number of idle connections kept (default 10).
+	PoolSize int
+}
+
+// ParseURL reads options from a redis:// or rediss:// URL:
+//
+//	redis://[[username]:password@]host[:port][/db]
+func ParseURL(raw string) (Options, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Options{}, fmt.Errorf("redis: invalid URL: %w", err)
+	}
+	var opts Options
+	switch u.Scheme {
+	case "redis":
+	case "rediss":
+		opts.TLS = true
+	default:
+		return Options{}, fmt.Errorf("redis: unsupported URL scheme %q", u.Scheme)
+	}
+	opts.Addr = u.Host
+	if u.Port() == "" && u.Host != "" {
+		opts.Addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		opts.Username = u.User.Username()
+		opts.Password, _ = u.User.Password()
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if opts.DB, err = strconv.Atoi(db); err != nil || opts.DB < 0 {
+			return Options{}, fmt.Errorf("redis: invalid database %q", db)
+		}
+	}
+	return opts, nil
+}
+
+// Client sends commands over a pool of connections. It is safe for
+// concurrent use.
+type Client struct {
+	opts Options
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+// NewClient creates a client. Connections are opened on first use.
+func NewClient(opts Options) *Client {
+	if opts.Addr == "" {
+		opts.Addr = "localhost:6379"
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 5 * time.Second
+	}
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 10
+	}
+	return &Client{opts: opts}
+}
+
+// Do sends one command and returns its reply. ctx's deadline bounds the
+// round trip.
+func (c *Client) Do(ctx context.Context, args ...any) (any, error) {
+	if len(args) == 0 {
+		return nil, errors.New("redis: empty command")
+	}
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := cn.do(ctx, args)
+	var serverErr Error
+	if err != nil && !errors.As(err, &serverErr) && !errors.Is(err, ErrNil) {
+		// The connection may be mid-reply; never reuse it.
+		cn.close()
+		return nil, err
+	}
+	c.put(cn)
+	return reply, err
+}
+
+// Ping checks that the server answers.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Close closes the idle connections. Commands in flight finish; their
+// connections are closed when returned.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	idle := c.idle
+	c.idle, c.closed = nil, true
+	c.mu.Unlock()
+	for _, cn := range idle {
+		cn.close()
+	}
+	return nil
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClosed
+	}
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+	return c.dial(ctx)
+}
+
+func (c *Client) put(cn *conn) {
+	c.mu.Lock()
+	if !c.closed && len(c.idle) < c.opts.PoolSize {
+		c.idle = append(c.idle, cn)
+		cn = nil
+	}
+	c.mu.Unlock()
+	if cn != nil {
+		cn.close()
+	}
+}
+
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.DialTimeout)
+	defer cancel()
+
+	var nc net.Conn
+	var err error
+	if c.opts.TLS {
+		host, _, _ := net.SplitHostPort(c.opts.Addr)
+		d := &tls.Dialer{Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		nc, err = d.DialContext(ctx, "tcp", c.opts.Addr)
+	} else {
+		var d net.Dialer
+		nc, err = d.DialContext(ctx, "tcp", c.opts.Addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis: dial %s: %w", c.opts.Addr, err)
+	}
+
+	cn := &conn{nc: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+	if c.opts.Password != "" {
+		args := []any{"AUTH", c.opts.Password}
+		if c.opts.Username != "" {
+			args = []any{"AUTH", c.opts.Username, c.opts.Password}
+		}
+		if _, err := cn.do(ctx, args); err != nil {
+			cn.close()
+			return nil, fmt.Errorf("redis: auth: %w", err)
+		}
+	}
+	if c.opts.DB != 0 {
+		if _, err := cn.do(ctx, []any{"SELECT", c.opts.DB}); err != nil {
+			cn.close()
+			return nil, fmt.Errorf("redis: select %d: %w", c.opts.DB, err)
+		}
+	}
+	return cn, nil
+}
+
+// =============================================================================
+// Connection and protocol
+// =============================================================================
+
+type conn struct {
+	nc net.Conn
+	r  *bufio.Reader
+	w  *bufio.Writer
+}
+
+func (cn *conn) close() {
+	_ = cn.nc.Close()
+}
+
+func (cn *conn) do(ctx context.Context, args []any) (any, error) {
+	deadline, _ := ctx.Deadline()
+	if err := cn.nc.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if err := writeCommand(cn.w, args); err != nil {
+		return nil, err
+	}
+	if err := cn.w.Flush(); err != nil {
+		return nil, err
+	}
+	return readReply(cn.r)
+}
+
+// writeCommand writes args as an array of bulk strings.
+func writeCommand(w *bufio.Writer, args []any) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var b []byte
+		switch v := arg.(type) {
+		case string:
+			b = []byte(v)
+		case []byte:
+			b = v
+		case int:
+			b = strconv.AppendInt(nil, int64(v), 10)
+		case int64:
+			b = strconv.AppendInt(nil, v, 10)
+		case float64:
+			b = strconv.AppendFloat(nil, v, 'f', -1, 64)
+		default:
+			return fmt.Errorf("redis: unsupported argument type %T", arg)
+		}
+		fmt.Fprintf(w, "$%d\r\n", len(b))
+		w.Write(b)
+		w.WriteString("\r\n")
+	}
+	return nil
+}
+
+// readReply reads one reply. Error replies inside arrays are returned as
+// Error elements rather than failing the whole reply.
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, Error(body)
+	case ':':
+		n, err := strconv.ParseInt(body, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed integer %q", body)
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, ErrNil
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, ErrNil
+		}
+		items := make([]any, n)
+		for i := range items {
+			item, err := readReply(r)
+			var serverErr Error
+			switch {
+			case errors.As(err, &serverErr):
+				items[i] = serverErr
+			case errors.Is(err, ErrNil):
+				items[i] = nil
+			case err != nil:
+				return nil, err
+			default:
+				items[i] = item
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
+
+// Int64 converts an integer reply.
+func Int64(reply any, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	switch v := reply.(type) {
+	case int64:
+		return v, nil
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, fmt.Errorf("redis: unexpected reply type %T for integer", reply)
+}
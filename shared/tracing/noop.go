@@ -0,0 +1,20 @@
+//go:build !otel
+
+package tracing
+
+import "context"
+
+// Setup does nothing: tracing is compiled in with the otel build tag.
+func Setup(ctx context.Context) (shutdown func(context.Context) error, err error) {
+	return func(context.Context) error { return nil }, nil
+}
+
+func enabled() bool { return false }
+
+func start(ctx context.Context, _ string, _ spanKind, _ []Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func extract(ctx context.Context, _ Carrier) context.Context { return ctx }
+
+func inject(context.Context, Carrier) {}
@@ -0,0 +1,151 @@
+//go:build otel
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies espyna's spans to the backend.
+const instrumentationName = "github.com/erniealice/espyna-golang"
+
+// defaultServiceName is used when OTEL_SERVICE_NAME is not set.
+const defaultServiceName = "espyna"
+
+var (
+	active     atomic.Bool
+	propagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+)
+
+// Setup installs the OTLP exporter configured by the environment. Without
+// an OTLP endpoint, or with OTEL_SDK_DISABLED=true, tracing stays off and
+// Setup returns a no-op shutdown. Call shutdown on exit to flush spans.
+func Setup(ctx context.Context) (shutdown func(context.Context) error, err error) {
+	noop := func(context.Context) error { return nil }
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return noop, nil
+	}
+	if os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		log.Printf("⚠️  Tracing compiled in but no OTEL_EXPORTER_OTLP_ENDPOINT is set; spans are not exported")
+		return noop, nil
+	}
+
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	var exporter *otlptrace.Exporter
+	switch protocol {
+	case "", "http/protobuf":
+		exporter, err = otlptracehttp.New(ctx)
+	case "grpc":
+		exporter, err = otlptracegrpc.New(ctx)
+	default:
+		return noop, fmt.Errorf("unsupported OTLP protocol %q (use http/protobuf or grpc)", protocol)
+	}
+	if err != nil {
+		return noop, fmt.Errorf("create OTLP trace exporter: %w", err)
+	}
+
+	// Later options win, so the environment overrides the default name.
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", defaultServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("build trace resource: %w", err)
+	}
+
+	// The sampler follows OTEL_TRACES_SAMPLER, read by the SDK.
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	active.Store(true)
+
+	return func(ctx context.Context) error {
+		active.Store(false)
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+func enabled() bool { return active.Load() }
+
+func start(ctx context.Context, name string, kind spanKind, attrs []Attribute) (context.Context, Span) {
+	if !active.Load() {
+		return ctx, noopSpan{}
+	}
+	opts := []trace.SpanStartOption{trace.WithAttributes(convert(attrs)...)}
+	switch kind {
+	case kindServer:
+		opts = append(opts, trace.WithSpanKind(trace.SpanKindServer))
+	case kindClient:
+		opts = append(opts, trace.WithSpanKind(trace.SpanKindClient))
+	}
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+	return ctx, otelSpan{span}
+}
+
+func extract(ctx context.Context, carrier Carrier) context.Context {
+	return propagator.Extract(ctx, carrier)
+}
+
+func inject(ctx context.Context, carrier Carrier) {
+	propagator.Inject(ctx, carrier)
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) SetName(name string) { s.span.SetName(name) }
+
+func (s otelSpan) SetAttributes(attrs ...Attribute) { s.span.SetAttributes(convert(attrs)...) }
+
+func (s otelSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+func convert(attrs []Attribute) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			out = append(out, attribute.String(a.Key, v))
+		case bool:
+			out = append(out, attribute.Bool(a.Key, v))
+		case int:
+			out = append(out, attribute.Int(a.Key, v))
+		case int64:
+			out = append(out, attribute.Int64(a.Key, v))
+		case float64:
+			out = append(out, attribute.Float64(a.Key, v))
+		default:
+			out = append(out, attribute.String(a.Key, fmt.Sprint(v)))
+		}
+	}
+	return out
+}
@@ -0,0 +1,203 @@
+// Package tracing records OpenTelemetry spans for requests, use cases,
+// database operations and outbound provider calls.
+//
+// Tracing is optional and compiled in with the otel build tag. Without the
+// tag every function here is a no-op and the package depends only on the Go
+// standard library, so adapters call it unconditionally. With the tag, Setup
+// exports spans over OTLP when an endpoint is configured through the
+// standard OpenTelemetry environment variables:
+//
+//	OTEL_EXPORTER_OTLP_ENDPOINT           collector URL, e.g. http://localhost:4318
+//	OTEL_EXPORTER_OTLP_TRACES_ENDPOINT    overrides the endpoint for traces
+//	OTEL_EXPORTER_OTLP_PROTOCOL           http/protobuf (default) or grpc
+//	OTEL_EXPORTER_OTLP_HEADERS            e.g. authorization headers of a vendor
+//	OTEL_SERVICE_NAME                     defaults to espyna
+//	OTEL_RESOURCE_ATTRIBUTES              e.g. deployment.environment=staging
+//	OTEL_TRACES_SAMPLER(_ARG)             e.g. parentbased_traceidratio and 0.1
+//	OTEL_SDK_DISABLED                     true turns tracing off
+//
+// Server adapters start a span per request with StartServer, continuing the
+// trace of a caller that sent W3C traceparent headers. Use case handlers
+// and database operations start child spans, and NewTransport adds a client
+// span and the traceparent header to calls to third-party providers.
+//
+// Layer: Shared Adapter Toolkit (L4), like shared/correlation.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Attribute is a key-value pair recorded on a span.
+type Attribute struct {
+	Key   string
+	Value any // string, bool, int, int64 or float64
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is an operation being traced.
+type Span interface {
+	// SetName renames the span, e.g. once the router matched a route.
+	SetName(name string)
+	SetAttributes(attrs ...Attribute)
+	// End finishes the span, marking it failed when err is non-nil.
+	End(err error)
+}
+
+// Carrier reads and writes propagation fields, such as the traceparent
+// header, on a request. It matches OpenTelemetry's TextMapCarrier.
+type Carrier interface {
+	Get(key string) string
+	Set(key, value string)
+	Keys() []string
+}
+
+// PropagationFields are the headers carrying the trace context (W3C trace
+// context and baggage), for frameworks whose headers are not an
+// http.Header.
+var PropagationFields = []string{"traceparent", "tracestate", "baggage"}
+
+// HeaderCarrier adapts HTTP headers to Carrier.
+type HeaderCarrier http.Header
+
+// Get returns the first value of the header.
+func (c HeaderCarrier) Get(key string) string { return http.Header(c).Get(key) }
+
+// Set replaces the header.
+func (c HeaderCarrier) Set(key, value string) { http.Header(c).Set(key, value) }
+
+// Keys lists the header names.
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// noopSpan is returned while tracing is off.
+type noopSpan struct{}
+
+func (noopSpan) SetName(string)             {}
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) End(error)                  {}
+
+type spanKind int
+
+const (
+	kindInternal spanKind = iota
+	kindServer
+	kindClient
+)
+
+// Enabled reports whether spans are being exported.
+func Enabled() bool {
+	return enabled()
+}
+
+// Start starts an internal span, a child of the span on ctx.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return start(ctx, name, kindInternal, attrs)
+}
+
+// StartServer starts the span of an incoming request, continuing the trace
+// propagated in carrier when there is one.
+func StartServer(ctx context.Context, name string, carrier Carrier, attrs ...Attribute) (context.Context, Span) {
+	if carrier != nil {
+		ctx = extract(ctx, carrier)
+	}
+	return start(ctx, name, kindServer, attrs)
+}
+
+// EndServer finishes the span of an HTTP request once the router matched
+// route (empty when none did): the span is renamed to method and route, and
+// responses with status 500 and above mark it failed.
+func EndServer(span Span, method, route string, status int) {
+	if route != "" {
+		span.SetName(method + " " + route)
+		span.SetAttributes(String("http.route", route))
+	}
+	span.SetAttributes(Int("http.response.status_code", status))
+	if status >= 500 {
+		span.End(fmt.Errorf("HTTP %d", status))
+		return
+	}
+	span.End(nil)
+}
+
+// StartClient starts the span of an outbound call.
+func StartClient(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return start(ctx, name, kindClient, attrs)
+}
+
+// StartDBOperation starts the span of a database operation on table,
+// named after the operation and table as the database conventions suggest.
+func StartDBOperation(ctx context.Context, system, operation, table string) (context.Context, Span) {
+	return start(ctx, operation+" "+table, kindClient, []Attribute{
+		String("db.system.name", system),
+		String("db.operation.name", operation),
+		String("db.collection.name", table),
+	})
+}
+
+// Inject writes the trace context of ctx into carrier for a downstream
+// service.
+func Inject(ctx context.Context, carrier Carrier) {
+	inject(ctx, carrier)
+}
+
+// Transport is an http.RoundTripper that records a client span for each
+// request and propagates the trace context in its headers.
+type Transport struct {
+	// Base sends the requests; nil means http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base (nil for http.DefaultTransport).
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// RoundTrip sends req inside a client span. Responses with status 400 and
+// above mark the span failed.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if !enabled() {
+		return base.RoundTrip(req)
+	}
+
+	ctx, span := StartClient(req.Context(), "HTTP "+req.Method,
+		String("http.request.method", req.Method),
+		String("server.address", req.URL.Host),
+		String("url.path", req.URL.Path),
+	)
+	req = req.Clone(ctx)
+	Inject(ctx, HeaderCarrier(req.Header))
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+	span.SetAttributes(Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.End(fmt.Errorf("HTTP %d", resp.StatusCode))
+	} else {
+		span.End(nil)
+	}
+	return resp, nil
+}
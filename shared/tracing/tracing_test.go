@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestDisabledTracingIsTransparent(t *testing.T) {
+	if Enabled() {
+		t.Skip("tracing is exporting spans")
+	}
+	ctx := context.Background()
+	got, span := StartServer(ctx, "GET", HeaderCarrier(http.Header{}))
+	if got != ctx {
+		t.Error("StartServer changed the context while tracing is off")
+	}
+	EndServer(span, "GET", "/api/client/list", http.StatusOK)
+
+	var sent *http.Request
+	client := &http.Client{Transport: NewTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		sent = r
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))}
+	req, _ := http.NewRequest(http.MethodGet, "http://provider.test/v1", nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if sent.Header.Get("traceparent") != "" {
+		t.Error("Transport added a traceparent header while tracing is off")
+	}
+}
+
+func TestTransportReturnsBaseErrors(t *testing.T) {
+	want := errors.New("connection refused")
+	transport := NewTransport(roundTripFunc(func(*http.Request) (*http.Response, error) { return nil, want }))
+	req, _ := http.NewRequest(http.MethodGet, "http://provider.test/v1", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, want) {
+		t.Errorf("RoundTrip error = %v, want %v", err, want)
+	}
+}
+
+func TestHeaderCarrier(t *testing.T) {
+	c := HeaderCarrier(http.Header{})
+	c.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	if got := c.Get("Traceparent"); got == "" {
+		t.Error("Get did not find the header Set stored")
+	}
+	if keys := c.Keys(); len(keys) != 1 {
+		t.Errorf("Keys = %v, want one key", keys)
+	}
+}
@@ -6,6 +6,29 @@ import (
 )
 
 var (
-	GenerateObjectID  = internal.GenerateObjectID
-	DetectContentType = internal.DetectContentType
+	GenerateObjectID         = internal.GenerateObjectID
+	DetectContentType        = internal.DetectContentType
+	SanitizeDownloadFilename = internal.SanitizeDownloadFilename
+	ParseIsolation           = internal.ParseIsolation
+	WithObjectOwner          = internal.WithObjectOwner
+	TagMetadata              = internal.TagMetadata
+)
+
+// Workspace isolation for cloud adapters.
+type (
+	Isolation      = internal.Isolation
+	WorkspaceScope = internal.WorkspaceScope
+)
+
+const (
+	IsolationNone   = internal.IsolationNone
+	IsolationPrefix = internal.IsolationPrefix
+	IsolationBucket = internal.IsolationBucket
+)
+
+// Object metadata keys stamped by TagMetadata.
+const (
+	MetadataWorkspaceID = internal.MetadataWorkspaceID
+	MetadataEntityType  = internal.MetadataEntityType
+	MetadataEntityID    = internal.MetadataEntityID
 )
@@ -0,0 +1,146 @@
+//go:build mock_auth
+
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+)
+
+// Scenario is an ordered journey through the API, e.g. signup → enroll →
+// invoice → pay. Later steps use IDs captured by earlier ones, so the first
+// failing step stops the scenario.
+type Scenario struct {
+	Name  string
+	Steps []Step
+}
+
+// Step performs one business action and asserts the state it leaves behind.
+type Step struct {
+	Name string
+	// Requires lists the routes the step calls as "METHOD /path". When one
+	// is not composed, the step and every step after it are skipped.
+	Requires []string
+	Run      func(t *testing.T, env *ServerEnvironment, state *ScenarioState)
+}
+
+// ScenarioState carries IDs from one step to the next.
+type ScenarioState struct {
+	ids map[string]string
+}
+
+// Set records an ID under key.
+func (s *ScenarioState) Set(key, id string) {
+	s.ids[key] = id
+}
+
+// ID returns the ID recorded under key, failing the step when it is absent.
+func (s *ScenarioState) ID(t *testing.T, key string) string {
+	t.Helper()
+	id, ok := s.ids[key]
+	if !ok || id == "" {
+		t.Fatalf("scenario state has no %q; an earlier step did not record it", key)
+	}
+	return id
+}
+
+// RunScenario runs the steps in order as subtests.
+func RunScenario(t *testing.T, env *ServerEnvironment, sc Scenario) {
+	state := &ScenarioState{ids: make(map[string]string)}
+	stopped := ""
+
+	for i, step := range sc.Steps {
+		name := fmt.Sprintf("%02d_%s", i+1, step.Name)
+		passed := t.Run(name, func(t *testing.T) {
+			if stopped != "" {
+				t.Skipf("skipped: %s", stopped)
+			}
+			for _, route := range step.Requires {
+				if !env.HasRoute(route) {
+					stopped = fmt.Sprintf("route %s is not composed by this provider set", route)
+					t.Skip(stopped)
+				}
+			}
+			step.Run(t, env, state)
+		})
+		if !passed && stopped == "" {
+			stopped = fmt.Sprintf("step %q failed", step.Name)
+		}
+	}
+}
+
+// Call POSTs {"data": data} to path and returns the decoded response,
+// failing unless it reports success.
+func (env *ServerEnvironment) Call(t *testing.T, path string, data any) map[string]any {
+	t.Helper()
+	resp := env.PerformRequest(t, APIRequest{
+		Method: "POST",
+		Path:   path,
+		Body:   map[string]any{"data": data},
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("%s: status %d, body: %s", path, resp.StatusCode, resp.Body)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(resp.Body, &body); err != nil {
+		t.Fatalf("%s: invalid JSON response: %v", path, err)
+	}
+	if success, _ := body["success"].(bool); !success {
+		t.Fatalf("%s: success=false, body: %s", path, resp.Body)
+	}
+	return body
+}
+
+// Create calls <entityPath>/create and returns the created record.
+func (env *ServerEnvironment) Create(t *testing.T, entityPath string, data map[string]any) map[string]any {
+	t.Helper()
+	record := FirstRecord(t, env.Call(t, entityPath+"/create", data))
+	if id, _ := record["id"].(string); id == "" {
+		t.Fatalf("%s/create returned a record without id: %v", entityPath, record)
+	}
+	return record
+}
+
+// Read calls <entityPath>/read for id and returns the stored record.
+func (env *ServerEnvironment) Read(t *testing.T, entityPath, id string) map[string]any {
+	t.Helper()
+	return FirstRecord(t, env.Call(t, entityPath+"/read", map[string]any{"id": id}))
+}
+
+// FirstRecord returns data[0] from a response body.
+func FirstRecord(t *testing.T, body map[string]any) map[string]any {
+	t.Helper()
+	data, ok := body["data"].([]any)
+	if !ok || len(data) == 0 {
+		t.Fatalf("response has no data records: %v", body)
+	}
+	record, ok := data[0].(map[string]any)
+	if !ok {
+		t.Fatalf("data[0] is not an object: %v", data[0])
+	}
+	return record
+}
+
+// AssertFields checks each expected field of record. Values are compared by
+// their printed form so JSON numbers match Go integers.
+func AssertFields(t *testing.T, record map[string]any, want map[string]any) {
+	t.Helper()
+	for field, expected := range want {
+		got, ok := record[field]
+		if !ok {
+			t.Errorf("field %s missing from %v", field, record)
+			continue
+		}
+		// Whole JSON numbers print as 1.7e+12; compare them as integers.
+		if f, isFloat := got.(float64); isFloat && f == math.Trunc(f) {
+			got = int64(f)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Errorf("field %s = %v, want %v", field, got, expected)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+//go:build mock_auth
+
+package helper
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erniealice/espyna-golang/consumer"
+	"github.com/erniealice/espyna-golang/internal/composition/contracts"
+	"github.com/erniealice/espyna-golang/internal/composition/routing"
+	"github.com/erniealice/espyna-golang/shared/identity"
+	"github.com/erniealice/espyna-golang/tests/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+// ScenarioUserID is the identity every request runs as. Mock auth allows
+// all permissions, so it acts as a workspace operator.
+const ScenarioUserID = "e2e-operator"
+
+// ServerEnvironment serves the container's real composed routes, unlike
+// TestEnvironment which serves canned responses. Requests go through the
+// same JSON → protobuf → use case path as the net/http server adapter.
+type ServerEnvironment struct {
+	*TestEnvironment
+	routes map[string]bool
+}
+
+// SetupServerEnvironment boots the container with mock providers and serves
+// every route it composes. E2E_PROVIDER selects the database setup from
+// testutil.SetupTestEnvironment (default "mock").
+func SetupServerEnvironment(t *testing.T) *ServerEnvironment {
+	provider := os.Getenv("E2E_PROVIDER")
+	if provider == "" {
+		provider = "mock"
+	}
+	testutil.SetupTestEnvironment(provider)
+	t.Setenv("CONFIG_AUTH_PROVIDER", "mock_auth")
+	t.Setenv("CONFIG_PAYMENT_PROVIDER", "mock_payment")
+
+	container, err := consumer.NewContainerFromEnv()
+	if err != nil {
+		t.Fatalf("Failed to create container: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	routes := make(map[string]bool)
+	if manager := container.GetRouteManager(); manager != nil {
+		for _, route := range manager.GetAllRoutes() {
+			key := route.Method + " " + route.Path
+			if routes[key] {
+				continue // ServeMux panics on duplicate patterns
+			}
+			mux.HandleFunc(key, routeHandler(route))
+			routes[key] = true
+		}
+	}
+
+	server := httptest.NewServer(mux)
+	env := &ServerEnvironment{
+		TestEnvironment: &TestEnvironment{
+			Server:    server,
+			Container: container,
+			Client:    &http.Client{Timeout: 10 * time.Second},
+			BaseURL:   server.URL,
+			cleanup: func() {
+				server.Close()
+				container.Close()
+			},
+		},
+		routes: routes,
+	}
+
+	t.Cleanup(env.cleanup)
+	t.Logf("Serving %d composed routes (provider: %s)", len(routes), provider)
+	return env
+}
+
+// HasRoute reports whether the container composed a route, given as
+// "METHOD /path". Routes are missing when the provider does not supply the
+// use case's repository.
+func (env *ServerEnvironment) HasRoute(route string) bool {
+	return env.routes[route]
+}
+
+// routeHandler mirrors the net/http server adapter: parse the JSON body with
+// the handler's protobuf parser, execute with the scenario identity and
+// encode the response.
+func routeHandler(route *routing.Route) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req proto.Message
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeScenarioError(w, http.StatusBadRequest, "failed to read body: "+err.Error())
+			return
+		}
+		if len(strings.TrimSpace(string(body))) > 0 {
+			parser, ok := route.Handler.(contracts.ProtobufParser)
+			if !ok {
+				writeScenarioError(w, http.StatusInternalServerError, "handler does not support JSON parsing")
+				return
+			}
+			if req, err = parser.ParseRequestFromJSON(body); err != nil {
+				writeScenarioError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		ctx := identity.WithRequestIdentity(r.Context(), &identity.RequestIdentity{UserID: ScenarioUserID})
+		resp, err := route.Handler.Execute(ctx, req)
+		if err != nil {
+			writeScenarioError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func writeScenarioError(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"success": false,
+		"message": message,
+	})
+}
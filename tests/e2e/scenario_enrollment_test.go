@@ -0,0 +1,255 @@
+//go:build mock_auth && mock_db && mock_payment
+
+package e2e
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erniealice/espyna-golang/tests/e2e/helper"
+)
+
+// TestScenarioClientEnrollment walks one client from signup to their first
+// scheduled session through the composed API routes: signup → seed workflow
+// templates → create client → enroll → invoice → pay via a simulated
+// provider webhook → schedule a session. Each step reads back what it wrote
+// so regressions between modules surface at the step that broke.
+//
+//	go test -tags mock_auth,mock_db,mock_payment ./tests/e2e/ -run TestScenario -v
+func TestScenarioClientEnrollment(t *testing.T) {
+	env := helper.SetupServerEnvironment(t)
+	suffix := fmt.Sprint(time.Now().UnixNano())
+
+	helper.RunScenario(t, env, helper.Scenario{
+		Name: "client enrollment",
+		Steps: []helper.Step{
+			{
+				Name: "Signup",
+				Requires: []string{
+					"POST /api/entity/user/create",
+					"POST /api/entity/workspace/create",
+					"POST /api/entity/workspace-user/create",
+					"POST /api/entity/workspace-user/read",
+				},
+				Run: func(t *testing.T, env *helper.ServerEnvironment, state *helper.ScenarioState) {
+					email := "owner-" + suffix + "@example.com"
+					user := env.Create(t, "/api/entity/user", map[string]any{
+						"emailAddress": email,
+						"firstName":    "Olivia",
+						"lastName":     "Owner",
+					})
+					helper.AssertFields(t, user, map[string]any{"email_address": email})
+
+					workspace := env.Create(t, "/api/entity/workspace", map[string]any{
+						"name": "E2E Studio " + suffix,
+					})
+					membership := env.Create(t, "/api/entity/workspace-user", map[string]any{
+						"workspaceId": workspace["id"],
+						"userId":      user["id"],
+					})
+
+					stored := env.Read(t, "/api/entity/workspace-user", membership["id"].(string))
+					helper.AssertFields(t, stored, map[string]any{
+						"workspace_id": workspace["id"],
+						"user_id":      user["id"],
+					})
+					state.Set("workspace", workspace["id"].(string))
+					state.Set("owner", user["id"].(string))
+				},
+			},
+			{
+				Name: "SeedTemplates",
+				Requires: []string{
+					"POST /api/workflow/workflow-template/create",
+					"POST /api/workflow/stage-template/create",
+					"POST /api/workflow/stage-template/read",
+					"POST /api/workflow/activity-template/create",
+					"POST /api/workflow/activity-template/read",
+				},
+				Run: func(t *testing.T, env *helper.ServerEnvironment, state *helper.ScenarioState) {
+					template := env.Create(t, "/api/workflow/workflow-template", map[string]any{
+						"name": "Client Onboarding " + suffix,
+					})
+					stage := env.Create(t, "/api/workflow/stage-template", map[string]any{
+						"workflowTemplateId": template["id"],
+						"name":               "Intake",
+					})
+					activity := env.Create(t, "/api/workflow/activity-template", map[string]any{
+						"stageTemplateId": stage["id"],
+						"name":            "Collect documents",
+					})
+
+					helper.AssertFields(t, env.Read(t, "/api/workflow/stage-template", stage["id"].(string)), map[string]any{
+						"workflow_template_id": template["id"],
+					})
+					helper.AssertFields(t, env.Read(t, "/api/workflow/activity-template", activity["id"].(string)), map[string]any{
+						"stage_template_id": stage["id"],
+					})
+					state.Set("workflow_template", template["id"].(string))
+				},
+			},
+			{
+				Name: "CreateClient",
+				Requires: []string{
+					"POST /api/entity/client/create",
+					"POST /api/entity/client/read",
+				},
+				Run: func(t *testing.T, env *helper.ServerEnvironment, state *helper.ScenarioState) {
+					client := env.Create(t, "/api/entity/client", map[string]any{
+						"user": map[string]any{
+							"emailAddress": "client-" + suffix + "@example.com",
+							"firstName":    "Carlos",
+							"lastName":     "Client",
+						},
+					})
+
+					stored := env.Read(t, "/api/entity/client", client["id"].(string))
+					helper.AssertFields(t, stored, map[string]any{"active": true})
+					if userID, _ := stored["user_id"].(string); userID == "" {
+						t.Errorf("client %s was stored without a user_id", client["id"])
+					}
+					state.Set("client", client["id"].(string))
+				},
+			},
+			{
+				Name: "Enroll",
+				Requires: []string{
+					"POST /api/subscription/plan/create",
+					"POST /api/subscription/price-plan/create",
+					"POST /api/subscription/subscription/create",
+					"POST /api/subscription/subscription/read",
+				},
+				Run: func(t *testing.T, env *helper.ServerEnvironment, state *helper.ScenarioState) {
+					clientID := state.ID(t, "client")
+					plan := env.Create(t, "/api/subscription/plan", map[string]any{
+						"name": "Monthly Coaching " + suffix,
+					})
+					pricePlan := env.Create(t, "/api/subscription/price-plan", map[string]any{
+						"planId":   plan["id"],
+						"amount":   2500,
+						"currency": "PHP",
+					})
+					subscription := env.Create(t, "/api/subscription/subscription", map[string]any{
+						"name":        "Carlos — Monthly Coaching",
+						"clientId":    clientID,
+						"pricePlanId": pricePlan["id"],
+					})
+
+					stored := env.Read(t, "/api/subscription/subscription", subscription["id"].(string))
+					helper.AssertFields(t, stored, map[string]any{
+						"client_id":     clientID,
+						"price_plan_id": pricePlan["id"],
+						"active":        true,
+					})
+					state.Set("subscription", subscription["id"].(string))
+				},
+			},
+			{
+				Name: "Invoice",
+				Requires: []string{
+					"POST /api/subscription/invoice/create",
+					"POST /api/subscription/invoice/read",
+				},
+				Run: func(t *testing.T, env *helper.ServerEnvironment, state *helper.ScenarioState) {
+					subscriptionID := state.ID(t, "subscription")
+					invoice := env.Create(t, "/api/subscription/invoice", map[string]any{
+						"subscriptionId": subscriptionID,
+						"amount":         2500,
+					})
+
+					stored := env.Read(t, "/api/subscription/invoice", invoice["id"].(string))
+					helper.AssertFields(t, stored, map[string]any{
+						"subscription_id": subscriptionID,
+						"amount":          2500,
+						"active":          true,
+					})
+					if number, _ := stored["invoice_number"].(string); !strings.HasPrefix(number, "INV-") {
+						t.Errorf("invoice_number = %q, want a generated INV- number", number)
+					}
+					state.Set("invoice", invoice["id"].(string))
+				},
+			},
+			{
+				Name: "PayViaWebhook",
+				Requires: []string{
+					"POST /integration/payment/webhook",
+					"POST /integration/payment/log",
+					"POST /api/subscription/invoice/read",
+				},
+				Run: func(t *testing.T, env *helper.ServerEnvironment, state *helper.ScenarioState) {
+					invoiceID := state.ID(t, "invoice")
+					payload, err := json.Marshal(map[string]any{
+						"payment_id": invoiceID,
+						"status":     "success",
+						"amount":     2500,
+						"currency":   "PHP",
+					})
+					if err != nil {
+						t.Fatalf("marshal webhook payload: %v", err)
+					}
+
+					// Payload is a bytes field, which protojson reads as base64.
+					result := helper.FirstRecord(t, env.Call(t, "/integration/payment/webhook", map[string]any{
+						"providerId": "mock",
+						"payload":    base64.StdEncoding.EncodeToString(payload),
+					}))
+					helper.AssertFields(t, result, map[string]any{
+						"payment_id": invoiceID,
+						"action":     "success",
+					})
+
+					executionID := "e2e-webhook-" + suffix
+					logged := env.Call(t, "/integration/payment/log", map[string]any{
+						"executionId": executionID,
+						"providerId":  "mock",
+						"paymentId":   invoiceID,
+					})
+					helper.AssertFields(t, logged, map[string]any{"id": executionID})
+
+					// Payment must not disturb the invoice it settles.
+					helper.AssertFields(t, env.Read(t, "/api/subscription/invoice", invoiceID), map[string]any{
+						"amount": 2500,
+						"active": true,
+					})
+				},
+			},
+			{
+				Name: "ScheduleSession",
+				Requires: []string{
+					"POST /api/event/event/create",
+					"POST /api/event/event/read",
+					"POST /api/event/event-client/create",
+					"POST /api/event/event-client/read",
+				},
+				Run: func(t *testing.T, env *helper.ServerEnvironment, state *helper.ScenarioState) {
+					clientID := state.ID(t, "client")
+					start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+					event := env.Create(t, "/api/event/event", map[string]any{
+						"name":             "Kickoff session " + suffix,
+						"startDateTimeUtc": start.UnixMilli(),
+						"endDateTimeUtc":   start.Add(time.Hour).UnixMilli(),
+						"timezone":         "Asia/Manila",
+					})
+					attendance := env.Create(t, "/api/event/event-client", map[string]any{
+						"eventId":  event["id"],
+						"clientId": clientID,
+					})
+
+					helper.AssertFields(t, env.Read(t, "/api/event/event", event["id"].(string)), map[string]any{
+						"start_date_time_utc": start.UnixMilli(),
+						"timezone":            "Asia/Manila",
+						"active":              true,
+					})
+					helper.AssertFields(t, env.Read(t, "/api/event/event-client", attendance["id"].(string)), map[string]any{
+						"event_id":  event["id"],
+						"client_id": clientID,
+					})
+				},
+			},
+		},
+	})
+}